@@ -810,6 +810,15 @@ func (s Struct) WriteNewFunc(l *LineWriter) {
 	l.Write("}")
 }
 
+func (s Struct) WriteJSONFuncs(l *LineWriter) {
+	l.Write("// MarshalJSON implements json.Marshaler; see the package documentation")
+	l.Write("// for how fields are encoded.")
+	l.Write("func (v %s) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }", s.Name)
+	l.Write("// UnmarshalJSON implements json.Unmarshaler; see the package documentation")
+	l.Write("// for how fields are decoded.")
+	l.Write("func (v *%s) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }", s.Name)
+}
+
 func (s Struct) WriteNewPtrFunc(l *LineWriter) {
 	l.Write("// NewPtr%[1]s returns a pointer to a default %[1]s", s.Name)
 	l.Write("// This is a shortcut for creating a new(struct) and calling Default yourself.")
@@ -491,9 +491,10 @@ func main() {
 			}
 		}
 
-		// everything gets a default and new function
+		// everything gets a default, new, and json functions
 		s.WriteDefaultFunc(l)
 		s.WriteNewFunc(l)
+		s.WriteJSONFuncs(l)
 	}
 
 	l.Write("// RequestForKey returns the request corresponding to the given request key")
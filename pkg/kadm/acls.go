@@ -3,6 +3,7 @@ package kadm
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 
@@ -101,7 +102,7 @@ func (b *ACLBuilder) PrefixUserExcept(except ...string) {
 
 // NewACLs returns a new ACL builder.
 func NewACLs() *ACLBuilder {
-	return new(ACLBuilder)
+	return &ACLBuilder{pattern: ACLPatternLiteral}
 }
 
 // AnyResource lists & deletes ACLs of any type matching the given names
@@ -687,12 +688,20 @@ func (cl *Client) CreateACLs(ctx context.Context, b *ACLBuilder) (CreateACLsResu
 		b.denyHosts = []string{"*"}
 	}
 
+	req := kmsg.NewPtrCreateACLsRequest()
+	req.Creations = b.creations()
+	return cl.createACLs(ctx, req)
+}
+
+// creations expands the builder into every ACL to create. Hosts must already
+// be defaulted.
+func (b *ACLBuilder) creations() []kmsg.CreateACLsRequestCreation {
 	var clusters []string
 	if b.anyCluster {
 		clusters = []string{"kafka-cluster"}
 	}
 
-	req := kmsg.NewPtrCreateACLsRequest()
+	var creations []kmsg.CreateACLsRequestCreation
 	for _, typeNames := range []struct {
 		t     kmsg.ACLResourceType
 		names []string
@@ -723,14 +732,17 @@ func (cl *Client) CreateACLs(ctx context.Context, b *ACLBuilder) (CreateACLsResu
 							c.Principal = principal
 							c.Host = host
 							c.PermissionType = perm.permType
-							req.Creations = append(req.Creations, c)
+							creations = append(creations, c)
 						}
 					}
 				}
 			}
 		}
 	}
+	return creations
+}
 
+func (cl *Client) createACLs(ctx context.Context, req *kmsg.CreateACLsRequest) (CreateACLsResults, error) {
 	resp, err := req.RequestWith(ctx, cl.cl)
 	if err != nil {
 		return nil, err
@@ -823,6 +835,10 @@ func (cl *Client) DeleteACLs(ctx context.Context, b *ACLBuilder) (DeleteACLsResu
 
 	req := kmsg.NewPtrDeleteACLsRequest()
 	req.Filters = dels
+	return cl.deleteACLs(ctx, req)
+}
+
+func (cl *Client) deleteACLs(ctx context.Context, req *kmsg.DeleteACLsRequest) (DeleteACLsResults, error) {
 	resp, err := req.RequestWith(ctx, cl.cl)
 	if err != nil {
 		return nil, err
@@ -1115,3 +1131,219 @@ func createDelDescACL(b *ACLBuilder) ([]kmsg.DeleteACLsRequestFilter, []*kmsg.De
 	}
 	return deletions, describes, nil
 }
+
+// ACLs returns every ACL that CreateACLs would create with this builder,
+// validating the builder for creating first. This can be used to build the
+// desired ACLs for ReconcileACLs.
+func (b *ACLBuilder) ACLs() (DescribedACLs, error) {
+	if err := b.ValidateCreate(); err != nil {
+		return nil, err
+	}
+	b = b.dup()
+	if len(b.allow) != 0 && len(b.allowHosts) == 0 {
+		b.allowHosts = []string{"*"}
+	}
+	if len(b.deny) != 0 && len(b.denyHosts) == 0 {
+		b.denyHosts = []string{"*"}
+	}
+	var acls DescribedACLs
+	for _, c := range b.creations() {
+		acls = append(acls, DescribedACL{
+			Principal:  c.Principal,
+			Host:       c.Host,
+			Type:       c.ResourceType,
+			Name:       c.ResourceName,
+			Pattern:    c.ResourcePatternType,
+			Operation:  c.Operation,
+			Permission: c.PermissionType,
+		})
+	}
+	return acls, nil
+}
+
+// Sorted returns the ACLs sorted by resource type, resource name, pattern,
+// principal, host, operation, and permission.
+func (ds DescribedACLs) Sorted() DescribedACLs {
+	s := append(DescribedACLs(nil), ds...)
+	sort.Slice(s, func(i, j int) bool { return s[i].less(s[j]) })
+	return s
+}
+
+func (d DescribedACL) less(other DescribedACL) bool {
+	switch {
+	case d.Type != other.Type:
+		return d.Type < other.Type
+	case d.Name != other.Name:
+		return d.Name < other.Name
+	case d.Pattern != other.Pattern:
+		return d.Pattern < other.Pattern
+	case d.Principal != other.Principal:
+		return d.Principal < other.Principal
+	case d.Host != other.Host:
+		return d.Host < other.Host
+	case d.Operation != other.Operation:
+		return d.Operation < other.Operation
+	default:
+		return d.Permission < other.Permission
+	}
+}
+
+// normalize defaults an empty host to the wildcard host and an unknown
+// pattern to LITERAL, which are the defaults when creating ACLs.
+func (d DescribedACL) normalize() DescribedACL {
+	if d.Host == "" {
+		d.Host = "*"
+	}
+	if d.Pattern == ACLPatternUnknown {
+		d.Pattern = ACLPatternLiteral
+	}
+	return d
+}
+
+// ACLDiff is the difference between desired and existing ACLs.
+type ACLDiff struct {
+	Create    DescribedACLs // Create are desired ACLs that do not exist.
+	Delete    DescribedACLs // Delete are existing ACLs that are not desired.
+	Unchanged DescribedACLs // Unchanged are desired ACLs that already exist.
+}
+
+// DiffACLs returns the ACLs that need to be created and deleted to turn the
+// existing ACLs into the desired ACLs. Duplicate ACLs are ignored, an empty
+// host is treated as the wildcard host "*", and an unknown pattern is treated
+// as LITERAL. Each list in the diff is sorted.
+func DiffACLs(desired, existing DescribedACLs) ACLDiff {
+	want := make(map[DescribedACL]bool, len(desired))
+	for _, d := range desired {
+		want[d.normalize()] = true
+	}
+	have := make(map[DescribedACL]bool, len(existing))
+	for _, e := range existing {
+		have[e.normalize()] = true
+	}
+
+	var diff ACLDiff
+	for d := range want {
+		if have[d] {
+			diff.Unchanged = append(diff.Unchanged, d)
+		} else {
+			diff.Create = append(diff.Create, d)
+		}
+	}
+	for e := range have {
+		if !want[e] {
+			diff.Delete = append(diff.Delete, e)
+		}
+	}
+	diff.Create = diff.Create.Sorted()
+	diff.Delete = diff.Delete.Sorted()
+	diff.Unchanged = diff.Unchanged.Sorted()
+	return diff
+}
+
+// ReconciledACLs is the result of ReconcileACLs.
+type ReconciledACLs struct {
+	ACLDiff
+
+	Created CreateACLsResults // Created contains the results of creating ACLs, if not a dry run.
+	Deleted DeleteACLsResults // Deleted contains the results of deleting ACLs, if not a dry run.
+}
+
+// Error returns the first error from creating or deleting ACLs, if any.
+func (r ReconciledACLs) Error() error {
+	for _, c := range r.Created {
+		if c.Err != nil {
+			return &ErrAndMessage{c.Err, c.ErrMessage}
+		}
+	}
+	for _, d := range r.Deleted {
+		if d.Err != nil {
+			return &ErrAndMessage{d.Err, d.ErrMessage}
+		}
+		for _, m := range d.Deleted {
+			if m.Err != nil {
+				return &ErrAndMessage{m.Err, m.ErrMessage}
+			}
+		}
+	}
+	return nil
+}
+
+// ReconcileACLs makes the ACLs matched by scope exactly the desired ACLs. This
+// describes the existing ACLs that scope matches, diffs them against the
+// desired ACLs with DiffACLs, and then, unless dryRun is true, creates the
+// missing ACLs and deletes the undesired ACLs. Missing ACLs are created before
+// undesired ACLs are deleted, so that changing an ACL does not briefly remove
+// access.
+//
+// The scope limits which existing ACLs are managed: existing ACLs outside of
+// the scope are never deleted. If scope is nil, every ACL in the cluster is
+// managed, meaning every ACL that is not desired is deleted. Desired ACLs
+// should be within the scope; otherwise, they are created every time this is
+// run.
+//
+// Deleting uses one exact filter per ACL, so only the undesired ACLs are
+// deleted. If describing, creating, or deleting fails entirely, this returns
+// an error. Individual ACL errors are in the result; see ReconciledACLs.Error.
+func (cl *Client) ReconcileACLs(ctx context.Context, desired DescribedACLs, scope *ACLBuilder, dryRun bool) (ReconciledACLs, error) {
+	if scope == nil {
+		scope = NewACLs().
+			AnyResource().
+			ResourcePatternType(ACLPatternAny).
+			Allow().AllowHosts().
+			Deny().DenyHosts().
+			Operations()
+	}
+	described, err := cl.DescribeACLs(ctx, scope)
+	if err != nil {
+		return ReconciledACLs{}, err
+	}
+	var existing DescribedACLs
+	for _, d := range described {
+		if d.Err != nil {
+			return ReconciledACLs{}, &ErrAndMessage{d.Err, d.ErrMessage}
+		}
+		existing = append(existing, d.Described...)
+	}
+
+	r := ReconciledACLs{ACLDiff: DiffACLs(desired, existing)}
+	if dryRun {
+		return r, nil
+	}
+
+	if len(r.Create) > 0 {
+		req := kmsg.NewPtrCreateACLsRequest()
+		for _, d := range r.Create {
+			c := kmsg.NewCreateACLsRequestCreation()
+			c.ResourceType = d.Type
+			c.ResourceName = d.Name
+			c.ResourcePatternType = d.Pattern
+			c.Operation = d.Operation
+			c.Principal = d.Principal
+			c.Host = d.Host
+			c.PermissionType = d.Permission
+			req.Creations = append(req.Creations, c)
+		}
+		if r.Created, err = cl.createACLs(ctx, req); err != nil {
+			return r, err
+		}
+	}
+
+	if len(r.Delete) > 0 {
+		req := kmsg.NewPtrDeleteACLsRequest()
+		for _, d := range r.Delete {
+			f := kmsg.NewDeleteACLsRequestFilter()
+			f.ResourceType = d.Type
+			f.ResourceName = kmsg.StringPtr(d.Name)
+			f.ResourcePatternType = d.Pattern
+			f.Operation = d.Operation
+			f.Principal = kmsg.StringPtr(d.Principal)
+			f.Host = kmsg.StringPtr(d.Host)
+			f.PermissionType = d.Permission
+			req.Filters = append(req.Filters, f)
+		}
+		if r.Deleted, err = cl.deleteACLs(ctx, req); err != nil {
+			return r, err
+		}
+	}
+	return r, nil
+}
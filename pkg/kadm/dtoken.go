@@ -2,10 +2,13 @@ package kadm
 
 import (
 	"context"
+	"encoding/base64"
 	"time"
 
 	"github.com/twmb/franz-go/pkg/kerr"
 	"github.com/twmb/franz-go/pkg/kmsg"
+	"github.com/twmb/franz-go/pkg/sasl"
+	"github.com/twmb/franz-go/pkg/sasl/scram"
 )
 
 // Principal is a principal that owns or renews a delegation token. This is the
@@ -16,6 +19,13 @@ type Principal struct {
 	Name string // Name is the name of a principal owner or renewer.
 }
 
+func (p Principal) typ() string {
+	if p.Type == "" {
+		return "User"
+	}
+	return p.Type
+}
+
 // DelegationToken contains information about a delegation token.
 type DelegationToken struct {
 	// Owner is the owner of the delegation token.
@@ -45,13 +55,37 @@ type DelegationToken struct {
 	Renewers []Principal
 }
 
+// ScramAuth returns SCRAM credentials to authenticate with this token: the
+// token ID is the user, the base64 encoded HMAC is the password, and IsToken
+// is set.
+func (t DelegationToken) ScramAuth() scram.Auth {
+	return scram.Auth{
+		User:    t.TokenID,
+		Pass:    base64.StdEncoding.EncodeToString(t.HMAC),
+		IsToken: true,
+	}
+}
+
+// AsSha256Mechanism returns a SCRAM-SHA-256 mechanism that authenticates with
+// this token, for use in a new client with kgo.SASL. Tokens can authenticate
+// with any SCRAM mechanism that the broker enables.
+func (t DelegationToken) AsSha256Mechanism() sasl.Mechanism {
+	return t.ScramAuth().AsSha256Mechanism()
+}
+
+// AsSha512Mechanism returns a SCRAM-SHA-512 mechanism that authenticates with
+// this token, for use in a new client with kgo.SASL.
+func (t DelegationToken) AsSha512Mechanism() sasl.Mechanism {
+	return t.ScramAuth().AsSha512Mechanism()
+}
+
 // DelegationTokens contains a list of delegation tokens.
 type DelegationTokens []DelegationToken
 
 // CreateDelegationToken is a create delegation token request, allowing you to
 // create scoped tokens with the same ACLs as the creator. This allows you to
-// more easily manage authorization for a wide array of clients. All delegation
-// tokens use SCRAM-SHA-256 SASL for authorization.
+// more easily manage authorization for a wide array of clients. Delegation
+// tokens use SCRAM SASL for authorization.
 type CreateDelegationToken struct {
 	// Owner overrides the owner of the token from the principal issuing
 	// the request to the principal in this field. This allows a superuser
@@ -69,8 +103,8 @@ type CreateDelegationToken struct {
 	MaxLifetime time.Duration
 }
 
-// CreateDelegationToken creates a delegation token, which is a scoped
-// SCRAM-SHA-256 username and password.
+// CreateDelegationToken creates a delegation token, which is a scoped SCRAM
+// username and password.
 //
 // Creating delegation tokens allows for an (ideally) quicker and easier method
 // of enabling authorization for a wide array of clients. Rather than having to
@@ -85,12 +119,12 @@ type CreateDelegationToken struct {
 func (cl *Client) CreateDelegationToken(ctx context.Context, d CreateDelegationToken) (DelegationToken, error) {
 	req := kmsg.NewPtrCreateDelegationTokenRequest()
 	if d.Owner != nil {
-		req.OwnerPrincipalType = &d.Owner.Type
+		req.OwnerPrincipalType = kmsg.StringPtr(d.Owner.typ())
 		req.OwnerPrincipalName = &d.Owner.Name
 	}
 	for _, renewer := range d.Renewers {
 		rr := kmsg.NewCreateDelegationTokenRequestRenewer()
-		rr.PrincipalType = renewer.Type
+		rr.PrincipalType = renewer.typ()
 		rr.PrincipalName = renewer.Name
 		req.Renewers = append(req.Renewers, rr)
 	}
@@ -182,7 +216,7 @@ func (cl *Client) DescribeDelegationTokens(ctx context.Context, owners ...Princi
 	req := kmsg.NewPtrDescribeDelegationTokenRequest()
 	for _, owner := range owners {
 		ro := kmsg.NewDescribeDelegationTokenRequestOwner()
-		ro.PrincipalType = owner.Type
+		ro.PrincipalType = owner.typ()
 		ro.PrincipalName = owner.Name
 		req.Owners = append(req.Owners, ro)
 	}
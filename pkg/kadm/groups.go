@@ -2,9 +2,11 @@ package kadm
 
 import (
 	"context"
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"sort"
+	"time"
 
 	"github.com/twmb/franz-go/pkg/kerr"
 	"github.com/twmb/franz-go/pkg/kgo"
@@ -1839,3 +1841,535 @@ func calculateEmptyLag(commit OffsetResponses, startOffsets, endOffsets ListedOf
 }
 
 var errListMissing = errors.New("missing from list offsets")
+
+// GroupActivity is how active a group is, as determined by
+// DescribeGroupActivity.
+type GroupActivity int8
+
+const (
+	// GroupActive is a group that has members.
+	GroupActive GroupActivity = iota
+	// GroupEmpty is a group that has no members, but that has consumed
+	// records produced after the abandonment cutoff, or whose activity
+	// could not be fully determined.
+	GroupEmpty
+	// GroupAbandoned is a group that has no members and that has not
+	// consumed any record produced after the abandonment cutoff.
+	GroupAbandoned
+)
+
+// String returns "Active", "Empty", "Abandoned", or "Unknown".
+func (a GroupActivity) String() string {
+	switch a {
+	case GroupActive:
+		return "Active"
+	case GroupEmpty:
+		return "Empty"
+	case GroupAbandoned:
+		return "Abandoned"
+	default:
+		return "Unknown"
+	}
+}
+
+// DescribedGroupActivity is the activity of a single group.
+type DescribedGroupActivity struct {
+	Group    string        // Group is the group name.
+	State    string        // State is the group's state (Empty, Dead, Stable, etc.).
+	Members  int           // Members is the number of members in the group.
+	Activity GroupActivity // Activity is how active the group is.
+
+	// Committed is the group's committed offsets. This is only fetched
+	// for groups with no members.
+	Committed OffsetResponses
+
+	Err error // Err is non-nil if the group could not be described or its offsets could not be fetched.
+}
+
+// DescribedGroupActivities contains the activity of multiple groups.
+type DescribedGroupActivities map[string]DescribedGroupActivity
+
+// Sorted returns all groups sorted by group name.
+func (as DescribedGroupActivities) Sorted() []DescribedGroupActivity {
+	s := make([]DescribedGroupActivity, 0, len(as))
+	for _, a := range as {
+		s = append(s, a)
+	}
+	sort.Slice(s, func(i, j int) bool { return s[i].Group < s[j].Group })
+	return s
+}
+
+// Abandoned returns a sorted list of all abandoned groups.
+func (as DescribedGroupActivities) Abandoned() []string {
+	var abandoned []string
+	for _, a := range as {
+		if a.Err == nil && a.Activity == GroupAbandoned {
+			abandoned = append(abandoned, a.Group)
+		}
+	}
+	sort.Strings(abandoned)
+	return abandoned
+}
+
+// Error iterates over all groups and returns the first error encountered, if
+// any.
+func (as DescribedGroupActivities) Error() error {
+	for _, a := range as {
+		if a.Err != nil {
+			return a.Err
+		}
+	}
+	return nil
+}
+
+// DescribeGroupActivity classifies groups as active, empty, or abandoned. If
+// no groups are specified, this describes all groups.
+//
+// Kafka does not return when offsets were committed, so this uses the
+// timestamps of records instead: a group without members is abandoned if,
+// for every partition it has committed offsets for, it has not consumed any
+// record that was produced within abandonedAfter of now. A group without
+// members or committed offsets is abandoned as well. If offsets cannot be
+// listed for any committed partition, the group is considered empty rather
+// than abandoned.
+//
+// If a group cannot be described or its offsets cannot be fetched, the group
+// has an error. If any request fails with an auth error, this returns
+// *AuthError.
+func (cl *Client) DescribeGroupActivity(ctx context.Context, abandonedAfter time.Duration, groups ...string) (DescribedGroupActivities, error) {
+	as := make(DescribedGroupActivities)
+
+	described, err := cl.DescribeGroups(ctx, groups...)
+	var ae *AuthError
+	var se *ShardErrors
+	switch {
+	case errors.As(err, &ae):
+		return nil, err
+	case errors.As(err, &se) && !se.AllFailed:
+		for _, se := range se.Errs {
+			for _, g := range se.Req.(*kmsg.DescribeGroupsRequest).Groups {
+				as[g] = DescribedGroupActivity{Group: g, Err: se.Err}
+			}
+		}
+	case err != nil:
+		return nil, err
+	}
+
+	var empty []string
+	for _, g := range described {
+		a := DescribedGroupActivity{
+			Group:   g.Group,
+			State:   g.State,
+			Members: len(g.Members),
+			Err:     g.Err,
+		}
+		if g.Err == nil && len(g.Members) == 0 {
+			a.Activity = GroupEmpty
+			empty = append(empty, g.Group)
+		}
+		as[g.Group] = a
+	}
+	if len(empty) == 0 {
+		return as, nil
+	}
+
+	fetched := cl.FetchManyOffsets(ctx, empty...)
+	if err := fetched.Error(); errors.As(err, &ae) {
+		return nil, err
+	}
+	var topics TopicsSet
+	for _, f := range fetched {
+		a := as[f.Group]
+		a.Committed, a.Err = f.Fetched, f.Err
+		as[f.Group] = a
+		f.Fetched.Partitions().Each(func(t string, p int32) {
+			topics.Add(t, p)
+		})
+	}
+
+	var cutoffs ListedOffsets
+	if len(topics) > 0 {
+		cutoff := time.Now().Add(-abandonedAfter).UnixMilli()
+		cutoffs, err = cl.ListOffsetsAfterMilli(ctx, cutoff, topics.Topics()...)
+		if errors.As(err, &ae) {
+			return nil, err
+		}
+	}
+
+	for _, g := range empty {
+		a := as[g]
+		if a.Err != nil {
+			continue
+		}
+		abandoned := true
+		a.Committed.Each(func(o OffsetResponse) {
+			if o.Err != nil {
+				abandoned = false
+				return
+			}
+			if o.At < 0 {
+				return
+			}
+			c, ok := cutoffs.Lookup(o.Topic, o.Partition)
+			if !ok || c.Err != nil || o.At > c.Offset {
+				abandoned = false
+			}
+		})
+		if abandoned {
+			a.Activity = GroupAbandoned
+		}
+		as[g] = a
+	}
+	return as, nil
+}
+
+// CopyGroupOffsets commits the offsets committed by group "from" to group
+// "to", returning the commit responses. A consumer that then joins "to"
+// resumes where "from" left off.
+//
+// Kafka only accepts commits from outside a group while the group has no
+// members, so "to" should not be running. This returns an error if the
+// offsets of "from" could not be fetched; errors committing individual
+// partitions are in the responses.
+func (cl *Client) CopyGroupOffsets(ctx context.Context, from, to string) (OffsetResponses, error) {
+	fetched, err := cl.FetchOffsets(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+	if err := fetched.Error(); err != nil {
+		return nil, err
+	}
+	if len(fetched) == 0 {
+		return make(OffsetResponses), nil
+	}
+	return cl.CommitOffsets(ctx, to, fetched.Offsets())
+}
+
+// CopyGroupOffsetsTo commits the offsets committed by group "from" in this
+// client's cluster to group "to" in the dst client's cluster, translating
+// offsets by timestamp with TranslateOffsets. Commit metadata is copied as
+// is.
+//
+// As with CopyGroupOffsets, "to" should not be running. This returns an
+// error if the offsets of "from" could not be fetched or translated; errors
+// committing individual partitions are in the responses.
+func (cl *Client) CopyGroupOffsetsTo(ctx context.Context, dst *Client, from, to string) (OffsetResponses, error) {
+	fetched, err := cl.FetchOffsets(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+	if err := fetched.Error(); err != nil {
+		return nil, err
+	}
+	if len(fetched) == 0 {
+		return make(OffsetResponses), nil
+	}
+	os := fetched.Offsets()
+	translated, err := cl.TranslateOffsets(ctx, dst, os)
+	if err != nil {
+		return nil, err
+	}
+	if err := translated.Error(); err != nil {
+		return nil, err
+	}
+	commit := translated.Offsets()
+	for t, ps := range commit {
+		for p, o := range ps {
+			o.Metadata = os[t][p].Metadata
+			ps[p] = o
+		}
+	}
+	return dst.CommitOffsets(ctx, to, commit)
+}
+
+// TranslateOffsets translates offsets in this client's cluster to offsets in
+// the dst client's cluster by timestamp. This is useful when migrating
+// consumers between clusters that contain the same records at different
+// offsets, such as a cluster and its mirror.
+//
+// For each offset, this reads the timestamp of the record at the offset and
+// lists the first offset in dst at or after that timestamp. The timestamp is
+// read from the header of the record batch containing the record, so the
+// translated offset may be slightly before the exact record: consumers may
+// reprocess a few records, but do not skip any. An offset at or past the end
+// of a partition translates to the end of the dst partition, and an offset
+// before the start of a partition translates to the start.
+//
+// Each returned ListedOffset contains the dst offset and leader epoch, and
+// the timestamp that was translated: -1 if translated to the end, or -2 if
+// translated to the start. Partitions that could not be translated have Err
+// set. This returns an error only if either cluster could not be described.
+func (cl *Client) TranslateOffsets(ctx context.Context, dst *Client, os Offsets) (ListedOffsets, error) {
+	src, err := cl.offsetTimestamps(ctx, os)
+	if err != nil {
+		return nil, err
+	}
+	tds, err := dst.ListTopics(ctx, os.TopicsSet().Topics()...)
+	if err != nil {
+		return nil, err
+	}
+
+	translated := make(ListedOffsets)
+	at := make(map[string]map[int32]int64)
+	src.Each(func(l ListedOffset) {
+		var exists bool
+		if td, ok := tds[l.Topic]; ok && td.Err == nil {
+			_, exists = td.Partitions[l.Partition]
+		}
+		if l.Err == nil && !exists {
+			l.Err = kerr.UnknownTopicOrPartition
+		}
+		if l.Err != nil {
+			l.Offset = -1
+			translated.add(l)
+			return
+		}
+		if at[l.Topic] == nil {
+			at[l.Topic] = make(map[int32]int64)
+		}
+		at[l.Topic][l.Partition] = l.Timestamp
+	})
+	if len(at) == 0 {
+		return translated, nil
+	}
+
+	listed := make(ListedOffsets)
+	if err := dst.listOffsetsAt(ctx, 0, listed, at); err != nil {
+		var se *ShardErrors
+		if !errors.As(err, &se) || se.AllFailed {
+			return nil, err
+		}
+	}
+	for t, ps := range at {
+		for p, timestamp := range ps {
+			l, ok := listed.Lookup(t, p)
+			if !ok {
+				l = ListedOffset{Topic: t, Partition: p, Offset: -1, LeaderEpoch: -1, Err: errListOffsetsMissing}
+			}
+			l.Timestamp = timestamp
+			translated.add(l)
+		}
+	}
+	return translated, nil
+}
+
+var (
+	errListOffsetsMissing = errors.New("partition missing in list offsets response")
+	errNoBatchTimestamp   = errors.New("unable to read the timestamp of the record batch at the offset")
+)
+
+func (l ListedOffsets) add(o ListedOffset) {
+	ps := l[o.Topic]
+	if ps == nil {
+		ps = make(map[int32]ListedOffset)
+		l[o.Topic] = ps
+	}
+	ps[o.Partition] = o
+}
+
+// offsetTimestamps returns the timestamp of the record batch containing each
+// offset, -1 if the offset is at or past the end of its partition, or -2 if
+// the offset is before the start of its partition.
+func (cl *Client) offsetTimestamps(ctx context.Context, os Offsets) (ListedOffsets, error) {
+	m, err := cl.Metadata(ctx, os.TopicsSet().Topics()...)
+	if err != nil {
+		return nil, err
+	}
+
+	timestamps := make(ListedOffsets)
+	set := func(o Offset, timestamp int64, err error) {
+		timestamps.add(ListedOffset{
+			Topic:       o.Topic,
+			Partition:   o.Partition,
+			Timestamp:   timestamp,
+			Offset:      o.At,
+			LeaderEpoch: -1,
+			Err:         err,
+		})
+	}
+
+	byLeader := make(map[int32][]Offset)
+	os.Each(func(o Offset) {
+		td, exists := m.Topics[o.Topic]
+		var pd PartitionDetail
+		if exists {
+			pd, exists = td.Partitions[o.Partition]
+		}
+		switch {
+		case td.Err != nil:
+			set(o, -1, td.Err)
+		case !exists:
+			set(o, -1, kerr.UnknownTopicOrPartition)
+		case pd.Err != nil:
+			set(o, -1, pd.Err)
+		case pd.Leader < 0:
+			set(o, -1, kerr.LeaderNotAvailable)
+		default:
+			byLeader[pd.Leader] = append(byLeader[pd.Leader], o)
+		}
+	})
+
+	var outOfRange []Offset
+	for leader, os := range byLeader {
+		retry, oor := cl.fetchTimestamps(ctx, leader, m.Topics, os, set)
+		outOfRange = append(outOfRange, oor...)
+
+		// A partition's first batch may be larger than the max bytes we
+		// fetch per partition, in which case Kafka only returns it if the
+		// partition is the first in the request.
+		for _, o := range retry {
+			retry, oor := cl.fetchTimestamps(ctx, leader, m.Topics, []Offset{o}, set)
+			if len(retry) > 0 {
+				set(o, -1, errNoBatchTimestamp)
+			}
+			outOfRange = append(outOfRange, oor...)
+		}
+	}
+
+	if len(outOfRange) > 0 {
+		var topics []string
+		for _, o := range outOfRange {
+			topics = append(topics, o.Topic)
+		}
+		ends, err := cl.ListEndOffsets(ctx, topics...)
+		if err != nil {
+			var se *ShardErrors
+			if !errors.As(err, &se) || se.AllFailed {
+				return nil, err
+			}
+		}
+		for _, o := range outOfRange {
+			end, ok := ends.Lookup(o.Topic, o.Partition)
+			switch {
+			case !ok:
+				set(o, -1, errListOffsetsMissing)
+			case end.Err != nil:
+				set(o, -1, end.Err)
+			case o.At >= end.Offset:
+				set(o, -1, nil)
+			default:
+				set(o, -2, nil)
+			}
+		}
+	}
+	return timestamps, nil
+}
+
+// fetchTimestamps fetches the record batch at each offset from the leader and
+// saves its timestamp with set. This returns the offsets that need to be
+// fetched again individually and the offsets that are out of range.
+func (cl *Client) fetchTimestamps(
+	ctx context.Context,
+	leader int32,
+	tds TopicDetails,
+	os []Offset,
+	set func(Offset, int64, error),
+) (retry, outOfRange []Offset) {
+	req := kmsg.NewPtrFetchRequest()
+	req.MinBytes = 1
+	var (
+		pending = make(map[string]map[int32]Offset)
+		names   = make(map[TopicID]string)
+		idxs    = make(map[string]int)
+	)
+	for _, o := range os {
+		idx, ok := idxs[o.Topic]
+		if !ok {
+			idx = len(req.Topics)
+			idxs[o.Topic] = idx
+			pending[o.Topic] = make(map[int32]Offset)
+			names[tds[o.Topic].ID] = o.Topic
+			rt := kmsg.NewFetchRequestTopic()
+			rt.Topic = o.Topic
+			rt.TopicID = tds[o.Topic].ID
+			req.Topics = append(req.Topics, rt)
+		}
+		pending[o.Topic][o.Partition] = o
+		rp := kmsg.NewFetchRequestTopicPartition()
+		rp.Partition = o.Partition
+		rp.FetchOffset = o.At
+		rp.PartitionMaxBytes = 1 << 20
+		req.Topics[idx].Partitions = append(req.Topics[idx].Partitions, rp)
+	}
+
+	resp, err := req.RequestWith(ctx, cl.cl.Broker(int(leader)))
+	if err == nil {
+		err = kerr.ErrorForCode(resp.ErrorCode)
+	}
+	if err != nil {
+		for _, o := range os {
+			set(o, -1, err)
+		}
+		return nil, nil
+	}
+
+	for _, rt := range resp.Topics {
+		t := rt.Topic
+		if resp.Version >= 13 {
+			t = names[rt.TopicID]
+		}
+		for _, rp := range rt.Partitions {
+			o, ok := pending[t][rp.Partition]
+			if !ok {
+				continue
+			}
+			delete(pending[t], rp.Partition)
+			err := kerr.ErrorForCode(rp.ErrorCode)
+			switch {
+			case err == kerr.OffsetOutOfRange:
+				outOfRange = append(outOfRange, o)
+			case err != nil:
+				set(o, -1, err)
+			case o.At >= rp.HighWatermark:
+				set(o, -1, nil)
+			default:
+				if timestamp, ok := batchTimestampAt(rp.RecordBatches, o.At); ok {
+					set(o, timestamp, nil)
+				} else {
+					retry = append(retry, o)
+				}
+			}
+		}
+	}
+	for _, ps := range pending {
+		for _, o := range ps {
+			retry = append(retry, o)
+		}
+	}
+	return retry, outOfRange
+}
+
+// batchTimestampAt returns the timestamp of the first record in the batch
+// containing the given offset, or the log append time if the batch uses log
+// append time. Only the batch header is read, so truncated batches are fine.
+func batchTimestampAt(b []byte, offset int64) (int64, bool) {
+	const (
+		lengthEnd         = 12 // base offset, length
+		magicAt           = 16 // after partition leader epoch
+		attributesAt      = 21 // after magic, crc
+		lastOffsetDeltaAt = 23
+		firstTimestampAt  = 27
+		maxTimestampAt    = 35
+		headerEnd         = 43
+	)
+	for len(b) >= headerEnd {
+		if b[magicAt] != 2 {
+			return 0, false
+		}
+		base := int64(binary.BigEndian.Uint64(b))
+		lastOffsetDelta := int32(binary.BigEndian.Uint32(b[lastOffsetDeltaAt:]))
+		if base+int64(lastOffsetDelta) >= offset {
+			timestamp := int64(binary.BigEndian.Uint64(b[firstTimestampAt:]))
+			if binary.BigEndian.Uint16(b[attributesAt:])&0x08 != 0 {
+				timestamp = int64(binary.BigEndian.Uint64(b[maxTimestampAt:]))
+			}
+			return timestamp, timestamp >= 0
+		}
+		length := int64(binary.BigEndian.Uint32(b[8:]))
+		if length <= 0 || lengthEnd+length > int64(len(b)) {
+			return 0, false
+		}
+		b = b[lengthEnd+length:]
+	}
+	return 0, false
+}
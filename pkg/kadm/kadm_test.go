@@ -4,6 +4,8 @@ import (
 	"errors"
 	"reflect"
 	"testing"
+
+	"github.com/twmb/franz-go/pkg/kmsg"
 )
 
 func input[V any](v V) V { return v }
@@ -86,3 +88,30 @@ func TestAny(t *testing.T) {
 		}
 	}
 }
+
+func TestDiffACLs(t *testing.T) {
+	desired, err := NewACLs().
+		Topics("foo", "bar").
+		Allow("User:a").
+		Operations(OpRead, OpWrite).
+		ACLs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	existing := DescribedACLs{
+		{Principal: "User:a", Type: kmsg.ACLResourceTypeTopic, Name: "foo", Operation: OpRead, Permission: kmsg.ACLPermissionTypeAllow},
+		{Principal: "User:a", Host: "*", Type: kmsg.ACLResourceTypeTopic, Name: "foo", Pattern: ACLPatternLiteral, Operation: OpRead, Permission: kmsg.ACLPermissionTypeAllow},
+		{Principal: "User:b", Host: "*", Type: kmsg.ACLResourceTypeTopic, Name: "foo", Pattern: ACLPatternLiteral, Operation: OpRead, Permission: kmsg.ACLPermissionTypeAllow},
+	}
+
+	diff := DiffACLs(desired, existing)
+	if len(diff.Unchanged) != 1 || diff.Unchanged[0].Operation != OpRead || diff.Unchanged[0].Name != "foo" {
+		t.Errorf("got unchanged %v, expected only foo read", diff.Unchanged)
+	}
+	if len(diff.Create) != 3 || diff.Create[0].Name != "bar" {
+		t.Errorf("got create %v, expected bar read, bar write, foo write", diff.Create)
+	}
+	if len(diff.Delete) != 1 || diff.Delete[0].Principal != "User:b" {
+		t.Errorf("got delete %v, expected User:b", diff.Delete)
+	}
+}
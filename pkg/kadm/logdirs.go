@@ -590,3 +590,93 @@ func (cl *Client) DescribeBrokerLogDirs(ctx context.Context, broker int32, s Top
 	}
 	return newDescribeLogDirsResp(broker, resp), nil
 }
+
+// LogDirUsage aggregates the sizes of described log directories by broker,
+// topic, and partition. Every replica of a partition, including future
+// replicas that are being moved between directories, counts toward usage.
+type LogDirUsage struct {
+	Total      int64                      // Total is the size of all replicas on all brokers.
+	Brokers    map[int32]int64            // Brokers is the size of all replicas on each broker.
+	Topics     map[string]int64           // Topics is the size of all replicas of each topic.
+	Partitions map[string]map[int32]int64 // Partitions is the size of all replicas of each partition.
+}
+
+// TopicUsage is the size of all replicas of a topic.
+type TopicUsage struct {
+	Topic string // Topic is the topic.
+	Size  int64  // Size is the size of all replicas of the topic, in bytes.
+}
+
+// Usage aggregates the sizes of all described log directories.
+func (ds DescribedAllLogDirs) Usage() LogDirUsage {
+	u := LogDirUsage{
+		Brokers:    make(map[int32]int64),
+		Topics:     make(map[string]int64),
+		Partitions: make(map[string]map[int32]int64),
+	}
+	for broker, bds := range ds {
+		size := bds.Size()
+		u.Total += size
+		u.Brokers[broker] = size
+		bds.EachPartition(func(p DescribedLogDirPartition) {
+			u.Topics[p.Topic] += p.Size
+			ps := u.Partitions[p.Topic]
+			if ps == nil {
+				ps = make(map[int32]int64)
+				u.Partitions[p.Topic] = ps
+			}
+			ps[p.Partition] += p.Size
+		})
+	}
+	return u
+}
+
+// SortedTopicsBySize returns the size of every topic sorted from smallest to
+// largest. If topics are of equal size, the sorting is by topic.
+func (u LogDirUsage) SortedTopicsBySize() []TopicUsage {
+	all := make([]TopicUsage, 0, len(u.Topics))
+	for t, size := range u.Topics {
+		all = append(all, TopicUsage{t, size})
+	}
+	sort.Slice(all, func(i, j int) bool {
+		l, r := all[i], all[j]
+		return l.Size < r.Size || l.Size == r.Size && l.Topic < r.Topic
+	})
+	return all
+}
+
+// BrokerSkew returns how unevenly data is spread across brokers: the
+// difference between the largest and smallest broker usage divided by the
+// mean broker usage. A skew of 0 means every broker uses the same amount of
+// disk, and a skew of 1 means the difference between the largest and
+// smallest broker is as large as the mean. This returns 0 if there is no
+// usage.
+func (u LogDirUsage) BrokerSkew() float64 {
+	if len(u.Brokers) == 0 || u.Total == 0 {
+		return 0
+	}
+	first := true
+	var smallest, largest int64
+	for _, size := range u.Brokers {
+		if first || size < smallest {
+			smallest = size
+		}
+		if first || size > largest {
+			largest = size
+		}
+		first = false
+	}
+	mean := float64(u.Total) / float64(len(u.Brokers))
+	return float64(largest-smallest) / mean
+}
+
+// DescribeLogDirUsage describes the log directories for every input topic
+// partition on every broker and aggregates their sizes. If the input set is
+// nil, this describes all log directories.
+//
+// This may return *ShardErrors, in which case the usage only includes
+// brokers that were successfully described.
+func (cl *Client) DescribeLogDirUsage(ctx context.Context, s TopicsSet) (LogDirUsage, error) {
+	ds, err := cl.DescribeAllLogDirs(ctx, s)
+	return ds.Usage(), err
+}
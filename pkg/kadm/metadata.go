@@ -435,11 +435,7 @@ func (cl *Client) listOffsets(ctx context.Context, isolation int8, timestamp int
 		return nil, err
 	}
 
-	// If we request with timestamps, we may request twice: once for after
-	// timestamps, and once for any -1 (and no error) offsets where the
-	// timestamp is in the future.
 	list := make(ListedOffsets)
-
 	for _, td := range tds {
 		if td.Err != nil {
 			list[td.Topic] = map[int32]ListedOffset{
@@ -451,6 +447,26 @@ func (cl *Client) listOffsets(ctx context.Context, isolation int8, timestamp int
 			}
 		}
 	}
+	at := make(map[string]map[int32]int64)
+	for t, td := range tds {
+		if td.Err != nil {
+			continue
+		}
+		ps := make(map[int32]int64, len(td.Partitions))
+		for p := range td.Partitions {
+			ps[p] = timestamp
+		}
+		at[t] = ps
+	}
+	return list, cl.listOffsetsAt(ctx, isolation, list, at)
+}
+
+// listOffsetsAt lists offsets for each partition at its own timestamp into
+// list.
+func (cl *Client) listOffsetsAt(ctx context.Context, isolation int8, list ListedOffsets, at map[string]map[int32]int64) error {
+	// If we request with timestamps, we may request twice: once for after
+	// timestamps, and once for any -1 (and no error) offsets where the
+	// timestamp is in the future.
 	rerequest := make(map[string][]int32)
 	shardfn := func(kr kmsg.Response) error {
 		resp := kr.(*kmsg.ListOffsetsResponse)
@@ -472,7 +488,7 @@ func (cl *Client) listOffsets(ctx context.Context, isolation int8, timestamp int
 					LeaderEpoch: p.LeaderEpoch,
 					Err:         kerr.ErrorForCode(p.ErrorCode),
 				}
-				if timestamp != -1 && p.Offset == -1 && p.ErrorCode == 0 {
+				if at[t.Topic][p.Partition] != -1 && p.Offset == -1 && p.ErrorCode == 0 {
 					rerequest[t.Topic] = append(rerequest[t.Topic], p.Partition)
 				}
 			}
@@ -482,13 +498,10 @@ func (cl *Client) listOffsets(ctx context.Context, isolation int8, timestamp int
 
 	req := kmsg.NewPtrListOffsetsRequest()
 	req.IsolationLevel = isolation
-	for t, td := range tds {
+	for t, ps := range at {
 		rt := kmsg.NewListOffsetsRequestTopic()
-		if td.Err != nil {
-			continue
-		}
 		rt.Topic = t
-		for p := range td.Partitions {
+		for p, timestamp := range ps {
 			rp := kmsg.NewListOffsetsRequestTopicPartition()
 			rp.Partition = p
 			rp.Timestamp = timestamp
@@ -497,7 +510,7 @@ func (cl *Client) listOffsets(ctx context.Context, isolation int8, timestamp int
 		req.Topics = append(req.Topics, rt)
 	}
 	shards := cl.cl.RequestSharded(ctx, req)
-	err = shardErrEach(req, shards, shardfn)
+	err := shardErrEach(req, shards, shardfn)
 	if len(rerequest) > 0 {
 		req.Topics = req.Topics[:0]
 		for t, ps := range rerequest {
@@ -514,5 +527,5 @@ func (cl *Client) listOffsets(ctx context.Context, isolation int8, timestamp int
 		shards = cl.cl.RequestSharded(ctx, req)
 		err = mergeShardErrs(err, shardErrEach(req, shards, shardfn))
 	}
-	return list, err
+	return err
 }
@@ -799,6 +799,90 @@ type ElectLeadersResult struct {
 // leaders request.
 type ElectLeadersResults map[string]map[int32]ElectLeadersResult
 
+// Lookup returns the result at t and p and whether it exists.
+func (rs ElectLeadersResults) Lookup(t string, p int32) (ElectLeadersResult, bool) {
+	if len(rs) == 0 {
+		return ElectLeadersResult{}, false
+	}
+	ps := rs[t]
+	if len(ps) == 0 {
+		return ElectLeadersResult{}, false
+	}
+	r, exists := ps[p]
+	return r, exists
+}
+
+// Each calls fn for every elect leaders result.
+func (rs ElectLeadersResults) Each(fn func(ElectLeadersResult)) {
+	for _, ps := range rs {
+		for _, r := range ps {
+			fn(r)
+		}
+	}
+}
+
+// Sorted returns all elect leaders results sorted first by topic, then by
+// partition.
+func (rs ElectLeadersResults) Sorted() []ElectLeadersResult {
+	var s []ElectLeadersResult
+	for _, ps := range rs {
+		for _, r := range ps {
+			s = append(s, r)
+		}
+	}
+	sort.Slice(s, func(i, j int) bool {
+		l, r := s[i], s[j]
+		if l.Topic < r.Topic {
+			return true
+		}
+		if l.Topic > r.Topic {
+			return false
+		}
+		return l.Partition < r.Partition
+	})
+	return s
+}
+
+// On calls fn for the result topic/partition if it exists, returning the
+// result and the error returned from fn. If fn is nil, this simply returns
+// the result.
+//
+// The fn is given a copy of the result. This function returns the copy as
+// well; any modifications within fn are modifications on the returned copy.
+//
+// If the topic or partition does not exist, this returns
+// kerr.UnknownTopicOrPartition.
+func (rs ElectLeadersResults) On(topic string, partition int32, fn func(*ElectLeadersResult) error) (ElectLeadersResult, error) {
+	if len(rs) > 0 {
+		t, ok := rs[topic]
+		if ok {
+			p, ok := t[partition]
+			if ok {
+				if fn == nil {
+					return p, nil
+				}
+				return p, fn(&p)
+			}
+		}
+	}
+	return ElectLeadersResult{}, kerr.UnknownTopicOrPartition
+}
+
+// Error iterates over all results and returns the first error encountered,
+// if any. kerr.ElectionNotNeeded is not considered an error: Kafka returns it
+// for partitions whose preferred replica is already the leader, which is the
+// goal of a preferred election.
+func (rs ElectLeadersResults) Error() error {
+	for _, ps := range rs {
+		for _, r := range ps {
+			if r.Err != nil && !errors.Is(r.Err, kerr.ElectionNotNeeded) {
+				return r.Err
+			}
+		}
+	}
+	return nil
+}
+
 // ElectLeaders elects leaders for partitions. This request was added in Kafka
 // 2.2 to replace the previously-ZooKeeper-only option of triggering leader
 // elections. See KIP-183 for more details.
@@ -810,6 +894,9 @@ type ElectLeadersResults map[string]map[int32]ElectLeadersResult
 //
 // If s is nil, this will elect leaders for all partitions.
 //
+// Per-partition failures are returned in the results rather than as the
+// function error; use the results' Error method to check for any failure.
+//
 // This will return *AuthError if you do not have ALTER on CLUSTER for
 // kafka-cluster.
 func (cl *Client) ElectLeaders(ctx context.Context, how ElectLeadersHow, s TopicsSet) (ElectLeadersResults, error) {
@@ -828,6 +915,9 @@ func (cl *Client) ElectLeaders(ctx context.Context, how ElectLeadersHow, s Topic
 	if err := maybeAuthErr(resp.ErrorCode); err != nil {
 		return nil, err
 	}
+	if err := kerr.ErrorForCode(resp.ErrorCode); err != nil {
+		return nil, err
+	}
 	if resp.Version == 0 { // v0 does not have the election type field
 		how = ElectPreferredReplica
 	}
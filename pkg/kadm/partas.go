@@ -3,6 +3,7 @@ package kadm
 import (
 	"context"
 	"sort"
+	"time"
 
 	"github.com/twmb/franz-go/pkg/kerr"
 	"github.com/twmb/franz-go/pkg/kmsg"
@@ -206,3 +207,193 @@ func (cl *Client) ListPartitionReassignments(ctx context.Context, s TopicsSet) (
 	}
 	return a, nil
 }
+
+// PartitionReassignmentProgress is the progress of a single partition's
+// reassignment, as returned from DescribePartitionReassignments.
+type PartitionReassignmentProgress struct {
+	Topic            string  // Topic is the topic of this partition.
+	Partition        int32   // Partition is the partition number.
+	Replicas         []int32 // Replicas are the partition's current replicas, including replicas being added and removed.
+	AddingReplicas   []int32 // AddingReplicas are replicas currently being added to the partition.
+	RemovingReplicas []int32 // RemovingReplicas are replicas currently being removed from the partition.
+	Leader           int32   // Leader is the partition leader, or -1 if there is none.
+	ISR              []int32 // ISR is the partition's current in sync replicas.
+
+	// ReplicaLagBytes is how many bytes each adding replica is behind the
+	// leader, measured by the size of the replica's log compared to the
+	// size of the leader's log. A replica is only present if both sizes
+	// could be described.
+	ReplicaLagBytes map[int32]int64
+
+	// Done is true if the partition is no longer being reassigned.
+	Done bool
+
+	// Err is non-nil if the partition could not be described.
+	Err error
+}
+
+// PartitionReassignmentsProgress contains the reassignment progress of all
+// partitions in a DescribePartitionReassignments request.
+type PartitionReassignmentsProgress map[string]map[int32]PartitionReassignmentProgress
+
+// Sorted returns the progress sorted by topic and partition.
+func (ps PartitionReassignmentsProgress) Sorted() []PartitionReassignmentProgress {
+	var all []PartitionReassignmentProgress
+	ps.Each(func(p PartitionReassignmentProgress) {
+		all = append(all, p)
+	})
+	sort.Slice(all, func(i, j int) bool {
+		l, r := all[i], all[j]
+		return l.Topic < r.Topic || l.Topic == r.Topic && l.Partition < r.Partition
+	})
+	return all
+}
+
+// Each calls fn for every partition.
+func (ps PartitionReassignmentsProgress) Each(fn func(PartitionReassignmentProgress)) {
+	for _, pps := range ps {
+		for _, p := range pps {
+			fn(p)
+		}
+	}
+}
+
+// Done returns whether every partition is done being reassigned.
+func (ps PartitionReassignmentsProgress) Done() bool {
+	for _, pps := range ps {
+		for _, p := range pps {
+			if !p.Done {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Error returns the first error in the progress, if any.
+func (ps PartitionReassignmentsProgress) Error() error {
+	for _, pps := range ps {
+		for _, p := range pps {
+			if p.Err != nil {
+				return p.Err
+			}
+		}
+	}
+	return nil
+}
+
+// DescribePartitionReassignments returns the reassignment progress of all
+// requested partitions. This lists active reassignments, issues a metadata
+// request for the current leaders and ISRs, and, if any replicas are being
+// added, describes log directories to find how far behind the leader the
+// adding replicas are. Partitions that are not being reassigned are Done.
+//
+// Failing to describe log directories is not fatal: adding replicas are
+// missing from ReplicaLagBytes if their lag could not be determined.
+func (cl *Client) DescribePartitionReassignments(ctx context.Context, s TopicsSet) (PartitionReassignmentsProgress, error) {
+	if len(s) == 0 {
+		return make(PartitionReassignmentsProgress), nil
+	}
+
+	listed, err := cl.ListPartitionReassignments(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+	topics, err := cl.ListTopicsWithInternal(ctx, s.Topics()...)
+	if err != nil {
+		return nil, err
+	}
+
+	var adding TopicsSet
+	listed.Each(func(r ListPartitionReassignmentsResponse) {
+		if len(r.AddingReplicas) > 0 {
+			adding.Add(r.Topic, r.Partition)
+		}
+	})
+	var dirs DescribedAllLogDirs
+	if len(adding) > 0 {
+		dirs, _ = cl.DescribeAllLogDirs(ctx, adding) // best effort
+	}
+	sizeOn := func(broker int32, t string, p int32) (int64, bool) {
+		d, ok := dirs[broker].LookupPartition(t, p)
+		return d.Size, ok
+	}
+
+	ps := make(PartitionReassignmentsProgress)
+	s.Each(func(t string, p int32) {
+		pps := ps[t]
+		if pps == nil {
+			pps = make(map[int32]PartitionReassignmentProgress)
+			ps[t] = pps
+		}
+		progress := PartitionReassignmentProgress{
+			Topic:     t,
+			Partition: p,
+			Leader:    -1,
+			Done:      true,
+		}
+		td, ok := topics[t]
+		switch {
+		case !ok:
+			progress.Err = kerr.UnknownTopicOrPartition
+		case td.Err != nil:
+			progress.Err = td.Err
+		default:
+			pd, ok := td.Partitions[p]
+			if !ok {
+				progress.Err = kerr.UnknownTopicOrPartition
+				break
+			}
+			progress.Replicas = pd.Replicas
+			progress.Leader = pd.Leader
+			progress.ISR = pd.ISR
+			progress.Err = pd.Err
+		}
+		if r, ok := listed[t][p]; ok {
+			progress.Replicas = r.Replicas
+			progress.AddingReplicas = r.AddingReplicas
+			progress.RemovingReplicas = r.RemovingReplicas
+			progress.Done = false
+			if leaderSize, ok := sizeOn(progress.Leader, t, p); ok {
+				for _, replica := range r.AddingReplicas {
+					if size, ok := sizeOn(replica, t, p); ok {
+						if progress.ReplicaLagBytes == nil {
+							progress.ReplicaLagBytes = make(map[int32]int64)
+						}
+						progress.ReplicaLagBytes[replica] = max(leaderSize-size, 0)
+					}
+				}
+			}
+		}
+		pps[p] = progress
+	})
+	return ps, nil
+}
+
+// WatchPartitionReassignments calls DescribePartitionReassignments every
+// interval and calls fn with the progress until every partition is done
+// being reassigned or the context is canceled. This returns nil once every
+// partition is done, the context error if the context is canceled, or the
+// error from describing if describing fails.
+//
+// This can be paired with AlterPartitionAssignments to report the status of
+// a rebalance until it completes.
+func (cl *Client) WatchPartitionReassignments(ctx context.Context, s TopicsSet, interval time.Duration, fn func(PartitionReassignmentsProgress)) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		ps, err := cl.DescribePartitionReassignments(ctx, s)
+		if err != nil {
+			return err
+		}
+		fn(ps)
+		if ps.Done() {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package kadm
+
+import (
+	"context"
+	"sort"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// Client quota entity types.
+const (
+	QuotaEntityUser     = "user"      // QuotaEntityUser is the entity type for a user principal.
+	QuotaEntityClientID = "client-id" // QuotaEntityClientID is the entity type for a client ID.
+	QuotaEntityIP       = "ip"        // QuotaEntityIP is the entity type for a client IP.
+)
+
+// Client quota keys. Byte rates are in bytes per second, request percentage
+// is a percentage of a single broker thread's time (and can exceed 100), and
+// the mutation and connection rates are per second.
+const (
+	QuotaProducerByteRate       = "producer_byte_rate"
+	QuotaConsumerByteRate       = "consumer_byte_rate"
+	QuotaRequestPercentage      = "request_percentage"
+	QuotaControllerMutationRate = "controller_mutation_rate"
+	QuotaConnectionCreationRate = "connection_creation_rate"
+)
+
+// QuotaUser returns an entity component for the given user principal name.
+func QuotaUser(user string) ClientQuotaEntityComponent {
+	return ClientQuotaEntityComponent{Type: QuotaEntityUser, Name: &user}
+}
+
+// QuotaDefaultUser returns an entity component for the default user quota.
+func QuotaDefaultUser() ClientQuotaEntityComponent {
+	return ClientQuotaEntityComponent{Type: QuotaEntityUser}
+}
+
+// QuotaClientID returns an entity component for the given client ID.
+func QuotaClientID(id string) ClientQuotaEntityComponent {
+	return ClientQuotaEntityComponent{Type: QuotaEntityClientID, Name: &id}
+}
+
+// QuotaDefaultClientID returns an entity component for the default client ID
+// quota.
+func QuotaDefaultClientID() ClientQuotaEntityComponent {
+	return ClientQuotaEntityComponent{Type: QuotaEntityClientID}
+}
+
+// QuotaIP returns an entity component for the given IP.
+func QuotaIP(ip string) ClientQuotaEntityComponent {
+	return ClientQuotaEntityComponent{Type: QuotaEntityIP, Name: &ip}
+}
+
+// QuotaDefaultIP returns an entity component for the default IP quota.
+func QuotaDefaultIP() ClientQuotaEntityComponent {
+	return ClientQuotaEntityComponent{Type: QuotaEntityIP}
+}
+
+// NewClientQuotaEntity returns an entity made of the given components,
+// sorted by type. For example, the quota for client ID "bar" of user "foo" is
+//
+//	NewClientQuotaEntity(QuotaUser("foo"), QuotaClientID("bar"))
+func NewClientQuotaEntity(components ...ClientQuotaEntityComponent) ClientQuotaEntity {
+	e := append(ClientQuotaEntity(nil), components...)
+	sort.SliceStable(e, func(i, j int) bool { return e[i].Type < e[j].Type })
+	return e
+}
+
+// Equal returns whether the entities contain the same components, in any
+// order.
+func (ds ClientQuotaEntity) Equal(other ClientQuotaEntity) bool {
+	if len(ds) != len(other) {
+		return false
+	}
+	l, r := NewClientQuotaEntity(ds...), NewClientQuotaEntity(other...)
+	for i := range l {
+		if l[i].Type != r[i].Type || (l[i].Name == nil) != (r[i].Name == nil) ||
+			l[i].Name != nil && *l[i].Name != *r[i].Name {
+			return false
+		}
+	}
+	return true
+}
+
+// Get returns the value of the given quota key, and whether it exists.
+func (vs ClientQuotaValues) Get(key string) (float64, bool) {
+	for _, v := range vs {
+		if v.Key == key {
+			return v.Value, true
+		}
+	}
+	return 0, false
+}
+
+// On finds the described quotas for the given entity and calls fn on them.
+// If the entity was not described, this returns false.
+func (qs DescribedClientQuotas) On(entity ClientQuotaEntity, fn func(*DescribedClientQuota)) (DescribedClientQuota, bool) {
+	for i := range qs {
+		q := &qs[i]
+		if q.Entity.Equal(entity) {
+			if fn != nil {
+				fn(q)
+			}
+			return *q, true
+		}
+	}
+	return DescribedClientQuota{}, false
+}
+
+// Error iterates over all altered entities and returns the first error
+// encountered, if any.
+func (as AlteredClientQuotas) Error() error {
+	for _, a := range as {
+		if a.Err != nil {
+			return a.Err
+		}
+	}
+	return nil
+}
+
+// SetQuota returns an op that sets the given quota key to value.
+func SetQuota(key string, value float64) AlterClientQuotaOp {
+	return AlterClientQuotaOp{Key: key, Value: value}
+}
+
+// RemoveQuota returns an op that removes the given quota key.
+func RemoveQuota(key string) AlterClientQuotaOp {
+	return AlterClientQuotaOp{Key: key, Remove: true}
+}
+
+// SetProducerByteRate returns an op that limits producing to the given bytes
+// per second.
+func SetProducerByteRate(bytesPerSecond int64) AlterClientQuotaOp {
+	return SetQuota(QuotaProducerByteRate, float64(bytesPerSecond))
+}
+
+// SetConsumerByteRate returns an op that limits consuming to the given bytes
+// per second.
+func SetConsumerByteRate(bytesPerSecond int64) AlterClientQuotaOp {
+	return SetQuota(QuotaConsumerByteRate, float64(bytesPerSecond))
+}
+
+// SetRequestPercentage returns an op that limits request handling time to
+// the given percentage of a single broker thread. For example, 200 allows
+// using two threads' worth of time.
+func SetRequestPercentage(percentage float64) AlterClientQuotaOp {
+	return SetQuota(QuotaRequestPercentage, percentage)
+}
+
+// SetControllerMutationRate returns an op that limits topic partition
+// creations and deletions to the given rate per second.
+func SetControllerMutationRate(perSecond float64) AlterClientQuotaOp {
+	return SetQuota(QuotaControllerMutationRate, perSecond)
+}
+
+// SetConnectionCreationRate returns an op that limits connection creation to
+// the given rate per second. This quota is only supported for IP entities.
+func SetConnectionCreationRate(perSecond float64) AlterClientQuotaOp {
+	return SetQuota(QuotaConnectionCreationRate, perSecond)
+}
+
+// DescribeClientQuotasFor describes the quotas of exactly the given entity:
+// each named component is matched exactly, each default component matches
+// the default, and other component types must be absent.
+func (cl *Client) DescribeClientQuotasFor(ctx context.Context, entity ClientQuotaEntity) (DescribedClientQuota, error) {
+	components := make([]DescribeClientQuotaComponent, 0, len(entity))
+	for _, c := range entity {
+		dc := DescribeClientQuotaComponent{Type: c.Type, MatchName: c.Name}
+		if c.Name == nil {
+			dc.MatchType = kmsg.QuotasMatchTypeDefault
+		}
+		components = append(components, dc)
+	}
+	qs, err := cl.DescribeClientQuotas(ctx, true, components)
+	if err != nil {
+		return DescribedClientQuota{}, err
+	}
+	q, _ := qs.On(entity, nil)
+	q.Entity = entity
+	return q, nil
+}
+
+// AlterClientQuota alters the quotas of a single entity, returning the
+// entity's error, if any.
+func (cl *Client) AlterClientQuota(ctx context.Context, entity ClientQuotaEntity, ops ...AlterClientQuotaOp) error {
+	as, err := cl.AlterClientQuotas(ctx, []AlterClientQuotaEntry{{Entity: entity, Ops: ops}})
+	if err != nil {
+		return err
+	}
+	if len(as) != 1 {
+		return kerr.UnknownServerError
+	}
+	if as[0].Err != nil {
+		return &ErrAndMessage{as[0].Err, as[0].ErrMessage}
+	}
+	return nil
+}
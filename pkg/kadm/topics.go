@@ -3,6 +3,7 @@ package kadm
 import (
 	"context"
 	"errors"
+	"fmt"
 	"sort"
 
 	"github.com/twmb/franz-go/pkg/kerr"
@@ -643,3 +644,276 @@ func (cl *Client) createPartitions(ctx context.Context, dry bool, add, set int,
 	}
 	return rs, nil
 }
+
+// TopicSpec is the desired state of a topic, for use in ApplyTopics.
+type TopicSpec struct {
+	Topic string // Topic is the topic name.
+
+	// Partitions is the desired number of partitions. If the topic exists
+	// with fewer partitions, partitions are added. Partitions cannot be
+	// removed, so a topic with more partitions is an error in the plan.
+	// If zero or negative, the topic is created with the broker default
+	// and the partitions of an existing topic are left as is.
+	Partitions int32
+
+	// ReplicationFactor is the replication factor to create the topic
+	// with. If zero or negative, the broker default is used. The
+	// replication factor of an existing topic is never changed, since that
+	// requires reassigning partitions; see
+	// TopicPlan.ReplicationFactorDiffers.
+	ReplicationFactor int16
+
+	// Configs are the desired topic configs. Only configs in this map are
+	// managed: a non-nil value sets the config, while a nil value deletes
+	// any value set on the topic so that the config falls back to the
+	// broker default. Configs not in this map are left as is.
+	Configs map[string]*string
+}
+
+// TopicPlan contains the changes needed to bring a topic to its spec.
+type TopicPlan struct {
+	Topic string // Topic is the topic this plan is for.
+
+	Create bool // Create is whether the topic does not exist and will be created.
+
+	CurrentPartitions int32 // CurrentPartitions is the number of partitions the topic has, or 0 if it does not exist.
+	Partitions        int32 // Partitions is the desired number of partitions, or -1 to keep the current count.
+
+	CurrentReplicationFactor int16 // CurrentReplicationFactor is the replication factor of the topic, or 0 if it does not exist.
+	ReplicationFactor        int16 // ReplicationFactor is the desired replication factor, or -1 for the broker default.
+
+	// Configs contains the config changes for this topic. If the topic
+	// is being created, these are the configs it is created with.
+	Configs []AlterConfig
+
+	// Err is non-nil if the topic could not be planned or if the spec
+	// cannot be applied, or, if not a dry run, if applying failed.
+	Err        error
+	ErrMessage string // ErrMessage a potential extra message describing any error.
+}
+
+// AddsPartitions returns whether the plan adds partitions to an existing
+// topic.
+func (p TopicPlan) AddsPartitions() bool {
+	return !p.Create && p.Partitions > p.CurrentPartitions
+}
+
+// ReplicationFactorDiffers returns whether the topic exists with a different
+// replication factor than desired. ApplyTopics does not change the
+// replication factor; you can use AlterPartitionAssignments to do so.
+func (p TopicPlan) ReplicationFactorDiffers() bool {
+	return !p.Create && p.ReplicationFactor > 0 && p.ReplicationFactor != p.CurrentReplicationFactor
+}
+
+// HasChanges returns whether the plan changes anything.
+func (p TopicPlan) HasChanges() bool {
+	return p.Create || p.AddsPartitions() || len(p.Configs) > 0
+}
+
+// TopicPlans contains plans for many topics, sorted by topic.
+type TopicPlans []TopicPlan
+
+// Changed returns the plans that have changes.
+func (ps TopicPlans) Changed() TopicPlans {
+	var changed TopicPlans
+	for _, p := range ps {
+		if p.HasChanges() {
+			changed = append(changed, p)
+		}
+	}
+	return changed
+}
+
+// Error iterates over all plans and returns the first error encountered, if
+// any.
+func (ps TopicPlans) Error() error {
+	for _, p := range ps {
+		if p.Err != nil {
+			if p.ErrMessage != "" {
+				return &ErrAndMessage{p.Err, p.ErrMessage}
+			}
+			return p.Err
+		}
+	}
+	return nil
+}
+
+// ApplyTopics brings topics to their specs: topics that do not exist are
+// created, partitions are added to topics that have fewer than desired, and
+// configs that differ from the spec are incrementally altered. If dryRun is
+// true, this only returns what would be changed.
+//
+// A config is considered unchanged if its current value, whether set on the
+// topic or inherited from the broker, is the desired value. Sensitive configs
+// cannot be compared and are always set.
+//
+// This returns an error if the cluster state could not be described. Errors
+// for individual topics, including specs that cannot be applied and, if not
+// a dry run, failed changes, are in the returned plans; see TopicPlans.Error.
+// Topics with an error in their plan are not changed.
+func (cl *Client) ApplyTopics(ctx context.Context, dryRun bool, specs ...TopicSpec) (TopicPlans, error) {
+	ps, err := cl.planTopics(ctx, specs)
+	if err != nil || dryRun {
+		return ps, err
+	}
+
+	addTo := make(map[int32][]string)
+	for i := range ps {
+		p := &ps[i]
+		switch {
+		case p.Err != nil:
+		case p.Create:
+			configs := make(map[string]*string, len(p.Configs))
+			for _, c := range p.Configs {
+				configs[c.Name] = c.Value
+			}
+			resp, err := cl.CreateTopic(ctx, p.Partitions, p.ReplicationFactor, configs, p.Topic)
+			p.Err, p.ErrMessage = err, resp.ErrMessage
+		default:
+			if p.AddsPartitions() {
+				addTo[p.Partitions] = append(addTo[p.Partitions], p.Topic)
+			}
+			if len(p.Configs) == 0 {
+				continue
+			}
+			resps, err := cl.AlterTopicConfigs(ctx, p.Configs, p.Topic)
+			if err != nil {
+				p.Err = err
+				continue
+			}
+			if resp, err := resps.On(p.Topic, nil); err != nil {
+				p.Err = err
+			} else {
+				p.Err, p.ErrMessage = resp.Err, resp.ErrMessage
+			}
+		}
+	}
+
+	for n, topics := range addTo {
+		resps, err := cl.UpdatePartitions(ctx, int(n), topics...)
+		for _, t := range topics {
+			i := sort.Search(len(ps), func(i int) bool { return ps[i].Topic >= t })
+			p := &ps[i]
+			if p.Err != nil {
+				continue
+			}
+			switch resp, exists := resps[t]; {
+			case err != nil:
+				p.Err = err
+			case !exists:
+				p.Err = errors.New("topic was not part of create partitions response")
+			default:
+				p.Err, p.ErrMessage = resp.Err, resp.ErrMessage
+			}
+		}
+	}
+	return ps, nil
+}
+
+func (cl *Client) planTopics(ctx context.Context, specs []TopicSpec) (TopicPlans, error) {
+	if len(specs) == 0 {
+		return nil, nil
+	}
+	names := make([]string, 0, len(specs))
+	for _, s := range specs {
+		names = append(names, s.Topic)
+	}
+	tds, err := cl.ListTopicsWithInternal(ctx, names...)
+	if err != nil {
+		return nil, err
+	}
+
+	ps := make(TopicPlans, 0, len(specs))
+	var existing []string
+	for _, s := range specs {
+		p := TopicPlan{
+			Topic:             s.Topic,
+			Partitions:        s.Partitions,
+			ReplicationFactor: s.ReplicationFactor,
+		}
+		if p.Partitions <= 0 {
+			p.Partitions = -1
+		}
+		if p.ReplicationFactor <= 0 {
+			p.ReplicationFactor = -1
+		}
+		td, exists := tds[s.Topic]
+		switch {
+		case !exists || errors.Is(td.Err, kerr.UnknownTopicOrPartition):
+			p.Create = true
+			for _, k := range sortedConfigKeys(s.Configs) {
+				if v := s.Configs[k]; v != nil {
+					p.Configs = append(p.Configs, AlterConfig{Op: SetConfig, Name: k, Value: v})
+				}
+			}
+		case td.Err != nil:
+			p.Err = td.Err
+		default:
+			p.CurrentPartitions = int32(len(td.Partitions))
+			p.CurrentReplicationFactor = int16(td.Partitions.NumReplicas())
+			if p.Partitions > 0 && p.Partitions < p.CurrentPartitions {
+				p.Err = fmt.Errorf("cannot decrease partitions from %d to %d", p.CurrentPartitions, p.Partitions)
+			}
+			existing = append(existing, s.Topic)
+		}
+		ps = append(ps, p)
+	}
+	sort.Slice(ps, func(i, j int) bool { return ps[i].Topic < ps[j].Topic })
+	for i := 1; i < len(ps); i++ {
+		if ps[i].Topic == ps[i-1].Topic {
+			return nil, fmt.Errorf("duplicate spec for topic %q", ps[i].Topic)
+		}
+	}
+	if len(existing) == 0 {
+		return ps, nil
+	}
+
+	rcs, err := cl.DescribeTopicConfigs(ctx, existing...)
+	if err != nil {
+		var se *ShardErrors
+		if !errors.As(err, &se) || se.AllFailed {
+			return nil, err
+		}
+	}
+	for _, s := range specs {
+		i := sort.Search(len(ps), func(i int) bool { return ps[i].Topic >= s.Topic })
+		p := &ps[i]
+		if p.Create || p.Err != nil {
+			continue
+		}
+		rc, err := rcs.On(s.Topic, nil)
+		if err == nil {
+			err = rc.Err
+		}
+		if err != nil {
+			p.Err, p.ErrMessage = err, rc.ErrMessage
+			continue
+		}
+		current := make(map[string]Config, len(rc.Configs))
+		for _, c := range rc.Configs {
+			current[c.Key] = c
+		}
+		for _, k := range sortedConfigKeys(s.Configs) {
+			v := s.Configs[k]
+			c, exists := current[k]
+			switch {
+			case v == nil:
+				if exists && c.Source == kmsg.ConfigSourceDynamicTopicConfig {
+					p.Configs = append(p.Configs, AlterConfig{Op: DeleteConfig, Name: k})
+				}
+			case !exists || c.Sensitive || c.Value == nil || *c.Value != *v:
+				p.Configs = append(p.Configs, AlterConfig{Op: SetConfig, Name: k, Value: v})
+			}
+		}
+	}
+	return ps, nil
+}
+
+func sortedConfigKeys(configs map[string]*string) []string {
+	keys := make([]string, 0, len(configs))
+	for k := range configs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"sort"
+	"time"
 
 	"github.com/twmb/franz-go/pkg/kerr"
 	"github.com/twmb/franz-go/pkg/kmsg"
@@ -759,3 +760,133 @@ func (cl *Client) WriteTxnMarkers(ctx context.Context, markers ...TxnMarkers) (T
 		return nil
 	})
 }
+
+// HangingTransaction is a transaction that is open on a partition, holding
+// back the partition's last stable offset, but that the producer's
+// transaction coordinator is not tracking. Read committed consumers cannot
+// progress past a hanging transaction until it is aborted.
+type HangingTransaction struct {
+	// Producer is the state of the producer with the open transaction on
+	// the partition, as returned from DescribeProducers.
+	Producer DescribedProducer
+
+	// TxnID is the transactional ID currently using the producer ID, or
+	// empty if no transactional ID is using the producer ID.
+	TxnID string
+	// TxnState is the state of TxnID's transaction, or empty if TxnID is
+	// empty.
+	TxnState string
+}
+
+// FindHangingTransactions returns transactions that are hanging on the
+// requested topic set, mirroring kafka-transactions.sh find-hanging. If the
+// input set is empty, this checks all partitions. The returned transactions
+// are sorted by topic, partition, and producer.
+//
+// A transaction is a candidate if it has been open on a partition longer than
+// maxTxnTimeout, which should be the brokers' transaction.max.timeout.ms; if
+// maxTxnTimeout is not positive, Kafka's default of 15 minutes is used. A
+// candidate is hanging if no transactional ID is using its producer ID, or if
+// the transactional ID has moved on: it is using a different epoch, is no
+// longer ongoing, or no longer includes the partition. Hanging transactions
+// can be aborted with AbortTransaction.
+//
+// This may return *ShardErrors or *AuthError.
+func (cl *Client) FindHangingTransactions(ctx context.Context, s TopicsSet, maxTxnTimeout time.Duration) ([]HangingTransaction, error) {
+	if maxTxnTimeout <= 0 {
+		maxTxnTimeout = 15 * time.Minute
+	}
+	described, err := cl.DescribeProducers(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		cutoff     = time.Now().Add(-maxTxnTimeout).UnixMilli()
+		candidates []DescribedProducer
+		pids       []int64
+		seenPIDs   = make(map[int64]bool)
+	)
+	described.EachProducer(func(p DescribedProducer) {
+		if p.CurrentTxnStartOffset < 0 || p.LastTimestamp > cutoff {
+			return
+		}
+		candidates = append(candidates, p)
+		if !seenPIDs[p.ProducerID] {
+			seenPIDs[p.ProducerID] = true
+			pids = append(pids, p.ProducerID)
+		}
+	})
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	listed, err := cl.ListTransactions(ctx, pids, nil)
+	if err != nil {
+		return nil, err
+	}
+	txnIDs := make(map[int64]string, len(listed))
+	for _, l := range listed {
+		txnIDs[l.ProducerID] = l.TxnID
+	}
+	var txns DescribedTransactions
+	if len(listed) > 0 {
+		if txns, err = cl.DescribeTransactions(ctx, listed.TransactionalIDs()...); err != nil {
+			return nil, err
+		}
+	}
+
+	var hanging []HangingTransaction
+	for _, p := range candidates {
+		h := HangingTransaction{Producer: p}
+		if txnID, ok := txnIDs[p.ProducerID]; ok {
+			t, ok := txns[txnID]
+			if ok && t.Err == nil &&
+				t.ProducerID == p.ProducerID &&
+				t.ProducerEpoch == p.ProducerEpoch &&
+				t.State == "Ongoing" &&
+				t.Topics.Lookup(p.Topic, p.Partition) {
+				continue
+			}
+			h.TxnID = txnID
+			h.TxnState = t.State
+		}
+		hanging = append(hanging, h)
+	}
+	sort.Slice(hanging, func(i, j int) bool { return hanging[i].Producer.Less(&hanging[j].Producer) })
+	return hanging, nil
+}
+
+// AbortTransaction aborts the open transaction of the described producer on
+// the producer's topic and partition by writing an abort marker directly to
+// the partition, mirroring kafka-transactions.sh abort. This is meant to
+// abort hanging transactions, as returned from FindHangingTransactions.
+// Aborting a transaction that its coordinator is still tracking breaks the
+// transaction's atomicity.
+//
+// This may return *ShardErrors or *AuthError, or the error writing the marker
+// to the partition.
+func (cl *Client) AbortTransaction(ctx context.Context, p DescribedProducer) error {
+	var s TopicsSet
+	s.Add(p.Topic, p.Partition)
+	coordinatorEpoch := p.CoordinatorEpoch
+	if coordinatorEpoch < 0 {
+		coordinatorEpoch = 0 // the producer has not been through a coordinator; use the lowest epoch
+	}
+	resps, err := cl.WriteTxnMarkers(ctx, TxnMarkers{
+		ProducerID:       p.ProducerID,
+		ProducerEpoch:    p.ProducerEpoch,
+		Commit:           false,
+		CoordinatorEpoch: coordinatorEpoch,
+		Topics:           s,
+	})
+	if err != nil {
+		return err
+	}
+	for _, rp := range resps.SortedPartitions() {
+		if rp.Err != nil {
+			return rp.Err
+		}
+	}
+	return nil
+}
@@ -1,10 +1,11 @@
 package kfake
 
 import (
+	"encoding/binary"
+	"errors"
+	"fmt"
 	"hash/crc32"
-	"net"
-	"strconv"
-	"time"
+	"math"
 
 	"github.com/twmb/franz-go/pkg/kerr"
 	"github.com/twmb/franz-go/pkg/kmsg"
@@ -12,15 +13,14 @@ import (
 
 // TODO
 // * Leaders
-// * Support txns
 // * Multiple batches in one produce
-// * Compact
 
 func init() { regKey(0, 3, 10) }
 
-func (c *Cluster) handleProduce(b *broker, kreq kmsg.Request) (kmsg.Response, error) {
+func (c *Cluster) handleProduce(creq *clientReq) (kmsg.Response, error) {
 	var (
-		req   = kreq.(*kmsg.ProduceRequest)
+		b     = creq.cc.b
+		req   = creq.kreq.(*kmsg.ProduceRequest)
 		resp  = req.ResponseKind().(*kmsg.ProduceResponse)
 		tdone = make(map[string][]kmsg.ProduceResponseTopicPartition)
 	)
@@ -59,19 +59,16 @@ func (c *Cluster) handleProduce(b *broker, kreq kmsg.Request) (kmsg.Response, er
 		if includeBrokers {
 			for _, b := range c.bs {
 				sb := kmsg.NewProduceResponseBroker()
-				h, p, _ := net.SplitHostPort(b.ln.Addr().String())
-				p32, _ := strconv.Atoi(p)
 				sb.NodeID = b.node
-				sb.Host = h
-				sb.Port = int32(p32)
+				sb.Host, sb.Port = b.hostport(creq.cc.listener)
 				resp.Brokers = append(resp.Brokers, sb)
 			}
 		}
 		return resp
 	}
 
-	if req.TransactionID != nil {
-		donets(kerr.TransactionalIDAuthorizationFailed.Code)
+	if req.TransactionID != nil && *req.TransactionID == "" {
+		donets(kerr.InvalidRequest.Code)
 		return toresp(), nil
 	}
 	switch req.Acks {
@@ -80,12 +77,21 @@ func (c *Cluster) handleProduce(b *broker, kreq kmsg.Request) (kmsg.Response, er
 		donets(kerr.InvalidRequiredAcks.Code)
 		return toresp(), nil
 	}
+	if req.TransactionID != nil && !c.allowedTxnID(creq, *req.TransactionID, kmsg.ACLOperationWrite) {
+		donets(kerr.TransactionalIDAuthorizationFailed.Code)
+		return toresp(), nil
+	}
 
-	now := time.Now().UnixMilli()
+	now := b.now().UnixMilli()
+	md := c.metadataFor(b) // nil unless this broker is serving stale metadata
 	for _, rt := range req.Topics {
+		if !c.allowedTopic(creq, rt.Topic, kmsg.ACLOperationWrite) {
+			donet(rt, kerr.TopicAuthorizationFailed.Code)
+			continue
+		}
 		for _, rp := range rt.Partitions {
 			pd, ok := c.data.tps.getp(rt.Topic, rp.Partition)
-			if !ok {
+			if !ok || !md.hasPartition(c, rt.Topic, rp.Partition) {
 				donep(rt.Topic, rp, kerr.UnknownTopicOrPartition.Code)
 				continue
 			}
@@ -96,6 +102,16 @@ func (c *Cluster) handleProduce(b *broker, kreq kmsg.Request) (kmsg.Response, er
 				includeBrokers = true
 				continue
 			}
+			if errCode := c.failProduce(rt.Topic, rp.Partition); errCode != 0 {
+				donep(rt.Topic, rp, errCode)
+				continue
+			}
+			if req.Acks == -1 {
+				if errCode := c.checkMinISR(rt.Topic, pd); errCode != 0 {
+					donep(rt.Topic, rp, errCode)
+					continue
+				}
+			}
 
 			var b kmsg.RecordBatch
 			if err := b.ReadFrom(rp.Records); err != nil {
@@ -128,12 +144,18 @@ func (c *Cluster) handleProduce(b *broker, kreq kmsg.Request) (kmsg.Response, er
 				continue
 			}
 			logAppendTime := int64(-1)
-			if attrs&0x0008 > 0 {
+			if attrs&0x0008 > 0 || c.data.config(rt.Topic, "message.timestamp.type") == "LogAppendTime" {
+				b.Attributes |= 0x0008
 				b.FirstTimestamp = now
 				b.MaxTimestamp = now
+				b.CRC = int32(crc32.Checksum(b.AppendTo(nil)[21:], crc32c))
 				logAppendTime = now
 			}
-			if attrs&0xfff0 != 0 { // TODO txn bit
+			var allowedAttrs uint16
+			if req.TransactionID != nil {
+				allowedAttrs = attrTxnal
+			}
+			if attrs&0xfff0&^allowedAttrs != 0 {
 				donep(rt.Topic, rp, kerr.CorruptMessage.Code)
 				continue
 			}
@@ -142,28 +164,87 @@ func (c *Cluster) handleProduce(b *broker, kreq kmsg.Request) (kmsg.Response, er
 				continue
 			}
 
+			if errCode, errRecs, err := c.validateRecords(rt.Topic, &b, now); err != nil {
+				donep(rt.Topic, rp, kerr.CorruptMessage.Code)
+				continue
+			} else if errCode != 0 {
+				sp := donep(rt.Topic, rp, errCode)
+				sp.ErrorRecords = errRecs
+				sp.ErrorMessage = kmsg.StringPtr("One or more records have been rejected")
+				continue
+			}
+			if errRecs, err := c.rejectRecords(rt.Topic, rp.Partition, &b); err != nil {
+				donep(rt.Topic, rp, kerr.CorruptMessage.Code)
+				continue
+			} else if len(errRecs) > 0 {
+				sp := donep(rt.Topic, rp, kerr.InvalidRecord.Code)
+				sp.ErrorRecords = errRecs
+				sp.ErrorMessage = kmsg.StringPtr("One or more records have been rejected")
+				continue
+			}
+
+			// As in Kafka, a topic with a compression.type other
+			// than "producer" stores batches with its own codec.
+			nbytes := len(rp.Records)
+			if codec, ok := compressionCodecs[c.data.config(rt.Topic, "compression.type")]; ok && codec != int8(b.Attributes&0x0007) {
+				if err := recompress(&b, codec); err != nil {
+					donep(rt.Topic, rp, kerr.CorruptMessage.Code)
+					continue
+				}
+				nbytes = int(b.Length) + 12
+			} else if _, err := decompress(int8(b.Attributes&0x0007), b.Records); err != nil {
+				// Batches stored as produced are still
+				// decompressed to validate them, as in Kafka.
+				donep(rt.Topic, rp, kerr.CorruptMessage.Code)
+				continue
+			}
+
+			if req.TransactionID != nil {
+				if errCode := c.validateTxnProduce(*req.TransactionID, rt.Topic, rp.Partition, &b); errCode != 0 {
+					donep(rt.Topic, rp, errCode)
+					continue
+				}
+			}
+
+			if c.pids.expired(b.ProducerID, b.ProducerEpoch, b.FirstSequence, c.cfg.pidExpiration, c.now()) {
+				sp := donep(rt.Topic, rp, kerr.UnknownProducerID.Code)
+				sp.LogStartOffset = pd.logStartOffset // lets clients detect prefix truncation
+				continue
+			}
+			// As in Kafka, a stale epoch is fenced, and a producer
+			// can only move to a new epoch by restarting its
+			// sequence at zero. Duplicates of any of the last five
+			// batches succeed with the original batch's offset.
 			seqs, epoch := c.pids.get(b.ProducerID, b.ProducerEpoch, rt.Topic, rp.Partition)
-			if be := b.ProducerEpoch; be != -1 {
+			if be := b.ProducerEpoch; seqs != nil && be != -1 && be != epoch {
 				if be < epoch {
-					donep(rt.Topic, rp, kerr.FencedLeaderEpoch.Code)
+					donep(rt.Topic, rp, kerr.InvalidProducerEpoch.Code)
 					continue
-				} else if be > epoch {
-					donep(rt.Topic, rp, kerr.UnknownLeaderEpoch.Code)
+				}
+				if b.FirstSequence != 0 {
+					donep(rt.Topic, rp, kerr.OutOfOrderSequenceNumber.Code)
 					continue
 				}
+				c.pids.newEpoch(b.ProducerID, be)
+				seqs, _ = c.pids.get(b.ProducerID, be, rt.Topic, rp.Partition)
 			}
-			ok, dup := seqs.pushAndValidate(b.FirstSequence, b.NumRecords)
+			ok, dup, dupOffset := seqs.pushAndValidate(b.FirstSequence, b.NumRecords, pd.highWatermark)
 			if !ok {
 				donep(rt.Topic, rp, kerr.OutOfOrderSequenceNumber.Code)
 				continue
 			}
 			if dup {
-				donep(rt.Topic, rp, 0)
+				sp := donep(rt.Topic, rp, 0)
+				sp.BaseOffset = dupOffset
+				sp.LogStartOffset = pd.logStartOffset
 				continue
 			}
+			c.pids.used(b.ProducerID, c.now())
 			baseOffset := pd.highWatermark
 			lso := pd.logStartOffset
-			pd.pushBatch(len(rp.Records), b)
+			pd.pushBatch(nbytes, b)
+			c.eosRecord(rt.Topic, rp.Partition, baseOffset, &b)
+			c.stats.produced(rt.Topic, nbytes, b.NumRecords)
 			sp := donep(rt.Topic, rp, 0)
 			sp.BaseOffset = baseOffset
 			sp.LogAppendTime = logAppendTime
@@ -178,3 +259,95 @@ func (c *Cluster) handleProduce(b *broker, kreq kmsg.Request) (kmsg.Response, er
 }
 
 var crc32c = crc32.MakeTable(crc32.Castagnoli)
+
+// validateRecords checks every record in the batch against the topic's
+// timestamp bounds and the configured record limits, returning an error code
+// and an error record for every invalid record. Timestamp errors take
+// precedence over record limit errors. Records are only decompressed if
+// there is something to check. This returns an error if the batch cannot be
+// decompressed or decoded.
+func (c *Cluster) validateRecords(t string, b *kmsg.RecordBatch, now int64) (int16, []kmsg.ProduceResponseTopicPartitionErrorRecord, error) {
+	cfg := &c.cfg
+	var (
+		checkLimits = cfg.maxRecordKeyBytes > 0 || cfg.maxRecordValueBytes > 0 || cfg.maxRecordHeaders > 0 || cfg.maxRecordHeaderBytes > 0
+		before      = int64(math.MaxInt64)
+		after       = int64(math.MaxInt64)
+	)
+	if uint16(b.Attributes)&0x0008 == 0 { // only CreateTime timestamps are validated
+		before = c.data.configInt(t, "message.timestamp.before.max.ms")
+		after = c.data.configInt(t, "message.timestamp.after.max.ms")
+		diff := c.data.configInt(t, "message.timestamp.difference.max.ms")
+		if diff < before {
+			before = diff
+		}
+		if diff < after {
+			after = diff
+		}
+	}
+	checkTimestamps := before < math.MaxInt64 || after < math.MaxInt64
+	if !checkLimits && !checkTimestamps {
+		return 0, nil, nil
+	}
+
+	raw, err := decompress(int8(b.Attributes&0x0007), b.Records)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var (
+		tsErrs  []kmsg.ProduceResponseTopicPartitionErrorRecord
+		recErrs []kmsg.ProduceResponseTopicPartitionErrorRecord
+	)
+	adderr := func(errs *[]kmsg.ProduceResponseTopicPartitionErrorRecord, i int32, msg string) {
+		er := kmsg.NewProduceResponseTopicPartitionErrorRecord()
+		er.RelativeOffset = i
+		er.ErrorMessage = &msg
+		*errs = append(*errs, er)
+	}
+	for i := int32(0); i < b.NumRecords; i++ {
+		length, n := binary.Varint(raw)
+		if n <= 0 || length < 0 || int64(len(raw)-n) < length {
+			return 0, nil, errors.New("invalid record length")
+		}
+		var r kmsg.Record
+		if err := r.ReadFrom(raw[:n+int(length)]); err != nil {
+			return 0, nil, err
+		}
+		raw = raw[n+int(length):]
+
+		if checkTimestamps {
+			ts := b.FirstTimestamp + r.TimestampDelta64
+			switch {
+			case now-ts > before:
+				adderr(&tsErrs, i, fmt.Sprintf("timestamp %d of message with offset %d is out of range: more than %dms before the broker's time", ts, i, before))
+			case ts-now > after:
+				adderr(&tsErrs, i, fmt.Sprintf("timestamp %d of message with offset %d is out of range: more than %dms after the broker's time", ts, i, after))
+			}
+		}
+
+		if !checkLimits {
+			continue
+		}
+		var hbytes int
+		for _, h := range r.Headers {
+			hbytes += len(h.Key) + len(h.Value)
+		}
+		switch {
+		case cfg.maxRecordKeyBytes > 0 && len(r.Key) > cfg.maxRecordKeyBytes:
+			adderr(&recErrs, i, fmt.Sprintf("record key size %d is larger than the maximum %d", len(r.Key), cfg.maxRecordKeyBytes))
+		case cfg.maxRecordValueBytes > 0 && len(r.Value) > cfg.maxRecordValueBytes:
+			adderr(&recErrs, i, fmt.Sprintf("record value size %d is larger than the maximum %d", len(r.Value), cfg.maxRecordValueBytes))
+		case cfg.maxRecordHeaders > 0 && len(r.Headers) > cfg.maxRecordHeaders:
+			adderr(&recErrs, i, fmt.Sprintf("record header count %d is larger than the maximum %d", len(r.Headers), cfg.maxRecordHeaders))
+		case cfg.maxRecordHeaderBytes > 0 && hbytes > cfg.maxRecordHeaderBytes:
+			adderr(&recErrs, i, fmt.Sprintf("record header size %d is larger than the maximum %d", hbytes, cfg.maxRecordHeaderBytes))
+		}
+	}
+	if len(tsErrs) > 0 {
+		return kerr.InvalidTimestamp.Code, tsErrs, nil
+	}
+	if len(recErrs) > 0 {
+		return kerr.InvalidRecord.Code, recErrs, nil
+	}
+	return 0, nil, nil
+}
@@ -0,0 +1,277 @@
+package kfake
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestRecordLimits(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(1), SeedTopics(1, "t"),
+		MaxRecordKeyBytes(2),
+		MaxRecordValueBytes(4),
+		MaxRecordHeaders(1),
+		MaxRecordHeaderBytes(4),
+	)
+	br := newTestClient(t, c).Broker(0)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	produce := func(rs ...*kgo.Record) kmsg.ProduceResponseTopicPartition {
+		t.Helper()
+		return produceBatch(ctx, t, br, "t", 0, rs...)
+	}
+	header := func(k, v string) *kgo.Record {
+		return &kgo.Record{Headers: []kgo.RecordHeader{{Key: k, Value: []byte(v)}}}
+	}
+
+	sp := produce(
+		kgo.KeyStringRecord("k", "v"),
+		kgo.KeyStringRecord("key", "v"),
+		kgo.StringRecord("value"),
+		&kgo.Record{Headers: []kgo.RecordHeader{{Key: "a"}, {Key: "b"}}},
+		header("hk", "hvv"),
+		header("hk", "hv"),
+	)
+	if sp.ErrorCode != kerr.InvalidRecord.Code {
+		t.Fatalf("got %v, expected INVALID_RECORD", kerr.ErrorForCode(sp.ErrorCode))
+	}
+	exp := map[int32]string{1: "key size", 2: "value size", 3: "header count", 4: "header size"}
+	if len(sp.ErrorRecords) != len(exp) {
+		t.Fatalf("got %d error records, expected %d", len(sp.ErrorRecords), len(exp))
+	}
+	for _, er := range sp.ErrorRecords {
+		if want, ok := exp[er.RelativeOffset]; !ok || er.ErrorMessage == nil || !strings.Contains(*er.ErrorMessage, want) {
+			t.Errorf("unexpected error record %d: %v", er.RelativeOffset, er.ErrorMessage)
+		}
+	}
+
+	// Nothing from a rejected batch is written; records within the
+	// limits are.
+	if sp = produce(kgo.KeyStringRecord("k", "v"), header("hk", "hv")); sp.ErrorCode != 0 {
+		t.Fatalf("producing valid records: %v", kerr.ErrorForCode(sp.ErrorCode))
+	}
+	if sp.BaseOffset != 0 {
+		t.Errorf("valid records were written at offset %d, expected 0", sp.BaseOffset)
+	}
+}
+
+func TestTimestampBounds(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(1))
+	br := newTestClient(t, c).Broker(0)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	minute := "60000"
+	for topic, configs := range map[string]map[string]*string{
+		"bounds": {"message.timestamp.before.max.ms": &minute, "message.timestamp.after.max.ms": &minute},
+		"diff":   {"message.timestamp.difference.max.ms": &minute},
+	} {
+		if _, err := newTestAdmin(t, c).CreateTopic(ctx, 1, 1, configs, topic); err != nil {
+			t.Fatal(err)
+		}
+		now := time.Now()
+		sp := produceBatch(ctx, t, br, topic, 0,
+			&kgo.Record{Timestamp: now.Add(-2 * time.Minute)},
+			&kgo.Record{Timestamp: now.Add(-30 * time.Second)},
+			&kgo.Record{Timestamp: now.Add(30 * time.Second)},
+			&kgo.Record{Timestamp: now.Add(2 * time.Minute)},
+		)
+		if sp.ErrorCode != kerr.InvalidTimestamp.Code {
+			t.Fatalf("%s: got %v, expected INVALID_TIMESTAMP", topic, kerr.ErrorForCode(sp.ErrorCode))
+		}
+		if len(sp.ErrorRecords) != 2 ||
+			sp.ErrorRecords[0].RelativeOffset != 0 || !strings.Contains(*sp.ErrorRecords[0].ErrorMessage, "before") ||
+			sp.ErrorRecords[1].RelativeOffset != 3 || !strings.Contains(*sp.ErrorRecords[1].ErrorMessage, "after") {
+			t.Errorf("%s: got error records %+v, expected records 0 and 3", topic, sp.ErrorRecords)
+		}
+
+		if sp = produceBatch(ctx, t, br, topic, 0, &kgo.Record{Timestamp: now}); sp.ErrorCode != 0 {
+			t.Errorf("%s: producing a current timestamp: %v", topic, kerr.ErrorForCode(sp.ErrorCode))
+		}
+	}
+}
+
+// produceBatch produces the records to the partition in one zstd
+// compressed batch, so that validation must decompress the batch. Records
+// without a timestamp are given the current time.
+func produceBatch(ctx context.Context, t *testing.T, br *kgo.Broker, topic string, partition int32, rs ...*kgo.Record) kmsg.ProduceResponseTopicPartition {
+	t.Helper()
+	now := time.Now()
+	for _, r := range rs {
+		if r.Timestamp.IsZero() {
+			r.Timestamp = now
+		}
+	}
+	b := newRecordBatchFrom(rs)
+	if err := recompress(&b, 4); err != nil {
+		t.Fatal(err)
+	}
+	req := kmsg.NewPtrProduceRequest()
+	req.Acks = -1
+	req.TimeoutMillis = 5000
+	rt := kmsg.NewProduceRequestTopic()
+	rt.Topic = topic
+	rp := kmsg.NewProduceRequestTopicPartition()
+	rp.Partition = partition
+	rp.Records = b.AppendTo(nil)
+	rt.Partitions = append(rt.Partitions, rp)
+	req.Topics = append(req.Topics, rt)
+	resp, err := req.RequestWith(ctx, br)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.Topics[0].Partitions[0]
+}
+
+func TestReplicationLatency(t *testing.T) {
+	const delay = 300 * time.Millisecond
+	var calls atomic.Int32
+	c := newTestCluster(t, NumBrokers(1), SeedTopics(2, "t"), ReplicationLatency(func(topic string, partition int32) time.Duration {
+		calls.Add(1)
+		if topic == "t" && partition == 0 {
+			return delay
+		}
+		return 0
+	}))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	produce := func(cl *kgo.Client) error {
+		return cl.ProduceSync(ctx, &kgo.Record{Topic: "t", Partition: 0}).FirstErr()
+	}
+
+	// An acks=all produce is delayed by its slowest partition, but the
+	// records are readable before the response is sent.
+	cl := newTestClient(t, c, kgo.RecordPartitioner(kgo.ManualPartitioner()))
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() { done <- produce(cl) }()
+	for {
+		if rs, _ := c.ReadRecords("t", 0, 0, 0); len(rs) == 1 {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	select {
+	case <-done:
+		t.Fatal("acks=all produce was replied to before the replication latency")
+	default:
+	}
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < delay {
+		t.Errorf("acks=all produce took %v, expected at least %v", elapsed, delay)
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("latency function was called %d times, expected once", n)
+	}
+
+	// Leader acks are not delayed.
+	cl = newTestClient(t, c,
+		kgo.RequiredAcks(kgo.LeaderAck()),
+		kgo.DisableIdempotentWrite(),
+		kgo.RecordPartitioner(kgo.ManualPartitioner()),
+	)
+	start = time.Now()
+	if err := produce(cl); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed >= delay {
+		t.Errorf("acks=1 produce took %v, expected no replication latency", elapsed)
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("latency function was called %d times, expected only for acks=all", n)
+	}
+}
+
+func TestReplicationLatencyZeroTimeout(t *testing.T) {
+	const delay = time.Second
+	c := newTestCluster(t, NumBrokers(1), SeedTopics(1, "t"), ReplicationLatency(func(string, int32) time.Duration {
+		return delay
+	}))
+	br := newTestClient(t, c).Broker(0)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// As in Kafka, a request with a zero timeout does not wait for
+	// replication: it times out immediately.
+	b := newRecordBatchFrom([]*kgo.Record{{Value: []byte("v"), Timestamp: time.Now()}})
+	req := kmsg.NewPtrProduceRequest()
+	req.Acks = -1
+	req.TimeoutMillis = 0
+	rt := kmsg.NewProduceRequestTopic()
+	rt.Topic = "t"
+	rp := kmsg.NewProduceRequestTopicPartition()
+	rp.Records = b.AppendTo(nil)
+	rt.Partitions = append(rt.Partitions, rp)
+	req.Topics = append(req.Topics, rt)
+	start := time.Now()
+	resp, err := req.RequestWith(ctx, br)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := kerr.ErrorForCode(resp.Topics[0].Partitions[0].ErrorCode); err != kerr.RequestTimedOut {
+		t.Errorf("got %v, expected %v", err, kerr.RequestTimedOut)
+	}
+	if elapsed := time.Since(start); elapsed >= delay {
+		t.Errorf("produce took %v, expected to not wait for the %v replication latency", elapsed, delay)
+	}
+}
+
+func TestLognormalLatency(t *testing.T) {
+	const (
+		p50 = 10 * time.Millisecond
+		p99 = 200 * time.Millisecond
+		n   = 100000
+	)
+	fn := LognormalLatency(p50, p99)
+	ds := make([]time.Duration, n)
+	for i := range ds {
+		ds[i] = fn("t", 0)
+	}
+	slices.Sort(ds)
+	for _, q := range []struct {
+		name string
+		got  time.Duration
+		exp  time.Duration
+	}{
+		{"p50", ds[n/2], p50},
+		{"p99", ds[n*99/100], p99},
+	} {
+		if q.got < q.exp*8/10 || q.got > q.exp*12/10 {
+			t.Errorf("got %s %v, expected about %v", q.name, q.got, q.exp)
+		}
+	}
+
+	// Without a tail, the median is always returned.
+	fn = LognormalLatency(p50, p50)
+	if d := fn("t", 0); d != p50 {
+		t.Errorf("got %v without a tail, expected %v", d, p50)
+	}
+}
+
+func TestLatencySpikes(t *testing.T) {
+	const n = 10000
+	fn := LatencySpikes(func(string, int32) time.Duration { return time.Millisecond }, 0.1, time.Second)
+	var spikes int
+	for i := 0; i < n; i++ {
+		switch d := fn("t", 0); d {
+		case time.Millisecond:
+		case time.Millisecond + time.Second:
+			spikes++
+		default:
+			t.Fatalf("got latency %v, expected 1ms or 1.001s", d)
+		}
+	}
+	if spikes < n*7/100 || spikes > n*13/100 {
+		t.Errorf("got %d spikes of %d, expected about 10%%", spikes, n)
+	}
+}
@@ -1,8 +1,6 @@
 package kfake
 
 import (
-	"net"
-	"strconv"
 	"sync"
 	"time"
 
@@ -14,9 +12,12 @@ import (
 //
 // * If topic does not exist, we hang
 // * Topic created while waiting is not returned in final response
-// * If any partition is on a different broker, we return immediately
+// * If any partition is on a broker that is not a replica, or the leader
+//   redirects the client to a preferred read replica, we return immediately
+// * Followers serve v11+ fetches, see racks.go
 // * Out of range fetch causes early return
 // * Raw bytes of batch counts against wait bytes
+// * Fetch sessions are supported, see fetch_sessions.go
 
 func init() { regKey(1, 4, 16) }
 
@@ -34,7 +35,19 @@ func (c *Cluster) handleFetch(creq *clientReq, w *watchFetch) (kmsg.Response, er
 		nbytes      int
 		returnEarly bool
 		needp       tps[int]
+		sess        *fetchSession
+		fullSess    bool
 	)
+	if w != nil {
+		sess, fullSess = w.sess, w.fullSess
+	} else {
+		var errCode int16
+		if sess, fullSess, errCode = c.fetchSession(creq, req); errCode != 0 {
+			resp.ErrorCode = errCode
+			return resp, nil
+		}
+	}
+	md := c.metadataFor(creq.cc.b) // nil unless this broker is serving stale metadata
 	if w == nil {
 	out:
 		for i, rt := range req.Topics {
@@ -46,13 +59,17 @@ func (c *Cluster) handleFetch(creq *clientReq, w *watchFetch) (kmsg.Response, er
 			if !ok {
 				continue
 			}
+			if !c.allowedTopic(creq, rt.Topic, kmsg.ACLOperationRead) {
+				returnEarly = true // TopicAuthorizationFailed
+				break out
+			}
 			for _, rp := range rt.Partitions {
 				pd, ok := t[rp.Partition]
-				if !ok || pd.createdAt.After(creq.at) {
+				if !ok || pd.createdAt.After(creq.at) || !md.hasPartition(c, rt.Topic, rp.Partition) {
 					continue
 				}
-				if pd.leader != creq.cc.b {
-					returnEarly = true // NotLeaderForPartition
+				if ok, preferred := c.fetchReplica(creq, rt.Topic, pd); !ok || preferred >= 0 {
+					returnEarly = true // NotLeaderForPartition, or PreferredReadReplica
 					break out
 				}
 				i, ok, atEnd := pd.searchOffset(rp.FetchOffset)
@@ -65,6 +82,9 @@ func (c *Cluster) handleFetch(creq *clientReq, w *watchFetch) (kmsg.Response, er
 				}
 				pbytes := 0
 				for _, b := range pd.batches[i:] {
+					if req.IsolationLevel == 1 && b.FirstOffset >= pd.lastStableOffset {
+						break
+					}
 					nbytes += b.nbytes
 					pbytes += b.nbytes
 					if pbytes >= int(rp.PartitionMaxBytes) {
@@ -85,6 +105,8 @@ func (c *Cluster) handleFetch(creq *clientReq, w *watchFetch) (kmsg.Response, er
 			needp:    needp,
 			deadline: deadline,
 			creq:     creq,
+			sess:     sess,
+			fullSess: fullSess,
 		}
 		w.cb = func() {
 			select {
@@ -99,14 +121,13 @@ func (c *Cluster) handleFetch(creq *clientReq, w *watchFetch) (kmsg.Response, er
 			}
 			for _, rp := range rt.Partitions {
 				pd, ok := t[rp.Partition]
-				if !ok || pd.createdAt.After(creq.at) {
+				if !ok || pd.createdAt.After(creq.at) || !md.hasPartition(c, rt.Topic, rp.Partition) {
 					continue
 				}
-				pd.watch[w] = struct{}{}
-				w.in = append(w.in, pd)
+				w.watch(rt.Topic, rp.Partition, pd)
 			}
 		}
-		w.t = time.AfterFunc(wait, w.cb)
+		c.park(w, wait)
 		return nil, nil
 	}
 
@@ -139,11 +160,8 @@ func (c *Cluster) handleFetch(creq *clientReq, w *watchFetch) (kmsg.Response, er
 		if includeBrokers {
 			for _, b := range c.bs {
 				sb := kmsg.NewFetchResponseBroker()
-				h, p, _ := net.SplitHostPort(b.ln.Addr().String())
-				p32, _ := strconv.Atoi(p)
 				sb.NodeID = b.node
-				sb.Host = h
-				sb.Port = int32(p32)
+				sb.Host, sb.Port = b.hostport(creq.cc.listener)
 				resp.Brokers = append(resp.Brokers, sb)
 			}
 		}
@@ -155,6 +173,14 @@ full:
 		for _, rp := range rt.Partitions {
 			pd, ok := c.data.tps.getp(rt.Topic, rp.Partition)
 			if !ok {
+				if req.Version >= 13 {
+					donep(rt.Topic, rt.TopicID, rp.Partition, c.data.staleIDErr(rt.TopicID))
+				} else {
+					donep(rt.Topic, rt.TopicID, rp.Partition, kerr.UnknownTopicOrPartition.Code)
+				}
+				continue
+			}
+			if !md.hasPartition(c, rt.Topic, rp.Partition) {
 				if req.Version >= 13 {
 					donep(rt.Topic, rt.TopicID, rp.Partition, kerr.UnknownTopicID.Code)
 				} else {
@@ -162,7 +188,12 @@ full:
 				}
 				continue
 			}
-			if pd.leader != creq.cc.b {
+			if !c.allowedTopic(creq, rt.Topic, kmsg.ACLOperationRead) {
+				donep(rt.Topic, rt.TopicID, rp.Partition, kerr.TopicAuthorizationFailed.Code)
+				continue
+			}
+			ok, preferred := c.fetchReplica(creq, rt.Topic, pd)
+			if !ok {
 				p := donep(rt.Topic, rt.TopicID, rp.Partition, kerr.NotLeaderForPartition.Code)
 				p.CurrentLeader.LeaderID = pd.leader.node
 				p.CurrentLeader.LeaderEpoch = pd.epoch
@@ -173,6 +204,10 @@ full:
 			sp.HighWatermark = pd.highWatermark
 			sp.LastStableOffset = pd.lastStableOffset
 			sp.LogStartOffset = pd.logStartOffset
+			if preferred >= 0 {
+				sp.PreferredReadReplica = preferred
+				continue
+			}
 			i, ok, atEnd := pd.searchOffset(rp.FetchOffset)
 			if atEnd {
 				continue
@@ -181,20 +216,48 @@ full:
 				sp.ErrorCode = kerr.OffsetOutOfRange.Code
 				continue
 			}
-			var pbytes int
+			var (
+				pbytes int
+				size   int   // bytes of the batches added
+				n      int   // number of batches added
+				end    int64 = -1
+				isFull bool
+			)
 			for _, b := range pd.batches[i:] {
+				if req.IsolationLevel == 1 && b.FirstOffset >= pd.lastStableOffset {
+					break
+				}
 				if nbytes = nbytes + b.nbytes; nbytes > int(req.MaxBytes) && batchesAdded > 1 {
-					break full
+					isFull = true
+					break
 				}
 				if pbytes = pbytes + b.nbytes; pbytes > int(rp.PartitionMaxBytes) && batchesAdded > 1 {
 					break
 				}
 				batchesAdded++
-				sp.RecordBatches = b.AppendTo(sp.RecordBatches)
+				size += b.nbytes
+				n++
+			}
+			// We size the batches up front so that appending never
+			// grows (and copies) them.
+			if n > 0 {
+				sp.RecordBatches = c.fetchBuf(creq, size)
+			}
+			for _, b := range pd.batches[i : i+n] {
+				sp.RecordBatches = c.appendFetchBatch(sp.RecordBatches, rt.Topic, rp.Partition, &b)
+				c.stats.fetched(rt.Topic, b.nbytes, b.NumRecords)
+				end = b.FirstOffset + int64(b.LastOffsetDelta)
+			}
+			if req.IsolationLevel == 1 {
+				sp.AbortedTransactions = pd.abortedIn(rp.FetchOffset, end)
+			}
+			if isFull {
+				break full
 			}
 		}
 	}
 
+	sess.respond(resp, fullSess)
 	return resp, nil
 }
 
@@ -203,15 +266,36 @@ type watchFetch struct {
 	needp    tps[int]
 	deadline time.Time
 	creq     *clientReq
+	sess     *fetchSession // the fetch's session, if any
+	fullSess bool          // whether the session fetch is full
 
-	in []*partData
-	cb func()
-	t  *time.Timer
+	in    []*partData
+	parts map[string][]int32
+	cb    func()
+	t     *time.Timer
 
 	once    sync.Once
 	cleaned bool
 }
 
+// park registers w as a parked fetch until it is woken or the wait expires.
+func (c *Cluster) park(w *watchFetch, wait time.Duration) {
+	if c.parked == nil {
+		c.parked = make(map[*watchFetch]struct{})
+	}
+	c.parked[w] = struct{}{}
+	w.t = time.AfterFunc(wait, w.cb)
+}
+
+func (w *watchFetch) watch(t string, p int32, pd *partData) {
+	pd.watch[w] = struct{}{}
+	w.in = append(w.in, pd)
+	if w.parts == nil {
+		w.parts = make(map[string][]int32)
+	}
+	w.parts[t] = append(w.parts[t], p)
+}
+
 func (w *watchFetch) push(nbytes int) {
 	w.need -= nbytes
 	if w.need <= 0 {
@@ -222,6 +306,10 @@ func (w *watchFetch) push(nbytes int) {
 }
 
 func (w *watchFetch) deleted() {
+	w.wake()
+}
+
+func (w *watchFetch) wake() {
 	w.once.Do(func() {
 		go w.cb()
 	})
@@ -229,8 +317,39 @@ func (w *watchFetch) deleted() {
 
 func (w *watchFetch) cleanup(c *Cluster) {
 	w.cleaned = true
+	delete(c.parked, w)
 	for _, in := range w.in {
 		delete(in.watch, w)
 	}
 	w.t.Stop()
 }
+
+// fetchBuf returns an empty buffer with capacity for at least n bytes of
+// record batches. With HighThroughput, the buffer comes from the cluster's
+// pool and is tracked on the request, so that only buffers we handed out are
+// returned to the pool once the response is written.
+func (c *Cluster) fetchBuf(creq *clientReq, n int) []byte {
+	if !c.cfg.highThroughput {
+		return make([]byte, 0, n)
+	}
+	buf, _ := c.fetchBufs.Get().(*[]byte)
+	if buf != nil && cap(*buf) < n {
+		c.fetchBufs.Put(buf) // too small for us, but not for a later fetch
+		buf = nil
+	}
+	if buf == nil {
+		b := make([]byte, 0, n)
+		buf = &b
+	}
+	creq.fetchBufs = append(creq.fetchBufs, buf)
+	return (*buf)[:0]
+}
+
+// releaseFetchBufs returns buffers handed out by fetchBuf to the pool once
+// their response is written. Nothing may use the response after.
+func (c *Cluster) releaseFetchBufs(bufs []*[]byte) {
+	for _, buf := range bufs {
+		*buf = (*buf)[:0]
+		c.fetchBufs.Put(buf)
+	}
+}
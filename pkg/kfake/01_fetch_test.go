@@ -0,0 +1,145 @@
+package kfake
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// parkFetch issues a fetch for all partitions of topic t from offset 0 that
+// waits up to maxWait for a byte of data, returning a channel that receives
+// the number of record batch bytes returned, or -1 if the request failed.
+func parkFetch(ctx context.Context, t *testing.T, c *Cluster, cl *kgo.Client, maxWait time.Duration) <-chan int {
+	t.Helper()
+	req := kmsg.NewPtrFetchRequest()
+	req.MaxWaitMillis = int32(maxWait.Milliseconds())
+	req.MinBytes = 1
+	req.MaxBytes = 1 << 20
+	rt := kmsg.NewFetchRequestTopic()
+	c.admin(func() {
+		rt.TopicID = c.data.t2id["t"]
+		ps, _ := c.data.tps.gett("t")
+		for p := range ps {
+			rp := kmsg.NewFetchRequestTopicPartition()
+			rp.Partition = p
+			rp.PartitionMaxBytes = 1 << 20
+			rt.Partitions = append(rt.Partitions, rp)
+		}
+	})
+	req.Topics = append(req.Topics, rt)
+
+	done := make(chan int, 1)
+	go func() {
+		resp, err := req.RequestWith(ctx, cl.Broker(0))
+		if err != nil {
+			done <- -1
+			return
+		}
+		var n int
+		for _, rt := range resp.Topics {
+			for _, rp := range rt.Partitions {
+				n += len(rp.RecordBatches)
+			}
+		}
+		done <- n
+	}()
+	return done
+}
+
+// waitParked waits until n fetches are parked.
+func waitParked(ctx context.Context, t *testing.T, c *Cluster, n int) []ParkedFetch {
+	t.Helper()
+	for {
+		if fs := c.ParkedFetches(); len(fs) == n {
+			return fs
+		}
+		select {
+		case <-ctx.Done():
+			t.Fatalf("%d fetches never parked", n)
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+}
+
+func TestParkedFetches(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(1), SeedTopics(2, "t"))
+	cl := newTestClient(t, c, kgo.ClientID("parker"))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	done := parkFetch(ctx, t, c, cl, 5*time.Second)
+	f := waitParked(ctx, t, c, 1)[0]
+	if f.ClientID != "parker" || f.Node != 0 || f.Key != int16(kmsg.Fetch) {
+		t.Errorf("got parked fetch %+v, expected a fetch from parker to node 0", f)
+	}
+	slices.Sort(f.Partitions["t"])
+	if exp := map[string][]int32{"t": {0, 1}}; !reflect.DeepEqual(f.Partitions, exp) {
+		t.Errorf("got parked partitions %v, expected %v", f.Partitions, exp)
+	}
+	if d := f.Deadline.Sub(start); d < 4*time.Second || d > 6*time.Second {
+		t.Errorf("got deadline %v from the fetch, expected about 5s", d)
+	}
+
+	// Appending data from within the cluster wakes the fetch.
+	if _, err := c.ProduceTo("t", 1, kgo.StringRecord("v")); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case n := <-done:
+		if n <= 0 {
+			t.Errorf("woken fetch returned %d bytes, expected data", n)
+		}
+	case <-ctx.Done():
+		t.Fatal("fetch was not woken by appended data")
+	}
+	if fs := c.ParkedFetches(); len(fs) != 0 {
+		t.Errorf("got %d parked fetches after waking, expected 0", len(fs))
+	}
+}
+
+func TestWakeParkedFetches(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(1), SeedTopics(1, "t"), SeedTopics(1, "other"))
+	cl := newTestClient(t, c)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	done := parkFetch(ctx, t, c, cl, 5*time.Second)
+	waitParked(ctx, t, c, 1)
+	if n := c.WakeParkedFetches("other"); n != 0 {
+		t.Errorf("woke %d fetches waiting on other topics, expected 0", n)
+	}
+	if n := c.WakeParkedFetches("t"); n != 1 {
+		t.Errorf("woke %d fetches, expected 1", n)
+	}
+	select {
+	case n := <-done:
+		if n != 0 {
+			t.Errorf("woken fetch returned %d bytes, expected none", n)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("fetch was not woken")
+	}
+
+	// Records generated within the cluster also wake fetches.
+	done = parkFetch(ctx, t, c, cl, 5*time.Second)
+	waitParked(ctx, t, c, 1)
+	stop, err := c.GenerateRecords("t", 100, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stop()
+	select {
+	case n := <-done:
+		if n <= 0 {
+			t.Errorf("woken fetch returned %d bytes, expected data", n)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("fetch was not woken by generated records")
+	}
+}
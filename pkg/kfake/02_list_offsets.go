@@ -9,8 +9,9 @@ import (
 
 func init() { regKey(2, 0, 7) }
 
-func (c *Cluster) handleListOffsets(b *broker, kreq kmsg.Request) (kmsg.Response, error) {
-	req := kreq.(*kmsg.ListOffsetsRequest)
+func (c *Cluster) handleListOffsets(creq *clientReq) (kmsg.Response, error) {
+	b := creq.cc.b
+	req := creq.kreq.(*kmsg.ListOffsetsRequest)
 	resp := req.ResponseKind().(*kmsg.ListOffsetsResponse)
 
 	if err := checkReqVersion(req.Key(), req.Version); err != nil {
@@ -37,15 +38,21 @@ func (c *Cluster) handleListOffsets(b *broker, kreq kmsg.Request) (kmsg.Response
 		return &st.Partitions[len(st.Partitions)-1]
 	}
 
+	md := c.metadataFor(b) // nil unless this broker is serving stale metadata
 	for _, rt := range req.Topics {
 		ps, ok := c.data.tps.gett(rt.Topic)
+		allowed := c.allowedTopic(creq, rt.Topic, kmsg.ACLOperationDescribe)
 		for _, rp := range rt.Partitions {
+			if !allowed {
+				donep(rt.Topic, rp.Partition, kerr.TopicAuthorizationFailed.Code)
+				continue
+			}
 			if !ok {
 				donep(rt.Topic, rp.Partition, kerr.UnknownTopicOrPartition.Code)
 				continue
 			}
 			pd, ok := ps[rp.Partition]
-			if !ok {
+			if !ok || !md.hasPartition(c, rt.Topic, rp.Partition) {
 				donep(rt.Topic, rp.Partition, kerr.UnknownTopicOrPartition.Code)
 				continue
 			}
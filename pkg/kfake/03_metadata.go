@@ -1,17 +1,14 @@
 package kfake
 
 import (
-	"net"
-	"strconv"
-
 	"github.com/twmb/franz-go/pkg/kerr"
 	"github.com/twmb/franz-go/pkg/kmsg"
 )
 
 func init() { regKey(3, 0, 12) }
 
-func (c *Cluster) handleMetadata(kreq kmsg.Request) (kmsg.Response, error) {
-	req := kreq.(*kmsg.MetadataRequest)
+func (c *Cluster) handleMetadata(creq *clientReq) (kmsg.Response, error) {
+	req := creq.kreq.(*kmsg.MetadataRequest)
 	resp := req.ResponseKind().(*kmsg.MetadataResponse)
 
 	if err := checkReqVersion(req.Key(), req.Version); err != nil {
@@ -20,16 +17,16 @@ func (c *Cluster) handleMetadata(kreq kmsg.Request) (kmsg.Response, error) {
 
 	for _, b := range c.bs {
 		sb := kmsg.NewMetadataResponseBroker()
-		h, p, _ := net.SplitHostPort(b.ln.Addr().String())
-		p32, _ := strconv.Atoi(p)
 		sb.NodeID = b.node
-		sb.Host = h
-		sb.Port = int32(p32)
+		sb.Host, sb.Port = b.hostport(creq.cc.listener)
+		sb.Rack = b.rack
 		resp.Brokers = append(resp.Brokers, sb)
 	}
 
 	resp.ClusterID = &c.cfg.clusterID
-	resp.ControllerID = c.controller.node
+	resp.ControllerID = c.advertisedController().node
+
+	md := c.metadataFor(creq.cc.b) // nil unless this broker is serving stale metadata
 
 	id2t := make(map[uuid]string)
 	tidx := make(map[string]int)
@@ -45,6 +42,7 @@ func (c *Cluster) handleMetadata(kreq kmsg.Request) (kmsg.Response, error) {
 			st.Topic = kmsg.StringPtr(t)
 		}
 		st.TopicID = id
+		st.IsInternal = t == offsetsTopic
 		st.ErrorCode = errCode
 		resp.Topics = append(resp.Topics, st)
 		return &resp.Topics[len(resp.Topics)-1]
@@ -57,21 +55,12 @@ func (c *Cluster) handleMetadata(kreq kmsg.Request) (kmsg.Response, error) {
 		st.Partitions = append(st.Partitions, sp)
 		return &st.Partitions[len(st.Partitions)-1]
 	}
-	okp := func(t string, id uuid, p int32, pd *partData) {
-		nreplicas := c.data.treplicas[t]
-		if nreplicas > len(c.bs) {
-			nreplicas = len(c.bs)
-		}
-
+	okp := func(t string, id uuid, p int32, mp mdPartition) {
 		sp := donep(t, id, p, 0)
-		sp.Leader = pd.leader.node
-		sp.LeaderEpoch = pd.epoch
-
-		for i := 0; i < nreplicas; i++ {
-			idx := (pd.leader.bsIdx + i) % len(c.bs)
-			sp.Replicas = append(sp.Replicas, c.bs[idx].node)
-		}
-		sp.ISR = sp.Replicas
+		sp.Leader = mp.leader
+		sp.LeaderEpoch = mp.epoch
+		sp.Replicas = mp.replicas
+		sp.ISR = mp.isr
 	}
 
 	allowAuto := req.AllowAutoTopicCreation && c.cfg.allowAutoTopic
@@ -82,7 +71,7 @@ func (c *Cluster) handleMetadata(kreq kmsg.Request) (kmsg.Response, error) {
 		// Duplicate topics are merged into one response topic.
 		// Topics with no topic and no ID are ignored.
 		if rt.TopicID != noID {
-			if topic, ok = c.data.id2t[rt.TopicID]; !ok {
+			if topic, ok = md.topicName(c, rt.TopicID); !ok {
 				donet("", rt.TopicID, kerr.UnknownTopicID.Code)
 				continue
 			}
@@ -91,27 +80,67 @@ func (c *Cluster) handleMetadata(kreq kmsg.Request) (kmsg.Response, error) {
 		} else {
 			topic = *rt.Topic
 		}
+		if !c.allowedTopic(creq, topic, kmsg.ACLOperationDescribe) {
+			donet(topic, rt.TopicID, kerr.TopicAuthorizationFailed.Code)
+			continue
+		}
 
-		ps, ok := c.data.tps.gett(topic)
+		mt, ok := md.topic(c, topic)
 		if !ok {
+			// Kafka returns INVALID_TOPIC_EXCEPTION for illegal names
+			// whether or not auto creation is allowed.
+			if validTopicName(topic) != nil {
+				donet(topic, rt.TopicID, kerr.InvalidTopicException.Code)
+				continue
+			}
 			if !allowAuto {
 				donet(topic, rt.TopicID, kerr.UnknownTopicOrPartition.Code)
 				continue
 			}
-			c.data.mkt(topic, -1, -1, nil)
-			ps, _ = c.data.tps.gett(topic)
+			if !c.allowedCluster(creq, kmsg.ACLOperationCreate) && !c.allowedTopic(creq, topic, kmsg.ACLOperationCreate) {
+				donet(topic, rt.TopicID, kerr.TopicAuthorizationFailed.Code)
+				continue
+			}
+			// A stale broker asks the controller to create the
+			// topic, which may already exist, but does not yet
+			// know the topic's leaders.
+			if _, exists := c.data.tps.gett(topic); !exists {
+				// Kafka fails to create a topic that is still
+				// being deleted, and replies that the leader is
+				// not yet available.
+				if c.data.isDeleting(topic) {
+					donet(topic, rt.TopicID, kerr.LeaderNotAvailable.Code)
+					continue
+				}
+				if err := c.data.validateNewTopic(topic); err != nil {
+					donet(topic, rt.TopicID, kerr.InvalidTopicException.Code)
+					continue
+				}
+				if err := c.checkResourceLimits(1, c.newTopicPartitions(topic, -1)); err != nil {
+					donet(topic, rt.TopicID, kerr.PolicyViolation.Code)
+					continue
+				}
+				c.data.mkt(topic, -1, -1, nil)
+			}
+			if md != nil {
+				donet(topic, rt.TopicID, kerr.LeaderNotAvailable.Code)
+				continue
+			}
+			mt = c.snapshotTopic(topic)
 		}
 
-		id := c.data.t2id[topic]
-		for p, pd := range ps {
-			okp(topic, id, p, pd)
+		for p, mp := range mt.ps {
+			okp(topic, mt.id, p, mp)
 		}
 	}
-	if req.Topics == nil && c.data.tps != nil {
-		for topic, ps := range c.data.tps {
-			id := c.data.t2id[topic]
-			for p, pd := range ps {
-				okp(topic, id, p, pd)
+	if req.Topics == nil {
+		for _, topic := range md.topics(c) {
+			if !c.allowedTopic(creq, topic, kmsg.ACLOperationDescribe) {
+				continue
+			}
+			mt, _ := md.topic(c, topic)
+			for p, mp := range mt.ps {
+				okp(topic, mt.id, p, mp)
 			}
 		}
 	}
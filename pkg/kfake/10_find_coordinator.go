@@ -1,17 +1,14 @@
 package kfake
 
 import (
-	"net"
-	"strconv"
-
 	"github.com/twmb/franz-go/pkg/kerr"
 	"github.com/twmb/franz-go/pkg/kmsg"
 )
 
 func init() { regKey(10, 0, 4) }
 
-func (c *Cluster) handleFindCoordinator(kreq kmsg.Request) (kmsg.Response, error) {
-	req := kreq.(*kmsg.FindCoordinatorRequest)
+func (c *Cluster) handleFindCoordinator(creq *clientReq) (kmsg.Response, error) {
+	req := creq.kreq.(*kmsg.FindCoordinatorRequest)
 	resp := req.ResponseKind().(*kmsg.FindCoordinatorResponse)
 
 	if err := checkReqVersion(req.Key(), req.Version); err != nil {
@@ -47,14 +44,23 @@ func (c *Cluster) handleFindCoordinator(kreq kmsg.Request) (kmsg.Response, error
 			sc.ErrorCode = kerr.InvalidRequest.Code
 			continue
 		}
+		if req.CoordinatorType == 0 && !c.allowedGroup(creq, key, kmsg.ACLOperationDescribe) {
+			sc.ErrorCode = kerr.GroupAuthorizationFailed.Code
+			continue
+		}
+		if req.CoordinatorType == 1 && !c.allowedTxnID(creq, key, kmsg.ACLOperationDescribe) {
+			sc.ErrorCode = kerr.TransactionalIDAuthorizationFailed.Code
+			continue
+		}
 
-		b := c.coordinator(key)
-		host, port, _ := net.SplitHostPort(b.ln.Addr().String())
-		iport, _ := strconv.Atoi(port)
+		if err := c.coordFault(key); err == kerr.CoordinatorNotAvailable {
+			sc.ErrorCode = err.Code
+			continue
+		}
 
+		b := c.coordinator(key)
 		sc.NodeID = b.node
-		sc.Host = host
-		sc.Port = int32(iport)
+		sc.Host, sc.Port = b.hostport(creq.cc.listener)
 	}
 
 	return resp, nil
@@ -5,6 +5,7 @@ import (
 	"sort"
 	"sync"
 
+	"github.com/twmb/franz-go/pkg/kerr"
 	"github.com/twmb/franz-go/pkg/kmsg"
 )
 
@@ -14,11 +15,13 @@ func (c *Cluster) handleApiVersions(kreq kmsg.Request) (kmsg.Response, error) {
 	req := kreq.(*kmsg.ApiVersionsRequest)
 	resp := req.ResponseKind().(*kmsg.ApiVersionsResponse)
 
+	// Like Kafka, if the version is too new, we reply with v0 and
+	// UNSUPPORTED_VERSION along with our keys, so that the client can
+	// retry with a version we support.
 	if resp.Version > 3 {
-		resp.Version = 0 // downgrades to 0 if the version is unknown
-	}
-
-	if err := checkReqVersion(req.Key(), req.Version); err != nil {
+		resp.Version = 0
+		resp.ErrorCode = kerr.UnsupportedVersion.Code
+	} else if err := checkReqVersion(req.Key(), req.Version); err != nil {
 		return nil, err
 	}
 
@@ -34,6 +37,15 @@ func (c *Cluster) handleApiVersions(kreq kmsg.Request) (kmsg.Response, error) {
 	})
 	resp.ApiKeys = apiVersionsSorted
 
+	if keys := c.handlerKeys(); len(keys) > 0 {
+		resp.ApiKeys = append(append([]kmsg.ApiVersionsResponseApiKey(nil), apiVersionsSorted...), keys...)
+		sort.Slice(resp.ApiKeys, func(i, j int) bool {
+			return resp.ApiKeys[i].ApiKey < resp.ApiKeys[j].ApiKey
+		})
+	}
+	resp.ApiKeys = c.withMaxVersions(c.withoutLegacyVersions(resp.ApiKeys))
+	c.downgradeApiVersions(resp)
+
 	return resp, nil
 }
 
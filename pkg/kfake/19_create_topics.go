@@ -5,14 +5,11 @@ import (
 	"github.com/twmb/franz-go/pkg/kmsg"
 )
 
-// TODO
-//
-// * Return InvalidTopicException when names collide
-
 func init() { regKey(19, 0, 7) }
 
-func (c *Cluster) handleCreateTopics(b *broker, kreq kmsg.Request) (kmsg.Response, error) {
-	req := kreq.(*kmsg.CreateTopicsRequest)
+func (c *Cluster) handleCreateTopics(creq *clientReq) (kmsg.Response, error) {
+	b := creq.cc.b
+	req := creq.kreq.(*kmsg.CreateTopicsRequest)
 	resp := req.ResponseKind().(*kmsg.CreateTopicsResponse)
 
 	if err := checkReqVersion(req.Key(), req.Version); err != nil {
@@ -46,11 +43,26 @@ func (c *Cluster) handleCreateTopics(b *broker, kreq kmsg.Request) (kmsg.Respons
 		uniq[rt.Topic] = struct{}{}
 	}
 
+	allowedCluster := c.allowedCluster(creq, kmsg.ACLOperationCreate)
 	for _, rt := range req.Topics {
+		if !allowedCluster && !c.allowedTopic(creq, rt.Topic, kmsg.ACLOperationCreate) {
+			donet(rt.Topic, kerr.TopicAuthorizationFailed.Code)
+			continue
+		}
 		if _, ok := c.data.tps.gett(rt.Topic); ok {
 			donet(rt.Topic, kerr.TopicAlreadyExists.Code)
 			continue
 		}
+		if c.data.isDeleting(rt.Topic) {
+			st := donet(rt.Topic, kerr.TopicAlreadyExists.Code)
+			st.ErrorMessage = kmsg.StringPtr("Topic '" + rt.Topic + "' is marked for deletion.")
+			continue
+		}
+		if err := c.data.validateNewTopic(rt.Topic); err != nil {
+			st := donet(rt.Topic, kerr.InvalidTopicException.Code)
+			st.ErrorMessage = kmsg.StringPtr(err.Error())
+			continue
+		}
 		if len(rt.ReplicaAssignment) > 0 {
 			donet(rt.Topic, kerr.InvalidReplicaAssignment.Code)
 			continue
@@ -63,22 +75,57 @@ func (c *Cluster) handleCreateTopics(b *broker, kreq kmsg.Request) (kmsg.Respons
 			donet(rt.Topic, kerr.InvalidPartitions.Code)
 			continue
 		}
+		if err := c.checkResourceLimits(1, c.newTopicPartitions(rt.Topic, int(rt.NumPartitions))); err != nil {
+			st := donet(rt.Topic, kerr.PolicyViolation.Code)
+			st.ErrorMessage = kmsg.StringPtr(err.Error())
+			continue
+		}
 		configs := make(map[string]*string)
-		for _, c := range rt.Configs {
-			configs[c.Name] = c.Value
+		var invalid error
+		for _, rc := range rt.Configs {
+			if err := checkTopicConfig(rc.Name, rc.Value, true); err != nil {
+				invalid = err
+				break
+			}
+			configs[rc.Name] = rc.Value
+		}
+		if invalid != nil {
+			st := donet(rt.Topic, configErrCode(invalid))
+			st.ErrorMessage = kmsg.StringPtr(invalid.Error())
+			continue
+		}
+		if req.ValidateOnly {
+			donet(rt.Topic, 0)
+			continue
 		}
 		c.data.mkt(rt.Topic, int(rt.NumPartitions), int(rt.ReplicationFactor), configs)
 		st := donet(rt.Topic, 0)
+		if req.TimeoutMillis <= 0 {
+			// As in Kafka, a non-positive timeout does not wait
+			// for the topic to be created: it is created, but we
+			// time out.
+			st.ErrorCode = kerr.RequestTimedOut.Code
+			continue
+		}
 		st.TopicID = c.data.t2id[rt.Topic]
 		st.NumPartitions = int32(len(c.data.tps[rt.Topic]))
 		st.ReplicationFactor = int16(c.data.treplicas[rt.Topic])
-		for k, v := range configs {
-			c := kmsg.NewCreateTopicsResponseTopicConfig()
-			c.Name = k
-			c.Value = v
-			// Source?
-			st.Configs = append(st.Configs, c)
-		}
+		nameIdxs := make(map[string]int)
+		c.data.configs(rt.Topic, func(k string, v *string, src kmsg.ConfigSource, sensitive bool) {
+			rc := kmsg.NewCreateTopicsResponseTopicConfig()
+			rc.Name = k
+			rc.Value = v
+			rc.Source = int8(src)
+			rc.IsSensitive = sensitive
+			// Later sources override earlier ones, as in
+			// DescribeConfigs.
+			if idx, ok := nameIdxs[k]; ok {
+				st.Configs[idx] = rc
+				return
+			}
+			nameIdxs[k] = len(st.Configs)
+			st.Configs = append(st.Configs, rc)
+		})
 	}
 
 	return resp, nil
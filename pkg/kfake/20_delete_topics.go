@@ -7,8 +7,9 @@ import (
 
 func init() { regKey(20, 0, 6) }
 
-func (c *Cluster) handleDeleteTopics(b *broker, kreq kmsg.Request) (kmsg.Response, error) {
-	req := kreq.(*kmsg.DeleteTopicsRequest)
+func (c *Cluster) handleDeleteTopics(creq *clientReq) (kmsg.Response, error) {
+	b := creq.cc.b
+	req := creq.kreq.(*kmsg.DeleteTopicsRequest)
 	resp := req.ResponseKind().(*kmsg.DeleteTopicsResponse)
 
 	if err := checkReqVersion(req.Key(), req.Version); err != nil {
@@ -55,10 +56,8 @@ func (c *Cluster) handleDeleteTopics(b *broker, kreq kmsg.Request) (kmsg.Respons
 	var toDeletes []toDelete
 	defer func() {
 		for _, td := range toDeletes {
-			delete(c.data.tps, td.topic)
-			delete(c.data.id2t, td.id)
-			delete(c.data.t2id, td.topic)
-
+			c.data.deleteTopic(td.topic)
+			c.data.markDeleting(td.topic)
 		}
 	}()
 	for _, rt := range req.Topics {
@@ -71,8 +70,11 @@ func (c *Cluster) handleDeleteTopics(b *broker, kreq kmsg.Request) (kmsg.Respons
 			topic = c.data.id2t[rt.TopicID]
 			id = rt.TopicID
 		}
-		t, ok := c.data.tps.gett(topic)
-		if !ok {
+		if !c.allowedTopic(creq, topic, kmsg.ACLOperationDelete) {
+			donet(&topic, id, kerr.TopicAuthorizationFailed.Code)
+			continue
+		}
+		if _, ok := c.data.tps.gett(topic); !ok {
 			if rt.Topic != nil {
 				donet(&topic, id, kerr.UnknownTopicOrPartition.Code)
 			} else {
@@ -81,13 +83,14 @@ func (c *Cluster) handleDeleteTopics(b *broker, kreq kmsg.Request) (kmsg.Respons
 			continue
 		}
 
-		donet(&topic, id, 0)
-		toDeletes = append(toDeletes, toDelete{topic, id})
-		for _, pd := range t {
-			for watch := range pd.watch {
-				watch.deleted()
-			}
+		// As in Kafka, a non-positive timeout does not wait for the
+		// deletion to finish: the topic is deleted, but we time out.
+		if req.TimeoutMillis <= 0 {
+			donet(&topic, id, kerr.RequestTimedOut.Code)
+		} else {
+			donet(&topic, id, 0)
 		}
+		toDeletes = append(toDeletes, toDelete{topic, id})
 	}
 
 	return resp, nil
@@ -1,6 +1,8 @@
 package kfake
 
 import (
+	"strings"
+
 	"github.com/twmb/franz-go/pkg/kerr"
 	"github.com/twmb/franz-go/pkg/kmsg"
 )
@@ -11,8 +13,9 @@ import (
 
 func init() { regKey(21, 0, 2) }
 
-func (c *Cluster) handleDeleteRecords(b *broker, kreq kmsg.Request) (kmsg.Response, error) {
-	req := kreq.(*kmsg.DeleteRecordsRequest)
+func (c *Cluster) handleDeleteRecords(creq *clientReq) (kmsg.Response, error) {
+	b := creq.cc.b
+	req := creq.kreq.(*kmsg.DeleteRecordsRequest)
 	resp := req.ResponseKind().(*kmsg.DeleteRecordsResponse)
 
 	if err := checkReqVersion(req.Key(), req.Version); err != nil {
@@ -41,7 +44,12 @@ func (c *Cluster) handleDeleteRecords(b *broker, kreq kmsg.Request) (kmsg.Respon
 
 	for _, rt := range req.Topics {
 		ps, ok := c.data.tps.gett(rt.Topic)
+		allowed := c.allowedTopic(creq, rt.Topic, kmsg.ACLOperationDelete)
 		for _, rp := range rt.Partitions {
+			if !allowed {
+				donep(rt.Topic, rp.Partition, kerr.TopicAuthorizationFailed.Code)
+				continue
+			}
 			if !ok {
 				donep(rt.Topic, rp.Partition, kerr.UnknownTopicOrPartition.Code)
 				continue
@@ -55,18 +63,28 @@ func (c *Cluster) handleDeleteRecords(b *broker, kreq kmsg.Request) (kmsg.Respon
 				donep(rt.Topic, rp.Partition, kerr.NotLeaderForPartition.Code)
 				continue
 			}
+			if !strings.Contains(c.data.config(rt.Topic, "cleanup.policy"), "delete") {
+				// As in Kafka, records cannot be deleted from
+				// compacted topics.
+				donep(rt.Topic, rp.Partition, kerr.PolicyViolation.Code)
+				continue
+			}
 			to := rp.Offset
 			if to == -1 {
 				to = pd.highWatermark
 			}
-			if to < pd.logStartOffset || to > pd.highWatermark {
+			if to < 0 || to > pd.highWatermark {
 				donep(rt.Topic, rp.Partition, kerr.OffsetOutOfRange.Code)
 				continue
 			}
-			pd.logStartOffset = to
-			pd.trimLeft()
+			// Deleting below the log start offset is a no-op that
+			// returns the current log start offset.
+			if to > pd.logStartOffset {
+				pd.logStartOffset = to
+				pd.trimLeft()
+			}
 			sp := donep(rt.Topic, rp.Partition, 0)
-			sp.LowWatermark = to
+			sp.LowWatermark = pd.logStartOffset
 		}
 	}
 
@@ -0,0 +1,69 @@
+package kfake
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func TestDeleteRecordsEdges(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(1), SeedTopics(1, "t", "c"))
+	adm := newTestAdmin(t, c)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	compact := "compact"
+	c.admin(func() { _ = c.data.setTopicConfig("c", "cleanup.policy", &compact, false) })
+	for _, topic := range []string{"t", "c"} {
+		if _, err := c.ProduceTo(topic, 0, kgo.StringRecord("a"), kgo.StringRecord("b"), kgo.StringRecord("c")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	deleteTo := func(topic string, at int64) kadm.DeleteRecordsResponse {
+		t.Helper()
+		os := kadm.Offsets{}
+		os.Add(kadm.Offset{Topic: topic, Partition: 0, At: at})
+		rs, err := adm.DeleteRecords(ctx, os)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r, err := rs.On(topic, 0, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return r
+	}
+
+	for _, test := range []struct {
+		name  string
+		topic string
+		at    int64
+		exp   error
+		low   int64
+	}{
+		{"delete to 2", "t", 2, nil, 2},
+		{"below the log start", "t", 1, nil, 2},
+		{"past the high watermark", "t", 4, kerr.OffsetOutOfRange, 0},
+		{"to the high watermark", "t", -1, nil, 3},
+		{"compacted topic", "c", 1, kerr.PolicyViolation, 0},
+	} {
+		r := deleteTo(test.topic, test.at)
+		if !errors.Is(r.Err, test.exp) {
+			t.Errorf("%s: got %v, expected %v", test.name, r.Err, test.exp)
+			continue
+		}
+		if test.exp == nil && r.LowWatermark != test.low {
+			t.Errorf("%s: got low watermark %d, expected %d", test.name, r.LowWatermark, test.low)
+		}
+	}
+
+	if rs, err := c.ReadRecords("c", 0, 0, 0); err != nil || len(rs) != 3 {
+		t.Errorf("compacted topic: got %d records and err %v, expected all 3", len(rs), err)
+	}
+}
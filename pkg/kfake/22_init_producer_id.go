@@ -7,14 +7,13 @@ import (
 
 // TODO
 //
-// * Transactional IDs
-// * v3+
+// * v3+ epoch bumping for idempotent (non-transactional) producers
 
 func init() { regKey(22, 0, 4) }
 
-func (c *Cluster) handleInitProducerID(kreq kmsg.Request) (kmsg.Response, error) {
+func (c *Cluster) handleInitProducerID(creq *clientReq) (kmsg.Response, error) {
 	var (
-		req  = kreq.(*kmsg.InitProducerIDRequest)
+		req  = creq.kreq.(*kmsg.InitProducerIDRequest)
 		resp = req.ResponseKind().(*kmsg.InitProducerIDResponse)
 	)
 
@@ -23,11 +22,27 @@ func (c *Cluster) handleInitProducerID(kreq kmsg.Request) (kmsg.Response, error)
 	}
 
 	if req.TransactionalID != nil {
-		resp.ErrorCode = kerr.UnknownServerError.Code
+		if err := c.validateTxn(creq, *req.TransactionalID); err != nil {
+			resp.ErrorCode = err.Code
+			return resp, nil
+		}
+		t, err := c.initTxn(*req.TransactionalID, req.TransactionTimeoutMillis, req.ProducerID, req.ProducerEpoch)
+		if err != nil {
+			resp.ErrorCode = err.Code
+			return resp, nil
+		}
+		resp.ProducerID = t.pid
+		resp.ProducerEpoch = t.epoch
 		return resp, nil
 	}
 
-	pid := c.pids.create(nil)
+	// As in Kafka, idempotent producers need IDEMPOTENT_WRITE on the
+	// cluster or WRITE on any topic.
+	if !c.allowedCluster(creq, kmsg.ACLOperationIdempotentWrite) && !c.allowedAny(creq, kmsg.ACLResourceTypeTopic, kmsg.ACLOperationWrite) {
+		resp.ErrorCode = kerr.ClusterAuthorizationFailed.Code
+		return resp, nil
+	}
+	pid := c.pids.create(nil, c.now())
 	resp.ProducerID = pid.id
 	resp.ProducerEpoch = pid.epoch
 	return resp, nil
@@ -37,6 +37,7 @@ func (c *Cluster) handleOffsetForLeaderEpoch(b *broker, kreq kmsg.Request) (kmsg
 		return &st.Partitions[len(st.Partitions)-1]
 	}
 
+	md := c.metadataFor(b) // nil unless this broker is serving stale metadata
 	for _, rt := range req.Topics {
 		ps, ok := c.data.tps.gett(rt.Topic)
 		for _, rp := range rt.Partitions {
@@ -49,7 +50,7 @@ func (c *Cluster) handleOffsetForLeaderEpoch(b *broker, kreq kmsg.Request) (kmsg
 				continue
 			}
 			pd, ok := ps[rp.Partition]
-			if !ok {
+			if !ok || !md.hasPartition(c, rt.Topic, rp.Partition) {
 				donep(rt.Topic, rp.Partition, kerr.UnknownTopicOrPartition.Code)
 				continue
 			}
@@ -57,7 +58,8 @@ func (c *Cluster) handleOffsetForLeaderEpoch(b *broker, kreq kmsg.Request) (kmsg
 				donep(rt.Topic, rp.Partition, kerr.NotLeaderForPartition.Code)
 				continue
 			}
-			if rp.CurrentLeaderEpoch < pd.epoch {
+			// A current leader epoch of -1 skips fencing.
+			if rp.CurrentLeaderEpoch != -1 && rp.CurrentLeaderEpoch < pd.epoch {
 				donep(rt.Topic, rp.Partition, kerr.FencedLeaderEpoch.Code)
 				continue
 			} else if rp.CurrentLeaderEpoch > pd.epoch {
@@ -74,6 +76,11 @@ func (c *Cluster) handleOffsetForLeaderEpoch(b *broker, kreq kmsg.Request) (kmsg
 				continue
 			}
 
+			if pd.epochHistory != nil {
+				sp.LeaderEpoch, sp.EndOffset = pd.historyEpochEnd(rp.LeaderEpoch)
+				continue
+			}
+
 			// If our epoch was bumped before anything was
 			// produced, return the epoch and a start offset of 0.
 			if len(pd.batches) == 0 {
@@ -119,3 +126,24 @@ func (c *Cluster) handleOffsetForLeaderEpoch(b *broker, kreq kmsg.Request) (kmsg
 	}
 	return resp, nil
 }
+
+// historyEpochEnd returns the end offset for the requested epoch from the
+// partition's fabricated epoch history. The requested epoch is never our
+// current epoch.
+func (pd *partData) historyEpochEnd(epoch int32) (int32, int64) {
+	if epoch > pd.epoch {
+		return -1, -1
+	}
+	floor, ok := int32(-1), false
+	for e := range pd.epochHistory {
+		if e <= epoch && (!ok || e > floor) {
+			floor, ok = e, true
+		}
+	}
+	if ok {
+		return floor, pd.epochHistory[floor]
+	}
+	// The requested epoch is before anything in our history: like
+	// Kafka, we return the requested epoch and the log start offset.
+	return epoch, pd.logStartOffset
+}
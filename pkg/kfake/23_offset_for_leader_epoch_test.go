@@ -0,0 +1,75 @@
+package kfake
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestSetEpochHistory(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(1), SeedTopics(1, "t"))
+	cl := newTestClient(t, c)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := c.SetEpochHistory("t", 1, map[int32]int64{0: 1}); err == nil {
+		t.Error("SetEpochHistory on a missing partition did not fail")
+	}
+	for i := 0; i < 10; i++ {
+		if _, err := c.ProduceTo("t", 0, kgo.StringRecord("v")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The history ends epoch 0 at 4 and epoch 2 at 7, bumping the
+	// current epoch to 3.
+	if err := c.SetEpochHistory("t", 0, map[int32]int64{0: 4, 2: 7}); err != nil {
+		t.Fatal(err)
+	}
+	var epoch int32
+	c.admin(func() {
+		pd, _ := c.data.tps.getp("t", 0)
+		epoch = pd.epoch
+	})
+	if epoch != 3 {
+		t.Fatalf("got current epoch %d, expected 3", epoch)
+	}
+
+	req := kmsg.NewPtrOffsetForLeaderEpochRequest()
+	req.ReplicaID = -1
+	rt := kmsg.NewOffsetForLeaderEpochRequestTopic()
+	rt.Topic = "t"
+	for _, e := range []int32{0, 1, 2, 3, 4} {
+		rp := kmsg.NewOffsetForLeaderEpochRequestTopicPartition()
+		rp.CurrentLeaderEpoch = -1
+		rp.LeaderEpoch = e
+		rt.Partitions = append(rt.Partitions, rp)
+	}
+	req.Topics = append(req.Topics, rt)
+	resp, err := req.RequestWith(ctx, cl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, exp := range []struct {
+		epoch int32
+		end   int64
+	}{
+		{0, 4},
+		{0, 4}, // epoch 1 is not in the history, so epoch 0 answers
+		{2, 7},
+		{3, 10},  // the current epoch ends at the high watermark
+		{-1, -1}, // unknown future epoch
+	} {
+		sp := resp.Topics[0].Partitions[i]
+		if err := kerr.ErrorForCode(sp.ErrorCode); err != nil {
+			t.Fatal(err)
+		}
+		if sp.LeaderEpoch != exp.epoch || sp.EndOffset != exp.end {
+			t.Errorf("epoch %d: got epoch %d end %d, expected %d %d", i, sp.LeaderEpoch, sp.EndOffset, exp.epoch, exp.end)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package kfake
+
+import (
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func init() { regKey(24, 0, 3) }
+
+func (c *Cluster) handleAddPartitionsToTxn(creq *clientReq) (kmsg.Response, error) {
+	req := creq.kreq.(*kmsg.AddPartitionsToTxnRequest)
+	resp := req.ResponseKind().(*kmsg.AddPartitionsToTxnResponse)
+
+	if err := checkReqVersion(req.Key(), req.Version); err != nil {
+		return nil, err
+	}
+
+	donets := func(errCode int16) {
+		for _, rt := range req.Topics {
+			st := kmsg.NewAddPartitionsToTxnResponseTopic()
+			st.Topic = rt.Topic
+			for _, p := range rt.Partitions {
+				sp := kmsg.NewAddPartitionsToTxnResponseTopicPartition()
+				sp.Partition = p
+				sp.ErrorCode = errCode
+				st.Partitions = append(st.Partitions, sp)
+			}
+			resp.Topics = append(resp.Topics, st)
+		}
+	}
+
+	if err := c.validateTxn(creq, req.TransactionalID); err != nil {
+		donets(err.Code)
+		return resp, nil
+	}
+	t, err := c.validateTxnProducer(req.TransactionalID, req.ProducerID, req.ProducerEpoch)
+	if err != nil {
+		donets(err.Code)
+		return resp, nil
+	}
+
+	// If any partition is unauthorized or does not exist, nothing is
+	// added: those partitions fail and every other partition is not
+	// attempted.
+	partErr := func(topic string, p int32) int16 {
+		if !c.allowedTopic(creq, topic, kmsg.ACLOperationWrite) {
+			return kerr.TopicAuthorizationFailed.Code
+		}
+		if _, ok := c.data.tps.getp(topic, p); !ok {
+			return kerr.UnknownTopicOrPartition.Code
+		}
+		return 0
+	}
+	var failed bool
+	for _, rt := range req.Topics {
+		for _, p := range rt.Partitions {
+			if partErr(rt.Topic, p) != 0 {
+				failed = true
+			}
+		}
+	}
+	if failed {
+		for _, rt := range req.Topics {
+			st := kmsg.NewAddPartitionsToTxnResponseTopic()
+			st.Topic = rt.Topic
+			for _, p := range rt.Partitions {
+				sp := kmsg.NewAddPartitionsToTxnResponseTopicPartition()
+				sp.Partition = p
+				sp.ErrorCode = kerr.OperationNotAttempted.Code
+				if errCode := partErr(rt.Topic, p); errCode != 0 {
+					sp.ErrorCode = errCode
+				}
+				st.Partitions = append(st.Partitions, sp)
+			}
+			resp.Topics = append(resp.Topics, st)
+		}
+		return resp, nil
+	}
+
+	c.beginTxn(t)
+	for _, rt := range req.Topics {
+		for _, p := range rt.Partitions {
+			t.parts.set(rt.Topic, p, struct{}{})
+		}
+	}
+	donets(0)
+	return resp, nil
+}
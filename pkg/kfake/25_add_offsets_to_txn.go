@@ -0,0 +1,42 @@
+package kfake
+
+import (
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func init() { regKey(25, 0, 3) }
+
+func (c *Cluster) handleAddOffsetsToTxn(creq *clientReq) (kmsg.Response, error) {
+	req := creq.kreq.(*kmsg.AddOffsetsToTxnRequest)
+	resp := req.ResponseKind().(*kmsg.AddOffsetsToTxnResponse)
+
+	if err := checkReqVersion(req.Key(), req.Version); err != nil {
+		return nil, err
+	}
+
+	if err := c.validateTxn(creq, req.TransactionalID); err != nil {
+		resp.ErrorCode = err.Code
+		return resp, nil
+	}
+	if req.Group == "" {
+		resp.ErrorCode = kerr.InvalidGroupID.Code
+		return resp, nil
+	}
+	if !c.allowedGroup(creq, req.Group, kmsg.ACLOperationRead) {
+		resp.ErrorCode = kerr.GroupAuthorizationFailed.Code
+		return resp, nil
+	}
+	t, err := c.validateTxnProducer(req.TransactionalID, req.ProducerID, req.ProducerEpoch)
+	if err != nil {
+		resp.ErrorCode = err.Code
+		return resp, nil
+	}
+
+	c.beginTxn(t)
+	if t.groups == nil {
+		t.groups = make(map[string]struct{})
+	}
+	t.groups[req.Group] = struct{}{}
+	return resp, nil
+}
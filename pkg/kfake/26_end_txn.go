@@ -0,0 +1,39 @@
+package kfake
+
+import (
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func init() { regKey(26, 0, 3) }
+
+func (c *Cluster) handleEndTxn(creq *clientReq) (kmsg.Response, error) {
+	req := creq.kreq.(*kmsg.EndTxnRequest)
+	resp := req.ResponseKind().(*kmsg.EndTxnResponse)
+
+	if err := checkReqVersion(req.Key(), req.Version); err != nil {
+		return nil, err
+	}
+
+	if err := c.validateTxn(creq, req.TransactionalID); err != nil {
+		resp.ErrorCode = err.Code
+		return resp, nil
+	}
+	t, err := c.validateTxnProducer(req.TransactionalID, req.ProducerID, req.ProducerEpoch)
+	if err != nil {
+		resp.ErrorCode = err.Code
+		return resp, nil
+	}
+
+	if !t.ongoing() {
+		// A retry of an end that already completed succeeds, as in
+		// Kafka; anything else is ending a transaction that never
+		// began.
+		if !t.ended || t.lastCommit != req.Commit {
+			resp.ErrorCode = kerr.InvalidTxnState.Code
+		}
+		return resp, nil
+	}
+	c.endTxn(t, req.Commit)
+	return resp, nil
+}
@@ -0,0 +1,29 @@
+package kfake
+
+import (
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func init() { regKey(28, 0, 3) }
+
+func (c *Cluster) handleTxnOffsetCommit(creq *clientReq) (kmsg.Response, error) {
+	req := creq.kreq.(*kmsg.TxnOffsetCommitRequest)
+	resp := req.ResponseKind().(*kmsg.TxnOffsetCommitResponse)
+
+	if err := checkReqVersion(req.Key(), req.Version); err != nil {
+		return nil, err
+	}
+
+	if !c.allowedTxnID(creq, req.TransactionalID, kmsg.ACLOperationWrite) {
+		fillTxnOffsetCommit(req, resp, kerr.TransactionalIDAuthorizationFailed.Code)
+		return resp, nil
+	}
+	if _, err := c.validateTxnProducer(req.TransactionalID, req.ProducerID, req.ProducerEpoch); err != nil {
+		fillTxnOffsetCommit(req, resp, err.Code)
+		return resp, nil
+	}
+
+	c.groups.handleTxnOffsetCommit(creq)
+	return nil, nil
+}
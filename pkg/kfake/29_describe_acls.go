@@ -0,0 +1,68 @@
+package kfake
+
+import (
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func init() { regKey(29, 0, 3) }
+
+func (c *Cluster) handleDescribeACLs(creq *clientReq) (kmsg.Response, error) {
+	var (
+		req  = creq.kreq.(*kmsg.DescribeACLsRequest)
+		resp = req.ResponseKind().(*kmsg.DescribeACLsResponse)
+	)
+
+	if err := checkReqVersion(req.Key(), req.Version); err != nil {
+		return nil, err
+	}
+
+	if !c.allowedCluster(creq, kmsg.ACLOperationDescribe) {
+		resp.ErrorCode = kerr.ClusterAuthorizationFailed.Code
+		return resp, nil
+	}
+
+	f := aclFilter{
+		resourceType: req.ResourceType,
+		name:         req.ResourceName,
+		pattern:      req.ResourcePatternType,
+		principal:    req.Principal,
+		host:         req.Host,
+		operation:    req.Operation,
+		permission:   req.PermissionType,
+	}
+	if code, msg := f.validate(); code != 0 {
+		resp.ErrorCode = code
+		resp.ErrorMessage = &msg
+		return resp, nil
+	}
+
+	type resource struct {
+		t       kmsg.ACLResourceType
+		name    string
+		pattern kmsg.ACLResourcePatternType
+	}
+	idx := make(map[resource]int)
+	c.aclsMu.RLock()
+	defer c.aclsMu.RUnlock()
+	for _, a := range c.acls.filter(f) {
+		r := resource{a.resourceType, a.name, a.pattern}
+		i, ok := idx[r]
+		if !ok {
+			i = len(resp.Resources)
+			idx[r] = i
+			sr := kmsg.NewDescribeACLsResponseResource()
+			sr.ResourceType = a.resourceType
+			sr.ResourceName = a.name
+			sr.ResourcePatternType = a.pattern
+			resp.Resources = append(resp.Resources, sr)
+		}
+		sa := kmsg.NewDescribeACLsResponseResourceACL()
+		sa.Principal = a.principal
+		sa.Host = a.host
+		sa.Operation = a.operation
+		sa.PermissionType = a.permission
+		resp.Resources[i].ACLs = append(resp.Resources[i].ACLs, sa)
+	}
+	return resp, nil
+}
@@ -0,0 +1,48 @@
+package kfake
+
+import (
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func init() { regKey(30, 0, 3) }
+
+func (c *Cluster) handleCreateACLs(creq *clientReq) (kmsg.Response, error) {
+	var (
+		req  = creq.kreq.(*kmsg.CreateACLsRequest)
+		resp = req.ResponseKind().(*kmsg.CreateACLsResponse)
+	)
+
+	if err := checkReqVersion(req.Key(), req.Version); err != nil {
+		return nil, err
+	}
+
+	allowed := c.allowedCluster(creq, kmsg.ACLOperationAlter)
+	c.aclsMu.Lock()
+	defer c.aclsMu.Unlock()
+	for _, rc := range req.Creations {
+		sr := kmsg.NewCreateACLsResponseResult()
+		if !allowed {
+			sr.ErrorCode = kerr.ClusterAuthorizationFailed.Code
+			resp.Results = append(resp.Results, sr)
+			continue
+		}
+		a := acl{
+			resourceType: rc.ResourceType,
+			name:         rc.ResourceName,
+			pattern:      rc.ResourcePatternType,
+			principal:    rc.Principal,
+			host:         rc.Host,
+			operation:    rc.Operation,
+			permission:   rc.PermissionType,
+		}
+		if code, msg := a.validateCreate(); code != 0 {
+			sr.ErrorCode = code
+			sr.ErrorMessage = &msg
+		} else {
+			c.acls.add(a)
+		}
+		resp.Results = append(resp.Results, sr)
+	}
+	return resp, nil
+}
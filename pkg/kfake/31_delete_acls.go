@@ -0,0 +1,59 @@
+package kfake
+
+import (
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func init() { regKey(31, 0, 3) }
+
+func (c *Cluster) handleDeleteACLs(creq *clientReq) (kmsg.Response, error) {
+	var (
+		req  = creq.kreq.(*kmsg.DeleteACLsRequest)
+		resp = req.ResponseKind().(*kmsg.DeleteACLsResponse)
+	)
+
+	if err := checkReqVersion(req.Key(), req.Version); err != nil {
+		return nil, err
+	}
+
+	allowed := c.allowedCluster(creq, kmsg.ACLOperationAlter)
+	c.aclsMu.Lock()
+	defer c.aclsMu.Unlock()
+	for _, rf := range req.Filters {
+		sr := kmsg.NewDeleteACLsResponseResult()
+		if !allowed {
+			sr.ErrorCode = kerr.ClusterAuthorizationFailed.Code
+			resp.Results = append(resp.Results, sr)
+			continue
+		}
+		f := aclFilter{
+			resourceType: rf.ResourceType,
+			name:         rf.ResourceName,
+			pattern:      rf.ResourcePatternType,
+			principal:    rf.Principal,
+			host:         rf.Host,
+			operation:    rf.Operation,
+			permission:   rf.PermissionType,
+		}
+		if code, msg := f.validate(); code != 0 {
+			sr.ErrorCode = code
+			sr.ErrorMessage = &msg
+			resp.Results = append(resp.Results, sr)
+			continue
+		}
+		for _, a := range c.acls.delete(f) {
+			sm := kmsg.NewDeleteACLsResponseResultMatchingACL()
+			sm.ResourceType = a.resourceType
+			sm.ResourceName = a.name
+			sm.ResourcePatternType = a.pattern
+			sm.Principal = a.principal
+			sm.Host = a.host
+			sm.Operation = a.operation
+			sm.PermissionType = a.permission
+			sr.MatchingACLs = append(sr.MatchingACLs, sm)
+		}
+		resp.Results = append(resp.Results, sr)
+	}
+	return resp, nil
+}
@@ -9,8 +9,9 @@ import (
 
 func init() { regKey(32, 0, 4) }
 
-func (c *Cluster) handleDescribeConfigs(b *broker, kreq kmsg.Request) (kmsg.Response, error) {
-	req := kreq.(*kmsg.DescribeConfigsRequest)
+func (c *Cluster) handleDescribeConfigs(creq *clientReq) (kmsg.Response, error) {
+	b := creq.cc.b
+	req := creq.kreq.(*kmsg.DescribeConfigsRequest)
 	resp := req.ResponseKind().(*kmsg.DescribeConfigsResponse)
 
 	if err := checkReqVersion(req.Key(), req.Version); err != nil {
@@ -75,6 +76,10 @@ func (c *Cluster) handleDescribeConfigs(b *broker, kreq kmsg.Request) (kmsg.Resp
 outer:
 	for i := range req.Resources {
 		rr := &req.Resources[i]
+		if errCode := c.configAuthErr(creq, rr.ResourceType, rr.ResourceName, kmsg.ACLOperationDescribeConfigs); errCode != 0 {
+			doner(rr.ResourceName, rr.ResourceType, errCode)
+			continue
+		}
 		switch rr.ResourceType {
 		case kmsg.ConfigResourceTypeBroker:
 			id := int32(-1)
@@ -9,8 +9,9 @@ import (
 
 func init() { regKey(33, 0, 2) }
 
-func (c *Cluster) handleAlterConfigs(b *broker, kreq kmsg.Request) (kmsg.Response, error) {
-	req := kreq.(*kmsg.AlterConfigsRequest)
+func (c *Cluster) handleAlterConfigs(creq *clientReq) (kmsg.Response, error) {
+	b := creq.cc.b
+	req := creq.kreq.(*kmsg.AlterConfigsRequest)
 	resp := req.ResponseKind().(*kmsg.AlterConfigsResponse)
 
 	if err := checkReqVersion(req.Key(), req.Version); err != nil {
@@ -29,6 +30,10 @@ func (c *Cluster) handleAlterConfigs(b *broker, kreq kmsg.Request) (kmsg.Respons
 outer:
 	for i := range req.Resources {
 		rr := &req.Resources[i]
+		if errCode := c.configAuthErr(creq, rr.ResourceType, rr.ResourceName, kmsg.ACLOperationAlterConfigs); errCode != 0 {
+			doner(rr.ResourceName, rr.ResourceType, errCode)
+			continue
+		}
 		switch rr.ResourceType {
 		case kmsg.ConfigResourceTypeBroker:
 			id := int32(-1)
@@ -40,13 +45,16 @@ outer:
 					continue outer
 				}
 			}
-			var invalid bool
+			var invalid error
 			for i := range rr.Configs {
 				rc := &rr.Configs[i]
-				invalid = invalid || !c.setBrokerConfig(rc.Name, rc.Value, true)
+				if err := c.setBrokerConfig(rc.Name, rc.Value, true); err != nil && invalid == nil {
+					invalid = err
+				}
 			}
-			if invalid {
-				doner(rr.ResourceName, rr.ResourceType, kerr.InvalidRequest.Code)
+			if invalid != nil {
+				r := doner(rr.ResourceName, rr.ResourceType, configErrCode(invalid))
+				r.ErrorMessage = kmsg.StringPtr(invalid.Error())
 				continue
 			}
 			doner(rr.ResourceName, rr.ResourceType, 0)
@@ -64,13 +72,16 @@ outer:
 				doner(rr.ResourceName, rr.ResourceType, kerr.UnknownTopicOrPartition.Code)
 				continue
 			}
-			var invalid bool
+			var invalid error
 			for i := range rr.Configs {
 				rc := &rr.Configs[i]
-				invalid = invalid || !c.data.setTopicConfig(rr.ResourceName, rc.Name, rc.Value, true)
+				if err := c.data.setTopicConfig(rr.ResourceName, rc.Name, rc.Value, true); err != nil && invalid == nil {
+					invalid = err
+				}
 			}
-			if invalid {
-				doner(rr.ResourceName, rr.ResourceType, kerr.InvalidRequest.Code)
+			if invalid != nil {
+				r := doner(rr.ResourceName, rr.ResourceType, configErrCode(invalid))
+				r.ErrorMessage = kmsg.StringPtr(invalid.Error())
 				continue
 			}
 			doner(rr.ResourceName, rr.ResourceType, 0)
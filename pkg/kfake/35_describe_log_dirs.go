@@ -1,70 +1,94 @@
 package kfake
 
 import (
+	"sort"
+
+	"github.com/twmb/franz-go/pkg/kerr"
 	"github.com/twmb/franz-go/pkg/kmsg"
 )
 
+// Behavior:
+//
+// * Each broker describes the partitions it is a replica of; replicas share
+//   the leader's log, so every replica reports the same size
+// * A partition's size is the bytes of its stored record batches
+// * Every broker has the default log dir, even if it hosts no partitions
+// * Each dir has logDirBytes of total space, less the size of its partitions
+// * Requested partitions that the broker does not host are skipped
+
 func init() { regKey(35, 0, 4) }
 
-func (c *Cluster) handleDescribeLogDirs(b *broker, kreq kmsg.Request) (kmsg.Response, error) {
-	req := kreq.(*kmsg.DescribeLogDirsRequest)
+// logDirBytes is the total space of every log dir.
+const logDirBytes = 32 << 30
+
+func (c *Cluster) handleDescribeLogDirs(creq *clientReq) (kmsg.Response, error) {
+	b := creq.cc.b
+	req := creq.kreq.(*kmsg.DescribeLogDirsRequest)
 	resp := req.ResponseKind().(*kmsg.DescribeLogDirsResponse)
 
 	if err := checkReqVersion(req.Key(), req.Version); err != nil {
 		return nil, err
 	}
 
-	totalSpace := make(map[string]int64)
-	individual := make(map[string]map[string]map[int32]int64)
+	// Before v3, Kafka replies with no dirs if unauthorized.
+	if !c.allowedCluster(creq, kmsg.ACLOperationDescribe) {
+		if req.Version >= 3 {
+			resp.ErrorCode = kerr.ClusterAuthorizationFailed.Code
+		}
+		return resp, nil
+	}
+
+	var requested tps[struct{}]
+	for _, rt := range req.Topics {
+		for _, p := range rt.Partitions {
+			requested.set(rt.Topic, p, struct{}{})
+		}
+	}
 
-	add := func(d string, t string, p int32, s int64) {
-		totalSpace[d] += s
-		ts, ok := individual[d]
-		if !ok {
+	used := map[string]int64{defLogDir: 0}
+	hosted := make(map[string]map[string]map[int32]int64) // dir => topic => partition => size
+	c.data.tps.each(func(t string, p int32, pd *partData) {
+		if !contains(c.replicas(t, pd), b.node) {
+			return
+		}
+		used[pd.dir] += pd.nbytes
+		if _, ok := requested.getp(t, p); req.Topics != nil && !ok {
+			return
+		}
+		ts := hosted[pd.dir]
+		if ts == nil {
 			ts = make(map[string]map[int32]int64)
-			individual[d] = ts
+			hosted[pd.dir] = ts
 		}
-		ps, ok := ts[t]
-		if !ok {
+		ps := ts[t]
+		if ps == nil {
 			ps = make(map[int32]int64)
 			ts[t] = ps
 		}
-		ps[p] += s
-	}
-
-	if req.Topics == nil {
-		c.data.tps.each(func(t string, p int32, d *partData) {
-			add(d.dir, t, p, d.nbytes)
-		})
-	} else {
-		for _, t := range req.Topics {
-			for _, p := range t.Partitions {
-				d, ok := c.data.tps.getp(t.Topic, p)
-				if ok {
-					add(d.dir, t.Topic, p, d.nbytes)
-				}
-			}
-		}
-	}
+		ps[p] = pd.nbytes
+	})
 
-	for dir, ts := range individual {
+	for dir, n := range used {
 		rd := kmsg.NewDescribeLogDirsResponseDir()
 		rd.Dir = dir
-		rd.TotalBytes = totalSpace[dir]
-		rd.UsableBytes = 32 << 30
-		for t, ps := range ts {
+		rd.TotalBytes = logDirBytes
+		rd.UsableBytes = max(logDirBytes-n, 0)
+		for t, ps := range hosted[dir] {
 			rt := kmsg.NewDescribeLogDirsResponseDirTopic()
 			rt.Topic = t
-			for p, s := range ps {
+			for p, size := range ps {
 				rp := kmsg.NewDescribeLogDirsResponseDirTopicPartition()
 				rp.Partition = p
-				rp.Size = s
+				rp.Size = size
 				rt.Partitions = append(rt.Partitions, rp)
 			}
+			sort.Slice(rt.Partitions, func(i, j int) bool { return rt.Partitions[i].Partition < rt.Partitions[j].Partition })
 			rd.Topics = append(rd.Topics, rt)
 		}
+		sort.Slice(rd.Topics, func(i, j int) bool { return rd.Topics[i].Topic < rd.Topics[j].Topic })
 		resp.Dirs = append(resp.Dirs, rd)
 	}
+	sort.Slice(resp.Dirs, func(i, j int) bool { return resp.Dirs[i].Dir < resp.Dirs[j].Dir })
 
 	return resp, nil
 }
@@ -0,0 +1,79 @@
+package kfake
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestDescribeLogDirs(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(3))
+	cl := newTestClient(t, c)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if _, err := newTestAdmin(t, c).CreateTopic(ctx, 1, 2, nil, "t"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.ProduceTo("t", 0, kgo.StringRecord("a"), kgo.StringRecord("b")); err != nil {
+		t.Fatal(err)
+	}
+	p0 := c.Info().Topics["t"][0]
+	if p0.Bytes <= 0 {
+		t.Fatalf("partition has %d bytes", p0.Bytes)
+	}
+
+	describe := func(node int32, topics []kmsg.DescribeLogDirsRequestTopic) kmsg.DescribeLogDirsResponseDir {
+		t.Helper()
+		req := kmsg.NewPtrDescribeLogDirsRequest()
+		req.Topics = topics
+		resp, err := req.RequestWith(ctx, cl.Broker(int(node)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.ErrorCode != 0 {
+			t.Fatal(kerr.ErrorForCode(resp.ErrorCode))
+		}
+		if len(resp.Dirs) != 1 {
+			t.Fatalf("broker %d: got %d dirs, expected the default dir", node, len(resp.Dirs))
+		}
+		return resp.Dirs[0]
+	}
+
+	// Each replica reports the partition at the leader's size, and the
+	// other broker reports an empty default dir.
+	for node := int32(0); node < 3; node++ {
+		dir := describe(node, nil)
+		if !contains(p0.Replicas, node) {
+			if len(dir.Topics) != 0 || dir.UsableBytes != dir.TotalBytes {
+				t.Errorf("broker %d is not a replica, but described %d topics with %d of %d bytes usable", node, len(dir.Topics), dir.UsableBytes, dir.TotalBytes)
+			}
+			continue
+		}
+		if len(dir.Topics) != 1 || len(dir.Topics[0].Partitions) != 1 {
+			t.Fatalf("replica %d described %+v, expected one partition", node, dir.Topics)
+		}
+		if size := dir.Topics[0].Partitions[0].Size; size != p0.Bytes {
+			t.Errorf("replica %d: got size %d, expected %d", node, size, p0.Bytes)
+		}
+		if dir.UsableBytes != dir.TotalBytes-p0.Bytes {
+			t.Errorf("replica %d: got %d of %d bytes usable, expected %d used", node, dir.UsableBytes, dir.TotalBytes, p0.Bytes)
+		}
+	}
+
+	// Partitions that are not requested are not described, but still
+	// count against the dir's space.
+	rt := kmsg.NewDescribeLogDirsRequestTopic()
+	rt.Topic, rt.Partitions = "t", []int32{1}
+	dir := describe(p0.Leader, []kmsg.DescribeLogDirsRequestTopic{rt})
+	if len(dir.Topics) != 0 {
+		t.Errorf("described unrequested topics %+v", dir.Topics)
+	}
+	if dir.UsableBytes != dir.TotalBytes-p0.Bytes {
+		t.Errorf("got %d of %d bytes usable, expected %d used", dir.UsableBytes, dir.TotalBytes, p0.Bytes)
+	}
+}
@@ -1,8 +1,6 @@
 package kfake
 
 import (
-	"errors"
-
 	"github.com/twmb/franz-go/pkg/kerr"
 	"github.com/twmb/franz-go/pkg/kmsg"
 )
@@ -17,6 +15,15 @@ func (c *Cluster) handleSASLAuthenticate(creq *clientReq) (kmsg.Response, error)
 		return nil, err
 	}
 
+	// As in Kafka, a failed authentication is replied to, and the
+	// connection is closed on the next request.
+	fail := func(msg string) (kmsg.Response, error) {
+		creq.cc.saslStage = saslStageFailed
+		resp.ErrorCode = kerr.SaslAuthenticationFailed.Code
+		resp.ErrorMessage = kmsg.StringPtr("Authentication failed: " + msg)
+		return resp, nil
+	}
+
 	switch creq.cc.saslStage {
 	default:
 		resp.ErrorCode = kerr.IllegalSaslState.Code
@@ -25,27 +32,26 @@ func (c *Cluster) handleSASLAuthenticate(creq *clientReq) (kmsg.Response, error)
 	case saslStageAuthPlain:
 		u, p, err := saslSplitPlain(req.SASLAuthBytes)
 		if err != nil {
-			return nil, err
-		}
-		if c.sasls.plain == nil {
-			return nil, errors.New("invalid sasl")
+			return fail(err.Error())
 		}
-		if p != c.sasls.plain[u] {
-			return nil, errors.New("invalid sasl")
+		if pass, ok := c.sasls.plain[u]; !ok || p != pass {
+			return fail("invalid username or password")
 		}
 		creq.cc.saslStage = saslStageComplete
+		creq.cc.user = u
+		creq.cc.setPrincipal("User:" + u)
 
 	case saslStageAuthScram0_256:
 		c0, err := scramParseClient0(req.SASLAuthBytes)
 		if err != nil {
-			return nil, err
-		}
-		if c.sasls.scram256 == nil {
-			return nil, errors.New("invalid sasl")
+			return fail(err.Error())
 		}
 		a, ok := c.sasls.scram256[c0.user]
+		if c0.token {
+			a, ok = c.sasls.tokenAuth(saslScram256, c0.user, creq.cc.b.now())
+		}
 		if !ok {
-			return nil, errors.New("invalid sasl")
+			return fail("invalid username or password")
 		}
 		s0, serverFirst := scramServerFirst(c0, a)
 		resp.SASLAuthBytes = serverFirst
@@ -55,14 +61,14 @@ func (c *Cluster) handleSASLAuthenticate(creq *clientReq) (kmsg.Response, error)
 	case saslStageAuthScram0_512:
 		c0, err := scramParseClient0(req.SASLAuthBytes)
 		if err != nil {
-			return nil, err
-		}
-		if c.sasls.scram512 == nil {
-			return nil, errors.New("invalid sasl")
+			return fail(err.Error())
 		}
 		a, ok := c.sasls.scram512[c0.user]
+		if c0.token {
+			a, ok = c.sasls.tokenAuth(saslScram512, c0.user, creq.cc.b.now())
+		}
 		if !ok {
-			return nil, errors.New("invalid sasl")
+			return fail("invalid username or password")
 		}
 		s0, serverFirst := scramServerFirst(c0, a)
 		resp.SASLAuthBytes = serverFirst
@@ -72,11 +78,17 @@ func (c *Cluster) handleSASLAuthenticate(creq *clientReq) (kmsg.Response, error)
 	case saslStageAuthScram1:
 		serverFinal, err := creq.cc.s0.serverFinal(req.SASLAuthBytes)
 		if err != nil {
-			return nil, err
+			return fail(err.Error())
 		}
 		resp.SASLAuthBytes = serverFinal
 		creq.cc.saslStage = saslStageComplete
+		creq.cc.user = creq.cc.s0.user
+		creq.cc.token = creq.cc.s0.token
 		creq.cc.s0 = nil
+		creq.cc.setPrincipal("User:" + creq.cc.user)
+		if t, ok := c.sasls.tokens[creq.cc.user]; ok && creq.cc.token {
+			creq.cc.setPrincipal(t.ownerType + ":" + t.ownerName)
+		}
 	}
 
 	return resp, nil
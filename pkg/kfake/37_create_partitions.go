@@ -7,8 +7,9 @@ import (
 
 func init() { regKey(37, 0, 3) }
 
-func (c *Cluster) handleCreatePartitions(b *broker, kreq kmsg.Request) (kmsg.Response, error) {
-	req := kreq.(*kmsg.CreatePartitionsRequest)
+func (c *Cluster) handleCreatePartitions(creq *clientReq) (kmsg.Response, error) {
+	b := creq.cc.b
+	req := creq.kreq.(*kmsg.CreatePartitionsRequest)
 	resp := req.ResponseKind().(*kmsg.CreatePartitionsResponse)
 
 	if err := checkReqVersion(req.Key(), req.Version); err != nil {
@@ -43,6 +44,10 @@ func (c *Cluster) handleCreatePartitions(b *broker, kreq kmsg.Request) (kmsg.Res
 	}
 
 	for _, rt := range req.Topics {
+		if !c.allowedTopic(creq, rt.Topic, kmsg.ACLOperationAlter) {
+			donet(rt.Topic, kerr.TopicAuthorizationFailed.Code)
+			continue
+		}
 		t, ok := c.data.tps.gett(rt.Topic)
 		if !ok {
 			donet(rt.Topic, kerr.UnknownTopicOrPartition.Code)
@@ -56,8 +61,17 @@ func (c *Cluster) handleCreatePartitions(b *broker, kreq kmsg.Request) (kmsg.Res
 			donet(rt.Topic, kerr.InvalidPartitions.Code)
 			continue
 		}
+		if err := c.checkResourceLimits(0, int(rt.Count)-len(t)); err != nil {
+			st := donet(rt.Topic, kerr.PolicyViolation.Code)
+			st.ErrorMessage = kmsg.StringPtr(err.Error())
+			continue
+		}
 		for i := int32(len(t)); i < rt.Count; i++ {
-			c.data.tps.mkp(rt.Topic, i, c.newPartData)
+			c.data.tps.mkp(rt.Topic, i, func() *partData { return c.newPartData(rt.Topic, i) })
+		}
+		if req.TimeoutMillis <= 0 {
+			donet(rt.Topic, kerr.RequestTimedOut.Code) // created, but we did not wait; see CreateTopics
+			continue
 		}
 		donet(rt.Topic, 0)
 	}
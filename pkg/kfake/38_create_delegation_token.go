@@ -0,0 +1,124 @@
+package kfake
+
+import (
+	"encoding/base64"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// TODO
+// * RenewDelegationToken, ExpireDelegationToken, DescribeDelegationToken
+
+func init() { regKey(38, 0, 3) }
+
+const (
+	dtokenExpiry      = 24 * time.Hour     // delegation.token.expiry.time.ms
+	dtokenMaxLifetime = 7 * 24 * time.Hour // delegation.token.max.lifetime.ms
+)
+
+// dtoken is a delegation token. Clients authenticate with the token using
+// SCRAM with the tokenauth extension: the user is the token ID and the
+// password is the base64 encoded HMAC.
+type dtoken struct {
+	id            string
+	hmac          []byte
+	ownerType     string
+	ownerName     string
+	requesterType string
+	requesterName string
+	renewers      []kmsg.CreateDelegationTokenRequestRenewer
+	issue         time.Time
+	expiry        time.Time
+	max           time.Time
+	scram256      scramAuth
+	scram512      scramAuth
+}
+
+// tokenAuth returns the SCRAM credentials for the given token ID, if the
+// token exists and has not expired.
+func (s sasls) tokenAuth(mechanism, id string, now time.Time) (scramAuth, bool) {
+	t, ok := s.tokens[id]
+	if !ok || !now.Before(t.expiry) {
+		return scramAuth{}, false
+	}
+	if mechanism == saslScram512 {
+		return t.scram512, true
+	}
+	return t.scram256, true
+}
+
+func (c *Cluster) handleCreateDelegationToken(creq *clientReq) (kmsg.Response, error) {
+	var (
+		req  = creq.kreq.(*kmsg.CreateDelegationTokenRequest)
+		resp = req.ResponseKind().(*kmsg.CreateDelegationTokenResponse)
+	)
+
+	if err := checkReqVersion(req.Key(), req.Version); err != nil {
+		return nil, err
+	}
+
+	// Kafka only allows creating tokens on connections that authenticated
+	// with SASL, and not with a delegation token itself.
+	if creq.cc.user == "" || creq.cc.token {
+		resp.ErrorCode = kerr.DelegationTokenRequestNotAllowed.Code
+		return resp, nil
+	}
+
+	ownerName := creq.cc.user
+	if req.OwnerPrincipalName != nil && *req.OwnerPrincipalName != "" {
+		if req.OwnerPrincipalType != nil && *req.OwnerPrincipalType != "User" {
+			resp.ErrorCode = kerr.InvalidPrincipalType.Code
+			return resp, nil
+		}
+		ownerName = *req.OwnerPrincipalName
+	}
+	for _, r := range req.Renewers {
+		if r.PrincipalType != "User" {
+			resp.ErrorCode = kerr.InvalidPrincipalType.Code
+			return resp, nil
+		}
+	}
+
+	var (
+		now      = creq.cc.b.now()
+		lifetime = dtokenMaxLifetime
+	)
+	if req.MaxLifetimeMillis > 0 && time.Duration(req.MaxLifetimeMillis)*time.Millisecond < lifetime {
+		lifetime = time.Duration(req.MaxLifetimeMillis) * time.Millisecond
+	}
+	t := &dtoken{
+		id:            base64.RawURLEncoding.EncodeToString(randBytes(16)),
+		hmac:          randBytes(64),
+		ownerType:     "User",
+		ownerName:     ownerName,
+		requesterType: "User",
+		requesterName: creq.cc.user,
+		renewers:      req.Renewers,
+		issue:         now,
+		max:           now.Add(lifetime),
+	}
+	t.expiry = now.Add(dtokenExpiry)
+	if t.expiry.After(t.max) {
+		t.expiry = t.max
+	}
+	pass := base64.StdEncoding.EncodeToString(t.hmac)
+	t.scram256 = newScramAuth(saslScram256, pass)
+	t.scram512 = newScramAuth(saslScram512, pass)
+	if c.sasls.tokens == nil {
+		c.sasls.tokens = make(map[string]*dtoken)
+	}
+	c.sasls.tokens[t.id] = t
+
+	resp.PrincipalType = t.ownerType
+	resp.PrincipalName = t.ownerName
+	resp.TokenRequesterPrincipalType = t.requesterType
+	resp.TokenRequesterPrincipalName = t.requesterName
+	resp.IssueTimestamp = t.issue.UnixMilli()
+	resp.ExpiryTimestamp = t.expiry.UnixMilli()
+	resp.MaxTimestamp = t.max.UnixMilli()
+	resp.TokenID = t.id
+	resp.HMAC = t.hmac
+	return resp, nil
+}
@@ -0,0 +1,85 @@
+package kfake
+
+import (
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// ElectLeaders
+//
+// Behavior:
+// * Any broker can handle the request; elections are applied immediately
+// * Preferred elections move leadership to the first replica, unclean
+//   elections only elect a leader for partitions without a live leader
+// * Null topics elects all partitions, omitting partitions that did not need
+//   an election
+//
+// Version notes:
+// * v0: preferred elections only, no top level error code
+// * v1: ElectionType, top level error code
+
+func init() { regKey(43, 0, 2) }
+
+func (c *Cluster) handleElectLeaders(creq *clientReq) (kmsg.Response, error) {
+	req := creq.kreq.(*kmsg.ElectLeadersRequest)
+	resp := req.ResponseKind().(*kmsg.ElectLeadersResponse)
+
+	if err := checkReqVersion(req.Key(), req.Version); err != nil {
+		return nil, err
+	}
+
+	tidx := make(map[string]int)
+	donep := func(t string, p int32, errCode int16) {
+		i, ok := tidx[t]
+		if !ok {
+			i = len(resp.Topics)
+			tidx[t] = i
+			st := kmsg.NewElectLeadersResponseTopic()
+			st.Topic = t
+			resp.Topics = append(resp.Topics, st)
+		}
+		sp := kmsg.NewElectLeadersResponseTopicPartition()
+		sp.Partition = p
+		sp.ErrorCode = errCode
+		resp.Topics[i].Partitions = append(resp.Topics[i].Partitions, sp)
+	}
+
+	if !c.allowedCluster(creq, kmsg.ACLOperationAlter) {
+		if req.Version >= 1 {
+			resp.ErrorCode = kerr.ClusterAuthorizationFailed.Code
+			return resp, nil
+		}
+		for _, rt := range req.Topics {
+			for _, p := range rt.Partitions {
+				donep(rt.Topic, p, kerr.ClusterAuthorizationFailed.Code)
+			}
+		}
+		return resp, nil
+	}
+
+	elect := c.electPreferred
+	if req.Version >= 1 && req.ElectionType == 1 {
+		elect = c.electUnclean
+	}
+
+	if req.Topics == nil {
+		c.data.tps.each(func(t string, p int32, pd *partData) {
+			if errCode := elect(t, p, pd); errCode != kerr.ElectionNotNeeded.Code {
+				donep(t, p, errCode)
+			}
+		})
+		return resp, nil
+	}
+
+	for _, rt := range req.Topics {
+		for _, p := range rt.Partitions {
+			pd, ok := c.data.tps.getp(rt.Topic, p)
+			if !ok {
+				donep(rt.Topic, p, kerr.UnknownTopicOrPartition.Code)
+				continue
+			}
+			donep(rt.Topic, p, elect(rt.Topic, p, pd))
+		}
+	}
+	return resp, nil
+}
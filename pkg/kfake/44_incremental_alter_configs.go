@@ -9,8 +9,9 @@ import (
 
 func init() { regKey(44, 0, 1) }
 
-func (c *Cluster) handleIncrementalAlterConfigs(b *broker, kreq kmsg.Request) (kmsg.Response, error) {
-	req := kreq.(*kmsg.IncrementalAlterConfigsRequest)
+func (c *Cluster) handleIncrementalAlterConfigs(creq *clientReq) (kmsg.Response, error) {
+	b := creq.cc.b
+	req := creq.kreq.(*kmsg.IncrementalAlterConfigsRequest)
 	resp := req.ResponseKind().(*kmsg.IncrementalAlterConfigsResponse)
 
 	if err := checkReqVersion(req.Key(), req.Version); err != nil {
@@ -29,6 +30,10 @@ func (c *Cluster) handleIncrementalAlterConfigs(b *broker, kreq kmsg.Request) (k
 outer:
 	for i := range req.Resources {
 		rr := &req.Resources[i]
+		if errCode := c.configAuthErr(creq, rr.ResourceType, rr.ResourceName, kmsg.ACLOperationAlterConfigs); errCode != 0 {
+			doner(rr.ResourceName, rr.ResourceType, errCode)
+			continue
+		}
 		switch rr.ResourceType {
 		case kmsg.ConfigResourceTypeBroker:
 			id := int32(-1)
@@ -40,19 +45,25 @@ outer:
 					continue outer
 				}
 			}
-			var invalid bool
+			var invalid error
 			for i := range rr.Configs {
 				rc := &rr.Configs[i]
+				var err error
 				switch rc.Op {
 				case kmsg.IncrementalAlterConfigOpSet:
-					invalid = invalid || !c.setBrokerConfig(rr.Configs[i].Name, rr.Configs[i].Value, true)
+					err = c.setBrokerConfig(rc.Name, rc.Value, true)
 				case kmsg.IncrementalAlterConfigOpDelete:
+					err = checkBrokerConfig(rc.Name, nil, false)
 				default:
-					invalid = true
+					err = configErrf(kerr.InvalidRequest, "unsupported config operation %d", rc.Op)
+				}
+				if err != nil && invalid == nil {
+					invalid = err
 				}
 			}
-			if invalid {
-				doner(rr.ResourceName, rr.ResourceType, kerr.InvalidRequest.Code)
+			if invalid != nil {
+				r := doner(rr.ResourceName, rr.ResourceType, configErrCode(invalid))
+				r.ErrorMessage = kmsg.StringPtr(invalid.Error())
 				continue
 			}
 			doner(rr.ResourceName, rr.ResourceType, 0)
@@ -74,19 +85,25 @@ outer:
 				doner(rr.ResourceName, rr.ResourceType, kerr.UnknownTopicOrPartition.Code)
 				continue
 			}
-			var invalid bool
+			var invalid error
 			for i := range rr.Configs {
 				rc := &rr.Configs[i]
+				var err error
 				switch rc.Op {
 				case kmsg.IncrementalAlterConfigOpSet:
-					invalid = invalid || !c.data.setTopicConfig(rr.ResourceName, rc.Name, rc.Value, true)
+					err = c.data.setTopicConfig(rr.ResourceName, rc.Name, rc.Value, true)
 				case kmsg.IncrementalAlterConfigOpDelete:
+					err = checkTopicConfig(rc.Name, nil, false)
 				default:
-					invalid = true
+					err = configErrf(kerr.InvalidRequest, "unsupported config operation %d", rc.Op)
+				}
+				if err != nil && invalid == nil {
+					invalid = err
 				}
 			}
-			if invalid {
-				doner(rr.ResourceName, rr.ResourceType, kerr.InvalidRequest.Code)
+			if invalid != nil {
+				r := doner(rr.ResourceName, rr.ResourceType, configErrCode(invalid))
+				r.ErrorMessage = kmsg.StringPtr(invalid.Error())
 				continue
 			}
 			doner(rr.ResourceName, rr.ResourceType, 0)
@@ -0,0 +1,97 @@
+package kfake
+
+import (
+	"fmt"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func init() { regKey(45, 0, 0) }
+
+func (c *Cluster) handleAlterPartitionAssignments(creq *clientReq) (kmsg.Response, error) {
+	b := creq.cc.b
+	req := creq.kreq.(*kmsg.AlterPartitionAssignmentsRequest)
+	resp := req.ResponseKind().(*kmsg.AlterPartitionAssignmentsResponse)
+
+	if err := checkReqVersion(req.Key(), req.Version); err != nil {
+		return nil, err
+	}
+
+	if b != c.controller {
+		resp.ErrorCode = kerr.NotController.Code
+		return resp, nil
+	}
+	if !c.allowedCluster(creq, kmsg.ACLOperationAlter) {
+		resp.ErrorCode = kerr.ClusterAuthorizationFailed.Code
+		return resp, nil
+	}
+
+	tidx := make(map[string]int)
+	donep := func(t string, p int32, errCode int16, msg string) {
+		i, ok := tidx[t]
+		if !ok {
+			i = len(resp.Topics)
+			tidx[t] = i
+			st := kmsg.NewAlterPartitionAssignmentsResponseTopic()
+			st.Topic = t
+			resp.Topics = append(resp.Topics, st)
+		}
+		sp := kmsg.NewAlterPartitionAssignmentsResponseTopicPartition()
+		sp.Partition = p
+		sp.ErrorCode = errCode
+		if msg != "" {
+			sp.ErrorMessage = &msg
+		}
+		resp.Topics[i].Partitions = append(resp.Topics[i].Partitions, sp)
+	}
+
+	for _, rt := range req.Topics {
+		for _, rp := range rt.Partitions {
+			pd, ok := c.data.tps.getp(rt.Topic, rp.Partition)
+			if !ok {
+				donep(rt.Topic, rp.Partition, kerr.UnknownTopicOrPartition.Code, "")
+				continue
+			}
+			if rp.Replicas == nil {
+				if pd.reassign == nil {
+					donep(rt.Topic, rp.Partition, kerr.NoReassignmentInProgress.Code, "")
+					continue
+				}
+				c.reassign(rt.Topic, rp.Partition, pd, nil)
+				donep(rt.Topic, rp.Partition, 0, "")
+				continue
+			}
+			if msg := c.validateAssignment(rp.Replicas); msg != "" {
+				donep(rt.Topic, rp.Partition, kerr.InvalidReplicaAssignment.Code, msg)
+				continue
+			}
+			c.reassign(rt.Topic, rp.Partition, pd, rp.Replicas)
+			donep(rt.Topic, rp.Partition, 0, "")
+		}
+	}
+	return resp, nil
+}
+
+// validateAssignment returns why a replica assignment is invalid, or an empty
+// string if it is valid.
+func (c *Cluster) validateAssignment(replicas []int32) string {
+	if len(replicas) == 0 {
+		return "the replica assignment is empty"
+	}
+	seen := make(map[int32]struct{}, len(replicas))
+	for _, node := range replicas {
+		if _, ok := seen[node]; ok {
+			return fmt.Sprintf("the replica assignment has duplicate broker %d", node)
+		}
+		seen[node] = struct{}{}
+		var exists bool
+		for _, b := range c.bs {
+			exists = exists || b.node == node
+		}
+		if !exists {
+			return fmt.Sprintf("the replica assignment has unknown broker %d", node)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,63 @@
+package kfake
+
+import (
+	"sort"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func init() { regKey(46, 0, 0) }
+
+func (c *Cluster) handleListPartitionReassignments(creq *clientReq) (kmsg.Response, error) {
+	b := creq.cc.b
+	req := creq.kreq.(*kmsg.ListPartitionReassignmentsRequest)
+	resp := req.ResponseKind().(*kmsg.ListPartitionReassignmentsResponse)
+
+	if err := checkReqVersion(req.Key(), req.Version); err != nil {
+		return nil, err
+	}
+
+	if b != c.controller {
+		resp.ErrorCode = kerr.NotController.Code
+		return resp, nil
+	}
+	if !c.allowedCluster(creq, kmsg.ACLOperationDescribe) {
+		resp.ErrorCode = kerr.ClusterAuthorizationFailed.Code
+		return resp, nil
+	}
+
+	// A null topic list lists every reassignment; requested partitions
+	// that do not exist or are not being reassigned are skipped.
+	var list tps[reassignment]
+	if req.Topics == nil {
+		c.data.tps.each(func(t string, p int32, pd *partData) {
+			if pd.reassign != nil {
+				list.set(t, p, *pd.reassign)
+			}
+		})
+	} else {
+		for _, rt := range req.Topics {
+			for _, p := range rt.Partitions {
+				if pd, ok := c.data.tps.getp(rt.Topic, p); ok && pd.reassign != nil {
+					list.set(rt.Topic, p, *pd.reassign)
+				}
+			}
+		}
+	}
+
+	for t, ps := range list {
+		st := kmsg.NewListPartitionReassignmentsResponseTopic()
+		st.Topic = t
+		for p, r := range ps {
+			sp := kmsg.NewListPartitionReassignmentsResponseTopicPartition()
+			sp.Partition = p
+			sp.Replicas, sp.AddingReplicas, sp.RemovingReplicas = r.replicas()
+			st.Partitions = append(st.Partitions, sp)
+		}
+		sort.Slice(st.Partitions, func(i, j int) bool { return st.Partitions[i].Partition < st.Partitions[j].Partition })
+		resp.Topics = append(resp.Topics, st)
+	}
+	sort.Slice(resp.Topics, func(i, j int) bool { return resp.Topics[i].Topic < resp.Topics[j].Topic })
+	return resp, nil
+}
@@ -15,8 +15,14 @@ func (c *Cluster) handleOffsetDelete(creq *clientReq) (kmsg.Response, error) {
 		return nil, err
 	}
 
-	if c.groups.handleOffsetDelete(creq) {
-		return nil, nil
+	// Coordinator and authorization errors take precedence over the
+	// group not existing.
+	if kerr := c.validateGroup(creq, req.Group); kerr != nil {
+		resp.ErrorCode = kerr.Code
+		return resp, nil
+	}
+	if gresp, ok := c.groups.handleOffsetDelete(creq); ok {
+		return gresp, nil
 	}
 	resp.ErrorCode = kerr.GroupIDNotFound.Code
 	return resp, nil
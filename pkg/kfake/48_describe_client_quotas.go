@@ -0,0 +1,101 @@
+package kfake
+
+import (
+	"sort"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func init() { regKey(48, 0, 1) }
+
+func (c *Cluster) handleDescribeClientQuotas(creq *clientReq) (kmsg.Response, error) {
+	req := creq.kreq.(*kmsg.DescribeClientQuotasRequest)
+	resp := req.ResponseKind().(*kmsg.DescribeClientQuotasResponse)
+
+	if err := checkReqVersion(req.Key(), req.Version); err != nil {
+		return nil, err
+	}
+
+	fail := func(code int16, msg string) (kmsg.Response, error) {
+		resp.ErrorCode = code
+		resp.ErrorMessage = &msg
+		return resp, nil
+	}
+	if !c.allowedCluster(creq, kmsg.ACLOperationDescribeConfigs) {
+		return fail(kerr.ClusterAuthorizationFailed.Code, kerr.ClusterAuthorizationFailed.Message)
+	}
+
+	filters := make(map[string]kmsg.DescribeClientQuotasRequestComponent)
+	for _, rc := range req.Components {
+		switch rc.EntityType {
+		case quotaTypeUser, quotaTypeClientID, quotaTypeIP:
+		default:
+			return fail(kerr.InvalidRequest.Code, "unknown entity type "+rc.EntityType)
+		}
+		if _, ok := filters[rc.EntityType]; ok {
+			return fail(kerr.InvalidRequest.Code, "duplicate filter entity type "+rc.EntityType)
+		}
+		switch rc.MatchType {
+		case kmsg.QuotasMatchTypeExact:
+			if rc.Match == nil {
+				return fail(kerr.InvalidRequest.Code, "exact match filters require a match")
+			}
+		case kmsg.QuotasMatchTypeDefault, kmsg.QuotasMatchTypeAny:
+		default:
+			return fail(kerr.InvalidRequest.Code, "unknown match type "+rc.MatchType.String())
+		}
+		filters[rc.EntityType] = rc
+	}
+	if _, ok := filters[quotaTypeIP]; ok && len(filters) > 1 {
+		return fail(kerr.InvalidRequest.Code, "ip filters cannot be combined with user or client-id filters")
+	}
+
+	keys := make([]string, 0, len(c.quotas))
+outer:
+	for key, e := range c.quotas {
+		if req.Strict && len(e.components) != len(filters) {
+			continue
+		}
+		for typ, f := range filters {
+			var (
+				name  *string
+				found bool
+			)
+			for _, ec := range e.components {
+				if ec.typ == typ {
+					name, found = ec.name, true
+				}
+			}
+			switch {
+			case !found,
+				f.MatchType == kmsg.QuotasMatchTypeExact && (name == nil || *name != *f.Match),
+				f.MatchType == kmsg.QuotasMatchTypeDefault && name != nil,
+				f.MatchType == kmsg.QuotasMatchTypeAny && name == nil:
+				continue outer
+			}
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		e := c.quotas[key]
+		se := kmsg.NewDescribeClientQuotasResponseEntry()
+		for _, ec := range e.components {
+			ent := kmsg.NewDescribeClientQuotasResponseEntryEntity()
+			ent.Type = ec.typ
+			ent.Name = ec.name
+			se.Entity = append(se.Entity, ent)
+		}
+		for k, v := range e.values {
+			sv := kmsg.NewDescribeClientQuotasResponseEntryValue()
+			sv.Key = k
+			sv.Value = v
+			se.Values = append(se.Values, sv)
+		}
+		sort.Slice(se.Values, func(i, j int) bool { return se.Values[i].Key < se.Values[j].Key })
+		resp.Entries = append(resp.Entries, se)
+	}
+	return resp, nil
+}
@@ -0,0 +1,45 @@
+package kfake
+
+import (
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func init() { regKey(49, 0, 1) }
+
+func (c *Cluster) handleAlterClientQuotas(creq *clientReq) (kmsg.Response, error) {
+	req := creq.kreq.(*kmsg.AlterClientQuotasRequest)
+	resp := req.ResponseKind().(*kmsg.AlterClientQuotasResponse)
+
+	if err := checkReqVersion(req.Key(), req.Version); err != nil {
+		return nil, err
+	}
+
+	allowed := c.allowedCluster(creq, kmsg.ACLOperationAlterConfigs)
+	for _, re := range req.Entries {
+		se := kmsg.NewAlterClientQuotasResponseEntry()
+		components := make([]quotaComponent, 0, len(re.Entity))
+		for _, ent := range re.Entity {
+			sent := kmsg.NewAlterClientQuotasResponseEntryEntity()
+			sent.Type = ent.Type
+			sent.Name = ent.Name
+			se.Entity = append(se.Entity, sent)
+
+			var name *string
+			if ent.Name != nil {
+				n := *ent.Name
+				name = &n
+			}
+			components = append(components, quotaComponent{ent.Type, name})
+		}
+		if !allowed {
+			se.ErrorCode = kerr.ClusterAuthorizationFailed.Code
+		} else if err := c.quotas.alter(components, re.Ops, req.ValidateOnly); err != nil {
+			msg := err.Error()
+			se.ErrorCode = kerr.InvalidRequest.Code
+			se.ErrorMessage = &msg
+		}
+		resp.Entries = append(resp.Entries, se)
+	}
+	return resp, nil
+}
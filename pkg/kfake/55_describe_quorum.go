@@ -0,0 +1,94 @@
+package kfake
+
+import (
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// Behavior:
+//
+// * Only __cluster_metadata partition 0 exists; all other partitions are unknown
+// * Brokers that are not voters are observers
+// * Voters that are not brokers have an unknown log end offset
+
+func init() { regKey(55, 0, 2) }
+
+func (c *Cluster) handleDescribeQuorum(kreq kmsg.Request) (kmsg.Response, error) {
+	req := kreq.(*kmsg.DescribeQuorumRequest)
+	resp := req.ResponseKind().(*kmsg.DescribeQuorumResponse)
+
+	if err := checkReqVersion(req.Key(), req.Version); err != nil {
+		return nil, err
+	}
+
+	q := &c.quorum
+	now := c.controller.now().UnixMilli()
+	isBroker := func(id int32) bool {
+		for _, b := range c.bs {
+			if b.node == id {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, rt := range req.Topics {
+		st := kmsg.NewDescribeQuorumResponseTopic()
+		st.Topic = rt.Topic
+		for _, rp := range rt.Partitions {
+			sp := kmsg.NewDescribeQuorumResponseTopicPartition()
+			sp.Partition = rp.Partition
+			if rt.Topic != "__cluster_metadata" || rp.Partition != 0 {
+				sp.ErrorCode = kerr.UnknownTopicOrPartition.Code
+				st.Partitions = append(st.Partitions, sp)
+				continue
+			}
+			sp.LeaderID = q.leader()
+			sp.LeaderEpoch = q.epoch
+			sp.HighWatermark = q.hwm
+			for _, v := range q.voters {
+				rs := kmsg.NewDescribeQuorumResponseTopicPartitionReplicaState()
+				rs.ReplicaID = v.id
+				rs.ReplicaDirectoryID = v.dir
+				rs.LogEndOffset = -1
+				switch {
+				case v.id == sp.LeaderID:
+					rs.LogEndOffset = q.hwm
+				case isBroker(v.id):
+					rs.LogEndOffset = q.hwm
+					rs.LastFetchTimestamp = now
+					rs.LastCaughtUpTimestamp = now
+				}
+				sp.CurrentVoters = append(sp.CurrentVoters, rs)
+			}
+			for _, b := range c.bs {
+				if _, v := q.voter(b.node); v != nil {
+					continue
+				}
+				rs := kmsg.NewDescribeQuorumResponseTopicPartitionReplicaState()
+				rs.ReplicaID = b.node
+				rs.LogEndOffset = q.hwm
+				rs.LastFetchTimestamp = now
+				rs.LastCaughtUpTimestamp = now
+				sp.Observers = append(sp.Observers, rs)
+			}
+			st.Partitions = append(st.Partitions, sp)
+		}
+		resp.Topics = append(resp.Topics, st)
+	}
+
+	for _, v := range q.voters {
+		sn := kmsg.NewDescribeQuorumResponseNode()
+		sn.NodeID = v.id
+		for _, l := range v.listeners {
+			sl := kmsg.NewDescribeQuorumResponseNodeListener()
+			sl.Name = l.name
+			sl.Host = l.host
+			sl.Port = l.port
+			sn.Listeners = append(sn.Listeners, sl)
+		}
+		resp.Nodes = append(resp.Nodes, sn)
+	}
+
+	return resp, nil
+}
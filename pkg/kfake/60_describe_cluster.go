@@ -0,0 +1,60 @@
+package kfake
+
+import (
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// Behavior:
+//
+// * Brokers and the controller are the same as advertised in Metadata
+// * Only the broker endpoint type is supported; brokers are not controllers
+// * Authorized operations are only included if the client can describe the cluster
+
+func init() { regKey(60, 0, 1) }
+
+func (c *Cluster) handleDescribeCluster(creq *clientReq) (kmsg.Response, error) {
+	req := creq.kreq.(*kmsg.DescribeClusterRequest)
+	resp := req.ResponseKind().(*kmsg.DescribeClusterResponse)
+
+	if err := checkReqVersion(req.Key(), req.Version); err != nil {
+		return nil, err
+	}
+
+	switch req.EndpointType {
+	case 1: // brokers
+	case 2: // controllers
+		resp.ErrorCode = kerr.MismatchedEndpointType.Code
+		return resp, nil
+	default:
+		resp.ErrorCode = kerr.UnsupportedEndpointType.Code
+		return resp, nil
+	}
+
+	resp.ClusterID = c.cfg.clusterID
+	resp.ControllerID = c.advertisedController().node
+	for _, b := range c.bs {
+		sb := kmsg.NewDescribeClusterResponseBroker()
+		sb.NodeID = b.node
+		sb.Host, sb.Port = b.hostport(creq.cc.listener)
+		sb.Rack = b.rack
+		resp.Brokers = append(resp.Brokers, sb)
+	}
+
+	if req.IncludeClusterAuthorizedOperations {
+		resp.ClusterAuthorizedOperations = 0
+		if c.allowedCluster(creq, kmsg.ACLOperationDescribe) {
+			resp.ClusterAuthorizedOperations = c.authorizedOps(creq, kmsg.ACLResourceTypeCluster, "kafka-cluster",
+				kmsg.ACLOperationAlter,
+				kmsg.ACLOperationAlterConfigs,
+				kmsg.ACLOperationClusterAction,
+				kmsg.ACLOperationCreate,
+				kmsg.ACLOperationDescribe,
+				kmsg.ACLOperationDescribeConfigs,
+				kmsg.ACLOperationIdempotentWrite,
+			)
+		}
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,62 @@
+package kfake
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestDescribeCluster(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(3), WithRacks("a", "b"))
+	cl := newTestClient(t, c)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	describe := func(endpointType int8) *kmsg.DescribeClusterResponse {
+		t.Helper()
+		req := kmsg.NewPtrDescribeClusterRequest()
+		req.EndpointType = endpointType
+		req.IncludeClusterAuthorizedOperations = true
+		resp, err := req.RequestWith(ctx, cl)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+
+	resp := describe(1)
+	if resp.ErrorCode != 0 {
+		t.Fatal(kerr.ErrorForCode(resp.ErrorCode))
+	}
+	info := c.Info()
+	if resp.ClusterID != info.ClusterID || resp.ControllerID != info.Controller {
+		t.Errorf("got cluster %s with controller %d, expected %s with %d", resp.ClusterID, resp.ControllerID, info.ClusterID, info.Controller)
+	}
+	if len(resp.Brokers) != len(info.Brokers) {
+		t.Fatalf("got %d brokers, expected %d", len(resp.Brokers), len(info.Brokers))
+	}
+	for i, b := range resp.Brokers {
+		exp := info.Brokers[i]
+		if b.NodeID != exp.Node || b.Rack == nil || *b.Rack != exp.Rack {
+			t.Errorf("got broker %d in rack %v, expected %d in rack %s", b.NodeID, b.Rack, exp.Node, exp.Rack)
+		}
+	}
+	if resp.ClusterAuthorizedOperations <= 0 {
+		t.Errorf("got cluster authorized operations %d", resp.ClusterAuthorizedOperations)
+	}
+
+	for _, test := range []struct {
+		endpointType int8
+		exp          *kerr.Error
+	}{
+		{2, kerr.MismatchedEndpointType},
+		{3, kerr.UnsupportedEndpointType},
+	} {
+		if code := describe(test.endpointType).ErrorCode; code != test.exp.Code {
+			t.Errorf("endpoint type %d: got %v, expected %v", test.endpointType, kerr.ErrorForCode(code), test.exp)
+		}
+	}
+}
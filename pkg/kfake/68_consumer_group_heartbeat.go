@@ -0,0 +1,17 @@
+package kfake
+
+import (
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func init() { regKey(68, 0, 1) }
+
+func (c *Cluster) handleConsumerGroupHeartbeat(creq *clientReq) (kmsg.Response, error) {
+	req := creq.kreq.(*kmsg.ConsumerGroupHeartbeatRequest)
+
+	if err := checkReqVersion(req.Key(), req.Version); err != nil {
+		return nil, err
+	}
+
+	return c.groups.handleConsumerHeartbeat(creq), nil
+}
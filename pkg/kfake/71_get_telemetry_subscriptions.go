@@ -0,0 +1,40 @@
+package kfake
+
+import (
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func init() { regKey(71, 0, 0) }
+
+func (c *Cluster) handleGetTelemetrySubscriptions(kreq kmsg.Request) (kmsg.Response, error) {
+	req := kreq.(*kmsg.GetTelemetrySubscriptionsRequest)
+	resp := req.ResponseKind().(*kmsg.GetTelemetrySubscriptionsResponse)
+
+	if err := checkReqVersion(req.Key(), req.Version); err != nil {
+		return nil, err
+	}
+
+	id, tc := c.telemetry.get(req.ClientInstanceID)
+	if req.ClientInstanceID == noID {
+		resp.ClientInstanceID = id
+	}
+
+	subID, interval, metrics := c.telemetry.subscription()
+	now := time.Now()
+	if !tc.canGet(now, interval) {
+		resp.ErrorCode = kerr.ThrottlingQuotaExceeded.Code
+		return resp, nil
+	}
+	tc.lastGet = now
+
+	resp.SubscriptionID = subID
+	resp.AcceptedCompressionTypes = acceptedTelemetryCodecs
+	resp.PushIntervalMillis = int32(interval.Milliseconds())
+	resp.TelemetryMaxBytes = telemetryMaxBytes
+	resp.DeltaTemporality = true
+	resp.RequestedMetrics = metrics
+	return resp, nil
+}
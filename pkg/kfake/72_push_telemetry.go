@@ -0,0 +1,21 @@
+package kfake
+
+import (
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func init() { regKey(72, 0, 0) }
+
+func (c *Cluster) handlePushTelemetry(kreq kmsg.Request) (kmsg.Response, error) {
+	req := kreq.(*kmsg.PushTelemetryRequest)
+	resp := req.ResponseKind().(*kmsg.PushTelemetryResponse)
+
+	if err := checkReqVersion(req.Key(), req.Version); err != nil {
+		return nil, err
+	}
+
+	if err := c.telemetry.push(req.ClientInstanceID, req.SubscriptionID, req.Terminating, req.CompressionType, req.Metrics); err != nil {
+		resp.ErrorCode = err.Code
+	}
+	return resp, nil
+}
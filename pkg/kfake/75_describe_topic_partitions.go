@@ -0,0 +1,121 @@
+package kfake
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// Behavior:
+//
+// * The response partition limit is capped at 2000, Kafka's default
+//   max.request.partition.size.limit
+// * If topics are requested, a cursor topic must be one of them
+// * Unknown requested topics do not count against the partition limit
+
+func init() { regKey(75, 0, 0) }
+
+func (c *Cluster) handleDescribeTopicPartitions(kreq kmsg.Request) (kmsg.Response, error) {
+	req := kreq.(*kmsg.DescribeTopicPartitionsRequest)
+	resp := req.ResponseKind().(*kmsg.DescribeTopicPartitionsResponse)
+
+	if err := checkReqVersion(req.Key(), req.Version); err != nil {
+		return nil, err
+	}
+
+	const maxLimit = 2000
+	limit := int(req.ResponsePartitionLimit)
+	if limit <= 0 || limit > maxLimit {
+		limit = maxLimit
+	}
+
+	var topics []string
+	if len(req.Topics) == 0 {
+		for t := range c.data.tps {
+			topics = append(topics, t)
+		}
+	} else {
+		uniq := make(map[string]struct{})
+		for _, rt := range req.Topics {
+			if _, ok := uniq[rt.Topic]; ok {
+				continue
+			}
+			uniq[rt.Topic] = struct{}{}
+			topics = append(topics, rt.Topic)
+		}
+		if req.Cursor != nil {
+			if _, ok := uniq[req.Cursor.Topic]; !ok {
+				return nil, errors.New("cursor topic is not one of the requested topics")
+			}
+		}
+	}
+	sort.Strings(topics)
+
+	var startPartition int32
+	if req.Cursor != nil {
+		if req.Cursor.Partition < 0 {
+			return nil, errors.New("invalid negative cursor partition")
+		}
+		i := sort.SearchStrings(topics, req.Cursor.Topic)
+		topics = topics[i:]
+		if len(topics) > 0 && topics[0] == req.Cursor.Topic {
+			startPartition = req.Cursor.Partition
+		}
+	}
+
+	for i, t := range topics {
+		st := kmsg.NewDescribeTopicPartitionsResponseTopic()
+		st.Topic = kmsg.StringPtr(t)
+		ps, ok := c.data.tps.gett(t)
+		if !ok {
+			st.ErrorCode = kerr.UnknownTopicOrPartition.Code
+			resp.Topics = append(resp.Topics, st)
+			continue
+		}
+		st.TopicID = c.data.t2id[t]
+
+		var partitions []int32
+		for p := range ps {
+			if i > 0 || p >= startPartition {
+				partitions = append(partitions, p)
+			}
+		}
+		sort.Slice(partitions, func(i, j int) bool { return partitions[i] < partitions[j] })
+
+		for _, p := range partitions {
+			if limit == 0 {
+				next := kmsg.NewDescribeTopicPartitionsResponseNextCursor()
+				next.Topic = t
+				next.Partition = p
+				resp.NextCursor = &next
+				break
+			}
+			limit--
+
+			pd := ps[p]
+			sp := kmsg.NewDescribeTopicPartitionsResponseTopicPartition()
+			sp.Partition = p
+			sp.LeaderID = pd.leader.node
+			sp.LeaderEpoch = pd.epoch
+			sp.Replicas = c.replicas(t, pd)
+			sp.ISR = c.isr(t, pd)
+			st.Partitions = append(st.Partitions, sp)
+		}
+		resp.Topics = append(resp.Topics, st)
+
+		// If we exhausted our limit exactly at the end of this topic,
+		// the next cursor is the start of the next topic.
+		if resp.NextCursor == nil && limit == 0 && i+1 < len(topics) {
+			next := kmsg.NewDescribeTopicPartitionsResponseNextCursor()
+			next.Topic = topics[i+1]
+			resp.NextCursor = &next
+		}
+		if resp.NextCursor != nil {
+			break
+		}
+	}
+
+	return resp, nil
+}
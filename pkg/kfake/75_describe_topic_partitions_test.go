@@ -0,0 +1,65 @@
+package kfake
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestDescribeTopicPartitionsPages(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(1), SeedTopics(3, "a"), SeedTopics(2, "b"))
+	br := newTestClient(t, c, withKeys(75)).Broker(0)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	describe := func(cursor *kmsg.DescribeTopicPartitionsRequestCursor, topics ...string) *kmsg.DescribeTopicPartitionsResponse {
+		t.Helper()
+		req := kmsg.NewPtrDescribeTopicPartitionsRequest()
+		req.ResponsePartitionLimit = 2
+		req.Cursor = cursor
+		for _, topic := range topics {
+			rt := kmsg.NewDescribeTopicPartitionsRequestTopic()
+			rt.Topic = topic
+			req.Topics = append(req.Topics, rt)
+		}
+		resp, err := req.RequestWith(ctx, br)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+	page := func(resp *kmsg.DescribeTopicPartitionsResponse) (got []string) {
+		for _, st := range resp.Topics {
+			for _, sp := range st.Partitions {
+				got = append(got, fmt.Sprintf("%s/%d", *st.Topic, sp.Partition))
+			}
+		}
+		return got
+	}
+
+	var (
+		cursor *kmsg.DescribeTopicPartitionsRequestCursor
+		pages  [][]string
+	)
+	for i := 0; i < 5; i++ {
+		resp := describe(cursor, "a", "b")
+		pages = append(pages, page(resp))
+		if resp.NextCursor == nil {
+			break
+		}
+		cursor = &kmsg.DescribeTopicPartitionsRequestCursor{Topic: resp.NextCursor.Topic, Partition: resp.NextCursor.Partition}
+	}
+	if exp := [][]string{{"a/0", "a/1"}, {"a/2", "b/0"}, {"b/1"}}; !reflect.DeepEqual(pages, exp) {
+		t.Errorf("got pages %v, expected %v", pages, exp)
+	}
+
+	resp := describe(nil, "missing")
+	if len(resp.Topics) != 1 || resp.Topics[0].ErrorCode != kerr.UnknownTopicOrPartition.Code {
+		t.Errorf("describing a missing topic: got %+v, expected UNKNOWN_TOPIC_OR_PARTITION", resp.Topics)
+	}
+}
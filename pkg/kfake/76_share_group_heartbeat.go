@@ -0,0 +1,17 @@
+package kfake
+
+import (
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func init() { regKey(76, 0, 0) }
+
+func (c *Cluster) handleShareGroupHeartbeat(creq *clientReq) (kmsg.Response, error) {
+	req := creq.kreq.(*kmsg.ShareGroupHeartbeatRequest)
+
+	if err := checkReqVersion(req.Key(), req.Version); err != nil {
+		return nil, err
+	}
+
+	return c.shareGroups.handleHeartbeat(creq), nil
+}
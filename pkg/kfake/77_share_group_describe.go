@@ -0,0 +1,17 @@
+package kfake
+
+import (
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func init() { regKey(77, 0, 0) }
+
+func (c *Cluster) handleShareGroupDescribe(creq *clientReq) (kmsg.Response, error) {
+	req := creq.kreq.(*kmsg.ShareGroupDescribeRequest)
+
+	if err := checkReqVersion(req.Key(), req.Version); err != nil {
+		return nil, err
+	}
+
+	return c.shareGroups.handleDescribe(creq), nil
+}
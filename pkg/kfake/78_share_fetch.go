@@ -0,0 +1,228 @@
+package kfake
+
+import (
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// Behavior:
+//
+// * Acknowledgements are processed first, then records are acquired
+// * If the request acknowledges records, we never wait for MinBytes
+// * Closing the session (epoch -1) releases all records the member acquired
+
+func init() { regKey(78, 0, 0) }
+
+func (c *Cluster) handleShareFetch(creq *clientReq, w *watchFetch) (kmsg.Response, error) {
+	var (
+		req  = creq.kreq.(*kmsg.ShareFetchRequest)
+		resp = req.ResponseKind().(*kmsg.ShareFetchResponse)
+	)
+
+	if err := checkReqVersion(req.Key(), req.Version); err != nil {
+		return nil, err
+	}
+
+	if req.GroupID == nil || req.MemberID == nil {
+		resp.ErrorCode = kerr.InvalidRequest.Code
+		return resp, nil
+	}
+	g := c.shareGroups.get(*req.GroupID)
+	if g == nil || g.members[*req.MemberID] == nil {
+		resp.ErrorCode = kerr.UnknownMemberID.Code
+		return resp, nil
+	}
+	member := *req.MemberID
+
+	tidx := make(map[uuid]int)
+	donet := func(id uuid) *kmsg.ShareFetchResponseTopic {
+		if i, ok := tidx[id]; ok {
+			return &resp.Topics[i]
+		}
+		tidx[id] = len(resp.Topics)
+		st := kmsg.NewShareFetchResponseTopic()
+		st.TopicID = id
+		resp.Topics = append(resp.Topics, st)
+		return &resp.Topics[len(resp.Topics)-1]
+	}
+	pidx := make(map[uuid]map[int32]int)
+	donep := func(id uuid, p int32) *kmsg.ShareFetchResponseTopicPartition {
+		st := donet(id)
+		if i, ok := pidx[id][p]; ok {
+			return &st.Partitions[i]
+		}
+		if pidx[id] == nil {
+			pidx[id] = make(map[int32]int)
+		}
+		pidx[id][p] = len(st.Partitions)
+		sp := kmsg.NewShareFetchResponseTopicPartition()
+		sp.Partition = p
+		st.Partitions = append(st.Partitions, sp)
+		return &st.Partitions[len(st.Partitions)-1]
+	}
+
+	var includeBrokers bool
+	defer func() {
+		if includeBrokers {
+			for _, b := range c.bs {
+				sb := kmsg.NewShareFetchResponseNodeEndpoint()
+				sb.NodeID = b.node
+				sb.Host, sb.Port = b.hostport(creq.cc.listener)
+				resp.NodeEndpoints = append(resp.NodeEndpoints, sb)
+			}
+		}
+	}()
+
+	// leader returns the partition data if this broker is the leader,
+	// otherwise it fills in the appropriate error and returns nil.
+	leader := func(t string, id uuid, p int32, setErr func(*kmsg.ShareFetchResponseTopicPartition, int16)) *partData {
+		pd, ok := c.data.tps.getp(t, p)
+		if !ok {
+			setErr(donep(id, p), kerr.UnknownTopicOrPartition.Code)
+			return nil
+		}
+		if pd.leader != creq.cc.b {
+			sp := donep(id, p)
+			setErr(sp, kerr.NotLeaderForPartition.Code)
+			sp.CurrentLeader.LeaderID = pd.leader.node
+			sp.CurrentLeader.LeaderEpoch = pd.epoch
+			includeBrokers = true
+			return nil
+		}
+		return pd
+	}
+	fetchErr := func(sp *kmsg.ShareFetchResponseTopicPartition, code int16) { sp.ErrorCode = code }
+	ackErr := func(sp *kmsg.ShareFetchResponseTopicPartition, code int16) { sp.AcknowledgeErrorCode = code }
+
+	node := creq.cc.b.node
+	var s *shareSession
+	if w == nil {
+		var errCode int16
+		if s, errCode = g.session(member, node, req.ShareSessionEpoch, true); errCode != 0 {
+			resp.ErrorCode = errCode
+			return resp, nil
+		}
+
+		var acked bool
+		for _, rt := range req.Topics {
+			t, ok := c.data.id2t[rt.TopicID]
+			if !ok {
+				for _, rp := range rt.Partitions {
+					donep(rt.TopicID, rp.Partition).ErrorCode = kerr.UnknownTopicID.Code
+				}
+				continue
+			}
+			for _, rp := range rt.Partitions {
+				s.parts.set(t, rp.Partition, rp.PartitionMaxBytes)
+				if len(rp.AcknowledgementBatches) == 0 {
+					continue
+				}
+				acked = true
+				pd := leader(t, rt.TopicID, rp.Partition, ackErr)
+				if pd == nil {
+					continue
+				}
+				sp := g.part(t, rp.Partition, pd)
+				donep(rt.TopicID, rp.Partition).AcknowledgeErrorCode = sp.acknowledge(member, rp.AcknowledgementBatches)
+			}
+		}
+		for _, rt := range req.ForgottenTopicsData {
+			t, ok := c.data.id2t[rt.TopicID]
+			if !ok {
+				continue
+			}
+			for _, p := range rt.Partitions {
+				s.parts.delp(t, p)
+			}
+		}
+
+		if req.ShareSessionEpoch == -1 {
+			g.closeSession(member, node)
+			return resp, nil
+		}
+
+		if !acked && req.MaxWaitMillis > 0 && !g.anyAcquirable(s) {
+			wait := time.Duration(req.MaxWaitMillis) * time.Millisecond
+			need := int(req.MinBytes)
+			if need < 1 {
+				need = 1
+			}
+			w := &watchFetch{
+				need:     need,
+				deadline: creq.at.Add(wait),
+				creq:     creq,
+			}
+			w.cb = func() {
+				select {
+				case c.watchFetchCh <- w:
+				case <-c.die:
+				}
+			}
+			s.parts.each(func(t string, p int32, _ *int32) {
+				if pd, ok := c.data.tps.getp(t, p); ok {
+					w.watch(t, p, pd)
+				}
+			})
+			c.park(w, wait)
+			return nil, nil
+		}
+	} else if s = g.sessions[shareSessionKey{member, node}]; s == nil {
+		return resp, nil // session closed while we were waiting
+	}
+
+	remaining := int(req.MaxBytes)
+	s.parts.each(func(t string, p int32, maxBytes *int32) {
+		if remaining <= 0 {
+			return
+		}
+		id := c.data.t2id[t]
+		pd := leader(t, id, p, fetchErr)
+		if pd == nil {
+			return
+		}
+		limit := int(*maxBytes)
+		if limit <= 0 || limit > remaining {
+			limit = remaining
+		}
+		batches, acquired, nbytes := g.part(t, p, pd).acquire(pd, member, limit)
+		if len(acquired) == 0 {
+			return
+		}
+		remaining -= nbytes
+		sp := donep(id, p)
+		sp.Records = batches
+		sp.AcquiredRecords = acquired
+	})
+
+	return resp, nil
+}
+
+// anyAcquirable returns whether any partition in the session has records
+// that can be acquired immediately.
+func (g *shareGroup) anyAcquirable(s *shareSession) bool {
+	var acquirable bool
+	s.parts.each(func(t string, p int32, _ *int32) {
+		pd, ok := g.c.data.tps.getp(t, p)
+		if !ok || acquirable {
+			return
+		}
+		sp, ok := g.parts.getp(t, p)
+		if !ok {
+			acquirable = pd.highWatermark > pd.logStartOffset
+			return
+		}
+		if sp.end < pd.highWatermark {
+			acquirable = true
+			return
+		}
+		for _, r := range sp.recs {
+			if r.state == shareRecAvailable {
+				acquirable = true
+				return
+			}
+		}
+	})
+	return acquirable
+}
@@ -0,0 +1,78 @@
+package kfake
+
+import (
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func init() { regKey(79, 0, 0) }
+
+func (c *Cluster) handleShareAcknowledge(creq *clientReq) (kmsg.Response, error) {
+	var (
+		req  = creq.kreq.(*kmsg.ShareAcknowledgeRequest)
+		resp = req.ResponseKind().(*kmsg.ShareAcknowledgeResponse)
+	)
+
+	if err := checkReqVersion(req.Key(), req.Version); err != nil {
+		return nil, err
+	}
+
+	if req.GroupID == nil || req.MemberID == nil {
+		resp.ErrorCode = kerr.InvalidRequest.Code
+		return resp, nil
+	}
+	g := c.shareGroups.get(*req.GroupID)
+	if g == nil || g.members[*req.MemberID] == nil {
+		resp.ErrorCode = kerr.UnknownMemberID.Code
+		return resp, nil
+	}
+	member := *req.MemberID
+	node := creq.cc.b.node
+
+	if _, errCode := g.session(member, node, req.ShareSessionEpoch, false); errCode != 0 {
+		resp.ErrorCode = errCode
+		return resp, nil
+	}
+
+	var includeBrokers bool
+	for _, rt := range req.Topics {
+		st := kmsg.NewShareAcknowledgeResponseTopic()
+		st.TopicID = rt.TopicID
+		t, tok := c.data.id2t[rt.TopicID]
+		for _, rp := range rt.Partitions {
+			sp := kmsg.NewShareAcknowledgeResponseTopicPartition()
+			sp.Partition = rp.Partition
+			pd, ok := c.data.tps.getp(t, rp.Partition)
+			switch {
+			case !tok:
+				sp.ErrorCode = kerr.UnknownTopicID.Code
+			case !ok:
+				sp.ErrorCode = kerr.UnknownTopicOrPartition.Code
+			case pd.leader != creq.cc.b:
+				sp.ErrorCode = kerr.NotLeaderForPartition.Code
+				sp.CurrentLeader.LeaderID = pd.leader.node
+				sp.CurrentLeader.LeaderEpoch = pd.epoch
+				includeBrokers = true
+			default:
+				sp.ErrorCode = g.part(t, rp.Partition, pd).acknowledge(member, shareAckBatches(rp.AcknowledgementBatches))
+			}
+			st.Partitions = append(st.Partitions, sp)
+		}
+		resp.Topics = append(resp.Topics, st)
+	}
+
+	if req.ShareSessionEpoch == -1 {
+		g.closeSession(member, node)
+	}
+
+	if includeBrokers {
+		for _, b := range c.bs {
+			sb := kmsg.NewShareAcknowledgeResponseNodeEndpoint()
+			sb.NodeID = b.node
+			sb.Host, sb.Port = b.hostport(creq.cc.listener)
+			resp.NodeEndpoints = append(resp.NodeEndpoints, sb)
+		}
+	}
+
+	return resp, nil
+}
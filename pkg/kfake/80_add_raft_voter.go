@@ -0,0 +1,29 @@
+package kfake
+
+import (
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func init() { regKey(80, 0, 0) }
+
+func (c *Cluster) handleAddRaftVoter(kreq kmsg.Request) (kmsg.Response, error) {
+	req := kreq.(*kmsg.AddRaftVoterRequest)
+	resp := req.ResponseKind().(*kmsg.AddRaftVoterResponse)
+
+	if err := checkReqVersion(req.Key(), req.Version); err != nil {
+		return nil, err
+	}
+
+	if err := c.quorum.validateClusterID(req.ClusterID); err != nil {
+		resp.ErrorCode = err.Code
+		return resp, nil
+	}
+	var listeners []quorumVoterListener
+	for _, l := range req.Listeners {
+		listeners = append(listeners, quorumVoterListener{l.Name, l.Host, l.Port})
+	}
+	if err := c.quorum.add(req.VoterID, req.VoterDirectoryID, listeners); err != nil {
+		resp.ErrorCode = err.Code
+	}
+	return resp, nil
+}
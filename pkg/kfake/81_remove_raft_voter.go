@@ -0,0 +1,25 @@
+package kfake
+
+import (
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func init() { regKey(81, 0, 0) }
+
+func (c *Cluster) handleRemoveRaftVoter(kreq kmsg.Request) (kmsg.Response, error) {
+	req := kreq.(*kmsg.RemoveRaftVoterRequest)
+	resp := req.ResponseKind().(*kmsg.RemoveRaftVoterResponse)
+
+	if err := checkReqVersion(req.Key(), req.Version); err != nil {
+		return nil, err
+	}
+
+	if err := c.quorum.validateClusterID(req.ClusterID); err != nil {
+		resp.ErrorCode = err.Code
+		return resp, nil
+	}
+	if err := c.quorum.remove(req.VoterID, req.VoterDirectoryID); err != nil {
+		resp.ErrorCode = err.Code
+	}
+	return resp, nil
+}
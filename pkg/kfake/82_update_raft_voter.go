@@ -0,0 +1,36 @@
+package kfake
+
+import (
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func init() { regKey(82, 0, 0) }
+
+func (c *Cluster) handleUpdateRaftVoter(kreq kmsg.Request) (kmsg.Response, error) {
+	req := kreq.(*kmsg.UpdateRaftVoterRequest)
+	resp := req.ResponseKind().(*kmsg.UpdateRaftVoterResponse)
+
+	if err := checkReqVersion(req.Key(), req.Version); err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		l := &resp.CurrentLeader
+		l.LeaderID = c.quorum.leader()
+		l.LeaderEpoch = c.quorum.epoch
+		l.Host, l.Port = c.controller.hostport("")
+	}()
+
+	if err := c.quorum.validateClusterID(req.ClusterID); err != nil {
+		resp.ErrorCode = err.Code
+		return resp, nil
+	}
+	var listeners []quorumVoterListener
+	for _, l := range req.Listeners {
+		listeners = append(listeners, quorumVoterListener{l.Name, l.Host, l.Port})
+	}
+	if err := c.quorum.update(req.VoterID, req.VoterDirectoryID, req.CurrentLeaderEpoch, listeners); err != nil {
+		resp.ErrorCode = err.Code
+	}
+	return resp, nil
+}
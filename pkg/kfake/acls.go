@@ -0,0 +1,336 @@
+package kfake
+
+import (
+	"crypto/tls"
+	"net"
+	"strings"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// ACLs are always stored and can be described and deleted, but are only
+// enforced with EnableACLs. Authorization follows Kafka's authorizer:
+//
+//   - superusers (users seeded with Superuser, or the default SASL user) are
+//     always allowed
+//   - a request is denied if any DENY ACL matches, otherwise allowed if any
+//     ALLOW ACL matches, otherwise denied, as with Kafka's default of
+//     allow.everyone.if.no.acl.found=false
+//   - ALLOW ACLs for READ, WRITE, DELETE, or ALTER imply DESCRIBE, and ALLOW
+//     ACLs for ALTER_CONFIGS imply DESCRIBE_CONFIGS
+//
+// The principal is User:<user> for SASL users (or the token owner for
+// delegation tokens), User:<subject DN> for TLS client certificates, and
+// User:ANONYMOUS otherwise. ACL hosts are matched against the client IP.
+//
+// TODO
+//
+// * Authorized operations in Metadata and DescribeGroups
+// * Requests not listed in EnableACLs are not authorized
+
+type (
+	acl struct {
+		resourceType kmsg.ACLResourceType
+		name         string
+		pattern      kmsg.ACLResourcePatternType
+		principal    string
+		host         string
+		operation    kmsg.ACLOperation
+		permission   kmsg.ACLPermissionType
+	}
+
+	// aclFilter is the common shape of DescribeACLs requests and
+	// DeleteACLs filters.
+	aclFilter struct {
+		resourceType kmsg.ACLResourceType
+		name         *string
+		pattern      kmsg.ACLResourcePatternType
+		principal    *string
+		host         *string
+		operation    kmsg.ACLOperation
+		permission   kmsg.ACLPermissionType
+	}
+
+	acls []acl
+)
+
+// validateCreate returns the error code for creating a, if a is invalid.
+func (a acl) validateCreate() (int16, string) {
+	switch {
+	case a.resourceType == kmsg.ACLResourceTypeUnknown || a.resourceType == kmsg.ACLResourceTypeAny:
+		return kerr.InvalidRequest.Code, "invalid resource type"
+	case a.pattern != kmsg.ACLResourcePatternTypeLiteral && a.pattern != kmsg.ACLResourcePatternTypePrefixed:
+		return kerr.InvalidRequest.Code, "invalid resource pattern type, only literal and prefixed are allowed"
+	case a.operation == kmsg.ACLOperationUnknown || a.operation == kmsg.ACLOperationAny:
+		return kerr.InvalidRequest.Code, "invalid operation"
+	case a.permission != kmsg.ACLPermissionTypeAllow && a.permission != kmsg.ACLPermissionTypeDeny:
+		return kerr.InvalidRequest.Code, "invalid permission type"
+	case !strings.Contains(a.principal, ":"):
+		return kerr.InvalidRequest.Code, "invalid principal, expected type:name"
+	case a.name == "":
+		return kerr.InvalidRequest.Code, "invalid empty resource name"
+	case a.resourceType == kmsg.ACLResourceTypeCluster && a.name != "kafka-cluster":
+		return kerr.InvalidRequest.Code, "invalid cluster resource name, only kafka-cluster is allowed"
+	}
+	return 0, ""
+}
+
+// validate returns the error code for using f, if f is invalid.
+func (f aclFilter) validate() (int16, string) {
+	switch {
+	case f.resourceType == kmsg.ACLResourceTypeUnknown:
+		return kerr.InvalidRequest.Code, "invalid unknown resource type"
+	case f.pattern == kmsg.ACLResourcePatternTypeUnknown:
+		return kerr.InvalidRequest.Code, "invalid unknown resource pattern type"
+	case f.operation == kmsg.ACLOperationUnknown:
+		return kerr.InvalidRequest.Code, "invalid unknown operation"
+	case f.permission == kmsg.ACLPermissionTypeUnknown:
+		return kerr.InvalidRequest.Code, "invalid unknown permission type"
+	}
+	return 0, ""
+}
+
+// matches returns whether the filter matches the acl, following Kafka's
+// ResourcePatternFilter and AccessControlEntryFilter semantics.
+func (f aclFilter) matches(a acl) bool {
+	if f.resourceType != kmsg.ACLResourceTypeAny && f.resourceType != a.resourceType {
+		return false
+	}
+	switch f.pattern {
+	case kmsg.ACLResourcePatternTypeAny:
+		if f.name != nil && *f.name != a.name {
+			return false
+		}
+	case kmsg.ACLResourcePatternTypeMatch:
+		// MATCH: a nil name matches anything, otherwise the name
+		// matches literal ACLs of the same name or the wildcard, and
+		// prefixed ACLs that prefix the name.
+		if f.name != nil {
+			switch a.pattern {
+			case kmsg.ACLResourcePatternTypeLiteral:
+				if a.name != *f.name && a.name != "*" {
+					return false
+				}
+			case kmsg.ACLResourcePatternTypePrefixed:
+				if !strings.HasPrefix(*f.name, a.name) {
+					return false
+				}
+			default:
+				return false
+			}
+		}
+	default:
+		if f.pattern != a.pattern || f.name != nil && *f.name != a.name {
+			return false
+		}
+	}
+	if f.principal != nil && *f.principal != a.principal {
+		return false
+	}
+	if f.host != nil && *f.host != a.host {
+		return false
+	}
+	if f.operation != kmsg.ACLOperationAny && f.operation != a.operation {
+		return false
+	}
+	if f.permission != kmsg.ACLPermissionTypeAny && f.permission != a.permission {
+		return false
+	}
+	return true
+}
+
+// add adds the acl if it does not already exist.
+func (as *acls) add(a acl) {
+	for _, exist := range *as {
+		if exist == a {
+			return
+		}
+	}
+	*as = append(*as, a)
+}
+
+func (as acls) filter(f aclFilter) []acl {
+	var matched []acl
+	for _, a := range as {
+		if f.matches(a) {
+			matched = append(matched, a)
+		}
+	}
+	return matched
+}
+
+// delete deletes all acls matching the filter, returning what was deleted.
+func (as *acls) delete(f aclFilter) []acl {
+	var deleted []acl
+	keep := (*as)[:0]
+	for _, a := range *as {
+		if f.matches(a) {
+			deleted = append(deleted, a)
+		} else {
+			keep = append(keep, a)
+		}
+	}
+	*as = keep
+	return deleted
+}
+
+// EnableACLs enables authorization: requests are authorized against ACLs
+// created with CreateACLs, and unauthorized requests fail with
+// TOPIC_AUTHORIZATION_FAILED, GROUP_AUTHORIZATION_FAILED,
+// TRANSACTIONAL_ID_AUTHORIZATION_FAILED, or CLUSTER_AUTHORIZATION_FAILED, as
+// in Kafka. Users seeded with Superuser are superusers and are always
+// allowed; users added with AddUser are not. Without SASL, clients are
+// User:ANONYMOUS, or User:<subject DN> if they present a TLS client
+// certificate.
+//
+// Produce, Fetch, ListOffsets, Metadata, group requests, offset requests,
+// transaction requests, topic creation and deletion, record deletion,
+// partition creation, config requests, and ACL requests are authorized.
+func EnableACLs() Opt {
+	return opt{func(cfg *cfg) { cfg.enableACLs = true }}
+}
+
+// implies returns whether an ALLOW ACL for operation op allows operation
+// want.
+func implies(op, want kmsg.ACLOperation) bool {
+	switch {
+	case op == want, op == kmsg.ACLOperationAll:
+		return true
+	case want == kmsg.ACLOperationDescribe:
+		switch op {
+		case kmsg.ACLOperationRead, kmsg.ACLOperationWrite, kmsg.ACLOperationDelete, kmsg.ACLOperationAlter:
+			return true
+		}
+	case want == kmsg.ACLOperationDescribeConfigs:
+		return op == kmsg.ACLOperationAlterConfigs
+	}
+	return false
+}
+
+// matchesResource returns whether the acl's resource pattern matches name.
+func (a acl) matchesResource(name string) bool {
+	if a.pattern == kmsg.ACLResourcePatternTypePrefixed {
+		return strings.HasPrefix(name, a.name)
+	}
+	return a.name == name || a.name == "*"
+}
+
+// matchesClient returns whether the acl applies to the principal and host.
+func (a acl) matchesClient(principal, host string) bool {
+	return (a.principal == principal || a.principal == "User:*") && (a.host == host || a.host == "*")
+}
+
+// clientPrincipal returns the principal and host of the client that issued
+// creq.
+func (c *Cluster) clientPrincipal(creq *clientReq) (string, string) {
+	principal := creq.cc.principal
+	if principal == "" {
+		principal = "User:ANONYMOUS"
+		if tc, ok := creq.cc.conn.(*tls.Conn); ok {
+			if certs := tc.ConnectionState().PeerCertificates; len(certs) > 0 {
+				principal = "User:" + certs[0].Subject.String()
+			}
+		}
+	}
+	host := creq.cc.conn.RemoteAddr().String()
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return principal, host
+}
+
+// allowed returns whether the client that issued creq is allowed to perform
+// op on the given resource. This is safe to call from group manage loops.
+func (c *Cluster) allowed(creq *clientReq, rt kmsg.ACLResourceType, name string, op kmsg.ACLOperation) bool {
+	if !c.cfg.enableACLs {
+		return true
+	}
+	principal, host := c.clientPrincipal(creq)
+	if _, ok := c.superusers[principal]; ok {
+		return true
+	}
+	c.aclsMu.RLock()
+	defer c.aclsMu.RUnlock()
+	var allow bool
+	for _, a := range c.acls {
+		if a.resourceType != rt || !a.matchesResource(name) || !a.matchesClient(principal, host) {
+			continue
+		}
+		switch a.permission {
+		case kmsg.ACLPermissionTypeDeny:
+			if a.operation == op || a.operation == kmsg.ACLOperationAll {
+				return false
+			}
+		case kmsg.ACLPermissionTypeAllow:
+			allow = allow || implies(a.operation, op)
+		}
+	}
+	return allow
+}
+
+// allowedAny returns whether the client that issued creq is allowed to
+// perform op on any resource of the given type, as Kafka's
+// authorizeByResourceType.
+func (c *Cluster) allowedAny(creq *clientReq, rt kmsg.ACLResourceType, op kmsg.ACLOperation) bool {
+	if !c.cfg.enableACLs {
+		return true
+	}
+	principal, host := c.clientPrincipal(creq)
+	if _, ok := c.superusers[principal]; ok {
+		return true
+	}
+	c.aclsMu.RLock()
+	defer c.aclsMu.RUnlock()
+	for _, a := range c.acls {
+		if a.resourceType == rt && a.permission == kmsg.ACLPermissionTypeAllow && a.matchesClient(principal, host) && implies(a.operation, op) {
+			return true
+		}
+	}
+	return false
+}
+
+// authorizedOps returns the bitfield of the given ops that the client that
+// issued creq is allowed to perform on the resource, as returned in
+// AuthorizedOperations response fields.
+func (c *Cluster) authorizedOps(creq *clientReq, rt kmsg.ACLResourceType, name string, ops ...kmsg.ACLOperation) int32 {
+	var authorized int32
+	for _, op := range ops {
+		if c.allowed(creq, rt, name, op) {
+			authorized |= 1 << op
+		}
+	}
+	return authorized
+}
+
+func (c *Cluster) allowedCluster(creq *clientReq, op kmsg.ACLOperation) bool {
+	return c.allowed(creq, kmsg.ACLResourceTypeCluster, "kafka-cluster", op)
+}
+
+func (c *Cluster) allowedTopic(creq *clientReq, t string, op kmsg.ACLOperation) bool {
+	return c.allowed(creq, kmsg.ACLResourceTypeTopic, t, op)
+}
+
+func (c *Cluster) allowedGroup(creq *clientReq, g string, op kmsg.ACLOperation) bool {
+	return c.allowed(creq, kmsg.ACLResourceTypeGroup, g, op)
+}
+
+func (c *Cluster) allowedTxnID(creq *clientReq, id string, op kmsg.ACLOperation) bool {
+	return c.allowed(creq, kmsg.ACLResourceTypeTransactionalId, id, op)
+}
+
+// configAuthErr returns the error code if the client that issued creq is not
+// allowed to perform op on the configs of the given resource, or 0.
+func (c *Cluster) configAuthErr(creq *clientReq, rt kmsg.ConfigResourceType, name string, op kmsg.ACLOperation) int16 {
+	switch rt {
+	case kmsg.ConfigResourceTypeBroker:
+		if !c.allowedCluster(creq, op) {
+			return kerr.ClusterAuthorizationFailed.Code
+		}
+	case kmsg.ConfigResourceTypeTopic:
+		if !c.allowedTopic(creq, name, op) {
+			return kerr.TopicAuthorizationFailed.Code
+		}
+	}
+	return 0
+}
@@ -0,0 +1,110 @@
+package kfake
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+	"github.com/twmb/franz-go/pkg/sasl/plain"
+)
+
+func TestEnableACLs(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(1), SeedTopics(1, "t", "other"),
+		EnableSASL(), EnableACLs(), Superuser("PLAIN", "admin", "pw"))
+	if err := c.AddUser("PLAIN", "u", "p"); err != nil {
+		t.Fatal(err)
+	}
+	adm := kadm.NewClient(newTestClient(t, c, kgo.SASL(plain.Auth{User: "admin", Pass: "pw"}.AsMechanism())))
+	user := newTestClient(t, c, kgo.SASL(plain.Auth{User: "u", Pass: "p"}.AsMechanism()))
+	br := user.Broker(0)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	create := func(b *kadm.ACLBuilder) {
+		t.Helper()
+		rs, err := adm.CreateACLs(ctx, b.ResourcePatternType(kadm.ACLPatternLiteral))
+		if err == nil && len(rs) > 0 {
+			err = rs[0].Err
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	produce := func(topic string) error {
+		t.Helper()
+		return kerr.ErrorForCode(produceBatch(ctx, t, br, topic, 0, kgo.StringRecord("v")).ErrorCode)
+	}
+	describe := func(topic string) error {
+		t.Helper()
+		req := kmsg.NewPtrMetadataRequest()
+		rt := kmsg.NewMetadataRequestTopic()
+		rt.Topic = kmsg.StringPtr(topic)
+		req.Topics = append(req.Topics, rt)
+		resp, err := req.RequestWith(ctx, br)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return kerr.ErrorForCode(resp.Topics[0].ErrorCode)
+	}
+
+	// With no ACLs, everything is denied.
+	if err := produce("t"); !errors.Is(err, kerr.TopicAuthorizationFailed) {
+		t.Errorf("produce without ACLs: got %v, expected TOPIC_AUTHORIZATION_FAILED", err)
+	}
+
+	// WRITE allows producing and implies DESCRIBE, but only for the topic.
+	create(kadm.NewACLs().Allow("User:u").AllowHosts().Topics("t").Operations(kadm.OpWrite))
+	if err := produce("t"); err != nil {
+		t.Errorf("produce with WRITE: %v", err)
+	}
+	if err := describe("t"); err != nil {
+		t.Errorf("metadata with WRITE: %v", err)
+	}
+	if err := produce("other"); !errors.Is(err, kerr.TopicAuthorizationFailed) {
+		t.Errorf("produce to another topic: got %v, expected TOPIC_AUTHORIZATION_FAILED", err)
+	}
+	if err := describe("other"); !errors.Is(err, kerr.TopicAuthorizationFailed) {
+		t.Errorf("metadata for another topic: got %v, expected TOPIC_AUTHORIZATION_FAILED", err)
+	}
+
+	// DENY wins over ALLOW.
+	create(kadm.NewACLs().Deny("User:u").DenyHosts().Topics("t").Operations(kadm.OpWrite))
+	if err := produce("t"); !errors.Is(err, kerr.TopicAuthorizationFailed) {
+		t.Errorf("produce with a DENY: got %v, expected TOPIC_AUTHORIZATION_FAILED", err)
+	}
+
+	// Group and cluster operations are authorized as well.
+	find := kmsg.NewPtrFindCoordinatorRequest()
+	find.CoordinatorKeys = []string{"g"}
+	findResp, err := find.RequestWith(ctx, br)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code := findResp.Coordinators[0].ErrorCode; code != kerr.GroupAuthorizationFailed.Code {
+		t.Errorf("find coordinator: got %v, expected GROUP_AUTHORIZATION_FAILED", kerr.ErrorForCode(code))
+	}
+	createACLs := kmsg.NewPtrCreateACLsRequest()
+	creation := kmsg.NewCreateACLsRequestCreation()
+	creation.ResourceType, creation.ResourceName = kmsg.ACLResourceTypeTopic, "other"
+	creation.ResourcePatternType = kmsg.ACLResourcePatternTypeLiteral
+	creation.Principal, creation.Host = "User:u", "*"
+	creation.Operation, creation.PermissionType = kmsg.ACLOperationAll, kmsg.ACLPermissionTypeAllow
+	createACLs.Creations = append(createACLs.Creations, creation)
+	aclResp, err := createACLs.RequestWith(ctx, br)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code := aclResp.Results[0].ErrorCode; code != kerr.ClusterAuthorizationFailed.Code {
+		t.Errorf("create ACLs: got %v, expected CLUSTER_AUTHORIZATION_FAILED", kerr.ErrorForCode(code))
+	}
+
+	// The superuser bypasses ACLs.
+	if _, err := adm.CreateTopic(ctx, 1, 1, nil, "new"); err != nil {
+		t.Errorf("superuser create topic: %v", err)
+	}
+}
@@ -0,0 +1,92 @@
+package kfake
+
+import (
+	"errors"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// ApiVersionsFault configures how FailApiVersions fails ApiVersions requests.
+// ApiVersions is the first request on every connection, and mishandling a
+// failure here is a common source of client bootstrap bugs.
+type ApiVersionsFault struct {
+	// Delay delays the reply (or the connection close) by the duration.
+	Delay time.Duration
+
+	// Close closes the connection rather than replying.
+	Close bool
+
+	// OnlyV0 replies as a broker that only supports ApiVersions v0 does:
+	// a v0 response with UNSUPPORTED_VERSION and no keys. Clients are
+	// expected to retry with v0; v0 requests are not failed and do not
+	// count against the number of requests to fail.
+	OnlyV0 bool
+
+	// ErrorCode, if non-zero, is returned in an otherwise empty response.
+	ErrorCode int16
+}
+
+type apiVersionsFault struct {
+	ApiVersionsFault
+	remaining int
+}
+
+// FailApiVersions fails the next n ApiVersions requests, across all brokers,
+// as configured by f. If Close, OnlyV0, and ErrorCode are all unset, the
+// requests are only delayed. If more than one is set, Close takes precedence
+// over OnlyV0, which takes precedence over ErrorCode. Calling this again
+// replaces the prior fault, and n <= 0 clears it.
+func (c *Cluster) FailApiVersions(n int, f ApiVersionsFault) {
+	c.admin(func() {
+		c.apiVersionsFault = nil
+		if n > 0 {
+			c.apiVersionsFault = &apiVersionsFault{f, n}
+		}
+	})
+}
+
+// failApiVersions returns whether the ApiVersions request was handled by a
+// fault. If the fault is delayed, the reply is sent later and this returns a
+// nil response and nil error.
+func (c *Cluster) failApiVersions(creq *clientReq) (kmsg.Response, error, bool) {
+	f := c.apiVersionsFault
+	req := creq.kreq.(*kmsg.ApiVersionsRequest)
+	if f == nil || f.OnlyV0 && !f.Close && req.Version == 0 {
+		return nil, nil, false
+	}
+	if f.remaining--; f.remaining <= 0 {
+		c.apiVersionsFault = nil
+	}
+
+	var (
+		kresp kmsg.Response
+		err   error
+	)
+	switch {
+	case f.Close:
+		err = errors.New("failing ApiVersions by closing the connection")
+	case f.OnlyV0:
+		resp := req.ResponseKind().(*kmsg.ApiVersionsResponse)
+		resp.Version = 0
+		resp.ErrorCode = kerr.UnsupportedVersion.Code
+		kresp = resp
+	case f.ErrorCode != 0:
+		resp := req.ResponseKind().(*kmsg.ApiVersionsResponse)
+		resp.ErrorCode = f.ErrorCode
+		kresp = resp
+	default:
+		kresp, err = c.handleApiVersions(req)
+	}
+	if f.Delay <= 0 {
+		return kresp, err, true
+	}
+	time.AfterFunc(f.Delay, func() {
+		select {
+		case creq.cc.respCh <- clientResp{kresp: kresp, corr: creq.corr, err: err, seq: creq.seq}:
+		case <-c.die:
+		}
+	})
+	return nil, nil, true
+}
@@ -0,0 +1,94 @@
+package kfake
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestFailApiVersions(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(1))
+
+	dial := func() net.Conn {
+		t.Helper()
+		conn, err := net.DialTimeout("tcp", c.ListenAddrs()[0], time.Second)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { conn.Close() })
+		conn.SetDeadline(time.Now().Add(5 * time.Second))
+		return conn
+	}
+	// apiVersions issues an ApiVersions request at the given version and
+	// decodes the response at the version the response says it is.
+	apiVersions := func(conn net.Conn, version, respVersion int16) (*kmsg.ApiVersionsResponse, error) {
+		t.Helper()
+		req := kmsg.NewPtrApiVersionsRequest()
+		req.Version = version
+		req.ClientSoftwareName, req.ClientSoftwareVersion = "kfake", "test"
+		if _, err := conn.Write(appendFrame(nil, req, 1)); err != nil {
+			return nil, err
+		}
+		var size [4]byte
+		if _, err := io.ReadFull(conn, size[:]); err != nil {
+			return nil, err
+		}
+		body := make([]byte, binary.BigEndian.Uint32(size[:]))
+		if _, err := io.ReadFull(conn, body); err != nil {
+			return nil, err
+		}
+		resp := kmsg.NewPtrApiVersionsResponse()
+		resp.Version = respVersion
+		if err := resp.ReadFrom(body[4:]); err != nil {
+			t.Fatalf("unable to decode response: %v", err)
+		}
+		return resp, nil
+	}
+	check := func(resp *kmsg.ApiVersionsResponse, err error, code int16, keys bool) {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.ErrorCode != code || (len(resp.ApiKeys) > 0) != keys {
+			t.Errorf("got %v with %d keys, expected %v and keys %v", kerr.ErrorForCode(resp.ErrorCode), len(resp.ApiKeys), kerr.ErrorForCode(code), keys)
+		}
+	}
+
+	c.FailApiVersions(1, ApiVersionsFault{ErrorCode: kerr.UnknownServerError.Code})
+	conn := dial()
+	resp, err := apiVersions(conn, 3, 3)
+	check(resp, err, kerr.UnknownServerError.Code, false)
+	resp, err = apiVersions(conn, 3, 3)
+	check(resp, err, 0, true)
+
+	// A v0-only broker does not fail v0 requests, and they do not count
+	// against the fault.
+	c.FailApiVersions(1, ApiVersionsFault{OnlyV0: true})
+	conn = dial()
+	resp, err = apiVersions(conn, 0, 0)
+	check(resp, err, 0, true)
+	resp, err = apiVersions(conn, 3, 0)
+	check(resp, err, kerr.UnsupportedVersion.Code, false)
+	resp, err = apiVersions(conn, 3, 3)
+	check(resp, err, 0, true)
+
+	c.FailApiVersions(1, ApiVersionsFault{Close: true, Delay: 100 * time.Millisecond})
+	start := time.Now()
+	if _, err := apiVersions(dial(), 3, 3); !errors.Is(err, io.EOF) {
+		t.Errorf("got %v, expected the connection to be closed", err)
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("connection was closed after %v, before the delay", elapsed)
+	}
+
+	c.FailApiVersions(5, ApiVersionsFault{Close: true})
+	c.FailApiVersions(0, ApiVersionsFault{Close: true})
+	resp, err = apiVersions(dial(), 3, 3)
+	check(resp, err, 0, true)
+}
@@ -0,0 +1,103 @@
+package kfake
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// BenchmarkFetch measures fetching every partition of a topic from the start,
+// as a consumer reading a backlog does.
+func BenchmarkFetch(b *testing.B) {
+	const (
+		partitions = 8
+		batches    = 64 // per partition
+		records    = 16 // per batch
+	)
+	value := bytes.Repeat([]byte("v"), 1<<10)
+
+	for _, bench := range []struct {
+		name string
+		opts []Opt
+	}{
+		{"default", nil},
+		{"high_throughput", []Opt{HighThroughput()}},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			c, err := NewCluster(append([]Opt{NumBrokers(1), SeedTopics(partitions, "t")}, bench.opts...)...)
+			if err != nil {
+				b.Fatal(err)
+			}
+			defer c.Close()
+
+			var size int64
+			for p := int32(0); p < partitions; p++ {
+				for i := 0; i < batches; i++ {
+					rs := make([]*kgo.Record, records)
+					for j := range rs {
+						rs[j] = &kgo.Record{Value: value}
+					}
+					if _, err := c.ProduceTo("t", p, rs...); err != nil {
+						b.Fatal(err)
+					}
+				}
+				size += int64(batches * records * len(value))
+			}
+
+			cl, err := kgo.NewClient(kgo.SeedBrokers(c.ListenAddrs()...))
+			if err != nil {
+				b.Fatal(err)
+			}
+			defer cl.Close()
+
+			// Fetch v13+ identifies topics by ID.
+			mreq := kmsg.NewPtrMetadataRequest()
+			mt := kmsg.NewMetadataRequestTopic()
+			mt.Topic = kmsg.StringPtr("t")
+			mreq.Topics = append(mreq.Topics, mt)
+			meta, err := mreq.RequestWith(context.Background(), cl)
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			req := kmsg.NewPtrFetchRequest()
+			req.ReplicaID = -1
+			req.MaxBytes = 1 << 30
+			req.SessionEpoch = -1
+			rt := kmsg.NewFetchRequestTopic()
+			rt.Topic = "t"
+			rt.TopicID = meta.Topics[0].TopicID
+			for p := int32(0); p < partitions; p++ {
+				rp := kmsg.NewFetchRequestTopicPartition()
+				rp.Partition = p
+				rp.PartitionMaxBytes = 1 << 30
+				rt.Partitions = append(rt.Partitions, rp)
+			}
+			req.Topics = append(req.Topics, rt)
+
+			broker := cl.Broker(0)
+			ctx := context.Background()
+			b.SetBytes(size)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				kresp, err := broker.Request(ctx, req)
+				if err != nil {
+					b.Fatal(err)
+				}
+				var fetched int64
+				for _, rt := range kresp.(*kmsg.FetchResponse).Topics {
+					for _, rp := range rt.Partitions {
+						fetched += int64(len(rp.RecordBatches))
+					}
+				}
+				if fetched < size {
+					b.Fatalf("fetched %d bytes, exp at least %d", fetched, size)
+				}
+			}
+		})
+	}
+}
@@ -1,9 +1,14 @@
 package kfake
 
 import (
+	"crypto/tls"
 	"encoding/binary"
+	"encoding/json"
+	"fmt"
 	"io"
 	"net"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/twmb/franz-go/pkg/kbin"
@@ -17,8 +22,28 @@ type (
 		conn   net.Conn
 		respCh chan clientResp
 
+		listener string // name of the listener the conn was accepted on, empty for the primary
+		sasl     bool   // whether the conn's listener requires SASL
+
+		// For graceful shutdown: the reader closes readDone when it
+		// stops reading requests, and pending is the number of read
+		// requests that still need a response written.
+		readDone chan struct{}
+		pending  atomic.Int64
+
 		saslStage saslStage
 		s0        *scramServer0
+		user      string // the authenticated SASL user, or token ID if token is true
+		token     bool   // whether the user authenticated with a delegation token
+
+		// For Connections: node and connectedAt are set when the conn
+		// is accepted, and clientID and principal are written under
+		// infoMu.
+		node        int32
+		connectedAt time.Time
+		infoMu      sync.Mutex
+		clientID    string // the client ID of the most recent request
+		principal   string // the ACL principal of the authenticated SASL user
 	}
 
 	clientReq struct {
@@ -28,33 +53,49 @@ type (
 		cid  string
 		corr int32
 		seq  uint32
+
+		// fetchBufs are the pooled buffers handed out for the record
+		// batches of this request's fetch response, if any.
+		fetchBufs []*[]byte
 	}
 
 	clientResp struct {
-		kresp kmsg.Response
-		corr  int32
-		err   error
-		seq   uint32
+		kresp     kmsg.Response
+		corr      int32
+		err       error
+		seq       uint32
+		fetchBufs []*[]byte // returned to the pool once the response is written
 	}
 )
 
 func (creq *clientReq) empty() bool { return creq == nil || creq.cc == nil || creq.kreq == nil }
 
 func (cc *clientConn) read() {
-	defer cc.conn.Close()
+	defer close(cc.readDone)
 
 	type read struct {
-		body []byte
-		err  error
+		body      []byte
+		err       error
+		handshake bool // if true, err is from the TLS handshake
 	}
 	var (
-		who    = cc.conn.RemoteAddr()
-		size   = make([]byte, 4)
-		readCh = make(chan read, 1)
-		seq    uint32
+		who        = cc.conn.RemoteAddr()
+		size       = make([]byte, 4)
+		readCh     = make(chan read, 1)
+		seq        uint32
+		tlsConn, _ = cc.conn.(*tls.Conn)
 	)
 	for {
 		go func() {
+			// We handshake explicitly, rather than on the first
+			// read, so that we can log why handshakes fail, e.g. a
+			// client not presenting a required certificate.
+			if tlsConn != nil {
+				if err := tlsConn.Handshake(); err != nil {
+					readCh <- read{err: err, handshake: true}
+					return
+				}
+			}
 			if _, err := io.ReadFull(cc.conn, size); err != nil {
 				readCh <- read{err: err}
 				return
@@ -68,40 +109,42 @@ func (cc *clientConn) read() {
 		select {
 		case <-cc.c.die:
 			return
+		case <-cc.c.draining:
+			return // the writer closes the conn once drained
 		case read = <-readCh:
 		}
 
 		if err := read.err; err != nil {
-			cc.c.cfg.logger.Logf(LogLevelDebug, "client %s disconnected from read: %v", who, err)
+			if read.handshake {
+				cc.c.cfg.logger.Logf(LogLevelInfo, "client %s failed TLS handshake: %v", who, err)
+			} else {
+				cc.c.cfg.logger.Logf(LogLevelDebug, "client %s disconnected from read: %v", who, err)
+			}
+			cc.closeRead()
 			return
 		}
 
-		var (
-			body     = read.body
-			reader   = kbin.Reader{Src: body}
-			key      = reader.Int16()
-			version  = reader.Int16()
-			corr     = reader.Int32()
-			clientID = reader.NullableString()
-			kreq     = kmsg.RequestForKey(key)
-		)
-		kreq.SetVersion(version)
-		if kreq.IsFlexible() {
-			kmsg.SkipTags(&reader)
-		}
-		if err := kreq.ReadFrom(reader.Src); err != nil {
+		kreq, corr, cid, err := parseRequest(read.body)
+		if err != nil {
 			cc.c.cfg.logger.Logf(LogLevelDebug, "client %s unable to parse request: %v", who, err)
+			cc.closeRead()
 			return
 		}
-
-		// Within Kafka, a null client ID is treated as an empty string.
-		var cid string
-		if clientID != nil {
-			cid = *clientID
+		if !cc.waitPaused() {
+			return
 		}
 
+		cc.setClientID(cid)
+		cc.c.stats.request(cc.node, kreq.Key(), 4+len(read.body))
+		cc.logProtocol("request from", cid, corr, kreq.Key(), kreq.GetVersion(), kreq)
+		cc.c.recordRequest(cc.b.node, cid, kreq)
+
+		// Produce requests with no acks are never replied to.
+		if p, ok := kreq.(*kmsg.ProduceRequest); !ok || p.Acks != 0 {
+			cc.pending.Add(1)
+		}
 		select {
-		case cc.c.reqCh <- &clientReq{cc, kreq, time.Now(), cid, corr, seq}:
+		case cc.c.reqCh <- &clientReq{cc: cc, kreq: kreq, at: time.Now(), cid: cid, corr: corr, seq: seq}:
 			seq++
 		case <-cc.c.die:
 			return
@@ -109,7 +152,79 @@ func (cc *clientConn) read() {
 	}
 }
 
+// parseRequest parses a request frame, without its leading size, into the
+// request and its header fields.
+func parseRequest(body []byte) (kmsg.Request, int32, string, error) {
+	var (
+		reader   = kbin.Reader{Src: body}
+		key      = reader.Int16()
+		version  = reader.Int16()
+		corr     = reader.Int32()
+		clientID = reader.NullableString()
+	)
+	if err := reader.Complete(); err != nil {
+		return nil, 0, "", err
+	}
+	kreq := kmsg.RequestForKey(key)
+	if kreq == nil {
+		return nil, 0, "", fmt.Errorf("unknown request key %d", key)
+	}
+	// Decoding a version we do not know can misinterpret the entire
+	// body, so like Kafka, we reject it. ApiVersions is the exception:
+	// handleApiVersions replies to newer versions so that clients can
+	// retry with a version we support.
+	if key != 18 && (version < 0 || version > kreq.MaxVersion()) {
+		return nil, 0, "", fmt.Errorf("unknown version %d for request key %d", version, key)
+	}
+	kreq.SetVersion(version)
+	if kreq.IsFlexible() {
+		kmsg.SkipTags(&reader)
+	}
+	if err := kreq.ReadFrom(reader.Src); err != nil {
+		return nil, 0, "", err
+	}
+
+	// Within Kafka, a null client ID is treated as an empty string.
+	var cid string
+	if clientID != nil {
+		cid = *clientID
+	}
+	return kreq, corr, cid, nil
+}
+
+// appendResponse appends a response frame, including its leading size, to dst.
+func appendResponse(dst []byte, kresp kmsg.Response, corr int32) []byte {
+	start := len(dst)
+	dst = append(dst, 0, 0, 0, 0) // size, filled in below
+	dst = kbin.AppendInt32(dst, corr)
+
+	// ApiVersions responses never have a flexible header.
+	if kresp.IsFlexible() && kresp.Key() != 18 {
+		dst = append(dst, 0) // empty tag section
+	}
+	dst = kresp.AppendTo(dst)
+	binary.BigEndian.PutUint32(dst[start:], uint32(len(dst)-start-4))
+	return dst
+}
+
+// closeRead closes the connection after a read failure. Nothing pending can be
+// written to a closed connection, so we clear pending to let the writer exit
+// as soon as the reader is done rather than waiting on in flight requests.
+func (cc *clientConn) closeRead() {
+	cc.conn.Close()
+	cc.pending.Store(0)
+}
+
 func (cc *clientConn) write() {
+	defer cc.c.conns.Done()
+	defer func() {
+		cc.c.liveMu.Lock()
+		delete(cc.c.live, cc)
+		cc.c.liveMu.Unlock()
+		if fn := cc.c.cfg.onDisconnect; fn != nil {
+			fn(cc.info())
+		}
+	}()
 	defer cc.conn.Close()
 
 	var (
@@ -129,6 +244,12 @@ func (cc *clientConn) write() {
 		oooresp = make(map[uint32]clientResp)
 	)
 	for {
+		// Once the reader is done, we only wait for responses to
+		// requests that were already read.
+		readDone := cc.readDone
+		if cc.pending.Load() > 0 {
+			readDone = nil
+		}
 		resp, ok := oooresp[seq]
 		if !ok {
 			select {
@@ -138,6 +259,11 @@ func (cc *clientConn) write() {
 					continue
 				}
 				seq = resp.seq + 1
+			case <-readDone:
+				if cc.pending.Load() > 0 {
+					continue // a request was read before readDone closed
+				}
+				return
 			case <-cc.c.die:
 				return
 			}
@@ -150,21 +276,26 @@ func (cc *clientConn) write() {
 			return
 		}
 
-		// Size, corr, and empty tag section if flexible: 9 bytes max.
-		buf = append(buf[:0], 0, 0, 0, 0, 0, 0, 0, 0, 0)
-		buf = resp.kresp.AppendTo(buf)
+		cc.logProtocol("response to", "", resp.corr, resp.kresp.Key(), resp.kresp.GetVersion(), resp.kresp)
 
-		start := 0
-		l := len(buf) - 4
-		if !resp.kresp.IsFlexible() || resp.kresp.Key() == 18 {
-			l--
-			start++
+		if d := cc.c.faultLatency(resp.kresp.Key(), cc.b.node); d > 0 {
+			select {
+			case <-cc.c.die:
+				return
+			case <-time.After(d):
+			}
+		}
+
+		corr := resp.corr
+		if cc.c.corrFault(resp.kresp.Key()) {
+			corr++
 		}
-		binary.BigEndian.PutUint32(buf[start:], uint32(l))
-		binary.BigEndian.PutUint32(buf[start+4:], uint32(resp.corr))
+		buf = appendResponse(buf[:0], resp.kresp, corr)
+		cc.c.stats.response(cc.node, resp.kresp, len(buf))
+		cc.c.releaseFetchBufs(resp.fetchBufs)
 
 		go func() {
-			_, err := cc.conn.Write(buf[start:])
+			_, err := cc.conn.Write(buf)
 			writeCh <- err
 		}()
 
@@ -178,5 +309,22 @@ func (cc *clientConn) write() {
 			cc.c.cfg.logger.Logf(LogLevelDebug, "client %s disconnected from write: %v", who, err)
 			return
 		}
+		cc.pending.Add(-1)
+	}
+}
+
+// logProtocol logs a request or response as JSON if LogProtocol is enabled.
+func (cc *clientConn) logProtocol(what, cid string, corr int32, key, version int16, msg any) {
+	if !cc.c.cfg.logProtocol {
+		return
+	}
+	body, err := json.Marshal(msg)
+	if err != nil {
+		body = []byte(fmt.Sprintf("<unable to encode: %v>", err))
+	}
+	who := cc.conn.RemoteAddr().String()
+	if cid != "" {
+		who += " (" + cid + ")"
 	}
+	cc.c.cfg.logger.Logf(LogLevelDebug, "node %d: %s v%d %s %s, correlation %d: %s", cc.b.node, kmsg.NameForKey(key), version, what, who, corr, body)
 }
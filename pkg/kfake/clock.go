@@ -0,0 +1,62 @@
+package kfake
+
+import (
+	"time"
+)
+
+// The cluster clock is what brokers consider "now": it timestamps
+// LogAppendTime batches, transaction markers, and records appended with
+// ProduceTo, validates produce timestamps, and decides when records are past
+// retention, when producer IDs expire, and when group sessions, rebalances,
+// and transactions time out. Each broker's clock is the cluster clock plus
+// its SetBrokerClockSkew skew. ListOffsets by timestamp resolves against the
+// timestamps stored in batches, so it follows the clock for LogAppendTime
+// topics.
+//
+// By default the cluster clock is the wall clock. Clock plugs in a different
+// clock, and AdvanceTime moves the cluster clock forward. Timeouts still use
+// real timers, but a timer only fires what has timed out by the cluster
+// clock: with a stopped clock, timeouts only fire in AdvanceTime. Retention
+// is applied when logs are cleaned (see CleanLogs), not when time advances.
+//
+// Things that are not tied to broker time use the wall clock: injected
+// faults and latencies, topic deletion delays, fetch max wait, share group
+// sessions and record locks, and request timestamps.
+
+// Clock sets the function returning the cluster's current time, overriding
+// the default of time.Now. The function is called concurrently. A stopped
+// clock (one that always returns the same time) makes time based behavior
+// deterministic: time only moves with AdvanceTime.
+func Clock(now func() time.Time) Opt {
+	return opt{func(cfg *cfg) { cfg.clock = now }}
+}
+
+// Now returns the cluster clock's current time.
+func (c *Cluster) Now() time.Time {
+	return c.now()
+}
+
+// AdvanceTime moves the cluster clock forward by d, and then immediately
+// times out any group sessions, group rebalances, and transactions whose
+// timeouts have passed and completes due partition reassignments, as their
+// timers would have had the time passed. A non-positive d only checks
+// timeouts.
+func (c *Cluster) AdvanceTime(d time.Duration) {
+	if d > 0 {
+		c.clockOffset.Add(int64(d))
+	}
+	c.admin(func() {
+		now := c.now()
+		for _, g := range c.groups.gs {
+			g.waitControl(g.expireTimeouts)
+		}
+		for _, t := range c.txns {
+			c.expireTxn(t, now)
+		}
+		c.completeDueReassignments(now)
+	})
+}
+
+func (c *Cluster) now() time.Time {
+	return c.cfg.clock().Add(time.Duration(c.clockOffset.Load()))
+}
@@ -0,0 +1,121 @@
+package kfake
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestBrokerClockSkew(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(2))
+	cl := newTestClient(t, c)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := c.SetBrokerClockSkew(2, time.Hour); err == nil {
+		t.Error("SetBrokerClockSkew on a missing node did not fail")
+	}
+	if err := c.SetBrokerClockSkew(1, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	// Each node leads one partition; LogAppendTime batches are stamped
+	// with the leader's clock.
+	logAppend, minute := "LogAppendTime", "60000"
+	for topic, configs := range map[string]map[string]*string{
+		"t": {"message.timestamp.type": &logAppend},
+		"v": {"message.timestamp.before.max.ms": &minute},
+	} {
+		if _, err := newTestAdmin(t, c).CreateTopic(ctx, 2, 1, configs, topic); err != nil {
+			t.Fatal(err)
+		}
+		for p := int32(0); p < 2; p++ {
+			if err := c.MoveTopicPartition(topic, p, p); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	start := time.Now()
+	for p := int32(0); p < 2; p++ {
+		if sp := produceBatch(ctx, t, cl.Broker(int(p)), "t", p, kgo.StringRecord("v")); sp.ErrorCode != 0 {
+			t.Fatalf("produce to partition %d: %v", p, kerr.ErrorForCode(sp.ErrorCode))
+		}
+	}
+	for p, exp := range []time.Duration{0, time.Hour} {
+		rs, err := c.ReadRecords("t", int32(p), 0, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if skew := rs[0].Timestamp.Sub(start); skew < exp-time.Minute || skew > exp+time.Minute {
+			t.Errorf("partition %d: record was stamped %v from now, expected about %v", p, skew, exp)
+		}
+	}
+
+	// Produced timestamps are validated against the leader's clock.
+	if sp := produceBatch(ctx, t, cl.Broker(0), "v", 0, kgo.StringRecord("v")); sp.ErrorCode != 0 {
+		t.Errorf("produce to the unskewed broker: %v", kerr.ErrorForCode(sp.ErrorCode))
+	}
+	if sp := produceBatch(ctx, t, cl.Broker(1), "v", 1, kgo.StringRecord("v")); sp.ErrorCode != kerr.InvalidTimestamp.Code {
+		t.Errorf("produce to the skewed broker: got %v, expected INVALID_TIMESTAMP", kerr.ErrorForCode(sp.ErrorCode))
+	}
+}
+
+func TestClockAdvanceTime(t *testing.T) {
+	start := time.Now().Add(-24 * time.Hour).Truncate(time.Millisecond)
+	c := newTestCluster(t, NumBrokers(1), SeedTopics(1, "t"), Clock(func() time.Time { return start }))
+	br := newTestClient(t, c).Broker(0)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Records are stamped with the cluster clock.
+	r := kgo.StringRecord("v")
+	if _, err := c.ProduceTo("t", 0, r); err != nil {
+		t.Fatal(err)
+	}
+	if !r.Timestamp.Equal(start) {
+		t.Errorf("got record timestamp %v, expected the cluster clock %v", r.Timestamp, start)
+	}
+	c.AdvanceTime(time.Minute)
+	r = kgo.StringRecord("v")
+	if _, err := c.ProduceTo("t", 0, r); err != nil {
+		t.Fatal(err)
+	}
+	if exp := start.Add(time.Minute); !r.Timestamp.Equal(exp) {
+		t.Errorf("got record timestamp %v after advancing, expected %v", r.Timestamp, exp)
+	}
+
+	// Group sessions (10s in joinGroup) time out only as the cluster
+	// clock advances.
+	member, generation := joinGroup(ctx, t, br, "g")
+	sync := kmsg.NewPtrSyncGroupRequest()
+	sync.Group, sync.Generation, sync.MemberID = "g", generation, member
+	if resp, err := sync.RequestWith(ctx, br); err != nil || resp.ErrorCode != 0 {
+		t.Fatalf("sync: %v %v", err, resp)
+	}
+	heartbeat := func() int16 {
+		t.Helper()
+		req := kmsg.NewPtrHeartbeatRequest()
+		req.Group, req.Generation, req.MemberID = "g", generation, member
+		resp, err := req.RequestWith(ctx, br)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.ErrorCode
+	}
+	c.AdvanceTime(9 * time.Second)
+	if code := heartbeat(); code != 0 {
+		t.Fatalf("heartbeat within the session timeout: %v", kerr.ErrorForCode(code))
+	}
+	c.AdvanceTime(9 * time.Second) // the heartbeat extended the session
+	if code := heartbeat(); code != 0 {
+		t.Fatalf("heartbeat within the extended session timeout: %v", kerr.ErrorForCode(code))
+	}
+	c.AdvanceTime(10*time.Second + time.Millisecond)
+	if code := heartbeat(); code != kerr.UnknownMemberID.Code {
+		t.Errorf("heartbeat after the session timeout: got %v, expected UNKNOWN_MEMBER_ID", kerr.ErrorForCode(code))
+	}
+}
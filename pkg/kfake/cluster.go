@@ -1,11 +1,13 @@
 package kfake
 
 import (
+	"context"
 	"crypto/tls"
 	"errors"
 	"fmt"
 	"math/rand"
 	"net"
+	"path"
 	"strconv"
 	"sync"
 	"sync/atomic"
@@ -16,9 +18,11 @@ import (
 
 // TODO
 //
-// * Add raft and make the brokers independent
+// * Make the brokers independent -- brokers share state; the controller
+//   quorum and metadata propagation are simulated, see quorum.go and
+//   metadata_delay.go
 //
-// * Support multiple replicas -- we just pass this through
+// * Replicate data -- replicas and the ISR are simulated, see replicas.go
 
 type (
 
@@ -27,37 +31,109 @@ type (
 		cfg cfg
 
 		controller *broker
-		bs         []*broker
 
+		// After MoveController, metadata advertises the old controller
+		// until staleControllerUntil.
+		staleController      *broker
+		staleControllerUntil time.Time
+		bs                   []*broker
+
+		clockOffset    atomic.Int64 // nanoseconds added to cfg.clock by AdvanceTime
 		coordinatorGen atomic.Uint64
+		coordFaultsMu  sync.Mutex
+		coordFaults    map[string]coordFault
+		corrFaultsMu   sync.Mutex
+		corrFaults     map[int16]int
+		faultsMu       sync.Mutex
+		latencyFaults  map[int16]map[int32]time.Duration
+		errFaults      map[int16]map[int32]errFault
+		recordMu       sync.Mutex
+		recorders      []*requestRecorder
 
 		adminCh      chan func()
 		reqCh        chan *clientReq
 		wakeCh       chan *slept
 		watchFetchCh chan *watchFetch
+		parked       map[*watchFetch]struct{}
 
 		controlMu      sync.Mutex
 		control        map[int16]map[*controlCtx]struct{}
 		currentBroker  *broker
 		currentControl *controlCtx
+		handlers       map[int16]func(kmsg.Request) (kmsg.Response, error)
 		sleeping       map[*clientConn]*bsleep
+		deferred       []deferredReq // requests waiting for a running control function, owned by run
 		controlSleep   chan sleepChs
 
-		data   data
-		pids   pids
-		groups groups
-		sasls  sasls
-		bcfgs  map[string]*string
+		data          data
+		pids          pids
+		txns          txns
+		groups        groups
+		shareGroups   shareGroups
+		quorum        quorum
+		telemetry     telemetry
+		eos           eos
+		fetchFaults   tps[fetchFaults]
+		produceFaults tps[produceFaults]
+		versionFaults map[int16]int16
+		mdHistory     []mdSnapshot
+		mdGen         uint64 // bumped by metadataChanged
+		sasls         sasls
+		quotas        quotas
+		aclsMu        sync.RWMutex
+		acls          acls
+		superusers    map[string]struct{} // principals that bypass ACLs
+		bcfgs         map[string]*string
+
+		apiVersionsFault *apiVersionsFault
+		readOnly         int16
+		topicLatencies   map[string]LatencyProfile
+		fetchBufs        sync.Pool // *[]byte record batch buffers for reuse with HighThroughput
+		staleLeaders     tps[staleLeader]
 
 		die  chan struct{}
 		dead atomic.Bool
+
+		draining  chan struct{}
+		drainOnce sync.Once
+		conns     sync.WaitGroup // one per connection, done when its writer exits
+
+		liveMu sync.Mutex
+		live   map[*clientConn]struct{}
+
+		stats clusterStats
+
+		watchMu  sync.Mutex
+		watchers []*Watcher
+
+		offsetsMu   sync.Mutex
+		offsetsLog  []offsetsRecord
+		offsetsWake chan struct{}
+
+		held map[int]*broker // port => removed node holding its listener
 	}
 
 	broker struct {
-		c     *Cluster
-		ln    net.Listener
-		node  int32
-		bsIdx int
+		c       *Cluster
+		ln      net.Listener
+		lns     []net.Listener // additional listeners, in order of cfg.listeners
+		node    int32
+		bsIdx   int
+		rack    *string
+		skew    time.Duration // added to the cluster clock for this broker's clock
+		mdDelay time.Duration // how long this broker serves stale topic metadata, if mdDelaySet
+		held    atomic.Bool   // if true, the node was removed but its listener is held, and conns are immediately closed
+
+		mdDelaySet bool // if false, the broker uses the cluster's MetadataPropagationDelay
+
+		pauseMu   sync.Mutex
+		paused    chan struct{} // non-nil while paused, closed when the pause changes
+		pauseHang bool          // if true, the paused broker keeps connections open but does not read requests
+
+		connLimit connLimiter
+
+		fetchSessions map[int32]*fetchSession // by session ID
+		quotaBuckets  map[quotaBucketKey]*quotaBucket
 	}
 
 	controlFn func(kmsg.Request) (kmsg.Response, error, bool)
@@ -90,13 +166,21 @@ func MustCluster(opts ...Opt) *Cluster {
 func NewCluster(opts ...Opt) (*Cluster, error) {
 	cfg := cfg{
 		nbrokers:        3,
+		listenHost:      "127.0.0.1",
 		logger:          new(nopLogger),
 		clusterID:       "kfake",
 		defaultNumParts: 10,
 
+		fetchSessionSlots: 1000,
+
+		clock: time.Now,
+
 		minSessionTimeout: 6 * time.Second,
 		maxSessionTimeout: 5 * time.Minute,
 
+		consumerHeartbeatInterval: 5 * time.Second,
+		consumerSessionTimeout:    45 * time.Second,
+
 		sasls: make(map[struct{ m, u string }]string),
 	}
 	for _, opt := range opts {
@@ -105,6 +189,31 @@ func NewCluster(opts ...Opt) (*Cluster, error) {
 	if len(cfg.ports) > 0 {
 		cfg.nbrokers = len(cfg.ports)
 	}
+	if len(cfg.nodeIDs) > 0 {
+		if len(cfg.ports) > 0 && len(cfg.ports) != len(cfg.nodeIDs) {
+			return nil, fmt.Errorf("mismatched number of ports %d and node IDs %d", len(cfg.ports), len(cfg.nodeIDs))
+		}
+		seen := make(map[int32]bool)
+		for _, id := range cfg.nodeIDs {
+			if id < 0 || seen[id] {
+				return nil, fmt.Errorf("invalid negative or duplicate node ID %d", id)
+			}
+			seen[id] = true
+		}
+		cfg.nbrokers = len(cfg.nodeIDs)
+	}
+	seenListeners := make(map[string]bool)
+	for _, l := range cfg.listeners {
+		if l.name == "" || seenListeners[l.name] {
+			return nil, fmt.Errorf("invalid empty or duplicate listener name %q", l.name)
+		}
+		seenListeners[l.name] = true
+	}
+	for _, p := range cfg.topicPolicies {
+		if _, err := path.Match(p.pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid topic policy pattern %q: %v", p.pattern, err)
+		}
+	}
 
 	c := &Cluster{
 		cfg: cfg,
@@ -115,6 +224,7 @@ func NewCluster(opts ...Opt) (*Cluster, error) {
 		watchFetchCh: make(chan *watchFetch, 20),
 		control:      make(map[int16]map[*controlCtx]struct{}),
 		controlSleep: make(chan sleepChs, 1),
+		offsetsWake:  make(chan struct{}, 1),
 
 		sleeping: make(map[*clientConn]*bsleep),
 
@@ -126,10 +236,14 @@ func NewCluster(opts ...Opt) (*Cluster, error) {
 		},
 		bcfgs: make(map[string]*string),
 
-		die: make(chan struct{}),
+		die:      make(chan struct{}),
+		draining: make(chan struct{}),
 	}
 	c.data.c = c
 	c.groups.c = c
+	c.shareGroups.c = c
+	c.quorum.c = c
+	c.telemetry.c = c
 	var err error
 	defer func() {
 		if err != nil {
@@ -137,33 +251,24 @@ func NewCluster(opts ...Opt) (*Cluster, error) {
 		}
 	}()
 
+	c.superusers = make(map[string]struct{})
 	for mu, p := range cfg.sasls {
-		switch mu.m {
-		case saslPlain:
-			if c.sasls.plain == nil {
-				c.sasls.plain = make(map[string]string)
-			}
-			c.sasls.plain[mu.u] = p
-		case saslScram256:
-			if c.sasls.scram256 == nil {
-				c.sasls.scram256 = make(map[string]scramAuth)
-			}
-			c.sasls.scram256[mu.u] = newScramAuth(saslScram256, p)
-		case saslScram512:
-			if c.sasls.scram512 == nil {
-				c.sasls.scram512 = make(map[string]scramAuth)
-			}
-			c.sasls.scram512[mu.u] = newScramAuth(saslScram512, p)
-		default:
-			return nil, fmt.Errorf("unknown SASL mechanism %v", mu.m)
+		if err := c.sasls.set(mu.m, mu.u, p); err != nil {
+			return nil, err
 		}
+		c.superusers["User:"+mu.u] = struct{}{}
 	}
 	cfg.sasls = nil
 
-	if cfg.enableSASL && c.sasls.empty() {
+	anySASL := cfg.enableSASL
+	for _, l := range cfg.listeners {
+		anySASL = anySASL || l.sasl
+	}
+	if anySASL && c.sasls.empty() {
 		c.sasls.scram256 = map[string]scramAuth{
 			"admin": newScramAuth(saslScram256, "admin"),
 		}
+		c.superusers["User:admin"] = struct{}{}
 	}
 
 	for i := 0; i < cfg.nbrokers; i++ {
@@ -172,27 +277,45 @@ func NewCluster(opts ...Opt) (*Cluster, error) {
 			port = cfg.ports[i]
 		}
 		var ln net.Listener
-		ln, err = newListener(port, c.cfg.tls)
+		ln, err = c.newListener(port, c.cfg.tls)
 		if err != nil {
 			return nil, err
 		}
+		var lns []net.Listener
+		if lns, err = c.newAdditionalListeners(nil); err != nil {
+			ln.Close()
+			return nil, err
+		}
+		node := int32(i)
+		if len(cfg.nodeIDs) > 0 {
+			node = cfg.nodeIDs[i]
+		}
 		b := &broker{
 			c:     c,
 			ln:    ln,
-			node:  int32(i),
+			lns:   lns,
+			node:  node,
 			bsIdx: len(c.bs),
+			rack:  c.rackFor(len(c.bs)),
 		}
 		c.bs = append(c.bs, b)
-		go b.listen()
+		b.listenAll()
 	}
 	c.controller = c.bs[len(c.bs)-1]
+	c.quorum.init()
 	go c.run()
+	if cfg.pruneInterval > 0 {
+		go c.pruneLoop(cfg.pruneInterval)
+	}
+	if cfg.cleanInterval > 0 {
+		go c.cleanLoop(cfg.cleanInterval)
+	}
 
 	seedTopics := make(map[string]int32)
 	for _, sts := range cfg.seedTopics {
 		p := sts.p
 		if p < 1 {
-			p = int32(cfg.defaultNumParts)
+			p = -1 // mkt applies any topic policy, then the default
 		}
 		for _, t := range sts.ts {
 			seedTopics[t] = p
@@ -201,15 +324,39 @@ func NewCluster(opts ...Opt) (*Cluster, error) {
 	for t, p := range seedTopics {
 		c.data.mkt(t, int(p), -1, nil)
 	}
+	if cfg.offsetsPartitions > 0 {
+		c.data.mkt(offsetsTopic, cfg.offsetsPartitions, -1, map[string]*string{
+			"cleanup.policy": kmsg.StringPtr("compact"),
+		})
+	}
 	return c, nil
 }
 
-// ListenAddrs returns the hostports that the cluster is listening on.
+// ListenAddrs returns the hostports that the cluster is listening on, using
+// the AdvertisedHost if set.
 func (c *Cluster) ListenAddrs() []string {
 	var addrs []string
 	c.admin(func() {
 		for _, b := range c.bs {
-			addrs = append(addrs, b.ln.Addr().String())
+			h, p := b.hostport("")
+			addrs = append(addrs, net.JoinHostPort(h, strconv.Itoa(int(p))))
+		}
+	})
+	return addrs
+}
+
+// ListenerAddrs returns the hostports that the cluster is listening on for
+// the named Listener. This returns nil if the listener does not exist.
+func (c *Cluster) ListenerAddrs(name string) []string {
+	var addrs []string
+	c.admin(func() {
+		for i, l := range c.cfg.listeners {
+			if l.name != name {
+				continue
+			}
+			for _, b := range c.bs {
+				addrs = append(addrs, b.lns[i].Addr().String())
+			}
 		}
 	})
 	return addrs
@@ -222,12 +369,46 @@ func (c *Cluster) Close() {
 	}
 	close(c.die)
 	for _, b := range c.bs {
-		b.ln.Close()
+		b.closeListeners()
+	}
+	for _, b := range c.held {
+		b.closeListeners()
 	}
 }
 
-func newListener(port int, tc *tls.Config) (net.Listener, error) {
-	l, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+// Shutdown gracefully shuts down the cluster. The cluster stops accepting new
+// connections and stops reading new requests on existing connections, waits
+// for every request already read to be replied to and for all replies to be
+// written, and then closes the cluster. Connections are closed once they are
+// drained. If the context is canceled before everything is drained, the
+// cluster is closed immediately and the context's error is returned.
+func (c *Cluster) Shutdown(ctx context.Context) error {
+	if c.dead.Load() {
+		return nil
+	}
+	c.drainOnce.Do(func() {
+		close(c.draining)
+		for _, b := range c.bs {
+			b.closeListeners()
+		}
+	})
+	drained := make(chan struct{})
+	go func() {
+		c.conns.Wait()
+		close(drained)
+	}()
+	var err error
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	c.Close()
+	return err
+}
+
+func (c *Cluster) newListener(port int, tc *tls.Config) (net.Listener, error) {
+	l, err := net.Listen("tcp", net.JoinHostPort(c.cfg.listenHost, strconv.Itoa(port)))
 	if err != nil {
 		return nil, err
 	}
@@ -237,104 +418,248 @@ func newListener(port int, tc *tls.Config) (net.Listener, error) {
 	return l, nil
 }
 
-func (b *broker) listen() {
-	defer b.ln.Close()
+// newAdditionalListeners opens a listener for every configured Listener,
+// using the given ports if non-nil.
+func (c *Cluster) newAdditionalListeners(ports []int) ([]net.Listener, error) {
+	var lns []net.Listener
+	for i, l := range c.cfg.listeners {
+		var port int
+		if ports != nil {
+			port = ports[i]
+		}
+		ln, err := c.newListener(port, l.tls)
+		if err != nil {
+			for _, ln := range lns {
+				ln.Close()
+			}
+			return nil, err
+		}
+		lns = append(lns, ln)
+	}
+	return lns, nil
+}
+
+func (b *broker) listenAll() {
+	go b.listen(b.ln, listener{sasl: b.c.cfg.enableSASL})
+	for i, ln := range b.lns {
+		go b.listen(ln, b.c.cfg.listeners[i])
+	}
+}
+
+func (b *broker) closeListeners() {
+	b.ln.Close()
+	for _, ln := range b.lns {
+		ln.Close()
+	}
+}
+
+// hostport returns the host and port of the named listener, or of the primary
+// listener if the name is empty.
+func (b *broker) hostport(listener string) (string, int32) {
+	ln := b.ln
+	for i, l := range b.c.cfg.listeners {
+		if l.name == listener {
+			ln = b.lns[i]
+		}
+	}
+	h, p, _ := net.SplitHostPort(ln.Addr().String())
+	p32, _ := strconv.Atoi(p)
+	if adv := b.c.cfg.advertisedHost; adv != "" {
+		h = adv
+	}
+	return h, int32(p32)
+}
+
+func lnPort(ln net.Listener) int {
+	_, p, _ := net.SplitHostPort(ln.Addr().String())
+	port, _ := strconv.Atoi(p)
+	return port
+}
+
+func (b *broker) listen(ln net.Listener, l listener) {
+	defer ln.Close()
 	for {
-		conn, err := b.ln.Accept()
+		conn, err := ln.Accept()
 		if err != nil {
 			return
 		}
-
-		cc := &clientConn{
-			c:      b.c,
-			b:      b,
-			conn:   conn,
-			respCh: make(chan clientResp, 2),
+		if b.held.Load() {
+			conn.Close()
+			continue
+		}
+		if paused, hang := b.pauseState(); paused != nil && !hang {
+			conn.Close()
+			continue
 		}
-		go cc.read()
-		go cc.write()
+		wait, ok := b.admit(conn.RemoteAddr())
+		if !ok {
+			b.c.cfg.logger.Logf(LogLevelDebug, "rejecting connection from %s: connection rate limit exceeded", conn.RemoteAddr())
+			conn.Close()
+			continue
+		}
+		if wait == 0 {
+			b.serve(conn, l)
+			continue
+		}
+		b.c.cfg.logger.Logf(LogLevelDebug, "delaying connection from %s by %v: connection rate limit exceeded", conn.RemoteAddr(), wait)
+		go func() {
+			t := time.NewTimer(wait)
+			defer t.Stop()
+			select {
+			case <-t.C:
+				b.serve(conn, l)
+			case <-b.c.die:
+				conn.Close()
+			}
+		}()
+	}
+}
+
+func (b *broker) serve(conn net.Conn, l listener) {
+	cc := &clientConn{
+		c:        b.c,
+		b:        b,
+		conn:     conn,
+		respCh:   make(chan clientResp, 2),
+		listener: l.name,
+		sasl:     l.sasl,
+		readDone: make(chan struct{}),
+
+		node:        b.node,
+		connectedAt: time.Now(),
+	}
+	b.c.liveMu.Lock()
+	if max := b.c.cfg.limits.Connections; max > 0 && len(b.c.live) >= max {
+		b.c.liveMu.Unlock()
+		b.c.cfg.logger.Logf(LogLevelDebug, "rejecting connection from %s: connection limit of %d reached", conn.RemoteAddr(), max)
+		conn.Close()
+		return
+	}
+	b.c.conns.Add(1)
+	if b.c.live == nil {
+		b.c.live = make(map[*clientConn]struct{})
+	}
+	b.c.live[cc] = struct{}{}
+	b.c.liveMu.Unlock()
+	b.c.stats.connected(cc.node)
+	if fn := b.c.cfg.onConnect; fn != nil {
+		fn(cc.info())
 	}
+	go cc.read()
+	go cc.write()
 }
 
+// run is the cluster's request loop. All cluster state is owned by this
+// goroutine, so requests, admin functions, and control functions are handled
+// one at a time. Requests that can block for a long time are parked off of
+// the loop so that other connections continue to be served:
+//
+//   - Fetch and ShareFetch requests that are waiting for data are parked
+//     until data arrives or their max wait expires (see watchFetch).
+//
+//   - Group requests are handed to the group's own goroutine, so a JoinGroup
+//     waiting for a rebalance only blocks its group.
+//
+//   - Control functions that call SleepControl are parked until woken, and
+//     only later requests on the same connection wait for them.
+//
+//   - Delayed responses (replication, topic, and injected latency) are
+//     written after the delay without holding the loop.
+//
+//   - While a control function runs, the loop keeps serving requests that
+//     no control function or handler could intercept. Requests that could
+//     be intercepted, and later requests on the running request's
+//     connection, are deferred until the control function returns or
+//     sleeps (see serveDuringControl).
 func (c *Cluster) run() {
 outer:
 	for {
+		c.recordMetadata()
+
 		var (
 			creq    *clientReq
 			w       *watchFetch
 			s       *slept
-			kreq    kmsg.Request
 			kresp   kmsg.Response
 			err     error
 			handled bool
 		)
 
-		select {
-		case <-c.die:
-			return
+		// Requests deferred while a control function was running are
+		// handled before any new request, in the order they arrived.
+		if len(c.deferred) > 0 {
+			d := c.deferred[0]
+			c.deferred = c.deferred[1:]
+			if creq, w = d.creq, d.w; !c.admit(creq, w) {
+				continue
+			}
+		} else {
+			select {
+			case <-c.die:
+				return
 
-		case admin := <-c.adminCh:
-			admin()
-			continue
+			case admin := <-c.adminCh:
+				admin()
+				continue
 
-		case creq = <-c.reqCh:
-			if c.cfg.sleepOutOfOrder {
-				break
-			}
-			// If we have any sleeping request on this node,
-			// we enqueue the new live request to the end and
-			// wait for the sleeping request to finish.
-			bs := c.sleeping[creq.cc]
-			if bs.enqueue(&slept{
-				creq:    creq,
-				waiting: true,
-			}) {
+			case <-c.offsetsWake:
+				c.flushOffsets()
 				continue
-			}
 
-		case s = <-c.wakeCh:
-			// On wakeup, we know we are handling a control
-			// function that was slept, or a request that was
-			// waiting for a control function to finish sleeping.
-			creq = s.creq
-			if s.waiting {
-				break
-			}
+			case creq = <-c.reqCh:
+				if !c.admit(creq, nil) {
+					continue
+				}
 
-			// We continue a previously sleeping request, and
-			// handle results similar to tryControl.
-			//
-			// Control flow is weird here, but is described more
-			// fully in the finish/resleep/etc methods.
-			c.continueSleptControl(s)
-		inner:
-			for {
-				select {
-				case admin := <-c.adminCh:
-					admin()
-					continue inner
-				case res := <-s.res:
-					c.finishSleptControl(s)
-					cctx := s.cctx
-					s = nil
-					kresp, err, handled = res.kresp, res.err, res.handled
-					c.maybePopControl(handled, cctx)
-					if handled {
-						goto afterControl
+			case s = <-c.wakeCh:
+				// On wakeup, we know we are handling a control
+				// function that was slept, or a request that was
+				// waiting for a control function to finish sleeping.
+				creq = s.creq
+				if s.waiting {
+					break
+				}
+
+				// We continue a previously sleeping request, and
+				// handle results similar to tryControl.
+				//
+				// Control flow is weird here, but is described more
+				// fully in the finish/resleep/etc methods.
+				c.continueSleptControl(s)
+			inner:
+				for {
+					select {
+					case admin := <-c.adminCh:
+						admin()
+						continue inner
+					case <-c.offsetsWake:
+						c.flushOffsets()
+						continue inner
+					case next := <-c.reqCh:
+						c.serveDuringControl(creq, next, nil)
+						continue inner
+					case next := <-c.watchFetchCh:
+						c.serveDuringControl(creq, next.creq, next)
+						continue inner
+					case res := <-s.res:
+						kresp, err, handled = res.kresp, res.err, res.handled
+						c.finishSleptControl(s, handled)
+						s = nil
+						if handled {
+							goto afterControl
+						}
+						break inner
+					case sleepChs := <-c.controlSleep:
+						c.resleepSleptControl(s, sleepChs)
+						continue outer
 					}
-					break inner
-				case sleepChs := <-c.controlSleep:
-					c.resleepSleptControl(s, sleepChs)
-					continue outer
 				}
-			}
 
-		case w = <-c.watchFetchCh:
-			if w.cleaned {
-				continue // already cleaned up, this is an extraneous timer fire
+			case w = <-c.watchFetchCh:
+				if creq = w.creq; !c.admit(creq, w) {
+					continue
+				}
 			}
-			w.cleanup(c)
-			creq = w.creq
 		}
 
 		kresp, err, handled = c.tryControl(creq)
@@ -342,80 +667,7 @@ outer:
 			goto afterControl
 		}
 
-		if c.cfg.enableSASL {
-			if allow := c.handleSASL(creq); !allow {
-				err = errors.New("not allowed given SASL state")
-				goto afterControl
-			}
-		}
-
-		kreq = creq.kreq
-		switch k := kmsg.Key(kreq.Key()); k {
-		case kmsg.Produce:
-			kresp, err = c.handleProduce(creq.cc.b, kreq)
-		case kmsg.Fetch:
-			kresp, err = c.handleFetch(creq, w)
-		case kmsg.ListOffsets:
-			kresp, err = c.handleListOffsets(creq.cc.b, kreq)
-		case kmsg.Metadata:
-			kresp, err = c.handleMetadata(kreq)
-		case kmsg.OffsetCommit:
-			kresp, err = c.handleOffsetCommit(creq)
-		case kmsg.OffsetFetch:
-			kresp, err = c.handleOffsetFetch(creq)
-		case kmsg.FindCoordinator:
-			kresp, err = c.handleFindCoordinator(kreq)
-		case kmsg.JoinGroup:
-			kresp, err = c.handleJoinGroup(creq)
-		case kmsg.Heartbeat:
-			kresp, err = c.handleHeartbeat(creq)
-		case kmsg.LeaveGroup:
-			kresp, err = c.handleLeaveGroup(creq)
-		case kmsg.SyncGroup:
-			kresp, err = c.handleSyncGroup(creq)
-		case kmsg.DescribeGroups:
-			kresp, err = c.handleDescribeGroups(creq)
-		case kmsg.ListGroups:
-			kresp, err = c.handleListGroups(creq)
-		case kmsg.SASLHandshake:
-			kresp, err = c.handleSASLHandshake(creq)
-		case kmsg.ApiVersions:
-			kresp, err = c.handleApiVersions(kreq)
-		case kmsg.CreateTopics:
-			kresp, err = c.handleCreateTopics(creq.cc.b, kreq)
-		case kmsg.DeleteTopics:
-			kresp, err = c.handleDeleteTopics(creq.cc.b, kreq)
-		case kmsg.DeleteRecords:
-			kresp, err = c.handleDeleteRecords(creq.cc.b, kreq)
-		case kmsg.InitProducerID:
-			kresp, err = c.handleInitProducerID(kreq)
-		case kmsg.OffsetForLeaderEpoch:
-			kresp, err = c.handleOffsetForLeaderEpoch(creq.cc.b, kreq)
-		case kmsg.DescribeConfigs:
-			kresp, err = c.handleDescribeConfigs(creq.cc.b, kreq)
-		case kmsg.AlterConfigs:
-			kresp, err = c.handleAlterConfigs(creq.cc.b, kreq)
-		case kmsg.AlterReplicaLogDirs:
-			kresp, err = c.handleAlterReplicaLogDirs(creq.cc.b, kreq)
-		case kmsg.DescribeLogDirs:
-			kresp, err = c.handleDescribeLogDirs(creq.cc.b, kreq)
-		case kmsg.SASLAuthenticate:
-			kresp, err = c.handleSASLAuthenticate(creq)
-		case kmsg.CreatePartitions:
-			kresp, err = c.handleCreatePartitions(creq.cc.b, kreq)
-		case kmsg.DeleteGroups:
-			kresp, err = c.handleDeleteGroups(creq)
-		case kmsg.IncrementalAlterConfigs:
-			kresp, err = c.handleIncrementalAlterConfigs(creq.cc.b, kreq)
-		case kmsg.OffsetDelete:
-			kresp, err = c.handleOffsetDelete(creq)
-		case kmsg.DescribeUserSCRAMCredentials:
-			kresp, err = c.handleDescribeUserSCRAMCredentials(kreq)
-		case kmsg.AlterUserSCRAMCredentials:
-			kresp, err = c.handleAlterUserSCRAMCredentials(creq.cc.b, kreq)
-		default:
-			err = fmt.Errorf("unhandled key %v", k)
-		}
+		kresp, err = c.handleReq(creq, w)
 
 	afterControl:
 		// If s is non-nil, this is either a previously slept control
@@ -425,16 +677,197 @@ outer:
 		if s != nil {
 			s.continueDequeue <- struct{}{}
 		}
-		if kresp == nil && err == nil { // produce request with no acks, or otherwise hijacked request (group, sleep)
-			continue
+		if !c.respond(creq, kresp, err) {
+			return
 		}
+	}
+}
 
-		select {
-		case creq.cc.respCh <- clientResp{kresp: kresp, corr: creq.corr, err: err, seq: creq.seq}:
-		case <-c.die:
-			return
+// handleReq handles a request that was not controlled. A nil response and
+// error means the request is replied to later (or never, for acks=0
+// produce requests).
+func (c *Cluster) handleReq(creq *clientReq, w *watchFetch) (kresp kmsg.Response, err error) {
+	if creq.cc.sasl {
+		if allow := c.handleSASL(creq); !allow {
+			return nil, errors.New("not allowed given SASL state")
 		}
 	}
+
+	if err := c.rejectLegacyVersion(creq.kreq); err != nil {
+		return nil, err
+	}
+	if err := c.rejectAboveMaxVersion(creq.kreq); err != nil {
+		return nil, err
+	}
+	if kresp, err, handled := c.rejectVersion(creq.kreq); handled {
+		return kresp, err
+	}
+	if kresp, handled := c.rejectWrite(creq.kreq); handled {
+		return kresp, nil
+	}
+	if kresp, err, handled := c.injectError(creq); handled {
+		return kresp, err
+	}
+	if kresp, err, handled := c.tryHandler(creq); handled {
+		return kresp, err
+	}
+
+	kreq := creq.kreq
+	switch k := kmsg.Key(kreq.Key()); k {
+	case kmsg.Produce:
+		kresp, err = c.handleProduce(creq)
+		d := c.replicationDelay(kreq, kresp)
+		if pd := c.topicDelay(kresp); pd > d {
+			d = pd
+		}
+		if td := c.throttleProduce(creq, kresp); td > d {
+			d = td
+		}
+		if d > 0 && err == nil {
+			c.replyAfter(creq, kresp, d)
+			kresp = nil
+		}
+	case kmsg.Fetch:
+		kresp, err = c.handleFetch(creq, w)
+		d := c.topicDelay(kresp)
+		if td := c.throttleFetch(creq, kresp); td > d {
+			d = td
+		}
+		if d > 0 && err == nil {
+			c.replyAfter(creq, kresp, d)
+			kresp = nil
+		}
+	case kmsg.ListOffsets:
+		kresp, err = c.handleListOffsets(creq)
+	case kmsg.Metadata:
+		kresp, err = c.handleMetadata(creq)
+	case kmsg.OffsetCommit:
+		kresp, err = c.handleOffsetCommit(creq)
+	case kmsg.OffsetFetch:
+		kresp, err = c.handleOffsetFetch(creq)
+	case kmsg.FindCoordinator:
+		kresp, err = c.handleFindCoordinator(creq)
+	case kmsg.JoinGroup:
+		kresp, err = c.handleJoinGroup(creq)
+	case kmsg.Heartbeat:
+		kresp, err = c.handleHeartbeat(creq)
+	case kmsg.LeaveGroup:
+		kresp, err = c.handleLeaveGroup(creq)
+	case kmsg.SyncGroup:
+		kresp, err = c.handleSyncGroup(creq)
+	case kmsg.DescribeGroups:
+		kresp, err = c.handleDescribeGroups(creq)
+	case kmsg.ListGroups:
+		kresp, err = c.handleListGroups(creq)
+	case kmsg.SASLHandshake:
+		kresp, err = c.handleSASLHandshake(creq)
+	case kmsg.ApiVersions:
+		var handled bool
+		if kresp, err, handled = c.failApiVersions(creq); !handled {
+			kresp, err = c.handleApiVersions(kreq)
+		}
+	case kmsg.CreateTopics:
+		kresp, err = c.handleCreateTopics(creq)
+	case kmsg.DeleteTopics:
+		kresp, err = c.handleDeleteTopics(creq)
+	case kmsg.DeleteRecords:
+		kresp, err = c.handleDeleteRecords(creq)
+	case kmsg.InitProducerID:
+		kresp, err = c.handleInitProducerID(creq)
+	case kmsg.OffsetForLeaderEpoch:
+		kresp, err = c.handleOffsetForLeaderEpoch(creq.cc.b, kreq)
+	case kmsg.AddPartitionsToTxn:
+		kresp, err = c.handleAddPartitionsToTxn(creq)
+	case kmsg.AddOffsetsToTxn:
+		kresp, err = c.handleAddOffsetsToTxn(creq)
+	case kmsg.EndTxn:
+		kresp, err = c.handleEndTxn(creq)
+	case kmsg.TxnOffsetCommit:
+		kresp, err = c.handleTxnOffsetCommit(creq)
+	case kmsg.DescribeACLs:
+		kresp, err = c.handleDescribeACLs(creq)
+	case kmsg.CreateACLs:
+		kresp, err = c.handleCreateACLs(creq)
+	case kmsg.DeleteACLs:
+		kresp, err = c.handleDeleteACLs(creq)
+	case kmsg.DescribeConfigs:
+		kresp, err = c.handleDescribeConfigs(creq)
+	case kmsg.AlterConfigs:
+		kresp, err = c.handleAlterConfigs(creq)
+	case kmsg.AlterReplicaLogDirs:
+		kresp, err = c.handleAlterReplicaLogDirs(creq.cc.b, kreq)
+	case kmsg.DescribeLogDirs:
+		kresp, err = c.handleDescribeLogDirs(creq)
+	case kmsg.SASLAuthenticate:
+		kresp, err = c.handleSASLAuthenticate(creq)
+	case kmsg.CreateDelegationToken:
+		kresp, err = c.handleCreateDelegationToken(creq)
+	case kmsg.CreatePartitions:
+		kresp, err = c.handleCreatePartitions(creq)
+	case kmsg.DeleteGroups:
+		kresp, err = c.handleDeleteGroups(creq)
+	case kmsg.ElectLeaders:
+		kresp, err = c.handleElectLeaders(creq)
+	case kmsg.IncrementalAlterConfigs:
+		kresp, err = c.handleIncrementalAlterConfigs(creq)
+	case kmsg.OffsetDelete:
+		kresp, err = c.handleOffsetDelete(creq)
+	case kmsg.AlterPartitionAssignments:
+		kresp, err = c.handleAlterPartitionAssignments(creq)
+	case kmsg.ListPartitionReassignments:
+		kresp, err = c.handleListPartitionReassignments(creq)
+	case kmsg.DescribeClientQuotas:
+		kresp, err = c.handleDescribeClientQuotas(creq)
+	case kmsg.AlterClientQuotas:
+		kresp, err = c.handleAlterClientQuotas(creq)
+	case kmsg.DescribeUserSCRAMCredentials:
+		kresp, err = c.handleDescribeUserSCRAMCredentials(kreq)
+	case kmsg.AlterUserSCRAMCredentials:
+		kresp, err = c.handleAlterUserSCRAMCredentials(creq.cc.b, kreq)
+	case kmsg.DescribeQuorum:
+		kresp, err = c.handleDescribeQuorum(kreq)
+	case kmsg.DescribeCluster:
+		kresp, err = c.handleDescribeCluster(creq)
+	case kmsg.ConsumerGroupHeartbeat:
+		kresp, err = c.handleConsumerGroupHeartbeat(creq)
+	case kmsg.GetTelemetrySubscriptions:
+		kresp, err = c.handleGetTelemetrySubscriptions(kreq)
+	case kmsg.PushTelemetry:
+		kresp, err = c.handlePushTelemetry(kreq)
+	case kmsg.DescribeTopicPartitions:
+		kresp, err = c.handleDescribeTopicPartitions(kreq)
+	case kmsg.ShareGroupHeartbeat:
+		kresp, err = c.handleShareGroupHeartbeat(creq)
+	case kmsg.ShareGroupDescribe:
+		kresp, err = c.handleShareGroupDescribe(creq)
+	case kmsg.ShareFetch:
+		kresp, err = c.handleShareFetch(creq, w)
+	case kmsg.ShareAcknowledge:
+		kresp, err = c.handleShareAcknowledge(creq)
+	case kmsg.AddRaftVoter:
+		kresp, err = c.handleAddRaftVoter(kreq)
+	case kmsg.RemoveRaftVoter:
+		kresp, err = c.handleRemoveRaftVoter(kreq)
+	case kmsg.UpdateRaftVoter:
+		kresp, err = c.handleUpdateRaftVoter(kreq)
+	default:
+		err = fmt.Errorf("unhandled key %v", k)
+	}
+	return kresp, err
+}
+
+// respond sends the response for a request to its connection's writer; this
+// returns false if the cluster is closing.
+func (c *Cluster) respond(creq *clientReq, kresp kmsg.Response, err error) bool {
+	if kresp == nil && err == nil { // produce request with no acks, or otherwise hijacked request (group, sleep)
+		return true
+	}
+	select {
+	case creq.cc.respCh <- clientResp{kresp: kresp, corr: creq.corr, err: err, seq: creq.seq, fetchBufs: creq.fetchBufs}:
+		return true
+	case <-c.die:
+		return false
+	}
 }
 
 // Control is a function to call on any client request the cluster handles.
@@ -456,7 +889,10 @@ outer:
 //
 // Control functions are run serially unless you use SleepControl, multiple
 // control functions are "in progress", and you run Cluster.Close. Closing a
-// Cluster awakens all sleeping control functions.
+// Cluster awakens all sleeping control functions. While a control function
+// runs, requests that any control function could intercept, and later
+// requests on the same connection, wait for it to return or sleep; other
+// requests continue to be served.
 func (c *Cluster) Control(fn func(kmsg.Request) (kmsg.Response, error, bool)) {
 	c.ControlKey(-1, fn)
 }
@@ -481,7 +917,10 @@ func (c *Cluster) Control(fn func(kmsg.Request) (kmsg.Response, error, bool)) {
 //
 // Control functions are run serially unless you use SleepControl, multiple
 // control functions are "in progress", and you run Cluster.Close. Closing a
-// Cluster awakens all sleeping control functions.
+// Cluster awakens all sleeping control functions. While a control function
+// runs, requests that any control function could intercept, and later
+// requests on the same connection, wait for it to return or sleep; other
+// requests continue to be served.
 func (c *Cluster) ControlKey(key int16, fn func(kmsg.Request) (kmsg.Response, error, bool)) {
 	c.controlMu.Lock()
 	defer c.controlMu.Unlock()
@@ -497,6 +936,61 @@ func (c *Cluster) ControlKey(key int16, fn func(kmsg.Request) (kmsg.Response, er
 	}] = struct{}{}
 }
 
+// ControlAsync is like Control, but the control function can reply to the
+// request later rather than returning the response. See ControlKeyAsync.
+func (c *Cluster) ControlAsync(fn func(kreq kmsg.Request, respond func(kmsg.Response, error)) bool) {
+	c.ControlKeyAsync(-1, fn)
+}
+
+// ControlKeyAsync is like ControlKey, but the control function can reply to
+// the request later rather than returning the response. If fn returns true,
+// the request is handled and its reply is whatever is eventually passed to
+// respond, which can be called from any goroutine; the first call wins. Until
+// respond is called, the request sleeps as with SleepControl: the cluster
+// continues to serve other connections, and later requests on the same
+// connection wait. If fn returns false, the request is not handled and
+// respond must not be called.
+//
+// This can be used to have a broker reply to a request long after receiving
+// it without stalling the cluster, for example:
+//
+//	c.ControlKeyAsync(int16(kmsg.Produce), func(kreq kmsg.Request, respond func(kmsg.Response, error)) bool {
+//		time.AfterFunc(5*time.Second, func() { respond(someResponse, nil) })
+//		return true
+//	})
+//
+// As with ControlKey, KeepControl and DropControl can be called from fn.
+func (c *Cluster) ControlKeyAsync(key int16, fn func(kreq kmsg.Request, respond func(kmsg.Response, error)) bool) {
+	type reply struct {
+		kresp kmsg.Response
+		err   error
+	}
+	c.ControlKey(key, func(kreq kmsg.Request) (kmsg.Response, error, bool) {
+		replyCh := make(chan reply, 1)
+		respond := func(kresp kmsg.Response, err error) {
+			select {
+			case replyCh <- reply{kresp, err}:
+			default:
+			}
+		}
+		if !fn(kreq, respond) {
+			return nil, nil, false
+		}
+		var r reply
+		select {
+		case r = <-replyCh:
+		default:
+			c.SleepControl(func() {
+				select {
+				case r = <-replyCh:
+				case <-c.die:
+				}
+			})
+		}
+		return r.kresp, r.err, true
+	})
+}
+
 // KeepControl marks the currently running control function to be kept even if
 // you handle the request and return true. This can be used to continuously
 // control requests without needing to re-add control functions manually.
@@ -580,6 +1074,74 @@ func (c *Cluster) CurrentNode() int32 {
 	return -1
 }
 
+// RegisterHandler registers a persistent handler for a request key, replacing
+// any previously registered handler for the key. This can be used to
+// implement requests the cluster does not support, or to replace the
+// cluster's implementation of a request. Unlike control functions, handlers
+// are never dropped, and they run after control functions: a control function
+// can still intercept a request that a handler would handle.
+//
+// If the key is not implemented by the cluster, ApiVersions responses
+// advertise the key with versions 0 through the max version kmsg knows of;
+// the handler must validate the request version itself. Handlers are run
+// serially in the cluster's run loop, and thus must not call other Cluster
+// functions besides CurrentNode. Registering a nil handler removes the
+// handler for the key.
+func (c *Cluster) RegisterHandler(key int16, fn func(kmsg.Request) (kmsg.Response, error)) {
+	c.controlMu.Lock()
+	defer c.controlMu.Unlock()
+	if fn == nil {
+		delete(c.handlers, key)
+		return
+	}
+	if c.handlers == nil {
+		c.handlers = make(map[int16]func(kmsg.Request) (kmsg.Response, error))
+	}
+	c.handlers[key] = fn
+}
+
+// tryHandler runs a registered handler for the request, if there is one.
+func (c *Cluster) tryHandler(creq *clientReq) (kmsg.Response, error, bool) {
+	c.controlMu.Lock()
+	fn, ok := c.handlers[creq.kreq.Key()]
+	if !ok {
+		c.controlMu.Unlock()
+		return nil, nil, false
+	}
+	c.currentBroker = creq.cc.b
+	c.controlMu.Unlock()
+
+	kresp, err := fn(creq.kreq)
+
+	c.controlMu.Lock()
+	c.currentBroker = nil
+	c.controlMu.Unlock()
+	return kresp, err, true
+}
+
+// handlerKeys returns the versions to advertise for keys with registered
+// handlers that the cluster does not otherwise implement.
+func (c *Cluster) handlerKeys() []kmsg.ApiVersionsResponseApiKey {
+	c.controlMu.Lock()
+	defer c.controlMu.Unlock()
+	var keys []kmsg.ApiVersionsResponseApiKey
+	for key := range c.handlers {
+		if _, exists := apiVersionsKeys[key]; exists {
+			continue
+		}
+		req := kmsg.RequestForKey(key)
+		if req == nil {
+			continue
+		}
+		keys = append(keys, kmsg.ApiVersionsResponseApiKey{
+			ApiKey:     key,
+			MinVersion: 0,
+			MaxVersion: req.MaxVersion(),
+		})
+	}
+	return keys
+}
+
 func (c *Cluster) tryControl(creq *clientReq) (kresp kmsg.Response, err error, handled bool) {
 	c.controlMu.Lock()
 	defer c.controlMu.Unlock()
@@ -605,7 +1167,21 @@ func (c *Cluster) tryControlKey(key int16, creq *clientReq) (kmsg.Response, erro
 			case admin := <-c.adminCh:
 				admin()
 				continue
+			case <-c.offsetsWake:
+				c.flushOffsets()
+				continue
+			case next := <-c.reqCh:
+				c.serveDuringControl(creq, next, nil)
+				continue
+			case next := <-c.watchFetchCh:
+				c.serveDuringControl(creq, next.creq, next)
+				continue
 			case res := <-res:
+				// The control function returned; we re-lock for
+				// tryControl to unlock.
+				c.controlMu.Lock()
+				c.currentControl = nil
+				c.currentBroker = nil
 				c.maybePopControl(res.handled, cctx)
 				return res.kresp, res.err, res.handled
 			case sleepChs := <-c.controlSleep:
@@ -627,17 +1203,13 @@ func (c *Cluster) runControl(cctx *controlCtx, creq *clientReq) chan controlResp
 	c.currentBroker = creq.cc.b
 	c.currentControl = cctx
 	// We unlock before entering a control function so that the control
-	// function can modify / add more control. We re-lock when exiting the
-	// control function. This does pose some weird control flow issues
-	// w.r.t. sleeping requests. Here, we have to re-lock before sending
-	// down res, otherwise we risk unlocking an unlocked mu in
-	// finishSleepControl.
+	// function can modify / add more control, and so that the run loop
+	// can serve requests the control function cannot affect while it
+	// runs (see serveDuringControl). The run loop re-locks once the
+	// function returns or sleeps.
 	c.controlMu.Unlock()
 	go func() {
 		kresp, err, handled := cctx.fn(creq.kreq)
-		c.controlMu.Lock()
-		c.currentControl = nil
-		c.currentBroker = nil
 		res <- controlResp{kresp, err, handled}
 	}()
 	return res
@@ -673,12 +1245,13 @@ func (c *Cluster) continueSleptControl(s *slept) {
 	s.sleepChs.clientCont <- struct{}{}
 }
 
-func (c *Cluster) finishSleptControl(s *slept) {
-	// When finishing a slept control, the control function exited and
-	// grabbed the control mu. We clear the control, unlock, and allow the
-	// slept control to be dequeued.
+func (c *Cluster) finishSleptControl(s *slept, handled bool) {
+	// When finishing a slept control, the control function exited. We
+	// clear the control and allow the slept control to be dequeued.
+	c.controlMu.Lock()
 	c.currentControl = nil
 	c.currentBroker = nil
+	c.maybePopControl(handled, s.cctx)
 	c.controlMu.Unlock()
 	s.continueDequeue <- struct{}{}
 }
@@ -706,6 +1279,71 @@ func (c *Cluster) maybePopControl(handled bool, cctx *controlCtx) {
 	}
 }
 
+// deferredReq is a request, or a fetch that finished waiting, that arrived
+// while a control function was running and must wait for it.
+type deferredReq struct {
+	creq *clientReq
+	w    *watchFetch
+}
+
+// admit prepares a new request, or a fetch that finished waiting, to be
+// handled. This returns false if the request should not be handled now: the
+// fetch was already handled, or the request is queued behind a sleeping
+// request on its connection.
+func (c *Cluster) admit(creq *clientReq, w *watchFetch) bool {
+	if w != nil {
+		if w.cleaned {
+			return false // already cleaned up, this is an extraneous timer fire
+		}
+		w.cleanup(c)
+		return true
+	}
+	if c.cfg.sleepOutOfOrder {
+		return true
+	}
+	// If we have any sleeping request on this node, we enqueue the new
+	// live request to the end and wait for the sleeping request to
+	// finish.
+	bs := c.sleeping[creq.cc]
+	return !bs.enqueue(&slept{
+		creq:    creq,
+		waiting: true,
+	})
+}
+
+// serveDuringControl handles a request that arrives while the control
+// function for running is in progress. Control functions run serially and
+// requests on a connection are handled in order, so the request is deferred
+// until the control function returns or sleeps if it is on the same
+// connection as running (or a request already deferred), or if a control
+// function or handler could intercept it. Otherwise, the request cannot
+// observe the control function and is handled now.
+func (c *Cluster) serveDuringControl(running, creq *clientReq, w *watchFetch) {
+	deferred := creq.cc == running.cc
+	for _, d := range c.deferred {
+		deferred = deferred || d.creq.cc == creq.cc
+	}
+	if deferred || c.mayControl(creq) {
+		c.deferred = append(c.deferred, deferredReq{creq, w})
+		return
+	}
+	if !c.admit(creq, w) {
+		return
+	}
+	kresp, err := c.handleReq(creq, w)
+	c.respond(creq, kresp, err)
+}
+
+// mayControl returns whether a control function or handler could intercept
+// the request.
+func (c *Cluster) mayControl(creq *clientReq) bool {
+	c.controlMu.Lock()
+	defer c.controlMu.Unlock()
+	key := creq.kreq.Key()
+	_, handler := c.handlers[key]
+	return len(c.control[key]) > 0 || len(c.control[-1]) > 0 || handler
+}
+
 // bsleep manages sleeping requests on a connection to a broker, or
 // non-sleeping requests that are waiting for sleeping requests to finish.
 type bsleep struct {
@@ -936,6 +1574,19 @@ func (c *Cluster) admin(fn func()) {
 	<-wait
 }
 
+// tryAdmin is like admin, but returns false rather than blocking forever if
+// the cluster is closed before fn is run. This is for background goroutines.
+func (c *Cluster) tryAdmin(fn func()) bool {
+	ran := make(chan struct{})
+	select {
+	case c.adminCh <- func() { fn(); close(ran) }:
+		<-ran
+		return true
+	case <-c.die:
+		return false
+	}
+}
+
 // MoveTopicPartition simulates the rebalancing of a partition to an alternative
 // broker. This returns an error if the topic, partition, or node does not exit.
 func (c *Cluster) MoveTopicPartition(topic string, partition int32, nodeID int32) error {
@@ -957,8 +1608,33 @@ func (c *Cluster) MoveTopicPartition(topic string, partition int32, nodeID int32
 			err = errors.New("topic/partition not found")
 			return
 		}
-		pd.leader = br
-		pd.epoch++
+		c.moveLeader(topic, partition, pd, br)
+	})
+	return err
+}
+
+// RecreateTopic deletes and recreates a topic in one step, keeping its
+// partition count, replication factor, and configs, but giving it a new topic
+// ID and empty partitions, as happens when an operator deletes and recreates
+// a topic. Fetches parked on the topic are woken. Fetch requests that use the
+// old topic ID fail with INCONSISTENT_TOPIC_ID, while metadata requests for
+// the old ID fail with UNKNOWN_TOPIC_ID. Committed offsets are untouched. This
+// returns an error if the topic does not exist.
+func (c *Cluster) RecreateTopic(topic string) error {
+	var err error
+	c.admin(func() {
+		ps, ok := c.data.tps.gett(topic)
+		if !ok {
+			err = fmt.Errorf("topic %q does not exist", topic)
+			return
+		}
+		var (
+			nparts    = len(ps)
+			nreplicas = c.data.treplicas[topic]
+			configs   = c.data.tcfgs[topic]
+		)
+		c.data.deleteTopic(topic)
+		c.data.mkt(topic, nparts, nreplicas, configs)
 	})
 	return err
 }
@@ -985,8 +1661,142 @@ func (c *Cluster) RehashCoordinators() {
 	c.coordinatorGen.Add(1)
 }
 
+// ParkedFetch is a long-poll fetch that is waiting in the cluster for data
+// or for its max wait to expire.
+type ParkedFetch struct {
+	// ClientID is the client ID of the client that issued the fetch.
+	ClientID string
+	// Node is the broker the fetch was issued to.
+	Node int32
+	// Key is the request key: kmsg.Fetch or kmsg.ShareFetch.
+	Key int16
+	// Partitions are the partitions the fetch is waiting on.
+	Partitions map[string][]int32
+	// Deadline is when the fetch will return if no data arrives.
+	Deadline time.Time
+}
+
+// ParkedFetches returns all fetches that are currently parked waiting for
+// data. Tests can poll this to know that a consumer is waiting in a fetch
+// before producing or injecting faults.
+//
+// Parked fetches are woken as soon as enough data is appended to the
+// partitions they are waiting on, no matter how the data is appended: by
+// produce requests, or from within the cluster with GenerateRecords or any
+// other API that adds records. Use WakeParkedFetches to wake fetches early
+// for anything else.
+func (c *Cluster) ParkedFetches() []ParkedFetch {
+	var fs []ParkedFetch
+	c.admin(func() {
+		for w := range c.parked {
+			f := ParkedFetch{
+				ClientID:   w.creq.cid,
+				Node:       w.creq.cc.b.node,
+				Key:        w.creq.kreq.Key(),
+				Partitions: make(map[string][]int32, len(w.parts)),
+				Deadline:   w.deadline,
+			}
+			for t, ps := range w.parts {
+				f.Partitions[t] = append([]int32(nil), ps...)
+			}
+			fs = append(fs, f)
+		}
+	})
+	return fs
+}
+
+// WakeParkedFetches wakes all fetches that are parked waiting for data on any
+// partition of topic, or all parked fetches if topic is empty, returning the
+// number of fetches woken. Woken fetches reply immediately with whatever data
+// is available, even if that is less than the fetch's MinBytes. This is
+// useful if a test changes something that a waiting fetch should notice
+// before its MaxWait expires, such as a partition's log start offset.
+func (c *Cluster) WakeParkedFetches(topic string) int {
+	var n int
+	c.admin(func() {
+		for w := range c.parked {
+			if _, ok := w.parts[topic]; ok || topic == "" {
+				w.wake()
+				n++
+			}
+		}
+	})
+	return n
+}
+
+// GroupPartitionLag is the lag of a group on a single partition.
+type GroupPartitionLag struct {
+	Topic     string
+	Partition int32
+
+	// Committed is the group's committed offset, or -1 if the group has
+	// not committed for this partition.
+	Committed int64
+	// Start is the partition's log start offset.
+	Start int64
+	// End is the partition's high watermark.
+	End int64
+	// Lag is End minus Committed, or End minus Start if the group has not
+	// committed. Lag is never negative.
+	Lag int64
+}
+
+// Lag returns the lag of a group for every partition of every topic the group
+// has committed offsets for. This returns an error if the group does not
+// exist. Committed offsets for topics that no longer exist are skipped.
+func (c *Cluster) Lag(group string) (map[string]map[int32]GroupPartitionLag, error) {
+	var (
+		lag map[string]map[int32]GroupPartitionLag
+		err error
+	)
+	c.admin(func() {
+		g, ok := c.groups.gs[group]
+		if !ok {
+			err = fmt.Errorf("group %q not found", group)
+			return
+		}
+		var commits tps[offsetCommit]
+		if !g.waitControl(func() {
+			g.commits.each(func(t string, p int32, oc *offsetCommit) {
+				commits.set(t, p, *oc)
+			})
+		}) {
+			err = fmt.Errorf("group %q not found", group)
+			return
+		}
+		lag = make(map[string]map[int32]GroupPartitionLag)
+		for t := range commits {
+			ps, ok := c.data.tps.gett(t)
+			if !ok {
+				continue
+			}
+			lag[t] = make(map[int32]GroupPartitionLag)
+			for p, pd := range ps {
+				l := GroupPartitionLag{
+					Topic:     t,
+					Partition: p,
+					Committed: -1,
+					Start:     pd.logStartOffset,
+					End:       pd.highWatermark,
+				}
+				from := l.Start
+				if oc, ok := commits.getp(t, p); ok {
+					l.Committed = oc.offset
+					from = oc.offset
+				}
+				if l.Lag = l.End - from; l.Lag < 0 {
+					l.Lag = 0
+				}
+				lag[t][p] = l
+			}
+		}
+	})
+	return lag, err
+}
+
 // AddNode adds a node to the cluster. If nodeID is -1, the next node ID is
-// used. If port is 0 or negative, a random port is chosen. This returns the
+// used. If port is 0 or negative, a random port is chosen. If the port is held
+// from RemoveNodeHoldPort, the held listener is reused. This returns the
 // added node ID and the port used, or an error if the node already exists or
 // the port cannot be listened to.
 func (c *Cluster) AddNode(nodeID int32, port int) (int32, int, error) {
@@ -1016,30 +1826,68 @@ func (c *Cluster) AddNode(nodeID int32, port int) (int32, int, error) {
 		if port < 0 {
 			port = 0
 		}
+		if b, ok := c.held[port]; ok {
+			delete(c.held, port)
+			b.node = nodeID
+			b.bsIdx = len(c.bs)
+			b.rack = c.rackFor(len(c.bs))
+			b.skew = 0
+			b.held.Store(false)
+			c.bs = append(c.bs, b)
+			c.cfg.nbrokers++
+			c.shufflePartitionsLocked()
+			return
+		}
 		var ln net.Listener
-		if ln, err = newListener(port, c.cfg.tls); err != nil {
+		if ln, err = c.newListener(port, c.cfg.tls); err != nil {
 			return
 		}
-		_, strPort, _ := net.SplitHostPort(ln.Addr().String())
-		port, _ = strconv.Atoi(strPort)
+		var lns []net.Listener
+		if lns, err = c.newAdditionalListeners(nil); err != nil {
+			ln.Close()
+			return
+		}
+		port = lnPort(ln)
 		b := &broker{
 			c:     c,
 			ln:    ln,
+			lns:   lns,
 			node:  nodeID,
 			bsIdx: len(c.bs),
+			rack:  c.rackFor(len(c.bs)),
 		}
 		c.bs = append(c.bs, b)
 		c.cfg.nbrokers++
 		c.shufflePartitionsLocked()
-		go b.listen()
+		b.listenAll()
 	})
 	return nodeID, port, err
 }
 
-// RemoveNode removes a ndoe from the cluster. This returns an error if the
-// node does not exist.
+// RemoveNode removes a node from the cluster. If the node is the controller,
+// the controller fails over to another broker, preferring a broker that is a
+// quorum voter. This returns an error if the node does not exist.
 func (c *Cluster) RemoveNode(nodeID int32) error {
-	var err error
+	_, err := c.removeNode(nodeID, false)
+	return err
+}
+
+// RemoveNodeHoldPort removes a node from the cluster like RemoveNode, but
+// keeps listening on the node's port and closes the node's connections.
+// Until the port is passed to AddNode, any connection to the port is
+// immediately closed. Adding a node on the held port atomically reuses the
+// listener, so clients that cached the old node's address reconnect to the
+// new node, as with a broker replacement behind a stable advertised listener.
+// This returns the held port, or an error if the node does not exist.
+func (c *Cluster) RemoveNodeHoldPort(nodeID int32) (int, error) {
+	return c.removeNode(nodeID, true)
+}
+
+func (c *Cluster) removeNode(nodeID int32, hold bool) (int, error) {
+	var (
+		port int
+		err  error
+	)
 	c.admin(func() {
 		for i, b := range c.bs {
 			if b.node == nodeID {
@@ -1047,17 +1895,176 @@ func (c *Cluster) RemoveNode(nodeID int32) error {
 					err = errors.New("cannot remove all brokers")
 					return
 				}
-				b.ln.Close()
+				if hold {
+					port = lnPort(b.ln)
+					b.held.Store(true)
+					if c.held == nil {
+						c.held = make(map[int]*broker)
+					}
+					c.held[port] = b
+					c.liveMu.Lock()
+					for cc := range c.live {
+						if cc.b == b {
+							cc.conn.Close()
+						}
+					}
+					c.liveMu.Unlock()
+				} else {
+					b.closeListeners()
+				}
+				b.setPause(false, false)
 				c.cfg.nbrokers--
 				c.bs[i] = c.bs[len(c.bs)-1]
 				c.bs[i].bsIdx = i
 				c.bs = c.bs[:len(c.bs)-1]
+				if b == c.controller {
+					c.quorum.failover()
+				}
 				c.shufflePartitionsLocked()
 				return
 			}
 		}
 		err = fmt.Errorf("node %d not found", nodeID)
 	})
+	return port, err
+}
+
+// Restart simulates a full cluster outage and recovery: every broker stops
+// listening, all client connections are closed, and then every broker listens
+// again on the same address. All topic, group, and configuration state is
+// preserved, while connection specific state (SASL authentication, parked
+// fetches) is lost. This returns an error if any broker is unable to listen
+// on its prior address; brokers that could not listen remain down.
+func (c *Cluster) Restart() error {
+	var err error
+	c.admin(func() {
+		for _, b := range c.bs {
+			b.closeListeners()
+		}
+		c.liveMu.Lock()
+		for cc := range c.live {
+			cc.conn.Close()
+		}
+		c.liveMu.Unlock()
+		for w := range c.parked {
+			w.cleanup(c)
+		}
+		for _, b := range c.bs {
+			ln, lerr := c.newListener(lnPort(b.ln), c.cfg.tls)
+			if lerr != nil {
+				err = fmt.Errorf("unable to restart node %d: %w", b.node, lerr)
+				continue
+			}
+			var ports []int
+			for _, ln := range b.lns {
+				ports = append(ports, lnPort(ln))
+			}
+			lns, lerr := c.newAdditionalListeners(ports)
+			if lerr != nil {
+				ln.Close()
+				err = fmt.Errorf("unable to restart node %d: %w", b.node, lerr)
+				continue
+			}
+			b.ln, b.lns = ln, lns
+			b.listenAll()
+		}
+	})
+	return err
+}
+
+// MoveController moves the controller role to the given node, as if the
+// controller failed over. For the stale duration, metadata responses continue
+// to advertise the old controller, and the old controller replies
+// NOT_CONTROLLER to controller-bound requests (CreateTopics, DeleteTopics,
+// CreatePartitions, AlterUserScramCredentials), so clients must retry until
+// they rediscover the new controller. The node becomes a quorum voter if it is
+// not one already. This returns an error if the node does not exist.
+func (c *Cluster) MoveController(nodeID int32, stale time.Duration) error {
+	var err error
+	c.admin(func() {
+		for _, b := range c.bs {
+			if b.node != nodeID {
+				continue
+			}
+			if b != c.controller {
+				c.staleController = c.controller
+				c.staleControllerUntil = time.Now().Add(stale)
+				c.quorum.elect(b)
+			}
+			return
+		}
+		err = fmt.Errorf("node %d not found", nodeID)
+	})
+	return err
+}
+
+// advertisedController returns the controller to advertise in metadata,
+// which is the old controller if a MoveController stale window is active.
+func (c *Cluster) advertisedController() *broker {
+	if c.staleController != nil && time.Now().Before(c.staleControllerUntil) {
+		for _, b := range c.bs {
+			if b == c.staleController {
+				return b
+			}
+		}
+	}
+	c.staleController = nil
+	return c.controller
+}
+
+// SetBrokerClockSkew skews a broker's clock relative to the cluster clock.
+// The broker uses its skewed clock for anything that is timestamped by the
+// broker, such as LogAppendTime batches and produce timestamp validation.
+// This returns an error if the node does not exist.
+func (c *Cluster) SetBrokerClockSkew(nodeID int32, skew time.Duration) error {
+	var err error
+	c.admin(func() {
+		for _, b := range c.bs {
+			if b.node == nodeID {
+				b.skew = skew
+				return
+			}
+		}
+		err = fmt.Errorf("node %d not found", nodeID)
+	})
+	return err
+}
+
+func (b *broker) now() time.Time {
+	return b.c.now().Add(b.skew)
+}
+
+// SetEpochHistory overrides the leader epoch history that OffsetForLeaderEpoch
+// uses for a partition, rather than deriving it from the epochs that batches
+// were produced in. The map is each prior epoch to that epoch's end offset;
+// a requested epoch is answered with the largest epoch in the history at or
+// below it and that epoch's end offset. Setting an end offset below what a
+// client has already consumed fabricates a divergence, which the client
+// should detect as truncation. If the history contains an epoch at or past
+// the partition's current epoch, the current epoch is bumped past it. The
+// current epoch always ends at the high watermark. A nil history removes the
+// override. This returns an error if the partition does not exist.
+func (c *Cluster) SetEpochHistory(topic string, partition int32, endOffsets map[int32]int64) error {
+	var err error
+	c.admin(func() {
+		pd, ok := c.data.tps.getp(topic, partition)
+		if !ok {
+			err = fmt.Errorf("topic %s partition %d not found", topic, partition)
+			return
+		}
+		pd.epochHistory = nil
+		if endOffsets == nil {
+			return
+		}
+		c.metadataChanged()
+		pd.epochHistory = make(map[int32]int64, len(endOffsets))
+		for e, o := range endOffsets {
+			pd.epochHistory[e] = o
+			if e >= pd.epoch {
+				pd.epoch = e + 1
+			}
+		}
+	})
 	return err
 }
 
@@ -1071,14 +2078,13 @@ func (c *Cluster) ShufflePartitionLeaders() {
 }
 
 func (c *Cluster) shufflePartitionsLocked() {
-	c.data.tps.each(func(_ string, _ int32, p *partData) {
+	c.data.tps.each(func(t string, p int32, pd *partData) {
 		var leader *broker
 		if len(c.bs) == 0 {
 			leader = c.noLeader()
 		} else {
 			leader = c.bs[rand.Intn(len(c.bs))]
 		}
-		p.leader = leader
-		p.epoch++
+		c.moveLeader(t, p, pd, leader)
 	})
 }
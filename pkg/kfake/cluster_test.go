@@ -0,0 +1,508 @@
+package kfake
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"reflect"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestControlDoesNotBlockOtherRequests(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(1), SeedTopics(1, "t"))
+
+	entered := make(chan struct{}, 2)
+	release := make(chan struct{})
+	c.ControlKey(int16(kmsg.ListOffsets), func(kmsg.Request) (kmsg.Response, error, bool) {
+		c.KeepControl()
+		entered <- struct{}{}
+		<-release // block without sleeping
+		return nil, nil, false
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	first := make(chan error, 1)
+	go func() {
+		_, err := newTestAdmin(t, c).ListEndOffsets(ctx, "t")
+		first <- err
+	}()
+	select {
+	case <-entered:
+	case <-ctx.Done():
+		t.Fatal("control function never ran")
+	}
+
+	// A ListOffsets from another client could be controlled, so it waits
+	// for the running control function.
+	second := make(chan error, 1)
+	go func() {
+		_, err := newTestAdmin(t, c).ListEndOffsets(ctx, "t")
+		second <- err
+	}()
+
+	// Requests that no control function can intercept are served while
+	// the control function is blocked.
+	adm := newTestAdmin(t, c)
+	if _, err := adm.ListTopics(ctx); err != nil {
+		t.Fatalf("metadata while control was running: %v", err)
+	}
+	if _, err := adm.CreateTopic(ctx, 1, 1, nil, "t2"); err != nil {
+		t.Fatalf("create topic while control was running: %v", err)
+	}
+
+	select {
+	case err := <-second:
+		t.Fatalf("controllable request completed while control was running: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	for _, ch := range []chan error{first, second} {
+		select {
+		case err := <-ch:
+			if err != nil {
+				t.Errorf("list offsets: %v", err)
+			}
+		case <-ctx.Done():
+			t.Fatal("list offsets never completed")
+		}
+	}
+	if n := len(entered); n != 1 {
+		t.Errorf("control ran %d more times, want 1 for the deferred request", n)
+	}
+}
+
+func TestControlKeyAsync(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(1), SeedTopics(1, "t"))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	const delay = 200 * time.Millisecond
+	var calls atomic.Int32
+	entered := make(chan struct{})
+	c.ControlKeyAsync(int16(kmsg.ListOffsets), func(kreq kmsg.Request, respond func(kmsg.Response, error)) bool {
+		c.KeepControl()
+		if calls.Add(1) > 1 {
+			return false // later requests are handled as normal
+		}
+		reply := func(code int16) kmsg.Response {
+			req := kreq.(*kmsg.ListOffsetsRequest)
+			resp := req.ResponseKind().(*kmsg.ListOffsetsResponse)
+			for _, rt := range req.Topics {
+				st := kmsg.NewListOffsetsResponseTopic()
+				st.Topic = rt.Topic
+				for _, rp := range rt.Partitions {
+					sp := kmsg.NewListOffsetsResponseTopicPartition()
+					sp.Partition = rp.Partition
+					sp.ErrorCode = code
+					st.Partitions = append(st.Partitions, sp)
+				}
+				resp.Topics = append(resp.Topics, st)
+			}
+			return resp
+		}
+		time.AfterFunc(delay, func() {
+			respond(reply(kerr.KafkaStorageError.Code), nil)
+			respond(reply(kerr.UnknownServerError.Code), nil) // the first reply wins
+		})
+		close(entered)
+		return true
+	})
+
+	listOffsets := func(br *kgo.Broker) (int16, time.Duration) {
+		req := kmsg.NewPtrListOffsetsRequest()
+		rt := kmsg.NewListOffsetsRequestTopic()
+		rt.Topic = "t"
+		rp := kmsg.NewListOffsetsRequestTopicPartition()
+		rp.Timestamp = -1
+		rt.Partitions = append(rt.Partitions, rp)
+		req.Topics = append(req.Topics, rt)
+		start := time.Now()
+		resp, err := req.RequestWith(ctx, br)
+		if err != nil {
+			t.Error(err)
+			return -1, 0
+		}
+		return resp.Topics[0].Partitions[0].ErrorCode, time.Since(start)
+	}
+
+	type result struct {
+		code int16
+		took time.Duration
+	}
+	first, second := newTestClient(t, c).Broker(0), newTestClient(t, c).Broker(0)
+	delayed := make(chan result, 1)
+	go func() {
+		code, took := listOffsets(first)
+		delayed <- result{code, took}
+	}()
+	select {
+	case <-entered:
+	case <-ctx.Done():
+		t.Fatal("control function never ran")
+	}
+
+	// While the first request waits for its reply, the control function
+	// is sleeping, so even requests it could intercept are served.
+	if code, took := listOffsets(second); code != 0 || took >= delay {
+		t.Errorf("request while a reply was pending: got %v after %v", kerr.ErrorForCode(code), took)
+	}
+	select {
+	case <-delayed:
+		t.Fatal("delayed request completed before its reply")
+	default:
+	}
+
+	r := <-delayed
+	if r.code != kerr.KafkaStorageError.Code || r.took < delay {
+		t.Errorf("delayed request: got %v after %v, expected KAFKA_STORAGE_ERROR after %v", kerr.ErrorForCode(r.code), r.took, delay)
+	}
+}
+
+func TestShutdownDrains(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	for _, drain := range []bool{true, false} {
+		c := newTestCluster(t, NumBrokers(1), SeedTopics(1, "t"))
+		cl := newTestClient(t, c, kgo.RequestRetries(0))
+		addr := c.ListenAddrs()[0]
+
+		// A parked fetch is in flight; shutting down waits for its
+		// reply, unless our context expires first.
+		maxWait, shutdownWait := 300*time.Millisecond, 5*time.Second
+		if !drain {
+			maxWait, shutdownWait = 5*time.Second, 50*time.Millisecond
+		}
+		done := parkFetch(ctx, t, c, cl, maxWait)
+		waitParked(ctx, t, c, 1)
+
+		sctx, scancel := context.WithTimeout(ctx, shutdownWait)
+		err := c.Shutdown(sctx)
+		scancel()
+		if drain && err != nil {
+			t.Fatalf("shutdown: %v", err)
+		} else if !drain && !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("shutdown with an expiring context: got %v, expected deadline exceeded", err)
+		}
+		select {
+		case n := <-done:
+			if drain && n != 0 {
+				t.Errorf("drained fetch returned %d, expected an empty reply", n)
+			} else if !drain && n != -1 {
+				t.Errorf("fetch returned %d after the cluster closed, expected a connection error", n)
+			}
+		case <-ctx.Done():
+			t.Fatal("fetch never returned")
+		}
+
+		if conn, err := net.DialTimeout("tcp", addr, time.Second); err == nil {
+			conn.Close()
+			t.Error("cluster accepted a connection after shutdown")
+		}
+		if err := c.Shutdown(ctx); err != nil {
+			t.Errorf("second shutdown: %v", err)
+		}
+	}
+}
+
+func TestRestart(t *testing.T) {
+	var connects atomic.Int32
+	c := newTestCluster(t, NumBrokers(2), SeedTopics(2, "t"), OnConnect(func(Connection) { connects.Add(1) }))
+	cl := newTestClient(t, c, kgo.DefaultProduceTopic("t"))
+	adm := newTestAdmin(t, c)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := cl.ProduceSync(ctx, kgo.StringRecord("before")).FirstErr(); err != nil {
+		t.Fatal(err)
+	}
+	var os kadm.Offsets
+	os.AddOffset("t", 0, 1, -1)
+	if err := adm.CommitAllOffsets(ctx, "g", os); err != nil {
+		t.Fatal(err)
+	}
+	addrs := c.ListenAddrs()
+	before := connects.Load()
+
+	if err := c.Restart(); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.ListenAddrs(); !reflect.DeepEqual(got, addrs) {
+		t.Errorf("got addrs %v after restart, expected %v", got, addrs)
+	}
+
+	// Our clients reconnect and see all prior state.
+	if err := cl.ProduceSync(ctx, kgo.StringRecord("after")).FirstErr(); err != nil {
+		t.Fatal(err)
+	}
+	if connects.Load() == before {
+		t.Error("client did not reconnect after restart")
+	}
+	ends, err := adm.ListEndOffsets(ctx, "t")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var total int64
+	ends.Each(func(o kadm.ListedOffset) { total += o.Offset })
+	if total != 2 {
+		t.Errorf("got %d records after restart, expected 2", total)
+	}
+	fetched, err := adm.FetchOffsets(ctx, "g")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o, _ := fetched.Lookup("t", 0); o.At != 1 {
+		t.Errorf("got committed offset %d after restart, expected 1", o.At)
+	}
+}
+
+func TestRemoveNodeHoldPort(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(2), SeedTopics(1, "t"))
+	cl := newTestClient(t, c)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if _, err := c.RemoveNodeHoldPort(5); err == nil {
+		t.Error("RemoveNodeHoldPort of a missing node did not fail")
+	}
+	addr := c.ListenAddrs()[1]
+	if _, err := kmsg.NewPtrMetadataRequest().RequestWith(ctx, cl.Broker(1)); err != nil {
+		t.Fatal(err)
+	}
+
+	port, err := c.RemoveNodeHoldPort(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, p, _ := net.SplitHostPort(addr); p != strconv.Itoa(port) {
+		t.Fatalf("held port %d, expected the port of %s", port, addr)
+	}
+
+	// While held, connections are accepted and immediately closed.
+	conn, err := net.DialTimeout("tcp", addr, time.Second)
+	if err != nil {
+		t.Fatalf("held port is not listening: %v", err)
+	}
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if _, err := conn.Read(make([]byte, 1)); !errors.Is(err, io.EOF) {
+		t.Errorf("read from held port: got %v, expected EOF", err)
+	}
+	conn.Close()
+
+	// The replacement node takes over the port, and our client's cached
+	// address for node 1 reaches it. The client's first request may see
+	// its old connection was closed; it then reconnects.
+	if _, p, err := c.AddNode(1, port); err != nil || p != port {
+		t.Fatalf("AddNode on the held port: got port %d, err %v", p, err)
+	}
+	if _, err := kmsg.NewPtrMetadataRequest().RequestWith(ctx, cl.Broker(1)); err != nil {
+		if _, err := kmsg.NewPtrMetadataRequest().RequestWith(ctx, cl.Broker(1)); err != nil {
+			t.Errorf("request to the replaced node: %v", err)
+		}
+	}
+}
+
+func TestNodeIDs(t *testing.T) {
+	for _, opts := range [][]Opt{
+		{NodeIDs(1, 1)},
+		{NodeIDs(-1)},
+		{NodeIDs(1, 2), Ports(0)},
+	} {
+		if _, err := NewCluster(opts...); err == nil {
+			t.Errorf("NewCluster with invalid node IDs did not fail")
+		}
+	}
+
+	c := newTestCluster(t, NumBrokers(1), NodeIDs(1001, 1002, 1005), SeedTopics(3, "t"))
+	cl := newTestClient(t, c, kgo.DefaultProduceTopic("t"))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	resp, err := kmsg.NewPtrMetadataRequest().RequestWith(ctx, cl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nodes := make(map[int32]bool)
+	for _, b := range resp.Brokers {
+		nodes[b.NodeID] = true
+	}
+	if exp := map[int32]bool{1001: true, 1002: true, 1005: true}; !reflect.DeepEqual(nodes, exp) {
+		t.Fatalf("got brokers %v, expected %v", nodes, exp)
+	}
+	for _, st := range resp.Topics {
+		for _, sp := range st.Partitions {
+			if !nodes[sp.Leader] {
+				t.Errorf("partition %d is led by unknown node %d", sp.Partition, sp.Leader)
+			}
+		}
+	}
+	if err := cl.ProduceSync(ctx, kgo.StringRecord("v")).FirstErr(); err != nil {
+		t.Errorf("produce: %v", err)
+	}
+
+	if id, _, err := c.AddNode(-1, 0); err != nil || nodes[id] || id < 0 {
+		t.Errorf("AddNode: got node %d, err %v, expected a new node ID", id, err)
+	}
+}
+
+func TestMoveController(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(3))
+	cl := newTestClient(t, c)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := c.MoveController(5, 0); err == nil {
+		t.Error("MoveController to a missing node did not fail")
+	}
+	controller := func() int32 {
+		t.Helper()
+		resp, err := kmsg.NewPtrMetadataRequest().RequestWith(ctx, cl)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.ControllerID
+	}
+	create := func(node int32, topic string) int16 {
+		t.Helper()
+		req := kmsg.NewPtrCreateTopicsRequest()
+		req.TimeoutMillis = 5000
+		rt := kmsg.NewCreateTopicsRequestTopic()
+		rt.Topic = topic
+		rt.NumPartitions, rt.ReplicationFactor = 1, 1
+		req.Topics = append(req.Topics, rt)
+		resp, err := req.RequestWith(ctx, cl.Broker(int(node)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.Topics[0].ErrorCode
+	}
+
+	old := controller()
+	next := (old + 1) % 3
+	const stale = 300 * time.Millisecond
+	moved := time.Now()
+	if err := c.MoveController(next, stale); err != nil {
+		t.Fatal(err)
+	}
+
+	// During the stale window, metadata advertises the old controller,
+	// which rejects controller requests.
+	if got := controller(); got != old {
+		t.Errorf("got controller %d during the stale window, expected the old %d", got, old)
+	}
+	if code := create(old, "a"); code != kerr.NotController.Code {
+		t.Errorf("create on the old controller: got %v, expected NOT_CONTROLLER", kerr.ErrorForCode(code))
+	}
+	if code := create(next, "b"); code != 0 {
+		t.Errorf("create on the new controller: %v", kerr.ErrorForCode(code))
+	}
+
+	// A client retries until it discovers the new controller.
+	if _, err := newTestAdmin(t, c).CreateTopic(ctx, 1, 1, nil, "c"); err != nil {
+		t.Errorf("admin create topic: %v", err)
+	}
+	if time.Since(moved) < stale {
+		time.Sleep(stale - time.Since(moved))
+	}
+	if got := controller(); got != next {
+		t.Errorf("got controller %d after the stale window, expected %d", got, next)
+	}
+}
+
+func TestRegisterHandler(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(1), ClusterID("real"))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if _, err := kmsg.NewPtrListTransactionsRequest().RequestWith(ctx, newTestClient(t, c)); err == nil {
+		t.Fatal("unimplemented request succeeded")
+	}
+
+	// Handlers implement missing keys, which are then advertised, and
+	// persist across requests.
+	var n int
+	c.RegisterHandler(int16(kmsg.ListTransactions), func(kreq kmsg.Request) (kmsg.Response, error) {
+		n++
+		resp := kreq.ResponseKind().(*kmsg.ListTransactionsResponse)
+		resp.UnknownStateFilters = []string{"handled"}
+		return resp, nil
+	})
+	cl := newTestClient(t, c)
+	for i := 0; i < 2; i++ {
+		resp, err := kmsg.NewPtrListTransactionsRequest().RequestWith(ctx, cl)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(resp.UnknownStateFilters) != 1 || resp.UnknownStateFilters[0] != "handled" {
+			t.Errorf("got %+v, expected the handler's response", resp)
+		}
+	}
+	if n != 2 {
+		t.Errorf("handler ran %d times, expected 2", n)
+	}
+
+	// Handlers replace implemented keys, control functions run first,
+	// and a nil handler restores the cluster's implementation.
+	describe := func() string {
+		t.Helper()
+		resp, err := kmsg.NewPtrDescribeClusterRequest().RequestWith(ctx, cl)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.ClusterID
+	}
+	c.RegisterHandler(int16(kmsg.DescribeCluster), func(kreq kmsg.Request) (kmsg.Response, error) {
+		resp := kreq.ResponseKind().(*kmsg.DescribeClusterResponse)
+		resp.ClusterID = "handler"
+		return resp, nil
+	})
+	c.ControlKey(int16(kmsg.DescribeCluster), func(kreq kmsg.Request) (kmsg.Response, error, bool) {
+		resp := kreq.ResponseKind().(*kmsg.DescribeClusterResponse)
+		resp.ClusterID = "control"
+		return resp, nil, true
+	})
+	for _, exp := range []string{"control", "handler"} {
+		if got := describe(); got != exp {
+			t.Errorf("got cluster ID %q, expected %q", got, exp)
+		}
+	}
+	c.RegisterHandler(int16(kmsg.DescribeCluster), nil)
+	if got := describe(); got != "real" {
+		t.Errorf("got cluster ID %q after removing the handler, expected %q", got, "real")
+	}
+}
+
+func TestListenAdvertisedHost(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(2), ListenHost("0.0.0.0"), AdvertisedHost("localhost"))
+	for _, addr := range c.ListenAddrs() {
+		if host, _, _ := net.SplitHostPort(addr); host != "localhost" {
+			t.Errorf("listen address %s does not use the advertised host", addr)
+		}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	resp, err := kmsg.NewPtrMetadataRequest().RequestWith(ctx, newTestClient(t, c))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Brokers) != 2 {
+		t.Fatalf("got %d brokers, expected 2", len(resp.Brokers))
+	}
+	for _, b := range resp.Brokers {
+		if b.Host != "localhost" {
+			t.Errorf("broker %d advertised host %s, expected localhost", b.NodeID, b.Host)
+		}
+	}
+}
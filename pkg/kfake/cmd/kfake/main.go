@@ -0,0 +1,196 @@
+// Command kfake runs a fake Kafka cluster, for testing clients that are not
+// written in Go or that run out of process, such as in docker-compose test
+// environments.
+//
+// Install with
+//
+//	go install github.com/twmb/franz-go/pkg/kfake/cmd/kfake@latest
+//
+// By default, three brokers listen on 127.0.0.1:9092 through 9094. Once the
+// cluster is up, the bootstrap addresses are printed to stdout as a single
+// comma separated line. The cluster runs until interrupted.
+//
+// To run in a container, listen on all interfaces and advertise the host
+// other containers use to reach this one:
+//
+//	kfake -listen-host 0.0.0.0 -advertised-host kfake -topics foo:3,bar
+//
+// SASL users are added with -sasl MECHANISM:user:pass, which can be repeated.
+// Mechanisms are PLAIN, SCRAM-SHA-256, and SCRAM-SHA-512. TLS is enabled with
+// -tls-cert and -tls-key, and -tls-client-ca additionally requires clients to
+// present a certificate signed by the CA.
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/twmb/franz-go/pkg/kfake"
+)
+
+var (
+	brokers        = flag.Int("brokers", 3, "number of brokers")
+	port           = flag.Int("port", 9092, "port of the first broker, with following brokers on the following ports; 0 chooses random ports")
+	listenHost     = flag.String("listen-host", "127.0.0.1", "host to listen on")
+	advertisedHost = flag.String("advertised-host", "", "host to advertise to clients, if different from the listen host")
+	clusterID      = flag.String("cluster-id", "kfake", "cluster ID")
+	partitions     = flag.Int("partitions", 10, "default number of partitions for created topics")
+	topics         = flag.String("topics", "", "comma separated topics to create, each optionally suffixed with :partitions")
+	autoCreate     = flag.Bool("auto-create-topics", false, "allow topics to be created when produced to or requested in metadata")
+	tlsCert        = flag.String("tls-cert", "", "TLS certificate file; enables TLS with -tls-key")
+	tlsKey         = flag.String("tls-key", "", "TLS key file")
+	tlsClientCA    = flag.String("tls-client-ca", "", "CA file to verify required client certificates with")
+	logLevel       = flag.String("log-level", "none", "log level: none, error, warn, info, or debug")
+
+	sasls []string
+)
+
+func main() {
+	flag.Func("sasl", "SASL user as MECHANISM:user:pass, enabling SASL; can be repeated", func(s string) error {
+		sasls = append(sasls, s)
+		return nil
+	})
+	flag.Parse()
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	opts, err := buildOpts()
+	if err != nil {
+		return err
+	}
+	c, err := kfake.NewCluster(opts...)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	fmt.Println(strings.Join(c.ListenAddrs(), ","))
+
+	sigs := make(chan os.Signal, 2)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	<-sigs
+	return nil
+}
+
+func buildOpts() ([]kfake.Opt, error) {
+	if *brokers < 1 {
+		return nil, errors.New("-brokers must be at least 1")
+	}
+	opts := []kfake.Opt{
+		kfake.NumBrokers(*brokers),
+		kfake.ListenHost(*listenHost),
+		kfake.ClusterID(*clusterID),
+		kfake.DefaultNumPartitions(*partitions),
+	}
+	if *port > 0 {
+		ports := make([]int, *brokers)
+		for i := range ports {
+			ports[i] = *port + i
+		}
+		opts = append(opts, kfake.Ports(ports...))
+	}
+	if *advertisedHost != "" {
+		opts = append(opts, kfake.AdvertisedHost(*advertisedHost))
+	}
+	if *autoCreate {
+		opts = append(opts, kfake.AllowAutoTopicCreation())
+	}
+
+	if *topics != "" {
+		for _, t := range strings.Split(*topics, ",") {
+			name, ps, hasPartitions := strings.Cut(strings.TrimSpace(t), ":")
+			p := -1 // the default number of partitions
+			if hasPartitions {
+				var err error
+				if p, err = strconv.Atoi(ps); err != nil || p < 1 {
+					return nil, fmt.Errorf("invalid partitions in topic %q", t)
+				}
+			}
+			if name == "" {
+				return nil, fmt.Errorf("invalid empty topic in %q", *topics)
+			}
+			opts = append(opts, kfake.SeedTopics(int32(p), name))
+		}
+	}
+
+	if len(sasls) > 0 {
+		opts = append(opts, kfake.EnableSASL())
+	}
+	for _, s := range sasls {
+		parts := strings.SplitN(s, ":", 3)
+		if len(parts) != 3 || parts[1] == "" {
+			return nil, fmt.Errorf("invalid -sasl %q, expected MECHANISM:user:pass", s)
+		}
+		mechanism := strings.ToUpper(parts[0])
+		switch mechanism {
+		case "PLAIN", "SCRAM-SHA-256", "SCRAM-SHA-512":
+		default:
+			return nil, fmt.Errorf("invalid -sasl %q, unknown mechanism %q", s, parts[0])
+		}
+		opts = append(opts, kfake.Superuser(mechanism, parts[1], parts[2]))
+	}
+
+	if *tlsCert != "" || *tlsKey != "" {
+		tc, err := buildTLS()
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, kfake.TLS(tc))
+	} else if *tlsClientCA != "" {
+		return nil, errors.New("-tls-client-ca requires -tls-cert and -tls-key")
+	}
+
+	if *logLevel != "none" {
+		var level kfake.LogLevel
+		switch *logLevel {
+		case "error":
+			level = kfake.LogLevelError
+		case "warn":
+			level = kfake.LogLevelWarn
+		case "info":
+			level = kfake.LogLevelInfo
+		case "debug":
+			level = kfake.LogLevelDebug
+		default:
+			return nil, fmt.Errorf("invalid -log-level %q", *logLevel)
+		}
+		opts = append(opts, kfake.WithLogger(kfake.BasicLogger(os.Stderr, level)))
+	}
+	return opts, nil
+}
+
+func buildTLS() (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(*tlsCert, *tlsKey)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load TLS certificate: %w", err)
+	}
+	tc := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+	}
+	if *tlsClientCA != "" {
+		pem, err := os.ReadFile(*tlsClientCA)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read client CA: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in client CA %s", *tlsClientCA)
+		}
+		tc.ClientCAs = pool
+		tc.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return tc, nil
+}
@@ -0,0 +1,164 @@
+package kfake
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"strings"
+
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// Compaction is only run on demand with Cluster.Compact or CleanLogs, or
+// periodically with AutoCleanLogs. Like Kafka, a
+// tombstone (a record with a null value) that is the latest record for its
+// key survives the first compaction that sees it, which stamps the tombstone
+// with a delete horizon of now + delete.retention.ms. Compactions after the
+// horizon remove the tombstone. "Now" is the partition leader's clock, which
+// can be advanced with SetBrokerClockSkew.
+//
+// Compacted batches are rewritten with their original compression, keep their
+// base offset and last offset delta, and are removed entirely if no records
+// remain. Control batches are never compacted.
+
+// Compact compacts all partitions of the given topics whose cleanup.policy
+// includes "compact". If no topics are given, all compacted topics are
+// compacted.
+func (c *Cluster) Compact(topics ...string) error {
+	var err error
+	c.admin(func() {
+		if len(topics) == 0 {
+			for t := range c.data.tps {
+				topics = append(topics, t)
+			}
+		}
+		for _, t := range topics {
+			ps, ok := c.data.tps.gett(t)
+			if !ok {
+				continue
+			}
+			if !strings.Contains(c.data.config(t, "cleanup.policy"), "compact") {
+				continue
+			}
+			if err = c.compactTopic(t, ps); err != nil {
+				return
+			}
+		}
+	})
+	return err
+}
+
+func (c *Cluster) compactTopic(t string, ps map[int32]*partData) error {
+	retention := c.data.configInt(t, "delete.retention.ms")
+	for _, pd := range ps {
+		if err := pd.compact(pd.leader.now().UnixMilli(), retention); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type compactRec struct {
+	raw  []byte
+	key  []byte
+	tomb bool
+	off  int64
+}
+
+func (pd *partData) compact(now, retention int64) error {
+	var (
+		recs   = make([][]compactRec, len(pd.batches))
+		latest = make(map[string]int64)
+	)
+	for i := range pd.batches {
+		b := &pd.batches[i]
+		if b.Attributes&0x0020 != 0 { // control batch
+			continue
+		}
+		raw, err := decompress(int8(b.Attributes&0x0007), b.Records)
+		if err != nil {
+			return err
+		}
+		for j := int32(0); j < b.NumRecords; j++ {
+			length, n := binary.Varint(raw)
+			if n <= 0 || length < 0 || int64(len(raw)-n) < length {
+				return errors.New("invalid record length")
+			}
+			var r kmsg.Record
+			if err := r.ReadFrom(raw[:n+int(length)]); err != nil {
+				return err
+			}
+			cr := compactRec{
+				raw:  raw[:n+int(length)],
+				key:  r.Key,
+				tomb: r.Value == nil,
+				off:  b.FirstOffset + int64(r.OffsetDelta),
+			}
+			raw = raw[n+int(length):]
+			recs[i] = append(recs[i], cr)
+			if cr.key != nil {
+				latest[string(cr.key)] = cr.off
+			}
+		}
+	}
+
+	keep := pd.batches[:0]
+	for i, b := range pd.batches {
+		if b.Attributes&0x0020 != 0 {
+			keep = append(keep, b)
+			continue
+		}
+		var (
+			records []byte
+			nrecs   int32
+		)
+		for _, cr := range recs[i] {
+			if cr.key != nil {
+				if latest[string(cr.key)] != cr.off {
+					delete(pd.tombstones, cr.off)
+					continue
+				}
+				if cr.tomb {
+					horizon, ok := pd.tombstones[cr.off]
+					if !ok {
+						if pd.tombstones == nil {
+							pd.tombstones = make(map[int64]int64)
+						}
+						pd.tombstones[cr.off] = now + retention
+					} else if now >= horizon {
+						delete(pd.tombstones, cr.off)
+						continue
+					}
+				}
+			}
+			records = append(records, cr.raw...)
+			nrecs++
+		}
+		if nrecs == b.NumRecords {
+			keep = append(keep, b)
+			continue
+		}
+		pd.nbytes -= int64(b.nbytes)
+		if nrecs == 0 {
+			continue
+		}
+		codec := int8(b.Attributes & 0x0007)
+		b.Attributes &^= 0x0007
+		b.NumRecords = nrecs
+		b.Records = records
+		b.Length = int32(49 + len(records))
+		b.CRC = int32(crc32.Checksum(b.AppendTo(nil)[21:], crc32c))
+		if err := recompress(&b.RecordBatch, codec); err != nil {
+			return err
+		}
+		b.reencode()
+		b.nbytes = int(b.Length) + 12
+		pd.nbytes += int64(b.nbytes)
+		keep = append(keep, b)
+	}
+	for i := len(keep); i < len(pd.batches); i++ {
+		pd.batches[i] = partBatch{}
+	}
+	pd.batches = keep
+	return nil
+}
@@ -0,0 +1,88 @@
+package kfake
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func TestCompactionTombstones(t *testing.T) {
+	start := time.Now()
+	c := newTestCluster(t, NumBrokers(1), SeedTopics(1, "plain"), Clock(func() time.Time { return start }))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	compact, minute := "compact", "60000"
+	if _, err := newTestAdmin(t, c).CreateTopic(ctx, 1, 1, map[string]*string{
+		"cleanup.policy":      &compact,
+		"delete.retention.ms": &minute,
+	}, "t"); err != nil {
+		t.Fatal(err)
+	}
+	tomb := func(k string) *kgo.Record { return &kgo.Record{Key: []byte(k)} }
+	rs := []*kgo.Record{
+		kgo.KeyStringRecord("a", "1"),
+		kgo.KeyStringRecord("b", "1"),
+		tomb("a"),
+		kgo.KeyStringRecord("b", "2"),
+		tomb("c"),
+	}
+	for _, topic := range []string{"t", "plain"} {
+		for _, r := range rs {
+			if _, err := c.ProduceTo(topic, 0, &kgo.Record{Key: r.Key, Value: r.Value}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	// read returns the remaining offsets and values, with "-" for
+	// tombstones.
+	read := func(topic string) (offsets []int64, values []string) {
+		t.Helper()
+		rs, err := c.ReadRecords(topic, 0, 0, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, r := range rs {
+			v := string(r.Value)
+			if r.Value == nil {
+				v = "-"
+			}
+			offsets = append(offsets, r.Offset)
+			values = append(values, v)
+		}
+		return offsets, values
+	}
+	check := func(when, topic string, expOffsets []int64, expValues []string) {
+		t.Helper()
+		offsets, values := read(topic)
+		if !reflect.DeepEqual(offsets, expOffsets) || !reflect.DeepEqual(values, expValues) {
+			t.Errorf("%s: %s has offsets %v values %v, expected %v %v", when, topic, offsets, values, expOffsets, expValues)
+		}
+	}
+
+	// The first compaction keeps the latest record per key, including
+	// tombstones, and leaves uncompacted topics alone.
+	if err := c.Compact(); err != nil {
+		t.Fatal(err)
+	}
+	check("first compaction", "t", []int64{2, 3, 4}, []string{"-", "2", "-"})
+	check("first compaction", "plain", []int64{0, 1, 2, 3, 4}, []string{"1", "1", "-", "2", "-"})
+
+	// Tombstones are only removed once delete.retention.ms has passed
+	// since the compaction that first saw them.
+	c.AdvanceTime(59 * time.Second)
+	if err := c.Compact("t"); err != nil {
+		t.Fatal(err)
+	}
+	check("before the delete horizon", "t", []int64{2, 3, 4}, []string{"-", "2", "-"})
+
+	c.AdvanceTime(time.Second)
+	if err := c.Compact("t"); err != nil {
+		t.Fatal(err)
+	}
+	check("after the delete horizon", "t", []int64{3}, []string{"2"})
+}
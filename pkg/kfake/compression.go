@@ -0,0 +1,142 @@
+package kfake
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+
+	"github.com/klauspost/compress/s2"
+	"github.com/klauspost/compress/zstd"
+	"github.com/pierrec/lz4/v4"
+
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+var (
+	errUnknownCodec      = errors.New("unknown compression codec")
+	errMalformedXerial   = errors.New("malformed xerial framing")
+	xerialPfx            = []byte{130, 83, 78, 65, 80, 80, 89, 0}
+	zstdDecoderSingleton *zstd.Decoder
+	zstdEncoderSingleton *zstd.Encoder
+)
+
+func init() {
+	zstdDecoderSingleton, _ = zstd.NewReader(nil, zstd.WithDecoderConcurrency(1))
+	zstdEncoderSingleton, _ = zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1))
+}
+
+// compressionCodecs maps topic compression.type values to Kafka compression
+// codecs.
+var compressionCodecs = map[string]int8{
+	"uncompressed": 0,
+	"gzip":         1,
+	"snappy":       2,
+	"lz4":          3,
+	"zstd":         4,
+}
+
+// compress compresses src with the given Kafka compression codec, as the
+// franz-go client does.
+func compress(codec int8, src []byte) ([]byte, error) {
+	switch codec {
+	case 0:
+		return src, nil
+	case 1:
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		if _, err := gz.Write(src); err != nil {
+			return nil, err
+		}
+		if err := gz.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case 2:
+		return s2.EncodeSnappy(nil, src), nil
+	case 3:
+		var buf bytes.Buffer
+		lz := lz4.NewWriter(&buf)
+		if _, err := lz.Write(src); err != nil {
+			return nil, err
+		}
+		if err := lz.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case 4:
+		return zstdEncoderSingleton.EncodeAll(src, nil), nil
+	default:
+		return nil, errUnknownCodec
+	}
+}
+
+// recompress rewrites the batch's records with the given codec, updating the
+// batch's attributes, length, and CRC.
+func recompress(b *kmsg.RecordBatch, codec int8) error {
+	raw, err := decompress(int8(b.Attributes&0x0007), b.Records)
+	if err != nil {
+		return err
+	}
+	if raw, err = compress(codec, raw); err != nil {
+		return err
+	}
+	b.Attributes = b.Attributes&^0x0007 | int16(codec)
+	b.Records = raw
+	b.Length = int32(49 + len(raw))
+	b.CRC = int32(crc32.Checksum(b.AppendTo(nil)[21:], crc32c))
+	return nil
+}
+
+// decompress decompresses src that was compressed with the given Kafka
+// compression codec (the low three bits of batch attributes).
+func decompress(codec int8, src []byte) ([]byte, error) {
+	switch codec {
+	case 0:
+		return src, nil
+	case 1:
+		gz, err := gzip.NewReader(bytes.NewReader(src))
+		if err != nil {
+			return nil, err
+		}
+		return io.ReadAll(gz)
+	case 2:
+		if len(src) > 16 && bytes.HasPrefix(src, xerialPfx) {
+			return xerialDecode(src)
+		}
+		return s2.Decode(nil, src)
+	case 3:
+		return io.ReadAll(lz4.NewReader(bytes.NewReader(src)))
+	case 4:
+		return zstdDecoderSingleton.DecodeAll(src, nil)
+	default:
+		return nil, errUnknownCodec
+	}
+}
+
+func xerialDecode(src []byte) ([]byte, error) {
+	// bytes 0-8: xerial header
+	// bytes 8-16: xerial version
+	// everything after: uint32 chunk size, snappy chunk
+	src = src[16:]
+	var dst []byte
+	for len(src) > 0 {
+		if len(src) < 4 {
+			return nil, errMalformedXerial
+		}
+		size := int32(binary.BigEndian.Uint32(src))
+		src = src[4:]
+		if size < 0 || len(src) < int(size) {
+			return nil, errMalformedXerial
+		}
+		chunk, err := s2.Decode(nil, src[:size])
+		if err != nil {
+			return nil, err
+		}
+		src = src[size:]
+		dst = append(dst, chunk...)
+	}
+	return dst, nil
+}
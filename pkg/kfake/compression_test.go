@@ -0,0 +1,126 @@
+package kfake
+
+import (
+	"context"
+	"hash/crc32"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestCompressionType(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(1), SeedTopics(1, "gzip", "producer", "compacted"))
+	cl := newTestClient(t, c)
+	br := cl.Broker(0)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	gzip, compact := "gzip", "compact"
+	c.admin(func() {
+		_ = c.data.setTopicConfig("gzip", "compression.type", &gzip, false)
+		_ = c.data.setTopicConfig("compacted", "cleanup.policy", &compact, false)
+	})
+
+	produce := func(topic string, b kmsg.RecordBatch) int16 {
+		t.Helper()
+		req := kmsg.NewPtrProduceRequest()
+		req.Acks = -1
+		req.TimeoutMillis = 5000
+		rt := kmsg.NewProduceRequestTopic()
+		rt.Topic = topic
+		rp := kmsg.NewProduceRequestTopicPartition()
+		rp.Records = b.AppendTo(nil)
+		rt.Partitions = append(rt.Partitions, rp)
+		req.Topics = append(req.Topics, rt)
+		resp, err := req.RequestWith(ctx, br)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.Topics[0].Partitions[0].ErrorCode
+	}
+	batch := func(codec int8, rs ...*kgo.Record) kmsg.RecordBatch {
+		t.Helper()
+		for _, r := range rs {
+			r.Timestamp = time.Now()
+		}
+		b := newRecordBatchFrom(rs)
+		if err := recompress(&b, codec); err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+	// stored returns the codec of each stored batch, failing the test if
+	// a batch's CRC is invalid, and the stored records.
+	stored := func(topic string) ([]int8, []*kgo.Record) {
+		t.Helper()
+		var (
+			codecs []int8
+			badCRC bool
+		)
+		c.admin(func() {
+			pd, _ := c.data.tps.getp(topic, 0)
+			for _, b := range pd.batches {
+				codecs = append(codecs, int8(b.Attributes&0x0007))
+				badCRC = badCRC || int32(crc32.Checksum(b.AppendTo(nil)[21:], crc32c)) != b.CRC
+			}
+		})
+		if badCRC {
+			t.Errorf("%s: stored a batch with an invalid CRC", topic)
+		}
+		rs, err := c.ReadRecords(topic, 0, 0, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return codecs, rs
+	}
+
+	// Batches are recompressed to the topic's compression.type, and kept
+	// as produced for "producer".
+	for _, test := range []struct {
+		topic string
+		exp   int8
+	}{
+		{"gzip", 1},
+		{"producer", 4},
+	} {
+		if code := produce(test.topic, batch(4, kgo.KeySliceRecord([]byte("k"), []byte("v")))); code != 0 {
+			t.Fatalf("%s: %v", test.topic, kerr.ErrorForCode(code))
+		}
+		codecs, rs := stored(test.topic)
+		if len(codecs) != 1 || codecs[0] != test.exp {
+			t.Errorf("%s: got stored codecs %v, expected [%d]", test.topic, codecs, test.exp)
+		}
+		if len(rs) != 1 || string(rs[0].Key) != "k" || string(rs[0].Value) != "v" {
+			t.Errorf("%s: got %d records, expected k=v", test.topic, len(rs))
+		}
+	}
+
+	// Batches that cannot be decompressed are corrupt.
+	b := batch(0, kgo.StringRecord("v"))
+	b.Attributes |= 4
+	b.CRC = int32(crc32.Checksum(b.AppendTo(nil)[21:], crc32c))
+	if code := produce("producer", b); code != kerr.CorruptMessage.Code {
+		t.Errorf("produce an undecodable batch: got %v, expected CORRUPT_MESSAGE", kerr.ErrorForCode(code))
+	}
+
+	// Compaction keeps the batch's codec.
+	if code := produce("compacted", batch(2,
+		kgo.KeySliceRecord([]byte("k"), []byte("1")),
+		kgo.KeySliceRecord([]byte("k"), []byte("2")),
+	)); code != 0 {
+		t.Fatal(kerr.ErrorForCode(code))
+	}
+	if err := c.Compact("compacted"); err != nil {
+		t.Fatal(err)
+	}
+	codecs, rs := stored("compacted")
+	if len(codecs) != 1 || codecs[0] != 2 {
+		t.Errorf("compacted: got stored codecs %v, expected [2]", codecs)
+	}
+	if len(rs) != 1 || string(rs[0].Value) != "2" {
+		t.Errorf("compacted: got %d records, expected only the latest value", len(rs))
+	}
+}
@@ -3,6 +3,8 @@ package kfake
 import (
 	"crypto/tls"
 	"time"
+
+	"github.com/twmb/franz-go/pkg/kversion"
 )
 
 // Opt is an option to configure a client.
@@ -19,23 +21,93 @@ type seedTopics struct {
 	ts []string
 }
 
+type listener struct {
+	name string
+	tls  *tls.Config
+	sasl bool
+}
+
+type topicPolicy struct {
+	pattern    string
+	partitions int32
+	replicas   int16
+	configs    map[string]string
+}
+
 type cfg struct {
 	nbrokers        int
 	ports           []int
+	listenHost      string
+	advertisedHost  string
+	nodeIDs         []int32
 	logger          Logger
+	logProtocol     bool
 	clusterID       string
 	allowAutoTopic  bool
 	defaultNumParts int
 	seedTopics      []seedTopics
+	topicPolicies   []topicPolicy
 
 	minSessionTimeout time.Duration
 	maxSessionTimeout time.Duration
+	maxGroupSize      int
+
+	consumerHeartbeatInterval time.Duration
+	consumerSessionTimeout    time.Duration
+
+	offsetsPartitions int
 
 	enableSASL bool
+	enableACLs bool
 	sasls      map[struct{ m, u string }]string // cleared after client initialization
 	tls        *tls.Config
+	listeners  []listener
+
+	connRate       float64
+	connRateReject bool
 
 	sleepOutOfOrder bool
+
+	telemetryInterval time.Duration
+	telemetryMetrics  []string
+
+	trackEOS bool
+
+	replicationLatency func(topic string, partition int32) time.Duration
+
+	maxRecordKeyBytes    int
+	maxRecordValueBytes  int
+	maxRecordHeaders     int
+	maxRecordHeaderBytes int
+
+	pidExpiration time.Duration
+
+	limits ResourceLimits
+
+	topicDeletionDelay time.Duration
+
+	pruneInterval time.Duration
+	cleanInterval time.Duration
+
+	highThroughput bool
+
+	fetchSessionSlots int
+
+	clock func() time.Time
+
+	leaderMetadataDelay time.Duration
+	mdPropagationDelay  time.Duration
+
+	reassignDelay time.Duration
+
+	removeLegacy   bool
+	maxVersions    *kversion.Versions
+	maxKeyVersions map[int16]int16
+
+	racks []string
+
+	onConnect    func(Connection)
+	onDisconnect func(Connection)
 }
 
 // NumBrokers sets the number of brokers to start in the fake cluster.
@@ -49,11 +121,41 @@ func Ports(ports ...int) Opt {
 	return opt{func(cfg *cfg) { cfg.ports = ports }}
 }
 
+// ListenHost sets the host brokers listen on, overriding the default of
+// 127.0.0.1. Use "0.0.0.0" (along with AdvertisedHost) to accept connections
+// from other machines or containers.
+func ListenHost(host string) Opt {
+	return opt{func(cfg *cfg) { cfg.listenHost = host }}
+}
+
+// AdvertisedHost sets the host brokers advertise in metadata and that
+// ListenAddrs returns, overriding the default of the host the brokers listen
+// on. This is the host clients use to connect.
+func AdvertisedHost(host string) Opt {
+	return opt{func(cfg *cfg) { cfg.advertisedHost = host }}
+}
+
+// NodeIDs sets the node IDs of the brokers to start in the fake cluster,
+// overriding NumBrokers and the default IDs of 0 through NumBrokers-1. This is
+// useful to mirror production clusters, which often number brokers starting
+// at 1 or 1001, or have gaps in their IDs. If Ports is also used, the number
+// of ports must match the number of node IDs.
+func NodeIDs(ids ...int32) Opt {
+	return opt{func(cfg *cfg) { cfg.nodeIDs = ids }}
+}
+
 // WithLogger sets the logger to use.
 func WithLogger(logger Logger) Opt {
 	return opt{func(cfg *cfg) { cfg.logger = logger }}
 }
 
+// LogProtocol logs every request received and response sent as JSON at the
+// debug level, so that you can see exactly what a client sent and what the
+// cluster replied with when a test fails. This is very verbose.
+func LogProtocol() Opt {
+	return opt{func(cfg *cfg) { cfg.logProtocol = true }}
+}
+
 // ClusterID sets the cluster ID to return in metadata responses.
 func ClusterID(clusterID string) Opt {
 	return opt{func(cfg *cfg) { cfg.clusterID = clusterID }}
@@ -84,6 +186,41 @@ func GroupMaxSessionTimeout(d time.Duration) Opt {
 	return opt{func(cfg *cfg) { cfg.maxSessionTimeout = d }}
 }
 
+// GroupMaxSize sets the maximum number of members in a group, similar to
+// Kafka's group.max.size. Members joining a full group are rejected with
+// GROUP_MAX_SIZE_REACHED; members that already joined are unaffected. By
+// default, group size is not limited.
+func GroupMaxSize(n int) Opt {
+	return opt{func(cfg *cfg) { cfg.maxGroupSize = n }}
+}
+
+// GroupConsumerHeartbeatInterval sets the heartbeat interval the cluster
+// tells members of KIP-848 consumer groups to use, similar to Kafka's
+// group.consumer.heartbeat.interval.ms, overriding the default 5 seconds.
+func GroupConsumerHeartbeatInterval(d time.Duration) Opt {
+	return opt{func(cfg *cfg) { cfg.consumerHeartbeatInterval = d }}
+}
+
+// GroupConsumerSessionTimeout sets the session timeout for members of KIP-848
+// consumer groups, similar to Kafka's group.consumer.session.timeout.ms,
+// overriding the default 45 seconds. Members that do not heartbeat within the
+// timeout are removed from their group.
+func GroupConsumerSessionTimeout(d time.Duration) Opt {
+	return opt{func(cfg *cfg) { cfg.consumerSessionTimeout = d }}
+}
+
+// MaterializeOffsets creates the internal __consumer_offsets topic with the
+// given number of partitions and writes every group offset commit to it, as
+// Kafka does. Deleted offsets, whether deleted with OffsetDelete, by deleting
+// the group, or by pruning, are written as tombstones. The topic is compacted
+// like any other compacted topic (see Compact and AutoCleanLogs), leaving the
+// latest commit per group, topic, and partition. Records are keyed and valued
+// with kmsg.OffsetCommitKey and kmsg.OffsetCommitValue. By default, commits
+// are only kept in memory.
+func MaterializeOffsets(partitions int) Opt {
+	return opt{func(cfg *cfg) { cfg.offsetsPartitions = partitions }}
+}
+
 // EnableSASL enables SASL authentication for the cluster. If you do not
 // configure a bootstrap user / pass, the default superuser is "admin" /
 // "admin" with the SCRAM-SHA-256 SASL mechanisms.
@@ -93,19 +230,44 @@ func EnableSASL() Opt {
 
 // Superuser seeds the cluster with a superuser. The method must be either
 // PLAIN, SCRAM-SHA-256, or SCRAM-SHA-512.
-// Note that PLAIN superusers cannot be deleted.
+// Note that PLAIN superusers can only be deleted with RemoveUser.
 // SCRAM superusers can be modified with AlterUserScramCredentials.
+// Users can also be added and removed at runtime with AddUser and RemoveUser.
 // If you delete all SASL users, the kfake cluster will be unusable.
+// With EnableACLs, superusers bypass all ACLs.
 func Superuser(method, user, pass string) Opt {
 	return opt{func(cfg *cfg) { cfg.sasls[struct{ m, u string }{method, user}] = pass }}
 }
 
 // TLS enables TLS for the cluster, using the provided TLS config for
-// listening.
+// listening. Client certificates are verified per the config's ClientAuth.
+// NewTLSCerts can generate a throwaway CA and certificates for testing.
 func TLS(c *tls.Config) Opt {
 	return opt{func(cfg *cfg) { cfg.tls = c }}
 }
 
+// Listener adds a named listener to every broker, in addition to the primary
+// listener configured with Ports, TLS, and EnableSASL. Each listener is on its
+// own random port and has its own security: if tc is non-nil, the listener
+// uses TLS, and if sasl is true, clients must authenticate with SASL before
+// issuing other requests (i.e., PLAINTEXT, SSL, SASL_PLAINTEXT, or SASL_SSL).
+// Like real brokers, responses that contain broker addresses advertise the
+// addresses of the listener the request was received on. Use ListenerAddrs
+// to get the addresses for a listener.
+func Listener(name string, tc *tls.Config, sasl bool) Opt {
+	return opt{func(cfg *cfg) { cfg.listeners = append(cfg.listeners, listener{name, tc, sasl}) }}
+}
+
+// ConnectionRateLimit limits how many connections per second each source IP
+// can open to each broker, emulating Kafka's connection creation rate quota
+// or cloud provider connection throttling. Short bursts of up to one second's
+// worth of connections are allowed. By default, excess connections are
+// delayed until they are within the rate, as Kafka does; if reject is true,
+// excess connections are immediately closed instead.
+func ConnectionRateLimit(perSecond float64, reject bool) Opt {
+	return opt{func(cfg *cfg) { cfg.connRate, cfg.connRateReject = perSecond, reject }}
+}
+
 // SeedTopics provides topics to create by default in the cluster. Each topic
 // will use the given partitions and use the default internal replication
 // factor. If you use a non-positive number for partitions, [DefaultNumPartitions]
@@ -124,3 +286,129 @@ func SeedTopics(partitions int32, ts ...string) Opt {
 func SleepOutOfOrder() Opt {
 	return opt{func(cfg *cfg) { cfg.sleepOutOfOrder = true }}
 }
+
+// TelemetrySubscription sets the client telemetry (KIP-714) subscription that
+// the cluster returns to clients: clients are asked to push metrics matching
+// the given name prefixes every interval. An empty prefix subscribes to all
+// metrics. By default, clients are not subscribed to any metrics and the push
+// interval is 5 minutes. Pushed metrics can be inspected with
+// [Cluster.PushedTelemetry].
+func TelemetrySubscription(interval time.Duration, metrics ...string) Opt {
+	return opt{func(cfg *cfg) { cfg.telemetryInterval, cfg.telemetryMetrics = interval, metrics }}
+}
+
+// TrackExactlyOnce tracks the producer ID, epoch, sequence number, and
+// transactional attributes of every batch the cluster accepts, so that
+// [Cluster.VerifyExactlyOnce] can be used at the end of a test to check that
+// no duplicates or gaps were committed, even across client restarts.
+func TrackExactlyOnce() Opt {
+	return opt{func(cfg *cfg) { cfg.trackEOS = true }}
+}
+
+// ReplicationLatency simulates replication for acks=all produce requests:
+// the function is called for every partition successfully produced to, and
+// the response is delayed by the slowest returned latency. The function can
+// sample from any distribution, e.g. a lognormal with occasional spikes (see
+// LognormalLatency and LatencySpikes), so that produce latencies seen by
+// clients resemble production. The function is called serially from the
+// cluster's run loop and must not block. Produced records are visible to
+// consumers before the delayed response is sent. Partitions whose latency
+// exceeds the produce request's timeout fail with REQUEST_TIMED_OUT once the
+// timeout elapses.
+func ReplicationLatency(fn func(topic string, partition int32) time.Duration) Opt {
+	return opt{func(cfg *cfg) { cfg.replicationLatency = fn }}
+}
+
+// MaxRecordKeyBytes rejects produced records with keys larger than n bytes
+// with INVALID_RECORD. By default, key size is not limited.
+func MaxRecordKeyBytes(n int) Opt {
+	return opt{func(cfg *cfg) { cfg.maxRecordKeyBytes = n }}
+}
+
+// MaxRecordValueBytes rejects produced records with values larger than n
+// bytes with INVALID_RECORD. By default, value size is not limited.
+func MaxRecordValueBytes(n int) Opt {
+	return opt{func(cfg *cfg) { cfg.maxRecordValueBytes = n }}
+}
+
+// MaxRecordHeaders rejects produced records with more than n headers with
+// INVALID_RECORD. By default, the number of headers is not limited.
+func MaxRecordHeaders(n int) Opt {
+	return opt{func(cfg *cfg) { cfg.maxRecordHeaders = n }}
+}
+
+// MaxRecordHeaderBytes rejects produced records whose header keys and values
+// sum to more than n bytes with INVALID_RECORD. By default, header size is
+// not limited.
+func MaxRecordHeaderBytes(n int) Opt {
+	return opt{func(cfg *cfg) { cfg.maxRecordHeaderBytes = n }}
+}
+
+// ProducerIDExpiration expires producer IDs that have not been used to
+// produce for d, similar to Kafka's producer.id.expiration.ms. Produce
+// requests using an expired producer ID fail with UNKNOWN_PRODUCER_ID unless
+// they restart the producer's sequence numbers at zero. By
+// default, producer IDs never expire. See also [Cluster.ExpireProducerID].
+func ProducerIDExpiration(d time.Duration) Opt {
+	return opt{func(cfg *cfg) { cfg.pidExpiration = d }}
+}
+
+// AsyncTopicDeletion deletes topics asynchronously, as ZooKeeper based Kafka
+// clusters do: a deleted topic is marked for deletion for d, during which it
+// is omitted from metadata and fetching or producing to it fails, but it
+// cannot be recreated. CreateTopics for the topic fails with
+// TOPIC_ALREADY_EXISTS and automatic topic creation replies with
+// LEADER_NOT_AVAILABLE. By default, topics are deleted immediately.
+func AsyncTopicDeletion(d time.Duration) Opt {
+	return opt{func(cfg *cfg) { cfg.topicDeletionDelay = d }}
+}
+
+// HighThroughput trades memory for speed so that benchmarks of producers and
+// consumers measure the client rather than the fake cluster. Every produced
+// batch is encoded once when it is appended and the encoded bytes are copied
+// directly into fetch responses, rather than re-encoding batches for every
+// fetch, and the buffers holding a fetch response's batches are reused once
+// the response is written. This roughly doubles the memory used to hold
+// records. Only fetching is optimized; other requests are handled as usual.
+// Responses served in process with HandleRequest do not reuse buffers.
+func HighThroughput() Opt {
+	return opt{func(cfg *cfg) { cfg.highThroughput = true }}
+}
+
+// LeaderMetadataDelay keeps serving a partition's old leader and epoch in
+// Metadata responses for d after the leader moves, such as with
+// ShufflePartitionLeaders or MoveTopicPartition, while produce and fetch
+// requests must already go to the new leader. Clients that trust metadata
+// see a window of NOT_LEADER_FOR_PARTITION errors, as happens in real
+// clusters while leadership changes propagate. Unlike
+// SetBrokerMetadataDelay, this only delays leadership changes and applies to
+// every broker.
+func LeaderMetadataDelay(d time.Duration) Opt {
+	return opt{func(cfg *cfg) { cfg.leaderMetadataDelay = d }}
+}
+
+// MetadataPropagationDelay makes every broker other than the controller apply
+// metadata changes d after the controller makes them, as brokers do while
+// replicating the KRaft metadata log. Until a broker catches up, it serves
+// its old view of topics and leaders in Metadata responses and replies
+// UNKNOWN_TOPIC_OR_PARTITION to produce, fetch, and offset requests for
+// partitions it does not know of yet. The controller always has current
+// metadata, including a broker that just became the controller.
+// SetBrokerMetadataDelay overrides the delay for individual brokers.
+func MetadataPropagationDelay(d time.Duration) Opt {
+	return opt{func(cfg *cfg) { cfg.mdPropagationDelay = d }}
+}
+
+// TopicPolicy sets the default partition count, replication factor, and
+// configs for topics whose name matches pattern, simulating a broker-side
+// topic creation policy. The pattern uses [path.Match] syntax, e.g.
+// "changelog-*". The policy applies to auto created topics, seed topics, and
+// CreateTopics requests: a non-positive partitions or replicationFactor here
+// falls back to the cluster default, and configs only fill in keys that the
+// create request did not set itself. If multiple policies match a topic, the
+// first one provided wins.
+func TopicPolicy(pattern string, partitions int32, replicationFactor int16, configs map[string]string) Opt {
+	return opt{func(cfg *cfg) {
+		cfg.topicPolicies = append(cfg.topicPolicies, topicPolicy{pattern, partitions, replicationFactor, configs})
+	}}
+}
@@ -0,0 +1,115 @@
+package kfake
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestConfigValidation(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(1), SeedTopics(1, "t"))
+	adm := newTestAdmin(t, c)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	str := func(s string) *string { return &s }
+	retention := func() string {
+		t.Helper()
+		rcs, err := adm.DescribeTopicConfigs(ctx, "t")
+		if err != nil {
+			t.Fatal(err)
+		}
+		rc, err := rcs.On("t", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, cfg := range rc.Configs {
+			if cfg.Key == "retention.ms" {
+				return cfg.MaybeValue()
+			}
+		}
+		t.Fatal("retention.ms was not described")
+		return ""
+	}
+	initial := retention()
+
+	for _, test := range []struct {
+		name    string
+		configs []kadm.AlterConfig
+		exp     *kerr.Error
+	}{
+		{"unknown name", []kadm.AlterConfig{{Name: "bogus", Value: str("1")}}, kerr.InvalidConfig},
+		{"invalid number", []kadm.AlterConfig{{Name: "retention.ms", Value: str("abc")}}, kerr.InvalidConfig},
+		{"invalid enum", []kadm.AlterConfig{{Name: "cleanup.policy", Value: str("bogus")}}, kerr.InvalidConfig},
+		{"valid and invalid", []kadm.AlterConfig{{Name: "retention.ms", Value: str("1000")}, {Name: "bogus", Value: str("1")}}, kerr.InvalidConfig},
+	} {
+		for _, alter := range []func(context.Context, []kadm.AlterConfig, ...string) (kadm.AlterConfigsResponses, error){
+			adm.AlterTopicConfigs,
+			adm.AlterTopicConfigsState,
+		} {
+			rs, err := alter(ctx, test.configs, "t")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if r := rs[0]; !errors.Is(r.Err, test.exp) || r.ErrMessage == "" {
+				t.Errorf("%s: got %v %q, expected %v with a message", test.name, r.Err, r.ErrMessage, test.exp)
+			}
+		}
+	}
+	if got := retention(); got != initial {
+		t.Errorf("a rejected alter changed retention.ms from %s to %s", initial, got)
+	}
+
+	rs, err := adm.AlterTopicConfigs(ctx, []kadm.AlterConfig{{Name: "retention.ms", Value: str("1000")}}, "t")
+	if err == nil {
+		err = rs[0].Err
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := retention(); got != "1000" {
+		t.Errorf("got retention.ms %s after a valid alter, expected 1000", got)
+	}
+
+	rs, err = adm.AlterBrokerConfigs(ctx, []kadm.AlterConfig{{Name: "broker.id", Value: str("9")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !errors.Is(rs[0].Err, kerr.InvalidRequest) {
+		t.Errorf("altering a read-only broker config: got %v, expected INVALID_REQUEST", rs[0].Err)
+	}
+
+	// CreateTopics validates configs, honors ValidateOnly, and returns the
+	// topic's configs with their sources.
+	cr, err := adm.CreateTopic(ctx, 1, 1, map[string]*string{"retention.ms": str("abc")}, "bad")
+	if !errors.Is(err, kerr.InvalidConfig) {
+		t.Errorf("create with an invalid config: got %v (%s), expected INVALID_CONFIG", err, cr.ErrMessage)
+	}
+	crs, err := adm.ValidateCreateTopics(ctx, 1, 1, map[string]*string{"retention.ms": str("5")}, "v")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := crs["v"].Err; err != nil {
+		t.Errorf("validate create: %v", err)
+	}
+	if tds, err := adm.ListTopics(ctx); err != nil {
+		t.Fatal(err)
+	} else if tds.Has("v") || tds.Has("bad") {
+		t.Errorf("topics were created: %v", tds.Names())
+	}
+	cr, err = adm.CreateTopic(ctx, 1, 1, map[string]*string{"retention.ms": str("5")}, "made")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := cr.Configs["retention.ms"]; got.MaybeValue() != "5" || got.Source != kmsg.ConfigSourceDynamicTopicConfig {
+		t.Errorf("create: got retention.ms %+v, expected 5 from the topic", got)
+	}
+	if got := cr.Configs["cleanup.policy"]; got.MaybeValue() != "delete" || got.Source != kmsg.ConfigSourceDefaultConfig {
+		t.Errorf("create: got cleanup.policy %+v, expected the default delete", got)
+	}
+}
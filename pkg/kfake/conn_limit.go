@@ -0,0 +1,71 @@
+package kfake
+
+import (
+	"math"
+	"net"
+	"sync"
+	"time"
+)
+
+// Like Kafka's per-IP connection creation rate quota (KIP-612), each broker
+// limits how quickly each source IP can open connections. Each IP has a token
+// bucket that refills at the configured rate and holds up to one second of
+// tokens. A connection that arrives when the bucket is empty is either delayed
+// until a token is available (Kafka's behavior) or immediately closed.
+
+type (
+	connLimiter struct {
+		mu  sync.Mutex
+		ips map[string]*connBucket
+	}
+
+	connBucket struct {
+		tokens float64
+		last   time.Time
+	}
+)
+
+// admit returns how long a new connection from addr must wait before it is
+// served, or false if the connection should be rejected.
+func (b *broker) admit(addr net.Addr) (time.Duration, bool) {
+	rate := b.c.cfg.connRate
+	if rate <= 0 {
+		return 0, true
+	}
+	ip := addr.String()
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+
+	l := &b.connLimit
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var (
+		now   = time.Now()
+		burst = math.Max(1, rate)
+	)
+	if l.ips == nil {
+		l.ips = make(map[string]*connBucket)
+	}
+	bk, ok := l.ips[ip]
+	if !ok {
+		bk = &connBucket{tokens: burst, last: now}
+		l.ips[ip] = bk
+	}
+	bk.tokens = math.Min(burst, bk.tokens+now.Sub(bk.last).Seconds()*rate)
+	bk.last = now
+
+	if bk.tokens >= 1 {
+		bk.tokens--
+		return 0, true
+	}
+	if b.c.cfg.connRateReject {
+		return 0, false
+	}
+	// We reserve the next token: tokens go negative for every
+	// connection that is already waiting.
+	wait := time.Duration((1 - bk.tokens) / rate * float64(time.Second))
+	bk.tokens--
+	return wait, true
+}
@@ -0,0 +1,70 @@
+package kfake
+
+import (
+	"errors"
+	"io"
+	"net"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestConnRateAdmit(t *testing.T) {
+	addr := &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 1}
+	other := &net.TCPAddr{IP: net.IPv4(127, 0, 0, 2), Port: 1}
+	for _, reject := range []bool{false, true} {
+		b := &broker{c: &Cluster{cfg: cfg{connRate: 2, connRateReject: reject}}}
+
+		// A burst of one second's worth of connections is allowed.
+		for i := 0; i < 2; i++ {
+			if wait, ok := b.admit(addr); !ok || wait != 0 {
+				t.Fatalf("reject %v: connection %d: got wait %v ok %v, expected immediate", reject, i, wait, ok)
+			}
+		}
+		wait, ok := b.admit(addr)
+		if reject {
+			if ok {
+				t.Error("excess connection was not rejected")
+			}
+		} else if !ok || wait < 400*time.Millisecond || wait > 500*time.Millisecond {
+			t.Errorf("excess connection: got wait %v ok %v, expected about 500ms", wait, ok)
+		} else if wait, _ := b.admit(addr); wait < 900*time.Millisecond {
+			t.Errorf("second excess connection: got wait %v, expected to queue behind the first", wait)
+		}
+
+		// Buckets are per IP.
+		if wait, ok := b.admit(other); !ok || wait != 0 {
+			t.Errorf("reject %v: other IP: got wait %v ok %v, expected immediate", reject, wait, ok)
+		}
+	}
+}
+
+func TestConnRateReject(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(1), ConnectionRateLimit(2, true))
+	addr := c.ListenAddrs()[0]
+
+	var conns []net.Conn
+	defer func() {
+		for _, conn := range conns {
+			conn.Close()
+		}
+	}()
+	for i := 0; i < 3; i++ {
+		conn, err := net.DialTimeout("tcp", addr, time.Second)
+		if err != nil {
+			t.Fatal(err)
+		}
+		conns = append(conns, conn)
+	}
+
+	// Admitted connections stay open; the excess one is closed.
+	for i, conn := range conns {
+		conn.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+		_, err := conn.Read(make([]byte, 1))
+		if closed := errors.Is(err, io.EOF); closed != (i == 2) {
+			t.Errorf("connection %d: got read error %v, expected closed %v", i, err, i == 2)
+		} else if !closed && !errors.Is(err, os.ErrDeadlineExceeded) {
+			t.Errorf("connection %d: got read error %v, expected a timeout", i, err)
+		}
+	}
+}
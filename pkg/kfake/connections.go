@@ -0,0 +1,92 @@
+package kfake
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Connection describes an open client connection.
+type Connection struct {
+	Node        int32     // Node is the broker the client is connected to.
+	Listener    string    // Listener is the name of the listener the client connected to, empty for the primary listener.
+	RemoteAddr  string    // RemoteAddr is the client's address, which identifies the connection.
+	ClientID    string    // ClientID is the client ID of the most recent request on the connection.
+	Principal   string    // Principal is the SASL authenticated principal, such as "User:bob", or empty.
+	ConnectedAt time.Time // ConnectedAt is when the connection was accepted.
+}
+
+// OnConnect calls fn whenever a client connection is accepted, before any
+// request is read. The connection has no client ID or principal yet.
+//
+// Connection hooks are called from connection goroutines, concurrently across
+// connections. A hook must not block and must not call Cluster functions
+// other than Connections and KillConnection.
+func OnConnect(fn func(Connection)) Opt {
+	return opt{func(cfg *cfg) { cfg.onConnect = fn }}
+}
+
+// OnDisconnect calls fn whenever a client connection is closed, for any
+// reason. See OnConnect for restrictions on connection hooks.
+func OnDisconnect(fn func(Connection)) Opt {
+	return opt{func(cfg *cfg) { cfg.onDisconnect = fn }}
+}
+
+// Connections returns every open client connection, sorted by node and then by
+// when the connection was accepted.
+func (c *Cluster) Connections() []Connection {
+	c.liveMu.Lock()
+	conns := make([]Connection, 0, len(c.live))
+	for cc := range c.live {
+		conns = append(conns, cc.info())
+	}
+	c.liveMu.Unlock()
+	sort.Slice(conns, func(i, j int) bool {
+		if conns[i].Node != conns[j].Node {
+			return conns[i].Node < conns[j].Node
+		}
+		return conns[i].ConnectedAt.Before(conns[j].ConnectedAt)
+	})
+	return conns
+}
+
+// KillConnection closes the client connection from remoteAddr, as returned in
+// Connection.RemoteAddr. This returns an error if no such connection is open.
+func (c *Cluster) KillConnection(remoteAddr string) error {
+	c.liveMu.Lock()
+	defer c.liveMu.Unlock()
+	for cc := range c.live {
+		if cc.conn.RemoteAddr().String() == remoteAddr {
+			cc.conn.Close()
+			return nil
+		}
+	}
+	return fmt.Errorf("connection from %s not found", remoteAddr)
+}
+
+func (cc *clientConn) info() Connection {
+	cc.infoMu.Lock()
+	defer cc.infoMu.Unlock()
+	return Connection{
+		Node:        cc.node,
+		Listener:    cc.listener,
+		RemoteAddr:  cc.conn.RemoteAddr().String(),
+		ClientID:    cc.clientID,
+		Principal:   cc.principal,
+		ConnectedAt: cc.connectedAt,
+	}
+}
+
+func (cc *clientConn) setClientID(cid string) {
+	cc.infoMu.Lock()
+	defer cc.infoMu.Unlock()
+	cc.clientID = cid
+}
+
+// setPrincipal sets the SASL authenticated principal. The principal is only
+// written in the cluster run loop, which can read it without the lock.
+func (cc *clientConn) setPrincipal(principal string) {
+	cc.infoMu.Lock()
+	defer cc.infoMu.Unlock()
+	cc.principal = principal
+}
@@ -0,0 +1,82 @@
+package kfake
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+	"github.com/twmb/franz-go/pkg/sasl/plain"
+)
+
+func TestConnections(t *testing.T) {
+	var (
+		connected    = make(chan Connection, 10)
+		disconnected = make(chan Connection, 10)
+	)
+	c := newTestCluster(t, NumBrokers(1), EnableSASL(), Superuser("PLAIN", "admin", "pw"),
+		OnConnect(func(conn Connection) { connected <- conn }),
+		OnDisconnect(func(conn Connection) { disconnected <- conn }),
+	)
+	// Requests go to the seed broker, so that the client only has one
+	// connection.
+	cl := newTestClient(t, c,
+		kgo.ClientID("me"),
+		kgo.SASL(plain.Auth{User: "admin", Pass: "pw"}.AsMechanism()),
+	)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	recv := func(what string, ch chan Connection) Connection {
+		t.Helper()
+		select {
+		case conn := <-ch:
+			return conn
+		case <-ctx.Done():
+			t.Fatalf("%s hook was not called", what)
+			return Connection{}
+		}
+	}
+
+	if conns := c.Connections(); len(conns) != 0 {
+		t.Fatalf("got %d connections before any request, expected 0", len(conns))
+	}
+	if _, err := kmsg.NewPtrMetadataRequest().RequestWith(ctx, cl); err != nil {
+		t.Fatal(err)
+	}
+	accepted := recv("connect", connected)
+	if accepted.ClientID != "" || accepted.Principal != "" {
+		t.Errorf("connect hook got client ID %q and principal %q, expected neither", accepted.ClientID, accepted.Principal)
+	}
+
+	conns := c.Connections()
+	if len(conns) != 1 {
+		t.Fatalf("got %d connections, expected 1", len(conns))
+	}
+	conn := conns[0]
+	if conn.Node != 0 || conn.ClientID != "me" || conn.Principal != "User:admin" || conn.RemoteAddr != accepted.RemoteAddr || conn.ConnectedAt.IsZero() {
+		t.Errorf("got connection %+v, expected node 0 from %s with client ID me as User:admin", conn, accepted.RemoteAddr)
+	}
+
+	if err := c.KillConnection("127.0.0.1:1"); err == nil {
+		t.Error("killing a missing connection succeeded")
+	}
+	if err := c.KillConnection(conn.RemoteAddr); err != nil {
+		t.Fatal(err)
+	}
+	if closed := recv("disconnect", disconnected); closed.RemoteAddr != conn.RemoteAddr {
+		t.Errorf("disconnect hook got %s, expected %s", closed.RemoteAddr, conn.RemoteAddr)
+	}
+	if conns := c.Connections(); len(conns) != 0 {
+		t.Errorf("got %d connections after killing the only one, expected 0", len(conns))
+	}
+
+	// The client reconnects on its next request.
+	if _, err := kmsg.NewPtrMetadataRequest().RequestWith(ctx, cl); err != nil {
+		t.Fatal(err)
+	}
+	if reconnected := recv("connect", connected); reconnected.RemoteAddr == conn.RemoteAddr {
+		t.Errorf("reconnect reused remote address %s", conn.RemoteAddr)
+	}
+}
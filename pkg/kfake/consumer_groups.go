@@ -0,0 +1,546 @@
+package kfake
+
+import (
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// Consumer groups (KIP-848) are groups whose members only issue
+// ConsumerGroupHeartbeat requests; the cluster computes every assignment.
+// They share the group type and its manage goroutine with classic groups so
+// that offsets are committed and fetched the same way, but a group has
+// either classic members or consumer members, never both. Heartbeats are
+// never delayed, so the cluster waits while the group handles a heartbeat,
+// which lets the group read the cluster's topics.
+//
+// Assignments are reconciled as in Kafka. The group epoch is bumped whenever
+// any member's target assignment changes. A member that owns partitions
+// that are not in its target must first revoke them, and keeps its epoch
+// until it heartbeats that it no longer owns them. It then moves to the
+// group epoch and is assigned the partitions of its target that no other
+// member still owns; the rest are assigned on later heartbeats once the
+// other members release them.
+//
+// TODO
+//
+// * The "uniform" and "range" assignors are both implemented by spreading
+//   every topic's partitions evenly across the topic's subscribers, keeping
+//   prior assignments where possible. Racks are ignored.
+// * Static members that leave with epoch -2 are removed immediately rather
+//   than kept for their session timeout.
+// * ConsumerGroupDescribe is not supported. As in Kafka, DescribeGroups
+//   describes consumer groups as Dead.
+// * Offset commits validate the member epoch for all OffsetCommit versions,
+//   and TxnOffsetCommit does not validate consumer group members at all.
+
+type consumerMember struct {
+	memberID   string
+	instanceID *string
+	rackID     *string
+	clientID   string
+	clientHost string
+
+	topics []string       // subscribed topic names
+	regex  *regexp.Regexp // subscribed regex (v1+), if any
+
+	epoch     int32
+	prevEpoch int32
+
+	targeted bool               // false until the member is first given a target
+	target   map[string][]int32 // the member's assignment at the group epoch
+	assigned map[string][]int32 // what the member is currently assigned
+	revoking map[string][]int32 // what the member must revoke before advancing
+	owned    map[string][]int32 // what the member last reported owning
+	changed  bool               // whether assigned changed since we last sent it
+
+	t    *time.Timer
+	last time.Time // cluster clock time of the last heartbeat
+}
+
+// handleConsumerHeartbeat runs the heartbeat in the group's manage loop while
+// the cluster waits, creating the group if a member is joining.
+func (gs *groups) handleConsumerHeartbeat(creq *clientReq) *kmsg.ConsumerGroupHeartbeatResponse {
+	req := creq.kreq.(*kmsg.ConsumerGroupHeartbeatRequest)
+	resp := req.ResponseKind().(*kmsg.ConsumerGroupHeartbeatResponse)
+
+	if kerr := gs.c.validateGroup(creq, req.Group); kerr != nil {
+		resp.ErrorCode = kerr.Code
+		return resp
+	}
+	if kerr := validateConsumerHeartbeat(req); kerr != nil {
+		resp.ErrorCode = kerr.Code
+		return resp
+	}
+
+	if gs.gs == nil {
+		gs.gs = make(map[string]*group)
+	}
+	g := gs.gs[req.Group]
+	created := g == nil && req.MemberEpoch == 0
+	if created {
+		g = gs.newGroup(req.Group)
+		gs.gs[req.Group] = g
+		go g.manage(nil)
+	}
+	if g == nil || !g.waitControl(func() {
+		resp = g.handleConsumerHeartbeat(creq)
+		if created && len(g.consumers) == 0 {
+			delete(gs.gs, g.name)
+			g.quitOnce()
+		}
+	}) {
+		resp.ErrorCode = kerr.GroupIDNotFound.Code
+	}
+	return resp
+}
+
+// validateConsumerHeartbeat validates the request fields as Kafka does before
+// looking at the group.
+func validateConsumerHeartbeat(req *kmsg.ConsumerGroupHeartbeatRequest) *kerr.Error {
+	// KIP-1082: since v1, clients generate their own member ID.
+	if req.Version >= 1 && req.MemberID == "" {
+		return kerr.InvalidRequest
+	}
+	switch epoch := req.MemberEpoch; {
+	case epoch == 0:
+		if req.RebalanceTimeoutMillis < 0 ||
+			req.Topics == nil || len(req.Topics) > 0 ||
+			req.SubscribedTopicNames == nil && req.SubscribedTopicRegex == nil {
+			return kerr.InvalidRequest
+		}
+	case epoch < -2:
+		return kerr.InvalidRequest
+	case epoch == -2 && req.InstanceID == nil:
+		return kerr.InvalidRequest
+	default:
+		if req.MemberID == "" {
+			return kerr.InvalidRequest
+		}
+	}
+	if a := req.ServerAssignor; a != nil && *a != "uniform" && *a != "range" {
+		return kerr.UnsupportedAssignor
+	}
+	if re := req.SubscribedTopicRegex; re != nil && *re != "" {
+		if _, err := compileConsumerRegex(*re); err != nil {
+			return kerr.InvalidRegularExpression
+		}
+	}
+	return nil
+}
+
+// compileConsumerRegex compiles a subscription regex, which must match an
+// entire topic name. Kafka uses RE2/J, which has the same syntax as Go.
+func compileConsumerRegex(re string) (*regexp.Regexp, error) {
+	return regexp.Compile("^(?:" + re + ")$")
+}
+
+func (g *group) handleConsumerHeartbeat(creq *clientReq) *kmsg.ConsumerGroupHeartbeatResponse {
+	req := creq.kreq.(*kmsg.ConsumerGroupHeartbeatRequest)
+	resp := req.ResponseKind().(*kmsg.ConsumerGroupHeartbeatResponse)
+
+	// As in Kafka, a classic group with members is not a consumer group.
+	// An empty classic group is converted.
+	if len(g.members) > 0 || len(g.pending) > 0 {
+		resp.ErrorCode = kerr.GroupIDNotFound.Code
+		return resp
+	}
+	if g.consumers == nil {
+		g.consumers = make(map[string]*consumerMember)
+		g.protocolType = "consumer"
+	}
+
+	var m *consumerMember
+	switch req.MemberEpoch {
+	case -1, -2:
+		m = g.consumers[req.MemberID]
+		if m == nil {
+			resp.ErrorCode = kerr.UnknownMemberID.Code
+			return resp
+		}
+		g.removeConsumer(m)
+		resp.MemberID = &req.MemberID
+		resp.MemberEpoch = req.MemberEpoch
+		return resp
+
+	case 0:
+		if req.MemberID != "" {
+			m = g.consumers[req.MemberID]
+		}
+		if m == nil {
+			if req.InstanceID != nil {
+				for _, o := range g.consumers {
+					if o.instanceID != nil && *o.instanceID == *req.InstanceID {
+						resp.ErrorCode = kerr.UnreleasedInstanceID.Code
+						return resp
+					}
+				}
+			}
+			if max := g.c.cfg.maxGroupSize; max > 0 && len(g.consumers) >= max {
+				resp.ErrorCode = kerr.GroupMaxSizeReached.Code
+				return resp
+			}
+			// At v0, the broker generates the member ID.
+			memberID := req.MemberID
+			if memberID == "" {
+				memberID = generateMemberID(creq.cid, req.InstanceID)
+			}
+			m = &consumerMember{
+				memberID:   memberID,
+				instanceID: req.InstanceID,
+			}
+			g.consumers[memberID] = m
+		}
+		// A member rejoining has released everything it owned.
+		m.assigned, m.revoking = nil, nil
+		m.clientID = creq.cid
+		m.clientHost = creq.cc.conn.RemoteAddr().String()
+
+	default:
+		m = g.consumers[req.MemberID]
+		if m == nil {
+			resp.ErrorCode = kerr.UnknownMemberID.Code
+			return resp
+		}
+		// A member that did not receive our last response may
+		// heartbeat with its previous epoch, so long as it does not
+		// own anything it is no longer assigned.
+		if req.MemberEpoch != m.epoch &&
+			(req.MemberEpoch != m.prevEpoch || req.Topics == nil || !withinAssignment(g.consumerOwned(req.Topics), m.assigned)) {
+			resp.ErrorCode = kerr.FencedMemberEpoch.Code
+			return resp
+		}
+	}
+
+	if req.RackID != nil {
+		m.rackID = req.RackID
+	}
+	if req.SubscribedTopicNames != nil {
+		m.topics = req.SubscribedTopicNames
+	}
+	if req.SubscribedTopicRegex != nil {
+		m.regex = nil
+		if *req.SubscribedTopicRegex != "" {
+			m.regex, _ = compileConsumerRegex(*req.SubscribedTopicRegex) // validated already
+		}
+	}
+	if req.Topics != nil {
+		m.owned = g.consumerOwned(req.Topics)
+	}
+	g.keepConsumerAlive(m)
+
+	// Topics may have been created or have had partitions added since
+	// the last heartbeat, and members that left or timed out have not
+	// yet had their partitions moved, so we recompute targets every time.
+	g.assignConsumers()
+	g.reconcile(m)
+	g.state = groupStable
+
+	resp.MemberID = &m.memberID
+	resp.MemberEpoch = m.epoch
+	resp.HeartbeatIntervalMillis = int32(g.c.cfg.consumerHeartbeatInterval.Milliseconds())
+	if m.changed || req.MemberEpoch != m.epoch {
+		a := kmsg.NewConsumerGroupHeartbeatResponseAssignment()
+		for t, ps := range m.assigned {
+			at := kmsg.NewConsumerGroupHeartbeatResponseAssignmentTopic()
+			at.TopicID = g.c.data.t2id[t]
+			at.Partitions = ps
+			a.Topics = append(a.Topics, at)
+		}
+		resp.Assignment = &a
+		m.changed = false
+	}
+	return resp
+}
+
+// consumerOwned maps the partitions a member reports owning to topic names,
+// dropping topics that no longer exist.
+func (g *group) consumerOwned(topics []kmsg.ConsumerGroupHeartbeatRequestTopic) map[string][]int32 {
+	owned := make(map[string][]int32)
+	for _, t := range topics {
+		name, ok := g.c.data.id2t[t.TopicID]
+		if !ok || len(t.Partitions) == 0 {
+			continue
+		}
+		owned[name] = append(owned[name], t.Partitions...)
+	}
+	for _, ps := range owned {
+		sort.Slice(ps, func(i, j int) bool { return ps[i] < ps[j] })
+	}
+	return owned
+}
+
+// subscribedTopics returns the existing topics a member subscribes to, by
+// name or by regex.
+func (g *group) subscribedTopics(m *consumerMember) []string {
+	var topics []string
+	seen := make(map[string]bool)
+	for _, t := range m.topics {
+		if _, ok := g.c.data.tps.gett(t); ok && !seen[t] {
+			seen[t] = true
+			topics = append(topics, t)
+		}
+	}
+	if m.regex != nil {
+		for t := range g.c.data.tps {
+			if t != offsetsTopic && !seen[t] && m.regex.MatchString(t) {
+				seen[t] = true
+				topics = append(topics, t)
+			}
+		}
+	}
+	return topics
+}
+
+// assignConsumers computes every member's target assignment, bumping the
+// group epoch if any target changed.
+func (g *group) assignConsumers() {
+	ids := make([]string, 0, len(g.consumers))
+	for id := range g.consumers {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	subscribers := make(map[string][]*consumerMember)
+	targets := make(map[string]map[string][]int32, len(ids))
+	for _, id := range ids {
+		m := g.consumers[id]
+		targets[id] = make(map[string][]int32)
+		for _, t := range g.subscribedTopics(m) {
+			subscribers[t] = append(subscribers[t], m)
+		}
+	}
+	for t, ms := range subscribers {
+		ps, _ := g.c.data.tps.gett(t)
+		for i, assigned := range balanceTopic(t, int32(len(ps)), ms) {
+			if len(assigned) > 0 {
+				targets[ms[i].memberID][t] = assigned
+			}
+		}
+	}
+
+	var changed bool
+	for _, id := range ids {
+		m := g.consumers[id]
+		if !m.targeted || !sameAssignment(m.target, targets[id]) {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		return
+	}
+	g.consumerEpoch++
+	for _, id := range ids {
+		m := g.consumers[id]
+		m.target = targets[id]
+		m.targeted = true
+	}
+}
+
+// balanceTopic spreads a topic's partitions evenly across the topic's
+// subscribers. Members keep partitions from their prior target while they
+// are within their share, and the remaining partitions go to the members
+// with the fewest partitions.
+func balanceTopic(t string, partitions int32, ms []*consumerMember) [][]int32 {
+	var (
+		base  = int(partitions) / len(ms)
+		extra = int(partitions) % len(ms)
+		out   = make([][]int32, len(ms))
+		taken = make(map[int32]bool)
+	)
+	for i, m := range ms {
+		for _, p := range m.target[t] {
+			if p < partitions && !taken[p] && len(out[i]) < base {
+				out[i] = append(out[i], p)
+				taken[p] = true
+			}
+		}
+	}
+	for i, m := range ms {
+		if extra == 0 {
+			break
+		}
+		for _, p := range m.target[t] {
+			if p < partitions && !taken[p] {
+				out[i] = append(out[i], p)
+				taken[p] = true
+				extra--
+				break
+			}
+		}
+	}
+	var free []int32
+	for p := int32(0); p < partitions; p++ {
+		if !taken[p] {
+			free = append(free, p)
+		}
+	}
+	for i := range out {
+		for len(out[i]) < base {
+			out[i], free = append(out[i], free[0]), free[1:]
+		}
+	}
+	for i := range out {
+		if extra == 0 {
+			break
+		}
+		if len(out[i]) == base {
+			out[i], free = append(out[i], free[0]), free[1:]
+			extra--
+		}
+	}
+	for _, ps := range out {
+		sort.Slice(ps, func(i, j int) bool { return ps[i] < ps[j] })
+	}
+	return out
+}
+
+// reconcile moves a member toward its target assignment; see the top of this
+// file.
+func (g *group) reconcile(m *consumerMember) {
+	if len(m.revoking) > 0 {
+		if overlapsAssignment(m.owned, m.revoking) {
+			return
+		}
+		m.revoking = nil
+	}
+	if revoke := subtractAssignment(m.assigned, m.target); len(revoke) > 0 {
+		m.assigned = subtractAssignment(m.assigned, revoke)
+		m.revoking = revoke
+		m.changed = true
+		return
+	}
+	if m.epoch != g.consumerEpoch {
+		m.prevEpoch, m.epoch = m.epoch, g.consumerEpoch
+	}
+
+	held := make(map[string][]int32)
+	for _, o := range g.consumers {
+		if o == m {
+			continue
+		}
+		for _, a := range []map[string][]int32{o.assigned, o.revoking} {
+			for t, ps := range a {
+				held[t] = append(held[t], ps...)
+			}
+		}
+	}
+	if next := subtractAssignment(m.target, held); !sameAssignment(next, m.assigned) {
+		m.assigned = next
+		m.changed = true
+	}
+}
+
+// consumerState returns the group's state as Kafka names consumer group
+// states: Empty, Reconciling if any member has not yet reached its target,
+// and Stable otherwise.
+func (g *group) consumerState() string {
+	if len(g.consumers) == 0 {
+		return "Empty"
+	}
+	for _, m := range g.consumers {
+		if m.epoch != g.consumerEpoch || len(m.revoking) > 0 || !sameAssignment(m.assigned, m.target) {
+			return "Reconciling"
+		}
+	}
+	return "Stable"
+}
+
+// keepConsumerAlive resets the member's session timeout; if the member does
+// not heartbeat within the timeout, it is removed from the group.
+func (g *group) keepConsumerAlive(m *consumerMember) {
+	if m.t != nil {
+		m.t.Stop()
+	}
+	m.last = g.c.now()
+	var t *time.Timer
+	t = time.AfterFunc(g.c.cfg.consumerSessionTimeout, func() {
+		select {
+		case <-g.quitCh:
+		case g.controlCh <- func() {
+			if m.t == t {
+				g.expireConsumer(m, g.c.now())
+			}
+		}:
+		}
+	})
+	m.t = t
+}
+
+// expireConsumer removes the member if its session has expired by now.
+func (g *group) expireConsumer(m *consumerMember, now time.Time) {
+	if g.consumers[m.memberID] != m || now.Sub(m.last) < g.c.cfg.consumerSessionTimeout {
+		return
+	}
+	g.removeConsumer(m)
+}
+
+// removeConsumer removes a member that left or timed out. A session timeout
+// does not run while the cluster waits, so we cannot look at topics here;
+// the partitions the member was assigned are reassigned when the remaining
+// members next heartbeat.
+func (g *group) removeConsumer(m *consumerMember) {
+	if m.t != nil {
+		m.t.Stop()
+	}
+	delete(g.consumers, m.memberID)
+	if len(g.consumers) == 0 {
+		g.state = groupEmpty
+	}
+}
+
+// validateConsumerCommit validates an offset commit against a consumer group
+// with members: the generation of the commit is the member epoch.
+func (g *group) validateConsumerCommit(memberID string, epoch int32) *kerr.Error {
+	m, ok := g.consumers[memberID]
+	if !ok {
+		return kerr.UnknownMemberID
+	}
+	if epoch != m.epoch {
+		return kerr.StaleMemberEpoch
+	}
+	return nil
+}
+
+// withinAssignment returns whether every partition in l is in r.
+func withinAssignment(l, r map[string][]int32) bool {
+	return len(subtractAssignment(l, r)) == 0
+}
+
+func overlapsAssignment(l, r map[string][]int32) bool {
+	return countAssignment(subtractAssignment(l, r)) != countAssignment(l)
+}
+
+func countAssignment(a map[string][]int32) int {
+	var n int
+	for _, ps := range a {
+		n += len(ps)
+	}
+	return n
+}
+
+// subtractAssignment returns the partitions in l that are not in r, keeping
+// l's order and dropping topics with no partitions left.
+func subtractAssignment(l, r map[string][]int32) map[string][]int32 {
+	out := make(map[string][]int32)
+	for t, lps := range l {
+		in := make(map[int32]bool, len(r[t]))
+		for _, p := range r[t] {
+			in[p] = true
+		}
+		var ps []int32
+		for _, p := range lps {
+			if !in[p] {
+				ps = append(ps, p)
+			}
+		}
+		if len(ps) > 0 {
+			out[t] = ps
+		}
+	}
+	return out
+}
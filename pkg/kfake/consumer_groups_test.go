@@ -0,0 +1,181 @@
+package kfake
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// consumerTester issues ConsumerGroupHeartbeat requests for members of group
+// "g" subscribing to topic "t". The client cannot route the requests itself
+// (see withKeys), so we issue them all to the one broker in the cluster.
+type consumerTester struct {
+	t   *testing.T
+	ctx context.Context
+	br  *kgo.Broker
+	id  uuid
+}
+
+func newConsumerTester(ctx context.Context, t *testing.T, c *Cluster, cl *kgo.Client) *consumerTester {
+	s := &consumerTester{t: t, ctx: ctx, br: cl.Broker(0)}
+	c.admin(func() { s.id = c.data.t2id["t"] })
+	return s
+}
+
+// heartbeat heartbeats as member at epoch, owning the given partitions of
+// "t"; joining (epoch 0) subscribes to "t".
+func (s *consumerTester) heartbeat(member string, epoch int32, owned ...int32) *kmsg.ConsumerGroupHeartbeatResponse {
+	s.t.Helper()
+	req := kmsg.NewPtrConsumerGroupHeartbeatRequest()
+	req.Group = "g"
+	req.MemberID = member
+	req.MemberEpoch = epoch
+	req.RebalanceTimeoutMillis = 10000
+	req.Topics = []kmsg.ConsumerGroupHeartbeatRequestTopic{}
+	if epoch == 0 {
+		req.SubscribedTopicNames = []string{"t"}
+	}
+	if len(owned) > 0 {
+		rt := kmsg.NewConsumerGroupHeartbeatRequestTopic()
+		rt.TopicID = s.id
+		rt.Partitions = owned
+		req.Topics = append(req.Topics, rt)
+	}
+	resp, err := req.RequestWith(s.ctx, s.br)
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	return resp
+}
+
+// expect checks a heartbeat response's epoch and assignment of "t"; a nil
+// assignment means the response must not contain an assignment.
+func (s *consumerTester) expect(what string, resp *kmsg.ConsumerGroupHeartbeatResponse, epoch int32, assigned []int32) {
+	s.t.Helper()
+	if err := kerr.ErrorForCode(resp.ErrorCode); err != nil {
+		s.t.Fatalf("%s: %v", what, err)
+	}
+	var got []int32
+	if resp.Assignment != nil {
+		got = []int32{}
+		for _, t := range resp.Assignment.Topics {
+			if t.TopicID != s.id {
+				s.t.Errorf("%s: assigned unknown topic ID %v", what, t.TopicID)
+			}
+			got = append(got, t.Partitions...)
+		}
+	}
+	if resp.MemberEpoch != epoch || !reflect.DeepEqual(got, assigned) {
+		s.t.Errorf("%s: got epoch %d assigned %v, expected epoch %d assigned %v", what, resp.MemberEpoch, got, epoch, assigned)
+	}
+}
+
+func TestConsumerGroup(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(1), SeedTopics(3, "t"))
+	cl := newTestClient(t, c, withKeys(68))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	s := newConsumerTester(ctx, t, c, cl)
+
+	s.expect("a joins", s.heartbeat("a", 0), 1, []int32{0, 1, 2})
+	s.expect("a is stable", s.heartbeat("a", 1, 0, 1, 2), 1, nil)
+
+	// b's target is partition 2, which a must revoke before b gets it;
+	// a keeps its epoch until it no longer owns 2.
+	s.expect("b joins", s.heartbeat("b", 0), 2, []int32{})
+	s.expect("a revokes", s.heartbeat("a", 1, 0, 1, 2), 1, []int32{0, 1})
+	s.expect("b waits", s.heartbeat("b", 2), 2, nil)
+	s.expect("a revoked", s.heartbeat("a", 1, 0, 1), 2, []int32{0, 1})
+	s.expect("b is assigned", s.heartbeat("b", 2), 2, []int32{2})
+
+	if resp := s.heartbeat("b", 5, 2); resp.ErrorCode != kerr.FencedMemberEpoch.Code {
+		t.Errorf("heartbeat from a future epoch: got %v, expected FENCED_MEMBER_EPOCH", kerr.ErrorForCode(resp.ErrorCode))
+	}
+	if resp := s.heartbeat("c", 1); resp.ErrorCode != kerr.UnknownMemberID.Code {
+		t.Errorf("heartbeat from an unknown member: got %v, expected UNKNOWN_MEMBER_ID", kerr.ErrorForCode(resp.ErrorCode))
+	}
+	if resp := s.heartbeat("", 1); resp.ErrorCode != kerr.InvalidRequest.Code {
+		t.Errorf("heartbeat without a member ID: got %v, expected INVALID_REQUEST", kerr.ErrorForCode(resp.ErrorCode))
+	}
+
+	// Commits are validated against the member epoch.
+	commit := func(member string, epoch int32) int16 {
+		t.Helper()
+		req := kmsg.NewPtrOffsetCommitRequest()
+		req.Group, req.MemberID, req.Generation = "g", member, epoch
+		rt := kmsg.NewOffsetCommitRequestTopic()
+		rt.Topic = "t"
+		rp := kmsg.NewOffsetCommitRequestTopicPartition()
+		rp.Partition, rp.Offset = 2, 1
+		rt.Partitions = append(rt.Partitions, rp)
+		req.Topics = append(req.Topics, rt)
+		resp, err := req.RequestWith(ctx, s.br)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.Topics[0].Partitions[0].ErrorCode
+	}
+	if code := commit("b", 1); code != kerr.StaleMemberEpoch.Code {
+		t.Errorf("commit at a stale epoch: got %v, expected STALE_MEMBER_EPOCH", kerr.ErrorForCode(code))
+	}
+	if code := commit("b", 2); code != 0 {
+		t.Errorf("commit: got %v, expected success", kerr.ErrorForCode(code))
+	}
+
+	list := kmsg.NewPtrListGroupsRequest()
+	if resp, err := list.RequestWith(ctx, s.br); err != nil {
+		t.Fatal(err)
+	} else if len(resp.Groups) != 1 || resp.Groups[0].GroupType != "consumer" || resp.Groups[0].GroupState != "Stable" {
+		t.Errorf("list: got %+v, expected one stable consumer group", resp.Groups)
+	}
+
+	// Classic members cannot join while the group has members.
+	join := kmsg.NewPtrJoinGroupRequest()
+	join.Group = "g"
+	join.SessionTimeoutMillis = 10000
+	join.ProtocolType = "consumer"
+	p := kmsg.NewJoinGroupRequestProtocol()
+	p.Name = "range"
+	join.Protocols = append(join.Protocols, p)
+	if resp, err := join.RequestWith(ctx, s.br); err != nil {
+		t.Fatal(err)
+	} else if resp.ErrorCode != kerr.InconsistentGroupProtocol.Code {
+		t.Errorf("classic join: got %v, expected INCONSISTENT_GROUP_PROTOCOL", kerr.ErrorForCode(resp.ErrorCode))
+	}
+
+	// Once b leaves, a is given everything at a new epoch.
+	leave := s.heartbeat("b", -1)
+	if leave.ErrorCode != 0 || leave.MemberEpoch != -1 {
+		t.Errorf("leave: got %v at epoch %d", kerr.ErrorForCode(leave.ErrorCode), leave.MemberEpoch)
+	}
+	s.expect("a takes over", s.heartbeat("a", 2, 0, 1), 3, []int32{0, 1, 2})
+}
+
+func TestConsumerGroupSessionTimeout(t *testing.T) {
+	start := time.Now()
+	c := newTestCluster(t, NumBrokers(1), SeedTopics(2, "t"), Clock(func() time.Time { return start }))
+	cl := newTestClient(t, c, withKeys(68))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	s := newConsumerTester(ctx, t, c, cl)
+
+	s.expect("a joins", s.heartbeat("a", 0), 1, []int32{0, 1})
+	s.expect("b joins", s.heartbeat("b", 0), 2, []int32{})
+	s.expect("a revokes", s.heartbeat("a", 1, 0, 1), 1, []int32{0})
+	s.expect("a revoked", s.heartbeat("a", 1, 0), 2, []int32{0})
+	s.expect("b is assigned", s.heartbeat("b", 2), 2, []int32{1})
+
+	// b stops heartbeating and is removed once its session times out.
+	c.AdvanceTime(30 * time.Second)
+	s.expect("a heartbeats", s.heartbeat("a", 2, 0), 2, nil)
+	c.AdvanceTime(30 * time.Second)
+	if resp := s.heartbeat("b", 2, 1); resp.ErrorCode != kerr.UnknownMemberID.Code {
+		t.Errorf("heartbeat after timing out: got %v, expected UNKNOWN_MEMBER_ID", kerr.ErrorForCode(resp.ErrorCode))
+	}
+	s.expect("a takes over", s.heartbeat("a", 2, 0), 3, []int32{0, 1})
+}
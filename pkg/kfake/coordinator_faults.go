@@ -0,0 +1,64 @@
+package kfake
+
+import (
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+)
+
+// Coordinator faults make the coordinator for a group or transactional ID
+// unusable for a while, as a real coordinator is while it loads state after
+// becoming the coordinator, or while the coordinator partition has no leader.
+
+type coordFault struct {
+	err   *kerr.Error
+	until time.Time
+}
+
+// CoordinatorLoading makes the coordinator for the group or transactional ID
+// key reply COORDINATOR_LOAD_IN_PROGRESS to requests for the key until d
+// elapses, as a broker does while it loads state after becoming the
+// coordinator. FindCoordinator still returns the coordinator. This can be
+// paired with RehashCoordinators to simulate a coordinator moving.
+//
+// Calling this or CoordinatorUnavailable again for the same key replaces the
+// prior fault, and a zero or negative d clears it.
+func (c *Cluster) CoordinatorLoading(key string, d time.Duration) {
+	c.setCoordFault(key, kerr.CoordinatorLoadInProgress, d)
+}
+
+// CoordinatorUnavailable makes the coordinator for the group or transactional
+// ID key unavailable until d elapses: FindCoordinator and requests for the key
+// reply COORDINATOR_NOT_AVAILABLE. See CoordinatorLoading for more details.
+func (c *Cluster) CoordinatorUnavailable(key string, d time.Duration) {
+	c.setCoordFault(key, kerr.CoordinatorNotAvailable, d)
+}
+
+func (c *Cluster) setCoordFault(key string, err *kerr.Error, d time.Duration) {
+	c.coordFaultsMu.Lock()
+	defer c.coordFaultsMu.Unlock()
+	if d <= 0 {
+		delete(c.coordFaults, key)
+		return
+	}
+	if c.coordFaults == nil {
+		c.coordFaults = make(map[string]coordFault)
+	}
+	c.coordFaults[key] = coordFault{err, time.Now().Add(d)}
+}
+
+// coordFault returns the error the coordinator for key is currently failing
+// with, if any. This is called from both the run loop and group goroutines.
+func (c *Cluster) coordFault(key string) *kerr.Error {
+	c.coordFaultsMu.Lock()
+	defer c.coordFaultsMu.Unlock()
+	f, ok := c.coordFaults[key]
+	if !ok {
+		return nil
+	}
+	if !time.Now().Before(f.until) {
+		delete(c.coordFaults, key)
+		return nil
+	}
+	return f.err
+}
@@ -0,0 +1,84 @@
+package kfake
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestCoordinatorFaults(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(1))
+	br := newTestClient(t, c).Broker(0)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	find := func(key string) int16 {
+		t.Helper()
+		req := kmsg.NewPtrFindCoordinatorRequest()
+		req.CoordinatorKeys = []string{key}
+		resp, err := req.RequestWith(ctx, br)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.Coordinators[0].ErrorCode
+	}
+	describe := func() int16 {
+		t.Helper()
+		req := kmsg.NewPtrDescribeGroupsRequest()
+		req.Groups = []string{"g"}
+		resp, err := req.RequestWith(ctx, br)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.Groups[0].ErrorCode
+	}
+	initPID := func() int16 {
+		t.Helper()
+		req := kmsg.NewPtrInitProducerIDRequest()
+		req.TransactionalID = kmsg.StringPtr("txn")
+		req.TransactionTimeoutMillis = 10000
+		resp, err := req.RequestWith(ctx, br)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.ErrorCode
+	}
+
+	// A loading coordinator is still found, but rejects requests for the
+	// key until the load finishes.
+	c.CoordinatorLoading("g", time.Hour)
+	c.CoordinatorLoading("txn", 200*time.Millisecond)
+	if code := find("g"); code != 0 {
+		t.Errorf("finding a loading coordinator: %v", kerr.ErrorForCode(code))
+	}
+	if code := describe(); code != kerr.CoordinatorLoadInProgress.Code {
+		t.Errorf("describing a group: got %v, expected COORDINATOR_LOAD_IN_PROGRESS", kerr.ErrorForCode(code))
+	}
+	if code := initPID(); code != kerr.CoordinatorLoadInProgress.Code {
+		t.Errorf("initializing a transactional ID: got %v, expected COORDINATOR_LOAD_IN_PROGRESS", kerr.ErrorForCode(code))
+	}
+	time.Sleep(250 * time.Millisecond)
+	if code := initPID(); code != 0 {
+		t.Errorf("initializing a transactional ID after loading: %v", kerr.ErrorForCode(code))
+	}
+
+	// An unavailable coordinator cannot be found; a zero duration clears
+	// any fault.
+	c.CoordinatorUnavailable("g", time.Hour)
+	if code := find("g"); code != kerr.CoordinatorNotAvailable.Code {
+		t.Errorf("finding an unavailable coordinator: got %v, expected COORDINATOR_NOT_AVAILABLE", kerr.ErrorForCode(code))
+	}
+	if code := describe(); code != kerr.CoordinatorNotAvailable.Code {
+		t.Errorf("describing a group: got %v, expected COORDINATOR_NOT_AVAILABLE", kerr.ErrorForCode(code))
+	}
+	c.CoordinatorUnavailable("g", 0)
+	if code := find("g"); code != 0 {
+		t.Errorf("finding a coordinator after clearing its fault: %v", kerr.ErrorForCode(code))
+	}
+	if code := describe(); code != 0 {
+		t.Errorf("describing a group after clearing its coordinator fault: %v", kerr.ErrorForCode(code))
+	}
+}
@@ -0,0 +1,40 @@
+package kfake
+
+// Correlation faults reply to requests with the wrong correlation ID, as a
+// broker or proxy that desynchronized the request and response stream would.
+
+// CorruptCorrelationIDs makes the next n responses for the given request key
+// use the wrong correlation ID, so that client detection of a desynchronized
+// connection can be tested: clients must close the connection and retry the
+// request on a new one. The response body itself is valid. Calling this again
+// for the same key replaces the prior count, and a zero or negative n clears
+// it.
+func (c *Cluster) CorruptCorrelationIDs(key int16, n int) {
+	c.corrFaultsMu.Lock()
+	defer c.corrFaultsMu.Unlock()
+	if n <= 0 {
+		delete(c.corrFaults, key)
+		return
+	}
+	if c.corrFaults == nil {
+		c.corrFaults = make(map[int16]int)
+	}
+	c.corrFaults[key] = n
+}
+
+// corrFault returns whether the next response for key should use the wrong
+// correlation ID. This is called from connection writer goroutines.
+func (c *Cluster) corrFault(key int16) bool {
+	c.corrFaultsMu.Lock()
+	defer c.corrFaultsMu.Unlock()
+	n, ok := c.corrFaults[key]
+	if !ok {
+		return false
+	}
+	if n <= 1 {
+		delete(c.corrFaults, key)
+	} else {
+		c.corrFaults[key] = n - 1
+	}
+	return true
+}
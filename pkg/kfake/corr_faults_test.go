@@ -0,0 +1,46 @@
+package kfake
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestCorruptCorrelationIDs(t *testing.T) {
+	var conns atomic.Int32
+	c := newTestCluster(t, NumBrokers(1), OnConnect(func(Connection) { conns.Add(1) }))
+	br := newTestClient(t, c).Broker(0)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	describe := func() error {
+		_, err := kmsg.NewPtrDescribeClusterRequest().RequestWith(ctx, br)
+		return err
+	}
+	if err := describe(); err != nil {
+		t.Fatal(err)
+	}
+	before := conns.Load()
+
+	// The corrupted response fails the request; the client must use a new
+	// connection for the next.
+	c.CorruptCorrelationIDs(int16(kmsg.DescribeCluster), 1)
+	if err := describe(); err == nil {
+		t.Fatal("request with a corrupted correlation ID succeeded")
+	}
+	if err := describe(); err != nil {
+		t.Fatalf("request after the fault: %v", err)
+	}
+	if n := conns.Load(); n <= before {
+		t.Errorf("client did not reconnect after a corrupted correlation ID")
+	}
+
+	c.CorruptCorrelationIDs(int16(kmsg.DescribeCluster), 5)
+	c.CorruptCorrelationIDs(int16(kmsg.DescribeCluster), 0)
+	if err := describe(); err != nil {
+		t.Errorf("request after clearing the fault: %v", err)
+	}
+}
@@ -2,11 +2,16 @@ package kfake
 
 import (
 	"crypto/sha256"
+	"errors"
+	"fmt"
 	"math/rand"
+	"path"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/twmb/franz-go/pkg/kerr"
 	"github.com/twmb/franz-go/pkg/kmsg"
 )
 
@@ -28,9 +33,14 @@ type (
 		t2id      map[string]uuid               // topic name => topic IDs
 		treplicas map[string]int                // topic name => # replicas
 		tcfgs     map[string]map[string]*string // topic name => config name => config value
+		deleting  map[string]time.Time          // topic name => when its async deletion finishes
+		retired   map[uuid]string               // IDs of deleted topics => topic name
 	}
 
 	partData struct {
+		t string
+		p int32
+
 		batches []partBatch
 		dir     string
 
@@ -47,6 +57,16 @@ type (
 
 		watch map[*watchFetch]struct{}
 
+		openTxns    map[int64]int64 // producer ID => first offset of its open transaction
+		abortedTxns []abortedTxn    // in order of abort marker offset
+
+		outOfSync    map[int32]struct{} // follower node IDs taken out of the ISR with SetReplicaInSync
+		tombstones   map[int64]int64    // tombstone offset => delete horizon millis, set on first compaction
+		epochHistory map[int32]int64    // if non-nil, prior epoch => end offset, overriding batch epochs for OffsetForLeaderEpoch
+
+		assigned []int32       // explicit replica assignment from a reassignment, if non-nil
+		reassign *reassignment // in progress reassignment, if non-nil
+
 		createdAt time.Time
 	}
 
@@ -65,6 +85,11 @@ type (
 		// When we drop the earlier timestamp, we update all following
 		// firstMaxTimestamps that match the dropped timestamp.
 		maxEarlierTimestamp int64
+
+		// If HighThroughput, the encoded batch. Anything that modifies
+		// a stored batch must call reencode, and anything that writes a
+		// stored batch must use appendTo.
+		raw []byte
 	}
 )
 
@@ -78,11 +103,30 @@ func (d *data) mkt(t string, nparts int, nreplicas int, configs map[string]*stri
 	for {
 		sha := sha256.Sum256([]byte(strconv.Itoa(int(time.Now().UnixNano()))))
 		copy(id[:], sha[:])
-		if _, exists := d.id2t[id]; !exists {
+		_, exists := d.id2t[id]
+		_, retired := d.retired[id]
+		if !exists && !retired {
 			break
 		}
 	}
 
+	if p := d.c.topicPolicy(t); p != nil {
+		if nparts < 0 && p.partitions > 0 {
+			nparts = int(p.partitions)
+		}
+		if nreplicas < 0 && p.replicas > 0 {
+			nreplicas = int(p.replicas)
+		}
+		for k, v := range p.configs {
+			if configs == nil {
+				configs = make(map[string]*string)
+			}
+			if _, exists := configs[k]; !exists {
+				v := v
+				configs[k] = &v
+			}
+		}
+	}
 	if nparts < 0 {
 		nparts = d.c.cfg.defaultNumParts
 	}
@@ -96,8 +140,127 @@ func (d *data) mkt(t string, nparts int, nreplicas int, configs map[string]*stri
 		d.tcfgs[t] = configs
 	}
 	for i := 0; i < nparts; i++ {
-		d.tps.mkp(t, int32(i), d.c.newPartData)
+		p := int32(i)
+		d.tps.mkp(t, p, func() *partData { return d.c.newPartData(t, p) })
+	}
+	d.c.emit(Event{Type: EventTopicCreated, Topic: t})
+}
+
+// validTopicName validates a topic name as Kafka does in Topic.validate: the
+// name must be non-empty, not "." or "..", at most 249 characters, and only
+// contain ASCII alphanumerics, '.', '_', and '-'.
+func validTopicName(t string) error {
+	switch t {
+	case "":
+		return errors.New("Topic name is illegal, it can't be empty")
+	case ".", "..":
+		return errors.New("Topic name cannot be \".\" or \"..\"")
+	}
+	if len(t) > 249 {
+		return fmt.Errorf("Topic name is invalid: '%s' is too long, max length is 249 characters", t)
+	}
+	for _, r := range t {
+		switch {
+		case r >= 'a' && r <= 'z',
+			r >= 'A' && r <= 'Z',
+			r >= '0' && r <= '9',
+			r == '.', r == '_', r == '-':
+		default:
+			return fmt.Errorf("Topic name '%s' is illegal, it contains a character other than ASCII alphanumerics, '.', '_' and '-'", t)
+		}
+	}
+	return nil
+}
+
+// collidingTopic returns an existing topic whose name is equal to t once
+// '.' is replaced with '_', which would collide in metric names.
+func (d *data) collidingTopic(t string) (string, bool) {
+	norm := strings.ReplaceAll(t, ".", "_")
+	for et := range d.tps {
+		if et != t && strings.ReplaceAll(et, ".", "_") == norm {
+			return et, true
+		}
+	}
+	return "", false
+}
+
+// validateNewTopic returns an error if t cannot be created because it is
+// invalid or collides with an existing topic.
+func (d *data) validateNewTopic(t string) error {
+	if err := validTopicName(t); err != nil {
+		return err
+	}
+	if et, ok := d.collidingTopic(t); ok {
+		return fmt.Errorf("Topic '%s' collides with existing topic: %s", t, et)
 	}
+	return nil
+}
+
+// deleteTopic removes t, remembering its ID so that requests using the stale
+// ID can be answered as Kafka does if the topic is recreated.
+func (d *data) deleteTopic(t string) {
+	id := d.t2id[t]
+	for _, pd := range d.tps[t] {
+		for watch := range pd.watch {
+			watch.deleted()
+		}
+	}
+	delete(d.tps, t)
+	delete(d.id2t, id)
+	delete(d.t2id, t)
+	d.c.metadataChanged()
+	if d.retired == nil {
+		d.retired = make(map[uuid]string)
+	}
+	d.retired[id] = t
+	d.c.emit(Event{Type: EventTopicDeleted, Topic: t})
+}
+
+// staleIDErr returns the error for a request using a topic ID that is not
+// current: INCONSISTENT_TOPIC_ID if the ID belonged to a topic that has since
+// been recreated under the same name, otherwise UNKNOWN_TOPIC_ID.
+func (d *data) staleIDErr(id uuid) int16 {
+	if t, ok := d.retired[id]; ok {
+		if _, exists := d.t2id[t]; exists {
+			return kerr.InconsistentTopicID.Code
+		}
+	}
+	return kerr.UnknownTopicID.Code
+}
+
+// markDeleting marks t as being deleted until the cluster's topic deletion
+// delay elapses, if the cluster deletes topics asynchronously.
+func (d *data) markDeleting(t string) {
+	delay := d.c.cfg.topicDeletionDelay
+	if delay <= 0 {
+		return
+	}
+	if d.deleting == nil {
+		d.deleting = make(map[string]time.Time)
+	}
+	d.deleting[t] = time.Now().Add(delay)
+}
+
+// isDeleting returns whether t is still being deleted asynchronously.
+func (d *data) isDeleting(t string) bool {
+	until, ok := d.deleting[t]
+	if ok && !time.Now().Before(until) {
+		delete(d.deleting, t)
+		return false
+	}
+	return ok
+}
+
+// topicPolicy returns the first policy whose pattern matches the topic, if
+// any. Patterns are validated in NewCluster.
+func (c *Cluster) topicPolicy(t string) *topicPolicy {
+	for i := range c.cfg.topicPolicies {
+		p := &c.cfg.topicPolicies[i]
+		if ok, _ := path.Match(p.pattern, t); ok {
+			return p
+		}
+	}
+	return nil
 }
 
 func (c *Cluster) noLeader() *broker {
@@ -107,8 +270,11 @@ func (c *Cluster) noLeader() *broker {
 	}
 }
 
-func (c *Cluster) newPartData() *partData {
+func (c *Cluster) newPartData(t string, p int32) *partData {
+	c.metadataChanged()
 	return &partData{
+		t:         t,
+		p:         p,
 		dir:       defLogDir,
 		leader:    c.bs[rand.Intn(len(c.bs))],
 		watch:     make(map[*watchFetch]struct{}),
@@ -125,41 +291,60 @@ func (pd *partData) pushBatch(nbytes int, b kmsg.RecordBatch) {
 	}
 	b.FirstOffset = pd.highWatermark
 	b.PartitionLeaderEpoch = pd.epoch
-	pd.batches = append(pd.batches, partBatch{b, nbytes, pd.epoch, maxEarlierTimestamp})
+	var raw []byte
+	if pd.leader.c.cfg.highThroughput {
+		raw = b.AppendTo(make([]byte, 0, int(b.Length)+12))
+	}
+	pd.batches = append(pd.batches, partBatch{b, nbytes, pd.epoch, maxEarlierTimestamp, raw})
 	pd.highWatermark += int64(b.NumRecords)
-	pd.lastStableOffset += int64(b.NumRecords) // TODO
+	pd.trackTxn(&b)
 	pd.nbytes += int64(nbytes)
 	for w := range pd.watch {
 		w.push(nbytes)
 	}
+	pd.leader.c.emit(Event{
+		Type:       EventBatchAppended,
+		Topic:      pd.t,
+		Partition:  pd.p,
+		Offset:     b.FirstOffset,
+		NumRecords: b.NumRecords,
+		Control:    b.Attributes&0x0020 != 0,
+	})
+}
+
+// appendTo appends the encoded batch to dst.
+func (b *partBatch) appendTo(dst []byte) []byte {
+	if b.raw != nil {
+		return append(dst, b.raw...)
+	}
+	return b.AppendTo(dst)
+}
+
+// reencode refreshes the encoded batch after the batch was modified.
+func (b *partBatch) reencode() {
+	if b.raw != nil {
+		b.raw = b.AppendTo(make([]byte, 0, int(b.Length)+12))
+	}
 }
 
 func (pd *partData) searchOffset(o int64) (index int, found bool, atEnd bool) {
 	if o < pd.logStartOffset || o > pd.highWatermark {
 		return 0, false, false
 	}
-	if len(pd.batches) == 0 {
-		if o == 0 {
-			return 0, false, true
-		}
-	} else {
-		lastBatch := pd.batches[len(pd.batches)-1]
-		if end := lastBatch.FirstOffset + int64(lastBatch.LastOffsetDelta) + 1; end == o {
-			return 0, false, true
-		}
+	if o == pd.highWatermark {
+		return 0, false, true
 	}
 
-	index, found = sort.Find(len(pd.batches), func(idx int) int {
+	// Compaction can leave gaps in the log: if o was compacted away, we
+	// return the first batch after it, as Kafka does.
+	index = sort.Search(len(pd.batches), func(idx int) bool {
 		b := &pd.batches[idx]
-		if o < b.FirstOffset {
-			return -1
-		}
-		if o >= b.FirstOffset+int64(b.LastOffsetDelta)+1 {
-			return 1
-		}
-		return 0
+		return o < b.FirstOffset+int64(b.LastOffsetDelta)+1
 	})
-	return index, found, false
+	if index == len(pd.batches) {
+		return 0, false, true
+	}
+	return index, true, false
 }
 
 func (pd *partData) trimLeft() {
@@ -172,6 +357,9 @@ func (pd *partData) trimLeft() {
 		pd.batches = pd.batches[1:]
 		pd.nbytes -= int64(b0.nbytes)
 	}
+	for len(pd.abortedTxns) > 0 && pd.abortedTxns[0].last < pd.logStartOffset {
+		pd.abortedTxns = pd.abortedTxns[1:]
+	}
 }
 
 /////////////
@@ -185,11 +373,15 @@ func (pd *partData) trimLeft() {
 //   - default configs
 //   - dynamic broker configs
 func (c *Cluster) brokerConfigs(node int32, fn func(k string, v *string, src kmsg.ConfigSource, sensitive bool)) {
+	rack := "krack"
 	if node >= 0 {
 		for _, b := range c.bs {
 			if b.node == node {
 				id := strconv.Itoa(int(node))
 				fn("broker.id", &id, kmsg.ConfigSourceStaticBrokerConfig, false)
+				if b.rack != nil {
+					rack = *b.rack
+				}
 				break
 			}
 		}
@@ -199,7 +391,7 @@ func (c *Cluster) brokerConfigs(node int32, fn func(k string, v *string, src kms
 		v    string
 		sens bool
 	}{
-		{k: "broker.rack", v: "krack"},
+		{k: "broker.rack", v: rack},
 		{k: "sasl.enabled.mechanisms", v: "PLAIN,SCRAM-SHA-256,SCRAM-SHA-512"},
 		{k: "super.users", sens: true},
 	} {
@@ -243,77 +435,233 @@ func (d *data) configs(t string, fn func(k string, v *string, src kmsg.ConfigSou
 	}
 }
 
-// Unlike Kafka, we validate the value before allowing it to be set.
-func (c *Cluster) setBrokerConfig(k string, v *string, dry bool) bool {
-	if dry {
-		return true
+// Unlike Kafka, we validate the value before allowing it to be set. If dry,
+// this only validates.
+func (c *Cluster) setBrokerConfig(k string, v *string, dry bool) error {
+	if err := checkBrokerConfig(k, v, true); err != nil || dry {
+		return err
 	}
 	c.bcfgs[k] = v
-	return true
+	return nil
 }
 
-func (d *data) setTopicConfig(t string, k string, v *string, dry bool) bool {
-	if dry {
-		return true
+// brokerConfigInt returns the dynamic broker config value for k if it is set
+// and a valid integer, otherwise the default.
+func (c *Cluster) brokerConfigInt(k string) int64 {
+	if v, ok := c.bcfgs[k]; ok && v != nil {
+		if i, err := strconv.ParseInt(*v, 10, 64); err == nil {
+			return i
+		}
+	}
+	i, _ := strconv.ParseInt(configDefaults[k], 10, 64)
+	return i
+}
+
+// config returns the value of topic config k for topic t: the dynamic topic
+// config if set, otherwise the equivalent dynamic broker config if set,
+// otherwise the default.
+func (d *data) config(t, k string) string {
+	if v, ok := d.tcfgs[t][k]; ok && v != nil {
+		return *v
+	}
+	if bk := validTopicConfigs[k]; bk != "" {
+		if v, ok := d.c.bcfgs[bk]; ok && v != nil {
+			return *v
+		}
+	}
+	return configDefaults[k]
+}
+
+// configInt returns the integer value of topic config k for topic t, or the
+// default if the config is not a valid integer.
+func (d *data) configInt(t, k string) int64 {
+	i, err := strconv.ParseInt(d.config(t, k), 10, 64)
+	if err != nil {
+		i, _ = strconv.ParseInt(configDefaults[k], 10, 64)
+	}
+	return i
+}
+
+// setTopicConfig validates and sets a dynamic topic config. If dry, this only
+// validates.
+func (d *data) setTopicConfig(t string, k string, v *string, dry bool) error {
+	if err := checkTopicConfig(k, v, true); err != nil || dry {
+		return err
 	}
 	if _, ok := d.tcfgs[t]; !ok {
 		d.tcfgs[t] = make(map[string]*string)
 	}
 	d.tcfgs[t][k] = v
-	return true
+	return nil
+}
+
+// checkTopicConfig returns an INVALID_CONFIG error if k is not a topic config
+// we support or, if set, v is not a valid value for k.
+func checkTopicConfig(k string, v *string, set bool) error {
+	if _, ok := validTopicConfigs[k]; !ok {
+		return configErrf(kerr.InvalidConfig, "unknown topic config %q", k)
+	}
+	return checkConfigValue(k, v, set)
+}
+
+// checkBrokerConfig is checkTopicConfig for broker configs, additionally
+// returning an INVALID_REQUEST error if k cannot be altered dynamically.
+func checkBrokerConfig(k string, v *string, set bool) error {
+	if _, ok := validBrokerConfigs[k]; !ok {
+		return configErrf(kerr.InvalidConfig, "unknown broker config %q", k)
+	}
+	if readOnlyBrokerConfigs[k] {
+		return configErrf(kerr.InvalidRequest, "cannot update read-only broker config %q dynamically", k)
+	}
+	return checkConfigValue(k, v, set)
+}
+
+func checkConfigValue(k string, v *string, set bool) error {
+	if !set {
+		return nil
+	}
+	if valid, ok := configValidators[k]; ok && !valid(v) {
+		val := "null"
+		if v != nil {
+			val = *v
+		}
+		return configErrf(kerr.InvalidConfig, "invalid value %s for config %q", val, k)
+	}
+	return nil
+}
+
+// configErr is a config validation error: the message is returned as the
+// response's error message alongside the error code.
+type configErr struct {
+	code *kerr.Error
+	msg  string
+}
+
+func configErrf(code *kerr.Error, format string, args ...any) error {
+	return &configErr{code, fmt.Sprintf(format, args...)}
+}
+
+func (e *configErr) Error() string { return e.msg }
+func (e *configErr) Unwrap() error { return e.code }
+
+// configErrCode returns the error code for a config validation error.
+func configErrCode(err error) int16 {
+	var ke *kerr.Error
+	if errors.As(err, &ke) {
+		return ke.Code
+	}
+	return kerr.InvalidConfig.Code
 }
 
 // All valid topic configs we support, as well as the equivalent broker
 // config if there is one.
 var validTopicConfigs = map[string]string{
-	"cleanup.policy":         "",
-	"compression.type":       "compression.type",
-	"max.message.bytes":      "log.message.max.bytes",
-	"message.timestamp.type": "log.message.timestamp.type",
-	"min.insync.replicas":    "min.insync.replicas",
-	"retention.bytes":        "log.retention.bytes",
-	"retention.ms":           "log.retention.ms",
+	"cleanup.policy":                      "",
+	"compression.type":                    "compression.type",
+	"delete.retention.ms":                 "log.cleaner.delete.retention.ms",
+	"max.message.bytes":                   "log.message.max.bytes",
+	"message.timestamp.after.max.ms":      "log.message.timestamp.after.max.ms",
+	"message.timestamp.before.max.ms":     "log.message.timestamp.before.max.ms",
+	"message.timestamp.difference.max.ms": "log.message.timestamp.difference.max.ms",
+	"message.timestamp.type":              "log.message.timestamp.type",
+	"min.insync.replicas":                 "min.insync.replicas",
+	"retention.bytes":                     "log.retention.bytes",
+	"retention.ms":                        "log.retention.ms",
 }
 
 // All valid broker configs we support, as well as their equivalent
 // topic config if there is one.
 var validBrokerConfigs = map[string]string{
-	"broker.id":                  "",
-	"broker.rack":                "",
-	"compression.type":           "compression.type",
-	"default.replication.factor": "",
-	"fetch.max.bytes":            "",
-	"log.dir":                    "",
-	"log.message.timestamp.type": "message.timestamp.type",
-	"log.retention.bytes":        "retention.bytes",
-	"log.retention.ms":           "retention.ms",
-	"message.max.bytes":          "max.message.bytes",
-	"min.insync.replicas":        "min.insync.replicas",
-	"sasl.enabled.mechanisms":    "",
-	"super.users":                "",
+	"broker.id":                               "",
+	"broker.rack":                             "",
+	"compression.type":                        "compression.type",
+	"default.replication.factor":              "",
+	"fetch.max.bytes":                         "",
+	"group.share.delivery.count.limit":        "",
+	"group.share.record.lock.duration.ms":     "",
+	"log.cleaner.delete.retention.ms":         "delete.retention.ms",
+	"log.dir":                                 "",
+	"log.message.timestamp.after.max.ms":      "message.timestamp.after.max.ms",
+	"log.message.timestamp.before.max.ms":     "message.timestamp.before.max.ms",
+	"log.message.timestamp.difference.max.ms": "message.timestamp.difference.max.ms",
+	"log.message.timestamp.type":              "message.timestamp.type",
+	"log.retention.bytes":                     "retention.bytes",
+	"log.retention.ms":                        "retention.ms",
+	"message.max.bytes":                       "max.message.bytes",
+	"min.insync.replicas":                     "min.insync.replicas",
+	"sasl.enabled.mechanisms":                 "",
+	"super.users":                             "",
 }
 
 // Default topic and broker configs.
 var configDefaults = map[string]string{
-	"cleanup.policy":         "delete",
-	"compression.type":       "producer",
-	"max.message.bytes":      "1048588",
-	"message.timestamp.type": "CreateTime",
-	"min.insync.replicas":    "1",
-	"retention.bytes":        "-1",
-	"retention.ms":           "604800000",
-
-	"default.replication.factor": "3",
-	"fetch.max.bytes":            "57671680",
-	"log.dir":                    defLogDir,
-	"log.message.timestamp.type": "CreateTime",
-	"log.retention.bytes":        "-1",
-	"log.retention.ms":           "604800000",
-	"message.max.bytes":          "1048588",
+	"cleanup.policy":                      "delete",
+	"compression.type":                    "producer",
+	"delete.retention.ms":                 "86400000",
+	"max.message.bytes":                   "1048588",
+	"message.timestamp.after.max.ms":      "9223372036854775807",
+	"message.timestamp.before.max.ms":     "9223372036854775807",
+	"message.timestamp.difference.max.ms": "9223372036854775807",
+	"message.timestamp.type":              "CreateTime",
+	"min.insync.replicas":                 "1",
+	"retention.bytes":                     "-1",
+	"retention.ms":                        "604800000",
+
+	"default.replication.factor":              "3",
+	"fetch.max.bytes":                         "57671680",
+	"group.share.delivery.count.limit":        "5",
+	"group.share.record.lock.duration.ms":     "30000",
+	"log.cleaner.delete.retention.ms":         "86400000",
+	"log.dir":                                 defLogDir,
+	"log.message.timestamp.after.max.ms":      "9223372036854775807",
+	"log.message.timestamp.before.max.ms":     "9223372036854775807",
+	"log.message.timestamp.difference.max.ms": "9223372036854775807",
+	"log.message.timestamp.type":              "CreateTime",
+	"log.retention.bytes":                     "-1",
+	"log.retention.ms":                        "604800000",
+	"message.max.bytes":                       "1048588",
 }
 
 const defLogDir = "/mem/kfake"
 
+// Static broker configs, which are only reported by DescribeConfigs.
+var readOnlyBrokerConfigs = map[string]bool{
+	"broker.id":               true,
+	"broker.rack":             true,
+	"log.dir":                 true,
+	"sasl.enabled.mechanisms": true,
+	"super.users":             true,
+}
+
+// Validators for config values; a config with no validator accepts any
+// value.
+var configValidators = map[string]func(*string) bool{
+	"cleanup.policy":                      staticConfig("delete", "compact", "compact,delete", "delete,compact"),
+	"compression.type":                    staticConfig("producer", "uncompressed", "gzip", "snappy", "lz4", "zstd"),
+	"delete.retention.ms":                 numberConfig(0, true, 0, false),
+	"max.message.bytes":                   numberConfig(0, true, 0, false),
+	"message.timestamp.after.max.ms":      numberConfig(0, true, 0, false),
+	"message.timestamp.before.max.ms":     numberConfig(0, true, 0, false),
+	"message.timestamp.difference.max.ms": numberConfig(0, true, 0, false),
+	"message.timestamp.type":              staticConfig("CreateTime", "LogAppendTime"),
+	"min.insync.replicas":                 numberConfig(1, true, 0, false),
+	"retention.bytes":                     numberConfig(-1, true, 0, false),
+	"retention.ms":                        numberConfig(-1, true, 0, false),
+
+	"default.replication.factor":              numberConfig(1, true, 0, false),
+	"fetch.max.bytes":                         numberConfig(0, true, 0, false),
+	"group.share.delivery.count.limit":        numberConfig(2, true, 10, true),
+	"group.share.record.lock.duration.ms":     numberConfig(1000, true, 3600000, true),
+	"log.cleaner.delete.retention.ms":         numberConfig(0, true, 0, false),
+	"log.message.timestamp.after.max.ms":      numberConfig(0, true, 0, false),
+	"log.message.timestamp.before.max.ms":     numberConfig(0, true, 0, false),
+	"log.message.timestamp.difference.max.ms": numberConfig(0, true, 0, false),
+	"log.message.timestamp.type":              staticConfig("CreateTime", "LogAppendTime"),
+	"log.retention.bytes":                     numberConfig(-1, true, 0, false),
+	"log.retention.ms":                        numberConfig(-1, true, 0, false),
+	"message.max.bytes":                       numberConfig(0, true, 0, false),
+}
+
 func staticConfig(s ...string) func(*string) bool {
 	return func(v *string) bool {
 		if v == nil {
@@ -1,6 +1,18 @@
 package kfake
 
-import "testing"
+import (
+	"context"
+	"encoding/binary"
+	"hash/crc32"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
 
 func TestConfigDefaults(t *testing.T) {
 	exceptions := map[string]struct{}{
@@ -17,3 +29,124 @@ func TestConfigDefaults(t *testing.T) {
 		}
 	}
 }
+
+func TestHighThroughputBatches(t *testing.T) {
+	for _, highThroughput := range []bool{false, true} {
+		opts := []Opt{NumBrokers(1)}
+		if highThroughput {
+			opts = append(opts, HighThroughput())
+		}
+		c := newTestCluster(t, opts...)
+		cl := newTestClient(t, c)
+		adm := newTestAdmin(t, c)
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		compact := "compact"
+		created, err := adm.CreateTopic(ctx, 1, 1, map[string]*string{"cleanup.policy": &compact}, "t")
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Compaction rewrites the first batch and drops nothing else.
+		for _, rs := range [][]*kgo.Record{
+			{kgo.KeyStringRecord("a", "1"), kgo.KeyStringRecord("b", "1")},
+			{kgo.KeyStringRecord("a", "2")},
+			{kgo.KeyStringRecord("c", "1")},
+		} {
+			if _, err := c.ProduceTo("t", 0, rs...); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		check := func(why string, from int64, exp ...fetchedRecord) {
+			t.Helper()
+			if got := fetchRecords(ctx, t, cl, created.ID, from); !reflect.DeepEqual(got, exp) {
+				t.Errorf("high throughput %v, %s: got %v, expected %v", highThroughput, why, got, exp)
+			}
+		}
+
+		if err := c.Compact("t"); err != nil {
+			t.Fatal(err)
+		}
+		check("compacted", 0,
+			fetchedRecord{1, "b", "1", true},
+			fetchedRecord{2, "a", "2", true},
+			fetchedRecord{3, "c", "1", true},
+		)
+
+		rs := kadm.Offsets{}
+		rs.Add(kadm.Offset{Topic: "t", Partition: 0, At: 2})
+		if _, err := adm.DeleteRecords(ctx, rs); err != nil {
+			t.Fatal(err)
+		}
+		check("deleted records", 2,
+			fetchedRecord{2, "a", "2", true},
+			fetchedRecord{3, "c", "1", true},
+		)
+
+		c.DropFetchOffset("t", 0, 2)
+		c.CorruptFetchOffset("t", 0, 3)
+		check("fetch faults", 2,
+			fetchedRecord{3, "c", "1", false},
+		)
+		c.ClearFetchFaults()
+		check("cleared fetch faults", 2,
+			fetchedRecord{2, "a", "2", true},
+			fetchedRecord{3, "c", "1", true},
+		)
+	}
+}
+
+type fetchedRecord struct {
+	offset     int64
+	key, value string
+	crcOK      bool
+}
+
+// fetchRecords fetches partition 0 of the topic from the given offset and
+// decodes every record in the response, noting whether its batch's CRC is
+// valid.
+func fetchRecords(ctx context.Context, t *testing.T, cl *kgo.Client, id [16]byte, from int64) []fetchedRecord {
+	t.Helper()
+	req := kmsg.NewPtrFetchRequest()
+	req.MaxWaitMillis = 0
+	req.MaxBytes = 1 << 20
+	rt := kmsg.NewFetchRequestTopic()
+	rt.TopicID = id
+	rp := kmsg.NewFetchRequestTopicPartition()
+	rp.FetchOffset = from
+	rp.PartitionMaxBytes = 1 << 20
+	rt.Partitions = append(rt.Partitions, rp)
+	req.Topics = append(req.Topics, rt)
+	resp, err := req.RequestWith(ctx, cl.Broker(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := kerr.ErrorForCode(resp.Topics[0].Partitions[0].ErrorCode); err != nil {
+		t.Fatal(err)
+	}
+
+	var rs []fetchedRecord
+	raw := resp.Topics[0].Partitions[0].RecordBatches
+	for len(raw) > 0 {
+		var b kmsg.RecordBatch
+		if err := b.ReadFrom(raw); err != nil {
+			t.Fatal(err)
+		}
+		n := int(b.Length) + 12
+		crcOK := int32(crc32.Checksum(raw[21:n], crc32c)) == b.CRC
+		raw = raw[n:]
+
+		records := b.Records
+		for i := int32(0); i < b.NumRecords; i++ {
+			length, n := binary.Varint(records)
+			var r kmsg.Record
+			if err := r.ReadFrom(records[:n+int(length)]); err != nil {
+				t.Fatal(err)
+			}
+			records = records[n+int(length):]
+			rs = append(rs, fetchedRecord{b.FirstOffset + int64(r.OffsetDelta), string(r.Key), string(r.Value), crcOK})
+		}
+	}
+	return rs
+}
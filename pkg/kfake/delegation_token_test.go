@@ -0,0 +1,54 @@
+package kfake
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+	"github.com/twmb/franz-go/pkg/sasl/scram"
+)
+
+func TestDelegationTokenAuth(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(1), EnableSASL(), Superuser("SCRAM-SHA-256", "admin", "pw"))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	admin := newTestClient(t, c, kgo.SASL(scram.Auth{User: "admin", Pass: "pw"}.AsSha256Mechanism())).Broker(0)
+	resp, err := kmsg.NewPtrCreateDelegationTokenRequest().RequestWith(ctx, admin)
+	if err == nil {
+		err = kerr.ErrorForCode(resp.ErrorCode)
+	}
+	if err != nil {
+		t.Fatalf("create delegation token: %v", err)
+	}
+	if resp.PrincipalName != "admin" || resp.TokenRequesterPrincipalName != "admin" {
+		t.Errorf("got token owner %s and requester %s, expected admin", resp.PrincipalName, resp.TokenRequesterPrincipalName)
+	}
+
+	// The token authenticates with either SCRAM mechanism, but cannot be
+	// used to create more tokens.
+	token := scram.Auth{User: resp.TokenID, Pass: base64.StdEncoding.EncodeToString(resp.HMAC), IsToken: true}
+	for _, br := range []*kgo.Broker{
+		newTestClient(t, c, kgo.SASL(token.AsSha256Mechanism())).Broker(0),
+		newTestClient(t, c, kgo.SASL(token.AsSha512Mechanism())).Broker(0),
+	} {
+		resp, err := kmsg.NewPtrCreateDelegationTokenRequest().RequestWith(ctx, br)
+		if err != nil {
+			t.Fatalf("authenticating with the token: %v", err)
+		}
+		if resp.ErrorCode != kerr.DelegationTokenRequestNotAllowed.Code {
+			t.Errorf("creating a token with a token: got %v, expected DELEGATION_TOKEN_REQUEST_NOT_ALLOWED", kerr.ErrorForCode(resp.ErrorCode))
+		}
+	}
+
+	// Without the tokenauth extension, the token ID is not a user.
+	token.IsToken = false
+	br := newTestClient(t, c, kgo.SASL(token.AsSha256Mechanism()), kgo.RequestRetries(0)).Broker(0)
+	if _, err := kmsg.NewPtrMetadataRequest().RequestWith(ctx, br); err == nil {
+		t.Error("authenticating with a token ID as a user succeeded")
+	}
+}
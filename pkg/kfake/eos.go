@@ -0,0 +1,153 @@
+package kfake
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// When exactly once tracking is enabled, we remember the producer ID, epoch,
+// sequence, and transactional attributes of every batch we accept. At the end
+// of a test, VerifyExactlyOnce checks that the accepted batches could only
+// have been produced by correct idempotent / transactional producers:
+//
+// * Per partition, a producer ID never goes back to an older epoch
+// * Per partition and epoch, sequence numbers start at 0 and have no gaps or
+//   duplicates
+// * Every transaction is eventually committed or aborted
+//
+// We only track metadata, not record contents, so memory stays small even
+// for large tests.
+
+type (
+	eos struct {
+		batches []eosBatch
+	}
+
+	eosBatch struct {
+		t      string
+		p      int32
+		offset int64
+
+		pid   int64
+		epoch int16
+		seq   int32
+		n     int32
+
+		txnal   bool
+		control bool
+		commit  bool // for control batches, whether this is a commit marker
+	}
+
+	eosKey struct {
+		pid int64
+		t   string
+		p   int32
+	}
+
+	eosState struct {
+		epoch   int16
+		next    int32
+		openTxn int64 // offset of the first batch in an open transaction, or -1
+	}
+)
+
+// eosRecord tracks a batch that was just appended at the given offset. This is
+// a no-op if tracking is disabled.
+func (c *Cluster) eosRecord(t string, p int32, offset int64, b *kmsg.RecordBatch) {
+	if !c.cfg.trackEOS {
+		return
+	}
+	attrs := uint16(b.Attributes)
+	eb := eosBatch{
+		t:       t,
+		p:       p,
+		offset:  offset,
+		pid:     b.ProducerID,
+		epoch:   b.ProducerEpoch,
+		seq:     b.FirstSequence,
+		n:       b.NumRecords,
+		txnal:   attrs&0x0010 != 0,
+		control: attrs&0x0020 != 0,
+	}
+	if eb.control {
+		var r kmsg.Record
+		if err := r.ReadFrom(b.Records); err == nil && len(r.Key) >= 4 {
+			eb.commit = binary.BigEndian.Uint16(r.Key[2:]) == 1
+		}
+	}
+	c.eos.batches = append(c.eos.batches, eb)
+}
+
+func (c *Cluster) verifyEOS() error {
+	var (
+		errs   []error
+		states = make(map[eosKey]*eosState)
+	)
+	for _, b := range c.eos.batches {
+		if b.pid < 0 {
+			continue
+		}
+		k := eosKey{b.pid, b.t, b.p}
+		s := states[k]
+
+		if b.control {
+			if s == nil || s.openTxn < 0 {
+				continue // Kafka can write markers for partitions with no data, e.g. when aborting on epoch bump
+			}
+			s.openTxn = -1
+			continue
+		}
+
+		switch {
+		case s == nil:
+			s = &eosState{epoch: b.epoch, openTxn: -1}
+			states[k] = s
+			if b.seq != 0 {
+				errs = append(errs, fmt.Errorf("%s[%d] offset %d: producer %d epoch %d started at sequence %d, not 0", b.t, b.p, b.offset, b.pid, b.epoch, b.seq))
+			}
+		case b.epoch < s.epoch:
+			errs = append(errs, fmt.Errorf("%s[%d] offset %d: producer %d accepted epoch %d after fenced by epoch %d", b.t, b.p, b.offset, b.pid, b.epoch, s.epoch))
+			continue
+		case b.epoch > s.epoch:
+			s.epoch = b.epoch
+			if b.seq != 0 {
+				errs = append(errs, fmt.Errorf("%s[%d] offset %d: producer %d epoch %d started at sequence %d, not 0", b.t, b.p, b.offset, b.pid, b.epoch, b.seq))
+			}
+		case b.seq < s.next:
+			errs = append(errs, fmt.Errorf("%s[%d] offset %d: producer %d epoch %d duplicate sequence %d, expected %d", b.t, b.p, b.offset, b.pid, b.epoch, b.seq, s.next))
+		case b.seq > s.next:
+			errs = append(errs, fmt.Errorf("%s[%d] offset %d: producer %d epoch %d sequence gap, got %d, expected %d", b.t, b.p, b.offset, b.pid, b.epoch, b.seq, s.next))
+		}
+		s.next = int32((int64(b.seq) + int64(b.n)) % math.MaxInt32)
+		if b.txnal && s.openTxn < 0 {
+			s.openTxn = b.offset
+		}
+	}
+	for k, s := range states {
+		if s.openTxn >= 0 {
+			errs = append(errs, fmt.Errorf("%s[%d]: producer %d transaction starting at offset %d was never committed nor aborted", k.t, k.p, k.pid, s.openTxn))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// VerifyExactlyOnce checks every batch the cluster accepted since it started
+// for idempotent and transactional correctness, returning an error describing
+// every violation found. This requires the cluster to be created with the
+// TrackExactlyOnce option.
+//
+// A violation is a producer ID going back to an older epoch, a sequence
+// number gap or duplicate within a producer epoch, or a transaction that was
+// never ended.
+func (c *Cluster) VerifyExactlyOnce() error {
+	if !c.cfg.trackEOS {
+		return errors.New("exactly once tracking is not enabled")
+	}
+	var err error
+	c.admin(func() { err = c.verifyEOS() })
+	return err
+}
@@ -0,0 +1,88 @@
+package kfake
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestVerifyExactlyOnce(t *testing.T) {
+	if err := newTestCluster(t, NumBrokers(1)).VerifyExactlyOnce(); err == nil {
+		t.Error("VerifyExactlyOnce without tracking did not fail")
+	}
+
+	c := newTestCluster(t, NumBrokers(1), SeedTopics(1, "t"), TrackExactlyOnce())
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Correct idempotent and transactional producers, including a
+	// transactional client restarting, pass verification.
+	idem := newTestClient(t, c, kgo.DefaultProduceTopic("t"))
+	for i := 0; i < 3; i++ {
+		if err := idem.ProduceSync(ctx, kgo.StringRecord("v")).FirstErr(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		txn := newTestClient(t, c, kgo.TransactionalID("tx"), kgo.DefaultProduceTopic("t"))
+		if err := txn.BeginTransaction(); err != nil {
+			t.Fatal(err)
+		}
+		if err := txn.ProduceSync(ctx, kgo.StringRecord("v"), kgo.StringRecord("v")).FirstErr(); err != nil {
+			t.Fatal(err)
+		}
+		if err := txn.EndTransaction(ctx, kgo.TryCommit); err != nil {
+			t.Fatal(err)
+		}
+		txn.Close()
+	}
+	if err := c.VerifyExactlyOnce(); err != nil {
+		t.Fatalf("correct producers failed verification: %v", err)
+	}
+
+	// Produce validation would reject these, so we append directly.
+	appendBatch := func(pid int64, epoch int16, seq int32, txnal bool) {
+		t.Helper()
+		b := kmsg.RecordBatch{
+			ProducerID:    pid,
+			ProducerEpoch: epoch,
+			FirstSequence: seq,
+			NumRecords:    1,
+		}
+		if txnal {
+			b.Attributes = 0x0010
+		}
+		if _, err := c.AppendRecordBatch("t", 0, b); err != nil {
+			t.Fatal(err)
+		}
+	}
+	appendBatch(1000, 1, 0, false)
+	appendBatch(1000, 1, 0, false) // duplicate
+	appendBatch(1000, 1, 3, false) // gap
+	appendBatch(1000, 0, 4, false) // fenced epoch
+	appendBatch(1001, 0, 1, false) // does not start at 0
+	appendBatch(1002, 0, 0, true)  // never ended
+
+	err := c.VerifyExactlyOnce()
+	if err == nil {
+		t.Fatal("violations passed verification")
+	}
+	for _, exp := range []string{
+		"producer 1000 epoch 1 duplicate sequence 0",
+		"producer 1000 epoch 1 sequence gap, got 3, expected 1",
+		"producer 1000 accepted epoch 0 after fenced by epoch 1",
+		"producer 1001 epoch 0 started at sequence 1",
+		"producer 1002 transaction starting at offset",
+	} {
+		if !strings.Contains(err.Error(), exp) {
+			t.Errorf("verification error is missing %q: %v", exp, err)
+		}
+	}
+	if n := strings.Count(err.Error(), "\n") + 1; n != 5 {
+		t.Errorf("got %d violations, expected 5: %v", n, err)
+	}
+}
@@ -0,0 +1,198 @@
+package kfake
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Tests can watch for cluster events rather than polling the cluster or
+// sleeping until it reaches some state. Events are emitted from the cluster
+// run loop and from group goroutines as state changes, and are queued per
+// watcher so that a slow watcher never blocks the cluster.
+
+// EventType is a type of cluster event.
+type EventType int8
+
+const (
+	// EventTopicCreated is emitted when a topic is created, including
+	// auto created and mirrored topics.
+	EventTopicCreated EventType = iota + 1
+	// EventTopicDeleted is emitted when a topic is deleted.
+	EventTopicDeleted
+	// EventLeaderChanged is emitted when a partition's leader changes.
+	EventLeaderChanged
+	// EventBatchAppended is emitted when a record batch is appended to a
+	// partition, including transaction control batches.
+	EventBatchAppended
+	// EventGroupRebalanced is emitted when a classic group completes a
+	// rebalance: the leader synced and the group is stable.
+	EventGroupRebalanced
+	// EventOffsetsCommitted is emitted for every partition offset a group
+	// commits, including transactional commits once the transaction
+	// commits.
+	EventOffsetsCommitted
+)
+
+func (t EventType) String() string {
+	switch t {
+	case EventTopicCreated:
+		return "TopicCreated"
+	case EventTopicDeleted:
+		return "TopicDeleted"
+	case EventLeaderChanged:
+		return "LeaderChanged"
+	case EventBatchAppended:
+		return "BatchAppended"
+	case EventGroupRebalanced:
+		return "GroupRebalanced"
+	case EventOffsetsCommitted:
+		return "OffsetsCommitted"
+	default:
+		return fmt.Sprintf("EventType(%d)", int8(t))
+	}
+}
+
+// Event is a cluster event. Fields that do not apply to the event's type are
+// zero.
+type Event struct {
+	Type EventType
+
+	Topic     string // Topic is the topic for every event type but EventGroupRebalanced.
+	Partition int32  // Partition is the partition for leader, batch, and commit events.
+
+	Leader      int32 // Leader is the new leader for EventLeaderChanged.
+	LeaderEpoch int32 // LeaderEpoch is the new leader epoch for EventLeaderChanged.
+
+	Offset     int64 // Offset is the batch's first offset for EventBatchAppended, or the committed offset for EventOffsetsCommitted.
+	NumRecords int32 // NumRecords is the number of records in the batch for EventBatchAppended.
+	Control    bool  // Control is whether the batch is a transaction control batch for EventBatchAppended.
+
+	Group      string // Group is the group for group and commit events.
+	Generation int32  // Generation is the generation the group rebalanced to for EventGroupRebalanced.
+}
+
+// maxWatchQueue is the number of events queued for a watcher that is not
+// receiving; further events are dropped until the watcher catches up.
+const maxWatchQueue = 10000
+
+// Watcher receives cluster events; see Watch.
+type Watcher struct {
+	// C receives events. C is closed once the watcher is stopped or the
+	// cluster is closed.
+	C <-chan Event
+
+	c    *Cluster
+	mask uint64 // bit per watched EventType
+
+	mu      sync.Mutex
+	queue   []Event
+	wake    chan struct{}
+	dropped int64
+
+	stopOnce sync.Once
+	stop     chan struct{}
+}
+
+// Watch returns a watcher that receives every event of the given types, or
+// of all types if none are given, in the order the events happened. Up to
+// 10,000 events are queued until received; events that happen while the
+// queue is full are dropped and counted in Dropped. The watcher must be
+// stopped when no longer needed, otherwise it is only cleaned up when the
+// cluster is closed.
+func (c *Cluster) Watch(events ...EventType) *Watcher {
+	ch := make(chan Event)
+	w := &Watcher{
+		C:    ch,
+		c:    c,
+		wake: make(chan struct{}, 1),
+		stop: make(chan struct{}),
+	}
+	if len(events) == 0 {
+		w.mask = ^uint64(0)
+	}
+	for _, t := range events {
+		w.mask |= 1 << uint(t)
+	}
+	c.watchMu.Lock()
+	c.watchers = append(c.watchers, w)
+	c.watchMu.Unlock()
+	go w.pump(ch)
+	return w
+}
+
+// Stop stops the watcher, closing C and dropping any events that were not
+// yet received. Stop can be called multiple times.
+func (w *Watcher) Stop() {
+	w.stopOnce.Do(func() {
+		c := w.c
+		c.watchMu.Lock()
+		for i, other := range c.watchers {
+			if other == w {
+				c.watchers = append(c.watchers[:i], c.watchers[i+1:]...)
+				break
+			}
+		}
+		c.watchMu.Unlock()
+		close(w.stop)
+	})
+}
+
+// Dropped returns the number of events that were dropped because the
+// watcher's queue was full.
+func (w *Watcher) Dropped() int64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.dropped
+}
+
+func (w *Watcher) pump(ch chan Event) {
+	defer close(ch)
+	for {
+		w.mu.Lock()
+		if len(w.queue) == 0 {
+			w.mu.Unlock()
+			select {
+			case <-w.wake:
+				continue
+			case <-w.stop:
+				return
+			case <-w.c.die:
+				return
+			}
+		}
+		ev := w.queue[0]
+		w.queue = w.queue[1:]
+		w.mu.Unlock()
+
+		select {
+		case ch <- ev:
+		case <-w.stop:
+			return
+		case <-w.c.die:
+			return
+		}
+	}
+}
+
+// emit queues ev to every watcher of its type.
+func (c *Cluster) emit(ev Event) {
+	c.watchMu.Lock()
+	defer c.watchMu.Unlock()
+	for _, w := range c.watchers {
+		if w.mask&(1<<uint(ev.Type)) == 0 {
+			continue
+		}
+		w.mu.Lock()
+		if len(w.queue) >= maxWatchQueue {
+			w.dropped++
+			w.mu.Unlock()
+			continue
+		}
+		w.queue = append(w.queue, ev)
+		w.mu.Unlock()
+		select {
+		case w.wake <- struct{}{}:
+		default:
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package kfake
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestWatch(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(1))
+	w := c.Watch(EventTopicCreated, EventBatchAppended)
+	defer w.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := newTestAdmin(t, c).CreateTopic(ctx, 1, 1, nil, "t"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.ProduceTo("t", 0, kgo.StringRecord("a"), kgo.StringRecord("b")); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, exp := range []Event{
+		{Type: EventTopicCreated, Topic: "t"},
+		{Type: EventBatchAppended, Topic: "t", NumRecords: 2},
+	} {
+		select {
+		case ev := <-w.C:
+			if ev != exp {
+				t.Errorf("got event %+v, expected %+v", ev, exp)
+			}
+		case <-ctx.Done():
+			t.Fatalf("timed out waiting for %v", exp.Type)
+		}
+	}
+
+	w.Stop()
+	w.Stop() // stopping twice is fine
+	select {
+	case ev, ok := <-w.C:
+		if ok {
+			t.Fatalf("got event %+v after Stop", ev)
+		}
+	case <-ctx.Done():
+		t.Fatal("C was not closed after Stop")
+	}
+	c.watchMu.Lock()
+	n := len(c.watchers)
+	c.watchMu.Unlock()
+	if n != 0 {
+		t.Errorf("got %d watchers after Stop, expected 0", n)
+	}
+}
+
+func TestWatchDropsWhenFull(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(1), SeedTopics(1, "t"))
+	w := c.Watch(EventBatchAppended)
+	defer w.Stop()
+
+	// The watcher may take one event off the queue to send before we
+	// fill it, so one more than the queue size may be kept.
+	const extra = 5
+	for i := 0; i < maxWatchQueue+extra; i++ {
+		c.emit(Event{Type: EventBatchAppended, Topic: "t", Offset: int64(i)})
+	}
+	if dropped := w.Dropped(); dropped != extra && dropped != extra-1 {
+		t.Fatalf("got %d dropped events, expected %d or %d", dropped, extra, extra-1)
+	}
+
+	// Events are still received in order.
+	for i := int64(0); i < 3; i++ {
+		if ev := <-w.C; ev.Offset != i {
+			t.Fatalf("got offset %d, expected %d", ev.Offset, i)
+		}
+	}
+}
+
+func TestWatchEvents(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(2), SeedTopics(1, "t"))
+	w := c.Watch(EventTopicDeleted, EventLeaderChanged, EventGroupRebalanced, EventOffsetsCommitted)
+	defer w.Stop()
+	cl := newTestClient(t, c)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var leader int32
+	c.admin(func() {
+		pd, _ := c.data.tps.getp("t", 0)
+		leader = pd.leader.node
+	})
+	if err := c.MoveTopicPartition("t", 0, 1-leader); err != nil {
+		t.Fatal(err)
+	}
+
+	var coordinator int32
+	c.admin(func() { coordinator = c.coordinator("g").node })
+	br := cl.Broker(int(coordinator))
+	member, generation := joinGroup(ctx, t, br, "g")
+	sync := kmsg.NewPtrSyncGroupRequest()
+	sync.Group, sync.Generation, sync.MemberID = "g", generation, member
+	if resp, err := sync.RequestWith(ctx, br); err != nil || resp.ErrorCode != 0 {
+		t.Fatalf("sync: %v %v", err, resp)
+	}
+	commit := kmsg.NewPtrOffsetCommitRequest()
+	commit.Group, commit.MemberID, commit.Generation = "g", member, generation
+	rt := kmsg.NewOffsetCommitRequestTopic()
+	rt.Topic = "t"
+	rp := kmsg.NewOffsetCommitRequestTopicPartition()
+	rp.Offset = 5
+	rt.Partitions = append(rt.Partitions, rp)
+	commit.Topics = append(commit.Topics, rt)
+	if resp, err := commit.RequestWith(ctx, br); err != nil || resp.Topics[0].Partitions[0].ErrorCode != 0 {
+		t.Fatalf("commit: %v %v", err, resp)
+	}
+
+	if _, err := kadm.NewClient(cl).DeleteTopic(ctx, "t"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, exp := range []Event{
+		{Type: EventLeaderChanged, Topic: "t", Leader: 1 - leader, LeaderEpoch: 1},
+		{Type: EventGroupRebalanced, Group: "g", Generation: generation},
+		{Type: EventOffsetsCommitted, Topic: "t", Offset: 5, Group: "g"},
+		{Type: EventTopicDeleted, Topic: "t"},
+	} {
+		select {
+		case ev := <-w.C:
+			if ev != exp {
+				t.Errorf("got event %+v, expected %+v", ev, exp)
+			}
+		case <-ctx.Done():
+			t.Fatalf("timed out waiting for %v", exp.Type)
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package kfake
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// Injected faults are declarative alternatives to ControlKey for the common
+// cases of slow brokers, flaky brokers, and dropped connections. Latency and
+// error faults are per request key and can be scoped to specific brokers; a
+// fault for a specific broker takes precedence over a fault for all brokers.
+//
+// Injected latency delays writing responses on the connection, after the
+// request is handled. Because responses on a connection are written in order,
+// a delayed response delays every response after it on the same connection,
+// as with a slow broker.
+//
+// Injected errors fail the request without handling it. Write requests (see
+// SetReadOnly), Fetch, and ListOffsets fail every partition or resource with
+// the error; batched FindCoordinator and OffsetFetch requests fail every
+// group or key. Other requests fail with the error in their top level error
+// code, and requests whose response has no top level error code at their
+// version (DescribeGroups, or Metadata before v13, for example) have their
+// connection closed.
+
+type errFault struct {
+	code        int16
+	probability float64
+}
+
+// InjectLatency delays every response for the given request key by latency
+// on the given brokers, or on all brokers if no node IDs are given. A zero
+// latency removes the fault. This combines with other latency options, such
+// as SetTopicLatency: the delays add.
+func (c *Cluster) InjectLatency(key int16, latency time.Duration, nodeIDs ...int32) {
+	c.faultsMu.Lock()
+	defer c.faultsMu.Unlock()
+	c.latencyFaults = setFault(c.latencyFaults, key, latency, latency == 0, nodeIDs)
+}
+
+// InjectError fails requests for the given request key with errCode with the
+// given probability, on the given brokers, or on all brokers if no node IDs
+// are given. A probability of 1 fails every request; a zero probability or
+// zero errCode removes the fault.
+func (c *Cluster) InjectError(key, errCode int16, probability float64, nodeIDs ...int32) {
+	c.faultsMu.Lock()
+	defer c.faultsMu.Unlock()
+	f := errFault{errCode, probability}
+	c.errFaults = setFault(c.errFaults, key, f, errCode == 0 || probability <= 0, nodeIDs)
+}
+
+// ClearFaults removes all faults added with InjectLatency and InjectError.
+func (c *Cluster) ClearFaults() {
+	c.faultsMu.Lock()
+	defer c.faultsMu.Unlock()
+	c.latencyFaults = nil
+	c.errFaults = nil
+}
+
+// DropConnections closes every client connection to the given broker, as if
+// the network between clients and the broker was interrupted. The broker
+// keeps listening, so clients can reconnect immediately. This returns an error
+// if the node does not exist.
+func (c *Cluster) DropConnections(nodeID int32) error {
+	var err error
+	c.admin(func() {
+		for _, b := range c.bs {
+			if b.node != nodeID {
+				continue
+			}
+			c.liveMu.Lock()
+			for cc := range c.live {
+				if cc.b == b {
+					cc.conn.Close()
+				}
+			}
+			c.liveMu.Unlock()
+			return
+		}
+		err = fmt.Errorf("node %d not found", nodeID)
+	})
+	return err
+}
+
+// setFault sets or removes a fault for key on the given nodes, with -1
+// meaning all nodes.
+func setFault[V any](m map[int16]map[int32]V, key int16, v V, remove bool, nodeIDs []int32) map[int16]map[int32]V {
+	if len(nodeIDs) == 0 {
+		nodeIDs = []int32{-1}
+	}
+	for _, node := range nodeIDs {
+		if remove {
+			delete(m[key], node)
+			if len(m[key]) == 0 {
+				delete(m, key)
+			}
+			continue
+		}
+		if m == nil {
+			m = make(map[int16]map[int32]V)
+		}
+		if m[key] == nil {
+			m[key] = make(map[int32]V)
+		}
+		m[key][node] = v
+	}
+	return m
+}
+
+// getFault returns the fault for key on node, preferring a node specific
+// fault over one for all nodes.
+func getFault[V any](m map[int16]map[int32]V, key int16, node int32) (V, bool) {
+	nodes := m[key]
+	if v, ok := nodes[node]; ok {
+		return v, true
+	}
+	v, ok := nodes[-1]
+	return v, ok
+}
+
+// faultLatency returns the injected latency for a response. This is called
+// from connection writer goroutines.
+func (c *Cluster) faultLatency(key int16, node int32) time.Duration {
+	c.faultsMu.Lock()
+	defer c.faultsMu.Unlock()
+	d, _ := getFault(c.latencyFaults, key, node)
+	return d
+}
+
+// injectError returns an error response (or a connection closing error) if
+// an injected error fault fires for the request.
+func (c *Cluster) injectError(creq *clientReq) (kmsg.Response, error, bool) {
+	c.faultsMu.Lock()
+	f, ok := getFault(c.errFaults, creq.kreq.Key(), creq.cc.b.node)
+	c.faultsMu.Unlock()
+	if !ok || rand.Float64() >= f.probability {
+		return nil, nil, false
+	}
+
+	kreq := creq.kreq
+	if kresp, ok := writeErrResponse(kreq, f.code); ok {
+		return kresp, nil, true
+	}
+	kresp := kreq.ResponseKind()
+	kresp.SetVersion(kreq.GetVersion())
+	closeErr := fmt.Errorf("%s v%d failed by injected error %d", kmsg.NameForKey(kreq.Key()), kreq.GetVersion(), f.code)
+
+	switch req := kreq.(type) {
+	case *kmsg.FetchRequest:
+		resp := kresp.(*kmsg.FetchResponse)
+		for _, rt := range req.Topics {
+			st := kmsg.NewFetchResponseTopic()
+			st.Topic = rt.Topic
+			st.TopicID = rt.TopicID
+			for _, rp := range rt.Partitions {
+				sp := kmsg.NewFetchResponseTopicPartition()
+				sp.Partition = rp.Partition
+				sp.ErrorCode = f.code
+				st.Partitions = append(st.Partitions, sp)
+			}
+			resp.Topics = append(resp.Topics, st)
+		}
+		return resp, nil, true
+
+	case *kmsg.ListOffsetsRequest:
+		resp := kresp.(*kmsg.ListOffsetsResponse)
+		for _, rt := range req.Topics {
+			st := kmsg.NewListOffsetsResponseTopic()
+			st.Topic = rt.Topic
+			for _, rp := range rt.Partitions {
+				sp := kmsg.NewListOffsetsResponseTopicPartition()
+				sp.Partition = rp.Partition
+				sp.ErrorCode = f.code
+				st.Partitions = append(st.Partitions, sp)
+			}
+			resp.Topics = append(resp.Topics, st)
+		}
+		return resp, nil, true
+
+	case *kmsg.FindCoordinatorRequest:
+		if req.Version < 4 {
+			break
+		}
+		resp := kresp.(*kmsg.FindCoordinatorResponse)
+		for _, key := range req.CoordinatorKeys {
+			sc := kmsg.NewFindCoordinatorResponseCoordinator()
+			sc.Key = key
+			sc.ErrorCode = f.code
+			resp.Coordinators = append(resp.Coordinators, sc)
+		}
+		return resp, nil, true
+
+	case *kmsg.MetadataRequest:
+		// The top level error code field exists before v13, but is
+		// not serialized.
+		if req.Version < 13 {
+			return nil, closeErr, true
+		}
+
+	case *kmsg.OffsetFetchRequest:
+		if req.Version < 8 {
+			break
+		}
+		resp := kresp.(*kmsg.OffsetFetchResponse)
+		for _, rg := range req.Groups {
+			sg := kmsg.NewOffsetFetchResponseGroup()
+			sg.Group = rg.Group
+			sg.ErrorCode = f.code
+			resp.Groups = append(resp.Groups, sg)
+		}
+		return resp, nil, true
+	}
+
+	ec := reflect.ValueOf(kresp).Elem().FieldByName("ErrorCode")
+	if !ec.IsValid() || ec.Kind() != reflect.Int16 {
+		return nil, closeErr, true
+	}
+	ec.SetInt(int64(f.code))
+	return kresp, nil, true
+}
@@ -0,0 +1,116 @@
+package kfake
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestInjectedFaults(t *testing.T) {
+	var conns atomic.Int32
+	c := newTestCluster(t, NumBrokers(2), SeedTopics(2, "t"), OnConnect(func(Connection) { conns.Add(1) }))
+	cl := newTestClient(t, c, kgo.RequestRetries(0))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// listOffsets issues ListOffsets for both partitions to the node,
+	// returning the first partition error and how long the request took.
+	listOffsets := func(node int) (int16, time.Duration) {
+		t.Helper()
+		req := kmsg.NewPtrListOffsetsRequest()
+		rt := kmsg.NewListOffsetsRequestTopic()
+		rt.Topic = "t"
+		for p := int32(0); p < 2; p++ {
+			rp := kmsg.NewListOffsetsRequestTopicPartition()
+			rp.Partition = p
+			rp.Timestamp = -1
+			rt.Partitions = append(rt.Partitions, rp)
+		}
+		req.Topics = append(req.Topics, rt)
+		start := time.Now()
+		resp, err := req.RequestWith(ctx, cl.Broker(node))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.Topics[0].Partitions[0].ErrorCode, time.Since(start)
+	}
+	describeCluster := func() (int16, error) {
+		resp, err := kmsg.NewPtrDescribeClusterRequest().RequestWith(ctx, cl.Broker(0))
+		if err != nil {
+			return 0, err
+		}
+		return resp.ErrorCode, nil
+	}
+
+	// A fault for a node takes precedence over a fault for all nodes.
+	c.InjectError(int16(kmsg.ListOffsets), kerr.OffsetNotAvailable.Code, 1)
+	c.InjectError(int16(kmsg.ListOffsets), kerr.KafkaStorageError.Code, 1, 0)
+	if code, _ := listOffsets(0); code != kerr.KafkaStorageError.Code {
+		t.Errorf("node 0: got %v, expected KAFKA_STORAGE_ERROR", kerr.ErrorForCode(code))
+	}
+	if code, _ := listOffsets(1); code != kerr.OffsetNotAvailable.Code {
+		t.Errorf("node 1: got %v, expected OFFSET_NOT_AVAILABLE", kerr.ErrorForCode(code))
+	}
+
+	// Other requests get a top level error code, or have their connection
+	// closed if there is none to set.
+	c.InjectError(int16(kmsg.DescribeCluster), kerr.ClusterAuthorizationFailed.Code, 1)
+	if code, err := describeCluster(); err != nil || code != kerr.ClusterAuthorizationFailed.Code {
+		t.Errorf("describe cluster: got %v %v, expected CLUSTER_AUTHORIZATION_FAILED", kerr.ErrorForCode(code), err)
+	}
+	c.InjectError(int16(kmsg.Metadata), kerr.UnknownServerError.Code, 1)
+	if _, err := kmsg.NewPtrMetadataRequest().RequestWith(ctx, cl.Broker(0)); err == nil {
+		t.Error("metadata with an injected error succeeded, expected the connection to be closed")
+	}
+
+	// A zero probability never fires, and ClearFaults removes everything.
+	c.InjectError(int16(kmsg.DescribeCluster), kerr.ClusterAuthorizationFailed.Code, 0)
+	if code, err := describeCluster(); err != nil || code != 0 {
+		t.Errorf("describe cluster with a zero probability: got %v %v", kerr.ErrorForCode(code), err)
+	}
+	c.ClearFaults()
+	// Only one node leads partition 0, so the other may return
+	// NOT_LEADER_FOR_PARTITION.
+	if code, _ := listOffsets(0); code != 0 && code != kerr.NotLeaderForPartition.Code {
+		t.Errorf("after ClearFaults: got %v", kerr.ErrorForCode(code))
+	}
+
+	const latency = 200 * time.Millisecond
+	c.InjectLatency(int16(kmsg.ListOffsets), latency, 1)
+	if _, took := listOffsets(1); took < latency {
+		t.Errorf("node 1 responded in %v, before the injected latency %v", took, latency)
+	}
+	if _, took := listOffsets(0); took >= latency {
+		t.Errorf("node 0 responded in %v, expected no injected latency", took)
+	}
+	c.InjectLatency(int16(kmsg.ListOffsets), 0, 1)
+	if _, took := listOffsets(1); took >= latency {
+		t.Errorf("node 1 responded in %v after removing the latency", took)
+	}
+
+	// Dropped connections are reconnected.
+	before := conns.Load()
+	if err := c.DropConnections(0); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.DropConnections(5); err == nil {
+		t.Error("dropping connections to a missing node: got no error")
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, err := describeCluster(); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("unable to reconnect after DropConnections")
+		}
+	}
+	if conns.Load() == before {
+		t.Error("the client did not reconnect after DropConnections")
+	}
+}
@@ -0,0 +1,78 @@
+package kfake
+
+// Fetch faults modify batches as they are served in fetch responses, leaving
+// the stored batches untouched. Once the faults are cleared, the same data
+// can be fetched again cleanly.
+
+type (
+	fetchFaults []fetchFault
+
+	fetchFault struct {
+		offset int64
+		drop   bool // if false, the batch is corrupted
+	}
+)
+
+func (c *Cluster) addFetchFault(topic string, partition int32, f fetchFault) {
+	c.admin(func() {
+		fs := c.fetchFaults.mkpDefault(topic, partition)
+		*fs = append(*fs, f)
+	})
+}
+
+// CorruptFetchOffset corrupts the batch containing the given offset whenever
+// the batch is served in a fetch response: the batch has an invalid CRC.
+// The stored batch is not modified; use ClearFetchFaults to serve the batch
+// normally again.
+func (c *Cluster) CorruptFetchOffset(topic string, partition int32, offset int64) {
+	c.addFetchFault(topic, partition, fetchFault{offset: offset})
+}
+
+// DropFetchOffset drops the batch containing the given offset whenever the
+// batch would be served in a fetch response: fetches skip straight to the
+// following batch. The stored batch is not modified; use ClearFetchFaults to
+// serve the batch normally again.
+func (c *Cluster) DropFetchOffset(topic string, partition int32, offset int64) {
+	c.addFetchFault(topic, partition, fetchFault{offset: offset, drop: true})
+}
+
+// ClearFetchFaults removes all faults added with CorruptFetchOffset or
+// DropFetchOffset.
+func (c *Cluster) ClearFetchFaults() {
+	c.admin(func() {
+		c.fetchFaults = nil
+	})
+}
+
+// appendFetchBatch appends the batch to dst, applying any fetch faults for
+// the batch's offsets.
+func (c *Cluster) appendFetchBatch(dst []byte, t string, p int32, b *partBatch) []byte {
+	fs, ok := c.fetchFaults.getp(t, p)
+	if !ok {
+		return b.appendTo(dst)
+	}
+	var (
+		last    = b.FirstOffset + int64(b.LastOffsetDelta)
+		corrupt bool
+	)
+	for _, f := range *fs {
+		if f.offset < b.FirstOffset || f.offset > last {
+			continue
+		}
+		if f.drop {
+			return dst
+		}
+		corrupt = true
+	}
+	start := len(dst)
+	dst = b.appendTo(dst)
+	if corrupt {
+		// The CRC follows the base offset (8 bytes), length (4),
+		// partition leader epoch (4), and magic (1).
+		crc := dst[start+17 : start+21]
+		for i := range crc {
+			crc[i] = ^crc[i]
+		}
+	}
+	return dst
+}
@@ -0,0 +1,68 @@
+package kfake
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func TestFetchFaults(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(1))
+	cl := newTestClient(t, c)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	created, err := newTestAdmin(t, c).CreateTopic(ctx, 1, 1, nil, "t")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, rs := range [][]*kgo.Record{
+		{kgo.StringRecord("a"), kgo.StringRecord("b")},
+		{kgo.StringRecord("c")},
+		{kgo.StringRecord("d")},
+	} {
+		if _, err := c.ProduceTo("t", 0, rs...); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Faults apply to the whole batch containing the offset.
+	c.CorruptFetchOffset("t", 0, 1)
+	c.DropFetchOffset("t", 0, 2)
+	c.DropFetchOffset("t", 1, 0) // missing partitions are ignored
+
+	exp := []fetchedRecord{
+		{0, "", "a", false},
+		{1, "", "b", false},
+		{3, "", "d", true},
+	}
+	if got := fetchRecords(ctx, t, cl, created.ID, 0); !reflect.DeepEqual(got, exp) {
+		t.Errorf("with faults: got %v, expected %v", got, exp)
+	}
+	if got := fetchRecords(ctx, t, cl, created.ID, 2); !reflect.DeepEqual(got, exp[2:]) {
+		t.Errorf("fetching a dropped offset: got %v, expected %v", got, exp[2:])
+	}
+
+	// The stored batches are untouched.
+	rs, err := c.ReadRecords("t", 0, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rs) != 4 {
+		t.Errorf("read %d stored records, expected 4", len(rs))
+	}
+
+	c.ClearFetchFaults()
+	exp = []fetchedRecord{
+		{0, "", "a", true},
+		{1, "", "b", true},
+		{2, "", "c", true},
+		{3, "", "d", true},
+	}
+	if got := fetchRecords(ctx, t, cl, created.ID, 0); !reflect.DeepEqual(got, exp) {
+		t.Errorf("after clearing faults: got %v, expected %v", got, exp)
+	}
+}
@@ -0,0 +1,213 @@
+package kfake
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// Fetch sessions (KIP-227) are cached per broker, as in Kafka. A fetch with
+// session epoch 0 creates a session holding every partition in the request;
+// later fetches with the session's ID and next epoch add or update the
+// partitions they include and remove their forgotten topics. Incremental
+// responses only include partitions with records, errors, or a changed high
+// watermark, last stable offset, or log start offset since they were last
+// returned. A fetch with epoch -1 closes its session and is sessionless.
+//
+// When a broker's cache is full, creating a session evicts the broker's least
+// recently used session. Clients using an evicted session receive
+// FETCH_SESSION_ID_NOT_FOUND and restart with a full fetch, as do clients
+// whose sessions were evicted with EvictFetchSessions. A fetch using the
+// wrong epoch for its session receives INVALID_FETCH_SESSION_EPOCH.
+
+type (
+	fetchSession struct {
+		id       int32
+		epoch    int32 // the next epoch expected from the client
+		parts    tps[fetchSessionPart]
+		lastUsed time.Time
+	}
+
+	fetchSessionPart struct {
+		topicID uuid
+		req     kmsg.FetchRequestTopicPartition // the latest fetch state sent for the partition
+
+		// The offsets last returned for the partition; -2 if the
+		// partition has not been returned yet.
+		hwm, lso, logStart int64
+	}
+)
+
+func newFetchSessionPart() *fetchSessionPart {
+	return &fetchSessionPart{hwm: -2, lso: -2, logStart: -2}
+}
+
+// FetchSessionCacheSlots sets the number of fetch sessions each broker
+// caches, as Kafka's max.incremental.fetch.session.cache.slots, overriding
+// the default of 1000. Zero disables fetch sessions: every fetch response has
+// session ID 0, and clients always issue full fetches.
+func FetchSessionCacheSlots(n int) Opt {
+	return opt{func(cfg *cfg) { cfg.fetchSessionSlots = n }}
+}
+
+// EvictFetchSessions evicts every fetch session on the given brokers, or on
+// all brokers if no node IDs are given. The next incremental fetch for an
+// evicted session fails with FETCH_SESSION_ID_NOT_FOUND.
+func (c *Cluster) EvictFetchSessions(nodeIDs ...int32) {
+	c.admin(func() {
+		for _, b := range c.bs {
+			if len(nodeIDs) == 0 {
+				b.fetchSessions = nil
+				continue
+			}
+			for _, node := range nodeIDs {
+				if b.node == node {
+					b.fetchSessions = nil
+				}
+			}
+		}
+	})
+}
+
+// fetchSession resolves the session for a fetch request, replacing the
+// request's topics with every partition in the session. This returns the
+// session (nil if the fetch is sessionless), whether the response must
+// include every partition, and any session error.
+func (c *Cluster) fetchSession(creq *clientReq, req *kmsg.FetchRequest) (*fetchSession, bool, int16) {
+	if req.Version < 7 {
+		return nil, true, 0
+	}
+	b := creq.cc.b
+
+	if req.SessionEpoch == 0 || req.SessionEpoch == -1 {
+		if req.SessionID != 0 {
+			delete(b.fetchSessions, req.SessionID)
+		}
+		if req.SessionEpoch == -1 || c.cfg.fetchSessionSlots <= 0 {
+			return nil, true, 0
+		}
+		if len(b.fetchSessions) >= c.cfg.fetchSessionSlots {
+			var lru *fetchSession
+			for _, s := range b.fetchSessions {
+				if lru == nil || s.lastUsed.Before(lru.lastUsed) {
+					lru = s
+				}
+			}
+			delete(b.fetchSessions, lru.id)
+		}
+		s := &fetchSession{epoch: 1}
+		for {
+			s.id = rand.Int31()
+			if _, exists := b.fetchSessions[s.id]; s.id != 0 && !exists {
+				break
+			}
+		}
+		if b.fetchSessions == nil {
+			b.fetchSessions = make(map[int32]*fetchSession)
+		}
+		b.fetchSessions[s.id] = s
+		s.update(c, req)
+		return s, true, 0
+	}
+
+	s, ok := b.fetchSessions[req.SessionID]
+	if !ok {
+		return nil, false, kerr.FetchSessionIDNotFound.Code
+	}
+	if req.SessionEpoch != s.epoch {
+		return nil, false, kerr.InvalidFetchSessionEpoch.Code
+	}
+	if s.epoch == math.MaxInt32 {
+		s.epoch = 1
+	} else {
+		s.epoch++
+	}
+	s.update(c, req)
+	return s, false, 0
+}
+
+// update applies the request's partitions and forgotten topics to the
+// session, and then replaces the request's topics with the session's
+// partitions. Topics whose IDs are unknown are kept in the request (to be
+// answered with UNKNOWN_TOPIC_ID) but not added to the session.
+func (s *fetchSession) update(c *Cluster, req *kmsg.FetchRequest) {
+	s.lastUsed = time.Now()
+
+	var unknown []kmsg.FetchRequestTopic
+	for _, rt := range req.Topics {
+		if req.Version >= 13 {
+			rt.Topic = c.data.id2t[rt.TopicID]
+		}
+		if rt.Topic == "" {
+			unknown = append(unknown, rt)
+			continue
+		}
+		for _, rp := range rt.Partitions {
+			sp := s.parts.mkp(rt.Topic, rp.Partition, newFetchSessionPart)
+			if sp.topicID != rt.TopicID { // recreated topic
+				*sp = *newFetchSessionPart()
+				sp.topicID = rt.TopicID
+			}
+			sp.req = rp
+		}
+	}
+	for _, ft := range req.ForgottenTopics {
+		t := ft.Topic
+		if req.Version >= 13 {
+			t = c.data.id2t[ft.TopicID]
+		}
+		for _, p := range ft.Partitions {
+			s.parts.delp(t, p)
+		}
+	}
+
+	topics := make([]string, 0, len(s.parts))
+	for t := range s.parts {
+		topics = append(topics, t)
+	}
+	sort.Strings(topics)
+	req.Topics = make([]kmsg.FetchRequestTopic, 0, len(topics)+len(unknown))
+	for _, t := range topics {
+		ps := s.parts[t]
+		rt := kmsg.NewFetchRequestTopic()
+		rt.Topic = t
+		for _, sp := range ps {
+			rt.TopicID = sp.topicID
+			rt.Partitions = append(rt.Partitions, sp.req)
+		}
+		sort.Slice(rt.Partitions, func(i, j int) bool { return rt.Partitions[i].Partition < rt.Partitions[j].Partition })
+		req.Topics = append(req.Topics, rt)
+	}
+	req.Topics = append(req.Topics, unknown...)
+}
+
+// respond sets the response's session ID and, for incremental fetches,
+// removes partitions that have nothing new since they were last returned.
+func (s *fetchSession) respond(resp *kmsg.FetchResponse, full bool) {
+	if s == nil {
+		return
+	}
+	resp.SessionID = s.id
+	keept := resp.Topics[:0]
+	for _, rt := range resp.Topics {
+		keepp := rt.Partitions[:0]
+		for _, rp := range rt.Partitions {
+			sp, ok := s.parts.getp(rt.Topic, rp.Partition)
+			changed := !ok || sp.hwm != rp.HighWatermark || sp.lso != rp.LastStableOffset || sp.logStart != rp.LogStartOffset
+			if ok {
+				sp.hwm, sp.lso, sp.logStart = rp.HighWatermark, rp.LastStableOffset, rp.LogStartOffset
+			}
+			if full || changed || rp.ErrorCode != 0 || len(rp.RecordBatches) > 0 {
+				keepp = append(keepp, rp)
+			}
+		}
+		if rt.Partitions = keepp; len(keepp) > 0 {
+			keept = append(keept, rt)
+		}
+	}
+	resp.Topics = keept
+}
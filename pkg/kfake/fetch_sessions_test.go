@@ -0,0 +1,88 @@
+package kfake
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestFetchSessionEviction(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(1), SeedTopics(1, "t"), FetchSessionCacheSlots(1))
+	cl := newTestClient(t, c)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	topics, err := newTestAdmin(t, c).ListTopics(ctx, "t")
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := topics["t"].ID
+
+	fetch := func(sessionID, epoch int32) *kmsg.FetchResponse {
+		t.Helper()
+		req := kmsg.NewPtrFetchRequest()
+		req.MaxWaitMillis = 0
+		req.SessionID = sessionID
+		req.SessionEpoch = epoch
+		rt := kmsg.NewFetchRequestTopic()
+		rt.Topic = "t"
+		rt.TopicID = id
+		rp := kmsg.NewFetchRequestTopicPartition()
+		rp.PartitionMaxBytes = 1 << 20
+		rt.Partitions = append(rt.Partitions, rp)
+		req.Topics = append(req.Topics, rt)
+		resp, err := req.RequestWith(ctx, cl.Broker(0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+
+	a := fetch(0, 0)
+	if a.SessionID == 0 {
+		t.Fatal("fetch at epoch 0 did not create a session")
+	}
+	if resp := fetch(a.SessionID, 1); resp.ErrorCode != 0 {
+		t.Fatalf("incremental fetch: %v", kerr.ErrorForCode(resp.ErrorCode))
+	}
+
+	// The cache holds one session, so a second session evicts the first.
+	b := fetch(0, 0)
+	if b.SessionID == 0 || b.SessionID == a.SessionID {
+		t.Fatalf("second session ID %d, want a new nonzero ID", b.SessionID)
+	}
+	if resp := fetch(a.SessionID, 2); resp.ErrorCode != kerr.FetchSessionIDNotFound.Code {
+		t.Fatalf("fetch with an evicted session: got %v, want FETCH_SESSION_ID_NOT_FOUND", kerr.ErrorForCode(resp.ErrorCode))
+	}
+	if resp := fetch(b.SessionID, 2); resp.ErrorCode != kerr.InvalidFetchSessionEpoch.Code {
+		t.Fatalf("fetch with the wrong epoch: got %v, want INVALID_FETCH_SESSION_EPOCH", kerr.ErrorForCode(resp.ErrorCode))
+	}
+
+	c.EvictFetchSessions(0)
+	if resp := fetch(b.SessionID, 1); resp.ErrorCode != kerr.FetchSessionIDNotFound.Code {
+		t.Fatalf("fetch after EvictFetchSessions: got %v, want FETCH_SESSION_ID_NOT_FOUND", kerr.ErrorForCode(resp.ErrorCode))
+	}
+}
+
+func TestFetchSessionsDisabled(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(1), SeedTopics(1, "t"), FetchSessionCacheSlots(0))
+	cl := newTestClient(t, c, kgo.ConsumeTopics("t"), kgo.FetchMaxWait(100*time.Millisecond))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if _, err := c.ProduceTo("t", 0, kgo.StringRecord("v")); err != nil {
+		t.Fatal(err)
+	}
+	if fs := cl.PollFetches(ctx); fs.NumRecords() != 1 {
+		t.Fatalf("got %d records, want 1 (errors: %v)", fs.NumRecords(), fs.Errors())
+	}
+	var n int
+	c.admin(func() { n = len(c.bs[0].fetchSessions) })
+	if n != 0 {
+		t.Fatalf("broker cached %d sessions with sessions disabled", n)
+	}
+}
@@ -0,0 +1,173 @@
+package kfake
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// The functions in this file allow feeding requests into the cluster without
+// a TCP connection, which is useful for fuzzing the request handling path.
+// Each request is handled on a new in memory connection to the first broker
+// in the cluster, exactly as if a client connected and issued one request.
+// A panic while handling a request crashes the process, which fuzzers report
+// as a failure.
+
+// HandleFrame handles a raw request frame, as written on the wire including
+// the leading four byte size, and returns the raw response frame. This
+// returns an error if the frame cannot be parsed, if the cluster would close
+// the connection rather than reply, or if the context is canceled before the
+// request is replied to. Produce requests with acks=0 are never replied to,
+// and for these this returns a nil response once the request is handled.
+func (c *Cluster) HandleFrame(ctx context.Context, frame []byte) ([]byte, error) {
+	if len(frame) < 4 {
+		return nil, fmt.Errorf("frame of %d bytes is too short to contain a size", len(frame))
+	}
+	if size := binary.BigEndian.Uint32(frame); int64(size) != int64(len(frame)-4) {
+		return nil, fmt.Errorf("frame size %d does not match the %d bytes following it", size, len(frame)-4)
+	}
+	kreq, corr, cid, err := parseRequest(frame[4:])
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse request: %w", err)
+	}
+	kresp, err := c.handle(ctx, kreq, corr, cid)
+	if kresp == nil || err != nil {
+		return nil, err
+	}
+	return appendResponse(nil, kresp, corr), nil
+}
+
+// HandleRequest handles a request as if it were issued by a client and
+// returns the response. The request's version must be set. This returns an
+// error if the cluster would close the connection rather than reply, or if
+// the context is canceled before the request is replied to. Produce requests
+// with acks=0 are never replied to, and for these this returns a nil response
+// once the request is handled.
+func (c *Cluster) HandleRequest(ctx context.Context, kreq kmsg.Request) (kmsg.Response, error) {
+	return c.handle(ctx, kreq, 0, "")
+}
+
+func (c *Cluster) handle(ctx context.Context, kreq kmsg.Request, corr int32, cid string) (kmsg.Response, error) {
+	var b *broker
+	c.admin(func() { b = c.bs[0] })
+
+	conn, peer := net.Pipe()
+	defer conn.Close()
+	defer peer.Close()
+
+	// The response channel is buffered so that replies that arrive after
+	// the context is canceled never block the cluster.
+	cc := &clientConn{
+		c:        c,
+		b:        b,
+		conn:     conn,
+		respCh:   make(chan clientResp, 1),
+		sasl:     c.cfg.enableSASL,
+		readDone: make(chan struct{}),
+	}
+	creq := &clientReq{cc: cc, kreq: kreq, at: time.Now(), cid: cid, corr: corr}
+	cc.logProtocol("request from", cid, corr, kreq.Key(), kreq.GetVersion(), kreq)
+
+	select {
+	case c.reqCh <- creq:
+	case <-c.die:
+		return nil, errors.New("cluster is closed")
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	// The run loop handles requests serially, so once an admin function
+	// runs, an acks=0 produce request has been handled.
+	if p, ok := kreq.(*kmsg.ProduceRequest); ok && p.Acks == 0 {
+		c.admin(func() {})
+		return nil, nil
+	}
+
+	select {
+	case resp := <-cc.respCh:
+		if resp.err != nil {
+			return nil, resp.err
+		}
+		cc.logProtocol("response to", "", resp.corr, resp.kresp.Key(), resp.kresp.GetVersion(), resp.kresp)
+		return resp.kresp, nil
+	case <-c.die:
+		return nil, errors.New("cluster is closed")
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// CheckInvariants checks that the cluster's internal state is consistent,
+// returning an error describing the first inconsistency found. This is meant
+// to be called after handling fuzzed requests to ensure no request left the
+// cluster in a state that a real broker could never be in.
+func (c *Cluster) CheckInvariants() error {
+	var err error
+	c.admin(func() {
+		live := make(map[*broker]bool, len(c.bs))
+		for _, b := range c.bs {
+			live[b] = true
+		}
+		if len(c.data.t2id) != len(c.data.id2t) {
+			err = fmt.Errorf("%d topic names map to IDs, but %d IDs map to names", len(c.data.t2id), len(c.data.id2t))
+			return
+		}
+		for t, ps := range c.data.tps {
+			id, ok := c.data.t2id[t]
+			if !ok {
+				err = fmt.Errorf("topic %s has no ID", t)
+				return
+			}
+			if c.data.id2t[id] != t {
+				err = fmt.Errorf("topic %s ID maps back to %q", t, c.data.id2t[id])
+				return
+			}
+			for p, pd := range ps {
+				if err = pd.checkInvariants(live); err != nil {
+					err = fmt.Errorf("topic %s partition %d: %w", t, p, err)
+					return
+				}
+			}
+		}
+	})
+	return err
+}
+
+func (pd *partData) checkInvariants(live map[*broker]bool) error {
+	if pd.leader == nil || !live[pd.leader] {
+		return errors.New("leader is not a broker in the cluster")
+	}
+	if pd.logStartOffset > pd.highWatermark || pd.lastStableOffset > pd.highWatermark {
+		return fmt.Errorf("offsets past the high watermark %d: log start %d, last stable %d", pd.highWatermark, pd.logStartOffset, pd.lastStableOffset)
+	}
+	var (
+		nbytes int64
+		end    int64 = -1
+	)
+	for i := range pd.batches {
+		b := &pd.batches[i]
+		if b.FirstOffset < end {
+			return fmt.Errorf("batch at offset %d overlaps prior batch ending at %d", b.FirstOffset, end)
+		}
+		if b.LastOffsetDelta < 0 {
+			return fmt.Errorf("batch at offset %d has negative last offset delta %d", b.FirstOffset, b.LastOffsetDelta)
+		}
+		if b.epoch > pd.epoch {
+			return fmt.Errorf("batch at offset %d has epoch %d past the partition epoch %d", b.FirstOffset, b.epoch, pd.epoch)
+		}
+		end = b.FirstOffset + int64(b.LastOffsetDelta) + 1
+		nbytes += int64(b.nbytes)
+	}
+	if end > pd.highWatermark {
+		return fmt.Errorf("batches end at %d, past the high watermark %d", end, pd.highWatermark)
+	}
+	if nbytes != pd.nbytes {
+		return fmt.Errorf("batches total %d bytes, but the partition tracks %d", nbytes, pd.nbytes)
+	}
+	return nil
+}
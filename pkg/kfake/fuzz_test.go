@@ -0,0 +1,110 @@
+package kfake
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kbin"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// appendFrame appends a request frame, as a client would write it, to dst.
+func appendFrame(dst []byte, kreq kmsg.Request, corr int32) []byte {
+	start := len(dst)
+	dst = append(dst, 0, 0, 0, 0)
+	dst = kbin.AppendInt16(dst, kreq.Key())
+	dst = kbin.AppendInt16(dst, kreq.GetVersion())
+	dst = kbin.AppendInt32(dst, corr)
+	dst = kbin.AppendNullableString(dst, kmsg.StringPtr("fuzz"))
+	if kreq.IsFlexible() {
+		dst = append(dst, 0)
+	}
+	dst = kreq.AppendTo(dst)
+	binary.BigEndian.PutUint32(dst[start:], uint32(len(dst)-start-4))
+	return dst
+}
+
+func TestHandleRequest(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(1))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	create := kmsg.NewPtrCreateTopicsRequest()
+	create.SetVersion(7)
+	rt := kmsg.NewCreateTopicsRequestTopic()
+	rt.Topic, rt.NumPartitions, rt.ReplicationFactor = "t", 2, 1
+	create.Topics = append(create.Topics, rt)
+	kresp, err := c.HandleRequest(ctx, create)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := kerr.ErrorForCode(kresp.(*kmsg.CreateTopicsResponse).Topics[0].ErrorCode); err != nil {
+		t.Fatal(err)
+	}
+
+	// Frames round trip through the wire format, keeping the correlation
+	// ID.
+	meta := kmsg.NewPtrMetadataRequest()
+	meta.SetVersion(12)
+	raw, err := c.HandleFrame(ctx, appendFrame(nil, meta, 7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp := meta.ResponseKind().(*kmsg.MetadataResponse)
+	resp.SetVersion(12)
+	if corr := binary.BigEndian.Uint32(raw[4:]); corr != 7 {
+		t.Errorf("got correlation ID %d, expected 7", corr)
+	}
+	if err := resp.ReadFrom(raw[9:]); err != nil { // size, correlation ID, empty tags
+		t.Fatal(err)
+	}
+	if len(resp.Topics) != 1 || *resp.Topics[0].Topic != "t" || len(resp.Topics[0].Partitions) != 2 {
+		t.Errorf("got metadata topics %+v, expected t with 2 partitions", resp.Topics)
+	}
+
+	for _, frame := range [][]byte{nil, {0, 0, 0, 5, 0}, {0, 0, 0, 2, 0, 99}} {
+		if _, err := c.HandleFrame(ctx, frame); err == nil {
+			t.Errorf("frame %v was handled, expected an error", frame)
+		}
+	}
+
+	if err := c.CheckInvariants(); err != nil {
+		t.Fatal(err)
+	}
+	c.admin(func() {
+		pd, _ := c.data.tps.getp("t", 0)
+		pd.logStartOffset = pd.highWatermark + 1
+	})
+	if err := c.CheckInvariants(); err == nil {
+		t.Error("CheckInvariants did not catch a log start offset past the high watermark")
+	}
+}
+
+func FuzzHandleFrame(f *testing.F) {
+	for _, kreq := range []kmsg.Request{
+		kmsg.NewPtrApiVersionsRequest(),
+		kmsg.NewPtrMetadataRequest(),
+		kmsg.NewPtrCreateTopicsRequest(),
+		kmsg.NewPtrProduceRequest(),
+		kmsg.NewPtrFetchRequest(),
+	} {
+		kreq.SetVersion(kreq.MaxVersion())
+		f.Add(appendFrame(nil, kreq, 1))
+	}
+	f.Fuzz(func(t *testing.T, frame []byte) {
+		c, err := NewCluster(NumBrokers(1), SeedTopics(1, "t"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer c.Close()
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		c.HandleFrame(ctx, frame)
+		if err := c.CheckInvariants(); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
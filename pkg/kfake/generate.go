@@ -0,0 +1,144 @@
+package kfake
+
+import (
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// GenerateRecords continuously appends records to topic from within the
+// cluster, as if a producer were producing at rate records per second. Each
+// record has no key and a random value of size bytes, and records are spread
+// round robin across the topic's partitions. This is useful to test consumer
+// catch up, lag, and fetch fairness without running a producer in the test.
+//
+// Records are appended by partition leaders every 10ms (or every record, for
+// rates below 100/s) with the leader's current time, and wake any fetches
+// waiting on the partition. Ticks are skipped while the topic does not exist.
+// Generation runs until the returned function is called or the cluster is
+// closed; once the stop function returns, no more records are generated.
+//
+// This returns an error if the topic does not exist or rate is not positive.
+func (c *Cluster) GenerateRecords(topic string, rate float64, size int) (stop func(), err error) {
+	if rate <= 0 {
+		return nil, errors.New("rate must be positive")
+	}
+	if size < 0 {
+		size = 0
+	}
+	c.admin(func() {
+		if _, ok := c.data.tps.gett(topic); !ok {
+			err = fmt.Errorf("topic %q does not exist", topic)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		quit = make(chan struct{})
+		done = make(chan struct{})
+		once sync.Once
+	)
+	go c.generate(topic, rate, size, quit, done)
+	return func() {
+		once.Do(func() { close(quit) })
+		<-done
+	}, nil
+}
+
+func (c *Cluster) generate(topic string, rate float64, size int, quit, done chan struct{}) {
+	defer close(done)
+
+	interval := 10 * time.Millisecond
+	if every := time.Duration(float64(time.Second) / rate); every > interval {
+		interval = every
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var (
+		start     = time.Now()
+		generated int64
+		next      int32
+		rng       = rand.New(rand.NewSource(time.Now().UnixNano()))
+	)
+	for {
+		select {
+		case <-quit:
+			return
+		case <-c.die:
+			return
+		case <-ticker.C:
+		}
+
+		due := int64(rate*time.Since(start).Seconds()) - generated
+		if due <= 0 {
+			continue
+		}
+
+		fn := func() {
+			ps, ok := c.data.tps.gett(topic)
+			if !ok || len(ps) == 0 {
+				return
+			}
+			generated += due
+
+			values := make(map[int32][][]byte)
+			for ; due > 0; due-- {
+				p := next % int32(len(ps))
+				next++
+				v := make([]byte, size)
+				rng.Read(v)
+				values[p] = append(values[p], v)
+			}
+			for p, vs := range values {
+				pd, ok := ps[p]
+				if !ok {
+					continue
+				}
+				b := newRecordBatch(pd.leader.now().UnixMilli(), vs)
+				pd.pushBatch(int(b.Length)+12, b)
+			}
+		}
+
+		if !c.tryAdmin(fn) {
+			return
+		}
+	}
+}
+
+// newRecordBatch returns a non-transactional, non-idempotent, uncompressed
+// batch containing keyless records with the given values, all timestamped
+// with now.
+func newRecordBatch(now int64, values [][]byte) kmsg.RecordBatch {
+	var records []byte
+	for i, v := range values {
+		r := kmsg.Record{
+			OffsetDelta: int32(i),
+			Value:       v,
+		}
+		r.Length = int32(len(r.AppendTo(nil)) - 1) // a zero length encodes as one byte
+		records = r.AppendTo(records)
+	}
+	b := kmsg.RecordBatch{
+		Length:               int32(49 + len(records)),
+		PartitionLeaderEpoch: -1,
+		Magic:                2,
+		LastOffsetDelta:      int32(len(values) - 1),
+		FirstTimestamp:       now,
+		MaxTimestamp:         now,
+		ProducerID:           -1,
+		ProducerEpoch:        -1,
+		FirstSequence:        -1,
+		NumRecords:           int32(len(values)),
+		Records:              records,
+	}
+	b.CRC = int32(crc32.Checksum(b.AppendTo(nil)[21:], crc32c))
+	return b
+}
@@ -0,0 +1,69 @@
+package kfake
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+)
+
+func TestGenerateRecords(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(1), SeedTopics(2, "t"))
+	cl := newTestClient(t, c)
+	adm := newTestAdmin(t, c)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if _, err := c.GenerateRecords("missing", 10, 1); err == nil {
+		t.Error("generating to a missing topic did not fail")
+	}
+	if _, err := c.GenerateRecords("t", 0, 1); err == nil {
+		t.Error("generating at a zero rate did not fail")
+	}
+
+	stop, err := c.GenerateRecords("t", 1000, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ends := func() map[int32]int64 {
+		t.Helper()
+		offsets, err := adm.ListEndOffsets(ctx, "t")
+		if err != nil {
+			t.Fatal(err)
+		}
+		m := make(map[int32]int64)
+		offsets.Each(func(o kadm.ListedOffset) { m[o.Partition] = o.Offset })
+		return m
+	}
+	for {
+		if m := ends(); m[0] >= 10 && m[1] >= 10 {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			t.Fatalf("records were not generated to both partitions: %v", ends())
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	stop()
+	stop() // no-op
+
+	before := ends()
+	time.Sleep(50 * time.Millisecond)
+	if after := ends(); after[0] != before[0] || after[1] != before[1] {
+		t.Errorf("records were generated after stopping: %v to %v", before, after)
+	}
+
+	var id [16]byte
+	c.admin(func() { id = c.data.t2id["t"] })
+	rs := fetchRecords(ctx, t, cl, id, 0)
+	if len(rs) == 0 {
+		t.Fatal("fetched no generated records")
+	}
+	for _, r := range rs {
+		if !r.crcOK || r.key != "" || len(r.value) != 8 {
+			t.Fatalf("got generated record %+v, expected a valid keyless record with an 8 byte value", r)
+		}
+	}
+}
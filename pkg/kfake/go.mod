@@ -1,11 +1,20 @@
 module github.com/twmb/franz-go/pkg/kfake
 
-go 1.21
-
-toolchain go1.22.0
+// kfake requires released franz-go, kadm, and kmsg versions rather than
+// replacing them with this repository's copies: go install refuses modules
+// with replace directives, and kfake ships the installable cmd/kfake. The
+// tests module replaces kfake and its dependencies with this repository's
+// copies, so kfake is built and tested against unreleased changes there.
+//
+// go 1.24.0 is the minimum that kmsg allows: every kmsg release with the
+// share group fields kfake uses (v1.12.0 and later) requires go 1.24.0.
+go 1.24.0
 
 require (
-	github.com/twmb/franz-go v1.16.1
-	github.com/twmb/franz-go/pkg/kmsg v1.8.0
-	golang.org/x/crypto v0.23.0
+	github.com/klauspost/compress v1.18.0
+	github.com/pierrec/lz4/v4 v4.1.22
+	github.com/twmb/franz-go v1.19.4
+	github.com/twmb/franz-go/pkg/kadm v1.16.1
+	github.com/twmb/franz-go/pkg/kmsg v1.13.1
+	golang.org/x/crypto v0.38.0
 )
@@ -0,0 +1,186 @@
+package kfake
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// The coordinator records every classic group rebalance so that tests can
+// assert on what the coordinator saw and decided, rather than only on what
+// clients observed through their callbacks.
+
+// maxGroupRebalances is how many rebalances are kept per group; older
+// rebalances are dropped.
+const maxGroupRebalances = 1000
+
+// GroupRebalance describes one completed join phase of a classic group and
+// the assignments the leader chose in the following sync.
+type GroupRebalance struct {
+	// Generation is the generation the rebalance moved the group to.
+	Generation int32
+	// At is when the join phase completed.
+	At time.Time
+
+	// ProtocolType is the group's protocol type, i.e. "consumer".
+	ProtocolType string
+	// Protocol is the protocol (balancer) chosen for this generation, or
+	// empty if no members remained and the group became empty.
+	Protocol string
+	// Leader is the member ID of the leader for this generation.
+	Leader string
+
+	// Members are the members of the generation, sorted by member ID.
+	Members []GroupRebalanceMember
+
+	// Assignments are the member assignments the leader sent in
+	// SyncGroup, by member ID. This is nil if the leader has not synced,
+	// or if the group rebalanced again before the leader synced.
+	Assignments map[string][]byte
+}
+
+// GroupRebalanceMember is a member of a group in a rebalance, and what the
+// member joined with.
+type GroupRebalanceMember struct {
+	MemberID   string
+	InstanceID *string
+	ClientID   string
+	ClientHost string
+
+	// Protocols are the protocols the member joined with, in the member's
+	// order of preference. For consumer groups, the metadata can be
+	// decoded with kmsg.ConsumerMemberMetadata.
+	Protocols []kmsg.JoinGroupRequestProtocol
+}
+
+// GroupRebalances returns the rebalances of a classic group, oldest first.
+// This returns an error if the group does not exist.
+func (c *Cluster) GroupRebalances(group string) ([]GroupRebalance, error) {
+	var (
+		rs  []GroupRebalance
+		err error
+	)
+	c.admin(func() {
+		g, ok := c.groups.gs[group]
+		if !ok {
+			err = fmt.Errorf("group %q not found", group)
+			return
+		}
+		if !g.waitControl(func() {
+			rs = make([]GroupRebalance, 0, len(g.rebalances))
+			for _, r := range g.rebalances {
+				rs = append(rs, r.clone())
+			}
+		}) {
+			err = fmt.Errorf("group %q not found", group)
+		}
+	})
+	return rs, err
+}
+
+func (r GroupRebalance) clone() GroupRebalance {
+	r.Members = append([]GroupRebalanceMember(nil), r.Members...)
+	for i := range r.Members {
+		m := &r.Members[i]
+		if m.InstanceID != nil {
+			id := *m.InstanceID
+			m.InstanceID = &id
+		}
+		ps := make([]kmsg.JoinGroupRequestProtocol, len(m.Protocols))
+		for j, p := range m.Protocols {
+			ps[j] = kmsg.JoinGroupRequestProtocol{
+				Name:     p.Name,
+				Metadata: append([]byte(nil), p.Metadata...),
+			}
+		}
+		m.Protocols = ps
+	}
+	if r.Assignments != nil {
+		as := make(map[string][]byte, len(r.Assignments))
+		for id, a := range r.Assignments {
+			as[id] = append([]byte(nil), a...)
+		}
+		r.Assignments = as
+	}
+	return r
+}
+
+// Called in the manage loop at the end of completeRebalance.
+func (g *group) recordRebalance() {
+	r := GroupRebalance{
+		Generation:   g.generation,
+		At:           time.Now(),
+		ProtocolType: g.protocolType,
+		Protocol:     g.protocol,
+		Leader:       g.leader,
+	}
+	if len(g.members) == 0 {
+		r.Protocol, r.Leader = "", ""
+	}
+	for _, m := range g.members {
+		r.Members = append(r.Members, GroupRebalanceMember{
+			MemberID:   m.memberID,
+			InstanceID: m.join.InstanceID,
+			ClientID:   m.clientID,
+			ClientHost: m.clientHost,
+			Protocols:  m.join.Protocols,
+		})
+	}
+	sort.Slice(r.Members, func(i, j int) bool { return r.Members[i].MemberID < r.Members[j].MemberID })
+
+	if len(g.rebalances) == maxGroupRebalances {
+		g.rebalances = append(g.rebalances[:0], g.rebalances[1:]...)
+	}
+	g.rebalances = append(g.rebalances, r)
+}
+
+// Called in the manage loop when the leader syncs.
+func (g *group) recordLeaderSync(req *kmsg.SyncGroupRequest) {
+	n := len(g.rebalances)
+	if n == 0 || g.rebalances[n-1].Generation != g.generation {
+		return
+	}
+	as := make(map[string][]byte, len(req.GroupAssignment))
+	for _, a := range req.GroupAssignment {
+		as[a.MemberID] = a.MemberAssignment
+	}
+	g.rebalances[n-1].Assignments = as
+}
+
+// HoldGroupSync holds rebalances of a classic group open in the
+// CompletingRebalance state until the returned release function is called:
+// SyncGroup requests, including the leader's, wait rather than complete the
+// rebalance. While a rebalance is held, OffsetCommit requests for the current
+// generation fail with REBALANCE_IN_PROGRESS and requests for older
+// generations fail with ILLEGAL_GENERATION, which allows testing how clients
+// behave when committing during a rebalance.
+//
+// Members waiting in SyncGroup do not heartbeat, so a held rebalance is
+// eventually broken by members timing out of the group. Calling release more
+// than once is a no-op. This returns an error if the group does not exist.
+func (c *Cluster) HoldGroupSync(name string) (release func(), err error) {
+	var g *group
+	c.admin(func() {
+		g = c.groups.gs[name]
+	})
+	if g == nil || !g.waitControl(func() { g.holdSync = true }) {
+		return nil, fmt.Errorf("group %q not found", name)
+	}
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			g.waitControl(func() {
+				g.holdSync = false
+				if req := g.heldSync; req != nil {
+					g.heldSync = nil
+					if g.state == groupCompletingRebalance && req.Generation == g.generation {
+						g.completeLeaderSync(req)
+					}
+				}
+			})
+		})
+	}, nil
+}
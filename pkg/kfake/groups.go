@@ -3,6 +3,7 @@ package kfake
 import (
 	"bytes"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -10,7 +11,6 @@ import (
 	"github.com/twmb/franz-go/pkg/kmsg"
 )
 
-// TODO instance IDs
 // TODO persisting groups so commits can happen to client-managed groups
 //      we need lastCommit, and need to better prune empty groups
 
@@ -31,8 +31,18 @@ type (
 		members map[string]*groupMember
 		pending map[string]*groupMember
 
+		// staticMembers maps static members' instance IDs to their
+		// current member IDs.
+		staticMembers map[string]string
+
 		commits tps[offsetCommit]
 
+		// txnCommits are pending transactional offset commits, by
+		// producer ID, that become commits once the transaction
+		// commits. These make offsets unstable for OffsetFetch
+		// requests that require stable offsets.
+		txnCommits map[int64]*tps[offsetCommit]
+
 		generation   int32
 		protocolType string
 		protocols    map[string]int
@@ -43,7 +53,17 @@ type (
 
 		nJoining int
 
-		tRebalance *time.Timer
+		tRebalance        *time.Timer
+		rebalanceDeadline time.Time // cluster clock time that tRebalance completes the rebalance
+		rebalances        []GroupRebalance
+
+		holdSync bool                   // if true, the leader's sync is held in heldSync
+		heldSync *kmsg.SyncGroupRequest // held until the hold is released
+
+		// consumers is non-nil if this is a KIP-848 consumer group,
+		// and maps member IDs to members; see consumer_groups.go.
+		consumers     map[string]*consumerMember
+		consumerEpoch int32
 
 		quit   sync.Once
 		quitCh chan struct{}
@@ -51,6 +71,7 @@ type (
 
 	groupMember struct {
 		memberID   string
+		instanceID *string
 		clientID   string
 		clientHost string
 
@@ -62,8 +83,10 @@ type (
 
 		assignment []byte
 
-		t    *time.Timer
-		last time.Time
+		t         *time.Timer
+		last      time.Time     // cluster clock time of the last heartbeat
+		timeout   time.Duration // session timeout, measured from last
+		onTimeout func()        // run if the session times out; nil once run
 	}
 
 	offsetCommit struct {
@@ -114,11 +137,21 @@ func (c *Cluster) validateGroup(creq *clientReq, group string) *kerr.Error {
 			return kerr.InvalidGroupID
 		}
 	}
+	op := kmsg.ACLOperationRead
+	switch kmsg.Key(creq.kreq.Key()) {
+	case kmsg.OffsetFetch, kmsg.DescribeGroups, kmsg.ShareGroupDescribe:
+		op = kmsg.ACLOperationDescribe
+	case kmsg.DeleteGroups, kmsg.OffsetDelete:
+		op = kmsg.ACLOperationDelete
+	}
+	if !c.allowedGroup(creq, group, op) {
+		return kerr.GroupAuthorizationFailed
+	}
 	coordinator := c.coordinator(group).node
 	if coordinator != creq.cc.b.node {
 		return kerr.NotCoordinator
 	}
-	return nil
+	return c.coordFault(group)
 }
 
 func generateMemberID(clientID string, instanceID *string) string {
@@ -134,15 +167,16 @@ func generateMemberID(clientID string, instanceID *string) string {
 
 func (gs *groups) newGroup(name string) *group {
 	return &group{
-		c:         gs.c,
-		gs:        gs,
-		name:      name,
-		members:   make(map[string]*groupMember),
-		pending:   make(map[string]*groupMember),
-		protocols: make(map[string]int),
-		reqCh:     make(chan *clientReq),
-		controlCh: make(chan func()),
-		quitCh:    make(chan struct{}),
+		c:             gs.c,
+		gs:            gs,
+		name:          name,
+		members:       make(map[string]*groupMember),
+		pending:       make(map[string]*groupMember),
+		staticMembers: make(map[string]string),
+		protocols:     make(map[string]int),
+		reqCh:         make(chan *clientReq),
+		controlCh:     make(chan func()),
+		quitCh:        make(chan struct{}),
 	}
 }
 
@@ -219,39 +253,107 @@ start:
 	}
 }
 
-func (gs *groups) handleOffsetDelete(creq *clientReq) bool {
-	return gs.handleHijack(creq.kreq.(*kmsg.OffsetDeleteRequest).Group, creq)
+func (gs *groups) handleTxnOffsetCommit(creq *clientReq) {
+	if gs.gs == nil {
+		gs.gs = make(map[string]*group)
+	}
+	req := creq.kreq.(*kmsg.TxnOffsetCommitRequest)
+start:
+	g := gs.gs[req.Group]
+	if g == nil {
+		g = gs.newGroup(req.Group)
+		waitCommit := make(chan struct{})
+		gs.gs[req.Group] = g
+		go g.manage(func() { close(waitCommit) })
+		defer func() { <-waitCommit }()
+	}
+	select {
+	case g.reqCh <- creq:
+	case <-g.quitCh:
+		goto start
+	}
+}
+
+// handleOffsetDelete runs the request in the group's manage loop while the
+// cluster waits, so that the group can check the cluster's topics. This
+// returns false if the group does not exist.
+func (gs *groups) handleOffsetDelete(creq *clientReq) (*kmsg.OffsetDeleteResponse, bool) {
+	g := gs.gs[creq.kreq.(*kmsg.OffsetDeleteRequest).Group]
+	if g == nil {
+		return nil, false
+	}
+	var resp *kmsg.OffsetDeleteResponse
+	if !g.waitControl(func() { resp = g.handleOffsetDelete(creq) }) {
+		return nil, false
+	}
+	return resp, true
 }
 
 func (gs *groups) handleList(creq *clientReq) *kmsg.ListGroupsResponse {
 	req := creq.kreq.(*kmsg.ListGroupsRequest)
 	resp := req.ResponseKind().(*kmsg.ListGroupsResponse)
 
-	var states map[string]struct{}
-	if len(req.StatesFilter) > 0 {
-		states = make(map[string]struct{})
-		for _, state := range req.StatesFilter {
-			states[state] = struct{}{}
+	// As in Kafka, state and type filters are case insensitive.
+	filter := func(fs []string) map[string]struct{} {
+		if len(fs) == 0 {
+			return nil
+		}
+		m := make(map[string]struct{})
+		for _, f := range fs {
+			m[strings.ToLower(f)] = struct{}{}
+		}
+		return m
+	}
+	states, types := filter(req.StatesFilter), filter(req.TypesFilter)
+	matches := func(m map[string]struct{}, v string) bool {
+		if m == nil {
+			return true
 		}
+		_, ok := m[strings.ToLower(v)]
+		return ok
+	}
+
+	// As in Kafka, only groups the client can describe are listed, unless
+	// the client can describe the cluster.
+	allowedCluster := gs.c.allowedCluster(creq, kmsg.ACLOperationDescribe)
+	listed := func(name string) bool {
+		return gs.c.coordinator(name).node == creq.cc.b.node &&
+			(allowedCluster || gs.c.allowedGroup(creq, name, kmsg.ACLOperationDescribe))
+	}
+	add := func(name, protocolType, state, typ string) {
+		if !matches(states, state) || !matches(types, typ) {
+			return
+		}
+		sg := kmsg.NewListGroupsResponseGroup()
+		sg.Group = name
+		sg.ProtocolType = protocolType
+		sg.GroupState = state
+		sg.GroupType = typ
+		resp.Groups = append(resp.Groups, sg)
 	}
 
 	for _, g := range gs.gs {
-		if g.c.coordinator(g.name).node != creq.cc.b.node {
+		if !listed(g.name) {
 			continue
 		}
 		g.waitControl(func() {
-			if states != nil {
-				if _, ok := states[g.state.String()]; !ok {
-					return
-				}
+			if g.consumers != nil {
+				add(g.name, "consumer", g.consumerState(), "consumer")
+				return
 			}
-			sg := kmsg.NewListGroupsResponseGroup()
-			sg.Group = g.name
-			sg.ProtocolType = g.protocolType
-			sg.GroupState = g.state.String()
-			resp.Groups = append(resp.Groups, sg)
+			add(g.name, g.protocolType, g.state.String(), "classic")
 		})
 	}
+	// Share groups have their own group type, which was introduced in
+	// v5; older versions only list classic groups.
+	if req.Version >= 5 {
+		for _, g := range gs.c.shareGroups.gs {
+			if !listed(g.name) {
+				continue
+			}
+			add(g.name, "share", g.state(), "share")
+		}
+	}
 	return resp
 }
 
@@ -272,12 +374,20 @@ func (gs *groups) handleDescribe(creq *clientReq) *kmsg.DescribeGroupsResponse {
 			sg.ErrorCode = kerr.Code
 			continue
 		}
+		if req.IncludeAuthorizedOperations {
+			sg.AuthorizedOperations = gs.c.authorizedOps(creq, kmsg.ACLResourceTypeGroup, rg,
+				kmsg.ACLOperationRead, kmsg.ACLOperationDescribe, kmsg.ACLOperationDelete)
+		}
 		g, ok := gs.gs[rg]
 		if !ok {
 			sg.State = groupDead.String()
 			continue
 		}
 		if !g.waitControl(func() {
+			if g.consumers != nil {
+				sg.State = groupDead.String() // as in Kafka, only classic groups are described
+				return
+			}
 			sg.State = g.state.String()
 			sg.ProtocolType = g.protocolType
 			if g.state == groupStable {
@@ -286,6 +396,7 @@ func (gs *groups) handleDescribe(creq *clientReq) *kmsg.DescribeGroupsResponse {
 			for _, m := range g.members {
 				sm := kmsg.NewDescribeGroupsResponseGroupMember()
 				sm.MemberID = m.memberID
+				sm.InstanceID = m.instanceID
 				sm.ClientID = m.clientID
 				sm.ClientHost = m.clientHost
 				if g.state == groupStable {
@@ -334,6 +445,9 @@ func (gs *groups) handleDelete(creq *clientReq) *kmsg.DeleteGroupsResponse {
 			case groupDead:
 				sg.ErrorCode = kerr.GroupIDNotFound.Code
 			case groupEmpty:
+				g.commits.each(func(t string, p int32, _ *offsetCommit) {
+					gs.c.logOffset(rg, t, p, nil)
+				})
 				g.quitOnce()
 				delete(gs.gs, rg)
 			case groupPreparingRebalance, groupCompletingRebalance, groupStable:
@@ -405,6 +519,9 @@ func (gs *groups) handleOffsetFetch(creq *clientReq) *kmsg.OffsetFetchResponse {
 		if !g.waitControl(func() {
 			if rg.Topics == nil {
 				for t, ps := range g.commits {
+					if !gs.c.allowedTopic(creq, t, kmsg.ACLOperationDescribe) {
+						continue
+					}
 					st := kmsg.NewOffsetFetchResponseGroupTopic()
 					st.Topic = t
 					for p, c := range ps {
@@ -413,6 +530,7 @@ func (gs *groups) handleOffsetFetch(creq *clientReq) *kmsg.OffsetFetchResponse {
 						sp.Offset = c.offset
 						sp.LeaderEpoch = c.leaderEpoch
 						sp.Metadata = c.metadata
+						g.maybeUnstable(req.RequireStable, t, &sp)
 						st.Partitions = append(st.Partitions, sp)
 					}
 					sg.Topics = append(sg.Topics, st)
@@ -421,9 +539,17 @@ func (gs *groups) handleOffsetFetch(creq *clientReq) *kmsg.OffsetFetchResponse {
 				for _, t := range rg.Topics {
 					st := kmsg.NewOffsetFetchResponseGroupTopic()
 					st.Topic = t.Topic
+					allowed := gs.c.allowedTopic(creq, t.Topic, kmsg.ACLOperationDescribe)
 					for _, p := range t.Partitions {
 						sp := kmsg.NewOffsetFetchResponseGroupTopicPartition()
 						sp.Partition = p
+						if !allowed {
+							sp.Offset = -1
+							sp.LeaderEpoch = -1
+							sp.ErrorCode = kerr.TopicAuthorizationFailed.Code
+							st.Partitions = append(st.Partitions, sp)
+							continue
+						}
 						c, ok := g.commits.getp(t.Topic, p)
 						if !ok {
 							sp.Offset = -1
@@ -433,6 +559,7 @@ func (gs *groups) handleOffsetFetch(creq *clientReq) *kmsg.OffsetFetchResponse {
 							sp.LeaderEpoch = c.leaderEpoch
 							sp.Metadata = c.metadata
 						}
+						g.maybeUnstable(req.RequireStable, t.Topic, &sp)
 						st.Partitions = append(st.Partitions, sp)
 					}
 					sg.Topics = append(sg.Topics, st)
@@ -445,6 +572,24 @@ func (gs *groups) handleOffsetFetch(creq *clientReq) *kmsg.OffsetFetchResponse {
 	return resp
 }
 
+// maybeUnstable fails the partition with UNSTABLE_OFFSET_COMMIT if the
+// request requires stable offsets and any transaction has a pending offset
+// commit for the partition, as Kafka does (KIP-447).
+func (g *group) maybeUnstable(requireStable bool, t string, sp *kmsg.OffsetFetchResponseGroupTopicPartition) {
+	if !requireStable {
+		return
+	}
+	for _, pending := range g.txnCommits {
+		if _, ok := pending.getp(t, sp.Partition); ok {
+			sp.Offset = -1
+			sp.LeaderEpoch = -1
+			sp.Metadata = nil
+			sp.ErrorCode = kerr.UnstableOffsetCommit.Code
+			return
+		}
+	}
+}
+
 func (g *group) handleOffsetDelete(creq *clientReq) *kmsg.OffsetDeleteResponse {
 	req := creq.kreq.(*kmsg.OffsetDeleteRequest)
 	resp := req.ResponseKind().(*kmsg.OffsetDeleteResponse)
@@ -507,15 +652,30 @@ func (g *group) handleOffsetDelete(creq *clientReq) *kmsg.OffsetDeleteResponse {
 				}
 			}
 		}
+		for _, m := range g.consumers {
+			for _, topic := range g.subscribedTopics(m) {
+				subTopics[topic] = struct{}{}
+			}
+		}
 	}
 
 	for _, t := range req.Topics {
+		allowed := g.c.allowedTopic(creq, t.Topic, kmsg.ACLOperationRead)
 		for _, p := range t.Partitions {
+			if !allowed {
+				donep(t.Topic, p.Partition, kerr.TopicAuthorizationFailed.Code)
+				continue
+			}
+			if _, ok := g.c.data.tps.getp(t.Topic, p.Partition); !ok {
+				donep(t.Topic, p.Partition, kerr.UnknownTopicOrPartition.Code)
+				continue
+			}
 			if _, ok := subTopics[t.Topic]; ok {
 				donep(t.Topic, p.Partition, kerr.GroupSubscribedToTopic.Code)
 				continue
 			}
 			g.commits.delp(t.Topic, p.Partition)
+			g.c.logOffset(g.name, t.Topic, p.Partition, nil)
 			donep(t.Topic, p.Partition, 0)
 		}
 	}
@@ -540,6 +700,11 @@ func (g *group) manage(detachNew func()) {
 		}
 		detachNew()
 	}
+	// Consumer groups are created while the cluster waits on their first
+	// heartbeat, which cleans up an invalid join itself.
+	if detachNew == nil {
+		firstJoin = func(bool) {}
+	}
 
 	defer func() {
 		for _, m := range g.members {
@@ -552,6 +717,11 @@ func (g *group) manage(detachNew func()) {
 				m.t.Stop()
 			}
 		}
+		for _, m := range g.consumers {
+			if m.t != nil {
+				m.t.Stop()
+			}
+		}
 	}()
 
 	for {
@@ -575,8 +745,10 @@ func (g *group) manage(detachNew func()) {
 				var ok bool
 				kresp, ok = g.handleOffsetCommit(creq)
 				firstJoin(ok)
-			case *kmsg.OffsetDeleteRequest:
-				kresp = g.handleOffsetDelete(creq)
+			case *kmsg.TxnOffsetCommitRequest:
+				var ok bool
+				kresp, ok = g.handleTxnOffsetCommit(creq)
+				firstJoin(ok)
 			}
 			if kresp != nil {
 				g.reply(creq, kresp, nil)
@@ -620,14 +792,20 @@ func (g *group) handleJoin(creq *clientReq) (kmsg.Response, bool) {
 		resp.ErrorCode = kerr.Code
 		return resp, false
 	}
-	if req.InstanceID != nil {
-		resp.ErrorCode = kerr.InvalidGroupID.Code
-		return resp, false
-	}
 	if st := int64(req.SessionTimeoutMillis); st < g.c.cfg.minSessionTimeout.Milliseconds() || st > g.c.cfg.maxSessionTimeout.Milliseconds() {
 		resp.ErrorCode = kerr.InvalidSessionTimeout.Code
 		return resp, false
 	}
+	// As in Kafka, classic members cannot join a consumer group that has
+	// members. An empty consumer group is converted.
+	if g.consumers != nil {
+		if len(g.consumers) > 0 {
+			resp.ErrorCode = kerr.InconsistentGroupProtocol.Code
+			return resp, true
+		}
+		g.consumers = nil
+		g.protocolType = ""
+	}
 	if !g.protocolsMatch(req.ProtocolType, req.Protocols) {
 		resp.ErrorCode = kerr.InconsistentGroupProtocol.Code
 		return resp, false
@@ -635,25 +813,45 @@ func (g *group) handleJoin(creq *clientReq) (kmsg.Response, bool) {
 
 	// Clients first join with no member ID. For join v4+, we generate
 	// the member ID and add the member to pending. For v3 and below,
-	// we immediately enter rebalance.
+	// we immediately enter rebalance. Static members skip pending, and
+	// static members that are rejoining replace their old member ID.
 	if req.MemberID == "" {
+		if req.InstanceID != nil {
+			if id, ok := g.staticMembers[*req.InstanceID]; ok {
+				return g.rejoinStatic(g.members[id], creq, req, resp)
+			}
+		}
+		if max := g.c.cfg.maxGroupSize; max > 0 && len(g.members)+len(g.pending) >= max {
+			resp.ErrorCode = kerr.GroupMaxSizeReached.Code
+			return resp, false
+		}
 		memberID := generateMemberID(creq.cid, req.InstanceID)
 		resp.MemberID = memberID
 		m := &groupMember{
 			memberID:   memberID,
+			instanceID: req.InstanceID,
 			clientID:   creq.cid,
 			clientHost: creq.cc.conn.RemoteAddr().String(),
 			join:       req,
 		}
-		if req.Version >= 4 {
+		if req.InstanceID != nil {
+			g.staticMembers[*req.InstanceID] = memberID
+		} else if req.Version >= 4 {
 			g.addPendingRebalance(m)
 			resp.ErrorCode = kerr.MemberIDRequired.Code
 			return resp, true
 		}
-		g.addMemberAndRebalance(m, creq, req)
+		join := *req // members joining immediately need their ID in their saved join
+		join.MemberID = memberID
+		g.addMemberAndRebalance(m, creq, &join)
 		return nil, true
 	}
 
+	if g.fenced(req.InstanceID, req.MemberID) {
+		resp.ErrorCode = kerr.FencedInstanceID.Code
+		return resp, false
+	}
+
 	// Pending members rejoining immediately enters rebalance.
 	if m, ok := g.pending[req.MemberID]; ok {
 		g.addMemberAndRebalance(m, creq, req)
@@ -696,8 +894,8 @@ func (g *group) handleSync(creq *clientReq) kmsg.Response {
 		resp.ErrorCode = kerr.Code
 		return resp
 	}
-	if req.InstanceID != nil {
-		resp.ErrorCode = kerr.InvalidGroupID.Code
+	if g.fenced(req.InstanceID, req.MemberID) {
+		resp.ErrorCode = kerr.FencedInstanceID.Code
 		return resp
 	}
 	m, ok := g.members[req.MemberID]
@@ -726,6 +924,10 @@ func (g *group) handleSync(creq *clientReq) kmsg.Response {
 	case groupCompletingRebalance:
 		m.waitingReply = creq
 		if req.MemberID == g.leader {
+			if g.holdSync {
+				g.heldSync = req
+				return nil
+			}
 			g.completeLeaderSync(req)
 		}
 		return nil
@@ -747,8 +949,8 @@ func (g *group) handleHeartbeat(creq *clientReq) kmsg.Response {
 		resp.ErrorCode = kerr.Code
 		return resp
 	}
-	if req.InstanceID != nil {
-		resp.ErrorCode = kerr.InvalidGroupID.Code
+	if g.fenced(req.InstanceID, req.MemberID) {
+		resp.ErrorCode = kerr.FencedInstanceID.Code
 		return resp
 	}
 	m, ok := g.members[req.MemberID]
@@ -798,8 +1000,17 @@ func (g *group) handleLeave(creq *clientReq) kmsg.Response {
 
 		r := &resp.Members[len(resp.Members)-1]
 		if rm.InstanceID != nil {
-			r.ErrorCode = kerr.UnknownMemberID.Code
-			continue
+			id, ok := g.staticMembers[*rm.InstanceID]
+			if !ok {
+				r.ErrorCode = kerr.UnknownMemberID.Code
+				continue
+			}
+			if rm.MemberID != "" && rm.MemberID != id {
+				r.ErrorCode = kerr.FencedInstanceID.Code
+				continue
+			}
+			rm.MemberID = id
+			r.MemberID = id
 		}
 		if m, ok := g.members[rm.MemberID]; !ok {
 			if p, ok := g.pending[rm.MemberID]; !ok {
@@ -838,13 +1049,18 @@ func (g *group) handleOffsetCommit(creq *clientReq) (*kmsg.OffsetCommitResponse,
 		fillOffsetCommit(req, resp, kerr.Code)
 		return resp, false
 	}
-	if req.InstanceID != nil {
-		fillOffsetCommit(req, resp, kerr.InvalidGroupID.Code)
+	if g.fenced(req.InstanceID, req.MemberID) {
+		fillOffsetCommit(req, resp, kerr.FencedInstanceID.Code)
 		return resp, false
 	}
 
 	var m *groupMember
-	if len(g.members) > 0 {
+	if len(g.consumers) > 0 {
+		if kerr := g.validateConsumerCommit(req.MemberID, req.Generation); kerr != nil {
+			fillOffsetCommit(req, resp, kerr.Code)
+			return resp, true
+		}
+	} else if len(g.members) > 0 {
 		var ok bool
 		m, ok = g.members[req.MemberID]
 		if !ok {
@@ -865,7 +1081,9 @@ func (g *group) handleOffsetCommit(creq *clientReq) (*kmsg.OffsetCommitResponse,
 			return resp, false
 		}
 		if g.state != groupEmpty {
-			panic("invalid state: no members, but group not empty")
+			g.c.cfg.logger.Logf(LogLevelError, "group %s: invalid state %s: no members, but group not empty", g.name, g.state)
+			fillOffsetCommit(req, resp, kerr.UnknownServerError.Code)
+			return resp, false
 		}
 	}
 
@@ -875,8 +1093,11 @@ func (g *group) handleOffsetCommit(creq *clientReq) (*kmsg.OffsetCommitResponse,
 		return resp, true
 	case groupEmpty:
 		for _, t := range req.Topics {
+			if !g.c.allowedTopic(creq, t.Topic, kmsg.ACLOperationRead) {
+				continue
+			}
 			for _, p := range t.Partitions {
-				g.commits.set(t.Topic, p.Partition, offsetCommit{
+				g.setCommit(t.Topic, p.Partition, offsetCommit{
 					offset:      p.Offset,
 					leaderEpoch: p.LeaderEpoch,
 					metadata:    p.Metadata,
@@ -884,10 +1105,14 @@ func (g *group) handleOffsetCommit(creq *clientReq) (*kmsg.OffsetCommitResponse,
 			}
 		}
 		fillOffsetCommit(req, resp, 0)
+		g.failUnauthorizedCommits(creq, resp)
 	case groupPreparingRebalance, groupStable:
 		for _, t := range req.Topics {
+			if !g.c.allowedTopic(creq, t.Topic, kmsg.ACLOperationRead) {
+				continue
+			}
 			for _, p := range t.Partitions {
-				g.commits.set(t.Topic, p.Partition, offsetCommit{
+				g.setCommit(t.Topic, p.Partition, offsetCommit{
 					offset:      p.Offset,
 					leaderEpoch: p.LeaderEpoch,
 					metadata:    p.Metadata,
@@ -895,7 +1120,10 @@ func (g *group) handleOffsetCommit(creq *clientReq) (*kmsg.OffsetCommitResponse,
 			}
 		}
 		fillOffsetCommit(req, resp, 0)
-		g.updateHeartbeat(m)
+		g.failUnauthorizedCommits(creq, resp)
+		if m != nil {
+			g.updateHeartbeat(m)
+		}
 	case groupCompletingRebalance:
 		fillOffsetCommit(req, resp, kerr.RebalanceInProgress.Code)
 		g.updateHeartbeat(m)
@@ -903,11 +1131,97 @@ func (g *group) handleOffsetCommit(creq *clientReq) (*kmsg.OffsetCommitResponse,
 	return resp, true
 }
 
+func fillTxnOffsetCommit(req *kmsg.TxnOffsetCommitRequest, resp *kmsg.TxnOffsetCommitResponse, code int16) {
+	for _, t := range req.Topics {
+		st := kmsg.NewTxnOffsetCommitResponseTopic()
+		st.Topic = t.Topic
+		for _, p := range t.Partitions {
+			sp := kmsg.NewTxnOffsetCommitResponseTopicPartition()
+			sp.Partition = p.Partition
+			sp.ErrorCode = code
+			st.Partitions = append(st.Partitions, sp)
+		}
+		resp.Topics = append(resp.Topics, st)
+	}
+}
+
+// Handles a transactional commit. The commit is pending until the producer's
+// transaction ends; see Cluster.endTxn. The member and generation are only
+// validated if the client provides them (v3+) and the group has members.
+func (g *group) handleTxnOffsetCommit(creq *clientReq) (*kmsg.TxnOffsetCommitResponse, bool) {
+	req := creq.kreq.(*kmsg.TxnOffsetCommitRequest)
+	resp := req.ResponseKind().(*kmsg.TxnOffsetCommitResponse)
+
+	if kerr := g.c.validateGroup(creq, req.Group); kerr != nil {
+		fillTxnOffsetCommit(req, resp, kerr.Code)
+		return resp, false
+	}
+	if g.fenced(req.InstanceID, req.MemberID) {
+		fillTxnOffsetCommit(req, resp, kerr.FencedInstanceID.Code)
+		return resp, false
+	}
+	if req.MemberID != "" && len(g.members) > 0 {
+		if _, ok := g.members[req.MemberID]; !ok {
+			fillTxnOffsetCommit(req, resp, kerr.UnknownMemberID.Code)
+			return resp, true
+		}
+		if req.Generation != g.generation {
+			fillTxnOffsetCommit(req, resp, kerr.IllegalGeneration.Code)
+			return resp, true
+		}
+	}
+
+	if g.txnCommits == nil {
+		g.txnCommits = make(map[int64]*tps[offsetCommit])
+	}
+	pending := g.txnCommits[req.ProducerID]
+	if pending == nil {
+		pending = new(tps[offsetCommit])
+		g.txnCommits[req.ProducerID] = pending
+	}
+	for _, t := range req.Topics {
+		if !g.c.allowedTopic(creq, t.Topic, kmsg.ACLOperationRead) {
+			continue
+		}
+		for _, p := range t.Partitions {
+			pending.set(t.Topic, p.Partition, offsetCommit{
+				offset:      p.Offset,
+				leaderEpoch: p.LeaderEpoch,
+				metadata:    p.Metadata,
+			})
+		}
+	}
+	fillTxnOffsetCommit(req, resp, 0)
+	for i := range resp.Topics {
+		st := &resp.Topics[i]
+		if !g.c.allowedTopic(creq, st.Topic, kmsg.ACLOperationRead) {
+			for j := range st.Partitions {
+				st.Partitions[j].ErrorCode = kerr.TopicAuthorizationFailed.Code
+			}
+		}
+	}
+	return resp, true
+}
+
+// failUnauthorizedCommits fails the partitions of topics that the client is
+// not allowed to commit; the commits for these topics were skipped.
+func (g *group) failUnauthorizedCommits(creq *clientReq, resp *kmsg.OffsetCommitResponse) {
+	for i := range resp.Topics {
+		st := &resp.Topics[i]
+		if !g.c.allowedTopic(creq, st.Topic, kmsg.ACLOperationRead) {
+			for j := range st.Partitions {
+				st.Partitions[j].ErrorCode = kerr.TopicAuthorizationFailed.Code
+			}
+		}
+	}
+}
+
 // Transitions the group to the preparing rebalance state. We first need to
 // clear any member that is currently sitting in sync. If enough members have
 // entered join, we immediately proceed to completeRebalance, otherwise we
 // begin a wait timer.
 func (g *group) rebalance() {
+	g.heldSync = nil
 	if g.state == groupCompletingRebalance {
 		for _, m := range g.members {
 			m.assignment = nil
@@ -938,14 +1252,20 @@ func (g *group) rebalance() {
 		}
 	}
 	if g.tRebalance == nil {
-		g.tRebalance = time.AfterFunc(time.Duration(rebalanceTimeoutMs)*time.Millisecond, func() {
+		timeout := time.Duration(rebalanceTimeoutMs) * time.Millisecond
+		g.rebalanceDeadline = g.c.now().Add(timeout)
+		var t *time.Timer
+		t = time.AfterFunc(timeout, func() {
 			select {
 			case <-g.quitCh:
 			case g.controlCh <- func() {
-				g.completeRebalance()
+				if g.tRebalance == t && !g.c.now().Before(g.rebalanceDeadline) {
+					g.completeRebalance()
+				}
 			}:
 			}
 		})
+		g.tRebalance = t
 	}
 }
 
@@ -964,10 +1284,7 @@ func (g *group) completeRebalance() {
 			for _, p := range m.join.Protocols {
 				g.protocols[p.Name]--
 			}
-			delete(g.members, m.memberID)
-			if m.t != nil {
-				m.t.Stop()
-			}
+			g.deleteMember(m)
 			continue
 		}
 		if m.memberID == g.leader {
@@ -981,6 +1298,7 @@ func (g *group) completeRebalance() {
 	}
 	if len(g.members) == 0 {
 		g.state = groupEmpty
+		g.recordRebalance()
 		return
 	}
 	g.state = groupCompletingRebalance
@@ -1006,10 +1324,12 @@ func (g *group) completeRebalance() {
 		g.fillJoinResp(req, resp)
 		g.reply(m.waitingReply, resp, m)
 	}
+	g.recordRebalance()
 }
 
 // Transitions the group to stable, the final step of a rebalance.
 func (g *group) completeLeaderSync(req *kmsg.SyncGroupRequest) {
+	g.recordLeaderSync(req)
 	for _, m := range g.members {
 		m.assignment = nil
 	}
@@ -1031,6 +1351,14 @@ func (g *group) completeLeaderSync(req *kmsg.SyncGroupRequest) {
 		g.reply(m.waitingReply, resp, m)
 	}
 	g.state = groupStable
+	g.c.emit(Event{Type: EventGroupRebalanced, Group: g.name, Generation: g.generation})
+}
+
+// setCommit commits an offset for the group.
+func (g *group) setCommit(t string, p int32, oc offsetCommit) {
+	g.commits.set(t, p, oc)
+	g.c.logOffset(g.name, t, p, &oc)
+	g.c.emit(Event{Type: EventOffsetsCommitted, Topic: t, Partition: p, Offset: oc.offset, Group: g.name})
 }
 
 func (g *group) updateHeartbeat(m *groupMember) {
@@ -1039,6 +1367,86 @@ func (g *group) updateHeartbeat(m *groupMember) {
 	})
 }
 
+// Removes a member that left or timed out, releasing its instance ID if it is
+// a static member.
+func (g *group) deleteMember(m *groupMember) {
+	delete(g.members, m.memberID)
+	if m.instanceID != nil && g.staticMembers[*m.instanceID] == m.memberID {
+		delete(g.staticMembers, *m.instanceID)
+	}
+	if m.t != nil {
+		m.t.Stop()
+	}
+}
+
+// Returns whether a request using instanceID is from a static member that has
+// since been replaced by a new member ID.
+func (g *group) fenced(instanceID *string, memberID string) bool {
+	if instanceID == nil {
+		return false
+	}
+	id, ok := g.staticMembers[*instanceID]
+	return ok && id != memberID
+}
+
+// Handles a static member rejoining with no member ID, as it does after a
+// restart: the member takes over a new member ID, fencing the old one. If the
+// group is stable and the member still supports the group's protocol, this
+// does not rebalance and the member receives its previous assignment when it
+// syncs. As in Kafka, a rejoining leader is told to skip assignment (v9+), or
+// is given its old member ID as the leader ID so that it does not balance.
+func (g *group) rejoinStatic(m *groupMember, creq *clientReq, req *kmsg.JoinGroupRequest, resp *kmsg.JoinGroupResponse) (kmsg.Response, bool) {
+	if !m.waitingReply.empty() {
+		old := m.waitingReply.kreq.ResponseKind()
+		switch old := old.(type) {
+		case *kmsg.JoinGroupResponse:
+			old.ErrorCode = kerr.FencedInstanceID.Code
+			g.nJoining--
+		case *kmsg.SyncGroupResponse:
+			old.ErrorCode = kerr.FencedInstanceID.Code
+		}
+		g.reply(m.waitingReply, old, m)
+	}
+
+	oldID := m.memberID
+	delete(g.members, oldID)
+	newID := generateMemberID(creq.cid, req.InstanceID)
+	wasLeader := g.leader == oldID
+	if wasLeader {
+		g.leader = newID
+	}
+	m.memberID = newID
+	m.clientID = creq.cid
+	m.clientHost = creq.cc.conn.RemoteAddr().String()
+	g.members[newID] = m
+	g.staticMembers[*req.InstanceID] = newID
+
+	join := *req // the join is saved, and we need it to have the new ID
+	join.MemberID = newID
+	if g.state == groupStable && supportsProtocol(req.Protocols, g.protocol) {
+		for _, p := range m.join.Protocols {
+			g.protocols[p.Name]--
+		}
+		for _, p := range join.Protocols {
+			g.protocols[p.Name]++
+		}
+		m.join = &join
+		g.fillJoinResp(&join, resp)
+		if wasLeader {
+			if req.Version >= 9 {
+				resp.SkipAssignment = true
+			} else {
+				resp.LeaderID = oldID
+				resp.Members = nil
+			}
+		}
+		g.updateHeartbeat(m)
+		return resp, true
+	}
+	g.updateMemberAndRebalance(m, creq, &join)
+	return nil, true
+}
+
 func (g *group) addPendingRebalance(m *groupMember) {
 	g.pending[m.memberID] = m
 	g.atSessionTimeout(m, func() {
@@ -1057,19 +1465,59 @@ func (g *group) atSessionTimeout(m *groupMember, fn func()) {
 	if m.t != nil {
 		m.t.Stop()
 	}
-	timeout := time.Millisecond * time.Duration(m.join.SessionTimeoutMillis)
-	m.last = time.Now()
-	tfn := func() {
+	m.timeout = time.Millisecond * time.Duration(m.join.SessionTimeoutMillis)
+	m.last = g.c.now()
+	m.onTimeout = fn
+	var t *time.Timer
+	t = time.AfterFunc(m.timeout, func() {
 		select {
 		case <-g.quitCh:
 		case g.controlCh <- func() {
-			if time.Since(m.last) >= timeout {
-				fn()
+			if m.t == t {
+				g.expireSession(m, g.c.now())
 			}
 		}:
 		}
+	})
+	m.t = t
+}
+
+// expireSession runs the member's session timeout if its session has expired
+// by now. The timer only checks the session; with a plugged in Clock, a
+// session can also expire in AdvanceTime.
+func (g *group) expireSession(m *groupMember, now time.Time) {
+	if m.onTimeout == nil || now.Sub(m.last) < m.timeout {
+		return
+	}
+	fn := m.onTimeout
+	m.onTimeout = nil
+	if m.t != nil {
+		m.t.Stop()
+		m.t = nil
+	}
+	fn()
+}
+
+// expireTimeouts runs every session timeout and the rebalance timeout that
+// have passed by the cluster clock. This is called from AdvanceTime.
+func (g *group) expireTimeouts() {
+	now := g.c.now()
+	var ms []*groupMember
+	for _, m := range g.members {
+		ms = append(ms, m)
+	}
+	for _, m := range g.pending {
+		ms = append(ms, m)
+	}
+	for _, m := range ms {
+		g.expireSession(m, now)
+	}
+	for _, m := range g.consumers {
+		g.expireConsumer(m, now)
+	}
+	if g.tRebalance != nil && !now.Before(g.rebalanceDeadline) {
+		g.completeRebalance()
 	}
-	m.t = time.AfterFunc(timeout, tfn)
 }
 
 // This is used to update a member from a new join request, or to clear a
@@ -1088,10 +1536,7 @@ func (g *group) updateMemberAndRebalance(m *groupMember, waitingReply *clientReq
 		}
 		m.waitingReply = waitingReply
 	} else {
-		delete(g.members, m.memberID)
-		if m.t != nil {
-			m.t.Stop()
-		}
+		g.deleteMember(m)
 		if !m.waitingReply.empty() {
 			g.nJoining--
 		}
@@ -1136,6 +1581,15 @@ func (g *group) protocolsMatch(protocolType string, protocols []kmsg.JoinGroupRe
 	return false
 }
 
+func supportsProtocol(protocols []kmsg.JoinGroupRequestProtocol, protocol string) bool {
+	for _, p := range protocols {
+		if p.Name == protocol {
+			return true
+		}
+	}
+	return false
+}
+
 // Returns if a new join request is the same as an old request; if so, for
 // non-leaders, we just return the old join response.
 func (m *groupMember) sameJoin(req *kmsg.JoinGroupRequest) bool {
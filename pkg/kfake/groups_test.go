@@ -0,0 +1,605 @@
+package kfake
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestLag(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(1), SeedTopics(2, "t"))
+	adm := newTestAdmin(t, c)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if _, err := c.Lag("g"); err == nil {
+		t.Error("Lag of a missing group did not fail")
+	}
+
+	for p, n := range []int{5, 3} {
+		rs := make([]*kgo.Record, n)
+		for i := range rs {
+			rs[i] = kgo.StringRecord("v")
+		}
+		if _, err := c.ProduceTo("t", int32(p), rs...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var os kadm.Offsets
+	os.AddOffset("t", 0, 2, -1)
+	if err := adm.CommitAllOffsets(ctx, "g", os); err != nil {
+		t.Fatal(err)
+	}
+
+	lag, err := c.Lag("g")
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := map[int32]GroupPartitionLag{
+		0: {Topic: "t", Partition: 0, Committed: 2, Start: 0, End: 5, Lag: 3},
+		1: {Topic: "t", Partition: 1, Committed: -1, Start: 0, End: 3, Lag: 3},
+	}
+	if len(lag) != 1 || len(lag["t"]) != len(exp) {
+		t.Fatalf("got lag %+v, expected two partitions of t", lag)
+	}
+	for p, e := range exp {
+		if got := lag["t"][p]; got != e {
+			t.Errorf("partition %d: got %+v, expected %+v", p, got, e)
+		}
+	}
+
+	// An uncommitted partition lags from the log start, and lag is never
+	// negative.
+	if _, err := adm.DeleteRecords(ctx, kadm.Offsets{"t": {
+		1: {Topic: "t", Partition: 1, At: 1},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	os = nil
+	os.AddOffset("t", 0, 10, -1)
+	if err := adm.CommitAllOffsets(ctx, "g", os); err != nil {
+		t.Fatal(err)
+	}
+	if lag, err = c.Lag("g"); err != nil {
+		t.Fatal(err)
+	}
+	if got := lag["t"][1]; got.Start != 1 || got.Lag != 2 {
+		t.Errorf("after deleting records: got %+v, expected start 1 and lag 2", got)
+	}
+	if got := lag["t"][0]; got.Committed != 10 || got.Lag != 0 {
+		t.Errorf("after committing past the end: got %+v, expected lag 0", got)
+	}
+}
+
+func TestGroupRebalances(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(1), SeedTopics(2, "t"))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if _, err := c.GroupRebalances("g"); err == nil {
+		t.Error("GroupRebalances of a missing group did not fail")
+	}
+
+	assigned := make(chan struct{}, 1)
+	newTestClient(t, c,
+		kgo.ConsumerGroup("g"),
+		kgo.ConsumeTopics("t"),
+		kgo.ClientID("member"),
+		kgo.Balancers(kgo.RangeBalancer()),
+		kgo.OnPartitionsAssigned(func(context.Context, *kgo.Client, map[string][]int32) {
+			select {
+			case assigned <- struct{}{}:
+			default:
+			}
+		}),
+	)
+	select {
+	case <-assigned:
+	case <-ctx.Done():
+		t.Fatal("member was never assigned partitions")
+	}
+
+	rs, err := c.GroupRebalances("g")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rs) != 1 {
+		t.Fatalf("got %d rebalances, expected 1", len(rs))
+	}
+	r := rs[0]
+	if r.Generation != 1 || r.ProtocolType != "consumer" || r.Protocol != "range" {
+		t.Errorf("got generation %d, protocol %s/%s, expected 1, consumer/range", r.Generation, r.ProtocolType, r.Protocol)
+	}
+	if len(r.Members) != 1 || r.Members[0].MemberID != r.Leader || r.Members[0].ClientID != "member" {
+		t.Fatalf("got members %+v with leader %s, expected the one member leading", r.Members, r.Leader)
+	}
+
+	var meta kmsg.ConsumerMemberMetadata
+	if err := meta.ReadFrom(r.Members[0].Protocols[0].Metadata); err != nil || len(meta.Topics) != 1 || meta.Topics[0] != "t" {
+		t.Errorf("got member metadata %+v (err %v), expected a subscription to t", meta, err)
+	}
+	var assignment kmsg.ConsumerMemberAssignment
+	if err := assignment.ReadFrom(r.Assignments[r.Leader]); err != nil || len(assignment.Topics) != 1 || len(assignment.Topics[0].Partitions) != 2 {
+		t.Errorf("got assignment %+v (err %v), expected both partitions of t", assignment, err)
+	}
+}
+
+func TestGroupProtocols(t *testing.T) {
+	for _, test := range []struct {
+		balancer    kgo.GroupBalancer
+		generations int32 // generations to balance a second member
+		revoked     int   // partitions the first member gives up
+	}{
+		{kgo.RangeBalancer(), 2, 4},             // eager: everything is revoked
+		{kgo.CooperativeStickyBalancer(), 3, 2}, // cooperative: only what moves
+	} {
+		t.Run(test.balancer.ProtocolName(), func(t *testing.T) {
+			c := newTestCluster(t, NumBrokers(1), SeedTopics(4, "t"))
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			revoked := make(chan int, 1)
+			opts := []kgo.Opt{
+				kgo.ConsumerGroup("g"),
+				kgo.ConsumeTopics("t"),
+				kgo.Balancers(test.balancer),
+				kgo.HeartbeatInterval(100 * time.Millisecond),
+			}
+			newTestClient(t, c, append(opts, kgo.OnPartitionsRevoked(func(_ context.Context, _ *kgo.Client, rs map[string][]int32) {
+				if len(rs["t"]) > 0 {
+					select {
+					case revoked <- len(rs["t"]):
+					default:
+					}
+				}
+			}))...)
+			waitBalanced(ctx, t, c, 1)
+			newTestClient(t, c, opts...)
+			r := waitBalanced(ctx, t, c, 2)
+
+			if r.Generation != test.generations || r.Protocol != test.balancer.ProtocolName() {
+				t.Errorf("got generation %d with %s, expected %d with %s", r.Generation, r.Protocol, test.generations, test.balancer.ProtocolName())
+			}
+			select {
+			case n := <-revoked:
+				if n != test.revoked {
+					t.Errorf("first member revoked %d partitions, expected %d", n, test.revoked)
+				}
+			case <-ctx.Done():
+				t.Fatal("first member never revoked partitions")
+			}
+		})
+	}
+}
+
+// waitBalanced waits until the latest rebalance of group g has the given
+// number of members with the 4 partitions of t split evenly among them, and
+// returns that rebalance.
+func waitBalanced(ctx context.Context, t *testing.T, c *Cluster, members int) GroupRebalance {
+	t.Helper()
+	for {
+		if rs, _ := c.GroupRebalances("g"); len(rs) > 0 {
+			r := rs[len(rs)-1]
+			seen := make(map[int32]bool)
+			balanced := len(r.Members) == members && len(r.Assignments) == members
+			for _, a := range r.Assignments {
+				var assignment kmsg.ConsumerMemberAssignment
+				if err := assignment.ReadFrom(a); err != nil {
+					t.Fatal(err)
+				}
+				var n int
+				for _, at := range assignment.Topics {
+					for _, p := range at.Partitions {
+						if seen[p] {
+							t.Fatalf("partition %d assigned twice in generation %d", p, r.Generation)
+						}
+						seen[p] = true
+						n++
+					}
+				}
+				balanced = balanced && n == 4/members
+			}
+			if balanced {
+				return r
+			}
+		}
+		select {
+		case <-ctx.Done():
+			t.Fatalf("group never balanced across %d members", members)
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+// joinGroup joins a classic consumer group as a new member, returning the
+// member ID and generation.
+func joinGroup(ctx context.Context, t *testing.T, br *kgo.Broker, group string) (string, int32) {
+	t.Helper()
+	req := kmsg.NewPtrJoinGroupRequest()
+	req.Group = group
+	req.SessionTimeoutMillis = 10000
+	req.RebalanceTimeoutMillis = 10000
+	req.ProtocolType = "consumer"
+	meta := kmsg.NewConsumerMemberMetadata()
+	meta.Topics = []string{"t"}
+	p := kmsg.NewJoinGroupRequestProtocol()
+	p.Name, p.Metadata = "range", meta.AppendTo(nil)
+	req.Protocols = append(req.Protocols, p)
+	for {
+		resp, err := req.RequestWith(ctx, br)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.ErrorCode == kerr.MemberIDRequired.Code {
+			req.MemberID = resp.MemberID
+			continue
+		}
+		if err := kerr.ErrorForCode(resp.ErrorCode); err != nil {
+			t.Fatalf("join: %v", err)
+		}
+		return resp.MemberID, resp.Generation
+	}
+}
+
+func TestHoldGroupSync(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(1), SeedTopics(1, "t"))
+	br := newTestClient(t, c).Broker(0)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if _, err := c.HoldGroupSync("g"); err == nil {
+		t.Error("HoldGroupSync of a missing group did not fail")
+	}
+
+	member, generation := joinGroup(ctx, t, br, "g")
+	release, err := c.HoldGroupSync("g")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	synced := make(chan int16, 1)
+	go func() {
+		req := kmsg.NewPtrSyncGroupRequest()
+		req.Group, req.MemberID, req.Generation = "g", member, generation
+		a := kmsg.NewSyncGroupRequestGroupAssignment()
+		a.MemberID = member
+		req.GroupAssignment = append(req.GroupAssignment, a)
+		resp, err := req.RequestWith(ctx, br)
+		if err != nil {
+			synced <- -1
+			return
+		}
+		synced <- resp.ErrorCode
+	}()
+
+	commit := func(generation int32) int16 {
+		t.Helper()
+		req := kmsg.NewPtrOffsetCommitRequest()
+		req.Group, req.MemberID, req.Generation = "g", member, generation
+		rt := kmsg.NewOffsetCommitRequestTopic()
+		rt.Topic = "t"
+		rp := kmsg.NewOffsetCommitRequestTopicPartition()
+		rt.Partitions = append(rt.Partitions, rp)
+		req.Topics = append(req.Topics, rt)
+		resp, err := req.RequestWith(ctx, br)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.Topics[0].Partitions[0].ErrorCode
+	}
+
+	// The held sync does not complete, and commits are rejected until the
+	// hold is released.
+	if code := commit(generation); code != kerr.RebalanceInProgress.Code {
+		t.Errorf("commit during a held rebalance: got %v, expected REBALANCE_IN_PROGRESS", kerr.ErrorForCode(code))
+	}
+	if code := commit(generation - 1); code != kerr.IllegalGeneration.Code {
+		t.Errorf("commit with a stale generation: got %v, expected ILLEGAL_GENERATION", kerr.ErrorForCode(code))
+	}
+	select {
+	case code := <-synced:
+		t.Fatalf("sync completed with %v while held", kerr.ErrorForCode(code))
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	release()
+	release() // no-op
+	select {
+	case code := <-synced:
+		if code != 0 {
+			t.Fatalf("sync after release: %v", kerr.ErrorForCode(code))
+		}
+	case <-ctx.Done():
+		t.Fatal("sync did not complete after release")
+	}
+	if code := commit(generation); code != 0 {
+		t.Errorf("commit after the rebalance completed: %v", kerr.ErrorForCode(code))
+	}
+}
+
+func TestGroupMaxSize(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(1), SeedTopics(1, "t"), GroupMaxSize(1))
+	br := newTestClient(t, c).Broker(0)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	member, _ := joinGroup(ctx, t, br, "g")
+
+	join := func(memberID string) int16 {
+		t.Helper()
+		req := kmsg.NewPtrJoinGroupRequest()
+		req.Group, req.MemberID = "g", memberID
+		req.SessionTimeoutMillis = 10000
+		req.RebalanceTimeoutMillis = 10000
+		req.ProtocolType = "consumer"
+		p := kmsg.NewJoinGroupRequestProtocol()
+		p.Name = "range"
+		req.Protocols = append(req.Protocols, p)
+		resp, err := req.RequestWith(ctx, br)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.ErrorCode
+	}
+	if code := join(""); code != kerr.GroupMaxSizeReached.Code {
+		t.Errorf("joining a full group: got %v, expected GROUP_MAX_SIZE_REACHED", kerr.ErrorForCode(code))
+	}
+	if code := join(member); code != 0 {
+		t.Errorf("rejoining as an existing member: %v", kerr.ErrorForCode(code))
+	}
+}
+
+func TestStaticMembership(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(1), SeedTopics(1, "t"))
+	br := newTestClient(t, c).Broker(0)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	instance := "i"
+	join := func() *kmsg.JoinGroupResponse {
+		t.Helper()
+		req := kmsg.NewPtrJoinGroupRequest()
+		req.Group = "g"
+		req.InstanceID = &instance
+		req.SessionTimeoutMillis = 10000
+		req.RebalanceTimeoutMillis = 10000
+		req.ProtocolType = "consumer"
+		meta := kmsg.NewConsumerMemberMetadata()
+		meta.Topics = []string{"t"}
+		p := kmsg.NewJoinGroupRequestProtocol()
+		p.Name, p.Metadata = "range", meta.AppendTo(nil)
+		req.Protocols = append(req.Protocols, p)
+		resp, err := req.RequestWith(ctx, br)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := kerr.ErrorForCode(resp.ErrorCode); err != nil {
+			t.Fatalf("join: %v", err)
+		}
+		return resp
+	}
+	sync := func(member string, generation int32, assignment []byte) *kmsg.SyncGroupResponse {
+		t.Helper()
+		req := kmsg.NewPtrSyncGroupRequest()
+		req.Group = "g"
+		req.Generation = generation
+		req.MemberID = member
+		req.InstanceID = &instance
+		if assignment != nil {
+			a := kmsg.NewSyncGroupRequestGroupAssignment()
+			a.MemberID, a.MemberAssignment = member, assignment
+			req.GroupAssignment = append(req.GroupAssignment, a)
+		}
+		resp, err := req.RequestWith(ctx, br)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+
+	// Static members join without MEMBER_ID_REQUIRED.
+	first := join()
+	if first.LeaderID != first.MemberID || first.Generation != 1 {
+		t.Fatalf("first join: got leader %s generation %d, expected ourselves at generation 1", first.LeaderID, first.Generation)
+	}
+	if resp := sync(first.MemberID, first.Generation, []byte("assigned")); resp.ErrorCode != 0 || string(resp.MemberAssignment) != "assigned" {
+		t.Fatalf("first sync: got %v %q", kerr.ErrorForCode(resp.ErrorCode), resp.MemberAssignment)
+	}
+
+	// Rejoining after a restart takes a new member ID without a rebalance,
+	// and the old member ID is fenced.
+	second := join()
+	if second.MemberID == first.MemberID || second.Generation != first.Generation {
+		t.Errorf("rejoin: got member %s generation %d, expected a new member at generation %d", second.MemberID, second.Generation, first.Generation)
+	}
+	if resp := sync(second.MemberID, second.Generation, nil); resp.ErrorCode != 0 || string(resp.MemberAssignment) != "assigned" {
+		t.Errorf("rejoin sync: got %v %q, expected our previous assignment", kerr.ErrorForCode(resp.ErrorCode), resp.MemberAssignment)
+	}
+	hb := kmsg.NewPtrHeartbeatRequest()
+	hb.Group = "g"
+	hb.Generation = first.Generation
+	hb.MemberID = first.MemberID
+	hb.InstanceID = &instance
+	hbResp, err := hb.RequestWith(ctx, br)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hbResp.ErrorCode != kerr.FencedInstanceID.Code {
+		t.Errorf("heartbeat with the old member ID: got %v, expected FENCED_INSTANCE_ID", kerr.ErrorForCode(hbResp.ErrorCode))
+	}
+
+	describe := func() []kmsg.DescribeGroupsResponseGroupMember {
+		t.Helper()
+		req := kmsg.NewPtrDescribeGroupsRequest()
+		req.Groups = []string{"g"}
+		resp, err := req.RequestWith(ctx, br)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.Groups[0].Members
+	}
+	if ms := describe(); len(ms) != 1 || ms[0].MemberID != second.MemberID || ms[0].InstanceID == nil || *ms[0].InstanceID != instance {
+		t.Errorf("describe: got members %+v, expected %s with instance %s", ms, second.MemberID, instance)
+	}
+
+	// Static members can be removed by instance ID alone.
+	leave := kmsg.NewPtrLeaveGroupRequest()
+	leave.Group = "g"
+	lm := kmsg.NewLeaveGroupRequestMember()
+	lm.InstanceID = &instance
+	leave.Members = append(leave.Members, lm)
+	leaveResp, err := leave.RequestWith(ctx, br)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if leaveResp.ErrorCode != 0 || len(leaveResp.Members) != 1 || leaveResp.Members[0].ErrorCode != 0 {
+		t.Fatalf("leave: got %+v", leaveResp)
+	}
+	if ms := describe(); len(ms) != 0 {
+		t.Errorf("describe after leaving: got members %+v, expected none", ms)
+	}
+}
+
+func TestGroupAdmin(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(1), SeedTopics(2, "t"))
+	br := newTestClient(t, c).Broker(0)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// "empty" only has commits, and "active" has a member subscribed to t.
+	commit := kmsg.NewPtrOffsetCommitRequest()
+	commit.Group, commit.Generation = "empty", -1
+	rt := kmsg.NewOffsetCommitRequestTopic()
+	rt.Topic = "t"
+	for p := int32(0); p < 2; p++ {
+		rp := kmsg.NewOffsetCommitRequestTopicPartition()
+		rp.Partition = p
+		rt.Partitions = append(rt.Partitions, rp)
+	}
+	commit.Topics = append(commit.Topics, rt)
+	if resp, err := commit.RequestWith(ctx, br); err != nil || resp.Topics[0].Partitions[0].ErrorCode != 0 {
+		t.Fatalf("commit: %v %v", err, resp)
+	}
+	joinGroup(ctx, t, br, "active")
+
+	list := func(states, types []string) map[string]string {
+		t.Helper()
+		req := kmsg.NewPtrListGroupsRequest()
+		req.StatesFilter, req.TypesFilter = states, types
+		resp, err := req.RequestWith(ctx, br)
+		if err != nil {
+			t.Fatal(err)
+		}
+		groups := make(map[string]string)
+		for _, g := range resp.Groups {
+			if g.GroupType != "classic" {
+				t.Errorf("group %s: got type %q, expected classic", g.Group, g.GroupType)
+			}
+			groups[g.Group] = g.GroupState
+		}
+		return groups
+	}
+	if groups := list(nil, nil); len(groups) != 2 || groups["empty"] != "Empty" {
+		t.Errorf("listing every group: got %v", groups)
+	}
+	if groups := list([]string{"empty"}, nil); len(groups) != 1 || groups["empty"] == "" {
+		t.Errorf("listing empty groups: got %v", groups)
+	}
+	if groups := list(nil, []string{"CLASSIC"}); len(groups) != 2 {
+		t.Errorf("listing classic groups: got %v", groups)
+	}
+	if groups := list(nil, []string{"share"}); len(groups) != 0 {
+		t.Errorf("listing share groups: got %v", groups)
+	}
+
+	describe := kmsg.NewPtrDescribeGroupsRequest()
+	describe.Groups = []string{"active", "missing"}
+	describe.IncludeAuthorizedOperations = true
+	dresp, err := describe.RequestWith(ctx, br)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, g := range dresp.Groups {
+		if g.ErrorCode != 0 {
+			t.Errorf("describe %s: %v", g.Group, kerr.ErrorForCode(g.ErrorCode))
+		}
+		if g.AuthorizedOperations <= 0 {
+			t.Errorf("describe %s: got authorized operations %d", g.Group, g.AuthorizedOperations)
+		}
+	}
+	if dresp.Groups[0].State != "CompletingRebalance" || dresp.Groups[1].State != "Dead" {
+		t.Errorf("got states %s and %s, expected CompletingRebalance and Dead", dresp.Groups[0].State, dresp.Groups[1].State)
+	}
+
+	offsetDelete := func(group string, partitions ...int32) (int16, []int16) {
+		t.Helper()
+		req := kmsg.NewPtrOffsetDeleteRequest()
+		req.Group = group
+		rt := kmsg.NewOffsetDeleteRequestTopic()
+		rt.Topic = "t"
+		for _, p := range partitions {
+			rp := kmsg.NewOffsetDeleteRequestTopicPartition()
+			rp.Partition = p
+			rt.Partitions = append(rt.Partitions, rp)
+		}
+		req.Topics = append(req.Topics, rt)
+		resp, err := req.RequestWith(ctx, br)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var codes []int16
+		for _, rt := range resp.Topics {
+			for _, rp := range rt.Partitions {
+				codes = append(codes, rp.ErrorCode)
+			}
+		}
+		return resp.ErrorCode, codes
+	}
+	if code, _ := offsetDelete("missing", 0); code != kerr.GroupIDNotFound.Code {
+		t.Errorf("offset delete of a missing group: got %v, expected GROUP_ID_NOT_FOUND", kerr.ErrorForCode(code))
+	}
+	if _, codes := offsetDelete("active", 0); len(codes) != 1 || codes[0] != kerr.GroupSubscribedToTopic.Code {
+		t.Errorf("offset delete of a subscribed topic: got %v, expected GROUP_SUBSCRIBED_TO_TOPIC", codes)
+	}
+	if _, codes := offsetDelete("empty", 0, 5); len(codes) != 2 || codes[0] != 0 || codes[1] != kerr.UnknownTopicOrPartition.Code {
+		t.Errorf("offset delete: got %v, expected success and UNKNOWN_TOPIC_OR_PARTITION", codes)
+	}
+	fetch := kmsg.NewPtrOffsetFetchRequest()
+	fg := kmsg.NewOffsetFetchRequestGroup()
+	fg.Group = "empty"
+	fetch.Groups = append(fetch.Groups, fg)
+	fresp, err := fetch.RequestWith(ctx, br)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var committed []int32
+	for _, rt := range fresp.Groups[0].Topics {
+		for _, rp := range rt.Partitions {
+			committed = append(committed, rp.Partition)
+		}
+	}
+	if len(committed) != 1 || committed[0] != 1 {
+		t.Errorf("got committed partitions %v after deleting partition 0, expected [1]", committed)
+	}
+
+	del := kmsg.NewPtrDeleteGroupsRequest()
+	del.Groups = []string{"active", "missing", "empty"}
+	delResp, err := del.RequestWith(ctx, br)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, exp := range []error{kerr.NonEmptyGroup, kerr.GroupIDNotFound, nil} {
+		g := delResp.Groups[i]
+		if err := kerr.ErrorForCode(g.ErrorCode); err != exp {
+			t.Errorf("delete %s: got %v, expected %v", g.Group, err, exp)
+		}
+	}
+	if groups := list(nil, nil); len(groups) != 1 || groups["active"] == "" {
+		t.Errorf("listing after deleting: got %v, expected only active", groups)
+	}
+}
@@ -0,0 +1,56 @@
+package kfake
+
+import (
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+	"github.com/twmb/franz-go/pkg/kversion"
+)
+
+// newTestCluster returns a cluster that is closed when the test ends.
+func newTestCluster(t *testing.T, opts ...Opt) *Cluster {
+	t.Helper()
+	c, err := NewCluster(opts...)
+	if err != nil {
+		t.Fatalf("unable to create cluster: %v", err)
+	}
+	t.Cleanup(c.Close)
+	return c
+}
+
+// newTestClient returns a client for the cluster that is closed when the test
+// ends.
+func newTestClient(t *testing.T, c *Cluster, opts ...kgo.Opt) *kgo.Client {
+	t.Helper()
+	opts = append([]kgo.Opt{
+		kgo.SeedBrokers(c.ListenAddrs()...),
+		kgo.RetryTimeout(10 * time.Second),
+	}, opts...)
+	cl, err := kgo.NewClient(opts...)
+	if err != nil {
+		t.Fatalf("unable to create client: %v", err)
+	}
+	t.Cleanup(cl.Close)
+	return cl
+}
+
+// newTestAdmin returns an admin client for the cluster.
+func newTestAdmin(t *testing.T, c *Cluster, opts ...kgo.Opt) *kadm.Client {
+	t.Helper()
+	return kadm.NewClient(newTestClient(t, c, opts...))
+}
+
+// withKeys returns an option that allows the client to issue requests that
+// are not in its default max versions, such as share group requests. The
+// client cannot route these requests itself, so they must be issued to a
+// specific broker.
+func withKeys(keys ...int16) kgo.Opt {
+	v := kversion.Stable()
+	for _, key := range keys {
+		v.SetMaxKeyVersion(key, kmsg.RequestForKey(key).MaxVersion())
+	}
+	return kgo.MaxVersions(v)
+}
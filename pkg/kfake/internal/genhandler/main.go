@@ -0,0 +1,203 @@
+// Command genhandler scaffolds kfake request handlers for request keys kfake
+// does not yet handle.
+//
+// From the kfake directory, list the unhandled keys with
+//
+//	go run ./internal/genhandler -list
+//
+// and scaffold handlers by key or name with
+//
+//	go run ./internal/genhandler 57 DescribeProducers
+//
+// For each key, this writes NN_snake_name.go containing the version bound
+// registration and a handler stub that checks the request version and
+// returns an empty response, and adds the key's routing case to cluster.go.
+// The stub is meant to be filled in by hand; the version bounds default to
+// every version kmsg supports and should be narrowed to what the handler
+// actually implements.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+var (
+	dir  = flag.String("dir", ".", "kfake package directory")
+	list = flag.Bool("list", false, "list unhandled request keys and exit")
+)
+
+var handlerFile = regexp.MustCompile(`^(\d+)_.*\.go$`)
+
+// The routing switch in cluster.go ends with this default case; new cases
+// are inserted before it at the same indentation.
+var routeDefault = regexp.MustCompile(`(?m)^(\t*)default:\n\t*err = fmt\.Errorf\("unhandled key %v", k\)\n`)
+
+func main() {
+	flag.Parse()
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	handled, err := handledKeys(*dir)
+	if err != nil {
+		return err
+	}
+
+	if *list {
+		for k := int16(0); ; k++ {
+			req := kmsg.RequestForKey(k)
+			if req == nil {
+				if k > 100 {
+					return nil
+				}
+				continue
+			}
+			if !handled[k] {
+				fmt.Printf("%d\t%s\t(max version %d)\n", k, kmsg.NameForKey(k), req.MaxVersion())
+			}
+		}
+	}
+
+	if flag.NArg() == 0 {
+		return fmt.Errorf("usage: genhandler [-dir kfake] [-list] key|name...")
+	}
+	for _, arg := range flag.Args() {
+		k, err := parseKey(arg)
+		if err != nil {
+			return err
+		}
+		if handled[k] {
+			return fmt.Errorf("key %d (%s) is already handled", k, kmsg.NameForKey(k))
+		}
+		if err := scaffold(*dir, k); err != nil {
+			return err
+		}
+		handled[k] = true
+	}
+	return nil
+}
+
+// handledKeys returns the keys that have a handler file in dir.
+func handledKeys(dir string) (map[int16]bool, error) {
+	ents, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	handled := make(map[int16]bool)
+	for _, ent := range ents {
+		m := handlerFile.FindStringSubmatch(ent.Name())
+		if m == nil {
+			continue
+		}
+		k, err := strconv.ParseInt(m[1], 10, 16)
+		if err != nil {
+			return nil, fmt.Errorf("invalid handler file name %s: %v", ent.Name(), err)
+		}
+		handled[int16(k)] = true
+	}
+	return handled, nil
+}
+
+// parseKey parses a request key number or name, such as 57 or
+// DescribeProducers.
+func parseKey(arg string) (int16, error) {
+	if k, err := strconv.ParseInt(arg, 10, 16); err == nil {
+		if kmsg.RequestForKey(int16(k)) == nil {
+			return 0, fmt.Errorf("unknown request key %d", k)
+		}
+		return int16(k), nil
+	}
+	for k := int16(0); k <= 100; k++ {
+		if kmsg.RequestForKey(k) != nil && strings.EqualFold(kmsg.NameForKey(k), arg) {
+			return k, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown request %q", arg)
+}
+
+// snake converts a request name to the snake case used in handler file names,
+// keeping acronyms together: DescribeUserSCRAMCredentials becomes
+// describe_user_scram_credentials.
+func snake(name string) string {
+	rs := []rune(name)
+	var sb strings.Builder
+	for i, r := range rs {
+		if i > 0 && unicode.IsUpper(r) {
+			prevLower := unicode.IsLower(rs[i-1]) || unicode.IsDigit(rs[i-1])
+			nextLower := i+1 < len(rs) && unicode.IsLower(rs[i+1])
+			if prevLower || nextLower && unicode.IsUpper(rs[i-1]) {
+				sb.WriteByte('_')
+			}
+		}
+		sb.WriteRune(unicode.ToLower(r))
+	}
+	return sb.String()
+}
+
+const stub = `package kfake
+
+import (
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func init() { regKey(%[1]d, 0, %[3]d) }
+
+func (c *Cluster) handle%[2]s(creq *clientReq) (kmsg.Response, error) {
+	req := creq.kreq.(*kmsg.%[2]sRequest)
+	resp := req.ResponseKind().(*kmsg.%[2]sResponse)
+
+	if err := checkReqVersion(req.Key(), req.Version); err != nil {
+		return nil, err
+	}
+
+	// TODO: implement %[2]s.
+
+	return resp, nil
+}
+`
+
+func scaffold(dir string, k int16) error {
+	name := kmsg.NameForKey(k)
+	req := kmsg.RequestForKey(k)
+
+	src, err := format.Source([]byte(fmt.Sprintf(stub, k, name, req.MaxVersion())))
+	if err != nil {
+		return fmt.Errorf("unable to format %s handler: %v", name, err)
+	}
+	path := filepath.Join(dir, fmt.Sprintf("%02d_%s.go", k, snake(name)))
+	if err := os.WriteFile(path, src, 0o644); err != nil {
+		return err
+	}
+
+	clusterPath := filepath.Join(dir, "cluster.go")
+	cluster, err := os.ReadFile(clusterPath)
+	if err != nil {
+		return err
+	}
+	ms := routeDefault.FindAllSubmatchIndex(cluster, -1)
+	if len(ms) != 1 {
+		return fmt.Errorf("unable to find the request routing switch in %s", clusterPath)
+	}
+	at, indent := ms[0][0], string(cluster[ms[0][2]:ms[0][3]])
+	route := fmt.Sprintf("%[1]scase kmsg.%[2]s:\n%[1]s\tkresp, err = c.handle%[2]s(creq)\n", indent, name)
+	routed := string(cluster[:at]) + route + string(cluster[at:])
+	if err := os.WriteFile(clusterPath, []byte(routed), 0o644); err != nil {
+		return err
+	}
+
+	fmt.Printf("wrote %s and routed %s in %s\n", path, name, clusterPath)
+	return nil
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSnake(t *testing.T) {
+	for _, test := range []struct {
+		name, exp string
+	}{
+		{"Produce", "produce"},
+		{"DescribeUserSCRAMCredentials", "describe_user_scram_credentials"},
+		{"OffsetForLeaderEpoch", "offset_for_leader_epoch"},
+		{"LeaderAndISR", "leader_and_isr"},
+		{"InitProducerID", "init_producer_id"},
+	} {
+		if got := snake(test.name); got != test.exp {
+			t.Errorf("snake(%s): got %s, expected %s", test.name, got, test.exp)
+		}
+	}
+}
+
+func TestParseKey(t *testing.T) {
+	for _, arg := range []string{"40", "ExpireDelegationToken", "expiredelegationtoken"} {
+		if k, err := parseKey(arg); err != nil || k != 40 {
+			t.Errorf("parseKey(%s): got %d %v, expected 40", arg, k, err)
+		}
+	}
+	for _, arg := range []string{"9999", "Bogus"} {
+		if _, err := parseKey(arg); err == nil {
+			t.Errorf("parseKey(%s): got no error", arg)
+		}
+	}
+}
+
+func TestScaffold(t *testing.T) {
+	// The kfake package is two directories up; scaffolding into a copy of
+	// its cluster.go ensures we keep up with the routing switch.
+	kfake := filepath.Join("..", "..")
+	handled, err := handledKeys(kfake)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !handled[0] || handled[40] {
+		t.Fatalf("got handled keys %v, expected Produce but not ExpireDelegationToken", handled)
+	}
+
+	dir := t.TempDir()
+	cluster, err := os.ReadFile(filepath.Join(kfake, "cluster.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "cluster.go"), cluster, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := scaffold(dir, 40); err != nil {
+		t.Fatal(err)
+	}
+
+	fset := token.NewFileSet()
+	if _, err := parser.ParseFile(fset, filepath.Join(dir, "40_expire_delegation_token.go"), nil, 0); err != nil {
+		t.Errorf("scaffolded handler does not parse: %v", err)
+	}
+	if _, err := parser.ParseFile(fset, filepath.Join(dir, "cluster.go"), nil, 0); err != nil {
+		t.Errorf("routed cluster.go does not parse: %v", err)
+	}
+	routed, err := os.ReadFile(filepath.Join(dir, "cluster.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := routeDefault.FindSubmatch(routed)
+	if m == nil {
+		t.Fatal("routed cluster.go lost its default case")
+	}
+	indent := string(m[1])
+	route := indent + "case kmsg.ExpireDelegationToken:\n" + indent + "\tkresp, err = c.handleExpireDelegationToken(creq)\n" + indent + "default:"
+	if !strings.Contains(string(routed), route) {
+		t.Errorf("routed cluster.go is missing the case before the default:\n%s", route)
+	}
+
+	// Without a routing switch, we fail rather than guess.
+	if err := os.WriteFile(filepath.Join(dir, "cluster.go"), []byte("package kfake\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := scaffold(dir, 41); err == nil {
+		t.Error("scaffolding without a routing switch: got no error")
+	}
+}
@@ -0,0 +1,171 @@
+// Package kadmtest provides a kadm.Client backed by an in-process kfake
+// cluster, so that admin tooling can be tested against realistic responses
+// without running a real cluster.
+//
+// The cluster can be seeded with topics, records, committed group offsets, and
+// ACLs. Seeding goes through the same requests that kadm itself issues, so
+// everything that is seeded is visible exactly as a real client would see it.
+// Note that kfake stores ACLs but does not enforce them.
+package kadmtest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kfake"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// Opt is an option to configure and seed the cluster backing New.
+type Opt interface {
+	apply(*cfg)
+}
+
+type opt struct{ fn func(*cfg) }
+
+func (opt opt) apply(cfg *cfg) { opt.fn(cfg) }
+
+type (
+	topic struct {
+		name       string
+		partitions int32
+		configs    map[string]*string
+	}
+
+	groupOffsets struct {
+		group   string
+		offsets map[string]map[int32]int64
+	}
+
+	cfg struct {
+		clusterOpts []kfake.Opt
+		clientOpts  []kgo.Opt
+		timeout     time.Duration
+
+		topics  []topic
+		records []*kgo.Record
+		groups  []groupOffsets
+		acls    []*kadm.ACLBuilder
+	}
+)
+
+// ClusterOpts adds options to use when creating the kfake cluster. By
+// default, the cluster has one broker.
+func ClusterOpts(opts ...kfake.Opt) Opt {
+	return opt{func(cfg *cfg) { cfg.clusterOpts = append(cfg.clusterOpts, opts...) }}
+}
+
+// ClientOpts adds options to use when creating the client that the returned
+// kadm.Client wraps, in addition to the cluster's seed brokers.
+func ClientOpts(opts ...kgo.Opt) Opt {
+	return opt{func(cfg *cfg) { cfg.clientOpts = append(cfg.clientOpts, opts...) }}
+}
+
+// SeedTimeout sets how long seeding the cluster can take before the test
+// fails, overriding the default 10s.
+func SeedTimeout(timeout time.Duration) Opt {
+	return opt{func(cfg *cfg) { cfg.timeout = timeout }}
+}
+
+// Topic creates a topic with the given number of partitions and optional
+// configs. The replication factor is the number of brokers in the cluster, up
+// to 3.
+func Topic(name string, partitions int32, configs map[string]*string) Opt {
+	return opt{func(cfg *cfg) { cfg.topics = append(cfg.topics, topic{name, partitions, configs}) }}
+}
+
+// Records produces records after all topics are created, in order. Records
+// without a topic, or to topics that are not created with Topic, fail seeding.
+// Records without an explicit partition are produced to partition 0 so that
+// offsets are predictable.
+func Records(rs ...*kgo.Record) Opt {
+	return opt{func(cfg *cfg) { cfg.records = append(cfg.records, rs...) }}
+}
+
+// GroupOffsets commits offsets for an (empty) group after records are
+// produced. Committed offsets must be at most the end offset of the
+// partition, which is the number of records produced to it.
+func GroupOffsets(group string, offsets map[string]map[int32]int64) Opt {
+	return opt{func(cfg *cfg) { cfg.groups = append(cfg.groups, groupOffsets{group, offsets}) }}
+}
+
+// ACLs creates all ACLs described by the builder.
+func ACLs(b *kadm.ACLBuilder) Opt {
+	return opt{func(cfg *cfg) { cfg.acls = append(cfg.acls, b) }}
+}
+
+// New returns an admin client connected to a new kfake cluster that is seeded
+// according to the options, along with the cluster. The test fails if the
+// cluster or client cannot be created, or if anything fails to be seeded. The
+// client and cluster are closed when the test and all its subtests complete.
+func New(t testing.TB, opts ...Opt) (*kadm.Client, *kfake.Cluster) {
+	t.Helper()
+
+	cfg := cfg{
+		clusterOpts: []kfake.Opt{kfake.NumBrokers(1)},
+		timeout:     10 * time.Second,
+	}
+	for _, opt := range opts {
+		opt.apply(&cfg)
+	}
+
+	c := kfake.NewTestCluster(t, cfg.clusterOpts...)
+	adm := kadm.NewClient(c.NewTestClient(t, cfg.clientOpts...))
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.timeout)
+	defer cancel()
+
+	rf := int16(len(c.ListenAddrs()))
+	if rf > 3 {
+		rf = 3
+	}
+	for _, topic := range cfg.topics {
+		if _, err := adm.CreateTopic(ctx, topic.partitions, rf, topic.configs, topic.name); err != nil {
+			t.Fatalf("unable to seed topic %q: %v", topic.name, err)
+		}
+	}
+
+	if len(cfg.records) > 0 {
+		for _, r := range cfg.records {
+			if r.Topic == "" {
+				t.Fatal("unable to seed record without a topic")
+			}
+		}
+		cl, err := kgo.NewClient(kgo.SeedBrokers(c.ListenAddrs()...), kgo.RecordPartitioner(kgo.ManualPartitioner()))
+		if err != nil {
+			t.Fatalf("unable to create client to seed records: %v", err)
+		}
+		defer cl.Close()
+		if err := cl.ProduceSync(ctx, cfg.records...).FirstErr(); err != nil {
+			t.Fatalf("unable to seed records: %v", err)
+		}
+	}
+
+	for _, g := range cfg.groups {
+		var os kadm.Offsets
+		for t, ps := range g.offsets {
+			for p, o := range ps {
+				os.AddOffset(t, p, o, -1)
+			}
+		}
+		if err := adm.CommitAllOffsets(ctx, g.group, os); err != nil {
+			t.Fatalf("unable to seed offsets for group %q: %v", g.group, err)
+		}
+	}
+
+	for _, b := range cfg.acls {
+		results, err := adm.CreateACLs(ctx, b)
+		if err != nil {
+			t.Fatalf("unable to seed acls: %v", err)
+		}
+		for _, r := range results {
+			if r.Err != nil {
+				t.Fatalf("unable to seed acl for principal %q on %s %q: %v (%s)", r.Principal, r.Type, r.Name, r.Err, r.ErrMessage)
+			}
+		}
+	}
+
+	return adm, c
+}
@@ -0,0 +1,82 @@
+package kadmtest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func TestNew(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	retention := "1000"
+	adm, _ := New(t,
+		Topic("a", 2, map[string]*string{"retention.ms": &retention}),
+		Records(
+			&kgo.Record{Topic: "a", Value: []byte("v0")},
+			&kgo.Record{Topic: "a", Value: []byte("v1")},
+			&kgo.Record{Topic: "a", Partition: 1, Value: []byte("v2")},
+		),
+		GroupOffsets("g", map[string]map[int32]int64{"a": {0: 1}}),
+		ACLs(kadm.NewACLs().Allow("User:alice").Topics("a").Operations(kadm.OpRead).ResourcePatternType(kadm.ACLPatternLiteral)),
+	)
+
+	ends, err := adm.ListEndOffsets(ctx, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for p, exp := range map[int32]int64{0: 2, 1: 1} {
+		if o, _ := ends.Lookup("a", p); o.Offset != exp {
+			t.Errorf("partition %d: got end offset %d, expected %d", p, o.Offset, exp)
+		}
+	}
+
+	rcs, err := adm.DescribeTopicConfigs(ctx, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rc, err := rcs.On("a", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var found bool
+	for _, c := range rc.Configs {
+		found = found || c.Key == "retention.ms" && c.MaybeValue() == retention
+	}
+	if !found {
+		t.Error("seeded topic config retention.ms=1000 was not set")
+	}
+
+	os, err := adm.FetchOffsets(ctx, "g")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o, _ := os.Lookup("a", 0); o.At != 1 {
+		t.Errorf("got committed offset %d, expected 1", o.At)
+	}
+
+	all := kadm.NewACLs().AnyResource().Allow().AllowHosts().Operations(kadm.OpAny).ResourcePatternType(kadm.ACLPatternAny)
+	described, err := adm.DescribeACLs(ctx, all)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(described) != 1 || len(described[0].Described) != 1 || described[0].Described[0].Principal != "User:alice" {
+		t.Errorf("got acls %+v, expected the one seeded acl", described)
+	}
+	deleted, err := adm.DeleteACLs(ctx, all)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deleted) != 1 || len(deleted[0].Deleted) != 1 {
+		t.Errorf("got deleted acls %+v, expected the one seeded acl", deleted)
+	}
+	if described, err = adm.DescribeACLs(ctx, all); err != nil {
+		t.Fatal(err)
+	} else if len(described[0].Described) != 0 {
+		t.Errorf("got acls %+v after deleting, expected none", described)
+	}
+}
@@ -0,0 +1,73 @@
+package kfake
+
+import (
+	"fmt"
+
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// legacyMinVersions are the minimum request versions that Kafka 4.0 supports
+// per KIP-896, which removed every version older than Kafka 2.1's. Keys not
+// listed kept all of their versions.
+var legacyMinVersions = map[int16]int16{
+	0:  3, // Produce
+	1:  4, // Fetch
+	2:  1, // ListOffsets
+	8:  2, // OffsetCommit
+	9:  1, // OffsetFetch
+	11: 2, // JoinGroup
+	19: 2, // CreateTopics
+	20: 1, // DeleteTopics
+	23: 2, // OffsetForLeaderEpoch
+	29: 1, // DescribeACLs
+	30: 1, // CreateACLs
+	31: 1, // DeleteACLs
+	32: 1, // DescribeConfigs
+	34: 1, // AlterReplicaLogDirs
+	35: 1, // DescribeLogDirs
+	38: 1, // CreateDelegationToken
+	39: 1, // RenewDelegationToken
+	40: 1, // ExpireDelegationToken
+	41: 1, // DescribeDelegationToken
+}
+
+// RemoveLegacyProtocols emulates Kafka 4.0, which removed support for request
+// versions older than Kafka 2.1 (KIP-896). ApiVersions responses advertise
+// the raised minimum versions, and requests using a removed version cause
+// the connection to be closed, as Kafka does when it cannot parse a request.
+// This can be used to verify that clients and applications work against
+// clusters that no longer support legacy protocols.
+func RemoveLegacyProtocols() Opt {
+	return opt{func(cfg *cfg) { cfg.removeLegacy = true }}
+}
+
+// rejectLegacyVersion returns an error if the request uses a version removed
+// with RemoveLegacyProtocols.
+func (c *Cluster) rejectLegacyVersion(kreq kmsg.Request) error {
+	if !c.cfg.removeLegacy {
+		return nil
+	}
+	if min, ok := legacyMinVersions[kreq.Key()]; ok && kreq.GetVersion() < min {
+		return fmt.Errorf("%s version %d was removed in Kafka 4.0, the minimum version is %d", kmsg.NameForKey(kreq.Key()), kreq.GetVersion(), min)
+	}
+	return nil
+}
+
+// withoutLegacyVersions returns keys with minimum versions raised per
+// RemoveLegacyProtocols, dropping keys with no versions remaining.
+func (c *Cluster) withoutLegacyVersions(keys []kmsg.ApiVersionsResponseApiKey) []kmsg.ApiVersionsResponseApiKey {
+	if !c.cfg.removeLegacy {
+		return keys
+	}
+	raised := make([]kmsg.ApiVersionsResponseApiKey, 0, len(keys))
+	for _, k := range keys {
+		if min, ok := legacyMinVersions[k.ApiKey]; ok && k.MinVersion < min {
+			if k.MaxVersion < min {
+				continue
+			}
+			k.MinVersion = min
+		}
+		raised = append(raised, k)
+	}
+	return raised
+}
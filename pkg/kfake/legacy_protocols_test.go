@@ -0,0 +1,61 @@
+package kfake
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestRemoveLegacyProtocols(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(1), SeedTopics(1, "t"), RemoveLegacyProtocols())
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	resp, err := kmsg.NewPtrApiVersionsRequest().RequestWith(ctx, newTestClient(t, c).Broker(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mins := make(map[int16]int16)
+	for _, k := range resp.ApiKeys {
+		mins[k.ApiKey] = k.MinVersion
+	}
+	for key, exp := range map[int16]int16{0: 3, 1: 4, 2: 1, 18: 0} {
+		if got, ok := mins[key]; !ok || got != exp {
+			t.Errorf("%s: got min version %d (advertised %v), expected %d", kmsg.NameForKey(key), got, ok, exp)
+		}
+	}
+
+	// A removed version closes the connection; the first remaining
+	// version is served.
+	listOffsets := func(version int16) error {
+		t.Helper()
+		conn, err := net.DialTimeout("tcp", c.ListenAddrs()[0], time.Second)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+		conn.SetDeadline(time.Now().Add(5 * time.Second))
+		req := kmsg.NewPtrListOffsetsRequest()
+		req.Version = version
+		rt := kmsg.NewListOffsetsRequestTopic()
+		rt.Topic = "t"
+		rt.Partitions = append(rt.Partitions, kmsg.NewListOffsetsRequestTopicPartition())
+		req.Topics = append(req.Topics, rt)
+		if _, err := conn.Write(appendFrame(nil, req, 1)); err != nil {
+			t.Fatal(err)
+		}
+		_, err = conn.Read(make([]byte, 4))
+		return err
+	}
+	if err := listOffsets(0); !errors.Is(err, io.EOF) {
+		t.Errorf("ListOffsets v0: got %v, expected the connection to be closed", err)
+	}
+	if err := listOffsets(1); err != nil {
+		t.Errorf("ListOffsets v1: %v", err)
+	}
+}
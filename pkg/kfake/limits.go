@@ -0,0 +1,60 @@
+package kfake
+
+import (
+	"fmt"
+)
+
+// ResourceLimits are cluster wide limits on resources, emulating the quotas
+// of managed Kafka offerings. A zero limit is unlimited.
+type ResourceLimits struct {
+	// Topics limits the number of topics. Creating topics past the limit
+	// fails with POLICY_VIOLATION.
+	Topics int
+	// Partitions limits the total number of partitions across all
+	// topics. Creating topics or partitions past the limit fails with
+	// POLICY_VIOLATION.
+	Partitions int
+	// Connections limits the number of concurrently open connections
+	// across all brokers. Connections past the limit are closed as soon
+	// as they are accepted.
+	Connections int
+}
+
+// LimitResources limits the cluster's resources, which is useful to test the
+// quota exceeded paths of provisioning tooling. The limits apply to topics
+// created with CreateTopics, CreatePartitions, and automatic topic creation;
+// topics seeded with SeedTopics are counted but never rejected.
+func LimitResources(l ResourceLimits) Opt {
+	return opt{func(cfg *cfg) { cfg.limits = l }}
+}
+
+// checkResourceLimits returns an error if adding the topics and partitions
+// would exceed the cluster's resource limits.
+func (c *Cluster) checkResourceLimits(topics, partitions int) error {
+	l := &c.cfg.limits
+	if l.Topics > 0 && len(c.data.tps)+topics > l.Topics {
+		return fmt.Errorf("Unable to create topic: the cluster is limited to %d topics", l.Topics)
+	}
+	if l.Partitions > 0 {
+		var have int
+		for _, ps := range c.data.tps {
+			have += len(ps)
+		}
+		if have+partitions > l.Partitions {
+			return fmt.Errorf("Unable to create partitions: the cluster has %d partitions and is limited to %d", have, l.Partitions)
+		}
+	}
+	return nil
+}
+
+// newTopicPartitions returns the number of partitions mkt would create for
+// the topic.
+func (c *Cluster) newTopicPartitions(t string, nparts int) int {
+	if nparts < 0 {
+		if p := c.topicPolicy(t); p != nil && p.partitions > 0 {
+			return int(p.partitions)
+		}
+		return c.cfg.defaultNumParts
+	}
+	return nparts
+}
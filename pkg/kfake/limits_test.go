@@ -0,0 +1,82 @@
+package kfake
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestLimitResources(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(1), SeedTopics(1, "seed"), LimitResources(ResourceLimits{Topics: 2, Partitions: 4}))
+	adm := newTestAdmin(t, c)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	create := func(topic string, partitions int32) error {
+		t.Helper()
+		resp, err := adm.CreateTopic(ctx, partitions, 1, nil, topic)
+		if err != nil && resp.Err == nil {
+			t.Fatal(err)
+		}
+		return resp.Err
+	}
+	if err := create("a", 4); !errors.Is(err, kerr.PolicyViolation) {
+		t.Errorf("creating past the partition limit: got %v, expected POLICY_VIOLATION", err)
+	}
+	if err := create("a", 2); err != nil {
+		t.Fatalf("creating within the limits: %v", err)
+	}
+	if err := create("b", 1); !errors.Is(err, kerr.PolicyViolation) {
+		t.Errorf("creating past the topic limit: got %v, expected POLICY_VIOLATION", err)
+	}
+
+	for _, test := range []struct {
+		total int
+		err   error
+	}{
+		{4, kerr.PolicyViolation},
+		{3, nil},
+	} {
+		resps, err := adm.UpdatePartitions(ctx, test.total, "a")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := resps["a"].Err; !errors.Is(err, test.err) {
+			t.Errorf("growing to %d partitions: got %v, expected %v", test.total, err, test.err)
+		}
+	}
+}
+
+func TestLimitConnections(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(2), LimitResources(ResourceLimits{Connections: 2}))
+
+	// The limit is across all brokers. We wait for each connection to be
+	// served before opening the next, so that we know which is rejected.
+	var conns []net.Conn
+	defer func() {
+		for _, conn := range conns {
+			conn.Close()
+		}
+	}()
+	for i, addr := range append(c.ListenAddrs(), c.ListenAddrs()[0]) {
+		conn, err := net.DialTimeout("tcp", addr, time.Second)
+		if err != nil {
+			t.Fatal(err)
+		}
+		conns = append(conns, conn)
+		conn.SetDeadline(time.Now().Add(5 * time.Second))
+		if _, err := conn.Write(appendFrame(nil, kmsg.NewPtrApiVersionsRequest(), 1)); err != nil {
+			t.Fatal(err)
+		}
+		// The rejected connection is closed, or reset because it was
+		// closed with our unread request.
+		if _, err := conn.Read(make([]byte, 1)); (err != nil) != (i == 2) {
+			t.Errorf("connection %d: got read error %v, expected closed %v", i, err, i == 2)
+		}
+	}
+}
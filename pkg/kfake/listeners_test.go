@@ -0,0 +1,87 @@
+package kfake
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+	"github.com/twmb/franz-go/pkg/sasl/plain"
+)
+
+func TestListeners(t *testing.T) {
+	certs, err := NewTLSCerts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := newTestCluster(t, NumBrokers(2),
+		Superuser("PLAIN", "u", "p"),
+		Listener("SSL", certs.ServerConfig(false), false),
+		Listener("SASL_PLAINTEXT", nil, true),
+	)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if addrs := c.ListenerAddrs("missing"); addrs != nil {
+		t.Errorf("got addrs %v for a missing listener, expected nil", addrs)
+	}
+
+	// brokers returns the sorted broker addresses advertised to the
+	// client.
+	brokers := func(cl *kgo.Client) ([]string, error) {
+		resp, err := kmsg.NewPtrMetadataRequest().RequestWith(ctx, cl)
+		if err != nil {
+			return nil, err
+		}
+		var addrs []string
+		for _, b := range resp.Brokers {
+			addrs = append(addrs, net.JoinHostPort(b.Host, strconv.Itoa(int(b.Port))))
+		}
+		sort.Strings(addrs)
+		return addrs, nil
+	}
+	sorted := func(addrs []string) []string {
+		addrs = append([]string(nil), addrs...)
+		sort.Strings(addrs)
+		return addrs
+	}
+
+	for _, test := range []struct {
+		name  string
+		addrs []string
+		opts  []kgo.Opt
+		ok    bool
+	}{
+		{"PLAINTEXT", c.ListenAddrs(), nil, true},
+		{"SSL", c.ListenerAddrs("SSL"), []kgo.Opt{kgo.DialTLSConfig(certs.ClientConfig(nil))}, true},
+		{"SSL without TLS", c.ListenerAddrs("SSL"), nil, false},
+		{"SASL_PLAINTEXT", c.ListenerAddrs("SASL_PLAINTEXT"), []kgo.Opt{kgo.SASL(plain.Auth{User: "u", Pass: "p"}.AsMechanism())}, true},
+		{"SASL_PLAINTEXT without SASL", c.ListenerAddrs("SASL_PLAINTEXT"), nil, false},
+	} {
+		cl := newTestClient(t, c, append([]kgo.Opt{
+			kgo.SeedBrokers(test.addrs...),
+			kgo.RetryTimeout(500 * time.Millisecond),
+			kgo.RequestRetries(0),
+		}, test.opts...)...)
+		got, err := brokers(cl)
+		if !test.ok {
+			if err == nil {
+				t.Errorf("%s: metadata succeeded, expected failure", test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		// Brokers advertise the listener the request arrived on.
+		if exp := sorted(test.addrs); !reflect.DeepEqual(got, exp) {
+			t.Errorf("%s: got advertised brokers %v, expected %v", test.name, got, exp)
+		}
+	}
+}
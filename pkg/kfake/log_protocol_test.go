@@ -0,0 +1,72 @@
+package kfake
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+type captureLogger struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (l *captureLogger) Logf(level LogLevel, msg string, args ...any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lines = append(l.lines, level.String()+" "+fmt.Sprintf(msg, args...))
+}
+
+// matching returns the captured lines containing every substring.
+func (l *captureLogger) matching(subs ...string) []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var matched []string
+outer:
+	for _, line := range l.lines {
+		for _, sub := range subs {
+			if !strings.Contains(line, sub) {
+				continue outer
+			}
+		}
+		matched = append(matched, line)
+	}
+	return matched
+}
+
+func TestLogProtocol(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	for _, enabled := range []bool{false, true} {
+		l := new(captureLogger)
+		opts := []Opt{NumBrokers(1), WithLogger(l)}
+		if enabled {
+			opts = append(opts, LogProtocol())
+		}
+		c := newTestCluster(t, opts...)
+		cl := newTestClient(t, c, kgo.ClientID("logged"))
+
+		req := kmsg.NewPtrMetadataRequest()
+		rt := kmsg.NewMetadataRequestTopic()
+		rt.Topic = kmsg.StringPtr("logged-topic")
+		req.Topics = append(req.Topics, rt)
+		if _, err := req.RequestWith(ctx, cl); err != nil {
+			t.Fatal(err)
+		}
+
+		requests := l.matching("DBG", "Metadata v", "request from", "(logged)", `"Topic":"logged-topic"`)
+		responses := l.matching("DBG", "Metadata v", "response to", `"ErrorCode":3`)
+		if enabled && (len(requests) != 1 || len(responses) != 1) {
+			t.Errorf("got %d request and %d response logs, expected 1 each", len(requests), len(responses))
+		} else if !enabled && len(requests)+len(responses) > 0 {
+			t.Errorf("protocol was logged without LogProtocol: %v %v", requests, responses)
+		}
+	}
+}
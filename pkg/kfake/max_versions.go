@@ -0,0 +1,104 @@
+package kfake
+
+import (
+	"fmt"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+	"github.com/twmb/franz-go/pkg/kversion"
+)
+
+// Max versions emulate older Kafka releases. ApiVersions responses advertise
+// each key's versions capped to the configured max versions, and keys that
+// the configured versions do not contain are not advertised at all. Requests
+// above a key's max version (or for a key that is not advertised) cause the
+// connection to be closed, as Kafka does when it cannot parse a request.
+//
+// ApiVersions requests above the max ApiVersions version are answered as
+// Kafka answers them, with a v0 UNSUPPORTED_VERSION response, so that clients
+// retry with a lower version or use the versions in the response.
+
+// MaxVersions caps the request versions the cluster supports to those in
+// versions, such as kversion.V2_8_0(), to emulate an older Kafka release.
+// Keys that versions does not have are unsupported. MaxKeyVersion overrides
+// individual keys.
+func MaxVersions(versions *kversion.Versions) Opt {
+	return opt{func(cfg *cfg) { cfg.maxVersions = versions }}
+}
+
+// MaxKeyVersion caps the max version the cluster supports for a single
+// request key, overriding MaxVersions for the key. A negative max makes the
+// key unsupported.
+func MaxKeyVersion(key, max int16) Opt {
+	return opt{func(cfg *cfg) {
+		if cfg.maxKeyVersions == nil {
+			cfg.maxKeyVersions = make(map[int16]int16)
+		}
+		cfg.maxKeyVersions[key] = max
+	}}
+}
+
+// maxVersion returns the max version configured for key, or false if the key
+// is not capped. A negative max means the key is unsupported.
+func (c *Cluster) maxVersion(key int16) (int16, bool) {
+	if max, ok := c.cfg.maxKeyVersions[key]; ok {
+		return max, true
+	}
+	if vs := c.cfg.maxVersions; vs != nil {
+		if max, ok := vs.LookupMaxKeyVersion(key); ok {
+			return max, true
+		}
+		return -1, true
+	}
+	return 0, false
+}
+
+// rejectAboveMaxVersion returns an error if the request is above the key's
+// max version. ApiVersions is handled in handleApiVersions.
+func (c *Cluster) rejectAboveMaxVersion(kreq kmsg.Request) error {
+	if kreq.Key() == int16(kmsg.ApiVersions) {
+		return nil
+	}
+	if max, ok := c.maxVersion(kreq.Key()); ok && kreq.GetVersion() > max {
+		if max < 0 {
+			return fmt.Errorf("%s is not supported", kmsg.NameForKey(kreq.Key()))
+		}
+		return fmt.Errorf("%s version %d is above the max supported version %d", kmsg.NameForKey(kreq.Key()), kreq.GetVersion(), max)
+	}
+	return nil
+}
+
+// withMaxVersions returns keys with max versions lowered per MaxVersions and
+// MaxKeyVersion, dropping keys with no versions remaining.
+func (c *Cluster) withMaxVersions(keys []kmsg.ApiVersionsResponseApiKey) []kmsg.ApiVersionsResponseApiKey {
+	if c.cfg.maxVersions == nil && len(c.cfg.maxKeyVersions) == 0 {
+		return keys
+	}
+	capped := make([]kmsg.ApiVersionsResponseApiKey, 0, len(keys))
+	for _, k := range keys {
+		if max, ok := c.maxVersion(k.ApiKey); ok && k.MaxVersion > max {
+			if max < k.MinVersion {
+				continue
+			}
+			k.MaxVersion = max
+		}
+		capped = append(capped, k)
+	}
+	return capped
+}
+
+// downgradeApiVersions downgrades the response to an ApiVersions request
+// above the max ApiVersions version to a v0 UNSUPPORTED_VERSION response.
+// Kafka 2.4+ (ApiVersions v3+, KIP-511) replies with every key it supports,
+// and older Kafka replies with no keys.
+func (c *Cluster) downgradeApiVersions(resp *kmsg.ApiVersionsResponse) {
+	max, ok := c.maxVersion(resp.Key())
+	if !ok || resp.Version <= max {
+		return
+	}
+	resp.Version = 0
+	resp.ErrorCode = kerr.UnsupportedVersion.Code
+	if max < 3 {
+		resp.ApiKeys = nil
+	}
+}
@@ -0,0 +1,104 @@
+package kfake
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+	"github.com/twmb/franz-go/pkg/kversion"
+)
+
+func TestMaxVersions(t *testing.T) {
+	v28 := kversion.V2_8_0()
+	c := newTestCluster(t, NumBrokers(1), SeedTopics(1, "t"),
+		MaxVersions(v28),
+		MaxKeyVersion(int16(kmsg.Metadata), 9),
+		MaxKeyVersion(int16(kmsg.DeleteRecords), -1),
+	)
+	cl := newTestClient(t, c, kgo.ConsumeTopics("t"), kgo.ConsumeResetOffset(kgo.NewOffset().AtStart()))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	resp, err := kmsg.NewPtrApiVersionsRequest().RequestWith(ctx, cl.Broker(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	advertised := make(map[int16]int16)
+	for _, k := range resp.ApiKeys {
+		advertised[k.ApiKey] = k.MaxVersion
+	}
+	produceMax, _ := v28.LookupMaxKeyVersion(int16(kmsg.Produce))
+	for _, test := range []struct {
+		name string
+		key  kmsg.Key
+		max  int16 // -1 if not advertised
+	}{
+		{"capped by MaxVersions", kmsg.Produce, produceMax},
+		{"capped by MaxKeyVersion", kmsg.Metadata, 9},
+		{"removed by MaxKeyVersion", kmsg.DeleteRecords, -1},
+		{"newer than MaxVersions", kmsg.DescribeProducers, -1},
+	} {
+		max, ok := advertised[int16(test.key)]
+		if !ok {
+			max = -1
+		}
+		if max != test.max {
+			t.Errorf("%s: got max version %d, expected %d", test.name, max, test.max)
+		}
+	}
+
+	for _, test := range []struct {
+		name   string
+		req    kmsg.Request
+		v      int16
+		reject bool
+	}{
+		{"at the max", kmsg.NewPtrMetadataRequest(), 9, false},
+		{"above the max", kmsg.NewPtrMetadataRequest(), 10, true},
+		{"unsupported key", kmsg.NewPtrDeleteRecordsRequest(), 0, true},
+		{"ApiVersions", kmsg.NewPtrApiVersionsRequest(), 4, false},
+	} {
+		test.req.SetVersion(test.v)
+		if err := c.rejectAboveMaxVersion(test.req); (err != nil) != test.reject {
+			t.Errorf("%s: got %v, expected rejected %v", test.name, err, test.reject)
+		}
+	}
+
+	// Clients negotiate down to the capped versions.
+	if _, err := c.ProduceTo("t", 0, kgo.StringRecord("v")); err != nil {
+		t.Fatal(err)
+	}
+	if err := cl.ProduceSync(ctx, &kgo.Record{Topic: "t", Value: []byte("w")}).FirstErr(); err != nil {
+		t.Fatal(err)
+	}
+	var n int
+	for n < 2 {
+		fs := cl.PollFetches(ctx)
+		if err := fs.Err0(); err != nil {
+			t.Fatal(err)
+		}
+		n += fs.NumRecords()
+	}
+
+	// Brokers before KIP-511 reply to a too new ApiVersions with no keys.
+	old := newTestCluster(t, NumBrokers(1), MaxKeyVersion(int16(kmsg.ApiVersions), 2))
+	req := kmsg.NewPtrApiVersionsRequest()
+	req.Version = 3
+	kresp, err := old.handleApiVersions(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp := kresp.(*kmsg.ApiVersionsResponse); resp.Version != 0 || resp.ErrorCode != kerr.UnsupportedVersion.Code || len(resp.ApiKeys) != 0 {
+		t.Errorf("got v%d %v with %d keys, expected v0 UNSUPPORTED_VERSION with no keys", resp.Version, kerr.ErrorForCode(resp.ErrorCode), len(resp.ApiKeys))
+	}
+	req.Version = 2
+	if kresp, err = old.handleApiVersions(req); err != nil {
+		t.Fatal(err)
+	}
+	if resp := kresp.(*kmsg.ApiVersionsResponse); resp.ErrorCode != 0 || len(resp.ApiKeys) == 0 {
+		t.Errorf("ApiVersions at the max: got %v with %d keys", kerr.ErrorForCode(resp.ErrorCode), len(resp.ApiKeys))
+	}
+}
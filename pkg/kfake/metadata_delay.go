@@ -0,0 +1,263 @@
+package kfake
+
+import (
+	"fmt"
+	"time"
+)
+
+// Brokers can be configured to serve stale topic metadata for a while after
+// topics or leadership change, as real brokers do while metadata propagates
+// from the controller. When any broker has a delay, the run loop snapshots
+// topic metadata after every change (anything that changes topic metadata
+// calls metadataChanged), and metadata requests to a delayed
+// broker are answered from the newest snapshot that is at least as old as the
+// broker's delay. A delayed broker also replies UNKNOWN_TOPIC_OR_PARTITION to
+// data requests for partitions that are not in its snapshot.
+//
+// Brokers delay by MetadataPropagationDelay unless SetBrokerMetadataDelay
+// was used for the broker; the controller is the source of metadata and is
+// never delayed by MetadataPropagationDelay.
+
+type (
+	mdSnapshot struct {
+		at  time.Time
+		gen uint64 // the metadata generation this is a snapshot of
+		ts  map[string]mdTopic
+	}
+
+	mdTopic struct {
+		id uuid
+		ps map[int32]mdPartition
+	}
+
+	mdPartition struct {
+		leader   int32
+		epoch    int32
+		replicas []int32
+		isr      []int32
+	}
+
+	// staleLeader is the metadata of a partition from before its leader
+	// moved, served until the LeaderMetadataDelay elapses.
+	staleLeader struct {
+		id    uuid
+		mp    mdPartition
+		until time.Time
+	}
+)
+
+// SetBrokerMetadataDelay sets how long a broker continues to serve topic
+// metadata from before a change, such as a topic being created or deleted or
+// a partition leader moving. This can be used to reproduce races where one
+// broker knows about a new topic and another does not yet. Until the broker
+// catches up, it also replies UNKNOWN_TOPIC_OR_PARTITION to produce, fetch,
+// and offset requests for partitions it does not know of. A delay of zero
+// serves current metadata. This overrides MetadataPropagationDelay for the
+// broker, and returns an error if the node does not exist.
+func (c *Cluster) SetBrokerMetadataDelay(nodeID int32, delay time.Duration) error {
+	var err error
+	c.admin(func() {
+		for _, b := range c.bs {
+			if b.node == nodeID {
+				b.mdDelay = delay
+				b.mdDelaySet = true
+				c.recordMetadata()
+				return
+			}
+		}
+		err = fmt.Errorf("node %d not found", nodeID)
+	})
+	return err
+}
+
+// metadataDelay returns how long the broker lags behind current metadata.
+func (c *Cluster) metadataDelay(b *broker) time.Duration {
+	switch {
+	case b.mdDelaySet:
+		return b.mdDelay
+	case b == c.controller:
+		return 0
+	default:
+		return c.cfg.mdPropagationDelay
+	}
+}
+
+func (c *Cluster) snapshotTopic(t string) mdTopic {
+	st := mdTopic{
+		id: c.data.t2id[t],
+		ps: make(map[int32]mdPartition),
+	}
+	for p, pd := range c.data.tps[t] {
+		st.ps[p] = mdPartition{
+			leader:   pd.leader.node,
+			epoch:    pd.epoch,
+			replicas: c.replicas(t, pd),
+			isr:      c.isr(t, pd),
+		}
+	}
+	return st
+}
+
+func (c *Cluster) snapshotMetadata() mdSnapshot {
+	s := mdSnapshot{
+		at:  time.Now(),
+		gen: c.mdGen,
+		ts:  make(map[string]mdTopic, len(c.data.tps)),
+	}
+	for t := range c.data.tps {
+		s.ts[t] = c.snapshotTopic(t)
+	}
+	return s
+}
+
+// metadataChanged must be called whenever topic metadata changes: topics or
+// partitions are created or deleted, a leader or leader epoch changes, or
+// replicas or the ISR change. This bumps the metadata generation so that the
+// next recordMetadata saves a new snapshot.
+func (c *Cluster) metadataChanged() { c.mdGen++ }
+
+// recordMetadata is called at the top of every run loop iteration, right after
+// anything could have changed. If any broker delays metadata, we save a new
+// snapshot if the metadata generation changed and prune snapshots that no
+// broker can serve anymore.
+func (c *Cluster) recordMetadata() {
+	var maxDelay time.Duration
+	for _, b := range c.bs {
+		if d := c.metadataDelay(b); d > maxDelay {
+			maxDelay = d
+		}
+	}
+	if maxDelay == 0 {
+		c.mdHistory = nil
+		return
+	}
+
+	if n := len(c.mdHistory); n == 0 || c.mdHistory[n-1].gen != c.mdGen {
+		c.mdHistory = append(c.mdHistory, c.snapshotMetadata())
+	}
+
+	cutoff := time.Now().Add(-maxDelay)
+	var drop int
+	for drop < len(c.mdHistory)-1 && !c.mdHistory[drop+1].at.After(cutoff) {
+		drop++
+	}
+	c.mdHistory = c.mdHistory[drop:]
+}
+
+// metadataFor returns the stale topic metadata the broker should currently
+// serve, or nil if the broker should serve current metadata.
+func (c *Cluster) metadataFor(b *broker) *mdSnapshot {
+	d := c.metadataDelay(b)
+	if d == 0 || len(c.mdHistory) == 0 {
+		return nil
+	}
+	cutoff := time.Now().Add(-d)
+	s := &c.mdHistory[0]
+	for i := 1; i < len(c.mdHistory); i++ {
+		if c.mdHistory[i].at.After(cutoff) {
+			break
+		}
+		s = &c.mdHistory[i]
+	}
+	return s
+}
+
+// topic returns the metadata for a topic from the snapshot, or current
+// metadata if the snapshot is nil.
+func (s *mdSnapshot) topic(c *Cluster, t string) (mdTopic, bool) {
+	if s != nil {
+		mt, ok := s.ts[t]
+		return mt, ok
+	}
+	if _, ok := c.data.tps.gett(t); !ok {
+		return mdTopic{}, false
+	}
+	mt := c.snapshotTopic(t)
+	c.applyStaleLeaders(t, mt)
+	return mt, true
+}
+
+// hasPartition returns whether the snapshot has the current incarnation of a
+// partition, which is always true for current metadata (a nil snapshot).
+func (s *mdSnapshot) hasPartition(c *Cluster, t string, p int32) bool {
+	if s == nil {
+		return true
+	}
+	mt, ok := s.ts[t]
+	if !ok || mt.id != c.data.t2id[t] {
+		return false
+	}
+	_, ok = mt.ps[p]
+	return ok
+}
+
+// topicName returns the name of a topic ID from the snapshot, or from current
+// metadata if the snapshot is nil.
+func (s *mdSnapshot) topicName(c *Cluster, id uuid) (string, bool) {
+	if s == nil {
+		t, ok := c.data.id2t[id]
+		return t, ok
+	}
+	for t, mt := range s.ts {
+		if mt.id == id {
+			return t, true
+		}
+	}
+	return "", false
+}
+
+// topics returns all topic names in the snapshot, or in current metadata if
+// the snapshot is nil.
+func (s *mdSnapshot) topics(c *Cluster) []string {
+	var ts []string
+	if s == nil {
+		for t := range c.data.tps {
+			ts = append(ts, t)
+		}
+		return ts
+	}
+	for t := range s.ts {
+		ts = append(ts, t)
+	}
+	return ts
+}
+
+// leaderMoving is called before a partition's leader changes. If the cluster
+// uses LeaderMetadataDelay, this saves the partition's current metadata to be
+// served until the delay elapses. If the leader moves again within the
+// window, the original metadata continues to be served for a full delay.
+func (c *Cluster) leaderMoving(t string, p int32) {
+	d := c.cfg.leaderMetadataDelay
+	if d <= 0 {
+		return
+	}
+	now := time.Now()
+	if sl, ok := c.staleLeaders.getp(t, p); ok && now.Before(sl.until) {
+		sl.until = now.Add(d)
+		return
+	}
+	mp, ok := c.snapshotTopic(t).ps[p]
+	if !ok {
+		return
+	}
+	c.staleLeaders.set(t, p, staleLeader{c.data.t2id[t], mp, now.Add(d)})
+}
+
+// applyStaleLeaders replaces the current metadata of any partition in mt
+// whose leader recently moved with its metadata from before the move.
+func (c *Cluster) applyStaleLeaders(t string, mt mdTopic) {
+	sls, ok := c.staleLeaders.gett(t)
+	if !ok {
+		return
+	}
+	now := time.Now()
+	for p, sl := range sls {
+		if sl.id != mt.id || !now.Before(sl.until) {
+			c.staleLeaders.delp(t, p)
+			continue
+		}
+		if _, ok := mt.ps[p]; ok {
+			mt.ps[p] = sl.mp
+		}
+	}
+}
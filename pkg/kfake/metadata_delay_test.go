@@ -0,0 +1,221 @@
+package kfake
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestBrokerMetadataDelay(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(2))
+	cl := newTestClient(t, c)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	const delay = 500 * time.Millisecond
+	if err := c.SetBrokerMetadataDelay(1, delay); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.SetBrokerMetadataDelay(2, delay); err == nil {
+		t.Error("SetBrokerMetadataDelay on a missing node did not fail")
+	}
+
+	metadata := func(node int32) *kmsg.MetadataResponse {
+		t.Helper()
+		req := kmsg.NewPtrMetadataRequest()
+		req.AllowAutoTopicCreation = false
+		rt := kmsg.NewMetadataRequestTopic()
+		rt.Topic = kmsg.StringPtr("t")
+		req.Topics = append(req.Topics, rt)
+		resp, err := req.RequestWith(ctx, cl.Broker(int(node)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+	knows := func(node int32) bool {
+		t.Helper()
+		resp := metadata(node)
+		return len(resp.Topics) == 1 && resp.Topics[0].ErrorCode == 0
+	}
+
+	// Nothing changes while we send requests, so we keep one snapshot.
+	for i := 0; i < 5; i++ {
+		metadata(1)
+	}
+	var snapshots int
+	c.admin(func() { snapshots = len(c.mdHistory) })
+	if snapshots != 1 {
+		t.Fatalf("got %d metadata snapshots with no changes, expected 1", snapshots)
+	}
+
+	created := time.Now()
+	if _, err := newTestAdmin(t, c).CreateTopic(ctx, 1, 1, nil, "t"); err != nil {
+		t.Fatal(err)
+	}
+	if !knows(0) {
+		t.Fatal("undelayed broker does not know the new topic")
+	}
+	if knows(1) {
+		t.Fatal("delayed broker knows the new topic immediately")
+	}
+	if code := metadata(1).Topics[0].ErrorCode; code != kerr.UnknownTopicOrPartition.Code {
+		t.Errorf("delayed broker replied %v, expected UNKNOWN_TOPIC_OR_PARTITION", kerr.ErrorForCode(code))
+	}
+
+	for !knows(1) {
+		if time.Since(created) > 5*delay {
+			t.Fatal("delayed broker never learned of the new topic")
+		}
+		time.Sleep(delay / 10)
+	}
+	if elapsed := time.Since(created); elapsed < delay {
+		t.Errorf("delayed broker learned of the new topic after %v, expected at least %v", elapsed, delay)
+	}
+
+	// Clearing the delay serves current metadata and drops our history.
+	if err := c.SetBrokerMetadataDelay(1, 0); err != nil {
+		t.Fatal(err)
+	}
+	metadata(1)
+	c.admin(func() { snapshots = len(c.mdHistory) })
+	if snapshots != 0 {
+		t.Errorf("got %d metadata snapshots with no delays, expected 0", snapshots)
+	}
+}
+
+func TestLeaderMetadataDelay(t *testing.T) {
+	const delay = 300 * time.Millisecond
+	c := newTestCluster(t, NumBrokers(2), SeedTopics(1, "t"), LeaderMetadataDelay(delay))
+	cl := newTestClient(t, c)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	leader := func() (int32, int32) {
+		t.Helper()
+		req := kmsg.NewPtrMetadataRequest()
+		rt := kmsg.NewMetadataRequestTopic()
+		rt.Topic = kmsg.StringPtr("t")
+		req.Topics = append(req.Topics, rt)
+		resp, err := req.RequestWith(ctx, cl.Broker(0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		sp := resp.Topics[0].Partitions[0]
+		return sp.Leader, sp.LeaderEpoch
+	}
+	old, epoch := leader()
+	moved := 1 - old
+	start := time.Now()
+	if err := c.MoveTopicPartition("t", 0, moved); err != nil {
+		t.Fatal(err)
+	}
+
+	// Metadata serves the old leader while only the new leader accepts
+	// produces.
+	if l, e := leader(); l != old || e != epoch {
+		t.Errorf("metadata after the move: got leader %d epoch %d, expected the old leader %d epoch %d", l, e, old, epoch)
+	}
+	if sp := produceBatch(ctx, t, cl.Broker(int(old)), "t", 0, kgo.StringRecord("v")); sp.ErrorCode != kerr.NotLeaderForPartition.Code {
+		t.Errorf("produce to the old leader: got %v, expected NOT_LEADER_OR_FOLLOWER", kerr.ErrorForCode(sp.ErrorCode))
+	}
+	if sp := produceBatch(ctx, t, cl.Broker(int(moved)), "t", 0, kgo.StringRecord("v")); sp.ErrorCode != 0 {
+		t.Errorf("produce to the new leader: %v", kerr.ErrorForCode(sp.ErrorCode))
+	}
+
+	time.Sleep(delay - time.Since(start) + 50*time.Millisecond)
+	if l, e := leader(); l != moved || e != epoch+1 {
+		t.Errorf("metadata after the delay: got leader %d epoch %d, expected %d epoch %d", l, e, moved, epoch+1)
+	}
+}
+
+func TestMetadataPropagationDelay(t *testing.T) {
+	const delay = 300 * time.Millisecond
+	c := newTestCluster(t, NumBrokers(3), MetadataPropagationDelay(delay))
+	cl := newTestClient(t, c)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	controller := c.Info().Controller
+	follower := (controller + 1) % 3
+	created := time.Now()
+	if _, err := newTestAdmin(t, c).CreateTopic(ctx, 1, 1, nil, "t"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.MoveTopicPartition("t", 0, follower); err != nil {
+		t.Fatal(err)
+	}
+
+	knows := func(node int32) bool {
+		t.Helper()
+		req := kmsg.NewPtrMetadataRequest()
+		rt := kmsg.NewMetadataRequestTopic()
+		rt.Topic = kmsg.StringPtr("t")
+		req.Topics = append(req.Topics, rt)
+		resp, err := req.RequestWith(ctx, cl.Broker(int(node)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.Topics[0].ErrorCode == 0
+	}
+	if !knows(controller) {
+		t.Fatal("controller does not know the new topic")
+	}
+	if knows(follower) {
+		t.Fatal("broker knows the new topic before the propagation delay")
+	}
+
+	// A broker leading a partition it does not know of yet rejects data
+	// requests for it.
+	if p := produceBatch(ctx, t, cl.Broker(int(follower)), "t", 0, kgo.StringRecord("v")); p.ErrorCode != kerr.UnknownTopicOrPartition.Code {
+		t.Errorf("produce to a lagging broker: got %v, expected UNKNOWN_TOPIC_OR_PARTITION", kerr.ErrorForCode(p.ErrorCode))
+	}
+	fetch := kmsg.NewPtrFetchRequest()
+	fetch.MaxBytes = 1 << 20
+	ft := kmsg.NewFetchRequestTopic()
+	c.admin(func() { ft.TopicID = c.data.t2id["t"] })
+	fp := kmsg.NewFetchRequestTopicPartition()
+	fp.PartitionMaxBytes = 1 << 20
+	ft.Partitions = append(ft.Partitions, fp)
+	fetch.Topics = append(fetch.Topics, ft)
+	fresp, err := fetch.RequestWith(ctx, cl.Broker(int(follower)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code := fresp.Topics[0].Partitions[0].ErrorCode; code != kerr.UnknownTopicID.Code {
+		t.Errorf("fetch from a lagging broker: got %v, expected UNKNOWN_TOPIC_ID", kerr.ErrorForCode(code))
+	}
+
+	for !knows(follower) {
+		if time.Since(created) > 10*delay {
+			t.Fatal("broker never learned of the new topic")
+		}
+		time.Sleep(delay / 10)
+	}
+	if p := produceBatch(ctx, t, cl.Broker(int(follower)), "t", 0, kgo.StringRecord("v")); p.ErrorCode != 0 {
+		t.Errorf("produce after the propagation delay: %v", kerr.ErrorForCode(p.ErrorCode))
+	}
+
+	// Removing the controller fails over to another broker, which is
+	// never delayed.
+	if err := c.RemoveNode(controller); err != nil {
+		t.Fatal(err)
+	}
+	info := c.Info()
+	var live bool
+	for _, b := range info.Brokers {
+		live = live || b.Node == info.Controller
+	}
+	if info.Controller == controller || !live {
+		t.Errorf("got controller %d after removing controller %d, expected a live broker", info.Controller, controller)
+	}
+	var delayed time.Duration
+	c.admin(func() { delayed = c.metadataDelay(c.controller) })
+	if delayed != 0 {
+		t.Errorf("new controller is delayed by %v", delayed)
+	}
+}
@@ -0,0 +1,216 @@
+package kfake
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Mirror is a topic being mirrored from one cluster into another, created with
+// MirrorTopic.
+type Mirror struct {
+	src, dst *Cluster
+	topic    string
+	lag      time.Duration
+
+	quit chan struct{}
+	done chan struct{}
+	once sync.Once
+
+	// Only used in the mirror goroutine.
+	next    map[int32]int64 // next source offset to copy, per partition
+	pending []mirrorBatch   // source batches seen but not yet copied
+
+	mu           sync.Mutex
+	translations map[int32][]OffsetTranslation
+}
+
+type mirrorBatch struct {
+	partition int32
+	seen      time.Time
+	b         partBatch
+}
+
+// OffsetTranslation records where a mirrored batch of source offsets was
+// appended in the target cluster: source offsets SourceOffset through
+// SourceOffset+Span-1 correspond to target offsets TargetOffset through
+// TargetOffset+Span-1.
+type OffsetTranslation struct {
+	SourceOffset int64
+	TargetOffset int64
+	Span         int64
+}
+
+// MirrorTopic continuously copies topic from src into this cluster, as
+// MirrorMaker would, until the returned mirror is stopped or either cluster is
+// closed. This can be used to test migrating consumers between clusters and
+// offset translation tooling in process.
+//
+// Batches are copied as is, including their timestamps, producer IDs, and
+// control batches, once they have been in the source cluster for lag; the
+// target cluster assigns its own offsets. If the topic does not exist in this
+// cluster, it is created with the source's partition count the first time
+// data is copied; source partitions that do not exist in this cluster are
+// not copied. Where each copied batch landed is recorded and can be queried
+// with TranslateOffset and Translations.
+//
+// This returns an error if the topic does not exist in src.
+func (c *Cluster) MirrorTopic(src *Cluster, topic string, lag time.Duration) (*Mirror, error) {
+	if src == c {
+		return nil, errors.New("cannot mirror a cluster into itself")
+	}
+	var err error
+	src.admin(func() {
+		if _, ok := src.data.tps.gett(topic); !ok {
+			err = fmt.Errorf("topic %q does not exist in the source cluster", topic)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Mirror{
+		src:          src,
+		dst:          c,
+		topic:        topic,
+		lag:          lag,
+		quit:         make(chan struct{}),
+		done:         make(chan struct{}),
+		next:         make(map[int32]int64),
+		translations: make(map[int32][]OffsetTranslation),
+	}
+	go m.run()
+	return m, nil
+}
+
+// Stop stops mirroring. Once Stop returns, no more batches are copied.
+func (m *Mirror) Stop() {
+	m.once.Do(func() { close(m.quit) })
+	<-m.done
+}
+
+// TranslateOffset returns the target cluster offset corresponding to the
+// source offset for a partition, such as a committed offset of a consumer
+// group being migrated. The source offset can be one past the last mirrored
+// offset, which translates to the end of the mirrored data. This returns
+// false if the offset has not been mirrored.
+func (m *Mirror) TranslateOffset(partition int32, sourceOffset int64) (int64, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ts := m.translations[partition]
+	idx := sort.Search(len(ts), func(i int) bool {
+		return sourceOffset < ts[i].SourceOffset+ts[i].Span
+	})
+	if idx == len(ts) {
+		if len(ts) > 0 {
+			last := ts[len(ts)-1]
+			if sourceOffset == last.SourceOffset+last.Span {
+				return last.TargetOffset + last.Span, true
+			}
+		}
+		return 0, false
+	}
+	t := ts[idx]
+	if sourceOffset < t.SourceOffset { // before the first mirrored batch, or in a gap
+		return t.TargetOffset, true
+	}
+	return t.TargetOffset + sourceOffset - t.SourceOffset, true
+}
+
+// Translations returns the offset translations for every batch mirrored for
+// the partition so far, in order.
+func (m *Mirror) Translations(partition int32) []OffsetTranslation {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]OffsetTranslation(nil), m.translations[partition]...)
+}
+
+func (m *Mirror) run() {
+	defer close(m.done)
+
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.quit:
+			return
+		case <-m.src.die:
+			return
+		case <-m.dst.die:
+			return
+		case <-ticker.C:
+		}
+
+		var nparts int
+		if !m.src.tryAdmin(func() { nparts = m.read() }) {
+			return
+		}
+
+		now := time.Now()
+		due := 0
+		for due < len(m.pending) && !now.Before(m.pending[due].seen.Add(m.lag)) {
+			due++
+		}
+		if due == 0 {
+			continue
+		}
+		copying := m.pending[:due]
+		if !m.dst.tryAdmin(func() { m.write(copying, nparts) }) {
+			return
+		}
+		m.pending = m.pending[due:]
+	}
+}
+
+// read queues all new source batches, returning the source partition count.
+// This runs in the source cluster's run loop.
+func (m *Mirror) read() int {
+	ps, ok := m.src.data.tps.gett(m.topic)
+	if !ok {
+		return 0
+	}
+	now := time.Now()
+	for p, pd := range ps {
+		next := m.next[p]
+		if next < pd.logStartOffset {
+			next = pd.logStartOffset
+		}
+		idx, found, _ := pd.searchOffset(next)
+		if !found {
+			m.next[p] = next
+			continue
+		}
+		for _, b := range pd.batches[idx:] {
+			m.pending = append(m.pending, mirrorBatch{p, now, b})
+			next = b.FirstOffset + int64(b.LastOffsetDelta) + 1
+		}
+		m.next[p] = next
+	}
+	return len(ps)
+}
+
+// write appends batches to the target cluster. This runs in the target
+// cluster's run loop.
+func (m *Mirror) write(batches []mirrorBatch, nparts int) {
+	if _, ok := m.dst.data.tps.gett(m.topic); !ok && nparts > 0 {
+		m.dst.data.mkt(m.topic, nparts, -1, nil)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, mb := range batches {
+		pd, ok := m.dst.data.tps.getp(m.topic, mb.partition)
+		if !ok {
+			continue
+		}
+		offset := pd.highWatermark
+		pd.pushBatch(mb.b.nbytes, mb.b.RecordBatch)
+		m.translations[mb.partition] = append(m.translations[mb.partition], OffsetTranslation{
+			SourceOffset: mb.b.FirstOffset,
+			TargetOffset: offset,
+			Span:         int64(mb.b.LastOffsetDelta) + 1,
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package kfake
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func TestMirrorTopic(t *testing.T) {
+	src := newTestCluster(t, NumBrokers(1), SeedTopics(1, "t"), SeedTopics(2, "u"))
+	dst := newTestCluster(t, NumBrokers(1), SeedTopics(1, "t"))
+
+	if _, err := dst.MirrorTopic(dst, "t", 0); err == nil {
+		t.Error("mirroring a cluster into itself: got no error")
+	}
+	if _, err := dst.MirrorTopic(src, "missing", 0); err == nil {
+		t.Error("mirroring a missing topic: got no error")
+	}
+
+	hwm := func(c *Cluster, topic string) (int64, bool) {
+		var (
+			at     int64
+			exists bool
+		)
+		c.admin(func() {
+			if pd, ok := c.data.tps.getp(topic, 0); ok {
+				at, exists = pd.highWatermark, true
+			}
+		})
+		return at, exists
+	}
+	waitHWM := func(c *Cluster, topic string, exp int64) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			if at, _ := hwm(c, topic); at == exp {
+				return
+			}
+			if time.Now().After(deadline) {
+				at, _ := hwm(c, topic)
+				t.Fatalf("%s: got high watermark %d, expected %d", topic, at, exp)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	// The target already has data, so mirrored offsets are shifted.
+	if _, err := dst.ProduceTo("t", 0, kgo.StringRecord("x"), kgo.StringRecord("y")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := src.ProduceTo("t", 0, kgo.StringRecord("a"), kgo.StringRecord("b"), kgo.StringRecord("c")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := src.ProduceTo("t", 0, kgo.StringRecord("d")); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := dst.MirrorTopic(src, "t", 200*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Stop()
+
+	time.Sleep(50 * time.Millisecond)
+	if at, _ := hwm(dst, "t"); at != 2 {
+		t.Errorf("batches were copied before the lag: got high watermark %d, expected 2", at)
+	}
+	waitHWM(dst, "t", 6)
+
+	rs, err := dst.ReadRecords("t", 0, 2, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, r := range rs {
+		got = append(got, string(r.Value))
+	}
+	if exp := []string{"a", "b", "c", "d"}; !reflect.DeepEqual(got, exp) {
+		t.Errorf("got mirrored values %v, expected %v", got, exp)
+	}
+
+	if ts, exp := m.Translations(0), []OffsetTranslation{{0, 2, 3}, {3, 5, 1}}; !reflect.DeepEqual(ts, exp) {
+		t.Errorf("got translations %+v, expected %+v", ts, exp)
+	}
+	for _, test := range []struct {
+		source int64
+		exp    int64
+		ok     bool
+	}{
+		{0, 2, true},
+		{2, 4, true},
+		{3, 5, true},
+		{4, 6, true}, // one past the end
+		{5, 0, false},
+	} {
+		if at, ok := m.TranslateOffset(0, test.source); at != test.exp || ok != test.ok {
+			t.Errorf("translating %d: got %d %v, expected %d %v", test.source, at, ok, test.exp, test.ok)
+		}
+	}
+
+	// Nothing is copied once the mirror is stopped.
+	m.Stop()
+	if _, err := src.ProduceTo("t", 0, kgo.StringRecord("e")); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if at, _ := hwm(dst, "t"); at != 6 {
+		t.Errorf("batch copied after stop: got high watermark %d, expected 6", at)
+	}
+
+	// A topic missing in the target is created with the source's
+	// partitions.
+	if _, err := src.ProduceTo("u", 1, kgo.StringRecord("a")); err != nil {
+		t.Fatal(err)
+	}
+	mu, err := dst.MirrorTopic(src, "u", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mu.Stop()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if rs, err := dst.ReadRecords("u", 1, 0, 0); err == nil && len(rs) == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("partition 1 of u was not mirrored")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	var nparts int
+	dst.admin(func() {
+		if ps, ok := dst.data.tps.gett("u"); ok {
+			nparts = len(ps)
+		}
+	})
+	if nparts != 2 {
+		t.Errorf("got %d partitions in the created topic, expected 2", nparts)
+	}
+}
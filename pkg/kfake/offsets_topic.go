@@ -0,0 +1,108 @@
+package kfake
+
+import (
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// With MaterializeOffsets, every offset commit and deletion is written to the
+// compacted __consumer_offsets topic. Groups commit from their own
+// goroutines, which do not own partition data, so records are queued in
+// commit order and appended by the run loop. Each flush appends one batch per
+// partition that has queued records.
+//
+// Like Kafka, a group's records all go to the same partition, chosen by the
+// Java hash code of the group name. Only offset commit records are written;
+// group metadata records are not.
+
+const offsetsTopic = "__consumer_offsets"
+
+type offsetsRecord struct {
+	group string
+	t     string
+	p     int32
+	oc    *offsetCommit // nil for a tombstone
+}
+
+// logOffset queues an offset commit, or a tombstone if oc is nil, to be
+// appended to __consumer_offsets. This does nothing if offsets are not
+// materialized.
+func (c *Cluster) logOffset(group, t string, p int32, oc *offsetCommit) {
+	if c.cfg.offsetsPartitions <= 0 {
+		return
+	}
+	c.offsetsMu.Lock()
+	c.offsetsLog = append(c.offsetsLog, offsetsRecord{group, t, p, oc})
+	c.offsetsMu.Unlock()
+	select {
+	case c.offsetsWake <- struct{}{}:
+	default:
+	}
+}
+
+// flushOffsets appends all queued offset records; called in the run loop.
+func (c *Cluster) flushOffsets() {
+	c.offsetsMu.Lock()
+	queued := c.offsetsLog
+	c.offsetsLog = nil
+	c.offsetsMu.Unlock()
+
+	var (
+		order []int32
+		byp   = make(map[int32][]offsetsRecord)
+	)
+	for _, r := range queued {
+		p := offsetsPartition(r.group, c.cfg.offsetsPartitions)
+		if _, ok := byp[p]; !ok {
+			order = append(order, p)
+		}
+		byp[p] = append(byp[p], r)
+	}
+
+	for _, p := range order {
+		pd, ok := c.data.tps.getp(offsetsTopic, p)
+		if !ok {
+			continue // the topic was deleted
+		}
+		now := pd.leader.now()
+		var recs []*kgo.Record
+		for _, r := range byp[p] {
+			k := kmsg.NewOffsetCommitKey()
+			k.Version = 1
+			k.Group = r.group
+			k.Topic = r.t
+			k.Partition = r.p
+			rec := &kgo.Record{Key: k.AppendTo(nil), Timestamp: now}
+			if r.oc != nil {
+				v := kmsg.NewOffsetCommitValue()
+				v.Version = 3
+				v.Offset = r.oc.offset
+				v.LeaderEpoch = r.oc.leaderEpoch
+				if r.oc.metadata != nil {
+					v.Metadata = *r.oc.metadata
+				}
+				v.CommitTimestamp = now.UnixMilli()
+				rec.Value = v.AppendTo(nil)
+			}
+			recs = append(recs, rec)
+		}
+		b := newRecordBatchFrom(recs)
+		pd.pushBatch(int(b.Length)+12, b)
+	}
+}
+
+// offsetsPartition returns the __consumer_offsets partition for a group, as
+// Kafka's abs(group.hashCode()) % partitions.
+func offsetsPartition(group string, partitions int) int32 {
+	var h int32
+	for _, r := range group {
+		if r >= 0x10000 { // Java strings are UTF-16
+			r -= 0x10000
+			h = 31*h + int32(0xd800+(r>>10))
+			h = 31*h + int32(0xdc00+(r&0x3ff))
+			continue
+		}
+		h = 31*h + int32(r)
+	}
+	return int32(int(h&0x7fffffff) % partitions)
+}
@@ -0,0 +1,117 @@
+package kfake
+
+import (
+	"context"
+	"testing"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestMaterializeOffsets(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(1), MaterializeOffsets(3), SeedTopics(2, "t"))
+	adm := newTestAdmin(t, c)
+	ctx := context.Background()
+
+	commit := func(p int32, at int64) {
+		t.Helper()
+		var os kadm.Offsets
+		os.Add(kadm.Offset{Topic: "t", Partition: p, At: at, LeaderEpoch: -1})
+		if _, err := adm.CommitOffsets(ctx, "g", os); err != nil {
+			t.Fatalf("unable to commit: %v", err)
+		}
+	}
+	commit(0, 1)
+	commit(0, 5)
+	commit(1, 3)
+	if _, err := adm.DeleteOffsets(ctx, "g", kadm.TopicsSet{"t": {1: {}}}); err != nil {
+		t.Fatalf("unable to delete offsets: %v", err)
+	}
+
+	p := offsetsPartition("g", 3)
+	read := func() map[int32][]*int64 {
+		t.Helper()
+		var rs []*kgo.Record
+		c.admin(func() {
+			pd, _ := c.data.tps.getp(offsetsTopic, p)
+			for _, b := range pd.batches {
+				brs, err := readBatch(offsetsTopic, p, &b.RecordBatch)
+				if err != nil {
+					t.Errorf("unable to read offsets: %v", err)
+				}
+				rs = append(rs, brs...)
+			}
+		})
+		got := make(map[int32][]*int64)
+		for _, r := range rs {
+			var k kmsg.OffsetCommitKey
+			if err := k.ReadFrom(r.Key); err != nil {
+				t.Fatalf("invalid key: %v", err)
+			}
+			if k.Group != "g" || k.Topic != "t" {
+				t.Fatalf("unexpected key %+v", k)
+			}
+			if r.Value == nil {
+				got[k.Partition] = append(got[k.Partition], nil)
+				continue
+			}
+			var v kmsg.OffsetCommitValue
+			if err := v.ReadFrom(r.Value); err != nil {
+				t.Fatalf("invalid value: %v", err)
+			}
+			got[k.Partition] = append(got[k.Partition], &v.Offset)
+		}
+		return got
+	}
+
+	// Commits are written asynchronously by the run loop; a metadata
+	// request is handled after the flush.
+	waitForRecords := func(n int) {
+		t.Helper()
+		for i := 0; i < 100; i++ {
+			if _, err := adm.ListTopics(ctx); err != nil {
+				t.Fatal(err)
+			}
+			total := 0
+			for _, os := range read() {
+				total += len(os)
+			}
+			if total == n {
+				return
+			}
+		}
+		t.Fatalf("offsets topic never had %d records: %v", n, read())
+	}
+	waitForRecords(4)
+
+	got := read()
+	if len(got[0]) != 2 || *got[0][0] != 1 || *got[0][1] != 5 {
+		t.Errorf("partition 0 commits: got %v, want [1 5]", got[0])
+	}
+	if len(got[1]) != 2 || *got[1][0] != 3 || got[1][1] != nil {
+		t.Errorf("partition 1 commits: got %v, want [3 tombstone]", got[1])
+	}
+
+	// Compacting keeps the latest commit per key; the tombstone survives
+	// its first compaction.
+	if err := c.Compact(offsetsTopic); err != nil {
+		t.Fatalf("unable to compact: %v", err)
+	}
+	got = read()
+	if len(got[0]) != 1 || *got[0][0] != 5 {
+		t.Errorf("compacted partition 0: got %v, want [5]", got[0])
+	}
+	if len(got[1]) != 1 || got[1][0] != nil {
+		t.Errorf("compacted partition 1: got %v, want [tombstone]", got[1])
+	}
+
+	// The topic is internal and fetchable like any other topic.
+	tds, err := adm.ListTopicsWithInternal(ctx, offsetsTopic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if td := tds[offsetsTopic]; !td.IsInternal || len(td.Partitions) != 3 {
+		t.Errorf("got topic %+v, want internal with 3 partitions", td)
+	}
+}
@@ -0,0 +1,113 @@
+package kfake
+
+import (
+	"fmt"
+)
+
+// Pausing a broker simulates a broker that is temporarily down or hung,
+// without changing the cluster's shape: unlike RemoveNode, a paused broker
+// keeps its node ID, its partition leadership and replicas, and is still
+// advertised in metadata, as the cluster has not yet noticed it is gone.
+//
+// A broker paused with PauseNode closes its client connections and
+// immediately closes new connections, as if its process was down. A broker
+// paused with PauseNodeHang keeps its connections open and keeps accepting
+// new ones, but does not read requests until it is resumed, as if it was
+// stuck; clients only notice through their request timeouts. Requests that
+// were read before the pause are still answered. When a hung broker is
+// resumed, it handles the requests that were waiting, in order.
+//
+// A paused broker is not live for leader elections: ElectLeaders and
+// ElectPreferredLeaders do not elect it, and an unclean election moves
+// leadership away from it.
+
+// PauseNode pauses a broker as if it was down: its client connections are
+// closed, and new connections are closed immediately until ResumeNode is
+// called. This returns an error if the node does not exist.
+func (c *Cluster) PauseNode(nodeID int32) error {
+	return c.pauseNode(nodeID, true, false)
+}
+
+// PauseNodeHang pauses a broker as if it was hung: connections stay open and
+// new connections are accepted, but the broker does not read or answer
+// requests until ResumeNode is called. This returns an error if the node does
+// not exist.
+func (c *Cluster) PauseNodeHang(nodeID int32) error {
+	return c.pauseNode(nodeID, true, true)
+}
+
+// ResumeNode resumes a broker paused with PauseNode or PauseNodeHang. This
+// returns an error if the node does not exist.
+func (c *Cluster) ResumeNode(nodeID int32) error {
+	return c.pauseNode(nodeID, false, false)
+}
+
+func (c *Cluster) pauseNode(nodeID int32, pause, hang bool) error {
+	var err error
+	c.admin(func() {
+		for _, b := range c.bs {
+			if b.node != nodeID {
+				continue
+			}
+			b.setPause(pause, hang)
+			if pause && !hang {
+				c.liveMu.Lock()
+				for cc := range c.live {
+					if cc.b == b {
+						cc.conn.Close()
+					}
+				}
+				c.liveMu.Unlock()
+			}
+			return
+		}
+		err = fmt.Errorf("node %d not found", nodeID)
+	})
+	return err
+}
+
+// setPause pauses or resumes the broker, waking anything waiting on the prior
+// pause so that it can recheck.
+func (b *broker) setPause(pause, hang bool) {
+	b.pauseMu.Lock()
+	defer b.pauseMu.Unlock()
+	if b.paused != nil {
+		close(b.paused)
+		b.paused = nil
+	}
+	if pause {
+		b.paused = make(chan struct{})
+	}
+	b.pauseHang = hang
+}
+
+// pauseState returns a channel that is closed when the broker's pause
+// changes, or nil if the broker is not paused, and whether the pause hangs.
+func (b *broker) pauseState() (chan struct{}, bool) {
+	b.pauseMu.Lock()
+	defer b.pauseMu.Unlock()
+	return b.paused, b.pauseHang
+}
+
+// waitPaused waits while the conn's broker is hung, returning false if the
+// conn should stop reading because the broker is down or the cluster is
+// closing.
+func (cc *clientConn) waitPaused() bool {
+	for {
+		paused, hang := cc.b.pauseState()
+		if paused == nil {
+			return true
+		}
+		if !hang {
+			cc.closeRead()
+			return false
+		}
+		select {
+		case <-paused:
+		case <-cc.c.die:
+			return false
+		case <-cc.c.draining:
+			return false
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package kfake
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestPauseNode(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(2), SeedTopics(2, "t"))
+	cl := newTestClient(t, c)
+	hungCl := newTestClient(t, c)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := c.PauseNode(9); err == nil {
+		t.Error("pausing a missing node succeeded")
+	}
+	if err := c.ResumeNode(9); err == nil {
+		t.Error("resuming a missing node succeeded")
+	}
+
+	describe := func(br *kgo.Broker, timeout time.Duration) error {
+		t.Helper()
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		_, err := kmsg.NewPtrDescribeClusterRequest().RequestWith(ctx, br)
+		return err
+	}
+	if err := describe(cl.Broker(0), 5*time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	// A paused broker closes connections, but is still in metadata and
+	// keeps its leadership.
+	var led []int32
+	c.admin(func() {
+		c.data.tps.each(func(_ string, p int32, pd *partData) {
+			if pd.leader.node == 0 {
+				led = append(led, p)
+			}
+		})
+	})
+	if err := c.PauseNode(0); err != nil {
+		t.Fatal(err)
+	}
+	if err := describe(cl.Broker(0), 500*time.Millisecond); err == nil {
+		t.Error("request to a paused broker succeeded")
+	}
+	req := kmsg.NewPtrMetadataRequest()
+	resp, err := req.RequestWith(ctx, cl.Broker(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var advertised bool
+	for _, b := range resp.Brokers {
+		advertised = advertised || b.NodeID == 0
+	}
+	if !advertised {
+		t.Error("paused broker is not in metadata")
+	}
+	var stillLed int
+	for _, rp := range resp.Topics[0].Partitions {
+		if rp.Leader == 0 {
+			stillLed++
+		}
+	}
+	if stillLed != len(led) {
+		t.Errorf("paused broker leads %d partitions, expected %d", stillLed, len(led))
+	}
+	if err := c.ResumeNode(0); err != nil {
+		t.Fatal(err)
+	}
+	if err := describe(cl.Broker(0), 5*time.Second); err != nil {
+		t.Errorf("request after resuming: %v", err)
+	}
+
+	// A hung broker does not answer until it is resumed, at which point
+	// waiting requests are answered.
+	if err := c.PauseNodeHang(0); err != nil {
+		t.Fatal(err)
+	}
+	if err := describe(cl.Broker(0), 200*time.Millisecond); err == nil {
+		t.Error("request to a hung broker succeeded")
+	}
+	done := make(chan error, 1)
+	go func() {
+		_, err := kmsg.NewPtrDescribeClusterRequest().RequestWith(ctx, hungCl.Broker(0))
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		t.Fatalf("request to a hung broker finished: %v", err)
+	case <-time.After(200 * time.Millisecond):
+	}
+	if err := c.ResumeNode(0); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("waiting request after resuming: %v", err)
+		}
+	case <-ctx.Done():
+		t.Fatal("waiting request was not answered after resuming")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"hash/fnv"
 	"math"
 	"math/rand"
+	"time"
 )
 
 // TODO
@@ -20,6 +21,9 @@ type (
 		id    int64
 		epoch int16
 		tps   tps[pidseqs]
+
+		lastUse time.Time // when the ID was last created or produced with
+		expired bool      // if true, produces fail with UNKNOWN_PRODUCER_ID
 	}
 
 	pid struct {
@@ -28,11 +32,28 @@ type (
 	}
 
 	pidseqs struct {
-		seqs [5]int32
-		at   uint8
+		seqs    [5]int32
+		offsets [5]int64 // offsets[i] is the offset of the batch ending before seqs[i]
+		at      uint8
 	}
 )
 
+// ExpireProducerID immediately expires a producer ID, as if it had been idle
+// for longer than the ProducerIDExpiration. Produce requests using the ID fail
+// with UNKNOWN_PRODUCER_ID until the producer restarts its sequence numbers,
+// either with a new epoch or a new producer ID. This returns false if the
+// producer ID is unknown.
+func (c *Cluster) ExpireProducerID(id int64) bool {
+	var ok bool
+	c.admin(func() {
+		if pm := c.pids[id]; pm != nil {
+			c.pids.expire(pm)
+			ok = true
+		}
+	})
+	return ok
+}
+
 func (pids *pids) get(id int64, epoch int16, t string, p int32) (*pidseqs, int16) {
 	if *pids == nil {
 		return nil, 0
@@ -44,7 +65,7 @@ func (pids *pids) get(id int64, epoch int16, t string, p int32) (*pidseqs, int16
 	return pm.tps.mkpDefault(t, p), pm.epoch
 }
 
-func (pids *pids) create(txnalID *string) pid {
+func (pids *pids) create(txnalID *string, now time.Time) pid {
 	if *pids == nil {
 		*pids = make(map[int64]*pidMap)
 	}
@@ -62,35 +83,92 @@ func (pids *pids) create(txnalID *string) pid {
 		}
 	}
 	pm, exists := (*pids)[id]
-	if exists {
+	if exists && !pm.expired {
 		pm.epoch++
+		pm.tps = nil // sequences restart at zero in the new epoch
+		pm.lastUse = now
 		return pid{id, pm.epoch}
 	}
-	pm = &pidMap{id: id}
+	pm = &pidMap{id: id, lastUse: now}
 	(*pids)[id] = pm
 	return pid{id, 0}
 }
 
-func (seqs *pidseqs) pushAndValidate(firstSeq, numRecs int32) (ok, dup bool) {
+// expired returns whether the producer ID has expired, expiring it first if
+// it has been idle for at least d. As in Kafka, the broker forgets everything
+// about an expired producer: only a batch starting a new sequence (at
+// sequence zero, with a non-decreasing epoch) revives the ID.
+func (pids *pids) expired(id int64, epoch int16, firstSeq int32, d time.Duration, now time.Time) bool {
+	pm := (*pids)[id]
+	if pm == nil {
+		return false
+	}
+	if !pm.expired && d > 0 && now.Sub(pm.lastUse) >= d {
+		pids.expire(pm)
+	}
+	if pm.expired && firstSeq == 0 && epoch >= pm.epoch {
+		pm.expired = false
+		pm.epoch = epoch
+	}
+	return pm.expired
+}
+
+// newEpoch moves a producer ID to a higher epoch that the producer bumped
+// itself to, restarting its sequence numbers.
+func (pids *pids) newEpoch(id int64, epoch int16) {
+	if pm := (*pids)[id]; pm != nil {
+		pm.epoch = epoch
+		pm.tps = nil
+	}
+}
+
+// bump increments the epoch of a producer ID, fencing the producer, and
+// returns the new epoch.
+func (pids *pids) bump(id int64) int16 {
+	pm := (*pids)[id]
+	if pm == nil {
+		return 0
+	}
+	pm.epoch++
+	pm.tps = nil
+	return pm.epoch
+}
+
+func (pids *pids) expire(pm *pidMap) {
+	pm.expired = true
+	pm.tps = nil
+}
+
+func (pids *pids) used(id int64, now time.Time) {
+	if pm := (*pids)[id]; pm != nil {
+		pm.lastUse = now
+	}
+}
+
+// pushAndValidate validates a batch's sequence numbers against the last five
+// batches, as Kafka does. A batch that matches one of the last five is a
+// duplicate, and dupOffset is the offset the original batch was appended at.
+// Otherwise, the batch must start at the next expected sequence, and is
+// recorded as appended at offset.
+func (seqs *pidseqs) pushAndValidate(firstSeq, numRecs int32, offset int64) (ok, dup bool, dupOffset int64) {
 	// If there is no pid, we do not do duplicate detection.
 	if seqs == nil {
-		return true, false
+		return true, false, 0
 	}
 	var (
-		seq    = firstSeq
-		seq64  = int64(seq)
-		next64 = (seq64 + int64(numRecs)) % math.MaxInt32
-		next   = int32(next64)
+		seq  = firstSeq
+		next = int32((int64(seq) + int64(numRecs)) % (math.MaxInt32 + 1)) // sequences wrap to 0 after MaxInt32
 	)
 	for i := 0; i < 5; i++ {
 		if seqs.seqs[i] == seq && seqs.seqs[(i+1)%5] == next {
-			return true, true
+			return true, true, seqs.offsets[(i+1)%5]
 		}
 	}
 	if seqs.seqs[seqs.at] != seq {
-		return false, false
+		return false, false, 0
 	}
 	seqs.at = (seqs.at + 1) % 5
 	seqs.seqs[seqs.at] = next
-	return true, false
+	seqs.offsets[seqs.at] = offset
+	return true, false, 0
 }
@@ -0,0 +1,151 @@
+package kfake
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestProducerIDExpiration(t *testing.T) {
+	const expiration = 500 * time.Millisecond
+	c := newTestCluster(t, NumBrokers(1), SeedTopics(1, "t"), ProducerIDExpiration(expiration))
+	br := newTestClient(t, c).Broker(0)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	initReq := kmsg.NewPtrInitProducerIDRequest()
+	initResp, err := initReq.RequestWith(ctx, br)
+	if err == nil {
+		err = kerr.ErrorForCode(initResp.ErrorCode)
+	}
+	if err != nil {
+		t.Fatalf("init producer ID: %v", err)
+	}
+	id, epoch := initResp.ProducerID, initResp.ProducerEpoch
+
+	produce := func(seq int32) int16 {
+		t.Helper()
+		b := newRecordBatchFrom([]*kgo.Record{{Value: []byte("v"), Timestamp: time.Now()}})
+		b.ProducerID, b.ProducerEpoch, b.FirstSequence = id, epoch, seq
+		if err := recompress(&b, 0); err != nil {
+			t.Fatal(err)
+		}
+		req := kmsg.NewPtrProduceRequest()
+		req.Acks = -1
+		req.TimeoutMillis = 5000
+		rt := kmsg.NewProduceRequestTopic()
+		rt.Topic = "t"
+		rp := kmsg.NewProduceRequestTopicPartition()
+		rp.Records = b.AppendTo(nil)
+		rt.Partitions = append(rt.Partitions, rp)
+		req.Topics = append(req.Topics, rt)
+		resp, err := req.RequestWith(ctx, br)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.Topics[0].Partitions[0].ErrorCode
+	}
+
+	for seq := int32(0); seq < 2; seq++ {
+		if code := produce(seq); code != 0 {
+			t.Fatalf("produce at sequence %d: %v", seq, kerr.ErrorForCode(code))
+		}
+	}
+
+	if c.ExpireProducerID(id + 1) {
+		t.Error("expiring an unknown producer ID succeeded")
+	}
+	if !c.ExpireProducerID(id) {
+		t.Fatal("expiring the producer ID failed")
+	}
+	if code := produce(2); code != kerr.UnknownProducerID.Code {
+		t.Errorf("continuing the sequence of an expired ID: got %v, expected UNKNOWN_PRODUCER_ID", kerr.ErrorForCode(code))
+	}
+	if code := produce(0); code != 0 {
+		t.Errorf("restarting the sequence of an expired ID: %v", kerr.ErrorForCode(code))
+	}
+
+	// An idle ID expires on its own.
+	time.Sleep(expiration + 100*time.Millisecond)
+	if code := produce(1); code != kerr.UnknownProducerID.Code {
+		t.Errorf("producing with an idle ID: got %v, expected UNKNOWN_PRODUCER_ID", kerr.ErrorForCode(code))
+	}
+}
+
+func TestProducerSequences(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(1), SeedTopics(1, "t"))
+	br := newTestClient(t, c).Broker(0)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	initResp, err := kmsg.NewPtrInitProducerIDRequest().RequestWith(ctx, br)
+	if err == nil {
+		err = kerr.ErrorForCode(initResp.ErrorCode)
+	}
+	if err != nil {
+		t.Fatalf("init producer ID: %v", err)
+	}
+	id, epoch := initResp.ProducerID, initResp.ProducerEpoch
+
+	// produce writes a batch of n records, returning the error code and
+	// base offset.
+	produce := func(id int64, epoch int16, seq int32, n int) (int16, int64) {
+		t.Helper()
+		var rs []*kgo.Record
+		for i := 0; i < n; i++ {
+			rs = append(rs, &kgo.Record{Value: []byte("v"), Timestamp: time.Now()})
+		}
+		b := newRecordBatchFrom(rs)
+		b.ProducerID, b.ProducerEpoch, b.FirstSequence = id, epoch, seq
+		if err := recompress(&b, 0); err != nil {
+			t.Fatal(err)
+		}
+		req := kmsg.NewPtrProduceRequest()
+		req.Acks = -1
+		req.TimeoutMillis = 5000
+		rt := kmsg.NewProduceRequestTopic()
+		rt.Topic = "t"
+		rp := kmsg.NewProduceRequestTopicPartition()
+		rp.Records = b.AppendTo(nil)
+		rt.Partitions = append(rt.Partitions, rp)
+		req.Topics = append(req.Topics, rt)
+		resp, err := req.RequestWith(ctx, br)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sp := resp.Topics[0].Partitions[0]
+		return sp.ErrorCode, sp.BaseOffset
+	}
+
+	for _, test := range []struct {
+		name   string
+		id     int64
+		epoch  int16
+		seq    int32
+		n      int
+		exp    error
+		offset int64
+	}{
+		{"first batch", id, epoch, 0, 2, nil, 0},
+		{"next batch", id, epoch, 2, 1, nil, 2},
+		{"duplicate batch", id, epoch, 0, 2, nil, 0},
+		{"sequence gap", id, epoch, 5, 1, kerr.OutOfOrderSequenceNumber, -1},
+		{"new epoch without restarting", id, epoch + 1, 3, 1, kerr.OutOfOrderSequenceNumber, -1},
+		{"new epoch", id, epoch + 1, 0, 1, nil, 3},
+		{"stale epoch", id, epoch, 3, 1, kerr.InvalidProducerEpoch, -1},
+		{"unknown producer ID", id + 100, 0, 7, 1, nil, 4},
+	} {
+		code, offset := produce(test.id, test.epoch, test.seq, test.n)
+		if err := kerr.ErrorForCode(code); err != test.exp {
+			t.Errorf("%s: got %v, expected %v", test.name, err, test.exp)
+			continue
+		}
+		if test.exp == nil && offset != test.offset {
+			t.Errorf("%s: got base offset %d, expected %d", test.name, offset, test.offset)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package kfake
+
+import (
+	"encoding/binary"
+	"errors"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// Produce faults reject produce requests that would otherwise be valid, so
+// that client handling of broker-side rejections can be tested without
+// control functions.
+
+type (
+	produceFaults struct {
+		reject func(*kmsg.Record) string
+		fail   *produceFail
+	}
+
+	produceFail struct {
+		code      int16
+		remaining int       // if positive, how many more requests fail
+		until     time.Time // if non-zero, when requests stop failing
+	}
+)
+
+// FailProduceRequests fails the next n produce requests to the topic
+// partition with the given error code, as returned by the partition's leader.
+// This is useful for testing partition scoped retries and failover, i.e. by
+// failing with NOT_LEADER_OR_FOLLOWER or a retriable or fatal error. If n is
+// zero or negative, requests fail until ClearProduceFaults.
+//
+// Calling this or FailProducesFor again for the same partition replaces the
+// prior failure. Use ClearProduceFaults to stop failing early.
+func (c *Cluster) FailProduceRequests(topic string, partition int32, code int16, n int) {
+	c.admin(func() {
+		c.produceFaults.mkpDefault(topic, partition).fail = &produceFail{code: code, remaining: n}
+	})
+}
+
+// FailProducesFor fails all produce requests to the topic partition with the
+// given error code until d elapses. See FailProduceRequests for more details.
+func (c *Cluster) FailProducesFor(topic string, partition int32, code int16, d time.Duration) {
+	c.admin(func() {
+		c.produceFaults.mkpDefault(topic, partition).fail = &produceFail{code: code, until: time.Now().Add(d)}
+	})
+}
+
+// RejectProducedRecords rejects batches produced to the topic partition that
+// contain any record for which reject returns a non-empty reason, as Kafka
+// does when records fail broker-side validation (KIP-467). The whole batch
+// fails with INVALID_RECORD and none of its records are written, and the
+// response includes the relative offset and reason of every rejected record.
+//
+// Calling this again for the same partition replaces the reject function.
+// The function is called from the cluster's run loop and must not call back
+// into the cluster. Use ClearProduceFaults to accept records normally again.
+func (c *Cluster) RejectProducedRecords(topic string, partition int32, reject func(*kmsg.Record) string) {
+	c.admin(func() {
+		c.produceFaults.mkpDefault(topic, partition).reject = reject
+	})
+}
+
+// ClearProduceFaults removes all faults added with RejectProducedRecords,
+// FailProduceRequests, or FailProducesFor.
+func (c *Cluster) ClearProduceFaults() {
+	c.admin(func() {
+		c.produceFaults = nil
+	})
+}
+
+// rejectRecords returns the error records for a batch produced to t p, if any
+// produce fault rejects records in it.
+func (c *Cluster) rejectRecords(t string, p int32, b *kmsg.RecordBatch) ([]kmsg.ProduceResponseTopicPartitionErrorRecord, error) {
+	f, ok := c.produceFaults.getp(t, p)
+	if !ok || f.reject == nil {
+		return nil, nil
+	}
+	raw, err := decompress(int8(b.Attributes&0x0007), b.Records)
+	if err != nil {
+		return nil, err
+	}
+	var errs []kmsg.ProduceResponseTopicPartitionErrorRecord
+	for i := int32(0); i < b.NumRecords; i++ {
+		length, n := binary.Varint(raw)
+		if n <= 0 || length < 0 || int64(len(raw)-n) < length {
+			return nil, errors.New("invalid record length")
+		}
+		var r kmsg.Record
+		if err := r.ReadFrom(raw[:n+int(length)]); err != nil {
+			return nil, err
+		}
+		raw = raw[n+int(length):]
+
+		if reason := f.reject(&r); reason != "" {
+			er := kmsg.NewProduceResponseTopicPartitionErrorRecord()
+			er.RelativeOffset = i
+			er.ErrorMessage = &reason
+			errs = append(errs, er)
+		}
+	}
+	return errs, nil
+}
+
+// failProduce returns the error code to fail a produce to t p with, if any,
+// counting the request against the failure.
+func (c *Cluster) failProduce(t string, p int32) int16 {
+	f, ok := c.produceFaults.getp(t, p)
+	if !ok || f.fail == nil {
+		return 0
+	}
+	fail := f.fail
+	if !fail.until.IsZero() && !time.Now().Before(fail.until) {
+		f.fail = nil
+		return 0
+	}
+	if fail.remaining > 0 {
+		if fail.remaining--; fail.remaining == 0 {
+			f.fail = nil
+		}
+	}
+	return fail.code
+}
@@ -0,0 +1,92 @@
+package kfake
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestRejectProducedRecords(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(1), SeedTopics(2, "t"))
+	br := newTestClient(t, c).Broker(0)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	c.RejectProducedRecords("t", 0, func(r *kmsg.Record) string {
+		if string(r.Value) == "bad" {
+			return "bad value"
+		}
+		return ""
+	})
+
+	sp := produceBatch(ctx, t, br, "t", 0, kgo.StringRecord("ok"), kgo.StringRecord("bad"), kgo.StringRecord("ok"))
+	if sp.ErrorCode != kerr.InvalidRecord.Code {
+		t.Fatalf("got %v, expected INVALID_RECORD", kerr.ErrorForCode(sp.ErrorCode))
+	}
+	if len(sp.ErrorRecords) != 1 || sp.ErrorRecords[0].RelativeOffset != 1 || sp.ErrorRecords[0].ErrorMessage == nil || *sp.ErrorRecords[0].ErrorMessage != "bad value" {
+		t.Errorf("got error records %+v, expected record 1 rejected with \"bad value\"", sp.ErrorRecords)
+	}
+
+	// Other partitions are unaffected, and nothing from the rejected batch
+	// was written.
+	if sp = produceBatch(ctx, t, br, "t", 1, kgo.StringRecord("bad")); sp.ErrorCode != 0 {
+		t.Errorf("producing to an unfaulted partition: %v", kerr.ErrorForCode(sp.ErrorCode))
+	}
+	c.ClearProduceFaults()
+	if sp = produceBatch(ctx, t, br, "t", 0, kgo.StringRecord("bad")); sp.ErrorCode != 0 || sp.BaseOffset != 0 {
+		t.Errorf("after clearing faults: got %v at offset %d, expected success at offset 0", kerr.ErrorForCode(sp.ErrorCode), sp.BaseOffset)
+	}
+}
+
+func TestFailProduceRequests(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(1), SeedTopics(2, "t"))
+	br := newTestClient(t, c).Broker(0)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	produce := func(p int32) int16 {
+		t.Helper()
+		return produceBatch(ctx, t, br, "t", p, kgo.StringRecord("v")).ErrorCode
+	}
+
+	c.FailProduceRequests("t", 0, kerr.NotLeaderForPartition.Code, 2)
+	for i := 0; i < 2; i++ {
+		if code := produce(0); code != kerr.NotLeaderForPartition.Code {
+			t.Errorf("produce %d: got %v, expected NOT_LEADER_OR_FOLLOWER", i, kerr.ErrorForCode(code))
+		}
+	}
+	if code := produce(1); code != 0 {
+		t.Errorf("producing to an unfaulted partition: %v", kerr.ErrorForCode(code))
+	}
+	if code := produce(0); code != 0 {
+		t.Errorf("produce after the failures ran out: %v", kerr.ErrorForCode(code))
+	}
+
+	// Failing for a duration fails every request until it elapses.
+	c.FailProducesFor("t", 0, kerr.RequestTimedOut.Code, 200*time.Millisecond)
+	for i := 0; i < 3; i++ {
+		if code := produce(0); code != kerr.RequestTimedOut.Code {
+			t.Errorf("produce %d: got %v, expected REQUEST_TIMED_OUT", i, kerr.ErrorForCode(code))
+		}
+	}
+	time.Sleep(250 * time.Millisecond)
+	if code := produce(0); code != 0 {
+		t.Errorf("produce after the duration elapsed: %v", kerr.ErrorForCode(code))
+	}
+
+	// Failing with no count fails until faults are cleared.
+	c.FailProduceRequests("t", 0, kerr.KafkaStorageError.Code, 0)
+	for i := 0; i < 3; i++ {
+		if code := produce(0); code != kerr.KafkaStorageError.Code {
+			t.Errorf("produce %d: got %v, expected KAFKA_STORAGE_ERROR", i, kerr.ErrorForCode(code))
+		}
+	}
+	c.ClearProduceFaults()
+	if code := produce(0); code != 0 {
+		t.Errorf("produce after clearing faults: %v", kerr.ErrorForCode(code))
+	}
+}
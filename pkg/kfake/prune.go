@@ -0,0 +1,110 @@
+package kfake
+
+import (
+	"time"
+)
+
+// Prune prunes internal state that the cluster no longer needs, so that
+// long lived clusters (i.e., in soak tests) do not grow without bound:
+//
+//   - configs and other state for deleted topics, including the topic ID
+//     and async deletion state once deletion finishes
+//   - producer IDs that have expired, and sequence state for partitions of
+//     deleted topics
+//   - committed offsets for deleted topics
+//   - empty groups that have no committed offsets
+//   - coordinator faults that have elapsed
+//
+// Old IDs of deleted topics are forgotten as well: once pruned, fetching with
+// the old ID of a recreated topic fails with UNKNOWN_TOPIC_ID rather than
+// INCONSISTENT_TOPIC_ID. Expired producer IDs are forgotten entirely: once
+// pruned, producing with one is no longer rejected with UNKNOWN_PRODUCER_ID.
+// Groups that are pruned are no longer listed nor described, as Kafka does
+// once an empty group's offsets expire. This is unrelated to log retention and
+// compaction; see CleanLogs for that. See AutoPrune to prune periodically.
+func (c *Cluster) Prune() {
+	c.admin(c.prune)
+}
+
+// AutoPrune runs Prune every interval for the life of the cluster. By
+// default, the cluster is never pruned.
+func AutoPrune(interval time.Duration) Opt {
+	return opt{func(cfg *cfg) { cfg.pruneInterval = interval }}
+}
+
+func (c *Cluster) pruneLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.die:
+			return
+		case <-ticker.C:
+			if !c.tryAdmin(c.prune) {
+				return
+			}
+		}
+	}
+}
+
+func (c *Cluster) prune() {
+	d := &c.data
+	exists := func(t string) bool {
+		_, ok := d.tps[t]
+		return ok
+	}
+
+	for t := range d.deleting {
+		d.isDeleting(t) // drops the topic if deletion finished
+	}
+	d.retired = nil
+	for t := range d.treplicas {
+		if !exists(t) {
+			delete(d.treplicas, t)
+		}
+	}
+	for t := range d.tcfgs {
+		if !exists(t) {
+			delete(d.tcfgs, t)
+		}
+	}
+
+	for id, pm := range c.pids {
+		if pm.expired || c.cfg.pidExpiration > 0 && c.now().Sub(pm.lastUse) >= c.cfg.pidExpiration {
+			delete(c.pids, id)
+			continue
+		}
+		for t := range pm.tps {
+			if !exists(t) {
+				delete(pm.tps, t)
+			}
+		}
+	}
+
+	for name, g := range c.groups.gs {
+		g.waitControl(func() {
+			for t, ps := range g.commits {
+				if !exists(t) {
+					for p := range ps {
+						c.logOffset(name, t, p, nil)
+					}
+					delete(g.commits, t)
+				}
+			}
+			if g.state == groupDead || g.state == groupEmpty && len(g.commits) == 0 && len(g.txnCommits) == 0 {
+				g.quitOnce()
+				delete(c.groups.gs, name)
+			}
+		})
+	}
+
+	c.coordFaultsMu.Lock()
+	keys := make([]string, 0, len(c.coordFaults))
+	for k := range c.coordFaults {
+		keys = append(keys, k)
+	}
+	c.coordFaultsMu.Unlock()
+	for _, k := range keys {
+		c.coordFault(k) // drops the fault if it elapsed
+	}
+}
@@ -0,0 +1,80 @@
+package kfake
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestPrune(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(1), SeedTopics(1, "t", "gone"))
+	cl := newTestClient(t, c)
+	adm := kadm.NewClient(cl)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	retention := "1000"
+	if _, err := adm.AlterTopicConfigs(ctx, []kadm.AlterConfig{{Name: "retention.ms", Value: &retention}}, "gone"); err != nil {
+		t.Fatal(err)
+	}
+	for group, topic := range map[string]string{"live": "t", "dead": "gone"} {
+		var os kadm.Offsets
+		os.AddOffset(topic, 0, 0, -1)
+		if err := adm.CommitAllOffsets(ctx, group, os); err != nil {
+			t.Fatal(err)
+		}
+	}
+	initResp, err := kmsg.NewPtrInitProducerIDRequest().RequestWith(ctx, cl.Broker(0))
+	if err == nil {
+		err = kerr.ErrorForCode(initResp.ErrorCode)
+	}
+	if err != nil {
+		t.Fatalf("init producer ID: %v", err)
+	}
+	c.ExpireProducerID(initResp.ProducerID)
+	if _, err := adm.DeleteTopic(ctx, "gone"); err != nil {
+		t.Fatal(err)
+	}
+
+	var tracked bool
+	c.admin(func() {
+		tracked = len(c.data.retired) > 0 && c.pids[initResp.ProducerID] != nil && c.groups.gs["dead"] != nil
+	})
+	if !tracked {
+		t.Fatal("deleted topic ID, expired producer ID, or group was forgotten before pruning")
+	}
+
+	c.Prune()
+	c.admin(func() {
+		if _, ok := c.data.tcfgs["gone"]; ok {
+			t.Error("configs of the deleted topic were not pruned")
+		}
+		if _, ok := c.data.treplicas["gone"]; ok {
+			t.Error("replicas of the deleted topic were not pruned")
+		}
+		if len(c.data.retired) != 0 {
+			t.Error("the ID of the deleted topic was not pruned")
+		}
+		if _, ok := c.pids[initResp.ProducerID]; ok {
+			t.Error("expired producer ID was not pruned")
+		}
+		if _, ok := c.groups.gs["dead"]; ok {
+			t.Error("group with only offsets for a deleted topic was not pruned")
+		}
+		if _, ok := c.groups.gs["live"]; !ok {
+			t.Error("group with offsets for a live topic was pruned")
+		}
+	})
+
+	described, err := adm.DescribeGroups(ctx, "dead")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g := described["dead"]; g.State != "Dead" {
+		t.Errorf("pruned group is described as %q, expected Dead", g.State)
+	}
+}
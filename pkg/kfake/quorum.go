@@ -0,0 +1,179 @@
+package kfake
+
+import (
+	"github.com/twmb/franz-go/pkg/kerr"
+)
+
+// The cluster simulates a KRaft controller quorum with the controller broker
+// as the sole initial voter and quorum leader. Voters can be added, removed,
+// and updated with the KIP-853 RPCs. Voters do not need to correspond to
+// brokers, but only a voter that is also a broker can become the quorum
+// leader (and thus the controller). If the controller broker is removed,
+// leadership fails over to another broker.
+//
+// Brokers other than the controller can lag behind the controller's metadata,
+// see MetadataPropagationDelay. We do not simulate a metadata log: the high
+// watermark is the number of voter changes that have been made.
+
+const quorumListener = "CONTROLLER"
+
+type (
+	quorum struct {
+		c *Cluster
+
+		epoch  int32
+		hwm    int64
+		voters []*quorumVoter
+	}
+
+	quorumVoter struct {
+		id        int32
+		dir       uuid
+		listeners []quorumVoterListener
+	}
+
+	quorumVoterListener struct {
+		name string
+		host string
+		port uint16
+	}
+)
+
+func (q *quorum) init() {
+	q.epoch = 1
+	q.voters = []*quorumVoter{brokerVoter(q.c.controller)}
+}
+
+// brokerVoter returns a voter for a broker, listening on the broker's
+// primary listener.
+func brokerVoter(b *broker) *quorumVoter {
+	h, p := b.hostport("")
+	return &quorumVoter{
+		id:  b.node,
+		dir: randUUID(),
+		listeners: []quorumVoterListener{{
+			name: quorumListener,
+			host: h,
+			port: uint16(p),
+		}},
+	}
+}
+
+// elect makes the broker the quorum leader and thus the controller, adding
+// the broker as a voter if it is not one already.
+func (q *quorum) elect(b *broker) {
+	if _, v := q.voter(b.node); v == nil {
+		q.voters = append(q.voters, brokerVoter(b))
+		q.hwm++
+	}
+	q.c.controller = b
+	q.epoch++
+}
+
+// failover elects a new quorum leader after the controller broker was removed
+// from the cluster: the first voter that is still a broker, or otherwise the
+// first broker. The removed controller remains a voter, as a KRaft voter that
+// is down does.
+func (q *quorum) failover() {
+	next := q.c.bs[0]
+outer:
+	for _, v := range q.voters {
+		for _, b := range q.c.bs {
+			if b.node == v.id {
+				next = b
+				break outer
+			}
+		}
+	}
+	q.elect(next)
+}
+
+func (q *quorum) leader() int32 { return q.c.controller.node }
+
+func (q *quorum) voter(id int32) (int, *quorumVoter) {
+	for i, v := range q.voters {
+		if v.id == id {
+			return i, v
+		}
+	}
+	return -1, nil
+}
+
+// validateClusterID returns InconsistentClusterID if the request specified a
+// cluster ID that does not match ours.
+func (q *quorum) validateClusterID(id *string) *kerr.Error {
+	if id != nil && *id != q.c.cfg.clusterID {
+		return kerr.InconsistentClusterID
+	}
+	return nil
+}
+
+func (q *quorum) add(id int32, dir uuid, listeners []quorumVoterListener) *kerr.Error {
+	if len(listeners) == 0 {
+		return kerr.InvalidRequest
+	}
+	if _, v := q.voter(id); v != nil {
+		return kerr.DuplicateVoter
+	}
+	q.voters = append(q.voters, &quorumVoter{id, dir, listeners})
+	q.hwm++
+	return nil
+}
+
+// remove removes a voter. If the voter is the quorum leader, leadership moves
+// to the first remaining voter that is also a broker; if there is no such
+// voter, the leader cannot be removed.
+func (q *quorum) remove(id int32, dir uuid) *kerr.Error {
+	i, v := q.voter(id)
+	if v == nil || v.dir != dir {
+		return kerr.VoterNotFound
+	}
+	if len(q.voters) == 1 {
+		return kerr.InvalidRequest
+	}
+	if id == q.leader() {
+		var next *broker
+	outer:
+		for _, ov := range q.voters {
+			if ov == v {
+				continue
+			}
+			for _, b := range q.c.bs {
+				if b.node == ov.id {
+					next = b
+					break outer
+				}
+			}
+		}
+		if next == nil {
+			return kerr.InvalidRequest
+		}
+		q.c.controller = next
+		q.epoch++
+	}
+	q.voters = append(q.voters[:i], q.voters[i+1:]...)
+	q.hwm++
+	return nil
+}
+
+func (q *quorum) update(id int32, dir uuid, epoch int32, listeners []quorumVoterListener) *kerr.Error {
+	_, v := q.voter(id)
+	if v == nil {
+		return kerr.VoterNotFound
+	}
+	if v.dir != dir {
+		return kerr.InvalidVoterKey
+	}
+	switch {
+	case epoch < q.epoch:
+		return kerr.FencedLeaderEpoch
+	case epoch > q.epoch:
+		return kerr.UnknownLeaderEpoch
+	}
+	if len(listeners) == 0 {
+		return kerr.InvalidRequest
+	}
+	v.listeners = listeners
+	q.hwm++
+	return nil
+}
@@ -0,0 +1,103 @@
+package kfake
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestQuorumVoters(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(3), ClusterID("kfake"))
+	br := newTestClient(t, c, withKeys(80, 81, 82)).Broker(0)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	describe := func() kmsg.DescribeQuorumResponseTopicPartition {
+		t.Helper()
+		req := kmsg.NewPtrDescribeQuorumRequest()
+		rt := kmsg.NewDescribeQuorumRequestTopic()
+		rt.Topic = "__cluster_metadata"
+		rt.Partitions = append(rt.Partitions, kmsg.NewDescribeQuorumRequestTopicPartition())
+		req.Topics = append(req.Topics, rt)
+		resp, err := req.RequestWith(ctx, br)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sp := resp.Topics[0].Partitions[0]
+		if err := kerr.ErrorForCode(sp.ErrorCode); err != nil {
+			t.Fatal(err)
+		}
+		return sp
+	}
+	add := func(id int32, clusterID *string) int16 {
+		t.Helper()
+		req := kmsg.NewPtrAddRaftVoterRequest()
+		req.ClusterID = clusterID
+		req.VoterID = id
+		req.VoterDirectoryID = [16]byte{byte(id)}
+		l := kmsg.NewAddRaftVoterRequestListener()
+		l.Name, l.Host, l.Port = "CONTROLLER", "localhost", 9093
+		req.Listeners = append(req.Listeners, l)
+		resp, err := req.RequestWith(ctx, br)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.ErrorCode
+	}
+
+	// The controller starts as the only voter; other brokers observe.
+	sp := describe()
+	controller := sp.LeaderID
+	if len(sp.CurrentVoters) != 1 || sp.CurrentVoters[0].ReplicaID != controller || len(sp.Observers) != 2 {
+		t.Fatalf("got voters %+v and %d observers, expected the controller %d and 2 observers", sp.CurrentVoters, len(sp.Observers), controller)
+	}
+	epoch, hwm := sp.LeaderEpoch, sp.HighWatermark
+
+	var other int32
+	for other == controller {
+		other++
+	}
+	if code := add(other, kmsg.StringPtr("other")); code != kerr.InconsistentClusterID.Code {
+		t.Errorf("add with the wrong cluster ID: got %v, expected INCONSISTENT_CLUSTER_ID", kerr.ErrorForCode(code))
+	}
+	if code := add(other, kmsg.StringPtr("kfake")); code != 0 {
+		t.Fatalf("add: %v", kerr.ErrorForCode(code))
+	}
+	if code := add(other, nil); code != kerr.DuplicateVoter.Code {
+		t.Errorf("add twice: got %v, expected DUPLICATE_VOTER", kerr.ErrorForCode(code))
+	}
+	sp = describe()
+	if len(sp.CurrentVoters) != 2 || len(sp.Observers) != 1 || sp.HighWatermark != hwm+1 {
+		t.Fatalf("after add: got %d voters, %d observers, high watermark %d", len(sp.CurrentVoters), len(sp.Observers), sp.HighWatermark)
+	}
+
+	// Removing the quorum leader moves leadership to the remaining voter.
+	remove := kmsg.NewPtrRemoveRaftVoterRequest()
+	remove.VoterID = controller
+	remove.VoterDirectoryID = sp.CurrentVoters[0].ReplicaDirectoryID
+	resp, err := remove.RequestWith(ctx, br)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := kerr.ErrorForCode(resp.ErrorCode); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	sp = describe()
+	if sp.LeaderID != other || sp.LeaderEpoch != epoch+1 || len(sp.CurrentVoters) != 1 {
+		t.Fatalf("after removing the leader: got leader %d epoch %d with %d voters, expected leader %d epoch %d with 1 voter",
+			sp.LeaderID, sp.LeaderEpoch, len(sp.CurrentVoters), other, epoch+1)
+	}
+
+	// The last voter cannot be removed.
+	remove.VoterID = other
+	remove.VoterDirectoryID = [16]byte{byte(other)}
+	if resp, err = remove.RequestWith(ctx, br); err != nil {
+		t.Fatal(err)
+	}
+	if resp.ErrorCode != kerr.InvalidRequest.Code {
+		t.Errorf("removing the last voter: got %v, expected INVALID_REQUEST", kerr.ErrorForCode(resp.ErrorCode))
+	}
+}
@@ -0,0 +1,319 @@
+package kfake
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// Client quotas (KIP-546) are stored per entity: a user, a client ID, a user
+// and client ID, or an IP. An entity component with a nil name is the default
+// for its type. Quotas are described and altered with DescribeClientQuotas
+// and AlterClientQuotas, which require DESCRIBE_CONFIGS and ALTER_CONFIGS on
+// the cluster.
+//
+// Only byte rate quotas are enforced. As in Kafka, a request's quota is
+// resolved from the most specific entity that sets the quota key, in order:
+//
+//   - user and client ID
+//   - user and default client ID
+//   - user
+//   - default user and client ID
+//   - default user and default client ID
+//   - default user
+//   - client ID
+//   - default client ID
+//
+// The user is the client's principal name (ANONYMOUS for unauthenticated
+// clients). Each broker tracks usage separately, as in Kafka, with a token
+// bucket that refills at the quota's rate and holds up to one second of
+// bytes. Produce request record bytes count against producer_byte_rate, and
+// fetch response record bytes count against consumer_byte_rate. Once a
+// bucket is in debt, responses have a throttle time of how long the bucket
+// takes to refill to zero. A fetch response crossing into debt keeps its
+// records, but fetch responses while already in debt have their records
+// removed, as Kafka returns empty throttled fetch responses. Produce v0-v5
+// and fetch v0-v7, which predate clients throttling themselves (KIP-219),
+// have their response delayed by the throttle instead. Buckets refill with
+// the cluster clock, so AdvanceTime clears throttling.
+//
+// request_percentage, controller_mutation_rate, and connection_creation_rate
+// quotas are stored and described but not enforced.
+
+const (
+	quotaTypeUser     = "user"
+	quotaTypeClientID = "client-id"
+	quotaTypeIP       = "ip"
+
+	quotaProducerByteRate = "producer_byte_rate"
+	quotaConsumerByteRate = "consumer_byte_rate"
+)
+
+type (
+	quotas map[string]*quotaEntity // by quotaEntityKey
+
+	quotaEntity struct {
+		components []quotaComponent // sorted by type
+		values     map[string]float64
+	}
+
+	quotaComponent struct {
+		typ  string
+		name *string // nil for the default
+	}
+
+	quotaBucketKey struct {
+		quota          string
+		user, clientID string // the entity's user and client ID, if it has the component
+	}
+
+	quotaBucket struct {
+		bytes float64 // negative when in debt
+		last  time.Time
+	}
+)
+
+// quotaKeys are the valid quota keys, and whether each is a whole number.
+var quotaKeys = map[string]struct {
+	ip    bool // whether this is for IP entities rather than user and client ID entities
+	whole bool
+}{
+	quotaProducerByteRate:      {false, true},
+	quotaConsumerByteRate:      {false, true},
+	"request_percentage":       {false, false},
+	"controller_mutation_rate": {false, false},
+	"connection_creation_rate": {true, true},
+}
+
+func quotaEntityKey(components []quotaComponent) string {
+	var sb strings.Builder
+	for _, c := range components {
+		sb.WriteString(c.typ)
+		if c.name == nil {
+			sb.WriteString("\x00d\x00")
+		} else {
+			sb.WriteString("\x00n")
+			sb.WriteString(*c.name)
+			sb.WriteByte(0)
+		}
+	}
+	return sb.String()
+}
+
+// validateQuotaEntity validates and sorts an entity's components.
+func validateQuotaEntity(components []quotaComponent) error {
+	if len(components) == 0 {
+		return errors.New("entity must have at least one component")
+	}
+	sort.Slice(components, func(i, j int) bool { return components[i].typ < components[j].typ })
+	var hasIP bool
+	for i, c := range components {
+		switch c.typ {
+		case quotaTypeUser, quotaTypeClientID:
+		case quotaTypeIP:
+			hasIP = true
+			if c.name != nil && net.ParseIP(*c.name) == nil {
+				return fmt.Errorf("invalid ip address %q", *c.name)
+			}
+		default:
+			return fmt.Errorf("unknown entity type %q", c.typ)
+		}
+		if i > 0 && components[i-1].typ == c.typ {
+			return fmt.Errorf("duplicate entity type %q", c.typ)
+		}
+	}
+	if hasIP && len(components) > 1 {
+		return errors.New("ip entities cannot be combined with user or client-id entities")
+	}
+	return nil
+}
+
+// alter applies ops to an entity, validating every op before applying any.
+func (qs *quotas) alter(components []quotaComponent, ops []kmsg.AlterClientQuotasRequestEntryOp, validateOnly bool) error {
+	if err := validateQuotaEntity(components); err != nil {
+		return err
+	}
+	isIP := components[0].typ == quotaTypeIP
+	for _, op := range ops {
+		k, ok := quotaKeys[op.Key]
+		if !ok {
+			return fmt.Errorf("unknown quota key %q", op.Key)
+		}
+		if k.ip != isIP {
+			return fmt.Errorf("quota key %q is not valid for the entity", op.Key)
+		}
+		if op.Remove {
+			continue
+		}
+		if op.Value <= 0 || math.IsInf(op.Value, 0) || math.IsNaN(op.Value) {
+			return fmt.Errorf("quota %q value %v must be positive", op.Key, op.Value)
+		}
+		if k.whole && op.Value != math.Trunc(op.Value) {
+			return fmt.Errorf("quota %q value %v must be a whole number", op.Key, op.Value)
+		}
+	}
+	if validateOnly {
+		return nil
+	}
+
+	if *qs == nil {
+		*qs = make(quotas)
+	}
+	key := quotaEntityKey(components)
+	e := (*qs)[key]
+	if e == nil {
+		e = &quotaEntity{components: components, values: make(map[string]float64)}
+		(*qs)[key] = e
+	}
+	for _, op := range ops {
+		if op.Remove {
+			delete(e.values, op.Key)
+		} else {
+			e.values[op.Key] = op.Value
+		}
+	}
+	if len(e.values) == 0 {
+		delete(*qs, key)
+	}
+	return nil
+}
+
+// resolve returns the quota for a user and client ID, along with the bucket
+// it is tracked in.
+func (qs quotas) resolve(quota, user, clientID string) (float64, quotaBucketKey, bool) {
+	if len(qs) == 0 {
+		return 0, quotaBucketKey{}, false
+	}
+	var (
+		u, cid = &user, &clientID
+		order  = [][]quotaComponent{
+			{{quotaTypeClientID, cid}, {quotaTypeUser, u}},
+			{{quotaTypeClientID, nil}, {quotaTypeUser, u}},
+			{{quotaTypeUser, u}},
+			{{quotaTypeClientID, cid}, {quotaTypeUser, nil}},
+			{{quotaTypeClientID, nil}, {quotaTypeUser, nil}},
+			{{quotaTypeUser, nil}},
+			{{quotaTypeClientID, cid}},
+			{{quotaTypeClientID, nil}},
+		}
+	)
+	for _, components := range order {
+		e := qs[quotaEntityKey(components)]
+		if e == nil {
+			continue
+		}
+		v, ok := e.values[quota]
+		if !ok {
+			continue
+		}
+		bk := quotaBucketKey{quota: quota}
+		for _, c := range components {
+			if c.typ == quotaTypeUser {
+				bk.user = user
+			} else {
+				bk.clientID = clientID
+			}
+		}
+		return v, bk, true
+	}
+	return 0, quotaBucketKey{}, false
+}
+
+// quotaBucket returns the client's refilled bucket for a quota on the
+// request's broker, and the quota's rate, or nil if the client has no quota.
+func (c *Cluster) quotaBucket(creq *clientReq, quota string) (*quotaBucket, float64) {
+	principal, _ := c.clientPrincipal(creq)
+	user := strings.TrimPrefix(principal, "User:")
+	rate, bk, ok := c.quotas.resolve(quota, user, creq.cid)
+	if !ok {
+		return nil, 0
+	}
+
+	b := creq.cc.b
+	if b.quotaBuckets == nil {
+		b.quotaBuckets = make(map[quotaBucketKey]*quotaBucket)
+	}
+	now := c.now()
+	bucket := b.quotaBuckets[bk]
+	if bucket == nil {
+		bucket = &quotaBucket{bytes: rate, last: now}
+		b.quotaBuckets[bk] = bucket
+	}
+	if elapsed := now.Sub(bucket.last); elapsed > 0 {
+		bucket.bytes = math.Min(rate, bucket.bytes+elapsed.Seconds()*rate)
+		bucket.last = now
+	}
+	return bucket, rate
+}
+
+// throttle returns how long until the bucket is out of debt.
+func (bucket *quotaBucket) throttle(rate float64) time.Duration {
+	if bucket.bytes >= 0 {
+		return 0
+	}
+	return time.Duration(-bucket.bytes / rate * float64(time.Second))
+}
+
+// throttleProduce records a produce request's bytes against the client's
+// producer_byte_rate quota, setting the response's throttle. This returns how
+// long to delay the response, for versions that predate KIP-219.
+func (c *Cluster) throttleProduce(creq *clientReq, kresp kmsg.Response) time.Duration {
+	resp, ok := kresp.(*kmsg.ProduceResponse)
+	if !ok || len(c.quotas) == 0 {
+		return 0
+	}
+	bucket, rate := c.quotaBucket(creq, quotaProducerByteRate)
+	if bucket == nil {
+		return 0
+	}
+	req := creq.kreq.(*kmsg.ProduceRequest)
+	for _, rt := range req.Topics {
+		for _, rp := range rt.Partitions {
+			bucket.bytes -= float64(len(rp.Records))
+		}
+	}
+	throttle := bucket.throttle(rate)
+	resp.ThrottleMillis = int32(throttle.Milliseconds())
+	if req.Version < 6 {
+		return throttle
+	}
+	return 0
+}
+
+// throttleFetch records a fetch response's bytes against the client's
+// consumer_byte_rate quota and sets the response's throttle. If the client was
+// already throttled, the response's records are removed and not counted. This
+// returns how long to delay the response, for versions that predate KIP-219.
+func (c *Cluster) throttleFetch(creq *clientReq, kresp kmsg.Response) time.Duration {
+	resp, ok := kresp.(*kmsg.FetchResponse)
+	if !ok || len(c.quotas) == 0 {
+		return 0
+	}
+	bucket, rate := c.quotaBucket(creq, quotaConsumerByteRate)
+	if bucket == nil {
+		return 0
+	}
+	throttled := bucket.bytes < 0
+	for i := range resp.Topics {
+		for j := range resp.Topics[i].Partitions {
+			rp := &resp.Topics[i].Partitions[j]
+			if throttled {
+				rp.RecordBatches = nil
+			} else {
+				bucket.bytes -= float64(len(rp.RecordBatches))
+			}
+		}
+	}
+	throttle := bucket.throttle(rate)
+	resp.ThrottleMillis = int32(throttle.Milliseconds())
+	if resp.Version < 8 {
+		return throttle
+	}
+	return 0
+}
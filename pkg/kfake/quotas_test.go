@@ -0,0 +1,247 @@
+package kfake
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// alterQuota sets or, with a negative value, removes quotas for an entity of
+// alternating component types and names, returning the entry's error code.
+func alterQuota(ctx context.Context, t *testing.T, br *kgo.Broker, entity []string, kvs map[string]float64) int16 {
+	t.Helper()
+	req := kmsg.NewPtrAlterClientQuotasRequest()
+	re := kmsg.NewAlterClientQuotasRequestEntry()
+	for i := 0; i < len(entity); i += 2 {
+		e := kmsg.NewAlterClientQuotasRequestEntryEntity()
+		e.Type = entity[i]
+		if name := entity[i+1]; name != "" {
+			e.Name = &name
+		}
+		re.Entity = append(re.Entity, e)
+	}
+	for k, v := range kvs {
+		op := kmsg.NewAlterClientQuotasRequestEntryOp()
+		op.Key, op.Value, op.Remove = k, v, v < 0
+		re.Ops = append(re.Ops, op)
+	}
+	req.Entries = append(req.Entries, re)
+	resp, err := req.RequestWith(ctx, br)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.Entries[0].ErrorCode
+}
+
+func TestClientQuotasAlterDescribe(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(1))
+	br := newTestClient(t, c).Broker(0)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	user := []string{quotaTypeUser, "u"}
+	for _, test := range []struct {
+		name   string
+		entity []string
+		key    string
+		value  float64
+	}{
+		{"unknown key", user, "bytes_per_fortnight", 1},
+		{"ip key on a user", user, "connection_creation_rate", 1},
+		{"fractional byte rate", user, quotaProducerByteRate, 1.5},
+		{"zero value", user, "request_percentage", 0},
+		{"unknown entity type", []string{"group", "g"}, quotaProducerByteRate, 1},
+		{"ip with a user", []string{quotaTypeUser, "u", quotaTypeIP, "127.0.0.1"}, "connection_creation_rate", 1},
+		{"invalid ip", []string{quotaTypeIP, "nope"}, "connection_creation_rate", 1},
+	} {
+		if code := alterQuota(ctx, t, br, test.entity, map[string]float64{test.key: test.value}); code == 0 {
+			t.Errorf("%s: alter succeeded", test.name)
+		}
+	}
+
+	describe := func() map[string]float64 {
+		t.Helper()
+		req := kmsg.NewPtrDescribeClientQuotasRequest()
+		req.Strict = true
+		rc := kmsg.NewDescribeClientQuotasRequestComponent()
+		rc.EntityType, rc.Match = quotaTypeUser, kmsg.StringPtr("u")
+		req.Components = append(req.Components, rc)
+		resp, err := req.RequestWith(ctx, br)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.ErrorCode != 0 {
+			t.Fatalf("describe error code %d", resp.ErrorCode)
+		}
+		vs := make(map[string]float64)
+		for _, e := range resp.Entries {
+			for _, v := range e.Values {
+				vs[v.Key] = v.Value
+			}
+		}
+		return vs
+	}
+
+	if code := alterQuota(ctx, t, br, user, map[string]float64{quotaProducerByteRate: 100, "request_percentage": 12.5}); code != 0 {
+		t.Fatalf("alter error code %d", code)
+	}
+	vs := describe()
+	if v := vs[quotaProducerByteRate]; v != 100 {
+		t.Errorf("got producer_byte_rate %v, expected 100", v)
+	}
+	if v := vs["request_percentage"]; v != 12.5 {
+		t.Errorf("got request_percentage %v, expected 12.5", v)
+	}
+
+	// Removing every quota removes the entity.
+	if code := alterQuota(ctx, t, br, user, map[string]float64{quotaProducerByteRate: -1, "request_percentage": -1}); code != 0 {
+		t.Fatalf("alter error code %d", code)
+	}
+	if vs := describe(); len(vs) != 0 {
+		t.Errorf("got quotas %v after removing them all", vs)
+	}
+}
+
+func TestClientQuotasResolve(t *testing.T) {
+	str := func(s string) *string { return &s }
+	var qs quotas
+	set := func(rate float64, components ...quotaComponent) {
+		t.Helper()
+		op := kmsg.NewAlterClientQuotasRequestEntryOp()
+		op.Key, op.Value = quotaProducerByteRate, rate
+		if err := qs.alter(components, []kmsg.AlterClientQuotasRequestEntryOp{op}, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	set(1, quotaComponent{quotaTypeClientID, nil})
+	set(2, quotaComponent{quotaTypeClientID, str("c")})
+	set(3, quotaComponent{quotaTypeUser, nil})
+	set(4, quotaComponent{quotaTypeUser, str("u")})
+	set(5, quotaComponent{quotaTypeUser, str("u")}, quotaComponent{quotaTypeClientID, str("c")})
+
+	for _, test := range []struct {
+		user, clientID string
+		exp            float64
+		bucket         quotaBucketKey
+	}{
+		{"u", "c", 5, quotaBucketKey{quotaProducerByteRate, "u", "c"}},
+		{"u", "other", 4, quotaBucketKey{quotaProducerByteRate, "u", ""}},
+		{"other", "c", 3, quotaBucketKey{quotaProducerByteRate, "other", ""}},
+	} {
+		rate, bk, ok := qs.resolve(quotaProducerByteRate, test.user, test.clientID)
+		if !ok || rate != test.exp || bk != test.bucket {
+			t.Errorf("%s/%s: got %v %+v %v, expected %v %+v", test.user, test.clientID, rate, bk, ok, test.exp, test.bucket)
+		}
+	}
+	if _, _, ok := qs.resolve(quotaConsumerByteRate, "u", "c"); ok {
+		t.Error("resolved an unset quota")
+	}
+
+	// Without user quotas, client ID quotas apply.
+	del := kmsg.NewAlterClientQuotasRequestEntryOp()
+	del.Key, del.Remove = quotaProducerByteRate, true
+	for _, components := range [][]quotaComponent{
+		{{quotaTypeUser, nil}},
+		{{quotaTypeUser, str("u")}},
+		{{quotaTypeUser, str("u")}, {quotaTypeClientID, str("c")}},
+	} {
+		if err := qs.alter(components, []kmsg.AlterClientQuotasRequestEntryOp{del}, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if rate, _, _ := qs.resolve(quotaProducerByteRate, "u", "c"); rate != 2 {
+		t.Errorf("got client ID rate %v, expected 2", rate)
+	}
+	if rate, _, _ := qs.resolve(quotaProducerByteRate, "u", "other"); rate != 1 {
+		t.Errorf("got default client ID rate %v, expected 1", rate)
+	}
+}
+
+func TestClientQuotasThrottle(t *testing.T) {
+	now := time.Now()
+	c := newTestCluster(t, NumBrokers(1), SeedTopics(1, "t"), Clock(func() time.Time { return now }))
+	cl := newTestClient(t, c)
+	br := cl.Broker(0)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Incompressible values, so each batch is a bit over 500 bytes.
+	value := make([]byte, 500)
+	rand.New(rand.NewSource(0)).Read(value)
+	produce := func() int32 {
+		t.Helper()
+		req := kmsg.NewPtrProduceRequest()
+		req.Acks = -1
+		req.TimeoutMillis = 5000
+		b := newRecordBatchFrom([]*kgo.Record{{Value: value, Timestamp: now}})
+		if err := recompress(&b, 0); err != nil {
+			t.Fatal(err)
+		}
+		rt := kmsg.NewProduceRequestTopic()
+		rt.Topic = "t"
+		rp := kmsg.NewProduceRequestTopicPartition()
+		rp.Records = b.AppendTo(nil)
+		rt.Partitions = append(rt.Partitions, rp)
+		req.Topics = append(req.Topics, rt)
+		resp, err := req.RequestWith(ctx, br)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if code := resp.Topics[0].Partitions[0].ErrorCode; code != 0 {
+			t.Fatalf("produce error code %d", code)
+		}
+		return resp.ThrottleMillis
+	}
+
+	if ms := produce(); ms != 0 {
+		t.Fatalf("throttled %dms without a quota", ms)
+	}
+	if code := alterQuota(ctx, t, br, []string{quotaTypeClientID, "kgo"}, map[string]float64{quotaProducerByteRate: 1000, quotaConsumerByteRate: 2000}); code != 0 {
+		t.Fatalf("alter error code %d", code)
+	}
+
+	// The bucket starts with a second of bytes.
+	if ms := produce(); ms != 0 {
+		t.Errorf("throttled %dms within the quota", ms)
+	}
+	if ms := produce(); ms <= 0 || ms >= 1000 {
+		t.Errorf("got throttle %dms over the quota, expected 0 < throttle < 1000", ms)
+	}
+	c.AdvanceTime(time.Second)
+	if ms := produce(); ms != 0 {
+		t.Errorf("throttled %dms after the bucket refilled", ms)
+	}
+
+	// Fetch responses crossing into debt keep their records, and
+	// responses while in debt are empty.
+	fetch := func() (int, int32) {
+		t.Helper()
+		req := kmsg.NewPtrFetchRequest()
+		req.MaxBytes = 1 << 20
+		rt := kmsg.NewFetchRequestTopic()
+		c.admin(func() { rt.TopicID = c.data.t2id["t"] })
+		rp := kmsg.NewFetchRequestTopicPartition()
+		rp.PartitionMaxBytes = 1 << 20
+		rt.Partitions = append(rt.Partitions, rp)
+		req.Topics = append(req.Topics, rt)
+		resp, err := req.RequestWith(ctx, br)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return len(resp.Topics[0].Partitions[0].RecordBatches), resp.ThrottleMillis
+	}
+	if n, ms := fetch(); n == 0 || ms <= 0 {
+		t.Errorf("first fetch: got %d bytes throttled %dms, expected records and a throttle", n, ms)
+	}
+	if n, ms := fetch(); n != 0 || ms <= 0 {
+		t.Errorf("fetch in debt: got %d bytes throttled %dms, expected no records and a throttle", n, ms)
+	}
+	c.AdvanceTime(time.Minute)
+	if n, _ := fetch(); n == 0 {
+		t.Error("fetch after the bucket refilled returned no records")
+	}
+}
@@ -0,0 +1,52 @@
+package kfake
+
+import (
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// Brokers can be placed in racks with WithRacks. Racks are advertised in
+// Metadata and as each broker's broker.rack config.
+//
+// Consumers can fetch from followers (KIP-392): Fetch v11+ is served by any
+// replica of a partition, not just the leader. If a consumer's fetch to the
+// leader has a rack ID, and the leader is not in the consumer's rack but an
+// in sync replica is, the leader replies with that replica as the preferred
+// read replica and no records, as Kafka's RackAwareReplicaSelector does. The
+// consumer then fetches from the preferred replica. Replicas share the
+// leader's log, so followers are never behind.
+
+// WithRacks assigns racks to brokers: broker i is in rack racks[i%len(racks)].
+// Brokers added later with AddNode continue the rotation.
+func WithRacks(racks ...string) Opt {
+	return opt{func(cfg *cfg) { cfg.racks = racks }}
+}
+
+// rackFor returns the rack for the broker at idx, or nil if racks are not
+// configured.
+func (c *Cluster) rackFor(idx int) *string {
+	if len(c.cfg.racks) == 0 {
+		return nil
+	}
+	rack := c.cfg.racks[idx%len(c.cfg.racks)]
+	return &rack
+}
+
+// fetchReplica returns whether the request's broker can serve a fetch for the
+// partition, and if so, the preferred read replica the client should fetch
+// from instead, or -1.
+func (c *Cluster) fetchReplica(creq *clientReq, t string, pd *partData) (bool, int32) {
+	b := creq.cc.b
+	req := creq.kreq.(*kmsg.FetchRequest)
+	if pd.leader != b {
+		return req.Version >= 11 && contains(c.replicas(t, pd), b.node), -1
+	}
+	if req.Version < 11 || req.Rack == "" || b.rack != nil && *b.rack == req.Rack {
+		return true, -1
+	}
+	for _, node := range c.isr(t, pd) {
+		if rb := c.liveBroker(node); rb != nil && rb.rack != nil && *rb.rack == req.Rack {
+			return true, node
+		}
+	}
+	return true, -1
+}
@@ -0,0 +1,133 @@
+package kfake
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestFetchFromFollower(t *testing.T) {
+	racks := []string{"a", "b", "c"}
+	c := newTestCluster(t, NumBrokers(3), WithRacks(racks...))
+	cl := newTestClient(t, c)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	adm := kadm.NewClient(cl)
+	if _, err := adm.CreateTopic(ctx, 1, 3, nil, "t"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := adm.CreateTopic(ctx, 1, 1, nil, "single"); err != nil {
+		t.Fatal(err)
+	}
+	for _, topic := range []string{"t", "single"} {
+		if _, err := c.ProduceTo(topic, 0, kgo.StringRecord("v")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	resp, err := kmsg.NewPtrMetadataRequest().RequestWith(ctx, cl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rackOf := make(map[int32]string)
+	for _, b := range resp.Brokers {
+		if b.Rack == nil {
+			t.Fatalf("broker %d has no rack", b.NodeID)
+		}
+		rackOf[b.NodeID] = *b.Rack
+	}
+	if len(rackOf) != 3 || rackOf[0] != "a" || rackOf[1] != "b" || rackOf[2] != "c" {
+		t.Errorf("got racks %v, expected brokers 0-2 in racks a-c", rackOf)
+	}
+	leaders := make(map[string]int32)
+	for _, rt := range resp.Topics {
+		leaders[*rt.Topic] = rt.Partitions[0].Leader
+	}
+	leader := leaders["t"]
+	follower := (leader + 1) % 3
+
+	fetch := func(node int32, topic, rack string) kmsg.FetchResponseTopicPartition {
+		t.Helper()
+		req := kmsg.NewPtrFetchRequest()
+		req.MaxBytes = 1 << 20
+		req.Rack = rack
+		rt := kmsg.NewFetchRequestTopic()
+		c.admin(func() { rt.TopicID = c.data.t2id[topic] })
+		rp := kmsg.NewFetchRequestTopicPartition()
+		rp.PartitionMaxBytes = 1 << 20
+		rt.Partitions = append(rt.Partitions, rp)
+		req.Topics = append(req.Topics, rt)
+		resp, err := req.RequestWith(ctx, cl.Broker(int(node)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.Topics[0].Partitions[0]
+	}
+	check := func(name string, p kmsg.FetchResponseTopicPartition, preferred int32, records bool) {
+		t.Helper()
+		if err := kerr.ErrorForCode(p.ErrorCode); err != nil {
+			t.Errorf("%s: %v", name, err)
+			return
+		}
+		if p.PreferredReadReplica != preferred {
+			t.Errorf("%s: got preferred read replica %d, expected %d", name, p.PreferredReadReplica, preferred)
+		}
+		if got := len(p.RecordBatches) > 0; got != records {
+			t.Errorf("%s: got records %v, expected %v", name, got, records)
+		}
+	}
+
+	check("leader without a rack", fetch(leader, "t", ""), -1, true)
+	check("leader in the client's rack", fetch(leader, "t", rackOf[leader]), -1, true)
+	check("leader in another rack", fetch(leader, "t", rackOf[follower]), follower, false)
+	check("follower", fetch(follower, "t", rackOf[follower]), -1, true)
+	check("rack without a replica", fetch(leader, "t", "z"), -1, true)
+
+	// Out of sync replicas are not preferred.
+	if err := c.SetReplicaInSync("t", 0, follower, false); err != nil {
+		t.Fatal(err)
+	}
+	check("leader with an out of sync replica in the client's rack", fetch(leader, "t", rackOf[follower]), -1, true)
+	if err := c.SetReplicaInSync("t", 0, follower, true); err != nil {
+		t.Fatal(err)
+	}
+
+	// Brokers that are not replicas do not serve fetches.
+	other := (leaders["single"] + 1) % 3
+	if p := fetch(other, "single", ""); p.ErrorCode != kerr.NotLeaderForPartition.Code {
+		t.Errorf("fetch from a non-replica: got %v, expected NOT_LEADER_OR_FOLLOWER", kerr.ErrorForCode(p.ErrorCode))
+	}
+
+	// A consumer in the follower's rack follows the redirect.
+	var (
+		mu      sync.Mutex
+		fetched = make(map[int32]bool)
+	)
+	c.ControlKey(int16(kmsg.Fetch), func(kmsg.Request) (kmsg.Response, error, bool) {
+		c.KeepControl()
+		mu.Lock()
+		defer mu.Unlock()
+		fetched[c.CurrentNode()] = true
+		return nil, nil, false
+	})
+	consumer := newTestClient(t, c, kgo.Rack(rackOf[follower]), kgo.ConsumeTopics("t"), kgo.ConsumeResetOffset(kgo.NewOffset().AtStart()))
+	fs := consumer.PollFetches(ctx)
+	if err := fs.Err0(); err != nil {
+		t.Fatal(err)
+	}
+	if n := fs.NumRecords(); n != 1 {
+		t.Errorf("consumer in rack %s got %d records, expected 1", rackOf[follower], n)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if !fetched[follower] {
+		t.Errorf("consumer in rack %s did not fetch from follower %d, fetched from %v", rackOf[follower], follower, fetched)
+	}
+}
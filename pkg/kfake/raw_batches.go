@@ -0,0 +1,40 @@
+package kfake
+
+import (
+	"errors"
+
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// AppendRecordBatch appends b directly to the log of the given partition,
+// bypassing all produce validation, and returns the offset the batch was
+// appended at. This can be used to test how clients handle unusual or
+// malformed batches: any attributes (compression, timestamp type, the
+// transactional and control bits), producer ID, epoch, and sequence are kept
+// as is, and the batch is not checked for being decodable.
+//
+// The log assigns the batch's FirstOffset and PartitionLeaderEpoch; every
+// other field is served in fetch responses exactly as provided, including
+// Length and CRC, which are not recomputed. NumRecords and LastOffsetDelta
+// should agree, since the high watermark advances by NumRecords while
+// clients use LastOffsetDelta to determine the next offset to fetch.
+//
+// Appending wakes any fetches waiting on the partition. This returns an error
+// if the partition does not exist.
+func (c *Cluster) AppendRecordBatch(topic string, partition int32, b kmsg.RecordBatch) (int64, error) {
+	var (
+		offset int64
+		err    error
+	)
+	c.admin(func() {
+		pd, ok := c.data.tps.getp(topic, partition)
+		if !ok {
+			err = errors.New("topic/partition not found")
+			return
+		}
+		offset = pd.highWatermark
+		pd.pushBatch(len(b.AppendTo(nil)), b)
+		c.eosRecord(topic, partition, offset, &b)
+	})
+	return offset, err
+}
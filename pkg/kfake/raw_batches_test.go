@@ -0,0 +1,59 @@
+package kfake
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func TestAppendRecordBatch(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(1), SeedTopics(1, "t"))
+	cl := newTestClient(t, c)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if _, err := c.AppendRecordBatch("t", 1, newRecordBatchFrom([]*kgo.Record{kgo.StringRecord("v")})); err == nil {
+		t.Error("appending to a missing partition did not fail")
+	}
+	if _, err := c.ProduceTo("t", 0, kgo.StringRecord("v0")); err != nil {
+		t.Fatal(err)
+	}
+
+	// The batch is kept as is, including its invalid CRC.
+	b := newRecordBatchFrom([]*kgo.Record{kgo.StringRecord("v1"), kgo.StringRecord("v2")})
+	b.Attributes = 0x08 // log append time
+	b.ProducerID, b.ProducerEpoch, b.FirstSequence = 7, 3, 100
+	b.CRC = 1
+	offset, err := c.AppendRecordBatch("t", 0, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if offset != 1 {
+		t.Errorf("batch was appended at offset %d, expected 1", offset)
+	}
+
+	var id [16]byte
+	c.admin(func() {
+		id = c.data.t2id["t"]
+		pd, _ := c.data.tps.getp("t", 0)
+		got := pd.batches[1].RecordBatch
+		if got.FirstOffset != 1 || got.PartitionLeaderEpoch != pd.epoch {
+			t.Errorf("got first offset %d and leader epoch %d, expected 1 and %d", got.FirstOffset, got.PartitionLeaderEpoch, pd.epoch)
+		}
+		if got.Attributes != b.Attributes || got.ProducerID != 7 || got.ProducerEpoch != 3 || got.FirstSequence != 100 || got.CRC != 1 {
+			t.Errorf("stored batch %+v does not match the appended batch", got)
+		}
+	})
+
+	rs := fetchRecords(ctx, t, cl, id, 1)
+	if len(rs) != 2 {
+		t.Fatalf("fetched %d records, expected 2", len(rs))
+	}
+	for i, r := range rs {
+		if r.offset != int64(i+1) || r.crcOK {
+			t.Errorf("got record %+v, expected offset %d with a bad CRC", r, i+1)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package kfake
+
+import (
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// SetReadOnly puts the cluster in read only mode, emulating a cluster in
+// maintenance: requests that write (produce, topic and partition creation and
+// deletion, record deletion, offset commits and deletions, group deletion,
+// config alterations, and ACL creation and deletion) fail with errCode, while
+// everything else, including fetching and group membership, continues to
+// work. An errCode of zero makes the cluster writable again.
+func (c *Cluster) SetReadOnly(errCode int16) {
+	c.admin(func() {
+		c.readOnly = errCode
+	})
+}
+
+// rejectWrite returns an error response if the cluster is read only and the
+// request writes.
+func (c *Cluster) rejectWrite(kreq kmsg.Request) (kmsg.Response, bool) {
+	if c.readOnly == 0 {
+		return nil, false
+	}
+	return writeErrResponse(kreq, c.readOnly)
+}
+
+// writeErrResponse returns a response failing every topic, partition, or
+// resource in a write request with code. This returns false if the request
+// does not write, and a nil response for produce requests with acks=0.
+func writeErrResponse(kreq kmsg.Request, code int16) (kmsg.Response, bool) {
+	kresp := kreq.ResponseKind()
+	kresp.SetVersion(kreq.GetVersion())
+
+	switch req := kreq.(type) {
+	case *kmsg.ProduceRequest:
+		resp := kresp.(*kmsg.ProduceResponse)
+		for _, rt := range req.Topics {
+			st := kmsg.NewProduceResponseTopic()
+			st.Topic = rt.Topic
+			for _, rp := range rt.Partitions {
+				sp := kmsg.NewProduceResponseTopicPartition()
+				sp.Partition = rp.Partition
+				sp.ErrorCode = code
+				st.Partitions = append(st.Partitions, sp)
+			}
+			resp.Topics = append(resp.Topics, st)
+		}
+		if req.Acks == 0 {
+			return nil, true
+		}
+
+	case *kmsg.CreateTopicsRequest:
+		resp := kresp.(*kmsg.CreateTopicsResponse)
+		for _, rt := range req.Topics {
+			st := kmsg.NewCreateTopicsResponseTopic()
+			st.Topic = rt.Topic
+			st.ErrorCode = code
+			resp.Topics = append(resp.Topics, st)
+		}
+
+	case *kmsg.DeleteTopicsRequest:
+		resp := kresp.(*kmsg.DeleteTopicsResponse)
+		for _, t := range req.TopicNames {
+			st := kmsg.NewDeleteTopicsResponseTopic()
+			st.Topic = kmsg.StringPtr(t)
+			st.ErrorCode = code
+			resp.Topics = append(resp.Topics, st)
+		}
+		for _, rt := range req.Topics {
+			st := kmsg.NewDeleteTopicsResponseTopic()
+			st.Topic = rt.Topic
+			st.TopicID = rt.TopicID
+			st.ErrorCode = code
+			resp.Topics = append(resp.Topics, st)
+		}
+
+	case *kmsg.CreatePartitionsRequest:
+		resp := kresp.(*kmsg.CreatePartitionsResponse)
+		for _, rt := range req.Topics {
+			st := kmsg.NewCreatePartitionsResponseTopic()
+			st.Topic = rt.Topic
+			st.ErrorCode = code
+			resp.Topics = append(resp.Topics, st)
+		}
+
+	case *kmsg.DeleteRecordsRequest:
+		resp := kresp.(*kmsg.DeleteRecordsResponse)
+		for _, rt := range req.Topics {
+			st := kmsg.NewDeleteRecordsResponseTopic()
+			st.Topic = rt.Topic
+			for _, rp := range rt.Partitions {
+				sp := kmsg.NewDeleteRecordsResponseTopicPartition()
+				sp.Partition = rp.Partition
+				sp.ErrorCode = code
+				st.Partitions = append(st.Partitions, sp)
+			}
+			resp.Topics = append(resp.Topics, st)
+		}
+
+	case *kmsg.OffsetCommitRequest:
+		resp := kresp.(*kmsg.OffsetCommitResponse)
+		for _, rt := range req.Topics {
+			st := kmsg.NewOffsetCommitResponseTopic()
+			st.Topic = rt.Topic
+			for _, rp := range rt.Partitions {
+				sp := kmsg.NewOffsetCommitResponseTopicPartition()
+				sp.Partition = rp.Partition
+				sp.ErrorCode = code
+				st.Partitions = append(st.Partitions, sp)
+			}
+			resp.Topics = append(resp.Topics, st)
+		}
+
+	case *kmsg.OffsetDeleteRequest:
+		kresp.(*kmsg.OffsetDeleteResponse).ErrorCode = code
+
+	case *kmsg.DeleteGroupsRequest:
+		resp := kresp.(*kmsg.DeleteGroupsResponse)
+		for _, g := range req.Groups {
+			sg := kmsg.NewDeleteGroupsResponseGroup()
+			sg.Group = g
+			sg.ErrorCode = code
+			resp.Groups = append(resp.Groups, sg)
+		}
+
+	case *kmsg.AlterConfigsRequest:
+		resp := kresp.(*kmsg.AlterConfigsResponse)
+		for _, rr := range req.Resources {
+			sr := kmsg.NewAlterConfigsResponseResource()
+			sr.ResourceType = rr.ResourceType
+			sr.ResourceName = rr.ResourceName
+			sr.ErrorCode = code
+			resp.Resources = append(resp.Resources, sr)
+		}
+
+	case *kmsg.IncrementalAlterConfigsRequest:
+		resp := kresp.(*kmsg.IncrementalAlterConfigsResponse)
+		for _, rr := range req.Resources {
+			sr := kmsg.NewIncrementalAlterConfigsResponseResource()
+			sr.ResourceType = rr.ResourceType
+			sr.ResourceName = rr.ResourceName
+			sr.ErrorCode = code
+			resp.Resources = append(resp.Resources, sr)
+		}
+
+	case *kmsg.CreateACLsRequest:
+		resp := kresp.(*kmsg.CreateACLsResponse)
+		for range req.Creations {
+			sr := kmsg.NewCreateACLsResponseResult()
+			sr.ErrorCode = code
+			resp.Results = append(resp.Results, sr)
+		}
+
+	case *kmsg.DeleteACLsRequest:
+		resp := kresp.(*kmsg.DeleteACLsResponse)
+		for range req.Filters {
+			sr := kmsg.NewDeleteACLsResponseResult()
+			sr.ErrorCode = code
+			resp.Results = append(resp.Results, sr)
+		}
+
+	default:
+		return nil, false
+	}
+	return kresp, true
+}
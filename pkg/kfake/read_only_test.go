@@ -0,0 +1,54 @@
+package kfake
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func TestSetReadOnly(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(1), SeedTopics(1, "t"))
+	cl := newTestClient(t, c)
+	adm := kadm.NewClient(cl)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if sp := produceBatch(ctx, t, cl.Broker(0), "t", 0, kgo.StringRecord("v")); sp.ErrorCode != 0 {
+		t.Fatalf("produce: %v", kerr.ErrorForCode(sp.ErrorCode))
+	}
+
+	c.SetReadOnly(kerr.PolicyViolation.Code)
+	if sp := produceBatch(ctx, t, cl.Broker(0), "t", 0, kgo.StringRecord("v")); sp.ErrorCode != kerr.PolicyViolation.Code {
+		t.Errorf("produce while read only: got %v, expected POLICY_VIOLATION", kerr.ErrorForCode(sp.ErrorCode))
+	}
+	if _, err := adm.CreateTopic(ctx, 1, 1, nil, "new"); !errors.Is(err, kerr.PolicyViolation) {
+		t.Errorf("create topic while read only: got %v, expected POLICY_VIOLATION", err)
+	}
+	var os kadm.Offsets
+	os.AddOffset("t", 0, 1, -1)
+	if err := adm.CommitAllOffsets(ctx, "g", os); !errors.Is(err, kerr.PolicyViolation) {
+		t.Errorf("commit while read only: got %v, expected POLICY_VIOLATION", err)
+	}
+
+	// Reads still work.
+	ends, err := adm.ListEndOffsets(ctx, "t")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o, _ := ends.Lookup("t", 0); o.Err != nil || o.Offset != 1 {
+		t.Errorf("list end offsets while read only: got %d (err %v), expected 1", o.Offset, o.Err)
+	}
+	if _, err := adm.DescribeTopicConfigs(ctx, "t"); err != nil {
+		t.Errorf("describe configs while read only: %v", err)
+	}
+
+	c.SetReadOnly(0)
+	if sp := produceBatch(ctx, t, cl.Broker(0), "t", 0, kgo.StringRecord("v")); sp.ErrorCode != 0 || sp.BaseOffset != 1 {
+		t.Errorf("produce after leaving read only: got %v at offset %d, expected success at offset 1", kerr.ErrorForCode(sp.ErrorCode), sp.BaseOffset)
+	}
+}
@@ -0,0 +1,162 @@
+package kfake
+
+import (
+	"time"
+)
+
+// Partition reassignments (KIP-455) are started and cancelled with
+// AlterPartitionAssignments and listed with ListPartitionReassignments, both
+// of which are handled by the controller. A reassignment gives a partition an
+// explicit replica assignment in place of its default replicas (see
+// replicas.go).
+//
+// While a reassignment is in progress, a partition's replicas are the target
+// replicas followed by the removing replicas, and the adding replicas are not
+// in the ISR, as in Kafka. A reassignment that adds no replicas completes
+// immediately. Others complete after the ReassignmentDelay, or when
+// CompleteReassignments is called. On completion, the partition's replicas
+// are the target replicas, and if the leader is not one of them, leadership
+// moves to the first target replica. Cancelling a reassignment (altering with
+// null replicas) reverts the partition to its original replicas.
+//
+// Moving the leader to a broker that is not an assigned replica, with
+// MoveTopicPartition or ShufflePartitionLeaders, reverts the partition to its
+// default replicas.
+
+type reassignment struct {
+	original []int32 // replicas before the reassignment began
+	target   []int32
+	deadline time.Time // cluster clock time the reassignment completes; zero if only completed manually
+	timer    *time.Timer
+}
+
+// ReassignmentDelay sets how long partition reassignments that add replicas
+// take to complete, as if the new replicas were catching up. By default,
+// reassignments complete immediately. A negative delay leaves reassignments
+// in progress until CompleteReassignments is called. The delay is measured
+// with the cluster clock, so AdvanceTime completes due reassignments.
+func ReassignmentDelay(d time.Duration) Opt {
+	return opt{func(cfg *cfg) { cfg.reassignDelay = d }}
+}
+
+// CompleteReassignments completes every in progress partition reassignment.
+func (c *Cluster) CompleteReassignments() {
+	c.admin(func() {
+		c.data.tps.each(func(t string, p int32, pd *partData) {
+			if pd.reassign != nil {
+				c.completeReassignment(t, p, pd)
+			}
+		})
+	})
+}
+
+// completeDueReassignments completes reassignments whose delay has passed by
+// now.
+func (c *Cluster) completeDueReassignments(now time.Time) {
+	c.data.tps.each(func(t string, p int32, pd *partData) {
+		if r := pd.reassign; r != nil && !r.deadline.IsZero() && !now.Before(r.deadline) {
+			c.completeReassignment(t, p, pd)
+		}
+	})
+}
+
+// reassign starts, replaces, or cancels (if target is nil) a partition's
+// reassignment. The target must already be validated.
+func (c *Cluster) reassign(t string, p int32, pd *partData, target []int32) {
+	c.metadataChanged()
+	if r := pd.reassign; r != nil {
+		if r.timer != nil {
+			r.timer.Stop()
+		}
+		pd.reassign = nil
+		if target == nil {
+			pd.assigned = r.original
+			c.moveLeaderInto(t, p, pd)
+			return
+		}
+		// As in Kafka, replacing a reassignment keeps the original
+		// replicas of the first.
+		pd.assigned = r.original
+	}
+
+	r := &reassignment{
+		original: c.replicas(t, pd),
+		target:   append([]int32(nil), target...),
+	}
+	pd.reassign = r
+	if len(missing(r.original, r.target)) == 0 {
+		c.completeReassignment(t, p, pd)
+		return
+	}
+	d := c.cfg.reassignDelay
+	if d == 0 {
+		c.completeReassignment(t, p, pd)
+		return
+	}
+	if d < 0 {
+		return
+	}
+	r.deadline = c.now().Add(d)
+	r.timer = time.AfterFunc(d, func() {
+		c.tryAdmin(func() {
+			if pd.reassign == r {
+				c.completeDueReassignments(c.now())
+			}
+		})
+	})
+}
+
+func (c *Cluster) completeReassignment(t string, p int32, pd *partData) {
+	r := pd.reassign
+	if r.timer != nil {
+		r.timer.Stop()
+	}
+	pd.reassign = nil
+	pd.assigned = r.target
+	c.metadataChanged()
+	c.moveLeaderInto(t, p, pd)
+}
+
+// moveLeaderInto moves the partition's leader to its first assigned replica
+// that is a live broker, if the leader is not an assigned replica.
+func (c *Cluster) moveLeaderInto(t string, p int32, pd *partData) {
+	if contains(pd.assigned, pd.leader.node) {
+		return
+	}
+	for _, node := range pd.assigned {
+		for _, b := range c.bs {
+			if b.node == node {
+				c.moveLeader(t, p, pd, b)
+				return
+			}
+		}
+	}
+}
+
+// replicas returns the partition's replicas, adding replicas, and removing
+// replicas while the reassignment is in progress.
+func (r *reassignment) replicas() (replicas, adding, removing []int32) {
+	removing = missing(r.target, r.original)
+	replicas = append(append([]int32(nil), r.target...), removing...)
+	return replicas, missing(r.original, r.target), removing
+}
+
+// missing returns the nodes in b that are not in a.
+func missing(a, b []int32) []int32 {
+	var m []int32
+	for _, node := range b {
+		if !contains(a, node) {
+			m = append(m, node)
+		}
+	}
+	return m
+}
+
+func contains(nodes []int32, node int32) bool {
+	for _, n := range nodes {
+		if n == node {
+			return true
+		}
+	}
+	return false
+}
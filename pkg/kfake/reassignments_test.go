@@ -0,0 +1,167 @@
+package kfake
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestPartitionReassignments(t *testing.T) {
+	now := time.Now()
+	c := newTestCluster(t, NumBrokers(3), Clock(func() time.Time { return now }), ReassignmentDelay(time.Minute))
+	cl := newTestClient(t, c)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if _, err := kadm.NewClient(cl).CreateTopic(ctx, 1, 1, nil, "t"); err != nil {
+		t.Fatal(err)
+	}
+	var controller int32
+	c.admin(func() { controller = c.controller.node })
+	ctrl := cl.Broker(int(controller))
+
+	// alter reassigns partition p of t, returning the error code.
+	alter := func(br kmsg.Requestor, p int32, replicas []int32) int16 {
+		t.Helper()
+		req := kmsg.NewPtrAlterPartitionAssignmentsRequest()
+		rt := kmsg.NewAlterPartitionAssignmentsRequestTopic()
+		rt.Topic = "t"
+		rp := kmsg.NewAlterPartitionAssignmentsRequestTopicPartition()
+		rp.Partition, rp.Replicas = p, replicas
+		rt.Partitions = append(rt.Partitions, rp)
+		req.Topics = append(req.Topics, rt)
+		resp, err := req.RequestWith(ctx, br)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.ErrorCode != 0 {
+			return resp.ErrorCode
+		}
+		return resp.Topics[0].Partitions[0].ErrorCode
+	}
+	list := func() []kmsg.ListPartitionReassignmentsResponseTopicPartition {
+		t.Helper()
+		resp, err := kmsg.NewPtrListPartitionReassignmentsRequest().RequestWith(ctx, ctrl)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(resp.Topics) == 0 {
+			return nil
+		}
+		return resp.Topics[0].Partitions
+	}
+	partition := func() kmsg.MetadataResponseTopicPartition {
+		t.Helper()
+		req := kmsg.NewPtrMetadataRequest()
+		rt := kmsg.NewMetadataRequestTopic()
+		rt.Topic = kmsg.StringPtr("t")
+		req.Topics = append(req.Topics, rt)
+		resp, err := req.RequestWith(ctx, cl)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.Topics[0].Partitions[0]
+	}
+
+	original := partition().Replicas
+	if len(original) != 1 {
+		t.Fatalf("got replicas %v, expected one", original)
+	}
+	var others []int32
+	for node := int32(0); node < 3; node++ {
+		if node != original[0] {
+			others = append(others, node)
+		}
+	}
+
+	for _, test := range []struct {
+		name     string
+		br       kmsg.Requestor
+		p        int32
+		replicas []int32
+		exp      *kerr.Error
+	}{
+		{"not the controller", cl.Broker(int(controller+1) % 3), 0, others, kerr.NotController},
+		{"unknown partition", ctrl, 1, others, kerr.UnknownTopicOrPartition},
+		{"empty", ctrl, 0, []int32{}, kerr.InvalidReplicaAssignment},
+		{"duplicate broker", ctrl, 0, []int32{0, 0}, kerr.InvalidReplicaAssignment},
+		{"unknown broker", ctrl, 0, []int32{9}, kerr.InvalidReplicaAssignment},
+		{"cancelling nothing", ctrl, 0, nil, kerr.NoReassignmentInProgress},
+	} {
+		if code := alter(test.br, test.p, test.replicas); code != test.exp.Code {
+			t.Errorf("%s: got %v, expected %v", test.name, kerr.ErrorForCode(code), test.exp)
+		}
+	}
+
+	// An in progress reassignment lists the target plus removing
+	// replicas, and the adding replicas are not in sync.
+	if code := alter(ctrl, 0, others); code != 0 {
+		t.Fatalf("reassign: %v", kerr.ErrorForCode(code))
+	}
+	ps := list()
+	if len(ps) != 1 {
+		t.Fatalf("got %d reassigning partitions, expected 1", len(ps))
+	}
+	if exp := append(append([]int32(nil), others...), original...); !reflect.DeepEqual(ps[0].Replicas, exp) {
+		t.Errorf("got reassigning replicas %v, expected %v", ps[0].Replicas, exp)
+	}
+	if !reflect.DeepEqual(ps[0].AddingReplicas, others) || !reflect.DeepEqual(ps[0].RemovingReplicas, original) {
+		t.Errorf("got adding %v and removing %v, expected %v and %v", ps[0].AddingReplicas, ps[0].RemovingReplicas, others, original)
+	}
+	if isr := partition().ISR; !reflect.DeepEqual(isr, original) {
+		t.Errorf("got ISR %v while reassigning, expected %v", isr, original)
+	}
+
+	// The reassignment completes once the delay passes on the cluster
+	// clock, moving the leader into the target replicas.
+	c.AdvanceTime(time.Minute)
+	if ps := list(); len(ps) != 0 {
+		t.Errorf("got %d reassigning partitions after the delay, expected 0", len(ps))
+	}
+	p := partition()
+	if !reflect.DeepEqual(p.Replicas, others) || p.Leader != others[0] {
+		t.Errorf("got replicas %v led by %d, expected %v led by %d", p.Replicas, p.Leader, others, others[0])
+	}
+
+	// Cancelling reverts to the replicas before the reassignment.
+	if code := alter(ctrl, 0, original); code != 0 {
+		t.Fatalf("reassign: %v", kerr.ErrorForCode(code))
+	}
+	if code := alter(ctrl, 0, nil); code != 0 {
+		t.Fatalf("cancel: %v", kerr.ErrorForCode(code))
+	}
+	if ps := list(); len(ps) != 0 {
+		t.Errorf("got %d reassigning partitions after cancelling, expected 0", len(ps))
+	}
+	if p := partition(); !reflect.DeepEqual(p.Replicas, others) {
+		t.Errorf("got replicas %v after cancelling, expected %v", p.Replicas, others)
+	}
+
+	if code := alter(ctrl, 0, original); code != 0 {
+		t.Fatalf("reassign: %v", kerr.ErrorForCode(code))
+	}
+	c.CompleteReassignments()
+	if p := partition(); !reflect.DeepEqual(p.Replicas, original) || p.Leader != original[0] {
+		t.Errorf("got replicas %v led by %d after completing, expected %v", p.Replicas, p.Leader, original)
+	}
+
+	// Removing replicas only completes immediately.
+	if code := alter(ctrl, 0, others); code != 0 {
+		t.Fatalf("reassign: %v", kerr.ErrorForCode(code))
+	}
+	c.AdvanceTime(time.Minute)
+	if code := alter(ctrl, 0, others[:1]); code != 0 {
+		t.Fatalf("reassign: %v", kerr.ErrorForCode(code))
+	}
+	if ps := list(); len(ps) != 0 {
+		t.Errorf("got %d reassigning partitions after only removing replicas, expected 0", len(ps))
+	}
+	if p := partition(); !reflect.DeepEqual(p.Replicas, others[:1]) {
+		t.Errorf("got replicas %v, expected %v", p.Replicas, others[:1])
+	}
+}
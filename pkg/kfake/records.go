@@ -0,0 +1,193 @@
+package kfake
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// ProduceTo and ReadRecords let tests use the cluster as a fixture store,
+// seeding and asserting on partition data without a client. Both bypass
+// produce and fetch validation, ACLs, quotas, and faults.
+
+// ProduceTo appends records to a partition as one batch, as a non-idempotent
+// producer would, and returns the offset of the first record. Each record's
+// Key, Value, Headers, and Timestamp are used; a zero Timestamp uses the
+// partition leader's clock. The batch is compressed per the topic's
+// compression.type, and appending wakes any fetches waiting on the partition.
+// On success, each record's Topic, Partition, Offset, and Timestamp are set,
+// as the client sets them after producing. This returns an error if the
+// partition does not exist.
+func (c *Cluster) ProduceTo(topic string, partition int32, records ...*kgo.Record) (int64, error) {
+	if len(records) == 0 {
+		return 0, errors.New("no records to produce")
+	}
+	var (
+		offset int64
+		err    error
+	)
+	c.admin(func() {
+		pd, ok := c.data.tps.getp(topic, partition)
+		if !ok {
+			err = fmt.Errorf("topic partition %s/%d not found", topic, partition)
+			return
+		}
+		now := pd.leader.now()
+		for _, r := range records {
+			if r.Timestamp.IsZero() {
+				r.Timestamp = now
+			}
+		}
+		b := newRecordBatchFrom(records)
+		if codec, ok := compressionCodecs[c.data.config(topic, "compression.type")]; ok && codec != 0 {
+			if err = recompress(&b, codec); err != nil {
+				return
+			}
+		}
+		offset = pd.highWatermark
+		pd.pushBatch(int(b.Length)+12, b)
+		c.eosRecord(topic, partition, offset, &b)
+		for i, r := range records {
+			r.Topic = topic
+			r.Partition = partition
+			r.Offset = offset + int64(i)
+		}
+	})
+	return offset, err
+}
+
+// ReadRecords returns up to n records from a partition starting at offset,
+// as a read uncommitted consumer would see them: records of open and aborted
+// transactions are included, while transaction markers are not. A
+// non-positive n returns every record through the high watermark. This
+// returns an error if the partition does not exist or if offset is before the
+// log start offset or after the high watermark.
+func (c *Cluster) ReadRecords(topic string, partition int32, offset int64, n int) ([]*kgo.Record, error) {
+	var (
+		rs  []*kgo.Record
+		err error
+	)
+	c.admin(func() {
+		pd, ok := c.data.tps.getp(topic, partition)
+		if !ok {
+			err = fmt.Errorf("topic partition %s/%d not found", topic, partition)
+			return
+		}
+		i, ok, atEnd := pd.searchOffset(offset)
+		if atEnd {
+			return
+		}
+		if !ok {
+			err = fmt.Errorf("offset %d is out of range [%d, %d]", offset, pd.logStartOffset, pd.highWatermark)
+			return
+		}
+		for _, b := range pd.batches[i:] {
+			if b.Attributes&attrControl != 0 {
+				continue
+			}
+			var batchRecs []*kgo.Record
+			if batchRecs, err = readBatch(topic, partition, &b.RecordBatch); err != nil {
+				return
+			}
+			for _, r := range batchRecs {
+				if r.Offset < offset {
+					continue
+				}
+				rs = append(rs, r)
+				if n > 0 && len(rs) == n {
+					return
+				}
+			}
+		}
+	})
+	return rs, err
+}
+
+// newRecordBatchFrom returns an uncompressed batch containing the given
+// records, which must all have a timestamp.
+func newRecordBatchFrom(rs []*kgo.Record) kmsg.RecordBatch {
+	var (
+		first   = rs[0].Timestamp.UnixMilli()
+		maxTs   = first
+		records []byte
+	)
+	for _, r := range rs {
+		if ts := r.Timestamp.UnixMilli(); ts > maxTs {
+			maxTs = ts
+		}
+	}
+	for i, r := range rs {
+		kr := kmsg.Record{
+			TimestampDelta64: r.Timestamp.UnixMilli() - first,
+			OffsetDelta:      int32(i),
+			Key:              r.Key,
+			Value:            r.Value,
+		}
+		for _, h := range r.Headers {
+			kr.Headers = append(kr.Headers, kmsg.Header{Key: h.Key, Value: h.Value})
+		}
+		kr.Length = int32(len(kr.AppendTo(nil)) - 1) // a zero length encodes as one byte
+		records = kr.AppendTo(records)
+	}
+	b := kmsg.RecordBatch{
+		Length:               int32(49 + len(records)),
+		PartitionLeaderEpoch: -1,
+		Magic:                2,
+		LastOffsetDelta:      int32(len(rs) - 1),
+		FirstTimestamp:       first,
+		MaxTimestamp:         maxTs,
+		ProducerID:           -1,
+		ProducerEpoch:        -1,
+		FirstSequence:        -1,
+		NumRecords:           int32(len(rs)),
+		Records:              records,
+	}
+	b.CRC = int32(crc32.Checksum(b.AppendTo(nil)[21:], crc32c))
+	return b
+}
+
+// readBatch decodes the records in a stored batch.
+func readBatch(topic string, partition int32, b *kmsg.RecordBatch) ([]*kgo.Record, error) {
+	raw, err := decompress(int8(b.Attributes&0x0007), b.Records)
+	if err != nil {
+		return nil, err
+	}
+	rs := make([]*kgo.Record, 0, b.NumRecords)
+	for i := int32(0); i < b.NumRecords; i++ {
+		length, n := binary.Varint(raw)
+		if n <= 0 || length < 0 || int64(len(raw)-n) < length {
+			return nil, errors.New("invalid record length")
+		}
+		var kr kmsg.Record
+		if err := kr.ReadFrom(raw[:n+int(length)]); err != nil {
+			return nil, err
+		}
+		raw = raw[n+int(length):]
+
+		ts := b.FirstTimestamp + kr.TimestampDelta64
+		if b.Attributes&0x0008 != 0 { // LogAppendTime
+			ts = b.MaxTimestamp
+		}
+		r := &kgo.Record{
+			Key:           kr.Key,
+			Value:         kr.Value,
+			Timestamp:     time.UnixMilli(ts),
+			Topic:         topic,
+			Partition:     partition,
+			LeaderEpoch:   b.PartitionLeaderEpoch,
+			ProducerEpoch: b.ProducerEpoch,
+			ProducerID:    b.ProducerID,
+			Offset:        b.FirstOffset + int64(kr.OffsetDelta),
+		}
+		for _, h := range kr.Headers {
+			r.Headers = append(r.Headers, kgo.RecordHeader{Key: h.Key, Value: h.Value})
+		}
+		rs = append(rs, r)
+	}
+	return rs, nil
+}
@@ -0,0 +1,77 @@
+package kfake
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func TestProduceToReadRecords(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(1), SeedTopics(1, "t"))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if _, err := c.ProduceTo("t", 0); err == nil {
+		t.Error("producing no records: got no error")
+	}
+	if _, err := c.ProduceTo("t", 1, kgo.StringRecord("v")); err == nil {
+		t.Error("producing to a missing partition: got no error")
+	}
+
+	ts := time.Now().Add(-time.Hour).Truncate(time.Millisecond)
+	first := []*kgo.Record{
+		{Key: []byte("k"), Value: []byte("a"), Timestamp: ts, Headers: []kgo.RecordHeader{{Key: "h", Value: []byte("hv")}}},
+		kgo.StringRecord("b"),
+	}
+	if offset, err := c.ProduceTo("t", 0, first...); err != nil || offset != 0 {
+		t.Fatalf("got offset %d and err %v, expected 0", offset, err)
+	}
+	if offset, err := c.ProduceTo("t", 0, kgo.StringRecord("c")); err != nil || offset != 2 {
+		t.Fatalf("got offset %d and err %v, expected 2", offset, err)
+	}
+	if r := first[1]; r.Topic != "t" || r.Partition != 0 || r.Offset != 1 || r.Timestamp.IsZero() {
+		t.Errorf("produced record was not filled in: %+v", r)
+	}
+
+	rs, err := c.ReadRecords("t", 0, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var values string
+	for _, r := range rs {
+		values += string(r.Value)
+	}
+	if values != "abc" {
+		t.Errorf("got values %q, expected abc", values)
+	}
+	if r := rs[0]; string(r.Key) != "k" || !r.Timestamp.Equal(ts) || len(r.Headers) != 1 || string(r.Headers[0].Value) != "hv" {
+		t.Errorf("got first record %+v, expected its key, timestamp, and header", r)
+	}
+
+	// Reads can start mid batch and are limited to n records.
+	if rs, err := c.ReadRecords("t", 0, 1, 1); err != nil || len(rs) != 1 || string(rs[0].Value) != "b" || rs[0].Offset != 1 {
+		t.Errorf("read 1 from offset 1: got %d records and err %v, expected b", len(rs), err)
+	}
+	if rs, err := c.ReadRecords("t", 0, 3, 0); err != nil || len(rs) != 0 {
+		t.Errorf("read at the high watermark: got %d records and err %v, expected none", len(rs), err)
+	}
+	if _, err := c.ReadRecords("t", 0, 4, 0); err == nil {
+		t.Error("read past the high watermark: got no error")
+	}
+	if _, err := c.ReadRecords("missing", 0, 0, 0); err == nil {
+		t.Error("read a missing partition: got no error")
+	}
+
+	// Clients see seeded records.
+	cl := newTestClient(t, c, kgo.ConsumeTopics("t"), kgo.ConsumeResetOffset(kgo.NewOffset().AtStart()))
+	var consumed int
+	for consumed < 3 {
+		fs := cl.PollFetches(ctx)
+		if err := fs.Err(); err != nil {
+			t.Fatal(err)
+		}
+		consumed += fs.NumRecords()
+	}
+}
@@ -0,0 +1,216 @@
+package kfake
+
+import (
+	"fmt"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+)
+
+// Replicas are not actually replicated; a partition's data lives only in
+// partData. A partition's default replicas are its leader followed by the next
+// brokers, up to the topic's replication factor or the number of brokers,
+// whichever is smaller. When brokers are removed below a topic's replication
+// factor, the partition has fewer replicas. A partition that was reassigned
+// uses its assigned replicas instead (see reassignments.go).
+//
+// When the leader moves to another of the partition's replicas, the replicas
+// are kept as they were, so the first replica remains the preferred leader
+// that ElectLeaders and ElectPreferredLeaders move leadership back to. When
+// the leader moves to a broker that is not a replica, the partition goes back
+// to its default replicas, which follow the new leader.
+//
+// The ISR is every replica, less followers that were taken out of sync with
+// SetReplicaInSync and replicas being added by a reassignment. The leader is
+// always in sync. A produce request with
+// acks=all to a partition whose ISR is smaller than min.insync.replicas fails
+// with NOT_ENOUGH_REPLICAS.
+
+// replicas returns the partition's replica node IDs, leader first.
+func (c *Cluster) replicas(t string, pd *partData) []int32 {
+	if assigned := c.assignedReplicas(pd); assigned != nil {
+		return assigned
+	}
+	nreplicas := c.data.treplicas[t]
+	if nreplicas > len(c.bs) {
+		nreplicas = len(c.bs)
+	}
+	replicas := make([]int32, 0, nreplicas)
+	for i := 0; i < nreplicas; i++ {
+		idx := (pd.leader.bsIdx + i) % len(c.bs)
+		replicas = append(replicas, c.bs[idx].node)
+	}
+	return replicas
+}
+
+// assignedReplicas returns the live brokers of the partition's assigned
+// replicas, or nil if the partition uses its default replicas.
+func (c *Cluster) assignedReplicas(pd *partData) []int32 {
+	assigned := pd.assigned
+	if pd.reassign != nil {
+		assigned, _, _ = pd.reassign.replicas()
+	}
+	if assigned == nil || !contains(assigned, pd.leader.node) {
+		return nil
+	}
+	live := make([]int32, 0, len(assigned))
+	for _, node := range assigned {
+		for _, b := range c.bs {
+			if b.node == node {
+				live = append(live, node)
+				break
+			}
+		}
+	}
+	return live
+}
+
+// isr returns the partition's in sync replica node IDs. For default replicas,
+// the leader is first.
+func (c *Cluster) isr(t string, pd *partData) []int32 {
+	replicas := c.replicas(t, pd)
+	var adding []int32
+	if pd.reassign != nil {
+		_, adding, _ = pd.reassign.replicas()
+	}
+	if len(pd.outOfSync) == 0 && len(adding) == 0 {
+		return replicas
+	}
+	isr := replicas[:0:0]
+	for _, node := range replicas {
+		_, out := pd.outOfSync[node]
+		if out = out || contains(adding, node); !out || node == pd.leader.node {
+			isr = append(isr, node)
+		}
+	}
+	return isr
+}
+
+// SetReplicaInSync takes a follower of a partition out of the partition's ISR,
+// as if the follower fell behind, or puts it back. This can be used to test
+// how clients handle NOT_ENOUGH_REPLICAS when producing with acks=all to
+// topics with min.insync.replicas greater than 1. The leader is always in
+// sync: if an out of sync follower becomes the leader, it is in sync until
+// leadership moves again. This returns an error if the partition does not
+// exist.
+func (c *Cluster) SetReplicaInSync(topic string, partition, nodeID int32, inSync bool) error {
+	var err error
+	c.admin(func() {
+		pd, ok := c.data.tps.getp(topic, partition)
+		if !ok {
+			err = fmt.Errorf("topic partition %s/%d not found", topic, partition)
+			return
+		}
+		c.metadataChanged()
+		if inSync {
+			delete(pd.outOfSync, nodeID)
+			return
+		}
+		if pd.outOfSync == nil {
+			pd.outOfSync = make(map[int32]struct{})
+		}
+		pd.outOfSync[nodeID] = struct{}{}
+	})
+	return err
+}
+
+// moveLeader moves a partition's leadership to b, bumping the leader epoch.
+func (c *Cluster) moveLeader(t string, p int32, pd *partData, b *broker) {
+	// The assignment is checked directly, rather than through replicas,
+	// because a completed or cancelled reassignment moves the leader into
+	// an assignment that does not yet contain it.
+	if pd.reassign == nil && !contains(pd.assigned, b.node) {
+		if replicas := c.replicas(t, pd); !contains(replicas, b.node) {
+			pd.assigned = nil
+		} else if pd.assigned == nil && b != pd.leader {
+			pd.assigned = replicas
+		}
+	}
+	c.leaderMoving(t, p)
+	pd.leader = b
+	pd.epoch++
+	c.metadataChanged()
+	c.emit(Event{Type: EventLeaderChanged, Topic: t, Partition: p, Leader: b.node, LeaderEpoch: pd.epoch})
+}
+
+// ElectPreferredLeaders moves the leadership of every partition of the given
+// topics, or of all topics if none are given, back to the partition's
+// preferred (first) replica, as a preferred leader election would. Partitions
+// whose preferred replica is already the leader, or is not in sync, are left
+// alone. Clients producing to or fetching from the old leader receive
+// NOT_LEADER_OR_FOLLOWER.
+func (c *Cluster) ElectPreferredLeaders(topics ...string) {
+	c.admin(func() {
+		if len(topics) == 0 {
+			c.data.tps.each(func(t string, p int32, pd *partData) {
+				c.electPreferred(t, p, pd)
+			})
+			return
+		}
+		for _, t := range topics {
+			ps, _ := c.data.tps.gett(t)
+			for p, pd := range ps {
+				c.electPreferred(t, p, pd)
+			}
+		}
+	})
+}
+
+// electPreferred moves leadership to the partition's first replica, returning
+// ELECTION_NOT_NEEDED if it already leads, PREFERRED_LEADER_NOT_AVAILABLE if
+// it is not in sync, or 0.
+func (c *Cluster) electPreferred(t string, p int32, pd *partData) int16 {
+	replicas := c.replicas(t, pd)
+	if len(replicas) == 0 {
+		return kerr.PreferredLeaderNotAvailable.Code
+	}
+	preferred := replicas[0]
+	if preferred == pd.leader.node {
+		return kerr.ElectionNotNeeded.Code
+	}
+	b := c.liveBroker(preferred)
+	if b == nil || !contains(c.isr(t, pd), preferred) {
+		return kerr.PreferredLeaderNotAvailable.Code
+	}
+	c.moveLeader(t, p, pd, b)
+	return 0
+}
+
+// electUnclean elects the first live replica if the partition has no live
+// leader, returning ELECTION_NOT_NEEDED if it has one,
+// ELIGIBLE_LEADERS_NOT_AVAILABLE if no replica is live, or 0. Replicas are
+// not actually replicated, so no data is lost.
+func (c *Cluster) electUnclean(t string, p int32, pd *partData) int16 {
+	if c.liveBroker(pd.leader.node) != nil {
+		return kerr.ElectionNotNeeded.Code
+	}
+	for _, node := range c.replicas(t, pd) {
+		if b := c.liveBroker(node); b != nil {
+			c.moveLeader(t, p, pd, b)
+			return 0
+		}
+	}
+	return kerr.EligibleLeadersNotAvailable.Code
+}
+
+// liveBroker returns the cluster's broker with the given node ID, or nil if
+// there is no such broker or it is paused.
+func (c *Cluster) liveBroker(node int32) *broker {
+	for _, b := range c.bs {
+		if b.node == node {
+			if paused, _ := b.pauseState(); paused != nil {
+				return nil
+			}
+			return b
+		}
+	}
+	return nil
+}
+
+// checkMinISR returns NOT_ENOUGH_REPLICAS if the partition's ISR is smaller
+// than the topic's min.insync.replicas, or 0.
+func (c *Cluster) checkMinISR(t string, pd *partData) int16 {
+	if int64(len(c.isr(t, pd))) < c.data.configInt(t, "min.insync.replicas") {
+		return kerr.NotEnoughReplicas.Code
+	}
+	return 0
+}
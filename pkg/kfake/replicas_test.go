@@ -0,0 +1,241 @@
+package kfake
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestMinInSyncReplicas(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(3))
+	cl := newTestClient(t, c)
+	// kgo sets the acks of produce requests from the client config.
+	leaderAcks := newTestClient(t, c, kgo.RequiredAcks(kgo.LeaderAck()), kgo.DisableIdempotentWrite())
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	minISR := "2"
+	if _, err := kadm.NewClient(cl).CreateTopic(ctx, 1, 3, map[string]*string{"min.insync.replicas": &minISR}, "t"); err != nil {
+		t.Fatal(err)
+	}
+
+	// partition returns the partition's metadata from the leader.
+	partition := func() kmsg.MetadataResponseTopicPartition {
+		t.Helper()
+		req := kmsg.NewPtrMetadataRequest()
+		rt := kmsg.NewMetadataRequestTopic()
+		rt.Topic = kmsg.StringPtr("t")
+		req.Topics = append(req.Topics, rt)
+		resp, err := req.RequestWith(ctx, cl)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.Topics[0].Partitions[0]
+	}
+	produce := func(cl *kgo.Client) error {
+		t.Helper()
+		b := newRecordBatchFrom([]*kgo.Record{{Value: []byte("v"), Timestamp: time.Now()}})
+		if err := recompress(&b, 0); err != nil {
+			t.Fatal(err)
+		}
+		req := kmsg.NewPtrProduceRequest()
+		req.TimeoutMillis = 5000
+		rt := kmsg.NewProduceRequestTopic()
+		rt.Topic = "t"
+		rp := kmsg.NewProduceRequestTopicPartition()
+		rp.Records = b.AppendTo(nil)
+		rt.Partitions = append(rt.Partitions, rp)
+		req.Topics = append(req.Topics, rt)
+		resp, err := req.RequestWith(ctx, cl.Broker(int(partition().Leader)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return kerr.ErrorForCode(resp.Topics[0].Partitions[0].ErrorCode)
+	}
+
+	p := partition()
+	if len(p.Replicas) != 3 || len(p.ISR) != 3 || p.Replicas[0] != p.Leader {
+		t.Fatalf("got replicas %v and ISR %v with leader %d, expected 3 in sync replicas led by the leader", p.Replicas, p.ISR, p.Leader)
+	}
+	leader, followers := p.Leader, p.Replicas[1:]
+
+	if err := c.SetReplicaInSync("missing", 0, followers[0], false); err == nil {
+		t.Error("SetReplicaInSync on a missing partition: got no error")
+	}
+
+	// The leader is always in sync.
+	if err := c.SetReplicaInSync("t", 0, leader, false); err != nil {
+		t.Fatal(err)
+	}
+	if isr := partition().ISR; len(isr) != 3 {
+		t.Errorf("after taking the leader out of sync: got ISR %v, expected all replicas", isr)
+	}
+
+	if err := c.SetReplicaInSync("t", 0, followers[0], false); err != nil {
+		t.Fatal(err)
+	}
+	if isr := partition().ISR; len(isr) != 2 || contains(isr, followers[0]) {
+		t.Errorf("got ISR %v, expected %d out of sync", isr, followers[0])
+	}
+	if err := produce(cl); err != nil {
+		t.Errorf("produce at min.insync.replicas: %v", err)
+	}
+
+	if err := c.SetReplicaInSync("t", 0, followers[1], false); err != nil {
+		t.Fatal(err)
+	}
+	if err := produce(cl); !errors.Is(err, kerr.NotEnoughReplicas) {
+		t.Errorf("produce acks=all below min.insync.replicas: got %v, expected NOT_ENOUGH_REPLICAS", err)
+	}
+	if err := produce(leaderAcks); err != nil {
+		t.Errorf("produce acks=1 below min.insync.replicas: %v", err)
+	}
+
+	if err := c.SetReplicaInSync("t", 0, followers[1], true); err != nil {
+		t.Fatal(err)
+	}
+	if err := produce(cl); err != nil {
+		t.Errorf("produce after a follower caught up: %v", err)
+	}
+
+	// Removing a follower shrinks the replicas below the replication
+	// factor.
+	if err := c.RemoveNode(followers[1]); err != nil {
+		t.Fatal(err)
+	}
+	if p := partition(); len(p.Replicas) != 2 || contains(p.Replicas, followers[1]) {
+		t.Errorf("after removing %d: got replicas %v", followers[1], p.Replicas)
+	}
+	if err := produce(cl); !errors.Is(err, kerr.NotEnoughReplicas) {
+		t.Errorf("produce with too few brokers: got %v, expected NOT_ENOUGH_REPLICAS", err)
+	}
+}
+
+func TestElectLeaders(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(3))
+	cl := newTestClient(t, c)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if _, err := kadm.NewClient(cl).CreateTopic(ctx, 1, 3, nil, "t"); err != nil {
+		t.Fatal(err)
+	}
+	partition := func() kmsg.MetadataResponseTopicPartition {
+		t.Helper()
+		req := kmsg.NewPtrMetadataRequest()
+		rt := kmsg.NewMetadataRequestTopic()
+		rt.Topic = kmsg.StringPtr("t")
+		req.Topics = append(req.Topics, rt)
+		resp, err := req.RequestWith(ctx, cl)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.Topics[0].Partitions[0]
+	}
+	// elect runs an election for the given partitions of t, or for every
+	// partition if none are given, returning each partition's error code.
+	elect := func(typ int8, partitions ...int32) map[int32]int16 {
+		t.Helper()
+		req := kmsg.NewPtrElectLeadersRequest()
+		req.ElectionType = typ
+		if len(partitions) > 0 {
+			rt := kmsg.NewElectLeadersRequestTopic()
+			rt.Topic, rt.Partitions = "t", partitions
+			req.Topics = append(req.Topics, rt)
+		}
+		resp, err := req.RequestWith(ctx, cl)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.ErrorCode != 0 {
+			t.Fatalf("elect: %v", kerr.ErrorForCode(resp.ErrorCode))
+		}
+		codes := make(map[int32]int16)
+		for _, rt := range resp.Topics {
+			for _, rp := range rt.Partitions {
+				codes[rp.Partition] = rp.ErrorCode
+			}
+		}
+		return codes
+	}
+
+	p := partition()
+	replicas, preferred := p.Replicas, p.Replicas[0]
+	if p.Leader != preferred {
+		t.Fatalf("leader %d is not the first replica of %v", p.Leader, replicas)
+	}
+
+	// Moving to another replica keeps the replicas, so the preferred
+	// leader is unchanged.
+	if err := c.MoveTopicPartition("t", 0, replicas[1]); err != nil {
+		t.Fatal(err)
+	}
+	if p := partition(); p.Leader != replicas[1] || !reflect.DeepEqual(p.Replicas, replicas) {
+		t.Fatalf("got replicas %v led by %d, expected %v led by %d", p.Replicas, p.Leader, replicas, replicas[1])
+	}
+	if codes := elect(0, 0, 5); codes[0] != 0 || codes[5] != kerr.UnknownTopicOrPartition.Code {
+		t.Errorf("preferred election: got %v, expected success and UNKNOWN_TOPIC_OR_PARTITION", codes)
+	}
+	if p := partition(); p.Leader != preferred {
+		t.Errorf("got leader %d after a preferred election, expected %d", p.Leader, preferred)
+	}
+	if codes := elect(0, 0); codes[0] != kerr.ElectionNotNeeded.Code {
+		t.Errorf("repeating the election: got %v, expected ELECTION_NOT_NEEDED", kerr.ErrorForCode(codes[0]))
+	}
+	if codes := elect(0); len(codes) != 0 {
+		t.Errorf("electing every partition: got %v, expected partitions not needing an election to be omitted", codes)
+	}
+
+	// An out of sync preferred replica is not elected.
+	if err := c.MoveTopicPartition("t", 0, replicas[1]); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.SetReplicaInSync("t", 0, preferred, false); err != nil {
+		t.Fatal(err)
+	}
+	if codes := elect(0, 0); codes[0] != kerr.PreferredLeaderNotAvailable.Code {
+		t.Errorf("electing an out of sync replica: got %v, expected PREFERRED_LEADER_NOT_AVAILABLE", kerr.ErrorForCode(codes[0]))
+	}
+	c.ElectPreferredLeaders("t")
+	if p := partition(); p.Leader != replicas[1] {
+		t.Errorf("ElectPreferredLeaders elected an out of sync replica: leader %d", p.Leader)
+	}
+	if err := c.SetReplicaInSync("t", 0, preferred, true); err != nil {
+		t.Fatal(err)
+	}
+	c.ElectPreferredLeaders()
+	if p := partition(); p.Leader != preferred {
+		t.Errorf("got leader %d after ElectPreferredLeaders, expected %d", p.Leader, preferred)
+	}
+
+	// Unclean elections only elect for partitions without a live leader.
+	if codes := elect(1, 0); codes[0] != kerr.ElectionNotNeeded.Code {
+		t.Errorf("unclean election with a live leader: got %v, expected ELECTION_NOT_NEEDED", kerr.ErrorForCode(codes[0]))
+	}
+	var controller int32
+	c.admin(func() { controller = c.controller.node })
+	down := replicas[1]
+	if down == controller {
+		down = replicas[2]
+	}
+	if err := c.MoveTopicPartition("t", 0, down); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.PauseNode(down); err != nil {
+		t.Fatal(err)
+	}
+	defer c.ResumeNode(down)
+	if codes := elect(1, 0); codes[0] != 0 {
+		t.Errorf("unclean election: %v", kerr.ErrorForCode(codes[0]))
+	}
+	if p := partition(); p.Leader == down || !contains(replicas, p.Leader) {
+		t.Errorf("got leader %d after an unclean election away from %d, expected another of %v", p.Leader, down, replicas)
+	}
+}
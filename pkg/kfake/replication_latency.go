@@ -0,0 +1,96 @@
+package kfake
+
+import (
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// replicationDelay returns how long to delay a successful acks=all produce
+// response to simulate replication: the slowest of the sampled latencies of
+// every partition that was successfully produced to.
+//
+// As in Kafka, a produce request waits at most its TimeoutMillis for
+// replication: partitions that take longer fail with REQUEST_TIMED_OUT, even
+// though their records were already written to the leader's log and remain
+// visible to consumers.
+func (c *Cluster) replicationDelay(kreq kmsg.Request, kresp kmsg.Response) time.Duration {
+	fn := c.cfg.replicationLatency
+	if fn == nil || kresp == nil {
+		return 0
+	}
+	req := kreq.(*kmsg.ProduceRequest)
+	if req.Acks != -1 {
+		return 0
+	}
+	var (
+		timeout = time.Duration(req.TimeoutMillis) * time.Millisecond
+		max     time.Duration
+	)
+	resp := kresp.(*kmsg.ProduceResponse)
+	for i := range resp.Topics {
+		st := &resp.Topics[i]
+		for j := range st.Partitions {
+			sp := &st.Partitions[j]
+			if sp.ErrorCode != 0 {
+				continue
+			}
+			d := fn(st.Topic, sp.Partition)
+			if d > timeout {
+				d = timeout
+				sp.ErrorCode = kerr.RequestTimedOut.Code
+			}
+			if d > max {
+				max = d
+			}
+		}
+	}
+	return max
+}
+
+// z99 is the standard normal quantile of the 99th percentile.
+const z99 = 2.3263478740408408
+
+// LognormalLatency returns a ReplicationLatency function that samples every
+// partition's latency from a lognormal distribution with the given median
+// and 99th percentile, the long tailed shape of real replication latencies.
+// A p99 at or below the median always returns the median.
+func LognormalLatency(p50, p99 time.Duration) func(topic string, partition int32) time.Duration {
+	if p50 <= 0 || p99 <= p50 {
+		return func(string, int32) time.Duration { return max(p50, 0) }
+	}
+	var (
+		mu    = math.Log(float64(p50))
+		sigma = math.Log(float64(p99)/float64(p50)) / z99
+	)
+	return func(string, int32) time.Duration {
+		return time.Duration(math.Exp(mu + sigma*rand.NormFloat64()))
+	}
+}
+
+// LatencySpikes wraps a ReplicationLatency function so that, with
+// probability p, a sampled latency is increased by spike, simulating the
+// occasional stall of a slow follower, a GC pause, or a disk flush.
+func LatencySpikes(fn func(topic string, partition int32) time.Duration, p float64, spike time.Duration) func(topic string, partition int32) time.Duration {
+	return func(topic string, partition int32) time.Duration {
+		d := fn(topic, partition)
+		if rand.Float64() < p {
+			d += spike
+		}
+		return d
+	}
+}
+
+// replyAfter sends the response to the client after the delay. The client
+// connection's writer ensures responses are still written in order.
+func (c *Cluster) replyAfter(creq *clientReq, kresp kmsg.Response, delay time.Duration) {
+	time.AfterFunc(delay, func() {
+		select {
+		case creq.cc.respCh <- clientResp{kresp: kresp, corr: creq.corr, seq: creq.seq, fetchBufs: creq.fetchBufs}:
+		case <-c.die:
+		}
+	})
+}
@@ -0,0 +1,101 @@
+package kfake
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestRequestTimeouts(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(1), SeedTopics(1, "t"), ReplicationLatency(func(string, int32) time.Duration {
+		return time.Hour
+	}))
+	br := newTestClient(t, c).Broker(0)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Replication that outlasts the produce timeout fails the partition,
+	// but the records are written.
+	b := newRecordBatchFrom([]*kgo.Record{{Value: []byte("v"), Timestamp: time.Now()}})
+	produce := kmsg.NewPtrProduceRequest()
+	produce.Acks = -1
+	produce.TimeoutMillis = 100
+	rt := kmsg.NewProduceRequestTopic()
+	rt.Topic = "t"
+	rp := kmsg.NewProduceRequestTopicPartition()
+	rp.Records = b.AppendTo(nil)
+	rt.Partitions = append(rt.Partitions, rp)
+	produce.Topics = append(produce.Topics, rt)
+	start := time.Now()
+	presp, err := produce.RequestWith(ctx, br)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code := presp.Topics[0].Partitions[0].ErrorCode; code != kerr.RequestTimedOut.Code {
+		t.Errorf("produce: got %v, expected REQUEST_TIMED_OUT", kerr.ErrorForCode(code))
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond || elapsed > 5*time.Second {
+		t.Errorf("produce took %v, expected about the 100ms timeout", elapsed)
+	}
+	if rs, err := c.ReadRecords("t", 0, 0, 0); err != nil || len(rs) != 1 {
+		t.Errorf("got %d records (err %v) after the timed out produce, expected 1", len(rs), err)
+	}
+
+	// Admin requests with no timeout do not wait, but are applied.
+	create := kmsg.NewPtrCreateTopicsRequest()
+	ct := kmsg.NewCreateTopicsRequestTopic()
+	ct.Topic, ct.NumPartitions, ct.ReplicationFactor = "new", 1, 1
+	create.Topics = append(create.Topics, ct)
+	create.TimeoutMillis = 0
+	cresp, err := create.RequestWith(ctx, br)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code := cresp.Topics[0].ErrorCode; code != kerr.RequestTimedOut.Code {
+		t.Errorf("create topics: got %v, expected REQUEST_TIMED_OUT", kerr.ErrorForCode(code))
+	}
+
+	grow := kmsg.NewPtrCreatePartitionsRequest()
+	gt := kmsg.NewCreatePartitionsRequestTopic()
+	gt.Topic, gt.Count = "new", 2
+	grow.Topics = append(grow.Topics, gt)
+	grow.TimeoutMillis = 0
+	gresp, err := grow.RequestWith(ctx, br)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code := gresp.Topics[0].ErrorCode; code != kerr.RequestTimedOut.Code {
+		t.Errorf("create partitions: got %v, expected REQUEST_TIMED_OUT", kerr.ErrorForCode(code))
+	}
+	var n int
+	c.admin(func() {
+		ps, _ := c.data.tps.gett("new")
+		n = len(ps)
+	})
+	if n != 2 {
+		t.Errorf("got %d partitions after timed out requests, expected the topic to be created and grown to 2", n)
+	}
+
+	del := kmsg.NewPtrDeleteTopicsRequest()
+	dt := kmsg.NewDeleteTopicsRequestTopic()
+	dt.Topic = kmsg.StringPtr("new")
+	del.Topics = append(del.Topics, dt)
+	del.TopicNames = []string{"new"}
+	del.TimeoutMillis = 0
+	dresp, err := del.RequestWith(ctx, br)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code := dresp.Topics[0].ErrorCode; code != kerr.RequestTimedOut.Code {
+		t.Errorf("delete topics: got %v, expected REQUEST_TIMED_OUT", kerr.ErrorForCode(code))
+	}
+	var exists bool
+	c.admin(func() { _, exists = c.data.tps.gett("new") })
+	if exists {
+		t.Error("topic still exists after a timed out delete")
+	}
+}
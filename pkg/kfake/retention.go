@@ -0,0 +1,114 @@
+package kfake
+
+import (
+	"strings"
+	"time"
+)
+
+// Retention, like compaction, is only applied on demand with CleanLogs or
+// periodically with AutoCleanLogs. For topics whose cleanup.policy includes
+// "delete", the oldest batches are deleted while either
+//
+//   - the batch's max timestamp is older than now - retention.ms, or
+//   - the partition is larger than retention.bytes
+//
+// Kafka deletes whole segments; we delete whole batches, so retention is more
+// precise than in Kafka. Deleting batches advances the partition's log start
+// offset to the first remaining offset, or to the high watermark if every
+// batch is deleted. Fetching below the new log start offset fails with
+// OFFSET_OUT_OF_RANGE, and consumers reset according to their reset policy.
+// "Now" is the partition leader's clock, which can be moved with
+// SetBrokerClockSkew or AdvanceTime.
+
+// CleanLogs applies retention to all partitions of the given topics whose
+// cleanup.policy includes "delete", and compacts all partitions whose
+// cleanup.policy includes "compact". If no topics are given, all topics are
+// cleaned.
+func (c *Cluster) CleanLogs(topics ...string) error {
+	var err error
+	c.admin(func() { err = c.cleanLogs(topics) })
+	return err
+}
+
+// AutoCleanLogs runs CleanLogs every interval for the life of the cluster, as
+// Kafka's log.retention.check.interval.ms. By default, logs are never
+// cleaned.
+func AutoCleanLogs(interval time.Duration) Opt {
+	return opt{func(cfg *cfg) { cfg.cleanInterval = interval }}
+}
+
+func (c *Cluster) cleanLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.die:
+			return
+		case <-ticker.C:
+			var err error
+			if !c.tryAdmin(func() { err = c.cleanLogs(nil) }) {
+				return
+			}
+			if err != nil {
+				c.cfg.logger.Logf(LogLevelWarn, "unable to clean logs: %v", err)
+			}
+		}
+	}
+}
+
+func (c *Cluster) cleanLogs(topics []string) error {
+	if len(topics) == 0 {
+		for t := range c.data.tps {
+			topics = append(topics, t)
+		}
+	}
+	for _, t := range topics {
+		ps, ok := c.data.tps.gett(t)
+		if !ok {
+			continue
+		}
+		policy := c.data.config(t, "cleanup.policy")
+		if strings.Contains(policy, "delete") {
+			retentionMs := c.data.configInt(t, "retention.ms")
+			retentionBytes := c.data.configInt(t, "retention.bytes")
+			for _, pd := range ps {
+				pd.retain(pd.leader.now().UnixMilli(), retentionMs, retentionBytes)
+			}
+		}
+		if strings.Contains(policy, "compact") {
+			if err := c.compactTopic(t, ps); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// retain deletes the oldest batches that are past retention and advances the
+// log start offset. A negative retentionMs or retentionBytes is unlimited.
+func (pd *partData) retain(now, retentionMs, retentionBytes int64) {
+	var (
+		i      int
+		nbytes = pd.nbytes
+	)
+	for ; i < len(pd.batches); i++ {
+		b := &pd.batches[i]
+		expired := retentionMs >= 0 && b.MaxTimestamp < now-retentionMs
+		oversize := retentionBytes >= 0 && nbytes > retentionBytes
+		if !expired && !oversize {
+			break
+		}
+		nbytes -= int64(b.nbytes)
+	}
+	if i == 0 {
+		return
+	}
+	start := pd.highWatermark
+	if i < len(pd.batches) {
+		start = pd.batches[i].FirstOffset
+	}
+	if start > pd.logStartOffset {
+		pd.logStartOffset = start
+		pd.trimLeft()
+	}
+}
@@ -0,0 +1,136 @@
+package kfake
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestCleanLogsRetention(t *testing.T) {
+	start := time.Now()
+	c := newTestCluster(t, NumBrokers(1), SeedTopics(1, "t", "c"), Clock(func() time.Time { return start }))
+	cl := newTestClient(t, c)
+	adm := kadm.NewClient(cl)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	alter := func(topic string, configs ...kadm.AlterConfig) {
+		t.Helper()
+		rs, err := adm.AlterTopicConfigs(ctx, configs, topic)
+		if err == nil {
+			err = rs[0].Err
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	produce := func(topic string, rs ...*kgo.Record) {
+		t.Helper()
+		if _, err := c.ProduceTo(topic, 0, rs...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	logStart := func(topic string) int64 {
+		t.Helper()
+		offsets, err := adm.ListStartOffsets(ctx, topic)
+		if err == nil {
+			err = offsets.Error()
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		o, _ := offsets.Lookup(topic, 0)
+		return o.Offset
+	}
+	str := func(s string) *string { return &s }
+
+	alter("t", kadm.AlterConfig{Name: "retention.ms", Value: str("1000")})
+	produce("t", kgo.StringRecord("a"), kgo.StringRecord("b"))
+	c.AdvanceTime(2 * time.Second)
+	produce("t", kgo.StringRecord("c"))
+
+	if err := c.CleanLogs("t"); err != nil {
+		t.Fatal(err)
+	}
+	if got := logStart("t"); got != 2 {
+		t.Errorf("after retention.ms: got log start offset %d, expected 2", got)
+	}
+
+	// Fetching below the new log start offset is out of range.
+	var id [16]byte
+	c.admin(func() { id = c.data.t2id["t"] })
+	req := kmsg.NewPtrFetchRequest()
+	req.MaxBytes = 1 << 20
+	rt := kmsg.NewFetchRequestTopic()
+	rt.TopicID = id
+	rp := kmsg.NewFetchRequestTopicPartition()
+	rp.PartitionMaxBytes = 1 << 20
+	rt.Partitions = append(rt.Partitions, rp)
+	req.Topics = append(req.Topics, rt)
+	resp, err := req.RequestWith(ctx, cl.Broker(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := kerr.ErrorForCode(resp.Topics[0].Partitions[0].ErrorCode); !errors.Is(err, kerr.OffsetOutOfRange) {
+		t.Errorf("fetch below the log start offset: got %v, expected OFFSET_OUT_OF_RANGE", err)
+	}
+
+	// With retention.bytes 0, every batch is deleted and the log starts
+	// at the high watermark.
+	alter("t",
+		kadm.AlterConfig{Name: "retention.ms", Value: str("-1")},
+		kadm.AlterConfig{Name: "retention.bytes", Value: str("0")},
+	)
+	if err := c.CleanLogs(); err != nil {
+		t.Fatal(err)
+	}
+	if got := logStart("t"); got != 3 {
+		t.Errorf("after retention.bytes: got log start offset %d, expected 3", got)
+	}
+
+	// Compacted topics are compacted rather than deleted.
+	alter("c", kadm.AlterConfig{Name: "cleanup.policy", Value: str("compact")})
+	produce("c", &kgo.Record{Key: []byte("k"), Value: []byte("1")})
+	produce("c", &kgo.Record{Key: []byte("k"), Value: []byte("2")})
+	if err := c.CleanLogs("c"); err != nil {
+		t.Fatal(err)
+	}
+	rs, err := c.ReadRecords("c", 0, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rs) != 1 || string(rs[0].Value) != "2" || rs[0].Offset != 1 {
+		t.Errorf("after compaction: got %d records, expected only the latest value of k", len(rs))
+	}
+}
+
+func TestAutoCleanLogs(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(1), SeedTopics(1, "t"), AutoCleanLogs(10*time.Millisecond))
+	if _, err := c.ProduceTo("t", 0, kgo.StringRecord("v")); err != nil {
+		t.Fatal(err)
+	}
+	retention := "0"
+	c.admin(func() { _ = c.data.setTopicConfig("t", "retention.bytes", &retention, false) })
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		var logStart int64
+		c.admin(func() {
+			pd, _ := c.data.tps.getp("t", 0)
+			logStart = pd.logStartOffset
+		})
+		if logStart == 1 {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("got log start offset %d, expected the background cleaner to advance it to 1", logStart)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
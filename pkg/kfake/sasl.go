@@ -29,6 +29,7 @@ type (
 		plain    map[string]string    // user => pass
 		scram256 map[string]scramAuth // user => scram auth
 		scram512 map[string]scramAuth // user => scram auth
+		tokens   map[string]*dtoken   // token ID => delegation token
 	}
 
 	saslStage uint8
@@ -38,6 +39,74 @@ func (s sasls) empty() bool {
 	return len(s.plain) == 0 && len(s.scram256) == 0 && len(s.scram512) == 0
 }
 
+// set adds or updates a user for the mechanism.
+func (s *sasls) set(mechanism, user, pass string) error {
+	switch mechanism {
+	case saslPlain:
+		if s.plain == nil {
+			s.plain = make(map[string]string)
+		}
+		s.plain[user] = pass
+	case saslScram256:
+		if s.scram256 == nil {
+			s.scram256 = make(map[string]scramAuth)
+		}
+		s.scram256[user] = newScramAuth(saslScram256, pass)
+	case saslScram512:
+		if s.scram512 == nil {
+			s.scram512 = make(map[string]scramAuth)
+		}
+		s.scram512[user] = newScramAuth(saslScram512, pass)
+	default:
+		return fmt.Errorf("unknown SASL mechanism %v", mechanism)
+	}
+	return nil
+}
+
+// remove removes a user for the mechanism, returning whether it existed.
+func (s *sasls) remove(mechanism, user string) bool {
+	var exists bool
+	switch mechanism {
+	case saslPlain:
+		_, exists = s.plain[user]
+		delete(s.plain, user)
+	case saslScram256:
+		_, exists = s.scram256[user]
+		delete(s.scram256, user)
+	case saslScram512:
+		_, exists = s.scram512[user]
+		delete(s.scram512, user)
+	}
+	return exists
+}
+
+// AddUser adds a SASL user to the cluster, or changes the password of an
+// existing user, as if AlterUserSCRAMCredentials were issued (PLAIN users
+// cannot otherwise be changed at runtime). The mechanism must be either
+// PLAIN, SCRAM-SHA-256, or SCRAM-SHA-512. Connections that already
+// authenticated are unaffected; new connections must use the new password.
+// This can be used to test credential rotation. Unlike users seeded with
+// Superuser, added users are not superusers: with EnableACLs, they need ACLs.
+func (c *Cluster) AddUser(mechanism, user, pass string) error {
+	var err error
+	c.admin(func() { err = c.sasls.set(mechanism, user, pass) })
+	return err
+}
+
+// RemoveUser removes a SASL user for the given mechanism, returning an error
+// if the user does not exist. As in Kafka, connections that already
+// authenticated as the user remain authenticated; new connections fail
+// authentication. If you remove every user, no new connections can
+// authenticate.
+func (c *Cluster) RemoveUser(mechanism, user string) error {
+	var exists bool
+	c.admin(func() { exists = c.sasls.remove(mechanism, user) })
+	if !exists {
+		return fmt.Errorf("%s user %q does not exist", mechanism, user)
+	}
+	return nil
+}
+
 const (
 	saslStageBegin saslStage = iota
 	saslStageAuthPlain
@@ -45,6 +114,7 @@ const (
 	saslStageAuthScram0_512
 	saslStageAuthScram1
 	saslStageComplete
+	saslStageFailed
 )
 
 func (c *Cluster) handleSASL(creq *clientReq) (allow bool) {
@@ -70,6 +140,8 @@ func (c *Cluster) handleSASL(creq *clientReq) (allow bool) {
 		}
 	case saslStageComplete:
 		return true
+	case saslStageFailed:
+		return false
 	default:
 		panic("unreachable")
 	}
@@ -125,6 +197,7 @@ type scramClient0 struct {
 	user  string
 	bare  []byte // client-first-message-bare
 	nonce []byte // nonce in client0
+	token bool   // whether the tokenauth extension was present; user is a token ID
 }
 
 var scramUnescaper = strings.NewReplacer("=3D", "=", "=2C", ",")
@@ -141,7 +214,12 @@ func scramParseClient0(client0 []byte) (scramClient0, error) {
 		nonce = bytes.Clone(m[4])
 		ext   = string(m[5])
 	)
-	if len(ext) != 0 {
+	var token bool
+	switch ext {
+	case "":
+	case ",tokenauth=true": // KIP-48
+		token = true
+	default:
 		return scramClient0{}, errors.New("invalid extensions")
 	}
 	if zid != "" && zid != user {
@@ -151,6 +229,7 @@ func scramParseClient0(client0 []byte) (scramClient0, error) {
 		user:  scramUnescaper.Replace(user),
 		bare:  bare,
 		nonce: nonce,
+		token: token,
 	}, nil
 }
 
@@ -163,6 +242,8 @@ func scramServerFirst(client0 scramClient0, auth scramAuth) (scramServer0, []byt
 	))
 	return scramServer0{
 		a:      auth,
+		user:   client0.user,
+		token:  client0.token,
 		c0bare: client0.bare,
 		s0:     serverFirst,
 	}, serverFirst
@@ -171,6 +252,8 @@ func scramServerFirst(client0 scramClient0, auth scramAuth) (scramServer0, []byt
 // server-first-message
 type scramServer0 struct {
 	a      scramAuth
+	user   string
+	token  bool
 	c0bare []byte
 	s0     []byte
 }
@@ -281,7 +364,8 @@ func init() {
 	// 5: ext
 	client0 := fmt.Sprintf("^n,(?:a=(%s))?,((?:m=%s,)?n=(%s),r=(%s)(%s))$", saslName, value, saslName, printable, ext)
 
-	// We reject extensions in client0. Kafka does not validate the nonce
+	// We reject extensions in client0 other than tokenauth, which
+	// authenticates with a delegation token. Kafka does not validate the nonce
 	// and some clients may generate it incorrectly (i.e. old franz-go), so
 	// we do not validate it.
 	//
@@ -0,0 +1,80 @@
+package kfake
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+	"github.com/twmb/franz-go/pkg/sasl"
+	"github.com/twmb/franz-go/pkg/sasl/plain"
+	"github.com/twmb/franz-go/pkg/sasl/scram"
+)
+
+func TestAddRemoveUser(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(1), EnableSASL(), Superuser("PLAIN", "admin", "pw"))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// auth issues a request on a new client, returning the client's
+	// broker so that its authenticated connection can be reused.
+	auth := func(m sasl.Mechanism) (*kgo.Broker, error) {
+		br := newTestClient(t, c, kgo.SASL(m), kgo.RequestRetries(0)).Broker(0)
+		_, err := kmsg.NewPtrMetadataRequest().RequestWith(ctx, br)
+		return br, err
+	}
+	expFailed := func(what string, err error) {
+		t.Helper()
+		if !errors.Is(err, kerr.SaslAuthenticationFailed) {
+			t.Errorf("%s: got %v, expected SASL_AUTHENTICATION_FAILED", what, err)
+		}
+	}
+
+	if err := c.AddUser("GSSAPI", "u", "p"); err == nil {
+		t.Error("adding a user with an unknown mechanism: got no error")
+	}
+	if err := c.AddUser("PLAIN", "u", "p1"); err != nil {
+		t.Fatal(err)
+	}
+	old, err := auth(plain.Auth{User: "u", Pass: "p1"}.AsMechanism())
+	if err != nil {
+		t.Fatalf("authenticating as an added user: %v", err)
+	}
+	_, err = auth(plain.Auth{User: "nobody", Pass: "p"}.AsMechanism())
+	expFailed("unknown user", err)
+
+	// Changing the password rejects the old password on new connections
+	// only.
+	if err := c.AddUser("PLAIN", "u", "p2"); err != nil {
+		t.Fatal(err)
+	}
+	_, err = auth(plain.Auth{User: "u", Pass: "p1"}.AsMechanism())
+	expFailed("old password", err)
+	if _, err := auth(plain.Auth{User: "u", Pass: "p2"}.AsMechanism()); err != nil {
+		t.Errorf("new password: %v", err)
+	}
+	if _, err := kmsg.NewPtrMetadataRequest().RequestWith(ctx, old); err != nil {
+		t.Errorf("connection authenticated with the old password: %v", err)
+	}
+
+	if err := c.AddUser("SCRAM-SHA-512", "s", "p"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := auth(scram.Auth{User: "s", Pass: "p"}.AsSha512Mechanism()); err != nil {
+		t.Errorf("authenticating as an added SCRAM user: %v", err)
+	}
+	_, err = auth(scram.Auth{User: "s", Pass: "p"}.AsSha256Mechanism())
+	expFailed("SCRAM user with the other mechanism", err)
+
+	if err := c.RemoveUser("PLAIN", "nobody"); err == nil {
+		t.Error("removing a missing user: got no error")
+	}
+	if err := c.RemoveUser("PLAIN", "u"); err != nil {
+		t.Fatal(err)
+	}
+	_, err = auth(plain.Auth{User: "u", Pass: "p2"}.AsMechanism())
+	expFailed("removed user", err)
+}
@@ -0,0 +1,674 @@
+package kfake
+
+import (
+	"sort"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// Share groups (KIP-932) are managed entirely within the cluster run loop.
+// Unlike classic groups, nothing about a share group heartbeat needs to be
+// delayed, so we do not need a dedicated manage goroutine per group.
+//
+// Share partition state is, in Kafka, owned by the partition leader. We keep
+// all state in one place keyed by group and validate leadership on every
+// ShareFetch / ShareAcknowledge.
+//
+// Records are acquired for the broker config group.share.record.lock.duration.ms.
+// If a record is not acknowledged before the lock expires, it is released and
+// becomes available for redelivery. A record that is released after being
+// delivered group.share.delivery.count.limit times is archived instead.
+//
+// TODO
+//
+// * Only the "simple" assignor is supported: every member is assigned every
+//   partition of every topic it subscribes to.
+// * DescribeGroups / ListGroups do not return share groups.
+
+const (
+	shareHeartbeatInterval = 5 * time.Second
+	shareSessionTimeout    = 45 * time.Second
+)
+
+type (
+	shareGroups struct {
+		c  *Cluster
+		gs map[string]*shareGroup
+	}
+
+	shareGroup struct {
+		c    *Cluster
+		name string
+
+		epoch    int32
+		members  map[string]*shareMember
+		parts    tps[sharePart]
+		sessions map[shareSessionKey]*shareSession
+	}
+
+	shareMember struct {
+		memberID   string
+		clientID   string
+		clientHost string
+		rackID     *string
+
+		epoch    int32
+		topics   []string
+		assigned map[string][]int32
+
+		t *time.Timer
+	}
+
+	// sharePart is the state for a single share partition: the records
+	// between start and end are "in flight" and tracked individually. All
+	// records at or past end have never been delivered and are available.
+	sharePart struct {
+		g *shareGroup
+		t string
+		p int32
+
+		start int64
+		end   int64
+		recs  map[int64]*shareRec
+	}
+
+	shareRec struct {
+		state      shareRecState
+		member     string
+		deliveries int16
+		lock       *shareLock
+	}
+
+	// shareLock is the acquisition lock for all records acquired in a
+	// single ShareFetch for a single partition.
+	shareLock struct {
+		t *time.Timer
+	}
+
+	shareRecState int8
+
+	shareSessionKey struct {
+		member string
+		node   int32
+	}
+
+	shareSession struct {
+		epoch int32
+		parts tps[int32] // partition => max bytes
+	}
+)
+
+const (
+	shareRecAvailable shareRecState = iota
+	shareRecAcquired
+	shareRecAcknowledged
+	shareRecArchived
+)
+
+func (s shareRecState) String() string {
+	switch s {
+	case shareRecAvailable:
+		return "Available"
+	case shareRecAcquired:
+		return "Acquired"
+	case shareRecAcknowledged:
+		return "Acknowledged"
+	case shareRecArchived:
+		return "Archived"
+	default:
+		return "Unknown"
+	}
+}
+
+// state returns the group's state: share groups are Stable if they have
+// members, and Empty otherwise.
+func (g *shareGroup) state() string {
+	if len(g.members) > 0 {
+		return "Stable"
+	}
+	return "Empty"
+}
+
+func (sgs *shareGroups) get(name string) *shareGroup {
+	if sgs.gs == nil {
+		return nil
+	}
+	return sgs.gs[name]
+}
+
+func (sgs *shareGroups) getOrCreate(name string) *shareGroup {
+	if sgs.gs == nil {
+		sgs.gs = make(map[string]*shareGroup)
+	}
+	g := sgs.gs[name]
+	if g == nil {
+		g = &shareGroup{
+			c:        sgs.c,
+			name:     name,
+			members:  make(map[string]*shareMember),
+			sessions: make(map[shareSessionKey]*shareSession),
+		}
+		sgs.gs[name] = g
+	}
+	return g
+}
+
+///////////////
+// HEARTBEAT //
+///////////////
+
+func (sgs *shareGroups) handleHeartbeat(creq *clientReq) *kmsg.ShareGroupHeartbeatResponse {
+	req := creq.kreq.(*kmsg.ShareGroupHeartbeatRequest)
+	resp := req.ResponseKind().(*kmsg.ShareGroupHeartbeatResponse)
+
+	if kerr := sgs.c.validateGroup(creq, req.GroupID); kerr != nil {
+		resp.ErrorCode = kerr.Code
+		return resp
+	}
+
+	switch req.MemberEpoch {
+	case 0:
+		if req.SubscribedTopicNames == nil {
+			resp.ErrorCode = kerr.InvalidRequest.Code
+			return resp
+		}
+		g := sgs.getOrCreate(req.GroupID)
+		m := &shareMember{
+			memberID:   generateMemberID(creq.cid, nil),
+			clientID:   creq.cid,
+			clientHost: creq.cc.conn.RemoteAddr().String(),
+			rackID:     req.RackID,
+			topics:     req.SubscribedTopicNames,
+		}
+		g.members[m.memberID] = m
+		g.bumpEpoch()
+		g.keepAlive(m)
+		g.fillHeartbeat(m, resp, true)
+		return resp
+
+	case -1:
+		g := sgs.get(req.GroupID)
+		if g == nil || g.members[req.MemberID] == nil {
+			resp.ErrorCode = kerr.UnknownMemberID.Code
+			return resp
+		}
+		g.removeMember(g.members[req.MemberID])
+		resp.MemberID = &req.MemberID
+		resp.MemberEpoch = -1
+		return resp
+	}
+
+	g := sgs.get(req.GroupID)
+	if g == nil {
+		resp.ErrorCode = kerr.UnknownMemberID.Code
+		return resp
+	}
+	m := g.members[req.MemberID]
+	if m == nil {
+		resp.ErrorCode = kerr.UnknownMemberID.Code
+		return resp
+	}
+	if req.MemberEpoch > m.epoch {
+		resp.ErrorCode = kerr.FencedMemberEpoch.Code
+		return resp
+	}
+	if req.RackID != nil {
+		m.rackID = req.RackID
+	}
+	var changed bool
+	if req.SubscribedTopicNames != nil && !sameStrings(m.topics, req.SubscribedTopicNames) {
+		m.topics = req.SubscribedTopicNames
+		changed = true
+	}
+	// Topics may have been created or have had partitions added since
+	// the last heartbeat; we recompute the member's assignment every time.
+	if changed || !sameAssignment(m.assigned, g.assignFor(m)) {
+		g.bumpEpoch()
+	}
+	g.keepAlive(m)
+	g.fillHeartbeat(m, resp, req.MemberEpoch != m.epoch)
+	return resp
+}
+
+func (g *shareGroup) bumpEpoch() {
+	g.epoch++
+	for _, m := range g.members {
+		m.assigned = g.assignFor(m)
+		m.epoch = g.epoch
+	}
+}
+
+// assignFor implements the simple assignor: a member is assigned every
+// partition of every topic it subscribes to that exists.
+func (g *shareGroup) assignFor(m *shareMember) map[string][]int32 {
+	assigned := make(map[string][]int32)
+	for _, t := range m.topics {
+		ps, ok := g.c.data.tps.gett(t)
+		if !ok {
+			continue
+		}
+		for p := range ps {
+			assigned[t] = append(assigned[t], p)
+		}
+		sort.Slice(assigned[t], func(i, j int) bool { return assigned[t][i] < assigned[t][j] })
+	}
+	return assigned
+}
+
+func (g *shareGroup) fillHeartbeat(m *shareMember, resp *kmsg.ShareGroupHeartbeatResponse, withAssignment bool) {
+	resp.MemberID = &m.memberID
+	resp.MemberEpoch = m.epoch
+	resp.HeartbeatIntervalMillis = int32(shareHeartbeatInterval.Milliseconds())
+	if !withAssignment {
+		return
+	}
+	a := new(kmsg.ShareGroupHeartbeatResponseAssignment)
+	a.Default()
+	for t, ps := range m.assigned {
+		at := kmsg.NewShareGroupHeartbeatResponseAssignmentTopicPartition()
+		at.TopicID = g.c.data.t2id[t]
+		at.Partitions = ps
+		a.TopicPartitions = append(a.TopicPartitions, at)
+	}
+	resp.Assignment = a
+}
+
+// keepAlive resets the member's session timeout; if the member does not
+// heartbeat within the timeout, it is removed from the group.
+func (g *shareGroup) keepAlive(m *shareMember) {
+	if m.t != nil {
+		m.t.Stop()
+	}
+	var t *time.Timer
+	t = time.AfterFunc(shareSessionTimeout, func() {
+		select {
+		case <-g.c.die:
+		case g.c.adminCh <- func() {
+			if m.t == t && g.members[m.memberID] == m {
+				g.removeMember(m)
+			}
+		}:
+		}
+	})
+	m.t = t
+}
+
+func (g *shareGroup) removeMember(m *shareMember) {
+	if m.t != nil {
+		m.t.Stop()
+	}
+	delete(g.members, m.memberID)
+	for k := range g.sessions {
+		if k.member == m.memberID {
+			delete(g.sessions, k)
+		}
+	}
+	g.parts.each(func(_ string, _ int32, sp *sharePart) {
+		sp.releaseMember(m.memberID)
+	})
+	g.bumpEpoch()
+}
+
+//////////////
+// DESCRIBE //
+//////////////
+
+func (sgs *shareGroups) handleDescribe(creq *clientReq) *kmsg.ShareGroupDescribeResponse {
+	req := creq.kreq.(*kmsg.ShareGroupDescribeRequest)
+	resp := req.ResponseKind().(*kmsg.ShareGroupDescribeResponse)
+
+	for _, rg := range req.GroupIDs {
+		sg := kmsg.NewShareGroupDescribeResponseGroup()
+		sg.GroupID = rg
+		if kerr := sgs.c.validateGroup(creq, rg); kerr != nil {
+			sg.ErrorCode = kerr.Code
+			resp.Groups = append(resp.Groups, sg)
+			continue
+		}
+		g := sgs.get(rg)
+		if g == nil {
+			sg.ErrorCode = kerr.GroupIDNotFound.Code
+			resp.Groups = append(resp.Groups, sg)
+			continue
+		}
+		sg.GroupState = g.state()
+		sg.GroupEpoch = g.epoch
+		sg.AssignmentEpoch = g.epoch
+		sg.Assignor = "simple"
+		for _, m := range g.members {
+			sm := kmsg.NewShareGroupDescribeResponseGroupMember()
+			sm.MemberID = m.memberID
+			sm.RackID = m.rackID
+			sm.MemberEpoch = m.epoch
+			sm.ClientID = m.clientID
+			sm.ClientHost = m.clientHost
+			sm.SubscribedTopicNames = m.topics
+			for t, ps := range m.assigned {
+				at := kmsg.NewShareGroupDescribeResponseGroupMemberAssignmentTopicPartition()
+				at.TopicID = g.c.data.t2id[t]
+				at.Topic = t
+				at.Partitions = ps
+				sm.Assignment.TopicPartitions = append(sm.Assignment.TopicPartitions, at)
+			}
+			sg.Members = append(sg.Members, sm)
+		}
+		resp.Groups = append(resp.Groups, sg)
+	}
+	return resp
+}
+
+//////////////
+// SESSIONS //
+//////////////
+
+// session validates the share session epoch for a ShareFetch or
+// ShareAcknowledge request, returning the session to use or an error code.
+// An epoch of 0 creates a new session (and is only allowed when fetching).
+// An epoch of -1 returns the existing session, which the caller closes after
+// processing acknowledgements.
+func (g *shareGroup) session(member string, node int32, epoch int32, allowCreate bool) (*shareSession, int16) {
+	k := shareSessionKey{member, node}
+	s := g.sessions[k]
+	switch epoch {
+	case 0:
+		if !allowCreate {
+			return nil, kerr.InvalidShareSessionEpoch.Code
+		}
+		if s != nil {
+			g.closeSession(member, node)
+		}
+		s = &shareSession{epoch: 1}
+		g.sessions[k] = s
+		return s, 0
+	case -1:
+		if s == nil {
+			return nil, kerr.ShareSessionNotFound.Code
+		}
+		return s, 0
+	}
+	if s == nil {
+		return nil, kerr.ShareSessionNotFound.Code
+	}
+	if s.epoch != epoch {
+		return nil, kerr.InvalidShareSessionEpoch.Code
+	}
+	s.epoch++
+	if s.epoch < 0 {
+		s.epoch = 1
+	}
+	return s, 0
+}
+
+// closeSession drops a share session and releases every record the member
+// acquired in partitions tracked by the session.
+func (g *shareGroup) closeSession(member string, node int32) {
+	k := shareSessionKey{member, node}
+	s := g.sessions[k]
+	if s == nil {
+		return
+	}
+	delete(g.sessions, k)
+	s.parts.each(func(t string, p int32, _ *int32) {
+		if sp, ok := g.parts.getp(t, p); ok {
+			sp.releaseMember(member)
+		}
+	})
+}
+
+/////////////////////
+// SHARE PARTITION //
+/////////////////////
+
+func (g *shareGroup) part(t string, p int32, pd *partData) *sharePart {
+	return g.parts.mkp(t, p, func() *sharePart {
+		return &sharePart{
+			g:     g,
+			t:     t,
+			p:     p,
+			start: pd.logStartOffset,
+			end:   pd.logStartOffset,
+			recs:  make(map[int64]*shareRec),
+		}
+	})
+}
+
+// acquire acquires available records for the member from the partition,
+// returning the raw batches to send and the acquired offset ranges. Whole
+// batches are returned; the client uses the acquired ranges to filter.
+func (sp *sharePart) acquire(pd *partData, member string, maxBytes int) ([]byte, []kmsg.ShareFetchResponseTopicPartitionAcquiredRecord, int) {
+	if sp.start < pd.logStartOffset {
+		for o := sp.start; o < pd.logStartOffset; o++ {
+			delete(sp.recs, o)
+		}
+		sp.start = pd.logStartOffset
+		if sp.end < sp.start {
+			sp.end = sp.start
+		}
+	}
+
+	i, ok, atEnd := pd.searchOffset(sp.start)
+	if atEnd || !ok {
+		return nil, nil, 0
+	}
+
+	var (
+		batches  []byte
+		acquired []kmsg.ShareFetchResponseTopicPartitionAcquiredRecord
+		nbytes   int
+		lock     = new(shareLock)
+	)
+	for _, b := range pd.batches[i:] {
+		if nbytes > 0 && nbytes+b.nbytes > maxBytes {
+			break
+		}
+		var took bool
+		last := b.FirstOffset + int64(b.LastOffsetDelta)
+		for o := b.FirstOffset; o <= last; o++ {
+			if o < sp.start {
+				continue
+			}
+			if o >= sp.end {
+				for ; sp.end <= o; sp.end++ {
+					sp.recs[sp.end] = &shareRec{state: shareRecAvailable}
+				}
+			}
+			r := sp.recs[o]
+			if r.state != shareRecAvailable {
+				continue
+			}
+			r.state = shareRecAcquired
+			r.member = member
+			r.deliveries++
+			r.lock = lock
+			took = true
+			if n := len(acquired); n > 0 && acquired[n-1].LastOffset == o-1 && acquired[n-1].DeliveryCount == r.deliveries {
+				acquired[n-1].LastOffset = o
+			} else {
+				ar := kmsg.NewShareFetchResponseTopicPartitionAcquiredRecord()
+				ar.FirstOffset = o
+				ar.LastOffset = o
+				ar.DeliveryCount = r.deliveries
+				acquired = append(acquired, ar)
+			}
+		}
+		if took {
+			nbytes += b.nbytes
+			batches = b.AppendTo(batches)
+		}
+	}
+	if len(acquired) > 0 {
+		c := sp.g.c
+		lock.t = time.AfterFunc(time.Duration(c.brokerConfigInt("group.share.record.lock.duration.ms"))*time.Millisecond, func() {
+			select {
+			case <-c.die:
+			case c.adminCh <- func() { sp.expire(lock) }:
+			}
+		})
+	}
+	return batches, acquired, nbytes
+}
+
+// expire releases all records still acquired under the given lock.
+func (sp *sharePart) expire(lock *shareLock) {
+	var released bool
+	for _, r := range sp.recs {
+		if r.state == shareRecAcquired && r.lock == lock {
+			sp.release(r)
+			released = true
+		}
+	}
+	if released {
+		sp.advance()
+		sp.wake()
+	}
+}
+
+// release returns an acquired record to the available state, or archives
+// it if it has hit the delivery count limit.
+func (sp *sharePart) release(r *shareRec) {
+	r.state = shareRecAvailable
+	if int64(r.deliveries) >= sp.g.c.brokerConfigInt("group.share.delivery.count.limit") {
+		r.state = shareRecArchived
+	}
+	r.member = ""
+	r.lock = nil
+}
+
+// wake wakes any ShareFetch waiting on this partition, since records that
+// were released are immediately acquirable.
+func (sp *sharePart) wake() {
+	pd, ok := sp.g.c.data.tps.getp(sp.t, sp.p)
+	if !ok {
+		return
+	}
+	for w := range pd.watch {
+		if _, ok := w.creq.kreq.(*kmsg.ShareFetchRequest); ok {
+			w.deleted()
+		}
+	}
+}
+
+// acknowledge applies acknowledgement batches from the member, returning a
+// non-zero error code if any acknowledged record was not acquired by the
+// member.
+func (sp *sharePart) acknowledge(member string, batches []kmsg.ShareFetchRequestTopicPartitionAcknowledgementBatch) int16 {
+	var (
+		errCode  int16
+		released bool
+	)
+	for _, b := range batches {
+		if b.LastOffset < b.FirstOffset || len(b.AcknowledgeTypes) == 0 ||
+			len(b.AcknowledgeTypes) != 1 && int64(len(b.AcknowledgeTypes)) != b.LastOffset-b.FirstOffset+1 {
+			errCode = kerr.InvalidRequest.Code
+			continue
+		}
+		for o := b.FirstOffset; o <= b.LastOffset; o++ {
+			typ := b.AcknowledgeTypes[0]
+			if len(b.AcknowledgeTypes) > 1 {
+				typ = b.AcknowledgeTypes[o-b.FirstOffset]
+			}
+			r := sp.recs[o]
+			if r == nil || r.state != shareRecAcquired || r.member != member {
+				errCode = kerr.InvalidRecordState.Code
+				continue
+			}
+			switch typ {
+			case 0, 3: // gap, reject
+				r.state = shareRecArchived
+			case 1: // accept
+				r.state = shareRecAcknowledged
+			case 2: // release
+				sp.release(r)
+				released = true
+				continue
+			default:
+				errCode = kerr.InvalidRequest.Code
+				continue
+			}
+			r.member = ""
+			r.lock = nil
+		}
+	}
+	sp.advance()
+	if released {
+		sp.wake()
+	}
+	return errCode
+}
+
+// advance moves the share partition start offset past all leading records
+// that are finished (acknowledged or archived).
+func (sp *sharePart) advance() {
+	for sp.start < sp.end {
+		r := sp.recs[sp.start]
+		if r != nil && r.state != shareRecAcknowledged && r.state != shareRecArchived {
+			return
+		}
+		delete(sp.recs, sp.start)
+		sp.start++
+	}
+}
+
+func (sp *sharePart) releaseMember(member string) {
+	var released bool
+	for _, r := range sp.recs {
+		if r.state == shareRecAcquired && r.member == member {
+			sp.release(r)
+			released = true
+		}
+	}
+	if released {
+		sp.advance()
+		sp.wake()
+	}
+}
+
+// The share fetch and acknowledge requests have identical acknowledgement
+// batch shapes; we convert ShareAcknowledge batches to reuse acknowledge.
+func shareAckBatches(in []kmsg.ShareAcknowledgeRequestTopicPartitionAcknowledgementBatch) []kmsg.ShareFetchRequestTopicPartitionAcknowledgementBatch {
+	out := make([]kmsg.ShareFetchRequestTopicPartitionAcknowledgementBatch, 0, len(in))
+	for _, b := range in {
+		out = append(out, kmsg.ShareFetchRequestTopicPartitionAcknowledgementBatch{
+			FirstOffset:      b.FirstOffset,
+			LastOffset:       b.LastOffset,
+			AcknowledgeTypes: b.AcknowledgeTypes,
+		})
+	}
+	return out
+}
+
+func sameStrings(l, r []string) bool {
+	if len(l) != len(r) {
+		return false
+	}
+	ls := append([]string(nil), l...)
+	rs := append([]string(nil), r...)
+	sort.Strings(ls)
+	sort.Strings(rs)
+	for i := range ls {
+		if ls[i] != rs[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func sameAssignment(l, r map[string][]int32) bool {
+	if len(l) != len(r) {
+		return false
+	}
+	for t, lps := range l {
+		rps, ok := r[t]
+		if !ok || len(lps) != len(rps) {
+			return false
+		}
+		for i := range lps {
+			if lps[i] != rps[i] {
+				return false
+			}
+		}
+	}
+	return true
+}
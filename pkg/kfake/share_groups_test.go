@@ -0,0 +1,230 @@
+package kfake
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// shareTester issues share group requests for one member consuming
+// partition 0 of topic "t". The client does not know how to route share
+// group requests (see withKeys), so we issue them all to the one broker in
+// the cluster.
+type shareTester struct {
+	t     *testing.T
+	ctx   context.Context
+	br    *kgo.Broker
+	id    uuid
+	group string
+
+	member string
+	epoch  int32 // share session epoch
+}
+
+func newShareTester(ctx context.Context, t *testing.T, c *Cluster, cl *kgo.Client) *shareTester {
+	t.Helper()
+	s := &shareTester{t: t, ctx: ctx, br: cl.Broker(0), group: "g"}
+	c.admin(func() { s.id = c.data.t2id["t"] })
+
+	req := kmsg.NewPtrShareGroupHeartbeatRequest()
+	req.GroupID = s.group
+	req.SubscribedTopicNames = []string{"t"}
+	resp, err := req.RequestWith(ctx, s.br)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := kerr.ErrorForCode(resp.ErrorCode); err != nil {
+		t.Fatalf("join: %v", err)
+	}
+	if resp.MemberID == nil || resp.Assignment == nil || len(resp.Assignment.TopicPartitions) != 1 {
+		t.Fatalf("join: got member %v and assignment %+v, expected partition 0 of t", resp.MemberID, resp.Assignment)
+	}
+	s.member = *resp.MemberID
+	return s
+}
+
+// fetch issues a ShareFetch in the member's session, acknowledging the given
+// batches first.
+func (s *shareTester) fetch(maxWait time.Duration, acks ...kmsg.ShareFetchRequestTopicPartitionAcknowledgementBatch) *kmsg.ShareFetchResponse {
+	s.t.Helper()
+	req := kmsg.NewPtrShareFetchRequest()
+	req.GroupID = &s.group
+	req.MemberID = &s.member
+	req.ShareSessionEpoch = s.epoch
+	req.MaxWaitMillis = int32(maxWait.Milliseconds())
+	req.MaxBytes = 1 << 20
+	rt := kmsg.NewShareFetchRequestTopic()
+	rt.TopicID = s.id
+	rp := kmsg.NewShareFetchRequestTopicPartition()
+	rp.PartitionMaxBytes = 1 << 20
+	rp.AcknowledgementBatches = acks
+	rt.Partitions = append(rt.Partitions, rp)
+	req.Topics = append(req.Topics, rt)
+	resp, err := req.RequestWith(s.ctx, s.br)
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	if resp.ErrorCode == 0 {
+		s.epoch++
+	}
+	return resp
+}
+
+// acquired returns the acquired records in a fetch response, failing the test
+// on any error.
+func (s *shareTester) acquired(resp *kmsg.ShareFetchResponse) []kmsg.ShareFetchResponseTopicPartitionAcquiredRecord {
+	s.t.Helper()
+	if err := kerr.ErrorForCode(resp.ErrorCode); err != nil {
+		s.t.Fatalf("share fetch: %v", err)
+	}
+	var acquired []kmsg.ShareFetchResponseTopicPartitionAcquiredRecord
+	for _, rt := range resp.Topics {
+		for _, rp := range rt.Partitions {
+			if err := kerr.ErrorForCode(rp.ErrorCode); err != nil {
+				s.t.Fatalf("share fetch partition: %v", err)
+			}
+			if err := kerr.ErrorForCode(rp.AcknowledgeErrorCode); err != nil {
+				s.t.Fatalf("share fetch acknowledge: %v", err)
+			}
+			acquired = append(acquired, rp.AcquiredRecords...)
+		}
+	}
+	return acquired
+}
+
+func (s *shareTester) acknowledge(acks ...kmsg.ShareFetchRequestTopicPartitionAcknowledgementBatch) {
+	s.t.Helper()
+	req := kmsg.NewPtrShareAcknowledgeRequest()
+	req.GroupID = &s.group
+	req.MemberID = &s.member
+	req.ShareSessionEpoch = s.epoch
+	rt := kmsg.NewShareAcknowledgeRequestTopic()
+	rt.TopicID = s.id
+	rp := kmsg.NewShareAcknowledgeRequestTopicPartition()
+	for _, ack := range acks {
+		b := kmsg.NewShareAcknowledgeRequestTopicPartitionAcknowledgementBatch()
+		b.FirstOffset, b.LastOffset, b.AcknowledgeTypes = ack.FirstOffset, ack.LastOffset, ack.AcknowledgeTypes
+		rp.AcknowledgementBatches = append(rp.AcknowledgementBatches, b)
+	}
+	rt.Partitions = append(rt.Partitions, rp)
+	req.Topics = append(req.Topics, rt)
+	resp, err := req.RequestWith(s.ctx, s.br)
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	if err := kerr.ErrorForCode(resp.ErrorCode); err != nil {
+		s.t.Fatalf("share acknowledge: %v", err)
+	}
+	if err := kerr.ErrorForCode(resp.Topics[0].Partitions[0].ErrorCode); err != nil {
+		s.t.Fatalf("share acknowledge partition: %v", err)
+	}
+	s.epoch++
+}
+
+func shareAck(first, last int64, typ int8) kmsg.ShareFetchRequestTopicPartitionAcknowledgementBatch {
+	b := kmsg.NewShareFetchRequestTopicPartitionAcknowledgementBatch()
+	b.FirstOffset, b.LastOffset, b.AcknowledgeTypes = first, last, []int8{typ}
+	return b
+}
+
+func checkAcquired(t *testing.T, got []kmsg.ShareFetchResponseTopicPartitionAcquiredRecord, exp ...[3]int64) {
+	t.Helper()
+	if len(got) != len(exp) {
+		t.Fatalf("got %d acquired ranges, expected %d", len(got), len(exp))
+	}
+	for i, e := range exp {
+		if g := got[i]; g.FirstOffset != e[0] || g.LastOffset != e[1] || int64(g.DeliveryCount) != e[2] {
+			t.Errorf("acquired range %d: got %d-%d delivered %d times, expected %d-%d delivered %d times",
+				i, g.FirstOffset, g.LastOffset, g.DeliveryCount, e[0], e[1], e[2])
+		}
+	}
+}
+
+func TestShareGroup(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(1), SeedTopics(1, "t"))
+	cl := newTestClient(t, c, withKeys(76, 77, 78, 79))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if _, err := c.ProduceTo("t", 0, kgo.StringRecord("a"), kgo.StringRecord("b"), kgo.StringRecord("c")); err != nil {
+		t.Fatal(err)
+	}
+	s := newShareTester(ctx, t, c, cl)
+
+	resp := s.fetch(0)
+	checkAcquired(t, s.acquired(resp), [3]int64{0, 2, 1})
+	if len(resp.Topics[0].Partitions[0].Records) == 0 {
+		t.Error("share fetch acquired records but returned no batches")
+	}
+	checkAcquired(t, s.acquired(s.fetch(0))) // everything is acquired
+
+	// Accepted records are done; released records are redelivered.
+	s.acknowledge(shareAck(0, 1, 1), shareAck(2, 2, 2))
+	checkAcquired(t, s.acquired(s.fetch(0)), [3]int64{2, 2, 2})
+
+	// Acknowledging records we do not hold fails.
+	resp = s.fetch(0, shareAck(0, 0, 1))
+	if code := resp.Topics[0].Partitions[0].AcknowledgeErrorCode; code != kerr.InvalidRecordState.Code {
+		t.Errorf("acknowledging an accepted record: got %v, expected INVALID_RECORD_STATE", kerr.ErrorForCode(code))
+	}
+
+	describe := kmsg.NewPtrShareGroupDescribeRequest()
+	describe.GroupIDs = []string{s.group}
+	dresp, err := describe.RequestWith(ctx, s.br)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g := dresp.Groups[0]; g.ErrorCode != 0 || len(g.Members) != 1 || g.Members[0].MemberID != s.member {
+		t.Errorf("describe: got %+v, expected one member %s", g, s.member)
+	}
+
+	// After leaving, the member can no longer fetch.
+	leave := kmsg.NewPtrShareGroupHeartbeatRequest()
+	leave.GroupID = s.group
+	leave.MemberID = s.member
+	leave.MemberEpoch = -1
+	if _, err := leave.RequestWith(ctx, s.br); err != nil {
+		t.Fatal(err)
+	}
+	if resp := s.fetch(0); resp.ErrorCode != kerr.UnknownMemberID.Code {
+		t.Errorf("fetch after leaving: got %v, expected UNKNOWN_MEMBER_ID", kerr.ErrorForCode(resp.ErrorCode))
+	}
+}
+
+func TestShareGroupLocksAndDeliveryLimit(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(1), SeedTopics(1, "t"))
+	cl := newTestClient(t, c, withKeys(76, 77, 78, 79))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	lock, limit := "1000", "2"
+	if _, err := kadm.NewClient(cl).AlterBrokerConfigs(ctx, []kadm.AlterConfig{
+		{Name: "group.share.record.lock.duration.ms", Value: &lock},
+		{Name: "group.share.delivery.count.limit", Value: &limit},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.ProduceTo("t", 0, kgo.StringRecord("a")); err != nil {
+		t.Fatal(err)
+	}
+	s := newShareTester(ctx, t, c, cl)
+
+	start := time.Now()
+	checkAcquired(t, s.acquired(s.fetch(0)), [3]int64{0, 0, 1})
+
+	// We do not acknowledge the record; once its lock expires, our
+	// waiting fetch is woken to acquire it again.
+	checkAcquired(t, s.acquired(s.fetch(5*time.Second)), [3]int64{0, 0, 2})
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("record was redelivered after %v, before its lock expired", elapsed)
+	}
+
+	// Releasing the record at the delivery limit archives it.
+	s.acknowledge(shareAck(0, 0, 2))
+	checkAcquired(t, s.acquired(s.fetch(0)))
+}
@@ -0,0 +1,323 @@
+package kfake
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// A snapshot captures the data of a cluster: topics and their configs,
+// partitions and their records, committed group offsets, and producer IDs.
+// It does not capture anything tied to live clients or to time: group
+// membership, in progress transactions and transactional offset commits,
+// parked fetches, faults, and control functions. Snapshots are best taken
+// once clients have finished producing and committing.
+//
+// A partition's batches are kept encoded exactly as they are in the log, so
+// offsets, timestamps, compaction gaps, and transaction markers are restored
+// as is. A transaction that was open when the snapshot was taken holds the
+// partition's last stable offset until a marker is appended with
+// AppendTxnMarker.
+
+// Snapshot is a dump of a cluster's data, returned from Cluster.Snapshot and
+// used with Cluster.Restore or NewClusterFromSnapshot. All fields are
+// exported so that a snapshot can be serialized (for example, with
+// encoding/json) and committed as a test fixture.
+type Snapshot struct {
+	Topics      []SnapshotTopic      // topics, sorted by name
+	Groups      []SnapshotGroup      // groups with committed offsets, sorted by name
+	ProducerIDs []SnapshotProducerID // producer IDs, sorted by ID
+}
+
+// SnapshotTopic is a topic in a Snapshot.
+type SnapshotTopic struct {
+	Topic      string
+	TopicID    [16]byte
+	Replicas   int
+	Configs    map[string]*string // topic config overrides
+	Partitions []SnapshotPartition
+}
+
+// SnapshotPartition is a partition in a SnapshotTopic.
+type SnapshotPartition struct {
+	Partition      int32
+	Leader         int32 // leader node ID; a random broker is used if the node does not exist on restore
+	LeaderEpoch    int32
+	LogStartOffset int64
+	HighWatermark  int64
+	Batches        [][]byte // encoded record batches, in offset order
+}
+
+// SnapshotGroup is a group's committed offsets in a Snapshot.
+type SnapshotGroup struct {
+	Group   string
+	Commits []SnapshotCommit
+}
+
+// SnapshotCommit is a committed offset in a SnapshotGroup.
+type SnapshotCommit struct {
+	Topic       string
+	Partition   int32
+	Offset      int64
+	LeaderEpoch int32
+	Metadata    *string
+}
+
+// SnapshotProducerID is an idempotent or transactional producer ID in a
+// Snapshot, along with the next sequence number it must produce with to each
+// partition it has produced to in its current epoch.
+type SnapshotProducerID struct {
+	ID        int64
+	Epoch     int16
+	Sequences []SnapshotSequence
+}
+
+// SnapshotSequence is the next expected sequence number for a producer ID on
+// a partition.
+type SnapshotSequence struct {
+	Topic        string
+	Partition    int32
+	NextSequence int32
+}
+
+// Snapshot returns a dump of the cluster's topics, records, committed
+// offsets, and producer IDs. The snapshot shares no memory with the cluster.
+func (c *Cluster) Snapshot() *Snapshot {
+	s := new(Snapshot)
+	c.admin(func() {
+		for t, ps := range c.data.tps {
+			st := SnapshotTopic{
+				Topic:    t,
+				TopicID:  c.data.t2id[t],
+				Replicas: c.data.treplicas[t],
+			}
+			st.Configs = copyConfigs(c.data.tcfgs[t])
+			for p, pd := range ps {
+				sp := SnapshotPartition{
+					Partition:      p,
+					Leader:         pd.leader.node,
+					LeaderEpoch:    pd.epoch,
+					LogStartOffset: pd.logStartOffset,
+					HighWatermark:  pd.highWatermark,
+				}
+				for i := range pd.batches {
+					sp.Batches = append(sp.Batches, pd.batches[i].appendTo(nil))
+				}
+				st.Partitions = append(st.Partitions, sp)
+			}
+			sort.Slice(st.Partitions, func(i, j int) bool { return st.Partitions[i].Partition < st.Partitions[j].Partition })
+			s.Topics = append(s.Topics, st)
+		}
+		sort.Slice(s.Topics, func(i, j int) bool { return s.Topics[i].Topic < s.Topics[j].Topic })
+
+		for name, g := range c.groups.gs {
+			sg := SnapshotGroup{Group: name}
+			g.waitControl(func() {
+				g.commits.each(func(t string, p int32, oc *offsetCommit) {
+					var md *string
+					if oc.metadata != nil {
+						m := *oc.metadata
+						md = &m
+					}
+					sg.Commits = append(sg.Commits, SnapshotCommit{t, p, oc.offset, oc.leaderEpoch, md})
+				})
+			})
+			if len(sg.Commits) == 0 {
+				continue
+			}
+			sort.Slice(sg.Commits, func(i, j int) bool {
+				l, r := &sg.Commits[i], &sg.Commits[j]
+				return l.Topic < r.Topic || l.Topic == r.Topic && l.Partition < r.Partition
+			})
+			s.Groups = append(s.Groups, sg)
+		}
+		sort.Slice(s.Groups, func(i, j int) bool { return s.Groups[i].Group < s.Groups[j].Group })
+
+		for id, pm := range c.pids {
+			if pm.expired {
+				continue
+			}
+			sp := SnapshotProducerID{ID: id, Epoch: pm.epoch}
+			pm.tps.each(func(t string, p int32, seqs *pidseqs) {
+				sp.Sequences = append(sp.Sequences, SnapshotSequence{t, p, seqs.seqs[seqs.at]})
+			})
+			sort.Slice(sp.Sequences, func(i, j int) bool {
+				l, r := &sp.Sequences[i], &sp.Sequences[j]
+				return l.Topic < r.Topic || l.Topic == r.Topic && l.Partition < r.Partition
+			})
+			s.ProducerIDs = append(s.ProducerIDs, sp)
+		}
+		sort.Slice(s.ProducerIDs, func(i, j int) bool { return s.ProducerIDs[i].ID < s.ProducerIDs[j].ID })
+	})
+	return s
+}
+
+// NewClusterFromSnapshot returns a new cluster, as NewCluster, with its data
+// restored from s.
+func NewClusterFromSnapshot(s *Snapshot, opts ...Opt) (*Cluster, error) {
+	c, err := NewCluster(opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.Restore(s); err != nil {
+		c.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// Restore replaces the cluster's topics, records, committed offsets, and
+// producer IDs with those in s. All existing topics are deleted (waking any
+// fetches parked on them) and all existing groups are removed, so Restore is
+// best used before clients use the cluster. This returns an error, leaving
+// the cluster unchanged, if a batch in the snapshot cannot be decoded or a
+// partition's offsets are invalid.
+func (c *Cluster) Restore(s *Snapshot) error {
+	decoded, err := decodeSnapshot(s)
+	if err != nil {
+		return err
+	}
+	c.admin(func() {
+		for t := range c.data.tps {
+			c.data.deleteTopic(t)
+			delete(c.data.treplicas, t)
+			delete(c.data.tcfgs, t)
+		}
+		for name, g := range c.groups.gs {
+			g.waitControl(g.quitOnce)
+			delete(c.groups.gs, name)
+		}
+		c.pids = nil
+
+		for _, st := range s.Topics {
+			id := uuid(st.TopicID)
+			delete(c.data.retired, id)
+			c.data.id2t[id] = st.Topic
+			c.data.t2id[st.Topic] = id
+			c.data.treplicas[st.Topic] = st.Replicas
+			if cfgs := copyConfigs(st.Configs); cfgs != nil {
+				c.data.tcfgs[st.Topic] = cfgs
+			}
+			for _, sp := range st.Partitions {
+				pd := c.data.tps.mkp(st.Topic, sp.Partition, func() *partData { return c.newPartData(st.Topic, sp.Partition) })
+				sb, _ := decoded.getp(st.Topic, sp.Partition)
+				c.restorePartition(pd, &sp, sb)
+			}
+		}
+
+		for _, sg := range s.Groups {
+			if c.groups.gs == nil {
+				c.groups.gs = make(map[string]*group)
+			}
+			g := c.groups.newGroup(sg.Group)
+			for _, sc := range sg.Commits {
+				var md *string
+				if sc.Metadata != nil {
+					m := *sc.Metadata
+					md = &m
+				}
+				g.commits.set(sc.Topic, sc.Partition, offsetCommit{sc.Offset, sc.LeaderEpoch, md})
+			}
+			c.groups.gs[sg.Group] = g
+			go g.manage(func() {})
+		}
+
+		for _, sp := range s.ProducerIDs {
+			if c.pids == nil {
+				c.pids = make(map[int64]*pidMap)
+			}
+			pm := &pidMap{id: sp.ID, epoch: sp.Epoch, lastUse: c.now()}
+			for _, seq := range sp.Sequences {
+				pm.tps.mkpDefault(seq.Topic, seq.Partition).seqs[0] = seq.NextSequence
+			}
+			c.pids[sp.ID] = pm
+		}
+	})
+	return nil
+}
+
+// copyConfigs deep copies topic configs, returning nil if there are none.
+func copyConfigs(cfgs map[string]*string) map[string]*string {
+	if len(cfgs) == 0 {
+		return nil
+	}
+	cp := make(map[string]*string, len(cfgs))
+	for k, v := range cfgs {
+		if v != nil {
+			v := *v
+			cp[k] = &v
+		} else {
+			cp[k] = nil
+		}
+	}
+	return cp
+}
+
+// snapshotBatches are the decoded batches of a snapshot partition, along with
+// copies of their encoded form.
+type snapshotBatches struct {
+	batches []kmsg.RecordBatch
+	raws    [][]byte
+}
+
+// decodeSnapshot decodes every batch in s before the cluster is modified.
+func decodeSnapshot(s *Snapshot) (tps[snapshotBatches], error) {
+	var decoded tps[snapshotBatches]
+	for _, st := range s.Topics {
+		if err := validTopicName(st.Topic); err != nil {
+			return nil, err
+		}
+		for _, sp := range st.Partitions {
+			if sp.LogStartOffset < 0 || sp.LogStartOffset > sp.HighWatermark {
+				return nil, fmt.Errorf("topic partition %s/%d: log start offset %d is outside of [0, high watermark %d]", st.Topic, sp.Partition, sp.LogStartOffset, sp.HighWatermark)
+			}
+			sb := decoded.mkpDefault(st.Topic, sp.Partition)
+			for i, raw := range sp.Batches {
+				raw = append([]byte(nil), raw...)
+				var b kmsg.RecordBatch
+				if err := b.ReadFrom(raw); err != nil {
+					return nil, fmt.Errorf("topic partition %s/%d: unable to decode batch %d: %w", st.Topic, sp.Partition, i, err)
+				}
+				if end := b.FirstOffset + int64(b.LastOffsetDelta) + 1; end > sp.HighWatermark {
+					return nil, fmt.Errorf("topic partition %s/%d: batch %d ends at offset %d, past the high watermark %d", st.Topic, sp.Partition, i, end, sp.HighWatermark)
+				}
+				sb.batches = append(sb.batches, b)
+				sb.raws = append(sb.raws, raw)
+			}
+		}
+	}
+	return decoded, nil
+}
+
+// restorePartition fills a new partition from a snapshot partition and its
+// decoded batches.
+func (c *Cluster) restorePartition(pd *partData, sp *SnapshotPartition, sb *snapshotBatches) {
+	for _, b := range c.bs {
+		if b.node == sp.Leader {
+			pd.leader = b
+			break
+		}
+	}
+	pd.epoch = sp.LeaderEpoch
+	pd.logStartOffset = sp.LogStartOffset
+	pd.highWatermark = sp.HighWatermark
+	pd.lastStableOffset = sp.HighWatermark
+	for i := range sb.batches {
+		b := sb.batches[i]
+		nbytes := len(sb.raws[i])
+		maxEarlierTimestamp := b.FirstTimestamp
+		if maxEarlierTimestamp < pd.maxTimestamp {
+			maxEarlierTimestamp = pd.maxTimestamp
+		} else {
+			pd.maxTimestamp = maxEarlierTimestamp
+		}
+		var raw []byte
+		if c.cfg.highThroughput {
+			raw = sb.raws[i]
+		}
+		pd.batches = append(pd.batches, partBatch{b, nbytes, b.PartitionLeaderEpoch, maxEarlierTimestamp, raw})
+		pd.nbytes += int64(nbytes)
+		pd.trackTxn(&b)
+	}
+}
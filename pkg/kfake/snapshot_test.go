@@ -0,0 +1,163 @@
+package kfake
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestSnapshotRestore(t *testing.T) {
+	src := newTestCluster(t, NumBrokers(1), SeedTopics(2, "t"))
+	cl := newTestClient(t, src)
+	adm := kadm.NewClient(cl)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	retention := "1000"
+	if rs, err := adm.AlterTopicConfigs(ctx, []kadm.AlterConfig{{Name: "retention.ms", Value: &retention}}, "t"); err != nil || rs[0].Err != nil {
+		t.Fatalf("alter: %v %v", err, rs)
+	}
+	if _, err := src.ProduceTo("t", 0, kgo.StringRecord("a"), kgo.StringRecord("b")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := src.ProduceTo("t", 0, kgo.StringRecord("c")); err != nil {
+		t.Fatal(err)
+	}
+	os := kadm.Offsets{}
+	os.Add(kadm.Offset{Topic: "t", Partition: 0, At: 2})
+	if _, err := adm.DeleteRecords(ctx, os); err != nil {
+		t.Fatal(err)
+	}
+	commits := kadm.Offsets{}
+	commits.AddOffset("t", 0, 3, -1)
+	if err := adm.CommitAllOffsets(ctx, "g", commits); err != nil {
+		t.Fatal(err)
+	}
+
+	// An idempotent producer writes sequence 0 to partition 1.
+	initResp, err := kmsg.NewPtrInitProducerIDRequest().RequestWith(ctx, cl.Broker(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pid, epoch := initResp.ProducerID, initResp.ProducerEpoch
+	produce := func(c *Cluster, seq int32) int16 {
+		t.Helper()
+		b := newRecordBatchFrom([]*kgo.Record{{Value: []byte("p"), Timestamp: time.Now()}})
+		b.ProducerID, b.ProducerEpoch, b.FirstSequence = pid, epoch, seq
+		if err := recompress(&b, 0); err != nil {
+			t.Fatal(err)
+		}
+		req := kmsg.NewPtrProduceRequest()
+		req.Acks = -1
+		req.TimeoutMillis = 5000
+		rt := kmsg.NewProduceRequestTopic()
+		rt.Topic = "t"
+		rp := kmsg.NewProduceRequestTopicPartition()
+		rp.Partition = 1
+		rp.Records = b.AppendTo(nil)
+		rt.Partitions = append(rt.Partitions, rp)
+		req.Topics = append(req.Topics, rt)
+		resp, err := req.RequestWith(ctx, newTestClient(t, c).Broker(0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.Topics[0].Partitions[0].ErrorCode
+	}
+	if code := produce(src, 0); code != 0 {
+		t.Fatal(kerr.ErrorForCode(code))
+	}
+
+	// Snapshots are serializable.
+	snap := src.Snapshot()
+	raw, err := json.Marshal(snap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Snapshot
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(&decoded, snap) {
+		t.Fatal("snapshot changed through a JSON round trip")
+	}
+
+	dst, err := NewClusterFromSnapshot(&decoded, NumBrokers(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dst.Close()
+	dstAdm := kadm.NewClient(newTestClient(t, dst))
+
+	var srcID, dstID [16]byte
+	src.admin(func() { srcID = src.data.t2id["t"] })
+	dst.admin(func() { dstID = dst.data.t2id["t"] })
+	if srcID != dstID {
+		t.Errorf("got topic ID %x, expected %x", dstID, srcID)
+	}
+	rs, err := dst.ReadRecords("t", 0, 2, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rs) != 1 || string(rs[0].Value) != "c" || rs[0].Offset != 2 {
+		t.Errorf("got %d restored records, expected c at offset 2", len(rs))
+	}
+	if _, err := dst.ReadRecords("t", 0, 1, 0); err == nil {
+		t.Error("reading below the restored log start offset succeeded")
+	}
+	rcs, err := dstAdm.DescribeTopicConfigs(ctx, "t")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, cfg := range rcs[0].Configs {
+		if cfg.Key == "retention.ms" && cfg.MaybeValue() != retention {
+			t.Errorf("got retention.ms %s, expected %s", cfg.MaybeValue(), retention)
+		}
+	}
+	fetched, err := dstAdm.FetchOffsets(ctx, "g")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o, _ := fetched.Lookup("t", 0); o.At != 3 {
+		t.Errorf("got committed offset %d, expected 3", o.At)
+	}
+
+	// The producer continues its sequence in the restored cluster.
+	if code := produce(dst, 2); code != kerr.OutOfOrderSequenceNumber.Code {
+		t.Errorf("produce with a gap: got %v, expected OUT_OF_ORDER_SEQUENCE_NUMBER", kerr.ErrorForCode(code))
+	}
+	if code := produce(dst, 1); code != 0 {
+		t.Errorf("produce the next sequence: %v", kerr.ErrorForCode(code))
+	}
+
+	// A snapshot that cannot be decoded leaves the cluster unchanged.
+	bad := src.Snapshot()
+	bad.Topics[0].Partitions[0].Batches[0] = []byte("corrupt")
+	bad.Topics = append(bad.Topics, SnapshotTopic{Topic: "other"})
+	if err := dst.Restore(bad); err == nil {
+		t.Error("restoring a corrupt snapshot: got no error")
+	}
+	if rs, err := dst.ReadRecords("t", 1, 0, 0); err != nil || len(rs) != 2 {
+		t.Errorf("after a failed restore: got %d records in partition 1 and err %v, expected 2", len(rs), err)
+	}
+
+	// Restoring replaces existing topics and groups.
+	if err := dst.Restore(&Snapshot{Topics: []SnapshotTopic{{Topic: "other", TopicID: [16]byte{1}, Replicas: 1, Partitions: []SnapshotPartition{{}}}}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dst.ReadRecords("t", 0, 0, 0); err == nil {
+		t.Error("t still exists after restoring a snapshot without it")
+	}
+	if _, err := dst.ReadRecords("other", 0, 0, 0); err != nil {
+		t.Errorf("other: %v", err)
+	}
+	if groups, err := dstAdm.ListGroups(ctx); err != nil || len(groups) != 0 {
+		t.Errorf("got groups %v and err %v, expected none", groups.Groups(), err)
+	}
+}
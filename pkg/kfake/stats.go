@@ -0,0 +1,262 @@
+package kfake
+
+import (
+	"net"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// Stats are counters of what the cluster has done since it started, so that
+// tests can assert on cluster activity without intercepting requests with
+// control functions. Stats can be JSON encoded, and thus can be exposed with
+// expvar:
+//
+//	expvar.Publish("kfake", expvar.Func(func() any { return c.Stats() }))
+type Stats struct {
+	Brokers map[int32]BrokerStats // Brokers are per broker counters, by node ID.
+	Topics  map[string]TopicStats // Topics are per topic counters, by topic name.
+}
+
+// BrokerStats are counters for a single broker.
+type BrokerStats struct {
+	Connections  int64           // Connections is the number of client connections accepted.
+	Requests     map[int16]int64 // Requests is the number of requests read, by request key.
+	BytesRead    int64           // BytesRead is the number of request bytes read.
+	BytesWritten int64           // BytesWritten is the number of response bytes written.
+
+	// Errors is the number of error codes in responses written, by error
+	// code. Every top level, topic level, and partition level error code
+	// is counted.
+	Errors map[int16]int64
+}
+
+// TopicStats are counters for a single topic.
+type TopicStats struct {
+	ProducedBytes   int64           // ProducedBytes is the number of record batch bytes appended by Produce requests.
+	ProducedRecords int64           // ProducedRecords is the number of records appended by Produce requests.
+	FetchedBytes    int64           // FetchedBytes is the number of record batch bytes returned by Fetch requests.
+	FetchedRecords  int64           // FetchedRecords is the number of records in batches returned by Fetch requests.
+	Errors          map[int16]int64 // Errors is the number of error codes for the topic and its partitions in responses, by error code.
+}
+
+// Info describes the cluster's current brokers and partitions.
+type Info struct {
+	ClusterID  string                             // ClusterID is the cluster's ID.
+	Controller int32                              // Controller is the node ID of the controller.
+	Brokers    []BrokerInfo                       // Brokers are the cluster's brokers, sorted by node ID.
+	Topics     map[string]map[int32]PartitionInfo // Topics are the cluster's partitions, by topic and partition.
+}
+
+// BrokerInfo describes a broker.
+type BrokerInfo struct {
+	Node   int32  // Node is the broker's node ID.
+	Addr   string // Addr is the broker's primary listener address.
+	Rack   string // Rack is the broker's rack, if any (see WithRacks).
+	Paused bool   // Paused is whether the broker is paused (see PauseNode).
+}
+
+// PartitionInfo describes a partition.
+type PartitionInfo struct {
+	Leader           int32   // Leader is the partition's leader.
+	LeaderEpoch      int32   // LeaderEpoch is the partition's leader epoch.
+	Replicas         []int32 // Replicas are the partition's replicas.
+	ISR              []int32 // ISR are the partition's in sync replicas.
+	LogStartOffset   int64   // LogStartOffset is the partition's log start offset.
+	LastStableOffset int64   // LastStableOffset is the partition's last stable offset.
+	HighWatermark    int64   // HighWatermark is the partition's high watermark.
+	Bytes            int64   // Bytes is the size of the partition's record batches.
+}
+
+type clusterStats struct {
+	mu      sync.Mutex
+	brokers map[int32]*BrokerStats
+	topics  map[string]*TopicStats
+}
+
+// Stats returns a copy of the cluster's counters.
+func (c *Cluster) Stats() Stats {
+	s := &c.stats
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stats := Stats{
+		Brokers: make(map[int32]BrokerStats, len(s.brokers)),
+		Topics:  make(map[string]TopicStats, len(s.topics)),
+	}
+	for node, bs := range s.brokers {
+		cp := *bs
+		cp.Requests = copyCounts(bs.Requests)
+		cp.Errors = copyCounts(bs.Errors)
+		stats.Brokers[node] = cp
+	}
+	for t, ts := range s.topics {
+		cp := *ts
+		cp.Errors = copyCounts(ts.Errors)
+		stats.Topics[t] = cp
+	}
+	return stats
+}
+
+// Info returns the cluster's current brokers and partitions.
+func (c *Cluster) Info() Info {
+	var info Info
+	c.admin(func() {
+		info.ClusterID = c.cfg.clusterID
+		info.Controller = c.controller.node
+		for _, b := range c.bs {
+			h, p := b.hostport("")
+			bi := BrokerInfo{
+				Node: b.node,
+				Addr: net.JoinHostPort(h, strconv.Itoa(int(p))),
+			}
+			if b.rack != nil {
+				bi.Rack = *b.rack
+			}
+			paused, _ := b.pauseState()
+			bi.Paused = paused != nil
+			info.Brokers = append(info.Brokers, bi)
+		}
+		sort.Slice(info.Brokers, func(i, j int) bool { return info.Brokers[i].Node < info.Brokers[j].Node })
+
+		info.Topics = make(map[string]map[int32]PartitionInfo)
+		c.data.tps.each(func(t string, p int32, pd *partData) {
+			ps := info.Topics[t]
+			if ps == nil {
+				ps = make(map[int32]PartitionInfo)
+				info.Topics[t] = ps
+			}
+			ps[p] = PartitionInfo{
+				Leader:           pd.leader.node,
+				LeaderEpoch:      pd.epoch,
+				Replicas:         c.replicas(t, pd),
+				ISR:              c.isr(t, pd),
+				LogStartOffset:   pd.logStartOffset,
+				LastStableOffset: pd.lastStableOffset,
+				HighWatermark:    pd.highWatermark,
+				Bytes:            pd.nbytes,
+			}
+		})
+	})
+	return info
+}
+
+func copyCounts(m map[int16]int64) map[int16]int64 {
+	cp := make(map[int16]int64, len(m))
+	for k, v := range m {
+		cp[k] = v
+	}
+	return cp
+}
+
+// The functions below record stats from connection goroutines and the
+// cluster run loop. broker and topic must be called with the lock held.
+
+func (s *clusterStats) broker(node int32) *BrokerStats {
+	if s.brokers == nil {
+		s.brokers = make(map[int32]*BrokerStats)
+	}
+	bs := s.brokers[node]
+	if bs == nil {
+		bs = &BrokerStats{
+			Requests: make(map[int16]int64),
+			Errors:   make(map[int16]int64),
+		}
+		s.brokers[node] = bs
+	}
+	return bs
+}
+
+func (s *clusterStats) topic(t string) *TopicStats {
+	if s.topics == nil {
+		s.topics = make(map[string]*TopicStats)
+	}
+	ts := s.topics[t]
+	if ts == nil {
+		ts = &TopicStats{Errors: make(map[int16]int64)}
+		s.topics[t] = ts
+	}
+	return ts
+}
+
+func (s *clusterStats) connected(node int32) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.broker(node).Connections++
+}
+
+func (s *clusterStats) request(node int32, key int16, nbytes int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	bs := s.broker(node)
+	bs.Requests[key]++
+	bs.BytesRead += int64(nbytes)
+}
+
+func (s *clusterStats) response(node int32, kresp kmsg.Response, nbytes int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	bs := s.broker(node)
+	bs.BytesWritten += int64(nbytes)
+	s.countErrors(bs, reflect.ValueOf(kresp), "")
+}
+
+func (s *clusterStats) produced(t string, nbytes int, nrecords int32) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ts := s.topic(t)
+	ts.ProducedBytes += int64(nbytes)
+	ts.ProducedRecords += int64(nrecords)
+}
+
+func (s *clusterStats) fetched(t string, nbytes int, nrecords int32) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ts := s.topic(t)
+	ts.FetchedBytes += int64(nbytes)
+	ts.FetchedRecords += int64(nrecords)
+}
+
+// countErrors counts every non-zero ErrorCode field in v, attributing codes
+// within a struct that has a Topic field to that topic.
+func (s *clusterStats) countErrors(bs *BrokerStats, v reflect.Value, topic string) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			s.countErrors(bs, v.Elem(), topic)
+		}
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return // raw bytes, such as record batches
+		}
+		for i := 0; i < v.Len(); i++ {
+			s.countErrors(bs, v.Index(i), topic)
+		}
+	case reflect.Struct:
+		if t := v.FieldByName("Topic"); t.IsValid() {
+			switch t.Kind() {
+			case reflect.String:
+				topic = t.String()
+			case reflect.Ptr:
+				if !t.IsNil() && t.Elem().Kind() == reflect.String {
+					topic = t.Elem().String()
+				}
+			}
+		}
+		for i := 0; i < v.NumField(); i++ {
+			f := v.Field(i)
+			if v.Type().Field(i).Name == "ErrorCode" && f.Kind() == reflect.Int16 {
+				if code := int16(f.Int()); code != 0 {
+					bs.Errors[code]++
+					if topic != "" {
+						s.topic(topic).Errors[code]++
+					}
+				}
+				continue
+			}
+			s.countErrors(bs, f, topic)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package kfake
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestStatsInfo(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(1), SeedTopics(2, "t"))
+	br := newTestClient(t, c).Broker(0)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if p := produceBatch(ctx, t, br, "t", 0, kgo.StringRecord("a"), kgo.StringRecord("b"), kgo.StringRecord("c")); p.ErrorCode != 0 {
+		t.Fatal(kerr.ErrorForCode(p.ErrorCode))
+	}
+	if p := produceBatch(ctx, t, br, "t", 9, kgo.StringRecord("d")); p.ErrorCode != kerr.UnknownTopicOrPartition.Code {
+		t.Fatalf("produce to a missing partition: got %v, expected UNKNOWN_TOPIC_OR_PARTITION", kerr.ErrorForCode(p.ErrorCode))
+	}
+	req := kmsg.NewPtrFetchRequest()
+	req.MaxBytes = 1 << 20
+	rt := kmsg.NewFetchRequestTopic()
+	c.admin(func() { rt.TopicID = c.data.t2id["t"] })
+	rp := kmsg.NewFetchRequestTopicPartition()
+	rp.PartitionMaxBytes = 1 << 20
+	rt.Partitions = append(rt.Partitions, rp)
+	req.Topics = append(req.Topics, rt)
+	fresp, err := req.RequestWith(ctx, br)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fetchedBytes := int64(len(fresp.Topics[0].Partitions[0].RecordBatches))
+
+	stats := c.Stats()
+	bs := stats.Brokers[0]
+	if bs.Connections < 1 || bs.BytesRead <= 0 || bs.BytesWritten <= 0 {
+		t.Errorf("got broker stats %+v, expected connections and bytes", bs)
+	}
+	if n := bs.Requests[int16(kmsg.Produce)]; n != 2 {
+		t.Errorf("got %d produce requests, expected 2", n)
+	}
+	if n := bs.Requests[int16(kmsg.Fetch)]; n != 1 {
+		t.Errorf("got %d fetch requests, expected 1", n)
+	}
+	if n := bs.Errors[kerr.UnknownTopicOrPartition.Code]; n != 1 {
+		t.Errorf("got %d broker UNKNOWN_TOPIC_OR_PARTITION errors, expected 1", n)
+	}
+	ts := stats.Topics["t"]
+	if ts.ProducedRecords != 3 || ts.ProducedBytes <= 0 {
+		t.Errorf("got %d records and %d bytes produced, expected 3 records", ts.ProducedRecords, ts.ProducedBytes)
+	}
+	if ts.FetchedRecords != 3 || ts.FetchedBytes != fetchedBytes {
+		t.Errorf("got %d records and %d bytes fetched, expected 3 records and %d bytes", ts.FetchedRecords, ts.FetchedBytes, fetchedBytes)
+	}
+	if n := ts.Errors[kerr.UnknownTopicOrPartition.Code]; n != 1 {
+		t.Errorf("got %d topic UNKNOWN_TOPIC_OR_PARTITION errors, expected 1", n)
+	}
+	if _, err := json.Marshal(stats); err != nil {
+		t.Errorf("stats are not JSON encodable: %v", err)
+	}
+
+	// Stats are copies.
+	bs.Requests[int16(kmsg.Produce)] = 100
+	if n := c.Stats().Brokers[0].Requests[int16(kmsg.Produce)]; n != 2 {
+		t.Errorf("modifying returned stats changed the cluster's stats: got %d produce requests", n)
+	}
+
+	info := c.Info()
+	if info.ClusterID == "" || info.Controller != 0 {
+		t.Errorf("got cluster ID %q and controller %d", info.ClusterID, info.Controller)
+	}
+	if len(info.Brokers) != 1 || info.Brokers[0].Addr != c.ListenAddrs()[0] || info.Brokers[0].Paused {
+		t.Errorf("got brokers %+v, expected one unpaused broker at %s", info.Brokers, c.ListenAddrs()[0])
+	}
+	if n := len(info.Topics["t"]); n != 2 {
+		t.Fatalf("got %d partitions, expected 2", n)
+	}
+	p0 := info.Topics["t"][0]
+	if p0.Leader != 0 || p0.HighWatermark != 3 || p0.LastStableOffset != 3 || p0.LogStartOffset != 0 || p0.Bytes != ts.ProducedBytes {
+		t.Errorf("got partition 0 info %+v, expected offsets 0-3 and %d bytes", p0, ts.ProducedBytes)
+	}
+	if !reflect.DeepEqual(p0.Replicas, []int32{0}) || !reflect.DeepEqual(p0.ISR, []int32{0}) {
+		t.Errorf("got replicas %v and ISR %v, expected [0]", p0.Replicas, p0.ISR)
+	}
+
+	if err := c.PauseNode(0); err != nil {
+		t.Fatal(err)
+	}
+	if !c.Info().Brokers[0].Paused {
+		t.Error("paused broker is not paused in Info")
+	}
+}
@@ -0,0 +1,147 @@
+package kfake
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+)
+
+// Client telemetry (KIP-714) is collected by the cluster rather than exported
+// anywhere. Pushed payloads are decompressed and kept in memory; they can be
+// inspected with PushedTelemetry.
+//
+// Like Kafka, a client can push once per push interval, or immediately after
+// requesting its subscription.
+
+const (
+	defTelemetryPushInterval = 5 * time.Minute
+	telemetryMaxBytes        = 1 << 20
+)
+
+// TelemetryPush is a metrics payload that a client pushed to the cluster.
+type TelemetryPush struct {
+	// ClientInstanceID is the client instance that pushed the metrics.
+	ClientInstanceID [16]byte
+	// SubscriptionID is the subscription the metrics were pushed for.
+	SubscriptionID int32
+	// Terminating is whether this was the client's final push.
+	Terminating bool
+	// Metrics is the decompressed OpenTelemetry MetricsData payload.
+	Metrics []byte
+	// At is when the cluster received the push.
+	At time.Time
+}
+
+type (
+	telemetry struct {
+		c       *Cluster
+		clients map[uuid]*telemetryClient
+		pushes  []TelemetryPush
+	}
+
+	telemetryClient struct {
+		lastGet     time.Time
+		lastPush    time.Time
+		terminating bool
+	}
+)
+
+// subscription returns the subscription ID, push interval, and requested
+// metrics for the cluster's TelemetrySubscription configuration. The ID
+// changes whenever the configuration does.
+func (t *telemetry) subscription() (int32, time.Duration, []string) {
+	interval := t.c.cfg.telemetryInterval
+	if interval <= 0 {
+		interval = defTelemetryPushInterval
+	}
+	metrics := t.c.cfg.telemetryMetrics
+	if metrics == nil {
+		metrics = []string{}
+	}
+	id := int32(hashString(strconv.Itoa(int(interval.Milliseconds())) + "|" + strings.Join(metrics, ",")))
+	return id, interval, metrics
+}
+
+func (t *telemetry) get(id uuid) (uuid, *telemetryClient) {
+	if t.clients == nil {
+		t.clients = make(map[uuid]*telemetryClient)
+	}
+	if id == noID {
+		id = randUUID()
+	}
+	tc := t.clients[id]
+	if tc == nil {
+		tc = new(telemetryClient)
+		t.clients[id] = tc
+	}
+	return id, tc
+}
+
+// canGet mirrors Kafka: a client can request its subscription if it has
+// pushed since it last requested, or if a full interval has elapsed.
+func (tc *telemetryClient) canGet(now time.Time, interval time.Duration) bool {
+	return tc.lastGet.IsZero() || tc.lastPush.After(tc.lastGet) || now.Sub(tc.lastGet) >= interval
+}
+
+// canPush mirrors Kafka: a client can push if it has requested its
+// subscription since it last pushed, or if a full interval has elapsed.
+func (tc *telemetryClient) canPush(now time.Time, interval time.Duration) bool {
+	return tc.lastGet.After(tc.lastPush) || now.Sub(tc.lastPush) >= interval
+}
+
+func (t *telemetry) push(id uuid, subID int32, terminating bool, codec int8, metrics []byte) *kerr.Error {
+	if id == noID {
+		return kerr.InvalidRequest
+	}
+	tc := t.clients[id]
+	if tc == nil {
+		return kerr.UnknownSubscriptionID
+	}
+	if tc.terminating {
+		return kerr.InvalidRequest
+	}
+	curID, interval, _ := t.subscription()
+	if subID != curID {
+		return kerr.UnknownSubscriptionID
+	}
+	now := time.Now()
+	if !terminating && !tc.canPush(now, interval) {
+		return kerr.ThrottlingQuotaExceeded
+	}
+	if len(metrics) > telemetryMaxBytes {
+		return kerr.TelemetryTooLarge
+	}
+	raw, err := decompress(codec, metrics)
+	if err != nil {
+		if errors.Is(err, errUnknownCodec) {
+			return kerr.UnsupportedCompressionType
+		}
+		return kerr.InvalidRecord
+	}
+
+	tc.lastPush = now
+	tc.terminating = terminating
+	t.pushes = append(t.pushes, TelemetryPush{
+		ClientInstanceID: id,
+		SubscriptionID:   subID,
+		Terminating:      terminating,
+		Metrics:          raw,
+		At:               now,
+	})
+	return nil
+}
+
+var acceptedTelemetryCodecs = []int8{4, 3, 2, 1} // zstd, lz4, snappy, gzip
+
+// PushedTelemetry returns all metrics payloads that clients have pushed to
+// the cluster, in the order they were received.
+func (c *Cluster) PushedTelemetry() []TelemetryPush {
+	var pushes []TelemetryPush
+	c.admin(func() {
+		pushes = append(pushes, c.telemetry.pushes...)
+	})
+	return pushes
+}
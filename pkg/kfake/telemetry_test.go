@@ -0,0 +1,93 @@
+package kfake
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestTelemetry(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(1), TelemetrySubscription(time.Hour, "org.apache.kafka.producer."))
+	br := newTestClient(t, c, withKeys(71, 72)).Broker(0)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	get := func(id [16]byte) *kmsg.GetTelemetrySubscriptionsResponse {
+		t.Helper()
+		req := kmsg.NewPtrGetTelemetrySubscriptionsRequest()
+		req.ClientInstanceID = id
+		resp, err := req.RequestWith(ctx, br)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+	push := func(id [16]byte, subID int32, terminating bool, metrics string) int16 {
+		t.Helper()
+		req := kmsg.NewPtrPushTelemetryRequest()
+		req.ClientInstanceID = id
+		req.SubscriptionID = subID
+		req.Terminating = terminating
+		req.Metrics = []byte(metrics)
+		resp, err := req.RequestWith(ctx, br)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.ErrorCode
+	}
+
+	sub := get([16]byte{})
+	if err := kerr.ErrorForCode(sub.ErrorCode); err != nil {
+		t.Fatal(err)
+	}
+	id := sub.ClientInstanceID
+	if id == [16]byte{} {
+		t.Fatal("no client instance ID was assigned")
+	}
+	if sub.PushIntervalMillis != int32(time.Hour.Milliseconds()) || len(sub.RequestedMetrics) != 1 || sub.RequestedMetrics[0] != "org.apache.kafka.producer." {
+		t.Errorf("got interval %d and metrics %v, expected the configured subscription", sub.PushIntervalMillis, sub.RequestedMetrics)
+	}
+	if resp := get(id); resp.ErrorCode != kerr.ThrottlingQuotaExceeded.Code {
+		t.Errorf("second get before pushing: got %v, expected THROTTLING_QUOTA_EXCEEDED", kerr.ErrorForCode(resp.ErrorCode))
+	}
+
+	if code := push(id, sub.SubscriptionID+1, false, "x"); code != kerr.UnknownSubscriptionID.Code {
+		t.Errorf("push to a stale subscription: got %v, expected UNKNOWN_SUBSCRIPTION_ID", kerr.ErrorForCode(code))
+	}
+	if code := push(id, sub.SubscriptionID, false, "first"); code != 0 {
+		t.Fatalf("push: %v", kerr.ErrorForCode(code))
+	}
+	if code := push(id, sub.SubscriptionID, false, "x"); code != kerr.ThrottlingQuotaExceeded.Code {
+		t.Errorf("second push within the interval: got %v, expected THROTTLING_QUOTA_EXCEEDED", kerr.ErrorForCode(code))
+	}
+
+	// Having pushed, the client can get its subscription again, and can
+	// always push a final terminating payload; nothing is accepted after.
+	if resp := get(id); resp.ErrorCode != 0 {
+		t.Errorf("get after pushing: %v", kerr.ErrorForCode(resp.ErrorCode))
+	}
+	if code := push(id, sub.SubscriptionID, true, "last"); code != 0 {
+		t.Fatalf("terminating push: %v", kerr.ErrorForCode(code))
+	}
+	if code := push(id, sub.SubscriptionID, false, "x"); code != kerr.InvalidRequest.Code {
+		t.Errorf("push after terminating: got %v, expected INVALID_REQUEST", kerr.ErrorForCode(code))
+	}
+
+	pushes := c.PushedTelemetry()
+	if len(pushes) != 2 {
+		t.Fatalf("got %d pushes, expected 2", len(pushes))
+	}
+	for i, exp := range []struct {
+		metrics     string
+		terminating bool
+	}{{"first", false}, {"last", true}} {
+		p := pushes[i]
+		if p.ClientInstanceID != id || p.SubscriptionID != sub.SubscriptionID || p.Terminating != exp.terminating || !bytes.Equal(p.Metrics, []byte(exp.metrics)) {
+			t.Errorf("push %d: got %+v, expected %q (terminating %v)", i, p, exp.metrics, exp.terminating)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package kfake
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// NewTestCluster returns a new cluster for use in a test, failing the test if
+// the cluster cannot be created. The cluster is closed when the test and all
+// its subtests complete.
+//
+// Cluster logs at LogLevelInfo and above are written with t.Logf, and any
+// LogLevelError log fails the test with t.Errorf: the cluster only logs errors
+// when its internal state is inconsistent, which indicates a kfake bug rather
+// than a client bug. The logger can be overridden with WithLogger. Before the
+// cluster is closed, CheckInvariants is run and fails the test if the
+// cluster's state is inconsistent.
+func NewTestCluster(t testing.TB, opts ...Opt) *Cluster {
+	t.Helper()
+	l := &testLogger{t: t}
+	c, err := NewCluster(append([]Opt{WithLogger(l)}, opts...)...)
+	if err != nil {
+		t.Fatalf("unable to create kfake cluster: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := c.CheckInvariants(); err != nil {
+			t.Errorf("kfake cluster invariant violated: %v", err)
+		}
+		c.Close()
+		l.stop()
+	})
+	return c
+}
+
+// testLogger logs to a test until the test's cluster is closed; logging to a
+// test after it completes panics.
+type testLogger struct {
+	t testing.TB
+
+	mu      sync.Mutex
+	stopped bool
+}
+
+func (l *testLogger) Logf(level LogLevel, msg string, args ...any) {
+	if level > LogLevelInfo {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.stopped {
+		return
+	}
+	line := fmt.Sprintf("[%s] "+msg, append([]any{level}, args...)...)
+	if level == LogLevelError {
+		l.t.Errorf("kfake: %s", line)
+		return
+	}
+	l.t.Logf("kfake: %s", line)
+}
+
+func (l *testLogger) stop() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.stopped = true
+}
+
+// NewTestClient returns a client connected to a new single broker cluster
+// that allows topics to be auto created, along with the cluster, for use in a
+// test. The client is created with the given options in addition to the
+// cluster's seed brokers. The client and cluster are closed when the test
+// and all its subtests complete.
+//
+// To customize the cluster, or to create multiple clients against one
+// cluster, use NewTestCluster and Cluster.NewTestClient.
+func NewTestClient(t testing.TB, opts ...kgo.Opt) (*kgo.Client, *Cluster) {
+	t.Helper()
+	c := NewTestCluster(t, NumBrokers(1), AllowAutoTopicCreation())
+	return c.NewTestClient(t, append(opts[:len(opts):len(opts)], kgo.AllowAutoTopicCreation())...), c
+}
+
+// NewTestClient returns a client connected to the cluster for use in a test,
+// failing the test if the client cannot be created. The client is created
+// with the given options in addition to the cluster's seed brokers, and is
+// closed when the test and all its subtests complete.
+func (c *Cluster) NewTestClient(t testing.TB, opts ...kgo.Opt) *kgo.Client {
+	t.Helper()
+	cl, err := kgo.NewClient(append([]kgo.Opt{kgo.SeedBrokers(c.ListenAddrs()...)}, opts...)...)
+	if err != nil {
+		t.Fatalf("unable to create client: %v", err)
+	}
+	t.Cleanup(cl.Close)
+	return cl
+}
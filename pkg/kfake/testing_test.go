@@ -0,0 +1,100 @@
+package kfake
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func TestNewTestClient(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cl, c := NewTestClient(t, kgo.DefaultProduceTopic("auto"))
+	if err := cl.ProduceSync(ctx, kgo.StringRecord("v")).FirstErr(); err != nil {
+		t.Fatalf("produce to an auto created topic: %v", err)
+	}
+
+	consumer := c.NewTestClient(t, kgo.ConsumeTopics("auto"), kgo.FetchMaxWait(100*time.Millisecond))
+	fs := consumer.PollFetches(ctx)
+	if err := fs.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if rs := fs.Records(); len(rs) != 1 || string(rs[0].Value) != "v" {
+		t.Errorf("consumed %v, expected the one produced record", rs)
+	}
+}
+
+// recordTB records what a test cluster logs and reports rather than failing
+// the real test.
+type recordTB struct {
+	testing.TB
+
+	mu       sync.Mutex
+	logs     []string
+	errs     []string
+	cleanups []func()
+}
+
+func (r *recordTB) Helper() {}
+
+func (r *recordTB) Logf(format string, args ...any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.logs = append(r.logs, fmt.Sprintf(format, args...))
+}
+
+func (r *recordTB) Errorf(format string, args ...any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.errs = append(r.errs, fmt.Sprintf(format, args...))
+}
+
+func (r *recordTB) Fatalf(format string, args ...any) {
+	r.TB.Fatalf(format, args...)
+}
+
+func (r *recordTB) Cleanup(fn func()) { r.cleanups = append(r.cleanups, fn) }
+
+func (r *recordTB) cleanup() {
+	for i := len(r.cleanups) - 1; i >= 0; i-- {
+		r.cleanups[i]()
+	}
+}
+
+func TestNewTestClusterLogs(t *testing.T) {
+	r := &recordTB{TB: t}
+	c := NewTestCluster(r, NumBrokers(1), SeedTopics(1, "t"))
+	r.logs = nil // cluster startup may log
+
+	c.cfg.logger.Logf(LogLevelDebug, "dropped")
+	c.cfg.logger.Logf(LogLevelInfo, "info %d", 1)
+	c.cfg.logger.Logf(LogLevelError, "error %d", 2)
+	if len(r.logs) != 1 || !strings.Contains(r.logs[0], "info 1") {
+		t.Errorf("got logs %q, expected only the info log", r.logs)
+	}
+	if len(r.errs) != 1 || !strings.Contains(r.errs[0], "error 2") {
+		t.Errorf("got errors %q, expected only the error log", r.errs)
+	}
+
+	// Closing the cluster checks its state, which we corrupt here, and
+	// stops logging to the test.
+	c.admin(func() {
+		pd, _ := c.data.tps.getp("t", 0)
+		pd.logStartOffset = pd.highWatermark + 1
+	})
+	r.errs = nil
+	r.cleanup()
+	if len(r.errs) != 1 || !strings.Contains(r.errs[0], "invariant violated") {
+		t.Errorf("got errors %q on cleanup, expected an invariant violation", r.errs)
+	}
+	c.cfg.logger.Logf(LogLevelError, "after close")
+	if len(r.errs) != 1 {
+		t.Errorf("got errors %q, expected nothing logged after cleanup", r.errs)
+	}
+}
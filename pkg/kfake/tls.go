@@ -0,0 +1,150 @@
+package kfake
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"time"
+)
+
+// TLSCerts is a throwaway certificate authority and a server certificate it
+// issued for 127.0.0.1 and localhost, for testing TLS without certificate
+// files. Use ServerConfig with the TLS or Listener options, and ClientConfig
+// with a client's dial TLS config:
+//
+//	certs, _ := kfake.NewTLSCerts()
+//	c, _ := kfake.NewCluster(kfake.TLS(certs.ServerConfig(true)))
+//	client, _ := certs.ClientCert("client")
+//	cl, _ := kgo.NewClient(
+//		kgo.SeedBrokers(c.ListenAddrs()...),
+//		kgo.DialTLSConfig(certs.ClientConfig(&client)),
+//	)
+//
+// To test certificate failures, dial with a ClientConfig from a different
+// NewTLSCerts (an unknown authority), or without a client certificate when
+// the server requires one.
+type TLSCerts struct {
+	// CA is the certificate authority that issued the server
+	// certificate and every client certificate.
+	CA *x509.Certificate
+	// CAPool is a pool containing only CA.
+	CAPool *x509.CertPool
+	// Server is the server certificate, valid for 127.0.0.1 and
+	// localhost.
+	Server tls.Certificate
+
+	caKey *ecdsa.PrivateKey
+}
+
+// NewTLSCerts returns a new certificate authority and server certificate.
+// Certificates are valid for one day.
+func NewTLSCerts() (*TLSCerts, error) {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	caTmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "kfake CA"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, caTmpl, caTmpl, &caKey.PublicKey, caKey)
+	if err != nil {
+		return nil, err
+	}
+	ca, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	t := &TLSCerts{
+		CA:     ca,
+		CAPool: x509.NewCertPool(),
+		caKey:  caKey,
+	}
+	t.CAPool.AddCert(ca)
+
+	t.Server, err = t.issue(&x509.Certificate{
+		Subject:     pkix.Name{CommonName: "kfake"},
+		DNSNames:    []string{"localhost"},
+		IPAddresses: []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// ClientCert issues a client certificate with the given common name.
+func (t *TLSCerts) ClientCert(commonName string) (tls.Certificate, error) {
+	return t.issue(&x509.Certificate{
+		Subject:     pkix.Name{CommonName: commonName},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+}
+
+// ServerConfig returns a TLS config serving the server certificate. If
+// requireClientCerts is true, clients must present a certificate issued by
+// the CA, as with Kafka's ssl.client.auth=required.
+func (t *TLSCerts) ServerConfig(requireClientCerts bool) *tls.Config {
+	cfg := &tls.Config{
+		Certificates: []tls.Certificate{t.Server},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if requireClientCerts {
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+		cfg.ClientCAs = t.CAPool
+	}
+	return cfg
+}
+
+// ClientConfig returns a TLS config that trusts the CA and, if cert is
+// non-nil, presents cert to the server.
+func (t *TLSCerts) ClientConfig(cert *tls.Certificate) *tls.Config {
+	cfg := &tls.Config{
+		RootCAs:    t.CAPool,
+		MinVersion: tls.VersionTLS12,
+	}
+	if cert != nil {
+		cfg.Certificates = []tls.Certificate{*cert}
+	}
+	return cfg
+}
+
+func (t *TLSCerts) issue(tmpl *x509.Certificate) (tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 62))
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	tmpl.SerialNumber = serial
+	tmpl.NotBefore = t.CA.NotBefore
+	tmpl.NotAfter = t.CA.NotAfter
+	tmpl.KeyUsage = x509.KeyUsageDigitalSignature
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, t.CA, &key.PublicKey, t.caKey)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	return tls.Certificate{
+		Certificate: [][]byte{der},
+		PrivateKey:  key,
+		Leaf:        leaf,
+	}, nil
+}
@@ -0,0 +1,60 @@
+package kfake
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestTLSClientCerts(t *testing.T) {
+	certs, err := NewTLSCerts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := NewTLSCerts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := certs.ClientCert("client")
+	if err != nil {
+		t.Fatal(err)
+	}
+	untrusted, err := other.ClientCert("client")
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := new(captureLogger)
+	c := newTestCluster(t, NumBrokers(1), TLS(certs.ServerConfig(true)), WithLogger(l))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	for _, test := range []struct {
+		name string
+		opts []kgo.Opt
+		ok   bool
+	}{
+		{"client certificate", []kgo.Opt{kgo.DialTLSConfig(certs.ClientConfig(&cert))}, true},
+		{"no client certificate", []kgo.Opt{kgo.DialTLSConfig(certs.ClientConfig(nil))}, false},
+		{"untrusted client certificate", []kgo.Opt{kgo.DialTLSConfig(certs.ClientConfig(&untrusted))}, false},
+		{"untrusted server", []kgo.Opt{kgo.DialTLSConfig(other.ClientConfig(&cert))}, false},
+		{"plaintext", nil, false},
+	} {
+		cl := newTestClient(t, c, append([]kgo.Opt{
+			kgo.RetryTimeout(500 * time.Millisecond),
+			kgo.RequestRetries(0),
+		}, test.opts...)...)
+		_, err := kmsg.NewPtrMetadataRequest().RequestWith(ctx, cl)
+		if ok := err == nil; ok != test.ok {
+			t.Errorf("%s: got err %v, expected success %v", test.name, err, test.ok)
+		}
+	}
+
+	// Handshake failures are logged, so that tests can see why clients
+	// could not connect.
+	if lines := l.matching("INF", "failed TLS handshake"); len(lines) == 0 {
+		t.Error("handshake failures were not logged")
+	}
+}
@@ -0,0 +1,86 @@
+package kfake
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// LatencyProfile is the latency to add to responses for a topic. Each
+// latency is sampled uniformly between its min and max; a max below its min
+// is treated as equal to the min.
+type LatencyProfile struct {
+	// ProduceMin and ProduceMax bound the latency of produce responses
+	// that include the topic.
+	ProduceMin, ProduceMax time.Duration
+	// FetchMin and FetchMax bound the latency of fetch responses that
+	// return records for the topic.
+	FetchMin, FetchMax time.Duration
+}
+
+func sampleLatency(min, max time.Duration) time.Duration {
+	if max <= min {
+		return min
+	}
+	return min + time.Duration(rand.Int63n(int64(max-min)+1))
+}
+
+// SetTopicLatency sets the latency profile for a topic, allowing one topic's
+// produces to be slow while another's are fast. Responses covering multiple
+// topics are delayed by the slowest sampled latency among them; the delay
+// starts after the request is handled, so records are visible to consumers
+// before a delayed produce response is sent, and a fetch's wait for data is
+// not shortened. For produce requests, this combines with
+// ReplicationLatency by using the larger delay. A zero profile clears the
+// topic's latency. The topic does not need to exist.
+func (c *Cluster) SetTopicLatency(topic string, p LatencyProfile) {
+	c.admin(func() {
+		if p == (LatencyProfile{}) {
+			delete(c.topicLatencies, topic)
+			return
+		}
+		if c.topicLatencies == nil {
+			c.topicLatencies = make(map[string]LatencyProfile)
+		}
+		c.topicLatencies[topic] = p
+	})
+}
+
+// topicDelay returns how long to delay a produce or fetch response per the
+// latency profiles of the topics in it.
+func (c *Cluster) topicDelay(kresp kmsg.Response) time.Duration {
+	if len(c.topicLatencies) == 0 || kresp == nil {
+		return 0
+	}
+	var max time.Duration
+	sample := func(topic string, fetch bool) {
+		p, ok := c.topicLatencies[topic]
+		if !ok {
+			return
+		}
+		d := sampleLatency(p.ProduceMin, p.ProduceMax)
+		if fetch {
+			d = sampleLatency(p.FetchMin, p.FetchMax)
+		}
+		if d > max {
+			max = d
+		}
+	}
+	switch resp := kresp.(type) {
+	case *kmsg.ProduceResponse:
+		for _, st := range resp.Topics {
+			sample(st.Topic, false)
+		}
+	case *kmsg.FetchResponse:
+		for _, st := range resp.Topics {
+			for _, sp := range st.Partitions {
+				if len(sp.RecordBatches) > 0 {
+					sample(st.Topic, true)
+					break
+				}
+			}
+		}
+	}
+	return max
+}
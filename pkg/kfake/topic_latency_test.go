@@ -0,0 +1,60 @@
+package kfake
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func TestTopicLatency(t *testing.T) {
+	const delay = 200 * time.Millisecond
+	c := newTestCluster(t, NumBrokers(1), SeedTopics(1, "slow", "fast"))
+	cl := newTestClient(t, c)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	c.SetTopicLatency("slow", LatencyProfile{ProduceMin: delay, FetchMin: delay, FetchMax: delay + 10*time.Millisecond})
+	timed := func(fn func()) time.Duration {
+		start := time.Now()
+		fn()
+		return time.Since(start)
+	}
+	produce := func(topic string) time.Duration {
+		t.Helper()
+		return timed(func() {
+			if sp := produceBatch(ctx, t, cl.Broker(0), topic, 0, kgo.StringRecord("v")); sp.ErrorCode != 0 {
+				t.Fatalf("produce to %s failed with code %d", topic, sp.ErrorCode)
+			}
+		})
+	}
+	fetch := func(topic string) time.Duration {
+		t.Helper()
+		var id [16]byte
+		c.admin(func() { id = c.data.t2id[topic] })
+		return timed(func() {
+			if rs := fetchRecords(ctx, t, cl, id, 0); len(rs) == 0 {
+				t.Fatalf("fetched no records from %s", topic)
+			}
+		})
+	}
+
+	if d := produce("slow"); d < delay {
+		t.Errorf("produce to the slow topic took %v, expected at least %v", d, delay)
+	}
+	if d := produce("fast"); d >= delay {
+		t.Errorf("produce to the fast topic took %v, expected no latency", d)
+	}
+	if d := fetch("slow"); d < delay {
+		t.Errorf("fetch from the slow topic took %v, expected at least %v", d, delay)
+	}
+	if d := fetch("fast"); d >= delay {
+		t.Errorf("fetch from the fast topic took %v, expected no latency", d)
+	}
+
+	c.SetTopicLatency("slow", LatencyProfile{})
+	if d := produce("slow"); d >= delay {
+		t.Errorf("produce after clearing the latency took %v, expected no latency", d)
+	}
+}
@@ -0,0 +1,248 @@
+package kfake
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestTopicPolicy(t *testing.T) {
+	if _, err := NewCluster(TopicPolicy("[", 1, 1, nil)); err == nil {
+		t.Error("NewCluster with an invalid policy pattern did not fail")
+	}
+
+	c := newTestCluster(t, NumBrokers(3), AllowAutoTopicCreation(),
+		TopicPolicy("changelog-*", 4, 2, map[string]string{"cleanup.policy": "compact"}),
+		TopicPolicy("*", 2, 0, nil),
+		SeedTopics(-1, "changelog-seed"),
+	)
+	adm := newTestAdmin(t, c)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	check := func(topic string, partitions, replicas int, cleanup string) {
+		t.Helper()
+		var (
+			gotParts, gotReplicas int
+			gotCleanup            string
+		)
+		c.admin(func() {
+			ps, _ := c.data.tps.gett(topic)
+			gotParts = len(ps)
+			gotReplicas = c.data.treplicas[topic]
+			gotCleanup = c.data.config(topic, "cleanup.policy")
+		})
+		if gotParts != partitions || gotReplicas != replicas || gotCleanup != cleanup {
+			t.Errorf("%s: got %d partitions, %d replicas, cleanup.policy %s; expected %d, %d, %s",
+				topic, gotParts, gotReplicas, gotCleanup, partitions, replicas, cleanup)
+		}
+	}
+
+	// Explicit partitions, replicas, and configs win over the policy.
+	del := "delete"
+	if _, err := adm.CreateTopic(ctx, -1, -1, nil, "changelog-a"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := adm.CreateTopic(ctx, 1, 1, map[string]*string{"cleanup.policy": &del}, "changelog-b"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := adm.CreateTopic(ctx, -1, -1, nil, "other"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Auto created topics use the policy too.
+	cl := newTestClient(t, c, kgo.AllowAutoTopicCreation())
+	if err := cl.ProduceSync(ctx, &kgo.Record{Topic: "changelog-auto"}).FirstErr(); err != nil {
+		t.Fatal(err)
+	}
+
+	check("changelog-seed", 4, 2, "compact")
+	check("changelog-a", 4, 2, "compact")
+	check("changelog-b", 1, 1, "delete")
+	check("other", 2, 3, "delete")
+	check("changelog-auto", 4, 2, "compact")
+}
+
+func TestTopicNameValidation(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(1), AllowAutoTopicCreation(), SeedTopics(1, "a.b"))
+	cl := newTestClient(t, c)
+	adm := newTestAdmin(t, c)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	long := strings.Repeat("x", 249)
+	for _, test := range []struct {
+		topic string
+		valid bool
+	}{
+		{"", false},
+		{".", false},
+		{"..", false},
+		{"a b", false},
+		{"é", false},
+		{long + "x", false},
+		{"a_b", false}, // collides with a.b
+		{long, true},
+		{"A-z.0_9", true},
+	} {
+		resp, err := adm.CreateTopic(ctx, 1, 1, nil, test.topic)
+		if test.valid != (err == nil) {
+			t.Errorf("creating %q: got err %v, expected valid %v", test.topic, err, test.valid)
+		}
+		if !test.valid && !errors.Is(resp.Err, kerr.InvalidTopicException) {
+			t.Errorf("creating %q: got %v, expected INVALID_TOPIC_EXCEPTION", test.topic, resp.Err)
+		}
+	}
+
+	// Metadata rejects invalid names even when auto creating.
+	req := kmsg.NewPtrMetadataRequest()
+	req.AllowAutoTopicCreation = true
+	for _, topic := range []string{"a b", "a_b"} {
+		rt := kmsg.NewMetadataRequestTopic()
+		rt.Topic = kmsg.StringPtr(topic)
+		req.Topics = append(req.Topics, rt)
+	}
+	resp, err := req.RequestWith(ctx, cl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, st := range resp.Topics {
+		if st.ErrorCode != kerr.InvalidTopicException.Code {
+			t.Errorf("metadata for %q: got %v, expected INVALID_TOPIC_EXCEPTION", *st.Topic, kerr.ErrorForCode(st.ErrorCode))
+		}
+	}
+}
+
+func TestAsyncTopicDeletion(t *testing.T) {
+	const delay = 300 * time.Millisecond
+	c := newTestCluster(t, NumBrokers(1), SeedTopics(1, "t"), AllowAutoTopicCreation(), AsyncTopicDeletion(delay))
+	cl := newTestClient(t, c)
+	adm := kadm.NewClient(cl)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	if _, err := adm.DeleteTopic(ctx, "t"); err != nil {
+		t.Fatal(err)
+	}
+
+	meta := func(autoCreate bool) int16 {
+		t.Helper()
+		req := kmsg.NewPtrMetadataRequest()
+		req.AllowAutoTopicCreation = autoCreate
+		rt := kmsg.NewMetadataRequestTopic()
+		rt.Topic = kmsg.StringPtr("t")
+		req.Topics = append(req.Topics, rt)
+		resp, err := req.RequestWith(ctx, cl.Broker(0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.Topics[0].ErrorCode
+	}
+
+	// While the topic is marked for deletion, it is not in metadata and
+	// cannot be recreated.
+	if code := meta(false); code != kerr.UnknownTopicOrPartition.Code {
+		t.Errorf("metadata for a deleting topic: got %v, expected UNKNOWN_TOPIC_OR_PARTITION", kerr.ErrorForCode(code))
+	}
+	if code := meta(true); code != kerr.LeaderNotAvailable.Code {
+		t.Errorf("auto creating a deleting topic: got %v, expected LEADER_NOT_AVAILABLE", kerr.ErrorForCode(code))
+	}
+	if _, err := adm.CreateTopic(ctx, 1, 1, nil, "t"); !errors.Is(err, kerr.TopicAlreadyExists) {
+		t.Errorf("creating a deleting topic: got %v, expected TOPIC_ALREADY_EXISTS", err)
+	}
+
+	time.Sleep(delay - time.Since(start) + 50*time.Millisecond)
+	if _, err := adm.CreateTopic(ctx, 1, 1, nil, "t"); err != nil {
+		t.Errorf("creating the topic after deletion finished: %v", err)
+	}
+}
+
+func TestRecreateTopic(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(1), SeedTopics(2, "t"))
+	cl := newTestClient(t, c)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := c.RecreateTopic("missing"); err == nil {
+		t.Error("recreating a missing topic did not fail")
+	}
+	retention := "1000"
+	if _, err := kadm.NewClient(cl).AlterTopicConfigs(ctx, []kadm.AlterConfig{{Name: "retention.ms", Value: &retention}}, "t"); err != nil {
+		t.Fatal(err)
+	}
+
+	fetch := func(id uuid) int16 {
+		t.Helper()
+		req := kmsg.NewPtrFetchRequest()
+		req.MaxBytes = 1 << 20
+		rt := kmsg.NewFetchRequestTopic()
+		rt.TopicID = id
+		rp := kmsg.NewFetchRequestTopicPartition()
+		rp.PartitionMaxBytes = 1 << 20
+		rt.Partitions = append(rt.Partitions, rp)
+		req.Topics = append(req.Topics, rt)
+		resp, err := req.RequestWith(ctx, cl.Broker(0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.Topics[0].Partitions[0].ErrorCode
+	}
+
+	// A fetch waiting on the topic is woken when it is recreated.
+	done := parkFetch(ctx, t, c, cl, 5*time.Second)
+	waitParked(ctx, t, c, 1)
+	if err := c.RecreateTopic("t"); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("parked fetch was not woken by recreating the topic")
+	}
+
+	if _, err := c.ProduceTo("t", 0, kgo.StringRecord("v")); err != nil {
+		t.Fatal(err)
+	}
+	var oldID uuid
+	c.admin(func() { oldID = c.data.t2id["t"] })
+	if err := c.RecreateTopic("t"); err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		newID      uuid
+		nparts     int
+		hwm        int64
+		configured bool
+	)
+	c.admin(func() {
+		newID = c.data.t2id["t"]
+		ps, _ := c.data.tps.gett("t")
+		nparts = len(ps)
+		hwm = ps[0].highWatermark
+		_, configured = c.data.tcfgs["t"]["retention.ms"]
+	})
+	if newID == oldID || nparts != 2 || hwm != 0 || !configured {
+		t.Errorf("recreated topic: got new ID %v, %d partitions, high watermark %d, configs kept %v; expected a new ID, 2 empty partitions, and the configs kept",
+			newID != oldID, nparts, hwm, configured)
+	}
+
+	if code := fetch(oldID); code != kerr.InconsistentTopicID.Code {
+		t.Errorf("fetch with the old ID: got %v, expected INCONSISTENT_TOPIC_ID", kerr.ErrorForCode(code))
+	}
+	if code := fetch(newID); code != 0 {
+		t.Errorf("fetch with the new ID: %v", kerr.ErrorForCode(code))
+	}
+	c.Prune()
+	if code := fetch(oldID); code != kerr.UnknownTopicID.Code {
+		t.Errorf("fetch with the old ID after pruning: got %v, expected UNKNOWN_TOPIC_ID", kerr.ErrorForCode(code))
+	}
+}
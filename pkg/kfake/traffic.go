@@ -0,0 +1,168 @@
+package kfake
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// RecordedRequest is a request received by the cluster while recording.
+type RecordedRequest struct {
+	Node     int32        // Node is the broker that received the request.
+	ClientID string       // ClientID is the client ID from the request header.
+	Request  kmsg.Request // Request is the parsed request.
+}
+
+type requestRecorder struct {
+	keys map[int16]bool // nil records all keys
+	reqs []RecordedRequest
+}
+
+// RecordRequests records every request the cluster receives for the given
+// keys, or all requests if no keys are given, until the returned function is
+// called. The stop function returns the recorded requests in the order they
+// were read off of client connections, and can be called more than once.
+//
+// Requests issued in the background, such as metadata refreshes and group
+// heartbeats, are timing dependent. To record a stable sequence, for example
+// for CompareGolden, record only the keys your test is concerned with.
+func (c *Cluster) RecordRequests(keys ...int16) (stop func() []RecordedRequest) {
+	r := new(requestRecorder)
+	if len(keys) > 0 {
+		r.keys = make(map[int16]bool, len(keys))
+		for _, k := range keys {
+			r.keys[k] = true
+		}
+	}
+	c.recordMu.Lock()
+	c.recorders = append(c.recorders, r)
+	c.recordMu.Unlock()
+
+	return func() []RecordedRequest {
+		c.recordMu.Lock()
+		defer c.recordMu.Unlock()
+		for i, rr := range c.recorders {
+			if rr == r {
+				c.recorders = append(c.recorders[:i], c.recorders[i+1:]...)
+				break
+			}
+		}
+		return r.reqs
+	}
+}
+
+// recordRequest is called from connection read loops for every parsed
+// request.
+func (c *Cluster) recordRequest(node int32, clientID string, kreq kmsg.Request) {
+	c.recordMu.Lock()
+	defer c.recordMu.Unlock()
+	for _, r := range c.recorders {
+		if r.keys == nil || r.keys[kreq.Key()] {
+			r.reqs = append(r.reqs, RecordedRequest{node, clientID, kreq})
+		}
+	}
+}
+
+// goldenVolatile are request fields that differ run to run for the same
+// client behavior; they are replaced with "*" in golden output. Fields ending
+// in Timestamp are also replaced.
+var goldenVolatile = map[string]bool{
+	"Records":               true, // batches contain timestamps and vary with compression
+	"MemberID":              true,
+	"MemberEpoch":           true,
+	"Generation":            true,
+	"ProducerID":            true,
+	"ProducerEpoch":         true,
+	"SessionID":             true,
+	"SessionEpoch":          true,
+	"TopicID":               true,
+	"ClientInstanceID":      true,
+	"ClientSoftwareVersion": true,
+	"SubscriptionID":        true,
+}
+
+// GoldenRequests serializes recorded requests into a normalized, line
+// oriented form meant to be checked in as a golden file. Each request is one
+// line of its name, version, and JSON body. Correlation IDs, client IDs, and
+// the receiving broker are not included, and fields that vary between runs
+// for the same client behavior (timestamps, record batches, producer and
+// member IDs and epochs, fetch sessions, and topic IDs) are replaced with "*".
+func GoldenRequests(reqs []RecordedRequest) []byte {
+	var buf bytes.Buffer
+	for _, r := range reqs {
+		body, err := json.Marshal(r.Request)
+		if err == nil {
+			var v any
+			if err = json.Unmarshal(body, &v); err == nil {
+				body, err = json.Marshal(normalizeGolden(v))
+			}
+		}
+		if err != nil {
+			body = []byte(fmt.Sprintf("<unable to encode: %v>", err))
+		}
+		fmt.Fprintf(&buf, "%s v%d %s\n", kmsg.NameForKey(r.Request.Key()), r.Request.GetVersion(), body)
+	}
+	return buf.Bytes()
+}
+
+func normalizeGolden(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		delete(v, "Version")
+		delete(v, "UnknownTags")
+		for k, inner := range v {
+			if goldenVolatile[k] || strings.HasSuffix(k, "Timestamp") {
+				v[k] = "*"
+				continue
+			}
+			v[k] = normalizeGolden(inner)
+		}
+	case []any:
+		for i, inner := range v {
+			v[i] = normalizeGolden(inner)
+		}
+	}
+	return v
+}
+
+// CompareGolden compares the normalized form of reqs, as returned from
+// GoldenRequests, against the golden file at path. If update is true, the
+// golden file is written instead and this returns nil; tests commonly wire
+// update to a command line flag.
+//
+// If the requests differ from the golden file, the returned error describes
+// the first differing request.
+func CompareGolden(path string, reqs []RecordedRequest, update bool) error {
+	got := GoldenRequests(reqs)
+	if update {
+		return os.WriteFile(path, got, 0o644)
+	}
+	exp, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("unable to read golden file: %w", err)
+	}
+	if bytes.Equal(exp, got) {
+		return nil
+	}
+
+	expLines := strings.Split(strings.TrimSuffix(string(exp), "\n"), "\n")
+	gotLines := strings.Split(strings.TrimSuffix(string(got), "\n"), "\n")
+	for i := 0; i < len(expLines) || i < len(gotLines); i++ {
+		var e, g string
+		if i < len(expLines) {
+			e = expLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if e != g {
+			return fmt.Errorf("request %d differs from golden file %s:\nexp: %s\ngot: %s", i+1, path, e, g)
+		}
+	}
+	return errors.New("requests differ from golden file " + path)
+}
@@ -0,0 +1,67 @@
+package kfake
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestRecordRequestsGolden(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(1), SeedTopics(1, "t"))
+	br := newTestClient(t, c, kgo.ClientID("recorded")).Broker(0)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	listOffsets := func(timestamp int64) {
+		t.Helper()
+		req := kmsg.NewPtrListOffsetsRequest()
+		rt := kmsg.NewListOffsetsRequestTopic()
+		rt.Topic = "t"
+		rp := kmsg.NewListOffsetsRequestTopicPartition()
+		rp.Timestamp = timestamp
+		rt.Partitions = append(rt.Partitions, rp)
+		req.Topics = append(req.Topics, rt)
+		if _, err := req.RequestWith(ctx, br); err != nil {
+			t.Fatal(err)
+		}
+	}
+	record := func(timestamp int64) []RecordedRequest {
+		t.Helper()
+		stop := c.RecordRequests(int16(kmsg.ListOffsets))
+		listOffsets(timestamp)
+		if _, err := kmsg.NewPtrDescribeClusterRequest().RequestWith(ctx, br); err != nil {
+			t.Fatal(err)
+		}
+		return stop()
+	}
+
+	reqs := record(time.Now().UnixMilli())
+	if len(reqs) != 1 {
+		t.Fatalf("recorded %d requests, expected only the ListOffsets request", len(reqs))
+	}
+	if r := reqs[0]; r.Node != 0 || r.ClientID != "recorded" || r.Request.Key() != int16(kmsg.ListOffsets) {
+		t.Errorf("got recorded request %+v, expected ListOffsets from recorded to node 0", r)
+	}
+	golden := string(GoldenRequests(reqs))
+	if !strings.HasPrefix(golden, "ListOffsets v") || !strings.Contains(golden, `"Timestamp":"*"`) || !strings.Contains(golden, `"Topic":"t"`) {
+		t.Errorf("unexpected golden output %q", golden)
+	}
+
+	// The same behavior at a different time matches the golden file;
+	// different behavior does not.
+	path := filepath.Join(t.TempDir(), "golden")
+	if err := CompareGolden(path, reqs, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := CompareGolden(path, record(time.Now().UnixMilli()+1000), false); err != nil {
+		t.Errorf("compare of the same requests: %v", err)
+	}
+	if err := CompareGolden(path, append(reqs, reqs...), false); err == nil || !strings.Contains(err.Error(), "request 2 differs") {
+		t.Errorf("compare of different requests: got %v, expected request 2 to differ", err)
+	}
+}
@@ -0,0 +1,146 @@
+package kfake
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// Transactional batches hold back a partition's last stable offset until
+// their producer's commit or abort marker is appended. Read committed fetches
+// only return batches below the last stable offset, and include the aborted
+// transactions overlapping the returned batches so that clients can drop
+// aborted records, as Kafka does.
+
+const (
+	attrTxnal   = 0x0010
+	attrControl = 0x0020
+)
+
+type abortedTxn struct {
+	pid   int64
+	first int64 // first offset in the transaction
+	last  int64 // offset of the abort marker
+}
+
+// trackTxn updates transaction state and the last stable offset after b was
+// appended.
+func (pd *partData) trackTxn(b *kmsg.RecordBatch) {
+	switch {
+	case b.Attributes&attrControl != 0:
+		first, open := pd.openTxns[b.ProducerID]
+		delete(pd.openTxns, b.ProducerID)
+		if open && isAbortMarker(b) {
+			pd.abortedTxns = append(pd.abortedTxns, abortedTxn{b.ProducerID, first, b.FirstOffset})
+		}
+	case b.Attributes&attrTxnal != 0:
+		if _, open := pd.openTxns[b.ProducerID]; !open {
+			if pd.openTxns == nil {
+				pd.openTxns = make(map[int64]int64)
+			}
+			pd.openTxns[b.ProducerID] = b.FirstOffset
+		}
+	}
+	pd.lastStableOffset = pd.highWatermark
+	for _, first := range pd.openTxns {
+		if first < pd.lastStableOffset {
+			pd.lastStableOffset = first
+		}
+	}
+}
+
+// abortedIn returns the aborted transactions that overlap offsets from
+// through to, inclusive, for a read committed fetch response.
+func (pd *partData) abortedIn(from, to int64) []kmsg.FetchResponseTopicPartitionAbortedTransaction {
+	aborted := []kmsg.FetchResponseTopicPartitionAbortedTransaction{}
+	for _, a := range pd.abortedTxns {
+		if a.last < from || a.first > to {
+			continue
+		}
+		sa := kmsg.NewFetchResponseTopicPartitionAbortedTransaction()
+		sa.ProducerID = a.pid
+		sa.FirstOffset = a.first
+		aborted = append(aborted, sa)
+	}
+	return aborted
+}
+
+// isAbortMarker returns whether the control batch b contains an abort marker:
+// a control record's key is an int16 version followed by an int16 type, where
+// abort is type 0 and commit is type 1.
+func isAbortMarker(b *kmsg.RecordBatch) bool {
+	if b.NumRecords < 1 || b.Attributes&0x0007 != 0 {
+		return false
+	}
+	length, n := binary.Varint(b.Records)
+	if n <= 0 || length < 0 || int64(len(b.Records)-n) < length {
+		return false
+	}
+	var r kmsg.Record
+	if err := r.ReadFrom(b.Records[:n+int(length)]); err != nil {
+		return false
+	}
+	return len(r.Key) >= 4 && r.Key[2] == 0 && r.Key[3] == 0
+}
+
+// newTxnMarkerBatch returns a control batch containing one commit or abort
+// marker, encoded as Kafka encodes markers.
+func newTxnMarkerBatch(now, pid int64, epoch int16, commit bool, coordinatorEpoch int32) kmsg.RecordBatch {
+	key := []byte{0, 0, 0, 0} // version 0, type abort
+	if commit {
+		key[3] = 1
+	}
+	marker := kmsg.EndTxnMarker{CoordinatorEpoch: coordinatorEpoch}
+	r := kmsg.Record{
+		Key:   key,
+		Value: marker.AppendTo(nil),
+	}
+	r.Length = int32(len(r.AppendTo(nil)) - 1) // a length under 64 encodes as one byte
+	records := r.AppendTo(nil)
+	b := kmsg.RecordBatch{
+		Length:               int32(49 + len(records)),
+		PartitionLeaderEpoch: -1,
+		Magic:                2,
+		Attributes:           attrTxnal | attrControl,
+		FirstTimestamp:       now,
+		MaxTimestamp:         now,
+		ProducerID:           pid,
+		ProducerEpoch:        epoch,
+		FirstSequence:        -1,
+		NumRecords:           1,
+		Records:              records,
+	}
+	b.CRC = int32(crc32.Checksum(b.AppendTo(nil)[21:], crc32c))
+	return b
+}
+
+// AppendTxnMarker appends a commit or abort marker for the producer to the
+// log of the given partition, as a transaction coordinator does when ending
+// a transaction, and returns the offset of the marker. The marker is a
+// control batch encoded exactly as Kafka encodes it.
+//
+// Transactional batches, such as those added with AppendRecordBatch, hold
+// back the partition's last stable offset until the producer's marker is
+// appended. Read committed fetches only return batches before the last
+// stable offset and include the aborted transactions overlapping the
+// returned batches. This returns an error if the partition does not exist.
+func (c *Cluster) AppendTxnMarker(topic string, partition int32, producerID int64, producerEpoch int16, commit bool) (int64, error) {
+	var (
+		offset int64
+		err    error
+	)
+	c.admin(func() {
+		pd, ok := c.data.tps.getp(topic, partition)
+		if !ok {
+			err = errors.New("topic/partition not found")
+			return
+		}
+		offset = pd.highWatermark
+		b := newTxnMarkerBatch(pd.leader.now().UnixMilli(), producerID, producerEpoch, commit, 0)
+		pd.pushBatch(int(b.Length)+12, b)
+		c.eosRecord(topic, partition, offset, &b)
+	})
+	return offset, err
+}
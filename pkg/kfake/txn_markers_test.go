@@ -0,0 +1,110 @@
+package kfake
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestTxnMarkers(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(1), SeedTopics(1, "t"))
+	cl := newTestClient(t, c)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	appendTxnal := func(pid int64, values ...string) {
+		t.Helper()
+		var rs []*kgo.Record
+		for _, v := range values {
+			rs = append(rs, kgo.StringRecord(v))
+		}
+		b := newRecordBatchFrom(rs)
+		b.Attributes |= attrTxnal
+		b.ProducerID, b.FirstSequence = pid, 0
+		if err := recompress(&b, 0); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := c.AppendRecordBatch("t", 0, b); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// fetch issues a read committed fetch, returning the last stable
+	// offset, how many batches were returned, and the aborted
+	// transactions.
+	fetch := func(from int64) (int64, int, []kmsg.FetchResponseTopicPartitionAbortedTransaction) {
+		t.Helper()
+		req := kmsg.NewPtrFetchRequest()
+		req.IsolationLevel = 1
+		req.MaxBytes = 1 << 20
+		rt := kmsg.NewFetchRequestTopic()
+		c.admin(func() { rt.TopicID = c.data.t2id["t"] })
+		rp := kmsg.NewFetchRequestTopicPartition()
+		rp.FetchOffset = from
+		rp.PartitionMaxBytes = 1 << 20
+		rt.Partitions = append(rt.Partitions, rp)
+		req.Topics = append(req.Topics, rt)
+		resp, err := req.RequestWith(ctx, cl.Broker(0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		sp := resp.Topics[0].Partitions[0]
+		if err := kerr.ErrorForCode(sp.ErrorCode); err != nil {
+			t.Fatal(err)
+		}
+		var n int
+		for raw := sp.RecordBatches; len(raw) > 0; n++ {
+			var b kmsg.RecordBatch
+			if err := b.ReadFrom(raw); err != nil {
+				t.Fatal(err)
+			}
+			raw = raw[b.Length+12:]
+		}
+		return sp.LastStableOffset, n, sp.AbortedTransactions
+	}
+
+	// An open transaction holds back the last stable offset.
+	appendTxnal(1, "a0", "a1")
+	if lso, n, _ := fetch(0); lso != 0 || n != 0 {
+		t.Errorf("with an open transaction: got last stable offset %d and %d batches, expected 0 and none", lso, n)
+	}
+
+	if offset, err := c.AppendTxnMarker("t", 0, 1, 0, false); err != nil || offset != 2 {
+		t.Fatalf("abort marker: got offset %d (err %v), expected 2", offset, err)
+	}
+	lso, n, aborted := fetch(0)
+	if lso != 3 || n != 2 {
+		t.Errorf("after aborting: got last stable offset %d and %d batches, expected 3 and 2", lso, n)
+	}
+	if len(aborted) != 1 || aborted[0].ProducerID != 1 || aborted[0].FirstOffset != 0 {
+		t.Errorf("got aborted transactions %+v, expected producer 1 from offset 0", aborted)
+	}
+
+	appendTxnal(2, "b0")
+	if _, err := c.AppendTxnMarker("t", 0, 2, 0, true); err != nil {
+		t.Fatal(err)
+	}
+	if lso, _, aborted := fetch(3); lso != 5 || len(aborted) != 0 {
+		t.Errorf("after committing: got last stable offset %d and aborted transactions %+v, expected 5 and none", lso, aborted)
+	}
+
+	// A read committed client skips the aborted records and markers.
+	consumer := newTestClient(t, c,
+		kgo.ConsumePartitions(map[string]map[int32]kgo.Offset{"t": {0: kgo.NewOffset().AtStart()}}),
+		kgo.FetchIsolationLevel(kgo.ReadCommitted()),
+	)
+	fs := consumer.PollFetches(ctx)
+	if err := fs.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if rs := fs.Records(); len(rs) != 1 || string(rs[0].Value) != "b0" || rs[0].Offset != 3 {
+		t.Errorf("consumed %v, expected only the committed record at offset 3", rs)
+	}
+
+	if _, err := c.AppendTxnMarker("t", 1, 1, 0, true); err == nil {
+		t.Error("appending a marker to a missing partition did not fail")
+	}
+}
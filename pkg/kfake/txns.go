@@ -0,0 +1,207 @@
+package kfake
+
+import (
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// The transaction coordinator. Transactional IDs map to a producer ID (the
+// same ID every time, see pids.create) and the producer's current epoch. A
+// transaction begins once a partition or group is added to it and ends with
+// EndTxn or its timeout: we write commit or abort markers to every partition
+// in the transaction, and commit or discard the transaction's pending offset
+// commits in every group. Transactions that time out are aborted and the
+// producer's epoch is bumped, fencing the producer until it reinitializes.
+//
+// TODO
+//
+// * Two phase ending: we end transactions immediately rather than going
+//   through PrepareCommit / PrepareAbort, so EndTxn never returns
+//   CONCURRENT_TRANSACTIONS
+// * AddPartitionsToTxn v4+ (broker to coordinator batching, KIP-890)
+
+const maxTxnTimeout = 15 * time.Minute // Kafka's transaction.max.timeout.ms default
+
+type (
+	txns map[string]*txn
+
+	txn struct {
+		id        string
+		pid       int64
+		epoch     int16
+		prevEpoch int16 // the epoch before a timeout bumped it, or -1
+		timeout   time.Duration
+
+		// seq increments every time a transaction begins, so that a
+		// timeout firing after the transaction ended does nothing.
+		seq      uint64
+		parts    tps[struct{}]
+		groups   map[string]struct{}
+		tTimeout *time.Timer
+		deadline time.Time // cluster clock time the transaction times out
+
+		ended      bool // whether a transaction was ended and no new one has begun
+		lastCommit bool // whether the last ended transaction committed
+	}
+)
+
+func (t *txn) ongoing() bool {
+	return len(t.parts) > 0 || len(t.groups) > 0
+}
+
+func (c *Cluster) validateTxn(creq *clientReq, txnID string) *kerr.Error {
+	if txnID == "" {
+		return kerr.InvalidRequest
+	}
+	if !c.allowedTxnID(creq, txnID, kmsg.ACLOperationWrite) {
+		return kerr.TransactionalIDAuthorizationFailed
+	}
+	if c.coordinator(txnID).node != creq.cc.b.node {
+		return kerr.NotCoordinator
+	}
+	return c.coordFault(txnID)
+}
+
+// validateTxnProducer returns the transaction for the transactional ID, or an
+// error if the producer ID or epoch do not match the ID's current producer.
+func (c *Cluster) validateTxnProducer(txnID string, pid int64, epoch int16) (*txn, *kerr.Error) {
+	t := c.txns[txnID]
+	switch {
+	case t == nil || t.pid != pid:
+		return nil, kerr.InvalidProducerIDMapping
+	case epoch < t.epoch:
+		return nil, kerr.ProducerFenced
+	case epoch > t.epoch:
+		return nil, kerr.InvalidProducerEpoch
+	}
+	return t, nil
+}
+
+// initTxn handles InitProducerID for a transactional ID, aborting any ongoing
+// transaction and bumping the producer's epoch.
+func (c *Cluster) initTxn(txnID string, timeoutMillis int32, pid int64, epoch int16) (*txn, *kerr.Error) {
+	timeout := time.Duration(timeoutMillis) * time.Millisecond
+	if timeout <= 0 || timeout > maxTxnTimeout {
+		return nil, kerr.InvalidTransactionTimeout
+	}
+	t := c.txns[txnID]
+	if pid >= 0 { // KIP-360: the producer is bumping its own epoch
+		if t == nil || t.pid != pid {
+			return nil, kerr.InvalidProducerIDMapping
+		}
+		if epoch != t.epoch && epoch != t.prevEpoch {
+			return nil, kerr.ProducerFenced
+		}
+	}
+	if t == nil {
+		if c.txns == nil {
+			c.txns = make(txns)
+		}
+		t = &txn{id: txnID}
+		c.txns[txnID] = t
+	}
+	if t.ongoing() {
+		c.endTxn(t, false)
+	}
+	id := c.pids.create(&txnID, c.now())
+	t.pid, t.epoch, t.prevEpoch = id.id, id.epoch, -1
+	t.timeout = timeout
+	t.ended = false
+	return t, nil
+}
+
+// beginTxn is called whenever a partition or group is added to a transaction,
+// starting the transaction timeout if this is the first.
+func (c *Cluster) beginTxn(t *txn) {
+	if t.ongoing() {
+		return
+	}
+	t.seq++
+	t.ended = false
+	seq := t.seq
+	t.deadline = c.now().Add(t.timeout)
+	t.tTimeout = time.AfterFunc(t.timeout, func() {
+		c.tryAdmin(func() {
+			if t.seq == seq {
+				c.expireTxn(t, c.now())
+			}
+		})
+	})
+}
+
+// expireTxn aborts the transaction if it is ongoing and has timed out by now.
+// The timer only checks the transaction; with a plugged in Clock, a
+// transaction can also time out in AdvanceTime.
+func (c *Cluster) expireTxn(t *txn, now time.Time) {
+	if !t.ongoing() || now.Before(t.deadline) {
+		return
+	}
+	c.cfg.logger.Logf(LogLevelInfo, "aborting transaction for transactional ID %s: timed out after %v", t.id, t.timeout)
+	// As in Kafka, the epoch is bumped so that the producer is fenced;
+	// the abort markers use the new epoch.
+	t.prevEpoch = t.epoch
+	t.epoch = c.pids.bump(t.pid)
+	c.endTxn(t, false)
+}
+
+// endTxn writes commit or abort markers to every partition in the
+// transaction and commits or discards the transaction's pending offset
+// commits.
+func (c *Cluster) endTxn(t *txn, commit bool) {
+	if t.tTimeout != nil {
+		t.tTimeout.Stop()
+		t.tTimeout = nil
+	}
+	t.parts.each(func(topic string, partition int32, _ *struct{}) {
+		pd, ok := c.data.tps.getp(topic, partition)
+		if !ok {
+			return
+		}
+		offset := pd.highWatermark
+		b := newTxnMarkerBatch(pd.leader.now().UnixMilli(), t.pid, t.epoch, commit, 0)
+		pd.pushBatch(int(b.Length)+12, b)
+		c.eosRecord(topic, partition, offset, &b)
+	})
+	for group := range t.groups {
+		g, ok := c.groups.gs[group]
+		if !ok {
+			continue
+		}
+		g.waitControl(func() {
+			pending := g.txnCommits[t.pid]
+			delete(g.txnCommits, t.pid)
+			if !commit || pending == nil {
+				return
+			}
+			pending.each(func(topic string, partition int32, oc *offsetCommit) {
+				g.setCommit(topic, partition, *oc)
+			})
+		})
+	}
+	t.parts = nil
+	t.groups = nil
+	t.ended = true
+	t.lastCommit = commit
+}
+
+// validateTxnProduce returns an error code if a transactional batch is from
+// a stale producer or is for a partition that is not in the producer's
+// ongoing transaction.
+func (c *Cluster) validateTxnProduce(txnID, topic string, partition int32, b *kmsg.RecordBatch) int16 {
+	if b.Attributes&attrTxnal == 0 {
+		return kerr.InvalidRecord.Code
+	}
+	t := c.txns[txnID]
+	switch {
+	case t == nil || t.pid != b.ProducerID:
+		return kerr.InvalidProducerIDMapping.Code
+	case b.ProducerEpoch != t.epoch:
+		return kerr.InvalidProducerEpoch.Code
+	}
+	if _, ok := t.parts.getp(topic, partition); !ok {
+		return kerr.InvalidTxnState.Code
+	}
+	return 0
+}
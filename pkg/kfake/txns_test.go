@@ -0,0 +1,242 @@
+package kfake
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestTxnTimeoutAborts(t *testing.T) {
+	start := time.Now()
+	c := newTestCluster(t, NumBrokers(1), SeedTopics(1, "t"), Clock(func() time.Time { return start }))
+	cl := newTestClient(t, c,
+		kgo.TransactionalID("tx"),
+		kgo.TransactionTimeout(10*time.Second),
+		kgo.DefaultProduceTopic("t"),
+	)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := cl.BeginTransaction(); err != nil {
+		t.Fatal(err)
+	}
+	if err := cl.ProduceSync(ctx, kgo.StringRecord("v")).FirstErr(); err != nil {
+		t.Fatal(err)
+	}
+
+	// With a stopped clock, only AdvanceTime can time the transaction out.
+	c.AdvanceTime(9 * time.Second)
+	if hwm := endOffset(ctx, t, c); hwm != 1 {
+		t.Fatalf("high watermark %d before the timeout, want 1 (no marker)", hwm)
+	}
+	c.AdvanceTime(time.Second)
+	if hwm := endOffset(ctx, t, c); hwm != 2 {
+		t.Fatalf("high watermark %d after the timeout, want 2 (record and abort marker)", hwm)
+	}
+
+	// The coordinator bumped the epoch, so committing is fenced.
+	if err := cl.EndTransaction(ctx, kgo.TryCommit); err == nil {
+		t.Fatal("commit of a timed out transaction succeeded")
+	}
+	consumer := newTestClient(t, c,
+		kgo.ConsumeTopics("t"),
+		kgo.FetchIsolationLevel(kgo.ReadCommitted()),
+		kgo.FetchMaxWait(100*time.Millisecond),
+	)
+	pollCtx, pollCancel := context.WithTimeout(ctx, 500*time.Millisecond)
+	defer pollCancel()
+	if fs := consumer.PollFetches(pollCtx); fs.NumRecords() > 0 {
+		t.Fatalf("read committed consumer saw %d aborted records", fs.NumRecords())
+	}
+}
+
+func endOffset(ctx context.Context, t *testing.T, c *Cluster) int64 {
+	t.Helper()
+	offsets, err := newTestAdmin(t, c).ListEndOffsets(ctx, "t")
+	if err != nil {
+		t.Fatal(err)
+	}
+	o, _ := offsets.Lookup("t", 0)
+	return o.Offset
+}
+
+func TestOffsetFetchRequireStable(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(1), SeedTopics(1, "t"))
+	br := newTestClient(t, c).Broker(0)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	initReq := kmsg.NewPtrInitProducerIDRequest()
+	initReq.TransactionalID = kmsg.StringPtr("tx")
+	initReq.TransactionTimeoutMillis = 10000
+	initResp, err := initReq.RequestWith(ctx, br)
+	if err == nil {
+		err = kerr.ErrorForCode(initResp.ErrorCode)
+	}
+	if err != nil {
+		t.Fatalf("init producer ID: %v", err)
+	}
+	pid, epoch := initResp.ProducerID, initResp.ProducerEpoch
+
+	addReq := kmsg.NewPtrAddOffsetsToTxnRequest()
+	addReq.TransactionalID, addReq.ProducerID, addReq.ProducerEpoch, addReq.Group = "tx", pid, epoch, "g"
+	addResp, err := addReq.RequestWith(ctx, br)
+	if err == nil {
+		err = kerr.ErrorForCode(addResp.ErrorCode)
+	}
+	if err != nil {
+		t.Fatalf("add offsets to txn: %v", err)
+	}
+
+	commitReq := kmsg.NewPtrTxnOffsetCommitRequest()
+	commitReq.TransactionalID, commitReq.Group, commitReq.ProducerID, commitReq.ProducerEpoch = "tx", "g", pid, epoch
+	commitReq.Generation = -1
+	rt := kmsg.NewTxnOffsetCommitRequestTopic()
+	rt.Topic = "t"
+	rp := kmsg.NewTxnOffsetCommitRequestTopicPartition()
+	rp.Offset = 5
+	rt.Partitions = append(rt.Partitions, rp)
+	commitReq.Topics = append(commitReq.Topics, rt)
+	commitResp, err := commitReq.RequestWith(ctx, br)
+	if err == nil {
+		err = kerr.ErrorForCode(commitResp.Topics[0].Partitions[0].ErrorCode)
+	}
+	if err != nil {
+		t.Fatalf("txn offset commit: %v", err)
+	}
+
+	fetch := func(requireStable bool) (int64, int16) {
+		t.Helper()
+		req := kmsg.NewPtrOffsetFetchRequest()
+		req.RequireStable = requireStable
+		rg := kmsg.NewOffsetFetchRequestGroup()
+		rg.Group = "g"
+		rt := kmsg.NewOffsetFetchRequestGroupTopic()
+		rt.Topic = "t"
+		rt.Partitions = []int32{0}
+		rg.Topics = append(rg.Topics, rt)
+		req.Groups = append(req.Groups, rg)
+		resp, err := req.RequestWith(ctx, br)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sg := resp.Groups[0]
+		if err := kerr.ErrorForCode(sg.ErrorCode); err != nil {
+			t.Fatalf("offset fetch: %v", err)
+		}
+		sp := sg.Topics[0].Partitions[0]
+		return sp.Offset, sp.ErrorCode
+	}
+
+	// The pending commit is invisible to unstable fetches, and fails
+	// stable fetches until the transaction ends.
+	if o, code := fetch(false); o != -1 || code != 0 {
+		t.Errorf("unstable fetch of a pending commit: got offset %d, %v, expected -1 and no error", o, kerr.ErrorForCode(code))
+	}
+	if _, code := fetch(true); code != kerr.UnstableOffsetCommit.Code {
+		t.Errorf("stable fetch of a pending commit: got %v, expected UNSTABLE_OFFSET_COMMIT", kerr.ErrorForCode(code))
+	}
+
+	endReq := kmsg.NewPtrEndTxnRequest()
+	endReq.TransactionalID, endReq.ProducerID, endReq.ProducerEpoch, endReq.Commit = "tx", pid, epoch, true
+	endResp, err := endReq.RequestWith(ctx, br)
+	if err == nil {
+		err = kerr.ErrorForCode(endResp.ErrorCode)
+	}
+	if err != nil {
+		t.Fatalf("end txn: %v", err)
+	}
+	if o, code := fetch(true); o != 5 || code != 0 {
+		t.Errorf("stable fetch after commit: got offset %d, %v, expected 5 and no error", o, kerr.ErrorForCode(code))
+	}
+}
+
+func TestTxnFencing(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(1), SeedTopics(1, "t"))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	first := newTestClient(t, c, kgo.TransactionalID("tx"), kgo.DefaultProduceTopic("t"))
+	if err := first.BeginTransaction(); err != nil {
+		t.Fatal(err)
+	}
+	if err := first.ProduceSync(ctx, kgo.StringRecord("a")).FirstErr(); err != nil {
+		t.Fatal(err)
+	}
+
+	// A new producer with the same transactional ID aborts the ongoing
+	// transaction and fences the first producer.
+	second := newTestClient(t, c, kgo.TransactionalID("tx"), kgo.DefaultProduceTopic("t"))
+	if err := second.BeginTransaction(); err != nil {
+		t.Fatal(err)
+	}
+	if err := second.ProduceSync(ctx, kgo.StringRecord("b")).FirstErr(); err != nil {
+		t.Fatal(err)
+	}
+	if err := second.EndTransaction(ctx, kgo.TryCommit); err != nil {
+		t.Fatal(err)
+	}
+	if err := first.EndTransaction(ctx, kgo.TryCommit); err == nil {
+		t.Error("commit of a fenced producer succeeded")
+	}
+
+	rs, err := c.ReadRecords("t", 0, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rs) != 2 {
+		t.Errorf("read uncommitted: got %d records, expected 2", len(rs))
+	}
+	consumer := newTestClient(t, c,
+		kgo.ConsumeTopics("t"),
+		kgo.FetchIsolationLevel(kgo.ReadCommitted()),
+	)
+	fs := consumer.PollFetches(ctx)
+	if err := fs.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if recs := fs.Records(); len(recs) != 1 || string(recs[0].Value) != "b" {
+		t.Errorf("read committed: got %d records, expected only the committed b", len(recs))
+	}
+
+	// Transactional produces must be to partitions added to the
+	// transaction.
+	br := consumer.Broker(0)
+	initReq := kmsg.NewPtrInitProducerIDRequest()
+	initReq.TransactionalID = kmsg.StringPtr("raw")
+	initReq.TransactionTimeoutMillis = 10000
+	initResp, err := initReq.RequestWith(ctx, br)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := kerr.ErrorForCode(initResp.ErrorCode); err != nil {
+		t.Fatal(err)
+	}
+	b := newRecordBatchFrom([]*kgo.Record{{Value: []byte("v"), Timestamp: time.Now()}})
+	b.Attributes |= attrTxnal
+	b.ProducerID, b.ProducerEpoch = initResp.ProducerID, initResp.ProducerEpoch
+	if err := recompress(&b, 0); err != nil {
+		t.Fatal(err)
+	}
+	req := kmsg.NewPtrProduceRequest()
+	req.TransactionID = kmsg.StringPtr("raw")
+	req.Acks = -1
+	req.TimeoutMillis = 5000
+	rt := kmsg.NewProduceRequestTopic()
+	rt.Topic = "t"
+	rp := kmsg.NewProduceRequestTopicPartition()
+	rp.Records = b.AppendTo(nil)
+	rt.Partitions = append(rt.Partitions, rp)
+	req.Topics = append(req.Topics, rt)
+	resp, err := req.RequestWith(ctx, br)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code := resp.Topics[0].Partitions[0].ErrorCode; code != kerr.InvalidTxnState.Code {
+		t.Errorf("produce to a partition not in the transaction: got %v, expected INVALID_TXN_STATE", kerr.ErrorForCode(code))
+	}
+}
@@ -0,0 +1,70 @@
+package kfake
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// Version faults reject requests at versions that ApiVersions advertises, as
+// buggy brokers and proxies in front of brokers sometimes do, so that client
+// downgrade and retry logic can be tested.
+
+// RejectAdvertisedVersions rejects requests for key at any version above
+// maxVersion with UNSUPPORTED_VERSION, while ApiVersions continues to
+// advertise the cluster's normal version range. A negative maxVersion rejects
+// every version. Calling this again for the same key replaces the prior
+// fault.
+//
+// If the response for the key has a top level error code, the response has
+// only that error code set (for batched FindCoordinator requests, every
+// coordinator has the error). Otherwise, the connection is closed, as Kafka
+// does when it cannot handle a request version.
+func (c *Cluster) RejectAdvertisedVersions(key, maxVersion int16) {
+	c.admin(func() {
+		if c.versionFaults == nil {
+			c.versionFaults = make(map[int16]int16)
+		}
+		c.versionFaults[key] = maxVersion
+	})
+}
+
+// ClearRejectedVersions removes all faults added with
+// RejectAdvertisedVersions.
+func (c *Cluster) ClearRejectedVersions() {
+	c.admin(func() {
+		c.versionFaults = nil
+	})
+}
+
+// rejectVersion returns an UNSUPPORTED_VERSION response (or a connection
+// closing error) if the request's version is being rejected.
+func (c *Cluster) rejectVersion(kreq kmsg.Request) (kmsg.Response, error, bool) {
+	max, ok := c.versionFaults[kreq.Key()]
+	if !ok || kreq.GetVersion() <= max {
+		return nil, nil, false
+	}
+	kresp := kreq.ResponseKind()
+	kresp.SetVersion(kreq.GetVersion())
+
+	// FindCoordinator v4+ batches keys and has no top level error code.
+	if req, ok := kreq.(*kmsg.FindCoordinatorRequest); ok && req.Version >= 4 {
+		resp := kresp.(*kmsg.FindCoordinatorResponse)
+		for _, key := range req.CoordinatorKeys {
+			sc := kmsg.NewFindCoordinatorResponseCoordinator()
+			sc.Key = key
+			sc.ErrorCode = kerr.UnsupportedVersion.Code
+			resp.Coordinators = append(resp.Coordinators, sc)
+		}
+		return resp, nil, true
+	}
+
+	ec := reflect.ValueOf(kresp).Elem().FieldByName("ErrorCode")
+	if !ec.IsValid() || ec.Kind() != reflect.Int16 {
+		return nil, fmt.Errorf("%s v%d is rejected", kmsg.NameForKey(kreq.Key()), kreq.GetVersion()), true
+	}
+	ec.SetInt(int64(kerr.UnsupportedVersion.Code))
+	return kresp, nil, true
+}
@@ -0,0 +1,81 @@
+package kfake
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+	"github.com/twmb/franz-go/pkg/kversion"
+)
+
+func TestRejectAdvertisedVersions(t *testing.T) {
+	c := newTestCluster(t, NumBrokers(1))
+	br := newTestClient(t, c).Broker(0)
+	v := kversion.Stable()
+	v.SetMaxKeyVersion(int16(kmsg.DescribeCluster), 0)
+	oldBr := newTestClient(t, c, kgo.MaxVersions(v)).Broker(0)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	describe := func(br *kgo.Broker) int16 {
+		t.Helper()
+		resp, err := kmsg.NewPtrDescribeClusterRequest().RequestWith(ctx, br)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.ErrorCode
+	}
+
+	// Versions above the max fail with UNSUPPORTED_VERSION while
+	// ApiVersions still advertises them.
+	c.RejectAdvertisedVersions(int16(kmsg.DescribeCluster), 0)
+	if code := describe(br); code != kerr.UnsupportedVersion.Code {
+		t.Errorf("got %v, expected UNSUPPORTED_VERSION", kerr.ErrorForCode(code))
+	}
+	if code := describe(oldBr); code != 0 {
+		t.Errorf("requesting at the max version: %v", kerr.ErrorForCode(code))
+	}
+	resp, err := kmsg.NewPtrApiVersionsRequest().RequestWith(ctx, br)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, k := range resp.ApiKeys {
+		if k.ApiKey == int16(kmsg.DescribeCluster) && k.MaxVersion == 0 {
+			t.Error("ApiVersions advertised the lowered max version")
+		}
+	}
+
+	// Batched FindCoordinator fails every key.
+	c.RejectAdvertisedVersions(int16(kmsg.FindCoordinator), -1)
+	find := kmsg.NewPtrFindCoordinatorRequest()
+	find.CoordinatorKeys = []string{"a", "b"}
+	fresp, err := find.RequestWith(ctx, br)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fresp.Coordinators) != 2 {
+		t.Fatalf("got %d coordinators, expected 2", len(fresp.Coordinators))
+	}
+	for _, sc := range fresp.Coordinators {
+		if sc.ErrorCode != kerr.UnsupportedVersion.Code {
+			t.Errorf("coordinator %s: got %v, expected UNSUPPORTED_VERSION", sc.Key, kerr.ErrorForCode(sc.ErrorCode))
+		}
+	}
+
+	// Responses without a top level error code close the connection.
+	c.RejectAdvertisedVersions(int16(kmsg.ListOffsets), -1)
+	if _, err := kmsg.NewPtrListOffsetsRequest().RequestWith(ctx, br); err == nil {
+		t.Error("rejected list offsets request succeeded")
+	}
+
+	c.ClearRejectedVersions()
+	if code := describe(br); code != 0 {
+		t.Errorf("after clearing faults: %v", kerr.ErrorForCode(code))
+	}
+	if _, err := kmsg.NewPtrListOffsetsRequest().RequestWith(ctx, br); err != nil {
+		t.Errorf("list offsets after clearing faults: %v", err)
+	}
+}
@@ -0,0 +1,235 @@
+package kgo
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// BatchConsumer consumes in a group and hands each polled batch of records,
+// per partition, to a callback. A batch is committed only after the callback
+// succeeds; if the callback fails, the batch is retried with the client's
+// RetryBackoffFn until it succeeds. Batches for all partitions in a poll are
+// processed concurrently, and the next poll waits until every batch is done,
+// so a partition that fails repeatedly blocks consuming further records.
+//
+// When partitions are revoked in a rebalance, the revoke waits for in flight
+// batches for the revoked partitions to finish their current attempt. A batch
+// that succeeds is committed before the revoke completes; a batch that fails
+// is not retried and is left uncommitted for the partition's next owner. When
+// partitions are lost, the contexts of in flight batches for the lost
+// partitions are canceled and nothing is committed.
+//
+// Autocommitting is always disabled for a BatchConsumer.
+type BatchConsumer struct {
+	cl *Client
+	fn func(context.Context, FetchTopicPartition) error
+
+	mu       sync.Mutex
+	inflight map[string]map[int32]*batchRun
+}
+
+type batchRun struct {
+	ctx      context.Context
+	cancel   context.CancelFunc
+	stop     chan struct{} // closed on revoke: finish the current attempt but do not retry
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+// NewBatchConsumer is exactly the same as NewClient, but requires a consumer
+// group and wraps the client's OnPartitionsRevoked / OnPartitionsLost to
+// drain in flight batches, as described in the BatchConsumer documentation.
+// Any autocommit options are overridden to disable autocommitting.
+//
+// The fn callback is called with one partition's records at a time, and may
+// be called concurrently for different partitions. The context passed to fn
+// is canceled if Run's context is canceled or the partition is lost.
+func NewBatchConsumer(fn func(context.Context, FetchTopicPartition) error, opts ...Opt) (*BatchConsumer, error) {
+	b := &BatchConsumer{
+		fn:       fn,
+		inflight: make(map[string]map[int32]*batchRun),
+	}
+
+	var noGroup error
+
+	opts = append(opts, groupOpt{func(cfg *cfg) {
+		if cfg.group == "" {
+			cfg.seedBrokers = nil // force a validation error
+			noGroup = errors.New("missing required group")
+			return
+		}
+
+		cfg.autocommitDisable = true
+		cfg.autocommitGreedy = false
+		cfg.autocommitMarks = false
+		cfg.setRevoked, cfg.setLost = true, true
+
+		userRevoked := cfg.onRevoked
+		cfg.onRevoked = func(ctx context.Context, cl *Client, revoked map[string][]int32) {
+			b.drain(revoked, false)
+			if userRevoked != nil {
+				userRevoked(ctx, cl, revoked)
+			}
+		}
+
+		userLost := cfg.onLost
+		cfg.onLost = func(ctx context.Context, cl *Client, lost map[string][]int32) {
+			b.drain(lost, true)
+			if userLost != nil {
+				userLost(ctx, cl, lost)
+			} else if userRevoked != nil {
+				userRevoked(ctx, cl, lost)
+			}
+		}
+	}})
+
+	cl, err := NewClient(opts...)
+	if err != nil {
+		if noGroup != nil {
+			err = noGroup
+		}
+		return nil, err
+	}
+	b.cl = cl
+	return b, nil
+}
+
+// Client returns the underlying client that this batch consumer wraps. This
+// can be useful for functions that require a client, such as raw requests. The
+// returned client should not be used to poll or commit (leave that to the
+// BatchConsumer).
+func (b *BatchConsumer) Client() *Client {
+	return b.cl
+}
+
+// Close is a wrapper around Client.Close, with the exact same semantics.
+// Refer to that function's documentation.
+//
+// This function must be called to leave the group before shutting down.
+func (b *BatchConsumer) Close() {
+	b.cl.Close()
+}
+
+// Run polls and processes batches until the context is canceled or the client
+// is closed, returning the context's error or ErrClientClosed. Fetch errors
+// are logged and otherwise ignored.
+//
+// If Run returns while a batch is failing, the batch is left uncommitted, but
+// the client has already consumed past it. The client should be closed rather
+// than running again, so that the batch is reprocessed from the last commit.
+func (b *BatchConsumer) Run(ctx context.Context) error {
+	for {
+		fetches := b.cl.PollFetches(ctx)
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if b.cl.ctx.Err() != nil {
+			return ErrClientClosed
+		}
+		fetches.EachError(func(t string, p int32, err error) {
+			b.cl.cfg.logger.Log(LogLevelWarn, "batch consumer fetch error", "topic", t, "partition", p, "err", err)
+		})
+
+		var wg sync.WaitGroup
+		fetches.EachPartition(func(p FetchTopicPartition) {
+			if len(p.Records) == 0 {
+				return
+			}
+			run := b.start(ctx, p.Topic, p.Partition)
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer b.finish(p.Topic, p.Partition, run)
+				b.process(run, p)
+			}()
+		})
+		wg.Wait()
+	}
+}
+
+func (b *BatchConsumer) start(ctx context.Context, t string, p int32) *batchRun {
+	run := &batchRun{
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	run.ctx, run.cancel = context.WithCancel(ctx)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	ps := b.inflight[t]
+	if ps == nil {
+		ps = make(map[int32]*batchRun)
+		b.inflight[t] = ps
+	}
+	ps[p] = run
+	return run
+}
+
+func (b *BatchConsumer) finish(t string, p int32, run *batchRun) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.inflight[t], p)
+	if len(b.inflight[t]) == 0 {
+		delete(b.inflight, t)
+	}
+	run.cancel()
+	close(run.done)
+}
+
+func (b *BatchConsumer) process(run *batchRun, p FetchTopicPartition) {
+	for attempt := 1; ; attempt++ {
+		err := b.fn(run.ctx, p)
+		if err == nil {
+			if err := b.cl.CommitRecords(run.ctx, p.Records[len(p.Records)-1]); err != nil {
+				b.cl.cfg.logger.Log(LogLevelWarn, "batch consumer unable to commit processed batch", "topic", p.Topic, "partition", p.Partition, "err", err)
+			}
+			return
+		}
+
+		b.cl.cfg.logger.Log(LogLevelWarn, "batch consumer batch processing failed",
+			"topic", p.Topic,
+			"partition", p.Partition,
+			"attempt", attempt,
+			"err", err,
+		)
+
+		backoff := time.NewTimer(b.cl.cfg.retryBackoff(attempt))
+		select {
+		case <-run.ctx.Done():
+			backoff.Stop()
+			return
+		case <-run.stop:
+			backoff.Stop()
+			b.cl.cfg.logger.Log(LogLevelInfo, "batch consumer partition revoked, leaving failed batch uncommitted", "topic", p.Topic, "partition", p.Partition)
+			return
+		case <-backoff.C:
+		}
+	}
+}
+
+// drain stops retrying, and optionally cancels, in flight batches for the
+// given partitions and waits for them to finish.
+func (b *BatchConsumer) drain(parts map[string][]int32, cancel bool) {
+	var waits []chan struct{}
+	b.mu.Lock()
+	for t, ps := range parts {
+		for _, p := range ps {
+			run := b.inflight[t][p]
+			if run == nil {
+				continue
+			}
+			run.stopOnce.Do(func() { close(run.stop) })
+			if cancel {
+				run.cancel()
+			}
+			waits = append(waits, run.done)
+		}
+	}
+	b.mu.Unlock()
+
+	for _, done := range waits {
+		<-done
+	}
+}
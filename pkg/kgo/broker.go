@@ -169,6 +169,25 @@ type broker struct {
 	reqs ringReq
 	// dead is an atomic so a backed up reqs cannot block broker stoppage.
 	dead atomicBool
+
+	// The following fields are used for BrokerStates.
+	inflight    atomicI32 // requests issued that have not yet been promised
+	lastSuccess atomicI64 // nanosec of the last successfully read response
+	errMu       sync.Mutex
+	lastErr     error
+	lastErrAt   time.Time
+}
+
+// noteErr saves err as the last connection error for the broker, unless the
+// error is due to the client closing or a request being canceled.
+func (b *broker) noteErr(err error) {
+	if errors.Is(err, ErrClientClosed) || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return
+	}
+	b.errMu.Lock()
+	defer b.errMu.Unlock()
+	b.lastErr = err
+	b.lastErrAt = time.Now()
 }
 
 // brokerVersions is loaded once (and potentially a few times concurrently if
@@ -252,6 +271,7 @@ func (b *broker) do(
 	req kmsg.Request,
 	promise func(kmsg.Response, error),
 ) {
+	b.inflight.Add(1)
 	pr := promisedReq{ctx, req, promise, time.Now()}
 
 	first, dead := b.reqs.push(pr)
@@ -259,10 +279,17 @@ func (b *broker) do(
 	if first {
 		go b.handleReqs(pr)
 	} else if dead {
-		promise(nil, errChosenBrokerDead)
+		b.promise(pr.promise, nil, errChosenBrokerDead)
 	}
 }
 
+// promise calls the promise of a request issued with do, after which the
+// request is no longer in flight.
+func (b *broker) promise(promise func(kmsg.Response, error), resp kmsg.Response, err error) {
+	b.inflight.Add(-1)
+	promise(resp, err)
+}
+
 // waitResp runs a req, waits for the resp and returns the resp and err.
 func (b *broker) waitResp(ctx context.Context, req kmsg.Request) (kmsg.Response, error) {
 	var resp kmsg.Response
@@ -281,7 +308,7 @@ func (b *broker) handleReqs(pr promisedReq) {
 	var more, dead bool
 start:
 	if dead {
-		pr.promise(nil, errChosenBrokerDead)
+		b.promise(pr.promise, nil, errChosenBrokerDead)
 	} else {
 		b.handleReq(pr)
 	}
@@ -303,11 +330,12 @@ start:
 			// It is rare, but it is possible that the broker has
 			// an immediate issue on a new connection. We retry
 			// once.
+			b.noteErr(err)
 			if isRetryableBrokerErr(err) && !retriedOnNewConnection {
 				retriedOnNewConnection = true
 				goto start
 			}
-			pr.promise(nil, err)
+			b.promise(pr.promise, nil, err)
 			return
 		}
 	}
@@ -315,7 +343,7 @@ start:
 	v := b.loadVersions()
 
 	if int(req.Key()) > v.len() || b.cl.cfg.maxVersions != nil && !b.cl.cfg.maxVersions.HasKey(req.Key()) {
-		pr.promise(nil, errUnknownRequestKey)
+		b.promise(pr.promise, nil, errUnknownRequestKey)
 		return
 	}
 
@@ -323,7 +351,7 @@ start:
 	// versions. If the version for this request is negative, we
 	// know the broker cannot handle this request.
 	if v.versions[0] >= 0 && v.versions[req.Key()] < 0 {
-		pr.promise(nil, errBrokerTooOld)
+		b.promise(pr.promise, nil, errBrokerTooOld)
 		return
 	}
 
@@ -351,7 +379,7 @@ start:
 	if b.cl.cfg.minVersions != nil {
 		minVersion, _ = b.cl.cfg.minVersions.LookupMaxKeyVersion(req.Key())
 		if minVersion > -1 && version < minVersion {
-			pr.promise(nil, errBrokerTooOld)
+			b.promise(pr.promise, nil, errBrokerTooOld)
 			return
 		}
 	}
@@ -359,7 +387,7 @@ start:
 	req.SetVersion(version) // always go for highest version
 	setVersion := req.GetVersion()
 	if minVersion > -1 && setVersion < minVersion {
-		pr.promise(nil, fmt.Errorf("request key %d version returned %d below the user defined min of %d", req.Key(), setVersion, minVersion))
+		b.promise(pr.promise, nil, fmt.Errorf("request key %d version returned %d below the user defined min of %d", req.Key(), setVersion, minVersion))
 		return
 	}
 	if version < setVersion {
@@ -367,7 +395,7 @@ start:
 		// high, we need to fail with errBrokerTooOld. The broker wants
 		// an old version, we want a high version. We rely on this
 		// error in backcompat request sharding.
-		pr.promise(nil, errBrokerTooOld)
+		b.promise(pr.promise, nil, errBrokerTooOld)
 		return
 	}
 
@@ -384,13 +412,14 @@ start:
 		// For KIP-368.
 		cxn.cl.cfg.logger.Log(LogLevelDebug, "sasl expiry limit reached, reauthenticating", "broker", logID(cxn.b.meta.NodeID))
 		if err := cxn.sasl(); err != nil {
+			b.noteErr(err)
 			cxn.die()
 			if errors.Is(err, kerr.SaslAuthenticationFailed) && !retriedOnNewConnection {
 				cxn.cl.cfg.logger.Log(LogLevelDebug, "sasl reauth failed, retrying once on new connection", "broker", logID(cxn.b.meta.NodeID), "err", err)
 				retriedOnNewConnection = true
 				goto start
 			}
-			pr.promise(nil, err)
+			b.promise(pr.promise, nil, err)
 			return
 		}
 	}
@@ -403,7 +432,7 @@ start:
 	// loop. We could be more precise with error tracking, though.
 	select {
 	case <-pr.ctx.Done():
-		pr.promise(nil, pr.ctx.Err())
+		b.promise(pr.promise, nil, pr.ctx.Err())
 		return
 	default:
 	}
@@ -441,14 +470,15 @@ start:
 	corrID, bytesWritten, writeWait, timeToWrite, readEnqueue, writeErr := cxn.writeRequest(pr.ctx, pr.enqueue, req)
 
 	if writeErr != nil {
-		pr.promise(nil, writeErr)
+		b.noteErr(writeErr)
+		b.promise(pr.promise, nil, writeErr)
 		cxn.die()
 		cxn.hookWriteE2E(req.Key(), bytesWritten, writeWait, timeToWrite, writeErr)
 		return
 	}
 
 	if isNoResp {
-		pr.promise(noResp, nil)
+		b.promise(pr.promise, noResp, nil)
 		cxn.hookWriteE2E(req.Key(), bytesWritten, writeWait, timeToWrite, writeErr)
 		return
 	}
@@ -470,16 +500,20 @@ start:
 }
 
 func (cxn *brokerCxn) hookWriteE2E(key int16, bytesWritten int, writeWait, timeToWrite time.Duration, writeErr error) {
+	e2e := BrokerE2E{
+		BytesWritten: bytesWritten,
+		WriteWait:    writeWait,
+		TimeToWrite:  timeToWrite,
+		WriteErr:     writeErr,
+	}
 	cxn.cl.cfg.hooks.each(func(h Hook) {
 		if h, ok := h.(HookBrokerE2E); ok {
-			h.OnBrokerE2E(cxn.b.meta, key, BrokerE2E{
-				BytesWritten: bytesWritten,
-				WriteWait:    writeWait,
-				TimeToWrite:  timeToWrite,
-				WriteErr:     writeErr,
-			})
+			h.OnBrokerE2E(cxn.b.meta, key, e2e)
 		}
 	})
+	if m := cxn.cl.collector(); m != nil {
+		m.brokerE2E(cxn.b.meta, key, e2e)
+	}
 }
 
 // bufPool is used to reuse issued-request buffers across writes to brokers.
@@ -631,6 +665,12 @@ func (b *broker) connect(ctx context.Context) (net.Conn, error) {
 			h.OnBrokerConnect(b.meta, since, conn, err)
 		}
 	})
+	if m := b.cl.collector(); m != nil {
+		m.brokerConnect(b.meta, err)
+	}
+	if t := b.cl.telemetry; t != nil && err == nil {
+		t.brokerConnected()
+	}
 	if err != nil {
 		if !errors.Is(err, ErrClientClosed) && !errors.Is(err, context.Canceled) && !strings.Contains(err.Error(), "operation was canceled") {
 			if errors.Is(err, io.EOF) {
@@ -711,15 +751,16 @@ func (cxn *brokerCxn) init(isProduceCxn bool) error {
 }
 
 func (cxn *brokerCxn) requestAPIVersions() error {
-	maxVersion := int16(3)
+	maxVersion := kmsg.NewPtrApiVersionsRequest().MaxVersion()
 
 	// If the user configured a max versions, we check that the key exists
 	// before entering this function. Thus, we expect exists to be true,
 	// but we still doubly check it for sanity (as well as userMax, which
 	// can only be non-negative based off of LookupMaxKeyVersion's API).
+	// We never use a version higher than we know how to issue.
 	if cxn.cl.cfg.maxVersions != nil {
 		userMax, exists := cxn.cl.cfg.maxVersions.LookupMaxKeyVersion(18) // 18 == api versions
-		if exists && userMax >= 0 {
+		if exists && userMax >= 0 && userMax < maxVersion {
 			maxVersion = userMax
 		}
 	}
@@ -1219,22 +1260,26 @@ func (cxn *brokerCxn) readResponse(
 ) ([]byte, error) {
 	bytesRead, buf, readWait, timeToRead, readErr := cxn.readConn(ctx, timeout, readEnqueue)
 
+	e2e := BrokerE2E{
+		BytesWritten: bytesWritten,
+		BytesRead:    bytesRead,
+		WriteWait:    writeWait,
+		TimeToWrite:  timeToWrite,
+		ReadWait:     readWait,
+		TimeToRead:   timeToRead,
+		ReadErr:      readErr,
+	}
 	cxn.cl.cfg.hooks.each(func(h Hook) {
 		if h, ok := h.(HookBrokerRead); ok {
 			h.OnBrokerRead(cxn.b.meta, key, bytesRead, readWait, timeToRead, readErr)
 		}
 		if h, ok := h.(HookBrokerE2E); ok {
-			h.OnBrokerE2E(cxn.b.meta, key, BrokerE2E{
-				BytesWritten: bytesWritten,
-				BytesRead:    bytesRead,
-				WriteWait:    writeWait,
-				TimeToWrite:  timeToWrite,
-				ReadWait:     readWait,
-				TimeToRead:   timeToRead,
-				ReadErr:      readErr,
-			})
+			h.OnBrokerE2E(cxn.b.meta, key, e2e)
 		}
 	})
+	if m := cxn.cl.collector(); m != nil {
+		m.brokerE2E(cxn.b.meta, key, e2e)
+	}
 	if logger := cxn.cl.cfg.logger; logger.Level() >= LogLevelDebug {
 		logger.Log(LogLevelDebug, fmt.Sprintf("read %s v%d", kmsg.NameForKey(key), version), "broker", logID(cxn.b.meta.NodeID), "bytes_read", bytesRead, "read_wait", readWait, "time_to_read", timeToRead, "err", readErr)
 	}
@@ -1268,6 +1313,9 @@ func (cxn *brokerCxn) closeConn() {
 			h.OnBrokerDisconnect(cxn.b.meta, cxn.conn)
 		}
 	})
+	if m := cxn.cl.collector(); m != nil {
+		m.brokerDisconnect(cxn.b.meta)
+	}
 	cxn.conn.Close()
 	close(cxn.deadCh)
 }
@@ -1289,7 +1337,7 @@ func (cxn *brokerCxn) waitResp(pr promisedResp) {
 	if first {
 		go cxn.handleResps(pr)
 	} else if dead {
-		pr.promise(nil, errChosenBrokerDead)
+		cxn.b.promise(pr.promise, nil, errChosenBrokerDead)
 		cxn.hookWriteE2E(pr.resp.Key(), pr.bytesWritten, pr.writeWait, pr.timeToWrite, errChosenBrokerDead)
 	}
 }
@@ -1435,7 +1483,7 @@ func (cxn *brokerCxn) handleResps(pr promisedResp) {
 	var more, dead bool
 start:
 	if dead {
-		pr.promise(nil, errChosenBrokerDead)
+		cxn.b.promise(pr.promise, nil, errChosenBrokerDead)
 		cxn.hookWriteE2E(pr.resp.Key(), pr.bytesWritten, pr.writeWait, pr.timeToWrite, errChosenBrokerDead)
 	} else {
 		cxn.handleResp(pr)
@@ -1471,12 +1519,14 @@ func (cxn *brokerCxn) handleResp(pr promisedResp) {
 				}
 			}
 		}
-		pr.promise(nil, err)
+		cxn.b.noteErr(err)
+		cxn.b.promise(pr.promise, nil, err)
 		cxn.die()
 		return
 	}
 
 	cxn.successes++
+	cxn.b.lastSuccess.Store(time.Now().UnixNano())
 	readErr := pr.resp.ReadFrom(rawResp)
 
 	// If we had no error, we read the response successfully.
@@ -1499,9 +1549,12 @@ func (cxn *brokerCxn) handleResp(pr promisedResp) {
 						h.OnBrokerThrottle(cxn.b.meta, time.Duration(millis)*time.Millisecond, throttlesAfterResp)
 					}
 				})
+				if m := cxn.cl.collector(); m != nil {
+					m.brokerThrottle(cxn.b.meta, time.Duration(millis)*time.Millisecond)
+				}
 			}
 		}
 	}
 
-	pr.promise(pr.resp, readErr)
+	cxn.b.promise(pr.promise, pr.resp, readErr)
 }
@@ -0,0 +1,92 @@
+package kgo
+
+import "sync"
+
+// bufferLimits tracks the bytes buffered per topic and per partition for
+// MaxBufferedBytesPerTopic and MaxBufferedBytesPerPartition. Bytes are added
+// when a record is buffered into a partition and removed when the record's
+// batch is finished or failed.
+//
+// Entries are deleted once nothing is buffered, so that topics or partitions
+// that are no longer produced to do not accumulate.
+type bufferLimits struct {
+	maxTopic     int64
+	maxPartition int64
+
+	mu     sync.Mutex
+	topics map[string]*topicBuffered
+}
+
+type topicBuffered struct {
+	bytes int64
+	parts map[int32]int64
+}
+
+func newBufferLimits(cfg *cfg) *bufferLimits {
+	if cfg.maxBufferedBytesPerTopic <= 0 && cfg.maxBufferedBytesPerPartition <= 0 {
+		return nil
+	}
+	return &bufferLimits{
+		maxTopic:     cfg.maxBufferedBytesPerTopic,
+		maxPartition: cfg.maxBufferedBytesPerPartition,
+		topics:       make(map[string]*topicBuffered),
+	}
+}
+
+// add adds size bytes to the topic and partition, returning false without
+// adding anything if either would exceed its limit. As with MaxBufferedBytes,
+// a record is always allowed if nothing is buffered, so that a single record
+// larger than the limit does not fail forever.
+func (l *bufferLimits) add(topic string, partition int32, size int64) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	t := l.topics[topic]
+	if t == nil {
+		t = &topicBuffered{parts: make(map[int32]int64)}
+		l.topics[topic] = t
+	}
+	p := t.parts[partition]
+	if l.maxTopic > 0 && t.bytes > 0 && t.bytes+size > l.maxTopic ||
+		l.maxPartition > 0 && p > 0 && p+size > l.maxPartition {
+		if t.bytes == 0 {
+			delete(l.topics, topic)
+		}
+		return false
+	}
+	t.bytes += size
+	t.parts[partition] = p + size
+	return true
+}
+
+func (l *bufferLimits) remove(topic string, partition int32, size int64) {
+	if size == 0 {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	t := l.topics[topic]
+	if t == nil {
+		return
+	}
+	t.bytes -= size
+	if p := t.parts[partition] - size; p > 0 {
+		t.parts[partition] = p
+	} else {
+		delete(t.parts, partition)
+	}
+	if t.bytes <= 0 {
+		delete(l.topics, topic)
+	}
+}
+
+// removeRecords removes the size of all records in a batch from the batch's
+// topic and partition.
+func (l *bufferLimits) removeRecords(topic string, partition int32, records []promisedRec) {
+	var size int64
+	for i := range records {
+		size += records[i].userSize()
+	}
+	l.remove(topic, partition, size)
+}
@@ -0,0 +1,64 @@
+package kgo
+
+import "testing"
+
+func TestBufferLimits(t *testing.T) {
+	if l := newBufferLimits(&cfg{}); l != nil {
+		t.Fatal("expected no limits by default")
+	}
+
+	l := newBufferLimits(&cfg{maxBufferedBytesPerTopic: 10, maxBufferedBytesPerPartition: 6})
+	for i, step := range []struct {
+		add       bool
+		topic     string
+		partition int32
+		size      int64
+		exp       bool
+	}{
+		{true, "a", 0, 100, true}, // allowed when nothing is buffered
+		{true, "a", 1, 1, false},  // topic is over its limit
+		{false, "a", 0, 100, true},
+		{true, "a", 0, 5, true},
+		{true, "a", 0, 2, false}, // partition would be at 7
+		{true, "a", 1, 5, true},  // topic at 10
+		{true, "a", 2, 1, false}, // topic would be at 11
+		{true, "b", 0, 6, true},  // topics are independent
+		{false, "a", 1, 5, true},
+		{true, "a", 2, 5, true},
+		{false, "a", 0, 5, true},
+		{false, "a", 2, 5, true},
+		{false, "b", 0, 6, true},
+	} {
+		if !step.add {
+			l.remove(step.topic, step.partition, step.size)
+			continue
+		}
+		if got := l.add(step.topic, step.partition, step.size); got != step.exp {
+			t.Errorf("#%d: adding %d to %s/%d: got %v, exp %v", i, step.size, step.topic, step.partition, got, step.exp)
+		}
+	}
+	if len(l.topics) != 0 {
+		t.Errorf("expected nothing tracked once everything is removed, have %v", l.topics)
+	}
+
+	// Removing a batch's records removes their combined size, and a
+	// failed add does not leave an empty topic behind.
+	recs := []promisedRec{
+		{Record: &Record{Key: []byte("k"), Value: []byte("vv")}},
+		{Record: &Record{Value: []byte("v"), Headers: []RecordHeader{{Key: "h", Value: []byte("hv")}}}},
+	}
+	l.add("c", 0, 7)
+	l.removeRecords("c", 0, recs)
+	if len(l.topics) != 0 {
+		t.Errorf("expected nothing tracked after removing records, have %v", l.topics)
+	}
+	l = newBufferLimits(&cfg{maxBufferedBytesPerPartition: 1})
+	l.add("d", 0, 1)
+	if l.add("d", 0, 1) {
+		t.Fatal("unexpected add over the partition limit")
+	}
+	l.remove("d", 0, 1)
+	if len(l.topics) != 0 {
+		t.Errorf("expected nothing tracked, have %v", l.topics)
+	}
+}
@@ -60,6 +60,9 @@ type Client struct {
 	reqFormatter  *kmsg.RequestFormatter
 	connTimeouter connTimeouter
 
+	metrics   *clientMetrics   // non-nil if CollectMetrics is used or telemetry is enabled
+	telemetry *clientTelemetry // non-nil unless DisableMetricsPush is used
+
 	bufPool bufPool // for to brokers to share underlying reusable request buffers
 	prsPool prsPool // for sinks to reuse []promisedNumberedRecord
 
@@ -78,8 +81,9 @@ type Client struct {
 	producer producer
 	consumer consumer
 
-	compressor   *compressor
-	decompressor *decompressor
+	compressor       *compressor
+	topicCompressors map[string]*compressor // per-topic overrides of compressor
+	decompressor     *decompressor
 
 	coordinatorsMu sync.Mutex
 	coordinators   map[coordinatorKey]*coordinatorLoad
@@ -117,7 +121,7 @@ type hostport struct {
 
 // ValidateOpts returns an error if the options are invalid.
 func ValidateOpts(opts ...Opt) error {
-	_, _, _, err := validateCfg(opts...)
+	_, _, _, _, err := validateCfg(opts...)
 	return err
 }
 
@@ -136,23 +140,37 @@ func parseSeeds(addrs []string) ([]hostport, error) {
 // This function validates the configuration and returns a few things that we
 // initialize while validating. The difference between this and NewClient
 // initialization is all NewClient initialization is infallible.
-func validateCfg(opts ...Opt) (cfg, []hostport, *compressor, error) {
+func validateCfg(opts ...Opt) (cfg, []hostport, *compressor, map[string]*compressor, error) {
 	cfg := defaultCfg()
 	for _, opt := range opts {
 		opt.apply(&cfg)
 	}
 	if err := cfg.validate(); err != nil {
-		return cfg, nil, nil, err
+		return cfg, nil, nil, nil, err
 	}
 	seeds, err := parseSeeds(cfg.seedBrokers)
 	if err != nil {
-		return cfg, nil, nil, err
+		return cfg, nil, nil, nil, err
 	}
-	compressor, err := newCompressor(cfg.compression...)
+	defCompressor, err := newCompressor(cfg.compression...)
 	if err != nil {
-		return cfg, nil, nil, err
+		return cfg, nil, nil, nil, err
 	}
-	return cfg, seeds, compressor, nil
+	var topicCompressors map[string]*compressor
+	for topic, codecs := range cfg.topicCompression {
+		c, err := newCompressor(codecs...)
+		if err != nil {
+			return cfg, nil, nil, nil, fmt.Errorf("invalid compression for topic %q: %w", topic, err)
+		}
+		if topicCompressors == nil {
+			topicCompressors = make(map[string]*compressor)
+		}
+		topicCompressors[topic] = c
+	}
+	if err := validateZstdDicts(cfg.zstdDicts); err != nil {
+		return cfg, nil, nil, nil, err
+	}
+	return cfg, seeds, defCompressor, topicCompressors, nil
 }
 
 func namefn(fn any) string {
@@ -236,6 +254,14 @@ func (cl *Client) OptValues(opt any) []any {
 		return []any{"", false}
 	case namefn(SoftwareNameAndVersion):
 		return []any{cfg.softwareName, cfg.softwareVersion}
+	case namefn(CollectMetrics):
+		return []any{cfg.collectMetrics}
+	case namefn(DisableMetricsPush):
+		return []any{cfg.disableMetricsPush}
+	case namefn(KeySerde):
+		return []any{cfg.keySerde}
+	case namefn(ValueSerde):
+		return []any{cfg.valueSerde}
 	case namefn(WithLogger):
 		if _, wrapped := cfg.logger.(*wrappedLogger); wrapped {
 			return []any{cfg.logger.(*wrappedLogger).inner}
@@ -251,6 +277,8 @@ func (cl *Client) OptValues(opt any) []any {
 		return []any{cfg.dialTLS}
 	case namefn(DialTLS):
 		return []any{cfg.dialTLS != nil}
+	case namefn(DialProxy):
+		return []any{cfg.dialProxy}
 	case namefn(SeedBrokers):
 		return []any{cfg.seedBrokers}
 	case namefn(MaxVersions):
@@ -294,12 +322,28 @@ func (cl *Client) OptValues(opt any) []any {
 		return []any{cfg.maxProduceInflight}
 	case namefn(ProducerBatchCompression):
 		return []any{cfg.compression}
+	case namefn(ProducerTopicCompression):
+		return []any{cfg.topicCompression}
 	case namefn(ProducerBatchMaxBytes):
 		return []any{cfg.maxRecordBatchBytes}
 	case namefn(MaxBufferedRecords):
 		return []any{cfg.maxBufferedRecords}
 	case namefn(MaxBufferedBytes):
 		return []any{cfg.maxBufferedBytes}
+	case namefn(MaxBufferedBytesPerTopic):
+		return []any{cfg.maxBufferedBytesPerTopic}
+	case namefn(MaxBufferedBytesPerPartition):
+		return []any{cfg.maxBufferedBytesPerPartition}
+	case namefn(ProduceBufferWatermarks):
+		return []any{cfg.bufferHigh, cfg.bufferLow, cfg.onBufferHigh, cfg.onBufferLow}
+	case namefn(MaxProduceRecordsPerSecond):
+		return []any{cfg.produceRecordsRate}
+	case namefn(MaxProduceBytesPerSecond):
+		return []any{cfg.produceBytesRate}
+	case namefn(DeliveryReports):
+		return []any{cfg.deliveryReports}
+	case namefn(ProducerInterceptors):
+		return []any{cfg.producerInterceptors}
 	case namefn(RecordPartitioner):
 		return []any{cfg.partitioner}
 	case namefn(ProduceRequestTimeout):
@@ -325,6 +369,8 @@ func (cl *Client) OptValues(opt any) []any {
 		return []any{"", false}
 	case namefn(TransactionTimeout):
 		return []any{cfg.txnTimeout}
+	case namefn(RecoverTransactions):
+		return []any{cfg.txnRecover, cfg.onTxnRecover}
 
 	case namefn(ConsumePartitions):
 		return []any{cfg.partitions}
@@ -332,8 +378,12 @@ func (cl *Client) OptValues(opt any) []any {
 		return []any{cfg.preferLagFn}
 	case namefn(ConsumeRegex):
 		return []any{cfg.regex}
+	case namefn(ConsumeRegexDiscoveryInterval):
+		return []any{cfg.regexDiscoveryInterval}
 	case namefn(ConsumeResetOffset):
 		return []any{cfg.resetOffset}
+	case namefn(OnOffsetOutOfRange):
+		return []any{cfg.onOffsetOutOfRange}
 	case namefn(ConsumeTopics):
 		return []any{cfg.topics}
 	case namefn(DisableFetchSessions):
@@ -354,8 +404,18 @@ func (cl *Client) OptValues(opt any) []any {
 		return []any{cfg.maxConcurrentFetches}
 	case namefn(Rack):
 		return []any{cfg.rack}
+	case namefn(HedgeFetches):
+		return []any{cfg.hedgeFetches}
+	case namefn(DecompressionCodecs):
+		return []any{cfg.codecs}
+	case namefn(ZstdDecompressionDicts):
+		return []any{cfg.zstdDicts}
+	case namefn(ConsumerInterceptors):
+		return []any{cfg.consumerInterceptors}
 	case namefn(KeepRetryableFetchErrors):
 		return []any{cfg.keepRetryableFetchErrors}
+	case namefn(ConsumerLagInterval):
+		return []any{cfg.consumerLagInterval}
 
 	case namefn(AdjustFetchOffsetsFn):
 		return []any{cfg.adjustOffsetsBeforeAssign}
@@ -377,6 +437,10 @@ func (cl *Client) OptValues(opt any) []any {
 		return []any{cfg.autocommitGreedy}
 	case namefn(GroupProtocol):
 		return []any{cfg.protocol}
+	case namefn(ConsumerGroupProtocol):
+		return []any{cfg.groupProtocol}
+	case namefn(ServerAssignor):
+		return []any{cfg.serverAssignor}
 	case namefn(HeartbeatInterval):
 		return []any{cfg.heartbeatInterval}
 	case namefn(InstanceID):
@@ -416,7 +480,7 @@ func (cl *Client) OptValues(opt any) []any {
 // NewClient also launches a goroutine which periodically updates the cached
 // topic metadata.
 func NewClient(opts ...Opt) (*Client, error) {
-	cfg, seeds, compressor, err := validateCfg(opts...)
+	cfg, seeds, compressor, topicCompressors, err := validateCfg(opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -426,7 +490,8 @@ func NewClient(opts ...Opt) (*Client, error) {
 			switch key {
 			case ((*kmsg.JoinGroupRequest)(nil)).Key(),
 				((*kmsg.SyncGroupRequest)(nil)).Key(),
-				((*kmsg.HeartbeatRequest)(nil)).Key():
+				((*kmsg.HeartbeatRequest)(nil)).Key(),
+				((*kmsg.ConsumerGroupHeartbeatRequest)(nil)).Key():
 				return cfg.sessionTimeout
 			}
 			return 30 * time.Second
@@ -436,7 +501,11 @@ func NewClient(opts ...Opt) (*Client, error) {
 	if cfg.dialFn == nil {
 		dialer := &net.Dialer{Timeout: cfg.dialTimeout}
 		cfg.dialFn = dialer.DialContext
+		if cfg.dialProxy != nil {
+			cfg.dialFn = proxyDialer(cfg.dialProxy, dialer)
+		}
 		if cfg.dialTLS != nil {
+			dial := cfg.dialFn
 			cfg.dialFn = func(ctx context.Context, network, host string) (net.Conn, error) {
 				c := cfg.dialTLS.Clone()
 				if c.ServerName == "" {
@@ -446,10 +515,31 @@ func NewClient(opts ...Opt) (*Client, error) {
 					}
 					c.ServerName = server
 				}
-				return (&tls.Dialer{
-					NetDialer: dialer,
-					Config:    c,
-				}).DialContext(ctx, network, host)
+				if cfg.dialProxy == nil {
+					return (&tls.Dialer{
+						NetDialer: dialer,
+						Config:    c,
+					}).DialContext(ctx, network, host)
+				}
+
+				// tls.Dialer can only wrap a net.Dialer, so we
+				// handshake over the tunnel ourselves, within the
+				// same dial timeout.
+				if cfg.dialTimeout > 0 {
+					var cancel func()
+					ctx, cancel = context.WithTimeout(ctx, cfg.dialTimeout)
+					defer cancel()
+				}
+				conn, err := dial(ctx, network, host)
+				if err != nil {
+					return nil, err
+				}
+				tlsConn := tls.Client(conn, c)
+				if err := tlsConn.HandshakeContext(ctx); err != nil {
+					conn.Close()
+					return nil, err
+				}
+				return tlsConn, nil
 			}
 		}
 	}
@@ -482,8 +572,9 @@ func NewClient(opts ...Opt) (*Client, error) {
 		bufPool: newBufPool(),
 		prsPool: newPrsPool(),
 
-		compressor:   compressor,
-		decompressor: newDecompressor(),
+		compressor:       compressor,
+		topicCompressors: topicCompressors,
+		decompressor:     newDecompressor(cfg.zstdDecompressionDicts(), cfg.decompressionCodecs()...),
 
 		coordinators: make(map[coordinatorKey]*coordinatorLoad),
 
@@ -493,6 +584,15 @@ func NewClient(opts ...Opt) (*Client, error) {
 		metadone:             make(chan struct{}),
 	}
 
+	if cfg.collectMetrics {
+		cl.metrics = newClientMetrics()
+	}
+	// If our max versions do not have GetTelemetrySubscriptions, we could
+	// never push, so we do not start telemetry at all.
+	if !cfg.disableMetricsPush && (cfg.maxVersions == nil || cfg.maxVersions.HasKey(int16(kmsg.GetTelemetrySubscriptions))) {
+		cl.telemetry = newClientTelemetry(cl)
+	}
+
 	// Before we start any goroutines below, we must notify any interested
 	// hooks of our existence.
 	cl.cfg.hooks.each(func(h Hook) {
@@ -517,6 +617,15 @@ func NewClient(opts ...Opt) (*Client, error) {
 	cl.seeds.Store(seedBrokers)
 	go cl.updateMetadataLoop()
 	go cl.reapConnectionsLoop()
+	if cl.consumer.lag != nil {
+		go cl.consumer.lagLoop()
+	}
+	if cl.telemetry != nil {
+		go cl.telemetry.loop()
+	}
+	if cl.cfg.regex && cl.cfg.regexDiscoveryInterval > 0 && cl.consumer.consuming() {
+		go cl.consumer.regexDiscoveryLoop()
+	}
 
 	return cl, nil
 }
@@ -1030,6 +1139,11 @@ func (cl *Client) close(ctx context.Context) (rerr error) {
 	wg.Wait()
 	sessCloseCancel()
 
+	// Before brokers are stopped, we push our final client telemetry.
+	if cl.telemetry != nil {
+		cl.telemetry.close(ctx)
+	}
+
 	// Now we kill the client context and all brokers, ensuring all
 	// requests fail. This will finish all producer callbacks and
 	// stop the metadata loop.
@@ -1224,12 +1338,18 @@ start:
 						"response_error", retryErr,
 					)
 					if r.cl.waitTries(ctx, backoff) {
+						if m := r.cl.collector(); m != nil {
+							m.requestRetry()
+						}
 						next, nextErr = r.br()
 						goto start
 					}
 				} else if r.cl.shouldRetryNext(tries, err) {
 					next, nextErr = r.br()
 					if next != br && r.cl.waitTries(ctx, backoff) {
+						if m := r.cl.collector(); m != nil {
+							m.requestRetry()
+						}
 						goto start
 					}
 				}
@@ -1850,6 +1970,8 @@ func (cl *Client) handleCoordinatorReq(ctx context.Context, req kmsg.Request) Re
 		return cl.handleCoordinatorReqSimple(ctx, coordinatorTypeGroup, t.Group, req)
 	case *kmsg.OffsetDeleteRequest:
 		return cl.handleCoordinatorReqSimple(ctx, coordinatorTypeGroup, t.Group, req)
+	case *kmsg.ConsumerGroupHeartbeatRequest:
+		return cl.handleCoordinatorReqSimple(ctx, coordinatorTypeGroup, t.Group, req)
 	}
 }
 
@@ -1912,6 +2034,8 @@ func (cl *Client) handleReqWithCoordinator(
 			code = t.ErrorCode
 		case *kmsg.SyncGroupResponse:
 			code = t.ErrorCode
+		case *kmsg.ConsumerGroupHeartbeatResponse:
+			code = t.ErrorCode
 		}
 
 		// ListGroups, OffsetFetch, DeleteGroups, DescribeGroups, and
@@ -1964,6 +2088,94 @@ func (cl *Client) SeedBrokers() []*Broker {
 	return bs
 }
 
+// BrokerState is a snapshot of the client's connections to a broker, as
+// returned from BrokerStates.
+type BrokerState struct {
+	// Meta is the broker's metadata. Seed brokers have very negative node
+	// IDs; see NodeName.
+	Meta BrokerMetadata
+
+	// OpenConnections is the number of open connections to the broker.
+	// The client uses up to five: one each for produce requests, fetch
+	// requests, group join and sync requests, requests with timeouts, and
+	// everything else. Idle connections are closed per ConnIdleTimeout,
+	// so zero open connections is not necessarily a problem.
+	OpenConnections int
+
+	// InflightRequests is the number of requests issued to the broker
+	// that have not yet completed, including requests waiting to be
+	// written.
+	InflightRequests int
+
+	// LastError is the last error encountered while dialing, initializing
+	// a connection, writing to, or reading from the broker, and
+	// LastErrorTime is when it happened. Errors due to requests being
+	// canceled are not tracked, nor are errors returned inside successful
+	// responses.
+	LastError     error
+	LastErrorTime time.Time
+
+	// LastSuccess is when the client last successfully read a response
+	// from the broker.
+	LastSuccess time.Time
+
+	_ struct{} // allow us to add fields later
+}
+
+// Healthy returns whether the broker is likely reachable: there has been no
+// error since the last successful response.
+func (s BrokerState) Healthy() bool {
+	return s.LastError == nil || s.LastSuccess.After(s.LastErrorTime)
+}
+
+// BrokerStates returns the state of the client's connections to every seed
+// and discovered broker, which is useful to surface unreachable brokers in
+// application health checks. Brokers that the client has not needed to talk
+// to have no connections and no last success nor error.
+func (cl *Client) BrokerStates() []BrokerState {
+	cl.brokersMu.RLock()
+	seeds := cl.loadSeeds()
+	brokers := make([]*broker, 0, len(seeds)+len(cl.brokers))
+	brokers = append(brokers, seeds...)
+	brokers = append(brokers, cl.brokers...)
+	cl.brokersMu.RUnlock()
+
+	states := make([]BrokerState, 0, len(brokers))
+	for _, b := range brokers {
+		states = append(states, b.state())
+	}
+	return states
+}
+
+func (b *broker) state() BrokerState {
+	s := BrokerState{
+		Meta:             b.meta,
+		InflightRequests: int(b.inflight.Load()),
+	}
+	if last := b.lastSuccess.Load(); last != 0 {
+		s.LastSuccess = time.Unix(0, last)
+	}
+
+	b.errMu.Lock()
+	s.LastError, s.LastErrorTime = b.lastErr, b.lastErrAt
+	b.errMu.Unlock()
+
+	b.reapMu.Lock()
+	for _, cxn := range []*brokerCxn{
+		b.cxnNormal,
+		b.cxnProduce,
+		b.cxnFetch,
+		b.cxnGroup,
+		b.cxnSlow,
+	} {
+		if cxn != nil && !cxn.dead.Load() {
+			s.OpenConnections++
+		}
+	}
+	b.reapMu.Unlock()
+	return s
+}
+
 // UpdateSeedBrokers updates the client's list of seed brokers. Over the course
 // of a long period of time, your might replace all brokers that you originally
 // specified as seeds. This command allows you to replace the client's list of
@@ -2274,6 +2486,9 @@ func (cl *Client) handleShardedReq(ctx context.Context, req kmsg.Request) ([]Res
 					// top where the broker is loaded. This is the case on
 					// requests where the original request is split to
 					// dedicated brokers; we do not want to re-shard that.
+					if m := cl.collector(); m != nil {
+						m.requestRetry()
+					}
 					if !reshardable {
 						l.Log(LogLevelDebug, "sharded request failed, reissuing without resharding", "req", kmsg.Key(myIssue.req.Key()).Name(), "time_since_start", time.Since(start), "tries", try.tries, "err", err)
 						goto start
@@ -3605,9 +3820,10 @@ func (cl *writeTxnMarkersSharder) shard(ctx context.Context, kreq kmsg.Request,
 	}
 
 	type pidEpochCommit struct {
-		pid    int64
-		epoch  int16
-		commit bool
+		pid              int64
+		epoch            int16
+		commit           bool
+		coordinatorEpoch int32
 	}
 
 	brokerReqs := make(map[int32]map[pidEpochCommit]map[string][]int32)
@@ -3645,6 +3861,7 @@ func (cl *writeTxnMarkersSharder) shard(ctx context.Context, kreq kmsg.Request,
 			marker.ProducerID,
 			marker.ProducerEpoch,
 			marker.Committed,
+			marker.CoordinatorEpoch,
 		}
 		for _, topic := range marker.Topics {
 			t := topic.Topic
@@ -3680,6 +3897,7 @@ func (cl *writeTxnMarkersSharder) shard(ctx context.Context, kreq kmsg.Request,
 			rm.ProducerID = pec.pid
 			rm.ProducerEpoch = pec.epoch
 			rm.Committed = pec.commit
+			rm.CoordinatorEpoch = pec.coordinatorEpoch
 			for topic, parts := range topics {
 				rt := kmsg.NewWriteTxnMarkersRequestMarkerTopic()
 				rt.Topic = topic
@@ -3701,6 +3919,7 @@ func (cl *writeTxnMarkersSharder) shard(ctx context.Context, kreq kmsg.Request,
 			rm.ProducerID = pec.pid
 			rm.ProducerEpoch = pec.epoch
 			rm.Committed = pec.commit
+			rm.CoordinatorEpoch = pec.coordinatorEpoch
 			for topic, parts := range topics {
 				rt := kmsg.NewWriteTxnMarkersRequestMarkerTopic()
 				rt.Topic = topic
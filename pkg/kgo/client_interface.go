@@ -0,0 +1,45 @@
+package kgo
+
+import "context"
+
+// ClientInterface is the subset of Client methods that most applications use
+// to produce, consume, and commit. Applications can accept a ClientInterface
+// rather than a *Client so that the client can be mocked in unit tests; a
+// *Client always implements this interface.
+//
+// This interface may grow as commonly used methods are added to the client.
+// For testing against a real (fake) cluster instead of a mock, see the kfake
+// package's NewTestClient.
+type ClientInterface interface {
+	// Produce is Client.Produce.
+	Produce(ctx context.Context, r *Record, promise func(*Record, error))
+	// TryProduce is Client.TryProduce.
+	TryProduce(ctx context.Context, r *Record, promise func(*Record, error))
+	// ProduceSync is Client.ProduceSync.
+	ProduceSync(ctx context.Context, rs ...*Record) ProduceResults
+	// Flush is Client.Flush.
+	Flush(ctx context.Context) error
+
+	// PollFetches is Client.PollFetches.
+	PollFetches(ctx context.Context) Fetches
+	// PollRecords is Client.PollRecords.
+	PollRecords(ctx context.Context, maxPollRecords int) Fetches
+	// AllowRebalance is Client.AllowRebalance.
+	AllowRebalance()
+
+	// CommitRecords is Client.CommitRecords.
+	CommitRecords(ctx context.Context, rs ...*Record) error
+	// CommitUncommittedOffsets is Client.CommitUncommittedOffsets.
+	CommitUncommittedOffsets(ctx context.Context) error
+	// MarkCommitRecords is Client.MarkCommitRecords.
+	MarkCommitRecords(rs ...*Record)
+	// CommitMarkedOffsets is Client.CommitMarkedOffsets.
+	CommitMarkedOffsets(ctx context.Context) error
+
+	// Ping is Client.Ping.
+	Ping(ctx context.Context) error
+	// Close is Client.Close.
+	Close()
+}
+
+var _ ClientInterface = new(Client)
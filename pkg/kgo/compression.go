@@ -5,10 +5,12 @@ import (
 	"compress/gzip"
 	"encoding/binary"
 	"errors"
+	"fmt"
 	"io"
 	"runtime"
 	"sync"
 
+	"github.com/klauspost/compress/dict"
 	"github.com/klauspost/compress/s2"
 	"github.com/klauspost/compress/zstd"
 	"github.com/pierrec/lz4/v4"
@@ -32,25 +34,103 @@ const (
 // RecordBatch. All records in a RecordBatch are compressed into one record
 // for that batch.
 type CompressionCodec struct {
-	codec codecType
-	level int
+	codec  codecType
+	level  int
+	custom Codec
+	dict   []byte // zstd only
+}
+
+// Codec is a custom compression codec, which can be used to compress produced
+// batches with CustomCompression and to decompress fetched batches with
+// DecompressionCodecs.
+//
+// Kafka reserves three bits in a batch's attributes for the compression codec.
+// IDs one through four are the built in codecs (gzip, snappy, lz4, zstd); a
+// Codec with one of these IDs replaces the built in codec, which can be used
+// to, for example, compress zstd with custom encoder settings. IDs five through
+// seven are unused by Kafka and can be used for codecs that are specific to
+// your organization. Note that Kafka brokers reject batches using these IDs:
+// these IDs are only usable with proxies or brokers that understand them.
+//
+// A Codec must be safe for concurrent use.
+type Codec interface {
+	// ID returns the codec's ID, which must be between 1 and 7.
+	ID() uint8
+	// Compress appends the compressed src to dst and returns the result.
+	Compress(dst, src []byte) ([]byte, error)
+	// Decompress returns the decompressed src. The returned slice must
+	// not alias src.
+	Decompress(src []byte) ([]byte, error)
+}
+
+// CustomCompression returns a compression option that compresses with the
+// given codec. See the Codec documentation for more details.
+//
+// A custom codec used for producing is also used to decompress fetched
+// batches that have the codec's ID.
+func CustomCompression(c Codec) CompressionCodec {
+	return CompressionCodec{codec: codecType(c.ID()), custom: c}
 }
 
 // NoCompression is a compression option that avoids compression. This can
 // always be used as a fallback compression.
-func NoCompression() CompressionCodec { return CompressionCodec{codecNone, 0} }
+func NoCompression() CompressionCodec { return CompressionCodec{codec: codecNone} }
 
 // GzipCompression enables gzip compression with the default compression level.
-func GzipCompression() CompressionCodec { return CompressionCodec{codecGzip, gzip.DefaultCompression} }
+func GzipCompression() CompressionCodec {
+	return CompressionCodec{codec: codecGzip, level: gzip.DefaultCompression}
+}
 
 // SnappyCompression enables snappy compression.
-func SnappyCompression() CompressionCodec { return CompressionCodec{codecSnappy, 0} }
+func SnappyCompression() CompressionCodec { return CompressionCodec{codec: codecSnappy} }
 
 // Lz4Compression enables lz4 compression with the fastest compression level.
-func Lz4Compression() CompressionCodec { return CompressionCodec{codecLZ4, 0} }
+func Lz4Compression() CompressionCodec { return CompressionCodec{codec: codecLZ4} }
 
 // ZstdCompression enables zstd compression with the default compression level.
-func ZstdCompression() CompressionCodec { return CompressionCodec{codecZstd, 0} }
+func ZstdCompression() CompressionCodec { return CompressionCodec{codec: codecZstd} }
+
+// ZstdDictCompression enables zstd compression with the default compression
+// level, compressing with the given dictionary. Dictionaries dramatically
+// shrink batches of small records that share structure, such as JSON
+// records with the same fields. A dictionary can be trained from sample
+// records with TrainZstdDict.
+//
+// Consumers must have the dictionary to decompress batches compressed with
+// it; see ZstdDecompressionDicts. If the client also consumes, the
+// dictionary is automatically used for decompressing. If the dictionary is
+// invalid, creating the client fails.
+func ZstdDictCompression(dict []byte) CompressionCodec {
+	return CompressionCodec{codec: codecZstd, dict: dict}
+}
+
+// TrainZstdDict trains a zstd dictionary of at most maxSize bytes from sample
+// record values, for use with ZstdDictCompression and
+// ZstdDecompressionDicts. Samples should be representative of the records
+// that will be produced; more samples produce a better dictionary. A max size
+// of 64KiB or less is recommended, and a max size of zero uses 64KiB.
+func TrainZstdDict(samples [][]byte, maxSize int) ([]byte, error) {
+	if maxSize <= 0 {
+		maxSize = 64 << 10
+	}
+	return dict.BuildZstdDict(samples, dict.Options{
+		MaxDictSize: maxSize,
+		HashBytes:   6,
+	})
+}
+
+// validateZstdDicts returns an error if any dictionary cannot be used for
+// decompressing.
+func validateZstdDicts(dicts [][]byte) error {
+	for _, d := range dicts {
+		dec, err := zstd.NewReader(nil, zstd.WithDecoderDicts(d))
+		if err != nil {
+			return fmt.Errorf("invalid zstd dictionary: %w", err)
+		}
+		dec.Close()
+	}
+	return nil
+}
 
 // WithLevel changes the compression codec's "level", effectively allowing for
 // higher or lower compression ratios at the expense of CPU speed.
@@ -66,6 +146,7 @@ func (c CompressionCodec) WithLevel(level int) CompressionCodec {
 
 type compressor struct {
 	options  []codecType
+	custom   map[codecType]Codec
 	gzPool   sync.Pool
 	lz4Pool  sync.Pool
 	zstdPool sync.Pool
@@ -89,7 +170,11 @@ func newCompressor(codecs ...CompressionCodec) (*compressor, error) {
 	codecs = codecs[:keepIdx]
 
 	for _, codec := range codecs {
-		if codec.codec < 0 || codec.codec > 4 {
+		if codec.custom != nil {
+			if codec.codec < 1 || codec.codec > 7 {
+				return nil, fmt.Errorf("invalid custom compression codec ID %d, must be between 1 and 7", codec.codec)
+			}
+		} else if codec.codec < 0 || codec.codec > 4 {
 			return nil, errors.New("unknown compression codec")
 		}
 	}
@@ -99,6 +184,13 @@ func newCompressor(codecs ...CompressionCodec) (*compressor, error) {
 out:
 	for _, codec := range codecs {
 		c.options = append(c.options, codec.codec)
+		if codec.custom != nil {
+			if c.custom == nil {
+				c.custom = make(map[codecType]Codec)
+			}
+			c.custom[codec.codec] = codec.custom
+			continue
+		}
 		switch codec.codec {
 		case codecNone:
 			break out
@@ -144,6 +236,14 @@ out:
 				zstdEnc.Close()
 				opts = append(opts, zstd.WithEncoderLevel(zstd.EncoderLevel(codec.level)))
 			}
+			if codec.dict != nil {
+				zstdEnc, err := zstd.NewWriter(nil, append(opts, zstd.WithEncoderDict(codec.dict))...)
+				if err != nil {
+					return nil, fmt.Errorf("invalid zstd dictionary: %w", err)
+				}
+				zstdEnc.Close()
+				opts = append(opts, zstd.WithEncoderDict(codec.dict))
+			}
 			c.zstdPool = sync.Pool{New: fn}
 		}
 	}
@@ -174,6 +274,16 @@ func (c *compressor) compress(dst *bytes.Buffer, src []byte, produceRequestVersi
 		break
 	}
 
+	if custom := c.custom[use]; custom != nil {
+		// As with snappy and zstd below, we append directly to the
+		// buffer's underlying slice.
+		out, err := custom.Compress(dst.Bytes(), src)
+		if err != nil {
+			return nil, -1
+		}
+		return out, use
+	}
+
 	var out []byte
 	switch use {
 	case codecNone:
@@ -230,12 +340,13 @@ func (c *compressor) compress(dst *bytes.Buffer, src []byte, produceRequestVersi
 }
 
 type decompressor struct {
+	custom     map[codecType]Codec
 	ungzPool   sync.Pool
 	unlz4Pool  sync.Pool
 	unzstdPool sync.Pool
 }
 
-func newDecompressor() *decompressor {
+func newDecompressor(zstdDicts [][]byte, custom ...Codec) *decompressor {
 	d := &decompressor{
 		ungzPool: sync.Pool{
 			New: func() any { return new(gzip.Reader) },
@@ -248,6 +359,7 @@ func newDecompressor() *decompressor {
 				zstdDec, _ := zstd.NewReader(nil,
 					zstd.WithDecoderLowmem(true),
 					zstd.WithDecoderConcurrency(1),
+					zstd.WithDecoderDicts(zstdDicts...),
 				)
 				r := &zstdDecoder{zstdDec}
 				runtime.SetFinalizer(r, func(r *zstdDecoder) {
@@ -257,6 +369,12 @@ func newDecompressor() *decompressor {
 			},
 		},
 	}
+	for _, c := range custom {
+		if d.custom == nil {
+			d.custom = make(map[codecType]Codec)
+		}
+		d.custom[codecType(c.ID())] = c
+	}
 	return d
 }
 
@@ -270,6 +388,9 @@ func (d *decompressor) decompress(src []byte, codec byte) ([]byte, error) {
 	if compCodec == codecNone {
 		return src, nil
 	}
+	if custom := d.custom[compCodec]; custom != nil {
+		return custom.Decompress(src)
+	}
 	out := byteBuffers.Get().(*bytes.Buffer)
 	out.Reset()
 	defer byteBuffers.Put(out)
@@ -71,7 +71,7 @@ func TestCompressDecompress(t *testing.T) {
 	}
 
 	t.Parallel()
-	d := newDecompressor()
+	d := newDecompressor(nil)
 	inputs := [][]byte{
 		randStr(1 << 2),
 		randStr(1 << 5),
@@ -127,6 +127,65 @@ func TestCompressDecompress(t *testing.T) {
 	wg.Wait()
 }
 
+// reverseCodec is a custom codec that "compresses" by reversing bytes.
+type reverseCodec struct{ id uint8 }
+
+func (c reverseCodec) ID() uint8 { return c.id }
+
+func (reverseCodec) Compress(dst, src []byte) ([]byte, error) {
+	for i := len(src) - 1; i >= 0; i-- {
+		dst = append(dst, src[i])
+	}
+	return dst, nil
+}
+
+func (c reverseCodec) Decompress(src []byte) ([]byte, error) {
+	return c.Compress(nil, src)
+}
+
+func TestCustomCompression(t *testing.T) {
+	t.Parallel()
+
+	for _, id := range []uint8{0, 8} {
+		if _, err := newCompressor(CustomCompression(reverseCodec{id})); err == nil {
+			t.Errorf("custom codec ID %d: unexpected success", id)
+		}
+	}
+
+	in := []byte("abcdefgh")
+	for _, test := range []struct {
+		codecs  []CompressionCodec
+		version int16
+		exp     codecType
+	}{
+		{[]CompressionCodec{CustomCompression(reverseCodec{5})}, 7, 5},
+		{[]CompressionCodec{CustomCompression(reverseCodec{4}), SnappyCompression()}, 7, codecZstd}, // overrides zstd
+		{[]CompressionCodec{CustomCompression(reverseCodec{4}), SnappyCompression()}, 6, codecSnappy},
+	} {
+		c, err := newCompressor(test.codecs...)
+		if err != nil {
+			t.Fatalf("unexpected err: %v", err)
+		}
+		d := newDecompressor(nil, reverseCodec{4}, reverseCodec{5})
+
+		w := new(bytes.Buffer)
+		got, used := c.compress(w, in, test.version)
+		if used != test.exp {
+			t.Errorf("got codec %d != exp %d", used, test.exp)
+		}
+		if used != codecSnappy && !bytes.Equal(got, []byte("hgfedcba")) {
+			t.Errorf("got compressed %s, expected custom codec to be used", got)
+		}
+		got, err = d.decompress(got, byte(used))
+		if err != nil {
+			t.Fatalf("unexpected decompress err: %v", err)
+		}
+		if !bytes.Equal(got, in) {
+			t.Errorf("got decompress %s != exp %s", got, in)
+		}
+	}
+}
+
 func BenchmarkCompress(b *testing.B) {
 	in := bytes.Repeat([]byte("abcdefghijklmno pqrs tuvwxy   z"), 100)
 	for _, codec := range []codecType{codecGzip, codecSnappy, codecLZ4, codecZstd} {
@@ -155,7 +214,7 @@ func BenchmarkDecompress(b *testing.B) {
 
 		b.Run(fmt.Sprint(codec), func(b *testing.B) {
 			for i := 0; i < b.N; i++ {
-				d := newDecompressor()
+				d := newDecompressor(nil)
 				d.decompress(w.Bytes(), byte(codec))
 			}
 		})
@@ -213,3 +272,47 @@ func Test_xerialDecode(t *testing.T) {
 		})
 	}
 }
+
+func TestZstdDict(t *testing.T) {
+	t.Parallel()
+
+	var samples [][]byte
+	for i := 0; i < 200; i++ {
+		samples = append(samples, []byte(fmt.Sprintf(`{"user_id":%d,"event":"page_view","path":"/products/%d","referrer":"https://example.com/search?q=item"}`, i, i*7)))
+	}
+	dict, err := TrainZstdDict(samples, 4<<10)
+	if err != nil {
+		t.Fatalf("unable to train dict: %v", err)
+	}
+
+	plain, _ := newCompressor(ZstdCompression())
+	withDict, err := newCompressor(ZstdDictCompression(dict))
+	if err != nil {
+		t.Fatalf("unable to create dict compressor: %v", err)
+	}
+
+	in := samples[100]
+	plainOut, _ := plain.compress(new(bytes.Buffer), in, 7)
+	dictOut, used := withDict.compress(new(bytes.Buffer), in, 7)
+	if used != codecZstd {
+		t.Fatalf("got codec %d, expected zstd", used)
+	}
+	if len(dictOut) >= len(plainOut) {
+		t.Errorf("dict compressed %d bytes, not smaller than plain %d bytes", len(dictOut), len(plainOut))
+	}
+
+	got, err := newDecompressor([][]byte{dict}).decompress(dictOut, byte(used))
+	if err != nil {
+		t.Fatalf("unexpected decompress err: %v", err)
+	}
+	if !bytes.Equal(got, in) {
+		t.Errorf("got decompress %s != exp %s", got, in)
+	}
+	if _, err := newDecompressor(nil).decompress(dictOut, byte(used)); err == nil {
+		t.Error("unexpected success decompressing without the dict")
+	}
+
+	if _, err := newCompressor(ZstdDictCompression([]byte("not a dict"))); err == nil {
+		t.Error("unexpected success with an invalid dict")
+	}
+}
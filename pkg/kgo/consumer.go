@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"regexp"
 	"sort"
 	"strings"
 	"sync"
@@ -215,9 +216,17 @@ type consumer struct {
 
 	session atomic.Value // *consumerSession
 	kill    atomic.Bool
+	drain   atomic.Value // *consumerDrain, set once Drain is called
 
 	usingCursors usedCursors
 
+	lag *consumerLag // non-nil if ConsumerLagInterval is used
+
+	// regexes are the regular expressions to match topics against if
+	// consuming via regex. This is guarded by mu and is only changed in a
+	// blocking metadata fn.
+	regexes map[string]*regexp.Regexp
+
 	sourcesReadyMu          sync.Mutex
 	sourcesReadyCond        *sync.Cond
 	sourcesReadyForDraining []*source
@@ -324,6 +333,15 @@ func (c *consumer) init(cl *Client) {
 	c.paused.Store(make(pausedTopics))
 	c.sourcesReadyCond = sync.NewCond(&c.sourcesReadyMu)
 	c.pollWaitC = sync.NewCond(&c.pollWaitMu)
+	if cl.cfg.consumerLagInterval > 0 {
+		c.lag = &consumerLag{parts: make(map[string]map[int32]*lagState)}
+	}
+	if cl.cfg.regex {
+		c.regexes = make(map[string]*regexp.Regexp, len(cl.cfg.topics))
+		for raw, re := range cl.cfg.topics {
+			c.regexes[raw] = re
+		}
+	}
 
 	if len(cl.cfg.topics) > 0 || len(cl.cfg.partitions) > 0 {
 		defer cl.triggerUpdateMetadataNow("querying metadata for consumer initialization") // we definitely want to trigger a metadata update
@@ -440,6 +458,11 @@ func (cl *Client) PollRecords(ctx context.Context, maxPollRecords int) Fetches {
 		}
 	}
 
+	if d := c.loadDrain(); d != nil {
+		d.markProcessed()
+		return NewErrFetch(ErrDraining)
+	}
+
 	var fetches Fetches
 	fill := func() {
 		if c.cl.cfg.blockRebalanceOnPoll {
@@ -509,13 +532,16 @@ func (cl *Client) PollRecords(ctx context.Context, maxPollRecords int) Fetches {
 		if c.g != nil {
 			c.g.updateUncommitted(realFetches)
 		}
+		if c.lag != nil {
+			c.lag.updatePolled(realFetches, c.cl.cfg.isolationLevel == 1)
+		}
 	}
 
 	// We try filling fetches once before waiting. If we have no context,
 	// we guarantee that we just drain anything available and return.
 	fill()
 	if len(fetches) > 0 || ctx == nil {
-		return fetches
+		return cl.serdeDecode(cl.interceptPoll(fetches))
 	}
 
 	done := make(chan struct{})
@@ -525,7 +551,7 @@ func (cl *Client) PollRecords(ctx context.Context, maxPollRecords int) Fetches {
 		defer c.sourcesReadyMu.Unlock()
 		defer close(done)
 
-		for !quit && len(c.sourcesReadyForDraining) == 0 && len(c.fakeReadyForDraining) == 0 {
+		for !quit && c.loadDrain() == nil && len(c.sourcesReadyForDraining) == 0 && len(c.fakeReadyForDraining) == 0 {
 			c.sourcesReadyCond.Wait()
 		}
 	}()
@@ -547,8 +573,13 @@ func (cl *Client) PollRecords(ctx context.Context, maxPollRecords int) Fetches {
 	case <-done:
 	}
 
+	if d := c.loadDrain(); d != nil {
+		d.markProcessed()
+		return NewErrFetch(ErrDraining)
+	}
+
 	fill()
-	return fetches
+	return cl.serdeDecode(cl.interceptPoll(fetches))
 }
 
 // AllowRebalance allows a consumer group to rebalance if it was blocked by you
@@ -561,6 +592,9 @@ func (cl *Client) PollRecords(ctx context.Context, maxPollRecords int) Fetches {
 // rebalance completes. Internally, this function simply waits for lost
 // partitions to stop being fetched before allowing you to poll again.
 func (cl *Client) AllowRebalance() {
+	if d := cl.consumer.loadDrain(); d != nil {
+		d.markProcessed()
+	}
 	cl.consumer.allowRebalance()
 }
 
@@ -747,7 +781,7 @@ func (c *consumer) purgeTopics(topics []string) {
 }
 
 // AddConsumeTopics adds new topics to be consumed. This function is a no-op if
-// the client is configured to consume via regex.
+// the client is configured to consume via regex; see AddConsumeRegex instead.
 //
 // Note that if you are directly consuming and specified ConsumePartitions,
 // this function will not add the rest of the partitions for a topic unless the
@@ -1199,18 +1233,13 @@ func (c *consumer) filterMetadataAllTopics(topics []string) []string {
 	var rns reNews
 	defer rns.log(&c.cl.cfg)
 
-	var reSeen map[string]bool
-	if c.d != nil {
-		reSeen = c.d.reSeen
-	} else {
-		reSeen = c.g.reSeen
-	}
+	reSeen := c.reSeen()
 
 	keep := topics[:0]
 	for _, topic := range topics {
 		want, seen := reSeen[topic]
 		if !seen {
-			for rawRe, re := range c.cl.cfg.topics {
+			for rawRe, re := range c.regexes {
 				if want = re.MatchString(topic); want {
 					rns.add(rawRe, topic)
 					break
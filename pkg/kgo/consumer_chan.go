@@ -0,0 +1,210 @@
+package kgo
+
+import (
+	"context"
+	"sync"
+)
+
+// PartitionRecords is a channel of records for a single partition, as
+// returned from PartitionRecordsChan.
+type PartitionRecords struct {
+	Topic     string
+	Partition int32
+
+	// Records receives all consumed records for this partition, in order.
+	Records <-chan *Record
+}
+
+// RecordsChan is an alternative to a poll loop: this starts polling in a
+// goroutine and returns a channel that all polled records are sent on, in
+// order per partition. The channel has a buffer of the given size. Polling
+// is blocked while the channel is full, at which point the client stops
+// fetching once its own fetch buffers are full.
+//
+// Fetch errors are passed to onErr if it is non-nil; the errors are the same
+// as described in Fetches.Errors. The channel is closed once the context is
+// canceled or the client is closed; records polled but not yet sent are
+// dropped.
+//
+// Only one goroutine can poll at a time: you must not call PollFetches or
+// PollRecords, or start another channel, while the channel is open.
+func (cl *Client) RecordsChan(ctx context.Context, size int, onErr func(FetchError)) <-chan *Record {
+	ch := make(chan *Record, size)
+	go func() {
+		defer close(ch)
+		for {
+			fetches, ok := cl.pollChan(ctx, onErr)
+			if !ok {
+				return
+			}
+			for iter := fetches.RecordIter(); !iter.Done(); {
+				select {
+				case ch <- iter.Next():
+				case <-ctx.Done():
+					return
+				case <-cl.ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return ch
+}
+
+// PartitionRecordsChan is like RecordsChan, but sends records on a separate
+// channel per partition. The returned channel receives a PartitionRecords the
+// first time records are consumed for a partition, and must be drained
+// promptly: polling blocks until each new partition is received.
+//
+// Each partition's channel has a buffer of the given size. If a partition's
+// channel is full, fetching is paused for only that partition and the
+// remaining polled records are sent as the channel drains. Once all records
+// are sent, fetching is resumed. Slow processing of one partition therefore
+// does not block consuming any other partition. Because fetching is paused and
+// resumed internally, you must not pause or resume partitions yourself while
+// using this function.
+//
+// Fetch errors are passed to onErr if it is non-nil. All channels are closed
+// once the context is canceled or the client is closed. Partition channels are
+// not closed if a partition is revoked in a group rebalance; records stop
+// being sent on the channel until the partition is reassigned.
+//
+// Only one goroutine can poll at a time: you must not call PollFetches or
+// PollRecords, or start another channel, while the channels are open.
+func (cl *Client) PartitionRecordsChan(ctx context.Context, size int, onErr func(FetchError)) <-chan PartitionRecords {
+	parts := make(chan PartitionRecords)
+	go func() {
+		type tp struct {
+			t string
+			p int32
+		}
+		var (
+			chans = make(map[tp]*chanPartition)
+			quit  = make(chan struct{})
+			wg    sync.WaitGroup
+		)
+		defer func() {
+			close(quit)
+			wg.Wait()
+			for _, cp := range chans {
+				if cp.draining {
+					cl.ResumeFetchPartitions(map[string][]int32{cp.topic: {cp.partition}})
+				}
+				close(cp.ch)
+			}
+			close(parts)
+		}()
+
+		for {
+			fetches, ok := cl.pollChan(ctx, onErr)
+			if !ok {
+				return
+			}
+			var stop bool
+			fetches.EachPartition(func(p FetchTopicPartition) {
+				if stop || len(p.Records) == 0 {
+					return
+				}
+				key := tp{p.Topic, p.Partition}
+				cp := chans[key]
+				if cp == nil {
+					cp = &chanPartition{
+						topic:     p.Topic,
+						partition: p.Partition,
+						ch:        make(chan *Record, size),
+					}
+					select {
+					case parts <- PartitionRecords{p.Topic, p.Partition, cp.ch}:
+					case <-ctx.Done():
+						stop = true
+						return
+					case <-cl.ctx.Done():
+						stop = true
+						return
+					}
+					chans[key] = cp
+				}
+				cp.send(cl, p.Records, quit, &wg)
+			})
+			if stop {
+				return
+			}
+		}
+	}()
+	return parts
+}
+
+// chanPartition is a partition's channel for PartitionRecordsChan.
+type chanPartition struct {
+	topic     string
+	partition int32
+	ch        chan *Record
+
+	mu       sync.Mutex
+	backlog  []*Record
+	draining bool
+}
+
+// send sends records on the partition's channel without blocking. If the
+// channel fills, we pause fetching the partition and start a goroutine that
+// sends the backlog, resuming fetching once the backlog is empty.
+func (cp *chanPartition) send(cl *Client, rs []*Record, quit <-chan struct{}, wg *sync.WaitGroup) {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	if cp.draining {
+		cp.backlog = append(cp.backlog, rs...)
+		return
+	}
+	for i, r := range rs {
+		select {
+		case cp.ch <- r:
+			continue
+		default:
+		}
+		cp.backlog = append(cp.backlog[:0], rs[i:]...)
+		cp.draining = true
+		cl.PauseFetchPartitions(map[string][]int32{cp.topic: {cp.partition}})
+		wg.Add(1)
+		go cp.drain(cl, quit, wg)
+		return
+	}
+}
+
+func (cp *chanPartition) drain(cl *Client, quit <-chan struct{}, wg *sync.WaitGroup) {
+	defer wg.Done()
+	for {
+		cp.mu.Lock()
+		if len(cp.backlog) == 0 {
+			cp.draining = false
+			cl.ResumeFetchPartitions(map[string][]int32{cp.topic: {cp.partition}})
+			cp.mu.Unlock()
+			return
+		}
+		r := cp.backlog[0]
+		cp.backlog[0] = nil
+		cp.backlog = cp.backlog[1:]
+		cp.mu.Unlock()
+
+		select {
+		case cp.ch <- r:
+		case <-quit:
+			return
+		}
+	}
+}
+
+// pollChan polls for the channel functions, passing fetch errors to onErr.
+// This returns false if the context is canceled or the client is closed.
+func (cl *Client) pollChan(ctx context.Context, onErr func(FetchError)) (Fetches, bool) {
+	fetches := cl.PollFetches(ctx)
+	if ctx.Err() != nil || cl.ctx.Err() != nil {
+		return nil, false
+	}
+	if onErr != nil {
+		for _, fe := range fetches.Errors() {
+			onErr(fe)
+		}
+	}
+	return fetches, true
+}
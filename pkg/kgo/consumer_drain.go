@@ -0,0 +1,91 @@
+package kgo
+
+import (
+	"context"
+	"sync"
+)
+
+// consumerDrain tracks a Drain: processed is closed once the application has
+// finished processing the records it polled before the drain began.
+type consumerDrain struct {
+	once      sync.Once
+	processed chan struct{}
+}
+
+func (d *consumerDrain) markProcessed() {
+	d.once.Do(func() { close(d.processed) })
+}
+
+// loadDrain returns the current drain, or nil if the consumer is not
+// draining.
+func (c *consumer) loadDrain() *consumerDrain {
+	d, _ := c.drain.Load().(*consumerDrain)
+	return d
+}
+
+// Drain gracefully stops consuming: the client stops fetching, waits for the
+// application to finish processing the records it has already polled, commits
+// offsets, and then leaves the group. This is a structured alternative to
+// racing a final CommitOffsets against Close when shutting down a consumer.
+//
+// Once Drain is called, no new fetch requests are issued and polls no longer
+// return records: PollFetches and PollRecords return immediately with a fake
+// fetch containing ErrDraining, including any poll that is currently blocked.
+// Records that were fetched but not yet polled are discarded and are not
+// committed.
+//
+// The records returned from the last poll are considered processed once the
+// application polls again or calls AllowRebalance. A typical poll loop can
+// therefore keep running while Drain is called from another goroutine: the
+// loop finishes its current batch, polls, receives ErrDraining, and exits.
+// If you are using BlockRebalanceOnPoll, Drain allows rebalancing once the
+// polled records are processed.
+//
+// If you are group consuming with autocommitting enabled, Drain then commits
+// uncommitted offsets (or marked offsets, if using AutoCommitMarks) and
+// leaves the group as with LeaveGroupContext. If autocommitting is disabled,
+// you must commit offsets yourself before the polled records are considered
+// processed. If you are directly consuming, Drain returns once the polled
+// records are processed.
+//
+// This returns the first commit or leave group error, or the context's error
+// if the context is canceled before draining completes. The client remains
+// draining even if this returns an error: consuming cannot be resumed, and
+// the client should be closed. It is safe to call Drain more than once.
+func (cl *Client) Drain(ctx context.Context) error {
+	c := &cl.consumer
+
+	c.sourcesReadyMu.Lock()
+	d := c.loadDrain()
+	if d == nil {
+		d = &consumerDrain{processed: make(chan struct{})}
+		c.drain.Store(d)
+	}
+	c.sourcesReadyMu.Unlock()
+	c.sourcesReadyCond.Broadcast() // wake any blocked poll
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-cl.ctx.Done():
+		return ErrClientClosed
+	case <-d.processed:
+	}
+	c.allowRebalance()
+
+	if c.g == nil {
+		return nil
+	}
+	if !cl.cfg.autocommitDisable {
+		var err error
+		if cl.cfg.autocommitMarks {
+			err = cl.CommitMarkedOffsets(ctx)
+		} else {
+			err = cl.CommitUncommittedOffsets(ctx)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return cl.LeaveGroupContext(ctx)
+}
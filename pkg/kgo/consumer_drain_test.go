@@ -0,0 +1,38 @@
+package kgo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDrainWakesPoll(t *testing.T) {
+	cl, err := NewClient(
+		SeedBrokers("127.0.0.1:1"),
+		ConsumeTopics("foo"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cl.Close()
+
+	drained := make(chan error, 1)
+	go func() {
+		time.Sleep(50 * time.Millisecond) // let the poll below block
+		drained <- cl.Drain(context.Background())
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	fs := cl.PollFetches(ctx)
+	if err := fs.Err(); !errors.Is(err, ErrDraining) {
+		t.Fatalf("got poll err %v, exp ErrDraining", err)
+	}
+	if err := <-drained; err != nil {
+		t.Fatalf("got drain err %v, exp nil", err)
+	}
+	if err := cl.PollFetches(ctx).Err(); !errors.Is(err, ErrDraining) {
+		t.Fatalf("got second poll err %v, exp ErrDraining", err)
+	}
+}
@@ -26,6 +26,11 @@ type groupConsumer struct {
 
 	cooperative atomicBool // true if the group balancer chosen during Join is cooperative
 
+	// g848 is non-nil if we are using the KIP-848 consumer group protocol.
+	// This is set to nil in the manage loop if we downgrade to the classic
+	// protocol.
+	g848 *g848
+
 	// The data for topics that the user assigned. Metadata updates the
 	// atomic.Value in each pointer atomically.
 	tps *topicsPartitions
@@ -55,6 +60,12 @@ type groupConsumer struct {
 	lastAssigned map[string][]int32
 	nowAssigned  amtps
 
+	// rebalanced is the assignment after the last successful rebalance,
+	// passed to HookGroupRebalance as the previous assignment. Unlike
+	// lastAssigned, this is not cleared when eager consumers revoke
+	// everything before rejoining. This is only used in the manage loop.
+	rebalanced map[string][]int32
+
 	// Fetching ensures we continue fetching offsets across cooperative
 	// rebalance if an offset fetch returns early due to an immediate
 	// rebalance. See the large comment on adjustCooperativeFetchOffsets
@@ -262,7 +273,8 @@ func (cl *Client) LeaveGroupContext(ctx context.Context) error {
 }
 
 // GroupMetadata returns the current group member ID and generation, or an
-// empty string and -1 if not in the group.
+// empty string and -1 if not in the group. If using the "consumer"
+// ConsumerGroupProtocol, the generation is the member epoch.
 func (cl *Client) GroupMetadata() (string, int32) {
 	g := cl.consumer.g
 	if g == nil {
@@ -292,6 +304,9 @@ func (c *consumer) initGroup() {
 		left: make(chan struct{}),
 	}
 	c.g = g
+	if g.cfg.groupProtocol == "consumer" {
+		g.g848 = &g848{g: g, interval: g.cfg.heartbeatInterval}
+	}
 	if !g.cfg.setCommitCallback {
 		g.cfg.commitCallback = g.defaultCommitCallback
 	}
@@ -379,13 +394,29 @@ func (g *groupConsumer) manage() {
 		go g.loopCommit()
 	}
 
+	if g.g848 != nil {
+		g.g848.start()
+	}
+
 	var consecutiveErrors int
 	joinWhy := "beginning to manage the group lifecycle"
 	for {
 		if joinWhy == "" {
 			joinWhy = "rejoining from normal rebalance"
 		}
-		err := g.joinAndSync(joinWhy)
+		start := time.Now()
+		var err error
+		if g.g848 != nil {
+			if err = g.g848.join(); errNoConsumerProtocol(err) {
+				g.cfg.logger.Log(LogLevelWarn, "group coordinator does not support the consumer group protocol, downgrading to the classic protocol", "group", g.cfg.group, "err", err)
+				g.g848 = nil
+				g.memberGen.store("", -1) // the classic protocol's broker assigns our member ID
+			}
+		}
+		if g.g848 == nil {
+			err = g.joinAndSync(joinWhy)
+		}
+		g.onRebalance(time.Since(start), err)
 		if err == nil {
 			if joinWhy, err = g.setupAssignedAndHeartbeat(); err != nil {
 				if errors.Is(err, kerr.RebalanceInProgress) {
@@ -399,6 +430,14 @@ func (g *groupConsumer) manage() {
 		}
 		joinWhy = "rejoining after we previously errored and backed off"
 
+		// With the consumer protocol, being fenced is an expected part
+		// of membership: we lose everything we own and immediately
+		// rejoin with epoch 0. We do not surface the error.
+		fenced := g.g848 != nil && g.g848.fenced(err)
+		if fenced {
+			joinWhy = "rejoining after being fenced"
+		}
+
 		// If the user has BlockPollOnRebalance enabled, we have to
 		// block around the onLost and assigning.
 		g.c.waitAndAddRebalance()
@@ -424,12 +463,14 @@ func (g *groupConsumer) manage() {
 			if g.cfg.onLost != nil {
 				g.cfg.onLost(g.cl.ctx, g.cl, g.nowAssigned.read())
 			}
-			g.cfg.hooks.each(func(h Hook) {
-				if h, ok := h.(HookGroupManageError); ok {
-					h.OnGroupManageError(err)
-				}
-			})
-			g.c.addFakeReadyForDraining("", 0, &ErrGroupSession{err}, "notification of group management loop error")
+			if !fenced {
+				g.cfg.hooks.each(func(h Hook) {
+					if h, ok := h.(HookGroupManageError); ok {
+						h.OnGroupManageError(err)
+					}
+				})
+				g.c.addFakeReadyForDraining("", 0, &ErrGroupSession{err}, "notification of group management loop error")
+			}
 		}
 
 		// If we are eager, we should have invalidated everything
@@ -450,6 +491,7 @@ func (g *groupConsumer) manage() {
 
 			g.nowAssigned.store(nil)
 			g.lastAssigned = nil
+			g.rebalanced = nil
 			g.fetching = nil
 
 			g.leader.Store(false)
@@ -464,6 +506,17 @@ func (g *groupConsumer) manage() {
 			return
 		}
 
+		// With the consumer protocol, any fatal error requires us to
+		// rejoin from scratch with epoch 0.
+		if g.g848 != nil {
+			g.g848.reset()
+			if fenced {
+				g.cfg.logger.Log(LogLevelInfo, "fenced from the group, released our assignment as lost and rejoining", "group", g.cfg.group, "err", err)
+				consecutiveErrors = 0
+				continue
+			}
+		}
+
 		// Waiting for the backoff is a good time to update our
 		// metadata; maybe the error is from stale metadata.
 		consecutiveErrors++
@@ -486,6 +539,59 @@ func (g *groupConsumer) manage() {
 	}
 }
 
+// onRebalance calls HookGroupRebalance hooks after a join and sync.
+func (g *groupConsumer) onRebalance(dur time.Duration, err error) {
+	var (
+		previous                 = g.rebalanced
+		assigned, added, revoked map[string][]int32
+	)
+	if err == nil {
+		assigned = g.nowAssigned.clone()
+		added = diffTopicPartitions(assigned, previous)
+		revoked = diffTopicPartitions(previous, assigned)
+		g.rebalanced = assigned
+	}
+	memberID, generation := g.memberGen.load()
+	g.cfg.hooks.each(func(h Hook) {
+		if h, ok := h.(HookGroupRebalance); ok {
+			h.OnGroupRebalance(GroupRebalance{
+				Group:      g.cfg.group,
+				MemberID:   memberID,
+				Generation: generation,
+				Duration:   dur,
+				Previous:   previous,
+				Assigned:   assigned,
+				Added:      added,
+				Revoked:    revoked,
+				Err:        err,
+			})
+		}
+	})
+}
+
+// diffTopicPartitions returns the partitions in l that are not in r. This
+// always returns a non-nil map.
+func diffTopicPartitions(l, r map[string][]int32) map[string][]int32 {
+	diff := make(map[string][]int32)
+	for t, lps := range l {
+		rps, exists := r[t]
+		if !exists {
+			diff[t] = append([]int32(nil), lps...)
+			continue
+		}
+		in := make(map[int32]struct{}, len(rps))
+		for _, p := range rps {
+			in[p] = struct{}{}
+		}
+		for _, p := range lps {
+			if _, exists := in[p]; !exists {
+				diff[t] = append(diff[t], p)
+			}
+		}
+	}
+	return diff
+}
+
 func (g *groupConsumer) leave(ctx context.Context) {
 	// If g.using is nonzero before this check, then a manage goroutine has
 	// started. If not, it will never start because we set dying.
@@ -511,6 +617,11 @@ func (g *groupConsumer) leave(ctx context.Context) {
 
 		defer close(g.left)
 
+		if g.g848 != nil {
+			g.leaveErr = g.g848.leave(ctx)
+			return
+		}
+
 		if g.cfg.instanceID != nil {
 			return
 		}
@@ -920,7 +1031,11 @@ func (g *groupConsumer) setupAssignedAndHeartbeat() (string, error) {
 // If the offset fetch is successful, then we basically sit in this function
 // until a heartbeat errors or we, being the leader, decide to re-join.
 func (g *groupConsumer) heartbeat(fetchErrCh <-chan error, s *assignRevokeSession) (string, error) {
-	ticker := time.NewTicker(g.cfg.heartbeatInterval)
+	interval := g.cfg.heartbeatInterval
+	if g.g848 != nil {
+		interval = g.g848.interval
+	}
+	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
 	// We issue one heartbeat quickly if we are cooperative because
@@ -973,15 +1088,20 @@ func (g *groupConsumer) heartbeat(fetchErrCh <-chan error, s *assignRevokeSessio
 
 		if heartbeat {
 			g.cfg.logger.Log(LogLevelDebug, "heartbeating", "group", g.cfg.group)
-			req := kmsg.NewPtrHeartbeatRequest()
-			req.Group = g.cfg.group
-			memberID, generation := g.memberGen.load()
-			req.Generation = generation
-			req.MemberID = memberID
-			req.InstanceID = g.cfg.instanceID
-			var resp *kmsg.HeartbeatResponse
-			if resp, err = req.RequestWith(g.ctx, g.cl); err == nil {
-				err = kerr.ErrorForCode(resp.ErrorCode)
+			if g.g848 != nil {
+				err = g.g848.sessionHeartbeat(g.ctx)
+				ticker.Reset(g.g848.interval) // the broker decides our interval
+			} else {
+				req := kmsg.NewPtrHeartbeatRequest()
+				req.Group = g.cfg.group
+				memberID, generation := g.memberGen.load()
+				req.Generation = generation
+				req.MemberID = memberID
+				req.InstanceID = g.cfg.instanceID
+				var resp *kmsg.HeartbeatResponse
+				if resp, err = req.RequestWith(g.ctx, g.cl); err == nil {
+					err = kerr.ErrorForCode(resp.ErrorCode)
+				}
 			}
 			g.cfg.logger.Log(LogLevelDebug, "heartbeat complete", "group", g.cfg.group, "err", err)
 			if force != nil {
@@ -1731,8 +1851,8 @@ func (g *groupConsumer) findNewAssignments() {
 		// group.init from the config's topics, but can also be added
 		// to in AddConsumeTopics. By default, we use the topic. If
 		// this is regex based, the config's topics are regular
-		// expressions that we need to evaluate against (and we do not
-		// support adding new regex).
+		// expressions that we need to evaluate against (see
+		// filterMetadataAllTopics).
 		useTopic := true
 		if g.cfg.regex {
 			useTopic = g.reSeen[topic]
@@ -2843,6 +2963,7 @@ func (g *groupConsumer) commit(
 
 		if fn, ok := ctx.Value(commitContextFn).(func(*kmsg.OffsetCommitRequest) error); ok {
 			if err := fn(req); err != nil {
+				g.cl.interceptCommit(req, nil, err)
 				onDone(g.cl, req, nil, err)
 				return
 			}
@@ -2850,10 +2971,12 @@ func (g *groupConsumer) commit(
 
 		resp, err := req.RequestWith(commitCtx, g.cl)
 		if err != nil {
+			g.cl.interceptCommit(req, nil, err)
 			onDone(g.cl, req, nil, err)
 			return
 		}
 		g.updateCommitted(req, resp)
+		g.cl.interceptCommit(req, resp, nil)
 		onDone(g.cl, req, resp, nil)
 	}()
 }
@@ -0,0 +1,366 @@
+package kgo
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// g848 tracks a group member's state when using the KIP-848 "consumer" group
+// protocol. With this protocol, the broker computes assignments and members
+// only ever issue ConsumerGroupHeartbeat requests, reporting their
+// subscription and what they currently own.
+//
+// We map this protocol onto the classic group session flow rather than
+// having a second management loop. Every assignment is incremental, so the
+// member always behaves as a cooperative consumer. When a heartbeat returns a
+// new assignment, the heartbeat loop quits with RebalanceInProgress exactly
+// as it does for a classic rebalance, and "joining" applies the new
+// assignment. The session setup then revokes what was lost (which triggers a
+// rejoin, i.e. an immediate heartbeat reporting that we no longer own those
+// partitions) and fetches offsets for what was added.
+//
+// All fields are only used from the manage goroutine or the heartbeat
+// goroutine it waits on; leave only runs once manage has quit.
+type g848 struct {
+	g *groupConsumer
+
+	// assigned is the latest assignment the broker replied with, keyed by
+	// topic ID. The broker only replies with an assignment when it
+	// changes, so we keep it until every ID can be mapped to a topic.
+	// This is nil until we receive our first assignment after joining.
+	assigned map[[16]byte][]int32
+
+	interval time.Duration // the broker's heartbeat interval
+}
+
+// errNoConsumerProtocol is used when the group coordinator does not support
+// the consumer group protocol, or our MaxVersions does not allow it, and we
+// must downgrade.
+func errNoConsumerProtocol(err error) bool {
+	return errors.Is(err, errBrokerTooOld) || errors.Is(err, errUnknownRequestKey) || errors.Is(err, kerr.UnsupportedVersion)
+}
+
+// join heartbeats until the broker assigns us partitions, and then stores
+// the assignment. If a heartbeat already received a new assignment (which is
+// why the prior session ended), this applies the assignment immediately.
+func (g *g848) join() error {
+	g.g.cooperative.Store(true)
+	select {
+	case <-g.g.rejoinCh: // drain to avoid unnecessary rejoins
+	default:
+	}
+
+	if g.assigned != nil {
+		if resolved, _ := g.resolve(); !assignmentsEqual(resolved, g.g.nowAssigned.read()) {
+			g.g.cfg.logger.Log(LogLevelInfo, "applying new consumer group assignment", "group", g.g.cfg.group, "assigned", mtps(resolved))
+			g.g.nowAssigned.store(resolved)
+			return nil
+		}
+	}
+
+	g.g.cfg.logger.Log(LogLevelInfo, "joining group with the consumer group protocol", "group", g.g.cfg.group)
+	for {
+		if err := g.heartbeat(g.g.ctx); err != nil {
+			g.g.cfg.logger.Log(LogLevelWarn, "consumer group heartbeat failed while joining", "group", g.g.cfg.group, "err", err)
+			return err
+		}
+		if g.assigned != nil {
+			resolved, unresolved := g.resolve()
+			if unresolved {
+				g.g.cl.triggerUpdateMetadataNow("consumer group assignment contains unknown topic IDs")
+			}
+			memberID, epoch := g.g.memberGen.load()
+			g.g.cfg.logger.Log(LogLevelInfo, "joined group",
+				"group", g.g.cfg.group,
+				"member_id", memberID,
+				"member_epoch", epoch,
+				"assigned", mtps(resolved),
+			)
+			g.g.nowAssigned.store(resolved)
+			return nil
+		}
+
+		after := time.NewTimer(g.interval)
+		select {
+		case <-g.g.ctx.Done():
+			after.Stop()
+			return g.g.ctx.Err()
+		case <-after.C:
+		}
+	}
+}
+
+// sessionHeartbeat is used from the group session's heartbeat loop. If the
+// broker replied with an assignment that differs from what we currently own,
+// this returns RebalanceInProgress to end the session.
+func (g *g848) sessionHeartbeat(ctx context.Context) error {
+	if err := g.heartbeat(ctx); err != nil {
+		return err
+	}
+	if g.assigned == nil {
+		return nil
+	}
+	resolved, unresolved := g.resolve()
+	if unresolved {
+		g.g.cl.triggerUpdateMetadataNow("consumer group assignment contains unknown topic IDs")
+	}
+	if !assignmentsEqual(resolved, g.g.nowAssigned.read()) {
+		g.g.cfg.logger.Log(LogLevelInfo, "consumer group heartbeat returned a new assignment", "group", g.g.cfg.group, "assigned", mtps(resolved))
+		return kerr.RebalanceInProgress
+	}
+	return nil
+}
+
+// heartbeat issues one ConsumerGroupHeartbeat request with our full member
+// state, and saves the member ID, epoch, interval, and any assignment in the
+// response.
+func (g *g848) heartbeat(ctx context.Context) error {
+	cfg := g.g.cfg
+	memberID, epoch := g.g.memberGen.load()
+	if epoch < 0 {
+		epoch = 0
+	}
+
+	req := kmsg.NewPtrConsumerGroupHeartbeatRequest()
+	req.Group = cfg.group
+	req.MemberID = memberID
+	req.MemberEpoch = epoch
+	req.InstanceID = cfg.instanceID
+	if cfg.rack != "" {
+		req.RackID = &cfg.rack
+	}
+	req.RebalanceTimeoutMillis = int32(cfg.rebalanceTimeout.Milliseconds())
+	if cfg.regex && g.regexSupported() {
+		req.SubscribedTopicRegex = g.subscribedRegex()
+	} else {
+		req.SubscribedTopicNames = g.subscribed()
+	}
+	if cfg.serverAssignor != "" {
+		req.ServerAssignor = &cfg.serverAssignor
+	}
+	req.Topics = g.owned()
+
+	resp, err := req.RequestWith(ctx, g.g.cl)
+	if err == nil {
+		err = kerr.ErrorForCode(resp.ErrorCode)
+	}
+	if err != nil {
+		return err
+	}
+
+	if resp.MemberID != nil {
+		memberID = *resp.MemberID
+	}
+	g.g.memberGen.store(memberID, resp.MemberEpoch)
+	if resp.HeartbeatIntervalMillis > 0 {
+		g.interval = time.Duration(resp.HeartbeatIntervalMillis) * time.Millisecond
+	}
+	if resp.Assignment != nil {
+		assigned := make(map[[16]byte][]int32, len(resp.Assignment.Topics))
+		for _, t := range resp.Assignment.Topics {
+			assigned[t.TopicID] = append(assigned[t.TopicID], t.Partitions...)
+		}
+		g.assigned = assigned
+	}
+	return nil
+}
+
+// fenced returns whether err means the broker removed us from the group or
+// moved our epoch forward. We must abandon all partitions we own, calling
+// onLost, and rejoin.
+func (*g848) fenced(err error) bool {
+	return errors.Is(err, kerr.FencedMemberEpoch) || errors.Is(err, kerr.UnknownMemberID)
+}
+
+// reset is called after a fatal session error, once everything we owned was
+// released through onLost (or onRevoked if we are leaving); we must rejoin
+// with epoch 0. We keep our member ID even if the broker no longer knows it:
+// since v1, the member ID is generated by the client and must be sent on
+// every heartbeat, including the first.
+func (g *g848) reset() {
+	g.g.memberGen.store(g.g.memberGen.memberID(), 0)
+	g.assigned = nil
+}
+
+// start generates our member ID before we first join (KIP-1082). The
+// broker replies with the same ID, unless it only supports v0, in which
+// case it generates the ID itself and we use that instead.
+func (g *g848) start() {
+	g.g.memberGen.store(newMemberID(), -1)
+}
+
+// newMemberID returns a random v4 UUID formatted as Kafka formats UUIDs:
+// unpadded URL safe base64. Like Kafka, we avoid IDs that begin with a dash
+// so that they cannot be mistaken for command line flags.
+func newMemberID() string {
+	var id [16]byte
+	for {
+		if _, err := rand.Read(id[:]); err != nil {
+			panic(fmt.Sprintf("unable to generate a member ID: %v", err))
+		}
+		id[6] = id[6]&0x0f | 0x40 // version 4
+		id[8] = id[8]&0x3f | 0x80 // RFC 4122 variant
+		if s := base64.RawURLEncoding.EncodeToString(id[:]); s[0] != '-' {
+			return s
+		}
+	}
+}
+
+// leave issues a heartbeat with epoch -1 to leave the group, or -2 if we are
+// a static member: the broker keeps our assignment for the session timeout
+// so that we can restart with the same instance ID.
+func (g *g848) leave(ctx context.Context) error {
+	memberID, epoch := g.g.memberGen.load()
+	if epoch < 0 { // we never joined
+		return nil
+	}
+	epoch = -1
+	if g.g.cfg.instanceID != nil {
+		epoch = -2
+	}
+	g.g.cfg.logger.Log(LogLevelInfo, "leaving group",
+		"group", g.g.cfg.group,
+		"member_id", memberID,
+		"member_epoch", epoch,
+	)
+	req := kmsg.NewPtrConsumerGroupHeartbeatRequest()
+	req.Group = g.g.cfg.group
+	req.MemberID = memberID
+	req.MemberEpoch = epoch
+	req.InstanceID = g.g.cfg.instanceID
+	resp, err := req.RequestWith(ctx, g.g.cl)
+	if err != nil {
+		return err
+	}
+	return kerr.ErrorForCode(resp.ErrorCode)
+}
+
+// subscribed returns the sorted topics we are using.
+func (g *g848) subscribed() []string {
+	g.g.mu.Lock()
+	topics := make([]string, 0, len(g.g.using))
+	for topic := range g.g.using {
+		topics = append(topics, topic)
+	}
+	g.g.mu.Unlock()
+	sort.Strings(topics)
+	return topics
+}
+
+// regexSupported returns whether we can subscribe with a regex, which
+// requires ConsumerGroupHeartbeat v1 from both the broker and our max
+// versions.
+func (g *g848) regexSupported() bool {
+	key := int16(kmsg.ConsumerGroupHeartbeat)
+	if mv := g.g.cfg.maxVersions; mv != nil {
+		if v, ok := mv.LookupMaxKeyVersion(key); !ok || v < 1 {
+			return false
+		}
+	}
+	return g.g.cl.supportsKeyVersion(key, 1)
+}
+
+// subscribedRegex returns our consume regexes as the single regex the broker
+// matches topics against. The broker requires a regex to match an entire
+// topic name, whereas we match regexes anywhere in a name, so each regex is
+// allowed to match within a longer name.
+func (g *g848) subscribedRegex() *string {
+	regexes := g.g.cl.GetConsumeRegex()
+	for i, re := range regexes {
+		regexes[i] = ".*(?:" + re + ").*"
+	}
+	regex := strings.Join(regexes, "|")
+	return &regex
+}
+
+// owned returns what we currently own, by topic ID.
+func (g *g848) owned() []kmsg.ConsumerGroupHeartbeatRequestTopic {
+	_, t2id := g.topicIDs()
+	owned := make([]kmsg.ConsumerGroupHeartbeatRequestTopic, 0)
+	for topic, partitions := range g.g.nowAssigned.read() {
+		id, ok := t2id[topic]
+		if !ok {
+			continue
+		}
+		t := kmsg.NewConsumerGroupHeartbeatRequestTopic()
+		t.TopicID = id
+		t.Partitions = append([]int32(nil), partitions...)
+		owned = append(owned, t)
+	}
+	return owned
+}
+
+// resolve maps our latest assignment to topic names, returning whether any
+// topic ID is not yet known in our metadata.
+func (g *g848) resolve() (resolved map[string][]int32, unresolved bool) {
+	id2t, _ := g.topicIDs()
+	resolved = make(map[string][]int32, len(g.assigned))
+	for id, partitions := range g.assigned {
+		topic, ok := id2t[id]
+		if !ok {
+			unresolved = true
+			continue
+		}
+		if len(partitions) == 0 {
+			continue
+		}
+		partitions = append([]int32(nil), partitions...)
+		slices.Sort(partitions)
+		resolved[topic] = partitions
+	}
+	return resolved, unresolved
+}
+
+// topicIDs returns the topic ID mappings for all topics the group has loaded
+// metadata for.
+func (g *g848) topicIDs() (map[[16]byte]string, map[string][16]byte) {
+	var noID [16]byte
+	tps := g.g.tps.load()
+	id2t := make(map[[16]byte]string, len(tps))
+	t2id := make(map[string][16]byte, len(tps))
+	for topic, tp := range tps {
+		parts := tp.load()
+		if len(parts.partitions) == 0 {
+			continue
+		}
+		id := parts.partitions[0].cursor.topicID
+		if id == noID {
+			continue
+		}
+		id2t[id] = topic
+		t2id[topic] = id
+	}
+	return id2t, t2id
+}
+
+// assignmentsEqual returns whether two assignments contain the same
+// partitions, ignoring order.
+func assignmentsEqual(l, r map[string][]int32) bool {
+	if len(l) != len(r) {
+		return false
+	}
+	for topic, lps := range l {
+		rps, ok := r[topic]
+		if !ok || len(lps) != len(rps) {
+			return false
+		}
+		lps = append([]int32(nil), lps...)
+		rps = append([]int32(nil), rps...)
+		slices.Sort(lps)
+		slices.Sort(rps)
+		if !slices.Equal(lps, rps) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,17 @@
+package kgo
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+)
+
+func TestGroup848NoConsumerProtocol(t *testing.T) {
+	if !errNoConsumerProtocol(errBrokerTooOld) || !errNoConsumerProtocol(errUnknownRequestKey) || !errNoConsumerProtocol(kerr.UnsupportedVersion) {
+		t.Error("expected too old brokers, pinned max versions, and unsupported versions to downgrade")
+	}
+	if errNoConsumerProtocol(kerr.FencedMemberEpoch) || errNoConsumerProtocol(errors.New("other")) {
+		t.Error("expected other errors to not downgrade")
+	}
+}
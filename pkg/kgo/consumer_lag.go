@@ -0,0 +1,237 @@
+package kgo
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// PartitionLag is the lag for a single consumed partition, as returned from
+// ConsumerLag. All offsets are -1 if unknown.
+type PartitionLag struct {
+	// End is the end offset of the partition: the high watermark, or the
+	// last stable offset if reading committed records.
+	End int64
+	// Polled is the offset after the last polled record, i.e. the offset
+	// of the next record that will be polled.
+	Polled int64
+	// Committed is the group's committed offset for the partition. This
+	// is always -1 if not consuming in a group.
+	Committed int64
+
+	// Lag is End minus Polled, or End minus Committed if nothing has been
+	// polled for the partition yet. This is -1 if unknown.
+	Lag int64
+	// CommittedLag is End minus Committed, or -1 if unknown.
+	CommittedLag int64
+
+	// Updated is when End was last updated, either from a fetch response
+	// or from listing offsets.
+	Updated time.Time
+}
+
+// consumerLag tracks end offsets and polled offsets for ConsumerLag. This
+// is only non-nil on the consumer if ConsumerLagInterval is used.
+type consumerLag struct {
+	mu    sync.Mutex
+	parts map[string]map[int32]*lagState
+}
+
+type lagState struct {
+	end     int64
+	polled  int64
+	updated time.Time
+}
+
+func (l *consumerLag) stateLocked(t string, p int32) *lagState {
+	ps := l.parts[t]
+	if ps == nil {
+		ps = make(map[int32]*lagState)
+		l.parts[t] = ps
+	}
+	s := ps[p]
+	if s == nil {
+		s = &lagState{end: -1, polled: -1}
+		ps[p] = s
+	}
+	return s
+}
+
+// setEnd updates the end offset for a partition. End offsets that move
+// backwards are ignored: responses from fetches and from listing offsets can
+// race, and the later response may be stale.
+func (s *lagState) setEnd(end int64, now time.Time) {
+	if end < 0 || end < s.end {
+		return
+	}
+	s.end = end
+	s.updated = now
+}
+
+// updatePolled updates polled offsets and end offsets from polled fetches.
+func (l *consumerLag) updatePolled(fetches Fetches, readCommitted bool) {
+	now := time.Now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	fetches.EachPartition(func(p FetchTopicPartition) {
+		if p.Err != nil {
+			return
+		}
+		s := l.stateLocked(p.Topic, p.Partition)
+		if len(p.Records) > 0 {
+			s.polled = p.Records[len(p.Records)-1].Offset + 1
+		}
+		if readCommitted {
+			s.setEnd(p.LastStableOffset, now)
+		} else {
+			s.setEnd(p.HighWatermark, now)
+		}
+	})
+}
+
+// ConsumerLag returns the current lag for every partition being consumed, if
+// ConsumerLagInterval is used. This returns nil if ConsumerLagInterval is not
+// used.
+//
+// End offsets are updated from every fetch response and from listing offsets
+// every ConsumerLagInterval, so lag is kept fresh even while partitions are
+// paused or fetches are not being polled. The polled offset is the offset
+// after the last record returned from polling, and the committed offset is
+// the group's latest committed offset (see CommittedOffsets).
+func (cl *Client) ConsumerLag() map[string]map[int32]PartitionLag {
+	l := cl.consumer.lag
+	if l == nil {
+		return nil
+	}
+	committed := cl.CommittedOffsets()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	lag := make(map[string]map[int32]PartitionLag, len(l.parts))
+	for t, ps := range l.parts {
+		tlag := make(map[int32]PartitionLag, len(ps))
+		lag[t] = tlag
+		for p, s := range ps {
+			pl := PartitionLag{
+				End:          s.end,
+				Polled:       s.polled,
+				Committed:    -1,
+				Lag:          -1,
+				CommittedLag: -1,
+				Updated:      s.updated,
+			}
+			if c, ok := committed[t][p]; ok && c.Offset >= 0 {
+				pl.Committed = c.Offset
+			}
+			if pl.End >= 0 {
+				if pl.Committed >= 0 {
+					pl.CommittedLag = max0(pl.End - pl.Committed)
+					pl.Lag = pl.CommittedLag
+				}
+				if pl.Polled >= 0 {
+					pl.Lag = max0(pl.End - pl.Polled)
+				}
+			}
+			tlag[p] = pl
+		}
+	}
+	return lag
+}
+
+func max0(n int64) int64 {
+	if n < 0 {
+		return 0
+	}
+	return n
+}
+
+// lagLoop lists end offsets for all consumed partitions every
+// ConsumerLagInterval until the client is closed.
+func (c *consumer) lagLoop() {
+	ticker := time.NewTicker(c.cl.cfg.consumerLagInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.cl.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		c.listLagEnds()
+	}
+}
+
+// listLagEnds lists end offsets for the partitions currently being consumed,
+// and drops lag for partitions that are no longer being consumed.
+func (c *consumer) listLagEnds() {
+	assigned := make(map[string]map[int32]bool)
+	c.mu.Lock()
+	for cursor := range c.usingCursors {
+		ps := assigned[cursor.topic]
+		if ps == nil {
+			ps = make(map[int32]bool)
+			assigned[cursor.topic] = ps
+		}
+		ps[cursor.partition] = true
+	}
+	c.mu.Unlock()
+
+	l := c.lag
+	l.mu.Lock()
+	for t, ps := range l.parts {
+		for p := range ps {
+			if !assigned[t][p] {
+				delete(ps, p)
+			}
+		}
+		if len(ps) == 0 {
+			delete(l.parts, t)
+		}
+	}
+	l.mu.Unlock()
+
+	if len(assigned) == 0 {
+		return
+	}
+
+	req := kmsg.NewPtrListOffsetsRequest()
+	req.ReplicaID = -1
+	req.IsolationLevel = c.cl.cfg.isolationLevel
+	for t, ps := range assigned {
+		rt := kmsg.NewListOffsetsRequestTopic()
+		rt.Topic = t
+		for p := range ps {
+			rp := kmsg.NewListOffsetsRequestTopicPartition()
+			rp.Partition = p
+			rp.Timestamp = -1 // latest
+			rt.Partitions = append(rt.Partitions, rp)
+		}
+		req.Topics = append(req.Topics, rt)
+	}
+
+	ctx, cancel := context.WithTimeout(c.cl.ctx, c.cl.cfg.consumerLagInterval)
+	defer cancel()
+	shards := c.cl.RequestSharded(ctx, req)
+
+	now := time.Now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, shard := range shards {
+		if shard.Err != nil {
+			c.cl.cfg.logger.Log(LogLevelDebug, "unable to list end offsets for consumer lag", "broker", logID(shard.Meta.NodeID), "err", shard.Err)
+			continue
+		}
+		resp := shard.Resp.(*kmsg.ListOffsetsResponse)
+		for _, rt := range resp.Topics {
+			for _, rp := range rt.Partitions {
+				if err := kerr.ErrorForCode(rp.ErrorCode); err != nil {
+					continue
+				}
+				l.stateLocked(rt.Topic, rp.Partition).setEnd(rp.Offset, now)
+			}
+		}
+	}
+}
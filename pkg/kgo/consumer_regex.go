@@ -0,0 +1,134 @@
+package kgo
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+)
+
+var errNotRegexConsuming = errors.New("client is not consuming via regex")
+
+// AddConsumeRegex adds regular expressions to match topics against. This
+// function works only for clients that are consuming via regex, and returns
+// an error if the client is not or if any regular expression is invalid.
+//
+// Topics that were previously evaluated and did not match any regular
+// expression are re-evaluated on the next metadata update, which is
+// triggered immediately.
+func (cl *Client) AddConsumeRegex(regexes ...string) error {
+	c := &cl.consumer
+	if !cl.cfg.regex || !c.consuming() {
+		return errNotRegexConsuming
+	}
+	compiled := make(map[string]*regexp.Regexp, len(regexes))
+	for _, re := range regexes {
+		rc, err := regexp.Compile(re)
+		if err != nil {
+			return fmt.Errorf("invalid regular expression %q", re)
+		}
+		compiled[re] = rc
+	}
+	if len(compiled) == 0 {
+		return nil
+	}
+
+	cl.blockingMetadataFn(func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		for raw, rc := range compiled {
+			c.regexes[raw] = rc
+		}
+		reSeen := c.reSeen()
+		for topic, want := range reSeen {
+			if !want {
+				delete(reSeen, topic)
+			}
+		}
+	})
+	cl.triggerUpdateMetadataNow("from AddConsumeRegex")
+	return nil
+}
+
+// RemoveConsumeRegex removes regular expressions that topics are matched
+// against. Topics that no longer match any remaining regular expression are
+// purged from consuming, as if by PurgeTopicsFromConsuming. This function
+// works only for clients that are consuming via regex, and is a no-op
+// otherwise.
+func (cl *Client) RemoveConsumeRegex(regexes ...string) {
+	c := &cl.consumer
+	if !cl.cfg.regex || !c.consuming() || len(regexes) == 0 {
+		return
+	}
+
+	cl.blockingMetadataFn(func() {
+		var purge []string
+		func() {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			for _, re := range regexes {
+				delete(c.regexes, re)
+			}
+		topics:
+			for topic, want := range c.reSeen() {
+				if !want {
+					continue
+				}
+				for _, rc := range c.regexes {
+					if rc.MatchString(topic) {
+						continue topics
+					}
+				}
+				purge = append(purge, topic)
+			}
+		}()
+		if len(purge) > 0 {
+			sort.Strings(purge)
+			cl.cfg.logger.Log(LogLevelInfo, "purging topics that no longer match any consume regex", "topics", purge)
+			c.purgeTopics(purge)
+		}
+	})
+}
+
+// GetConsumeRegex returns the regular expressions currently being used to
+// match topics, or nil if the client is not consuming via regex.
+func (cl *Client) GetConsumeRegex() []string {
+	c := &cl.consumer
+	if !cl.cfg.regex || !c.consuming() {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	regexes := make([]string, 0, len(c.regexes))
+	for re := range c.regexes {
+		regexes = append(regexes, re)
+	}
+	sort.Strings(regexes)
+	return regexes
+}
+
+// reSeen returns the topics evaluated against regular expressions for the
+// direct or group consumer. This must be called with mu held.
+func (c *consumer) reSeen() map[string]bool {
+	if c.d != nil {
+		return c.d.reSeen
+	}
+	return c.g.reSeen
+}
+
+// regexDiscoveryLoop triggers a metadata update every
+// ConsumeRegexDiscoveryInterval until the client is closed, so that new
+// topics are discovered sooner than the metadata max age.
+func (c *consumer) regexDiscoveryLoop() {
+	ticker := time.NewTicker(c.cl.cfg.regexDiscoveryInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.cl.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		c.cl.triggerUpdateMetadata(true, "regex topic discovery")
+	}
+}
@@ -0,0 +1,112 @@
+package kgo
+
+import (
+	"context"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// SeekToTimestamp sets the consume offset of partitions to the first offset
+// at or after the given timestamp, and returns the offsets that were set. If
+// partitions is nil, this seeks all partitions that are currently being
+// consumed. Partitions with no record at or after the timestamp are seeked to
+// the end of the partition.
+//
+// Offsets are resolved with ListOffsets and are then set with SetOffsets, so
+// all of the caveats in the SetOffsets documentation apply: notably, only
+// partitions that were previously consumed are set, and if group consuming,
+// this should not be used concurrently with a rebalance or with committing.
+//
+// If resolving offsets for some partitions fails, the offsets for the other
+// partitions are still set and the first error is returned.
+func (cl *Client) SeekToTimestamp(ctx context.Context, ts time.Time, partitions map[string][]int32) (map[string]map[int32]EpochOffset, error) {
+	if partitions == nil {
+		partitions = cl.consumer.consumingPartitions()
+	}
+	if len(partitions) == 0 {
+		return nil, nil
+	}
+
+	offsets := make(map[string]map[int32]EpochOffset)
+	atEnd, err := cl.listOffsetsAt(ctx, partitions, ts.UnixMilli(), offsets)
+	if len(atEnd) > 0 {
+		_, endErr := cl.listOffsetsAt(ctx, atEnd, -1, offsets)
+		if err == nil {
+			err = endErr
+		}
+	}
+	cl.SetOffsets(offsets)
+	return offsets, err
+}
+
+// consumingPartitions returns all partitions currently being consumed.
+func (c *consumer) consumingPartitions() map[string][]int32 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	parts := make(map[string][]int32)
+	for cursor := range c.usingCursors {
+		parts[cursor.topic] = append(parts[cursor.topic], cursor.partition)
+	}
+	return parts
+}
+
+// listOffsetsAt lists offsets for the given timestamp, adding them to
+// offsets, and returns partitions that have no offset for the timestamp along
+// with the first error encountered.
+func (cl *Client) listOffsetsAt(ctx context.Context, partitions map[string][]int32, timestamp int64, offsets map[string]map[int32]EpochOffset) (map[string][]int32, error) {
+	req := kmsg.NewPtrListOffsetsRequest()
+	req.ReplicaID = -1
+	req.IsolationLevel = cl.cfg.isolationLevel
+	for t, ps := range partitions {
+		rt := kmsg.NewListOffsetsRequestTopic()
+		rt.Topic = t
+		for _, p := range ps {
+			rp := kmsg.NewListOffsetsRequestTopicPartition()
+			rp.Partition = p
+			rp.Timestamp = timestamp
+			rt.Partitions = append(rt.Partitions, rp)
+		}
+		req.Topics = append(req.Topics, rt)
+	}
+
+	var (
+		missing  map[string][]int32
+		firstErr error
+	)
+	for _, shard := range cl.RequestSharded(ctx, req) {
+		if shard.Err != nil {
+			if firstErr == nil {
+				firstErr = shard.Err
+			}
+			continue
+		}
+		resp := shard.Resp.(*kmsg.ListOffsetsResponse)
+		for _, rt := range resp.Topics {
+			for _, rp := range rt.Partitions {
+				if err := kerr.ErrorForCode(rp.ErrorCode); err != nil {
+					if firstErr == nil {
+						firstErr = err
+					}
+					continue
+				}
+				if rp.Offset < 0 {
+					if missing == nil {
+						missing = make(map[string][]int32)
+					}
+					missing[rt.Topic] = append(missing[rt.Topic], rp.Partition)
+					continue
+				}
+				to := offsets[rt.Topic]
+				if to == nil {
+					to = make(map[int32]EpochOffset)
+					offsets[rt.Topic] = to
+				}
+				to[rp.Partition] = EpochOffset{rp.LeaderEpoch, rp.Offset}
+			}
+		}
+	}
+	return missing, firstErr
+}
@@ -0,0 +1,119 @@
+package kgo
+
+import (
+	"context"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// OffsetValidation is the result of validating an EpochOffset with
+// ValidateOffsets.
+type OffsetValidation struct {
+	// Offset is the offset and epoch that was validated.
+	Offset EpochOffset
+
+	// End is where the leader epoch of Offset ends in the partition's
+	// log. End.Epoch is the largest epoch in the log that is at or before
+	// Offset.Epoch, and End.Offset is the first offset after that epoch.
+	// If Offset.Epoch is the partition's current leader epoch, End.Offset
+	// is the partition's log end offset.
+	End EpochOffset
+
+	// Err is non-nil if the offset could not be validated, or is
+	// *ErrDataLoss if Offset is past End.Offset: the log was truncated or
+	// diverged after the offset was recorded, and everything from
+	// End.Offset to Offset was lost. ErrDataLoss.ResetTo is End.Offset,
+	// which is where the client itself would resume consuming.
+	Err error
+}
+
+// ValidateOffsets validates offsets against the cluster with
+// OffsetForLeaderEpoch, the same check the consumer runs internally before
+// resuming from a committed or set offset. This is useful if you manage
+// offsets outside of Kafka and want to detect truncation (for example, after
+// an unclean leader election) before consuming from your stored offsets.
+//
+// As with committing, each EpochOffset is expected to contain the offset to
+// resume from (one past the last processed record) and the leader epoch of
+// the last processed record. Offsets with a negative epoch cannot be
+// validated and are returned as is with End equal to Offset.
+//
+// This requires Kafka 2.1+; validation for every offset fails with an error
+// if brokers are too old to support OffsetForLeaderEpoch v2.
+func (cl *Client) ValidateOffsets(ctx context.Context, offsets map[string]map[int32]EpochOffset) map[string]map[int32]OffsetValidation {
+	validated := make(map[string]map[int32]OffsetValidation, len(offsets))
+	set := func(topic string, partition int32, v OffsetValidation) {
+		vt := validated[topic]
+		if vt == nil {
+			vt = make(map[int32]OffsetValidation)
+			validated[topic] = vt
+		}
+		vt[partition] = v
+	}
+
+	req := kmsg.NewPtrOffsetForLeaderEpochRequest()
+	req.ReplicaID = -1
+	for topic, partitions := range offsets {
+		rt := kmsg.NewOffsetForLeaderEpochRequestTopic()
+		rt.Topic = topic
+		for partition, offset := range partitions {
+			if offset.Epoch < 0 {
+				set(topic, partition, OffsetValidation{Offset: offset, End: offset})
+				continue
+			}
+			rp := kmsg.NewOffsetForLeaderEpochRequestTopicPartition()
+			rp.Partition = partition
+			rp.CurrentLeaderEpoch = -1
+			rp.LeaderEpoch = offset.Epoch
+			rt.Partitions = append(rt.Partitions, rp)
+		}
+		if len(rt.Partitions) > 0 {
+			req.Topics = append(req.Topics, rt)
+		}
+	}
+	if len(req.Topics) == 0 {
+		return validated
+	}
+
+	for _, shard := range cl.RequestSharded(ctx, req) {
+		if shard.Err != nil {
+			for _, rt := range shard.Req.(*kmsg.OffsetForLeaderEpochRequest).Topics {
+				for _, rp := range rt.Partitions {
+					offset := offsets[rt.Topic][rp.Partition]
+					set(rt.Topic, rp.Partition, OffsetValidation{Offset: offset, Err: shard.Err})
+				}
+			}
+			continue
+		}
+		resp := shard.Resp.(*kmsg.OffsetForLeaderEpochResponse)
+		for _, rt := range resp.Topics {
+			for _, rp := range rt.Partitions {
+				offset, ok := offsets[rt.Topic][rp.Partition]
+				if !ok {
+					continue // should not happen: kafka replied with something we did not ask for
+				}
+				v := OffsetValidation{Offset: offset}
+				if err := kerr.ErrorForCode(rp.ErrorCode); err != nil {
+					v.Err = err
+				} else {
+					v.End = EpochOffset{rp.LeaderEpoch, rp.EndOffset}
+					if rp.EndOffset < offset.Offset {
+						v.Err = &ErrDataLoss{rt.Topic, rp.Partition, offset.Offset, rp.EndOffset}
+					}
+				}
+				set(rt.Topic, rp.Partition, v)
+			}
+		}
+	}
+
+	// Anything Kafka did not reply to is unknown.
+	for topic, partitions := range offsets {
+		for partition, offset := range partitions {
+			if _, ok := validated[topic][partition]; !ok {
+				set(topic, partition, OffsetValidation{Offset: offset, Err: kerr.UnknownTopicOrPartition})
+			}
+		}
+	}
+	return validated
+}
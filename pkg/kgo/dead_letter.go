@@ -0,0 +1,174 @@
+package kgo
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Headers added to records produced to a dead letter topic, describing where
+// the record came from and why it failed.
+const (
+	DeadLetterHeaderTopic     = "dlq.original.topic"
+	DeadLetterHeaderPartition = "dlq.original.partition"
+	DeadLetterHeaderOffset    = "dlq.original.offset"
+	DeadLetterHeaderError     = "dlq.error"
+	DeadLetterHeaderAttempts  = "dlq.attempts"
+)
+
+// DeadLetterQueue wraps processing consumed records such that a record that
+// fails processing too many times is produced to a dead letter topic rather
+// than blocking consumption forever. Once a record is either processed
+// successfully or dead lettered, it is considered done and is committed:
+//
+//   - if the client uses AutoCommitMarks, the record is marked for commit
+//   - if the client disables autocommitting, the record is committed
+//     synchronously with CommitRecords
+//   - otherwise, the client's autocommitting commits the record as usual
+//
+// A dead letter record has the same key, value, and headers as the original
+// record, plus DeadLetterHeader headers that describe the original topic,
+// partition, offset, the last processing error, and the number of attempts.
+// Dead letter records are produced synchronously, so a record is never
+// committed before its dead letter record is written.
+type DeadLetterQueue struct {
+	cl    *Client
+	topic string
+
+	attempts int
+	backoff  func(int) time.Duration
+}
+
+// DeadLetterOpt is an option to configure a DeadLetterQueue.
+type DeadLetterOpt interface {
+	apply(*DeadLetterQueue)
+}
+
+type dlqOpt struct{ fn func(*DeadLetterQueue) }
+
+func (opt dlqOpt) apply(q *DeadLetterQueue) { opt.fn(q) }
+
+// DeadLetterAttempts sets how many times a record is processed before it is
+// dead lettered, overriding the default of 3. Values less than one are
+// treated as one.
+func DeadLetterAttempts(n int) DeadLetterOpt {
+	return dlqOpt{func(q *DeadLetterQueue) {
+		if n < 1 {
+			n = 1
+		}
+		q.attempts = n
+	}}
+}
+
+// DeadLetterBackoff sets how long to wait before retrying processing a record
+// after the given failed attempt (starting at one), overriding the default of
+// no backoff.
+func DeadLetterBackoff(fn func(attempt int) time.Duration) DeadLetterOpt {
+	return dlqOpt{func(q *DeadLetterQueue) { q.backoff = fn }}
+}
+
+// NewDeadLetterQueue returns a DeadLetterQueue that uses the client to commit
+// processed records and to produce dead letter records to topic.
+func NewDeadLetterQueue(cl *Client, topic string, opts ...DeadLetterOpt) *DeadLetterQueue {
+	q := &DeadLetterQueue{
+		cl:       cl,
+		topic:    topic,
+		attempts: 3,
+		backoff:  func(int) time.Duration { return 0 },
+	}
+	for _, opt := range opts {
+		opt.apply(q)
+	}
+	return q
+}
+
+// Process processes a consumed record with fn, retrying on error, and dead
+// letters the record if every attempt fails. Once the record is done, it is
+// committed as described in the DeadLetterQueue documentation.
+//
+// This returns an error only if the context is canceled before the record is
+// done, if producing the dead letter record fails, or if committing fails. If
+// this returns an error, the record is not committed and should be processed
+// again.
+func (q *DeadLetterQueue) Process(ctx context.Context, r *Record, fn func(context.Context, *Record) error) error {
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx, r)
+		if err == nil {
+			break
+		}
+		if attempt >= q.attempts {
+			if err := q.produce(ctx, r, err, attempt); err != nil {
+				return err
+			}
+			break
+		}
+		if backoff := q.backoff(attempt); backoff > 0 {
+			timer := time.NewTimer(backoff)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			case <-timer.C:
+			}
+		} else if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+	return q.commit(ctx, r)
+}
+
+// ProcessFetches calls Process for every record in fetches, in order,
+// stopping at and returning the first error.
+func (q *DeadLetterQueue) ProcessFetches(ctx context.Context, fetches Fetches, fn func(context.Context, *Record) error) error {
+	for iter := fetches.RecordIter(); !iter.Done(); {
+		if err := q.Process(ctx, iter.Next(), fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (q *DeadLetterQueue) produce(ctx context.Context, r *Record, perr error, attempts int) error {
+	headers := make([]RecordHeader, 0, len(r.Headers)+5)
+	headers = append(headers, r.Headers...)
+	headers = append(headers,
+		RecordHeader{Key: DeadLetterHeaderTopic, Value: []byte(r.Topic)},
+		RecordHeader{Key: DeadLetterHeaderPartition, Value: strconv.AppendInt(nil, int64(r.Partition), 10)},
+		RecordHeader{Key: DeadLetterHeaderOffset, Value: strconv.AppendInt(nil, r.Offset, 10)},
+		RecordHeader{Key: DeadLetterHeaderError, Value: []byte(perr.Error())},
+		RecordHeader{Key: DeadLetterHeaderAttempts, Value: strconv.AppendInt(nil, int64(attempts), 10)},
+	)
+	dead := &Record{
+		Key:     r.Key,
+		Value:   r.Value,
+		Headers: headers,
+		Topic:   q.topic,
+	}
+	q.cl.cfg.logger.Log(LogLevelWarn, "dead lettering record after failed processing",
+		"topic", r.Topic,
+		"partition", r.Partition,
+		"offset", r.Offset,
+		"attempts", attempts,
+		"err", perr,
+	)
+	if err := q.cl.ProduceSync(ctx, dead).FirstErr(); err != nil {
+		return fmt.Errorf("unable to produce dead letter record: %w", err)
+	}
+	return nil
+}
+
+func (q *DeadLetterQueue) commit(ctx context.Context, r *Record) error {
+	cfg := &q.cl.cfg
+	switch {
+	case cfg.group == "" || cfg.txnID != nil:
+		return nil
+	case cfg.autocommitMarks:
+		q.cl.MarkCommitRecords(r)
+		return nil
+	case cfg.autocommitDisable:
+		return q.cl.CommitRecords(ctx, r)
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,317 @@
+package kgo
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// Headers added to records encrypted with RecordEncryption.
+const (
+	EncryptionHeaderKeyID = "enc.key.id" // the ID of the key that wrapped the data key
+	EncryptionHeaderDEK   = "enc.dek"    // the wrapped data key
+)
+
+// maxUnwrappedKeys bounds how many unwrapped data keys RecordEncryption
+// caches for decrypting.
+const maxUnwrappedKeys = 1000
+
+// KeyWrapper wraps and unwraps data keys for RecordEncryption, normally with
+// a key management service. Implementations must be safe for concurrent use.
+type KeyWrapper interface {
+	// WrapKey encrypts a data key, returning the ID of the key that
+	// wrapped it and the wrapped data key.
+	WrapKey(dek []byte) (keyID string, wrapped []byte, err error)
+	// UnwrapKey decrypts a data key that was wrapped by the key with the
+	// given ID.
+	UnwrapKey(keyID string, wrapped []byte) ([]byte, error)
+}
+
+// RecordEncryption envelope encrypts record values when producing and
+// decrypts them when consuming. A RecordEncryption is both a
+// ProducerInterceptor and a ConsumerInterceptor, and is used by passing it to
+// both ProducerInterceptors and ConsumerInterceptors. Because interceptors
+// are called before records are buffered, values are encrypted before they
+// are batched and compressed.
+//
+// Values are encrypted with AES-256-GCM using a random data key. The data key
+// is wrapped with the KeyWrapper, and the key ID and wrapped data key are
+// added to the record's EncryptionHeader headers. A data key is reused until
+// it is rotated, so the KeyWrapper is not called for every record. Record
+// keys are not encrypted, so that partitioning and compaction are unaffected,
+// and nil values (tombstones) are left as is.
+//
+// Once a record is produced, its value and context are restored to the
+// originals and the encryption headers are removed before its promise is
+// called.
+//
+// When consuming, records with EncryptionHeaderKeyID headers are decrypted and
+// the encryption headers are removed. If a record cannot be decrypted, the
+// record is left as is and the record's partition error is set, if it is
+// not already set.
+type RecordEncryption struct {
+	kw     KeyWrapper
+	rotate time.Duration
+
+	mu        sync.Mutex
+	dek       *dataKey
+	unwrapped map[string]cipher.AEAD
+}
+
+type dataKey struct {
+	aead    cipher.AEAD
+	keyID   string
+	wrapped []byte
+	created time.Time
+}
+
+// encryptedRecord is stored in an encrypted record's context to restore the
+// record once it is produced.
+type encryptedRecord struct {
+	ctx       context.Context
+	plaintext []byte
+}
+
+type encryptedRecordKey struct{}
+
+var (
+	_ ProducerInterceptor = new(RecordEncryption)
+	_ ConsumerInterceptor = new(RecordEncryption)
+)
+
+// NewRecordEncryption returns a RecordEncryption that wraps data keys with kw
+// and rotates to a new data key every rotate. If rotate is zero, data keys
+// are rotated hourly.
+func NewRecordEncryption(kw KeyWrapper, rotate time.Duration) *RecordEncryption {
+	if rotate <= 0 {
+		rotate = time.Hour
+	}
+	return &RecordEncryption{
+		kw:        kw,
+		rotate:    rotate,
+		unwrapped: make(map[string]cipher.AEAD),
+	}
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// currentKey returns the data key to encrypt with, creating a new one if
+// there is none or the current one is due for rotation.
+func (e *RecordEncryption) currentKey() (*dataKey, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.dek != nil && time.Since(e.dek.created) < e.rotate {
+		return e.dek, nil
+	}
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, fmt.Errorf("unable to generate data key: %w", err)
+	}
+	aead, err := newAEAD(raw)
+	if err != nil {
+		return nil, err
+	}
+	keyID, wrapped, err := e.kw.WrapKey(raw)
+	if err != nil {
+		return nil, fmt.Errorf("unable to wrap data key: %w", err)
+	}
+	e.dek = &dataKey{aead, keyID, wrapped, time.Now()}
+	return e.dek, nil
+}
+
+// OnSend implements ProducerInterceptor, encrypting the record's value.
+func (e *RecordEncryption) OnSend(r *Record) error {
+	if r.Value == nil {
+		return nil
+	}
+	dek, err := e.currentKey()
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, dek.aead.NonceSize(), dek.aead.NonceSize()+len(r.Value)+dek.aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("unable to generate nonce: %w", err)
+	}
+	if r.Context != nil {
+		r.Context = context.WithValue(r.Context, encryptedRecordKey{}, &encryptedRecord{r.Context, r.Value})
+	}
+	r.Value = dek.aead.Seal(nonce, nonce, r.Value, nil)
+	r.Headers = append(r.Headers,
+		RecordHeader{Key: EncryptionHeaderKeyID, Value: []byte(dek.keyID)},
+		RecordHeader{Key: EncryptionHeaderDEK, Value: dek.wrapped},
+	)
+	return nil
+}
+
+// OnAck implements ProducerInterceptor, restoring the record's plaintext
+// value.
+func (*RecordEncryption) OnAck(r *Record, _ error) {
+	if r.Context == nil {
+		return
+	}
+	orig, ok := r.Context.Value(encryptedRecordKey{}).(*encryptedRecord)
+	if !ok {
+		return
+	}
+	r.Context = orig.ctx
+	r.Value = orig.plaintext
+	r.Headers = stripEncryptionHeaders(r.Headers)
+}
+
+// OnPoll implements ConsumerInterceptor, decrypting all encrypted records.
+func (e *RecordEncryption) OnPoll(fetches Fetches) Fetches {
+	for i := range fetches {
+		for j := range fetches[i].Topics {
+			t := &fetches[i].Topics[j]
+			for k := range t.Partitions {
+				p := &t.Partitions[k]
+				for _, r := range p.Records {
+					if err := e.decrypt(r); err != nil && p.Err == nil {
+						p.Err = fmt.Errorf("unable to decrypt record at offset %d: %w", r.Offset, err)
+					}
+				}
+			}
+		}
+	}
+	return fetches
+}
+
+// OnCommit implements ConsumerInterceptor and does nothing.
+func (*RecordEncryption) OnCommit(*kmsg.OffsetCommitRequest, *kmsg.OffsetCommitResponse, error) {}
+
+func (e *RecordEncryption) decrypt(r *Record) error {
+	var (
+		keyID, wrapped []byte
+		encrypted      bool
+	)
+	for _, h := range r.Headers {
+		switch h.Key {
+		case EncryptionHeaderKeyID:
+			keyID, encrypted = h.Value, true
+		case EncryptionHeaderDEK:
+			wrapped = h.Value
+		}
+	}
+	if !encrypted {
+		return nil
+	}
+
+	aead, err := e.unwrap(string(keyID), wrapped)
+	if err != nil {
+		return err
+	}
+	ns := aead.NonceSize()
+	if len(r.Value) < ns {
+		return errors.New("encrypted value is too short")
+	}
+	plaintext, err := aead.Open(nil, r.Value[:ns], r.Value[ns:], nil)
+	if err != nil {
+		return err
+	}
+	r.Value = plaintext
+	r.Headers = stripEncryptionHeaders(r.Headers)
+	return nil
+}
+
+// unwrap returns the data key for the wrapped key, caching unwrapped keys so
+// that the KeyWrapper is called once per data key.
+func (e *RecordEncryption) unwrap(keyID string, wrapped []byte) (cipher.AEAD, error) {
+	cacheKey := keyID + "\x00" + string(wrapped)
+
+	e.mu.Lock()
+	aead := e.unwrapped[cacheKey]
+	e.mu.Unlock()
+	if aead != nil {
+		return aead, nil
+	}
+
+	raw, err := e.kw.UnwrapKey(keyID, wrapped)
+	if err != nil {
+		return nil, fmt.Errorf("unable to unwrap data key: %w", err)
+	}
+	if aead, err = newAEAD(raw); err != nil {
+		return nil, err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if len(e.unwrapped) >= maxUnwrappedKeys {
+		e.unwrapped = make(map[string]cipher.AEAD)
+	}
+	e.unwrapped[cacheKey] = aead
+	return aead, nil
+}
+
+func stripEncryptionHeaders(headers []RecordHeader) []RecordHeader {
+	keep := headers[:0]
+	for _, h := range headers {
+		if h.Key != EncryptionHeaderKeyID && h.Key != EncryptionHeaderDEK {
+			keep = append(keep, h)
+		}
+	}
+	return keep
+}
+
+// AESKeyWrapper is a KeyWrapper that wraps data keys with AES-GCM using local
+// keys, which is useful for testing or when keys are distributed out of band.
+// Data keys are wrapped with the current key and can be unwrapped with any
+// key, allowing keys to be rotated.
+type AESKeyWrapper struct {
+	current string
+	keys    map[string]cipher.AEAD
+}
+
+// NewAESKeyWrapper returns an AESKeyWrapper that wraps data keys with the key
+// named current. Keys must be 16, 24, or 32 bytes, selecting AES-128,
+// AES-192, or AES-256.
+func NewAESKeyWrapper(current string, keys map[string][]byte) (*AESKeyWrapper, error) {
+	w := &AESKeyWrapper{current: current, keys: make(map[string]cipher.AEAD, len(keys))}
+	for id, key := range keys {
+		aead, err := newAEAD(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %q: %w", id, err)
+		}
+		w.keys[id] = aead
+	}
+	if _, ok := w.keys[current]; !ok {
+		return nil, fmt.Errorf("missing current key %q", current)
+	}
+	return w, nil
+}
+
+// WrapKey implements KeyWrapper.
+func (w *AESKeyWrapper) WrapKey(dek []byte) (string, []byte, error) {
+	aead := w.keys[w.current]
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(dek)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", nil, err
+	}
+	return w.current, aead.Seal(nonce, nonce, dek, nil), nil
+}
+
+// UnwrapKey implements KeyWrapper.
+func (w *AESKeyWrapper) UnwrapKey(keyID string, wrapped []byte) ([]byte, error) {
+	aead, ok := w.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("unknown key %q", keyID)
+	}
+	ns := aead.NonceSize()
+	if len(wrapped) < ns {
+		return nil, errors.New("wrapped key is too short")
+	}
+	return aead.Open(nil, wrapped[:ns], wrapped[ns:], nil)
+}
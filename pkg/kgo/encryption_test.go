@@ -0,0 +1,75 @@
+package kgo
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func TestRecordEncryption(t *testing.T) {
+	kw, err := NewAESKeyWrapper("k1", map[string][]byte{
+		"k1": bytes.Repeat([]byte{1}, 32),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := NewRecordEncryption(kw, 0)
+
+	ctx := context.Background()
+	r := &Record{
+		Key:     []byte("key"),
+		Value:   []byte("secret"),
+		Headers: []RecordHeader{{Key: "h"}},
+		Context: ctx,
+	}
+	if err := e.OnSend(r); err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(r.Value, []byte("secret")) || len(r.Headers) != 3 {
+		t.Fatalf("record not encrypted: %v", r)
+	}
+
+	// Simulate consuming a copy of the produced record.
+	consumed := &Record{
+		Key:     r.Key,
+		Value:   append([]byte(nil), r.Value...),
+		Headers: append([]RecordHeader(nil), r.Headers...),
+	}
+	tombstone := &Record{Key: []byte("key")}
+	if err := e.OnSend(tombstone); err != nil || tombstone.Value != nil || len(tombstone.Headers) != 0 {
+		t.Errorf("tombstone unexpectedly modified: %v, %v", tombstone, err)
+	}
+
+	e.OnAck(r, nil)
+	if string(r.Value) != "secret" || len(r.Headers) != 1 || r.Context != ctx {
+		t.Errorf("produced record not restored: %v", r)
+	}
+
+	// Decrypting must not need the producer's cached data key.
+	d := NewRecordEncryption(kw, 0)
+	fetches := Fetches{{Topics: []FetchTopic{{
+		Topic:      "t",
+		Partitions: []FetchPartition{{Records: []*Record{consumed, tombstone}}},
+	}}}}
+	fetches = d.OnPoll(fetches)
+	if err := fetches.Err(); err != nil {
+		t.Fatalf("unexpected decrypt err: %v", err)
+	}
+	if string(consumed.Value) != "secret" || len(consumed.Headers) != 1 {
+		t.Errorf("consumed record not decrypted: %v", consumed)
+	}
+
+	// A record wrapped with an unknown key fails its partition.
+	other, _ := NewAESKeyWrapper("k2", map[string][]byte{"k2": bytes.Repeat([]byte{2}, 16)})
+	r = &Record{Value: []byte("secret")}
+	if err := NewRecordEncryption(other, 0).OnSend(r); err != nil {
+		t.Fatal(err)
+	}
+	fetches = d.OnPoll(Fetches{{Topics: []FetchTopic{{
+		Topic:      "t",
+		Partitions: []FetchPartition{{Records: []*Record{r}}},
+	}}}})
+	if fetches.Err() == nil {
+		t.Error("unexpected success decrypting with an unknown key")
+	}
+}
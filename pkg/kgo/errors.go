@@ -190,10 +190,23 @@ var (
 	// TryProduce.
 	ErrMaxBuffered = errors.New("the maximum amount of records are buffered, cannot buffer more")
 
+	// ErrMaxBufferedPartition is returned for records that are produced
+	// to a topic or partition that has MaxBufferedBytesPerTopic or
+	// MaxBufferedBytesPerPartition bytes buffered.
+	ErrMaxBufferedPartition = errors.New("the maximum amount of bytes are buffered for the record's topic or partition, cannot buffer more")
+
+	// ErrRateLimited is returned when producing with TryProduce would
+	// exceed MaxProduceRecordsPerSecond or MaxProduceBytesPerSecond.
+	ErrRateLimited = errors.New("the maximum produce rate is reached, cannot buffer more")
+
 	// ErrAborting is returned for all buffered records while
 	// AbortBufferedRecords is being called.
 	ErrAborting = errors.New("client is aborting buffered records")
 
+	// ErrDraining is injected into poll responses once Drain has been
+	// called, signaling that the client no longer returns records.
+	ErrDraining = errors.New("client is draining and no longer returns records")
+
 	// ErrClientClosed is returned in various places when the client's
 	// Close function has been called.
 	//
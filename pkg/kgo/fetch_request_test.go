@@ -0,0 +1,47 @@
+package kgo
+
+import (
+	"testing"
+
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestFetchRequestV17(t *testing.T) {
+	f := &fetchRequest{
+		maxWait:      100,
+		maxBytes:     1 << 20,
+		maxPartBytes: 1 << 10,
+		numOffsets:   1,
+		usedOffsets: usedOffsets{"t": {
+			0: &cursorOffsetNext{cursorOffset: cursorOffset{offset: 5}},
+		}},
+		torder:   []string{"t"},
+		porder:   map[string][]int32{"t": {0}},
+		topic2id: map[string][16]byte{"t": {1}},
+		session:  fetchSession{killed: true},
+	}
+	if v := f.MaxVersion(); v != 17 {
+		t.Fatalf("got max version %d, exp 17", v)
+	}
+	f.disableIDs = true
+	if v := f.MaxVersion(); v != 12 {
+		t.Fatalf("got max version %d with IDs disabled, exp 12", v)
+	}
+	f.disableIDs = false
+
+	f.SetVersion(17)
+	req := kmsg.NewPtrFetchRequest()
+	req.Version = 17
+	if err := req.ReadFrom(f.AppendTo(nil)); err != nil {
+		t.Fatalf("unable to read our v17 request: %v", err)
+	}
+	if len(req.Topics) != 1 || req.Topics[0].TopicID != [16]byte{1} || len(req.Topics[0].Partitions) != 1 {
+		t.Fatalf("got topics %+v, exp one topic ID with one partition", req.Topics)
+	}
+	if p := req.Topics[0].Partitions[0]; p.Partition != 0 || p.FetchOffset != 5 || p.PartitionMaxBytes != 1<<10 {
+		t.Errorf("got partition %+v, exp partition 0 at offset 5", p)
+	}
+	if req.ReplicaID != -1 || req.ReplicaState.ID != -1 {
+		t.Errorf("got replica ID %d and replica state ID %d, exp -1 for a consumer", req.ReplicaID, req.ReplicaState.ID)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"reflect"
 	"strconv"
 	"testing"
 	"time"
@@ -241,3 +242,24 @@ func (c *testConsumer) etl(etlsBeforeQuit int) {
 		}
 	}
 }
+
+func TestDiffTopicPartitions(t *testing.T) {
+	for _, test := range []struct {
+		l, r map[string][]int32
+		exp  map[string][]int32
+	}{
+		{nil, nil, map[string][]int32{}},
+		{map[string][]int32{"a": {0, 1}}, nil, map[string][]int32{"a": {0, 1}}},
+		{nil, map[string][]int32{"a": {0, 1}}, map[string][]int32{}},
+		{
+			map[string][]int32{"a": {0, 1, 2}, "b": {0}, "c": {3}},
+			map[string][]int32{"a": {1}, "c": {3}},
+			map[string][]int32{"a": {0, 2}, "b": {0}},
+		},
+	} {
+		got := diffTopicPartitions(test.l, test.r)
+		if !reflect.DeepEqual(got, test.exp) {
+			t.Errorf("diff of %v and %v: got %v, exp %v", test.l, test.r, got, test.exp)
+		}
+	}
+}
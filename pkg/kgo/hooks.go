@@ -203,6 +203,51 @@ type HookGroupManageError interface {
 	OnGroupManageError(error)
 }
 
+// GroupRebalance contains information about a group member joining and
+// syncing a group, i.e. participating in a rebalance.
+type GroupRebalance struct {
+	// Group is the group that was joined.
+	Group string
+	// MemberID and Generation are the member ID and generation of this
+	// member after the rebalance. If the rebalance failed, these are the
+	// member ID and generation the client has at the time of failure.
+	MemberID   string
+	Generation int32
+
+	// Duration is how long it took to join and sync the group.
+	Duration time.Duration
+
+	// Previous is the full assignment of this member after the prior
+	// successful rebalance, and is nil for the first rebalance of a
+	// group session. For eager consumers, this is the assignment before
+	// it was revoked to rejoin the group.
+	Previous map[string][]int32
+
+	// Assigned is the full assignment of this member after the rebalance,
+	// and is nil if the rebalance failed.
+	Assigned map[string][]int32
+
+	// Added and Revoked are the partitions in Assigned that are not in
+	// Previous, and the partitions in Previous that are not in Assigned.
+	// These are non-nil (but possibly empty) if the rebalance succeeded,
+	// so that a single hook can track assignment transitions without
+	// diffing the separate assigned and revoked callbacks.
+	Added   map[string][]int32
+	Revoked map[string][]int32
+
+	// Err is the error that caused joining or syncing to fail, if any.
+	// If non-nil, the client backs off and rejoins the group.
+	Err error
+}
+
+// HookGroupRebalance is called after every join and sync of a group, whether
+// successful or not, and before partitions are assigned with
+// OnPartitionsAssigned.
+type HookGroupRebalance interface {
+	// OnGroupRebalance is passed information about a group rebalance.
+	OnGroupRebalance(GroupRebalance)
+}
+
 ///////////////////////////////
 // PRODUCE & CONSUME BATCHES //
 ///////////////////////////////
@@ -358,6 +403,20 @@ type HookProduceRecordUnbuffered interface {
 	OnProduceRecordUnbuffered(*Record, error)
 }
 
+// HookProduceBufferLimited is called when a record is failed because its
+// topic or partition has MaxBufferedBytesPerTopic or
+// MaxBufferedBytesPerPartition bytes buffered.
+//
+// This hook can be used to alert on or create metrics for partitions that
+// are not draining, such as partitions whose leader is unavailable.
+type HookProduceBufferLimited interface {
+	// OnProduceBufferLimited is passed the topic and partition that a
+	// record was failed for. This is called once per failed record,
+	// just before the record's promise is called with
+	// ErrMaxBufferedPartition.
+	OnProduceBufferLimited(topic string, partition int32)
+}
+
 // HookFetchRecordBuffered is called when a record is internally buffered after
 // fetching, ready to be polled.
 //
@@ -407,11 +466,13 @@ func implementsAnyHook(h Hook) bool {
 		HookBrokerE2E,
 		HookBrokerThrottle,
 		HookGroupManageError,
+		HookGroupRebalance,
 		HookProduceBatchWritten,
 		HookFetchBatchRead,
 		HookProduceRecordBuffered,
 		HookProduceRecordPartitioned,
 		HookProduceRecordUnbuffered,
+		HookProduceBufferLimited,
 		HookFetchRecordBuffered,
 		HookFetchRecordUnbuffered:
 		return true
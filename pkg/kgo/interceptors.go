@@ -0,0 +1,87 @@
+package kgo
+
+import "github.com/twmb/franz-go/pkg/kmsg"
+
+// ProducerInterceptor intercepts records being produced, allowing
+// cross-cutting concerns such as tagging records, encrypting values, or
+// encoding with a schema to be layered into the client rather than into
+// application code. Interceptors are configured with ProducerInterceptors and
+// are called in the order they are configured.
+//
+// Unlike hooks, interceptors can fail records. Interceptors are called
+// synchronously in the producing path and should be fast.
+type ProducerInterceptor interface {
+	// OnSend is called with every produced record before the record is
+	// buffered, and before any HookProduceRecordBuffered hook. The record
+	// can be modified. If this returns an error, no further interceptors
+	// are called for the record and the record is failed with the error.
+	OnSend(*Record) error
+
+	// OnAck is called with every produced record just before the record's
+	// promise is called, with the error the promise will be called with.
+	// This is called for every interceptor, even if OnSend failed.
+	OnAck(*Record, error)
+}
+
+// ConsumerInterceptor intercepts consumed records and commits. Interceptors
+// are configured with ConsumerInterceptors and are called in the order they
+// are configured.
+type ConsumerInterceptor interface {
+	// OnPoll is called with the fetches of every poll just before the
+	// fetches are returned (but before records are decoded with KeySerde
+	// or ValueSerde), and returns the fetches to pass to the next
+	// interceptor. Records can be modified or removed; offsets to commit
+	// are tracked before interceptors are called, so removed records are
+	// still committed. This is not called for polls that return nothing,
+	// nor for polls that only return ErrClientClosed or a context error.
+	OnPoll(Fetches) Fetches
+
+	// OnCommit is called after every group commit request completes,
+	// with the same arguments that AutoCommitCallback functions are
+	// called with. If err is non-nil, the response is nil. This is not
+	// called for offsets committed in transactions.
+	OnCommit(req *kmsg.OffsetCommitRequest, resp *kmsg.OffsetCommitResponse, err error)
+}
+
+// ProducerInterceptors sets interceptors to call for every produced record,
+// in order. See ProducerInterceptor for more details.
+func ProducerInterceptors(interceptors ...ProducerInterceptor) ProducerOpt {
+	return producerOpt{func(cfg *cfg) { cfg.producerInterceptors = append(cfg.producerInterceptors, interceptors...) }}
+}
+
+// ConsumerInterceptors sets interceptors to call for every poll and commit,
+// in order. See ConsumerInterceptor for more details.
+func ConsumerInterceptors(interceptors ...ConsumerInterceptor) ConsumerOpt {
+	return consumerOpt{func(cfg *cfg) { cfg.consumerInterceptors = append(cfg.consumerInterceptors, interceptors...) }}
+}
+
+func (cl *Client) interceptSend(r *Record) error {
+	for _, i := range cl.cfg.producerInterceptors {
+		if err := i.OnSend(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (cl *Client) interceptAck(r *Record, err error) {
+	for _, i := range cl.cfg.producerInterceptors {
+		i.OnAck(r, err)
+	}
+}
+
+func (cl *Client) interceptPoll(fetches Fetches) Fetches {
+	if len(fetches) == 0 {
+		return fetches
+	}
+	for _, i := range cl.cfg.consumerInterceptors {
+		fetches = i.OnPoll(fetches)
+	}
+	return fetches
+}
+
+func (cl *Client) interceptCommit(req *kmsg.OffsetCommitRequest, resp *kmsg.OffsetCommitResponse, err error) {
+	for _, i := range cl.cfg.consumerInterceptors {
+		i.OnCommit(req, resp, err)
+	}
+}
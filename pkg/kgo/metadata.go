@@ -75,6 +75,112 @@ func (cl *Client) PartitionLeader(topic string, partition int32) (leader, leader
 	return p.leader, p.leaderEpoch, p.loadErr
 }
 
+// MetadataSnapshot is the client's view of cluster metadata at a point in
+// time, as returned from Client.MetadataSnapshot.
+type MetadataSnapshot struct {
+	// Brokers are the brokers discovered from metadata responses, not
+	// including seed brokers.
+	Brokers []BrokerMetadata
+
+	// Topics are the topics the client is producing to or consuming, as
+	// of the client's last metadata update for each topic.
+	Topics map[string]MetadataSnapshotTopic
+
+	// LastUpdate is when the client last successfully updated metadata,
+	// and is zero if metadata has never been loaded.
+	LastUpdate time.Time
+
+	_ struct{} // allow us to add fields later
+}
+
+// MetadataSnapshotTopic is a topic in a MetadataSnapshot.
+type MetadataSnapshotTopic struct {
+	// Producing and Consuming are whether the client is producing to or
+	// consuming the topic; both can be true.
+	Producing bool
+	Consuming bool
+
+	// IsInternal is whether the topic is internal to Kafka.
+	IsInternal bool
+
+	// LoadErr is the error the client saw the last time it loaded the
+	// topic, if any, such as UNKNOWN_TOPIC_OR_PARTITION.
+	LoadErr error
+
+	// Partitions are the topic's partitions, indexed by partition number.
+	Partitions []MetadataSnapshotPartition
+}
+
+// MetadataSnapshotPartition is a partition in a MetadataSnapshotTopic.
+type MetadataSnapshotPartition struct {
+	// Leader is the node ID the client believes leads the partition, and
+	// LeaderEpoch is the epoch of that leader. Produce and fetch requests
+	// for the partition are sent to this leader.
+	Leader      int32
+	LeaderEpoch int32
+
+	// ISR is the in-sync replica set from the latest metadata.
+	ISR []int32
+
+	// LoadErr is the error the client saw the last time it loaded the
+	// partition, if any, such as LEADER_NOT_AVAILABLE. If set, the leader
+	// and epoch are from before the error.
+	LoadErr error
+}
+
+// MetadataSnapshot returns the client's current view of cluster metadata:
+// the discovered brokers, every topic being produced to or consumed along
+// with each partition's leader and leader epoch, and when metadata was last
+// updated. This does not issue a metadata request; it is a read only view
+// meant for logging or exposing what the client believes, for example when
+// diagnosing produce or fetch requests going to an unexpected leader.
+func (cl *Client) MetadataSnapshot() MetadataSnapshot {
+	var s MetadataSnapshot
+
+	cl.brokersMu.RLock()
+	for _, b := range cl.brokers {
+		s.Brokers = append(s.Brokers, b.meta)
+	}
+	cl.brokersMu.RUnlock()
+
+	cl.metawait.mu.Lock()
+	s.LastUpdate = cl.metawait.lastUpdate
+	cl.metawait.mu.Unlock()
+
+	s.Topics = make(map[string]MetadataSnapshotTopic)
+	add := func(tps topicsPartitionsData, producing bool) {
+		for topic, t := range tps {
+			st, exists := s.Topics[topic]
+			if !exists {
+				tv := t.load()
+				st.IsInternal = tv.isInternal
+				st.LoadErr = tv.loadErr
+				for _, p := range tv.partitions {
+					st.Partitions = append(st.Partitions, MetadataSnapshotPartition{
+						Leader:      p.leader,
+						LeaderEpoch: p.leaderEpoch,
+						ISR:         append([]int32(nil), p.isr...),
+						LoadErr:     p.loadErr,
+					})
+				}
+			}
+			if producing {
+				st.Producing = true
+			} else {
+				st.Consuming = true
+			}
+			s.Topics[topic] = st
+		}
+	}
+	add(cl.producer.topics.load(), true)
+	if cl.consumer.g != nil {
+		add(cl.consumer.g.tps.load(), false)
+	} else if cl.consumer.d != nil {
+		add(cl.consumer.d.tps.load(), false)
+	}
+	return s
+}
+
 // waitmeta returns immediately if metadata was updated within the last second,
 // otherwise this waits for up to wait for a metadata update to complete.
 func (cl *Client) waitmeta(ctx context.Context, wait time.Duration, why string) {
@@ -490,6 +596,8 @@ type metadataPartition struct {
 	loadErr     int16
 	leader      int32
 	leaderEpoch int32
+	leaderRack  string
+	isr         []int32
 	sns         sinkAndSource
 }
 
@@ -501,6 +609,8 @@ func (mp metadataPartition) newPartition(cl *Client, isProduce bool) *topicParti
 	p := &topicPartition{
 		loadErr:            kerr.ErrorForCode(mp.loadErr),
 		topicPartitionData: td,
+		leaderRack:         mp.leaderRack,
+		isr:                mp.isr,
 	}
 	if isProduce {
 		p.records = &recBuf{
@@ -552,6 +662,13 @@ func (cl *Client) fetchTopicMetadata(all bool, reqTopics []string) (map[string]*
 	// odd set of support.
 	useLeaderEpoch := cl.supportsOffsetForLeaderEpoch()
 
+	racks := make(map[int32]string, len(meta.Brokers))
+	for _, b := range meta.Brokers {
+		if b.Rack != nil {
+			racks[b.NodeID] = *b.Rack
+		}
+	}
+
 	for i := range meta.Topics {
 		topicMeta := &meta.Topics[i]
 		if topicMeta.Topic == nil {
@@ -609,6 +726,8 @@ func (cl *Client) fetchTopicMetadata(all bool, reqTopics []string) (map[string]*
 				loadErr:     partMeta.ErrorCode,
 				leader:      partMeta.Leader,
 				leaderEpoch: leaderEpoch,
+				leaderRack:  racks[partMeta.Leader],
+				isr:         partMeta.ISR,
 			}
 			if mp.loadErr != 0 {
 				mp.leader = unknownSeedID(0) // ensure every records & cursor can use a sink or source
@@ -0,0 +1,371 @@
+package kgo
+
+import (
+	"math/bits"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Metrics is a snapshot of the metrics collected by a client with
+// CollectMetrics, as returned from Client.Metrics. All counters are totals
+// since the client was created.
+type Metrics struct {
+	// Brokers contains metrics per broker, keyed by node ID. Seed brokers
+	// have negative IDs, see BrokerMetadata for more details.
+	Brokers map[int32]BrokerMetrics
+	// Topics contains produce and fetch metrics per topic.
+	Topics map[string]TopicMetrics
+	// Requests contains metrics per request key, i.e. kmsg.Produce.
+	Requests map[int16]RequestMetrics
+
+	// RequestRetries is the number of times a request was retried, for
+	// all requests issued through the client.
+	RequestRetries int64
+}
+
+// BrokerMetrics contains metrics for a single broker.
+type BrokerMetrics struct {
+	Connects      int64 // successful connections
+	ConnectErrors int64 // failed connection attempts
+	Disconnects   int64 // connections closed
+
+	BytesWritten int64 // bytes written in requests
+	BytesRead    int64 // bytes read in responses
+	WriteErrors  int64 // requests that failed to be written
+	ReadErrors   int64 // responses that failed to be read
+
+	Throttles    int64         // responses that indicated throttling
+	ThrottleTime time.Duration // total time throttled
+
+	ProducedBatches int64 // batches successfully produced to this broker
+	ProducedRecords int64 // records successfully produced to this broker
+	ProducedBytes   int64 // compressed batch bytes produced to this broker
+
+	FetchedBatches int64 // batches fetched from this broker
+	FetchedRecords int64 // records fetched from this broker
+	FetchedBytes   int64 // compressed batch bytes fetched from this broker
+}
+
+// TopicMetrics contains produce and fetch metrics for a single topic.
+type TopicMetrics struct {
+	ProducedBatches         int64 // batches successfully produced
+	ProducedRecords         int64 // records successfully produced
+	ProducedBytes           int64 // uncompressed batch bytes produced
+	ProducedCompressedBytes int64 // compressed batch bytes produced
+	ProduceBatchRetries     int64 // times a batch was retried
+
+	// ProduceBatchBytes is the distribution of uncompressed produced
+	// batch sizes, in bytes.
+	ProduceBatchBytes Distribution
+
+	FetchedBatches         int64 // batches fetched
+	FetchedRecords         int64 // records fetched
+	FetchedBytes           int64 // uncompressed batch bytes fetched
+	FetchedCompressedBytes int64 // compressed batch bytes fetched
+
+	// FetchBatchBytes is the distribution of uncompressed fetched batch
+	// sizes, in bytes.
+	FetchBatchBytes Distribution
+}
+
+// RequestMetrics contains metrics for a single request key.
+type RequestMetrics struct {
+	Requests int64 // requests issued
+	Errors   int64 // requests that failed to be written or read
+
+	// Latency is the distribution of request latencies, from the start
+	// of writing the request to the end of reading the response, in
+	// nanoseconds. This does not include time waiting to write, nor
+	// requests that failed.
+	Latency Distribution
+}
+
+// Distribution summarizes observed values. Quantiles are approximate: each
+// is within 25% of the true value, and is never outside of Min and Max.
+type Distribution struct {
+	Count int64
+	Sum   int64
+	Min   int64
+	Max   int64
+
+	P50 int64
+	P90 int64
+	P99 int64
+}
+
+// Metrics returns a snapshot of the client's metrics if CollectMetrics is
+// used, and an empty snapshot otherwise.
+func (cl *Client) Metrics() Metrics {
+	if !cl.cfg.collectMetrics {
+		return Metrics{}
+	}
+	return cl.metrics.snapshot()
+}
+
+// collector returns the client's metrics if they are currently being
+// collected, and nil otherwise.
+func (cl *Client) collector() *clientMetrics {
+	if m := cl.metrics; m != nil && (m.active == nil || m.active.Load()) {
+		return m
+	}
+	return nil
+}
+
+// histogram buckets values log-linearly: each power of two is split into four
+// buckets. Values under four have their own bucket.
+type histogram struct {
+	counts [256]int64
+	count  int64
+	sum    int64
+	min    int64
+	max    int64
+}
+
+func histogramBucket(v int64) int {
+	if v < 4 {
+		return int(v)
+	}
+	e := bits.Len64(uint64(v)) - 1
+	sub := int(v>>(e-2)) & 3
+	return 4*(e-1) + sub
+}
+
+// histogramBucketRange returns the minimum and maximum value of a bucket.
+func histogramBucketRange(idx int) (lo, hi int64) {
+	if idx < 4 {
+		return int64(idx), int64(idx)
+	}
+	e, sub := idx/4+1, idx%4
+	lo = int64(4+sub) << (e - 2)
+	return lo, lo + 1<<(e-2) - 1
+}
+
+func (h *histogram) observe(v int64) {
+	if v < 0 {
+		v = 0
+	}
+	if h.count == 0 || v < h.min {
+		h.min = v
+	}
+	if v > h.max {
+		h.max = v
+	}
+	h.count++
+	h.sum += v
+	h.counts[histogramBucket(v)]++
+}
+
+func (h *histogram) quantile(q float64) int64 {
+	want := int64(q*float64(h.count) + 0.5)
+	if want < 1 {
+		want = 1
+	}
+	var seen int64
+	for i, n := range h.counts {
+		if seen += n; seen >= want {
+			_, hi := histogramBucketRange(i)
+			if hi > h.max {
+				return h.max
+			}
+			if hi < h.min {
+				return h.min
+			}
+			return hi
+		}
+	}
+	return h.max
+}
+
+func (h *histogram) distribution() Distribution {
+	if h == nil || h.count == 0 {
+		return Distribution{}
+	}
+	return Distribution{
+		Count: h.count,
+		Sum:   h.sum,
+		Min:   h.min,
+		Max:   h.max,
+		P50:   h.quantile(0.5),
+		P90:   h.quantile(0.9),
+		P99:   h.quantile(0.99),
+	}
+}
+
+// clientMetrics collects metrics for CollectMetrics and client telemetry.
+// This is not a hook: the client calls it directly wherever collector returns
+// it, so that users' hooks stay their own and nothing is paid for while
+// metrics are not collected. Everything is guarded by one mutex: metrics are
+// updated at most once per request or batch, not once per record.
+//
+// If active is non-nil, metrics are only collected while it is true; this is
+// used when metrics are only collected for client telemetry.
+type clientMetrics struct {
+	active *atomic.Bool
+
+	mu       sync.Mutex
+	brokers  map[int32]*BrokerMetrics
+	topics   map[string]*topicMetrics
+	requests map[int16]*requestMetrics
+	retries  int64
+}
+
+type topicMetrics struct {
+	TopicMetrics
+	produceBatchBytes histogram
+	fetchBatchBytes   histogram
+}
+
+type requestMetrics struct {
+	RequestMetrics
+	latency histogram
+}
+
+func newClientMetrics() *clientMetrics {
+	return &clientMetrics{
+		brokers:  make(map[int32]*BrokerMetrics),
+		topics:   make(map[string]*topicMetrics),
+		requests: make(map[int16]*requestMetrics),
+	}
+}
+
+func (m *clientMetrics) brokerLocked(id int32) *BrokerMetrics {
+	b := m.brokers[id]
+	if b == nil {
+		b = new(BrokerMetrics)
+		m.brokers[id] = b
+	}
+	return b
+}
+
+func (m *clientMetrics) topicLocked(topic string) *topicMetrics {
+	t := m.topics[topic]
+	if t == nil {
+		t = new(topicMetrics)
+		m.topics[topic] = t
+	}
+	return t
+}
+
+func (m *clientMetrics) brokerConnect(meta BrokerMetadata, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err != nil {
+		m.brokerLocked(meta.NodeID).ConnectErrors++
+	} else {
+		m.brokerLocked(meta.NodeID).Connects++
+	}
+}
+
+func (m *clientMetrics) brokerDisconnect(meta BrokerMetadata) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.brokerLocked(meta.NodeID).Disconnects++
+}
+
+func (m *clientMetrics) brokerE2E(meta BrokerMetadata, key int16, e2e BrokerE2E) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	b := m.brokerLocked(meta.NodeID)
+	b.BytesWritten += int64(e2e.BytesWritten)
+	b.BytesRead += int64(e2e.BytesRead)
+	if e2e.WriteErr != nil {
+		b.WriteErrors++
+	} else if e2e.ReadErr != nil {
+		b.ReadErrors++
+	}
+
+	r := m.requests[key]
+	if r == nil {
+		r = new(requestMetrics)
+		m.requests[key] = r
+	}
+	r.Requests++
+	if e2e.Err() != nil {
+		r.Errors++
+	} else {
+		r.latency.observe(int64(e2e.DurationE2E()))
+	}
+}
+
+func (m *clientMetrics) brokerThrottle(meta BrokerMetadata, throttleInterval time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	b := m.brokerLocked(meta.NodeID)
+	b.Throttles++
+	b.ThrottleTime += throttleInterval
+}
+
+func (m *clientMetrics) produceBatchWritten(meta BrokerMetadata, topic string, pm ProduceBatchMetrics) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	b := m.brokerLocked(meta.NodeID)
+	b.ProducedBatches++
+	b.ProducedRecords += int64(pm.NumRecords)
+	b.ProducedBytes += int64(pm.CompressedBytes)
+
+	t := m.topicLocked(topic)
+	t.ProducedBatches++
+	t.ProducedRecords += int64(pm.NumRecords)
+	t.ProducedBytes += int64(pm.UncompressedBytes)
+	t.ProducedCompressedBytes += int64(pm.CompressedBytes)
+	t.produceBatchBytes.observe(int64(pm.UncompressedBytes))
+}
+
+func (m *clientMetrics) fetchBatchRead(meta BrokerMetadata, topic string, fm FetchBatchMetrics) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	b := m.brokerLocked(meta.NodeID)
+	b.FetchedBatches++
+	b.FetchedRecords += int64(fm.NumRecords)
+	b.FetchedBytes += int64(fm.CompressedBytes)
+
+	t := m.topicLocked(topic)
+	t.FetchedBatches++
+	t.FetchedRecords += int64(fm.NumRecords)
+	t.FetchedBytes += int64(fm.UncompressedBytes)
+	t.FetchedCompressedBytes += int64(fm.CompressedBytes)
+	t.fetchBatchBytes.observe(int64(fm.UncompressedBytes))
+}
+
+func (m *clientMetrics) requestRetry() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.retries++
+}
+
+func (m *clientMetrics) produceBatchRetry(topic string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.topicLocked(topic).ProduceBatchRetries++
+}
+
+func (m *clientMetrics) snapshot() Metrics {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s := Metrics{
+		Brokers:        make(map[int32]BrokerMetrics, len(m.brokers)),
+		Topics:         make(map[string]TopicMetrics, len(m.topics)),
+		Requests:       make(map[int16]RequestMetrics, len(m.requests)),
+		RequestRetries: m.retries,
+	}
+	for id, b := range m.brokers {
+		s.Brokers[id] = *b
+	}
+	for topic, t := range m.topics {
+		tm := t.TopicMetrics
+		tm.ProduceBatchBytes = t.produceBatchBytes.distribution()
+		tm.FetchBatchBytes = t.fetchBatchBytes.distribution()
+		s.Topics[topic] = tm
+	}
+	for key, r := range m.requests {
+		rm := r.RequestMetrics
+		rm.Latency = r.latency.distribution()
+		s.Requests[key] = rm
+	}
+	return s
+}
@@ -0,0 +1,47 @@
+package kgo
+
+import "testing"
+
+func TestHistogramBuckets(t *testing.T) {
+	for v := int64(0); v < 1<<16; v++ {
+		lo, hi := histogramBucketRange(histogramBucket(v))
+		if v < lo || v > hi {
+			t.Fatalf("value %d in bucket [%d, %d]", v, lo, hi)
+		}
+		if float64(hi-lo) > 0.25*float64(lo) {
+			t.Fatalf("bucket [%d, %d] wider than 25%%", lo, hi)
+		}
+	}
+	for _, v := range []int64{1 << 40, 1<<62 + 12345, 1<<63 - 1} {
+		lo, hi := histogramBucketRange(histogramBucket(v))
+		if v < lo || v > hi {
+			t.Errorf("value %d in bucket [%d, %d]", v, lo, hi)
+		}
+	}
+}
+
+func TestHistogramDistribution(t *testing.T) {
+	var h histogram
+	for v := int64(1); v <= 1000; v++ {
+		h.observe(v)
+	}
+	d := h.distribution()
+	if d.Count != 1000 || d.Sum != 500500 || d.Min != 1 || d.Max != 1000 {
+		t.Fatalf("got %+v", d)
+	}
+	for _, q := range []struct {
+		got, exp int64
+	}{
+		{d.P50, 500},
+		{d.P90, 900},
+		{d.P99, 990},
+	} {
+		if q.got < q.exp || float64(q.got) > 1.25*float64(q.exp) {
+			t.Errorf("got quantile %d, expected within 25%% above %d", q.got, q.exp)
+		}
+	}
+
+	if d := new(histogram).distribution(); d != (Distribution{}) {
+		t.Errorf("empty histogram: got %+v", d)
+	}
+}
@@ -0,0 +1,264 @@
+package kgo
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// partitionProcessorBuffer is how many records are queued for a partition's
+// worker before fetching the partition is paused.
+const partitionProcessorBuffer = 64
+
+// PartitionProcessor consumes in a group and processes records concurrently
+// across partitions: every assigned partition has its own goroutine that
+// processes the partition's records one at a time, in order. Polling
+// continues while records are processed; if a partition's worker falls
+// behind, fetching is paused for only that partition until the worker
+// catches up.
+//
+// Every processed record is marked for commit, and the client autocommits
+// marked offsets, so the committed offset for a partition is always the
+// highest offset processed contiguously from the start of the partition.
+//
+// When partitions are revoked in a rebalance, the revoke waits for the
+// revoked partitions' workers to finish the record they are processing, drops
+// any records queued for those partitions, and commits all marked offsets
+// before the revoke completes. When partitions are lost, the contexts for the
+// lost partitions' workers are canceled and nothing is committed.
+type PartitionProcessor struct {
+	cl *Client
+	fn func(context.Context, *Record)
+
+	mu      sync.Mutex
+	workers map[string]map[int32]*partitionWorker
+}
+
+type partitionWorker struct {
+	cp *chanPartition
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	quit   chan struct{}
+	wg     sync.WaitGroup // tracks the worker goroutine and the partition's drain goroutine
+}
+
+// NewPartitionProcessor is exactly the same as NewClient, but requires a
+// consumer group and wraps the client's OnPartitionsRevoked /
+// OnPartitionsLost to stop workers for partitions that are no longer
+// assigned, as described in the PartitionProcessor documentation. Any
+// autocommit options are overridden to autocommit marked records.
+//
+// The fn callback is called with one record at a time per partition, and is
+// called concurrently for different partitions. Once fn returns, the record
+// is considered processed: fn must handle any processing errors itself. The
+// context passed to fn is canceled if Run's context is canceled or the
+// record's partition is lost.
+func NewPartitionProcessor(fn func(context.Context, *Record), opts ...Opt) (*PartitionProcessor, error) {
+	pp := &PartitionProcessor{
+		fn:      fn,
+		workers: make(map[string]map[int32]*partitionWorker),
+	}
+
+	var noGroup error
+
+	opts = append(opts, groupOpt{func(cfg *cfg) {
+		if cfg.group == "" {
+			cfg.seedBrokers = nil // force a validation error
+			noGroup = errors.New("missing required group")
+			return
+		}
+
+		cfg.autocommitDisable = false
+		cfg.autocommitGreedy = false
+		cfg.autocommitMarks = true
+		cfg.setRevoked, cfg.setLost = true, true
+
+		userRevoked := cfg.onRevoked
+		cfg.onRevoked = func(ctx context.Context, cl *Client, revoked map[string][]int32) {
+			pp.stop(revoked, false)
+			if err := cl.CommitMarkedOffsets(ctx); err != nil {
+				cl.cfg.logger.Log(LogLevelWarn, "partition processor unable to commit marked offsets in revoke", "err", err)
+			}
+			if userRevoked != nil {
+				userRevoked(ctx, cl, revoked)
+			}
+		}
+
+		userLost := cfg.onLost
+		cfg.onLost = func(ctx context.Context, cl *Client, lost map[string][]int32) {
+			pp.stop(lost, true)
+			if userLost != nil {
+				userLost(ctx, cl, lost)
+			} else if userRevoked != nil {
+				userRevoked(ctx, cl, lost)
+			}
+		}
+	}})
+
+	cl, err := NewClient(opts...)
+	if err != nil {
+		if noGroup != nil {
+			err = noGroup
+		}
+		return nil, err
+	}
+	pp.cl = cl
+	return pp, nil
+}
+
+// Client returns the underlying client that this partition processor wraps.
+// This can be useful for functions that require a client, such as raw
+// requests. The returned client should not be used to poll, commit, or pause
+// and resume partitions (leave that to the PartitionProcessor).
+func (pp *PartitionProcessor) Client() *Client {
+	return pp.cl
+}
+
+// Close is a wrapper around Client.Close, with the exact same semantics.
+// Refer to that function's documentation.
+//
+// This function must be called to leave the group before shutting down.
+// Leaving the group revokes all partitions, which commits all processed
+// records.
+func (pp *PartitionProcessor) Close() {
+	pp.cl.Close()
+}
+
+// Run polls and dispatches records to partition workers until the context is
+// canceled or the client is closed, returning the context's error or
+// ErrClientClosed. Fetch errors are logged and otherwise ignored. Before
+// returning, Run waits for every worker to finish the record it is
+// processing; records that were queued but not processed are dropped and are
+// not marked for commit.
+func (pp *PartitionProcessor) Run(ctx context.Context) error {
+	defer pp.stopAll()
+	for {
+		fetches := pp.cl.PollFetches(ctx)
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if pp.cl.ctx.Err() != nil {
+			return ErrClientClosed
+		}
+		fetches.EachError(func(t string, p int32, err error) {
+			pp.cl.cfg.logger.Log(LogLevelWarn, "partition processor fetch error", "topic", t, "partition", p, "err", err)
+		})
+		fetches.EachPartition(func(p FetchTopicPartition) {
+			if len(p.Records) == 0 {
+				return
+			}
+			pp.dispatch(ctx, p)
+		})
+	}
+}
+
+// dispatch sends records to the partition's worker, starting one if
+// necessary. We send while holding the lock so that a worker being stopped
+// never has records sent to it afterwards, which could pause the partition.
+func (pp *PartitionProcessor) dispatch(ctx context.Context, fp FetchTopicPartition) {
+	pp.mu.Lock()
+	defer pp.mu.Unlock()
+
+	w := pp.workerLocked(ctx, fp.Topic, fp.Partition)
+	w.cp.send(pp.cl, fp.Records, w.quit, &w.wg)
+}
+
+func (pp *PartitionProcessor) workerLocked(ctx context.Context, t string, p int32) *partitionWorker {
+	ps := pp.workers[t]
+	if ps == nil {
+		ps = make(map[int32]*partitionWorker)
+		pp.workers[t] = ps
+	}
+	if w := ps[p]; w != nil {
+		return w
+	}
+
+	w := &partitionWorker{
+		cp: &chanPartition{
+			topic:     t,
+			partition: p,
+			ch:        make(chan *Record, partitionProcessorBuffer),
+		},
+		quit: make(chan struct{}),
+	}
+	w.ctx, w.cancel = context.WithCancel(ctx)
+	ps[p] = w
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		for {
+			select {
+			case <-w.quit:
+				return
+			case r := <-w.cp.ch:
+				pp.fn(w.ctx, r)
+				if w.ctx.Err() != nil {
+					return
+				}
+				pp.cl.MarkCommitRecords(r)
+			}
+		}
+	}()
+	return w
+}
+
+// stop stops the workers for the given partitions, optionally canceling
+// their contexts, and waits for them to finish.
+func (pp *PartitionProcessor) stop(parts map[string][]int32, cancel bool) {
+	var stopped []*partitionWorker
+	pp.mu.Lock()
+	for t, ps := range parts {
+		for _, p := range ps {
+			w := pp.workers[t][p]
+			if w == nil {
+				continue
+			}
+			delete(pp.workers[t], p)
+			if len(pp.workers[t]) == 0 {
+				delete(pp.workers, t)
+			}
+			stopped = append(stopped, w)
+		}
+	}
+	pp.mu.Unlock()
+
+	pp.stopWorkers(stopped, cancel)
+}
+
+func (pp *PartitionProcessor) stopAll() {
+	var stopped []*partitionWorker
+	pp.mu.Lock()
+	for _, ps := range pp.workers {
+		for _, w := range ps {
+			stopped = append(stopped, w)
+		}
+	}
+	pp.workers = make(map[string]map[int32]*partitionWorker)
+	pp.mu.Unlock()
+
+	pp.stopWorkers(stopped, false)
+}
+
+func (pp *PartitionProcessor) stopWorkers(ws []*partitionWorker, cancel bool) {
+	for _, w := range ws {
+		close(w.quit)
+		if cancel {
+			w.cancel()
+		}
+	}
+	for _, w := range ws {
+		w.wg.Wait()
+		w.cancel()
+
+		// If the partition was paused while its backlog drained, we
+		// resume it so that it is fetched if it is reassigned.
+		w.cp.mu.Lock()
+		if w.cp.draining {
+			w.cp.draining = false
+			pp.cl.ResumeFetchPartitions(map[string][]int32{w.cp.topic: {w.cp.partition}})
+		}
+		w.cp.mu.Unlock()
+	}
+}
@@ -72,6 +72,22 @@ type TopicBackupIter interface {
 	Rem() int
 }
 
+// TopicRackPartitioner is an optional extension interface to
+// TopicPartitioner that can partition by the rack of each partition's leader.
+//
+// If a partitioner implements this interface, the Partition function will
+// never be called. This interface takes precedence over
+// TopicBackupPartitioner.
+type TopicRackPartitioner interface {
+	TopicPartitioner
+
+	// PartitionByRack is similar to Partition, but has an additional
+	// leaderRack function that returns the rack of the leader of the
+	// partition at the given index, or an empty string if the leader has
+	// no rack.
+	PartitionByRack(r *Record, n int, leaderRack func(int) string) int
+}
+
 ////////////
 // SIMPLE // - BasicConsistent, Manual, RoundRobin
 ////////////
@@ -442,6 +458,82 @@ func (p *stickyTopicPartitioner) Partition(_ *Record, n int) int {
 	return p.onPart
 }
 
+// RackPartitioner returns a partitioner that, for records without keys,
+// prefers partitions whose leaders are in the given rack, reducing cross rack
+// (or availability zone) traffic and its associated costs. This is typically
+// the same rack that you use for Rack when consuming.
+//
+// Records without keys are partitioned with the sticky strategy of the
+// StickyPartitioner, but only choosing from partitions whose leader is in the
+// same rack. If no partition leader is in the same rack (or rack is empty),
+// this chooses from all partitions. Note that this can skew how records are
+// distributed across partitions if leaders are not spread evenly across
+// racks.
+//
+// Records with keys are always hashed across all partitions with hasher so
+// that keys are consistently partitioned. If hasher is nil, this uses the
+// same hasher as the StickyKeyPartitioner.
+func RackPartitioner(rack string, hasher PartitionerHasher) Partitioner {
+	if hasher == nil {
+		hasher = KafkaHasher(murmur2)
+	}
+	return &rackPartitioner{rack, hasher}
+}
+
+type (
+	rackPartitioner struct {
+		rack   string
+		hasher PartitionerHasher
+	}
+
+	rackTopicPartitioner struct {
+		rack   string
+		hasher PartitionerHasher
+		local  []int
+		stickyTopicPartitioner
+	}
+)
+
+func (p *rackPartitioner) ForTopic(string) TopicPartitioner {
+	return &rackTopicPartitioner{
+		rack:                   p.rack,
+		hasher:                 p.hasher,
+		stickyTopicPartitioner: newStickyTopicPartitioner(),
+	}
+}
+
+func (*rackTopicPartitioner) RequiresConsistency(r *Record) bool { return r.Key != nil }
+func (*rackTopicPartitioner) Partition(*Record, int) int         { panic("unreachable") }
+
+func (p *rackTopicPartitioner) PartitionByRack(r *Record, n int, leaderRack func(int) string) int {
+	if r.Key != nil {
+		return p.hasher(r.Key, n)
+	}
+	if p.onPart != -1 && p.onPart < n {
+		return p.onPart
+	}
+
+	local := p.local[:0]
+	if p.rack != "" {
+		for i := 0; i < n; i++ {
+			if leaderRack(i) == p.rack {
+				local = append(local, i)
+			}
+		}
+	}
+	p.local = local
+	if len(local) == 0 {
+		return p.stickyTopicPartitioner.Partition(r, n)
+	}
+
+	pick := p.rng.Intn(len(local))
+	if local[pick] == p.lastPart && len(local) > 1 {
+		pick = (pick + 1) % len(local)
+	}
+	p.onPart = local[pick]
+	return p.onPart
+}
+
 // StickyKeyPartitioner mirrors the default Java partitioner from Kafka's 2.4
 // release (see KIP-480 and KAFKA-8601) until their 3.3 release. This was
 // replaced in 3.3 with the uniform sticky partitioner (KIP-794), which is
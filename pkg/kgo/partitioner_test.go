@@ -0,0 +1,50 @@
+package kgo
+
+import "testing"
+
+func TestRackPartitioner(t *testing.T) {
+	racks := []string{"a", "b", "a", "", "b", "a"}
+	leaderRack := func(i int) string { return racks[i] }
+
+	for _, test := range []struct {
+		rack string
+		exp  map[int]bool
+	}{
+		{"a", map[int]bool{0: true, 2: true, 5: true}},
+		{"b", map[int]bool{1: true, 4: true}},
+		{"c", map[int]bool{0: true, 1: true, 2: true, 3: true, 4: true, 5: true}},
+		{"", map[int]bool{0: true, 1: true, 2: true, 3: true, 4: true, 5: true}},
+	} {
+		p := RackPartitioner(test.rack, nil).ForTopic("t").(TopicRackPartitioner)
+		onNewBatch := p.(TopicPartitionerOnNewBatch)
+
+		seen := make(map[int]bool)
+		for i := 0; i < 200; i++ {
+			r := &Record{}
+			if p.RequiresConsistency(r) {
+				t.Fatal("keyless records should not require consistency")
+			}
+			pick := p.PartitionByRack(r, len(racks), leaderRack)
+			if !test.exp[pick] {
+				t.Fatalf("rack %q: picked partition %d outside of %v", test.rack, pick, test.exp)
+			}
+			if again := p.PartitionByRack(r, len(racks), leaderRack); again != pick {
+				t.Fatalf("rack %q: not sticky, picked %d then %d", test.rack, pick, again)
+			}
+			seen[pick] = true
+			onNewBatch.OnNewBatch()
+		}
+		if len(seen) != len(test.exp) {
+			t.Errorf("rack %q: picked %d distinct partitions, exp %d", test.rack, len(seen), len(test.exp))
+		}
+
+		// Keys are hashed across all partitions, ignoring racks.
+		r := &Record{Key: []byte("key")}
+		if !p.RequiresConsistency(r) {
+			t.Error("keyed records should require consistency")
+		}
+		if got, exp := p.PartitionByRack(r, len(racks), leaderRack), KafkaHasher(murmur2)(r.Key, len(racks)); got != exp {
+			t.Errorf("rack %q: keyed record went to %d, exp %d", test.rack, got, exp)
+		}
+	}
+}
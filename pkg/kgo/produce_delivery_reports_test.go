@@ -0,0 +1,86 @@
+package kgo
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestDeliveryReportsDoNotBlockPromises(t *testing.T) {
+	t.Parallel()
+
+	ch := make(chan ProduceResult)
+	cl, err := NewClient(DeliveryReports(ch))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cl.Close()
+
+	// Nothing is receiving, yet finishing promises returns immediately
+	// and every promise is called.
+	var promised int
+	for i := 0; i < 3; i++ {
+		pr := promisedRec{context.Background(), func(*Record, error) { promised++ }, StringRecord(string(rune('a' + i)))}
+		cl.finishRecordPromise(pr, nil, true)
+	}
+	if promised != 3 {
+		t.Fatalf("got %d promises called, expected 3", promised)
+	}
+
+	for i := 0; i < 3; i++ {
+		select {
+		case r := <-ch:
+			if exp := string(rune('a' + i)); string(r.Record.Value) != exp {
+				t.Fatalf("got result %s, expected %s", r.Record.Value, exp)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for result %d", i)
+		}
+	}
+}
+
+func TestDeliveryReportsStopOnClose(t *testing.T) {
+	t.Parallel()
+
+	ch := make(chan ProduceResult, 1)
+	cl, err := NewClient(DeliveryReports(ch))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The first result fits, the second is waiting on a reader that
+	// never comes; closing must not hang on it.
+	for i := 0; i < 2; i++ {
+		pr := promisedRec{context.Background(), func(*Record, error) {}, StringRecord("v")}
+		cl.finishRecordPromise(pr, nil, true)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		cl.Close()
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close blocked on an undrained delivery report channel")
+	}
+
+	p := &cl.producer
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		p.reportsMu.Lock()
+		sending := p.sendingReports
+		p.reportsMu.Unlock()
+		if !sending {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("delivery reports are still being sent after Close")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if len(ch) != 1 {
+		t.Fatalf("got %d results in the channel, expected 1", len(ch))
+	}
+}
@@ -0,0 +1,87 @@
+package kgo
+
+import (
+	"sync"
+	"time"
+)
+
+// produceLimiter limits the rate that records are buffered with token buckets
+// for records and bytes. Each bucket holds up to one second of tokens.
+//
+// Reserving takes tokens immediately, even if that puts a bucket into debt,
+// and returns how long the caller must wait before the debt is repaid. This
+// keeps records in order: a later reservation always waits at least as long
+// as an earlier one.
+type produceLimiter struct {
+	mu    sync.Mutex
+	recs  *tokenBucket
+	bytes *tokenBucket
+}
+
+type tokenBucket struct {
+	rate   float64 // tokens per second, also the bucket's capacity
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, now time.Time) *tokenBucket {
+	if rate <= 0 {
+		return nil
+	}
+	return &tokenBucket{rate: rate, tokens: rate, last: now}
+}
+
+// take takes n tokens and returns how long until the bucket is out of debt.
+func (b *tokenBucket) take(n float64, now time.Time) time.Duration {
+	if b == nil {
+		return 0
+	}
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.rate {
+		b.tokens = b.rate
+	}
+	b.last = now
+	b.tokens -= n
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+func (b *tokenBucket) refund(n float64) {
+	if b != nil {
+		b.tokens += n
+	}
+}
+
+func newProduceLimiter(cfg *cfg) *produceLimiter {
+	if cfg.produceRecordsRate <= 0 && cfg.produceBytesRate <= 0 {
+		return nil
+	}
+	now := time.Now()
+	return &produceLimiter{
+		recs:  newTokenBucket(float64(cfg.produceRecordsRate), now),
+		bytes: newTokenBucket(float64(cfg.produceBytesRate), now),
+	}
+}
+
+// reserve reserves one record of the given size, returning how long to wait
+// before the record can be buffered.
+func (l *produceLimiter) reserve(size int64) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	wait := l.recs.take(1, now)
+	if bwait := l.bytes.take(float64(size), now); bwait > wait {
+		wait = bwait
+	}
+	return wait
+}
+
+// cancel returns a reservation that was not used.
+func (l *produceLimiter) cancel(size int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.recs.refund(1)
+	l.bytes.refund(float64(size))
+}
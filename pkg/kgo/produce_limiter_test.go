@@ -0,0 +1,48 @@
+package kgo
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTokenBucket(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	b := newTokenBucket(10, now)
+
+	// The bucket starts full, allowing a one second burst.
+	for i := 0; i < 10; i++ {
+		if wait := b.take(1, now); wait != 0 {
+			t.Fatalf("take %d: got wait %v, expected none", i, wait)
+		}
+	}
+
+	// Going into debt requires waiting until the debt is repaid, and
+	// successive takes wait successively longer.
+	if wait := b.take(1, now); wait != 100*time.Millisecond {
+		t.Errorf("got wait %v, expected 100ms", wait)
+	}
+	if wait := b.take(1, now); wait != 200*time.Millisecond {
+		t.Errorf("got wait %v, expected 200ms", wait)
+	}
+
+	// Refunding returns the tokens.
+	b.refund(1)
+	if wait := b.take(1, now); wait != 200*time.Millisecond {
+		t.Errorf("after refund, got wait %v, expected 200ms", wait)
+	}
+
+	// Time refills the bucket, but only up to its capacity.
+	now = now.Add(time.Hour)
+	if wait := b.take(10, now); wait != 0 {
+		t.Errorf("after refill, got wait %v, expected none", wait)
+	}
+	if wait := b.take(1, now); wait != 100*time.Millisecond {
+		t.Errorf("after refill, got wait %v, expected 100ms", wait)
+	}
+
+	if newTokenBucket(0, now) != nil {
+		t.Error("expected no bucket for a zero rate")
+	}
+}
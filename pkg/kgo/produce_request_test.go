@@ -653,3 +653,18 @@ func BenchmarkAppendBatch(b *testing.B) {
 		})
 	}
 }
+
+func TestProduceRequestMaxVersion(t *testing.T) {
+	txid := "tx"
+	for _, test := range []struct {
+		txnID *string
+		exp   int16
+	}{
+		{nil, 12},
+		{&txid, 11}, // v12 has the broker add partitions to transactions
+	} {
+		if got := (&produceRequest{txnID: test.txnID}).MaxVersion(); got != test.exp {
+			t.Errorf("transactional %v: got max version %d, exp %d", test.txnID != nil, got, test.exp)
+		}
+	}
+}
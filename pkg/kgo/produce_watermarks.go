@@ -0,0 +1,77 @@
+package kgo
+
+import "math"
+
+// bufferWatermarks calls user functions when the produce buffer crosses high
+// and low watermarks. The state is protected by the producer's mu.
+//
+// Callbacks are delivered from a goroutine that is started on a transition
+// and exits once the delivered state matches the current state. This keeps
+// user functions out of the producer lock while guaranteeing that calls
+// strictly alternate between onHigh and onLow: if the buffer flaps while a
+// callback is running, intermediate transitions are coalesced.
+type bufferWatermarks struct {
+	highRecs, lowRecs   int64
+	highBytes, lowBytes int64 // zero if MaxBufferedBytes is not set
+	onHigh, onLow       func()
+
+	above     bool // whether the buffer crossed the high watermark and has not yet drained to the low
+	delivered bool // the last state passed to a callback
+	notifying bool // whether a goroutine is delivering callbacks
+}
+
+func newBufferWatermarks(cfg *cfg) *bufferWatermarks {
+	if cfg.bufferHigh == 0 {
+		return nil
+	}
+	w := &bufferWatermarks{
+		highRecs: int64(math.Ceil(cfg.bufferHigh * float64(cfg.maxBufferedRecords))),
+		lowRecs:  int64(cfg.bufferLow * float64(cfg.maxBufferedRecords)),
+		onHigh:   cfg.onBufferHigh,
+		onLow:    cfg.onBufferLow,
+	}
+	if cfg.maxBufferedBytes > 0 {
+		w.highBytes = int64(math.Ceil(cfg.bufferHigh * float64(cfg.maxBufferedBytes)))
+		w.lowBytes = int64(cfg.bufferLow * float64(cfg.maxBufferedBytes))
+	}
+	return w
+}
+
+// update is called with the producer's mu held after the number of buffered
+// records or bytes changes, and returns whether the caller must start a
+// goroutine to deliver callbacks with notify.
+func (w *bufferWatermarks) update(recs, bytes int64) bool {
+	if !w.above {
+		w.above = recs >= w.highRecs || w.highBytes > 0 && bytes >= w.highBytes
+	} else {
+		w.above = recs > w.lowRecs || w.highBytes > 0 && bytes > w.lowBytes
+	}
+	if w.above == w.delivered || w.notifying {
+		return false
+	}
+	w.notifying = true
+	return true
+}
+
+func (p *producer) notifyWatermarks() {
+	w := p.watermarks
+	for {
+		p.mu.Lock()
+		if w.above == w.delivered {
+			w.notifying = false
+			p.mu.Unlock()
+			return
+		}
+		w.delivered = w.above
+		above := w.above
+		p.mu.Unlock()
+
+		if above {
+			if w.onHigh != nil {
+				w.onHigh()
+			}
+		} else if w.onLow != nil {
+			w.onLow()
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package kgo
+
+import "testing"
+
+func TestBufferWatermarks(t *testing.T) {
+	t.Parallel()
+
+	w := newBufferWatermarks(&cfg{
+		maxBufferedRecords: 10,
+		maxBufferedBytes:   100,
+		bufferHigh:         0.8,
+		bufferLow:          0.5,
+	})
+
+	for _, step := range []struct {
+		recs, bytes int64
+		above       bool
+	}{
+		{1, 10, false},
+		{7, 79, false},
+		{8, 10, true},  // records cross high
+		{6, 10, true},  // still above low
+		{5, 60, true},  // bytes still above low
+		{5, 50, false}, // both at low
+		{2, 80, true},  // bytes cross high
+		{0, 0, false},
+	} {
+		w.update(step.recs, step.bytes)
+		w.notifying = false // we test state transitions only
+		if w.above != step.above {
+			t.Errorf("at %d records, %d bytes: got above %v, expected %v", step.recs, step.bytes, w.above, step.above)
+		}
+	}
+
+	if newBufferWatermarks(&cfg{maxBufferedRecords: 10}) != nil {
+		t.Error("expected nil watermarks when unconfigured")
+	}
+}
@@ -35,10 +35,16 @@ type producer struct {
 		buffered    []HookProduceRecordBuffered
 		partitioned []HookProduceRecordPartitioned
 		unbuffered  []HookProduceRecordUnbuffered
+		limited     []HookProduceBufferLimited
 	}
 
 	hasHookBatchWritten bool
 
+	limiter   *produceLimiter // non-nil if produce rate limits are configured
+	bufLimits *bufferLimits   // non-nil if per topic or per partition buffer limits are configured
+
+	watermarks *bufferWatermarks // non-nil if ProduceBufferWatermarks is used
+
 	// unknownTopics buffers all records for topics that are not loaded.
 	// The map is to a pointer to a slice for reasons documented in
 	// waitUnknownTopic.
@@ -63,6 +69,12 @@ type producer struct {
 	batchPromises ringBatchPromise
 	promisesMu    sync.Mutex
 
+	// Results queued for the DeliveryReports channel, sent in order by a
+	// goroutine that is running while sendingReports is true.
+	reportsMu      sync.Mutex
+	reports        []ProduceResult
+	sendingReports bool
+
 	txnMu sync.Mutex
 	inTxn bool
 
@@ -118,6 +130,9 @@ func (p *producer) init(cl *Client) {
 		err:   errReloadProducerID,
 	})
 	p.c = sync.NewCond(&p.mu)
+	p.limiter = newProduceLimiter(&cl.cfg)
+	p.bufLimits = newBufferLimits(&cl.cfg)
+	p.watermarks = newBufferWatermarks(&cl.cfg)
 
 	inithooks := func() {
 		if p.hooks == nil {
@@ -125,6 +140,7 @@ func (p *producer) init(cl *Client) {
 				buffered    []HookProduceRecordBuffered
 				partitioned []HookProduceRecordPartitioned
 				unbuffered  []HookProduceRecordUnbuffered
+				limited     []HookProduceBufferLimited
 			}{}
 		}
 	}
@@ -142,6 +158,10 @@ func (p *producer) init(cl *Client) {
 			inithooks()
 			p.hooks.unbuffered = append(p.hooks.unbuffered, h)
 		}
+		if h, ok := h.(HookProduceBufferLimited); ok {
+			inithooks()
+			p.hooks.limited = append(p.hooks.limited, h)
+		}
 		if _, ok := h.(HookProduceBatchWritten); ok {
 			p.hasHookBatchWritten = true
 		}
@@ -327,8 +347,10 @@ func (f *FirstErrPromise) Err() error {
 
 // TryProduce is similar to Produce, but rather than blocking if the client
 // currently has MaxBufferedRecords or MaxBufferedBytes buffered, this fails
-// immediately with ErrMaxBuffered. See the Produce documentation for more
-// details.
+// immediately with ErrMaxBuffered. Similarly, rather than blocking if producing
+// would exceed MaxProduceRecordsPerSecond or MaxProduceBytesPerSecond, this
+// fails immediately with ErrRateLimited. See the Produce documentation for
+// more details.
 func (cl *Client) TryProduce(
 	ctx context.Context,
 	r *Record,
@@ -398,6 +420,11 @@ func (cl *Client) produce(
 		r.Topic = cl.cfg.defaultProduceTopic
 	}
 
+	interceptErr := cl.serdeEncode(ctx, r)
+	if interceptErr == nil && len(cl.cfg.producerInterceptors) > 0 {
+		interceptErr = cl.interceptSend(r)
+	}
+
 	p := &cl.producer
 	if p.hooks != nil && len(p.hooks.buffered) > 0 {
 		for _, h := range p.hooks.buffered {
@@ -406,6 +433,10 @@ func (cl *Client) produce(
 	}
 
 	// We can now fail the rec after the buffered hook.
+	if interceptErr != nil {
+		p.promiseRecordBeforeBuf(promisedRec{ctx, promise, r}, interceptErr)
+		return
+	}
 	if r.Topic == "" {
 		p.promiseRecordBeforeBuf(promisedRec{ctx, promise, r}, errNoTopic)
 		return
@@ -421,6 +452,13 @@ func (cl *Client) produce(
 		return
 	}
 
+	if p.limiter != nil {
+		if err := p.waitRateLimit(ctx, userSize, block); err != nil {
+			p.promiseRecordBeforeBuf(promisedRec{ctx, promise, r}, err)
+			return
+		}
+	}
+
 	// We have to grab the produce lock to check if this record will exceed
 	// configured limits. We try to keep the logic tight since this is
 	// effectively a global lock around producing.
@@ -516,11 +554,43 @@ func (cl *Client) produce(
 	}
 	p.bufferedRecords = nextBufRecs
 	p.bufferedBytes = nextBufBytes
+	notify := p.watermarks != nil && p.watermarks.update(p.bufferedRecords, p.bufferedBytes)
 	p.mu.Unlock()
 
+	if notify {
+		go p.notifyWatermarks()
+	}
+
 	cl.partitionRecord(promisedRec{ctx, promise, r})
 }
 
+// waitRateLimit waits until a record of the given size can be buffered
+// without exceeding the configured produce rate limits.
+func (p *producer) waitRateLimit(ctx context.Context, size int64, block bool) error {
+	wait := p.limiter.reserve(size)
+	if wait <= 0 {
+		return nil
+	}
+	if !block {
+		p.limiter.cancel(size)
+		return ErrRateLimited
+	}
+
+	p.cl.cfg.logger.Log(LogLevelDebug, "blocking Produce because we are over the produce rate limit", "wait", wait)
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-p.cl.ctx.Done():
+		p.limiter.cancel(size)
+		return ErrClientClosed
+	case <-ctx.Done():
+		p.limiter.cancel(size)
+		return ctx.Err()
+	}
+}
+
 type batchPromise struct {
 	baseOffset int64
 	pid        int64
@@ -572,6 +642,52 @@ start:
 	}
 }
 
+// reportDelivery queues a result for the DeliveryReports channel. We never
+// send inline: promises are finished under promisesMu, and a full channel
+// must not stall promises, buffer releases, or a Produce that is waiting on
+// the buffer from the same goroutine that drains the channel.
+func (cl *Client) reportDelivery(r ProduceResult) {
+	p := &cl.producer
+	p.reportsMu.Lock()
+	p.reports = append(p.reports, r)
+	start := !p.sendingReports
+	p.sendingReports = true
+	p.reportsMu.Unlock()
+	if start {
+		go cl.sendDeliveryReports()
+	}
+}
+
+func (cl *Client) sendDeliveryReports() {
+	p := &cl.producer
+	ch := cl.cfg.deliveryReports
+	for {
+		p.reportsMu.Lock()
+		reports := p.reports
+		p.reports = nil
+		if len(reports) == 0 {
+			p.sendingReports = false
+			p.reportsMu.Unlock()
+			return
+		}
+		p.reportsMu.Unlock()
+
+		for _, r := range reports {
+			select {
+			case ch <- r:
+			case <-cl.ctx.Done():
+				// Once the client is closing, we only send
+				// what the channel has room for so that
+				// nothing waits on a reader that has quit.
+				select {
+				case ch <- r:
+				default:
+				}
+			}
+		}
+	}
+}
+
 func (cl *Client) finishRecordPromise(pr promisedRec, err error, beforeBuffering bool) {
 	p := &cl.producer
 
@@ -587,7 +703,13 @@ func (cl *Client) finishRecordPromise(pr promisedRec, err error, beforeBuffering
 	// allowing users of Flush to know all buf recs are done by the
 	// time we notify flush below.
 	userSize := pr.userSize()
+	if len(cl.cfg.producerInterceptors) > 0 {
+		cl.interceptAck(pr.Record, err)
+	}
 	pr.promise(pr.Record, err)
+	if cl.cfg.deliveryReports != nil {
+		cl.reportDelivery(ProduceResult{pr.Record, err})
+	}
 
 	// If this record was never buffered, it's size was never accounted
 	// for on any p field: return early.
@@ -600,11 +722,15 @@ func (cl *Client) finishRecordPromise(pr promisedRec, err error, beforeBuffering
 	p.bufferedBytes -= userSize
 	p.bufferedRecords--
 	broadcast := p.blocked.Load() > 0 || p.bufferedRecords == 0 && p.flushing.Load() > 0
+	notify := p.watermarks != nil && p.watermarks.update(p.bufferedRecords, p.bufferedBytes)
 	p.mu.Unlock()
 
 	if broadcast {
 		p.c.Broadcast()
 	}
+	if notify {
+		go p.notifyWatermarks()
+	}
 }
 
 // partitionRecord loads the partitions for a topic and produce to them. If
@@ -641,43 +767,41 @@ func (cl *Client) doPartitionRecord(parts *topicPartitions, partsData *topicPart
 		return
 	}
 
-	var pick int
+	trp, _ := parts.partitioner.(TopicRackPartitioner)
 	tlp, _ := parts.partitioner.(TopicBackupPartitioner)
-	if tlp != nil {
-		if parts.lb == nil {
-			parts.lb = new(leastBackupInput)
+	partition := func() int {
+		switch {
+		case trp != nil:
+			return trp.PartitionByRack(pr.Record, len(mapping), func(i int) string { return mapping[i].leaderRack })
+		case tlp != nil:
+			if parts.lb == nil {
+				parts.lb = new(leastBackupInput)
+			}
+			parts.lb.mapping = mapping
+			return tlp.PartitionByBackup(pr.Record, len(mapping), parts.lb)
+		default:
+			return parts.partitioner.Partition(pr.Record, len(mapping))
 		}
-		parts.lb.mapping = mapping
-		pick = tlp.PartitionByBackup(pr.Record, len(mapping), parts.lb)
-	} else {
-		pick = parts.partitioner.Partition(pr.Record, len(mapping))
 	}
+
+	pick := partition()
 	if pick < 0 || pick >= len(mapping) {
 		cl.producer.promiseRecord(pr, fmt.Errorf("invalid record partitioning choice of %d from %d available", pick, len(mapping)))
 		return
 	}
 
-	partition := mapping[pick]
-
 	onNewBatch, _ := parts.partitioner.(TopicPartitionerOnNewBatch)
 	abortOnNewBatch := onNewBatch != nil
-	processed := partition.records.bufferRecord(pr, abortOnNewBatch) // KIP-480
+	processed := mapping[pick].records.bufferRecord(pr, abortOnNewBatch) // KIP-480
 	if !processed {
 		onNewBatch.OnNewBatch()
 
-		if tlp != nil {
-			parts.lb.mapping = mapping
-			pick = tlp.PartitionByBackup(pr.Record, len(mapping), parts.lb)
-		} else {
-			pick = parts.partitioner.Partition(pr.Record, len(mapping))
-		}
-
+		pick = partition()
 		if pick < 0 || pick >= len(mapping) {
 			cl.producer.promiseRecord(pr, fmt.Errorf("invalid record partitioning choice of %d from %d available", pick, len(mapping)))
 			return
 		}
-		partition = mapping[pick]
-		partition.records.bufferRecord(pr, false) // KIP-480
+		mapping[pick].records.bufferRecord(pr, false) // KIP-480
 	}
 }
 
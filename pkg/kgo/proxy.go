@@ -0,0 +1,261 @@
+package kgo
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/textproto"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// proxyDialer returns a dial function that tunnels connections through the
+// proxy at u, connecting to the proxy itself with dialer. The scheme of u
+// must already have been validated.
+//
+// The dial timeout applies to the full dial: connecting to the proxy and
+// then asking the proxy to connect to the broker.
+func proxyDialer(u *url.URL, dialer *net.Dialer) func(context.Context, string, string) (net.Conn, error) {
+	connect := proxyConnectHTTP
+	if u.Scheme == "socks5" || u.Scheme == "socks5h" {
+		connect = proxyConnectSOCKS5
+	}
+	proxyAddr := u.Host
+	if u.Port() == "" {
+		port := "1080"
+		if u.Scheme == "http" {
+			port = "80"
+		}
+		proxyAddr = net.JoinHostPort(u.Hostname(), port)
+	}
+
+	return func(ctx context.Context, network, host string) (net.Conn, error) {
+		if dialer.Timeout > 0 {
+			var cancel func()
+			ctx, cancel = context.WithTimeout(ctx, dialer.Timeout)
+			defer cancel()
+		}
+		conn, err := dialer.DialContext(ctx, network, proxyAddr)
+		if err != nil {
+			return nil, err
+		}
+
+		// The proxy handshake is done on the raw connection; we bound
+		// it with the context deadline and close the connection if the
+		// context is canceled while we are blocked reading or writing.
+		if deadline, ok := ctx.Deadline(); ok {
+			conn.SetDeadline(deadline)
+		}
+		done := make(chan struct{})
+		quit := make(chan struct{})
+		go func() {
+			defer close(quit)
+			select {
+			case <-ctx.Done():
+				conn.SetDeadline(time.Now())
+			case <-done:
+			}
+		}()
+
+		tunneled, err := connect(conn, u.User, host)
+		close(done)
+		<-quit
+		if err == nil {
+			err = ctx.Err()
+		}
+		if err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("unable to connect to %s through proxy %s: %w", host, proxyAddr, err)
+		}
+		conn.SetDeadline(time.Time{})
+		return tunneled, nil
+	}
+}
+
+// proxyConnectSOCKS5 asks a SOCKS5 proxy to connect to host, per RFC 1928 and,
+// if user is non-nil, authenticates with username and password per RFC 1929.
+// The host is always sent as is, meaning the proxy resolves hostnames.
+func proxyConnectSOCKS5(conn net.Conn, user *url.Userinfo, host string) (net.Conn, error) {
+	const (
+		noAuth       = 0x00
+		userPassAuth = 0x02
+		noAcceptable = 0xff
+	)
+
+	method := byte(noAuth)
+	if user != nil {
+		method = userPassAuth
+	}
+	if _, err := conn.Write([]byte{5, 1, method}); err != nil {
+		return nil, err
+	}
+	var buf [2]byte
+	if _, err := io.ReadFull(conn, buf[:]); err != nil {
+		return nil, err
+	}
+	if buf[0] != 5 {
+		return nil, fmt.Errorf("socks5 proxy replied with unexpected version %d", buf[0])
+	}
+	switch buf[1] {
+	case method:
+	case noAcceptable:
+		return nil, errors.New("socks5 proxy did not accept our authentication method")
+	default:
+		return nil, fmt.Errorf("socks5 proxy chose unrequested authentication method %d", buf[1])
+	}
+
+	if method == userPassAuth {
+		username := user.Username()
+		password, _ := user.Password()
+		if len(username) > 255 || len(password) > 255 {
+			return nil, errors.New("socks5 username and password must each be at most 255 bytes")
+		}
+		auth := []byte{1, byte(len(username))}
+		auth = append(auth, username...)
+		auth = append(auth, byte(len(password)))
+		auth = append(auth, password...)
+		if _, err := conn.Write(auth); err != nil {
+			return nil, err
+		}
+		if _, err := io.ReadFull(conn, buf[:]); err != nil {
+			return nil, err
+		}
+		if buf[1] != 0 {
+			return nil, errors.New("socks5 proxy rejected our username and password")
+		}
+	}
+
+	hostname, portStr, err := net.SplitHostPort(host)
+	if err != nil {
+		return nil, err
+	}
+	port, err := strconv.ParseUint(portStr, 10, 16)
+	if err != nil {
+		return nil, fmt.Errorf("invalid port in %q: %w", host, err)
+	}
+	req := []byte{5, 1, 0} // version, CONNECT, reserved
+	if ip := net.ParseIP(hostname); ip == nil {
+		if len(hostname) > 255 {
+			return nil, fmt.Errorf("hostname %q is too long for socks5", hostname)
+		}
+		req = append(req, 3, byte(len(hostname)))
+		req = append(req, hostname...)
+	} else if ip4 := ip.To4(); ip4 != nil {
+		req = append(req, 1)
+		req = append(req, ip4...)
+	} else {
+		req = append(req, 4)
+		req = append(req, ip.To16()...)
+	}
+	req = binary.BigEndian.AppendUint16(req, uint16(port))
+	if _, err := conn.Write(req); err != nil {
+		return nil, err
+	}
+
+	// The reply is the version, status, a reserved byte, and then the
+	// address the proxy bound to, which we do not need.
+	var reply [4]byte
+	if _, err := io.ReadFull(conn, reply[:]); err != nil {
+		return nil, err
+	}
+	if reply[1] != 0 {
+		return nil, fmt.Errorf("socks5 proxy failed to connect: %s", socks5ReplyString(reply[1]))
+	}
+	var skip int
+	switch reply[3] {
+	case 1:
+		skip = net.IPv4len
+	case 4:
+		skip = net.IPv6len
+	case 3:
+		if _, err := io.ReadFull(conn, buf[:1]); err != nil {
+			return nil, err
+		}
+		skip = int(buf[0])
+	default:
+		return nil, fmt.Errorf("socks5 proxy replied with unknown address type %d", reply[3])
+	}
+	if _, err := io.CopyN(io.Discard, conn, int64(skip+2)); err != nil { // +2 for the port
+		return nil, err
+	}
+	return conn, nil
+}
+
+func socks5ReplyString(code byte) string {
+	switch code {
+	case 1:
+		return "general server failure"
+	case 2:
+		return "connection not allowed by ruleset"
+	case 3:
+		return "network unreachable"
+	case 4:
+		return "host unreachable"
+	case 5:
+		return "connection refused"
+	case 6:
+		return "TTL expired"
+	case 7:
+		return "command not supported"
+	case 8:
+		return "address type not supported"
+	default:
+		return "unknown reply code " + strconv.Itoa(int(code))
+	}
+}
+
+// proxyConnectHTTP asks an HTTP proxy to tunnel to host with a CONNECT
+// request, using basic proxy authorization if user is non-nil.
+func proxyConnectHTTP(conn net.Conn, user *url.Userinfo, host string) (net.Conn, error) {
+	var req strings.Builder
+	fmt.Fprintf(&req, "CONNECT %[1]s HTTP/1.1\r\nHost: %[1]s\r\n", host)
+	if user != nil {
+		password, _ := user.Password()
+		creds := base64.StdEncoding.EncodeToString([]byte(user.Username() + ":" + password))
+		fmt.Fprintf(&req, "Proxy-Authorization: Basic %s\r\n", creds)
+	}
+	req.WriteString("\r\n")
+	if _, err := io.WriteString(conn, req.String()); err != nil {
+		return nil, err
+	}
+
+	br := bufio.NewReader(conn)
+	tp := textproto.NewReader(br)
+	status, err := tp.ReadLine()
+	if err != nil {
+		return nil, err
+	}
+	proto, code, _ := strings.Cut(status, " ")
+	if !strings.HasPrefix(proto, "HTTP/1.") {
+		return nil, fmt.Errorf("http proxy replied with malformed status line %q", status)
+	}
+	if _, err := tp.ReadMIMEHeader(); err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(code, "200") {
+		return nil, fmt.Errorf("http proxy replied with status %q", code)
+	}
+
+	// Kafka brokers never write first, but if the proxy sent anything
+	// past the response, it must not be lost.
+	if br.Buffered() > 0 {
+		return &bufferedConn{conn, br}, nil
+	}
+	return conn, nil
+}
+
+// bufferedConn is a net.Conn that first reads what was buffered while
+// reading a proxy's response.
+type bufferedConn struct {
+	net.Conn
+	br *bufio.Reader
+}
+
+func (c *bufferedConn) Read(p []byte) (int, error) { return c.br.Read(p) }
@@ -0,0 +1,170 @@
+package kgo
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/textproto"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// listenProxy serves every connection accepted on a new local listener with fn
+// until the test ends, returning the listener's address.
+func listenProxy(t *testing.T, fn func(net.Conn)) string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				fn(conn)
+			}()
+		}
+	}()
+	return ln.Addr().String()
+}
+
+// tunnel dials host and copies between it and conn until either side is done.
+func tunnel(conn net.Conn, host string) {
+	upstream, err := net.Dial("tcp", host)
+	if err != nil {
+		return
+	}
+	defer upstream.Close()
+	go io.Copy(upstream, conn)
+	io.Copy(conn, upstream)
+}
+
+// fakeSOCKS5 is a minimal SOCKS5 proxy that requires user:pass if
+// authenticate is true and only supports hostname addresses.
+func fakeSOCKS5(authenticate bool) func(net.Conn) {
+	return func(conn net.Conn) {
+		r := bufio.NewReader(conn)
+		read := func(n int) []byte {
+			b := make([]byte, n)
+			io.ReadFull(r, b)
+			return b
+		}
+		greeting := read(2)
+		methods := read(int(greeting[1]))
+		want := byte(0)
+		if authenticate {
+			want = 2
+		}
+		if !strings.Contains(string(methods), string([]byte{want})) {
+			conn.Write([]byte{5, 0xff})
+			return
+		}
+		conn.Write([]byte{5, want})
+		if authenticate {
+			read(1)
+			user := string(read(int(read(1)[0])))
+			pass := string(read(int(read(1)[0])))
+			if user != "user" || pass != "pass" {
+				conn.Write([]byte{1, 1})
+				return
+			}
+			conn.Write([]byte{1, 0})
+		}
+		req := read(4)
+		if req[3] != 3 {
+			conn.Write([]byte{5, 8, 0, 1, 0, 0, 0, 0, 0, 0})
+			return
+		}
+		hostname := string(read(int(read(1)[0])))
+		port := binary.BigEndian.Uint16(read(2))
+		conn.Write([]byte{5, 0, 0, 1, 127, 0, 0, 1, 0, 0})
+		tunnel(conn, net.JoinHostPort(hostname, strconv.Itoa(int(port))))
+	}
+}
+
+// fakeHTTPProxy is a minimal HTTP CONNECT proxy that requires basic auth of
+// user:pass if authenticate is true.
+func fakeHTTPProxy(authenticate bool) func(net.Conn) {
+	return func(conn net.Conn) {
+		tp := textproto.NewReader(bufio.NewReader(conn))
+		line, _ := tp.ReadLine()
+		hdr, _ := tp.ReadMIMEHeader()
+		method, rest, _ := strings.Cut(line, " ")
+		host, _, _ := strings.Cut(rest, " ")
+		if method != "CONNECT" {
+			io.WriteString(conn, "HTTP/1.1 405 Method Not Allowed\r\n\r\n")
+			return
+		}
+		if authenticate && hdr.Get("Proxy-Authorization") != "Basic dXNlcjpwYXNz" {
+			io.WriteString(conn, "HTTP/1.1 407 Proxy Authentication Required\r\n\r\n")
+			return
+		}
+		io.WriteString(conn, "HTTP/1.1 200 Connection established\r\n\r\n")
+		tunnel(conn, host)
+	}
+}
+
+func TestProxyDialer(t *testing.T) {
+	echo := listenProxy(t, func(conn net.Conn) { io.Copy(conn, conn) })
+	_, port, _ := net.SplitHostPort(echo)
+	broker := net.JoinHostPort("localhost", port)
+
+	for _, test := range []struct {
+		name   string
+		scheme string
+		auth   bool
+		user   *url.Userinfo
+		expErr bool
+	}{
+		{"socks5", "socks5", false, nil, false},
+		{"socks5 auth", "socks5", true, url.UserPassword("user", "pass"), false},
+		{"socks5 bad auth", "socks5", true, url.UserPassword("user", "nope"), true},
+		{"socks5 missing auth", "socks5", true, nil, true},
+		{"http", "http", false, nil, false},
+		{"http auth", "http", true, url.UserPassword("user", "pass"), false},
+		{"http bad auth", "http", true, url.UserPassword("user", "nope"), true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			serve := fakeHTTPProxy(test.auth)
+			if test.scheme == "socks5" {
+				serve = fakeSOCKS5(test.auth)
+			}
+			u := &url.URL{Scheme: test.scheme, User: test.user, Host: listenProxy(t, serve)}
+			dial := proxyDialer(u, &net.Dialer{Timeout: 5 * time.Second})
+
+			conn, err := dial(context.Background(), "tcp", broker)
+			if test.expErr {
+				if err == nil {
+					conn.Close()
+					t.Fatal("expected error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			defer conn.Close()
+
+			msg := []byte("hello through the proxy")
+			if _, err := conn.Write(msg); err != nil {
+				t.Fatal(err)
+			}
+			got := make([]byte, len(msg))
+			if _, err := io.ReadFull(conn, got); err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != string(msg) {
+				t.Errorf("got %q, exp %q", got, msg)
+			}
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package kgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Serde encodes and decodes record keys or values, for example with a schema
+// registry. The sr package's *sr.Serde implements this interface.
+// Implementations must be safe for concurrent use.
+type Serde interface {
+	// Encode encodes v.
+	Encode(v any) ([]byte, error)
+	// DecodeNew decodes b into a new value.
+	DecodeNew(b []byte) (any, error)
+}
+
+// KeySerde sets a Serde to encode record keys with when producing and to
+// decode record keys with when consuming. See ValueSerde for more details.
+func KeySerde(s Serde) Opt {
+	return clientOpt{func(cfg *cfg) { cfg.keySerde = s }}
+}
+
+// ValueSerde sets a Serde to encode record values with when producing and to
+// decode record values with when consuming, allowing schema encoding to be
+// configured once for the client rather than at every call site.
+//
+// When producing, records created with SerdeRecord have their value encoded
+// before any ProducerInterceptor is called, and the encoding replaces the
+// record's Value. Producing a SerdeRecord with a non-nil value and no
+// ValueSerde fails the record. Records that are not created with SerdeRecord
+// are produced as is.
+//
+// When consuming, every polled record with a non-nil Value is decoded after
+// all ConsumerInterceptors are called, and the decoded value is available
+// with Record.SerdeValue. If a record cannot be decoded, the record is left
+// as is and the record's partition error is set, if it is not already set.
+func ValueSerde(s Serde) Opt {
+	return clientOpt{func(cfg *cfg) { cfg.valueSerde = s }}
+}
+
+// SerdeRecord returns a Record with the key and value to be encoded with the
+// client's KeySerde and ValueSerde when the record is produced. A nil key or
+// value is not encoded and is produced as nil.
+//
+// The key and value are stored in the record's Context. The produce context
+// is used as the parent of the record's Context, as if Context were nil.
+func SerdeRecord(key, value any) *Record {
+	return &Record{Context: &serdeContext{key: key, value: value}}
+}
+
+// SerdeKey returns the key the record was created with in SerdeRecord, or
+// the key decoded with KeySerde when consuming. This returns nil if the
+// record has no such key.
+func (r *Record) SerdeKey() any {
+	if sc := serdeContextOf(r); sc != nil {
+		return sc.key
+	}
+	return nil
+}
+
+// SerdeValue returns the value the record was created with in SerdeRecord,
+// or the value decoded with ValueSerde when consuming. This returns nil if
+// the record has no such value.
+func (r *Record) SerdeValue() any {
+	if sc := serdeContextOf(r); sc != nil {
+		return sc.value
+	}
+	return nil
+}
+
+// serdeContext carries the unencoded or decoded key and value of a record.
+// The parent context is nil for records from SerdeRecord until the record is
+// produced, in which case this behaves like context.Background.
+type serdeContext struct {
+	parent     context.Context
+	key, value any
+}
+
+type serdeContextKey struct{}
+
+func (c *serdeContext) Deadline() (time.Time, bool) {
+	if c.parent == nil {
+		return time.Time{}, false
+	}
+	return c.parent.Deadline()
+}
+
+func (c *serdeContext) Done() <-chan struct{} {
+	if c.parent == nil {
+		return nil
+	}
+	return c.parent.Done()
+}
+
+func (c *serdeContext) Err() error {
+	if c.parent == nil {
+		return nil
+	}
+	return c.parent.Err()
+}
+
+func (c *serdeContext) Value(key any) any {
+	if key == (serdeContextKey{}) {
+		return c
+	}
+	if c.parent == nil {
+		return nil
+	}
+	return c.parent.Value(key)
+}
+
+func serdeContextOf(r *Record) *serdeContext {
+	if r.Context == nil {
+		return nil
+	}
+	sc, _ := r.Context.Value(serdeContextKey{}).(*serdeContext)
+	return sc
+}
+
+// serdeEncode encodes the key and value of a record created with SerdeRecord,
+// setting the produce context as the record context's parent if necessary.
+func (cl *Client) serdeEncode(ctx context.Context, r *Record) error {
+	sc, ok := r.Context.(*serdeContext)
+	if !ok {
+		return nil
+	}
+	if sc.parent == nil {
+		sc.parent = ctx
+	}
+	var err error
+	if sc.key != nil {
+		if cl.cfg.keySerde == nil {
+			return errors.New("unable to encode record key: missing KeySerde")
+		}
+		if r.Key, err = cl.cfg.keySerde.Encode(sc.key); err != nil {
+			return fmt.Errorf("unable to encode record key: %w", err)
+		}
+	}
+	if sc.value != nil {
+		if cl.cfg.valueSerde == nil {
+			return errors.New("unable to encode record value: missing ValueSerde")
+		}
+		if r.Value, err = cl.cfg.valueSerde.Encode(sc.value); err != nil {
+			return fmt.Errorf("unable to encode record value: %w", err)
+		}
+	}
+	return nil
+}
+
+// serdeDecode decodes the keys and values of all polled records if KeySerde
+// or ValueSerde is used.
+func (cl *Client) serdeDecode(fetches Fetches) Fetches {
+	ks, vs := cl.cfg.keySerde, cl.cfg.valueSerde
+	if ks == nil && vs == nil {
+		return fetches
+	}
+	for i := range fetches {
+		for j := range fetches[i].Topics {
+			t := &fetches[i].Topics[j]
+			for k := range t.Partitions {
+				p := &t.Partitions[k]
+				for _, r := range p.Records {
+					if err := decodeSerdeRecord(ks, vs, r); err != nil && p.Err == nil {
+						p.Err = fmt.Errorf("unable to decode record at offset %d: %w", r.Offset, err)
+					}
+				}
+			}
+		}
+	}
+	return fetches
+}
+
+func decodeSerdeRecord(ks, vs Serde, r *Record) error {
+	var (
+		sc  = serdeContext{parent: r.Context}
+		err error
+	)
+	if ks != nil && r.Key != nil {
+		if sc.key, err = ks.DecodeNew(r.Key); err != nil {
+			return fmt.Errorf("key: %w", err)
+		}
+	}
+	if vs != nil && r.Value != nil {
+		if sc.value, err = vs.DecodeNew(r.Value); err != nil {
+			return fmt.Errorf("value: %w", err)
+		}
+	}
+	if sc.key != nil || sc.value != nil {
+		r.Context = &sc
+	}
+	return nil
+}
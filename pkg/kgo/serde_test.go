@@ -0,0 +1,58 @@
+package kgo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+type jsonSerde struct{}
+
+func (jsonSerde) Encode(v any) ([]byte, error) { return json.Marshal(v) }
+
+func (jsonSerde) DecodeNew(b []byte) (any, error) {
+	var v map[string]any
+	err := json.Unmarshal(b, &v)
+	return v, err
+}
+
+func TestSerde(t *testing.T) {
+	cl := &Client{cfg: cfg{valueSerde: jsonSerde{}}}
+
+	type key struct{}
+	ctx := context.WithValue(context.Background(), key{}, "v")
+	r := SerdeRecord(nil, map[string]any{"a": 1.0})
+	if err := cl.serdeEncode(ctx, r); err != nil {
+		t.Fatalf("unexpected encode err: %v", err)
+	}
+	if string(r.Value) != `{"a":1}` || r.Key != nil {
+		t.Errorf("got key %q value %q, expected nil key and {\"a\":1} value", r.Key, r.Value)
+	}
+	if r.Context.Value(key{}) != "v" {
+		t.Error("record context does not inherit from the produce context")
+	}
+
+	if err := cl.serdeEncode(ctx, SerdeRecord("k", nil)); err == nil {
+		t.Error("expected error encoding key with no KeySerde")
+	}
+
+	bad := &Record{Value: []byte("not json"), Offset: 3}
+	fetches := cl.serdeDecode(Fetches{{Topics: []FetchTopic{{
+		Topic: "t",
+		Partitions: []FetchPartition{{
+			Records: []*Record{{Value: r.Value}, bad, {}},
+		}},
+	}}}})
+	p := fetches[0].Topics[0].Partitions[0]
+	if v, _ := p.Records[0].SerdeValue().(map[string]any); v["a"] != 1.0 {
+		t.Errorf("got decoded value %v, expected map[a:1]", p.Records[0].SerdeValue())
+	}
+	var jsonErr *json.SyntaxError
+	if !errors.As(p.Err, &jsonErr) || bad.SerdeValue() != nil {
+		t.Errorf("got partition err %v, expected a json syntax error", p.Err)
+	}
+	if p.Records[2].Context != nil {
+		t.Error("record with no value was unexpectedly decoded")
+	}
+}
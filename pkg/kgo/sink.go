@@ -84,8 +84,9 @@ func (s *sink) createReq(id int64, epoch int16) (*produceRequest, *kmsg.AddParti
 		producerID:    id,
 		producerEpoch: epoch,
 
-		hasHook:    s.cl.producer.hasHookBatchWritten,
-		compressor: s.cl.compressor,
+		hasHook:          s.cl.producer.hasHookBatchWritten || s.cl.collector() != nil,
+		compressor:       s.cl.compressor,
+		topicCompressors: s.cl.topicCompressors,
 
 		wireLength:      s.cl.baseProduceRequestLength(), // start length with no topics
 		wireLengthLimit: s.cl.cfg.maxBrokerWriteBytes,
@@ -687,7 +688,7 @@ func (s *sink) handleReqResp(br *broker, req *produceRequest, resp kmsg.Response
 	}
 	s.firstRespCheck(req.idempotent(), req.version)
 	s.consecutiveFailures.Store(0)
-	defer req.metrics.hook(&s.cl.cfg, br) // defer to end so that non-written batches are removed
+	defer req.metrics.hook(s.cl, br) // defer to end so that non-written batches are removed
 
 	var b *bytes.Buffer
 	debug := s.cl.cfg.logger.Level() >= LogLevelDebug
@@ -1031,6 +1032,10 @@ func (cl *Client) finishBatch(batch *recBatch, producerID int64, producerEpoch i
 	batch.records = nil
 	batch.mu.Unlock()
 
+	if l := cl.producer.bufLimits; l != nil {
+		l.removeRecords(recBuf.topic, recBuf.partition, records)
+	}
+
 	cl.producer.promiseBatch(batchPromise{
 		baseOffset: baseOffset,
 		pid:        producerID,
@@ -1343,6 +1348,20 @@ func (recBuf *recBuf) bufferRecord(pr promisedRec, abortOnNewBatch bool) bool {
 		return true
 	}
 
+	// If per topic or per partition limits are configured, we account
+	// for the record now. Any return below that does not buffer the
+	// record must remove it.
+	limits := recBuf.cl.producer.bufLimits
+	if limits != nil && !limits.add(recBuf.topic, recBuf.partition, pr.userSize()) {
+		if hooks := recBuf.cl.producer.hooks; hooks != nil {
+			for _, h := range hooks.limited {
+				h.OnProduceBufferLimited(recBuf.topic, recBuf.partition)
+			}
+		}
+		recBuf.cl.producer.promiseRecord(pr, ErrMaxBufferedPartition)
+		return true
+	}
+
 	var (
 		newBatch       = true
 		onDrainBatch   = recBuf.batchDrainIdx == len(recBuf.batches)
@@ -1359,6 +1378,10 @@ func (recBuf *recBuf) bufferRecord(pr promisedRec, abortOnNewBatch bool) bool {
 		newBatch := recBuf.newRecordBatch()
 		appended, aborted := newBatch.tryBuffer(pr, produceVersion, recBuf.maxRecordBatchBytes, abortOnNewBatch)
 
+		if !appended && limits != nil {
+			limits.remove(recBuf.topic, recBuf.partition, pr.userSize())
+		}
+
 		switch {
 		case aborted: // not processed
 			return false
@@ -1521,6 +1544,10 @@ func (recBuf *recBuf) failAllRecords(err error) {
 		batch.records = nil
 		batch.mu.Unlock()
 
+		if l := recBuf.cl.producer.bufLimits; l != nil {
+			l.removeRecords(recBuf.topic, recBuf.partition, records)
+		}
+
 		recBuf.cl.producer.promiseBatch(batchPromise{
 			recs: records,
 			err:  err,
@@ -1747,7 +1774,8 @@ type produceRequest struct {
 	metrics produceMetrics
 	hasHook bool
 
-	compressor *compressor
+	compressor       *compressor
+	topicCompressors map[string]*compressor
 
 	// wireLength is initially the size of sending a produce request,
 	// including the request header, with no topics. We start with the
@@ -1759,17 +1787,18 @@ type produceRequest struct {
 
 type produceMetrics map[string]map[int32]ProduceBatchMetrics
 
-func (p produceMetrics) hook(cfg *cfg, br *broker) {
+func (p produceMetrics) hook(cl *Client, br *broker) {
 	if len(p) == 0 {
 		return
 	}
 	var hooks []HookProduceBatchWritten
-	cfg.hooks.each(func(h Hook) {
+	cl.cfg.hooks.each(func(h Hook) {
 		if h, ok := h.(HookProduceBatchWritten); ok {
 			hooks = append(hooks, h)
 		}
 	})
-	if len(hooks) == 0 {
+	m := cl.collector()
+	if len(hooks) == 0 && m == nil {
 		return
 	}
 	go func() {
@@ -1780,6 +1809,13 @@ func (p produceMetrics) hook(cfg *cfg, br *broker) {
 				}
 			}
 		}
+		if m != nil {
+			for topic, partitions := range p {
+				for _, metrics := range partitions {
+					m.produceBatchWritten(br.meta, topic, metrics)
+				}
+			}
+		}
 	}()
 }
 
@@ -1826,6 +1862,9 @@ func (p *produceRequest) tryAddBatch(produceVersion int32, recBuf *recBuf, batch
 		}
 	}
 
+	if m := recBuf.cl.collector(); m != nil && batch.tries > 0 {
+		m.produceBatchRetry(recBuf.topic)
+	}
 	batch.tries++
 	p.wireLength += batchWireLength
 	p.batches.addBatch(
@@ -2094,8 +2133,17 @@ func (b *recBatch) tryBuffer(pr promisedRec, produceVersion, maxBatchBytes int32
 // ENCODING // - this section is all about actually writing a produce request
 //////////////
 
-func (*produceRequest) Key() int16           { return 0 }
-func (*produceRequest) MaxVersion() int16    { return 11 }
+func (*produceRequest) Key() int16 { return 0 }
+
+// MaxVersion returns 12 only for non-transactional requests: with v12, the
+// broker adds partitions to transactions itself (KIP-890), which we do not
+// support, and it is otherwise no different from v11.
+func (p *produceRequest) MaxVersion() int16 {
+	if p.txnID != nil {
+		return 11
+	}
+	return 12
+}
 func (p *produceRequest) SetVersion(v int16) { p.version = v }
 func (p *produceRequest) GetVersion() int16  { return p.version }
 func (p *produceRequest) IsFlexible() bool   { return p.version >= 9 }
@@ -2131,6 +2179,11 @@ func (p *produceRequest) AppendTo(dst []byte) []byte {
 			dst = kbin.AppendArrayLen(dst, len(partitions))
 		}
 
+		compressor := p.compressor
+		if tc, ok := p.topicCompressors[topic]; ok {
+			compressor = tc
+		}
+
 		var tmetrics map[int32]ProduceBatchMetrics
 		if p.hasHook {
 			tmetrics = make(map[int32]ProduceBatchMetrics)
@@ -2152,9 +2205,9 @@ func (p *produceRequest) AppendTo(dst []byte) []byte {
 			batch.canFailFromLoadErrs = false // we are going to write this batch: the response status is now unknown
 			var pmetrics ProduceBatchMetrics
 			if p.version < 3 {
-				dst, pmetrics = batch.appendToAsMessageSet(dst, uint8(p.version), p.compressor)
+				dst, pmetrics = batch.appendToAsMessageSet(dst, uint8(p.version), compressor)
 			} else {
-				dst, pmetrics = batch.appendTo(dst, p.version, p.producerID, p.producerEpoch, p.txnID != nil, p.compressor)
+				dst, pmetrics = batch.appendTo(dst, p.version, p.producerID, p.producerEpoch, p.txnID != nil, compressor)
 			}
 			batch.mu.Unlock()
 			if p.hasHook {
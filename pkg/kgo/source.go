@@ -614,6 +614,12 @@ func (s *source) createReq() *fetchRequest {
 		session: s.session,
 	}
 
+	// A draining consumer issues no new fetches; an empty request stops
+	// the fetch loop.
+	if s.cl.consumer.loadDrain() != nil {
+		return req
+	}
+
 	paused := s.cl.consumer.loadPaused()
 
 	s.cursorsMu.Lock()
@@ -784,26 +790,64 @@ func (s *source) fetch(consumerSession *consumerSession, doneFetch chan<- struct
 	// If our fetch is killed, we want to cancel waiting for the response.
 	var (
 		kresp       kmsg.Response
+		reqResp     kmsg.Response
+		reqErr      error
 		requested   = make(chan struct{})
 		ctx, cancel = context.WithCancel(consumerSession.ctx)
 	)
 	defer cancel()
 
+	// The hedged request must be copied before the original is issued,
+	// since issuing the original modifies it.
+	var hreq *fetchRequest
+	if s.cl.cfg.hedgeFetches > 0 {
+		hreq = req.sessionlessCopy()
+	}
+
 	br, err := s.cl.brokerOrErr(ctx, s.nodeID, errUnknownBroker)
 	if err != nil {
+		reqErr = err
 		close(requested)
 	} else {
 		br.do(ctx, req, func(k kmsg.Response, e error) {
-			kresp, err = k, e
+			reqResp, reqErr = k, e
 			close(requested)
 		})
 	}
 
-	select {
-	case <-requested:
-		fetched = true
-	case <-ctx.Done():
-		return
+	// If the request is slow and we are hedging, we issue the same fetch
+	// to a follower and use whichever response comes back first; see
+	// HedgeFetches.
+	var (
+		hedgeAfter <-chan time.Time
+		hedged     chan hedgedFetch
+		hedgedWon  bool
+	)
+	if after := s.cl.cfg.hedgeFetches; hreq != nil && err == nil {
+		timer := time.NewTimer(time.Duration(req.maxWait)*time.Millisecond + after)
+		defer timer.Stop()
+		hedgeAfter = timer.C
+	}
+
+	for !fetched {
+		select {
+		case <-requested:
+			kresp, err = reqResp, reqErr
+			fetched = true
+		case <-hedgeAfter:
+			hedgeAfter = nil
+			hedged = s.hedgeFetch(ctx, consumerSession, hreq)
+		case h := <-hedged:
+			hedged = nil
+			if h.usable() {
+				s.cl.cfg.logger.Log(LogLevelInfo, "hedged fetch to follower returned before the original fetch, using the follower's response", "broker", logID(s.nodeID), "follower", logID(h.br.meta.NodeID))
+				br, kresp, err = h.br, h.resp, nil
+				hedgedWon = true
+				fetched = true
+			}
+		case <-ctx.Done():
+			return
+		}
 	}
 
 	var didBackoff bool
@@ -940,7 +984,11 @@ func (s *source) fetch(consumerSession *consumerSession, doneFetch chan<- struct
 	// advance past them).
 	setOffsets = true
 
-	if resp.Version < 7 || resp.SessionID <= 0 {
+	if hedgedWon {
+		// We abandoned the request in our session; we do not know
+		// whether the broker processed it, so we must start over.
+		s.session.reset()
+	} else if resp.Version < 7 || resp.SessionID <= 0 {
 		// If the version is less than 7, we cannot use fetch sessions,
 		// so we kill them on the first response.
 		s.session.kill()
@@ -988,6 +1036,109 @@ func (s *source) fetch(consumerSession *consumerSession, doneFetch chan<- struct
 	return
 }
 
+// hedgedFetch is the result of a fetch issued to a follower because the
+// original fetch was slow.
+type hedgedFetch struct {
+	br   *broker
+	resp kmsg.Response
+	err  error
+}
+
+// usable returns whether the hedged response can be used in place of the
+// original. We only use responses that have no errors at all: a follower can
+// be behind the leader or may not allow follower fetching, and we would
+// rather wait for the leader than handle errors from a replica we are not
+// actually consuming from.
+func (h hedgedFetch) usable() bool {
+	if h.err != nil {
+		return false
+	}
+	resp := h.resp.(*kmsg.FetchResponse)
+	if resp.ErrorCode != 0 {
+		return false
+	}
+	for i := range resp.Topics {
+		for j := range resp.Topics[i].Partitions {
+			if resp.Topics[i].Partitions[j].ErrorCode != 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// sessionlessCopy returns a copy of the request that does not use a fetch
+// session, for hedging. Issuing a request modifies its topic and partition
+// order, so we copy those; the used offsets are only read.
+func (f *fetchRequest) sessionlessCopy() *fetchRequest {
+	c := *f
+	c.session = fetchSession{epoch: -1, killed: true}
+	c.torder = append([]string(nil), f.torder...)
+	c.porder = make(map[string][]int32, len(f.porder))
+	for topic, partitions := range f.porder {
+		c.porder[topic] = append([]int32(nil), partitions...)
+	}
+	return &c
+}
+
+// hedgeFetch issues req, a sessionless copy of the original request, to an
+// in-sync follower of every partition in the request, returning a channel
+// that receives the result. This returns nil if there is no follower to
+// hedge to.
+func (s *source) hedgeFetch(ctx context.Context, consumerSession *consumerSession, req *fetchRequest) chan hedgedFetch {
+	tps := consumerSession.tps.load()
+	counts := make(map[int32]int)
+	for topic, partitions := range req.usedOffsets {
+		td := tps.loadTopic(topic)
+		if td == nil {
+			return nil
+		}
+		for partition := range partitions {
+			if int(partition) >= len(td.partitions) {
+				return nil
+			}
+			for _, replica := range td.partitions[partition].isr {
+				if replica != s.nodeID {
+					counts[replica]++
+				}
+			}
+		}
+	}
+
+	// We prefer followers in our rack, and then the lowest ID so that we
+	// consistently hedge to the same follower.
+	var follower *broker
+	s.cl.brokersMu.RLock()
+	for id, n := range counts {
+		if n != req.numOffsets {
+			continue
+		}
+		b := findBroker(s.cl.brokers, id)
+		if b == nil {
+			continue
+		}
+		sameRack := func(b *broker) bool { return b.meta.Rack != nil && *b.meta.Rack == s.cl.cfg.rack }
+		if follower == nil ||
+			sameRack(b) && !sameRack(follower) ||
+			sameRack(b) == sameRack(follower) && b.meta.NodeID < follower.meta.NodeID {
+			follower = b
+		}
+	}
+	s.cl.brokersMu.RUnlock()
+
+	if follower == nil {
+		s.cl.cfg.logger.Log(LogLevelDebug, "fetch is slow, but no single follower is in sync for all partitions being fetched; not hedging", "broker", logID(s.nodeID))
+		return nil
+	}
+	s.cl.cfg.logger.Log(LogLevelDebug, "fetch is slow, hedging to follower", "broker", logID(s.nodeID), "follower", logID(follower.meta.NodeID))
+
+	hedged := make(chan hedgedFetch, 1)
+	follower.do(ctx, req, func(resp kmsg.Response, err error) {
+		hedged <- hedgedFetch{follower, resp, err}
+	})
+	return hedged
+}
+
 // Parses a fetch response into a Fetch, offsets to reload, and whether
 // metadata needs updating.
 //
@@ -1008,6 +1159,7 @@ func (s *source) handleReqResp(br *broker, req *fetchRequest, resp *kmsg.FetchRe
 		numErrsStripped  int
 		kip320           = s.cl.supportsOffsetForLeaderEpoch()
 		kmove            kip951move
+		collector        = s.cl.collector()
 	)
 	defer kmove.maybeBeginMove(s.cl)
 
@@ -1069,7 +1221,7 @@ func (s *source) handleReqResp(br *broker, req *fetchRequest, resp *kmsg.FetchRe
 				continue
 			}
 
-			fp := partOffset.processRespPartition(br, rp, s.cl.decompressor, s.cl.cfg.hooks)
+			fp := partOffset.processRespPartition(br, rp, s.cl.decompressor, s.cl.cfg.hooks, collector)
 			if fp.Err != nil {
 				if moving := kmove.maybeAddFetchPartition(resp, rp, partOffset.from); moving {
 					strip(topic, partition, fp.Err)
@@ -1151,6 +1303,34 @@ func (s *source) handleReqResp(br *broker, req *fetchRequest, resp *kmsg.FetchRe
 				// no reset offset was configured. If so, we ignore
 				// trying to reset and instead keep our failed partition.
 				addList := func(replica int32, log bool) {
+					if fn := s.cl.cfg.onOffsetOutOfRange; fn != nil {
+						reset := fn(OffsetOutOfRangePartition{
+							Topic:          topic,
+							Partition:      partition,
+							Offset:         partOffset.offset,
+							LogStartOffset: fp.LogStartOffset,
+							HighWatermark:  fp.HighWatermark,
+							LastConsumed:   partOffset.from.lastConsumedTime,
+						})
+						if reset.noReset {
+							keep = true
+							return
+						}
+						reloadOffsets.addLoad(topic, partition, loadTypeList, offsetLoad{
+							replica: replica,
+							Offset:  reset,
+						})
+						if log {
+							s.cl.cfg.logger.Log(LogLevelWarn, "received OFFSET_OUT_OF_RANGE, resetting to the offset chosen by OnOffsetOutOfRange",
+								"broker", logID(s.nodeID),
+								"topic", topic,
+								"partition", partition,
+								"prior_offset", partOffset.offset,
+								"reset_offset", reset,
+							)
+						}
+						return
+					}
 					if s.cl.cfg.resetOffset.noReset {
 						keep = true
 					} else if !partOffset.from.lastConsumedTime.IsZero() {
@@ -1246,7 +1426,7 @@ func (s *source) handleReqResp(br *broker, req *fetchRequest, resp *kmsg.FetchRe
 
 // processRespPartition processes all records in all potentially compressed
 // batches (or message sets).
-func (o *cursorOffsetNext) processRespPartition(br *broker, rp *kmsg.FetchResponseTopicPartition, decompressor *decompressor, hooks hooks) FetchPartition {
+func (o *cursorOffsetNext) processRespPartition(br *broker, rp *kmsg.FetchResponseTopicPartition, decompressor *decompressor, hooks hooks, collector *clientMetrics) FetchPartition {
 	fp := FetchPartition{
 		Partition:        rp.Partition,
 		Err:              kerr.ErrorForCode(rp.ErrorCode),
@@ -1389,6 +1569,9 @@ func (o *cursorOffsetNext) processRespPartition(br *broker, rp *kmsg.FetchRespon
 				h.OnFetchBatchRead(br.meta, o.from.topic, o.from.partition, m)
 			}
 		})
+		if collector != nil {
+			collector.fetchBatchRead(br.meta, o.from.topic, m)
+		}
 	}
 
 	return fp
@@ -1973,6 +2156,7 @@ func PreferLagAt(preferLagAt int64) PreferLagFn {
 					i--
 				}
 			}
+			tlags[t] = tlag
 		}
 		if len(tlags) == 0 {
 			return nil, nil
@@ -2004,6 +2188,131 @@ func PreferLagAt(preferLagAt int64) PreferLagFn {
 	}
 }
 
+// PreferLagFairly is like PreferLagAt, but bounds how long any topic or
+// partition can be starved. With PreferLagAt, partitions that are not lagging
+// much can be perpetually ordered behind laggier partitions, and may receive
+// nothing if the laggier partitions fill the fetch response.
+//
+// A topic or partition is deferred in a fetch request if lag ordering moves
+// it later than where the default round-robin order would place it. Once
+// something has been deferred in maxDeferrals consecutive fetch requests
+// containing it, the next request that contains it places it first, in its
+// round-robin order. Topics are ordered before partitions within each topic,
+// and both are bounded independently. Deferrals are forgotten for any topic
+// or partition that is not in a fetch request, so that nothing is kept for
+// topics and partitions that are no longer being consumed.
+//
+// If maxDeferrals is less than one, this is equivalent to PreferLagAt. The
+// returned function is safe for concurrent use, as it is shared across all
+// brokers being fetched from.
+func PreferLagFairly(preferLagAt int64, maxDeferrals int) PreferLagFn {
+	byLag := PreferLagAt(preferLagAt)
+	if byLag == nil || maxDeferrals < 1 {
+		return byLag
+	}
+
+	var (
+		mu      sync.Mutex
+		tdefers = make(map[string]int)
+		pdefers = make(map[string]map[int32]int)
+	)
+	return func(lag map[string]map[int32]int64, torderPrior []string, porderPrior map[string][]int32) ([]string, map[string][]int32) {
+		torder, porder := byLag(lag, torderPrior, porderPrior)
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		// We only keep counts for what is in this call, so that
+		// topics and partitions we stop consuming are not kept forever.
+		pruneDefers(tdefers, torderPrior)
+		for t := range pdefers {
+			if _, ok := porderPrior[t]; !ok {
+				delete(pdefers, t)
+			}
+		}
+
+		torder = boundDeferrals(completeOrder(torder, torderPrior), torderPrior, tdefers, maxDeferrals)
+		rporder := make(map[string][]int32, len(porderPrior))
+		for t, prior := range porderPrior {
+			defers := pdefers[t]
+			if defers == nil {
+				defers = make(map[int32]int)
+				pdefers[t] = defers
+			}
+			pruneDefers(defers, prior)
+			rporder[t] = boundDeferrals(completeOrder(porder[t], prior), prior, defers, maxDeferrals)
+		}
+		return torder, rporder
+	}
+}
+
+// pruneDefers deletes deferral counts for anything not in prior.
+func pruneDefers[T comparable](defers map[T]int, prior []T) {
+	if len(defers) == 0 {
+		return
+	}
+	in := make(map[T]bool, len(prior))
+	for _, v := range prior {
+		in[v] = true
+	}
+	for v := range defers {
+		if !in[v] {
+			delete(defers, v)
+		}
+	}
+}
+
+// completeOrder returns order followed by anything in prior that order is
+// missing, which is how the client completes a PreferLagFn's order.
+func completeOrder[T comparable](order, prior []T) []T {
+	seen := make(map[T]bool, len(order))
+	complete := make([]T, 0, len(prior))
+	for _, v := range order {
+		if !seen[v] {
+			seen[v] = true
+			complete = append(complete, v)
+		}
+	}
+	for _, v := range prior {
+		if !seen[v] {
+			complete = append(complete, v)
+		}
+	}
+	return complete
+}
+
+// boundDeferrals moves anything in order that has been deferred at least
+// maxDeferrals times to the front, in prior order, and then updates how many consecutive
+// times everything in order has been deferred: placed later than in prior.
+func boundDeferrals[T comparable](order, prior []T, defers map[T]int, maxDeferrals int) []T {
+	bounded := make([]T, 0, len(order))
+	boosted := make(map[T]bool)
+	for _, v := range prior {
+		if defers[v] >= maxDeferrals {
+			bounded = append(bounded, v)
+			boosted[v] = true
+		}
+	}
+	for _, v := range order {
+		if !boosted[v] {
+			bounded = append(bounded, v)
+		}
+	}
+
+	priorIdx := make(map[T]int, len(prior))
+	for i, v := range prior {
+		priorIdx[v] = i
+	}
+	for i, v := range bounded {
+		if i > priorIdx[v] {
+			defers[v]++
+		} else {
+			delete(defers, v)
+		}
+	}
+	return bounded
+}
+
 // If the end user prefers to consume lag, we reorder our previously ordered
 // partitions, preferring first the laggiest topics, and then within those, the
 // laggiest partitions.
@@ -2106,7 +2415,7 @@ func (f *fetchRequest) MaxVersion() int16 {
 	if f.disableIDs || f.session.disableIDs {
 		return 12
 	}
-	return 16
+	return 17
 }
 func (f *fetchRequest) SetVersion(v int16) { f.version = v }
 func (f *fetchRequest) GetVersion() int16  { return f.version }
@@ -0,0 +1,78 @@
+package kgo
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestHedgedFetchUsable(t *testing.T) {
+	t.Parallel()
+
+	resp := func(topErr int16, partErrs ...int16) *kmsg.FetchResponse {
+		r := kmsg.NewPtrFetchResponse()
+		r.ErrorCode = topErr
+		rt := kmsg.NewFetchResponseTopic()
+		for i, code := range partErrs {
+			rp := kmsg.NewFetchResponseTopicPartition()
+			rp.Partition = int32(i)
+			rp.ErrorCode = code
+			rt.Partitions = append(rt.Partitions, rp)
+		}
+		r.Topics = append(r.Topics, rt)
+		return r
+	}
+	for _, test := range []struct {
+		name string
+		h    hedgedFetch
+		exp  bool
+	}{
+		{"ok", hedgedFetch{resp: resp(0, 0, 0)}, true},
+		{"request error", hedgedFetch{err: context.Canceled}, false},
+		{"top level error", hedgedFetch{resp: resp(kerr.FetchSessionIDNotFound.Code, 0)}, false},
+		{"partition error", hedgedFetch{resp: resp(0, 0, kerr.NotLeaderForPartition.Code)}, false},
+	} {
+		if got := test.h.usable(); got != test.exp {
+			t.Errorf("%s: got usable %v, exp %v", test.name, got, test.exp)
+		}
+	}
+}
+
+func TestFetchRequestSessionlessCopy(t *testing.T) {
+	t.Parallel()
+
+	req := &fetchRequest{
+		maxWait:    100,
+		numOffsets: 2,
+		usedOffsets: usedOffsets{"t": {
+			0: &cursorOffsetNext{},
+			1: &cursorOffsetNext{},
+		}},
+		torder:  []string{"t"},
+		porder:  map[string][]int32{"t": {0, 1}},
+		session: fetchSession{id: 7, epoch: 3},
+	}
+	c := req.sessionlessCopy()
+
+	if c.session.epoch != -1 || !c.session.killed {
+		t.Errorf("copy has session epoch %d killed %v, exp -1 and killed", c.session.epoch, c.session.killed)
+	}
+	if req.session.id != 7 || req.session.epoch != 3 || req.session.killed {
+		t.Errorf("original session was modified: %+v", req.session)
+	}
+	if c.maxWait != req.maxWait || c.numOffsets != req.numOffsets || !reflect.DeepEqual(c.usedOffsets, req.usedOffsets) {
+		t.Error("copy does not fetch what the original fetches")
+	}
+
+	// Issuing a request reorders torder and porder; doing so on the
+	// copy must not reorder the original.
+	c.torder[0] = "x"
+	c.porder["t"][0] = 1
+	c.porder["u"] = []int32{0}
+	if !reflect.DeepEqual(req.torder, []string{"t"}) || !reflect.DeepEqual(req.porder, map[string][]int32{"t": {0, 1}}) {
+		t.Errorf("modifying the copy modified the original order: %v %v", req.torder, req.porder)
+	}
+}
@@ -0,0 +1,84 @@
+package kgo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPreferLagAt(t *testing.T) {
+	// Partitions 1 and 2 are not lagging enough and are left for the
+	// client to add back in their prior order. Previously, dropping them
+	// was not saved, and the returned order could contain duplicates of
+	// the lagging partitions or the partitions that should be dropped.
+	lag := map[string]map[int32]int64{
+		"t":     {0: 100, 1: 1, 2: 5, 3: 50},
+		"quiet": {0: 1},
+	}
+	prior := map[string][]int32{"t": {1, 2, 0, 3}, "quiet": {0}}
+	for i := 0; i < 20; i++ { // map iteration order varies
+		torder, porder := PreferLagAt(10)(lag, []string{"quiet", "t"}, prior)
+		if exp := []string{"t"}; !reflect.DeepEqual(torder, exp) {
+			t.Fatalf("got topic order %v, exp %v", torder, exp)
+		}
+		if exp := map[string][]int32{"t": {0, 3}}; !reflect.DeepEqual(porder, exp) {
+			t.Fatalf("got partition order %v, exp %v", porder, exp)
+		}
+	}
+}
+
+func TestPreferLagFairly(t *testing.T) {
+	fn := PreferLagFairly(1, 2)
+
+	// Partition 0 has no lag and is always first in round-robin order,
+	// but partitions 1 and 2 are lagging and are ordered first. After
+	// two deferrals, partition 0 is placed first for one request.
+	lag := map[string]map[int32]int64{"t": {0: 0, 1: 10, 2: 20}}
+	prior := map[string][]int32{"t": {0, 1, 2}}
+	for i, exp := range [][]int32{
+		{2, 1, 0},
+		{2, 1, 0},
+		{0, 2, 1},
+		{2, 1, 0},
+		{2, 1, 0},
+		{0, 2, 1},
+	} {
+		torder, porder := fn(lag, []string{"t"}, prior)
+		if !reflect.DeepEqual(torder, []string{"t"}) {
+			t.Errorf("#%d: got topic order %v, exp [t]", i, torder)
+		}
+		if got := porder["t"]; !reflect.DeepEqual(got, exp) {
+			t.Errorf("#%d: got partition order %v, exp %v", i, got, exp)
+		}
+	}
+
+	// Topics are bounded the same way.
+	lag = map[string]map[int32]int64{"a": {0: 0}, "b": {0: 5}}
+	prior = map[string][]int32{"a": {0}, "b": {0}}
+	for i, exp := range [][]string{
+		{"b", "a"},
+		{"b", "a"},
+		{"a", "b"},
+	} {
+		if torder, _ := fn(lag, []string{"a", "b"}, prior); !reflect.DeepEqual(torder, exp) {
+			t.Errorf("topics #%d: got %v, exp %v", i, torder, exp)
+		}
+	}
+
+	// Counts are dropped for anything not in a call: partition 0 and
+	// topic "a" are deferred twice, then missing for one call, and then
+	// are deferred from scratch rather than boosted.
+	fn = PreferLagFairly(1, 2)
+	lag = map[string]map[int32]int64{"a": {0: 0}, "b": {0: 5}, "t": {0: 0, 1: 10, 2: 20}}
+	prior = map[string][]int32{"a": {0}, "b": {0}, "t": {0, 1, 2}}
+	for i := 0; i < 2; i++ {
+		fn(lag, []string{"a", "b", "t"}, prior)
+	}
+	fn(lag, []string{"b", "t"}, map[string][]int32{"b": {0}, "t": {1, 2}})
+	torder, porder := fn(lag, []string{"a", "b", "t"}, prior)
+	if exp := []string{"t", "b", "a"}; !reflect.DeepEqual(torder, exp) {
+		t.Errorf("after pruning: got topic order %v, exp %v", torder, exp)
+	}
+	if exp := []int32{2, 1, 0}; !reflect.DeepEqual(porder["t"], exp) {
+		t.Errorf("after pruning: got partition order %v, exp %v", porder["t"], exp)
+	}
+}
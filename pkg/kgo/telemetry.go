@@ -0,0 +1,483 @@
+package kgo
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// Client telemetry (KIP-714) pushes a subset of the client's metrics to
+// brokers that ask for them. Brokers opt in by configuring a client metrics
+// subscription; most clusters do not, in which case the client only asks for
+// its subscription once per push interval.
+//
+// Metrics are collected with the same collector as CollectMetrics. If the
+// client does not otherwise collect metrics, collection is only turned on
+// while the broker's subscription requests metrics.
+
+const defTelemetryInterval = 5 * time.Minute
+
+type clientTelemetry struct {
+	cl      *Client
+	metrics *clientMetrics
+
+	ctx    context.Context
+	cancel func()
+	done   chan struct{}
+
+	connectedOnce sync.Once
+	connected     chan struct{}
+
+	instanceID [16]byte
+	sub        *telemetrySubscription
+	start      time.Time        // when the client started, for cumulative sums
+	lastPush   time.Time        // when we last pushed, for delta sums
+	prev       map[string]int64 // values as of the last push, for delta sums
+
+	compressors map[int8]*compressor
+}
+
+type telemetrySubscription struct {
+	id       int32
+	codecs   []int8
+	interval time.Duration
+	maxBytes int32
+	delta    bool
+	metrics  []string
+}
+
+func newClientTelemetry(cl *Client) *clientTelemetry {
+	ctx, cancel := context.WithCancel(cl.ctx)
+	t := &clientTelemetry{
+		cl:        cl,
+		metrics:   cl.metrics,
+		ctx:       ctx,
+		cancel:    cancel,
+		done:      make(chan struct{}),
+		connected: make(chan struct{}),
+		start:     time.Now(),
+		prev:      make(map[string]int64),
+	}
+	if t.metrics == nil {
+		t.metrics = newClientMetrics()
+		t.metrics.active = new(atomic.Bool)
+		cl.metrics = t.metrics
+	}
+	return t
+}
+
+// brokerConnected lets the telemetry loop begin once the client connects to
+// any broker, so that telemetry does not make an unused client connect.
+func (t *clientTelemetry) brokerConnected() {
+	t.connectedOnce.Do(func() { close(t.connected) })
+}
+
+// setActive turns metric collection on or off if metrics are only collected
+// for telemetry. Cumulative sums start from when collection is turned on.
+func (t *clientTelemetry) setActive(active bool) {
+	if t.metrics.active == nil {
+		return
+	}
+	if !active {
+		t.metrics.active.Store(false)
+	} else if !t.metrics.active.Swap(true) {
+		t.start = time.Now()
+	}
+}
+
+// loop gets the client's subscription and pushes metrics every push interval
+// until the client is closed or the broker indicates we should stop.
+func (t *clientTelemetry) loop() {
+	defer close(t.done)
+
+	select {
+	case <-t.ctx.Done():
+		return
+	case <-t.connected:
+	}
+
+	var (
+		cl       = t.cl
+		needSub  = true
+		tries    int
+		wait     time.Duration
+		interval = defTelemetryInterval
+	)
+	for {
+		if wait > 0 {
+			timer := time.NewTimer(wait)
+			select {
+			case <-t.ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+		}
+
+		if needSub {
+			sub, err := t.getSubscription()
+			if err != nil {
+				if errors.Is(err, errBrokerTooOld) || errors.Is(err, errUnknownRequestKey) {
+					cl.cfg.logger.Log(LogLevelInfo, "brokers do not support client telemetry, not pushing metrics")
+					return
+				}
+				if t.ctx.Err() != nil {
+					return
+				}
+				tries++
+				wait = cl.cfg.retryBackoff(tries)
+				cl.cfg.logger.Log(LogLevelDebug, "unable to get client telemetry subscription", "err", err, "backoff", wait)
+				continue
+			}
+			tries = 0
+			t.sub = sub
+			interval = sub.interval
+			t.setActive(len(sub.metrics) > 0)
+			if len(sub.metrics) == 0 {
+				// Nothing is requested; we check again
+				// next interval in case that changes.
+				wait = interval
+				continue
+			}
+			needSub = false
+			cl.cfg.logger.Log(LogLevelInfo, "client telemetry subscription received, pushing metrics",
+				"subscription_id", sub.id,
+				"interval", interval,
+				"metrics", sub.metrics,
+			)
+
+			// The first push after getting a subscription is
+			// jittered to avoid many clients pushing at once.
+			cl.rng(func(r *rand.Rand) {
+				wait = interval/2 + time.Duration(r.Int63n(int64(interval)+1))
+			})
+			continue
+		}
+
+		err := t.push(t.ctx, false)
+		wait = interval
+		switch {
+		case err == nil:
+		case errors.Is(err, kerr.UnknownSubscriptionID),
+			errors.Is(err, kerr.UnsupportedCompressionType):
+			needSub, wait = true, 0
+		case errors.Is(err, kerr.InvalidRequest),
+			errors.Is(err, kerr.InvalidRecord):
+			cl.cfg.logger.Log(LogLevelError, "broker rejected client telemetry, no longer pushing metrics", "err", err)
+			t.sub = nil
+			t.setActive(false)
+			return
+		default:
+			if t.ctx.Err() != nil {
+				return
+			}
+			cl.cfg.logger.Log(LogLevelDebug, "unable to push client telemetry", "err", err)
+		}
+	}
+}
+
+func (t *clientTelemetry) getSubscription() (*telemetrySubscription, error) {
+	req := kmsg.NewPtrGetTelemetrySubscriptionsRequest()
+	req.ClientInstanceID = t.instanceID
+	resp, err := req.RequestWith(t.ctx, t.cl)
+	if err != nil {
+		return nil, err
+	}
+	if err := kerr.ErrorForCode(resp.ErrorCode); err != nil {
+		return nil, err
+	}
+	if t.instanceID == ([16]byte{}) {
+		t.instanceID = resp.ClientInstanceID
+	}
+	sub := &telemetrySubscription{
+		id:       resp.SubscriptionID,
+		codecs:   resp.AcceptedCompressionTypes,
+		interval: time.Duration(resp.PushIntervalMillis) * time.Millisecond,
+		maxBytes: resp.TelemetryMaxBytes,
+		delta:    resp.DeltaTemporality,
+		metrics:  resp.RequestedMetrics,
+	}
+	if sub.interval <= 0 {
+		sub.interval = defTelemetryInterval
+	}
+	return sub, nil
+}
+
+// push pushes all subscribed metrics. If terminating, this is the final push
+// before the client closes.
+func (t *clientTelemetry) push(ctx context.Context, terminating bool) error {
+	sub := t.sub
+	now := time.Now()
+
+	start := t.start
+	if sub.delta && !t.lastPush.IsZero() {
+		start = t.lastPush
+	}
+	ms := t.collect(sub)
+	payload := encodeTelemetry(ms, t.prev, sub.delta, start, now, t.cl.cfg.softwareName, t.cl.cfg.softwareVersion)
+	if sub.maxBytes > 0 && len(payload) > int(sub.maxBytes) {
+		t.cl.cfg.logger.Log(LogLevelWarn, "client telemetry is larger than the broker allows, skipping push",
+			"size", len(payload),
+			"max_bytes", sub.maxBytes,
+		)
+		return nil
+	}
+
+	req := kmsg.NewPtrPushTelemetryRequest()
+	req.ClientInstanceID = t.instanceID
+	req.SubscriptionID = sub.id
+	req.Terminating = terminating
+	req.Metrics = payload
+
+	buf := byteBuffers.Get().(*bytes.Buffer)
+	buf.Reset()
+	defer byteBuffers.Put(buf)
+	if c, codec := t.compressor(sub.codecs); c != nil {
+		if compressed, _ := c.compress(buf, payload, 7); compressed != nil && len(compressed) < len(payload) {
+			req.CompressionType = codec
+			req.Metrics = compressed
+		}
+	}
+
+	resp, err := req.RequestWith(ctx, t.cl)
+	if err != nil {
+		return err
+	}
+	if err := kerr.ErrorForCode(resp.ErrorCode); err != nil {
+		return err
+	}
+	t.lastPush = now
+	for _, m := range ms {
+		for _, p := range m.points {
+			t.prev[p.key(m.name)] = p.value
+		}
+	}
+	return nil
+}
+
+// compressor returns a compressor for the first codec the broker accepts, or
+// nil if the broker accepts none of the standard codecs.
+func (t *clientTelemetry) compressor(codecs []int8) (*compressor, int8) {
+	for _, codec := range codecs {
+		if codec < int8(codecGzip) || codec > int8(codecZstd) {
+			continue
+		}
+		if c := t.compressors[codec]; c != nil {
+			return c, codec
+		}
+		c, err := newCompressor(CompressionCodec{codec: codecType(codec)})
+		if err != nil || c == nil {
+			continue
+		}
+		if t.compressors == nil {
+			t.compressors = make(map[int8]*compressor)
+		}
+		t.compressors[codec] = c
+		return c, codec
+	}
+	return nil, 0
+}
+
+// close stops the telemetry loop and issues a final terminating push if the
+// broker is subscribed to metrics.
+func (t *clientTelemetry) close(ctx context.Context) {
+	t.cancel()
+	<-t.done
+	if t.sub == nil || len(t.sub.metrics) == 0 {
+		return
+	}
+	defer func() { t.sub = nil }()
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	if err := t.push(ctx, true); err != nil {
+		t.cl.cfg.logger.Log(LogLevelDebug, "unable to push final client telemetry", "err", err)
+	}
+}
+
+// telemetryMetric is a monotonic sum pushed with client telemetry.
+type telemetryMetric struct {
+	name   string
+	unit   string
+	points []telemetryPoint
+}
+
+type telemetryPoint struct {
+	attrs []string // key, value pairs
+	value int64
+}
+
+func (p telemetryPoint) key(name string) string {
+	return name + "|" + strings.Join(p.attrs, "|")
+}
+
+// collect converts the client's metrics into telemetry metrics, using the
+// Java client's naming, and keeps only the metrics the subscription asks for.
+func (t *clientTelemetry) collect(sub *telemetrySubscription) []telemetryMetric {
+	s := t.metrics.snapshot()
+
+	role := "org.apache.kafka.producer."
+	if t.cl.consumer.consuming() {
+		role = "org.apache.kafka.consumer."
+	}
+
+	var conns, closes, written, read, requests int64
+	for _, b := range s.Brokers {
+		conns += b.Connects
+		closes += b.Disconnects
+		written += b.BytesWritten
+		read += b.BytesRead
+	}
+	for _, r := range s.Requests {
+		requests += r.Requests
+	}
+	total := func(v int64) []telemetryPoint { return []telemetryPoint{{value: v}} }
+
+	ms := []telemetryMetric{
+		{role + "connection.creation.total", "1", total(conns)},
+		{role + "connection.close.total", "1", total(closes)},
+		{role + "outgoing.byte.total", "By", total(written)},
+		{role + "incoming.byte.total", "By", total(read)},
+		{role + "request.total", "1", total(requests)},
+	}
+
+	topics := make([]string, 0, len(s.Topics))
+	for topic := range s.Topics {
+		topics = append(topics, topic)
+	}
+	sort.Strings(topics)
+
+	var (
+		produced, fetched, fetchedBytes   int64
+		topicSent, topicSentBytes         []telemetryPoint
+		topicConsumed, topicConsumedBytes []telemetryPoint
+	)
+	for _, topic := range topics {
+		tm := s.Topics[topic]
+		attrs := []string{"topic", topic}
+		if tm.ProducedBatches > 0 {
+			produced += tm.ProducedRecords
+			topicSent = append(topicSent, telemetryPoint{attrs, tm.ProducedRecords})
+			topicSentBytes = append(topicSentBytes, telemetryPoint{attrs, tm.ProducedBytes})
+		}
+		if tm.FetchedBatches > 0 {
+			fetched += tm.FetchedRecords
+			fetchedBytes += tm.FetchedBytes
+			topicConsumed = append(topicConsumed, telemetryPoint{attrs, tm.FetchedRecords})
+			topicConsumedBytes = append(topicConsumedBytes, telemetryPoint{attrs, tm.FetchedBytes})
+		}
+	}
+	if len(topicSent) > 0 {
+		ms = append(ms,
+			telemetryMetric{"org.apache.kafka.producer.record.send.total", "1", total(produced)},
+			telemetryMetric{"org.apache.kafka.producer.topic.record.send.total", "1", topicSent},
+			telemetryMetric{"org.apache.kafka.producer.topic.byte.total", "By", topicSentBytes},
+		)
+	}
+	if len(topicConsumed) > 0 {
+		ms = append(ms,
+			telemetryMetric{"org.apache.kafka.consumer.fetch.manager.records.consumed.total", "1", total(fetched)},
+			telemetryMetric{"org.apache.kafka.consumer.fetch.manager.bytes.consumed.total", "By", total(fetchedBytes)},
+			telemetryMetric{"org.apache.kafka.consumer.fetch.manager.topic.records.consumed.total", "1", topicConsumed},
+			telemetryMetric{"org.apache.kafka.consumer.fetch.manager.topic.bytes.consumed.total", "By", topicConsumedBytes},
+		)
+	}
+
+	keep := ms[:0]
+	for _, m := range ms {
+		for _, prefix := range sub.metrics {
+			if strings.HasPrefix(m.name, prefix) {
+				keep = append(keep, m)
+				break
+			}
+		}
+	}
+	return keep
+}
+
+// encodeTelemetry encodes metrics as an OpenTelemetry MetricsData protobuf,
+// which is the format KIP-714 requires. If delta, values are reported as the
+// difference from prev.
+func encodeTelemetry(ms []telemetryMetric, prev map[string]int64, delta bool, start, now time.Time, scopeName, scopeVersion string) []byte {
+	temporality := uint64(2) // cumulative
+	if delta {
+		temporality = 1
+	}
+
+	var scope []byte
+	scope = pbAppendString(scope, 1, scopeName)
+	scope = pbAppendString(scope, 2, scopeVersion)
+
+	var scopeMetrics []byte
+	scopeMetrics = pbAppendBytes(scopeMetrics, 1, scope)
+	for _, m := range ms {
+		var sum []byte
+		for _, p := range m.points {
+			v := p.value
+			if delta {
+				v -= prev[p.key(m.name)]
+			}
+			var dp []byte
+			for i := 0; i+1 < len(p.attrs); i += 2 {
+				var kv, anyv []byte
+				anyv = pbAppendString(anyv, 1, p.attrs[i+1])
+				kv = pbAppendString(kv, 1, p.attrs[i])
+				kv = pbAppendBytes(kv, 2, anyv)
+				dp = pbAppendBytes(dp, 7, kv)
+			}
+			dp = pbAppendFixed64(dp, 2, uint64(start.UnixNano()))
+			dp = pbAppendFixed64(dp, 3, uint64(now.UnixNano()))
+			dp = pbAppendFixed64(dp, 6, uint64(v))
+			sum = pbAppendBytes(sum, 1, dp)
+		}
+		sum = pbAppendVarint(sum, 2, temporality)
+		sum = pbAppendVarint(sum, 3, 1) // monotonic
+
+		var metric []byte
+		metric = pbAppendString(metric, 1, m.name)
+		metric = pbAppendString(metric, 3, m.unit)
+		metric = pbAppendBytes(metric, 7, sum)
+		scopeMetrics = pbAppendBytes(scopeMetrics, 2, metric)
+	}
+
+	var resourceMetrics []byte
+	resourceMetrics = pbAppendBytes(resourceMetrics, 2, scopeMetrics)
+
+	var data []byte
+	return pbAppendBytes(data, 1, resourceMetrics)
+}
+
+func pbAppendTag(dst []byte, field, wire uint64) []byte {
+	return binary.AppendUvarint(dst, field<<3|wire)
+}
+
+func pbAppendVarint(dst []byte, field, v uint64) []byte {
+	dst = pbAppendTag(dst, field, 0)
+	return binary.AppendUvarint(dst, v)
+}
+
+func pbAppendFixed64(dst []byte, field, v uint64) []byte {
+	dst = pbAppendTag(dst, field, 1)
+	return binary.LittleEndian.AppendUint64(dst, v)
+}
+
+func pbAppendBytes(dst []byte, field uint64, b []byte) []byte {
+	dst = pbAppendTag(dst, field, 2)
+	dst = binary.AppendUvarint(dst, uint64(len(b)))
+	return append(dst, b...)
+}
+
+func pbAppendString(dst []byte, field uint64, s string) []byte {
+	dst = pbAppendTag(dst, field, 2)
+	dst = binary.AppendUvarint(dst, uint64(len(s)))
+	return append(dst, s...)
+}
@@ -0,0 +1,152 @@
+package kgo
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kversion"
+)
+
+// pbFields returns the raw values of all fields numbered field in a
+// protobuf message; varints and fixed64s are returned as eight little
+// endian bytes.
+func pbFields(t *testing.T, b []byte, field uint64) [][]byte {
+	var vs [][]byte
+	for len(b) > 0 {
+		tag, n := binary.Uvarint(b)
+		if n <= 0 {
+			t.Fatal("invalid tag")
+		}
+		b = b[n:]
+		var v []byte
+		switch tag & 7 {
+		case 0:
+			u, n := binary.Uvarint(b)
+			if n <= 0 {
+				t.Fatal("invalid varint")
+			}
+			b = b[n:]
+			v = binary.LittleEndian.AppendUint64(nil, u)
+		case 1:
+			v, b = b[:8], b[8:]
+		case 2:
+			l, n := binary.Uvarint(b)
+			if n <= 0 || uint64(len(b)-n) < l {
+				t.Fatal("invalid length")
+			}
+			v, b = b[n:n+int(l)], b[n+int(l):]
+		default:
+			t.Fatalf("unexpected wire type %d", tag&7)
+		}
+		if tag>>3 == field {
+			vs = append(vs, v)
+		}
+	}
+	return vs
+}
+
+func TestEncodeTelemetry(t *testing.T) {
+	ms := []telemetryMetric{{
+		name: "org.apache.kafka.producer.topic.record.send.total",
+		unit: "1",
+		points: []telemetryPoint{
+			{attrs: []string{"topic", "foo"}, value: 10},
+			{attrs: []string{"topic", "bar"}, value: 3},
+		},
+	}}
+	prev := map[string]int64{
+		ms[0].points[0].key(ms[0].name): 4,
+	}
+	now := time.Now()
+
+	for _, test := range []struct {
+		delta       bool
+		temporality uint64
+		exp         []int64
+	}{
+		{false, 2, []int64{10, 3}},
+		{true, 1, []int64{6, 3}},
+	} {
+		data := encodeTelemetry(ms, prev, test.delta, now.Add(-time.Second), now, "kgo", "v1")
+
+		rms := pbFields(t, data, 1)
+		if len(rms) != 1 {
+			t.Fatalf("got %d resource metrics, exp 1", len(rms))
+		}
+		sms := pbFields(t, rms[0], 2)
+		if len(sms) != 1 {
+			t.Fatalf("got %d scope metrics, exp 1", len(sms))
+		}
+		if scope := pbFields(t, sms[0], 1); len(scope) != 1 || string(pbFields(t, scope[0], 1)[0]) != "kgo" {
+			t.Errorf("missing or invalid scope")
+		}
+		metrics := pbFields(t, sms[0], 2)
+		if len(metrics) != 1 {
+			t.Fatalf("got %d metrics, exp 1", len(metrics))
+		}
+		if name := string(pbFields(t, metrics[0], 1)[0]); name != ms[0].name {
+			t.Errorf("got name %q, exp %q", name, ms[0].name)
+		}
+		sum := pbFields(t, metrics[0], 7)[0]
+		if temporality := binary.LittleEndian.Uint64(pbFields(t, sum, 2)[0]); temporality != test.temporality {
+			t.Errorf("delta %v: got temporality %d, exp %d", test.delta, temporality, test.temporality)
+		}
+		dps := pbFields(t, sum, 1)
+		if len(dps) != len(test.exp) {
+			t.Fatalf("got %d data points, exp %d", len(dps), len(test.exp))
+		}
+		for i, dp := range dps {
+			if v := int64(binary.LittleEndian.Uint64(pbFields(t, dp, 6)[0])); v != test.exp[i] {
+				t.Errorf("delta %v: point %d: got %d, exp %d", test.delta, i, v, test.exp[i])
+			}
+		}
+	}
+}
+
+func TestTelemetryCollectsWithoutHooks(t *testing.T) {
+	t.Parallel()
+
+	cl, err := NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cl.Close()
+
+	if hooks := cl.OptValue(WithHooks).(hooks); len(hooks) != 0 {
+		t.Errorf("got %d hooks, expected telemetry to add none", len(hooks))
+	}
+	if cl.producer.hasHookBatchWritten {
+		t.Error("producer has a batch written hook without any user hooks")
+	}
+
+	// Nothing is collected until a subscription asks for metrics.
+	if cl.collector() != nil {
+		t.Fatal("collecting metrics without a subscription")
+	}
+	cl.telemetry.setActive(true)
+	if cl.collector() == nil {
+		t.Fatal("not collecting metrics once subscribed")
+	}
+	cl.telemetry.setActive(false)
+	if cl.collector() != nil {
+		t.Fatal("still collecting metrics after the subscription stopped asking")
+	}
+	if s := cl.Metrics(); s.Brokers != nil {
+		t.Error("Metrics returned telemetry metrics without CollectMetrics")
+	}
+}
+
+func TestTelemetryPinnedMaxVersions(t *testing.T) {
+	t.Parallel()
+
+	cl, err := NewClient(MaxVersions(kversion.V3_5_0()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cl.Close()
+
+	if cl.telemetry != nil {
+		t.Error("telemetry started even though max versions cannot get a subscription")
+	}
+}
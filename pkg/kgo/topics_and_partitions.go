@@ -503,6 +503,17 @@ type topicPartition struct {
 	// whether the data changed (leader or leader epoch, etc.).
 	topicPartitionData
 
+	// The rack of the partition's leader, if known, for partitioners
+	// implementing TopicRackPartitioner. As with the ISR below, this is
+	// always from the latest metadata.
+	leaderRack string
+
+	// The in-sync replicas for this partition, used to pick a follower
+	// to send hedged fetches to. This is always from the latest metadata
+	// and is not part of topicPartitionData: the ISR changing does not
+	// require migrating anything.
+	isr []int32
+
 	// If we do not have a load error, we copy the records and cursor
 	// pointers from the old after updating any necessary fields in them
 	// (see migrate functions below).
@@ -426,6 +426,14 @@ retry:
 			willTryCommit = false
 			goto retry
 
+		case willTryCommit && s.cl.isReinitTxnErr(endTxnErr):
+			// Our commit failed and the producer ID is now failed.
+			// We reset to our committed offsets below to replay, and
+			// the next Begin recovers the producer ID.
+			s.cl.cfg.logger.Log(LogLevelInfo, "end transaction with commit failed with a recoverable transactional error; retrying as abort", "err", endTxnErr)
+			willTryCommit = false
+			goto retry
+
 		case errors.Is(endTxnErr, kerr.UnknownServerError):
 			s.cl.cfg.logger.Log(LogLevelInfo, "end transaction with commit unknown server error; retrying")
 			after := time.NewTimer(s.cl.cfg.retryBackoff(tries))
@@ -471,6 +479,78 @@ retry:
 	}
 }
 
+// GroupTransactFunc processes polled fetches in a GroupTransactSession's Run
+// loop, returning the records to produce in the same transaction that the
+// fetches are committed in.
+type GroupTransactFunc func(ctx context.Context, fetches Fetches) ([]*Record, error)
+
+// Run runs an exactly-once processing loop until the context is canceled, the
+// client is closed, or an error occurs. Each iteration polls, begins a
+// transaction, passes the fetches to fn, produces the records fn returns, and
+// ends the transaction, committing if everything succeeded.
+//
+// If the transaction is aborted rather than committed, for example because
+// the group rebalanced or a produce failed, End resets the consumer to the
+// last committed offsets, and the aborted records are polled and passed to fn
+// again. Thus, fn must only have side effects through the records it returns:
+// records should be returned rather than produced directly, so that produce
+// failures abort the transaction.
+//
+// If fn returns an error, the transaction is aborted, the consumer is reset
+// to the last committed offsets, and Run returns the error. Partition errors
+// in fetches are logged and otherwise ignored; records that were polled
+// successfully are still processed.
+//
+// Run returns ctx.Err() if the context is canceled, ErrClientClosed if the
+// client is closed, and otherwise any error from beginning or ending a
+// transaction. Errors from ending a transaction are not retryable; see End.
+func (s *GroupTransactSession) Run(ctx context.Context, fn GroupTransactFunc) error {
+	for {
+		fetches := s.PollFetches(ctx)
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if fetches.IsClientClosed() {
+			return ErrClientClosed
+		}
+		fetches.EachError(func(t string, p int32, err error) {
+			s.cl.cfg.logger.Log(LogLevelWarn, "transact session run loop fetch error", "topic", t, "partition", p, "err", err)
+		})
+		if fetches.Empty() {
+			continue
+		}
+
+		if err := s.Begin(); err != nil {
+			return err
+		}
+
+		rs, fnErr := fn(ctx, fetches)
+		if fnErr != nil {
+			if _, err := s.End(ctx, TryAbort); err != nil {
+				return err
+			}
+			return fnErr
+		}
+
+		e := AbortingFirstErrPromise(s.cl)
+		for _, r := range rs {
+			s.Produce(ctx, r, e.Promise())
+		}
+		produceErr := e.Err()
+		if produceErr != nil {
+			s.cl.cfg.logger.Log(LogLevelWarn, "transact session run loop produce failed, aborting and replaying", "err", produceErr)
+		}
+
+		committed, err := s.End(ctx, produceErr == nil)
+		if err != nil {
+			return err
+		}
+		if !committed {
+			s.cl.cfg.logger.Log(LogLevelInfo, "transact session run loop aborted, replaying from the last committed offsets")
+		}
+	}
+}
+
 // BeginTransaction sets the client to a transactional state, erroring if there
 // is no transactional ID, or if the producer is currently in a fatal
 // (unrecoverable) state, or if the client is already in a transaction.
@@ -978,20 +1058,85 @@ func (cl *Client) maybeRecoverProducerID(ctx context.Context) (necessary, did bo
 	kip588 := cl.producer.idVersion >= 4 && errors.Is(ke, kerr.InvalidProducerEpoch /* || err == kerr.TransactionTimedOut when implemented in Kafka */)
 
 	recoverable := kip360 || kip588
-	if !recoverable {
+	reinit := !recoverable && cl.isReinitTxnErr(err)
+	if !recoverable && !reinit {
 		return true, false, err // fatal, unrecoverable
 	}
 
 	// Storing errReloadProducerID will reset sequence numbers as appropriate
-	// when the producer ID is reloaded successfully.
-	cl.producer.id.Store(&producerID{
+	// when the producer ID is reloaded successfully. If we are
+	// reinitializing, we forget our ID and epoch entirely: Kafka will bump
+	// the epoch for our transactional ID, aborting any transaction still
+	// ongoing.
+	reload := &producerID{
 		id:    id,
 		epoch: epoch,
 		err:   errReloadProducerID,
-	})
+	}
+	if reinit {
+		reload.id, reload.epoch = -1, -1
+		cl.producer.readded = false
+	}
+	cl.producer.id.Store(reload)
+
+	cl.cfg.logger.Log(LogLevelInfo, "recovering producer id after transactional error",
+		"transactional_id", *cl.cfg.txnID,
+		"producer_id", id,
+		"epoch", epoch,
+		"err", err,
+		"reinitializing", reinit,
+	)
+	if fn := cl.cfg.onTxnRecover; fn != nil {
+		fn(TransactionRecovery{
+			Err:           err,
+			ProducerID:    id,
+			ProducerEpoch: epoch,
+			Reinitialized: reinit,
+		})
+	}
 	return true, true, nil
 }
 
+// TransactionRecovery describes the client recovering its producer ID after a
+// transactional error; see RecoverTransactions.
+type TransactionRecovery struct {
+	// Err is the error that failed the producer ID.
+	Err error
+	// ProducerID is the producer ID that failed.
+	ProducerID int64
+	// ProducerEpoch is the epoch of the producer ID that failed.
+	ProducerEpoch int16
+	// Reinitialized is true if Err is normally fatal and the client is
+	// initializing a new producer ID, fencing any other producer with the
+	// same transactional ID. If false, the client is recovering by bumping
+	// the epoch of its existing producer ID (KIP-360, KIP-588).
+	Reinitialized bool
+}
+
+// isReinitTxnErr returns whether err is an otherwise fatal transactional
+// error that we can recover from by initializing a new producer ID, which is
+// only true if the client opted in with RecoverTransactions.
+//
+// ProducerFenced is never recoverable: it means another producer took over
+// our transactional ID, and reinitializing would fence that producer in turn,
+// defeating zombie fencing.
+func (cl *Client) isReinitTxnErr(err error) bool {
+	if !cl.cfg.txnRecover {
+		return false
+	}
+	if pe := (*errProducerIDLoadFail)(nil); errors.As(err, &pe) {
+		return false // loading failed; we retry loading the next time we try
+	}
+	switch {
+	case errors.Is(err, kerr.InvalidProducerEpoch),
+		errors.Is(err, kerr.InvalidTxnState),
+		errors.Is(err, kerr.UnknownProducerID),
+		errors.Is(err, kerr.InvalidProducerIDMapping):
+		return true
+	}
+	return false
+}
+
 // If a transaction is begun too quickly after finishing an old transaction,
 // Kafka may still be finalizing its commit / abort and will return a
 // concurrent transactions error. We handle that by retrying for a bit.
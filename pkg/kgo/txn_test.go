@@ -6,9 +6,12 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"reflect"
 	"strconv"
 	"testing"
 	"time"
+
+	"github.com/twmb/franz-go/pkg/kerr"
 )
 
 // This test is identical to TestGroupETL but based around transactions.
@@ -261,3 +264,55 @@ func (c *testConsumer) transact(txnsBeforeQuit int) {
 		c.mu.Unlock()
 	}
 }
+
+func TestRecoverTransactions(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		enabled   bool
+		err       error
+		recovered bool
+	}{
+		{false, kerr.InvalidTxnState, false},
+		{true, kerr.InvalidTxnState, true},
+		{false, kerr.ProducerFenced, false},
+		{true, kerr.ProducerFenced, false}, // fenced by a newer producer: never recovered
+	} {
+		var recoveries []TransactionRecovery
+		opts := []Opt{SeedBrokers("localhost:1"), TransactionalID("txn")}
+		if test.enabled {
+			opts = append(opts, RecoverTransactions(func(r TransactionRecovery) { recoveries = append(recoveries, r) }))
+		}
+		cl, err := NewClient(opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer cl.Close()
+
+		cl.producer.id.Store(&producerID{id: 3, epoch: 7, err: test.err})
+		err = cl.BeginTransaction()
+		if !test.recovered {
+			if !errors.Is(err, test.err) {
+				t.Errorf("enabled %v: got err %v, exp %v", test.enabled, err, test.err)
+			}
+			if len(recoveries) != 0 {
+				t.Errorf("enabled %v: got unexpected recoveries %+v", test.enabled, recoveries)
+			}
+			if id := cl.producer.id.Load().(*producerID); id.id != 3 || id.epoch != 7 || !errors.Is(id.err, test.err) {
+				t.Errorf("enabled %v: got producer id %+v, exp 3/7 still failed", test.enabled, id)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("unexpected begin err: %v", err)
+		}
+
+		exp := []TransactionRecovery{{Err: test.err, ProducerID: 3, ProducerEpoch: 7, Reinitialized: true}}
+		if !reflect.DeepEqual(recoveries, exp) {
+			t.Errorf("got recoveries %+v, exp %+v", recoveries, exp)
+		}
+		if id := cl.producer.id.Load().(*producerID); id.id != -1 || id.epoch != -1 || !errors.Is(id.err, errReloadProducerID) {
+			t.Errorf("got producer id %+v, exp -1/-1 needing a reload", id)
+		}
+	}
+}
@@ -21,9 +21,24 @@
 //	struct := kmsg.NewFoo()
 //	struct.Field = "value I want to set"
 //
+// Every struct in this package implements json.Marshaler and
+// json.Unmarshaler so that requests and responses can be logged, diffed, and
+// replayed in a human readable form. Fields are encoded in order with their
+// Go names, and nullable fields are null if nil. Bytes (including raw record
+// batches, which are left encoded) are standard base64, UUIDs such as topic
+// IDs are unpadded URL safe base64 (the same as Kafka prints them), enums
+// are their names, and UnknownTags are only encoded if non-empty, as an
+// object of tag to base64 bytes.
+//
+// These methods are a breaking change from earlier releases, where
+// encoding/json used its defaults: UUIDs were arrays of 16 numbers, enums with
+// unknown values were "UNKNOWN", and UnknownTags was always {}. JSON stored or
+// compared from an earlier release must be re-encoded or updated.
+//
 // Most of this package is generated, but a few things are manual. What is
-// manual: all interfaces, the RequestFormatter, record / message / record
-// batch reading, and sticky member metadata serialization.
+// manual: all interfaces, the RequestFormatter and frame reading, record /
+// message / record batch reading, sticky member metadata serialization, and
+// JSON encoding.
 package kmsg
 
 import (
@@ -0,0 +1,149 @@
+package kmsg
+
+import (
+	"fmt"
+
+	"github.com/twmb/franz-go/pkg/kmsg/internal/kbin"
+)
+
+// RequestHeader is the header that precedes every request body on the wire.
+type RequestHeader struct {
+	// Key is the request key.
+	Key int16
+	// Version is the request version.
+	Version int16
+	// CorrelationID is the ID the client uses to match the response to
+	// this request.
+	CorrelationID int32
+	// ClientID is the client ID, which is nil if the client did not use
+	// one (or if this is a ControlledShutdown v0 request, which has no
+	// client ID).
+	ClientID *string
+	// UnknownTags are any tags in the header of flexible requests.
+	UnknownTags Tags
+}
+
+// ReadRequest decodes a request body, i.e. everything in a request after its
+// header, for the given key and version. Paired with ReadResponse and the
+// frame functions below, this is useful for debugging raw captured traffic:
+// every request and response in this package can also be marshaled to JSON.
+func ReadRequest(key, version int16, body []byte) (Request, error) {
+	r := RequestForKey(key)
+	if r == nil {
+		return nil, fmt.Errorf("unknown request key %d", key)
+	}
+	if err := checkVersion(r.MaxVersion(), key, version); err != nil {
+		return nil, err
+	}
+	r.SetVersion(version)
+	if err := r.ReadFrom(body); err != nil {
+		return nil, fmt.Errorf("unable to read %s v%d request: %w", NameForKey(key), version, err)
+	}
+	return r, nil
+}
+
+// ReadResponse decodes a response body, i.e. everything in a response after
+// its header, for the given key and version.
+func ReadResponse(key, version int16, body []byte) (Response, error) {
+	r := ResponseForKey(key)
+	if r == nil {
+		return nil, fmt.Errorf("unknown response key %d", key)
+	}
+	if err := checkVersion(r.MaxVersion(), key, version); err != nil {
+		return nil, err
+	}
+	r.SetVersion(version)
+	if err := r.ReadFrom(body); err != nil {
+		return nil, fmt.Errorf("unable to read %s v%d response: %w", NameForKey(key), version, err)
+	}
+	return r, nil
+}
+
+// ReadRequestFrame decodes a full request as written to the wire, including
+// its leading four byte size, and returns the request and its header. This is
+// the inverse of RequestFormatter.AppendRequest.
+func ReadRequestFrame(frame []byte) (Request, RequestHeader, error) {
+	var h RequestHeader
+	body, err := frameBody(frame)
+	if err != nil {
+		return nil, h, err
+	}
+	b := kbin.Reader{Src: body}
+	h.Key = b.Int16()
+	h.Version = b.Int16()
+	h.CorrelationID = b.Int32()
+	if err := b.Complete(); err != nil {
+		return nil, h, fmt.Errorf("unable to read request header: %w", err)
+	}
+	r := RequestForKey(h.Key)
+	if r == nil {
+		return nil, h, fmt.Errorf("unknown request key %d", h.Key)
+	}
+	r.SetVersion(h.Version)
+
+	// See AppendRequest: ControlledShutdown v0 has no client ID, and the
+	// client ID is never compact even in flexible versions.
+	if h.Key != 7 || h.Version != 0 {
+		h.ClientID = b.NullableString()
+		if r.IsFlexible() {
+			h.UnknownTags = internalReadTags(&b)
+		}
+		if err := b.Complete(); err != nil {
+			return nil, h, fmt.Errorf("unable to read request header: %w", err)
+		}
+	}
+
+	req, err := ReadRequest(h.Key, h.Version, b.Src)
+	return req, h, err
+}
+
+// ReadResponseFrame decodes a full response as read from the wire, including
+// its leading four byte size, and returns the response and its correlation
+// ID. Responses do not contain their key nor version, so these must be known
+// from the request with the same correlation ID.
+func ReadResponseFrame(key, version int16, frame []byte) (Response, int32, error) {
+	body, err := frameBody(frame)
+	if err != nil {
+		return nil, 0, err
+	}
+	r := ResponseForKey(key)
+	if r == nil {
+		return nil, 0, fmt.Errorf("unknown response key %d", key)
+	}
+	r.SetVersion(version)
+
+	b := kbin.Reader{Src: body}
+	corr := b.Int32()
+	// ApiVersions responses never have a flexible header, so that clients
+	// can always parse the response even if the broker does not support
+	// the version requested.
+	if r.IsFlexible() && key != 18 {
+		internalSkipTags(&b)
+	}
+	if err := b.Complete(); err != nil {
+		return nil, 0, fmt.Errorf("unable to read response header: %w", err)
+	}
+
+	resp, err := ReadResponse(key, version, b.Src)
+	return resp, corr, err
+}
+
+// frameBody strips and validates the leading size of a frame.
+func frameBody(frame []byte) ([]byte, error) {
+	b := kbin.Reader{Src: frame}
+	size := b.Int32()
+	if err := b.Complete(); err != nil {
+		return nil, fmt.Errorf("unable to read frame size: %w", err)
+	}
+	if size < 0 || int(size) != len(b.Src) {
+		return nil, fmt.Errorf("frame size %d does not match the %d bytes following it", size, len(b.Src))
+	}
+	return b.Src, nil
+}
+
+func checkVersion(max, key, version int16) error {
+	if version < 0 || version > max {
+		return fmt.Errorf("%s v%d is outside of the supported range of v0 to v%d", NameForKey(key), version, max)
+	}
+	return nil
+}
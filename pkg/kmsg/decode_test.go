@@ -0,0 +1,139 @@
+package kmsg
+
+import (
+	"encoding/binary"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// responseFrame returns resp as a broker would write it, with the given
+// correlation ID.
+func responseFrame(resp Response, corr int32) []byte {
+	body := binary.BigEndian.AppendUint32(nil, uint32(corr))
+	if resp.IsFlexible() && resp.Key() != 18 {
+		body = append(body, 0) // no header tags
+	}
+	body = resp.AppendTo(body)
+	return append(binary.BigEndian.AppendUint32(nil, uint32(len(body))), body...)
+}
+
+func TestReadRequestFrame(t *testing.T) {
+	topic := "t"
+	for _, version := range []int16{4, 9, 12} { // non-flexible and flexible
+		req := NewPtrMetadataRequest()
+		req.Version = version
+		rt := NewMetadataRequestTopic()
+		rt.Topic = &topic
+		req.Topics = append(req.Topics, rt)
+
+		frame := NewRequestFormatter(FormatterClientID("cid")).AppendRequest(nil, req, 7)
+		got, h, err := ReadRequestFrame(frame)
+		if err != nil {
+			t.Fatalf("v%d: unable to read: %v", version, err)
+		}
+		if h.Key != 3 || h.Version != version || h.CorrelationID != 7 || h.ClientID == nil || *h.ClientID != "cid" {
+			t.Errorf("v%d: unexpected header %#v", version, h)
+		}
+		if !reflect.DeepEqual(got, Request(req)) {
+			t.Errorf("v%d: got %#v, exp %#v", version, got, req)
+		}
+
+		// Every truncation of the frame must fail, rather than panic
+		// or decode a partial request.
+		for i := 0; i < len(frame); i++ {
+			if _, _, err := ReadRequestFrame(frame[:i]); err == nil {
+				t.Errorf("v%d: truncated frame of %d/%d bytes read without error", version, i, len(frame))
+			}
+		}
+	}
+
+	// ControlledShutdown v0 has no client ID.
+	cs := NewPtrControlledShutdownRequest()
+	cs.BrokerID = 3
+	body := []byte{0, 7, 0, 0, 0, 0, 0, 1} // key, version, correlation ID
+	body = cs.AppendTo(body)
+	got, h, err := ReadRequestFrame(append(binary.BigEndian.AppendUint32(nil, uint32(len(body))), body...))
+	if err != nil {
+		t.Fatalf("controlled shutdown: unable to read: %v", err)
+	}
+	if h.ClientID != nil || got.(*ControlledShutdownRequest).BrokerID != 3 {
+		t.Errorf("controlled shutdown: got client ID %v, request %#v", h.ClientID, got)
+	}
+}
+
+func TestReadResponseFrame(t *testing.T) {
+	for _, version := range []int16{4, 9, 12} {
+		resp := NewPtrMetadataResponse()
+		resp.Version = version
+		resp.ClusterID = nil
+		b := NewMetadataResponseBroker()
+		b.NodeID = 1
+		b.Host = "localhost"
+		b.Port = 9092
+		resp.Brokers = append(resp.Brokers, b)
+
+		frame := responseFrame(resp, 9)
+		got, corr, err := ReadResponseFrame(3, version, frame)
+		if err != nil {
+			t.Fatalf("v%d: unable to read: %v", version, err)
+		}
+		if corr != 9 || !reflect.DeepEqual(got, Response(resp)) {
+			t.Errorf("v%d: got correlation ID %d and %#v, exp 9 and %#v", version, corr, got, resp)
+		}
+		for i := 0; i < len(frame); i++ {
+			if _, _, err := ReadResponseFrame(3, version, frame[:i]); err == nil {
+				t.Errorf("v%d: truncated frame of %d/%d bytes read without error", version, i, len(frame))
+			}
+		}
+	}
+
+	// ApiVersions responses never have header tags, even when flexible.
+	av := NewPtrApiVersionsResponse()
+	av.Version = 3
+	av.ApiKeys = []ApiVersionsResponseApiKey{{ApiKey: 0, MaxVersion: 9}}
+	got, _, err := ReadResponseFrame(18, 3, responseFrame(av, 1))
+	if err != nil {
+		t.Fatalf("api versions: unable to read: %v", err)
+	}
+	if !reflect.DeepEqual(got, Response(av)) {
+		t.Errorf("api versions: got %#v, exp %#v", got, av)
+	}
+}
+
+func TestReadInvalid(t *testing.T) {
+	valid := NewRequestFormatter().AppendRequest(nil, NewPtrMetadataRequest(), 1)
+	unknownKey := append([]byte(nil), valid...)
+	binary.BigEndian.PutUint16(unknownKey[4:], 10000)
+	badVersion := append([]byte(nil), valid...)
+	binary.BigEndian.PutUint16(badVersion[6:], 10000)
+	trailing := append(append([]byte(nil), valid...), 0)
+	longer := append([]byte(nil), valid...)
+	binary.BigEndian.PutUint32(longer, uint32(len(valid)))
+
+	for _, test := range []struct {
+		name   string
+		read   func() error
+		errHas string
+	}{
+		{"request unknown key", func() error { _, err := ReadRequest(10000, 0, nil); return err }, "unknown request key 10000"},
+		{"response unknown key", func() error { _, err := ReadResponse(-1, 0, nil); return err }, "unknown response key -1"},
+		{"request negative version", func() error { _, err := ReadRequest(3, -1, nil); return err }, "outside of the supported range"},
+		{"response version too high", func() error { _, err := ReadResponse(3, 10000, nil); return err }, "outside of the supported range"},
+		{"request truncated body", func() error { _, err := ReadRequest(3, 4, []byte{0, 0}); return err }, "unable to read Metadata v4 request"},
+		{"response truncated body", func() error { _, err := ReadResponse(3, 4, []byte{0}); return err }, "unable to read Metadata v4 response"},
+		{"frame unknown key", func() error { _, _, err := ReadRequestFrame(unknownKey); return err }, "unknown request key 10000"},
+		{"frame bad version", func() error { _, _, err := ReadRequestFrame(badVersion); return err }, "outside of the supported range"},
+		{"frame trailing bytes", func() error { _, _, err := ReadRequestFrame(trailing); return err }, "does not match"},
+		{"frame size too large", func() error { _, _, err := ReadRequestFrame(longer); return err }, "does not match"},
+		{"frame without size", func() error { _, _, err := ReadRequestFrame([]byte{0, 0}); return err }, "unable to read frame size"},
+		{"response frame unknown key", func() error { _, _, err := ReadResponseFrame(10000, 0, []byte{0, 0, 0, 4, 0, 0, 0, 1}); return err }, "unknown response key"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.read()
+			if err == nil || !strings.Contains(err.Error(), test.errHas) {
+				t.Errorf("got err %v, exp one containing %q", err, test.errHas)
+			}
+		})
+	}
+}
@@ -13,7 +13,7 @@ import (
 
 // MaxKey is the maximum key used for any messages in this package.
 // Note that this value will change as Kafka adds more messages.
-const MaxKey = 69
+const MaxKey = 82
 
 type AssignmentTopicPartition struct {
 	TopicID [16]byte
@@ -39,6 +39,14 @@ func NewAssignmentTopicPartition() AssignmentTopicPartition {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v AssignmentTopicPartition) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *AssignmentTopicPartition) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // MessageV0 is the message format Kafka used prior to 0.10.
 //
 // To produce or fetch messages, Kafka would write many messages contiguously
@@ -169,6 +177,14 @@ func NewMessageV0() MessageV0 {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v MessageV0) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *MessageV0) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // MessageV1 is the message format Kafka used prior to 0.11.
 //
 // To produce or fetch messages, Kafka would write many messages contiguously
@@ -319,6 +335,14 @@ func NewMessageV1() MessageV1 {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v MessageV1) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *MessageV1) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // Header is user provided metadata for a record. Kafka does not look at
 // headers at all; they are solely for producers and consumers.
 type Header struct {
@@ -380,6 +404,14 @@ func NewHeader() Header {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v Header) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *Header) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // RecordBatch is a Kafka concept that groups many individual records together
 // in a more optimized format.
 type RecordBatch struct {
@@ -628,6 +660,14 @@ func NewRecordBatch() RecordBatch {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v RecordBatch) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *RecordBatch) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // OffsetCommitKey is the key for the Kafka internal __consumer_offsets topic
 // if the key starts with an int16 with a value of 0 or 1.
 //
@@ -722,6 +762,14 @@ func NewOffsetCommitKey() OffsetCommitKey {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v OffsetCommitKey) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *OffsetCommitKey) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // OffsetCommitValue is the value for the Kafka internal __consumer_offsets
 // topic if the key is of OffsetCommitKey type.
 //
@@ -841,6 +889,14 @@ func NewOffsetCommitValue() OffsetCommitValue {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v OffsetCommitValue) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *OffsetCommitValue) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // GroupMetadataKey is the key for the Kafka internal __consumer_offsets topic
 // if the key starts with an int16 with a value of 2.
 //
@@ -908,6 +964,14 @@ func NewGroupMetadataKey() GroupMetadataKey {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v GroupMetadataKey) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *GroupMetadataKey) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type GroupMetadataValueMember struct {
 	// MemberID is a group member.
 	MemberID string
@@ -953,6 +1017,14 @@ func NewGroupMetadataValueMember() GroupMetadataValueMember {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v GroupMetadataValueMember) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *GroupMetadataValueMember) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // GroupMetadataValue is the value for the Kafka internal __consumer_offsets
 // topic if the key is of GroupMetadataKey type.
 //
@@ -1334,6 +1406,14 @@ func NewGroupMetadataValue() GroupMetadataValue {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v GroupMetadataValue) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *GroupMetadataValue) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // TxnMetadataKey is the key for the Kafka internal __transaction_state topic
 // if the key starts with an int16 with a value of 0.
 type TxnMetadataKey struct {
@@ -1398,6 +1478,14 @@ func NewTxnMetadataKey() TxnMetadataKey {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v TxnMetadataKey) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *TxnMetadataKey) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type TxnMetadataValueTopic struct {
 	// Topic is a topic involved in this transaction.
 	Topic string
@@ -1419,6 +1507,14 @@ func NewTxnMetadataValueTopic() TxnMetadataValueTopic {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v TxnMetadataValueTopic) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *TxnMetadataValueTopic) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // TxnMetadataValue is the value for the Kafka internal __transaction_state
 // topic if the key is of TxnMetadataKey type.
 type TxnMetadataValue struct {
@@ -1614,6 +1710,14 @@ func NewTxnMetadataValue() TxnMetadataValue {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v TxnMetadataValue) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *TxnMetadataValue) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type StickyMemberMetadataCurrentAssignment struct {
 	// Topic is a topic the group member is currently assigned.
 	Topic string
@@ -1636,6 +1740,16 @@ func NewStickyMemberMetadataCurrentAssignment() StickyMemberMetadataCurrentAssig
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v StickyMemberMetadataCurrentAssignment) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *StickyMemberMetadataCurrentAssignment) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 // StickyMemberMetadata is is what is encoded in UserData for
 // ConsumerMemberMetadata in group join requests with the sticky partitioning
 // strategy.
@@ -1671,6 +1785,14 @@ func NewStickyMemberMetadata() StickyMemberMetadata {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v StickyMemberMetadata) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *StickyMemberMetadata) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type ConsumerMemberMetadataOwnedPartition struct {
 	Topic string
 
@@ -1690,6 +1812,16 @@ func NewConsumerMemberMetadataOwnedPartition() ConsumerMemberMetadataOwnedPartit
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ConsumerMemberMetadataOwnedPartition) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ConsumerMemberMetadataOwnedPartition) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 // ConsumerMemberMetadata is the metadata that is usually sent with a join group
 // request with the "consumer" protocol (normal, non-connect consumers).
 type ConsumerMemberMetadata struct {
@@ -1887,6 +2019,14 @@ func NewConsumerMemberMetadata() ConsumerMemberMetadata {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ConsumerMemberMetadata) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ConsumerMemberMetadata) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type ConsumerMemberAssignmentTopic struct {
 	// Topic is a topic in the assignment.
 	Topic string
@@ -1908,6 +2048,14 @@ func NewConsumerMemberAssignmentTopic() ConsumerMemberAssignmentTopic {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ConsumerMemberAssignmentTopic) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ConsumerMemberAssignmentTopic) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // ConsumerMemberAssignment is the assignment data that is usually sent with a
 // sync group request with the "consumer" protocol (normal, non-connect
 // consumers).
@@ -2038,6 +2186,14 @@ func NewConsumerMemberAssignment() ConsumerMemberAssignment {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ConsumerMemberAssignment) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ConsumerMemberAssignment) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // ConnectMemberMetadata is the metadata used in a join group request with the
 // "connect" protocol. v1 introduced incremental cooperative rebalancing (akin
 // to cooperative-sticky) per KIP-415.
@@ -2124,6 +2280,14 @@ func NewConnectMemberMetadata() ConnectMemberMetadata {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ConnectMemberMetadata) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ConnectMemberMetadata) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type ConnectMemberAssignmentAssignment struct {
 	Connector string
 
@@ -2143,6 +2307,14 @@ func NewConnectMemberAssignmentAssignment() ConnectMemberAssignmentAssignment {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ConnectMemberAssignmentAssignment) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ConnectMemberAssignmentAssignment) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type ConnectMemberAssignmentRevoked struct {
 	Connector string
 
@@ -2162,6 +2334,14 @@ func NewConnectMemberAssignmentRevoked() ConnectMemberAssignmentRevoked {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ConnectMemberAssignmentRevoked) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ConnectMemberAssignmentRevoked) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // ConnectMemberAssignment is the assignment that is used in a sync group
 // request with the "connect" protocol. See ConnectMemberMetadata for links to
 // the Kafka code where these fields are defined.
@@ -2408,6 +2588,14 @@ func NewConnectMemberAssignment() ConnectMemberAssignment {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ConnectMemberAssignment) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ConnectMemberAssignment) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // DefaultPrincipalData is the encoded principal data. This is used in an
 // envelope request from broker to broker.
 type DefaultPrincipalData struct {
@@ -2537,6 +2725,14 @@ func NewDefaultPrincipalData() DefaultPrincipalData {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DefaultPrincipalData) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DefaultPrincipalData) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // ControlRecordKey is the key in a control record.
 type ControlRecordKey struct {
 	Version int16
@@ -2601,6 +2797,14 @@ func NewControlRecordKey() ControlRecordKey {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ControlRecordKey) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ControlRecordKey) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // EndTxnMarker is the value for a control record when the key is type 0 or 1.
 type EndTxnMarker struct {
 	Version int16
@@ -2657,6 +2861,14 @@ func NewEndTxnMarker() EndTxnMarker {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v EndTxnMarker) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *EndTxnMarker) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type LeaderChangeMessageVoter struct {
 	VoterID int32
 
@@ -2677,6 +2889,14 @@ func NewLeaderChangeMessageVoter() LeaderChangeMessageVoter {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v LeaderChangeMessageVoter) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *LeaderChangeMessageVoter) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // LeaderChangeMessage is the value for a control record when the key is type 3.
 type LeaderChangeMessage struct {
 	Version int16
@@ -2855,6 +3075,14 @@ func NewLeaderChangeMessage() LeaderChangeMessage {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v LeaderChangeMessage) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *LeaderChangeMessage) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type ProduceRequestTopicPartition struct {
 	// Partition is a partition to send a record batch to.
 	Partition int32
@@ -2883,6 +3111,14 @@ func NewProduceRequestTopicPartition() ProduceRequestTopicPartition {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ProduceRequestTopicPartition) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ProduceRequestTopicPartition) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type ProduceRequestTopic struct {
 	// Topic is a topic to send record batches to.
 	Topic string
@@ -2907,6 +3143,14 @@ func NewProduceRequestTopic() ProduceRequestTopic {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ProduceRequestTopic) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ProduceRequestTopic) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // ProduceRequest issues records to be created to Kafka.
 //
 // Kafka 0.10.0 (v2) changed Records from MessageSet v0 to MessageSet v1.
@@ -3200,6 +3444,14 @@ func NewProduceRequest() ProduceRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ProduceRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ProduceRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type ProduceResponseTopicPartitionErrorRecord struct {
 	// RelativeOffset is the offset of the record that caused problems.
 	RelativeOffset int32
@@ -3224,6 +3476,18 @@ func NewProduceResponseTopicPartitionErrorRecord() ProduceResponseTopicPartition
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ProduceResponseTopicPartitionErrorRecord) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ProduceResponseTopicPartitionErrorRecord) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type ProduceResponseTopicPartitionCurrentLeader struct {
 	// The ID of the current leader, or -1 if unknown.
 	//
@@ -3254,6 +3518,18 @@ func NewProduceResponseTopicPartitionCurrentLeader() ProduceResponseTopicPartiti
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ProduceResponseTopicPartitionCurrentLeader) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ProduceResponseTopicPartitionCurrentLeader) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type ProduceResponseTopicPartition struct {
 	// Partition is the partition this response pertains to.
 	Partition int32
@@ -3390,6 +3666,14 @@ func NewProduceResponseTopicPartition() ProduceResponseTopicPartition {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ProduceResponseTopicPartition) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ProduceResponseTopicPartition) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type ProduceResponseTopic struct {
 	// Topic is the topic this response pertains to.
 	Topic string
@@ -3415,6 +3699,14 @@ func NewProduceResponseTopic() ProduceResponseTopic {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ProduceResponseTopic) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ProduceResponseTopic) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type ProduceResponseBroker struct {
 	// NodeID is the node ID of a Kafka broker.
 	NodeID int32
@@ -3445,6 +3737,14 @@ func NewProduceResponseBroker() ProduceResponseBroker {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ProduceResponseBroker) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ProduceResponseBroker) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // ProduceResponse is returned from a ProduceRequest.
 type ProduceResponse struct {
 	// Version is the version of this message used with a Kafka broker.
@@ -3986,6 +4286,14 @@ func NewProduceResponse() ProduceResponse {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ProduceResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ProduceResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type FetchRequestReplicaState struct {
 	// The replica ID of the follower, or -1 if this request is from a consumer.
 	//
@@ -4016,6 +4324,14 @@ func NewFetchRequestReplicaState() FetchRequestReplicaState {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v FetchRequestReplicaState) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *FetchRequestReplicaState) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type FetchRequestTopicPartition struct {
 	// Partition is a partition in a topic to try to fetch records for.
 	Partition int32
@@ -4070,6 +4386,14 @@ func NewFetchRequestTopicPartition() FetchRequestTopicPartition {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v FetchRequestTopicPartition) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *FetchRequestTopicPartition) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type FetchRequestTopic struct {
 	// Topic is a topic to try to fetch records for.
 	Topic string // v0-v12
@@ -4097,6 +4421,14 @@ func NewFetchRequestTopic() FetchRequestTopic {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v FetchRequestTopic) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *FetchRequestTopic) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type FetchRequestForgottenTopic struct {
 	// Topic is a topic to remove from being tracked (with the partitions below).
 	Topic string // v7-v12
@@ -4125,6 +4457,14 @@ func NewFetchRequestForgottenTopic() FetchRequestForgottenTopic {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v FetchRequestForgottenTopic) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *FetchRequestForgottenTopic) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // FetchRequest is a long-poll request of records from Kafka.
 //
 // Kafka 0.11.0.0 released v4 and changed the returned RecordBatches to contain
@@ -4756,6 +5096,14 @@ func NewFetchRequest() FetchRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v FetchRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *FetchRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type FetchResponseTopicPartitionDivergingEpoch struct {
 	// This field has a default of -1.
 	Epoch int32
@@ -4782,6 +5130,18 @@ func NewFetchResponseTopicPartitionDivergingEpoch() FetchResponseTopicPartitionD
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v FetchResponseTopicPartitionDivergingEpoch) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *FetchResponseTopicPartitionDivergingEpoch) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type FetchResponseTopicPartitionCurrentLeader struct {
 	// The ID of the current leader, or -1 if unknown.
 	//
@@ -4812,6 +5172,18 @@ func NewFetchResponseTopicPartitionCurrentLeader() FetchResponseTopicPartitionCu
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v FetchResponseTopicPartitionCurrentLeader) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *FetchResponseTopicPartitionCurrentLeader) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type FetchResponseTopicPartitionSnapshotID struct {
 	// This field has a default of -1.
 	EndOffset int64
@@ -4838,6 +5210,16 @@ func NewFetchResponseTopicPartitionSnapshotID() FetchResponseTopicPartitionSnaps
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v FetchResponseTopicPartitionSnapshotID) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *FetchResponseTopicPartitionSnapshotID) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type FetchResponseTopicPartitionAbortedTransaction struct {
 	// ProducerID is the producer ID that caused this aborted transaction.
 	ProducerID int64
@@ -4862,6 +5244,18 @@ func NewFetchResponseTopicPartitionAbortedTransaction() FetchResponseTopicPartit
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v FetchResponseTopicPartitionAbortedTransaction) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *FetchResponseTopicPartitionAbortedTransaction) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type FetchResponseTopicPartition struct {
 	// Partition is a partition in a topic that records may have been
 	// received for.
@@ -5006,6 +5400,14 @@ func NewFetchResponseTopicPartition() FetchResponseTopicPartition {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v FetchResponseTopicPartition) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *FetchResponseTopicPartition) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type FetchResponseTopic struct {
 	// Topic is a topic that records may have been received for.
 	Topic string // v0-v12
@@ -5034,6 +5436,14 @@ func NewFetchResponseTopic() FetchResponseTopic {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v FetchResponseTopic) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *FetchResponseTopic) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type FetchResponseBroker struct {
 	// NodeID is the node ID of a Kafka broker.
 	NodeID int32
@@ -5064,6 +5474,14 @@ func NewFetchResponseBroker() FetchResponseBroker {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v FetchResponseBroker) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *FetchResponseBroker) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // FetchResponse is returned from a FetchRequest.
 type FetchResponse struct {
 	// Version is the version of this message used with a Kafka broker.
@@ -5733,6 +6151,14 @@ func NewFetchResponse() FetchResponse {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v FetchResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *FetchResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type ListOffsetsRequestTopicPartition struct {
 	// Partition is a partition of a topic to get offsets for.
 	Partition int32
@@ -5798,6 +6224,14 @@ func NewListOffsetsRequestTopicPartition() ListOffsetsRequestTopicPartition {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ListOffsetsRequestTopicPartition) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ListOffsetsRequestTopicPartition) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type ListOffsetsRequestTopic struct {
 	// Topic is a topic to get offsets for.
 	Topic string
@@ -5822,6 +6256,14 @@ func NewListOffsetsRequestTopic() ListOffsetsRequestTopic {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ListOffsetsRequestTopic) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ListOffsetsRequestTopic) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // ListOffsetsRequest requests partition offsets from Kafka for use in
 // consuming records.
 //
@@ -6093,6 +6535,14 @@ func NewListOffsetsRequest() ListOffsetsRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ListOffsetsRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ListOffsetsRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type ListOffsetsResponseTopicPartition struct {
 	// Partition is the partition this array slot is for.
 	Partition int32
@@ -6179,6 +6629,14 @@ func NewListOffsetsResponseTopicPartition() ListOffsetsResponseTopicPartition {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ListOffsetsResponseTopicPartition) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ListOffsetsResponseTopicPartition) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type ListOffsetsResponseTopic struct {
 	// Topic is the topic this array slot is for.
 	Topic string
@@ -6204,6 +6662,14 @@ func NewListOffsetsResponseTopic() ListOffsetsResponseTopic {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ListOffsetsResponseTopic) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ListOffsetsResponseTopic) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // ListOffsetsResponse is returned from a ListOffsetsRequest.
 type ListOffsetsResponse struct {
 	// Version is the version of this message used with a Kafka broker.
@@ -6481,6 +6947,14 @@ func NewListOffsetsResponse() ListOffsetsResponse {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ListOffsetsResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ListOffsetsResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type MetadataRequestTopic struct {
 	// The topic ID. Only one of either topic ID or topic name should be used.
 	// If using the topic name, this should just be the default empty value.
@@ -6508,6 +6982,14 @@ func NewMetadataRequestTopic() MetadataRequestTopic {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v MetadataRequestTopic) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *MetadataRequestTopic) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // MetadataRequest requests metadata from Kafka.
 type MetadataRequest struct {
 	// Version is the version of this message used with a Kafka broker.
@@ -6758,6 +7240,14 @@ func NewMetadataRequest() MetadataRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v MetadataRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *MetadataRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type MetadataResponseBroker struct {
 	// NodeID is the node ID of a Kafka broker.
 	NodeID int32
@@ -6788,6 +7278,14 @@ func NewMetadataResponseBroker() MetadataResponseBroker {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v MetadataResponseBroker) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *MetadataResponseBroker) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type MetadataResponseTopicPartition struct {
 	// ErrorCode is any error for a partition in topic metadata.
 	//
@@ -6846,6 +7344,14 @@ func NewMetadataResponseTopicPartition() MetadataResponseTopicPartition {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v MetadataResponseTopicPartition) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *MetadataResponseTopicPartition) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type MetadataResponseTopic struct {
 	// ErrorCode is any error for a topic in a metadata request.
 	//
@@ -6900,6 +7406,14 @@ func NewMetadataResponseTopic() MetadataResponseTopic {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v MetadataResponseTopic) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *MetadataResponseTopic) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // MetadataResponse is returned from a MetdataRequest.
 type MetadataResponse struct {
 	// Version is the version of this message used with a Kafka broker.
@@ -7473,6 +7987,14 @@ func NewMetadataResponse() MetadataResponse {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v MetadataResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *MetadataResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // LeaderAndISRRequestTopicPartition is a common struct that is used across
 // different versions of LeaderAndISRRequest.
 type LeaderAndISRRequestTopicPartition struct {
@@ -7517,6 +8039,14 @@ func NewLeaderAndISRRequestTopicPartition() LeaderAndISRRequestTopicPartition {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v LeaderAndISRRequestTopicPartition) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *LeaderAndISRRequestTopicPartition) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // LeaderAndISRResponseTopicPartition is a common struct that is used across
 // different versions of LeaderAndISRResponse.
 type LeaderAndISRResponseTopicPartition struct {
@@ -7543,6 +8073,16 @@ func NewLeaderAndISRResponseTopicPartition() LeaderAndISRResponseTopicPartition
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v LeaderAndISRResponseTopicPartition) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *LeaderAndISRResponseTopicPartition) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type LeaderAndISRRequestTopicState struct {
 	Topic string
 
@@ -7567,6 +8107,14 @@ func NewLeaderAndISRRequestTopicState() LeaderAndISRRequestTopicState {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v LeaderAndISRRequestTopicState) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *LeaderAndISRRequestTopicState) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type LeaderAndISRRequestLiveLeader struct {
 	BrokerID int32
 
@@ -7591,6 +8139,14 @@ func NewLeaderAndISRRequestLiveLeader() LeaderAndISRRequestLiveLeader {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v LeaderAndISRRequestLiveLeader) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *LeaderAndISRRequestLiveLeader) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // LeaderAndISRRequest is an advanced request that controller brokers use
 // to broadcast state to other brokers. Manually using this request is a
 // great way to break your cluster.
@@ -8428,6 +8984,14 @@ func NewLeaderAndISRRequest() LeaderAndISRRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v LeaderAndISRRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *LeaderAndISRRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type LeaderAndISRResponseTopic struct {
 	TopicID [16]byte
 
@@ -8450,6 +9014,14 @@ func NewLeaderAndISRResponseTopic() LeaderAndISRResponseTopic {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v LeaderAndISRResponseTopic) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *LeaderAndISRResponseTopic) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // LeaderAndISRResponse is returned from a LeaderAndISRRequest.
 type LeaderAndISRResponse struct {
 	// Version is the version of this message used with a Kafka broker.
@@ -8751,6 +9323,14 @@ func NewLeaderAndISRResponse() LeaderAndISRResponse {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v LeaderAndISRResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *LeaderAndISRResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type StopReplicaRequestTopicPartitionState struct {
 	Partition int32
 
@@ -8777,6 +9357,16 @@ func NewStopReplicaRequestTopicPartitionState() StopReplicaRequestTopicPartition
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v StopReplicaRequestTopicPartitionState) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *StopReplicaRequestTopicPartitionState) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type StopReplicaRequestTopic struct {
 	Topic string
 
@@ -8803,6 +9393,14 @@ func NewStopReplicaRequestTopic() StopReplicaRequestTopic {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v StopReplicaRequestTopic) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *StopReplicaRequestTopic) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // StopReplicaRequest is an advanced request that brokers use to stop replicas.
 //
 // As this is an advanced request and there is little reason to issue it as a
@@ -9128,6 +9726,14 @@ func NewStopReplicaRequest() StopReplicaRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v StopReplicaRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *StopReplicaRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type StopReplicaResponsePartition struct {
 	Topic string
 
@@ -9152,6 +9758,14 @@ func NewStopReplicaResponsePartition() StopReplicaResponsePartition {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v StopReplicaResponsePartition) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *StopReplicaResponsePartition) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // StopReplicasResponse is returned from a StopReplicasRequest.
 type StopReplicaResponse struct {
 	// Version is the version of this message used with a Kafka broker.
@@ -9319,6 +9933,14 @@ func NewStopReplicaResponse() StopReplicaResponse {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v StopReplicaResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *StopReplicaResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type UpdateMetadataRequestTopicPartition struct {
 	Topic string // v0-v4
 
@@ -9355,6 +9977,16 @@ func NewUpdateMetadataRequestTopicPartition() UpdateMetadataRequestTopicPartitio
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v UpdateMetadataRequestTopicPartition) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *UpdateMetadataRequestTopicPartition) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type UpdateMetadataRequestTopicState struct {
 	Topic string
 
@@ -9379,6 +10011,14 @@ func NewUpdateMetadataRequestTopicState() UpdateMetadataRequestTopicState {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v UpdateMetadataRequestTopicState) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *UpdateMetadataRequestTopicState) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type UpdateMetadataRequestLiveBrokerEndpoint struct {
 	Port int32
 
@@ -9405,6 +10045,18 @@ func NewUpdateMetadataRequestLiveBrokerEndpoint() UpdateMetadataRequestLiveBroke
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v UpdateMetadataRequestLiveBrokerEndpoint) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *UpdateMetadataRequestLiveBrokerEndpoint) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type UpdateMetadataRequestLiveBroker struct {
 	ID int32
 
@@ -9433,6 +10085,14 @@ func NewUpdateMetadataRequestLiveBroker() UpdateMetadataRequestLiveBroker {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v UpdateMetadataRequestLiveBroker) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *UpdateMetadataRequestLiveBroker) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // UpdateMetadataRequest is an advanced request that brokers use to
 // issue metadata updates to each other.
 //
@@ -10291,6 +10951,14 @@ func NewUpdateMetadataRequest() UpdateMetadataRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v UpdateMetadataRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *UpdateMetadataRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // UpdateMetadataResponses is returned from an UpdateMetadataRequest.
 type UpdateMetadataResponse struct {
 	// Version is the version of this message used with a Kafka broker.
@@ -10374,6 +11042,14 @@ func NewUpdateMetadataResponse() UpdateMetadataResponse {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v UpdateMetadataResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *UpdateMetadataResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // ControlledShutdownRequest is an advanced request that can be used to
 // sthudown a broker in a controlled manner.
 //
@@ -10490,6 +11166,14 @@ func NewControlledShutdownRequest() ControlledShutdownRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ControlledShutdownRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ControlledShutdownRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type ControlledShutdownResponsePartitionsRemaining struct {
 	Topic string
 
@@ -10512,6 +11196,18 @@ func NewControlledShutdownResponsePartitionsRemaining() ControlledShutdownRespon
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ControlledShutdownResponsePartitionsRemaining) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ControlledShutdownResponsePartitionsRemaining) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 // ControlledShutdownResponse is returned from a ControlledShutdownRequest.
 type ControlledShutdownResponse struct {
 	// Version is the version of this message used with a Kafka broker.
@@ -10672,6 +11368,14 @@ func NewControlledShutdownResponse() ControlledShutdownResponse {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ControlledShutdownResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ControlledShutdownResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type OffsetCommitRequestTopicPartition struct {
 	// Partition if a partition to commit offsets for.
 	Partition int32
@@ -10719,6 +11423,14 @@ func NewOffsetCommitRequestTopicPartition() OffsetCommitRequestTopicPartition {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v OffsetCommitRequestTopicPartition) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *OffsetCommitRequestTopicPartition) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type OffsetCommitRequestTopic struct {
 	// Topic is a topic to commit offsets for.
 	Topic string
@@ -10743,6 +11455,14 @@ func NewOffsetCommitRequestTopic() OffsetCommitRequestTopic {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v OffsetCommitRequestTopic) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *OffsetCommitRequestTopic) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // OffsetCommitRequest commits offsets for consumed topics / partitions in
 // a group.
 type OffsetCommitRequest struct {
@@ -11123,6 +11843,14 @@ func NewOffsetCommitRequest() OffsetCommitRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v OffsetCommitRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *OffsetCommitRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type OffsetCommitResponseTopicPartition struct {
 	// Partition is the partition in a topic this array slot corresponds to.
 	Partition int32
@@ -11179,6 +11907,16 @@ func NewOffsetCommitResponseTopicPartition() OffsetCommitResponseTopicPartition
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v OffsetCommitResponseTopicPartition) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *OffsetCommitResponseTopicPartition) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type OffsetCommitResponseTopic struct {
 	// Topic is the topic this offset commit response corresponds to.
 	Topic string
@@ -11204,6 +11942,14 @@ func NewOffsetCommitResponseTopic() OffsetCommitResponseTopic {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v OffsetCommitResponseTopic) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *OffsetCommitResponseTopic) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // OffsetCommitResponse is returned from an OffsetCommitRequest.
 type OffsetCommitResponse struct {
 	// Version is the version of this message used with a Kafka broker.
@@ -11421,6 +12167,14 @@ func NewOffsetCommitResponse() OffsetCommitResponse {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v OffsetCommitResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *OffsetCommitResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type OffsetFetchRequestTopic struct {
 	// Topic is a topic to fetch offsets for.
 	Topic string
@@ -11445,6 +12199,14 @@ func NewOffsetFetchRequestTopic() OffsetFetchRequestTopic {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v OffsetFetchRequestTopic) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *OffsetFetchRequestTopic) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type OffsetFetchRequestGroupTopic struct {
 	Topic string
 
@@ -11467,6 +12229,14 @@ func NewOffsetFetchRequestGroupTopic() OffsetFetchRequestGroupTopic {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v OffsetFetchRequestGroupTopic) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *OffsetFetchRequestGroupTopic) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type OffsetFetchRequestGroup struct {
 	Group string
 
@@ -11498,6 +12268,14 @@ func NewOffsetFetchRequestGroup() OffsetFetchRequestGroup {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v OffsetFetchRequestGroup) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *OffsetFetchRequestGroup) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // OffsetFetchRequest requests the most recent committed offsets for topic
 // partitions in a group.
 type OffsetFetchRequest struct {
@@ -11955,6 +12733,14 @@ func NewOffsetFetchRequest() OffsetFetchRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v OffsetFetchRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *OffsetFetchRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type OffsetFetchResponseTopicPartition struct {
 	// Partition is the partition in a topic this array slot corresponds to.
 	Partition int32
@@ -12015,6 +12801,14 @@ func NewOffsetFetchResponseTopicPartition() OffsetFetchResponseTopicPartition {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v OffsetFetchResponseTopicPartition) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *OffsetFetchResponseTopicPartition) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type OffsetFetchResponseTopic struct {
 	// Topic is the topic this offset fetch response corresponds to.
 	Topic string
@@ -12040,6 +12834,14 @@ func NewOffsetFetchResponseTopic() OffsetFetchResponseTopic {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v OffsetFetchResponseTopic) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *OffsetFetchResponseTopic) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type OffsetFetchResponseGroupTopicPartition struct {
 	Partition int32
 
@@ -12070,6 +12872,16 @@ func NewOffsetFetchResponseGroupTopicPartition() OffsetFetchResponseGroupTopicPa
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v OffsetFetchResponseGroupTopicPartition) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *OffsetFetchResponseGroupTopicPartition) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type OffsetFetchResponseGroupTopic struct {
 	Topic string
 
@@ -12092,6 +12904,14 @@ func NewOffsetFetchResponseGroupTopic() OffsetFetchResponseGroupTopic {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v OffsetFetchResponseGroupTopic) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *OffsetFetchResponseGroupTopic) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type OffsetFetchResponseGroup struct {
 	Group string
 
@@ -12116,6 +12936,14 @@ func NewOffsetFetchResponseGroup() OffsetFetchResponseGroup {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v OffsetFetchResponseGroup) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *OffsetFetchResponseGroup) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // OffsetFetchResponse is returned from an OffsetFetchRequest.
 type OffsetFetchResponse struct {
 	// Version is the version of this message used with a Kafka broker.
@@ -12632,6 +13460,14 @@ func NewOffsetFetchResponse() OffsetFetchResponse {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v OffsetFetchResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *OffsetFetchResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // FindCoordinatorRequest requests the coordinator for a group or transaction.
 //
 // This coordinator is different from the broker leader coordinator. This
@@ -12817,6 +13653,14 @@ func NewFindCoordinatorRequest() FindCoordinatorRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v FindCoordinatorRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *FindCoordinatorRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type FindCoordinatorResponseCoordinator struct {
 	Key string
 
@@ -12847,6 +13691,16 @@ func NewFindCoordinatorResponseCoordinator() FindCoordinatorResponseCoordinator
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v FindCoordinatorResponseCoordinator) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *FindCoordinatorResponseCoordinator) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 // FindCoordinatorResponse is returned from a FindCoordinatorRequest.
 type FindCoordinatorResponse struct {
 	// Version is the version of this message used with a Kafka broker.
@@ -13189,6 +14043,14 @@ func NewFindCoordinatorResponse() FindCoordinatorResponse {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v FindCoordinatorResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *FindCoordinatorResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type JoinGroupRequestProtocol struct {
 	// Name is a name of a protocol. This is arbitrary, but is used
 	// in the official client to agree on a partition balancing strategy.
@@ -13225,6 +14087,14 @@ func NewJoinGroupRequestProtocol() JoinGroupRequestProtocol {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v JoinGroupRequestProtocol) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *JoinGroupRequestProtocol) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // JoinGroupRequest issues a request to join a Kafka group. This will create a
 // group if one does not exist. If joining an existing group, this may trigger
 // a group rebalance.
@@ -13598,6 +14468,14 @@ func NewJoinGroupRequest() JoinGroupRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v JoinGroupRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *JoinGroupRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type JoinGroupResponseMember struct {
 	// MemberID is a member in this group.
 	MemberID string
@@ -13626,6 +14504,14 @@ func NewJoinGroupResponseMember() JoinGroupResponseMember {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v JoinGroupResponseMember) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *JoinGroupResponseMember) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // JoinGroupResponse is returned from a JoinGroupRequest.
 type JoinGroupResponse struct {
 	// Version is the version of this message used with a Kafka broker.
@@ -14050,6 +14936,14 @@ func NewJoinGroupResponse() JoinGroupResponse {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v JoinGroupResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *JoinGroupResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // HeartbeatRequest issues a heartbeat for a member in a group, ensuring that
 // Kafka does not expire the member from the group.
 type HeartbeatRequest struct {
@@ -14231,6 +15125,14 @@ func NewHeartbeatRequest() HeartbeatRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v HeartbeatRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *HeartbeatRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // HeartbeatResponse is returned from a HeartbeatRequest.
 type HeartbeatResponse struct {
 	// Version is the version of this message used with a Kafka broker.
@@ -14349,6 +15251,14 @@ func NewHeartbeatResponse() HeartbeatResponse {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v HeartbeatResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *HeartbeatResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type LeaveGroupRequestMember struct {
 	MemberID string
 
@@ -14375,6 +15285,14 @@ func NewLeaveGroupRequestMember() LeaveGroupRequestMember {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v LeaveGroupRequestMember) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *LeaveGroupRequestMember) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // LeaveGroupRequest issues a request for a group member to leave the group,
 // triggering a group rebalance.
 //
@@ -14640,6 +15558,14 @@ func NewLeaveGroupRequest() LeaveGroupRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v LeaveGroupRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *LeaveGroupRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type LeaveGroupResponseMember struct {
 	MemberID string
 
@@ -14665,6 +15591,14 @@ func NewLeaveGroupResponseMember() LeaveGroupResponseMember {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v LeaveGroupResponseMember) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *LeaveGroupResponseMember) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // LeaveGroupResponse is returned from a LeaveGroupRequest.
 type LeaveGroupResponse struct {
 	// Version is the version of this message used with a Kafka broker.
@@ -14884,6 +15818,14 @@ func NewLeaveGroupResponse() LeaveGroupResponse {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v LeaveGroupResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *LeaveGroupResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type SyncGroupRequestGroupAssignment struct {
 	// MemberID is the member this assignment is for.
 	MemberID string
@@ -14909,6 +15851,14 @@ func NewSyncGroupRequestGroupAssignment() SyncGroupRequestGroupAssignment {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v SyncGroupRequestGroupAssignment) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *SyncGroupRequestGroupAssignment) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // SyncGroupRequest is issued by all group members after they receive a a
 // response for JoinGroup. The group leader is responsible for sending member
 // assignments with the request; all other members do not.
@@ -15237,6 +16187,14 @@ func NewSyncGroupRequest() SyncGroupRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v SyncGroupRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *SyncGroupRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // SyncGroupResponse is returned from a SyncGroupRequest.
 type SyncGroupResponse struct {
 	// Version is the version of this message used with a Kafka broker.
@@ -15434,6 +16392,14 @@ func NewSyncGroupResponse() SyncGroupResponse {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v SyncGroupResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *SyncGroupResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // DescribeGroupsRequest requests metadata for group IDs.
 type DescribeGroupsRequest struct {
 	// Version is the version of this message used with a Kafka broker.
@@ -15587,6 +16553,14 @@ func NewDescribeGroupsRequest() DescribeGroupsRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DescribeGroupsRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DescribeGroupsRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type DescribeGroupsResponseGroupMember struct {
 	// MemberID is the member ID of a member in this group.
 	MemberID string
@@ -15627,6 +16601,14 @@ func NewDescribeGroupsResponseGroupMember() DescribeGroupsResponseGroupMember {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DescribeGroupsResponseGroupMember) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DescribeGroupsResponseGroupMember) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type DescribeGroupsResponseGroup struct {
 	// ErrorCode is the error code for an individual group in a request.
 	//
@@ -15684,6 +16666,14 @@ func NewDescribeGroupsResponseGroup() DescribeGroupsResponseGroup {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DescribeGroupsResponseGroup) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DescribeGroupsResponseGroup) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // DescribeGroupsResponse is returned from a DescribeGroupsRequest.
 type DescribeGroupsResponse struct {
 	// Version is the version of this message used with a Kafka broker.
@@ -16112,6 +17102,14 @@ func NewDescribeGroupsResponse() DescribeGroupsResponse {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DescribeGroupsResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DescribeGroupsResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // ListGroupsRequest issues a request to list all groups.
 //
 // To list all groups in a cluster, this must be issued to every broker.
@@ -16312,6 +17310,14 @@ func NewListGroupsRequest() ListGroupsRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ListGroupsRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ListGroupsRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type ListGroupsResponseGroup struct {
 	// Group is a Kafka group.
 	Group string
@@ -16342,6 +17348,14 @@ func NewListGroupsResponseGroup() ListGroupsResponseGroup {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ListGroupsResponseGroup) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ListGroupsResponseGroup) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // ListGroupsResponse is returned from a ListGroupsRequest.
 type ListGroupsResponse struct {
 	// Version is the version of this message used with a Kafka broker.
@@ -16591,6 +17605,14 @@ func NewListGroupsResponse() ListGroupsResponse {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ListGroupsResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ListGroupsResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // SASLHandshakeRequest begins the sasl authentication flow. Note that Kerberos
 // GSSAPI authentication has its own unique flow.
 type SASLHandshakeRequest struct {
@@ -16685,6 +17707,14 @@ func NewSASLHandshakeRequest() SASLHandshakeRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v SASLHandshakeRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *SASLHandshakeRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // SASLHandshakeResponse is returned for a SASLHandshakeRequest.
 type SASLHandshakeResponse struct {
 	// Version is the version of this message used with a Kafka broker.
@@ -16793,6 +17823,14 @@ func NewSASLHandshakeResponse() SASLHandshakeResponse {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v SASLHandshakeResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *SASLHandshakeResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // ApiVersionsRequest requests what API versions a Kafka broker supports.
 //
 // Note that the client does not know the version a broker supports before
@@ -16953,6 +17991,14 @@ func NewApiVersionsRequest() ApiVersionsRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ApiVersionsRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ApiVersionsRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type ApiVersionsResponseApiKey struct {
 	// ApiKey is the key of a message request.
 	ApiKey int16
@@ -16980,6 +18026,14 @@ func NewApiVersionsResponseApiKey() ApiVersionsResponseApiKey {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ApiVersionsResponseApiKey) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ApiVersionsResponseApiKey) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type ApiVersionsResponseSupportedFeature struct {
 	// The name of the feature.
 	Name string
@@ -17007,6 +18061,16 @@ func NewApiVersionsResponseSupportedFeature() ApiVersionsResponseSupportedFeatur
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ApiVersionsResponseSupportedFeature) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ApiVersionsResponseSupportedFeature) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type ApiVersionsResponseFinalizedFeature struct {
 	// The name of the feature.
 	Name string
@@ -17034,6 +18098,16 @@ func NewApiVersionsResponseFinalizedFeature() ApiVersionsResponseFinalizedFeatur
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ApiVersionsResponseFinalizedFeature) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ApiVersionsResponseFinalizedFeature) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 // ApiVersionsResponse is returned from an ApiVersionsRequest.
 type ApiVersionsResponse struct {
 	// Version is the version of this message used with a Kafka broker.
@@ -17469,6 +18543,14 @@ func NewApiVersionsResponse() ApiVersionsResponse {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ApiVersionsResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ApiVersionsResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type CreateTopicsRequestTopicReplicaAssignment struct {
 	// Partition is a partition to create.
 	Partition int32
@@ -17493,6 +18575,18 @@ func NewCreateTopicsRequestTopicReplicaAssignment() CreateTopicsRequestTopicRepl
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v CreateTopicsRequestTopicReplicaAssignment) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *CreateTopicsRequestTopicReplicaAssignment) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type CreateTopicsRequestTopicConfig struct {
 	// Name is a topic level config key (e.g. segment.bytes).
 	Name string
@@ -17517,6 +18611,14 @@ func NewCreateTopicsRequestTopicConfig() CreateTopicsRequestTopicConfig {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v CreateTopicsRequestTopicConfig) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *CreateTopicsRequestTopicConfig) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type CreateTopicsRequestTopic struct {
 	// Topic is a topic to create.
 	Topic string
@@ -17557,6 +18659,14 @@ func NewCreateTopicsRequestTopic() CreateTopicsRequestTopic {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v CreateTopicsRequestTopic) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *CreateTopicsRequestTopic) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // CreateTopicsRequest creates Kafka topics.
 //
 // Version 4, introduced in Kafka 2.4.0, implies client support for
@@ -17941,6 +19051,14 @@ func NewCreateTopicsRequest() CreateTopicsRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v CreateTopicsRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *CreateTopicsRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type CreateTopicsResponseTopicConfig struct {
 	// Name is the configuration name (e.g. segment.bytes).
 	Name string
@@ -17980,6 +19098,14 @@ func NewCreateTopicsResponseTopicConfig() CreateTopicsResponseTopicConfig {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v CreateTopicsResponseTopicConfig) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *CreateTopicsResponseTopicConfig) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type CreateTopicsResponseTopic struct {
 	// Topic is the topic this response corresponds to.
 	Topic string
@@ -18061,6 +19187,14 @@ func NewCreateTopicsResponseTopic() CreateTopicsResponseTopic {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v CreateTopicsResponseTopic) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *CreateTopicsResponseTopic) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // CreateTopicsResponse is returned from a CreateTopicsRequest.
 type CreateTopicsResponse struct {
 	// Version is the version of this message used with a Kafka broker.
@@ -18423,6 +19557,14 @@ func NewCreateTopicsResponse() CreateTopicsResponse {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v CreateTopicsResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *CreateTopicsResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type DeleteTopicsRequestTopic struct {
 	Topic *string
 
@@ -18445,6 +19587,14 @@ func NewDeleteTopicsRequestTopic() DeleteTopicsRequestTopic {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DeleteTopicsRequestTopic) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DeleteTopicsRequestTopic) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // DeleteTopicsRequest deletes Kafka topics.
 type DeleteTopicsRequest struct {
 	// Version is the version of this message used with a Kafka broker.
@@ -18682,6 +19832,14 @@ func NewDeleteTopicsRequest() DeleteTopicsRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DeleteTopicsRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DeleteTopicsRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type DeleteTopicsResponseTopic struct {
 	// Topic is the topic requested for deletion.
 	Topic *string
@@ -18733,6 +19891,14 @@ func NewDeleteTopicsResponseTopic() DeleteTopicsResponseTopic {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DeleteTopicsResponseTopic) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DeleteTopicsResponseTopic) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // DeleteTopicsResponse is returned from a DeleteTopicsRequest.
 // Version 3 added the TOPIC_DELETION_DISABLED error proposed in KIP-322
 // and introduced in Kafka 2.1.0. Prior, the request timed out.
@@ -18968,6 +20134,14 @@ func NewDeleteTopicsResponse() DeleteTopicsResponse {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DeleteTopicsResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DeleteTopicsResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type DeleteRecordsRequestTopicPartition struct {
 	// Partition is a partition to delete records from.
 	Partition int32
@@ -18997,6 +20171,16 @@ func NewDeleteRecordsRequestTopicPartition() DeleteRecordsRequestTopicPartition
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DeleteRecordsRequestTopicPartition) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DeleteRecordsRequestTopicPartition) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type DeleteRecordsRequestTopic struct {
 	// Topic is a topic to delete records from.
 	Topic string
@@ -19021,6 +20205,14 @@ func NewDeleteRecordsRequestTopic() DeleteRecordsRequestTopic {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DeleteRecordsRequestTopic) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DeleteRecordsRequestTopic) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // DeleteRecordsRequest is an admin request to delete records from Kafka.
 // This was added for KIP-107.
 //
@@ -19261,6 +20453,14 @@ func NewDeleteRecordsRequest() DeleteRecordsRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DeleteRecordsRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DeleteRecordsRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type DeleteRecordsResponseTopicPartition struct {
 	// Partition is the partition this response corresponds to.
 	Partition int32
@@ -19307,6 +20507,16 @@ func NewDeleteRecordsResponseTopicPartition() DeleteRecordsResponseTopicPartitio
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DeleteRecordsResponseTopicPartition) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DeleteRecordsResponseTopicPartition) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type DeleteRecordsResponseTopic struct {
 	// Topic is the topic this response corresponds to.
 	Topic string
@@ -19332,6 +20542,14 @@ func NewDeleteRecordsResponseTopic() DeleteRecordsResponseTopic {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DeleteRecordsResponseTopic) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DeleteRecordsResponseTopic) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // DeleteRecordsResponse is returned from a DeleteRecordsRequest.
 type DeleteRecordsResponse struct {
 	// Version is the version of this message used with a Kafka broker.
@@ -19559,6 +20777,14 @@ func NewDeleteRecordsResponse() DeleteRecordsResponse {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DeleteRecordsResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DeleteRecordsResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // InitProducerIDRequest initializes a producer ID for idempotent transactions,
 // and if using transactions, a producer epoch. This is the first request
 // necessary to begin idempotent producing or transactions.
@@ -19723,6 +20949,14 @@ func NewInitProducerIDRequest() InitProducerIDRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v InitProducerIDRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *InitProducerIDRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // InitProducerIDResponse is returned for an InitProducerIDRequest.
 type InitProducerIDResponse struct {
 	// Version is the version of this message used with a Kafka broker.
@@ -19870,6 +21104,14 @@ func NewInitProducerIDResponse() InitProducerIDResponse {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v InitProducerIDResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *InitProducerIDResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type OffsetForLeaderEpochRequestTopicPartition struct {
 	// Partition is the number of a partition.
 	Partition int32
@@ -19904,6 +21146,18 @@ func NewOffsetForLeaderEpochRequestTopicPartition() OffsetForLeaderEpochRequestT
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v OffsetForLeaderEpochRequestTopicPartition) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *OffsetForLeaderEpochRequestTopicPartition) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type OffsetForLeaderEpochRequestTopic struct {
 	// Topic is the name of a topic.
 	Topic string
@@ -19928,6 +21182,14 @@ func NewOffsetForLeaderEpochRequestTopic() OffsetForLeaderEpochRequestTopic {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v OffsetForLeaderEpochRequestTopic) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *OffsetForLeaderEpochRequestTopic) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // OffsetForLeaderEpochRequest requests log end offsets for partitions.
 //
 // Version 2, proposed in KIP-320 and introduced in Kafka 2.1.0, can be used by
@@ -20168,6 +21430,14 @@ func NewOffsetForLeaderEpochRequest() OffsetForLeaderEpochRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v OffsetForLeaderEpochRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *OffsetForLeaderEpochRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type OffsetForLeaderEpochResponseTopicPartition struct {
 	// ErrorCode is the error code returned on request failure.
 	//
@@ -20240,6 +21510,18 @@ func NewOffsetForLeaderEpochResponseTopicPartition() OffsetForLeaderEpochRespons
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v OffsetForLeaderEpochResponseTopicPartition) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *OffsetForLeaderEpochResponseTopicPartition) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type OffsetForLeaderEpochResponseTopic struct {
 	// Topic is the topic this response corresponds to.
 	Topic string
@@ -20264,6 +21546,14 @@ func NewOffsetForLeaderEpochResponseTopic() OffsetForLeaderEpochResponseTopic {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v OffsetForLeaderEpochResponseTopic) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *OffsetForLeaderEpochResponseTopic) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // OffsetForLeaderEpochResponse is returned from an OffsetForLeaderEpochRequest.
 type OffsetForLeaderEpochResponse struct {
 	// Version is the version of this message used with a Kafka broker.
@@ -20501,6 +21791,14 @@ func NewOffsetForLeaderEpochResponse() OffsetForLeaderEpochResponse {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v OffsetForLeaderEpochResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *OffsetForLeaderEpochResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type AddPartitionsToTxnRequestTopic struct {
 	// Topic is a topic name.
 	Topic string
@@ -20526,6 +21824,14 @@ func NewAddPartitionsToTxnRequestTopic() AddPartitionsToTxnRequestTopic {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v AddPartitionsToTxnRequestTopic) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *AddPartitionsToTxnRequestTopic) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type AddPartitionsToTxnRequestTransactionTopic struct {
 	Topic string
 
@@ -20548,6 +21854,18 @@ func NewAddPartitionsToTxnRequestTransactionTopic() AddPartitionsToTxnRequestTra
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v AddPartitionsToTxnRequestTransactionTopic) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *AddPartitionsToTxnRequestTransactionTopic) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type AddPartitionsToTxnRequestTransaction struct {
 	TransactionalID string
 
@@ -20578,6 +21896,16 @@ func NewAddPartitionsToTxnRequestTransaction() AddPartitionsToTxnRequestTransact
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v AddPartitionsToTxnRequestTransaction) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *AddPartitionsToTxnRequestTransaction) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 // AddPartitionsToTxnRequest begins the producer side of a transaction for all
 // partitions in the request. Before producing any records to a partition in
 // the transaction, that partition must have been added to the transaction with
@@ -21027,6 +22355,14 @@ func NewAddPartitionsToTxnRequest() AddPartitionsToTxnRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v AddPartitionsToTxnRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *AddPartitionsToTxnRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type AddPartitionsToTxnResponseTransactionTopicPartition struct {
 	Partition int32
 
@@ -21049,6 +22385,18 @@ func NewAddPartitionsToTxnResponseTransactionTopicPartition() AddPartitionsToTxn
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v AddPartitionsToTxnResponseTransactionTopicPartition) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *AddPartitionsToTxnResponseTransactionTopicPartition) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type AddPartitionsToTxnResponseTransactionTopic struct {
 	Topic string
 
@@ -21071,6 +22419,18 @@ func NewAddPartitionsToTxnResponseTransactionTopic() AddPartitionsToTxnResponseT
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v AddPartitionsToTxnResponseTransactionTopic) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *AddPartitionsToTxnResponseTransactionTopic) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type AddPartitionsToTxnResponseTransaction struct {
 	// The transactional id corresponding to the transaction.
 	TransactionalID string
@@ -21094,6 +22454,16 @@ func NewAddPartitionsToTxnResponseTransaction() AddPartitionsToTxnResponseTransa
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v AddPartitionsToTxnResponseTransaction) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *AddPartitionsToTxnResponseTransaction) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type AddPartitionsToTxnResponseTopicPartition struct {
 	// Partition is a partition being responded to.
 	Partition int32
@@ -21149,6 +22519,18 @@ func NewAddPartitionsToTxnResponseTopicPartition() AddPartitionsToTxnResponseTop
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v AddPartitionsToTxnResponseTopicPartition) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *AddPartitionsToTxnResponseTopicPartition) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type AddPartitionsToTxnResponseTopic struct {
 	// Topic is a topic being responded to.
 	Topic string
@@ -21173,6 +22555,14 @@ func NewAddPartitionsToTxnResponseTopic() AddPartitionsToTxnResponseTopic {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v AddPartitionsToTxnResponseTopic) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *AddPartitionsToTxnResponseTopic) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // AddPartitionsToTxnResponse is a response to an AddPartitionsToTxnRequest.
 type AddPartitionsToTxnResponse struct {
 	// Version is the version of this message used with a Kafka broker.
@@ -21606,6 +22996,14 @@ func NewAddPartitionsToTxnResponse() AddPartitionsToTxnResponse {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v AddPartitionsToTxnResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *AddPartitionsToTxnResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // AddOffsetsToTxnRequest is a request that ties produced records to what group
 // is being consumed for the transaction.
 //
@@ -21778,6 +23176,14 @@ func NewAddOffsetsToTxnRequest() AddOffsetsToTxnRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v AddOffsetsToTxnRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *AddOffsetsToTxnRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // AddOffsetsToTxnResponse is a response to an AddOffsetsToTxnRequest.
 type AddOffsetsToTxnResponse struct {
 	// Version is the version of this message used with a Kafka broker.
@@ -21892,6 +23298,14 @@ func NewAddOffsetsToTxnResponse() AddOffsetsToTxnResponse {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v AddOffsetsToTxnResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *AddOffsetsToTxnResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // EndTxnRequest ends a transaction. This should be called after
 // TxnOffsetCommitRequest.
 type EndTxnRequest struct {
@@ -22041,6 +23455,14 @@ func NewEndTxnRequest() EndTxnRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v EndTxnRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *EndTxnRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // EndTxnResponse is a response for an EndTxnRequest.
 type EndTxnResponse struct {
 	// Version is the version of this message used with a Kafka broker.
@@ -22160,6 +23582,14 @@ func NewEndTxnResponse() EndTxnResponse {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v EndTxnResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *EndTxnResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type WriteTxnMarkersRequestMarkerTopic struct {
 	// Topic is the name of the topic to write markers for.
 	Topic string
@@ -22184,6 +23614,14 @@ func NewWriteTxnMarkersRequestMarkerTopic() WriteTxnMarkersRequestMarkerTopic {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v WriteTxnMarkersRequestMarkerTopic) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *WriteTxnMarkersRequestMarkerTopic) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type WriteTxnMarkersRequestMarker struct {
 	// ProducerID is the current producer ID to use when writing a marker.
 	ProducerID int64
@@ -22220,6 +23658,14 @@ func NewWriteTxnMarkersRequestMarker() WriteTxnMarkersRequestMarker {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v WriteTxnMarkersRequestMarker) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *WriteTxnMarkersRequestMarker) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // WriteTxnMarkersRequest is a broker-to-broker request that Kafka uses to
 // finish transactions.
 type WriteTxnMarkersRequest struct {
@@ -22484,6 +23930,14 @@ func NewWriteTxnMarkersRequest() WriteTxnMarkersRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v WriteTxnMarkersRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *WriteTxnMarkersRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type WriteTxnMarkersResponseMarkerTopicPartition struct {
 	// Partition is the partition this result is for.
 	Partition int32
@@ -22521,6 +23975,18 @@ func NewWriteTxnMarkersResponseMarkerTopicPartition() WriteTxnMarkersResponseMar
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v WriteTxnMarkersResponseMarkerTopicPartition) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *WriteTxnMarkersResponseMarkerTopicPartition) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type WriteTxnMarkersResponseMarkerTopic struct {
 	// Topic is the topic these results are for.
 	Topic string
@@ -22546,6 +24012,16 @@ func NewWriteTxnMarkersResponseMarkerTopic() WriteTxnMarkersResponseMarkerTopic
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v WriteTxnMarkersResponseMarkerTopic) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *WriteTxnMarkersResponseMarkerTopic) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type WriteTxnMarkersResponseMarker struct {
 	// ProducerID is the producer ID these results are for (from the input
 	// request).
@@ -22571,6 +24047,14 @@ func NewWriteTxnMarkersResponseMarker() WriteTxnMarkersResponseMarker {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v WriteTxnMarkersResponseMarker) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *WriteTxnMarkersResponseMarker) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // WriteTxnMarkersResponse is a response to a WriteTxnMarkersRequest.
 type WriteTxnMarkersResponse struct {
 	// Version is the version of this message used with a Kafka broker.
@@ -22822,6 +24306,14 @@ func NewWriteTxnMarkersResponse() WriteTxnMarkersResponse {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v WriteTxnMarkersResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *WriteTxnMarkersResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type TxnOffsetCommitRequestTopicPartition struct {
 	// Partition is a partition to add for a pending commit.
 	Partition int32
@@ -22862,6 +24354,16 @@ func NewTxnOffsetCommitRequestTopicPartition() TxnOffsetCommitRequestTopicPartit
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v TxnOffsetCommitRequestTopicPartition) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *TxnOffsetCommitRequestTopicPartition) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type TxnOffsetCommitRequestTopic struct {
 	// Topic is a topic to add for a pending commit.
 	Topic string
@@ -22886,6 +24388,14 @@ func NewTxnOffsetCommitRequestTopic() TxnOffsetCommitRequestTopic {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v TxnOffsetCommitRequestTopic) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *TxnOffsetCommitRequestTopic) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // TxnOffsetCommitRequest sends offsets that are a part of this transaction
 // to be committed once the transaction itself finishes. This effectively
 // replaces OffsetCommitRequest for when using transactions.
@@ -23285,6 +24795,14 @@ func NewTxnOffsetCommitRequest() TxnOffsetCommitRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v TxnOffsetCommitRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *TxnOffsetCommitRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type TxnOffsetCommitResponseTopicPartition struct {
 	// Partition is the partition this response is for.
 	Partition int32
@@ -23347,6 +24865,16 @@ func NewTxnOffsetCommitResponseTopicPartition() TxnOffsetCommitResponseTopicPart
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v TxnOffsetCommitResponseTopicPartition) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *TxnOffsetCommitResponseTopicPartition) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type TxnOffsetCommitResponseTopic struct {
 	// Topic is the topic this response is for.
 	Topic string
@@ -23371,6 +24899,14 @@ func NewTxnOffsetCommitResponseTopic() TxnOffsetCommitResponseTopic {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v TxnOffsetCommitResponseTopic) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *TxnOffsetCommitResponseTopic) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // TxnOffsetCommitResponse is a response to a TxnOffsetCommitRequest.
 type TxnOffsetCommitResponse struct {
 	// Version is the version of this message used with a Kafka broker.
@@ -23593,6 +25129,14 @@ func NewTxnOffsetCommitResponse() TxnOffsetCommitResponse {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v TxnOffsetCommitResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *TxnOffsetCommitResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // DescribeACLsRequest describes ACLs. Describing ACLs works on a filter basis:
 // anything that matches the filter is described. Note that there are two
 // "types" of filters in this request: the resource filter and the entry
@@ -23851,6 +25395,14 @@ func NewDescribeACLsRequest() DescribeACLsRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DescribeACLsRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DescribeACLsRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type DescribeACLsResponseResourceACL struct {
 	// Principal is who this ACL applies to.
 	Principal string
@@ -23881,6 +25433,14 @@ func NewDescribeACLsResponseResourceACL() DescribeACLsResponseResourceACL {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DescribeACLsResponseResourceACL) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DescribeACLsResponseResourceACL) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type DescribeACLsResponseResource struct {
 	// ResourceType is the resource type being described.
 	ResourceType ACLResourceType
@@ -23914,6 +25474,14 @@ func NewDescribeACLsResponseResource() DescribeACLsResponseResource {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DescribeACLsResponseResource) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DescribeACLsResponseResource) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // DescribeACLsResponse is a response to a describe acls request.
 type DescribeACLsResponse struct {
 	// Version is the version of this message used with a Kafka broker.
@@ -24273,6 +25841,14 @@ func NewDescribeACLsResponse() DescribeACLsResponse {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DescribeACLsResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DescribeACLsResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type CreateACLsRequestCreation struct {
 	// ResourceType is the type of resource this acl entry will be on.
 	// It is invalid to use UNKNOWN or ANY.
@@ -24324,6 +25900,14 @@ func NewCreateACLsRequestCreation() CreateACLsRequestCreation {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v CreateACLsRequestCreation) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *CreateACLsRequestCreation) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // CreateACLsRequest creates acls. Creating acls can be done as a batch; each
 // "creation" will be an acl entry.
 //
@@ -24595,6 +26179,14 @@ func NewCreateACLsRequest() CreateACLsRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v CreateACLsRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *CreateACLsRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type CreateACLsResponseResult struct {
 	// ErrorCode is an error for this particular creation (index wise).
 	ErrorCode int16
@@ -24619,6 +26211,14 @@ func NewCreateACLsResponseResult() CreateACLsResponseResult {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v CreateACLsResponseResult) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *CreateACLsResponseResult) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // CreateACLsResponse is a response for a CreateACLsRequest.
 type CreateACLsResponse struct {
 	// Version is the version of this message used with a Kafka broker.
@@ -24786,6 +26386,14 @@ func NewCreateACLsResponse() CreateACLsResponse {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v CreateACLsResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *CreateACLsResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type DeleteACLsRequestFilter struct {
 	ResourceType ACLResourceType
 
@@ -24820,6 +26428,14 @@ func NewDeleteACLsRequestFilter() DeleteACLsRequestFilter {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DeleteACLsRequestFilter) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DeleteACLsRequestFilter) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // DeleteACLsRequest deletes acls. This request works on filters the same way
 // that DescribeACLsRequest does. See DescribeACLsRequest for documentation of
 // the fields.
@@ -25090,6 +26706,14 @@ func NewDeleteACLsRequest() DeleteACLsRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DeleteACLsRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DeleteACLsRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type DeleteACLsResponseResultMatchingACL struct {
 	// ErrorCode contains an error for this individual acl for this filter.
 	ErrorCode int16
@@ -25130,6 +26754,16 @@ func NewDeleteACLsResponseResultMatchingACL() DeleteACLsResponseResultMatchingAC
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DeleteACLsResponseResultMatchingACL) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DeleteACLsResponseResultMatchingACL) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type DeleteACLsResponseResult struct {
 	// ErrorCode is the overall error code for this individual filter.
 	ErrorCode int16
@@ -25157,6 +26791,14 @@ func NewDeleteACLsResponseResult() DeleteACLsResponseResult {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DeleteACLsResponseResult) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DeleteACLsResponseResult) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // DeleteACLsResponse is a response for a DeleteACLsRequest.
 type DeleteACLsResponse struct {
 	// Version is the version of this message used with a Kafka broker.
@@ -25538,6 +27180,14 @@ func NewDeleteACLsResponse() DeleteACLsResponse {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DeleteACLsResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DeleteACLsResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type DescribeConfigsRequestResource struct {
 	// ResourceType is an enum corresponding to the type of config to describe.
 	ResourceType ConfigResourceType
@@ -25572,6 +27222,14 @@ func NewDescribeConfigsRequestResource() DescribeConfigsRequestResource {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DescribeConfigsRequestResource) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DescribeConfigsRequestResource) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // DescribeConfigsRequest issues a request to describe configs that Kafka
 // currently has. These are the key/value pairs that one uses to configure
 // brokers and topics.
@@ -25823,6 +27481,14 @@ func NewDescribeConfigsRequest() DescribeConfigsRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DescribeConfigsRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DescribeConfigsRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type DescribeConfigsResponseResourceConfigConfigSynonym struct {
 	Name string
 
@@ -25847,6 +27513,18 @@ func NewDescribeConfigsResponseResourceConfigConfigSynonym() DescribeConfigsResp
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DescribeConfigsResponseResourceConfigConfigSynonym) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DescribeConfigsResponseResourceConfigConfigSynonym) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type DescribeConfigsResponseResourceConfig struct {
 	// Name is a key this entry corresponds to (e.g. segment.bytes).
 	Name string
@@ -25904,6 +27582,16 @@ func NewDescribeConfigsResponseResourceConfig() DescribeConfigsResponseResourceC
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DescribeConfigsResponseResourceConfig) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DescribeConfigsResponseResourceConfig) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type DescribeConfigsResponseResource struct {
 	// ErrorCode is the error code returned for describing configs.
 	//
@@ -25952,6 +27640,14 @@ func NewDescribeConfigsResponseResource() DescribeConfigsResponseResource {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DescribeConfigsResponseResource) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DescribeConfigsResponseResource) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // DescribeConfigsResponse is returned from a DescribeConfigsRequest.
 type DescribeConfigsResponse struct {
 	// Version is the version of this message used with a Kafka broker.
@@ -26446,6 +28142,14 @@ func NewDescribeConfigsResponse() DescribeConfigsResponse {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DescribeConfigsResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DescribeConfigsResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type AlterConfigsRequestResourceConfig struct {
 	// Name is a key to set (e.g. segment.bytes).
 	Name string
@@ -26470,6 +28174,14 @@ func NewAlterConfigsRequestResourceConfig() AlterConfigsRequestResourceConfig {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v AlterConfigsRequestResourceConfig) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *AlterConfigsRequestResourceConfig) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type AlterConfigsRequestResource struct {
 	// ResourceType is an enum corresponding to the type of config to alter.
 	// The only two valid values are 2 (for topic) and 4 (for broker).
@@ -26509,6 +28221,14 @@ func NewAlterConfigsRequestResource() AlterConfigsRequestResource {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v AlterConfigsRequestResource) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *AlterConfigsRequestResource) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // AlterConfigsRequest issues a request to alter either topic or broker
 // configs.
 //
@@ -26794,6 +28514,14 @@ func NewAlterConfigsRequest() AlterConfigsRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v AlterConfigsRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *AlterConfigsRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type AlterConfigsResponseResource struct {
 	// ErrorCode is the error code returned for altering configs.
 	//
@@ -26838,6 +28566,14 @@ func NewAlterConfigsResponseResource() AlterConfigsResponseResource {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v AlterConfigsResponseResource) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *AlterConfigsResponseResource) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // AlterConfigsResponse is returned from an AlterConfigsRequest.
 type AlterConfigsResponse struct {
 	// Version is the version of this message used with a Kafka broker.
@@ -27046,6 +28782,14 @@ func NewAlterConfigsResponse() AlterConfigsResponse {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v AlterConfigsResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *AlterConfigsResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type AlterReplicaLogDirsRequestDirTopic struct {
 	// Topic is a topic to move.
 	Topic string
@@ -27070,6 +28814,16 @@ func NewAlterReplicaLogDirsRequestDirTopic() AlterReplicaLogDirsRequestDirTopic
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v AlterReplicaLogDirsRequestDirTopic) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *AlterReplicaLogDirsRequestDirTopic) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type AlterReplicaLogDirsRequestDir struct {
 	// Dir is an absolute path where everything listed below should
 	// end up.
@@ -27095,6 +28849,14 @@ func NewAlterReplicaLogDirsRequestDir() AlterReplicaLogDirsRequestDir {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v AlterReplicaLogDirsRequestDir) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *AlterReplicaLogDirsRequestDir) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // AlterReplicaLogDirsRequest requests for log directories to be moved
 // within Kafka.
 //
@@ -27354,6 +29116,14 @@ func NewAlterReplicaLogDirsRequest() AlterReplicaLogDirsRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v AlterReplicaLogDirsRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *AlterReplicaLogDirsRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type AlterReplicaLogDirsResponseTopicPartition struct {
 	// Partition is the partition this array slot corresponds to.
 	Partition int32
@@ -27388,6 +29158,18 @@ func NewAlterReplicaLogDirsResponseTopicPartition() AlterReplicaLogDirsResponseT
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v AlterReplicaLogDirsResponseTopicPartition) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *AlterReplicaLogDirsResponseTopicPartition) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type AlterReplicaLogDirsResponseTopic struct {
 	// Topic is the topic this array slot corresponds to.
 	Topic string
@@ -27413,6 +29195,14 @@ func NewAlterReplicaLogDirsResponseTopic() AlterReplicaLogDirsResponseTopic {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v AlterReplicaLogDirsResponseTopic) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *AlterReplicaLogDirsResponseTopic) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // AlterReplicaLogDirsResponse is returned from an AlterReplicaLogDirsRequest.
 type AlterReplicaLogDirsResponse struct {
 	// Version is the version of this message used with a Kafka broker.
@@ -27639,6 +29429,14 @@ func NewAlterReplicaLogDirsResponse() AlterReplicaLogDirsResponse {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v AlterReplicaLogDirsResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *AlterReplicaLogDirsResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type DescribeLogDirsRequestTopic struct {
 	// Topic is a topic to describe the log dir of.
 	Topic string
@@ -27663,6 +29461,14 @@ func NewDescribeLogDirsRequestTopic() DescribeLogDirsRequestTopic {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DescribeLogDirsRequestTopic) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DescribeLogDirsRequestTopic) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // DescribeLogDirsRequest requests directory information for topic partitions.
 // This request was added in support of KIP-113.
 type DescribeLogDirsRequest struct {
@@ -27857,6 +29663,14 @@ func NewDescribeLogDirsRequest() DescribeLogDirsRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DescribeLogDirsRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DescribeLogDirsRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type DescribeLogDirsResponseDirTopicPartition struct {
 	// Partition is a partition ID.
 	Partition int32
@@ -27898,6 +29712,18 @@ func NewDescribeLogDirsResponseDirTopicPartition() DescribeLogDirsResponseDirTop
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DescribeLogDirsResponseDirTopicPartition) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DescribeLogDirsResponseDirTopicPartition) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type DescribeLogDirsResponseDirTopic struct {
 	// Topic is the name of a Kafka topic.
 	Topic string
@@ -27923,6 +29749,14 @@ func NewDescribeLogDirsResponseDirTopic() DescribeLogDirsResponseDirTopic {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DescribeLogDirsResponseDirTopic) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DescribeLogDirsResponseDirTopic) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type DescribeLogDirsResponseDir struct {
 	// ErrorCode is the error code returned for describing log dirs.
 	//
@@ -27966,6 +29800,14 @@ func NewDescribeLogDirsResponseDir() DescribeLogDirsResponseDir {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DescribeLogDirsResponseDir) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DescribeLogDirsResponseDir) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // DescribeLogDirsResponse is returned from a DescribeLogDirsRequest.
 type DescribeLogDirsResponse struct {
 	// Version is the version of this message used with a Kafka broker.
@@ -28307,6 +30149,14 @@ func NewDescribeLogDirsResponse() DescribeLogDirsResponse {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DescribeLogDirsResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DescribeLogDirsResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // SASLAuthenticate continues a sasl authentication flow. Prior to Kafka 1.0.0,
 // authenticating with sasl involved sending raw blobs of data back and forth.
 // After, those blobs are wrapped in a SASLAuthenticateRequest The benefit of
@@ -28416,6 +30266,14 @@ func NewSASLAuthenticateRequest() SASLAuthenticateRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v SASLAuthenticateRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *SASLAuthenticateRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // SASLAuthenticateResponse is returned for a SASLAuthenticateRequest.
 type SASLAuthenticateResponse struct {
 	// Version is the version of this message used with a Kafka broker.
@@ -28562,6 +30420,14 @@ func NewSASLAuthenticateResponse() SASLAuthenticateResponse {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v SASLAuthenticateResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *SASLAuthenticateResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type CreatePartitionsRequestTopicAssignment struct {
 	// Replicas are replicas to assign a new partition to.
 	Replicas []int32
@@ -28583,6 +30449,16 @@ func NewCreatePartitionsRequestTopicAssignment() CreatePartitionsRequestTopicAss
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v CreatePartitionsRequestTopicAssignment) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *CreatePartitionsRequestTopicAssignment) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type CreatePartitionsRequestTopic struct {
 	// Topic is a topic for which to create additional partitions for.
 	Topic string
@@ -28621,6 +30497,14 @@ func NewCreatePartitionsRequestTopic() CreatePartitionsRequestTopic {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v CreatePartitionsRequestTopic) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *CreatePartitionsRequestTopic) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // CreatePartitionsRequest creates additional partitions for topics.
 type CreatePartitionsRequest struct {
 	// Version is the version of this message used with a Kafka broker.
@@ -28895,6 +30779,14 @@ func NewCreatePartitionsRequest() CreatePartitionsRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v CreatePartitionsRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *CreatePartitionsRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type CreatePartitionsResponseTopic struct {
 	// Topic is the topic that partitions were requested to be made for.
 	Topic string
@@ -28944,6 +30836,14 @@ func NewCreatePartitionsResponseTopic() CreatePartitionsResponseTopic {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v CreatePartitionsResponseTopic) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *CreatePartitionsResponseTopic) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // CreatePartitionsResponse is returned from a CreatePartitionsRequest.
 type CreatePartitionsResponse struct {
 	// Version is the version of this message used with a Kafka broker.
@@ -29141,6 +31041,14 @@ func NewCreatePartitionsResponse() CreatePartitionsResponse {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v CreatePartitionsResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *CreatePartitionsResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type CreateDelegationTokenRequestRenewer struct {
 	// PrincipalType is the "type" this principal is. This must be "User".
 	PrincipalType string
@@ -29165,6 +31073,16 @@ func NewCreateDelegationTokenRequestRenewer() CreateDelegationTokenRequestRenewe
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v CreateDelegationTokenRequestRenewer) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *CreateDelegationTokenRequestRenewer) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 // CreateDelegationTokenRequest issues a request to create a delegation token.
 //
 // Creating delegation tokens allows for an (ideally) quicker and easier method
@@ -29427,6 +31345,14 @@ func NewCreateDelegationTokenRequest() CreateDelegationTokenRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v CreateDelegationTokenRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *CreateDelegationTokenRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // CreateDelegationTokenResponse is a response to a CreateDelegationTokenRequest.
 type CreateDelegationTokenResponse struct {
 	// Version is the version of this message used with a Kafka broker.
@@ -29736,6 +31662,14 @@ func NewCreateDelegationTokenResponse() CreateDelegationTokenResponse {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v CreateDelegationTokenResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *CreateDelegationTokenResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // RenewDelegationTokenRequest is a request to renew a delegation token that
 // has not yet hit its max timestamp. Note that a client using a token cannot
 // renew its own token.
@@ -29854,6 +31788,14 @@ func NewRenewDelegationTokenRequest() RenewDelegationTokenRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v RenewDelegationTokenRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *RenewDelegationTokenRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // RenewDelegationTokenResponse is a response to a RenewDelegationTokenRequest.
 type RenewDelegationTokenResponse struct {
 	// Version is the version of this message used with a Kafka broker.
@@ -29975,6 +31917,14 @@ func NewRenewDelegationTokenResponse() RenewDelegationTokenResponse {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v RenewDelegationTokenResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *RenewDelegationTokenResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // ExpireDelegationTokenRequest is a request to change the expiry timestamp
 // of a delegation token. Note that a client using a token cannot expire its
 // own token.
@@ -30095,6 +32045,14 @@ func NewExpireDelegationTokenRequest() ExpireDelegationTokenRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ExpireDelegationTokenRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ExpireDelegationTokenRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // ExpireDelegationTokenResponse is a response to an ExpireDelegationTokenRequest.
 type ExpireDelegationTokenResponse struct {
 	// Version is the version of this message used with a Kafka broker.
@@ -30215,6 +32173,14 @@ func NewExpireDelegationTokenResponse() ExpireDelegationTokenResponse {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ExpireDelegationTokenResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ExpireDelegationTokenResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type DescribeDelegationTokenRequestOwner struct {
 	// PrincipalType is a type to match to describe delegation tokens created
 	// with this principal. This would be "User" with the simple authorizer.
@@ -30241,6 +32207,16 @@ func NewDescribeDelegationTokenRequestOwner() DescribeDelegationTokenRequestOwne
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DescribeDelegationTokenRequestOwner) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DescribeDelegationTokenRequestOwner) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 // DescribeDelegationTokenRequest is a request to describe delegation tokens.
 type DescribeDelegationTokenRequest struct {
 	// Version is the version of this message used with a Kafka broker.
@@ -30425,6 +32401,14 @@ func NewDescribeDelegationTokenRequest() DescribeDelegationTokenRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DescribeDelegationTokenRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DescribeDelegationTokenRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type DescribeDelegationTokenResponseTokenDetailRenewer struct {
 	PrincipalType string
 
@@ -30447,6 +32431,18 @@ func NewDescribeDelegationTokenResponseTokenDetailRenewer() DescribeDelegationTo
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DescribeDelegationTokenResponseTokenDetailRenewer) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DescribeDelegationTokenResponseTokenDetailRenewer) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type DescribeDelegationTokenResponseTokenDetail struct {
 	// PrincipalType is the principal type of who created this token.
 	PrincipalType string
@@ -30496,6 +32492,18 @@ func NewDescribeDelegationTokenResponseTokenDetail() DescribeDelegationTokenResp
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DescribeDelegationTokenResponseTokenDetail) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DescribeDelegationTokenResponseTokenDetail) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 // DescribeDelegationTokenResponsee is a response to a DescribeDelegationTokenRequest.
 type DescribeDelegationTokenResponse struct {
 	// Version is the version of this message used with a Kafka broker.
@@ -30908,6 +32916,14 @@ func NewDescribeDelegationTokenResponse() DescribeDelegationTokenResponse {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DescribeDelegationTokenResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DescribeDelegationTokenResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // DeleteGroupsRequest deletes consumer groups. This request was added for
 // Kafka 1.1.0 corresponding to the removal of RetentionTimeMillis from
 // OffsetCommitRequest. See KIP-229 for more details.
@@ -31050,6 +33066,14 @@ func NewDeleteGroupsRequest() DeleteGroupsRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DeleteGroupsRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DeleteGroupsRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type DeleteGroupsResponseGroup struct {
 	// Group is a group ID requested for deletion.
 	Group string
@@ -31087,6 +33111,14 @@ func NewDeleteGroupsResponseGroup() DeleteGroupsResponseGroup {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DeleteGroupsResponseGroup) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DeleteGroupsResponseGroup) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // DeleteGroupsResponse is returned from a DeleteGroupsRequest.
 type DeleteGroupsResponse struct {
 	// Version is the version of this message used with a Kafka broker.
@@ -31254,6 +33286,14 @@ func NewDeleteGroupsResponse() DeleteGroupsResponse {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DeleteGroupsResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DeleteGroupsResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type ElectLeadersRequestTopic struct {
 	// Topic is a topic to trigger leader elections for (but only for the
 	// partitions below).
@@ -31280,6 +33320,14 @@ func NewElectLeadersRequestTopic() ElectLeadersRequestTopic {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ElectLeadersRequestTopic) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ElectLeadersRequestTopic) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // ElectLeadersRequest begins a leader election for all given topic
 // partitions. This request was added in Kafka 2.2.0 to replace the zookeeper
 // only option of triggering leader elections before. See KIP-183 for more
@@ -31509,6 +33557,14 @@ func NewElectLeadersRequest() ElectLeadersRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ElectLeadersRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ElectLeadersRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type ElectLeadersResponseTopicPartition struct {
 	// Partition is the partition for this result.
 	Partition int32
@@ -31552,6 +33608,16 @@ func NewElectLeadersResponseTopicPartition() ElectLeadersResponseTopicPartition
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ElectLeadersResponseTopicPartition) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ElectLeadersResponseTopicPartition) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type ElectLeadersResponseTopic struct {
 	// Topic is topic for the given partition results below.
 	Topic string
@@ -31576,6 +33642,14 @@ func NewElectLeadersResponseTopic() ElectLeadersResponseTopic {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ElectLeadersResponseTopic) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ElectLeadersResponseTopic) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // ElectLeadersResponse is a response for an ElectLeadersRequest.
 type ElectLeadersResponse struct {
 	// Version is the version of this message used with a Kafka broker.
@@ -31828,6 +33902,14 @@ func NewElectLeadersResponse() ElectLeadersResponse {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ElectLeadersResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ElectLeadersResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type IncrementalAlterConfigsRequestResourceConfig struct {
 	// Name is a key to modify (e.g. segment.bytes).
 	//
@@ -31868,6 +33950,18 @@ func NewIncrementalAlterConfigsRequestResourceConfig() IncrementalAlterConfigsRe
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v IncrementalAlterConfigsRequestResourceConfig) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *IncrementalAlterConfigsRequestResourceConfig) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type IncrementalAlterConfigsRequestResource struct {
 	// ResourceType is an enum corresponding to the type of config to alter.
 	ResourceType ConfigResourceType
@@ -31906,6 +34000,16 @@ func NewIncrementalAlterConfigsRequestResource() IncrementalAlterConfigsRequestR
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v IncrementalAlterConfigsRequestResource) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *IncrementalAlterConfigsRequestResource) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 // IncrementalAlterConfigsRequest issues ar equest to alter either topic or
 // broker configs.
 //
@@ -32200,6 +34304,14 @@ func NewIncrementalAlterConfigsRequest() IncrementalAlterConfigsRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v IncrementalAlterConfigsRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *IncrementalAlterConfigsRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type IncrementalAlterConfigsResponseResource struct {
 	// ErrorCode is the error code returned for incrementally altering configs.
 	//
@@ -32245,6 +34357,18 @@ func NewIncrementalAlterConfigsResponseResource() IncrementalAlterConfigsRespons
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v IncrementalAlterConfigsResponseResource) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *IncrementalAlterConfigsResponseResource) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 // IncrementalAlterConfigsResponse is returned from an IncrementalAlterConfigsRequest.
 type IncrementalAlterConfigsResponse struct {
 	// Version is the version of this message used with a Kafka broker.
@@ -32457,6 +34581,14 @@ func NewIncrementalAlterConfigsResponse() IncrementalAlterConfigsResponse {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v IncrementalAlterConfigsResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *IncrementalAlterConfigsResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type AlterPartitionAssignmentsRequestTopicPartition struct {
 	// Partition is a partition to reassign.
 	Partition int32
@@ -32482,6 +34614,18 @@ func NewAlterPartitionAssignmentsRequestTopicPartition() AlterPartitionAssignmen
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v AlterPartitionAssignmentsRequestTopicPartition) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *AlterPartitionAssignmentsRequestTopicPartition) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type AlterPartitionAssignmentsRequestTopic struct {
 	// Topic is a topic to reassign the partitions of.
 	Topic string
@@ -32506,6 +34650,16 @@ func NewAlterPartitionAssignmentsRequestTopic() AlterPartitionAssignmentsRequest
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v AlterPartitionAssignmentsRequestTopic) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *AlterPartitionAssignmentsRequestTopic) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 // AlterPartitionAssignmentsRequest, proposed in KIP-455 and implemented in
 // Kafka 2.4.0, is a request to reassign partitions to certain brokers.
 //
@@ -32773,6 +34927,14 @@ func NewAlterPartitionAssignmentsRequest() AlterPartitionAssignmentsRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v AlterPartitionAssignmentsRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *AlterPartitionAssignmentsRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type AlterPartitionAssignmentsResponseTopicPartition struct {
 	// Partition is the partition being responded to.
 	Partition int32
@@ -32814,6 +34976,18 @@ func NewAlterPartitionAssignmentsResponseTopicPartition() AlterPartitionAssignme
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v AlterPartitionAssignmentsResponseTopicPartition) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *AlterPartitionAssignmentsResponseTopicPartition) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type AlterPartitionAssignmentsResponseTopic struct {
 	// Topic is the topic being responded to.
 	Topic string
@@ -32838,6 +35012,16 @@ func NewAlterPartitionAssignmentsResponseTopic() AlterPartitionAssignmentsRespon
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v AlterPartitionAssignmentsResponseTopic) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *AlterPartitionAssignmentsResponseTopic) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 // AlterPartitionAssignmentsResponse is returned for an AlterPartitionAssignmentsRequest.
 type AlterPartitionAssignmentsResponse struct {
 	// Version is the version of this message used with a Kafka broker.
@@ -33123,6 +35307,14 @@ func NewAlterPartitionAssignmentsResponse() AlterPartitionAssignmentsResponse {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v AlterPartitionAssignmentsResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *AlterPartitionAssignmentsResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type ListPartitionReassignmentsRequestTopic struct {
 	// Topic is a topic to list in progress partition reassingments of.
 	Topic string
@@ -33147,6 +35339,16 @@ func NewListPartitionReassignmentsRequestTopic() ListPartitionReassignmentsReque
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ListPartitionReassignmentsRequestTopic) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ListPartitionReassignmentsRequestTopic) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 // ListPartitionReassignmentsRequest, proposed in KIP-455 and implemented in
 // Kafka 2.4.0, is a request to list in progress partition reassignments.
 //
@@ -33365,6 +35567,14 @@ func NewListPartitionReassignmentsRequest() ListPartitionReassignmentsRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ListPartitionReassignmentsRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ListPartitionReassignmentsRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type ListPartitionReassignmentsResponseTopicPartition struct {
 	// Partition is the partition being responded to.
 	Partition int32
@@ -33395,6 +35605,18 @@ func NewListPartitionReassignmentsResponseTopicPartition() ListPartitionReassign
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ListPartitionReassignmentsResponseTopicPartition) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ListPartitionReassignmentsResponseTopicPartition) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type ListPartitionReassignmentsResponseTopic struct {
 	// Topic is the topic being responded to.
 	Topic string
@@ -33419,6 +35641,18 @@ func NewListPartitionReassignmentsResponseTopic() ListPartitionReassignmentsResp
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ListPartitionReassignmentsResponseTopic) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ListPartitionReassignmentsResponseTopic) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 // ListPartitionReassignmentsResponse is returned for a ListPartitionReassignmentsRequest.
 type ListPartitionReassignmentsResponse struct {
 	// Version is the version of this message used with a Kafka broker.
@@ -33784,6 +36018,16 @@ func NewListPartitionReassignmentsResponse() ListPartitionReassignmentsResponse
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ListPartitionReassignmentsResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ListPartitionReassignmentsResponse) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type OffsetDeleteRequestTopicPartition struct {
 	// Partition is a partition to delete offsets for.
 	Partition int32
@@ -33802,6 +36046,14 @@ func NewOffsetDeleteRequestTopicPartition() OffsetDeleteRequestTopicPartition {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v OffsetDeleteRequestTopicPartition) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *OffsetDeleteRequestTopicPartition) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type OffsetDeleteRequestTopic struct {
 	// Topic is a topic to delete offsets in.
 	Topic string
@@ -33823,6 +36075,14 @@ func NewOffsetDeleteRequestTopic() OffsetDeleteRequestTopic {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v OffsetDeleteRequestTopic) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *OffsetDeleteRequestTopic) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // OffsetDeleteRequest, proposed in KIP-496 and implemented in Kafka 2.4.0, is
 // a request to delete group offsets.
 //
@@ -33992,6 +36252,14 @@ func NewOffsetDeleteRequest() OffsetDeleteRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v OffsetDeleteRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *OffsetDeleteRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type OffsetDeleteResponseTopicPartition struct {
 	// Partition is the partition being responded to.
 	Partition int32
@@ -34021,6 +36289,16 @@ func NewOffsetDeleteResponseTopicPartition() OffsetDeleteResponseTopicPartition
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v OffsetDeleteResponseTopicPartition) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *OffsetDeleteResponseTopicPartition) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type OffsetDeleteResponseTopic struct {
 	// Topic is the topic being responded to.
 	Topic string
@@ -34042,6 +36320,14 @@ func NewOffsetDeleteResponseTopic() OffsetDeleteResponseTopic {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v OffsetDeleteResponseTopic) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *OffsetDeleteResponseTopic) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // OffsetDeleteResponse is a response to an offset delete request.
 type OffsetDeleteResponse struct {
 	// Version is the version of this message used with a Kafka broker.
@@ -34224,6 +36510,14 @@ func NewOffsetDeleteResponse() OffsetDeleteResponse {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v OffsetDeleteResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *OffsetDeleteResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type DescribeClientQuotasRequestComponent struct {
 	// EntityType is the entity component type that this filter component
 	// applies to; some possible values are "user" or "client-id".
@@ -34256,6 +36550,16 @@ func NewDescribeClientQuotasRequestComponent() DescribeClientQuotasRequestCompon
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DescribeClientQuotasRequestComponent) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DescribeClientQuotasRequestComponent) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 // DescribeClientQuotasRequest, proposed in KIP-546 and introduced with Kafka 2.6.0,
 // provides a way to describe client quotas.
 type DescribeClientQuotasRequest struct {
@@ -34464,6 +36768,14 @@ func NewDescribeClientQuotasRequest() DescribeClientQuotasRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DescribeClientQuotasRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DescribeClientQuotasRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type DescribeClientQuotasResponseEntryEntity struct {
 	// Type is the entity type.
 	Type string
@@ -34488,6 +36800,18 @@ func NewDescribeClientQuotasResponseEntryEntity() DescribeClientQuotasResponseEn
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DescribeClientQuotasResponseEntryEntity) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DescribeClientQuotasResponseEntryEntity) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type DescribeClientQuotasResponseEntryValue struct {
 	// Key is the quota configuration key.
 	Key string
@@ -34512,6 +36836,16 @@ func NewDescribeClientQuotasResponseEntryValue() DescribeClientQuotasResponseEnt
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DescribeClientQuotasResponseEntryValue) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DescribeClientQuotasResponseEntryValue) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type DescribeClientQuotasResponseEntry struct {
 	// Entity contains the quota entity components being described.
 	Entity []DescribeClientQuotasResponseEntryEntity
@@ -34536,6 +36870,14 @@ func NewDescribeClientQuotasResponseEntry() DescribeClientQuotasResponseEntry {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DescribeClientQuotasResponseEntry) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DescribeClientQuotasResponseEntry) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // DescribeClientQuotasResponse is a response for a DescribeClientQuotasRequest.
 type DescribeClientQuotasResponse struct {
 	// Version is the version of this message used with a Kafka broker.
@@ -34883,6 +37225,14 @@ func NewDescribeClientQuotasResponse() DescribeClientQuotasResponse {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DescribeClientQuotasResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DescribeClientQuotasResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type AlterClientQuotasRequestEntryEntity struct {
 	// Type is the entity component's type; e.g. "client-id", "user" or "ip".
 	Type string
@@ -34907,6 +37257,16 @@ func NewAlterClientQuotasRequestEntryEntity() AlterClientQuotasRequestEntryEntit
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v AlterClientQuotasRequestEntryEntity) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *AlterClientQuotasRequestEntryEntity) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type AlterClientQuotasRequestEntryOp struct {
 	// Key is the quota configuration key to alter.
 	Key string
@@ -34934,6 +37294,14 @@ func NewAlterClientQuotasRequestEntryOp() AlterClientQuotasRequestEntryOp {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v AlterClientQuotasRequestEntryOp) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *AlterClientQuotasRequestEntryOp) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type AlterClientQuotasRequestEntry struct {
 	// Entity contains the components of a quota entity to alter.
 	Entity []AlterClientQuotasRequestEntryEntity
@@ -34958,6 +37326,14 @@ func NewAlterClientQuotasRequestEntry() AlterClientQuotasRequestEntry {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v AlterClientQuotasRequestEntry) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *AlterClientQuotasRequestEntry) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // AlterClientQuotaRequest, proposed in KIP-546 and introduced with Kafka 2.6.0,
 // provides a way to alter client quotas.
 type AlterClientQuotasRequest struct {
@@ -35275,6 +37651,14 @@ func NewAlterClientQuotasRequest() AlterClientQuotasRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v AlterClientQuotasRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *AlterClientQuotasRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type AlterClientQuotasResponseEntryEntity struct {
 	// Type is the entity component's type; e.g. "client-id" or "user".
 	Type string
@@ -35299,6 +37683,16 @@ func NewAlterClientQuotasResponseEntryEntity() AlterClientQuotasResponseEntryEnt
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v AlterClientQuotasResponseEntryEntity) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *AlterClientQuotasResponseEntryEntity) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type AlterClientQuotasResponseEntry struct {
 	// ErrorCode is the error code for an alter on a matched entity.
 	ErrorCode int16
@@ -35326,6 +37720,14 @@ func NewAlterClientQuotasResponseEntry() AlterClientQuotasResponseEntry {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v AlterClientQuotasResponseEntry) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *AlterClientQuotasResponseEntry) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // AlterClientQuotasResponse is a response to an AlterClientQuotasRequest.
 type AlterClientQuotasResponse struct {
 	// Version is the version of this message used with a Kafka broker.
@@ -35586,6 +37988,14 @@ func NewAlterClientQuotasResponse() AlterClientQuotasResponse {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v AlterClientQuotasResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *AlterClientQuotasResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type DescribeUserSCRAMCredentialsRequestUser struct {
 	// The user name.
 	Name string
@@ -35607,6 +38017,18 @@ func NewDescribeUserSCRAMCredentialsRequestUser() DescribeUserSCRAMCredentialsRe
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DescribeUserSCRAMCredentialsRequestUser) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DescribeUserSCRAMCredentialsRequestUser) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 // DescribeUserSCRAMCredentialsRequest, proposed in KIP-554 and introduced
 // with Kafka 2.7.0, describes user SCRAM credentials.
 //
@@ -35770,6 +38192,16 @@ func NewDescribeUserSCRAMCredentialsRequest() DescribeUserSCRAMCredentialsReques
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DescribeUserSCRAMCredentialsRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DescribeUserSCRAMCredentialsRequest) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type DescribeUserSCRAMCredentialsResponseResultCredentialInfo struct {
 	// The SCRAM mechanism for this user, where 0 is UNKNOWN, 1 is SCRAM-SHA-256,
 	// and 2 is SCRAM-SHA-512.
@@ -35795,6 +38227,18 @@ func NewDescribeUserSCRAMCredentialsResponseResultCredentialInfo() DescribeUserS
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DescribeUserSCRAMCredentialsResponseResultCredentialInfo) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DescribeUserSCRAMCredentialsResponseResultCredentialInfo) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type DescribeUserSCRAMCredentialsResponseResult struct {
 	// The name this result corresponds to.
 	User string
@@ -35829,6 +38273,18 @@ func NewDescribeUserSCRAMCredentialsResponseResult() DescribeUserSCRAMCredential
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DescribeUserSCRAMCredentialsResponseResult) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DescribeUserSCRAMCredentialsResponseResult) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 // DescribeUserSCRAMCredentialsResponse is a response for a
 // DescribeUserSCRAMCredentialsRequest.
 type DescribeUserSCRAMCredentialsResponse struct {
@@ -36125,6 +38581,16 @@ func NewDescribeUserSCRAMCredentialsResponse() DescribeUserSCRAMCredentialsRespo
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DescribeUserSCRAMCredentialsResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DescribeUserSCRAMCredentialsResponse) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type AlterUserSCRAMCredentialsRequestDeletion struct {
 	// The user name to match for removal.
 	Name string
@@ -36149,6 +38615,18 @@ func NewAlterUserSCRAMCredentialsRequestDeletion() AlterUserSCRAMCredentialsRequ
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v AlterUserSCRAMCredentialsRequestDeletion) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *AlterUserSCRAMCredentialsRequestDeletion) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type AlterUserSCRAMCredentialsRequestUpsertion struct {
 	// The user name to use.
 	Name string
@@ -36184,6 +38662,18 @@ func NewAlterUserSCRAMCredentialsRequestUpsertion() AlterUserSCRAMCredentialsReq
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v AlterUserSCRAMCredentialsRequestUpsertion) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *AlterUserSCRAMCredentialsRequestUpsertion) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 // AlterUserSCRAMCredentialsRequest, proposed in KIP-554 and introduced
 // with Kafka 2.7.0, alters or deletes user SCRAM credentials.
 //
@@ -36473,6 +38963,14 @@ func NewAlterUserSCRAMCredentialsRequest() AlterUserSCRAMCredentialsRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v AlterUserSCRAMCredentialsRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *AlterUserSCRAMCredentialsRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type AlterUserSCRAMCredentialsResponseResult struct {
 	// The name this result corresponds to.
 	User string
@@ -36500,6 +38998,18 @@ func NewAlterUserSCRAMCredentialsResponseResult() AlterUserSCRAMCredentialsRespo
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v AlterUserSCRAMCredentialsResponseResult) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *AlterUserSCRAMCredentialsResponseResult) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 // AlterUserSCRAMCredentialsResponse is a response for an
 // AlterUserSCRAMCredentialsRequest.
 type AlterUserSCRAMCredentialsResponse struct {
@@ -36697,6 +39207,14 @@ func NewAlterUserSCRAMCredentialsResponse() AlterUserSCRAMCredentialsResponse {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v AlterUserSCRAMCredentialsResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *AlterUserSCRAMCredentialsResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type VoteRequestTopicPartition struct {
 	Partition int32
 
@@ -36729,6 +39247,14 @@ func NewVoteRequestTopicPartition() VoteRequestTopicPartition {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v VoteRequestTopicPartition) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *VoteRequestTopicPartition) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type VoteRequestTopic struct {
 	Topic string
 
@@ -36751,6 +39277,14 @@ func NewVoteRequestTopic() VoteRequestTopic {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v VoteRequestTopic) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *VoteRequestTopic) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // Part of KIP-595 to replace Kafka's dependence on Zookeeper with a
 // Kafka-only raft protocol,
 // VoteRequest is used by voters to hold a leader election.
@@ -37018,6 +39552,14 @@ func NewVoteRequest() VoteRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v VoteRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *VoteRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type VoteResponseTopicPartition struct {
 	Partition int32
 
@@ -37049,6 +39591,14 @@ func NewVoteResponseTopicPartition() VoteResponseTopicPartition {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v VoteResponseTopicPartition) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *VoteResponseTopicPartition) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type VoteResponseTopic struct {
 	Topic string
 
@@ -37071,6 +39621,14 @@ func NewVoteResponseTopic() VoteResponseTopic {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v VoteResponseTopic) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *VoteResponseTopic) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type VoteResponse struct {
 	// Version is the version of this message used with a Kafka broker.
 	Version int16
@@ -37302,6 +39860,14 @@ func NewVoteResponse() VoteResponse {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v VoteResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *VoteResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type BeginQuorumEpochRequestTopicPartition struct {
 	Partition int32
 
@@ -37325,6 +39891,16 @@ func NewBeginQuorumEpochRequestTopicPartition() BeginQuorumEpochRequestTopicPart
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v BeginQuorumEpochRequestTopicPartition) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *BeginQuorumEpochRequestTopicPartition) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type BeginQuorumEpochRequestTopic struct {
 	Topic string
 
@@ -37344,6 +39920,14 @@ func NewBeginQuorumEpochRequestTopic() BeginQuorumEpochRequestTopic {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v BeginQuorumEpochRequestTopic) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *BeginQuorumEpochRequestTopic) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // Part of KIP-595 to replace Kafka's dependence on Zookeeper with a
 // Kafka-only raft protocol,
 // BeginQuorumEpochRequest is sent by a leader (once it has enough votes)
@@ -37528,6 +40112,14 @@ func NewBeginQuorumEpochRequest() BeginQuorumEpochRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v BeginQuorumEpochRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *BeginQuorumEpochRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type BeginQuorumEpochResponseTopicPartition struct {
 	Partition int32
 
@@ -37553,6 +40145,16 @@ func NewBeginQuorumEpochResponseTopicPartition() BeginQuorumEpochResponseTopicPa
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v BeginQuorumEpochResponseTopicPartition) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *BeginQuorumEpochResponseTopicPartition) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type BeginQuorumEpochResponseTopic struct {
 	Topic string
 
@@ -37572,6 +40174,14 @@ func NewBeginQuorumEpochResponseTopic() BeginQuorumEpochResponseTopic {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v BeginQuorumEpochResponseTopic) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *BeginQuorumEpochResponseTopic) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type BeginQuorumEpochResponse struct {
 	// Version is the version of this message used with a Kafka broker.
 	Version int16
@@ -37741,6 +40351,14 @@ func NewBeginQuorumEpochResponse() BeginQuorumEpochResponse {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v BeginQuorumEpochResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *BeginQuorumEpochResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type EndQuorumEpochRequestTopicPartition struct {
 	Partition int32
 
@@ -37767,6 +40385,16 @@ func NewEndQuorumEpochRequestTopicPartition() EndQuorumEpochRequestTopicPartitio
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v EndQuorumEpochRequestTopicPartition) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *EndQuorumEpochRequestTopicPartition) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type EndQuorumEpochRequestTopic struct {
 	Topic string
 
@@ -37786,6 +40414,14 @@ func NewEndQuorumEpochRequestTopic() EndQuorumEpochRequestTopic {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v EndQuorumEpochRequestTopic) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *EndQuorumEpochRequestTopic) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // Part of KIP-595 to replace Kafka's dependence on Zookeeper with a
 // Kafka-only raft protocol,
 // EndQuorumEpochRequest is sent by a leader to gracefully step down as leader
@@ -37997,6 +40633,14 @@ func NewEndQuorumEpochRequest() EndQuorumEpochRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v EndQuorumEpochRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *EndQuorumEpochRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type EndQuorumEpochResponseTopicPartition struct {
 	Partition int32
 
@@ -38022,6 +40666,16 @@ func NewEndQuorumEpochResponseTopicPartition() EndQuorumEpochResponseTopicPartit
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v EndQuorumEpochResponseTopicPartition) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *EndQuorumEpochResponseTopicPartition) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type EndQuorumEpochResponseTopic struct {
 	Topic string
 
@@ -38041,6 +40695,14 @@ func NewEndQuorumEpochResponseTopic() EndQuorumEpochResponseTopic {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v EndQuorumEpochResponseTopic) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *EndQuorumEpochResponseTopic) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type EndQuorumEpochResponse struct {
 	// Version is the version of this message used with a Kafka broker.
 	Version int16
@@ -38210,10 +40872,21 @@ func NewEndQuorumEpochResponse() EndQuorumEpochResponse {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v EndQuorumEpochResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *EndQuorumEpochResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // A common struct used in DescribeQuorumResponse.
 type DescribeQuorumResponseTopicPartitionReplicaState struct {
 	ReplicaID int32
 
+	// The directory ID of the replica.
+	ReplicaDirectoryID [16]byte // v2+
+
 	// The last known log end offset of the follower, or -1 if it is unknown.
 	LogEndOffset int64
 
@@ -38249,6 +40922,18 @@ func NewDescribeQuorumResponseTopicPartitionReplicaState() DescribeQuorumRespons
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DescribeQuorumResponseTopicPartitionReplicaState) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DescribeQuorumResponseTopicPartitionReplicaState) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type DescribeQuorumRequestTopicPartition struct {
 	Partition int32
 
@@ -38269,6 +40954,16 @@ func NewDescribeQuorumRequestTopicPartition() DescribeQuorumRequestTopicPartitio
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DescribeQuorumRequestTopicPartition) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DescribeQuorumRequestTopicPartition) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type DescribeQuorumRequestTopic struct {
 	Topic string
 
@@ -38291,6 +40986,14 @@ func NewDescribeQuorumRequestTopic() DescribeQuorumRequestTopic {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DescribeQuorumRequestTopic) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DescribeQuorumRequestTopic) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // Part of KIP-642 (and KIP-595) to replace Kafka's dependence on Zookeeper with a
 // Kafka-only raft protocol,
 // DescribeQuorumRequest is sent by a leader to describe the quorum.
@@ -38305,7 +41008,7 @@ type DescribeQuorumRequest struct {
 }
 
 func (*DescribeQuorumRequest) Key() int16                 { return 55 }
-func (*DescribeQuorumRequest) MaxVersion() int16          { return 1 }
+func (*DescribeQuorumRequest) MaxVersion() int16          { return 2 }
 func (v *DescribeQuorumRequest) SetVersion(version int16) { v.Version = version }
 func (v *DescribeQuorumRequest) GetVersion() int16        { return v.Version }
 func (v *DescribeQuorumRequest) IsFlexible() bool         { return v.Version >= 0 }
@@ -38497,11 +41200,22 @@ func NewDescribeQuorumRequest() DescribeQuorumRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DescribeQuorumRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DescribeQuorumRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type DescribeQuorumResponseTopicPartition struct {
 	Partition int32
 
 	ErrorCode int16
 
+	// The error message, or null if there was no error.
+	ErrorMessage *string // v2+
+
 	// The ID of the current leader, or -1 if the leader is unknown.
 	LeaderID int32
 
@@ -38531,6 +41245,16 @@ func NewDescribeQuorumResponseTopicPartition() DescribeQuorumResponseTopicPartit
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DescribeQuorumResponseTopicPartition) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DescribeQuorumResponseTopicPartition) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type DescribeQuorumResponseTopic struct {
 	Topic string
 
@@ -38553,20 +41277,103 @@ func NewDescribeQuorumResponseTopic() DescribeQuorumResponseTopic {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DescribeQuorumResponseTopic) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DescribeQuorumResponseTopic) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
+type DescribeQuorumResponseNodeListener struct {
+	// The name of the endpoint.
+	Name string
+
+	// The hostname.
+	Host string
+
+	// The port.
+	Port uint16
+
+	// UnknownTags are tags Kafka sent that we do not know the purpose of.
+	UnknownTags Tags
+}
+
+// Default sets any default fields. Calling this allows for future compatibility
+// if new fields are added to DescribeQuorumResponseNodeListener.
+func (v *DescribeQuorumResponseNodeListener) Default() {
+}
+
+// NewDescribeQuorumResponseNodeListener returns a default DescribeQuorumResponseNodeListener
+// This is a shortcut for creating a struct and calling Default yourself.
+func NewDescribeQuorumResponseNodeListener() DescribeQuorumResponseNodeListener {
+	var v DescribeQuorumResponseNodeListener
+	v.Default()
+	return v
+}
+
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DescribeQuorumResponseNodeListener) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DescribeQuorumResponseNodeListener) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
+type DescribeQuorumResponseNode struct {
+	// The ID of the associated node.
+	NodeID int32
+
+	// The listeners of this controller.
+	Listeners []DescribeQuorumResponseNodeListener
+
+	// UnknownTags are tags Kafka sent that we do not know the purpose of.
+	UnknownTags Tags
+}
+
+// Default sets any default fields. Calling this allows for future compatibility
+// if new fields are added to DescribeQuorumResponseNode.
+func (v *DescribeQuorumResponseNode) Default() {
+}
+
+// NewDescribeQuorumResponseNode returns a default DescribeQuorumResponseNode
+// This is a shortcut for creating a struct and calling Default yourself.
+func NewDescribeQuorumResponseNode() DescribeQuorumResponseNode {
+	var v DescribeQuorumResponseNode
+	v.Default()
+	return v
+}
+
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DescribeQuorumResponseNode) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DescribeQuorumResponseNode) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type DescribeQuorumResponse struct {
 	// Version is the version of this message used with a Kafka broker.
 	Version int16
 
 	ErrorCode int16
 
+	// The error message, or null if there was no error.
+	ErrorMessage *string // v2+
+
 	Topics []DescribeQuorumResponseTopic
 
+	// The endpoints for the voters in the quorum.
+	Nodes []DescribeQuorumResponseNode // v2+
+
 	// UnknownTags are tags Kafka sent that we do not know the purpose of.
 	UnknownTags Tags
 }
 
 func (*DescribeQuorumResponse) Key() int16                 { return 55 }
-func (*DescribeQuorumResponse) MaxVersion() int16          { return 1 }
+func (*DescribeQuorumResponse) MaxVersion() int16          { return 2 }
 func (v *DescribeQuorumResponse) SetVersion(version int16) { v.Version = version }
 func (v *DescribeQuorumResponse) GetVersion() int16        { return v.Version }
 func (v *DescribeQuorumResponse) IsFlexible() bool         { return v.Version >= 0 }
@@ -38583,6 +41390,14 @@ func (v *DescribeQuorumResponse) AppendTo(dst []byte) []byte {
 		v := v.ErrorCode
 		dst = kbin.AppendInt16(dst, v)
 	}
+	if version >= 2 {
+		v := v.ErrorMessage
+		if isFlexible {
+			dst = kbin.AppendCompactNullableString(dst, v)
+		} else {
+			dst = kbin.AppendNullableString(dst, v)
+		}
+	}
 	{
 		v := v.Topics
 		if isFlexible {
@@ -38617,6 +41432,14 @@ func (v *DescribeQuorumResponse) AppendTo(dst []byte) []byte {
 						v := v.ErrorCode
 						dst = kbin.AppendInt16(dst, v)
 					}
+					if version >= 2 {
+						v := v.ErrorMessage
+						if isFlexible {
+							dst = kbin.AppendCompactNullableString(dst, v)
+						} else {
+							dst = kbin.AppendNullableString(dst, v)
+						}
+					}
 					{
 						v := v.LeaderID
 						dst = kbin.AppendInt32(dst, v)
@@ -38642,6 +41465,10 @@ func (v *DescribeQuorumResponse) AppendTo(dst []byte) []byte {
 								v := v.ReplicaID
 								dst = kbin.AppendInt32(dst, v)
 							}
+							if version >= 2 {
+								v := v.ReplicaDirectoryID
+								dst = kbin.AppendUuid(dst, v)
+							}
 							{
 								v := v.LogEndOffset
 								dst = kbin.AppendInt64(dst, v)
@@ -38673,6 +41500,10 @@ func (v *DescribeQuorumResponse) AppendTo(dst []byte) []byte {
 								v := v.ReplicaID
 								dst = kbin.AppendInt32(dst, v)
 							}
+							if version >= 2 {
+								v := v.ReplicaDirectoryID
+								dst = kbin.AppendUuid(dst, v)
+							}
 							{
 								v := v.LogEndOffset
 								dst = kbin.AppendInt64(dst, v)
@@ -38703,6 +41534,60 @@ func (v *DescribeQuorumResponse) AppendTo(dst []byte) []byte {
 			}
 		}
 	}
+	if version >= 2 {
+		v := v.Nodes
+		if isFlexible {
+			dst = kbin.AppendCompactArrayLen(dst, len(v))
+		} else {
+			dst = kbin.AppendArrayLen(dst, len(v))
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.NodeID
+				dst = kbin.AppendInt32(dst, v)
+			}
+			{
+				v := v.Listeners
+				if isFlexible {
+					dst = kbin.AppendCompactArrayLen(dst, len(v))
+				} else {
+					dst = kbin.AppendArrayLen(dst, len(v))
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						v := v.Name
+						if isFlexible {
+							dst = kbin.AppendCompactString(dst, v)
+						} else {
+							dst = kbin.AppendString(dst, v)
+						}
+					}
+					{
+						v := v.Host
+						if isFlexible {
+							dst = kbin.AppendCompactString(dst, v)
+						} else {
+							dst = kbin.AppendString(dst, v)
+						}
+					}
+					{
+						v := v.Port
+						dst = kbin.AppendUint16(dst, v)
+					}
+					if isFlexible {
+						dst = kbin.AppendUvarint(dst, 0+uint32(v.UnknownTags.Len()))
+						dst = v.UnknownTags.AppendEach(dst)
+					}
+				}
+			}
+			if isFlexible {
+				dst = kbin.AppendUvarint(dst, 0+uint32(v.UnknownTags.Len()))
+				dst = v.UnknownTags.AppendEach(dst)
+			}
+		}
+	}
 	if isFlexible {
 		dst = kbin.AppendUvarint(dst, 0+uint32(v.UnknownTags.Len()))
 		dst = v.UnknownTags.AppendEach(dst)
@@ -38730,6 +41615,23 @@ func (v *DescribeQuorumResponse) readFrom(src []byte, unsafe bool) error {
 		v := b.Int16()
 		s.ErrorCode = v
 	}
+	if version >= 2 {
+		var v *string
+		if isFlexible {
+			if unsafe {
+				v = b.UnsafeCompactNullableString()
+			} else {
+				v = b.CompactNullableString()
+			}
+		} else {
+			if unsafe {
+				v = b.UnsafeNullableString()
+			} else {
+				v = b.NullableString()
+			}
+		}
+		s.ErrorMessage = v
+	}
 	{
 		v := s.Topics
 		a := v
@@ -38795,6 +41697,23 @@ func (v *DescribeQuorumResponse) readFrom(src []byte, unsafe bool) error {
 						v := b.Int16()
 						s.ErrorCode = v
 					}
+					if version >= 2 {
+						var v *string
+						if isFlexible {
+							if unsafe {
+								v = b.UnsafeCompactNullableString()
+							} else {
+								v = b.CompactNullableString()
+							}
+						} else {
+							if unsafe {
+								v = b.UnsafeNullableString()
+							} else {
+								v = b.NullableString()
+							}
+						}
+						s.ErrorMessage = v
+					}
 					{
 						v := b.Int32()
 						s.LeaderID = v
@@ -38831,6 +41750,10 @@ func (v *DescribeQuorumResponse) readFrom(src []byte, unsafe bool) error {
 								v := b.Int32()
 								s.ReplicaID = v
 							}
+							if version >= 2 {
+								v := b.Uuid()
+								s.ReplicaDirectoryID = v
+							}
 							{
 								v := b.Int64()
 								s.LogEndOffset = v
@@ -38874,6 +41797,10 @@ func (v *DescribeQuorumResponse) readFrom(src []byte, unsafe bool) error {
 								v := b.Int32()
 								s.ReplicaID = v
 							}
+							if version >= 2 {
+								v := b.Uuid()
+								s.ReplicaDirectoryID = v
+							}
 							{
 								v := b.Int64()
 								s.LogEndOffset = v
@@ -38907,6 +41834,102 @@ func (v *DescribeQuorumResponse) readFrom(src []byte, unsafe bool) error {
 		v = a
 		s.Topics = v
 	}
+	if version >= 2 {
+		v := s.Nodes
+		a := v
+		var l int32
+		if isFlexible {
+			l = b.CompactArrayLen()
+		} else {
+			l = b.ArrayLen()
+		}
+		if !b.Ok() {
+			return b.Complete()
+		}
+		a = a[:0]
+		if l > 0 {
+			a = append(a, make([]DescribeQuorumResponseNode, l)...)
+		}
+		for i := int32(0); i < l; i++ {
+			v := &a[i]
+			v.Default()
+			s := v
+			{
+				v := b.Int32()
+				s.NodeID = v
+			}
+			{
+				v := s.Listeners
+				a := v
+				var l int32
+				if isFlexible {
+					l = b.CompactArrayLen()
+				} else {
+					l = b.ArrayLen()
+				}
+				if !b.Ok() {
+					return b.Complete()
+				}
+				a = a[:0]
+				if l > 0 {
+					a = append(a, make([]DescribeQuorumResponseNodeListener, l)...)
+				}
+				for i := int32(0); i < l; i++ {
+					v := &a[i]
+					v.Default()
+					s := v
+					{
+						var v string
+						if unsafe {
+							if isFlexible {
+								v = b.UnsafeCompactString()
+							} else {
+								v = b.UnsafeString()
+							}
+						} else {
+							if isFlexible {
+								v = b.CompactString()
+							} else {
+								v = b.String()
+							}
+						}
+						s.Name = v
+					}
+					{
+						var v string
+						if unsafe {
+							if isFlexible {
+								v = b.UnsafeCompactString()
+							} else {
+								v = b.UnsafeString()
+							}
+						} else {
+							if isFlexible {
+								v = b.CompactString()
+							} else {
+								v = b.String()
+							}
+						}
+						s.Host = v
+					}
+					{
+						v := b.Uint16()
+						s.Port = v
+					}
+					if isFlexible {
+						s.UnknownTags = internalReadTags(&b)
+					}
+				}
+				v = a
+				s.Listeners = v
+			}
+			if isFlexible {
+				s.UnknownTags = internalReadTags(&b)
+			}
+		}
+		v = a
+		s.Nodes = v
+	}
 	if isFlexible {
 		s.UnknownTags = internalReadTags(&b)
 	}
@@ -38934,6 +41957,14 @@ func NewDescribeQuorumResponse() DescribeQuorumResponse {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DescribeQuorumResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DescribeQuorumResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type AlterPartitionRequestTopicPartitionNewEpochISR struct {
 	// The broker ID .
 	BrokerID int32
@@ -38961,6 +41992,18 @@ func NewAlterPartitionRequestTopicPartitionNewEpochISR() AlterPartitionRequestTo
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v AlterPartitionRequestTopicPartitionNewEpochISR) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *AlterPartitionRequestTopicPartitionNewEpochISR) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type AlterPartitionRequestTopicPartition struct {
 	Partition int32
 
@@ -38996,6 +42039,16 @@ func NewAlterPartitionRequestTopicPartition() AlterPartitionRequestTopicPartitio
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v AlterPartitionRequestTopicPartition) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *AlterPartitionRequestTopicPartition) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type AlterPartitionRequestTopic struct {
 	Topic string // v0-v1
 
@@ -39020,6 +42073,14 @@ func NewAlterPartitionRequestTopic() AlterPartitionRequestTopic {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v AlterPartitionRequestTopic) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *AlterPartitionRequestTopic) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // AlterPartitionRequest, proposed in KIP-497 and introduced in Kafka 2.7.0,
 // is an admin request to modify ISR.
 //
@@ -39377,6 +42438,14 @@ func NewAlterPartitionRequest() AlterPartitionRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v AlterPartitionRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *AlterPartitionRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type AlterPartitionResponseTopicPartition struct {
 	Partition int32
 
@@ -39415,6 +42484,16 @@ func NewAlterPartitionResponseTopicPartition() AlterPartitionResponseTopicPartit
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v AlterPartitionResponseTopicPartition) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *AlterPartitionResponseTopicPartition) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type AlterPartitionResponseTopic struct {
 	Topic string // v0-v1
 
@@ -39439,6 +42518,14 @@ func NewAlterPartitionResponseTopic() AlterPartitionResponseTopic {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v AlterPartitionResponseTopic) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *AlterPartitionResponseTopic) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type AlterPartitionResponse struct {
 	// Version is the version of this message used with a Kafka broker.
 	Version int16
@@ -39737,6 +42824,14 @@ func NewAlterPartitionResponse() AlterPartitionResponse {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v AlterPartitionResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *AlterPartitionResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type UpdateFeaturesRequestFeatureUpdate struct {
 	// The name of the finalized feature to update.
 	Feature string
@@ -39776,6 +42871,16 @@ func NewUpdateFeaturesRequestFeatureUpdate() UpdateFeaturesRequestFeatureUpdate
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v UpdateFeaturesRequestFeatureUpdate) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *UpdateFeaturesRequestFeatureUpdate) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 // From KIP-584 and introduced in 2.7.0, this request updates broker-wide features.
 type UpdateFeaturesRequest struct {
 	// Version is the version of this message used with a Kafka broker.
@@ -39986,6 +43091,14 @@ func NewUpdateFeaturesRequest() UpdateFeaturesRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v UpdateFeaturesRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *UpdateFeaturesRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type UpdateFeaturesResponseResult struct {
 	// The name of the finalized feature.
 	Feature string
@@ -40013,6 +43126,14 @@ func NewUpdateFeaturesResponseResult() UpdateFeaturesResponseResult {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v UpdateFeaturesResponseResult) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *UpdateFeaturesResponseResult) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type UpdateFeaturesResponse struct {
 	// Version is the version of this message used with a Kafka broker.
 	Version int16
@@ -40241,6 +43362,14 @@ func NewUpdateFeaturesResponse() UpdateFeaturesResponse {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v UpdateFeaturesResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *UpdateFeaturesResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // Introduced for KIP-590, EnvelopeRequest is what brokers use to wrap an
 // incoming request before forwarding it to another broker.
 type EnvelopeRequest struct {
@@ -40387,6 +43516,14 @@ func NewEnvelopeRequest() EnvelopeRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v EnvelopeRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *EnvelopeRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type EnvelopeResponse struct {
 	// Version is the version of this message used with a Kafka broker.
 	Version int16
@@ -40492,6 +43629,14 @@ func NewEnvelopeResponse() EnvelopeResponse {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v EnvelopeResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *EnvelopeResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type FetchSnapshotRequestTopicPartitionSnapshotID struct {
 	EndOffset int64
 
@@ -40514,6 +43659,18 @@ func NewFetchSnapshotRequestTopicPartitionSnapshotID() FetchSnapshotRequestTopic
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v FetchSnapshotRequestTopicPartitionSnapshotID) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *FetchSnapshotRequestTopicPartitionSnapshotID) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type FetchSnapshotRequestTopicPartition struct {
 	// The partition to fetch.
 	Partition int32
@@ -40548,6 +43705,16 @@ func NewFetchSnapshotRequestTopicPartition() FetchSnapshotRequestTopicPartition
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v FetchSnapshotRequestTopicPartition) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *FetchSnapshotRequestTopicPartition) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type FetchSnapshotRequestTopic struct {
 	// The name of the topic to fetch.
 	Topic string
@@ -40572,6 +43739,14 @@ func NewFetchSnapshotRequestTopic() FetchSnapshotRequestTopic {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v FetchSnapshotRequestTopic) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *FetchSnapshotRequestTopic) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // Introduced for KIP-630, FetchSnapshotRequest is a part of the inter-Kafka
 // raft protocol to remove the dependency on Zookeeper.
 type FetchSnapshotRequest struct {
@@ -40907,6 +44082,14 @@ func NewFetchSnapshotRequest() FetchSnapshotRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v FetchSnapshotRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *FetchSnapshotRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type FetchSnapshotResponseTopicPartitionSnapshotID struct {
 	EndOffset int64
 
@@ -40929,6 +44112,18 @@ func NewFetchSnapshotResponseTopicPartitionSnapshotID() FetchSnapshotResponseTop
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v FetchSnapshotResponseTopicPartitionSnapshotID) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *FetchSnapshotResponseTopicPartitionSnapshotID) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type FetchSnapshotResponseTopicPartitionCurrentLeader struct {
 	LeaderID int32
 
@@ -40951,6 +44146,18 @@ func NewFetchSnapshotResponseTopicPartitionCurrentLeader() FetchSnapshotResponse
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v FetchSnapshotResponseTopicPartitionCurrentLeader) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *FetchSnapshotResponseTopicPartitionCurrentLeader) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type FetchSnapshotResponseTopicPartition struct {
 	// The partition.
 	Partition int32
@@ -41000,6 +44207,16 @@ func NewFetchSnapshotResponseTopicPartition() FetchSnapshotResponseTopicPartitio
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v FetchSnapshotResponseTopicPartition) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *FetchSnapshotResponseTopicPartition) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type FetchSnapshotResponseTopic struct {
 	// The name of the topic to fetch.
 	Topic string
@@ -41024,6 +44241,14 @@ func NewFetchSnapshotResponseTopic() FetchSnapshotResponseTopic {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v FetchSnapshotResponseTopic) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *FetchSnapshotResponseTopic) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // FetchSnapshotResponse is a response for a FetchSnapshotRequest.
 type FetchSnapshotResponse struct {
 	// Version is the version of this message used with a Kafka broker.
@@ -41375,6 +44600,14 @@ func NewFetchSnapshotResponse() FetchSnapshotResponse {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v FetchSnapshotResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *FetchSnapshotResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // Introduced for KIP-700, DescribeClusterRequest is effectively an "admin"
 // type metadata request for information that producers or consumers do not
 // need to care about.
@@ -41487,6 +44720,14 @@ func NewDescribeClusterRequest() DescribeClusterRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DescribeClusterRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DescribeClusterRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type DescribeClusterResponseBroker struct {
 	// NodeID is the node ID of a Kafka broker.
 	NodeID int32
@@ -41517,6 +44758,14 @@ func NewDescribeClusterResponseBroker() DescribeClusterResponseBroker {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DescribeClusterResponseBroker) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DescribeClusterResponseBroker) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // DescribeClusterResponse is a response to a DescribeClusterRequest.
 type DescribeClusterResponse struct {
 	// Version is the version of this message used with a Kafka broker.
@@ -41827,6 +45076,14 @@ func NewDescribeClusterResponse() DescribeClusterResponse {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DescribeClusterResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DescribeClusterResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type DescribeProducersRequestTopic struct {
 	Topic string
 
@@ -41850,6 +45107,14 @@ func NewDescribeProducersRequestTopic() DescribeProducersRequestTopic {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DescribeProducersRequestTopic) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DescribeProducersRequestTopic) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // Introduced for KIP-664, DescribeProducersRequest allows for introspecting
 // the state of the transaction coordinator. This request can be used to detect
 // hanging transactions or other EOS-related problems.
@@ -42044,6 +45309,14 @@ func NewDescribeProducersRequest() DescribeProducersRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DescribeProducersRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DescribeProducersRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type DescribeProducersResponseTopicPartitionActiveProducer struct {
 	ProducerID int64
 
@@ -42087,6 +45360,18 @@ func NewDescribeProducersResponseTopicPartitionActiveProducer() DescribeProducer
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DescribeProducersResponseTopicPartitionActiveProducer) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DescribeProducersResponseTopicPartitionActiveProducer) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type DescribeProducersResponseTopicPartition struct {
 	Partition int32
 
@@ -42127,6 +45412,18 @@ func NewDescribeProducersResponseTopicPartition() DescribeProducersResponseTopic
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DescribeProducersResponseTopicPartition) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DescribeProducersResponseTopicPartition) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type DescribeProducersResponseTopic struct {
 	Topic string
 
@@ -42149,6 +45446,14 @@ func NewDescribeProducersResponseTopic() DescribeProducersResponseTopic {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DescribeProducersResponseTopic) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DescribeProducersResponseTopic) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // DescribeProducersResponse is a response to a DescribeProducersRequest.
 type DescribeProducersResponse struct {
 	// Version is the version of this message used with a Kafka broker.
@@ -42481,6 +45786,14 @@ func NewDescribeProducersResponse() DescribeProducersResponse {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DescribeProducersResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DescribeProducersResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type BrokerRegistrationRequestListener struct {
 	// The name of this endpoint.
 	Name string
@@ -42511,6 +45824,14 @@ func NewBrokerRegistrationRequestListener() BrokerRegistrationRequestListener {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v BrokerRegistrationRequestListener) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *BrokerRegistrationRequestListener) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type BrokerRegistrationRequestFeature struct {
 	// The name of the feature.
 	Name string
@@ -42538,6 +45859,14 @@ func NewBrokerRegistrationRequestFeature() BrokerRegistrationRequestFeature {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v BrokerRegistrationRequestFeature) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *BrokerRegistrationRequestFeature) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // For KIP-500 / KIP-631, BrokerRegistrationRequest is an internal
 // broker-to-broker only request.
 type BrokerRegistrationRequest struct {
@@ -42910,6 +46239,14 @@ func NewBrokerRegistrationRequest() BrokerRegistrationRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v BrokerRegistrationRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *BrokerRegistrationRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // BrokerRegistrationResponse is a response to a BrokerRegistrationRequest.
 type BrokerRegistrationResponse struct {
 	// Version is the version of this message used with a Kafka broker.
@@ -43028,6 +46365,14 @@ func NewBrokerRegistrationResponse() BrokerRegistrationResponse {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v BrokerRegistrationResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *BrokerRegistrationResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // For KIP-500 / KIP-631, BrokerHeartbeatRequest is an internal
 // broker-to-broker only request.
 type BrokerHeartbeatRequest struct {
@@ -43171,6 +46516,14 @@ func NewBrokerHeartbeatRequest() BrokerHeartbeatRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v BrokerHeartbeatRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *BrokerHeartbeatRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // BrokerHeartbeatResponse is a response to a BrokerHeartbeatRequest.
 type BrokerHeartbeatResponse struct {
 	// Version is the version of this message used with a Kafka broker.
@@ -43308,6 +46661,14 @@ func NewBrokerHeartbeatResponse() BrokerHeartbeatResponse {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v BrokerHeartbeatResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *BrokerHeartbeatResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // For KIP-500 / KIP-631, UnregisterBrokerRequest is an admin request to
 // remove registration of a broker from the cluster.
 type UnregisterBrokerRequest struct {
@@ -43404,6 +46765,14 @@ func NewUnregisterBrokerRequest() UnregisterBrokerRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v UnregisterBrokerRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *UnregisterBrokerRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // UnregisterBrokerResponse is a response to a UnregisterBrokerRequest.
 type UnregisterBrokerResponse struct {
 	// Version is the version of this message used with a Kafka broker.
@@ -43533,6 +46902,14 @@ func NewUnregisterBrokerResponse() UnregisterBrokerResponse {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v UnregisterBrokerResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *UnregisterBrokerResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // For KIP-664, DescribeTransactionsRequest describes the state of transactions.
 type DescribeTransactionsRequest struct {
 	// Version is the version of this message used with a Kafka broker.
@@ -43673,6 +47050,14 @@ func NewDescribeTransactionsRequest() DescribeTransactionsRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DescribeTransactionsRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DescribeTransactionsRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type DescribeTransactionsResponseTransactionStateTopic struct {
 	Topic string
 
@@ -43695,6 +47080,18 @@ func NewDescribeTransactionsResponseTransactionStateTopic() DescribeTransactions
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DescribeTransactionsResponseTransactionStateTopic) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DescribeTransactionsResponseTransactionStateTopic) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type DescribeTransactionsResponseTransactionState struct {
 	// A potential error code for describing this transaction.
 	//
@@ -43753,6 +47150,18 @@ func NewDescribeTransactionsResponseTransactionState() DescribeTransactionsRespo
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DescribeTransactionsResponseTransactionState) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DescribeTransactionsResponseTransactionState) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 // DescribeTransactionsResponse is a response to a DescribeTransactionsRequest.
 type DescribeTransactionsResponse struct {
 	// Version is the version of this message used with a Kafka broker.
@@ -44082,6 +47491,14 @@ func NewDescribeTransactionsResponse() DescribeTransactionsResponse {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DescribeTransactionsResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DescribeTransactionsResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // For KIP-664, ListTransactionsRequest lists transactions.
 type ListTransactionsRequest struct {
 	// Version is the version of this message used with a Kafka broker.
@@ -44281,6 +47698,14 @@ func NewListTransactionsRequest() ListTransactionsRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ListTransactionsRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ListTransactionsRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type ListTransactionsResponseTransactionState struct {
 	// The transactional ID being used.
 	TransactionalID string
@@ -44308,6 +47733,18 @@ func NewListTransactionsResponseTransactionState() ListTransactionsResponseTrans
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ListTransactionsResponseTransactionState) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ListTransactionsResponseTransactionState) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 // ListTransactionsResponse is a response to a ListTransactionsRequest.
 type ListTransactionsResponse struct {
 	// Version is the version of this message used with a Kafka broker.
@@ -44575,6 +48012,14 @@ func NewListTransactionsResponse() ListTransactionsResponse {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ListTransactionsResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ListTransactionsResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // For KIP-730, AllocateProducerIDsRequest is a broker-to-broker request that
 // requests a block of producer IDs from the controller broker. This is more
 // specifically introduced for raft, but allows for one more request to avoid
@@ -44687,6 +48132,14 @@ func NewAllocateProducerIDsRequest() AllocateProducerIDsRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v AllocateProducerIDsRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *AllocateProducerIDsRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // AllocateProducerIDsResponse is a response to an AllocateProducerIDsRequest.
 type AllocateProducerIDsResponse struct {
 	// Version is the version of this message used with a Kafka broker.
@@ -44813,6 +48266,14 @@ func NewAllocateProducerIDsResponse() AllocateProducerIDsResponse {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v AllocateProducerIDsResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *AllocateProducerIDsResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type ConsumerGroupHeartbeatRequestTopic struct {
 	// The topic ID.
 	TopicID [16]byte
@@ -44837,6 +48298,16 @@ func NewConsumerGroupHeartbeatRequestTopic() ConsumerGroupHeartbeatRequestTopic
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ConsumerGroupHeartbeatRequestTopic) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ConsumerGroupHeartbeatRequestTopic) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 // ConsumerGroupHeartbeat is a part of KIP-848; there are a lot of details
 // to this request so documentation is left to the KIP itself.
 type ConsumerGroupHeartbeatRequest struct {
@@ -44869,6 +48340,9 @@ type ConsumerGroupHeartbeatRequest struct {
 	// Subscribed topics; null if unchanging.
 	SubscribedTopicNames []string
 
+	// Subscribed topic regex; null if unchanging.
+	SubscribedTopicRegex *string // v1+
+
 	// The server side assignor to use; null if unchanging.
 	ServerAssignor *string
 
@@ -44879,11 +48353,12 @@ type ConsumerGroupHeartbeatRequest struct {
 	UnknownTags Tags
 }
 
-func (*ConsumerGroupHeartbeatRequest) Key() int16                 { return 68 }
-func (*ConsumerGroupHeartbeatRequest) MaxVersion() int16          { return 0 }
-func (v *ConsumerGroupHeartbeatRequest) SetVersion(version int16) { v.Version = version }
-func (v *ConsumerGroupHeartbeatRequest) GetVersion() int16        { return v.Version }
-func (v *ConsumerGroupHeartbeatRequest) IsFlexible() bool         { return v.Version >= 0 }
+func (*ConsumerGroupHeartbeatRequest) Key() int16                   { return 68 }
+func (*ConsumerGroupHeartbeatRequest) MaxVersion() int16            { return 1 }
+func (v *ConsumerGroupHeartbeatRequest) SetVersion(version int16)   { v.Version = version }
+func (v *ConsumerGroupHeartbeatRequest) GetVersion() int16          { return v.Version }
+func (v *ConsumerGroupHeartbeatRequest) IsFlexible() bool           { return v.Version >= 0 }
+func (v *ConsumerGroupHeartbeatRequest) IsGroupCoordinatorRequest() {}
 func (v *ConsumerGroupHeartbeatRequest) ResponseKind() Response {
 	r := &ConsumerGroupHeartbeatResponse{Version: v.Version}
 	r.Default()
@@ -44960,6 +48435,14 @@ func (v *ConsumerGroupHeartbeatRequest) AppendTo(dst []byte) []byte {
 			}
 		}
 	}
+	if version >= 1 {
+		v := v.SubscribedTopicRegex
+		if isFlexible {
+			dst = kbin.AppendCompactNullableString(dst, v)
+		} else {
+			dst = kbin.AppendNullableString(dst, v)
+		}
+	}
 	{
 		v := v.ServerAssignor
 		if isFlexible {
@@ -45137,6 +48620,23 @@ func (v *ConsumerGroupHeartbeatRequest) readFrom(src []byte, unsafe bool) error
 		v = a
 		s.SubscribedTopicNames = v
 	}
+	if version >= 1 {
+		var v *string
+		if isFlexible {
+			if unsafe {
+				v = b.UnsafeCompactNullableString()
+			} else {
+				v = b.CompactNullableString()
+			}
+		} else {
+			if unsafe {
+				v = b.UnsafeNullableString()
+			} else {
+				v = b.NullableString()
+			}
+		}
+		s.SubscribedTopicRegex = v
+	}
 	{
 		var v *string
 		if isFlexible {
@@ -45239,6 +48739,14 @@ func NewConsumerGroupHeartbeatRequest() ConsumerGroupHeartbeatRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ConsumerGroupHeartbeatRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ConsumerGroupHeartbeatRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type ConsumerGroupHeartbeatResponseAssignmentTopic struct {
 	TopicID [16]byte
 
@@ -45261,6 +48769,18 @@ func NewConsumerGroupHeartbeatResponseAssignmentTopic() ConsumerGroupHeartbeatRe
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ConsumerGroupHeartbeatResponseAssignmentTopic) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ConsumerGroupHeartbeatResponseAssignmentTopic) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type ConsumerGroupHeartbeatResponseAssignment struct {
 	// The topics partitions that can be used immediately.
 	Topics []ConsumerGroupHeartbeatResponseAssignmentTopic
@@ -45282,6 +48802,18 @@ func NewConsumerGroupHeartbeatResponseAssignment() ConsumerGroupHeartbeatRespons
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ConsumerGroupHeartbeatResponseAssignment) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ConsumerGroupHeartbeatResponseAssignment) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 // ConsumerGroupHeartbeatResponse is returned from a ConsumerGroupHeartbeatRequest.
 type ConsumerGroupHeartbeatResponse struct {
 	// Version is the version of this message used with a Kafka broker.
@@ -45304,6 +48836,7 @@ type ConsumerGroupHeartbeatResponse struct {
 	// - UNSUPPORTED_ASSIGNOR (version 0+)
 	// - UNRELEASED_INSTANCE_ID (version 0+)
 	// - GROUP_MAX_SIZE_REACHED (version 0+)
+	// - INVALID_REGULAR_EXPRESSION (version 1+)
 	ErrorCode int16
 
 	// A supplementary message if this errored.
@@ -45327,7 +48860,7 @@ type ConsumerGroupHeartbeatResponse struct {
 }
 
 func (*ConsumerGroupHeartbeatResponse) Key() int16                 { return 68 }
-func (*ConsumerGroupHeartbeatResponse) MaxVersion() int16          { return 0 }
+func (*ConsumerGroupHeartbeatResponse) MaxVersion() int16          { return 1 }
 func (v *ConsumerGroupHeartbeatResponse) SetVersion(version int16) { v.Version = version }
 func (v *ConsumerGroupHeartbeatResponse) GetVersion() int16        { return v.Version }
 func (v *ConsumerGroupHeartbeatResponse) IsFlexible() bool         { return v.Version >= 0 }
@@ -45592,6 +49125,14 @@ func NewConsumerGroupHeartbeatResponse() ConsumerGroupHeartbeatResponse {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ConsumerGroupHeartbeatResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ConsumerGroupHeartbeatResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // Assignment contains consumer group assignments.
 type Assignment struct {
 	// The topics & partitions assigned to the member.
@@ -45614,6 +49155,14 @@ func NewAssignment() Assignment {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v Assignment) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *Assignment) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // ConsumerGroupDescribe is a part of KIP-848; this is the
 // "next generation" equivalent of DescribeGroups.
 type ConsumerGroupDescribeRequest struct {
@@ -45765,6 +49314,14 @@ func NewConsumerGroupDescribeRequest() ConsumerGroupDescribeRequest {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ConsumerGroupDescribeRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ConsumerGroupDescribeRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 type ConsumerGroupDescribeResponseGroupMember struct {
 	// The member ID.
 	MemberID string
@@ -45821,6 +49378,18 @@ func NewConsumerGroupDescribeResponseGroupMember() ConsumerGroupDescribeResponse
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ConsumerGroupDescribeResponseGroupMember) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ConsumerGroupDescribeResponseGroupMember) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 type ConsumerGroupDescribeResponseGroup struct {
 	// ErrorCode is the error for this response.
 	//
@@ -45878,6 +49447,16 @@ func NewConsumerGroupDescribeResponseGroup() ConsumerGroupDescribeResponseGroup
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ConsumerGroupDescribeResponseGroup) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ConsumerGroupDescribeResponseGroup) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
 // ConsumerGroupDescribeResponse is returned from a ConsumerGroupDescribeRequest.
 type ConsumerGroupDescribeResponse struct {
 	// Version is the version of this message used with a Kafka broker.
@@ -46649,6 +50228,6942 @@ func NewConsumerGroupDescribeResponse() ConsumerGroupDescribeResponse {
 	return v
 }
 
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ConsumerGroupDescribeResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ConsumerGroupDescribeResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
+// GetTelemetrySubscriptionsRequest, introduced in KIP-714, is sent by a client
+// to learn which metrics the broker would like the client to push.
+type GetTelemetrySubscriptionsRequest struct {
+	// Version is the version of this message used with a Kafka broker.
+	Version int16
+
+	// The unique identifier for the client instance. This is all zeroes on the
+	// first request, in which case the broker generates an ID.
+	ClientInstanceID [16]byte
+
+	// UnknownTags are tags Kafka sent that we do not know the purpose of.
+	UnknownTags Tags
+}
+
+func (*GetTelemetrySubscriptionsRequest) Key() int16                 { return 71 }
+func (*GetTelemetrySubscriptionsRequest) MaxVersion() int16          { return 0 }
+func (v *GetTelemetrySubscriptionsRequest) SetVersion(version int16) { v.Version = version }
+func (v *GetTelemetrySubscriptionsRequest) GetVersion() int16        { return v.Version }
+func (v *GetTelemetrySubscriptionsRequest) IsFlexible() bool         { return v.Version >= 0 }
+func (v *GetTelemetrySubscriptionsRequest) ResponseKind() Response {
+	r := &GetTelemetrySubscriptionsResponse{Version: v.Version}
+	r.Default()
+	return r
+}
+
+// RequestWith is requests v on r and returns the response or an error.
+// For sharded requests, the response may be merged and still return an error.
+// It is better to rely on client.RequestSharded than to rely on proper merging behavior.
+func (v *GetTelemetrySubscriptionsRequest) RequestWith(ctx context.Context, r Requestor) (*GetTelemetrySubscriptionsResponse, error) {
+	kresp, err := r.Request(ctx, v)
+	resp, _ := kresp.(*GetTelemetrySubscriptionsResponse)
+	return resp, err
+}
+
+func (v *GetTelemetrySubscriptionsRequest) AppendTo(dst []byte) []byte {
+	version := v.Version
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	{
+		v := v.ClientInstanceID
+		dst = kbin.AppendUuid(dst, v)
+	}
+	if isFlexible {
+		dst = kbin.AppendUvarint(dst, 0+uint32(v.UnknownTags.Len()))
+		dst = v.UnknownTags.AppendEach(dst)
+	}
+	return dst
+}
+
+func (v *GetTelemetrySubscriptionsRequest) ReadFrom(src []byte) error {
+	return v.readFrom(src, false)
+}
+
+func (v *GetTelemetrySubscriptionsRequest) UnsafeReadFrom(src []byte) error {
+	return v.readFrom(src, true)
+}
+
+func (v *GetTelemetrySubscriptionsRequest) readFrom(src []byte, unsafe bool) error {
+	v.Default()
+	b := kbin.Reader{Src: src}
+	version := v.Version
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	s := v
+	{
+		v := b.Uuid()
+		s.ClientInstanceID = v
+	}
+	if isFlexible {
+		s.UnknownTags = internalReadTags(&b)
+	}
+	return b.Complete()
+}
+
+// NewPtrGetTelemetrySubscriptionsRequest returns a pointer to a default GetTelemetrySubscriptionsRequest
+// This is a shortcut for creating a new(struct) and calling Default yourself.
+func NewPtrGetTelemetrySubscriptionsRequest() *GetTelemetrySubscriptionsRequest {
+	var v GetTelemetrySubscriptionsRequest
+	v.Default()
+	return &v
+}
+
+// Default sets any default fields. Calling this allows for future compatibility
+// if new fields are added to GetTelemetrySubscriptionsRequest.
+func (v *GetTelemetrySubscriptionsRequest) Default() {
+}
+
+// NewGetTelemetrySubscriptionsRequest returns a default GetTelemetrySubscriptionsRequest
+// This is a shortcut for creating a struct and calling Default yourself.
+func NewGetTelemetrySubscriptionsRequest() GetTelemetrySubscriptionsRequest {
+	var v GetTelemetrySubscriptionsRequest
+	v.Default()
+	return v
+}
+
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v GetTelemetrySubscriptionsRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *GetTelemetrySubscriptionsRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
+type GetTelemetrySubscriptionsResponse struct {
+	// Version is the version of this message used with a Kafka broker.
+	Version int16
+
+	// ThrottleMillis is how long of a throttle Kafka will apply to the client
+	// after responding to this request.
+	ThrottleMillis int32
+
+	// The error code, or 0 if there was no error.
+	ErrorCode int16
+
+	// The assigned client instance ID if ClientInstanceID was all zeroes in the
+	// request, otherwise all zeroes.
+	ClientInstanceID [16]byte
+
+	// A unique identifier for the current subscription set for this client
+	// instance.
+	SubscriptionID int32
+
+	// Compression types that the broker accepts for the PushTelemetryRequest.
+	AcceptedCompressionTypes []int8
+
+	// The configured push interval, which is the lowest configured interval in
+	// the current subscription set.
+	PushIntervalMillis int32
+
+	// The maximum bytes of binary data the broker accepts in a
+	// PushTelemetryRequest.
+	TelemetryMaxBytes int32
+
+	// Whether the broker wants delta temporality (true) or cumulative (false).
+	DeltaTemporality bool
+
+	// Requested telemetry metric prefix matches. An empty array means no metrics
+	// are subscribed; an array with one empty string means all metrics are
+	// subscribed.
+	RequestedMetrics []string
+
+	// UnknownTags are tags Kafka sent that we do not know the purpose of.
+	UnknownTags Tags
+}
+
+func (*GetTelemetrySubscriptionsResponse) Key() int16                 { return 71 }
+func (*GetTelemetrySubscriptionsResponse) MaxVersion() int16          { return 0 }
+func (v *GetTelemetrySubscriptionsResponse) SetVersion(version int16) { v.Version = version }
+func (v *GetTelemetrySubscriptionsResponse) GetVersion() int16        { return v.Version }
+func (v *GetTelemetrySubscriptionsResponse) IsFlexible() bool         { return v.Version >= 0 }
+func (v *GetTelemetrySubscriptionsResponse) Throttle() (int32, bool) {
+	return v.ThrottleMillis, v.Version >= 0
+}
+func (v *GetTelemetrySubscriptionsResponse) SetThrottle(throttleMillis int32) {
+	v.ThrottleMillis = throttleMillis
+}
+func (v *GetTelemetrySubscriptionsResponse) RequestKind() Request {
+	return &GetTelemetrySubscriptionsRequest{Version: v.Version}
+}
+
+func (v *GetTelemetrySubscriptionsResponse) AppendTo(dst []byte) []byte {
+	version := v.Version
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	{
+		v := v.ThrottleMillis
+		dst = kbin.AppendInt32(dst, v)
+	}
+	{
+		v := v.ErrorCode
+		dst = kbin.AppendInt16(dst, v)
+	}
+	{
+		v := v.ClientInstanceID
+		dst = kbin.AppendUuid(dst, v)
+	}
+	{
+		v := v.SubscriptionID
+		dst = kbin.AppendInt32(dst, v)
+	}
+	{
+		v := v.AcceptedCompressionTypes
+		if isFlexible {
+			dst = kbin.AppendCompactArrayLen(dst, len(v))
+		} else {
+			dst = kbin.AppendArrayLen(dst, len(v))
+		}
+		for i := range v {
+			v := v[i]
+			dst = kbin.AppendInt8(dst, v)
+		}
+	}
+	{
+		v := v.PushIntervalMillis
+		dst = kbin.AppendInt32(dst, v)
+	}
+	{
+		v := v.TelemetryMaxBytes
+		dst = kbin.AppendInt32(dst, v)
+	}
+	{
+		v := v.DeltaTemporality
+		dst = kbin.AppendBool(dst, v)
+	}
+	{
+		v := v.RequestedMetrics
+		if isFlexible {
+			dst = kbin.AppendCompactArrayLen(dst, len(v))
+		} else {
+			dst = kbin.AppendArrayLen(dst, len(v))
+		}
+		for i := range v {
+			v := v[i]
+			if isFlexible {
+				dst = kbin.AppendCompactString(dst, v)
+			} else {
+				dst = kbin.AppendString(dst, v)
+			}
+		}
+	}
+	if isFlexible {
+		dst = kbin.AppendUvarint(dst, 0+uint32(v.UnknownTags.Len()))
+		dst = v.UnknownTags.AppendEach(dst)
+	}
+	return dst
+}
+
+func (v *GetTelemetrySubscriptionsResponse) ReadFrom(src []byte) error {
+	return v.readFrom(src, false)
+}
+
+func (v *GetTelemetrySubscriptionsResponse) UnsafeReadFrom(src []byte) error {
+	return v.readFrom(src, true)
+}
+
+func (v *GetTelemetrySubscriptionsResponse) readFrom(src []byte, unsafe bool) error {
+	v.Default()
+	b := kbin.Reader{Src: src}
+	version := v.Version
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	s := v
+	{
+		v := b.Int32()
+		s.ThrottleMillis = v
+	}
+	{
+		v := b.Int16()
+		s.ErrorCode = v
+	}
+	{
+		v := b.Uuid()
+		s.ClientInstanceID = v
+	}
+	{
+		v := b.Int32()
+		s.SubscriptionID = v
+	}
+	{
+		v := s.AcceptedCompressionTypes
+		a := v
+		var l int32
+		if isFlexible {
+			l = b.CompactArrayLen()
+		} else {
+			l = b.ArrayLen()
+		}
+		if !b.Ok() {
+			return b.Complete()
+		}
+		a = a[:0]
+		if l > 0 {
+			a = append(a, make([]int8, l)...)
+		}
+		for i := int32(0); i < l; i++ {
+			v := b.Int8()
+			a[i] = v
+		}
+		v = a
+		s.AcceptedCompressionTypes = v
+	}
+	{
+		v := b.Int32()
+		s.PushIntervalMillis = v
+	}
+	{
+		v := b.Int32()
+		s.TelemetryMaxBytes = v
+	}
+	{
+		v := b.Bool()
+		s.DeltaTemporality = v
+	}
+	{
+		v := s.RequestedMetrics
+		a := v
+		var l int32
+		if isFlexible {
+			l = b.CompactArrayLen()
+		} else {
+			l = b.ArrayLen()
+		}
+		if !b.Ok() {
+			return b.Complete()
+		}
+		a = a[:0]
+		if l > 0 {
+			a = append(a, make([]string, l)...)
+		}
+		for i := int32(0); i < l; i++ {
+			var v string
+			if unsafe {
+				if isFlexible {
+					v = b.UnsafeCompactString()
+				} else {
+					v = b.UnsafeString()
+				}
+			} else {
+				if isFlexible {
+					v = b.CompactString()
+				} else {
+					v = b.String()
+				}
+			}
+			a[i] = v
+		}
+		v = a
+		s.RequestedMetrics = v
+	}
+	if isFlexible {
+		s.UnknownTags = internalReadTags(&b)
+	}
+	return b.Complete()
+}
+
+// NewPtrGetTelemetrySubscriptionsResponse returns a pointer to a default GetTelemetrySubscriptionsResponse
+// This is a shortcut for creating a new(struct) and calling Default yourself.
+func NewPtrGetTelemetrySubscriptionsResponse() *GetTelemetrySubscriptionsResponse {
+	var v GetTelemetrySubscriptionsResponse
+	v.Default()
+	return &v
+}
+
+// Default sets any default fields. Calling this allows for future compatibility
+// if new fields are added to GetTelemetrySubscriptionsResponse.
+func (v *GetTelemetrySubscriptionsResponse) Default() {
+}
+
+// NewGetTelemetrySubscriptionsResponse returns a default GetTelemetrySubscriptionsResponse
+// This is a shortcut for creating a struct and calling Default yourself.
+func NewGetTelemetrySubscriptionsResponse() GetTelemetrySubscriptionsResponse {
+	var v GetTelemetrySubscriptionsResponse
+	v.Default()
+	return v
+}
+
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v GetTelemetrySubscriptionsResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *GetTelemetrySubscriptionsResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
+// PushTelemetryRequest, introduced in KIP-714, is sent by a client to push
+// metrics for the subscription returned in GetTelemetrySubscriptionsResponse.
+type PushTelemetryRequest struct {
+	// Version is the version of this message used with a Kafka broker.
+	Version int16
+
+	// The unique identifier for the client instance.
+	ClientInstanceID [16]byte
+
+	// The unique identifier for the current subscription.
+	SubscriptionID int32
+
+	// Whether this is the final push from the client instance before it
+	// terminates.
+	Terminating bool
+
+	// The compression codec used to compress the metrics.
+	CompressionType int8
+
+	// The metrics, encoded in OpenTelemetry MetricsData v1 protobuf format.
+	Metrics []byte
+
+	// UnknownTags are tags Kafka sent that we do not know the purpose of.
+	UnknownTags Tags
+}
+
+func (*PushTelemetryRequest) Key() int16                 { return 72 }
+func (*PushTelemetryRequest) MaxVersion() int16          { return 0 }
+func (v *PushTelemetryRequest) SetVersion(version int16) { v.Version = version }
+func (v *PushTelemetryRequest) GetVersion() int16        { return v.Version }
+func (v *PushTelemetryRequest) IsFlexible() bool         { return v.Version >= 0 }
+func (v *PushTelemetryRequest) ResponseKind() Response {
+	r := &PushTelemetryResponse{Version: v.Version}
+	r.Default()
+	return r
+}
+
+// RequestWith is requests v on r and returns the response or an error.
+// For sharded requests, the response may be merged and still return an error.
+// It is better to rely on client.RequestSharded than to rely on proper merging behavior.
+func (v *PushTelemetryRequest) RequestWith(ctx context.Context, r Requestor) (*PushTelemetryResponse, error) {
+	kresp, err := r.Request(ctx, v)
+	resp, _ := kresp.(*PushTelemetryResponse)
+	return resp, err
+}
+
+func (v *PushTelemetryRequest) AppendTo(dst []byte) []byte {
+	version := v.Version
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	{
+		v := v.ClientInstanceID
+		dst = kbin.AppendUuid(dst, v)
+	}
+	{
+		v := v.SubscriptionID
+		dst = kbin.AppendInt32(dst, v)
+	}
+	{
+		v := v.Terminating
+		dst = kbin.AppendBool(dst, v)
+	}
+	{
+		v := v.CompressionType
+		dst = kbin.AppendInt8(dst, v)
+	}
+	{
+		v := v.Metrics
+		if isFlexible {
+			dst = kbin.AppendCompactBytes(dst, v)
+		} else {
+			dst = kbin.AppendBytes(dst, v)
+		}
+	}
+	if isFlexible {
+		dst = kbin.AppendUvarint(dst, 0+uint32(v.UnknownTags.Len()))
+		dst = v.UnknownTags.AppendEach(dst)
+	}
+	return dst
+}
+
+func (v *PushTelemetryRequest) ReadFrom(src []byte) error {
+	return v.readFrom(src, false)
+}
+
+func (v *PushTelemetryRequest) UnsafeReadFrom(src []byte) error {
+	return v.readFrom(src, true)
+}
+
+func (v *PushTelemetryRequest) readFrom(src []byte, unsafe bool) error {
+	v.Default()
+	b := kbin.Reader{Src: src}
+	version := v.Version
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	s := v
+	{
+		v := b.Uuid()
+		s.ClientInstanceID = v
+	}
+	{
+		v := b.Int32()
+		s.SubscriptionID = v
+	}
+	{
+		v := b.Bool()
+		s.Terminating = v
+	}
+	{
+		v := b.Int8()
+		s.CompressionType = v
+	}
+	{
+		var v []byte
+		if isFlexible {
+			v = b.CompactBytes()
+		} else {
+			v = b.Bytes()
+		}
+		s.Metrics = v
+	}
+	if isFlexible {
+		s.UnknownTags = internalReadTags(&b)
+	}
+	return b.Complete()
+}
+
+// NewPtrPushTelemetryRequest returns a pointer to a default PushTelemetryRequest
+// This is a shortcut for creating a new(struct) and calling Default yourself.
+func NewPtrPushTelemetryRequest() *PushTelemetryRequest {
+	var v PushTelemetryRequest
+	v.Default()
+	return &v
+}
+
+// Default sets any default fields. Calling this allows for future compatibility
+// if new fields are added to PushTelemetryRequest.
+func (v *PushTelemetryRequest) Default() {
+}
+
+// NewPushTelemetryRequest returns a default PushTelemetryRequest
+// This is a shortcut for creating a struct and calling Default yourself.
+func NewPushTelemetryRequest() PushTelemetryRequest {
+	var v PushTelemetryRequest
+	v.Default()
+	return v
+}
+
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v PushTelemetryRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *PushTelemetryRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
+type PushTelemetryResponse struct {
+	// Version is the version of this message used with a Kafka broker.
+	Version int16
+
+	// ThrottleMillis is how long of a throttle Kafka will apply to the client
+	// after responding to this request.
+	ThrottleMillis int32
+
+	// The error code, or 0 if there was no error.
+	ErrorCode int16
+
+	// UnknownTags are tags Kafka sent that we do not know the purpose of.
+	UnknownTags Tags
+}
+
+func (*PushTelemetryResponse) Key() int16                         { return 72 }
+func (*PushTelemetryResponse) MaxVersion() int16                  { return 0 }
+func (v *PushTelemetryResponse) SetVersion(version int16)         { v.Version = version }
+func (v *PushTelemetryResponse) GetVersion() int16                { return v.Version }
+func (v *PushTelemetryResponse) IsFlexible() bool                 { return v.Version >= 0 }
+func (v *PushTelemetryResponse) Throttle() (int32, bool)          { return v.ThrottleMillis, v.Version >= 0 }
+func (v *PushTelemetryResponse) SetThrottle(throttleMillis int32) { v.ThrottleMillis = throttleMillis }
+func (v *PushTelemetryResponse) RequestKind() Request {
+	return &PushTelemetryRequest{Version: v.Version}
+}
+
+func (v *PushTelemetryResponse) AppendTo(dst []byte) []byte {
+	version := v.Version
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	{
+		v := v.ThrottleMillis
+		dst = kbin.AppendInt32(dst, v)
+	}
+	{
+		v := v.ErrorCode
+		dst = kbin.AppendInt16(dst, v)
+	}
+	if isFlexible {
+		dst = kbin.AppendUvarint(dst, 0+uint32(v.UnknownTags.Len()))
+		dst = v.UnknownTags.AppendEach(dst)
+	}
+	return dst
+}
+
+func (v *PushTelemetryResponse) ReadFrom(src []byte) error {
+	return v.readFrom(src, false)
+}
+
+func (v *PushTelemetryResponse) UnsafeReadFrom(src []byte) error {
+	return v.readFrom(src, true)
+}
+
+func (v *PushTelemetryResponse) readFrom(src []byte, unsafe bool) error {
+	v.Default()
+	b := kbin.Reader{Src: src}
+	version := v.Version
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	s := v
+	{
+		v := b.Int32()
+		s.ThrottleMillis = v
+	}
+	{
+		v := b.Int16()
+		s.ErrorCode = v
+	}
+	if isFlexible {
+		s.UnknownTags = internalReadTags(&b)
+	}
+	return b.Complete()
+}
+
+// NewPtrPushTelemetryResponse returns a pointer to a default PushTelemetryResponse
+// This is a shortcut for creating a new(struct) and calling Default yourself.
+func NewPtrPushTelemetryResponse() *PushTelemetryResponse {
+	var v PushTelemetryResponse
+	v.Default()
+	return &v
+}
+
+// Default sets any default fields. Calling this allows for future compatibility
+// if new fields are added to PushTelemetryResponse.
+func (v *PushTelemetryResponse) Default() {
+}
+
+// NewPushTelemetryResponse returns a default PushTelemetryResponse
+// This is a shortcut for creating a struct and calling Default yourself.
+func NewPushTelemetryResponse() PushTelemetryResponse {
+	var v PushTelemetryResponse
+	v.Default()
+	return v
+}
+
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v PushTelemetryResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *PushTelemetryResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
+type DescribeTopicPartitionsRequestTopic struct {
+	// The topic name.
+	Topic string
+
+	// UnknownTags are tags Kafka sent that we do not know the purpose of.
+	UnknownTags Tags
+}
+
+// Default sets any default fields. Calling this allows for future compatibility
+// if new fields are added to DescribeTopicPartitionsRequestTopic.
+func (v *DescribeTopicPartitionsRequestTopic) Default() {
+}
+
+// NewDescribeTopicPartitionsRequestTopic returns a default DescribeTopicPartitionsRequestTopic
+// This is a shortcut for creating a struct and calling Default yourself.
+func NewDescribeTopicPartitionsRequestTopic() DescribeTopicPartitionsRequestTopic {
+	var v DescribeTopicPartitionsRequestTopic
+	v.Default()
+	return v
+}
+
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DescribeTopicPartitionsRequestTopic) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DescribeTopicPartitionsRequestTopic) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
+type DescribeTopicPartitionsRequestCursor struct {
+	// The name of the first topic to process.
+	Topic string
+
+	// The partition index to start with.
+	Partition int32
+
+	// UnknownTags are tags Kafka sent that we do not know the purpose of.
+	UnknownTags Tags
+}
+
+// Default sets any default fields. Calling this allows for future compatibility
+// if new fields are added to DescribeTopicPartitionsRequestCursor.
+func (v *DescribeTopicPartitionsRequestCursor) Default() {
+}
+
+// NewDescribeTopicPartitionsRequestCursor returns a default DescribeTopicPartitionsRequestCursor
+// This is a shortcut for creating a struct and calling Default yourself.
+func NewDescribeTopicPartitionsRequestCursor() DescribeTopicPartitionsRequestCursor {
+	var v DescribeTopicPartitionsRequestCursor
+	v.Default()
+	return v
+}
+
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DescribeTopicPartitionsRequestCursor) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DescribeTopicPartitionsRequestCursor) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
+// DescribeTopicPartitionsRequest, introduced in KIP-966, is a paginated
+// alternative to describing topics with MetadataRequest. Topics are returned
+// sorted by name; partitions within a topic are sorted by index. If a response
+// is truncated because of ResponsePartitionLimit, the response contains a
+// cursor to use in the next request.
+type DescribeTopicPartitionsRequest struct {
+	// Version is the version of this message used with a Kafka broker.
+	Version int16
+
+	// The topics to describe. If empty, all topics are described.
+	Topics []DescribeTopicPartitionsRequestTopic
+
+	// The maximum number of partitions included in the response.
+	//
+	// This field has a default of 2000.
+	ResponsePartitionLimit int32
+
+	// The first topic and partition index to fetch details for.
+	Cursor *DescribeTopicPartitionsRequestCursor
+
+	// UnknownTags are tags Kafka sent that we do not know the purpose of.
+	UnknownTags Tags
+}
+
+func (*DescribeTopicPartitionsRequest) Key() int16                 { return 75 }
+func (*DescribeTopicPartitionsRequest) MaxVersion() int16          { return 0 }
+func (v *DescribeTopicPartitionsRequest) SetVersion(version int16) { v.Version = version }
+func (v *DescribeTopicPartitionsRequest) GetVersion() int16        { return v.Version }
+func (v *DescribeTopicPartitionsRequest) IsFlexible() bool         { return v.Version >= 0 }
+func (v *DescribeTopicPartitionsRequest) ResponseKind() Response {
+	r := &DescribeTopicPartitionsResponse{Version: v.Version}
+	r.Default()
+	return r
+}
+
+// RequestWith is requests v on r and returns the response or an error.
+// For sharded requests, the response may be merged and still return an error.
+// It is better to rely on client.RequestSharded than to rely on proper merging behavior.
+func (v *DescribeTopicPartitionsRequest) RequestWith(ctx context.Context, r Requestor) (*DescribeTopicPartitionsResponse, error) {
+	kresp, err := r.Request(ctx, v)
+	resp, _ := kresp.(*DescribeTopicPartitionsResponse)
+	return resp, err
+}
+
+func (v *DescribeTopicPartitionsRequest) AppendTo(dst []byte) []byte {
+	version := v.Version
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	{
+		v := v.Topics
+		if isFlexible {
+			dst = kbin.AppendCompactArrayLen(dst, len(v))
+		} else {
+			dst = kbin.AppendArrayLen(dst, len(v))
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Topic
+				if isFlexible {
+					dst = kbin.AppendCompactString(dst, v)
+				} else {
+					dst = kbin.AppendString(dst, v)
+				}
+			}
+			if isFlexible {
+				dst = kbin.AppendUvarint(dst, 0+uint32(v.UnknownTags.Len()))
+				dst = v.UnknownTags.AppendEach(dst)
+			}
+		}
+	}
+	{
+		v := v.ResponsePartitionLimit
+		dst = kbin.AppendInt32(dst, v)
+	}
+	{
+		v := v.Cursor
+		if v == nil {
+			dst = append(dst, 255)
+		} else {
+			dst = append(dst, 1)
+			{
+				v := v.Topic
+				if isFlexible {
+					dst = kbin.AppendCompactString(dst, v)
+				} else {
+					dst = kbin.AppendString(dst, v)
+				}
+			}
+			{
+				v := v.Partition
+				dst = kbin.AppendInt32(dst, v)
+			}
+			if isFlexible {
+				dst = kbin.AppendUvarint(dst, 0+uint32(v.UnknownTags.Len()))
+				dst = v.UnknownTags.AppendEach(dst)
+			}
+		}
+	}
+	if isFlexible {
+		dst = kbin.AppendUvarint(dst, 0+uint32(v.UnknownTags.Len()))
+		dst = v.UnknownTags.AppendEach(dst)
+	}
+	return dst
+}
+
+func (v *DescribeTopicPartitionsRequest) ReadFrom(src []byte) error {
+	return v.readFrom(src, false)
+}
+
+func (v *DescribeTopicPartitionsRequest) UnsafeReadFrom(src []byte) error {
+	return v.readFrom(src, true)
+}
+
+func (v *DescribeTopicPartitionsRequest) readFrom(src []byte, unsafe bool) error {
+	v.Default()
+	b := kbin.Reader{Src: src}
+	version := v.Version
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	s := v
+	{
+		v := s.Topics
+		a := v
+		var l int32
+		if isFlexible {
+			l = b.CompactArrayLen()
+		} else {
+			l = b.ArrayLen()
+		}
+		if !b.Ok() {
+			return b.Complete()
+		}
+		a = a[:0]
+		if l > 0 {
+			a = append(a, make([]DescribeTopicPartitionsRequestTopic, l)...)
+		}
+		for i := int32(0); i < l; i++ {
+			v := &a[i]
+			v.Default()
+			s := v
+			{
+				var v string
+				if unsafe {
+					if isFlexible {
+						v = b.UnsafeCompactString()
+					} else {
+						v = b.UnsafeString()
+					}
+				} else {
+					if isFlexible {
+						v = b.CompactString()
+					} else {
+						v = b.String()
+					}
+				}
+				s.Topic = v
+			}
+			if isFlexible {
+				s.UnknownTags = internalReadTags(&b)
+			}
+		}
+		v = a
+		s.Topics = v
+	}
+	{
+		v := b.Int32()
+		s.ResponsePartitionLimit = v
+	}
+	{
+		if present := b.Int8(); present != -1 && b.Ok() {
+			s.Cursor = new(DescribeTopicPartitionsRequestCursor)
+			v := s.Cursor
+			v.Default()
+			s := v
+			{
+				var v string
+				if unsafe {
+					if isFlexible {
+						v = b.UnsafeCompactString()
+					} else {
+						v = b.UnsafeString()
+					}
+				} else {
+					if isFlexible {
+						v = b.CompactString()
+					} else {
+						v = b.String()
+					}
+				}
+				s.Topic = v
+			}
+			{
+				v := b.Int32()
+				s.Partition = v
+			}
+			if isFlexible {
+				s.UnknownTags = internalReadTags(&b)
+			}
+		}
+	}
+	if isFlexible {
+		s.UnknownTags = internalReadTags(&b)
+	}
+	return b.Complete()
+}
+
+// NewPtrDescribeTopicPartitionsRequest returns a pointer to a default DescribeTopicPartitionsRequest
+// This is a shortcut for creating a new(struct) and calling Default yourself.
+func NewPtrDescribeTopicPartitionsRequest() *DescribeTopicPartitionsRequest {
+	var v DescribeTopicPartitionsRequest
+	v.Default()
+	return &v
+}
+
+// Default sets any default fields. Calling this allows for future compatibility
+// if new fields are added to DescribeTopicPartitionsRequest.
+func (v *DescribeTopicPartitionsRequest) Default() {
+	v.ResponsePartitionLimit = 2000
+	{
+		v := &v.Cursor
+		_ = v
+	}
+}
+
+// NewDescribeTopicPartitionsRequest returns a default DescribeTopicPartitionsRequest
+// This is a shortcut for creating a struct and calling Default yourself.
+func NewDescribeTopicPartitionsRequest() DescribeTopicPartitionsRequest {
+	var v DescribeTopicPartitionsRequest
+	v.Default()
+	return v
+}
+
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DescribeTopicPartitionsRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DescribeTopicPartitionsRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
+type DescribeTopicPartitionsResponseTopicPartition struct {
+	// The partition error, or 0 if there was no error.
+	ErrorCode int16
+
+	// The partition index.
+	Partition int32
+
+	// The ID of the leader broker.
+	LeaderID int32
+
+	// The leader epoch of this partition.
+	//
+	// This field has a default of -1.
+	LeaderEpoch int32
+
+	// The set of all nodes that host this partition.
+	Replicas []int32
+
+	// The set of nodes that are in sync with the leader for this partition.
+	ISR []int32
+
+	// The new eligible leader replicas otherwise.
+	EligibleLeaderReplicas []int32
+
+	// The last known ELR.
+	LastKnownELR []int32
+
+	// The set of offline replicas of this partition.
+	OfflineReplicas []int32
+
+	// UnknownTags are tags Kafka sent that we do not know the purpose of.
+	UnknownTags Tags
+}
+
+// Default sets any default fields. Calling this allows for future compatibility
+// if new fields are added to DescribeTopicPartitionsResponseTopicPartition.
+func (v *DescribeTopicPartitionsResponseTopicPartition) Default() {
+	v.LeaderEpoch = -1
+}
+
+// NewDescribeTopicPartitionsResponseTopicPartition returns a default DescribeTopicPartitionsResponseTopicPartition
+// This is a shortcut for creating a struct and calling Default yourself.
+func NewDescribeTopicPartitionsResponseTopicPartition() DescribeTopicPartitionsResponseTopicPartition {
+	var v DescribeTopicPartitionsResponseTopicPartition
+	v.Default()
+	return v
+}
+
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DescribeTopicPartitionsResponseTopicPartition) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DescribeTopicPartitionsResponseTopicPartition) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
+type DescribeTopicPartitionsResponseTopic struct {
+	// The topic error, or 0 if there was no error.
+	ErrorCode int16
+
+	// The topic name.
+	Topic *string
+
+	// The topic ID.
+	TopicID [16]byte
+
+	// True if the topic is internal.
+	IsInternal bool
+
+	// Each partition in the topic.
+	Partitions []DescribeTopicPartitionsResponseTopicPartition
+
+	// 32-bit bitfield to represent authorized operations for this topic.
+	//
+	// This field has a default of -2147483648.
+	AuthorizedOperations int32
+
+	// UnknownTags are tags Kafka sent that we do not know the purpose of.
+	UnknownTags Tags
+}
+
+// Default sets any default fields. Calling this allows for future compatibility
+// if new fields are added to DescribeTopicPartitionsResponseTopic.
+func (v *DescribeTopicPartitionsResponseTopic) Default() {
+	v.AuthorizedOperations = -2147483648
+}
+
+// NewDescribeTopicPartitionsResponseTopic returns a default DescribeTopicPartitionsResponseTopic
+// This is a shortcut for creating a struct and calling Default yourself.
+func NewDescribeTopicPartitionsResponseTopic() DescribeTopicPartitionsResponseTopic {
+	var v DescribeTopicPartitionsResponseTopic
+	v.Default()
+	return v
+}
+
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DescribeTopicPartitionsResponseTopic) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DescribeTopicPartitionsResponseTopic) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
+type DescribeTopicPartitionsResponseNextCursor struct {
+	// The name for the first topic to process.
+	Topic string
+
+	// The partition index to start with.
+	Partition int32
+
+	// UnknownTags are tags Kafka sent that we do not know the purpose of.
+	UnknownTags Tags
+}
+
+// Default sets any default fields. Calling this allows for future compatibility
+// if new fields are added to DescribeTopicPartitionsResponseNextCursor.
+func (v *DescribeTopicPartitionsResponseNextCursor) Default() {
+}
+
+// NewDescribeTopicPartitionsResponseNextCursor returns a default DescribeTopicPartitionsResponseNextCursor
+// This is a shortcut for creating a struct and calling Default yourself.
+func NewDescribeTopicPartitionsResponseNextCursor() DescribeTopicPartitionsResponseNextCursor {
+	var v DescribeTopicPartitionsResponseNextCursor
+	v.Default()
+	return v
+}
+
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DescribeTopicPartitionsResponseNextCursor) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DescribeTopicPartitionsResponseNextCursor) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
+type DescribeTopicPartitionsResponse struct {
+	// Version is the version of this message used with a Kafka broker.
+	Version int16
+
+	// ThrottleMillis is how long of a throttle Kafka will apply to the client
+	// after responding to this request.
+	ThrottleMillis int32
+
+	// Each topic in the response.
+	Topics []DescribeTopicPartitionsResponseTopic
+
+	// The next topic and partition index to fetch details for.
+	NextCursor *DescribeTopicPartitionsResponseNextCursor
+
+	// UnknownTags are tags Kafka sent that we do not know the purpose of.
+	UnknownTags Tags
+}
+
+func (*DescribeTopicPartitionsResponse) Key() int16                 { return 75 }
+func (*DescribeTopicPartitionsResponse) MaxVersion() int16          { return 0 }
+func (v *DescribeTopicPartitionsResponse) SetVersion(version int16) { v.Version = version }
+func (v *DescribeTopicPartitionsResponse) GetVersion() int16        { return v.Version }
+func (v *DescribeTopicPartitionsResponse) IsFlexible() bool         { return v.Version >= 0 }
+func (v *DescribeTopicPartitionsResponse) Throttle() (int32, bool) {
+	return v.ThrottleMillis, v.Version >= 0
+}
+func (v *DescribeTopicPartitionsResponse) SetThrottle(throttleMillis int32) {
+	v.ThrottleMillis = throttleMillis
+}
+func (v *DescribeTopicPartitionsResponse) RequestKind() Request {
+	return &DescribeTopicPartitionsRequest{Version: v.Version}
+}
+
+func (v *DescribeTopicPartitionsResponse) AppendTo(dst []byte) []byte {
+	version := v.Version
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	{
+		v := v.ThrottleMillis
+		dst = kbin.AppendInt32(dst, v)
+	}
+	{
+		v := v.Topics
+		if isFlexible {
+			dst = kbin.AppendCompactArrayLen(dst, len(v))
+		} else {
+			dst = kbin.AppendArrayLen(dst, len(v))
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.ErrorCode
+				dst = kbin.AppendInt16(dst, v)
+			}
+			{
+				v := v.Topic
+				if isFlexible {
+					dst = kbin.AppendCompactNullableString(dst, v)
+				} else {
+					dst = kbin.AppendNullableString(dst, v)
+				}
+			}
+			{
+				v := v.TopicID
+				dst = kbin.AppendUuid(dst, v)
+			}
+			{
+				v := v.IsInternal
+				dst = kbin.AppendBool(dst, v)
+			}
+			{
+				v := v.Partitions
+				if isFlexible {
+					dst = kbin.AppendCompactArrayLen(dst, len(v))
+				} else {
+					dst = kbin.AppendArrayLen(dst, len(v))
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						v := v.ErrorCode
+						dst = kbin.AppendInt16(dst, v)
+					}
+					{
+						v := v.Partition
+						dst = kbin.AppendInt32(dst, v)
+					}
+					{
+						v := v.LeaderID
+						dst = kbin.AppendInt32(dst, v)
+					}
+					{
+						v := v.LeaderEpoch
+						dst = kbin.AppendInt32(dst, v)
+					}
+					{
+						v := v.Replicas
+						if isFlexible {
+							dst = kbin.AppendCompactArrayLen(dst, len(v))
+						} else {
+							dst = kbin.AppendArrayLen(dst, len(v))
+						}
+						for i := range v {
+							v := v[i]
+							dst = kbin.AppendInt32(dst, v)
+						}
+					}
+					{
+						v := v.ISR
+						if isFlexible {
+							dst = kbin.AppendCompactArrayLen(dst, len(v))
+						} else {
+							dst = kbin.AppendArrayLen(dst, len(v))
+						}
+						for i := range v {
+							v := v[i]
+							dst = kbin.AppendInt32(dst, v)
+						}
+					}
+					{
+						v := v.EligibleLeaderReplicas
+						if isFlexible {
+							dst = kbin.AppendCompactNullableArrayLen(dst, len(v), v == nil)
+						} else {
+							dst = kbin.AppendNullableArrayLen(dst, len(v), v == nil)
+						}
+						for i := range v {
+							v := v[i]
+							dst = kbin.AppendInt32(dst, v)
+						}
+					}
+					{
+						v := v.LastKnownELR
+						if isFlexible {
+							dst = kbin.AppendCompactNullableArrayLen(dst, len(v), v == nil)
+						} else {
+							dst = kbin.AppendNullableArrayLen(dst, len(v), v == nil)
+						}
+						for i := range v {
+							v := v[i]
+							dst = kbin.AppendInt32(dst, v)
+						}
+					}
+					{
+						v := v.OfflineReplicas
+						if isFlexible {
+							dst = kbin.AppendCompactArrayLen(dst, len(v))
+						} else {
+							dst = kbin.AppendArrayLen(dst, len(v))
+						}
+						for i := range v {
+							v := v[i]
+							dst = kbin.AppendInt32(dst, v)
+						}
+					}
+					if isFlexible {
+						dst = kbin.AppendUvarint(dst, 0+uint32(v.UnknownTags.Len()))
+						dst = v.UnknownTags.AppendEach(dst)
+					}
+				}
+			}
+			{
+				v := v.AuthorizedOperations
+				dst = kbin.AppendInt32(dst, v)
+			}
+			if isFlexible {
+				dst = kbin.AppendUvarint(dst, 0+uint32(v.UnknownTags.Len()))
+				dst = v.UnknownTags.AppendEach(dst)
+			}
+		}
+	}
+	{
+		v := v.NextCursor
+		if v == nil {
+			dst = append(dst, 255)
+		} else {
+			dst = append(dst, 1)
+			{
+				v := v.Topic
+				if isFlexible {
+					dst = kbin.AppendCompactString(dst, v)
+				} else {
+					dst = kbin.AppendString(dst, v)
+				}
+			}
+			{
+				v := v.Partition
+				dst = kbin.AppendInt32(dst, v)
+			}
+			if isFlexible {
+				dst = kbin.AppendUvarint(dst, 0+uint32(v.UnknownTags.Len()))
+				dst = v.UnknownTags.AppendEach(dst)
+			}
+		}
+	}
+	if isFlexible {
+		dst = kbin.AppendUvarint(dst, 0+uint32(v.UnknownTags.Len()))
+		dst = v.UnknownTags.AppendEach(dst)
+	}
+	return dst
+}
+
+func (v *DescribeTopicPartitionsResponse) ReadFrom(src []byte) error {
+	return v.readFrom(src, false)
+}
+
+func (v *DescribeTopicPartitionsResponse) UnsafeReadFrom(src []byte) error {
+	return v.readFrom(src, true)
+}
+
+func (v *DescribeTopicPartitionsResponse) readFrom(src []byte, unsafe bool) error {
+	v.Default()
+	b := kbin.Reader{Src: src}
+	version := v.Version
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	s := v
+	{
+		v := b.Int32()
+		s.ThrottleMillis = v
+	}
+	{
+		v := s.Topics
+		a := v
+		var l int32
+		if isFlexible {
+			l = b.CompactArrayLen()
+		} else {
+			l = b.ArrayLen()
+		}
+		if !b.Ok() {
+			return b.Complete()
+		}
+		a = a[:0]
+		if l > 0 {
+			a = append(a, make([]DescribeTopicPartitionsResponseTopic, l)...)
+		}
+		for i := int32(0); i < l; i++ {
+			v := &a[i]
+			v.Default()
+			s := v
+			{
+				v := b.Int16()
+				s.ErrorCode = v
+			}
+			{
+				var v *string
+				if isFlexible {
+					if unsafe {
+						v = b.UnsafeCompactNullableString()
+					} else {
+						v = b.CompactNullableString()
+					}
+				} else {
+					if unsafe {
+						v = b.UnsafeNullableString()
+					} else {
+						v = b.NullableString()
+					}
+				}
+				s.Topic = v
+			}
+			{
+				v := b.Uuid()
+				s.TopicID = v
+			}
+			{
+				v := b.Bool()
+				s.IsInternal = v
+			}
+			{
+				v := s.Partitions
+				a := v
+				var l int32
+				if isFlexible {
+					l = b.CompactArrayLen()
+				} else {
+					l = b.ArrayLen()
+				}
+				if !b.Ok() {
+					return b.Complete()
+				}
+				a = a[:0]
+				if l > 0 {
+					a = append(a, make([]DescribeTopicPartitionsResponseTopicPartition, l)...)
+				}
+				for i := int32(0); i < l; i++ {
+					v := &a[i]
+					v.Default()
+					s := v
+					{
+						v := b.Int16()
+						s.ErrorCode = v
+					}
+					{
+						v := b.Int32()
+						s.Partition = v
+					}
+					{
+						v := b.Int32()
+						s.LeaderID = v
+					}
+					{
+						v := b.Int32()
+						s.LeaderEpoch = v
+					}
+					{
+						v := s.Replicas
+						a := v
+						var l int32
+						if isFlexible {
+							l = b.CompactArrayLen()
+						} else {
+							l = b.ArrayLen()
+						}
+						if !b.Ok() {
+							return b.Complete()
+						}
+						a = a[:0]
+						if l > 0 {
+							a = append(a, make([]int32, l)...)
+						}
+						for i := int32(0); i < l; i++ {
+							v := b.Int32()
+							a[i] = v
+						}
+						v = a
+						s.Replicas = v
+					}
+					{
+						v := s.ISR
+						a := v
+						var l int32
+						if isFlexible {
+							l = b.CompactArrayLen()
+						} else {
+							l = b.ArrayLen()
+						}
+						if !b.Ok() {
+							return b.Complete()
+						}
+						a = a[:0]
+						if l > 0 {
+							a = append(a, make([]int32, l)...)
+						}
+						for i := int32(0); i < l; i++ {
+							v := b.Int32()
+							a[i] = v
+						}
+						v = a
+						s.ISR = v
+					}
+					{
+						v := s.EligibleLeaderReplicas
+						a := v
+						var l int32
+						if isFlexible {
+							l = b.CompactArrayLen()
+						} else {
+							l = b.ArrayLen()
+						}
+						if version < 0 || l == 0 {
+							a = []int32{}
+						}
+						if !b.Ok() {
+							return b.Complete()
+						}
+						a = a[:0]
+						if l > 0 {
+							a = append(a, make([]int32, l)...)
+						}
+						for i := int32(0); i < l; i++ {
+							v := b.Int32()
+							a[i] = v
+						}
+						v = a
+						s.EligibleLeaderReplicas = v
+					}
+					{
+						v := s.LastKnownELR
+						a := v
+						var l int32
+						if isFlexible {
+							l = b.CompactArrayLen()
+						} else {
+							l = b.ArrayLen()
+						}
+						if version < 0 || l == 0 {
+							a = []int32{}
+						}
+						if !b.Ok() {
+							return b.Complete()
+						}
+						a = a[:0]
+						if l > 0 {
+							a = append(a, make([]int32, l)...)
+						}
+						for i := int32(0); i < l; i++ {
+							v := b.Int32()
+							a[i] = v
+						}
+						v = a
+						s.LastKnownELR = v
+					}
+					{
+						v := s.OfflineReplicas
+						a := v
+						var l int32
+						if isFlexible {
+							l = b.CompactArrayLen()
+						} else {
+							l = b.ArrayLen()
+						}
+						if !b.Ok() {
+							return b.Complete()
+						}
+						a = a[:0]
+						if l > 0 {
+							a = append(a, make([]int32, l)...)
+						}
+						for i := int32(0); i < l; i++ {
+							v := b.Int32()
+							a[i] = v
+						}
+						v = a
+						s.OfflineReplicas = v
+					}
+					if isFlexible {
+						s.UnknownTags = internalReadTags(&b)
+					}
+				}
+				v = a
+				s.Partitions = v
+			}
+			{
+				v := b.Int32()
+				s.AuthorizedOperations = v
+			}
+			if isFlexible {
+				s.UnknownTags = internalReadTags(&b)
+			}
+		}
+		v = a
+		s.Topics = v
+	}
+	{
+		if present := b.Int8(); present != -1 && b.Ok() {
+			s.NextCursor = new(DescribeTopicPartitionsResponseNextCursor)
+			v := s.NextCursor
+			v.Default()
+			s := v
+			{
+				var v string
+				if unsafe {
+					if isFlexible {
+						v = b.UnsafeCompactString()
+					} else {
+						v = b.UnsafeString()
+					}
+				} else {
+					if isFlexible {
+						v = b.CompactString()
+					} else {
+						v = b.String()
+					}
+				}
+				s.Topic = v
+			}
+			{
+				v := b.Int32()
+				s.Partition = v
+			}
+			if isFlexible {
+				s.UnknownTags = internalReadTags(&b)
+			}
+		}
+	}
+	if isFlexible {
+		s.UnknownTags = internalReadTags(&b)
+	}
+	return b.Complete()
+}
+
+// NewPtrDescribeTopicPartitionsResponse returns a pointer to a default DescribeTopicPartitionsResponse
+// This is a shortcut for creating a new(struct) and calling Default yourself.
+func NewPtrDescribeTopicPartitionsResponse() *DescribeTopicPartitionsResponse {
+	var v DescribeTopicPartitionsResponse
+	v.Default()
+	return &v
+}
+
+// Default sets any default fields. Calling this allows for future compatibility
+// if new fields are added to DescribeTopicPartitionsResponse.
+func (v *DescribeTopicPartitionsResponse) Default() {
+	{
+		v := &v.NextCursor
+		_ = v
+	}
+}
+
+// NewDescribeTopicPartitionsResponse returns a default DescribeTopicPartitionsResponse
+// This is a shortcut for creating a struct and calling Default yourself.
+func NewDescribeTopicPartitionsResponse() DescribeTopicPartitionsResponse {
+	var v DescribeTopicPartitionsResponse
+	v.Default()
+	return v
+}
+
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v DescribeTopicPartitionsResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *DescribeTopicPartitionsResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
+// ShareGroupHeartbeat is a part of KIP-932; this is the share group
+// equivalent of ConsumerGroupHeartbeat. Share groups provide queue-like
+// semantics: records are acquired by members and individually acknowledged.
+type ShareGroupHeartbeatRequest struct {
+	// Version is the version of this message used with a Kafka broker.
+	Version int16
+
+	// The group ID.
+	GroupID string
+
+	// The member ID generated by the coordinator. This must be kept during
+	// the entire lifetime of the member.
+	MemberID string
+
+	// The current member epoch; 0 to join the group, -1 to leave.
+	MemberEpoch int32
+
+	// The rack ID of the member; null if not provided or if unchanging.
+	RackID *string
+
+	// Subscribed topics; null if unchanging.
+	SubscribedTopicNames []string
+
+	// UnknownTags are tags Kafka sent that we do not know the purpose of.
+	UnknownTags Tags
+}
+
+func (*ShareGroupHeartbeatRequest) Key() int16                 { return 76 }
+func (*ShareGroupHeartbeatRequest) MaxVersion() int16          { return 0 }
+func (v *ShareGroupHeartbeatRequest) SetVersion(version int16) { v.Version = version }
+func (v *ShareGroupHeartbeatRequest) GetVersion() int16        { return v.Version }
+func (v *ShareGroupHeartbeatRequest) IsFlexible() bool         { return v.Version >= 0 }
+func (v *ShareGroupHeartbeatRequest) ResponseKind() Response {
+	r := &ShareGroupHeartbeatResponse{Version: v.Version}
+	r.Default()
+	return r
+}
+
+// RequestWith is requests v on r and returns the response or an error.
+// For sharded requests, the response may be merged and still return an error.
+// It is better to rely on client.RequestSharded than to rely on proper merging behavior.
+func (v *ShareGroupHeartbeatRequest) RequestWith(ctx context.Context, r Requestor) (*ShareGroupHeartbeatResponse, error) {
+	kresp, err := r.Request(ctx, v)
+	resp, _ := kresp.(*ShareGroupHeartbeatResponse)
+	return resp, err
+}
+
+func (v *ShareGroupHeartbeatRequest) AppendTo(dst []byte) []byte {
+	version := v.Version
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	{
+		v := v.GroupID
+		if isFlexible {
+			dst = kbin.AppendCompactString(dst, v)
+		} else {
+			dst = kbin.AppendString(dst, v)
+		}
+	}
+	{
+		v := v.MemberID
+		if isFlexible {
+			dst = kbin.AppendCompactString(dst, v)
+		} else {
+			dst = kbin.AppendString(dst, v)
+		}
+	}
+	{
+		v := v.MemberEpoch
+		dst = kbin.AppendInt32(dst, v)
+	}
+	{
+		v := v.RackID
+		if isFlexible {
+			dst = kbin.AppendCompactNullableString(dst, v)
+		} else {
+			dst = kbin.AppendNullableString(dst, v)
+		}
+	}
+	{
+		v := v.SubscribedTopicNames
+		if isFlexible {
+			dst = kbin.AppendCompactNullableArrayLen(dst, len(v), v == nil)
+		} else {
+			dst = kbin.AppendNullableArrayLen(dst, len(v), v == nil)
+		}
+		for i := range v {
+			v := v[i]
+			if isFlexible {
+				dst = kbin.AppendCompactString(dst, v)
+			} else {
+				dst = kbin.AppendString(dst, v)
+			}
+		}
+	}
+	if isFlexible {
+		dst = kbin.AppendUvarint(dst, 0+uint32(v.UnknownTags.Len()))
+		dst = v.UnknownTags.AppendEach(dst)
+	}
+	return dst
+}
+
+func (v *ShareGroupHeartbeatRequest) ReadFrom(src []byte) error {
+	return v.readFrom(src, false)
+}
+
+func (v *ShareGroupHeartbeatRequest) UnsafeReadFrom(src []byte) error {
+	return v.readFrom(src, true)
+}
+
+func (v *ShareGroupHeartbeatRequest) readFrom(src []byte, unsafe bool) error {
+	v.Default()
+	b := kbin.Reader{Src: src}
+	version := v.Version
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	s := v
+	{
+		var v string
+		if unsafe {
+			if isFlexible {
+				v = b.UnsafeCompactString()
+			} else {
+				v = b.UnsafeString()
+			}
+		} else {
+			if isFlexible {
+				v = b.CompactString()
+			} else {
+				v = b.String()
+			}
+		}
+		s.GroupID = v
+	}
+	{
+		var v string
+		if unsafe {
+			if isFlexible {
+				v = b.UnsafeCompactString()
+			} else {
+				v = b.UnsafeString()
+			}
+		} else {
+			if isFlexible {
+				v = b.CompactString()
+			} else {
+				v = b.String()
+			}
+		}
+		s.MemberID = v
+	}
+	{
+		v := b.Int32()
+		s.MemberEpoch = v
+	}
+	{
+		var v *string
+		if isFlexible {
+			if unsafe {
+				v = b.UnsafeCompactNullableString()
+			} else {
+				v = b.CompactNullableString()
+			}
+		} else {
+			if unsafe {
+				v = b.UnsafeNullableString()
+			} else {
+				v = b.NullableString()
+			}
+		}
+		s.RackID = v
+	}
+	{
+		v := s.SubscribedTopicNames
+		a := v
+		var l int32
+		if isFlexible {
+			l = b.CompactArrayLen()
+		} else {
+			l = b.ArrayLen()
+		}
+		if version < 0 || l == 0 {
+			a = []string{}
+		}
+		if !b.Ok() {
+			return b.Complete()
+		}
+		a = a[:0]
+		if l > 0 {
+			a = append(a, make([]string, l)...)
+		}
+		for i := int32(0); i < l; i++ {
+			var v string
+			if unsafe {
+				if isFlexible {
+					v = b.UnsafeCompactString()
+				} else {
+					v = b.UnsafeString()
+				}
+			} else {
+				if isFlexible {
+					v = b.CompactString()
+				} else {
+					v = b.String()
+				}
+			}
+			a[i] = v
+		}
+		v = a
+		s.SubscribedTopicNames = v
+	}
+	if isFlexible {
+		s.UnknownTags = internalReadTags(&b)
+	}
+	return b.Complete()
+}
+
+// NewPtrShareGroupHeartbeatRequest returns a pointer to a default ShareGroupHeartbeatRequest
+// This is a shortcut for creating a new(struct) and calling Default yourself.
+func NewPtrShareGroupHeartbeatRequest() *ShareGroupHeartbeatRequest {
+	var v ShareGroupHeartbeatRequest
+	v.Default()
+	return &v
+}
+
+// Default sets any default fields. Calling this allows for future compatibility
+// if new fields are added to ShareGroupHeartbeatRequest.
+func (v *ShareGroupHeartbeatRequest) Default() {
+}
+
+// NewShareGroupHeartbeatRequest returns a default ShareGroupHeartbeatRequest
+// This is a shortcut for creating a struct and calling Default yourself.
+func NewShareGroupHeartbeatRequest() ShareGroupHeartbeatRequest {
+	var v ShareGroupHeartbeatRequest
+	v.Default()
+	return v
+}
+
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ShareGroupHeartbeatRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ShareGroupHeartbeatRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
+type ShareGroupHeartbeatResponseAssignmentTopicPartition struct {
+	TopicID [16]byte
+
+	Partitions []int32
+
+	// UnknownTags are tags Kafka sent that we do not know the purpose of.
+	UnknownTags Tags
+}
+
+// Default sets any default fields. Calling this allows for future compatibility
+// if new fields are added to ShareGroupHeartbeatResponseAssignmentTopicPartition.
+func (v *ShareGroupHeartbeatResponseAssignmentTopicPartition) Default() {
+}
+
+// NewShareGroupHeartbeatResponseAssignmentTopicPartition returns a default ShareGroupHeartbeatResponseAssignmentTopicPartition
+// This is a shortcut for creating a struct and calling Default yourself.
+func NewShareGroupHeartbeatResponseAssignmentTopicPartition() ShareGroupHeartbeatResponseAssignmentTopicPartition {
+	var v ShareGroupHeartbeatResponseAssignmentTopicPartition
+	v.Default()
+	return v
+}
+
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ShareGroupHeartbeatResponseAssignmentTopicPartition) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ShareGroupHeartbeatResponseAssignmentTopicPartition) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
+type ShareGroupHeartbeatResponseAssignment struct {
+	// The topics partitions assigned to the member.
+	TopicPartitions []ShareGroupHeartbeatResponseAssignmentTopicPartition
+
+	// UnknownTags are tags Kafka sent that we do not know the purpose of.
+	UnknownTags Tags
+}
+
+// Default sets any default fields. Calling this allows for future compatibility
+// if new fields are added to ShareGroupHeartbeatResponseAssignment.
+func (v *ShareGroupHeartbeatResponseAssignment) Default() {
+}
+
+// NewShareGroupHeartbeatResponseAssignment returns a default ShareGroupHeartbeatResponseAssignment
+// This is a shortcut for creating a struct and calling Default yourself.
+func NewShareGroupHeartbeatResponseAssignment() ShareGroupHeartbeatResponseAssignment {
+	var v ShareGroupHeartbeatResponseAssignment
+	v.Default()
+	return v
+}
+
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ShareGroupHeartbeatResponseAssignment) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ShareGroupHeartbeatResponseAssignment) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
+// ShareGroupHeartbeatResponse is returned from a ShareGroupHeartbeatRequest.
+type ShareGroupHeartbeatResponse struct {
+	// Version is the version of this message used with a Kafka broker.
+	Version int16
+
+	// ThrottleMillis is how long of a throttle Kafka will apply to the client
+	// after responding to this request.
+	ThrottleMillis int32
+
+	// ErrorCode is the error for this response.
+	//
+	// Supported errors:
+	// - GROUP_AUTHORIZATION_FAILED (version 0+)
+	// - NOT_COORDINATOR (version 0+)
+	// - COORDINATOR_NOT_AVAILABLE (version 0+)
+	// - COORDINATOR_LOAD_IN_PROGRESS (version 0+)
+	// - INVALID_REQUEST (version 0+)
+	// - UNKNOWN_MEMBER_ID (version 0+)
+	// - GROUP_MAX_SIZE_REACHED (version 0+)
+	ErrorCode int16
+
+	// A supplementary message if this errored.
+	ErrorMessage *string
+
+	// The member ID generated by the coordinator; provided when joining
+	// with MemberEpoch=0.
+	MemberID *string
+
+	// The member epoch.
+	MemberEpoch int32
+
+	// The heartbeat interval, in milliseconds.
+	HeartbeatIntervalMillis int32
+
+	// The assignment; null if not provided.
+	Assignment *ShareGroupHeartbeatResponseAssignment
+
+	// UnknownTags are tags Kafka sent that we do not know the purpose of.
+	UnknownTags Tags
+}
+
+func (*ShareGroupHeartbeatResponse) Key() int16                 { return 76 }
+func (*ShareGroupHeartbeatResponse) MaxVersion() int16          { return 0 }
+func (v *ShareGroupHeartbeatResponse) SetVersion(version int16) { v.Version = version }
+func (v *ShareGroupHeartbeatResponse) GetVersion() int16        { return v.Version }
+func (v *ShareGroupHeartbeatResponse) IsFlexible() bool         { return v.Version >= 0 }
+func (v *ShareGroupHeartbeatResponse) Throttle() (int32, bool) {
+	return v.ThrottleMillis, v.Version >= 0
+}
+func (v *ShareGroupHeartbeatResponse) SetThrottle(throttleMillis int32) {
+	v.ThrottleMillis = throttleMillis
+}
+func (v *ShareGroupHeartbeatResponse) RequestKind() Request {
+	return &ShareGroupHeartbeatRequest{Version: v.Version}
+}
+
+func (v *ShareGroupHeartbeatResponse) AppendTo(dst []byte) []byte {
+	version := v.Version
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	{
+		v := v.ThrottleMillis
+		dst = kbin.AppendInt32(dst, v)
+	}
+	{
+		v := v.ErrorCode
+		dst = kbin.AppendInt16(dst, v)
+	}
+	{
+		v := v.ErrorMessage
+		if isFlexible {
+			dst = kbin.AppendCompactNullableString(dst, v)
+		} else {
+			dst = kbin.AppendNullableString(dst, v)
+		}
+	}
+	{
+		v := v.MemberID
+		if isFlexible {
+			dst = kbin.AppendCompactNullableString(dst, v)
+		} else {
+			dst = kbin.AppendNullableString(dst, v)
+		}
+	}
+	{
+		v := v.MemberEpoch
+		dst = kbin.AppendInt32(dst, v)
+	}
+	{
+		v := v.HeartbeatIntervalMillis
+		dst = kbin.AppendInt32(dst, v)
+	}
+	{
+		v := v.Assignment
+		if v == nil {
+			dst = append(dst, 255)
+		} else {
+			dst = append(dst, 1)
+			{
+				v := v.TopicPartitions
+				if isFlexible {
+					dst = kbin.AppendCompactArrayLen(dst, len(v))
+				} else {
+					dst = kbin.AppendArrayLen(dst, len(v))
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						v := v.TopicID
+						dst = kbin.AppendUuid(dst, v)
+					}
+					{
+						v := v.Partitions
+						if isFlexible {
+							dst = kbin.AppendCompactArrayLen(dst, len(v))
+						} else {
+							dst = kbin.AppendArrayLen(dst, len(v))
+						}
+						for i := range v {
+							v := v[i]
+							dst = kbin.AppendInt32(dst, v)
+						}
+					}
+					if isFlexible {
+						dst = kbin.AppendUvarint(dst, 0+uint32(v.UnknownTags.Len()))
+						dst = v.UnknownTags.AppendEach(dst)
+					}
+				}
+			}
+			if isFlexible {
+				dst = kbin.AppendUvarint(dst, 0+uint32(v.UnknownTags.Len()))
+				dst = v.UnknownTags.AppendEach(dst)
+			}
+		}
+	}
+	if isFlexible {
+		dst = kbin.AppendUvarint(dst, 0+uint32(v.UnknownTags.Len()))
+		dst = v.UnknownTags.AppendEach(dst)
+	}
+	return dst
+}
+
+func (v *ShareGroupHeartbeatResponse) ReadFrom(src []byte) error {
+	return v.readFrom(src, false)
+}
+
+func (v *ShareGroupHeartbeatResponse) UnsafeReadFrom(src []byte) error {
+	return v.readFrom(src, true)
+}
+
+func (v *ShareGroupHeartbeatResponse) readFrom(src []byte, unsafe bool) error {
+	v.Default()
+	b := kbin.Reader{Src: src}
+	version := v.Version
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	s := v
+	{
+		v := b.Int32()
+		s.ThrottleMillis = v
+	}
+	{
+		v := b.Int16()
+		s.ErrorCode = v
+	}
+	{
+		var v *string
+		if isFlexible {
+			if unsafe {
+				v = b.UnsafeCompactNullableString()
+			} else {
+				v = b.CompactNullableString()
+			}
+		} else {
+			if unsafe {
+				v = b.UnsafeNullableString()
+			} else {
+				v = b.NullableString()
+			}
+		}
+		s.ErrorMessage = v
+	}
+	{
+		var v *string
+		if isFlexible {
+			if unsafe {
+				v = b.UnsafeCompactNullableString()
+			} else {
+				v = b.CompactNullableString()
+			}
+		} else {
+			if unsafe {
+				v = b.UnsafeNullableString()
+			} else {
+				v = b.NullableString()
+			}
+		}
+		s.MemberID = v
+	}
+	{
+		v := b.Int32()
+		s.MemberEpoch = v
+	}
+	{
+		v := b.Int32()
+		s.HeartbeatIntervalMillis = v
+	}
+	{
+		if present := b.Int8(); present != -1 && b.Ok() {
+			s.Assignment = new(ShareGroupHeartbeatResponseAssignment)
+			v := s.Assignment
+			v.Default()
+			s := v
+			{
+				v := s.TopicPartitions
+				a := v
+				var l int32
+				if isFlexible {
+					l = b.CompactArrayLen()
+				} else {
+					l = b.ArrayLen()
+				}
+				if !b.Ok() {
+					return b.Complete()
+				}
+				a = a[:0]
+				if l > 0 {
+					a = append(a, make([]ShareGroupHeartbeatResponseAssignmentTopicPartition, l)...)
+				}
+				for i := int32(0); i < l; i++ {
+					v := &a[i]
+					v.Default()
+					s := v
+					{
+						v := b.Uuid()
+						s.TopicID = v
+					}
+					{
+						v := s.Partitions
+						a := v
+						var l int32
+						if isFlexible {
+							l = b.CompactArrayLen()
+						} else {
+							l = b.ArrayLen()
+						}
+						if !b.Ok() {
+							return b.Complete()
+						}
+						a = a[:0]
+						if l > 0 {
+							a = append(a, make([]int32, l)...)
+						}
+						for i := int32(0); i < l; i++ {
+							v := b.Int32()
+							a[i] = v
+						}
+						v = a
+						s.Partitions = v
+					}
+					if isFlexible {
+						s.UnknownTags = internalReadTags(&b)
+					}
+				}
+				v = a
+				s.TopicPartitions = v
+			}
+			if isFlexible {
+				s.UnknownTags = internalReadTags(&b)
+			}
+		}
+	}
+	if isFlexible {
+		s.UnknownTags = internalReadTags(&b)
+	}
+	return b.Complete()
+}
+
+// NewPtrShareGroupHeartbeatResponse returns a pointer to a default ShareGroupHeartbeatResponse
+// This is a shortcut for creating a new(struct) and calling Default yourself.
+func NewPtrShareGroupHeartbeatResponse() *ShareGroupHeartbeatResponse {
+	var v ShareGroupHeartbeatResponse
+	v.Default()
+	return &v
+}
+
+// Default sets any default fields. Calling this allows for future compatibility
+// if new fields are added to ShareGroupHeartbeatResponse.
+func (v *ShareGroupHeartbeatResponse) Default() {
+	{
+		v := &v.Assignment
+		_ = v
+	}
+}
+
+// NewShareGroupHeartbeatResponse returns a default ShareGroupHeartbeatResponse
+// This is a shortcut for creating a struct and calling Default yourself.
+func NewShareGroupHeartbeatResponse() ShareGroupHeartbeatResponse {
+	var v ShareGroupHeartbeatResponse
+	v.Default()
+	return v
+}
+
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ShareGroupHeartbeatResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ShareGroupHeartbeatResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
+// ShareGroupDescribe is a part of KIP-932; this is the share group
+// equivalent of ConsumerGroupDescribe.
+type ShareGroupDescribeRequest struct {
+	// Version is the version of this message used with a Kafka broker.
+	Version int16
+
+	// The IDs of the groups to describe.
+	GroupIDs []string
+
+	// Whether to include authorized operations.
+	IncludeAuthorizedOperations bool
+
+	// UnknownTags are tags Kafka sent that we do not know the purpose of.
+	UnknownTags Tags
+}
+
+func (*ShareGroupDescribeRequest) Key() int16                 { return 77 }
+func (*ShareGroupDescribeRequest) MaxVersion() int16          { return 0 }
+func (v *ShareGroupDescribeRequest) SetVersion(version int16) { v.Version = version }
+func (v *ShareGroupDescribeRequest) GetVersion() int16        { return v.Version }
+func (v *ShareGroupDescribeRequest) IsFlexible() bool         { return v.Version >= 0 }
+func (v *ShareGroupDescribeRequest) ResponseKind() Response {
+	r := &ShareGroupDescribeResponse{Version: v.Version}
+	r.Default()
+	return r
+}
+
+// RequestWith is requests v on r and returns the response or an error.
+// For sharded requests, the response may be merged and still return an error.
+// It is better to rely on client.RequestSharded than to rely on proper merging behavior.
+func (v *ShareGroupDescribeRequest) RequestWith(ctx context.Context, r Requestor) (*ShareGroupDescribeResponse, error) {
+	kresp, err := r.Request(ctx, v)
+	resp, _ := kresp.(*ShareGroupDescribeResponse)
+	return resp, err
+}
+
+func (v *ShareGroupDescribeRequest) AppendTo(dst []byte) []byte {
+	version := v.Version
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	{
+		v := v.GroupIDs
+		if isFlexible {
+			dst = kbin.AppendCompactArrayLen(dst, len(v))
+		} else {
+			dst = kbin.AppendArrayLen(dst, len(v))
+		}
+		for i := range v {
+			v := v[i]
+			if isFlexible {
+				dst = kbin.AppendCompactString(dst, v)
+			} else {
+				dst = kbin.AppendString(dst, v)
+			}
+		}
+	}
+	{
+		v := v.IncludeAuthorizedOperations
+		dst = kbin.AppendBool(dst, v)
+	}
+	if isFlexible {
+		dst = kbin.AppendUvarint(dst, 0+uint32(v.UnknownTags.Len()))
+		dst = v.UnknownTags.AppendEach(dst)
+	}
+	return dst
+}
+
+func (v *ShareGroupDescribeRequest) ReadFrom(src []byte) error {
+	return v.readFrom(src, false)
+}
+
+func (v *ShareGroupDescribeRequest) UnsafeReadFrom(src []byte) error {
+	return v.readFrom(src, true)
+}
+
+func (v *ShareGroupDescribeRequest) readFrom(src []byte, unsafe bool) error {
+	v.Default()
+	b := kbin.Reader{Src: src}
+	version := v.Version
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	s := v
+	{
+		v := s.GroupIDs
+		a := v
+		var l int32
+		if isFlexible {
+			l = b.CompactArrayLen()
+		} else {
+			l = b.ArrayLen()
+		}
+		if !b.Ok() {
+			return b.Complete()
+		}
+		a = a[:0]
+		if l > 0 {
+			a = append(a, make([]string, l)...)
+		}
+		for i := int32(0); i < l; i++ {
+			var v string
+			if unsafe {
+				if isFlexible {
+					v = b.UnsafeCompactString()
+				} else {
+					v = b.UnsafeString()
+				}
+			} else {
+				if isFlexible {
+					v = b.CompactString()
+				} else {
+					v = b.String()
+				}
+			}
+			a[i] = v
+		}
+		v = a
+		s.GroupIDs = v
+	}
+	{
+		v := b.Bool()
+		s.IncludeAuthorizedOperations = v
+	}
+	if isFlexible {
+		s.UnknownTags = internalReadTags(&b)
+	}
+	return b.Complete()
+}
+
+// NewPtrShareGroupDescribeRequest returns a pointer to a default ShareGroupDescribeRequest
+// This is a shortcut for creating a new(struct) and calling Default yourself.
+func NewPtrShareGroupDescribeRequest() *ShareGroupDescribeRequest {
+	var v ShareGroupDescribeRequest
+	v.Default()
+	return &v
+}
+
+// Default sets any default fields. Calling this allows for future compatibility
+// if new fields are added to ShareGroupDescribeRequest.
+func (v *ShareGroupDescribeRequest) Default() {
+}
+
+// NewShareGroupDescribeRequest returns a default ShareGroupDescribeRequest
+// This is a shortcut for creating a struct and calling Default yourself.
+func NewShareGroupDescribeRequest() ShareGroupDescribeRequest {
+	var v ShareGroupDescribeRequest
+	v.Default()
+	return v
+}
+
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ShareGroupDescribeRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ShareGroupDescribeRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
+type ShareGroupDescribeResponseGroupMemberAssignmentTopicPartition struct {
+	// The topic ID.
+	TopicID [16]byte
+
+	// The topic name.
+	Topic string
+
+	// The partitions.
+	Partitions []int32
+
+	// UnknownTags are tags Kafka sent that we do not know the purpose of.
+	UnknownTags Tags
+}
+
+// Default sets any default fields. Calling this allows for future compatibility
+// if new fields are added to ShareGroupDescribeResponseGroupMemberAssignmentTopicPartition.
+func (v *ShareGroupDescribeResponseGroupMemberAssignmentTopicPartition) Default() {
+}
+
+// NewShareGroupDescribeResponseGroupMemberAssignmentTopicPartition returns a default ShareGroupDescribeResponseGroupMemberAssignmentTopicPartition
+// This is a shortcut for creating a struct and calling Default yourself.
+func NewShareGroupDescribeResponseGroupMemberAssignmentTopicPartition() ShareGroupDescribeResponseGroupMemberAssignmentTopicPartition {
+	var v ShareGroupDescribeResponseGroupMemberAssignmentTopicPartition
+	v.Default()
+	return v
+}
+
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ShareGroupDescribeResponseGroupMemberAssignmentTopicPartition) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ShareGroupDescribeResponseGroupMemberAssignmentTopicPartition) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
+type ShareGroupDescribeResponseGroupMemberAssignment struct {
+	// The topics & partitions assigned to the member.
+	TopicPartitions []ShareGroupDescribeResponseGroupMemberAssignmentTopicPartition
+
+	// UnknownTags are tags Kafka sent that we do not know the purpose of.
+	UnknownTags Tags
+}
+
+// Default sets any default fields. Calling this allows for future compatibility
+// if new fields are added to ShareGroupDescribeResponseGroupMemberAssignment.
+func (v *ShareGroupDescribeResponseGroupMemberAssignment) Default() {
+}
+
+// NewShareGroupDescribeResponseGroupMemberAssignment returns a default ShareGroupDescribeResponseGroupMemberAssignment
+// This is a shortcut for creating a struct and calling Default yourself.
+func NewShareGroupDescribeResponseGroupMemberAssignment() ShareGroupDescribeResponseGroupMemberAssignment {
+	var v ShareGroupDescribeResponseGroupMemberAssignment
+	v.Default()
+	return v
+}
+
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ShareGroupDescribeResponseGroupMemberAssignment) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ShareGroupDescribeResponseGroupMemberAssignment) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
+type ShareGroupDescribeResponseGroupMember struct {
+	// The member ID.
+	MemberID string
+
+	// The member rack ID, if any.
+	RackID *string
+
+	// The current member epoch.
+	MemberEpoch int32
+
+	// The client ID.
+	ClientID string
+
+	// The client host.
+	ClientHost string
+
+	// The subscribed topic names.
+	SubscribedTopicNames []string
+
+	// The current assignment.
+	Assignment ShareGroupDescribeResponseGroupMemberAssignment
+
+	// UnknownTags are tags Kafka sent that we do not know the purpose of.
+	UnknownTags Tags
+}
+
+// Default sets any default fields. Calling this allows for future compatibility
+// if new fields are added to ShareGroupDescribeResponseGroupMember.
+func (v *ShareGroupDescribeResponseGroupMember) Default() {
+	{
+		v := &v.Assignment
+		_ = v
+	}
+}
+
+// NewShareGroupDescribeResponseGroupMember returns a default ShareGroupDescribeResponseGroupMember
+// This is a shortcut for creating a struct and calling Default yourself.
+func NewShareGroupDescribeResponseGroupMember() ShareGroupDescribeResponseGroupMember {
+	var v ShareGroupDescribeResponseGroupMember
+	v.Default()
+	return v
+}
+
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ShareGroupDescribeResponseGroupMember) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ShareGroupDescribeResponseGroupMember) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
+type ShareGroupDescribeResponseGroup struct {
+	// ErrorCode is the error for this response.
+	//
+	// Supported errors:
+	// - GROUP_AUTHORIZATION_FAILED (version 0+)
+	// - NOT_COORDINATOR (version 0+)
+	// - COORDINATOR_NOT_AVAILABLE (version 0+)
+	// - COORDINATOR_LOAD_IN_PROGRESS (version 0+)
+	// - INVALID_REQUEST (version 0+)
+	// - INVALID_GROUP_ID (version 0+)
+	// - GROUP_ID_NOT_FOUND (version 0+)
+	ErrorCode int16
+
+	// A supplementary message if this errored.
+	ErrorMessage *string
+
+	// The group ID.
+	GroupID string
+
+	// The group state.
+	GroupState string
+
+	// The group epoch.
+	GroupEpoch int32
+
+	// The assignment epoch.
+	AssignmentEpoch int32
+
+	// The selected assignor.
+	Assignor string
+
+	// Members of the group.
+	Members []ShareGroupDescribeResponseGroupMember
+
+	// 32 bit bitfield representing authorized operations for the group.
+	//
+	// This field has a default of -2147483648.
+	AuthorizedOperations int32
+
+	// UnknownTags are tags Kafka sent that we do not know the purpose of.
+	UnknownTags Tags
+}
+
+// Default sets any default fields. Calling this allows for future compatibility
+// if new fields are added to ShareGroupDescribeResponseGroup.
+func (v *ShareGroupDescribeResponseGroup) Default() {
+	v.AuthorizedOperations = -2147483648
+}
+
+// NewShareGroupDescribeResponseGroup returns a default ShareGroupDescribeResponseGroup
+// This is a shortcut for creating a struct and calling Default yourself.
+func NewShareGroupDescribeResponseGroup() ShareGroupDescribeResponseGroup {
+	var v ShareGroupDescribeResponseGroup
+	v.Default()
+	return v
+}
+
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ShareGroupDescribeResponseGroup) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ShareGroupDescribeResponseGroup) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
+// ShareGroupDescribeResponse is returned from a ShareGroupDescribeRequest.
+type ShareGroupDescribeResponse struct {
+	// Version is the version of this message used with a Kafka broker.
+	Version int16
+
+	// ThrottleMillis is how long of a throttle Kafka will apply to the client
+	// after responding to this request.
+	ThrottleMillis int32
+
+	Groups []ShareGroupDescribeResponseGroup
+
+	// UnknownTags are tags Kafka sent that we do not know the purpose of.
+	UnknownTags Tags
+}
+
+func (*ShareGroupDescribeResponse) Key() int16                 { return 77 }
+func (*ShareGroupDescribeResponse) MaxVersion() int16          { return 0 }
+func (v *ShareGroupDescribeResponse) SetVersion(version int16) { v.Version = version }
+func (v *ShareGroupDescribeResponse) GetVersion() int16        { return v.Version }
+func (v *ShareGroupDescribeResponse) IsFlexible() bool         { return v.Version >= 0 }
+func (v *ShareGroupDescribeResponse) Throttle() (int32, bool) {
+	return v.ThrottleMillis, v.Version >= 0
+}
+func (v *ShareGroupDescribeResponse) SetThrottle(throttleMillis int32) {
+	v.ThrottleMillis = throttleMillis
+}
+func (v *ShareGroupDescribeResponse) RequestKind() Request {
+	return &ShareGroupDescribeRequest{Version: v.Version}
+}
+
+func (v *ShareGroupDescribeResponse) AppendTo(dst []byte) []byte {
+	version := v.Version
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	{
+		v := v.ThrottleMillis
+		dst = kbin.AppendInt32(dst, v)
+	}
+	{
+		v := v.Groups
+		if isFlexible {
+			dst = kbin.AppendCompactArrayLen(dst, len(v))
+		} else {
+			dst = kbin.AppendArrayLen(dst, len(v))
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.ErrorCode
+				dst = kbin.AppendInt16(dst, v)
+			}
+			{
+				v := v.ErrorMessage
+				if isFlexible {
+					dst = kbin.AppendCompactNullableString(dst, v)
+				} else {
+					dst = kbin.AppendNullableString(dst, v)
+				}
+			}
+			{
+				v := v.GroupID
+				if isFlexible {
+					dst = kbin.AppendCompactString(dst, v)
+				} else {
+					dst = kbin.AppendString(dst, v)
+				}
+			}
+			{
+				v := v.GroupState
+				if isFlexible {
+					dst = kbin.AppendCompactString(dst, v)
+				} else {
+					dst = kbin.AppendString(dst, v)
+				}
+			}
+			{
+				v := v.GroupEpoch
+				dst = kbin.AppendInt32(dst, v)
+			}
+			{
+				v := v.AssignmentEpoch
+				dst = kbin.AppendInt32(dst, v)
+			}
+			{
+				v := v.Assignor
+				if isFlexible {
+					dst = kbin.AppendCompactString(dst, v)
+				} else {
+					dst = kbin.AppendString(dst, v)
+				}
+			}
+			{
+				v := v.Members
+				if isFlexible {
+					dst = kbin.AppendCompactArrayLen(dst, len(v))
+				} else {
+					dst = kbin.AppendArrayLen(dst, len(v))
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						v := v.MemberID
+						if isFlexible {
+							dst = kbin.AppendCompactString(dst, v)
+						} else {
+							dst = kbin.AppendString(dst, v)
+						}
+					}
+					{
+						v := v.RackID
+						if isFlexible {
+							dst = kbin.AppendCompactNullableString(dst, v)
+						} else {
+							dst = kbin.AppendNullableString(dst, v)
+						}
+					}
+					{
+						v := v.MemberEpoch
+						dst = kbin.AppendInt32(dst, v)
+					}
+					{
+						v := v.ClientID
+						if isFlexible {
+							dst = kbin.AppendCompactString(dst, v)
+						} else {
+							dst = kbin.AppendString(dst, v)
+						}
+					}
+					{
+						v := v.ClientHost
+						if isFlexible {
+							dst = kbin.AppendCompactString(dst, v)
+						} else {
+							dst = kbin.AppendString(dst, v)
+						}
+					}
+					{
+						v := v.SubscribedTopicNames
+						if isFlexible {
+							dst = kbin.AppendCompactArrayLen(dst, len(v))
+						} else {
+							dst = kbin.AppendArrayLen(dst, len(v))
+						}
+						for i := range v {
+							v := v[i]
+							if isFlexible {
+								dst = kbin.AppendCompactString(dst, v)
+							} else {
+								dst = kbin.AppendString(dst, v)
+							}
+						}
+					}
+					{
+						v := &v.Assignment
+						{
+							v := v.TopicPartitions
+							if isFlexible {
+								dst = kbin.AppendCompactArrayLen(dst, len(v))
+							} else {
+								dst = kbin.AppendArrayLen(dst, len(v))
+							}
+							for i := range v {
+								v := &v[i]
+								{
+									v := v.TopicID
+									dst = kbin.AppendUuid(dst, v)
+								}
+								{
+									v := v.Topic
+									if isFlexible {
+										dst = kbin.AppendCompactString(dst, v)
+									} else {
+										dst = kbin.AppendString(dst, v)
+									}
+								}
+								{
+									v := v.Partitions
+									if isFlexible {
+										dst = kbin.AppendCompactArrayLen(dst, len(v))
+									} else {
+										dst = kbin.AppendArrayLen(dst, len(v))
+									}
+									for i := range v {
+										v := v[i]
+										dst = kbin.AppendInt32(dst, v)
+									}
+								}
+								if isFlexible {
+									dst = kbin.AppendUvarint(dst, 0+uint32(v.UnknownTags.Len()))
+									dst = v.UnknownTags.AppendEach(dst)
+								}
+							}
+						}
+						if isFlexible {
+							dst = kbin.AppendUvarint(dst, 0+uint32(v.UnknownTags.Len()))
+							dst = v.UnknownTags.AppendEach(dst)
+						}
+					}
+					if isFlexible {
+						dst = kbin.AppendUvarint(dst, 0+uint32(v.UnknownTags.Len()))
+						dst = v.UnknownTags.AppendEach(dst)
+					}
+				}
+			}
+			{
+				v := v.AuthorizedOperations
+				dst = kbin.AppendInt32(dst, v)
+			}
+			if isFlexible {
+				dst = kbin.AppendUvarint(dst, 0+uint32(v.UnknownTags.Len()))
+				dst = v.UnknownTags.AppendEach(dst)
+			}
+		}
+	}
+	if isFlexible {
+		dst = kbin.AppendUvarint(dst, 0+uint32(v.UnknownTags.Len()))
+		dst = v.UnknownTags.AppendEach(dst)
+	}
+	return dst
+}
+
+func (v *ShareGroupDescribeResponse) ReadFrom(src []byte) error {
+	return v.readFrom(src, false)
+}
+
+func (v *ShareGroupDescribeResponse) UnsafeReadFrom(src []byte) error {
+	return v.readFrom(src, true)
+}
+
+func (v *ShareGroupDescribeResponse) readFrom(src []byte, unsafe bool) error {
+	v.Default()
+	b := kbin.Reader{Src: src}
+	version := v.Version
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	s := v
+	{
+		v := b.Int32()
+		s.ThrottleMillis = v
+	}
+	{
+		v := s.Groups
+		a := v
+		var l int32
+		if isFlexible {
+			l = b.CompactArrayLen()
+		} else {
+			l = b.ArrayLen()
+		}
+		if !b.Ok() {
+			return b.Complete()
+		}
+		a = a[:0]
+		if l > 0 {
+			a = append(a, make([]ShareGroupDescribeResponseGroup, l)...)
+		}
+		for i := int32(0); i < l; i++ {
+			v := &a[i]
+			v.Default()
+			s := v
+			{
+				v := b.Int16()
+				s.ErrorCode = v
+			}
+			{
+				var v *string
+				if isFlexible {
+					if unsafe {
+						v = b.UnsafeCompactNullableString()
+					} else {
+						v = b.CompactNullableString()
+					}
+				} else {
+					if unsafe {
+						v = b.UnsafeNullableString()
+					} else {
+						v = b.NullableString()
+					}
+				}
+				s.ErrorMessage = v
+			}
+			{
+				var v string
+				if unsafe {
+					if isFlexible {
+						v = b.UnsafeCompactString()
+					} else {
+						v = b.UnsafeString()
+					}
+				} else {
+					if isFlexible {
+						v = b.CompactString()
+					} else {
+						v = b.String()
+					}
+				}
+				s.GroupID = v
+			}
+			{
+				var v string
+				if unsafe {
+					if isFlexible {
+						v = b.UnsafeCompactString()
+					} else {
+						v = b.UnsafeString()
+					}
+				} else {
+					if isFlexible {
+						v = b.CompactString()
+					} else {
+						v = b.String()
+					}
+				}
+				s.GroupState = v
+			}
+			{
+				v := b.Int32()
+				s.GroupEpoch = v
+			}
+			{
+				v := b.Int32()
+				s.AssignmentEpoch = v
+			}
+			{
+				var v string
+				if unsafe {
+					if isFlexible {
+						v = b.UnsafeCompactString()
+					} else {
+						v = b.UnsafeString()
+					}
+				} else {
+					if isFlexible {
+						v = b.CompactString()
+					} else {
+						v = b.String()
+					}
+				}
+				s.Assignor = v
+			}
+			{
+				v := s.Members
+				a := v
+				var l int32
+				if isFlexible {
+					l = b.CompactArrayLen()
+				} else {
+					l = b.ArrayLen()
+				}
+				if !b.Ok() {
+					return b.Complete()
+				}
+				a = a[:0]
+				if l > 0 {
+					a = append(a, make([]ShareGroupDescribeResponseGroupMember, l)...)
+				}
+				for i := int32(0); i < l; i++ {
+					v := &a[i]
+					v.Default()
+					s := v
+					{
+						var v string
+						if unsafe {
+							if isFlexible {
+								v = b.UnsafeCompactString()
+							} else {
+								v = b.UnsafeString()
+							}
+						} else {
+							if isFlexible {
+								v = b.CompactString()
+							} else {
+								v = b.String()
+							}
+						}
+						s.MemberID = v
+					}
+					{
+						var v *string
+						if isFlexible {
+							if unsafe {
+								v = b.UnsafeCompactNullableString()
+							} else {
+								v = b.CompactNullableString()
+							}
+						} else {
+							if unsafe {
+								v = b.UnsafeNullableString()
+							} else {
+								v = b.NullableString()
+							}
+						}
+						s.RackID = v
+					}
+					{
+						v := b.Int32()
+						s.MemberEpoch = v
+					}
+					{
+						var v string
+						if unsafe {
+							if isFlexible {
+								v = b.UnsafeCompactString()
+							} else {
+								v = b.UnsafeString()
+							}
+						} else {
+							if isFlexible {
+								v = b.CompactString()
+							} else {
+								v = b.String()
+							}
+						}
+						s.ClientID = v
+					}
+					{
+						var v string
+						if unsafe {
+							if isFlexible {
+								v = b.UnsafeCompactString()
+							} else {
+								v = b.UnsafeString()
+							}
+						} else {
+							if isFlexible {
+								v = b.CompactString()
+							} else {
+								v = b.String()
+							}
+						}
+						s.ClientHost = v
+					}
+					{
+						v := s.SubscribedTopicNames
+						a := v
+						var l int32
+						if isFlexible {
+							l = b.CompactArrayLen()
+						} else {
+							l = b.ArrayLen()
+						}
+						if !b.Ok() {
+							return b.Complete()
+						}
+						a = a[:0]
+						if l > 0 {
+							a = append(a, make([]string, l)...)
+						}
+						for i := int32(0); i < l; i++ {
+							var v string
+							if unsafe {
+								if isFlexible {
+									v = b.UnsafeCompactString()
+								} else {
+									v = b.UnsafeString()
+								}
+							} else {
+								if isFlexible {
+									v = b.CompactString()
+								} else {
+									v = b.String()
+								}
+							}
+							a[i] = v
+						}
+						v = a
+						s.SubscribedTopicNames = v
+					}
+					{
+						v := &s.Assignment
+						v.Default()
+						s := v
+						{
+							v := s.TopicPartitions
+							a := v
+							var l int32
+							if isFlexible {
+								l = b.CompactArrayLen()
+							} else {
+								l = b.ArrayLen()
+							}
+							if !b.Ok() {
+								return b.Complete()
+							}
+							a = a[:0]
+							if l > 0 {
+								a = append(a, make([]ShareGroupDescribeResponseGroupMemberAssignmentTopicPartition, l)...)
+							}
+							for i := int32(0); i < l; i++ {
+								v := &a[i]
+								v.Default()
+								s := v
+								{
+									v := b.Uuid()
+									s.TopicID = v
+								}
+								{
+									var v string
+									if unsafe {
+										if isFlexible {
+											v = b.UnsafeCompactString()
+										} else {
+											v = b.UnsafeString()
+										}
+									} else {
+										if isFlexible {
+											v = b.CompactString()
+										} else {
+											v = b.String()
+										}
+									}
+									s.Topic = v
+								}
+								{
+									v := s.Partitions
+									a := v
+									var l int32
+									if isFlexible {
+										l = b.CompactArrayLen()
+									} else {
+										l = b.ArrayLen()
+									}
+									if !b.Ok() {
+										return b.Complete()
+									}
+									a = a[:0]
+									if l > 0 {
+										a = append(a, make([]int32, l)...)
+									}
+									for i := int32(0); i < l; i++ {
+										v := b.Int32()
+										a[i] = v
+									}
+									v = a
+									s.Partitions = v
+								}
+								if isFlexible {
+									s.UnknownTags = internalReadTags(&b)
+								}
+							}
+							v = a
+							s.TopicPartitions = v
+						}
+						if isFlexible {
+							s.UnknownTags = internalReadTags(&b)
+						}
+					}
+					if isFlexible {
+						s.UnknownTags = internalReadTags(&b)
+					}
+				}
+				v = a
+				s.Members = v
+			}
+			{
+				v := b.Int32()
+				s.AuthorizedOperations = v
+			}
+			if isFlexible {
+				s.UnknownTags = internalReadTags(&b)
+			}
+		}
+		v = a
+		s.Groups = v
+	}
+	if isFlexible {
+		s.UnknownTags = internalReadTags(&b)
+	}
+	return b.Complete()
+}
+
+// NewPtrShareGroupDescribeResponse returns a pointer to a default ShareGroupDescribeResponse
+// This is a shortcut for creating a new(struct) and calling Default yourself.
+func NewPtrShareGroupDescribeResponse() *ShareGroupDescribeResponse {
+	var v ShareGroupDescribeResponse
+	v.Default()
+	return &v
+}
+
+// Default sets any default fields. Calling this allows for future compatibility
+// if new fields are added to ShareGroupDescribeResponse.
+func (v *ShareGroupDescribeResponse) Default() {
+}
+
+// NewShareGroupDescribeResponse returns a default ShareGroupDescribeResponse
+// This is a shortcut for creating a struct and calling Default yourself.
+func NewShareGroupDescribeResponse() ShareGroupDescribeResponse {
+	var v ShareGroupDescribeResponse
+	v.Default()
+	return v
+}
+
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ShareGroupDescribeResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ShareGroupDescribeResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
+type ShareFetchRequestTopicPartitionAcknowledgementBatch struct {
+	// First offset of batch of records to acknowledge.
+	FirstOffset int64
+
+	// Last offset (inclusive) of batch of records to acknowledge.
+	LastOffset int64
+
+	// Array of acknowledge types: 0 is a gap, 1 is accept, 2 is
+	// release, 3 is reject. A single type applies to the entire batch.
+	AcknowledgeTypes []int8
+
+	// UnknownTags are tags Kafka sent that we do not know the purpose of.
+	UnknownTags Tags
+}
+
+// Default sets any default fields. Calling this allows for future compatibility
+// if new fields are added to ShareFetchRequestTopicPartitionAcknowledgementBatch.
+func (v *ShareFetchRequestTopicPartitionAcknowledgementBatch) Default() {
+}
+
+// NewShareFetchRequestTopicPartitionAcknowledgementBatch returns a default ShareFetchRequestTopicPartitionAcknowledgementBatch
+// This is a shortcut for creating a struct and calling Default yourself.
+func NewShareFetchRequestTopicPartitionAcknowledgementBatch() ShareFetchRequestTopicPartitionAcknowledgementBatch {
+	var v ShareFetchRequestTopicPartitionAcknowledgementBatch
+	v.Default()
+	return v
+}
+
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ShareFetchRequestTopicPartitionAcknowledgementBatch) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ShareFetchRequestTopicPartitionAcknowledgementBatch) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
+type ShareFetchRequestTopicPartition struct {
+	// The partition index.
+	Partition int32
+
+	// The maximum bytes to fetch from this partition.
+	PartitionMaxBytes int32
+
+	// Record batches to acknowledge.
+	AcknowledgementBatches []ShareFetchRequestTopicPartitionAcknowledgementBatch
+
+	// UnknownTags are tags Kafka sent that we do not know the purpose of.
+	UnknownTags Tags
+}
+
+// Default sets any default fields. Calling this allows for future compatibility
+// if new fields are added to ShareFetchRequestTopicPartition.
+func (v *ShareFetchRequestTopicPartition) Default() {
+}
+
+// NewShareFetchRequestTopicPartition returns a default ShareFetchRequestTopicPartition
+// This is a shortcut for creating a struct and calling Default yourself.
+func NewShareFetchRequestTopicPartition() ShareFetchRequestTopicPartition {
+	var v ShareFetchRequestTopicPartition
+	v.Default()
+	return v
+}
+
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ShareFetchRequestTopicPartition) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ShareFetchRequestTopicPartition) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
+type ShareFetchRequestTopic struct {
+	// The unique topic ID.
+	TopicID [16]byte
+
+	// The partitions to fetch.
+	Partitions []ShareFetchRequestTopicPartition
+
+	// UnknownTags are tags Kafka sent that we do not know the purpose of.
+	UnknownTags Tags
+}
+
+// Default sets any default fields. Calling this allows for future compatibility
+// if new fields are added to ShareFetchRequestTopic.
+func (v *ShareFetchRequestTopic) Default() {
+}
+
+// NewShareFetchRequestTopic returns a default ShareFetchRequestTopic
+// This is a shortcut for creating a struct and calling Default yourself.
+func NewShareFetchRequestTopic() ShareFetchRequestTopic {
+	var v ShareFetchRequestTopic
+	v.Default()
+	return v
+}
+
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ShareFetchRequestTopic) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ShareFetchRequestTopic) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
+type ShareFetchRequestForgottenTopicsData struct {
+	// The unique topic ID.
+	TopicID [16]byte
+
+	// The partitions indexes to forget.
+	Partitions []int32
+
+	// UnknownTags are tags Kafka sent that we do not know the purpose of.
+	UnknownTags Tags
+}
+
+// Default sets any default fields. Calling this allows for future compatibility
+// if new fields are added to ShareFetchRequestForgottenTopicsData.
+func (v *ShareFetchRequestForgottenTopicsData) Default() {
+}
+
+// NewShareFetchRequestForgottenTopicsData returns a default ShareFetchRequestForgottenTopicsData
+// This is a shortcut for creating a struct and calling Default yourself.
+func NewShareFetchRequestForgottenTopicsData() ShareFetchRequestForgottenTopicsData {
+	var v ShareFetchRequestForgottenTopicsData
+	v.Default()
+	return v
+}
+
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ShareFetchRequestForgottenTopicsData) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ShareFetchRequestForgottenTopicsData) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
+// ShareFetch is a part of KIP-932 and is used by share group members to
+// acquire records. Records returned are acquired by the member for a limited
+// time and must be acknowledged (accepted, released, or rejected) with either
+// a subsequent ShareFetch or a ShareAcknowledge request.
+type ShareFetchRequest struct {
+	// Version is the version of this message used with a Kafka broker.
+	Version int16
+
+	// The group ID.
+	GroupID *string
+
+	// The member ID.
+	MemberID *string
+
+	// The current share session epoch: 0 to open a share session; -1 to close
+	// it; otherwise increments for consecutive requests.
+	ShareSessionEpoch int32
+
+	// The maximum time in milliseconds to wait for the response.
+	MaxWaitMillis int32
+
+	// The minimum bytes to accumulate in the response.
+	MinBytes int32
+
+	// The maximum bytes to fetch.
+	//
+	// This field has a default of 0x7fffffff.
+	MaxBytes int32
+
+	// The topics to fetch.
+	Topics []ShareFetchRequestTopic
+
+	// The partitions to remove from this share session.
+	ForgottenTopicsData []ShareFetchRequestForgottenTopicsData
+
+	// UnknownTags are tags Kafka sent that we do not know the purpose of.
+	UnknownTags Tags
+}
+
+func (*ShareFetchRequest) Key() int16                 { return 78 }
+func (*ShareFetchRequest) MaxVersion() int16          { return 0 }
+func (v *ShareFetchRequest) SetVersion(version int16) { v.Version = version }
+func (v *ShareFetchRequest) GetVersion() int16        { return v.Version }
+func (v *ShareFetchRequest) IsFlexible() bool         { return v.Version >= 0 }
+func (v *ShareFetchRequest) ResponseKind() Response {
+	r := &ShareFetchResponse{Version: v.Version}
+	r.Default()
+	return r
+}
+
+// RequestWith is requests v on r and returns the response or an error.
+// For sharded requests, the response may be merged and still return an error.
+// It is better to rely on client.RequestSharded than to rely on proper merging behavior.
+func (v *ShareFetchRequest) RequestWith(ctx context.Context, r Requestor) (*ShareFetchResponse, error) {
+	kresp, err := r.Request(ctx, v)
+	resp, _ := kresp.(*ShareFetchResponse)
+	return resp, err
+}
+
+func (v *ShareFetchRequest) AppendTo(dst []byte) []byte {
+	version := v.Version
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	{
+		v := v.GroupID
+		if isFlexible {
+			dst = kbin.AppendCompactNullableString(dst, v)
+		} else {
+			dst = kbin.AppendNullableString(dst, v)
+		}
+	}
+	{
+		v := v.MemberID
+		if isFlexible {
+			dst = kbin.AppendCompactNullableString(dst, v)
+		} else {
+			dst = kbin.AppendNullableString(dst, v)
+		}
+	}
+	{
+		v := v.ShareSessionEpoch
+		dst = kbin.AppendInt32(dst, v)
+	}
+	{
+		v := v.MaxWaitMillis
+		dst = kbin.AppendInt32(dst, v)
+	}
+	{
+		v := v.MinBytes
+		dst = kbin.AppendInt32(dst, v)
+	}
+	{
+		v := v.MaxBytes
+		dst = kbin.AppendInt32(dst, v)
+	}
+	{
+		v := v.Topics
+		if isFlexible {
+			dst = kbin.AppendCompactArrayLen(dst, len(v))
+		} else {
+			dst = kbin.AppendArrayLen(dst, len(v))
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.TopicID
+				dst = kbin.AppendUuid(dst, v)
+			}
+			{
+				v := v.Partitions
+				if isFlexible {
+					dst = kbin.AppendCompactArrayLen(dst, len(v))
+				} else {
+					dst = kbin.AppendArrayLen(dst, len(v))
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						v := v.Partition
+						dst = kbin.AppendInt32(dst, v)
+					}
+					{
+						v := v.PartitionMaxBytes
+						dst = kbin.AppendInt32(dst, v)
+					}
+					{
+						v := v.AcknowledgementBatches
+						if isFlexible {
+							dst = kbin.AppendCompactArrayLen(dst, len(v))
+						} else {
+							dst = kbin.AppendArrayLen(dst, len(v))
+						}
+						for i := range v {
+							v := &v[i]
+							{
+								v := v.FirstOffset
+								dst = kbin.AppendInt64(dst, v)
+							}
+							{
+								v := v.LastOffset
+								dst = kbin.AppendInt64(dst, v)
+							}
+							{
+								v := v.AcknowledgeTypes
+								if isFlexible {
+									dst = kbin.AppendCompactArrayLen(dst, len(v))
+								} else {
+									dst = kbin.AppendArrayLen(dst, len(v))
+								}
+								for i := range v {
+									v := v[i]
+									dst = kbin.AppendInt8(dst, v)
+								}
+							}
+							if isFlexible {
+								dst = kbin.AppendUvarint(dst, 0+uint32(v.UnknownTags.Len()))
+								dst = v.UnknownTags.AppendEach(dst)
+							}
+						}
+					}
+					if isFlexible {
+						dst = kbin.AppendUvarint(dst, 0+uint32(v.UnknownTags.Len()))
+						dst = v.UnknownTags.AppendEach(dst)
+					}
+				}
+			}
+			if isFlexible {
+				dst = kbin.AppendUvarint(dst, 0+uint32(v.UnknownTags.Len()))
+				dst = v.UnknownTags.AppendEach(dst)
+			}
+		}
+	}
+	{
+		v := v.ForgottenTopicsData
+		if isFlexible {
+			dst = kbin.AppendCompactArrayLen(dst, len(v))
+		} else {
+			dst = kbin.AppendArrayLen(dst, len(v))
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.TopicID
+				dst = kbin.AppendUuid(dst, v)
+			}
+			{
+				v := v.Partitions
+				if isFlexible {
+					dst = kbin.AppendCompactArrayLen(dst, len(v))
+				} else {
+					dst = kbin.AppendArrayLen(dst, len(v))
+				}
+				for i := range v {
+					v := v[i]
+					dst = kbin.AppendInt32(dst, v)
+				}
+			}
+			if isFlexible {
+				dst = kbin.AppendUvarint(dst, 0+uint32(v.UnknownTags.Len()))
+				dst = v.UnknownTags.AppendEach(dst)
+			}
+		}
+	}
+	if isFlexible {
+		dst = kbin.AppendUvarint(dst, 0+uint32(v.UnknownTags.Len()))
+		dst = v.UnknownTags.AppendEach(dst)
+	}
+	return dst
+}
+
+func (v *ShareFetchRequest) ReadFrom(src []byte) error {
+	return v.readFrom(src, false)
+}
+
+func (v *ShareFetchRequest) UnsafeReadFrom(src []byte) error {
+	return v.readFrom(src, true)
+}
+
+func (v *ShareFetchRequest) readFrom(src []byte, unsafe bool) error {
+	v.Default()
+	b := kbin.Reader{Src: src}
+	version := v.Version
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	s := v
+	{
+		var v *string
+		if isFlexible {
+			if unsafe {
+				v = b.UnsafeCompactNullableString()
+			} else {
+				v = b.CompactNullableString()
+			}
+		} else {
+			if unsafe {
+				v = b.UnsafeNullableString()
+			} else {
+				v = b.NullableString()
+			}
+		}
+		s.GroupID = v
+	}
+	{
+		var v *string
+		if isFlexible {
+			if unsafe {
+				v = b.UnsafeCompactNullableString()
+			} else {
+				v = b.CompactNullableString()
+			}
+		} else {
+			if unsafe {
+				v = b.UnsafeNullableString()
+			} else {
+				v = b.NullableString()
+			}
+		}
+		s.MemberID = v
+	}
+	{
+		v := b.Int32()
+		s.ShareSessionEpoch = v
+	}
+	{
+		v := b.Int32()
+		s.MaxWaitMillis = v
+	}
+	{
+		v := b.Int32()
+		s.MinBytes = v
+	}
+	{
+		v := b.Int32()
+		s.MaxBytes = v
+	}
+	{
+		v := s.Topics
+		a := v
+		var l int32
+		if isFlexible {
+			l = b.CompactArrayLen()
+		} else {
+			l = b.ArrayLen()
+		}
+		if !b.Ok() {
+			return b.Complete()
+		}
+		a = a[:0]
+		if l > 0 {
+			a = append(a, make([]ShareFetchRequestTopic, l)...)
+		}
+		for i := int32(0); i < l; i++ {
+			v := &a[i]
+			v.Default()
+			s := v
+			{
+				v := b.Uuid()
+				s.TopicID = v
+			}
+			{
+				v := s.Partitions
+				a := v
+				var l int32
+				if isFlexible {
+					l = b.CompactArrayLen()
+				} else {
+					l = b.ArrayLen()
+				}
+				if !b.Ok() {
+					return b.Complete()
+				}
+				a = a[:0]
+				if l > 0 {
+					a = append(a, make([]ShareFetchRequestTopicPartition, l)...)
+				}
+				for i := int32(0); i < l; i++ {
+					v := &a[i]
+					v.Default()
+					s := v
+					{
+						v := b.Int32()
+						s.Partition = v
+					}
+					{
+						v := b.Int32()
+						s.PartitionMaxBytes = v
+					}
+					{
+						v := s.AcknowledgementBatches
+						a := v
+						var l int32
+						if isFlexible {
+							l = b.CompactArrayLen()
+						} else {
+							l = b.ArrayLen()
+						}
+						if !b.Ok() {
+							return b.Complete()
+						}
+						a = a[:0]
+						if l > 0 {
+							a = append(a, make([]ShareFetchRequestTopicPartitionAcknowledgementBatch, l)...)
+						}
+						for i := int32(0); i < l; i++ {
+							v := &a[i]
+							v.Default()
+							s := v
+							{
+								v := b.Int64()
+								s.FirstOffset = v
+							}
+							{
+								v := b.Int64()
+								s.LastOffset = v
+							}
+							{
+								v := s.AcknowledgeTypes
+								a := v
+								var l int32
+								if isFlexible {
+									l = b.CompactArrayLen()
+								} else {
+									l = b.ArrayLen()
+								}
+								if !b.Ok() {
+									return b.Complete()
+								}
+								a = a[:0]
+								if l > 0 {
+									a = append(a, make([]int8, l)...)
+								}
+								for i := int32(0); i < l; i++ {
+									v := b.Int8()
+									a[i] = v
+								}
+								v = a
+								s.AcknowledgeTypes = v
+							}
+							if isFlexible {
+								s.UnknownTags = internalReadTags(&b)
+							}
+						}
+						v = a
+						s.AcknowledgementBatches = v
+					}
+					if isFlexible {
+						s.UnknownTags = internalReadTags(&b)
+					}
+				}
+				v = a
+				s.Partitions = v
+			}
+			if isFlexible {
+				s.UnknownTags = internalReadTags(&b)
+			}
+		}
+		v = a
+		s.Topics = v
+	}
+	{
+		v := s.ForgottenTopicsData
+		a := v
+		var l int32
+		if isFlexible {
+			l = b.CompactArrayLen()
+		} else {
+			l = b.ArrayLen()
+		}
+		if !b.Ok() {
+			return b.Complete()
+		}
+		a = a[:0]
+		if l > 0 {
+			a = append(a, make([]ShareFetchRequestForgottenTopicsData, l)...)
+		}
+		for i := int32(0); i < l; i++ {
+			v := &a[i]
+			v.Default()
+			s := v
+			{
+				v := b.Uuid()
+				s.TopicID = v
+			}
+			{
+				v := s.Partitions
+				a := v
+				var l int32
+				if isFlexible {
+					l = b.CompactArrayLen()
+				} else {
+					l = b.ArrayLen()
+				}
+				if !b.Ok() {
+					return b.Complete()
+				}
+				a = a[:0]
+				if l > 0 {
+					a = append(a, make([]int32, l)...)
+				}
+				for i := int32(0); i < l; i++ {
+					v := b.Int32()
+					a[i] = v
+				}
+				v = a
+				s.Partitions = v
+			}
+			if isFlexible {
+				s.UnknownTags = internalReadTags(&b)
+			}
+		}
+		v = a
+		s.ForgottenTopicsData = v
+	}
+	if isFlexible {
+		s.UnknownTags = internalReadTags(&b)
+	}
+	return b.Complete()
+}
+
+// NewPtrShareFetchRequest returns a pointer to a default ShareFetchRequest
+// This is a shortcut for creating a new(struct) and calling Default yourself.
+func NewPtrShareFetchRequest() *ShareFetchRequest {
+	var v ShareFetchRequest
+	v.Default()
+	return &v
+}
+
+// Default sets any default fields. Calling this allows for future compatibility
+// if new fields are added to ShareFetchRequest.
+func (v *ShareFetchRequest) Default() {
+	v.MaxBytes = 2147483647
+}
+
+// NewShareFetchRequest returns a default ShareFetchRequest
+// This is a shortcut for creating a struct and calling Default yourself.
+func NewShareFetchRequest() ShareFetchRequest {
+	var v ShareFetchRequest
+	v.Default()
+	return v
+}
+
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ShareFetchRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ShareFetchRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
+type ShareFetchResponseTopicPartitionCurrentLeader struct {
+	// The ID of the current leader, or -1 if unknown.
+	//
+	// This field has a default of -1.
+	LeaderID int32
+
+	// The latest known leader epoch.
+	//
+	// This field has a default of -1.
+	LeaderEpoch int32
+
+	// UnknownTags are tags Kafka sent that we do not know the purpose of.
+	UnknownTags Tags
+}
+
+// Default sets any default fields. Calling this allows for future compatibility
+// if new fields are added to ShareFetchResponseTopicPartitionCurrentLeader.
+func (v *ShareFetchResponseTopicPartitionCurrentLeader) Default() {
+	v.LeaderID = -1
+	v.LeaderEpoch = -1
+}
+
+// NewShareFetchResponseTopicPartitionCurrentLeader returns a default ShareFetchResponseTopicPartitionCurrentLeader
+// This is a shortcut for creating a struct and calling Default yourself.
+func NewShareFetchResponseTopicPartitionCurrentLeader() ShareFetchResponseTopicPartitionCurrentLeader {
+	var v ShareFetchResponseTopicPartitionCurrentLeader
+	v.Default()
+	return v
+}
+
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ShareFetchResponseTopicPartitionCurrentLeader) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ShareFetchResponseTopicPartitionCurrentLeader) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
+type ShareFetchResponseTopicPartitionAcquiredRecord struct {
+	// The earliest offset in this batch of acquired records.
+	FirstOffset int64
+
+	// The last offset (inclusive) of this batch of acquired records.
+	LastOffset int64
+
+	// The number of times this batch has been delivered.
+	DeliveryCount int16
+
+	// UnknownTags are tags Kafka sent that we do not know the purpose of.
+	UnknownTags Tags
+}
+
+// Default sets any default fields. Calling this allows for future compatibility
+// if new fields are added to ShareFetchResponseTopicPartitionAcquiredRecord.
+func (v *ShareFetchResponseTopicPartitionAcquiredRecord) Default() {
+}
+
+// NewShareFetchResponseTopicPartitionAcquiredRecord returns a default ShareFetchResponseTopicPartitionAcquiredRecord
+// This is a shortcut for creating a struct and calling Default yourself.
+func NewShareFetchResponseTopicPartitionAcquiredRecord() ShareFetchResponseTopicPartitionAcquiredRecord {
+	var v ShareFetchResponseTopicPartitionAcquiredRecord
+	v.Default()
+	return v
+}
+
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ShareFetchResponseTopicPartitionAcquiredRecord) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ShareFetchResponseTopicPartitionAcquiredRecord) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
+type ShareFetchResponseTopicPartition struct {
+	// The partition index.
+	Partition int32
+
+	// The fetch error code, or 0 if there was no fetch error.
+	ErrorCode int16
+
+	// The fetch error message, or null if there was no fetch error.
+	ErrorMessage *string
+
+	// The acknowledge error code, or 0 if there was no acknowledge error.
+	AcknowledgeErrorCode int16
+
+	// The acknowledge error message, or null if there was no acknowledge
+	// error.
+	AcknowledgeErrorMessage *string
+
+	// CurrentLeader is the currently known leader ID and epoch for this
+	// partition.
+	CurrentLeader ShareFetchResponseTopicPartitionCurrentLeader
+
+	// The record data.
+	Records []byte
+
+	// The acquired records.
+	AcquiredRecords []ShareFetchResponseTopicPartitionAcquiredRecord
+
+	// UnknownTags are tags Kafka sent that we do not know the purpose of.
+	UnknownTags Tags
+}
+
+// Default sets any default fields. Calling this allows for future compatibility
+// if new fields are added to ShareFetchResponseTopicPartition.
+func (v *ShareFetchResponseTopicPartition) Default() {
+	{
+		v := &v.CurrentLeader
+		_ = v
+		v.LeaderID = -1
+		v.LeaderEpoch = -1
+	}
+}
+
+// NewShareFetchResponseTopicPartition returns a default ShareFetchResponseTopicPartition
+// This is a shortcut for creating a struct and calling Default yourself.
+func NewShareFetchResponseTopicPartition() ShareFetchResponseTopicPartition {
+	var v ShareFetchResponseTopicPartition
+	v.Default()
+	return v
+}
+
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ShareFetchResponseTopicPartition) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ShareFetchResponseTopicPartition) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
+type ShareFetchResponseTopic struct {
+	// The unique topic ID.
+	TopicID [16]byte
+
+	// The topic partitions.
+	Partitions []ShareFetchResponseTopicPartition
+
+	// UnknownTags are tags Kafka sent that we do not know the purpose of.
+	UnknownTags Tags
+}
+
+// Default sets any default fields. Calling this allows for future compatibility
+// if new fields are added to ShareFetchResponseTopic.
+func (v *ShareFetchResponseTopic) Default() {
+}
+
+// NewShareFetchResponseTopic returns a default ShareFetchResponseTopic
+// This is a shortcut for creating a struct and calling Default yourself.
+func NewShareFetchResponseTopic() ShareFetchResponseTopic {
+	var v ShareFetchResponseTopic
+	v.Default()
+	return v
+}
+
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ShareFetchResponseTopic) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ShareFetchResponseTopic) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
+type ShareFetchResponseNodeEndpoint struct {
+	// NodeID is the node ID of a Kafka broker.
+	NodeID int32
+
+	// Host is the hostname of a Kafka broker.
+	Host string
+
+	// Port is the port of a Kafka broker.
+	Port int32
+
+	// Rack is the rack this Kafka broker is in.
+	Rack *string
+
+	// UnknownTags are tags Kafka sent that we do not know the purpose of.
+	UnknownTags Tags
+}
+
+// Default sets any default fields. Calling this allows for future compatibility
+// if new fields are added to ShareFetchResponseNodeEndpoint.
+func (v *ShareFetchResponseNodeEndpoint) Default() {
+}
+
+// NewShareFetchResponseNodeEndpoint returns a default ShareFetchResponseNodeEndpoint
+// This is a shortcut for creating a struct and calling Default yourself.
+func NewShareFetchResponseNodeEndpoint() ShareFetchResponseNodeEndpoint {
+	var v ShareFetchResponseNodeEndpoint
+	v.Default()
+	return v
+}
+
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ShareFetchResponseNodeEndpoint) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ShareFetchResponseNodeEndpoint) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
+// ShareFetchResponse is returned from a ShareFetchRequest.
+type ShareFetchResponse struct {
+	// Version is the version of this message used with a Kafka broker.
+	Version int16
+
+	// ThrottleMillis is how long of a throttle Kafka will apply to the client
+	// after responding to this request.
+	ThrottleMillis int32
+
+	// The top-level response error code.
+	//
+	// Supported errors:
+	// - GROUP_AUTHORIZATION_FAILED (version 0+)
+	// - TOPIC_AUTHORIZATION_FAILED (version 0+)
+	// - SHARE_SESSION_NOT_FOUND (version 0+)
+	// - INVALID_SHARE_SESSION_EPOCH (version 0+)
+	// - UNKNOWN_TOPIC_ID (version 0+)
+	// - INVALID_REQUEST (version 0+)
+	ErrorCode int16
+
+	// The top-level error message, or null if there was no error.
+	ErrorMessage *string
+
+	// The response topics.
+	Topics []ShareFetchResponseTopic
+
+	// Endpoints for all current leaders enumerated in PartitionData with error
+	// NOT_LEADER_OR_FOLLOWER.
+	NodeEndpoints []ShareFetchResponseNodeEndpoint
+
+	// UnknownTags are tags Kafka sent that we do not know the purpose of.
+	UnknownTags Tags
+}
+
+func (*ShareFetchResponse) Key() int16                         { return 78 }
+func (*ShareFetchResponse) MaxVersion() int16                  { return 0 }
+func (v *ShareFetchResponse) SetVersion(version int16)         { v.Version = version }
+func (v *ShareFetchResponse) GetVersion() int16                { return v.Version }
+func (v *ShareFetchResponse) IsFlexible() bool                 { return v.Version >= 0 }
+func (v *ShareFetchResponse) Throttle() (int32, bool)          { return v.ThrottleMillis, v.Version >= 0 }
+func (v *ShareFetchResponse) SetThrottle(throttleMillis int32) { v.ThrottleMillis = throttleMillis }
+func (v *ShareFetchResponse) RequestKind() Request             { return &ShareFetchRequest{Version: v.Version} }
+
+func (v *ShareFetchResponse) AppendTo(dst []byte) []byte {
+	version := v.Version
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	{
+		v := v.ThrottleMillis
+		dst = kbin.AppendInt32(dst, v)
+	}
+	{
+		v := v.ErrorCode
+		dst = kbin.AppendInt16(dst, v)
+	}
+	{
+		v := v.ErrorMessage
+		if isFlexible {
+			dst = kbin.AppendCompactNullableString(dst, v)
+		} else {
+			dst = kbin.AppendNullableString(dst, v)
+		}
+	}
+	{
+		v := v.Topics
+		if isFlexible {
+			dst = kbin.AppendCompactArrayLen(dst, len(v))
+		} else {
+			dst = kbin.AppendArrayLen(dst, len(v))
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.TopicID
+				dst = kbin.AppendUuid(dst, v)
+			}
+			{
+				v := v.Partitions
+				if isFlexible {
+					dst = kbin.AppendCompactArrayLen(dst, len(v))
+				} else {
+					dst = kbin.AppendArrayLen(dst, len(v))
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						v := v.Partition
+						dst = kbin.AppendInt32(dst, v)
+					}
+					{
+						v := v.ErrorCode
+						dst = kbin.AppendInt16(dst, v)
+					}
+					{
+						v := v.ErrorMessage
+						if isFlexible {
+							dst = kbin.AppendCompactNullableString(dst, v)
+						} else {
+							dst = kbin.AppendNullableString(dst, v)
+						}
+					}
+					{
+						v := v.AcknowledgeErrorCode
+						dst = kbin.AppendInt16(dst, v)
+					}
+					{
+						v := v.AcknowledgeErrorMessage
+						if isFlexible {
+							dst = kbin.AppendCompactNullableString(dst, v)
+						} else {
+							dst = kbin.AppendNullableString(dst, v)
+						}
+					}
+					{
+						v := &v.CurrentLeader
+						{
+							v := v.LeaderID
+							dst = kbin.AppendInt32(dst, v)
+						}
+						{
+							v := v.LeaderEpoch
+							dst = kbin.AppendInt32(dst, v)
+						}
+						if isFlexible {
+							dst = kbin.AppendUvarint(dst, 0+uint32(v.UnknownTags.Len()))
+							dst = v.UnknownTags.AppendEach(dst)
+						}
+					}
+					{
+						v := v.Records
+						if isFlexible {
+							dst = kbin.AppendCompactNullableBytes(dst, v)
+						} else {
+							dst = kbin.AppendNullableBytes(dst, v)
+						}
+					}
+					{
+						v := v.AcquiredRecords
+						if isFlexible {
+							dst = kbin.AppendCompactArrayLen(dst, len(v))
+						} else {
+							dst = kbin.AppendArrayLen(dst, len(v))
+						}
+						for i := range v {
+							v := &v[i]
+							{
+								v := v.FirstOffset
+								dst = kbin.AppendInt64(dst, v)
+							}
+							{
+								v := v.LastOffset
+								dst = kbin.AppendInt64(dst, v)
+							}
+							{
+								v := v.DeliveryCount
+								dst = kbin.AppendInt16(dst, v)
+							}
+							if isFlexible {
+								dst = kbin.AppendUvarint(dst, 0+uint32(v.UnknownTags.Len()))
+								dst = v.UnknownTags.AppendEach(dst)
+							}
+						}
+					}
+					if isFlexible {
+						dst = kbin.AppendUvarint(dst, 0+uint32(v.UnknownTags.Len()))
+						dst = v.UnknownTags.AppendEach(dst)
+					}
+				}
+			}
+			if isFlexible {
+				dst = kbin.AppendUvarint(dst, 0+uint32(v.UnknownTags.Len()))
+				dst = v.UnknownTags.AppendEach(dst)
+			}
+		}
+	}
+	{
+		v := v.NodeEndpoints
+		if isFlexible {
+			dst = kbin.AppendCompactArrayLen(dst, len(v))
+		} else {
+			dst = kbin.AppendArrayLen(dst, len(v))
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.NodeID
+				dst = kbin.AppendInt32(dst, v)
+			}
+			{
+				v := v.Host
+				if isFlexible {
+					dst = kbin.AppendCompactString(dst, v)
+				} else {
+					dst = kbin.AppendString(dst, v)
+				}
+			}
+			{
+				v := v.Port
+				dst = kbin.AppendInt32(dst, v)
+			}
+			{
+				v := v.Rack
+				if isFlexible {
+					dst = kbin.AppendCompactNullableString(dst, v)
+				} else {
+					dst = kbin.AppendNullableString(dst, v)
+				}
+			}
+			if isFlexible {
+				dst = kbin.AppendUvarint(dst, 0+uint32(v.UnknownTags.Len()))
+				dst = v.UnknownTags.AppendEach(dst)
+			}
+		}
+	}
+	if isFlexible {
+		dst = kbin.AppendUvarint(dst, 0+uint32(v.UnknownTags.Len()))
+		dst = v.UnknownTags.AppendEach(dst)
+	}
+	return dst
+}
+
+func (v *ShareFetchResponse) ReadFrom(src []byte) error {
+	return v.readFrom(src, false)
+}
+
+func (v *ShareFetchResponse) UnsafeReadFrom(src []byte) error {
+	return v.readFrom(src, true)
+}
+
+func (v *ShareFetchResponse) readFrom(src []byte, unsafe bool) error {
+	v.Default()
+	b := kbin.Reader{Src: src}
+	version := v.Version
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	s := v
+	{
+		v := b.Int32()
+		s.ThrottleMillis = v
+	}
+	{
+		v := b.Int16()
+		s.ErrorCode = v
+	}
+	{
+		var v *string
+		if isFlexible {
+			if unsafe {
+				v = b.UnsafeCompactNullableString()
+			} else {
+				v = b.CompactNullableString()
+			}
+		} else {
+			if unsafe {
+				v = b.UnsafeNullableString()
+			} else {
+				v = b.NullableString()
+			}
+		}
+		s.ErrorMessage = v
+	}
+	{
+		v := s.Topics
+		a := v
+		var l int32
+		if isFlexible {
+			l = b.CompactArrayLen()
+		} else {
+			l = b.ArrayLen()
+		}
+		if !b.Ok() {
+			return b.Complete()
+		}
+		a = a[:0]
+		if l > 0 {
+			a = append(a, make([]ShareFetchResponseTopic, l)...)
+		}
+		for i := int32(0); i < l; i++ {
+			v := &a[i]
+			v.Default()
+			s := v
+			{
+				v := b.Uuid()
+				s.TopicID = v
+			}
+			{
+				v := s.Partitions
+				a := v
+				var l int32
+				if isFlexible {
+					l = b.CompactArrayLen()
+				} else {
+					l = b.ArrayLen()
+				}
+				if !b.Ok() {
+					return b.Complete()
+				}
+				a = a[:0]
+				if l > 0 {
+					a = append(a, make([]ShareFetchResponseTopicPartition, l)...)
+				}
+				for i := int32(0); i < l; i++ {
+					v := &a[i]
+					v.Default()
+					s := v
+					{
+						v := b.Int32()
+						s.Partition = v
+					}
+					{
+						v := b.Int16()
+						s.ErrorCode = v
+					}
+					{
+						var v *string
+						if isFlexible {
+							if unsafe {
+								v = b.UnsafeCompactNullableString()
+							} else {
+								v = b.CompactNullableString()
+							}
+						} else {
+							if unsafe {
+								v = b.UnsafeNullableString()
+							} else {
+								v = b.NullableString()
+							}
+						}
+						s.ErrorMessage = v
+					}
+					{
+						v := b.Int16()
+						s.AcknowledgeErrorCode = v
+					}
+					{
+						var v *string
+						if isFlexible {
+							if unsafe {
+								v = b.UnsafeCompactNullableString()
+							} else {
+								v = b.CompactNullableString()
+							}
+						} else {
+							if unsafe {
+								v = b.UnsafeNullableString()
+							} else {
+								v = b.NullableString()
+							}
+						}
+						s.AcknowledgeErrorMessage = v
+					}
+					{
+						v := &s.CurrentLeader
+						v.Default()
+						s := v
+						{
+							v := b.Int32()
+							s.LeaderID = v
+						}
+						{
+							v := b.Int32()
+							s.LeaderEpoch = v
+						}
+						if isFlexible {
+							s.UnknownTags = internalReadTags(&b)
+						}
+					}
+					{
+						var v []byte
+						if isFlexible {
+							v = b.CompactNullableBytes()
+						} else {
+							v = b.NullableBytes()
+						}
+						s.Records = v
+					}
+					{
+						v := s.AcquiredRecords
+						a := v
+						var l int32
+						if isFlexible {
+							l = b.CompactArrayLen()
+						} else {
+							l = b.ArrayLen()
+						}
+						if !b.Ok() {
+							return b.Complete()
+						}
+						a = a[:0]
+						if l > 0 {
+							a = append(a, make([]ShareFetchResponseTopicPartitionAcquiredRecord, l)...)
+						}
+						for i := int32(0); i < l; i++ {
+							v := &a[i]
+							v.Default()
+							s := v
+							{
+								v := b.Int64()
+								s.FirstOffset = v
+							}
+							{
+								v := b.Int64()
+								s.LastOffset = v
+							}
+							{
+								v := b.Int16()
+								s.DeliveryCount = v
+							}
+							if isFlexible {
+								s.UnknownTags = internalReadTags(&b)
+							}
+						}
+						v = a
+						s.AcquiredRecords = v
+					}
+					if isFlexible {
+						s.UnknownTags = internalReadTags(&b)
+					}
+				}
+				v = a
+				s.Partitions = v
+			}
+			if isFlexible {
+				s.UnknownTags = internalReadTags(&b)
+			}
+		}
+		v = a
+		s.Topics = v
+	}
+	{
+		v := s.NodeEndpoints
+		a := v
+		var l int32
+		if isFlexible {
+			l = b.CompactArrayLen()
+		} else {
+			l = b.ArrayLen()
+		}
+		if !b.Ok() {
+			return b.Complete()
+		}
+		a = a[:0]
+		if l > 0 {
+			a = append(a, make([]ShareFetchResponseNodeEndpoint, l)...)
+		}
+		for i := int32(0); i < l; i++ {
+			v := &a[i]
+			v.Default()
+			s := v
+			{
+				v := b.Int32()
+				s.NodeID = v
+			}
+			{
+				var v string
+				if unsafe {
+					if isFlexible {
+						v = b.UnsafeCompactString()
+					} else {
+						v = b.UnsafeString()
+					}
+				} else {
+					if isFlexible {
+						v = b.CompactString()
+					} else {
+						v = b.String()
+					}
+				}
+				s.Host = v
+			}
+			{
+				v := b.Int32()
+				s.Port = v
+			}
+			{
+				var v *string
+				if isFlexible {
+					if unsafe {
+						v = b.UnsafeCompactNullableString()
+					} else {
+						v = b.CompactNullableString()
+					}
+				} else {
+					if unsafe {
+						v = b.UnsafeNullableString()
+					} else {
+						v = b.NullableString()
+					}
+				}
+				s.Rack = v
+			}
+			if isFlexible {
+				s.UnknownTags = internalReadTags(&b)
+			}
+		}
+		v = a
+		s.NodeEndpoints = v
+	}
+	if isFlexible {
+		s.UnknownTags = internalReadTags(&b)
+	}
+	return b.Complete()
+}
+
+// NewPtrShareFetchResponse returns a pointer to a default ShareFetchResponse
+// This is a shortcut for creating a new(struct) and calling Default yourself.
+func NewPtrShareFetchResponse() *ShareFetchResponse {
+	var v ShareFetchResponse
+	v.Default()
+	return &v
+}
+
+// Default sets any default fields. Calling this allows for future compatibility
+// if new fields are added to ShareFetchResponse.
+func (v *ShareFetchResponse) Default() {
+}
+
+// NewShareFetchResponse returns a default ShareFetchResponse
+// This is a shortcut for creating a struct and calling Default yourself.
+func NewShareFetchResponse() ShareFetchResponse {
+	var v ShareFetchResponse
+	v.Default()
+	return v
+}
+
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ShareFetchResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ShareFetchResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
+type ShareAcknowledgeRequestTopicPartitionAcknowledgementBatch struct {
+	// First offset of batch of records to acknowledge.
+	FirstOffset int64
+
+	// Last offset (inclusive) of batch of records to acknowledge.
+	LastOffset int64
+
+	// Array of acknowledge types: 0 is a gap, 1 is accept, 2 is
+	// release, 3 is reject. A single type applies to the entire batch.
+	AcknowledgeTypes []int8
+
+	// UnknownTags are tags Kafka sent that we do not know the purpose of.
+	UnknownTags Tags
+}
+
+// Default sets any default fields. Calling this allows for future compatibility
+// if new fields are added to ShareAcknowledgeRequestTopicPartitionAcknowledgementBatch.
+func (v *ShareAcknowledgeRequestTopicPartitionAcknowledgementBatch) Default() {
+}
+
+// NewShareAcknowledgeRequestTopicPartitionAcknowledgementBatch returns a default ShareAcknowledgeRequestTopicPartitionAcknowledgementBatch
+// This is a shortcut for creating a struct and calling Default yourself.
+func NewShareAcknowledgeRequestTopicPartitionAcknowledgementBatch() ShareAcknowledgeRequestTopicPartitionAcknowledgementBatch {
+	var v ShareAcknowledgeRequestTopicPartitionAcknowledgementBatch
+	v.Default()
+	return v
+}
+
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ShareAcknowledgeRequestTopicPartitionAcknowledgementBatch) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ShareAcknowledgeRequestTopicPartitionAcknowledgementBatch) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
+type ShareAcknowledgeRequestTopicPartition struct {
+	// The partition index.
+	Partition int32
+
+	// Record batches to acknowledge.
+	AcknowledgementBatches []ShareAcknowledgeRequestTopicPartitionAcknowledgementBatch
+
+	// UnknownTags are tags Kafka sent that we do not know the purpose of.
+	UnknownTags Tags
+}
+
+// Default sets any default fields. Calling this allows for future compatibility
+// if new fields are added to ShareAcknowledgeRequestTopicPartition.
+func (v *ShareAcknowledgeRequestTopicPartition) Default() {
+}
+
+// NewShareAcknowledgeRequestTopicPartition returns a default ShareAcknowledgeRequestTopicPartition
+// This is a shortcut for creating a struct and calling Default yourself.
+func NewShareAcknowledgeRequestTopicPartition() ShareAcknowledgeRequestTopicPartition {
+	var v ShareAcknowledgeRequestTopicPartition
+	v.Default()
+	return v
+}
+
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ShareAcknowledgeRequestTopicPartition) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ShareAcknowledgeRequestTopicPartition) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
+type ShareAcknowledgeRequestTopic struct {
+	// The unique topic ID.
+	TopicID [16]byte
+
+	// The partitions containing records to acknowledge.
+	Partitions []ShareAcknowledgeRequestTopicPartition
+
+	// UnknownTags are tags Kafka sent that we do not know the purpose of.
+	UnknownTags Tags
+}
+
+// Default sets any default fields. Calling this allows for future compatibility
+// if new fields are added to ShareAcknowledgeRequestTopic.
+func (v *ShareAcknowledgeRequestTopic) Default() {
+}
+
+// NewShareAcknowledgeRequestTopic returns a default ShareAcknowledgeRequestTopic
+// This is a shortcut for creating a struct and calling Default yourself.
+func NewShareAcknowledgeRequestTopic() ShareAcknowledgeRequestTopic {
+	var v ShareAcknowledgeRequestTopic
+	v.Default()
+	return v
+}
+
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ShareAcknowledgeRequestTopic) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ShareAcknowledgeRequestTopic) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
+// ShareAcknowledge is a part of KIP-932 and is used by share group members
+// to acknowledge acquired records without fetching more.
+type ShareAcknowledgeRequest struct {
+	// Version is the version of this message used with a Kafka broker.
+	Version int16
+
+	// The group ID.
+	GroupID *string
+
+	// The member ID.
+	MemberID *string
+
+	// The current share session epoch: 0 to open a share session; -1 to close
+	// it; otherwise increments for consecutive requests.
+	ShareSessionEpoch int32
+
+	// The topics containing records to acknowledge.
+	Topics []ShareAcknowledgeRequestTopic
+
+	// UnknownTags are tags Kafka sent that we do not know the purpose of.
+	UnknownTags Tags
+}
+
+func (*ShareAcknowledgeRequest) Key() int16                 { return 79 }
+func (*ShareAcknowledgeRequest) MaxVersion() int16          { return 0 }
+func (v *ShareAcknowledgeRequest) SetVersion(version int16) { v.Version = version }
+func (v *ShareAcknowledgeRequest) GetVersion() int16        { return v.Version }
+func (v *ShareAcknowledgeRequest) IsFlexible() bool         { return v.Version >= 0 }
+func (v *ShareAcknowledgeRequest) ResponseKind() Response {
+	r := &ShareAcknowledgeResponse{Version: v.Version}
+	r.Default()
+	return r
+}
+
+// RequestWith is requests v on r and returns the response or an error.
+// For sharded requests, the response may be merged and still return an error.
+// It is better to rely on client.RequestSharded than to rely on proper merging behavior.
+func (v *ShareAcknowledgeRequest) RequestWith(ctx context.Context, r Requestor) (*ShareAcknowledgeResponse, error) {
+	kresp, err := r.Request(ctx, v)
+	resp, _ := kresp.(*ShareAcknowledgeResponse)
+	return resp, err
+}
+
+func (v *ShareAcknowledgeRequest) AppendTo(dst []byte) []byte {
+	version := v.Version
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	{
+		v := v.GroupID
+		if isFlexible {
+			dst = kbin.AppendCompactNullableString(dst, v)
+		} else {
+			dst = kbin.AppendNullableString(dst, v)
+		}
+	}
+	{
+		v := v.MemberID
+		if isFlexible {
+			dst = kbin.AppendCompactNullableString(dst, v)
+		} else {
+			dst = kbin.AppendNullableString(dst, v)
+		}
+	}
+	{
+		v := v.ShareSessionEpoch
+		dst = kbin.AppendInt32(dst, v)
+	}
+	{
+		v := v.Topics
+		if isFlexible {
+			dst = kbin.AppendCompactArrayLen(dst, len(v))
+		} else {
+			dst = kbin.AppendArrayLen(dst, len(v))
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.TopicID
+				dst = kbin.AppendUuid(dst, v)
+			}
+			{
+				v := v.Partitions
+				if isFlexible {
+					dst = kbin.AppendCompactArrayLen(dst, len(v))
+				} else {
+					dst = kbin.AppendArrayLen(dst, len(v))
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						v := v.Partition
+						dst = kbin.AppendInt32(dst, v)
+					}
+					{
+						v := v.AcknowledgementBatches
+						if isFlexible {
+							dst = kbin.AppendCompactArrayLen(dst, len(v))
+						} else {
+							dst = kbin.AppendArrayLen(dst, len(v))
+						}
+						for i := range v {
+							v := &v[i]
+							{
+								v := v.FirstOffset
+								dst = kbin.AppendInt64(dst, v)
+							}
+							{
+								v := v.LastOffset
+								dst = kbin.AppendInt64(dst, v)
+							}
+							{
+								v := v.AcknowledgeTypes
+								if isFlexible {
+									dst = kbin.AppendCompactArrayLen(dst, len(v))
+								} else {
+									dst = kbin.AppendArrayLen(dst, len(v))
+								}
+								for i := range v {
+									v := v[i]
+									dst = kbin.AppendInt8(dst, v)
+								}
+							}
+							if isFlexible {
+								dst = kbin.AppendUvarint(dst, 0+uint32(v.UnknownTags.Len()))
+								dst = v.UnknownTags.AppendEach(dst)
+							}
+						}
+					}
+					if isFlexible {
+						dst = kbin.AppendUvarint(dst, 0+uint32(v.UnknownTags.Len()))
+						dst = v.UnknownTags.AppendEach(dst)
+					}
+				}
+			}
+			if isFlexible {
+				dst = kbin.AppendUvarint(dst, 0+uint32(v.UnknownTags.Len()))
+				dst = v.UnknownTags.AppendEach(dst)
+			}
+		}
+	}
+	if isFlexible {
+		dst = kbin.AppendUvarint(dst, 0+uint32(v.UnknownTags.Len()))
+		dst = v.UnknownTags.AppendEach(dst)
+	}
+	return dst
+}
+
+func (v *ShareAcknowledgeRequest) ReadFrom(src []byte) error {
+	return v.readFrom(src, false)
+}
+
+func (v *ShareAcknowledgeRequest) UnsafeReadFrom(src []byte) error {
+	return v.readFrom(src, true)
+}
+
+func (v *ShareAcknowledgeRequest) readFrom(src []byte, unsafe bool) error {
+	v.Default()
+	b := kbin.Reader{Src: src}
+	version := v.Version
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	s := v
+	{
+		var v *string
+		if isFlexible {
+			if unsafe {
+				v = b.UnsafeCompactNullableString()
+			} else {
+				v = b.CompactNullableString()
+			}
+		} else {
+			if unsafe {
+				v = b.UnsafeNullableString()
+			} else {
+				v = b.NullableString()
+			}
+		}
+		s.GroupID = v
+	}
+	{
+		var v *string
+		if isFlexible {
+			if unsafe {
+				v = b.UnsafeCompactNullableString()
+			} else {
+				v = b.CompactNullableString()
+			}
+		} else {
+			if unsafe {
+				v = b.UnsafeNullableString()
+			} else {
+				v = b.NullableString()
+			}
+		}
+		s.MemberID = v
+	}
+	{
+		v := b.Int32()
+		s.ShareSessionEpoch = v
+	}
+	{
+		v := s.Topics
+		a := v
+		var l int32
+		if isFlexible {
+			l = b.CompactArrayLen()
+		} else {
+			l = b.ArrayLen()
+		}
+		if !b.Ok() {
+			return b.Complete()
+		}
+		a = a[:0]
+		if l > 0 {
+			a = append(a, make([]ShareAcknowledgeRequestTopic, l)...)
+		}
+		for i := int32(0); i < l; i++ {
+			v := &a[i]
+			v.Default()
+			s := v
+			{
+				v := b.Uuid()
+				s.TopicID = v
+			}
+			{
+				v := s.Partitions
+				a := v
+				var l int32
+				if isFlexible {
+					l = b.CompactArrayLen()
+				} else {
+					l = b.ArrayLen()
+				}
+				if !b.Ok() {
+					return b.Complete()
+				}
+				a = a[:0]
+				if l > 0 {
+					a = append(a, make([]ShareAcknowledgeRequestTopicPartition, l)...)
+				}
+				for i := int32(0); i < l; i++ {
+					v := &a[i]
+					v.Default()
+					s := v
+					{
+						v := b.Int32()
+						s.Partition = v
+					}
+					{
+						v := s.AcknowledgementBatches
+						a := v
+						var l int32
+						if isFlexible {
+							l = b.CompactArrayLen()
+						} else {
+							l = b.ArrayLen()
+						}
+						if !b.Ok() {
+							return b.Complete()
+						}
+						a = a[:0]
+						if l > 0 {
+							a = append(a, make([]ShareAcknowledgeRequestTopicPartitionAcknowledgementBatch, l)...)
+						}
+						for i := int32(0); i < l; i++ {
+							v := &a[i]
+							v.Default()
+							s := v
+							{
+								v := b.Int64()
+								s.FirstOffset = v
+							}
+							{
+								v := b.Int64()
+								s.LastOffset = v
+							}
+							{
+								v := s.AcknowledgeTypes
+								a := v
+								var l int32
+								if isFlexible {
+									l = b.CompactArrayLen()
+								} else {
+									l = b.ArrayLen()
+								}
+								if !b.Ok() {
+									return b.Complete()
+								}
+								a = a[:0]
+								if l > 0 {
+									a = append(a, make([]int8, l)...)
+								}
+								for i := int32(0); i < l; i++ {
+									v := b.Int8()
+									a[i] = v
+								}
+								v = a
+								s.AcknowledgeTypes = v
+							}
+							if isFlexible {
+								s.UnknownTags = internalReadTags(&b)
+							}
+						}
+						v = a
+						s.AcknowledgementBatches = v
+					}
+					if isFlexible {
+						s.UnknownTags = internalReadTags(&b)
+					}
+				}
+				v = a
+				s.Partitions = v
+			}
+			if isFlexible {
+				s.UnknownTags = internalReadTags(&b)
+			}
+		}
+		v = a
+		s.Topics = v
+	}
+	if isFlexible {
+		s.UnknownTags = internalReadTags(&b)
+	}
+	return b.Complete()
+}
+
+// NewPtrShareAcknowledgeRequest returns a pointer to a default ShareAcknowledgeRequest
+// This is a shortcut for creating a new(struct) and calling Default yourself.
+func NewPtrShareAcknowledgeRequest() *ShareAcknowledgeRequest {
+	var v ShareAcknowledgeRequest
+	v.Default()
+	return &v
+}
+
+// Default sets any default fields. Calling this allows for future compatibility
+// if new fields are added to ShareAcknowledgeRequest.
+func (v *ShareAcknowledgeRequest) Default() {
+}
+
+// NewShareAcknowledgeRequest returns a default ShareAcknowledgeRequest
+// This is a shortcut for creating a struct and calling Default yourself.
+func NewShareAcknowledgeRequest() ShareAcknowledgeRequest {
+	var v ShareAcknowledgeRequest
+	v.Default()
+	return v
+}
+
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ShareAcknowledgeRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ShareAcknowledgeRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
+type ShareAcknowledgeResponseTopicPartitionCurrentLeader struct {
+	// The ID of the current leader, or -1 if unknown.
+	//
+	// This field has a default of -1.
+	LeaderID int32
+
+	// The latest known leader epoch.
+	//
+	// This field has a default of -1.
+	LeaderEpoch int32
+
+	// UnknownTags are tags Kafka sent that we do not know the purpose of.
+	UnknownTags Tags
+}
+
+// Default sets any default fields. Calling this allows for future compatibility
+// if new fields are added to ShareAcknowledgeResponseTopicPartitionCurrentLeader.
+func (v *ShareAcknowledgeResponseTopicPartitionCurrentLeader) Default() {
+	v.LeaderID = -1
+	v.LeaderEpoch = -1
+}
+
+// NewShareAcknowledgeResponseTopicPartitionCurrentLeader returns a default ShareAcknowledgeResponseTopicPartitionCurrentLeader
+// This is a shortcut for creating a struct and calling Default yourself.
+func NewShareAcknowledgeResponseTopicPartitionCurrentLeader() ShareAcknowledgeResponseTopicPartitionCurrentLeader {
+	var v ShareAcknowledgeResponseTopicPartitionCurrentLeader
+	v.Default()
+	return v
+}
+
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ShareAcknowledgeResponseTopicPartitionCurrentLeader) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ShareAcknowledgeResponseTopicPartitionCurrentLeader) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
+type ShareAcknowledgeResponseTopicPartition struct {
+	// The partition index.
+	Partition int32
+
+	// The error code, or 0 if there was no error.
+	ErrorCode int16
+
+	// The error message, or null if there was no error.
+	ErrorMessage *string
+
+	// CurrentLeader is the currently known leader ID and epoch for this
+	// partition.
+	CurrentLeader ShareAcknowledgeResponseTopicPartitionCurrentLeader
+
+	// UnknownTags are tags Kafka sent that we do not know the purpose of.
+	UnknownTags Tags
+}
+
+// Default sets any default fields. Calling this allows for future compatibility
+// if new fields are added to ShareAcknowledgeResponseTopicPartition.
+func (v *ShareAcknowledgeResponseTopicPartition) Default() {
+	{
+		v := &v.CurrentLeader
+		_ = v
+		v.LeaderID = -1
+		v.LeaderEpoch = -1
+	}
+}
+
+// NewShareAcknowledgeResponseTopicPartition returns a default ShareAcknowledgeResponseTopicPartition
+// This is a shortcut for creating a struct and calling Default yourself.
+func NewShareAcknowledgeResponseTopicPartition() ShareAcknowledgeResponseTopicPartition {
+	var v ShareAcknowledgeResponseTopicPartition
+	v.Default()
+	return v
+}
+
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ShareAcknowledgeResponseTopicPartition) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ShareAcknowledgeResponseTopicPartition) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
+type ShareAcknowledgeResponseTopic struct {
+	// The unique topic ID.
+	TopicID [16]byte
+
+	// The topic partitions.
+	Partitions []ShareAcknowledgeResponseTopicPartition
+
+	// UnknownTags are tags Kafka sent that we do not know the purpose of.
+	UnknownTags Tags
+}
+
+// Default sets any default fields. Calling this allows for future compatibility
+// if new fields are added to ShareAcknowledgeResponseTopic.
+func (v *ShareAcknowledgeResponseTopic) Default() {
+}
+
+// NewShareAcknowledgeResponseTopic returns a default ShareAcknowledgeResponseTopic
+// This is a shortcut for creating a struct and calling Default yourself.
+func NewShareAcknowledgeResponseTopic() ShareAcknowledgeResponseTopic {
+	var v ShareAcknowledgeResponseTopic
+	v.Default()
+	return v
+}
+
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ShareAcknowledgeResponseTopic) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ShareAcknowledgeResponseTopic) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
+type ShareAcknowledgeResponseNodeEndpoint struct {
+	// NodeID is the node ID of a Kafka broker.
+	NodeID int32
+
+	// Host is the hostname of a Kafka broker.
+	Host string
+
+	// Port is the port of a Kafka broker.
+	Port int32
+
+	// Rack is the rack this Kafka broker is in.
+	Rack *string
+
+	// UnknownTags are tags Kafka sent that we do not know the purpose of.
+	UnknownTags Tags
+}
+
+// Default sets any default fields. Calling this allows for future compatibility
+// if new fields are added to ShareAcknowledgeResponseNodeEndpoint.
+func (v *ShareAcknowledgeResponseNodeEndpoint) Default() {
+}
+
+// NewShareAcknowledgeResponseNodeEndpoint returns a default ShareAcknowledgeResponseNodeEndpoint
+// This is a shortcut for creating a struct and calling Default yourself.
+func NewShareAcknowledgeResponseNodeEndpoint() ShareAcknowledgeResponseNodeEndpoint {
+	var v ShareAcknowledgeResponseNodeEndpoint
+	v.Default()
+	return v
+}
+
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ShareAcknowledgeResponseNodeEndpoint) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ShareAcknowledgeResponseNodeEndpoint) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
+// ShareAcknowledgeResponse is returned from a ShareAcknowledgeRequest.
+type ShareAcknowledgeResponse struct {
+	// Version is the version of this message used with a Kafka broker.
+	Version int16
+
+	// ThrottleMillis is how long of a throttle Kafka will apply to the client
+	// after responding to this request.
+	ThrottleMillis int32
+
+	// The top level response error code.
+	//
+	// Supported errors:
+	// - GROUP_AUTHORIZATION_FAILED (version 0+)
+	// - TOPIC_AUTHORIZATION_FAILED (version 0+)
+	// - SHARE_SESSION_NOT_FOUND (version 0+)
+	// - INVALID_SHARE_SESSION_EPOCH (version 0+)
+	// - UNKNOWN_TOPIC_ID (version 0+)
+	// - INVALID_REQUEST (version 0+)
+	ErrorCode int16
+
+	// The top-level error message, or null if there was no error.
+	ErrorMessage *string
+
+	// The response topics.
+	Topics []ShareAcknowledgeResponseTopic
+
+	// Endpoints for all current leaders enumerated in PartitionData with error
+	// NOT_LEADER_OR_FOLLOWER.
+	NodeEndpoints []ShareAcknowledgeResponseNodeEndpoint
+
+	// UnknownTags are tags Kafka sent that we do not know the purpose of.
+	UnknownTags Tags
+}
+
+func (*ShareAcknowledgeResponse) Key() int16                 { return 79 }
+func (*ShareAcknowledgeResponse) MaxVersion() int16          { return 0 }
+func (v *ShareAcknowledgeResponse) SetVersion(version int16) { v.Version = version }
+func (v *ShareAcknowledgeResponse) GetVersion() int16        { return v.Version }
+func (v *ShareAcknowledgeResponse) IsFlexible() bool         { return v.Version >= 0 }
+func (v *ShareAcknowledgeResponse) Throttle() (int32, bool)  { return v.ThrottleMillis, v.Version >= 0 }
+func (v *ShareAcknowledgeResponse) SetThrottle(throttleMillis int32) {
+	v.ThrottleMillis = throttleMillis
+}
+func (v *ShareAcknowledgeResponse) RequestKind() Request {
+	return &ShareAcknowledgeRequest{Version: v.Version}
+}
+
+func (v *ShareAcknowledgeResponse) AppendTo(dst []byte) []byte {
+	version := v.Version
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	{
+		v := v.ThrottleMillis
+		dst = kbin.AppendInt32(dst, v)
+	}
+	{
+		v := v.ErrorCode
+		dst = kbin.AppendInt16(dst, v)
+	}
+	{
+		v := v.ErrorMessage
+		if isFlexible {
+			dst = kbin.AppendCompactNullableString(dst, v)
+		} else {
+			dst = kbin.AppendNullableString(dst, v)
+		}
+	}
+	{
+		v := v.Topics
+		if isFlexible {
+			dst = kbin.AppendCompactArrayLen(dst, len(v))
+		} else {
+			dst = kbin.AppendArrayLen(dst, len(v))
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.TopicID
+				dst = kbin.AppendUuid(dst, v)
+			}
+			{
+				v := v.Partitions
+				if isFlexible {
+					dst = kbin.AppendCompactArrayLen(dst, len(v))
+				} else {
+					dst = kbin.AppendArrayLen(dst, len(v))
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						v := v.Partition
+						dst = kbin.AppendInt32(dst, v)
+					}
+					{
+						v := v.ErrorCode
+						dst = kbin.AppendInt16(dst, v)
+					}
+					{
+						v := v.ErrorMessage
+						if isFlexible {
+							dst = kbin.AppendCompactNullableString(dst, v)
+						} else {
+							dst = kbin.AppendNullableString(dst, v)
+						}
+					}
+					{
+						v := &v.CurrentLeader
+						{
+							v := v.LeaderID
+							dst = kbin.AppendInt32(dst, v)
+						}
+						{
+							v := v.LeaderEpoch
+							dst = kbin.AppendInt32(dst, v)
+						}
+						if isFlexible {
+							dst = kbin.AppendUvarint(dst, 0+uint32(v.UnknownTags.Len()))
+							dst = v.UnknownTags.AppendEach(dst)
+						}
+					}
+					if isFlexible {
+						dst = kbin.AppendUvarint(dst, 0+uint32(v.UnknownTags.Len()))
+						dst = v.UnknownTags.AppendEach(dst)
+					}
+				}
+			}
+			if isFlexible {
+				dst = kbin.AppendUvarint(dst, 0+uint32(v.UnknownTags.Len()))
+				dst = v.UnknownTags.AppendEach(dst)
+			}
+		}
+	}
+	{
+		v := v.NodeEndpoints
+		if isFlexible {
+			dst = kbin.AppendCompactArrayLen(dst, len(v))
+		} else {
+			dst = kbin.AppendArrayLen(dst, len(v))
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.NodeID
+				dst = kbin.AppendInt32(dst, v)
+			}
+			{
+				v := v.Host
+				if isFlexible {
+					dst = kbin.AppendCompactString(dst, v)
+				} else {
+					dst = kbin.AppendString(dst, v)
+				}
+			}
+			{
+				v := v.Port
+				dst = kbin.AppendInt32(dst, v)
+			}
+			{
+				v := v.Rack
+				if isFlexible {
+					dst = kbin.AppendCompactNullableString(dst, v)
+				} else {
+					dst = kbin.AppendNullableString(dst, v)
+				}
+			}
+			if isFlexible {
+				dst = kbin.AppendUvarint(dst, 0+uint32(v.UnknownTags.Len()))
+				dst = v.UnknownTags.AppendEach(dst)
+			}
+		}
+	}
+	if isFlexible {
+		dst = kbin.AppendUvarint(dst, 0+uint32(v.UnknownTags.Len()))
+		dst = v.UnknownTags.AppendEach(dst)
+	}
+	return dst
+}
+
+func (v *ShareAcknowledgeResponse) ReadFrom(src []byte) error {
+	return v.readFrom(src, false)
+}
+
+func (v *ShareAcknowledgeResponse) UnsafeReadFrom(src []byte) error {
+	return v.readFrom(src, true)
+}
+
+func (v *ShareAcknowledgeResponse) readFrom(src []byte, unsafe bool) error {
+	v.Default()
+	b := kbin.Reader{Src: src}
+	version := v.Version
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	s := v
+	{
+		v := b.Int32()
+		s.ThrottleMillis = v
+	}
+	{
+		v := b.Int16()
+		s.ErrorCode = v
+	}
+	{
+		var v *string
+		if isFlexible {
+			if unsafe {
+				v = b.UnsafeCompactNullableString()
+			} else {
+				v = b.CompactNullableString()
+			}
+		} else {
+			if unsafe {
+				v = b.UnsafeNullableString()
+			} else {
+				v = b.NullableString()
+			}
+		}
+		s.ErrorMessage = v
+	}
+	{
+		v := s.Topics
+		a := v
+		var l int32
+		if isFlexible {
+			l = b.CompactArrayLen()
+		} else {
+			l = b.ArrayLen()
+		}
+		if !b.Ok() {
+			return b.Complete()
+		}
+		a = a[:0]
+		if l > 0 {
+			a = append(a, make([]ShareAcknowledgeResponseTopic, l)...)
+		}
+		for i := int32(0); i < l; i++ {
+			v := &a[i]
+			v.Default()
+			s := v
+			{
+				v := b.Uuid()
+				s.TopicID = v
+			}
+			{
+				v := s.Partitions
+				a := v
+				var l int32
+				if isFlexible {
+					l = b.CompactArrayLen()
+				} else {
+					l = b.ArrayLen()
+				}
+				if !b.Ok() {
+					return b.Complete()
+				}
+				a = a[:0]
+				if l > 0 {
+					a = append(a, make([]ShareAcknowledgeResponseTopicPartition, l)...)
+				}
+				for i := int32(0); i < l; i++ {
+					v := &a[i]
+					v.Default()
+					s := v
+					{
+						v := b.Int32()
+						s.Partition = v
+					}
+					{
+						v := b.Int16()
+						s.ErrorCode = v
+					}
+					{
+						var v *string
+						if isFlexible {
+							if unsafe {
+								v = b.UnsafeCompactNullableString()
+							} else {
+								v = b.CompactNullableString()
+							}
+						} else {
+							if unsafe {
+								v = b.UnsafeNullableString()
+							} else {
+								v = b.NullableString()
+							}
+						}
+						s.ErrorMessage = v
+					}
+					{
+						v := &s.CurrentLeader
+						v.Default()
+						s := v
+						{
+							v := b.Int32()
+							s.LeaderID = v
+						}
+						{
+							v := b.Int32()
+							s.LeaderEpoch = v
+						}
+						if isFlexible {
+							s.UnknownTags = internalReadTags(&b)
+						}
+					}
+					if isFlexible {
+						s.UnknownTags = internalReadTags(&b)
+					}
+				}
+				v = a
+				s.Partitions = v
+			}
+			if isFlexible {
+				s.UnknownTags = internalReadTags(&b)
+			}
+		}
+		v = a
+		s.Topics = v
+	}
+	{
+		v := s.NodeEndpoints
+		a := v
+		var l int32
+		if isFlexible {
+			l = b.CompactArrayLen()
+		} else {
+			l = b.ArrayLen()
+		}
+		if !b.Ok() {
+			return b.Complete()
+		}
+		a = a[:0]
+		if l > 0 {
+			a = append(a, make([]ShareAcknowledgeResponseNodeEndpoint, l)...)
+		}
+		for i := int32(0); i < l; i++ {
+			v := &a[i]
+			v.Default()
+			s := v
+			{
+				v := b.Int32()
+				s.NodeID = v
+			}
+			{
+				var v string
+				if unsafe {
+					if isFlexible {
+						v = b.UnsafeCompactString()
+					} else {
+						v = b.UnsafeString()
+					}
+				} else {
+					if isFlexible {
+						v = b.CompactString()
+					} else {
+						v = b.String()
+					}
+				}
+				s.Host = v
+			}
+			{
+				v := b.Int32()
+				s.Port = v
+			}
+			{
+				var v *string
+				if isFlexible {
+					if unsafe {
+						v = b.UnsafeCompactNullableString()
+					} else {
+						v = b.CompactNullableString()
+					}
+				} else {
+					if unsafe {
+						v = b.UnsafeNullableString()
+					} else {
+						v = b.NullableString()
+					}
+				}
+				s.Rack = v
+			}
+			if isFlexible {
+				s.UnknownTags = internalReadTags(&b)
+			}
+		}
+		v = a
+		s.NodeEndpoints = v
+	}
+	if isFlexible {
+		s.UnknownTags = internalReadTags(&b)
+	}
+	return b.Complete()
+}
+
+// NewPtrShareAcknowledgeResponse returns a pointer to a default ShareAcknowledgeResponse
+// This is a shortcut for creating a new(struct) and calling Default yourself.
+func NewPtrShareAcknowledgeResponse() *ShareAcknowledgeResponse {
+	var v ShareAcknowledgeResponse
+	v.Default()
+	return &v
+}
+
+// Default sets any default fields. Calling this allows for future compatibility
+// if new fields are added to ShareAcknowledgeResponse.
+func (v *ShareAcknowledgeResponse) Default() {
+}
+
+// NewShareAcknowledgeResponse returns a default ShareAcknowledgeResponse
+// This is a shortcut for creating a struct and calling Default yourself.
+func NewShareAcknowledgeResponse() ShareAcknowledgeResponse {
+	var v ShareAcknowledgeResponse
+	v.Default()
+	return v
+}
+
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v ShareAcknowledgeResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *ShareAcknowledgeResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
+type AddRaftVoterRequestListener struct {
+	// The name of the endpoint.
+	Name string
+
+	// The hostname.
+	Host string
+
+	// The port.
+	Port uint16
+
+	// UnknownTags are tags Kafka sent that we do not know the purpose of.
+	UnknownTags Tags
+}
+
+// Default sets any default fields. Calling this allows for future compatibility
+// if new fields are added to AddRaftVoterRequestListener.
+func (v *AddRaftVoterRequestListener) Default() {
+}
+
+// NewAddRaftVoterRequestListener returns a default AddRaftVoterRequestListener
+// This is a shortcut for creating a struct and calling Default yourself.
+func NewAddRaftVoterRequestListener() AddRaftVoterRequestListener {
+	var v AddRaftVoterRequestListener
+	v.Default()
+	return v
+}
+
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v AddRaftVoterRequestListener) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *AddRaftVoterRequestListener) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
+// Part of KIP-853 to support dynamic KRaft controller quorums,
+// AddRaftVoterRequest adds a voter to the KRaft quorum.
+type AddRaftVoterRequest struct {
+	// Version is the version of this message used with a Kafka broker.
+	Version int16
+
+	// The cluster ID of the request.
+	ClusterID *string
+
+	// How long to wait for the voter to be added, in milliseconds.
+	TimeoutMillis int32
+
+	// The replica ID of the voter getting added to the topic partition.
+	VoterID int32
+
+	// The directory ID of the voter getting added to the topic partition.
+	VoterDirectoryID [16]byte
+
+	// The endpoints that can be used to communicate with the voter.
+	Listeners []AddRaftVoterRequestListener
+
+	// UnknownTags are tags Kafka sent that we do not know the purpose of.
+	UnknownTags Tags
+}
+
+func (*AddRaftVoterRequest) Key() int16                 { return 80 }
+func (*AddRaftVoterRequest) MaxVersion() int16          { return 0 }
+func (v *AddRaftVoterRequest) SetVersion(version int16) { v.Version = version }
+func (v *AddRaftVoterRequest) GetVersion() int16        { return v.Version }
+func (v *AddRaftVoterRequest) IsFlexible() bool         { return v.Version >= 0 }
+func (v *AddRaftVoterRequest) IsAdminRequest()          {}
+func (v *AddRaftVoterRequest) ResponseKind() Response {
+	r := &AddRaftVoterResponse{Version: v.Version}
+	r.Default()
+	return r
+}
+
+// RequestWith is requests v on r and returns the response or an error.
+// For sharded requests, the response may be merged and still return an error.
+// It is better to rely on client.RequestSharded than to rely on proper merging behavior.
+func (v *AddRaftVoterRequest) RequestWith(ctx context.Context, r Requestor) (*AddRaftVoterResponse, error) {
+	kresp, err := r.Request(ctx, v)
+	resp, _ := kresp.(*AddRaftVoterResponse)
+	return resp, err
+}
+
+func (v *AddRaftVoterRequest) AppendTo(dst []byte) []byte {
+	version := v.Version
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	{
+		v := v.ClusterID
+		if isFlexible {
+			dst = kbin.AppendCompactNullableString(dst, v)
+		} else {
+			dst = kbin.AppendNullableString(dst, v)
+		}
+	}
+	{
+		v := v.TimeoutMillis
+		dst = kbin.AppendInt32(dst, v)
+	}
+	{
+		v := v.VoterID
+		dst = kbin.AppendInt32(dst, v)
+	}
+	{
+		v := v.VoterDirectoryID
+		dst = kbin.AppendUuid(dst, v)
+	}
+	{
+		v := v.Listeners
+		if isFlexible {
+			dst = kbin.AppendCompactArrayLen(dst, len(v))
+		} else {
+			dst = kbin.AppendArrayLen(dst, len(v))
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Name
+				if isFlexible {
+					dst = kbin.AppendCompactString(dst, v)
+				} else {
+					dst = kbin.AppendString(dst, v)
+				}
+			}
+			{
+				v := v.Host
+				if isFlexible {
+					dst = kbin.AppendCompactString(dst, v)
+				} else {
+					dst = kbin.AppendString(dst, v)
+				}
+			}
+			{
+				v := v.Port
+				dst = kbin.AppendUint16(dst, v)
+			}
+			if isFlexible {
+				dst = kbin.AppendUvarint(dst, 0+uint32(v.UnknownTags.Len()))
+				dst = v.UnknownTags.AppendEach(dst)
+			}
+		}
+	}
+	if isFlexible {
+		dst = kbin.AppendUvarint(dst, 0+uint32(v.UnknownTags.Len()))
+		dst = v.UnknownTags.AppendEach(dst)
+	}
+	return dst
+}
+
+func (v *AddRaftVoterRequest) ReadFrom(src []byte) error {
+	return v.readFrom(src, false)
+}
+
+func (v *AddRaftVoterRequest) UnsafeReadFrom(src []byte) error {
+	return v.readFrom(src, true)
+}
+
+func (v *AddRaftVoterRequest) readFrom(src []byte, unsafe bool) error {
+	v.Default()
+	b := kbin.Reader{Src: src}
+	version := v.Version
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	s := v
+	{
+		var v *string
+		if isFlexible {
+			if unsafe {
+				v = b.UnsafeCompactNullableString()
+			} else {
+				v = b.CompactNullableString()
+			}
+		} else {
+			if unsafe {
+				v = b.UnsafeNullableString()
+			} else {
+				v = b.NullableString()
+			}
+		}
+		s.ClusterID = v
+	}
+	{
+		v := b.Int32()
+		s.TimeoutMillis = v
+	}
+	{
+		v := b.Int32()
+		s.VoterID = v
+	}
+	{
+		v := b.Uuid()
+		s.VoterDirectoryID = v
+	}
+	{
+		v := s.Listeners
+		a := v
+		var l int32
+		if isFlexible {
+			l = b.CompactArrayLen()
+		} else {
+			l = b.ArrayLen()
+		}
+		if !b.Ok() {
+			return b.Complete()
+		}
+		a = a[:0]
+		if l > 0 {
+			a = append(a, make([]AddRaftVoterRequestListener, l)...)
+		}
+		for i := int32(0); i < l; i++ {
+			v := &a[i]
+			v.Default()
+			s := v
+			{
+				var v string
+				if unsafe {
+					if isFlexible {
+						v = b.UnsafeCompactString()
+					} else {
+						v = b.UnsafeString()
+					}
+				} else {
+					if isFlexible {
+						v = b.CompactString()
+					} else {
+						v = b.String()
+					}
+				}
+				s.Name = v
+			}
+			{
+				var v string
+				if unsafe {
+					if isFlexible {
+						v = b.UnsafeCompactString()
+					} else {
+						v = b.UnsafeString()
+					}
+				} else {
+					if isFlexible {
+						v = b.CompactString()
+					} else {
+						v = b.String()
+					}
+				}
+				s.Host = v
+			}
+			{
+				v := b.Uint16()
+				s.Port = v
+			}
+			if isFlexible {
+				s.UnknownTags = internalReadTags(&b)
+			}
+		}
+		v = a
+		s.Listeners = v
+	}
+	if isFlexible {
+		s.UnknownTags = internalReadTags(&b)
+	}
+	return b.Complete()
+}
+
+// NewPtrAddRaftVoterRequest returns a pointer to a default AddRaftVoterRequest
+// This is a shortcut for creating a new(struct) and calling Default yourself.
+func NewPtrAddRaftVoterRequest() *AddRaftVoterRequest {
+	var v AddRaftVoterRequest
+	v.Default()
+	return &v
+}
+
+// Default sets any default fields. Calling this allows for future compatibility
+// if new fields are added to AddRaftVoterRequest.
+func (v *AddRaftVoterRequest) Default() {
+}
+
+// NewAddRaftVoterRequest returns a default AddRaftVoterRequest
+// This is a shortcut for creating a struct and calling Default yourself.
+func NewAddRaftVoterRequest() AddRaftVoterRequest {
+	var v AddRaftVoterRequest
+	v.Default()
+	return v
+}
+
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v AddRaftVoterRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *AddRaftVoterRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
+type AddRaftVoterResponse struct {
+	// Version is the version of this message used with a Kafka broker.
+	Version int16
+
+	// ThrottleMillis is how long of a throttle Kafka will apply to the client
+	// after responding to this request.
+	ThrottleMillis int32
+
+	// The error code, or 0 if there was no error.
+	ErrorCode int16
+
+	// The error message, or null if there was no error.
+	ErrorMessage *string
+
+	// UnknownTags are tags Kafka sent that we do not know the purpose of.
+	UnknownTags Tags
+}
+
+func (*AddRaftVoterResponse) Key() int16                         { return 80 }
+func (*AddRaftVoterResponse) MaxVersion() int16                  { return 0 }
+func (v *AddRaftVoterResponse) SetVersion(version int16)         { v.Version = version }
+func (v *AddRaftVoterResponse) GetVersion() int16                { return v.Version }
+func (v *AddRaftVoterResponse) IsFlexible() bool                 { return v.Version >= 0 }
+func (v *AddRaftVoterResponse) Throttle() (int32, bool)          { return v.ThrottleMillis, v.Version >= 0 }
+func (v *AddRaftVoterResponse) SetThrottle(throttleMillis int32) { v.ThrottleMillis = throttleMillis }
+func (v *AddRaftVoterResponse) RequestKind() Request             { return &AddRaftVoterRequest{Version: v.Version} }
+
+func (v *AddRaftVoterResponse) AppendTo(dst []byte) []byte {
+	version := v.Version
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	{
+		v := v.ThrottleMillis
+		dst = kbin.AppendInt32(dst, v)
+	}
+	{
+		v := v.ErrorCode
+		dst = kbin.AppendInt16(dst, v)
+	}
+	{
+		v := v.ErrorMessage
+		if isFlexible {
+			dst = kbin.AppendCompactNullableString(dst, v)
+		} else {
+			dst = kbin.AppendNullableString(dst, v)
+		}
+	}
+	if isFlexible {
+		dst = kbin.AppendUvarint(dst, 0+uint32(v.UnknownTags.Len()))
+		dst = v.UnknownTags.AppendEach(dst)
+	}
+	return dst
+}
+
+func (v *AddRaftVoterResponse) ReadFrom(src []byte) error {
+	return v.readFrom(src, false)
+}
+
+func (v *AddRaftVoterResponse) UnsafeReadFrom(src []byte) error {
+	return v.readFrom(src, true)
+}
+
+func (v *AddRaftVoterResponse) readFrom(src []byte, unsafe bool) error {
+	v.Default()
+	b := kbin.Reader{Src: src}
+	version := v.Version
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	s := v
+	{
+		v := b.Int32()
+		s.ThrottleMillis = v
+	}
+	{
+		v := b.Int16()
+		s.ErrorCode = v
+	}
+	{
+		var v *string
+		if isFlexible {
+			if unsafe {
+				v = b.UnsafeCompactNullableString()
+			} else {
+				v = b.CompactNullableString()
+			}
+		} else {
+			if unsafe {
+				v = b.UnsafeNullableString()
+			} else {
+				v = b.NullableString()
+			}
+		}
+		s.ErrorMessage = v
+	}
+	if isFlexible {
+		s.UnknownTags = internalReadTags(&b)
+	}
+	return b.Complete()
+}
+
+// NewPtrAddRaftVoterResponse returns a pointer to a default AddRaftVoterResponse
+// This is a shortcut for creating a new(struct) and calling Default yourself.
+func NewPtrAddRaftVoterResponse() *AddRaftVoterResponse {
+	var v AddRaftVoterResponse
+	v.Default()
+	return &v
+}
+
+// Default sets any default fields. Calling this allows for future compatibility
+// if new fields are added to AddRaftVoterResponse.
+func (v *AddRaftVoterResponse) Default() {
+}
+
+// NewAddRaftVoterResponse returns a default AddRaftVoterResponse
+// This is a shortcut for creating a struct and calling Default yourself.
+func NewAddRaftVoterResponse() AddRaftVoterResponse {
+	var v AddRaftVoterResponse
+	v.Default()
+	return v
+}
+
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v AddRaftVoterResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *AddRaftVoterResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
+// Part of KIP-853 to support dynamic KRaft controller quorums,
+// RemoveRaftVoterRequest removes a voter from the KRaft quorum.
+type RemoveRaftVoterRequest struct {
+	// Version is the version of this message used with a Kafka broker.
+	Version int16
+
+	// The cluster ID of the request.
+	ClusterID *string
+
+	// The replica ID of the voter getting removed from the topic partition.
+	VoterID int32
+
+	// The directory ID of the voter getting removed from the topic partition.
+	VoterDirectoryID [16]byte
+
+	// UnknownTags are tags Kafka sent that we do not know the purpose of.
+	UnknownTags Tags
+}
+
+func (*RemoveRaftVoterRequest) Key() int16                 { return 81 }
+func (*RemoveRaftVoterRequest) MaxVersion() int16          { return 0 }
+func (v *RemoveRaftVoterRequest) SetVersion(version int16) { v.Version = version }
+func (v *RemoveRaftVoterRequest) GetVersion() int16        { return v.Version }
+func (v *RemoveRaftVoterRequest) IsFlexible() bool         { return v.Version >= 0 }
+func (v *RemoveRaftVoterRequest) IsAdminRequest()          {}
+func (v *RemoveRaftVoterRequest) ResponseKind() Response {
+	r := &RemoveRaftVoterResponse{Version: v.Version}
+	r.Default()
+	return r
+}
+
+// RequestWith is requests v on r and returns the response or an error.
+// For sharded requests, the response may be merged and still return an error.
+// It is better to rely on client.RequestSharded than to rely on proper merging behavior.
+func (v *RemoveRaftVoterRequest) RequestWith(ctx context.Context, r Requestor) (*RemoveRaftVoterResponse, error) {
+	kresp, err := r.Request(ctx, v)
+	resp, _ := kresp.(*RemoveRaftVoterResponse)
+	return resp, err
+}
+
+func (v *RemoveRaftVoterRequest) AppendTo(dst []byte) []byte {
+	version := v.Version
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	{
+		v := v.ClusterID
+		if isFlexible {
+			dst = kbin.AppendCompactNullableString(dst, v)
+		} else {
+			dst = kbin.AppendNullableString(dst, v)
+		}
+	}
+	{
+		v := v.VoterID
+		dst = kbin.AppendInt32(dst, v)
+	}
+	{
+		v := v.VoterDirectoryID
+		dst = kbin.AppendUuid(dst, v)
+	}
+	if isFlexible {
+		dst = kbin.AppendUvarint(dst, 0+uint32(v.UnknownTags.Len()))
+		dst = v.UnknownTags.AppendEach(dst)
+	}
+	return dst
+}
+
+func (v *RemoveRaftVoterRequest) ReadFrom(src []byte) error {
+	return v.readFrom(src, false)
+}
+
+func (v *RemoveRaftVoterRequest) UnsafeReadFrom(src []byte) error {
+	return v.readFrom(src, true)
+}
+
+func (v *RemoveRaftVoterRequest) readFrom(src []byte, unsafe bool) error {
+	v.Default()
+	b := kbin.Reader{Src: src}
+	version := v.Version
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	s := v
+	{
+		var v *string
+		if isFlexible {
+			if unsafe {
+				v = b.UnsafeCompactNullableString()
+			} else {
+				v = b.CompactNullableString()
+			}
+		} else {
+			if unsafe {
+				v = b.UnsafeNullableString()
+			} else {
+				v = b.NullableString()
+			}
+		}
+		s.ClusterID = v
+	}
+	{
+		v := b.Int32()
+		s.VoterID = v
+	}
+	{
+		v := b.Uuid()
+		s.VoterDirectoryID = v
+	}
+	if isFlexible {
+		s.UnknownTags = internalReadTags(&b)
+	}
+	return b.Complete()
+}
+
+// NewPtrRemoveRaftVoterRequest returns a pointer to a default RemoveRaftVoterRequest
+// This is a shortcut for creating a new(struct) and calling Default yourself.
+func NewPtrRemoveRaftVoterRequest() *RemoveRaftVoterRequest {
+	var v RemoveRaftVoterRequest
+	v.Default()
+	return &v
+}
+
+// Default sets any default fields. Calling this allows for future compatibility
+// if new fields are added to RemoveRaftVoterRequest.
+func (v *RemoveRaftVoterRequest) Default() {
+}
+
+// NewRemoveRaftVoterRequest returns a default RemoveRaftVoterRequest
+// This is a shortcut for creating a struct and calling Default yourself.
+func NewRemoveRaftVoterRequest() RemoveRaftVoterRequest {
+	var v RemoveRaftVoterRequest
+	v.Default()
+	return v
+}
+
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v RemoveRaftVoterRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *RemoveRaftVoterRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
+type RemoveRaftVoterResponse struct {
+	// Version is the version of this message used with a Kafka broker.
+	Version int16
+
+	// ThrottleMillis is how long of a throttle Kafka will apply to the client
+	// after responding to this request.
+	ThrottleMillis int32
+
+	// The error code, or 0 if there was no error.
+	ErrorCode int16
+
+	// The error message, or null if there was no error.
+	ErrorMessage *string
+
+	// UnknownTags are tags Kafka sent that we do not know the purpose of.
+	UnknownTags Tags
+}
+
+func (*RemoveRaftVoterResponse) Key() int16                 { return 81 }
+func (*RemoveRaftVoterResponse) MaxVersion() int16          { return 0 }
+func (v *RemoveRaftVoterResponse) SetVersion(version int16) { v.Version = version }
+func (v *RemoveRaftVoterResponse) GetVersion() int16        { return v.Version }
+func (v *RemoveRaftVoterResponse) IsFlexible() bool         { return v.Version >= 0 }
+func (v *RemoveRaftVoterResponse) Throttle() (int32, bool)  { return v.ThrottleMillis, v.Version >= 0 }
+func (v *RemoveRaftVoterResponse) SetThrottle(throttleMillis int32) {
+	v.ThrottleMillis = throttleMillis
+}
+func (v *RemoveRaftVoterResponse) RequestKind() Request {
+	return &RemoveRaftVoterRequest{Version: v.Version}
+}
+
+func (v *RemoveRaftVoterResponse) AppendTo(dst []byte) []byte {
+	version := v.Version
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	{
+		v := v.ThrottleMillis
+		dst = kbin.AppendInt32(dst, v)
+	}
+	{
+		v := v.ErrorCode
+		dst = kbin.AppendInt16(dst, v)
+	}
+	{
+		v := v.ErrorMessage
+		if isFlexible {
+			dst = kbin.AppendCompactNullableString(dst, v)
+		} else {
+			dst = kbin.AppendNullableString(dst, v)
+		}
+	}
+	if isFlexible {
+		dst = kbin.AppendUvarint(dst, 0+uint32(v.UnknownTags.Len()))
+		dst = v.UnknownTags.AppendEach(dst)
+	}
+	return dst
+}
+
+func (v *RemoveRaftVoterResponse) ReadFrom(src []byte) error {
+	return v.readFrom(src, false)
+}
+
+func (v *RemoveRaftVoterResponse) UnsafeReadFrom(src []byte) error {
+	return v.readFrom(src, true)
+}
+
+func (v *RemoveRaftVoterResponse) readFrom(src []byte, unsafe bool) error {
+	v.Default()
+	b := kbin.Reader{Src: src}
+	version := v.Version
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	s := v
+	{
+		v := b.Int32()
+		s.ThrottleMillis = v
+	}
+	{
+		v := b.Int16()
+		s.ErrorCode = v
+	}
+	{
+		var v *string
+		if isFlexible {
+			if unsafe {
+				v = b.UnsafeCompactNullableString()
+			} else {
+				v = b.CompactNullableString()
+			}
+		} else {
+			if unsafe {
+				v = b.UnsafeNullableString()
+			} else {
+				v = b.NullableString()
+			}
+		}
+		s.ErrorMessage = v
+	}
+	if isFlexible {
+		s.UnknownTags = internalReadTags(&b)
+	}
+	return b.Complete()
+}
+
+// NewPtrRemoveRaftVoterResponse returns a pointer to a default RemoveRaftVoterResponse
+// This is a shortcut for creating a new(struct) and calling Default yourself.
+func NewPtrRemoveRaftVoterResponse() *RemoveRaftVoterResponse {
+	var v RemoveRaftVoterResponse
+	v.Default()
+	return &v
+}
+
+// Default sets any default fields. Calling this allows for future compatibility
+// if new fields are added to RemoveRaftVoterResponse.
+func (v *RemoveRaftVoterResponse) Default() {
+}
+
+// NewRemoveRaftVoterResponse returns a default RemoveRaftVoterResponse
+// This is a shortcut for creating a struct and calling Default yourself.
+func NewRemoveRaftVoterResponse() RemoveRaftVoterResponse {
+	var v RemoveRaftVoterResponse
+	v.Default()
+	return v
+}
+
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v RemoveRaftVoterResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *RemoveRaftVoterResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
+type UpdateRaftVoterRequestListener struct {
+	// The name of the endpoint.
+	Name string
+
+	// The hostname.
+	Host string
+
+	// The port.
+	Port uint16
+
+	// UnknownTags are tags Kafka sent that we do not know the purpose of.
+	UnknownTags Tags
+}
+
+// Default sets any default fields. Calling this allows for future compatibility
+// if new fields are added to UpdateRaftVoterRequestListener.
+func (v *UpdateRaftVoterRequestListener) Default() {
+}
+
+// NewUpdateRaftVoterRequestListener returns a default UpdateRaftVoterRequestListener
+// This is a shortcut for creating a struct and calling Default yourself.
+func NewUpdateRaftVoterRequestListener() UpdateRaftVoterRequestListener {
+	var v UpdateRaftVoterRequestListener
+	v.Default()
+	return v
+}
+
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v UpdateRaftVoterRequestListener) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *UpdateRaftVoterRequestListener) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
+type UpdateRaftVoterRequestKRaftVersionFeature struct {
+	// The minimum supported KRaft protocol version.
+	MinSupportedVersion int16
+
+	// The maximum supported KRaft protocol version.
+	MaxSupportedVersion int16
+
+	// UnknownTags are tags Kafka sent that we do not know the purpose of.
+	UnknownTags Tags
+}
+
+// Default sets any default fields. Calling this allows for future compatibility
+// if new fields are added to UpdateRaftVoterRequestKRaftVersionFeature.
+func (v *UpdateRaftVoterRequestKRaftVersionFeature) Default() {
+}
+
+// NewUpdateRaftVoterRequestKRaftVersionFeature returns a default UpdateRaftVoterRequestKRaftVersionFeature
+// This is a shortcut for creating a struct and calling Default yourself.
+func NewUpdateRaftVoterRequestKRaftVersionFeature() UpdateRaftVoterRequestKRaftVersionFeature {
+	var v UpdateRaftVoterRequestKRaftVersionFeature
+	v.Default()
+	return v
+}
+
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v UpdateRaftVoterRequestKRaftVersionFeature) MarshalJSON() ([]byte, error) {
+	return marshalJSON(&v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *UpdateRaftVoterRequestKRaftVersionFeature) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
+// Part of KIP-853 to support dynamic KRaft controller quorums,
+// UpdateRaftVoterRequest is sent by a voter to the quorum leader to update
+// the voter's listeners and supported kraft.version range.
+type UpdateRaftVoterRequest struct {
+	// Version is the version of this message used with a Kafka broker.
+	Version int16
+
+	// The cluster ID of the request.
+	ClusterID *string
+
+	// The current leader epoch of the voter.
+	CurrentLeaderEpoch int32
+
+	// The replica ID of the voter getting updated in the topic partition.
+	VoterID int32
+
+	// The directory ID of the voter getting updated in the topic partition.
+	VoterDirectoryID [16]byte
+
+	// The endpoints that can be used to communicate with the voter.
+	Listeners []UpdateRaftVoterRequestListener
+
+	// The range of versions of the protocol that the replica supports.
+	KRaftVersionFeature UpdateRaftVoterRequestKRaftVersionFeature
+
+	// UnknownTags are tags Kafka sent that we do not know the purpose of.
+	UnknownTags Tags
+}
+
+func (*UpdateRaftVoterRequest) Key() int16                 { return 82 }
+func (*UpdateRaftVoterRequest) MaxVersion() int16          { return 0 }
+func (v *UpdateRaftVoterRequest) SetVersion(version int16) { v.Version = version }
+func (v *UpdateRaftVoterRequest) GetVersion() int16        { return v.Version }
+func (v *UpdateRaftVoterRequest) IsFlexible() bool         { return v.Version >= 0 }
+func (v *UpdateRaftVoterRequest) ResponseKind() Response {
+	r := &UpdateRaftVoterResponse{Version: v.Version}
+	r.Default()
+	return r
+}
+
+// RequestWith is requests v on r and returns the response or an error.
+// For sharded requests, the response may be merged and still return an error.
+// It is better to rely on client.RequestSharded than to rely on proper merging behavior.
+func (v *UpdateRaftVoterRequest) RequestWith(ctx context.Context, r Requestor) (*UpdateRaftVoterResponse, error) {
+	kresp, err := r.Request(ctx, v)
+	resp, _ := kresp.(*UpdateRaftVoterResponse)
+	return resp, err
+}
+
+func (v *UpdateRaftVoterRequest) AppendTo(dst []byte) []byte {
+	version := v.Version
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	{
+		v := v.ClusterID
+		if isFlexible {
+			dst = kbin.AppendCompactNullableString(dst, v)
+		} else {
+			dst = kbin.AppendNullableString(dst, v)
+		}
+	}
+	{
+		v := v.CurrentLeaderEpoch
+		dst = kbin.AppendInt32(dst, v)
+	}
+	{
+		v := v.VoterID
+		dst = kbin.AppendInt32(dst, v)
+	}
+	{
+		v := v.VoterDirectoryID
+		dst = kbin.AppendUuid(dst, v)
+	}
+	{
+		v := v.Listeners
+		if isFlexible {
+			dst = kbin.AppendCompactArrayLen(dst, len(v))
+		} else {
+			dst = kbin.AppendArrayLen(dst, len(v))
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Name
+				if isFlexible {
+					dst = kbin.AppendCompactString(dst, v)
+				} else {
+					dst = kbin.AppendString(dst, v)
+				}
+			}
+			{
+				v := v.Host
+				if isFlexible {
+					dst = kbin.AppendCompactString(dst, v)
+				} else {
+					dst = kbin.AppendString(dst, v)
+				}
+			}
+			{
+				v := v.Port
+				dst = kbin.AppendUint16(dst, v)
+			}
+			if isFlexible {
+				dst = kbin.AppendUvarint(dst, 0+uint32(v.UnknownTags.Len()))
+				dst = v.UnknownTags.AppendEach(dst)
+			}
+		}
+	}
+	{
+		v := &v.KRaftVersionFeature
+		{
+			v := v.MinSupportedVersion
+			dst = kbin.AppendInt16(dst, v)
+		}
+		{
+			v := v.MaxSupportedVersion
+			dst = kbin.AppendInt16(dst, v)
+		}
+		if isFlexible {
+			dst = kbin.AppendUvarint(dst, 0+uint32(v.UnknownTags.Len()))
+			dst = v.UnknownTags.AppendEach(dst)
+		}
+	}
+	if isFlexible {
+		dst = kbin.AppendUvarint(dst, 0+uint32(v.UnknownTags.Len()))
+		dst = v.UnknownTags.AppendEach(dst)
+	}
+	return dst
+}
+
+func (v *UpdateRaftVoterRequest) ReadFrom(src []byte) error {
+	return v.readFrom(src, false)
+}
+
+func (v *UpdateRaftVoterRequest) UnsafeReadFrom(src []byte) error {
+	return v.readFrom(src, true)
+}
+
+func (v *UpdateRaftVoterRequest) readFrom(src []byte, unsafe bool) error {
+	v.Default()
+	b := kbin.Reader{Src: src}
+	version := v.Version
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	s := v
+	{
+		var v *string
+		if isFlexible {
+			if unsafe {
+				v = b.UnsafeCompactNullableString()
+			} else {
+				v = b.CompactNullableString()
+			}
+		} else {
+			if unsafe {
+				v = b.UnsafeNullableString()
+			} else {
+				v = b.NullableString()
+			}
+		}
+		s.ClusterID = v
+	}
+	{
+		v := b.Int32()
+		s.CurrentLeaderEpoch = v
+	}
+	{
+		v := b.Int32()
+		s.VoterID = v
+	}
+	{
+		v := b.Uuid()
+		s.VoterDirectoryID = v
+	}
+	{
+		v := s.Listeners
+		a := v
+		var l int32
+		if isFlexible {
+			l = b.CompactArrayLen()
+		} else {
+			l = b.ArrayLen()
+		}
+		if !b.Ok() {
+			return b.Complete()
+		}
+		a = a[:0]
+		if l > 0 {
+			a = append(a, make([]UpdateRaftVoterRequestListener, l)...)
+		}
+		for i := int32(0); i < l; i++ {
+			v := &a[i]
+			v.Default()
+			s := v
+			{
+				var v string
+				if unsafe {
+					if isFlexible {
+						v = b.UnsafeCompactString()
+					} else {
+						v = b.UnsafeString()
+					}
+				} else {
+					if isFlexible {
+						v = b.CompactString()
+					} else {
+						v = b.String()
+					}
+				}
+				s.Name = v
+			}
+			{
+				var v string
+				if unsafe {
+					if isFlexible {
+						v = b.UnsafeCompactString()
+					} else {
+						v = b.UnsafeString()
+					}
+				} else {
+					if isFlexible {
+						v = b.CompactString()
+					} else {
+						v = b.String()
+					}
+				}
+				s.Host = v
+			}
+			{
+				v := b.Uint16()
+				s.Port = v
+			}
+			if isFlexible {
+				s.UnknownTags = internalReadTags(&b)
+			}
+		}
+		v = a
+		s.Listeners = v
+	}
+	{
+		v := &s.KRaftVersionFeature
+		v.Default()
+		s := v
+		{
+			v := b.Int16()
+			s.MinSupportedVersion = v
+		}
+		{
+			v := b.Int16()
+			s.MaxSupportedVersion = v
+		}
+		if isFlexible {
+			s.UnknownTags = internalReadTags(&b)
+		}
+	}
+	if isFlexible {
+		s.UnknownTags = internalReadTags(&b)
+	}
+	return b.Complete()
+}
+
+// NewPtrUpdateRaftVoterRequest returns a pointer to a default UpdateRaftVoterRequest
+// This is a shortcut for creating a new(struct) and calling Default yourself.
+func NewPtrUpdateRaftVoterRequest() *UpdateRaftVoterRequest {
+	var v UpdateRaftVoterRequest
+	v.Default()
+	return &v
+}
+
+// Default sets any default fields. Calling this allows for future compatibility
+// if new fields are added to UpdateRaftVoterRequest.
+func (v *UpdateRaftVoterRequest) Default() {
+	{
+		v := &v.KRaftVersionFeature
+		_ = v
+	}
+}
+
+// NewUpdateRaftVoterRequest returns a default UpdateRaftVoterRequest
+// This is a shortcut for creating a struct and calling Default yourself.
+func NewUpdateRaftVoterRequest() UpdateRaftVoterRequest {
+	var v UpdateRaftVoterRequest
+	v.Default()
+	return v
+}
+
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v UpdateRaftVoterRequest) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *UpdateRaftVoterRequest) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
+type UpdateRaftVoterResponseCurrentLeader struct {
+	// The replica ID of the current leader, or -1 if the leader is unknown.
+	//
+	// This field has a default of -1.
+	LeaderID int32
+
+	// The latest known leader epoch.
+	//
+	// This field has a default of -1.
+	LeaderEpoch int32
+
+	// The node's hostname.
+	Host string
+
+	// The node's port.
+	Port int32
+
+	// UnknownTags are tags Kafka sent that we do not know the purpose of.
+	UnknownTags Tags
+}
+
+// Default sets any default fields. Calling this allows for future compatibility
+// if new fields are added to UpdateRaftVoterResponseCurrentLeader.
+func (v *UpdateRaftVoterResponseCurrentLeader) Default() {
+	v.LeaderID = -1
+	v.LeaderEpoch = -1
+}
+
+// NewUpdateRaftVoterResponseCurrentLeader returns a default UpdateRaftVoterResponseCurrentLeader
+// This is a shortcut for creating a struct and calling Default yourself.
+func NewUpdateRaftVoterResponseCurrentLeader() UpdateRaftVoterResponseCurrentLeader {
+	var v UpdateRaftVoterResponseCurrentLeader
+	v.Default()
+	return v
+}
+
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v UpdateRaftVoterResponseCurrentLeader) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *UpdateRaftVoterResponseCurrentLeader) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
+type UpdateRaftVoterResponse struct {
+	// Version is the version of this message used with a Kafka broker.
+	Version int16
+
+	// ThrottleMillis is how long of a throttle Kafka will apply to the client
+	// after responding to this request.
+	ThrottleMillis int32
+
+	// The error code, or 0 if there was no error.
+	ErrorCode int16
+
+	// Details of the current leader of the quorum, if known.
+	CurrentLeader UpdateRaftVoterResponseCurrentLeader // tag 0
+
+	// UnknownTags are tags Kafka sent that we do not know the purpose of.
+	UnknownTags Tags
+}
+
+func (*UpdateRaftVoterResponse) Key() int16                 { return 82 }
+func (*UpdateRaftVoterResponse) MaxVersion() int16          { return 0 }
+func (v *UpdateRaftVoterResponse) SetVersion(version int16) { v.Version = version }
+func (v *UpdateRaftVoterResponse) GetVersion() int16        { return v.Version }
+func (v *UpdateRaftVoterResponse) IsFlexible() bool         { return v.Version >= 0 }
+func (v *UpdateRaftVoterResponse) Throttle() (int32, bool)  { return v.ThrottleMillis, v.Version >= 0 }
+func (v *UpdateRaftVoterResponse) SetThrottle(throttleMillis int32) {
+	v.ThrottleMillis = throttleMillis
+}
+func (v *UpdateRaftVoterResponse) RequestKind() Request {
+	return &UpdateRaftVoterRequest{Version: v.Version}
+}
+
+func (v *UpdateRaftVoterResponse) AppendTo(dst []byte) []byte {
+	version := v.Version
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	{
+		v := v.ThrottleMillis
+		dst = kbin.AppendInt32(dst, v)
+	}
+	{
+		v := v.ErrorCode
+		dst = kbin.AppendInt16(dst, v)
+	}
+	if isFlexible {
+		var toEncode []uint32
+		if !reflect.DeepEqual(v.CurrentLeader, (func() UpdateRaftVoterResponseCurrentLeader {
+			var v UpdateRaftVoterResponseCurrentLeader
+			v.Default()
+			return v
+		})()) {
+			toEncode = append(toEncode, 0)
+		}
+		dst = kbin.AppendUvarint(dst, uint32(len(toEncode)+v.UnknownTags.Len()))
+		for _, tag := range toEncode {
+			switch tag {
+			case 0:
+				{
+					v := v.CurrentLeader
+					dst = kbin.AppendUvarint(dst, 0)
+					sized := false
+					lenAt := len(dst)
+				fCurrentLeader:
+					{
+						v := v.LeaderID
+						dst = kbin.AppendInt32(dst, v)
+					}
+					{
+						v := v.LeaderEpoch
+						dst = kbin.AppendInt32(dst, v)
+					}
+					{
+						v := v.Host
+						if isFlexible {
+							dst = kbin.AppendCompactString(dst, v)
+						} else {
+							dst = kbin.AppendString(dst, v)
+						}
+					}
+					{
+						v := v.Port
+						dst = kbin.AppendInt32(dst, v)
+					}
+					if isFlexible {
+						dst = kbin.AppendUvarint(dst, 0+uint32(v.UnknownTags.Len()))
+						dst = v.UnknownTags.AppendEach(dst)
+					}
+					if !sized {
+						dst = kbin.AppendUvarint(dst[:lenAt], uint32(len(dst[lenAt:])))
+						sized = true
+						goto fCurrentLeader
+					}
+				}
+			}
+		}
+		dst = v.UnknownTags.AppendEach(dst)
+	}
+	return dst
+}
+
+func (v *UpdateRaftVoterResponse) ReadFrom(src []byte) error {
+	return v.readFrom(src, false)
+}
+
+func (v *UpdateRaftVoterResponse) UnsafeReadFrom(src []byte) error {
+	return v.readFrom(src, true)
+}
+
+func (v *UpdateRaftVoterResponse) readFrom(src []byte, unsafe bool) error {
+	v.Default()
+	b := kbin.Reader{Src: src}
+	version := v.Version
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	s := v
+	{
+		v := b.Int32()
+		s.ThrottleMillis = v
+	}
+	{
+		v := b.Int16()
+		s.ErrorCode = v
+	}
+	if isFlexible {
+		for i := b.Uvarint(); i > 0; i-- {
+			switch key := b.Uvarint(); key {
+			default:
+				s.UnknownTags.Set(key, b.Span(int(b.Uvarint())))
+			case 0:
+				b := kbin.Reader{Src: b.Span(int(b.Uvarint()))}
+				v := &s.CurrentLeader
+				v.Default()
+				s := v
+				{
+					v := b.Int32()
+					s.LeaderID = v
+				}
+				{
+					v := b.Int32()
+					s.LeaderEpoch = v
+				}
+				{
+					var v string
+					if unsafe {
+						if isFlexible {
+							v = b.UnsafeCompactString()
+						} else {
+							v = b.UnsafeString()
+						}
+					} else {
+						if isFlexible {
+							v = b.CompactString()
+						} else {
+							v = b.String()
+						}
+					}
+					s.Host = v
+				}
+				{
+					v := b.Int32()
+					s.Port = v
+				}
+				if isFlexible {
+					s.UnknownTags = internalReadTags(&b)
+				}
+				if err := b.Complete(); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return b.Complete()
+}
+
+// NewPtrUpdateRaftVoterResponse returns a pointer to a default UpdateRaftVoterResponse
+// This is a shortcut for creating a new(struct) and calling Default yourself.
+func NewPtrUpdateRaftVoterResponse() *UpdateRaftVoterResponse {
+	var v UpdateRaftVoterResponse
+	v.Default()
+	return &v
+}
+
+// Default sets any default fields. Calling this allows for future compatibility
+// if new fields are added to UpdateRaftVoterResponse.
+func (v *UpdateRaftVoterResponse) Default() {
+	{
+		v := &v.CurrentLeader
+		_ = v
+		v.LeaderID = -1
+		v.LeaderEpoch = -1
+	}
+}
+
+// NewUpdateRaftVoterResponse returns a default UpdateRaftVoterResponse
+// This is a shortcut for creating a struct and calling Default yourself.
+func NewUpdateRaftVoterResponse() UpdateRaftVoterResponse {
+	var v UpdateRaftVoterResponse
+	v.Default()
+	return v
+}
+
+// MarshalJSON implements json.Marshaler; see the package documentation
+// for how fields are encoded.
+func (v UpdateRaftVoterResponse) MarshalJSON() ([]byte, error) { return marshalJSON(&v) }
+
+// UnmarshalJSON implements json.Unmarshaler; see the package documentation
+// for how fields are decoded.
+func (v *UpdateRaftVoterResponse) UnmarshalJSON(b []byte) error { return unmarshalJSON(b, v) }
+
 // RequestForKey returns the request corresponding to the given request key
 // or nil if the key is unknown.
 func RequestForKey(key int16) Request {
@@ -46795,6 +57310,26 @@ func RequestForKey(key int16) Request {
 		return NewPtrConsumerGroupHeartbeatRequest()
 	case 69:
 		return NewPtrConsumerGroupDescribeRequest()
+	case 71:
+		return NewPtrGetTelemetrySubscriptionsRequest()
+	case 72:
+		return NewPtrPushTelemetryRequest()
+	case 75:
+		return NewPtrDescribeTopicPartitionsRequest()
+	case 76:
+		return NewPtrShareGroupHeartbeatRequest()
+	case 77:
+		return NewPtrShareGroupDescribeRequest()
+	case 78:
+		return NewPtrShareFetchRequest()
+	case 79:
+		return NewPtrShareAcknowledgeRequest()
+	case 80:
+		return NewPtrAddRaftVoterRequest()
+	case 81:
+		return NewPtrRemoveRaftVoterRequest()
+	case 82:
+		return NewPtrUpdateRaftVoterRequest()
 	}
 }
 
@@ -46944,6 +57479,26 @@ func ResponseForKey(key int16) Response {
 		return NewPtrConsumerGroupHeartbeatResponse()
 	case 69:
 		return NewPtrConsumerGroupDescribeResponse()
+	case 71:
+		return NewPtrGetTelemetrySubscriptionsResponse()
+	case 72:
+		return NewPtrPushTelemetryResponse()
+	case 75:
+		return NewPtrDescribeTopicPartitionsResponse()
+	case 76:
+		return NewPtrShareGroupHeartbeatResponse()
+	case 77:
+		return NewPtrShareGroupDescribeResponse()
+	case 78:
+		return NewPtrShareFetchResponse()
+	case 79:
+		return NewPtrShareAcknowledgeResponse()
+	case 80:
+		return NewPtrAddRaftVoterResponse()
+	case 81:
+		return NewPtrRemoveRaftVoterResponse()
+	case 82:
+		return NewPtrUpdateRaftVoterResponse()
 	}
 }
 
@@ -47093,6 +57648,26 @@ func NameForKey(key int16) string {
 		return "ConsumerGroupHeartbeat"
 	case 69:
 		return "ConsumerGroupDescribe"
+	case 71:
+		return "GetTelemetrySubscriptions"
+	case 72:
+		return "PushTelemetry"
+	case 75:
+		return "DescribeTopicPartitions"
+	case 76:
+		return "ShareGroupHeartbeat"
+	case 77:
+		return "ShareGroupDescribe"
+	case 78:
+		return "ShareFetch"
+	case 79:
+		return "ShareAcknowledge"
+	case 80:
+		return "AddRaftVoter"
+	case 81:
+		return "RemoveRaftVoter"
+	case 82:
+		return "UpdateRaftVoter"
 	}
 }
 
@@ -47170,6 +57745,16 @@ const (
 	AllocateProducerIDs          Key = 67
 	ConsumerGroupHeartbeat       Key = 68
 	ConsumerGroupDescribe        Key = 69
+	GetTelemetrySubscriptions    Key = 71
+	PushTelemetry                Key = 72
+	DescribeTopicPartitions      Key = 75
+	ShareGroupHeartbeat          Key = 76
+	ShareGroupDescribe           Key = 77
+	ShareFetch                   Key = 78
+	ShareAcknowledge             Key = 79
+	AddRaftVoter                 Key = 80
+	RemoveRaftVoter              Key = 81
+	UpdateRaftVoter              Key = 82
 )
 
 // Name returns the name for this key.
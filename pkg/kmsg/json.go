@@ -0,0 +1,281 @@
+package kmsg
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+var (
+	jsonTagsType  = reflect.TypeOf(Tags{})
+	jsonUUIDType  = reflect.TypeOf([16]byte{})
+	jsonBytesType = reflect.TypeOf([]byte(nil))
+)
+
+// marshalJSON is called from every generated MarshalJSON function; v must be
+// a pointer to a struct. We walk the struct ourselves rather than use
+// encoding/json directly so that UUIDs, tags, and enums with unknown values
+// are encoded sensibly no matter how deeply they are nested.
+func marshalJSON(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := appendJSON(&buf, reflect.ValueOf(v).Elem()); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func appendJSON(buf *bytes.Buffer, v reflect.Value) error {
+	switch v.Type() {
+	case jsonUUIDType:
+		u := v.Interface().([16]byte)
+		buf.WriteByte('"')
+		buf.WriteString(base64.RawURLEncoding.EncodeToString(u[:]))
+		buf.WriteByte('"')
+		return nil
+
+	case jsonBytesType:
+		if v.IsNil() {
+			buf.WriteString("null")
+			return nil
+		}
+		buf.WriteByte('"')
+		buf.WriteString(base64.StdEncoding.EncodeToString(v.Bytes()))
+		buf.WriteByte('"')
+		return nil
+
+	case jsonTagsType:
+		tags := v.Interface().(Tags)
+		buf.WriteByte('{')
+		var i int
+		tags.Each(func(key uint32, val []byte) {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			i++
+			fmt.Fprintf(buf, `"%d":"%s"`, key, base64.StdEncoding.EncodeToString(val))
+		})
+		buf.WriteByte('}')
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		t := v.Type()
+		buf.WriteByte('{')
+		var n int
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			fv := v.Field(i)
+			if f.Type == jsonTagsType {
+				if tags := fv.Interface().(Tags); tags.Len() == 0 {
+					continue // unknown tags are only encoded if present
+				}
+			}
+			if n > 0 {
+				buf.WriteByte(',')
+			}
+			n++
+			buf.WriteString(strconv.Quote(f.Name))
+			buf.WriteByte(':')
+			if err := appendJSON(buf, fv); err != nil {
+				return fmt.Errorf("%s: %w", f.Name, err)
+			}
+		}
+		buf.WriteByte('}')
+		return nil
+
+	case reflect.Pointer:
+		if v.IsNil() {
+			buf.WriteString("null")
+			return nil
+		}
+		return appendJSON(buf, v.Elem())
+
+	case reflect.Slice:
+		if v.IsNil() {
+			buf.WriteString("null")
+			return nil
+		}
+		buf.WriteByte('[')
+		for i := 0; i < v.Len(); i++ {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := appendJSON(buf, v.Index(i)); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+		return nil
+
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		// Enums encode as their name, unless the value is unknown and
+		// the name would not decode back to the same value.
+		if m, ok := v.Interface().(encoding.TextMarshaler); ok {
+			text, err := m.MarshalText()
+			if err != nil {
+				return err
+			}
+			back := reflect.New(v.Type())
+			if err := back.Interface().(encoding.TextUnmarshaler).UnmarshalText(text); err == nil && back.Elem().Int() == v.Int() {
+				buf.WriteString(strconv.Quote(string(text)))
+				return nil
+			}
+		}
+		buf.WriteString(strconv.FormatInt(v.Int(), 10))
+		return nil
+	}
+
+	b, err := json.Marshal(v.Interface())
+	if err != nil {
+		return err
+	}
+	buf.Write(b)
+	return nil
+}
+
+// unmarshalJSON is called from every generated UnmarshalJSON function; v
+// must be a pointer to a struct. This decodes what marshalJSON encodes.
+// Fields that are missing from the input are left untouched, and new structs
+// inside slices or nullable fields are defaulted before decoding into them.
+func unmarshalJSON(b []byte, v any) error {
+	return decodeJSON(b, reflect.ValueOf(v).Elem())
+}
+
+func decodeJSON(b []byte, v reflect.Value) error {
+	b = bytes.TrimSpace(b)
+	null := string(b) == "null"
+
+	switch v.Type() {
+	case jsonUUIDType:
+		var s string
+		if err := json.Unmarshal(b, &s); err != nil {
+			return err
+		}
+		u, err := base64.RawURLEncoding.DecodeString(s)
+		if err != nil {
+			return fmt.Errorf("invalid uuid %q: %w", s, err)
+		}
+		if len(u) != 16 {
+			return fmt.Errorf("invalid uuid %q: decoded to %d bytes rather than 16", s, len(u))
+		}
+		reflect.Copy(v, reflect.ValueOf(u))
+		return nil
+
+	case jsonBytesType:
+		var bs []byte
+		if !null {
+			if err := json.Unmarshal(b, &bs); err != nil {
+				return err
+			}
+		}
+		v.SetBytes(bs)
+		return nil
+
+	case jsonTagsType:
+		var kvs map[string][]byte
+		if err := json.Unmarshal(b, &kvs); err != nil {
+			return err
+		}
+		keys := make([]string, 0, len(kvs))
+		for k := range kvs {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		var tags Tags
+		for _, k := range keys {
+			key, err := strconv.ParseUint(k, 10, 32)
+			if err != nil {
+				return fmt.Errorf("invalid tag key %q: %w", k, err)
+			}
+			tags.Set(uint32(key), kvs[k])
+		}
+		v.Set(reflect.ValueOf(tags))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		if null {
+			return nil
+		}
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(b, &fields); err != nil {
+			return err
+		}
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			raw, ok := fields[f.Name]
+			if !ok {
+				continue
+			}
+			if err := decodeJSON(raw, v.Field(i)); err != nil {
+				return fmt.Errorf("%s: %w", f.Name, err)
+			}
+		}
+		return nil
+
+	case reflect.Pointer:
+		if null {
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		}
+		if v.IsNil() {
+			v.Set(newDefaulted(v.Type().Elem()))
+		}
+		return decodeJSON(b, v.Elem())
+
+	case reflect.Slice:
+		if null {
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		}
+		var elems []json.RawMessage
+		if err := json.Unmarshal(b, &elems); err != nil {
+			return err
+		}
+		s := reflect.MakeSlice(v.Type(), len(elems), len(elems))
+		for i, elem := range elems {
+			ev := s.Index(i)
+			ev.Set(newDefaulted(ev.Type()).Elem())
+			if err := decodeJSON(elem, ev); err != nil {
+				return fmt.Errorf("index %d: %w", i, err)
+			}
+		}
+		v.Set(s)
+		return nil
+
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if len(b) > 0 && b[0] != '"' {
+			n, err := strconv.ParseInt(string(b), 10, v.Type().Bits())
+			if err != nil {
+				return err
+			}
+			v.SetInt(n)
+			return nil
+		}
+	}
+
+	return json.Unmarshal(b, v.Addr().Interface())
+}
+
+// newDefaulted returns a pointer to a new t, calling Default if t has it.
+func newDefaulted(t reflect.Type) reflect.Value {
+	p := reflect.New(t)
+	if d, ok := p.Interface().(interface{ Default() }); ok {
+		d.Default()
+	}
+	return p
+}
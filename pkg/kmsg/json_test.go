@@ -0,0 +1,153 @@
+package kmsg
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestJSONRoundTrip(t *testing.T) {
+	str := func(s string) *string { return &s }
+
+	var tags Tags
+	tags.Set(10, []byte("ten"))
+	tags.Set(2, []byte{0, 1, 2})
+
+	record := NewRecord()
+	record.Key = []byte("k")
+	record.Value = nil // null values are tombstones
+	record.Headers = []Header{{Key: "h", Value: []byte("v")}}
+	batch := NewRecordBatch()
+	batch.NumRecords = 1
+	batch.Records = record.AppendTo(nil)
+
+	produceTopic := NewProduceRequestTopic()
+	produceTopic.Topic = "t"
+	producePartition := NewProduceRequestTopicPartition()
+	producePartition.Records = batch.AppendTo(nil)
+	producePartition.UnknownTags = tags
+	produceTopic.Partitions = append(produceTopic.Partitions, producePartition)
+
+	for _, test := range []struct {
+		name string
+		v    any
+	}{
+		{"null topics", &MetadataRequest{Version: 12, Topics: nil}},
+		{"empty topics", &MetadataRequest{Version: 12, Topics: []MetadataRequestTopic{}}},
+		{"nullable strings", &MetadataRequest{Version: 12, Topics: []MetadataRequestTopic{
+			{TopicID: [16]byte{0: 1, 15: 0xff}},
+			{Topic: str("t")},
+			{Topic: str("")},
+		}}},
+		{"tags", &MetadataRequest{Version: 12, UnknownTags: tags}},
+		{"enums", &DescribeConfigsRequest{Resources: []DescribeConfigsRequestResource{
+			{ResourceType: ConfigResourceTypeTopic, ResourceName: "t"},
+			{ResourceType: 3, ConfigNames: []string{"a", "b"}},
+		}}},
+		{"records", &ProduceRequest{Version: 9, TransactionID: str("txn"), Acks: -1, Topics: []ProduceRequestTopic{produceTopic}}},
+		{"null bytes", &ProduceRequest{Version: 9, Topics: []ProduceRequestTopic{{Partitions: []ProduceRequestTopicPartition{{Records: nil}}}}}},
+		{"empty bytes", &ProduceRequest{Version: 9, Topics: []ProduceRequestTopic{{Partitions: []ProduceRequestTopicPartition{{Records: []byte{}}}}}}},
+		{"record batch", &batch},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			b, err := json.Marshal(test.v)
+			if err != nil {
+				t.Fatalf("unable to marshal: %v", err)
+			}
+			got := reflect.New(reflect.TypeOf(test.v).Elem()).Interface()
+			if err := json.Unmarshal(b, got); err != nil {
+				t.Fatalf("unable to unmarshal %s: %v", b, err)
+			}
+			if !reflect.DeepEqual(got, test.v) {
+				t.Errorf("round trip through %s mismatch:\ngot %#v\nexp %#v", b, got, test.v)
+			}
+		})
+	}
+}
+
+func TestJSONEncoding(t *testing.T) {
+	str := func(s string) *string { return &s }
+
+	var tags Tags
+	tags.Set(3, []byte("x"))
+
+	for _, test := range []struct {
+		name string
+		v    any
+		exp  string
+	}{
+		{
+			"null and empty",
+			&MetadataRequest{Version: 12, Topics: []MetadataRequestTopic{{}}},
+			`{"Version":12,"Topics":[{"TopicID":"AAAAAAAAAAAAAAAAAAAAAA","Topic":null}],"AllowAutoTopicCreation":false,"IncludeClusterAuthorizedOperations":false,"IncludeTopicAuthorizedOperations":false}`,
+		},
+		{
+			"uuid and tags",
+			&MetadataRequest{Version: 12, Topics: []MetadataRequestTopic{{TopicID: [16]byte{15: 0xff}, Topic: str("t"), UnknownTags: tags}}},
+			`{"Version":12,"Topics":[{"TopicID":"AAAAAAAAAAAAAAAAAAAA_w","Topic":"t","UnknownTags":{"3":"eA=="}}],"AllowAutoTopicCreation":false,"IncludeClusterAuthorizedOperations":false,"IncludeTopicAuthorizedOperations":false}`,
+		},
+		{
+			"known and unknown enums",
+			&DescribeConfigsRequest{Resources: []DescribeConfigsRequestResource{{ResourceType: ConfigResourceTypeTopic}, {ResourceType: 3}}},
+			`{"Version":0,"Resources":[{"ResourceType":"TOPIC","ResourceName":"","ConfigNames":null},{"ResourceType":3,"ResourceName":"","ConfigNames":null}],"IncludeSynonyms":false,"IncludeDocumentation":false}`,
+		},
+		{
+			"bytes",
+			&ProduceRequest{Topics: []ProduceRequestTopic{{Topic: "t", Partitions: []ProduceRequestTopicPartition{{Records: []byte("abc")}, {}}}}},
+			`{"Version":0,"TransactionID":null,"Acks":0,"TimeoutMillis":0,"Topics":[{"Topic":"t","Partitions":[{"Partition":0,"Records":"YWJj"},{"Partition":0,"Records":null}]}]}`,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			b, err := json.Marshal(test.v)
+			if err != nil {
+				t.Fatalf("unable to marshal: %v", err)
+			}
+			if string(b) != test.exp {
+				t.Errorf("got %s\nexp %s", b, test.exp)
+			}
+		})
+	}
+}
+
+func TestJSONDecoding(t *testing.T) {
+	// Fields missing from the input are left as they were, and structs
+	// created while decoding are defaulted first.
+	req := NewPtrMetadataRequest()
+	req.Version = 7
+	req.AllowAutoTopicCreation = false
+	if err := json.Unmarshal([]byte(`{"Topics":[{"Topic":"t"}]}`), req); err != nil {
+		t.Fatal(err)
+	}
+	if req.Version != 7 || req.AllowAutoTopicCreation || len(req.Topics) != 1 || req.Topics[0].Topic == nil || *req.Topics[0].Topic != "t" {
+		t.Errorf("unexpected decoded request %#v", req)
+	}
+
+	// Enums can be decoded from their names or numbers.
+	var resource DescribeConfigsRequestResource
+	for _, in := range []string{`{"ResourceType":"TOPIC"}`, `{"ResourceType":2}`} {
+		resource.ResourceType = 0
+		if err := json.Unmarshal([]byte(in), &resource); err != nil {
+			t.Fatalf("%s: %v", in, err)
+		}
+		if resource.ResourceType != ConfigResourceTypeTopic {
+			t.Errorf("%s: got resource type %v, exp TOPIC", in, resource.ResourceType)
+		}
+	}
+
+	for _, test := range []struct {
+		in     string
+		errHas string
+	}{
+		{`{"Topics":[{"TopicID":"short"}]}`, "Topics: index 0: TopicID: invalid uuid"},
+		{`{"Topics":[{"TopicID":"!!"}]}`, "invalid uuid"},
+		{`{"UnknownTags":{"x":"AA=="}}`, `invalid tag key "x"`},
+		{`{"Topics":{}}`, "Topics"},
+		{`{"Version":"seven"}`, "Version"},
+	} {
+		err := json.Unmarshal([]byte(test.in), NewPtrMetadataRequest())
+		if err == nil || !strings.Contains(err.Error(), test.errHas) {
+			t.Errorf("%s: got err %v, exp one containing %q", test.in, err, test.errHas)
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"bytes"
 	"fmt"
 	"regexp"
+	"sort"
 	"sync"
 	"text/tabwriter"
 
@@ -68,6 +69,8 @@ var versions = []struct {
 	{"v3.6", V3_6_0()},
 	{"v3.7", V3_7_0()},
 	{"v3.8", V3_8_0()},
+	{"v3.9", V3_9_0()},
+	{"v4.0", V4_0_0()},
 }
 
 // VersionStrings returns all recognized versions, minus any patch, that can be
@@ -233,6 +236,26 @@ func TryRaftController() VersionGuessOpt {
 type guessCfg struct {
 	skipKeys []int16
 	listener listener
+	hosts    []string
+}
+
+func newGuessCfg(opts []VersionGuessOpt) guessCfg {
+	cfg := guessCfg{
+		listener: zkBroker,
+		// Envelope was added in 2.7 for kraft and zkBroker in 3.4; we
+		// need to skip it for 2.7 through 3.4 otherwise the version
+		// detection fails. We can just skip it generally since there
+		// are enough differentiating factors that accurately detecting
+		// envelope doesn't matter.
+		//
+		// TODO: add introduced-version to differentiate some specific
+		// keys.
+		skipKeys: []int16{4, 5, 6, 7, 27, 52, 53, 54, 55, 56, 57, 58, 59, 62, 63, 64, 67, 74, 75},
+	}
+	for _, opt := range opts {
+		opt.apply(&cfg)
+	}
+	return cfg
 }
 
 // VersionGuess attempts to guess which version of Kafka these versions belong
@@ -254,32 +277,96 @@ type guessCfg struct {
 // versions as Raft broker based versions.
 //
 // Internally, this function tries guessing the version against both KRaft and
-// Kafka APIs. The more exact match is returned.
+// Kafka APIs. The more exact match is returned. To also learn which type of
+// server the versions are from, use Guess.
+//
+// If the versions are likely not from Apache Kafka (see Vendor), the vendor
+// is appended to the guess in parentheses, for example, "v3.1 (Redpanda)".
 func (vs *Versions) VersionGuess(opts ...VersionGuessOpt) string {
+	g := vs.Guess(opts...)
+	if g.Vendor != VendorApacheKafka {
+		return g.Version + " (" + g.Vendor.String() + ")"
+	}
+	return g.Version
+}
+
+// Listener is the type of server that versions are from.
+type Listener int8
+
+const (
+	// ListenerZkBroker is a classical ZooKeeper based broker.
+	ListenerZkBroker Listener = iota
+	// ListenerRaftBroker is a KRaft based broker (v2.8+).
+	ListenerRaftBroker
+	// ListenerRaftController is a KRaft controller (v2.8+).
+	ListenerRaftController
+)
+
+func (l Listener) String() string {
+	switch l {
+	case ListenerZkBroker:
+		return "zk broker"
+	case ListenerRaftBroker:
+		return "kraft broker"
+	case ListenerRaftController:
+		return "kraft controller"
+	}
+	return "unknown"
+}
+
+// Guess is a detailed version guess; see Versions.Guess.
+type Guess struct {
+	// Version is the guessed Apache Kafka version, in the same format
+	// that VersionGuess returns, without the vendor.
+	Version string
+	// Listener is the type of server that the guess is for.
+	Listener Listener
+	// Exact is whether the versions exactly match a known release.
+	Exact bool
+	// Vendor is the likely implementation of the Kafka protocol that the
+	// versions are from.
+	Vendor Vendor
+}
+
+// Guess is VersionGuess, but also returns which type of server the versions
+// are likely from. Only ZooKeeper brokers support LeaderAndIsr requests and
+// only KRaft controllers support BrokerRegistration requests; when guesses
+// for multiple types of servers match, the guess for the type that the
+// versions look like they are from is preferred.
+//
+// A KRaft controller supports fewer requests than a broker, so releases that
+// do not change any controller request are indistinguishable when guessing
+// for a controller. In this case, the oldest matching release is returned.
+func (vs *Versions) Guess(opts ...VersionGuessOpt) Guess {
+	g := vs.guess(opts)
+	g.Vendor = vs.vendor(newGuessCfg(opts).hosts)
+	return g
+}
+
+func (vs *Versions) guess(opts []VersionGuessOpt) Guess {
 	standard := vs.versionGuess(opts...)
-	raftBroker := vs.versionGuess(append(opts, TryRaftBroker())...)
-	raftController := vs.versionGuess(append(opts, TryRaftController())...)
+	gs := []guess{
+		standard,
+		vs.versionGuess(append(opts, TryRaftBroker())...),
+		vs.versionGuess(append(opts, TryRaftController())...),
+	}
+	likely := vs.likelyListener()
+	sort.SliceStable(gs, func(i, j int) bool {
+		return gs[i].listener == likely && gs[j].listener != likely
+	})
 
 	// If any of these are exact, return the exact guess.
-	for _, g := range []guess{
-		standard,
-		raftBroker,
-		raftController,
-	} {
+	for _, g := range gs {
 		if g.how == guessExact {
-			return g.String()
+			return g.detail()
 		}
 	}
 
 	// If any are atLeast, that means it is newer than we can guess and we
 	// return the highest version.
-	for _, g := range []guess{
-		standard,
-		raftBroker,
-		raftController,
-	} {
+	for _, g := range gs {
 		if g.how == guessAtLeast {
-			return g.String()
+			return g.detail()
 		}
 	}
 
@@ -287,13 +374,39 @@ func (vs *Versions) VersionGuess(opts ...VersionGuessOpt) string {
 	// return highest of all three guesses, but that may be inaccurate:
 	// KRaft may detect a higher guess because not all requests exist in
 	// KRaft. Instead, we just return our standard guess.
-	return standard.String()
+	return standard.detail()
+}
+
+func (vs *Versions) likelyListener() listener {
+	switch {
+	case vs.HasKey(4): // leader and isr
+		return zkBroker
+	case vs.HasKey(62): // broker registration
+		return rController
+	default:
+		return rBroker
+	}
 }
 
 type guess struct {
-	v1  string
-	v2  string // for between
-	how int8
+	v1       string
+	v2       string // for between
+	how      int8
+	listener listener
+}
+
+func (g guess) detail() Guess {
+	d := Guess{
+		Version: g.String(),
+		Exact:   g.how == guessExact,
+	}
+	switch g.listener {
+	case rBroker:
+		d.Listener = ListenerRaftBroker
+	case rController:
+		d.Listener = ListenerRaftController
+	}
+	return d
 }
 
 const (
@@ -324,21 +437,7 @@ func (g guess) String() string {
 }
 
 func (vs *Versions) versionGuess(opts ...VersionGuessOpt) guess {
-	cfg := guessCfg{
-		listener: zkBroker,
-		// Envelope was added in 2.7 for kraft and zkBroker in 3.4; we
-		// need to skip it for 2.7 through 3.4 otherwise the version
-		// detection fails. We can just skip it generally since there
-		// are enough differentiating factors that accurately detecting
-		// envelope doesn't matter.
-		//
-		// TODO: add introduced-version to differentiate some specific
-		// keys.
-		skipKeys: []int16{4, 5, 6, 7, 27, 52, 53, 54, 55, 56, 57, 58, 59, 62, 63, 64, 67, 74, 75},
-	}
-	for _, opt := range opts {
-		opt.apply(&cfg)
-	}
+	cfg := newGuessCfg(opts)
 
 	skip := make(map[int16]bool, len(cfg.skipKeys))
 	for _, k := range cfg.skipKeys {
@@ -380,6 +479,8 @@ func (vs *Versions) versionGuess(opts ...VersionGuessOpt) guess {
 		{max360, "v3.6"},
 		{max370, "v3.7"},
 		{max380, "v3.8"},
+		{max390, "v3.9"},
+		{max400, "v4.0"},
 	} {
 		for k, v := range comparison.cmp.filter(cfg.listener) {
 			if v == -1 {
@@ -442,30 +543,30 @@ func (vs *Versions) versionGuess(opts ...VersionGuessOpt) guess {
 		case under && over:
 			// Regardless of equal being true or not, this is a custom version.
 			if last != "" {
-				return guess{v1: last, how: guessCustomAtLeast}
+				return guess{v1: last, how: guessCustomAtLeast, listener: cfg.listener}
 			}
-			return guess{v1: last, how: guessCustomUnknown}
+			return guess{v1: last, how: guessCustomUnknown, listener: cfg.listener}
 
 		case under:
 			// Regardless of equal being true or not, we have not yet hit
 			// this version.
 			if last != "" {
-				return guess{v1: last, v2: current, how: guessBetween}
+				return guess{v1: last, v2: current, how: guessBetween, listener: cfg.listener}
 			}
-			return guess{v1: current, how: guessNotEven}
+			return guess{v1: current, how: guessNotEven, listener: cfg.listener}
 
 		case over:
 			// Regardless of equal being true or not, we try again.
 			last = current
 
 		case equal:
-			return guess{v1: current, how: guessExact}
+			return guess{v1: current, how: guessExact, listener: cfg.listener}
 		}
 		// At least one of under, equal, or over must be true, so there
 		// is no default case.
 	}
 
-	return guess{v1: last, how: guessAtLeast}
+	return guess{v1: last, how: guessAtLeast, listener: cfg.listener}
 }
 
 // String returns a string representation of the versions; the format may
@@ -523,6 +624,11 @@ func V3_5_0() *Versions  { return zkBrokerOf(max350) }
 func V3_6_0() *Versions  { return zkBrokerOf(max360) }
 func V3_7_0() *Versions  { return zkBrokerOf(max370) }
 func V3_8_0() *Versions  { return zkBrokerOf(max380) }
+func V3_9_0() *Versions  { return zkBrokerOf(max390) }
+
+// V4_0_0 returns the versions of a v4.0 KRaft broker: ZooKeeper support was
+// removed in v4.0.
+func V4_0_0() *Versions { return &Versions{max400.filter(rBroker)} }
 
 func zkBrokerOf(lks listenerKeys) *Versions {
 	return &Versions{lks.filter(zkBroker)}
@@ -1158,6 +1264,23 @@ var max370 = nextMax(max360, func(v listenerKeys) listenerKeys {
 		k(zkBroker, rBroker), // 68 consumer group heartbeat
 	)
 
+	v = append(v,
+		k(), // 69 consumer group describe, not stable until 3.8
+
+		// KIP-919
+		k(rController), // 70 controller registration
+
+		// KIP-714
+		k(zkBroker, rBroker), // 71 get telemetry subscriptions
+		k(zkBroker, rBroker), // 72 push telemetry
+
+		// KIP-858
+		k(rController), // 73 assign replicas to dirs
+
+		// KIP-714
+		k(zkBroker, rBroker), // 74 list client metrics resources
+	)
+
 	return v
 })
 
@@ -1180,18 +1303,83 @@ var max380 = nextMax(max370, func(v listenerKeys) listenerKeys {
 	// b7c99e22a77392d6053fe231209e1de32b50a98b
 	// 68389c244e720566aaa8443cd3fc0b9d2ec4bb7a
 	// 5f410ceb04878ca44d2d007655155b5303a47907 stabilized
-	v = append(v,
-		k(zkBroker, rBroker), // 69 consumer group describe
-	)
+	v[69] = k(zkBroker, rBroker) // 69 consumer group describe
 
 	// KAFKA-16265 b4e96913cc6c827968e47a31261e0bd8fdf677b5 KIP-994 (part 1)
 	v[66].inc()
 
+	// KIP-966
+	v = append(v,
+		k(zkBroker, rBroker), // 75 describe topic partitions
+	)
+
+	return v
+})
+
+var max390 = nextMax(max380, func(v listenerKeys) listenerKeys {
+	// KIP-853
+	v[1].inc()  // 17 fetch
+	v[52].inc() // 1 vote
+	v[53].inc() // 1 begin quorum epoch
+	v[54].inc() // 1 end quorum epoch
+	v[55].inc() // 2 describe quorum
+	v[59].inc() // 1 fetch snapshot
+
+	// KIP-1005
+	v[2].inc() // 9 list offsets
+
+	// KAFKA-17011
+	v[18].inc() // 4 api versions
+
+	v = append(v,
+		// KIP-932, early access and not enabled by default.
+		k(), // 76 share group heartbeat
+		k(), // 77 share group describe
+		k(), // 78 share fetch
+		k(), // 79 share acknowledge
+
+		// KIP-853
+		k(rBroker, rController), // 80 add raft voter
+		k(rBroker, rController), // 81 remove raft voter
+		k(rController),          // 82 update raft voter
+	)
+
+	return v
+})
+
+var max400 = nextMax(max390, func(v listenerKeys) listenerKeys {
+	// KIP-890 (part 2)
+	v[0].inc()  // 12 produce
+	v[26].inc() // 5 end txn
+	v[28].inc() // 5 txn offset commit
+
+	// KIP-1075
+	v[2].inc() // 10 list offsets
+
+	// KIP-1102
+	v[3].inc() // 13 metadata
+
+	// KIP-932
+	v[10].inc() // 6 find coordinator
+
+	// KIP-1043
+	v[15].inc() // 6 describe groups
+
+	// KIP-996
+	v[52].inc() // 2 vote
+
+	// KIP-1073
+	v[60].inc() // 2 describe cluster
+
+	// KIP-848
+	v[68].inc() // 1 consumer group heartbeat
+	v[69].inc() // 1 consumer group describe
+
 	return v
 })
 
 var (
-	maxStable = max380
+	maxStable = max400
 	maxTip    = nextMax(maxStable, func(v listenerKeys) listenerKeys {
 		return v
 	})
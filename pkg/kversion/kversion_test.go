@@ -254,6 +254,10 @@ func TestGuessVersions(t *testing.T) {
 		{V3_4_0(), "v3.4"},
 		{V3_5_0(), "v3.5"},
 		{V3_6_0(), "v3.6"},
+		{V3_7_0(), "v3.7"},
+		{V3_8_0(), "v3.8"},
+		{V3_9_0(), "v3.9"},
+		{V4_0_0(), "v4.0"},
 	} {
 		got := test.vs.VersionGuess()
 		if got != test.exp {
@@ -261,3 +265,70 @@ func TestGuessVersions(t *testing.T) {
 		}
 	}
 }
+
+func TestGuessListener(t *testing.T) {
+	for _, test := range []struct {
+		vs       *Versions
+		version  string
+		listener Listener
+	}{
+		{V2_7_0(), "v2.7", ListenerZkBroker},
+		{V3_8_0(), "v3.8", ListenerZkBroker},
+		{&Versions{max380.filter(rBroker)}, "v3.8", ListenerRaftBroker},
+		{&Versions{max390.filter(rBroker)}, "v3.9", ListenerRaftBroker},
+		{&Versions{max390.filter(rController)}, "v3.9", ListenerRaftController},
+		{&Versions{max400.filter(rController)}, "v4.0", ListenerRaftController},
+		{V4_0_0(), "v4.0", ListenerRaftBroker},
+	} {
+		g := test.vs.Guess()
+		if g.Version != test.version || g.Listener != test.listener || !g.Exact {
+			t.Errorf("got %s %s (exact? %v) != exp %s %s", g.Version, g.Listener, g.Exact, test.version, test.listener)
+		}
+	}
+
+	// A broker newer than we know of should be "at least" the newest
+	// release, as a raft broker.
+	vs := V4_0_0()
+	vs.SetMaxKeyVersion(0, 100)
+	if g := vs.Guess(); g.Version != "at least v4.0" || g.Listener != ListenerRaftBroker || g.Exact {
+		t.Errorf("got %s %s (exact? %v) != exp at least v4.0 kraft broker", g.Version, g.Listener, g.Exact)
+	}
+}
+
+func TestGuessVendor(t *testing.T) {
+	// A reimplementation supports neither ZooKeeper nor KRaft internal
+	// requests.
+	reimpl := func(reassign bool) *Versions {
+		vs := V3_0_0()
+		for _, k := range []int16{4, 5, 6, 7, 55, 57, 64} {
+			vs.SetMaxKeyVersion(k, -1)
+		}
+		if !reassign {
+			vs.SetMaxKeyVersion(45, -1)
+			vs.SetMaxKeyVersion(46, -1)
+		}
+		return vs
+	}
+
+	for _, test := range []struct {
+		vs     *Versions
+		hosts  []string
+		vendor Vendor
+		guess  string
+	}{
+		{V3_8_0(), nil, VendorApacheKafka, "v3.8"},
+		{V4_0_0(), nil, VendorApacheKafka, "v4.0"},
+		{V3_8_0(), []string{"b-1.foo.abc123.c2.kafka.us-east-1.amazonaws.com:9098"}, VendorMSK, "v3.8 (Amazon MSK)"},
+		{V4_0_0(), []string{"pkc-abc12.us-west-2.aws.confluent.cloud:9092"}, VendorConfluentCloud, "v4.0 (Confluent Cloud)"},
+		{reimpl(true), nil, VendorRedpanda, "v3.0 (Redpanda)"},
+		{reimpl(false), nil, VendorWarpStream, "unknown custom version at least v2.3 (WarpStream)"},
+	} {
+		opts := []VersionGuessOpt{BrokerHosts(test.hosts...)}
+		if g := test.vs.Guess(opts...); g.Vendor != test.vendor {
+			t.Errorf("got vendor %s != exp %s", g.Vendor, test.vendor)
+		}
+		if got := test.vs.VersionGuess(opts...); got != test.guess {
+			t.Errorf("got %s != exp %s", got, test.guess)
+		}
+	}
+}
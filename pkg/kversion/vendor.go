@@ -0,0 +1,89 @@
+package kversion
+
+import (
+	"net"
+	"strings"
+)
+
+// Vendor is an implementation of the Kafka protocol or a managed Kafka
+// service.
+//
+// Vendors are guessed with heuristics. Managed services that run Apache Kafka
+// have the same API surface as Apache Kafka and can only be detected by the
+// hosts of their brokers; see BrokerHosts. Reimplementations of the protocol
+// are detected by their API surface: Apache Kafka brokers always support
+// either the ZooKeeper broker LeaderAndIsr request or the KRaft
+// DescribeQuorum, UpdateFeatures, and UnregisterBroker requests, which
+// reimplementations have no need for.
+type Vendor int8
+
+const (
+	// VendorApacheKafka is Apache Kafka, or anything that cannot be
+	// distinguished from it.
+	VendorApacheKafka Vendor = iota
+	// VendorMSK is Amazon Managed Streaming for Apache Kafka, detected by
+	// broker hosts only.
+	VendorMSK
+	// VendorConfluentCloud is Confluent Cloud, detected by broker hosts
+	// only.
+	VendorConfluentCloud
+	// VendorRedpanda is Redpanda, which supports partition reassignment
+	// requests.
+	VendorRedpanda
+	// VendorWarpStream is WarpStream, which has no replicas and thus does
+	// not support partition reassignment requests.
+	VendorWarpStream
+)
+
+func (v Vendor) String() string {
+	switch v {
+	case VendorApacheKafka:
+		return "Apache Kafka"
+	case VendorMSK:
+		return "Amazon MSK"
+	case VendorConfluentCloud:
+		return "Confluent Cloud"
+	case VendorRedpanda:
+		return "Redpanda"
+	case VendorWarpStream:
+		return "WarpStream"
+	}
+	return "unknown"
+}
+
+// BrokerHosts provides the hosts of the brokers that the versions are from,
+// which are used to detect managed services when guessing the vendor. Hosts
+// can include ports.
+func BrokerHosts(hosts ...string) VersionGuessOpt {
+	return guessOpt{func(cfg *guessCfg) { cfg.hosts = hosts }}
+}
+
+func (vs *Versions) vendor(hosts []string) Vendor {
+	for _, host := range hosts {
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		host = strings.ToLower(host)
+		switch {
+		case strings.HasSuffix(host, ".amazonaws.com") && strings.Contains(host, ".kafka"):
+			return VendorMSK
+		case strings.HasSuffix(host, ".confluent.cloud"):
+			return VendorConfluentCloud
+		case strings.Contains(host, "redpanda"):
+			return VendorRedpanda
+		case strings.Contains(host, "warpstream"):
+			return VendorWarpStream
+		}
+	}
+
+	isZk := vs.HasKey(4) // leader and isr
+	isRaft := vs.HasKey(55) || vs.HasKey(57) || vs.HasKey(64)
+	switch {
+	case isZk || isRaft:
+		return VendorApacheKafka
+	case vs.HasKey(45): // alter partition assignments
+		return VendorRedpanda
+	default:
+		return VendorWarpStream
+	}
+}
@@ -0,0 +1,195 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/sasl"
+)
+
+// ClientCredentials contains the configuration to fetch OAUTHBEARER tokens
+// from an OAuth 2.0 / OIDC token endpoint with the client credentials grant,
+// as specified in RFC6749 section 4.4.
+//
+// This client may add fields to this struct in the future.
+type ClientCredentials struct {
+	// TokenURL is the token endpoint of the identity provider, for
+	// example https://login.example.com/oauth2/token.
+	TokenURL string
+
+	// ClientID is the client ID to request tokens for.
+	ClientID string
+	// ClientSecret is the client secret.
+	ClientSecret string
+
+	// Scopes are optional scopes to request.
+	Scopes []string
+	// EndpointParams are optional additional form parameters to send to
+	// the token endpoint, such as an "audience" that some providers
+	// require.
+	EndpointParams url.Values
+
+	// Zid is an optional authorization ID to use in authenticating.
+	Zid string
+	// Extensions are key value pairs to add to every authentication
+	// request.
+	Extensions map[string]string
+
+	// HTTPClient is the client to use to request tokens, overriding the
+	// default of a client with a 30s timeout.
+	HTTPClient *http.Client
+
+	// RefreshAfter is the fraction of a token's lifetime after which the
+	// token is refreshed, overriding the default of 0.8. A token expiring
+	// in an hour is refreshed once 48 minutes have elapsed.
+	RefreshAfter float64
+
+	_ struct{} // require explicit field initialization
+}
+
+// AsMechanism returns an OAUTHBEARER sasl mechanism that fetches tokens with
+// the client credentials flow. See TokenSource for more details.
+func (c ClientCredentials) AsMechanism() sasl.Mechanism {
+	return Oauth(c.TokenSource())
+}
+
+// TokenSource returns a function that can be used with Oauth. The function
+// returns a cached token while the token is fresh, and fetches a new token
+// once the RefreshAfter fraction of the token's lifetime has elapsed. Tokens
+// are refreshed before they expire, so that a token used to authenticate a
+// connection remains valid for at least a portion of the session, and so
+// that a transient failure to fetch a new token does not fail authentication:
+// if fetching fails, the cached token is used until it actually expires.
+//
+// If the token endpoint does not reply with expires_in, tokens are not cached.
+//
+// The returned function is safe for concurrent use; concurrent calls while a
+// token is being fetched wait for that single fetch.
+func (c ClientCredentials) TokenSource() func(context.Context) (Auth, error) {
+	s := &ccSource{c: c}
+	if s.c.HTTPClient == nil {
+		s.c.HTTPClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	if s.c.RefreshAfter <= 0 || s.c.RefreshAfter > 1 {
+		s.c.RefreshAfter = 0.8
+	}
+	return s.auth
+}
+
+type ccSource struct {
+	c ClientCredentials
+
+	mu        sync.Mutex
+	token     string
+	refreshAt time.Time
+	expiresAt time.Time
+}
+
+func (s *ccSource) auth(ctx context.Context) (Auth, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if s.token == "" || !now.Before(s.refreshAt) {
+		token, lifetime, err := s.fetch(ctx)
+		switch {
+		case err == nil:
+			s.token = token
+			s.refreshAt = now.Add(time.Duration(float64(lifetime) * s.c.RefreshAfter))
+			s.expiresAt = now.Add(lifetime)
+		case s.token != "" && now.Before(s.expiresAt):
+			// Keep using our still valid token; we retry
+			// fetching the next time we are called.
+		default:
+			s.token = ""
+			return Auth{}, err
+		}
+	}
+
+	return Auth{
+		Zid:        s.c.Zid,
+		Token:      s.token,
+		Extensions: s.c.Extensions,
+	}, nil
+}
+
+// fetch requests a new token, returning the token and its lifetime. The
+// lifetime is zero if the endpoint did not specify one.
+func (s *ccSource) fetch(ctx context.Context) (string, time.Duration, error) {
+	form := url.Values{}
+	for k, vs := range s.c.EndpointParams {
+		form[k] = append([]string(nil), vs...)
+	}
+	form.Set("grant_type", "client_credentials")
+	if len(s.c.Scopes) > 0 {
+		form.Set("scope", strings.Join(s.c.Scopes, " "))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.c.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", 0, fmt.Errorf("unable to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	// https://www.rfc-editor.org/rfc/rfc6749#section-2.3.1: the client
+	// ID and secret are form encoded before being used as basic auth.
+	req.SetBasicAuth(url.QueryEscape(s.c.ClientID), url.QueryEscape(s.c.ClientSecret))
+
+	resp, err := s.c.HTTPClient.Do(req)
+	if err != nil {
+		return "", 0, fmt.Errorf("unable to request token: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", 0, fmt.Errorf("unable to read token response: %w", err)
+	}
+
+	var tr struct {
+		AccessToken      string      `json:"access_token"`
+		TokenType        string      `json:"token_type"`
+		ExpiresIn        json.Number `json:"expires_in"` // some providers use a string
+		Error            string      `json:"error"`
+		ErrorDescription string      `json:"error_description"`
+	}
+	jsonErr := json.Unmarshal(body, &tr)
+
+	if resp.StatusCode != http.StatusOK {
+		if jsonErr == nil && tr.Error != "" {
+			if tr.ErrorDescription != "" {
+				return "", 0, fmt.Errorf("token endpoint replied with status %d: %s: %s", resp.StatusCode, tr.Error, tr.ErrorDescription)
+			}
+			return "", 0, fmt.Errorf("token endpoint replied with status %d: %s", resp.StatusCode, tr.Error)
+		}
+		return "", 0, fmt.Errorf("token endpoint replied with status %d", resp.StatusCode)
+	}
+	if jsonErr != nil {
+		return "", 0, fmt.Errorf("unable to decode token response: %w", jsonErr)
+	}
+	if tr.AccessToken == "" {
+		return "", 0, errors.New("token response is missing access_token")
+	}
+	if tr.TokenType != "" && !strings.EqualFold(tr.TokenType, "bearer") {
+		return "", 0, fmt.Errorf("token response has unsupported token_type %q", tr.TokenType)
+	}
+
+	var lifetime time.Duration
+	if tr.ExpiresIn != "" {
+		secs, err := tr.ExpiresIn.Int64()
+		if err != nil {
+			return "", 0, fmt.Errorf("token response has invalid expires_in %q", tr.ExpiresIn)
+		}
+		if secs > 0 {
+			lifetime = time.Duration(secs) * time.Second
+		}
+	}
+	return tr.AccessToken, lifetime, nil
+}
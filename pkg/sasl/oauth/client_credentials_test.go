@@ -0,0 +1,189 @@
+package oauth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// tokenServer is a token endpoint that replies with whatever reply is set to.
+type tokenServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	status   int
+	reply    string
+	requests []*http.Request
+	forms    []url.Values
+}
+
+func newTokenServer(t *testing.T) *tokenServer {
+	s := &tokenServer{status: http.StatusOK}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("unable to parse token request form: %v", err)
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.requests = append(s.requests, r)
+		s.forms = append(s.forms, r.PostForm)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(s.status)
+		w.Write([]byte(s.reply))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *tokenServer) set(status int, reply string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status, s.reply = status, reply
+}
+
+func (s *tokenServer) numRequests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.requests)
+}
+
+func TestClientCredentialsRequest(t *testing.T) {
+	s := newTokenServer(t)
+	s.set(http.StatusOK, `{"access_token":"tok","token_type":"Bearer","expires_in":3600}`)
+
+	auth, err := ClientCredentials{
+		TokenURL:       s.URL,
+		ClientID:       "id:1",
+		ClientSecret:   "se cret",
+		Scopes:         []string{"a", "b"},
+		EndpointParams: url.Values{"audience": {"kafka"}, "grant_type": {"ignored"}},
+		Zid:            "zid",
+		Extensions:     map[string]string{"k": "v"},
+	}.TokenSource()(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if auth.Token != "tok" || auth.Zid != "zid" || auth.Extensions["k"] != "v" {
+		t.Errorf("unexpected auth %+v", auth)
+	}
+
+	r, form := s.requests[0], s.forms[0]
+	if r.Method != http.MethodPost {
+		t.Errorf("got method %s, exp POST", r.Method)
+	}
+	if id, secret, _ := r.BasicAuth(); id != "id%3A1" || secret != "se+cret" {
+		t.Errorf("got basic auth %q %q, exp form encoded id and secret", id, secret)
+	}
+	for k, exp := range map[string]string{
+		"grant_type": "client_credentials",
+		"scope":      "a b",
+		"audience":   "kafka",
+	} {
+		if got := form.Get(k); got != exp {
+			t.Errorf("form %s: got %q, exp %q", k, got, exp)
+		}
+	}
+}
+
+func TestClientCredentialsRefresh(t *testing.T) {
+	s := newTokenServer(t)
+	s.set(http.StatusOK, `{"access_token":"t1","expires_in":1}`)
+	source := ClientCredentials{TokenURL: s.URL, RefreshAfter: 0.2}.TokenSource()
+
+	expect := func(token string, requests int) {
+		t.Helper()
+		auth, err := source(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if auth.Token != token || s.numRequests() != requests {
+			t.Fatalf("got token %q after %d requests, exp %q after %d", auth.Token, s.numRequests(), token, requests)
+		}
+	}
+
+	// The first token is cached until it should be refreshed.
+	expect("t1", 1)
+	expect("t1", 1)
+
+	// Once we should refresh, a failed refresh keeps using the token
+	// that has not yet expired, and we retry on the next call.
+	time.Sleep(300 * time.Millisecond)
+	s.set(http.StatusServiceUnavailable, "")
+	expect("t1", 2)
+	s.set(http.StatusOK, `{"access_token":"t2","expires_in":"1"}`)
+	expect("t2", 3)
+	expect("t2", 3)
+
+	// Once the token expires, failing to refresh fails authentication.
+	time.Sleep(1100 * time.Millisecond)
+	s.set(http.StatusServiceUnavailable, "")
+	if _, err := source(context.Background()); err == nil {
+		t.Fatal("unexpected success with an expired token and a failing endpoint")
+	}
+	s.set(http.StatusOK, `{"access_token":"t3"}`)
+	expect("t3", 5)
+
+	// Without expires_in, tokens are not cached.
+	expect("t3", 6)
+}
+
+func TestClientCredentialsConcurrent(t *testing.T) {
+	s := newTokenServer(t)
+	s.set(http.StatusOK, `{"access_token":"tok","expires_in":3600}`)
+	source := ClientCredentials{TokenURL: s.URL}.TokenSource()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if auth, err := source(context.Background()); err != nil || auth.Token != "tok" {
+				t.Errorf("got %q, %v; exp tok", auth.Token, err)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := s.numRequests(); n != 1 {
+		t.Errorf("got %d token requests, exp 1", n)
+	}
+}
+
+func TestClientCredentialsErrors(t *testing.T) {
+	for _, test := range []struct {
+		status int
+		reply  string
+		errHas string
+	}{
+		{http.StatusBadRequest, `{"error":"invalid_client","error_description":"bad secret"}`, "status 400: invalid_client: bad secret"},
+		{http.StatusUnauthorized, `{"error":"unauthorized_client"}`, "status 401: unauthorized_client"},
+		{http.StatusInternalServerError, `<html>oops</html>`, "status 500"},
+		{http.StatusOK, `not json`, "unable to decode token response"},
+		{http.StatusOK, `{"token_type":"bearer"}`, "missing access_token"},
+		{http.StatusOK, `{"access_token":"tok","token_type":"mac"}`, `unsupported token_type "mac"`},
+		{http.StatusOK, `{"access_token":"tok","expires_in":"1.5"}`, `invalid expires_in "1.5"`},
+	} {
+		s := newTokenServer(t)
+		s.set(test.status, test.reply)
+		_, err := ClientCredentials{TokenURL: s.URL}.TokenSource()(context.Background())
+		if err == nil || !strings.Contains(err.Error(), test.errHas) {
+			t.Errorf("%d %s: got err %v, exp one containing %q", test.status, test.reply, err, test.errHas)
+		}
+	}
+
+	// An unreachable endpoint fails, as does a canceled context.
+	s := newTokenServer(t)
+	s.Close()
+	if _, err := (ClientCredentials{TokenURL: s.URL}.TokenSource()(context.Background())); err == nil || !strings.Contains(err.Error(), "unable to request token") {
+		t.Errorf("unreachable endpoint: got err %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (ClientCredentials{TokenURL: newTokenServer(t).URL}.TokenSource()(ctx)); err == nil {
+		t.Error("canceled context: unexpected success")
+	}
+}
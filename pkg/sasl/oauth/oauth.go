@@ -1,5 +1,8 @@
 // Package oauth provides OAUTHBEARER sasl authentication as specified in
 // RFC7628.
+//
+// Tokens can be provided directly, or fetched and refreshed from an OAuth 2.0
+// or OIDC token endpoint with ClientCredentials.
 package oauth
 
 import (
@@ -178,11 +178,13 @@ func (cl *Client) Subjects(ctx context.Context) ([]string, error) {
 
 // SchemaByID returns the schema for a given schema ID.
 //
+// The result is cached if the client uses CacheTTL.
+//
 // This supports params [Subject], [Format], and [FetchMaxID].
 func (cl *Client) SchemaByID(ctx context.Context, id int) (Schema, error) {
 	// GET /schemas/ids/{id}
 	var s Schema
-	err := cl.get(ctx, fmt.Sprintf("/schemas/ids/%d", id), &s)
+	err := cl.cachedGet(ctx, fmt.Sprintf("/schemas/ids/%d", id), &s)
 	return s, err
 }
 
@@ -196,11 +198,13 @@ func (cl *Client) SchemaByID(ctx context.Context, id int) (Schema, error) {
 //
 //	{"type":"boolean"}
 //
+// The result is cached if the client uses CacheTTL.
+//
 // This supports params [Subject], [Format].
 func (cl *Client) SchemaTextByID(ctx context.Context, id int) (string, error) {
 	// GET /schemas/ids/{id}/schema
 	var s []byte
-	if err := cl.get(ctx, fmt.Sprintf("/schemas/ids/%d/schema", id), &s); err != nil {
+	if err := cl.cachedGet(ctx, fmt.Sprintf("/schemas/ids/%d/schema", id), &s); err != nil {
 		return "", err
 	}
 	return string(s), nil
@@ -268,12 +272,14 @@ func (cl *Client) SubjectVersions(ctx context.Context, subject string) ([]int, e
 // SchemaByVersion returns the schema for a given subject and version. You can
 // use -1 as the version to return the latest schema.
 //
+// The result is cached if the client uses CacheTTL.
+//
 // This supports param [ShowDeleted].
 func (cl *Client) SchemaByVersion(ctx context.Context, subject string, version int) (SubjectSchema, error) {
 	// GET /subjects/{subject}/versions/{version}
 	var ss SubjectSchema
 	path := pathSubjectVersion(subject, version)
-	err := cl.get(ctx, path, &ss)
+	err := cl.cachedGet(ctx, path, &ss)
 	return ss, err
 }
 
@@ -288,12 +294,14 @@ func (cl *Client) SchemaByVersion(ctx context.Context, subject string, version i
 //
 //	{"type":"boolean"}
 //
+// The result is cached if the client uses CacheTTL.
+//
 // This supports param [ShowDeleted].
 func (cl *Client) SchemaTextByVersion(ctx context.Context, subject string, version int) (string, error) {
 	// GET /subjects/{subject}/versions/{version}/schema
 	var s []byte
 	path := pathSubjectVersion(subject, version) + "/schema"
-	if err := cl.get(ctx, path, &s); err != nil {
+	if err := cl.cachedGet(ctx, path, &s); err != nil {
 		return "", err
 	}
 	return string(s), nil
@@ -362,6 +370,7 @@ func (cl *Client) CreateSchema(ctx context.Context, subject string, s Schema) (S
 	if err := cl.post(ctx, path, s, &id); err != nil {
 		return SubjectSchema{}, err
 	}
+	cl.cacheInvalidate(subject)
 
 	usages, err := cl.SchemaUsagesByID(ctx, id.ID)
 	if err != nil {
@@ -408,6 +417,7 @@ func (cl *Client) DeleteSubject(ctx context.Context, subject string, how DeleteH
 	}
 	var versions []int
 	defer func() { sort.Ints(versions) }()
+	defer cl.cacheInvalidate(subject)
 	err := cl.delete(ctx, path, &versions)
 	return versions, err
 }
@@ -421,6 +431,7 @@ func (cl *Client) DeleteSchema(ctx context.Context, subject string, version int,
 	if how == HardDelete {
 		ctx = WithParams(ctx, hardDelete)
 	}
+	defer cl.cacheInvalidate(subject)
 	return cl.delete(ctx, path, nil)
 }
 
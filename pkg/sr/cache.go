@@ -0,0 +1,179 @@
+package sr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CacheTTL enables caching schemas looked up with SchemaByID, SchemaTextByID,
+// SchemaByVersion, and SchemaTextByVersion, and sets how long a cached
+// schema is used before it is fetched again. Caching is disabled by default.
+//
+// Schemas are cached per request path and parameters, meaning the latest
+// version of a subject (version -1) is cached separately from each specific
+// version. Creating or deleting schemas in a subject with this client drops
+// cached schemas for the subject, but changes made by other clients are only
+// seen after the TTL expires.
+//
+// Caching is meant for high throughput consumers that look up the same
+// schemas repeatedly; see CacheStaleTTL to serve stale schemas while
+// refreshing them in the background.
+func CacheTTL(ttl time.Duration) ClientOpt {
+	return clientOpt{func(cl *Client) { cl.cache.ttl = ttl }}
+}
+
+// CacheNegativeTTL caches "not found" responses for the given duration, so
+// that repeatedly looking up a missing schema does not repeatedly query the
+// registry. This option requires CacheTTL. Negative caching is disabled by
+// default. Creating a schema with this client drops all cached "not found"
+// responses.
+func CacheNegativeTTL(ttl time.Duration) ClientOpt {
+	return clientOpt{func(cl *Client) { cl.cache.negTTL = ttl }}
+}
+
+// CacheStaleTTL allows a cached schema to be used for up to ttl after its
+// CacheTTL expires while the schema is refreshed in the background
+// (stale-while-revalidate). If the background refresh fails, for example
+// because the registry is unavailable, the stale schema continues to be used
+// until it is successfully refreshed or the stale TTL also expires, allowing
+// clients to survive brief registry outages. This option requires CacheTTL.
+func CacheStaleTTL(ttl time.Duration) ClientOpt {
+	return clientOpt{func(cl *Client) { cl.cache.staleTTL = ttl }}
+}
+
+// PurgeCache drops everything that is cached; see CacheTTL.
+func (cl *Client) PurgeCache() {
+	cl.cache.mu.Lock()
+	defer cl.cache.mu.Unlock()
+	cl.cache.entries = nil
+}
+
+type cache struct {
+	ttl      time.Duration
+	negTTL   time.Duration
+	staleTTL time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+}
+
+type cacheEntry struct {
+	body       []byte
+	err        error // non-nil for cached "not found" responses
+	at         time.Time
+	refreshing bool
+}
+
+// cachedGet is get, but uses and fills the cache if caching is enabled.
+func (cl *Client) cachedGet(ctx context.Context, path string, into any) error {
+	c := &cl.cache
+	if c.ttl <= 0 {
+		return cl.get(ctx, path, into)
+	}
+
+	key := path + "?" + cl.query(ctx)
+
+	c.mu.Lock()
+	if e := c.entries[key]; e != nil {
+		age := time.Since(e.at)
+		switch {
+		case e.err != nil && age < c.negTTL:
+			c.mu.Unlock()
+			return e.err
+
+		case e.err == nil && age < c.ttl:
+			c.mu.Unlock()
+			return decodeCached(path, e.body, into)
+
+		case e.err == nil && age < c.ttl+c.staleTTL:
+			if !e.refreshing {
+				e.refreshing = true
+				go cl.cacheFetch(context.WithoutCancel(ctx), path, key)
+			}
+			c.mu.Unlock()
+			return decodeCached(path, e.body, into)
+		}
+	}
+	c.mu.Unlock()
+
+	body, err := cl.cacheFetch(ctx, path, key)
+	if err != nil {
+		return err
+	}
+	return decodeCached(path, body, into)
+}
+
+// cacheFetch requests path and saves the response into the cache under key.
+// If the request fails for any reason other than "not found", an existing
+// entry is kept as is.
+func (cl *Client) cacheFetch(ctx context.Context, path, key string) ([]byte, error) {
+	var body []byte
+	err := cl.get(ctx, path, &body)
+
+	c := &cl.cache
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries == nil {
+		c.entries = make(map[string]*cacheEntry)
+	}
+	switch {
+	case err == nil:
+		c.entries[key] = &cacheEntry{body: body, at: time.Now()}
+	case isNotFound(err) && c.negTTL > 0:
+		c.entries[key] = &cacheEntry{err: err, at: time.Now()}
+	case isNotFound(err):
+		delete(c.entries, key)
+	default:
+		if e := c.entries[key]; e != nil {
+			e.refreshing = false
+		}
+	}
+	return body, err
+}
+
+// cacheInvalidate drops everything cached for a subject as well as all
+// cached "not found" responses.
+func (cl *Client) cacheInvalidate(subject string) {
+	c := &cl.cache
+	if c.ttl <= 0 {
+		return
+	}
+	prefix := pathSubject(subject) + "/"
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, e := range c.entries {
+		if e.err != nil || strings.HasPrefix(key, prefix) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// query returns the encoded query parameters that a request with ctx uses.
+func (cl *Client) query(ctx context.Context) string {
+	req := &http.Request{URL: new(url.URL)}
+	cl.applyParams(ctx, req)
+	return req.URL.RawQuery
+}
+
+func decodeCached(path string, body []byte, into any) error {
+	if b, ok := into.(*[]byte); ok {
+		*b = body
+		return nil
+	}
+	if err := json.Unmarshal(body, into); err != nil {
+		return fmt.Errorf("unable to decode cached response body for %q: %w", path, err)
+	}
+	return nil
+}
+
+func isNotFound(err error) bool {
+	var re *ResponseError
+	return errors.As(err, &re) && re.StatusCode == http.StatusNotFound
+}
@@ -0,0 +1,74 @@
+package sr
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCache(t *testing.T) {
+	var (
+		reqs atomic.Int32
+		down atomic.Bool
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reqs.Add(1)
+		switch {
+		case down.Load():
+			w.WriteHeader(http.StatusInternalServerError)
+		case r.URL.Path == "/schemas/ids/1":
+			w.Write([]byte(`{"schema":"\"int\""}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error_code":40403,"message":"Schema not found"}`))
+		}
+	}))
+	defer srv.Close()
+
+	const ttl = 50 * time.Millisecond
+	cl, _ := NewClient(URLs(srv.URL), CacheTTL(ttl), CacheNegativeTTL(time.Hour), CacheStaleTTL(time.Hour))
+	ctx := context.Background()
+
+	expReqs := func(exp int32) {
+		t.Helper()
+		if got := reqs.Load(); got != exp {
+			t.Errorf("got %d requests, expected %d", got, exp)
+		}
+	}
+
+	for range 3 {
+		if s, err := cl.SchemaByID(ctx, 1); err != nil || s.Schema != `"int"` {
+			t.Fatalf("got %v, %v, expected int schema", s, err)
+		}
+		if _, err := cl.SchemaByID(ctx, 2); !isNotFound(err) {
+			t.Fatalf("got %v, expected not found", err)
+		}
+	}
+	expReqs(2)
+
+	// Parameters are part of the cache key.
+	cl.SchemaByID(WithParams(ctx, Format("resolved")), 1)
+	expReqs(3)
+
+	// After the TTL, the stale schema is returned even if the registry is
+	// down, and the refresh happens in the background.
+	down.Store(true)
+	time.Sleep(ttl)
+	if s, err := cl.SchemaByID(ctx, 1); err != nil || s.Schema != `"int"` {
+		t.Fatalf("got %v, %v, expected stale int schema", s, err)
+	}
+	for reqs.Load() != 4 {
+		time.Sleep(time.Millisecond)
+	}
+	if s, err := cl.SchemaByID(ctx, 1); err != nil || s.Schema != `"int"` {
+		t.Fatalf("got %v, %v, expected stale int schema after failed refresh", s, err)
+	}
+
+	cl.PurgeCache()
+	if _, err := cl.SchemaByID(ctx, 1); err == nil {
+		t.Error("got no error after purging the cache while the registry is down")
+	}
+}
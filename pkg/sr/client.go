@@ -6,10 +6,13 @@
 // encoding/decoding, you must register IDs and values to how to encode or
 // decode them.
 //
-// The client does not automatically cache schemas, instead, the Serde type is
+// The client does not cache schemas by default, instead, the Serde type is
 // used for the actual caching of IDs to how to encode/decode the IDs. The
 // Client type itself simply speaks http to your schema registry and returns
-// the results.
+// the results. Caching of schema lookups can be opted into with CacheTTL.
+//
+// For tests that should not depend on a real schema registry, the srfake
+// package provides an in-memory fake registry.
 //
 // To read more about the schema registry, see the following:
 //
@@ -64,6 +67,8 @@ type Client struct {
 		pass string
 	}
 	bearerToken string
+
+	cache cache
 }
 
 // NewClient returns a new schema registry client.
@@ -0,0 +1,160 @@
+package sr
+
+import (
+	"context"
+	"fmt"
+)
+
+// ReferencedSchema is a schema that is referenced by another schema, paired
+// with the name it is referenced by.
+type ReferencedSchema struct {
+	// Name is the name the reference is referred to by in the referencing
+	// schema. If a schema is referenced multiple times with different
+	// names, this is the first name encountered.
+	Name string
+
+	SubjectSchema
+}
+
+// ResolvedSchema is a schema and every schema it references, directly or
+// transitively.
+type ResolvedSchema struct {
+	SubjectSchema
+
+	// References contains every schema this schema depends on, ordered
+	// such that a schema comes after all schemas it references. This is
+	// the order that schemas must be registered in, and the order that
+	// most schema compilers require schemas to be loaded in.
+	References []ReferencedSchema
+}
+
+// ResolveSchemaByID returns the schema for a given schema ID and every
+// schema it references. The returned SubjectSchema only has the ID and
+// Schema set.
+//
+// This supports params [Subject], [Format], [FetchMaxID], and [ShowDeleted].
+func (cl *Client) ResolveSchemaByID(ctx context.Context, id int) (ResolvedSchema, error) {
+	s, err := cl.SchemaByID(ctx, id)
+	if err != nil {
+		return ResolvedSchema{}, err
+	}
+	refs, err := cl.ResolveReferences(ctx, s)
+	return ResolvedSchema{SubjectSchema{ID: id, Schema: s}, refs}, err
+}
+
+// ResolveSchemaByVersion returns the schema for a given subject and version
+// and every schema it references. You can use -1 as the version to return the
+// latest schema.
+//
+// This supports param [ShowDeleted].
+func (cl *Client) ResolveSchemaByVersion(ctx context.Context, subject string, version int) (ResolvedSchema, error) {
+	ss, err := cl.SchemaByVersion(ctx, subject, version)
+	if err != nil {
+		return ResolvedSchema{}, err
+	}
+	refs, err := cl.ResolveReferences(ctx, ss.Schema)
+	return ResolvedSchema{ss, refs}, err
+}
+
+// ResolveReferences fetches every schema that s references, directly or
+// transitively, ordered such that a schema comes after all schemas it
+// references. Each subject version is returned once, even if it is
+// referenced many times. This returns an error if the references contain a
+// cycle.
+//
+// This supports param [ShowDeleted].
+func (cl *Client) ResolveReferences(ctx context.Context, s Schema) ([]ReferencedSchema, error) {
+	type key struct {
+		subject string
+		version int
+	}
+	var (
+		refs     []ReferencedSchema
+		resolved = make(map[key]bool)
+		visiting = make(map[key]bool)
+		walk     func([]SchemaReference) error
+	)
+	walk = func(srefs []SchemaReference) error {
+		for _, sref := range srefs {
+			k := key{sref.Subject, sref.Version}
+			if resolved[k] {
+				continue
+			}
+			if visiting[k] {
+				return fmt.Errorf("schema reference cycle at subject %q version %d", sref.Subject, sref.Version)
+			}
+			visiting[k] = true
+			ss, err := cl.SchemaByVersion(ctx, sref.Subject, sref.Version)
+			if err != nil {
+				return fmt.Errorf("unable to resolve reference %q to subject %q version %d: %w", sref.Name, sref.Subject, sref.Version, err)
+			}
+			if err := walk(ss.References); err != nil {
+				return err
+			}
+			delete(visiting, k)
+			resolved[k] = true
+			refs = append(refs, ReferencedSchema{sref.Name, ss})
+		}
+		return nil
+	}
+	if err := walk(s.References); err != nil {
+		return nil, err
+	}
+	return refs, nil
+}
+
+// CreateSchemaWithReferences creates every referenced schema in its subject
+// and then creates s in the given subject, returning the created s.
+//
+// The references must be ordered such that a schema comes after all schemas
+// it references, which is the order returned from ResolveReferences. The
+// Name, Subject, and Schema of each reference are used; the Version and ID
+// are only used to match references. As each reference is created, any
+// SchemaReference in later schemas and in s to the same subject is updated to
+// the version that was just created, if the SchemaReference has the same
+// version as the ReferencedSchema or if it has no version (0 or -1). This
+// allows copying a ResolvedSchema to a different registry, or creating a new
+// set of schemas by referring to subjects only.
+//
+// Creating a schema that already exists returns the existing version, so
+// references that are already registered are left as is.
+//
+// This supports param [Normalize].
+func (cl *Client) CreateSchemaWithReferences(ctx context.Context, subject string, s Schema, refs ...ReferencedSchema) (SubjectSchema, error) {
+	type key struct {
+		subject string
+		version int
+	}
+	created := make(map[key]int)
+	update := func(srefs []SchemaReference) []SchemaReference {
+		if len(srefs) == 0 {
+			return srefs
+		}
+		updated := make([]SchemaReference, len(srefs))
+		for i, sref := range srefs {
+			if v, ok := created[key{sref.Subject, sref.Version}]; ok {
+				sref.Version = v
+			} else if v, ok := created[key{sref.Subject, -1}]; ok && sref.Version <= 0 {
+				sref.Version = v
+			}
+			updated[i] = sref
+		}
+		return updated
+	}
+
+	for _, ref := range refs {
+		rs := ref.Schema
+		rs.References = update(rs.References)
+		ss, err := cl.CreateSchema(ctx, ref.Subject, rs)
+		if err != nil {
+			return SubjectSchema{}, fmt.Errorf("unable to create reference %q in subject %q: %w", ref.Name, ref.Subject, err)
+		}
+		if ref.Version > 0 {
+			created[key{ref.Subject, ref.Version}] = ss.Version
+		}
+		created[key{ref.Subject, -1}] = ss.Version
+	}
+
+	s.References = update(s.References)
+	return cl.CreateSchema(ctx, subject, s)
+}
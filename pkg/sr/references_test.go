@@ -0,0 +1,69 @@
+package sr_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/twmb/franz-go/pkg/sr"
+	"github.com/twmb/franz-go/pkg/sr/srfake"
+)
+
+func TestReferences(t *testing.T) {
+	src, dst := srfake.New(), srfake.New()
+	defer src.Close()
+	defer dst.Close()
+	srcCl, _ := sr.NewClient(sr.URLs(src.URL()))
+	dstCl, _ := sr.NewClient(sr.URLs(dst.URL()))
+	ctx := context.Background()
+
+	// c <- b <- a, and a also references c directly.
+	a, err := srcCl.CreateSchemaWithReferences(ctx, "a",
+		sr.Schema{
+			Schema:     `{"type":"record","name":"a","fields":[{"name":"b","type":"b"},{"name":"c","type":"c"}]}`,
+			References: []sr.SchemaReference{{Name: "b", Subject: "b"}, {Name: "c", Subject: "c"}},
+		},
+		sr.ReferencedSchema{Name: "c", SubjectSchema: sr.SubjectSchema{Subject: "c", Schema: sr.Schema{
+			Schema: `{"type":"record","name":"c","fields":[]}`,
+		}}},
+		sr.ReferencedSchema{Name: "b", SubjectSchema: sr.SubjectSchema{Subject: "b", Schema: sr.Schema{
+			Schema:     `{"type":"record","name":"b","fields":[{"name":"c","type":"c"}]}`,
+			References: []sr.SchemaReference{{Name: "c", Subject: "c"}},
+		}}},
+	)
+	if err != nil {
+		t.Fatalf("unable to create schemas: %v", err)
+	}
+
+	resolved, err := srcCl.ResolveSchemaByID(ctx, a.ID)
+	if err != nil {
+		t.Fatalf("unable to resolve: %v", err)
+	}
+	var got []string
+	for _, ref := range resolved.References {
+		got = append(got, ref.Name)
+	}
+	if exp := []string{"c", "b"}; !reflect.DeepEqual(got, exp) {
+		t.Errorf("got resolved references %v, expected %v", got, exp)
+	}
+
+	// Bump c in the destination so that copied versions differ.
+	if _, err := dstCl.CreateSchema(ctx, "c", sr.Schema{Schema: `{"type":"record","name":"unrelated","fields":[]}`}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dstCl.CreateSchemaWithReferences(ctx, "a", resolved.Schema, resolved.References...); err != nil {
+		t.Fatalf("unable to copy schemas: %v", err)
+	}
+	copied, err := dstCl.ResolveSchemaByVersion(ctx, "a", -1)
+	if err != nil {
+		t.Fatalf("unable to resolve copy: %v", err)
+	}
+	for _, sref := range copied.References {
+		if sref.Subject == "c" && sref.Version != 2 {
+			t.Errorf("got copied c at version %d, expected 2", sref.Version)
+		}
+	}
+	if len(copied.References) != 2 {
+		t.Errorf("got %d copied references, expected 2", len(copied.References))
+	}
+}
@@ -77,7 +77,8 @@ func GenerateFn(fn func() any) EncodingOpt {
 // schema (namely, protobuf). The index into the schema to encode a
 // particular message is specified with `index`.
 //
-// NOTE: this option must be used for protobuf schemas.
+// NOTE: this option must be used for protobuf schemas. ProtobufIndex returns
+// the index of a message in a protobuf schema.
 //
 // For more information, see where `message-indexes` are described in:
 //
@@ -98,6 +99,7 @@ type tserde struct {
 	appendEncode func([]byte, any) ([]byte, error)
 	decode       func([]byte, any) error
 	gen          func() any
+	validate     func([]byte) error
 	typeof       reflect.Type
 
 	index         []int          // for encoding, an optional index we use
@@ -258,6 +260,7 @@ func (s *Serde) Register(id int, v any, opts ...EncodingOpt) {
 		appendEncode:  t.appendEncode,
 		decode:        t.decode,
 		gen:           t.gen,
+		validate:      t.validate,
 		typeof:        typeof,
 		index:         t.index,
 		subindex:      at.subindex,
@@ -304,14 +307,23 @@ func (s *Serde) AppendEncode(b []byte, v any) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
+	start := len(b)
 	if t.appendEncode != nil {
-		return t.appendEncode(b, v)
+		b, err = t.appendEncode(b, v)
+	} else {
+		var encoded []byte
+		encoded, err = t.encode(v)
+		b = append(b, encoded...)
 	}
-	encoded, err := t.encode(v)
 	if err != nil {
 		return nil, err
 	}
-	return append(b, encoded...), nil
+	if t.validate != nil {
+		if err := t.validate(b[start:]); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
 }
 
 // MustEncode returns the value of Encode, panicking on error. This is a
@@ -390,6 +402,11 @@ func (s *Serde) decodeFind(b []byte) ([]byte, tserde, error) {
 	if !t.exists || t.decode == nil {
 		return nil, tserde{}, ErrNotRegistered
 	}
+	if t.validate != nil {
+		if err := t.validate(b); err != nil {
+			return nil, tserde{}, err
+		}
+	}
 	return b, t, nil
 }
 
@@ -0,0 +1,159 @@
+package sr
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// JSON uses encoding/json to encode and decode values. This is the wire
+// format used by Confluent's JSON Schema serializers: the header is followed
+// by the JSON encoded value, with no message index.
+//
+// To validate values against a JSON Schema, pair this with ValidateFn.
+func JSON() EncodingOpt {
+	return encodingOpt{func(t *tserde) {
+		t.encode = json.Marshal
+		t.appendEncode = nil
+		t.decode = json.Unmarshal
+	}}
+}
+
+// ValidateFn validates a payload, without its header, after it is encoded
+// and before it is decoded. If validation fails, encoding or decoding returns
+// the validation error.
+//
+// This option is most useful with JSON Schema, where the schema is a set of
+// constraints on values rather than a description of how to encode them: you
+// can use any JSON Schema library to validate payloads against the registered
+// schema.
+func ValidateFn(fn func([]byte) error) EncodingOpt {
+	return encodingOpt{func(t *tserde) { t.validate = fn }}
+}
+
+// ProtobufIndex returns the message index of a message in a protobuf schema,
+// for use with the Index option. The message can be a fully qualified name,
+// or it can be a name relative to the schema's package, such as
+// "Outer.Inner" for a nested message.
+//
+// The message index is the path to the message through the message types
+// declared in the schema: the first element is the position of the top level
+// message in the file, the next is the position of a nested message within
+// that message, and so on. Groups count as nested messages, as they do in
+// protobuf descriptors. Enums, services, and extensions are not counted.
+//
+// This parses only as much of the schema as is needed to find messages; it
+// does not validate the schema.
+func ProtobufIndex(schema, message string) ([]int, error) {
+	message = strings.TrimPrefix(message, ".")
+
+	type scope struct {
+		name     string // full name without the package; empty for the file or non-message blocks
+		index    []int
+		message  bool
+		children int
+	}
+	var (
+		toks    = protoTokens(schema)
+		pkg     string
+		scopes  = []*scope{{message: true}} // the file can declare messages
+		pending *scope                      // a message whose opening brace is next
+	)
+	for i := 0; i < len(toks); i++ {
+		tok := toks[i]
+		cur := scopes[len(scopes)-1]
+		switch {
+		case tok == "package" && len(scopes) == 1 && i+1 < len(toks):
+			i++
+			pkg = toks[i]
+
+		case (tok == "message" || tok == "group" && len(scopes) > 1) && cur.message && pending == nil &&
+			i+1 < len(toks) && isProtoIdent(toks[i+1]):
+			i++
+			name := toks[i]
+			if cur.name != "" {
+				name = cur.name + "." + name
+			}
+			index := append(append([]int(nil), cur.index...), cur.children)
+			cur.children++
+			if name == message || pkg != "" && pkg+"."+name == message {
+				return index, nil
+			}
+			pending = &scope{name: name, index: index, message: true}
+
+		case tok == "{":
+			if pending != nil {
+				scopes = append(scopes, pending)
+				pending = nil
+			} else {
+				scopes = append(scopes, new(scope))
+			}
+
+		case tok == "}":
+			if len(scopes) == 1 {
+				return nil, fmt.Errorf("unbalanced braces in protobuf schema while looking for message %q", message)
+			}
+			scopes = scopes[:len(scopes)-1]
+		}
+	}
+	return nil, fmt.Errorf("message %q not found in protobuf schema", message)
+}
+
+// protoTokens splits a protobuf schema into identifiers and punctuation,
+// dropping whitespace, comments, and string literals.
+func protoTokens(schema string) []string {
+	var toks []string
+	for i := 0; i < len(schema); {
+		c := schema[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+
+		case strings.HasPrefix(schema[i:], "//"):
+			end := strings.IndexByte(schema[i:], '\n')
+			if end < 0 {
+				return toks
+			}
+			i += end + 1
+
+		case strings.HasPrefix(schema[i:], "/*"):
+			end := strings.Index(schema[i+2:], "*/")
+			if end < 0 {
+				return toks
+			}
+			i += 2 + end + 2
+
+		case c == '"' || c == '\'':
+			i++
+			for i < len(schema) && schema[i] != c {
+				if schema[i] == '\\' {
+					i++
+				}
+				i++
+			}
+			i++
+			toks = append(toks, `""`)
+
+		case isProtoIdentByte(c):
+			start := i
+			for i < len(schema) && isProtoIdentByte(schema[i]) {
+				i++
+			}
+			toks = append(toks, schema[start:i])
+
+		default:
+			toks = append(toks, schema[i:i+1])
+			i++
+		}
+	}
+	return toks
+}
+
+func isProtoIdentByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '.'
+}
+
+func isProtoIdent(tok string) bool {
+	c := tok[0]
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_'
+}
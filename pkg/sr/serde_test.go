@@ -3,6 +3,7 @@ package sr
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"io"
 	"reflect"
 	"testing"
@@ -264,3 +265,95 @@ func TestConfluentHeader(t *testing.T) {
 		t.Errorf("got %v != exp ErrBadHeader", err)
 	}
 }
+
+func TestProtobufIndex(t *testing.T) {
+	const schema = `
+syntax = "proto2";
+package com.example;
+
+// message Commented {}
+/* message AlsoCommented {} */
+message First {
+	option (my.opt) = { message: "x" };
+	optional string message = 1;
+	enum Kind { A = 0; }
+	message Nested {
+		message Deeper {}
+	}
+	oneof choice { string s = 2; }
+	optional group Grouped = 3 {
+		optional int32 g = 1;
+	}
+	message Second {}
+}
+
+service S { rpc Do(First) returns (First); }
+
+message Last {
+	map<string, First.Nested> m = 1;
+	message Only {}
+}
+`
+	for _, test := range []struct {
+		message string
+		exp     []int
+	}{
+		{"First", []int{0}},
+		{"com.example.First", []int{0}},
+		{".com.example.First.Nested", []int{0, 0}},
+		{"First.Nested.Deeper", []int{0, 0, 0}},
+		{"First.Grouped", []int{0, 1}},
+		{"First.Second", []int{0, 2}},
+		{"Last", []int{1}},
+		{"com.example.Last.Only", []int{1, 0}},
+		{"Commented", nil},
+		{"Kind", nil},
+		{"Nested", nil},
+	} {
+		index, err := ProtobufIndex(schema, test.message)
+		if test.exp == nil {
+			if err == nil {
+				t.Errorf("%s: got index %v, expected not found", test.message, index)
+			}
+			continue
+		}
+		if err != nil || !reflect.DeepEqual(index, test.exp) {
+			t.Errorf("%s: got %v, %v, expected %v", test.message, index, err, test.exp)
+		}
+	}
+}
+
+func TestJSONValidate(t *testing.T) {
+	type value struct {
+		A int `json:"a"`
+	}
+	errInvalid := errors.New("invalid")
+	validate := func(b []byte) error {
+		if bytes.Contains(b, []byte(`"a":0`)) {
+			return errInvalid
+		}
+		return nil
+	}
+
+	var serde Serde
+	serde.Register(1, value{}, JSON(), ValidateFn(validate))
+
+	b, err := serde.Encode(value{A: 3})
+	if err != nil {
+		t.Fatalf("unable to encode: %v", err)
+	}
+	if exp := append([]byte{0, 0, 0, 0, 1}, `{"a":3}`...); !bytes.Equal(b, exp) {
+		t.Errorf("got %q != exp %q", b, exp)
+	}
+	v, err := serde.DecodeNew(b)
+	if err != nil || !reflect.DeepEqual(v, &value{A: 3}) {
+		t.Errorf("got %v, %v, expected &{3}", v, err)
+	}
+
+	if _, err := serde.Encode(value{}); err != errInvalid {
+		t.Errorf("got encode err %v != exp invalid", err)
+	}
+	if err := serde.Decode(append([]byte{0, 0, 0, 0, 1}, `{"a":0}`...), new(value)); err != errInvalid {
+		t.Errorf("got decode err %v != exp invalid", err)
+	}
+}
@@ -0,0 +1,82 @@
+package srfake
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/twmb/franz-go/pkg/sr"
+)
+
+type avroRecord struct {
+	Type   string `json:"type"`
+	Fields []struct {
+		Name    string          `json:"name"`
+		Default json.RawMessage `json:"default"`
+	} `json:"fields"`
+}
+
+func defaultChecker(reader, writer sr.Schema) []string {
+	if reader.Type != writer.Type {
+		return []string{fmt.Sprintf("schema type changed from %s to %s", writer.Type, reader.Type)}
+	}
+	if reader.Type != sr.TypeAvro {
+		return nil
+	}
+	var rr, wr avroRecord
+	if json.Unmarshal([]byte(reader.Schema), &rr) != nil ||
+		json.Unmarshal([]byte(writer.Schema), &wr) != nil ||
+		rr.Type != "record" || wr.Type != "record" {
+		return nil
+	}
+	written := make(map[string]bool, len(wr.Fields))
+	for _, f := range wr.Fields {
+		written[f.Name] = true
+	}
+	var msgs []string
+	for _, f := range rr.Fields {
+		if !written[f.Name] && f.Default == nil {
+			msgs = append(msgs, fmt.Sprintf("reader field %q is missing from the writer schema and has no default", f.Name))
+		}
+	}
+	return msgs
+}
+
+// incompatibilities returns why a new schema is incompatible with the live
+// versions of a subject at the given level.
+func (r *Registry) incompatibilities(level sr.CompatibilityLevel, s sr.Schema, against []*version) []string {
+	var backward, forward, transitive bool
+	switch level {
+	case sr.CompatBackward:
+		backward = true
+	case sr.CompatBackwardTransitive:
+		backward, transitive = true, true
+	case sr.CompatForward:
+		forward = true
+	case sr.CompatForwardTransitive:
+		forward, transitive = true, true
+	case sr.CompatFull:
+		backward, forward = true, true
+	case sr.CompatFullTransitive:
+		backward, forward, transitive = true, true, true
+	default:
+		return nil
+	}
+	if !transitive && len(against) > 1 {
+		against = against[len(against)-1:]
+	}
+	var msgs []string
+	for _, v := range against {
+		existing := r.ids[v.id]
+		if backward {
+			for _, msg := range r.cfg.checker(s, existing) {
+				msgs = append(msgs, fmt.Sprintf("version %d: %s", v.version, msg))
+			}
+		}
+		if forward {
+			for _, msg := range r.cfg.checker(existing, s) {
+				msgs = append(msgs, fmt.Sprintf("version %d: %s", v.version, msg))
+			}
+		}
+	}
+	return msgs
+}
@@ -0,0 +1,661 @@
+package srfake
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/twmb/franz-go/pkg/sr"
+)
+
+// Error codes returned from the schema registry. The HTTP status is the error
+// code's first three digits.
+const (
+	errSubjectNotFound       = 40401
+	errVersionNotFound       = 40402
+	errSchemaNotFound        = 40403
+	errSubjectSoftDeleted    = 40404
+	errSubjectNotSoftDeleted = 40405
+	errVersionSoftDeleted    = 40406
+	errVersionNotSoftDeleted = 40407
+	errSubjectCompatNotFound = 40408
+	errSubjectModeNotFound   = 40409
+	errIncompatibleSchema    = 409
+	errInvalidSchema         = 42201
+	errInvalidVersion        = 42202
+	errInvalidCompatLevel    = 42203
+	errInvalidMode           = 42204
+	errOperationNotPermitted = 42205
+	errReferenceExists       = 42206
+	errInvalidRequest        = 400
+)
+
+const errSubjectCompatNotFoundText = "Subject '%s' does not have subject-level compatibility configured"
+
+type srError struct {
+	code int
+	msg  string
+}
+
+func newErr(code int, format string, args ...any) *srError {
+	return &srError{code, fmt.Sprintf(format, args...)}
+}
+
+// rawText is a response that is written as is rather than as JSON.
+type rawText string
+
+type handler func(req *http.Request) (any, *srError)
+
+func (r *Registry) routes() *http.ServeMux {
+	mux := http.NewServeMux()
+	for _, route := range []struct {
+		pattern string
+		h       handler
+	}{
+		{"GET /schemas/types", r.getTypes},
+		{"GET /schemas", r.getSchemas},
+		{"GET /schemas/ids/{id}", r.getSchemaByID},
+		{"GET /schemas/ids/{id}/schema", r.getSchemaTextByID},
+		{"GET /schemas/ids/{id}/subjects", r.getSubjectsByID},
+		{"GET /schemas/ids/{id}/versions", r.getVersionsByID},
+		{"GET /subjects", r.getSubjects},
+		{"POST /subjects/{subject}", r.lookupSchema},
+		{"DELETE /subjects/{subject}", r.deleteSubject},
+		{"GET /subjects/{subject}/versions", r.getSubjectVersions},
+		{"POST /subjects/{subject}/versions", r.registerSchema},
+		{"GET /subjects/{subject}/versions/{version}", r.getSubjectVersion},
+		{"GET /subjects/{subject}/versions/{version}/schema", r.getSubjectVersionText},
+		{"GET /subjects/{subject}/versions/{version}/referencedby", r.getReferencedBy},
+		{"DELETE /subjects/{subject}/versions/{version}", r.deleteSubjectVersion},
+		{"GET /config", r.getConfig},
+		{"GET /config/{subject}", r.getConfig},
+		{"PUT /config", r.putConfig},
+		{"PUT /config/{subject}", r.putConfig},
+		{"DELETE /config", r.deleteConfig},
+		{"DELETE /config/{subject}", r.deleteConfig},
+		{"POST /compatibility/subjects/{subject}/versions", r.checkCompatibility},
+		{"POST /compatibility/subjects/{subject}/versions/{version}", r.checkCompatibility},
+		{"GET /mode", r.getMode},
+		{"GET /mode/{subject}", r.getMode},
+		{"PUT /mode", r.putMode},
+		{"PUT /mode/{subject}", r.putMode},
+		{"DELETE /mode", r.deleteMode},
+		{"DELETE /mode/{subject}", r.deleteMode},
+	} {
+		mux.HandleFunc(route.pattern, r.serve(route.h))
+	}
+	return mux
+}
+
+func (r *Registry) serve(h handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		r.mu.Lock()
+		resp, err := h(req)
+		r.mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/vnd.schemaregistry.v1+json")
+		if err != nil {
+			status := err.code
+			for status >= 1000 {
+				status /= 10
+			}
+			w.WriteHeader(status)
+			json.NewEncoder(w).Encode(struct {
+				ErrorCode int    `json:"error_code"`
+				Message   string `json:"message"`
+			}{err.code, err.msg})
+			return
+		}
+		if text, ok := resp.(rawText); ok {
+			w.Write([]byte(text))
+			return
+		}
+		json.NewEncoder(w).Encode(resp)
+	}
+}
+
+func boolParam(req *http.Request, name string) bool {
+	return req.URL.Query().Get(name) == "true"
+}
+
+func decodeBody(req *http.Request, v any) *srError {
+	if err := json.NewDecoder(req.Body).Decode(v); err != nil {
+		return newErr(errInvalidRequest, "unable to decode request body: %v", err)
+	}
+	return nil
+}
+
+func (r *Registry) subjectSchema(name string, v *version) sr.SubjectSchema {
+	return sr.SubjectSchema{
+		Subject: name,
+		Version: v.version,
+		ID:      v.id,
+		Schema:  r.ids[v.id],
+	}
+}
+
+func sortedSubjects[V any](m map[string]V) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// lookupSubject returns the subject, which must have versions that are
+// visible given whether deleted versions are shown.
+func (r *Registry) lookupSubject(name string, showDeleted bool) (*subject, *srError) {
+	s := r.subjects[name]
+	if s == nil || len(s.versions) == 0 || s.softDeleted() && !showDeleted {
+		return nil, newErr(errSubjectNotFound, "Subject '%s' not found.", name)
+	}
+	return s, nil
+}
+
+// lookupVersion returns the subject and version for the request's subject and
+// version path values. The version can be "latest" or -1 for the latest
+// version.
+func (r *Registry) lookupVersion(req *http.Request, showDeleted bool) (string, *subject, *version, *srError) {
+	name := req.PathValue("subject")
+	s, err := r.lookupSubject(name, showDeleted)
+	if err != nil {
+		return name, nil, nil, err
+	}
+	raw := req.PathValue("version")
+	if raw == "latest" || raw == "-1" {
+		vs := s.live()
+		if showDeleted {
+			vs = s.versions
+		}
+		if len(vs) == 0 {
+			return name, nil, nil, newErr(errVersionNotFound, "Version latest not found.")
+		}
+		return name, s, vs[len(vs)-1], nil
+	}
+	n, perr := strconv.Atoi(raw)
+	if perr != nil || n < 1 {
+		return name, nil, nil, newErr(errInvalidVersion, "The specified version '%s' is not a valid version id.", raw)
+	}
+	v := s.find(n)
+	if v == nil || v.deleted && !showDeleted {
+		return name, nil, nil, newErr(errVersionNotFound, "Version %d not found.", n)
+	}
+	return name, s, v, nil
+}
+
+func (r *Registry) lookupID(req *http.Request) (int, sr.Schema, *srError) {
+	id, err := strconv.Atoi(req.PathValue("id"))
+	if err != nil {
+		return 0, sr.Schema{}, newErr(errInvalidRequest, "invalid schema id %q", req.PathValue("id"))
+	}
+	s, ok := r.ids[id]
+	if !ok {
+		return 0, sr.Schema{}, newErr(errSchemaNotFound, "Schema %d not found", id)
+	}
+	return id, s, nil
+}
+
+// usages returns every subject version using the schema ID, filtered by the
+// subject and deleted query parameters.
+func (r *Registry) usages(req *http.Request, id int) []sr.SubjectVersion {
+	var (
+		showDeleted = boolParam(req, "deleted")
+		only        = req.URL.Query().Get("subject")
+		svs         = []sr.SubjectVersion{}
+	)
+	for _, name := range sortedSubjects(r.subjects) {
+		if only != "" && name != only {
+			continue
+		}
+		for _, v := range r.subjects[name].versions {
+			if v.id == id && (!v.deleted || showDeleted) {
+				svs = append(svs, sr.SubjectVersion{Subject: name, Version: v.version})
+			}
+		}
+	}
+	return svs
+}
+
+func (*Registry) getTypes(*http.Request) (any, *srError) {
+	return []string{"AVRO", "JSON", "PROTOBUF"}, nil
+}
+
+func (r *Registry) getSchemas(req *http.Request) (any, *srError) {
+	var (
+		showDeleted = boolParam(req, "deleted")
+		latestOnly  = boolParam(req, "latestOnly")
+		prefix      = req.URL.Query().Get("subjectPrefix")
+		ss          = []sr.SubjectSchema{}
+	)
+	for _, name := range sortedSubjects(r.subjects) {
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		vs := r.subjects[name].live()
+		if showDeleted {
+			vs = r.subjects[name].versions
+		}
+		if latestOnly && len(vs) > 0 {
+			vs = vs[len(vs)-1:]
+		}
+		for _, v := range vs {
+			ss = append(ss, r.subjectSchema(name, v))
+		}
+	}
+	return ss, nil
+}
+
+func (r *Registry) getSchemaByID(req *http.Request) (any, *srError) {
+	_, s, err := r.lookupID(req)
+	return s, err
+}
+
+func (r *Registry) getSchemaTextByID(req *http.Request) (any, *srError) {
+	_, s, err := r.lookupID(req)
+	return rawText(s.Schema), err
+}
+
+func (r *Registry) getSubjectsByID(req *http.Request) (any, *srError) {
+	id, _, err := r.lookupID(req)
+	if err != nil {
+		return nil, err
+	}
+	subjects := []string{}
+	for _, sv := range r.usages(req, id) {
+		if len(subjects) == 0 || subjects[len(subjects)-1] != sv.Subject {
+			subjects = append(subjects, sv.Subject)
+		}
+	}
+	return subjects, nil
+}
+
+func (r *Registry) getVersionsByID(req *http.Request) (any, *srError) {
+	id, _, err := r.lookupID(req)
+	if err != nil {
+		return nil, err
+	}
+	return r.usages(req, id), nil
+}
+
+func (r *Registry) getSubjects(req *http.Request) (any, *srError) {
+	var (
+		showDeleted = boolParam(req, "deleted")
+		deletedOnly = boolParam(req, "deletedOnly")
+		prefix      = req.URL.Query().Get("subjectPrefix")
+		subjects    = []string{}
+	)
+	for _, name := range sortedSubjects(r.subjects) {
+		s := r.subjects[name]
+		if len(s.versions) == 0 || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		deleted := s.softDeleted()
+		if deletedOnly && !deleted || !deletedOnly && deleted && !showDeleted {
+			continue
+		}
+		subjects = append(subjects, name)
+	}
+	return subjects, nil
+}
+
+func (r *Registry) getSubjectVersions(req *http.Request) (any, *srError) {
+	showDeleted := boolParam(req, "deleted")
+	s, err := r.lookupSubject(req.PathValue("subject"), showDeleted)
+	if err != nil {
+		return nil, err
+	}
+	versions := []int{}
+	for _, v := range s.versions {
+		if !v.deleted && !boolParam(req, "deletedOnly") || v.deleted && (showDeleted || boolParam(req, "deletedOnly")) {
+			versions = append(versions, v.version)
+		}
+	}
+	return versions, nil
+}
+
+func (r *Registry) getSubjectVersion(req *http.Request) (any, *srError) {
+	name, _, v, err := r.lookupVersion(req, boolParam(req, "deleted"))
+	if err != nil {
+		return nil, err
+	}
+	return r.subjectSchema(name, v), nil
+}
+
+func (r *Registry) getSubjectVersionText(req *http.Request) (any, *srError) {
+	_, _, v, err := r.lookupVersion(req, boolParam(req, "deleted"))
+	if err != nil {
+		return nil, err
+	}
+	return rawText(r.ids[v.id].Schema), nil
+}
+
+func (r *Registry) getReferencedBy(req *http.Request) (any, *srError) {
+	name, _, v, err := r.lookupVersion(req, boolParam(req, "deleted"))
+	if err != nil {
+		return nil, err
+	}
+	ids := r.referencedBy(name, v.version)
+	sort.Ints(ids)
+	if ids == nil {
+		ids = []int{}
+	}
+	return ids, nil
+}
+
+// validateSchema checks that JSON based schemas are valid JSON and that all
+// references exist.
+func (r *Registry) validateSchema(s sr.Schema) *srError {
+	if s.Type != sr.TypeProtobuf && !json.Valid([]byte(s.Schema)) {
+		return newErr(errInvalidSchema, "Invalid schema: schema is not valid JSON")
+	}
+	for _, ref := range s.References {
+		rs := r.subjects[ref.Subject]
+		if rs == nil {
+			return newErr(errInvalidSchema, "Invalid schema: reference %q subject %q not found", ref.Name, ref.Subject)
+		}
+		if v := rs.find(ref.Version); v == nil || v.deleted {
+			return newErr(errInvalidSchema, "Invalid schema: reference %q subject %q version %d not found", ref.Name, ref.Subject, ref.Version)
+		}
+	}
+	return nil
+}
+
+func (r *Registry) lookupSchema(req *http.Request) (any, *srError) {
+	var s sr.Schema
+	if err := decodeBody(req, &s); err != nil {
+		return nil, err
+	}
+	name := req.PathValue("subject")
+	showDeleted := boolParam(req, "deleted")
+	subj, err := r.lookupSubject(name, showDeleted)
+	if err != nil {
+		return nil, err
+	}
+	key := schemaKey(s)
+	for _, v := range subj.versions {
+		if (!v.deleted || showDeleted) && schemaKey(r.ids[v.id]) == key {
+			return r.subjectSchema(name, v), nil
+		}
+	}
+	return nil, newErr(errSchemaNotFound, "Schema not found")
+}
+
+func (r *Registry) registerSchema(req *http.Request) (any, *srError) {
+	var s sr.Schema
+	if err := decodeBody(req, &s); err != nil {
+		return nil, err
+	}
+	name := req.PathValue("subject")
+	subj := r.subjects[name]
+	if r.effectiveMode(subj) == sr.ModeReadOnly {
+		return nil, newErr(errOperationNotPermitted, "Subject %s is in read-only mode", name)
+	}
+	if err := r.validateSchema(s); err != nil {
+		return nil, err
+	}
+
+	type idResp struct {
+		ID int `json:"id"`
+	}
+	key := schemaKey(s)
+	if subj != nil {
+		for _, v := range subj.live() {
+			if schemaKey(r.ids[v.id]) == key {
+				return idResp{v.id}, nil
+			}
+		}
+		if msgs := r.incompatibilities(r.effectiveCompat(subj), s, subj.live()); len(msgs) > 0 {
+			return nil, newErr(errIncompatibleSchema, "Schema being registered is incompatible with an earlier schema for subject %q, details: %s", name, strings.Join(msgs, "; "))
+		}
+	} else {
+		subj = new(subject)
+		r.subjects[name] = subj
+	}
+
+	id, ok := r.idsByKey[key]
+	if !ok {
+		id = r.nextID
+		r.nextID++
+		r.ids[id] = s
+		r.idsByKey[key] = id
+	}
+	next := 1
+	if n := len(subj.versions); n > 0 {
+		next = subj.versions[n-1].version + 1
+	}
+	subj.versions = append(subj.versions, &version{version: next, id: id})
+	return idResp{id}, nil
+}
+
+func (r *Registry) deleteSubject(req *http.Request) (any, *srError) {
+	name := req.PathValue("subject")
+	subj, err := r.lookupSubject(name, true)
+	if err != nil {
+		return nil, err
+	}
+	if r.effectiveMode(subj) == sr.ModeReadOnly {
+		return nil, newErr(errOperationNotPermitted, "Subject %s is in read-only mode", name)
+	}
+	permanent := boolParam(req, "permanent")
+	switch {
+	case permanent && !subj.softDeleted():
+		return nil, newErr(errSubjectNotSoftDeleted, "Subject '%s' was not deleted first before being permanently deleted", name)
+	case !permanent && subj.softDeleted():
+		return nil, newErr(errSubjectSoftDeleted, "Subject '%s' was soft deleted. Set permanent=true to delete permanently", name)
+	}
+
+	versions := []int{}
+	for _, v := range subj.versions {
+		if !permanent {
+			if ids := r.referencedBy(name, v.version); len(ids) > 0 {
+				return nil, newErr(errReferenceExists, "One or more references exist to the schema {subject=%s,version=%d}", name, v.version)
+			}
+		}
+		versions = append(versions, v.version)
+	}
+	if permanent {
+		subj.versions = nil
+		r.dropEmptySubject(name)
+		r.dropUnusedIDs()
+	} else {
+		for _, v := range subj.versions {
+			v.deleted = true
+		}
+	}
+	return versions, nil
+}
+
+func (r *Registry) deleteSubjectVersion(req *http.Request) (any, *srError) {
+	permanent := boolParam(req, "permanent")
+	name, subj, v, err := r.lookupVersion(req, permanent)
+	if err != nil {
+		return nil, err
+	}
+	if r.effectiveMode(subj) == sr.ModeReadOnly {
+		return nil, newErr(errOperationNotPermitted, "Subject %s is in read-only mode", name)
+	}
+	switch {
+	case permanent && !v.deleted:
+		return nil, newErr(errVersionNotSoftDeleted, "Subject '%s' Version %d was not deleted first before being permanently deleted", name, v.version)
+	case !permanent && v.deleted:
+		return nil, newErr(errVersionSoftDeleted, "Subject '%s' Version %d was soft deleted. Set permanent=true to delete permanently", name, v.version)
+	case !permanent && len(r.referencedBy(name, v.version)) > 0:
+		return nil, newErr(errReferenceExists, "One or more references exist to the schema {subject=%s,version=%d}", name, v.version)
+	}
+	if permanent {
+		for i, sv := range subj.versions {
+			if sv == v {
+				subj.versions = append(subj.versions[:i], subj.versions[i+1:]...)
+				break
+			}
+		}
+		r.dropUnusedIDs()
+	} else {
+		v.deleted = true
+	}
+	return v.version, nil
+}
+
+func (r *Registry) checkCompatibility(req *http.Request) (any, *srError) {
+	var s sr.Schema
+	if err := decodeBody(req, &s); err != nil {
+		return nil, err
+	}
+	if err := r.validateSchema(s); err != nil {
+		return nil, err
+	}
+	name := req.PathValue("subject")
+	subj := r.subjects[name]
+
+	var against []*version
+	level := r.effectiveCompat(subj)
+	if req.PathValue("version") != "" {
+		_, _, v, err := r.lookupVersion(req, false)
+		if err != nil {
+			return nil, err
+		}
+		against = []*version{v}
+	} else if subj != nil {
+		against = subj.live()
+		switch level {
+		case sr.CompatBackward:
+			level = sr.CompatBackwardTransitive
+		case sr.CompatForward:
+			level = sr.CompatForwardTransitive
+		case sr.CompatFull:
+			level = sr.CompatFullTransitive
+		}
+	}
+	msgs := r.incompatibilities(level, s, against)
+	if msgs == nil {
+		msgs = []string{}
+	}
+	return sr.CheckCompatibilityResult{Is: len(msgs) == 0, Messages: msgs}, nil
+}
+
+func (r *Registry) getConfig(req *http.Request) (any, *srError) {
+	type resp struct {
+		Level sr.CompatibilityLevel `json:"compatibilityLevel"`
+	}
+	name := req.PathValue("subject")
+	if name == "" {
+		return resp{r.compat}, nil
+	}
+	if subj := r.subjects[name]; subj != nil && subj.compat != nil {
+		return resp{*subj.compat}, nil
+	}
+	if boolParam(req, "defaultToGlobal") {
+		return resp{r.compat}, nil
+	}
+	return nil, newErr(errSubjectCompatNotFound, errSubjectCompatNotFoundText, name)
+}
+
+func (r *Registry) putConfig(req *http.Request) (any, *srError) {
+	var c sr.SetCompatibility
+	if err := json.NewDecoder(req.Body).Decode(&c); err != nil || c.Level.String() == "" {
+		return nil, newErr(errInvalidCompatLevel, "Invalid compatibility level")
+	}
+	name := req.PathValue("subject")
+	if name == "" {
+		r.compat = c.Level
+	} else {
+		subj := r.subjects[name]
+		if subj == nil {
+			subj = new(subject)
+			r.subjects[name] = subj
+		}
+		level := c.Level
+		subj.compat = &level
+	}
+	return sr.SetCompatibility{Level: c.Level}, nil
+}
+
+func (r *Registry) deleteConfig(req *http.Request) (any, *srError) {
+	name := req.PathValue("subject")
+	if name == "" {
+		prior := r.compat
+		r.compat = r.cfg.compat
+		return sr.SetCompatibility{Level: prior}, nil
+	}
+	subj := r.subjects[name]
+	if subj == nil || subj.compat == nil {
+		return nil, newErr(errSubjectCompatNotFound, errSubjectCompatNotFoundText, name)
+	}
+	prior := *subj.compat
+	subj.compat = nil
+	r.dropEmptySubject(name)
+	return sr.SetCompatibility{Level: prior}, nil
+}
+
+type modeResp struct {
+	Mode sr.Mode `json:"mode"`
+}
+
+func (r *Registry) getMode(req *http.Request) (any, *srError) {
+	name := req.PathValue("subject")
+	if name == "" {
+		return modeResp{r.mode}, nil
+	}
+	if subj := r.subjects[name]; subj != nil && subj.mode != nil {
+		return modeResp{*subj.mode}, nil
+	}
+	if boolParam(req, "defaultToGlobal") {
+		return modeResp{r.mode}, nil
+	}
+	return nil, newErr(errSubjectModeNotFound, "Subject '%s' does not have subject-level mode configured", name)
+}
+
+func (r *Registry) putMode(req *http.Request) (any, *srError) {
+	var m modeResp
+	if err := json.NewDecoder(req.Body).Decode(&m); err != nil {
+		return nil, newErr(errInvalidMode, "Invalid mode")
+	}
+	name := req.PathValue("subject")
+	if m.Mode == sr.ModeImport && !boolParam(req, "force") {
+		subj := r.subjects[name]
+		if name == "" && len(r.ids) > 0 || name != "" && subj != nil && len(subj.versions) > 0 {
+			return nil, newErr(errOperationNotPermitted, "Cannot import since found existing subjects")
+		}
+	}
+	if name == "" {
+		r.mode = m.Mode
+	} else {
+		subj := r.subjects[name]
+		if subj == nil {
+			subj = new(subject)
+			r.subjects[name] = subj
+		}
+		mode := m.Mode
+		subj.mode = &mode
+	}
+	return m, nil
+}
+
+func (r *Registry) deleteMode(req *http.Request) (any, *srError) {
+	name := req.PathValue("subject")
+	if name == "" {
+		prior := r.mode
+		r.mode = sr.ModeReadWrite
+		return modeResp{prior}, nil
+	}
+	subj := r.subjects[name]
+	if subj == nil || subj.mode == nil {
+		return nil, newErr(errSubjectModeNotFound, "Subject '%s' does not have subject-level mode configured", name)
+	}
+	prior := *subj.mode
+	subj.mode = nil
+	r.dropEmptySubject(name)
+	return modeResp{prior}, nil
+}
+
+// dropEmptySubject removes a subject that has no versions and no
+// configuration.
+func (r *Registry) dropEmptySubject(name string) {
+	if subj := r.subjects[name]; subj != nil && len(subj.versions) == 0 && subj.compat == nil && subj.mode == nil {
+		delete(r.subjects, name)
+	}
+}
@@ -0,0 +1,215 @@
+// Package srfake provides a fake, in-memory schema registry for testing.
+//
+// The fake registry serves the subset of the schema registry HTTP API that the
+// sr package's Client uses: subjects, versions, schema IDs, references,
+// compatibility configuration and checks, and modes. Everything is kept in
+// memory and is lost when the registry is closed.
+//
+// Compatibility checks are intentionally simple; see CompatibilityChecker for
+// what is checked by default.
+package srfake
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	"github.com/twmb/franz-go/pkg/sr"
+)
+
+// Opt is an option to configure a Registry.
+type Opt interface {
+	apply(*cfg)
+}
+
+type opt struct{ fn func(*cfg) }
+
+func (opt opt) apply(cfg *cfg) { opt.fn(cfg) }
+
+type cfg struct {
+	compat  sr.CompatibilityLevel
+	checker CompatibilityChecker
+}
+
+// GlobalCompatibility sets the initial global compatibility level, overriding
+// the default of BACKWARD.
+func GlobalCompatibility(level sr.CompatibilityLevel) Opt {
+	return opt{func(cfg *cfg) { cfg.compat = level }}
+}
+
+// CompatibilityChecker returns reasons that a reader schema cannot read data
+// written with a writer schema, or nothing if the reader is compatible.
+//
+// A BACKWARD check calls the checker with the new schema as the reader and
+// existing schemas as writers, a FORWARD check calls the checker with
+// existing schemas as readers and the new schema as the writer, and a FULL
+// check does both.
+//
+// The default checker only finds schema type changes and, for Avro records,
+// reader fields that are missing from the writer and that have no default.
+type CompatibilityChecker func(reader, writer sr.Schema) []string
+
+// CheckCompatibilityWith sets the function used to check the compatibility of
+// schemas, overriding the default checker.
+func CheckCompatibilityWith(fn CompatibilityChecker) Opt {
+	return opt{func(cfg *cfg) { cfg.checker = fn }}
+}
+
+// Registry is a fake schema registry. A Registry serves HTTP from a local
+// server that is started in New, and it can also be used as an http.Handler
+// directly.
+type Registry struct {
+	cfg cfg
+	srv *httptest.Server
+	mux *http.ServeMux
+
+	mu       sync.Mutex
+	nextID   int
+	ids      map[int]sr.Schema
+	idsByKey map[string]int
+	subjects map[string]*subject
+	compat   sr.CompatibilityLevel
+	mode     sr.Mode
+}
+
+type subject struct {
+	versions []*version // sorted by version
+	compat   *sr.CompatibilityLevel
+	mode     *sr.Mode
+}
+
+type version struct {
+	version int
+	id      int
+	deleted bool
+}
+
+// New returns a new fake registry that is serving on a local address. Use
+// URL to configure an sr.Client, and Close to shut down the registry.
+func New(opts ...Opt) *Registry {
+	r := &Registry{
+		cfg: cfg{
+			compat:  sr.CompatBackward,
+			checker: defaultChecker,
+		},
+		nextID:   1,
+		ids:      make(map[int]sr.Schema),
+		idsByKey: make(map[string]int),
+		subjects: make(map[string]*subject),
+		mode:     sr.ModeReadWrite,
+	}
+	for _, opt := range opts {
+		opt.apply(&r.cfg)
+	}
+	r.compat = r.cfg.compat
+	r.mux = r.routes()
+	r.srv = httptest.NewServer(r)
+	return r
+}
+
+// URL returns the URL the registry is serving on.
+func (r *Registry) URL() string {
+	return r.srv.URL
+}
+
+// Close shuts down the registry's server.
+func (r *Registry) Close() {
+	r.srv.Close()
+}
+
+// ServeHTTP implements http.Handler.
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mux.ServeHTTP(w, req)
+}
+
+// schemaKey returns the key to deduplicate schemas by. Schema text that is
+// valid JSON is compacted so that whitespace differences do not matter.
+func schemaKey(s sr.Schema) string {
+	text := []byte(s.Schema)
+	var compacted bytes.Buffer
+	if json.Compact(&compacted, text) == nil {
+		text = compacted.Bytes()
+	}
+	refs, _ := json.Marshal(s.References)
+	return s.Type.String() + "\x00" + string(text) + "\x00" + string(refs)
+}
+
+// live returns the non-deleted versions of the subject.
+func (s *subject) live() []*version {
+	var vs []*version
+	for _, v := range s.versions {
+		if !v.deleted {
+			vs = append(vs, v)
+		}
+	}
+	return vs
+}
+
+// softDeleted returns whether every version in the subject is soft deleted.
+func (s *subject) softDeleted() bool {
+	return len(s.versions) > 0 && len(s.live()) == 0
+}
+
+func (s *subject) find(v int) *version {
+	for _, sv := range s.versions {
+		if sv.version == v {
+			return sv
+		}
+	}
+	return nil
+}
+
+func (r *Registry) effectiveCompat(s *subject) sr.CompatibilityLevel {
+	if s != nil && s.compat != nil {
+		return *s.compat
+	}
+	return r.compat
+}
+
+func (r *Registry) effectiveMode(s *subject) sr.Mode {
+	if s != nil && s.mode != nil {
+		return *s.mode
+	}
+	return r.mode
+}
+
+// referencedBy returns the IDs of live schemas that reference the subject
+// version.
+func (r *Registry) referencedBy(subject string, version int) []int {
+	var ids []int
+	seen := make(map[int]bool)
+	for _, s := range r.subjects {
+		for _, v := range s.live() {
+			if seen[v.id] {
+				continue
+			}
+			for _, ref := range r.ids[v.id].References {
+				if ref.Subject == subject && ref.Version == version {
+					ids = append(ids, v.id)
+					seen[v.id] = true
+					break
+				}
+			}
+		}
+	}
+	return ids
+}
+
+// dropUnusedIDs removes schema IDs that are no longer used by any version,
+// after a hard delete.
+func (r *Registry) dropUnusedIDs() {
+	used := make(map[int]bool)
+	for _, s := range r.subjects {
+		for _, v := range s.versions {
+			used[v.id] = true
+		}
+	}
+	for id, s := range r.ids {
+		if !used[id] {
+			delete(r.ids, id)
+			delete(r.idsByKey, schemaKey(s))
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package srfake
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/twmb/franz-go/pkg/sr"
+)
+
+const (
+	avroV1 = `{"type":"record","name":"r","fields":[{"name":"a","type":"int"}]}`
+	avroV2 = `{"type":"record","name":"r","fields":[{"name":"a","type":"int"},{"name":"b","type":"int","default":0}]}`
+	avroV3 = `{"type":"record","name":"r","fields":[{"name":"a","type":"int"},{"name":"c","type":"int"}]}`
+)
+
+func TestRegistry(t *testing.T) {
+	reg := New()
+	defer reg.Close()
+	cl, err := sr.NewClient(sr.URLs(reg.URL()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	expCode := func(err error, code int) {
+		t.Helper()
+		var re *sr.ResponseError
+		if !errors.As(err, &re) || re.ErrorCode != code {
+			t.Fatalf("got err %v, expected error code %d", err, code)
+		}
+	}
+
+	s1, err := cl.CreateSchema(ctx, "foo-value", sr.Schema{Schema: avroV1})
+	if err != nil {
+		t.Fatalf("unable to create schema: %v", err)
+	}
+	if s1.ID != 1 || s1.Version != 1 || s1.Subject != "foo-value" {
+		t.Errorf("got %+v, expected id 1 version 1", s1)
+	}
+
+	// Registering the same schema, even formatted differently, returns
+	// the same ID, and a new subject reuses the ID.
+	again, err := cl.CreateSchema(ctx, "bar-value", sr.Schema{Schema: " " + avroV1 + "\n"})
+	if err != nil || again.ID != 1 || again.Version != 1 {
+		t.Errorf("got %+v, %v, expected id 1 version 1 in bar-value", again, err)
+	}
+
+	// A compatible change is a new version; an incompatible one fails.
+	s2, err := cl.CreateSchema(ctx, "foo-value", sr.Schema{Schema: avroV2})
+	if err != nil || s2.ID != 2 || s2.Version != 2 {
+		t.Errorf("got %+v, %v, expected id 2 version 2", s2, err)
+	}
+	_, err = cl.CreateSchema(ctx, "foo-value", sr.Schema{Schema: avroV3})
+	expCode(err, 409)
+
+	check, err := cl.CheckCompatibility(ctx, "foo-value", -1, sr.Schema{Schema: avroV3})
+	if err != nil || check.Is || len(check.Messages) == 0 {
+		t.Errorf("got %+v, %v, expected incompatible with messages", check, err)
+	}
+
+	// Lowering compatibility allows the change.
+	if res := cl.SetCompatibility(ctx, sr.SetCompatibility{Level: sr.CompatNone}, "foo-value"); res[0].Err != nil {
+		t.Fatalf("unable to set compatibility: %v", res[0].Err)
+	}
+	if res := cl.Compatibility(ctx, "foo-value", sr.GlobalSubject); res[0].Level != sr.CompatNone || res[1].Level != sr.CompatBackward {
+		t.Errorf("got compatibility %v and %v, expected NONE and BACKWARD", res[0].Level, res[1].Level)
+	}
+	if _, err := cl.CreateSchema(ctx, "foo-value", sr.Schema{Schema: avroV3}); err != nil {
+		t.Errorf("unable to create schema after lowering compatibility: %v", err)
+	}
+
+	found, err := cl.LookupSchema(ctx, "foo-value", sr.Schema{Schema: avroV2})
+	if err != nil || found.Version != 2 {
+		t.Errorf("got %+v, %v, expected version 2", found, err)
+	}
+	text, err := cl.SchemaTextByID(ctx, 2)
+	if err != nil || text != avroV2 {
+		t.Errorf("got %q, %v, expected %q", text, err, avroV2)
+	}
+
+	// References must exist, and referenced schemas cannot be deleted.
+	_, err = cl.CreateSchema(ctx, "ref-value", sr.Schema{
+		Schema:     `{"type":"record","name":"x","fields":[]}`,
+		References: []sr.SchemaReference{{Name: "r", Subject: "missing", Version: 1}},
+	})
+	expCode(err, 42201)
+	ref, err := cl.CreateSchema(ctx, "ref-value", sr.Schema{
+		Schema:     `{"type":"record","name":"x","fields":[]}`,
+		References: []sr.SchemaReference{{Name: "r", Subject: "bar-value", Version: 1}},
+	})
+	if err != nil {
+		t.Fatalf("unable to create schema with reference: %v", err)
+	}
+	refs, err := cl.SchemaReferences(ctx, "bar-value", 1)
+	if err != nil || len(refs) != 1 || refs[0].ID != ref.ID {
+		t.Errorf("got %+v, %v, expected one reference from id %d", refs, err, ref.ID)
+	}
+	_, err = cl.DeleteSubject(ctx, "bar-value", sr.SoftDelete)
+	expCode(err, 42206)
+
+	// Subjects must be soft deleted before being hard deleted.
+	_, err = cl.DeleteSubject(ctx, "foo-value", sr.HardDelete)
+	expCode(err, 40405)
+	versions, err := cl.DeleteSubject(ctx, "foo-value", sr.SoftDelete)
+	if err != nil || !reflect.DeepEqual(versions, []int{1, 2, 3}) {
+		t.Errorf("got %v, %v, expected versions [1 2 3]", versions, err)
+	}
+	subjects, _ := cl.Subjects(ctx)
+	deleted, _ := cl.Subjects(sr.WithParams(ctx, sr.ShowDeleted))
+	if !reflect.DeepEqual(subjects, []string{"bar-value", "ref-value"}) || len(deleted) != 3 {
+		t.Errorf("got subjects %v and with deleted %v", subjects, deleted)
+	}
+	if _, err := cl.DeleteSubject(ctx, "foo-value", sr.HardDelete); err != nil {
+		t.Errorf("unable to hard delete: %v", err)
+	}
+	_, err = cl.SchemaByID(ctx, 3)
+	expCode(err, 40403)
+
+	// Read only mode blocks registering.
+	if res := cl.SetMode(ctx, sr.ModeReadOnly); res[0].Err != nil {
+		t.Fatalf("unable to set mode: %v", res[0].Err)
+	}
+	_, err = cl.CreateSchema(ctx, "foo-value", sr.Schema{Schema: avroV1})
+	expCode(err, 42205)
+	var re *sr.ResponseError
+	if errors.As(err, &re) && re.StatusCode != http.StatusUnprocessableEntity {
+		t.Errorf("got status %d, expected 422", re.StatusCode)
+	}
+}
@@ -10,8 +10,9 @@ const (
 
 // Kotel represents the configuration options available for the kotel plugin.
 type Kotel struct {
-	meter  *Meter
-	tracer *Tracer
+	meter      *Meter
+	tracer     *Tracer
+	propagator *Propagator
 }
 
 // Opt interface used for setting optional kotel properties.
@@ -39,6 +40,15 @@ func WithMeter(m *Meter) Opt {
 	})
 }
 
+// WithPropagator configures Kotel with a Propagator.
+func WithPropagator(p *Propagator) Opt {
+	return optFunc(func(k *Kotel) {
+		if p != nil {
+			k.propagator = p
+		}
+	})
+}
+
 // Hooks return a list of kgo.hooks compatible with its interface.
 func (k *Kotel) Hooks() []kgo.Hook {
 	var hooks []kgo.Hook
@@ -48,6 +58,9 @@ func (k *Kotel) Hooks() []kgo.Hook {
 	if k.meter != nil {
 		hooks = append(hooks, k.meter)
 	}
+	if k.propagator != nil {
+		hooks = append(hooks, k.propagator)
+	}
 	return hooks
 }
 
@@ -40,6 +40,13 @@ func TestNewConfig(t *testing.T) {
 				tracer: NewTracer(),
 			},
 		},
+		{
+			name: "WithPropagator",
+			opts: []Opt{WithPropagator(NewPropagator(nil))},
+			want: &Kotel{
+				propagator: NewPropagator(nil),
+			},
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
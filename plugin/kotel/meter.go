@@ -6,6 +6,7 @@ import (
 	"math"
 	"net"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/twmb/franz-go/pkg/kgo"
@@ -22,11 +23,15 @@ var ( // interface checks to ensure we implement the hooks properly
 	_ kgo.HookBrokerRead          = new(Meter)
 	_ kgo.HookProduceBatchWritten = new(Meter)
 	_ kgo.HookFetchBatchRead      = new(Meter)
+	_ kgo.HookGroupRebalance      = new(Meter)
+	_ kgo.HookNewClient           = new(Meter)
+	_ kgo.HookClientClosed        = new(Meter)
 )
 
 const (
 	dimensionless = "1"
 	bytes         = "by"
+	seconds       = "s"
 )
 
 type Meter struct {
@@ -35,6 +40,9 @@ type Meter struct {
 	instruments instruments
 
 	mergeConnectsMeter bool
+
+	mu sync.Mutex
+	cl *kgo.Client // the client to observe consumer lag from, if any
 }
 
 // MeterOpt interface used for setting optional config properties.
@@ -108,6 +116,10 @@ type instruments struct {
 	produceRecords metric.Int64Counter
 	fetchBytes     metric.Int64Counter
 	fetchRecords   metric.Int64Counter
+
+	rebalances        metric.Int64Counter
+	rebalanceDuration metric.Float64Histogram
+	consumerLag       metric.Int64ObservableGauge
 }
 
 func (m *Meter) newInstruments() instruments {
@@ -221,6 +233,36 @@ func (m *Meter) newInstruments() instruments {
 		log.Printf("failed to create fetchRecords instrument, %v", err)
 	}
 
+	// group & lag
+
+	rebalances, err := m.meter.Int64Counter(
+		"messaging.kafka.group.rebalances.count",
+		metric.WithUnit(dimensionless),
+		metric.WithDescription("Total number of group rebalances, by group and outcome"),
+	)
+	if err != nil {
+		log.Printf("failed to create rebalances instrument, %v", err)
+	}
+
+	rebalanceDuration, err := m.meter.Float64Histogram(
+		"messaging.kafka.group.rebalance.duration",
+		metric.WithUnit(seconds),
+		metric.WithDescription("Time taken to join a group and receive an assignment, by group and outcome"),
+	)
+	if err != nil {
+		log.Printf("failed to create rebalanceDuration instrument, %v", err)
+	}
+
+	consumerLag, err := m.meter.Int64ObservableGauge(
+		"messaging.kafka.consumer.lag",
+		metric.WithUnit(dimensionless),
+		metric.WithDescription("Number of records the consumer is behind the end of a partition, by topic and partition; requires kgo.ConsumerLagInterval"),
+		metric.WithInt64Callback(m.observeLag),
+	)
+	if err != nil {
+		log.Printf("failed to create consumerLag instrument, %v", err)
+	}
+
 	return instruments{
 		connects:    connects,
 		connectErrs: connectErrs,
@@ -236,6 +278,10 @@ func (m *Meter) newInstruments() instruments {
 		produceRecords: produceRecords,
 		fetchBytes:     fetchBytes,
 		fetchRecords:   fetchRecords,
+
+		rebalances:        rebalances,
+		rebalanceDuration: rebalanceDuration,
+		consumerLag:       consumerLag,
 	}
 }
 
@@ -248,8 +294,50 @@ func strnode(node int32) string {
 	return strconv.Itoa(int(node))
 }
 
+// observeLag observes the lag of every partition the client is consuming, as
+// computed by the client if it uses kgo.ConsumerLagInterval.
+func (m *Meter) observeLag(_ context.Context, o metric.Int64Observer) error {
+	m.mu.Lock()
+	cl := m.cl
+	m.mu.Unlock()
+	if cl == nil {
+		return nil
+	}
+	for topic, partitions := range cl.ConsumerLag() {
+		for partition, lag := range partitions {
+			if lag.Lag < 0 {
+				continue
+			}
+			o.Observe(
+				lag.Lag,
+				metric.WithAttributeSet(attribute.NewSet(
+					attribute.String("topic", topic),
+					attribute.Int64("partition", int64(partition)),
+				)),
+			)
+		}
+	}
+	return nil
+}
+
 // Hooks ---------------------------------------------------------------------
 
+// OnNewClient saves the client to observe consumer lag from.
+func (m *Meter) OnNewClient(cl *kgo.Client) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cl = cl
+}
+
+// OnClientClosed stops observing the consumer lag of the client.
+func (m *Meter) OnClientClosed(cl *kgo.Client) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.cl == cl {
+		m.cl = nil
+	}
+}
+
 func (m *Meter) OnBrokerConnect(meta kgo.BrokerMetadata, _ time.Duration, _ net.Conn, err error) {
 	node := strnode(meta.NodeID)
 
@@ -373,3 +461,26 @@ func (m *Meter) OnFetchBatchRead(meta kgo.BrokerMetadata, topic string, _ int32,
 		metric.WithAttributeSet(attributes),
 	)
 }
+
+// OnGroupRebalance records a rebalance and how long it took, for both the
+// classic and the consumer group protocols.
+func (m *Meter) OnGroupRebalance(r kgo.GroupRebalance) {
+	outcome := "success"
+	if r.Err != nil {
+		outcome = "failure"
+	}
+	attributes := attribute.NewSet(
+		attribute.String("group", r.Group),
+		attribute.String("outcome", outcome),
+	)
+	m.instruments.rebalances.Add(
+		context.Background(),
+		1,
+		metric.WithAttributeSet(attributes),
+	)
+	m.instruments.rebalanceDuration.Record(
+		context.Background(),
+		r.Duration.Seconds(),
+		metric.WithAttributeSet(attributes),
+	)
+}
@@ -211,3 +211,71 @@ func TestHook_OnBrokerConnect(t *testing.T) {
 	})
 
 }
+
+func findMetric(t *testing.T, rm metricdata.ResourceMetrics, name string) metricdata.Metrics {
+	t.Helper()
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name == name {
+				return m
+			}
+		}
+	}
+	t.Fatalf("metric %s not found", name)
+	return metricdata.Metrics{}
+}
+
+func TestHook_Rebalance(t *testing.T) {
+	r := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(r))
+	m := NewMeter(MeterProvider(mp))
+
+	m.OnGroupRebalance(kgo.GroupRebalance{Group: "g", Duration: time.Second})
+	m.OnGroupRebalance(kgo.GroupRebalance{Group: "g", Duration: time.Second, Err: errors.New("whatever error")})
+
+	rm := metricdata.ResourceMetrics{}
+	if err := r.Collect(context.Background(), &rm); err != nil {
+		t.Errorf("unexpected error collecting metrics: %s", err)
+	}
+
+	want := metricdata.Metrics{
+		Name:        "messaging.kafka.group.rebalances.count",
+		Description: "Total number of group rebalances, by group and outcome",
+		Unit:        "1",
+		Data: metricdata.Sum[int64]{
+			Temporality: metricdata.CumulativeTemporality,
+			IsMonotonic: true,
+			DataPoints: []metricdata.DataPoint[int64]{
+				{
+					Value: 1,
+					Attributes: attribute.NewSet(
+						attribute.String("group", "g"),
+						attribute.String("outcome", "success"),
+					),
+				},
+				{
+					Value: 1,
+					Attributes: attribute.NewSet(
+						attribute.String("group", "g"),
+						attribute.String("outcome", "failure"),
+					),
+				},
+			},
+		},
+	}
+	metricdatatest.AssertEqual(t, want, findMetric(t, rm, want.Name),
+		metricdatatest.IgnoreTimestamp(),
+	)
+
+	durations := findMetric(t, rm, "messaging.kafka.group.rebalance.duration").Data.(metricdata.Histogram[float64])
+	var n uint64
+	for _, dp := range durations.DataPoints {
+		n += dp.Count
+		if dp.Sum != 1 {
+			t.Errorf("got rebalance duration sum %v, want 1", dp.Sum)
+		}
+	}
+	if n != 2 {
+		t.Errorf("got %d rebalance durations, want 2", n)
+	}
+}
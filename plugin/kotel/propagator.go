@@ -0,0 +1,71 @@
+package kotel
+
+import (
+	"context"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+var ( // interface checks to ensure we implement the hooks properly.
+	_ kgo.HookProduceRecordBuffered = new(Propagator)
+	_ kgo.HookFetchRecordBuffered   = new(Propagator)
+)
+
+// Propagator propagates trace context through record headers without
+// creating any spans of its own. When producing, the context of each record is
+// injected into the record's headers; when consuming, the context is
+// extracted from the headers into the record's Context. This allows traces to
+// span from producers to consumers when you create your own spans, or when
+// you only want to carry baggage.
+//
+// The Tracer already propagates context around the spans it creates, so a
+// Propagator should not be used alongside a Tracer.
+type Propagator struct {
+	propagators propagation.TextMapPropagator
+}
+
+// NewPropagator returns a Propagator that injects and extracts trace context
+// with the given propagator. If the propagator is nil, the global propagator
+// is used.
+func NewPropagator(propagator propagation.TextMapPropagator) *Propagator {
+	if propagator == nil {
+		propagator = otel.GetTextMapPropagator()
+	}
+	return &Propagator{propagators: propagator}
+}
+
+// Inject injects the trace context in ctx into the record's headers.
+func (p *Propagator) Inject(ctx context.Context, r *kgo.Record) {
+	p.propagators.Inject(ctx, NewRecordCarrier(r))
+}
+
+// Extract returns a copy of the record's context, or context.Background if
+// the record has no context, with the trace context from the record's
+// headers.
+func (p *Propagator) Extract(r *kgo.Record) context.Context {
+	ctx := r.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return p.propagators.Extract(ctx, NewRecordCarrier(r))
+}
+
+// Hooks ----------------------------------------------------------------------
+
+// OnProduceRecordBuffered injects the record's context into the record's
+// headers.
+func (p *Propagator) OnProduceRecordBuffered(r *kgo.Record) {
+	if r.Context == nil {
+		return
+	}
+	p.Inject(r.Context, r)
+}
+
+// OnFetchRecordBuffered extracts the trace context from the record's headers
+// and sets it as the record's context, so that it can be used in downstream
+// consumer processing.
+func (p *Propagator) OnFetchRecordBuffered(r *kgo.Record) {
+	r.Context = p.Extract(r)
+}
@@ -0,0 +1,46 @@
+package kotel
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestPropagator(t *testing.T) {
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x01},
+		SpanID:     trace.SpanID{0x02},
+		TraceFlags: trace.FlagsSampled,
+	})
+	p := NewPropagator(propagation.TraceContext{})
+
+	produced := &kgo.Record{
+		Topic:   "foo",
+		Context: trace.ContextWithSpanContext(context.Background(), sc),
+	}
+	p.OnProduceRecordBuffered(produced)
+	assert.Equal(t, []string{"traceparent"}, NewRecordCarrier(produced).Keys())
+
+	fetched := &kgo.Record{Topic: "foo", Headers: produced.Headers}
+	p.OnFetchRecordBuffered(fetched)
+	got := trace.SpanContextFromContext(fetched.Context)
+	assert.Equal(t, sc.TraceID(), got.TraceID())
+	assert.Equal(t, sc.SpanID(), got.SpanID())
+	assert.True(t, got.IsRemote())
+}
+
+func TestPropagatorNoContext(t *testing.T) {
+	p := NewPropagator(propagation.TraceContext{})
+
+	r := &kgo.Record{Topic: "foo"}
+	p.OnProduceRecordBuffered(r)
+	assert.Empty(t, r.Headers)
+
+	p.OnFetchRecordBuffered(r)
+	assert.NotNil(t, r.Context)
+	assert.False(t, trace.SpanContextFromContext(r.Context).IsValid())
+}
@@ -13,9 +13,14 @@ type cfg struct {
 	gatherer prometheus.Gatherer
 
 	withClientLabel  bool
-	histograms       map[Histogram][]float64
-	defBuckets       []float64
-	fetchProduceOpts fetchProduceOpts
+	withoutNodeLabel bool
+	constLabels      prometheus.Labels
+	nameFn           func(string) string
+
+	histograms         map[Histogram][]float64
+	defBuckets         []float64
+	nativeBucketFactor float64
+	fetchProduceOpts   fetchProduceOpts
 
 	handlerOpts  promhttp.HandlerOpts
 	goCollectors bool
@@ -101,6 +106,34 @@ func WithClientLabel() Opt {
 	return opt{func(c *cfg) { c.withClientLabel = true }}
 }
 
+// WithoutNodeLabel removes the "node_id" label from the connection, read,
+// write, and request metrics, aggregating them across all brokers. This is
+// useful to reduce cardinality against large clusters. The labels on fetch and
+// produce metrics are controlled separately with FetchAndProduceDetail.
+func WithoutNodeLabel() Opt {
+	return opt{func(c *cfg) { c.withoutNodeLabel = true }}
+}
+
+// ConstLabels adds constant labels to all metrics, such as an application or
+// environment name. These are in addition to the "client_id" label added with
+// WithClientLabel.
+func ConstLabels(labels prometheus.Labels) Opt {
+	return opt{func(c *cfg) { c.constLabels = labels }}
+}
+
+// MetricNameFn rewrites the name of every metric, allowing you to match your
+// organization's naming conventions. The function is given the name of a
+// metric without the namespace or subsystem, such as "connects_total", and
+// returns the name to use. The namespace and subsystem are still prefixed to
+// the returned name.
+//
+//	kprom.MetricNameFn(func(name string) string {
+//		return "kafka_" + name
+//	})
+func MetricNameFn(fn func(name string) string) Opt {
+	return opt{func(c *cfg) { c.nameFn = fn }}
+}
+
 // Subsystem sets the subsystem for the kprom metrics, overriding the default
 // empty string.
 func Subsystem(ss string) Opt {
@@ -114,6 +147,15 @@ func Buckets(buckets []float64) Opt {
 	return opt{func(c *cfg) { c.defBuckets = buckets }}
 }
 
+// NativeHistograms additionally exposes all enabled histograms as prometheus
+// native histograms with the given bucket factor, which must be greater than
+// 1. For example, a factor of 1.1 means each bucket is at most 10% wider than
+// the previous. The classic buckets (see Buckets and HistogramsFromOpts) are
+// still exposed for scrapers that do not support native histograms.
+func NativeHistograms(bucketFactor float64) Opt {
+	return opt{func(c *cfg) { c.nativeBucketFactor = bucketFactor }}
+}
+
 // DefBuckets are the default Histogram buckets. The default buckets are
 // tailored to broadly measure the kafka timings (in seconds).
 var DefBuckets = []float64{0.001, 0.002, 0.004, 0.008, 0.016, 0.032, 0.064, 0.128, 0.256, 0.512, 1.024, 2.048}
@@ -17,6 +17,11 @@
 // The above metrics can be expanded considerably with options in this package,
 // allowing timings, uncompressed and compressed bytes, and different labels.
 //
+// Histogram buckets can be set per histogram (or histograms can be exposed as
+// native histograms), the "node_id" label can be dropped from broker metrics,
+// constant labels can be added to all metrics, and metric names can be
+// rewritten to match your own naming conventions.
+//
 // This can be used in a client like so:
 //
 //	m := kprom.NewMetrics("my_namespace")
@@ -129,11 +134,28 @@ func (m *Metrics) OnNewClient(client *kgo.Client) {
 		namespace   = m.cfg.namespace
 		subsystem   = m.cfg.subsystem
 		constLabels prometheus.Labels
+		nodeLabels  []string
 	)
-	if m.cfg.withClientLabel {
+	if len(m.cfg.constLabels) > 0 || m.cfg.withClientLabel {
 		constLabels = make(prometheus.Labels)
+		for k, v := range m.cfg.constLabels {
+			constLabels[k] = v
+		}
+	}
+	if m.cfg.withClientLabel {
 		constLabels["client_id"] = client.OptValue(kgo.ClientID).(string)
 	}
+	if !m.cfg.withoutNodeLabel {
+		nodeLabels = []string{"node_id"}
+	}
+
+	// returns the metric name, possibly rewritten by MetricNameFn
+	name := func(n string) string {
+		if m.cfg.nameFn != nil {
+			return m.cfg.nameFn(n)
+		}
+		return n
+	}
 
 	// returns Hist buckets if set, otherwise defBucket
 	getHistogramBuckets := func(h Histogram) []float64 {
@@ -149,25 +171,25 @@ func (m *Metrics) OnNewClient(client *kgo.Client) {
 		Namespace:   namespace,
 		Subsystem:   subsystem,
 		ConstLabels: constLabels,
-		Name:        "connects_total",
+		Name:        name("connects_total"),
 		Help:        "Total number of connections opened",
-	}, []string{"node_id"})
+	}, nodeLabels)
 
 	m.connConnectErrorsTotal = factory.NewCounterVec(prometheus.CounterOpts{
 		Namespace:   namespace,
 		Subsystem:   subsystem,
 		ConstLabels: constLabels,
-		Name:        "connect_errors_total",
+		Name:        name("connect_errors_total"),
 		Help:        "Total number of connection errors",
-	}, []string{"node_id"})
+	}, nodeLabels)
 
 	m.connDisconnectsTotal = factory.NewCounterVec(prometheus.CounterOpts{
 		Namespace:   namespace,
 		Subsystem:   subsystem,
 		ConstLabels: constLabels,
-		Name:        "disconnects_total",
+		Name:        name("disconnects_total"),
 		Help:        "Total number of connections closed",
-	}, []string{"node_id"})
+	}, nodeLabels)
 
 	// Write
 
@@ -175,35 +197,37 @@ func (m *Metrics) OnNewClient(client *kgo.Client) {
 		Namespace:   namespace,
 		Subsystem:   subsystem,
 		ConstLabels: constLabels,
-		Name:        "write_bytes_total",
+		Name:        name("write_bytes_total"),
 		Help:        "Total number of bytes written to the TCP connection. The bytes count is tracked after compression (when used).",
-	}, []string{"node_id"})
+	}, nodeLabels)
 
 	m.writeErrorsTotal = factory.NewCounterVec(prometheus.CounterOpts{
 		Namespace:   namespace,
 		Subsystem:   subsystem,
 		ConstLabels: constLabels,
-		Name:        "write_errors_total",
+		Name:        name("write_errors_total"),
 		Help:        "Total number of write errors",
-	}, []string{"node_id"})
+	}, nodeLabels)
 
 	m.writeWaitSeconds = factory.NewHistogramVec(prometheus.HistogramOpts{
-		Namespace:   namespace,
-		Subsystem:   subsystem,
-		ConstLabels: constLabels,
-		Name:        "write_wait_seconds",
-		Help:        "Time spent waiting to write to Kafka",
-		Buckets:     getHistogramBuckets(WriteWait),
-	}, []string{"node_id"})
+		Namespace:                   namespace,
+		Subsystem:                   subsystem,
+		ConstLabels:                 constLabels,
+		Name:                        name("write_wait_seconds"),
+		Help:                        "Time spent waiting to write to Kafka",
+		Buckets:                     getHistogramBuckets(WriteWait),
+		NativeHistogramBucketFactor: m.cfg.nativeBucketFactor,
+	}, nodeLabels)
 
 	m.writeTimeSeconds = factory.NewHistogramVec(prometheus.HistogramOpts{
-		Namespace:   namespace,
-		Subsystem:   subsystem,
-		ConstLabels: constLabels,
-		Name:        "write_time_seconds",
-		Help:        "Time spent writing to Kafka",
-		Buckets:     getHistogramBuckets(WriteTime),
-	}, []string{"node_id"})
+		Namespace:                   namespace,
+		Subsystem:                   subsystem,
+		ConstLabels:                 constLabels,
+		Name:                        name("write_time_seconds"),
+		Help:                        "Time spent writing to Kafka",
+		Buckets:                     getHistogramBuckets(WriteTime),
+		NativeHistogramBucketFactor: m.cfg.nativeBucketFactor,
+	}, nodeLabels)
 
 	// Read
 
@@ -211,55 +235,59 @@ func (m *Metrics) OnNewClient(client *kgo.Client) {
 		Namespace:   namespace,
 		Subsystem:   subsystem,
 		ConstLabels: constLabels,
-		Name:        "read_bytes_total",
+		Name:        name("read_bytes_total"),
 		Help:        "Total number of bytes read from the TCP connection. The bytes count is tracked before uncompression (when used).",
-	}, []string{"node_id"})
+	}, nodeLabels)
 
 	m.readErrorsTotal = factory.NewCounterVec(prometheus.CounterOpts{
 		Namespace:   namespace,
 		Subsystem:   subsystem,
 		ConstLabels: constLabels,
-		Name:        "read_errors_total",
+		Name:        name("read_errors_total"),
 		Help:        "Total number of read errors",
-	}, []string{"node_id"})
+	}, nodeLabels)
 
 	m.readWaitSeconds = factory.NewHistogramVec(prometheus.HistogramOpts{
-		Namespace:   namespace,
-		Subsystem:   subsystem,
-		ConstLabels: constLabels,
-		Name:        "read_wait_seconds",
-		Help:        "Time spent waiting to read from Kafka",
-		Buckets:     getHistogramBuckets(ReadWait),
-	}, []string{"node_id"})
+		Namespace:                   namespace,
+		Subsystem:                   subsystem,
+		ConstLabels:                 constLabels,
+		Name:                        name("read_wait_seconds"),
+		Help:                        "Time spent waiting to read from Kafka",
+		Buckets:                     getHistogramBuckets(ReadWait),
+		NativeHistogramBucketFactor: m.cfg.nativeBucketFactor,
+	}, nodeLabels)
 
 	m.readTimeSeconds = factory.NewHistogramVec(prometheus.HistogramOpts{
-		Namespace:   namespace,
-		Subsystem:   subsystem,
-		ConstLabels: constLabels,
-		Name:        "read_time_seconds",
-		Help:        "Time spent reading from Kafka",
-		Buckets:     getHistogramBuckets(ReadTime),
-	}, []string{"node_id"})
+		Namespace:                   namespace,
+		Subsystem:                   subsystem,
+		ConstLabels:                 constLabels,
+		Name:                        name("read_time_seconds"),
+		Help:                        "Time spent reading from Kafka",
+		Buckets:                     getHistogramBuckets(ReadTime),
+		NativeHistogramBucketFactor: m.cfg.nativeBucketFactor,
+	}, nodeLabels)
 
 	// Request E2E duration & Throttle
 
 	m.requestDurationE2ESeconds = factory.NewHistogramVec(prometheus.HistogramOpts{
-		Namespace:   namespace,
-		Subsystem:   subsystem,
-		ConstLabels: constLabels,
-		Name:        "request_duration_e2e_seconds",
-		Help:        "Time from the start of when a request is written to the end of when the response for that request was fully read",
-		Buckets:     getHistogramBuckets(RequestDurationE2E),
-	}, []string{"node_id"})
+		Namespace:                   namespace,
+		Subsystem:                   subsystem,
+		ConstLabels:                 constLabels,
+		Name:                        name("request_duration_e2e_seconds"),
+		Help:                        "Time from the start of when a request is written to the end of when the response for that request was fully read",
+		Buckets:                     getHistogramBuckets(RequestDurationE2E),
+		NativeHistogramBucketFactor: m.cfg.nativeBucketFactor,
+	}, nodeLabels)
 
 	m.requestThrottledSeconds = factory.NewHistogramVec(prometheus.HistogramOpts{
-		Namespace:   namespace,
-		Subsystem:   subsystem,
-		ConstLabels: constLabels,
-		Name:        "request_throttled_seconds",
-		Help:        "Time the request was throttled",
-		Buckets:     getHistogramBuckets(RequestThrottled),
-	}, []string{"node_id"})
+		Namespace:                   namespace,
+		Subsystem:                   subsystem,
+		ConstLabels:                 constLabels,
+		Name:                        name("request_throttled_seconds"),
+		Help:                        "Time the request was throttled",
+		Buckets:                     getHistogramBuckets(RequestThrottled),
+		NativeHistogramBucketFactor: m.cfg.nativeBucketFactor,
+	}, nodeLabels)
 
 	// Produce
 
@@ -267,7 +295,7 @@ func (m *Metrics) OnNewClient(client *kgo.Client) {
 		Namespace:   namespace,
 		Subsystem:   subsystem,
 		ConstLabels: constLabels,
-		Name:        "produce_compressed_bytes_total",
+		Name:        name("produce_compressed_bytes_total"),
 		Help:        "Total number of compressed bytes produced",
 	}, m.cfg.fetchProduceOpts.labels)
 
@@ -279,7 +307,7 @@ func (m *Metrics) OnNewClient(client *kgo.Client) {
 		Namespace:   namespace,
 		Subsystem:   subsystem,
 		ConstLabels: constLabels,
-		Name:        produceUncompressedBytesName,
+		Name:        name(produceUncompressedBytesName),
 		Help:        "Total number of uncompressed bytes produced",
 	}, m.cfg.fetchProduceOpts.labels)
 
@@ -287,7 +315,7 @@ func (m *Metrics) OnNewClient(client *kgo.Client) {
 		Namespace:   namespace,
 		Subsystem:   subsystem,
 		ConstLabels: constLabels,
-		Name:        "produce_batches_total",
+		Name:        name("produce_batches_total"),
 		Help:        "Total number of batches produced",
 	}, m.cfg.fetchProduceOpts.labels)
 
@@ -295,7 +323,7 @@ func (m *Metrics) OnNewClient(client *kgo.Client) {
 		Namespace:   namespace,
 		Subsystem:   subsystem,
 		ConstLabels: constLabels,
-		Name:        "produce_records_total",
+		Name:        name("produce_records_total"),
 		Help:        "Total number of records produced",
 	}, m.cfg.fetchProduceOpts.labels)
 
@@ -305,7 +333,7 @@ func (m *Metrics) OnNewClient(client *kgo.Client) {
 		Namespace:   namespace,
 		Subsystem:   subsystem,
 		ConstLabels: constLabels,
-		Name:        "fetch_compressed_bytes_total",
+		Name:        name("fetch_compressed_bytes_total"),
 		Help:        "Total number of compressed bytes fetched",
 	}, m.cfg.fetchProduceOpts.labels)
 
@@ -317,7 +345,7 @@ func (m *Metrics) OnNewClient(client *kgo.Client) {
 		Namespace:   namespace,
 		Subsystem:   subsystem,
 		ConstLabels: constLabels,
-		Name:        fetchUncompressedBytesName,
+		Name:        name(fetchUncompressedBytesName),
 		Help:        "Total number of uncompressed bytes fetched",
 	}, m.cfg.fetchProduceOpts.labels)
 
@@ -325,7 +353,7 @@ func (m *Metrics) OnNewClient(client *kgo.Client) {
 		Namespace:   namespace,
 		Subsystem:   subsystem,
 		ConstLabels: constLabels,
-		Name:        "fetch_batches_total",
+		Name:        name("fetch_batches_total"),
 		Help:        "Total number of batches fetched",
 	}, m.cfg.fetchProduceOpts.labels)
 
@@ -333,7 +361,7 @@ func (m *Metrics) OnNewClient(client *kgo.Client) {
 		Namespace:   namespace,
 		Subsystem:   subsystem,
 		ConstLabels: constLabels,
-		Name:        "fetch_records_total",
+		Name:        name("fetch_records_total"),
 		Help:        "Total number of records fetched",
 	}, m.cfg.fetchProduceOpts.labels)
 
@@ -344,7 +372,7 @@ func (m *Metrics) OnNewClient(client *kgo.Client) {
 			Namespace:   namespace,
 			Subsystem:   subsystem,
 			ConstLabels: constLabels,
-			Name:        "buffered_produce_records_total",
+			Name:        name("buffered_produce_records_total"),
 			Help:        "Total number of records buffered within the client ready to be produced",
 		},
 		func() float64 { return float64(client.BufferedProduceRecords()) },
@@ -355,7 +383,7 @@ func (m *Metrics) OnNewClient(client *kgo.Client) {
 			Namespace:   namespace,
 			Subsystem:   subsystem,
 			ConstLabels: constLabels,
-			Name:        "buffered_fetch_records_total",
+			Name:        name("buffered_fetch_records_total"),
 			Help:        "Total number of records buffered within the client ready to be consumed",
 		},
 		func() float64 { return float64(client.BufferedFetchRecords()) },
@@ -393,20 +421,20 @@ func (m *Metrics) OnClientClosed(*kgo.Client) {
 // gathering.
 // This method is meant to be called by the hook system and not by the user
 func (m *Metrics) OnBrokerConnect(meta kgo.BrokerMetadata, _ time.Duration, _ net.Conn, err error) {
-	nodeId := kgo.NodeName(meta.NodeID)
+	nodeId := m.nodeLabelValues(meta)
 	if err != nil {
-		m.connConnectErrorsTotal.WithLabelValues(nodeId).Inc()
+		m.connConnectErrorsTotal.WithLabelValues(nodeId...).Inc()
 		return
 	}
-	m.connConnectsTotal.WithLabelValues(nodeId).Inc()
+	m.connConnectsTotal.WithLabelValues(nodeId...).Inc()
 }
 
 // OnBrokerDisconnect implements the HookBrokerDisconnect interface for metrics
 // gathering.
 // This method is meant to be called by the hook system and not by the user
 func (m *Metrics) OnBrokerDisconnect(meta kgo.BrokerMetadata, _ net.Conn) {
-	nodeId := kgo.NodeName(meta.NodeID)
-	m.connDisconnectsTotal.WithLabelValues(nodeId).Inc()
+	nodeId := m.nodeLabelValues(meta)
+	m.connDisconnectsTotal.WithLabelValues(nodeId...).Inc()
 }
 
 // OnBrokerThrottle implements the HookBrokerThrottle interface for metrics
@@ -414,8 +442,8 @@ func (m *Metrics) OnBrokerDisconnect(meta kgo.BrokerMetadata, _ net.Conn) {
 // This method is meant to be called by the hook system and not by the user
 func (m *Metrics) OnBrokerThrottle(meta kgo.BrokerMetadata, throttleInterval time.Duration, _ bool) {
 	if _, ok := m.cfg.histograms[RequestThrottled]; ok {
-		nodeId := kgo.NodeName(meta.NodeID)
-		m.requestThrottledSeconds.WithLabelValues(nodeId).Observe(throttleInterval.Seconds())
+		nodeId := m.nodeLabelValues(meta)
+		m.requestThrottledSeconds.WithLabelValues(nodeId...).Observe(throttleInterval.Seconds())
 	}
 }
 
@@ -468,32 +496,39 @@ func (m *Metrics) OnBrokerWrite(meta kgo.BrokerMetadata, _ int16, bytesWritten i
 // OnBrokerE2E implements the HookBrokerE2E interface for metrics gathering
 // This method is meant to be called by the hook system and not by the user
 func (m *Metrics) OnBrokerE2E(meta kgo.BrokerMetadata, _ int16, e2e kgo.BrokerE2E) {
-	nodeId := kgo.NodeName(meta.NodeID)
+	nodeId := m.nodeLabelValues(meta)
 	if e2e.WriteErr != nil {
-		m.writeErrorsTotal.WithLabelValues(nodeId).Inc()
+		m.writeErrorsTotal.WithLabelValues(nodeId...).Inc()
 		return
 	}
-	m.writeBytesTotal.WithLabelValues(nodeId).Add(float64(e2e.BytesWritten))
+	m.writeBytesTotal.WithLabelValues(nodeId...).Add(float64(e2e.BytesWritten))
 	if _, ok := m.cfg.histograms[WriteWait]; ok {
-		m.writeWaitSeconds.WithLabelValues(nodeId).Observe(e2e.WriteWait.Seconds())
+		m.writeWaitSeconds.WithLabelValues(nodeId...).Observe(e2e.WriteWait.Seconds())
 	}
 	if _, ok := m.cfg.histograms[WriteTime]; ok {
-		m.writeTimeSeconds.WithLabelValues(nodeId).Observe(e2e.TimeToWrite.Seconds())
+		m.writeTimeSeconds.WithLabelValues(nodeId...).Observe(e2e.TimeToWrite.Seconds())
 	}
 	if e2e.ReadErr != nil {
-		m.readErrorsTotal.WithLabelValues(nodeId).Inc()
+		m.readErrorsTotal.WithLabelValues(nodeId...).Inc()
 		return
 	}
-	m.readBytesTotal.WithLabelValues(nodeId).Add(float64(e2e.BytesRead))
+	m.readBytesTotal.WithLabelValues(nodeId...).Add(float64(e2e.BytesRead))
 	if _, ok := m.cfg.histograms[ReadWait]; ok {
-		m.readWaitSeconds.WithLabelValues(nodeId).Observe(e2e.ReadWait.Seconds())
+		m.readWaitSeconds.WithLabelValues(nodeId...).Observe(e2e.ReadWait.Seconds())
 	}
 	if _, ok := m.cfg.histograms[ReadTime]; ok {
-		m.readTimeSeconds.WithLabelValues(nodeId).Observe(e2e.TimeToRead.Seconds())
+		m.readTimeSeconds.WithLabelValues(nodeId...).Observe(e2e.TimeToRead.Seconds())
 	}
 	if _, ok := m.cfg.histograms[RequestDurationE2E]; ok {
-		m.requestDurationE2ESeconds.WithLabelValues(nodeId).Observe(e2e.DurationE2E().Seconds())
+		m.requestDurationE2ESeconds.WithLabelValues(nodeId...).Observe(e2e.DurationE2E().Seconds())
+	}
+}
+
+func (m *Metrics) nodeLabelValues(meta kgo.BrokerMetadata) []string {
+	if m.cfg.withoutNodeLabel {
+		return nil
 	}
+	return []string{kgo.NodeName(meta.NodeID)}
 }
 
 func (m *Metrics) fetchProducerLabels(nodeId, topic string) prometheus.Labels {
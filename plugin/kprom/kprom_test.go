@@ -0,0 +1,155 @@
+package kprom
+
+import (
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// newTestMetrics returns metrics registered for a new client, as when the
+// metrics are used as a client hook, and the registry they are in.
+func newTestMetrics(t *testing.T, opts ...Opt) (*Metrics, *prometheus.Registry) {
+	t.Helper()
+	reg := prometheus.NewRegistry()
+	m := NewMetrics("ns", append([]Opt{Registry(reg)}, opts...)...)
+	cl, err := kgo.NewClient(kgo.WithHooks(m), kgo.ClientID("cl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(cl.Close)
+	return m, reg
+}
+
+// observe calls the broker hooks as if a request was written to and read
+// from node 1.
+func observe(m *Metrics) {
+	meta := kgo.BrokerMetadata{NodeID: 1}
+	m.OnBrokerConnect(meta, time.Millisecond, nil, nil)
+	m.OnBrokerE2E(meta, 0, kgo.BrokerE2E{
+		BytesWritten: 10,
+		BytesRead:    20,
+		WriteWait:    time.Millisecond,
+		TimeToWrite:  time.Millisecond,
+		ReadWait:     time.Millisecond,
+		TimeToRead:   time.Millisecond,
+	})
+}
+
+func gather(t *testing.T, reg *prometheus.Registry) map[string]*dto.MetricFamily {
+	t.Helper()
+	mfs, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	byName := make(map[string]*dto.MetricFamily, len(mfs))
+	for _, mf := range mfs {
+		byName[mf.GetName()] = mf
+	}
+	return byName
+}
+
+// labels returns the sorted name=value labels of the family's first metric.
+func labels(mf *dto.MetricFamily) []string {
+	var names []string
+	for _, l := range mf.GetMetric()[0].GetLabel() {
+		names = append(names, l.GetName()+"="+l.GetValue())
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestNodeLabel(t *testing.T) {
+	for _, test := range []struct {
+		opts []Opt
+		exp  string
+	}{
+		{nil, "node_id=1"},
+		{[]Opt{WithoutNodeLabel()}, ""},
+	} {
+		m, reg := newTestMetrics(t, test.opts...)
+		observe(m)
+		mfs := gather(t, reg)
+		for _, name := range []string{"ns_connects_total", "ns_write_bytes_total", "ns_read_bytes_total"} {
+			mf, ok := mfs[name]
+			if !ok {
+				t.Fatalf("missing %s", name)
+			}
+			if got := strings.Join(labels(mf), ","); got != test.exp {
+				t.Errorf("%s: got labels %q, expected %q", name, got, test.exp)
+			}
+		}
+		if got := mfs["ns_write_bytes_total"].GetMetric()[0].GetCounter().GetValue(); got != 10 {
+			t.Errorf("got %v bytes written, expected 10", got)
+		}
+	}
+}
+
+func TestConstLabels(t *testing.T) {
+	m, reg := newTestMetrics(t,
+		ConstLabels(prometheus.Labels{"app": "a", "env": "e"}),
+		WithClientLabel(),
+	)
+	observe(m)
+	mfs := gather(t, reg)
+	for _, name := range []string{"ns_connects_total", "ns_buffered_produce_records_total"} {
+		mf, ok := mfs[name]
+		if !ok {
+			t.Fatalf("missing %s", name)
+		}
+		got := labels(mf)
+		for _, exp := range []string{"app=a", "env=e", "client_id=cl"} {
+			var found bool
+			for _, l := range got {
+				found = found || l == exp
+			}
+			if !found {
+				t.Errorf("%s: labels %v missing %s", name, got, exp)
+			}
+		}
+	}
+}
+
+func TestMetricNameFn(t *testing.T) {
+	m, reg := newTestMetrics(t,
+		Subsystem("ss"),
+		MetricNameFn(func(name string) string { return "kafka_" + name }),
+	)
+	observe(m)
+	mfs := gather(t, reg)
+	for name := range mfs {
+		if !strings.HasPrefix(name, "ns_ss_kafka_") {
+			t.Errorf("metric %s was not renamed", name)
+		}
+	}
+	if _, ok := mfs["ns_ss_kafka_connects_total"]; !ok {
+		t.Error("missing ns_ss_kafka_connects_total")
+	}
+}
+
+func TestNativeHistograms(t *testing.T) {
+	for _, factor := range []float64{0, 1.1} {
+		opts := []Opt{Histograms(WriteWait), Buckets([]float64{0.01, 0.1})}
+		if factor != 0 {
+			opts = append(opts, NativeHistograms(factor))
+		}
+		m, reg := newTestMetrics(t, opts...)
+		observe(m)
+		mf, ok := gather(t, reg)["ns_write_wait_seconds"]
+		if !ok {
+			t.Fatal("missing ns_write_wait_seconds")
+		}
+		h := mf.GetMetric()[0].GetHistogram()
+		if n := len(h.GetBucket()); n != 2 {
+			t.Errorf("factor %v: got %d classic buckets, expected 2", factor, n)
+		}
+		if native := h.Schema != nil; native != (factor != 0) {
+			t.Errorf("factor %v: got native histogram %v, expected %v", factor, native, factor != 0)
+		}
+	}
+}
@@ -7,6 +7,13 @@
 //	        kgo.WithLogger(kslog.New(slog.Default())),
 //	        // ...other opts
 //	)
+//
+// By default, the logger checks which levels are enabled on the slog logger
+// every time the client checks the log level. A different level can be
+// chosen with the Level, LevelVar, or LevelFn options.
+//
+// Key value pairs logged by the client are passed through to slog as is, as
+// are any slog.Attr values.
 package kslog
 
 import (
@@ -20,15 +27,56 @@ import (
 // initializing a client.
 type Logger struct {
 	sl *slog.Logger
+
+	levelFn func() kgo.LogLevel
 }
 
 // New returns a new kgo.Logger that wraps an slog.Logger.
-func New(sl *slog.Logger) *Logger {
-	return &Logger{sl}
+func New(sl *slog.Logger, opts ...Opt) *Logger {
+	l := &Logger{sl: sl}
+	l.levelFn = l.enabledLevel
+	for _, opt := range opts {
+		opt.apply(l)
+	}
+	return l
+}
+
+// Opt applies options to the logger.
+type Opt interface {
+	apply(*Logger)
+}
+
+type opt struct{ fn func(*Logger) }
+
+func (o opt) apply(l *Logger) { o.fn(l) }
+
+// LevelFn sets a function that can dynamically change the log level. You may
+// want to set this if checking if a log level is enabled on your handler is
+// expensive.
+func LevelFn(fn func() kgo.LogLevel) Opt {
+	return opt{func(l *Logger) { l.levelFn = fn }}
+}
+
+// LevelVar returns an option that uses the current level of the given
+// slog.LevelVar for LevelFn. If your handler uses the LevelVar already, using
+// this option is not necessary, but it is slightly less work than the
+// default level function that has to check if each level is enabled
+// individually.
+func LevelVar(lv *slog.LevelVar) Opt {
+	return LevelFn(func() kgo.LogLevel { return slogToKgoLevel(lv.Level()) })
+}
+
+// Level sets a static level for the kgo.Logger Level function.
+func Level(level kgo.LogLevel) Opt {
+	return LevelFn(func() kgo.LogLevel { return level })
 }
 
 // Level is for the kgo.Logger interface.
 func (l *Logger) Level() kgo.LogLevel {
+	return l.levelFn()
+}
+
+func (l *Logger) enabledLevel() kgo.LogLevel {
 	ctx := context.Background()
 	switch {
 	case l.sl.Enabled(ctx, slog.LevelDebug):
@@ -46,9 +94,27 @@ func (l *Logger) Level() kgo.LogLevel {
 
 // Log is for the kgo.Logger interface.
 func (l *Logger) Log(level kgo.LogLevel, msg string, keyvals ...any) {
+	if level == kgo.LogLevelNone {
+		return
+	}
 	l.sl.Log(context.Background(), kgoToSlogLevel(level), msg, keyvals...)
 }
 
+func slogToKgoLevel(level slog.Level) kgo.LogLevel {
+	switch {
+	case level <= slog.LevelDebug:
+		return kgo.LogLevelDebug
+	case level <= slog.LevelInfo:
+		return kgo.LogLevelInfo
+	case level <= slog.LevelWarn:
+		return kgo.LogLevelWarn
+	case level <= slog.LevelError:
+		return kgo.LogLevelError
+	default:
+		return kgo.LogLevelNone
+	}
+}
+
 func kgoToSlogLevel(level kgo.LogLevel) slog.Level {
 	switch level {
 	case kgo.LogLevelError:
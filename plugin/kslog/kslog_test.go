@@ -1,7 +1,10 @@
 package kslog_test
 
 import (
+	"bytes"
 	"log/slog"
+	"strings"
+	"testing"
 
 	"github.com/twmb/franz-go/pkg/kgo"
 	"github.com/twmb/franz-go/plugin/kslog"
@@ -13,3 +16,34 @@ func ExampleNew() {
 	l.Log(kgo.LogLevelInfo, "test message", "test-key", "test-val")
 	// Output:
 }
+
+func TestLevel(t *testing.T) {
+	lv := new(slog.LevelVar)
+	var buf bytes.Buffer
+	sl := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: lv}))
+
+	for _, test := range []struct {
+		level slog.Level
+		exp   kgo.LogLevel
+	}{
+		{slog.LevelDebug, kgo.LogLevelDebug},
+		{slog.LevelInfo, kgo.LogLevelInfo},
+		{slog.LevelInfo + 2, kgo.LogLevelWarn},
+		{slog.LevelError, kgo.LogLevelError},
+		{slog.LevelError + 1, kgo.LogLevelNone},
+	} {
+		lv.Set(test.level)
+		if got := kslog.New(sl).Level(); got != test.exp {
+			t.Errorf("enabled level %v: got %v != exp %v", test.level, got, test.exp)
+		}
+		if got := kslog.New(sl, kslog.LevelVar(lv)).Level(); got != test.exp {
+			t.Errorf("level var %v: got %v != exp %v", test.level, got, test.exp)
+		}
+	}
+
+	lv.Set(slog.LevelDebug)
+	kslog.New(sl).Log(kgo.LogLevelWarn, "msg", "k", "v", slog.Int("n", 1))
+	if got, exp := buf.String(), "level=WARN msg=msg k=v n=1\n"; !strings.HasSuffix(got, exp) {
+		t.Errorf("got %q, exp suffix %q", got, exp)
+	}
+}
@@ -0,0 +1,23 @@
+module github.com/twmb/franz-go/tests
+
+go 1.24.0
+
+require (
+	github.com/twmb/franz-go v1.19.4
+	github.com/twmb/franz-go/pkg/kadm v1.16.1
+	github.com/twmb/franz-go/pkg/kfake v0.0.0-00010101000000-000000000000
+	github.com/twmb/franz-go/pkg/kmsg v1.13.1
+)
+
+require (
+	github.com/klauspost/compress v1.18.0 // indirect
+	github.com/pierrec/lz4/v4 v4.1.22 // indirect
+	golang.org/x/crypto v0.38.0 // indirect
+)
+
+replace (
+	github.com/twmb/franz-go => ../
+	github.com/twmb/franz-go/pkg/kadm => ../pkg/kadm
+	github.com/twmb/franz-go/pkg/kfake => ../pkg/kfake
+	github.com/twmb/franz-go/pkg/kmsg => ../pkg/kmsg
+)
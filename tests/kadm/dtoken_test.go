@@ -0,0 +1,64 @@
+package kadm_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kfake"
+	"github.com/twmb/franz-go/pkg/kfake/kadmtest"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/sasl/scram"
+)
+
+func TestDelegationToken(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	adm, c := kadmtest.New(t,
+		kadmtest.ClusterOpts(kfake.EnableSASL(), kfake.Superuser("SCRAM-SHA-256", "admin", "pw")),
+		kadmtest.ClientOpts(kgo.SASL(scram.Auth{User: "admin", Pass: "pw"}.AsSha256Mechanism())),
+	)
+
+	// Principals without a type default to "User", which is the only
+	// type Kafka accepts.
+	token, err := adm.CreateDelegationToken(ctx, kadm.CreateDelegationToken{
+		Owner:    &kadm.Principal{Name: "bob"},
+		Renewers: []kadm.Principal{{Name: "carol"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := (kadm.Principal{Type: "User", Name: "bob"}); token.Owner != exp {
+		t.Errorf("got owner %v, exp %v", token.Owner, exp)
+	}
+	if exp := (kadm.Principal{Type: "User", Name: "admin"}); token.TokenRequesterPrincipal != exp {
+		t.Errorf("got requester %v, exp %v", token.TokenRequesterPrincipal, exp)
+	}
+	if _, err := adm.CreateDelegationToken(ctx, kadm.CreateDelegationToken{
+		Owner: &kadm.Principal{Type: "Group", Name: "bob"},
+	}); !errors.Is(err, kerr.InvalidPrincipalType) {
+		t.Errorf("got err %v for a Group owner, exp INVALID_PRINCIPAL_TYPE", err)
+	}
+
+	// The token authenticates with either SCRAM mechanism.
+	if auth := token.ScramAuth(); auth.User != token.TokenID || !auth.IsToken {
+		t.Errorf("unexpected scram auth %+v", auth)
+	}
+	for _, mechanism := range []string{"SCRAM-SHA-256", "SCRAM-SHA-512"} {
+		m := token.AsSha256Mechanism()
+		if mechanism == "SCRAM-SHA-512" {
+			m = token.AsSha512Mechanism()
+		}
+		if m.Name() != mechanism {
+			t.Errorf("got mechanism %s, exp %s", m.Name(), mechanism)
+		}
+		tokenAdm := kadm.NewClient(c.NewTestClient(t, kgo.SASL(m), kgo.RequestRetries(0)))
+		if _, err := tokenAdm.ListBrokers(ctx); err != nil {
+			t.Errorf("%s: unable to authenticate with the token: %v", mechanism, err)
+		}
+	}
+}
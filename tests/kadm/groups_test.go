@@ -0,0 +1,264 @@
+package kadm_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kfake"
+	"github.com/twmb/franz-go/pkg/kfake/kadmtest"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func TestDescribeGroupActivity(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Offsets 0 through 4 were produced two hours ago and offsets 5
+	// through 9 are produced now, so with a one hour cutoff, a group is
+	// abandoned if it has not consumed past offset 5. kfake lists offsets
+	// by timestamp per batch, so the new records are a separate batch.
+	var old, recent []*kgo.Record
+	for i := 0; i < 5; i++ {
+		old = append(old, &kgo.Record{Topic: "t", Value: []byte("v"), Timestamp: time.Now().Add(-2 * time.Hour)})
+		recent = append(recent, &kgo.Record{Value: []byte("v")})
+	}
+	adm, c := kadmtest.New(t,
+		kadmtest.Topic("t", 1, nil),
+		kadmtest.Records(old...),
+		kadmtest.GroupOffsets("stale", map[string]map[int32]int64{"t": {0: 3}}),
+		kadmtest.GroupOffsets("caught-up-to-cutoff", map[string]map[int32]int64{"t": {0: 5}}),
+		kadmtest.GroupOffsets("recent", map[string]map[int32]int64{"t": {0: 7}}),
+	)
+	if _, err := c.ProduceTo("t", 0, recent...); err != nil {
+		t.Fatal(err)
+	}
+
+	// A group with a member is active regardless of its offsets.
+	cl := c.NewTestClient(t, kgo.ConsumerGroup("active"), kgo.ConsumeTopics("t"))
+	if fs := cl.PollFetches(ctx); fs.NumRecords() == 0 {
+		t.Fatalf("unable to consume with the active group: %v", fs.Err0())
+	}
+
+	as, err := adm.DescribeGroupActivity(ctx, time.Hour, "active", "stale", "caught-up-to-cutoff", "recent")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := as.Error(); err != nil {
+		t.Fatalf("unexpected group error: %v", err)
+	}
+	for g, exp := range map[string]struct {
+		activity  kadm.GroupActivity
+		members   int
+		committed bool
+	}{
+		"active":              {kadm.GroupActive, 1, false},
+		"stale":               {kadm.GroupAbandoned, 0, true},
+		"caught-up-to-cutoff": {kadm.GroupAbandoned, 0, true},
+		"recent":              {kadm.GroupEmpty, 0, true},
+	} {
+		a, ok := as[g]
+		if !ok {
+			t.Errorf("%s: missing from activity", g)
+			continue
+		}
+		if a.Group != g || a.Activity != exp.activity || a.Members != exp.members || (len(a.Committed) > 0) != exp.committed {
+			t.Errorf("%s: got %s with %d members and committed %v, exp %s with %d members and committed %v",
+				g, a.Activity, a.Members, a.Committed, exp.activity, exp.members, exp.committed)
+		}
+	}
+	if got := as.Abandoned(); len(got) != 2 || got[0] != "caught-up-to-cutoff" || got[1] != "stale" {
+		t.Errorf("got abandoned %v, exp caught-up-to-cutoff and stale", got)
+	}
+	if sorted := as.Sorted(); len(sorted) != 4 || sorted[0].Group != "active" || sorted[3].Group != "stale" {
+		t.Errorf("got sorted %v, exp all four groups by name", sorted)
+	}
+
+	// A group that cannot be described has an error and is not
+	// abandoned, and other groups are still classified.
+	as, err = adm.DescribeGroupActivity(ctx, time.Hour, "stale", "unknown")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a := as["unknown"]; !errors.Is(a.Err, kerr.GroupIDNotFound) || !errors.Is(as.Error(), kerr.GroupIDNotFound) {
+		t.Errorf("got %+v, exp GROUP_ID_NOT_FOUND", a)
+	}
+	if got := as.Abandoned(); len(got) != 1 || got[0] != "stale" {
+		t.Errorf("got abandoned %v, exp only stale", got)
+	}
+
+	// A longer cutoff makes every committed group recent enough to be
+	// empty rather than abandoned. Describing no groups describes all.
+	as, err = adm.DescribeGroupActivity(ctx, 3*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(as) != 4 {
+		t.Errorf("got %d groups describing all, exp the 4 known groups", len(as))
+	}
+	for _, g := range []string{"stale", "caught-up-to-cutoff", "recent"} {
+		if a := as[g]; a.Activity != kadm.GroupEmpty {
+			t.Errorf("%s: got %s with a three hour cutoff, exp Empty", g, a.Activity)
+		}
+	}
+
+}
+
+func TestGroupActivityString(t *testing.T) {
+	for a, exp := range map[kadm.GroupActivity]string{
+		kadm.GroupActive:    "Active",
+		kadm.GroupEmpty:     "Empty",
+		kadm.GroupAbandoned: "Abandoned",
+		-1:                  "Unknown",
+	} {
+		if got := a.String(); got != exp {
+			t.Errorf("got %q, exp %q", got, exp)
+		}
+	}
+}
+
+func TestCopyGroupOffsets(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	adm, c := kadmtest.New(t,
+		kadmtest.Topic("t", 2, nil),
+		kadmtest.Records(
+			&kgo.Record{Topic: "t", Partition: 0},
+			&kgo.Record{Topic: "t", Partition: 1},
+		),
+	)
+
+	var os kadm.Offsets
+	os.Add(kadm.Offset{Topic: "t", Partition: 0, At: 1, LeaderEpoch: -1, Metadata: "m0"})
+	os.Add(kadm.Offset{Topic: "t", Partition: 1, At: 0, LeaderEpoch: -1, Metadata: "m1"})
+	if committed, err := adm.CommitOffsets(ctx, "from", os); err != nil || committed.Error() != nil {
+		t.Fatalf("unable to commit: %v, %v", err, committed.Error())
+	}
+
+	// Copying commits every offset with its metadata.
+	copied, err := adm.CopyGroupOffsets(ctx, "from", "to")
+	if err != nil || copied.Error() != nil || len(copied["t"]) != 2 {
+		t.Fatalf("got %v, %v, %v copying, exp both partitions committed", copied, err, copied.Error())
+	}
+	fetched, err := adm.FetchOffsets(ctx, "to")
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Each(func(o kadm.Offset) {
+		if got, ok := fetched.Lookup(o.Topic, o.Partition); !ok || got.At != o.At || got.Metadata != o.Metadata {
+			t.Errorf("%s/%d: got %+v, exp at %d with metadata %q", o.Topic, o.Partition, got.Offset, o.At, o.Metadata)
+		}
+	})
+
+	// Copying from a group that does not exist fails.
+	if _, err := adm.CopyGroupOffsets(ctx, "none", "to2"); !errors.Is(err, kerr.GroupIDNotFound) {
+		t.Errorf("got err %v copying from a missing group, exp GROUP_ID_NOT_FOUND", err)
+	}
+
+	// Kafka rejects commits from outside a group that has members, which
+	// is reported per partition.
+	cl := c.NewTestClient(t, kgo.ConsumerGroup("running"), kgo.ConsumeTopics("t"))
+	if fs := cl.PollFetches(ctx); fs.NumRecords() == 0 {
+		t.Fatalf("unable to consume with the running group: %v", fs.Err0())
+	}
+	copied, err = adm.CopyGroupOffsets(ctx, "from", "running")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if copied.Error() == nil {
+		t.Error("copying to a running group succeeded, exp a commit error")
+	}
+}
+
+func TestCopyGroupOffsetsTo(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Both clusters have batches with first timestamps 1000, 2000, and
+	// 3000, but dst has an extra batch first, so dst offsets are two
+	// more than src offsets.
+	src, srcCluster := kadmtest.New(t, kadmtest.Topic("t", 1, nil), kadmtest.Topic("src-only", 1, nil))
+	dst, dstCluster := kadmtest.New(t, kadmtest.Topic("t", 1, nil))
+	produce := func(c *kfake.Cluster, ms ...int64) {
+		for _, ms := range ms {
+			r0, r1 := &kgo.Record{Timestamp: time.UnixMilli(ms)}, &kgo.Record{Timestamp: time.UnixMilli(ms + 1)}
+			if _, err := c.ProduceTo("t", 0, r0, r1); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	produce(srcCluster, 1000, 2000, 3000) // offsets 0-1, 2-3, 4-5
+	produce(dstCluster, 500, 1000, 2000, 3000)
+
+	// The start of src is deleted so that an offset before the start can
+	// be translated.
+	var before kadm.Offsets
+	before.AddOffset("t", 0, 1, -1)
+	if deleted, err := src.DeleteRecords(ctx, before); err != nil || deleted.Error() != nil {
+		t.Fatalf("unable to delete records: %v, %v", err, deleted.Error())
+	}
+
+	for _, test := range []struct {
+		name     string
+		at       int64
+		expAt    int64
+		expStamp int64
+	}{
+		{"batch start", 2, 4, 2000},
+		{"inside batch", 3, 4, 2000}, // the batch start: reprocessed, not skipped
+		{"last batch", 5, 6, 3000},
+		{"at end", 6, 8, -1},
+		{"past end", 100, 8, -1},
+		{"before start", 0, 0, -2},
+	} {
+		var os kadm.Offsets
+		os.Add(kadm.Offset{Topic: "t", Partition: 0, At: test.at, LeaderEpoch: -1, Metadata: test.name})
+		translated, err := src.TranslateOffsets(ctx, dst, os)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		l, ok := translated.Lookup("t", 0)
+		if !ok || l.Err != nil || l.Offset != test.expAt || l.Timestamp != test.expStamp {
+			t.Errorf("%s: got %+v, exp offset %d with timestamp %d", test.name, l, test.expAt, test.expStamp)
+		}
+
+		group := "from-" + strconv.FormatInt(test.at, 10)
+		if committed, err := src.CommitOffsets(ctx, group, os); err != nil || committed.Error() != nil {
+			t.Fatalf("%s: unable to commit: %v, %v", test.name, err, committed.Error())
+		}
+		copied, err := src.CopyGroupOffsetsTo(ctx, dst, group, "to-"+group)
+		if err != nil || copied.Error() != nil {
+			t.Fatalf("%s: unable to copy: %v, %v", test.name, err, copied.Error())
+		}
+		fetched, err := dst.FetchOffsets(ctx, "to-"+group)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if o, ok := fetched.Lookup("t", 0); !ok || o.At != test.expAt || o.Metadata != test.name {
+			t.Errorf("%s: got copied %+v, exp at %d with the source metadata", test.name, o.Offset, test.expAt)
+		}
+	}
+
+	// Partitions that do not exist in dst cannot be translated, which
+	// fails the copy.
+	var missing kadm.Offsets
+	missing.AddOffset("src-only", 0, 0, -1)
+	translated, err := src.TranslateOffsets(ctx, dst, missing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l, _ := translated.Lookup("src-only", 0); !errors.Is(l.Err, kerr.UnknownTopicOrPartition) || l.Offset != -1 {
+		t.Errorf("got %+v translating a partition missing in dst, exp UNKNOWN_TOPIC_OR_PARTITION", l)
+	}
+	if committed, err := src.CommitOffsets(ctx, "from-missing", missing); err != nil || committed.Error() != nil {
+		t.Fatalf("unable to commit: %v, %v", err, committed.Error())
+	}
+	if _, err := src.CopyGroupOffsetsTo(ctx, dst, "from-missing", "to-missing"); !errors.Is(err, kerr.UnknownTopicOrPartition) {
+		t.Errorf("got err %v copying a partition missing in dst, exp UNKNOWN_TOPIC_OR_PARTITION", err)
+	}
+}
@@ -0,0 +1,151 @@
+package kadm_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kfake"
+	"github.com/twmb/franz-go/pkg/kfake/kadmtest"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func TestDescribeLogDirUsage(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// With four brokers and three replicas per partition, brokers host
+	// different partitions and so use different amounts of disk.
+	big := bytes.Repeat([]byte("x"), 1000)
+	adm, _ := kadmtest.New(t,
+		kadmtest.ClusterOpts(kfake.NumBrokers(4)),
+		kadmtest.Topic("empty", 1, nil),
+		kadmtest.Topic("small", 1, nil),
+		kadmtest.Topic("big", 2, nil),
+		kadmtest.Records(
+			&kgo.Record{Topic: "small", Value: []byte("v")},
+			&kgo.Record{Topic: "big", Partition: 0, Value: big},
+			&kgo.Record{Topic: "big", Partition: 1, Value: big},
+			&kgo.Record{Topic: "big", Partition: 1, Value: big},
+		),
+	)
+
+	u, err := adm.DescribeLogDirUsage(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Every partition's usage is its size on the leader times its three
+	// replicas, and topics and brokers sum to the total.
+	topics, err := adm.ListTopics(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var topicsTotal int64
+	for _, td := range topics {
+		var topicSize int64
+		for _, pd := range td.Partitions {
+			dirs, err := adm.DescribeBrokerLogDirs(ctx, pd.Leader, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			d, ok := dirs.LookupPartition(td.Topic, pd.Partition)
+			if !ok {
+				t.Fatalf("%s/%d not found on its leader %d", td.Topic, pd.Partition, pd.Leader)
+			}
+			if got, exp := u.Partitions[td.Topic][pd.Partition], 3*d.Size; got != exp {
+				t.Errorf("%s/%d: got usage %d, exp %d", td.Topic, pd.Partition, got, exp)
+			}
+			topicSize += 3 * d.Size
+		}
+		if u.Topics[td.Topic] != topicSize {
+			t.Errorf("%s: got usage %d, exp %d", td.Topic, u.Topics[td.Topic], topicSize)
+		}
+		topicsTotal += topicSize
+	}
+	var brokersTotal int64
+	for _, size := range u.Brokers {
+		brokersTotal += size
+	}
+	if len(u.Brokers) != 4 || u.Total != topicsTotal || u.Total != brokersTotal || u.Total == 0 {
+		t.Errorf("got total %d, topics total %d, and brokers total %d over %d brokers, exp equal non-zero totals over 4 brokers",
+			u.Total, topicsTotal, brokersTotal, len(u.Brokers))
+	}
+	if u.Topics["empty"] != 0 || u.Topics["small"] == 0 || u.Topics["big"] <= u.Topics["small"] {
+		t.Errorf("got topic usage %v, exp empty < small < big", u.Topics)
+	}
+
+	sorted := u.SortedTopicsBySize()
+	if len(sorted) != 3 || sorted[0].Topic != "empty" || sorted[1].Topic != "small" || sorted[2].Topic != "big" {
+		t.Errorf("got sorted topics %v, exp empty, small, big", sorted)
+	}
+	if skew := u.BrokerSkew(); skew <= 0 {
+		t.Errorf("got broker skew %v, exp brokers to differ", skew)
+	}
+
+	// Describing specific partitions only includes those partitions, but
+	// on every broker hosting them.
+	var s kadm.TopicsSet
+	s.Add("big", 1)
+	u, err = adm.DescribeLogDirUsage(ctx, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(u.Topics) != 1 || len(u.Partitions["big"]) != 1 || u.Total != u.Partitions["big"][1] || u.Total == 0 {
+		t.Errorf("got usage %+v, exp only big/1", u)
+	}
+}
+
+func TestLogDirUsageBrokerSkew(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		brokers map[int32]int64
+		exp     float64
+	}{
+		{"no brokers", nil, 0},
+		{"no usage", map[int32]int64{0: 0, 1: 0}, 0},
+		{"even", map[int32]int64{0: 100, 1: 100, 2: 100}, 0},
+		{"difference is the mean", map[int32]int64{0: 100, 1: 300}, 1},
+		{"one empty broker", map[int32]int64{0: 0, 1: 200, 2: 400}, 2},
+	} {
+		u := kadm.LogDirUsage{Brokers: test.brokers}
+		for _, size := range test.brokers {
+			u.Total += size
+		}
+		if got := u.BrokerSkew(); math.Abs(got-test.exp) > 1e-9 {
+			t.Errorf("%s: got skew %v, exp %v", test.name, got, test.exp)
+		}
+	}
+}
+
+func TestDescribeLogDirUsageShardErrors(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	adm, c := kadmtest.New(t,
+		kadmtest.ClusterOpts(kfake.NumBrokers(3)),
+		kadmtest.Topic("t", 1, nil),
+		kadmtest.Records(&kgo.Record{Topic: "t", Value: []byte("v")}),
+	)
+
+	// Brokers that fail are missing from the usage, and the others are
+	// still aggregated.
+	if err := c.PauseNode(2); err != nil {
+		t.Fatal(err)
+	}
+	defer c.ResumeNode(2)
+	shortCtx, shortCancel := context.WithTimeout(ctx, 500*time.Millisecond)
+	defer shortCancel()
+	u, err := adm.DescribeLogDirUsage(shortCtx, nil)
+	var se *kadm.ShardErrors
+	if !errors.As(err, &se) {
+		t.Fatalf("got err %v, exp shard errors", err)
+	}
+	if _, ok := u.Brokers[2]; ok || len(u.Brokers) != 2 || u.Total == 0 {
+		t.Errorf("got broker usage %v, exp only brokers 0 and 1", u.Brokers)
+	}
+}
@@ -0,0 +1,130 @@
+package kadm_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kfake"
+	"github.com/twmb/franz-go/pkg/kfake/kadmtest"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestElectLeaders(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	adm, c := kadmtest.New(t,
+		kadmtest.ClusterOpts(kfake.NumBrokers(3)),
+		kadmtest.Topic("t", 3, nil),
+	)
+
+	// Partition 0 is led by a replica other than its preferred replica,
+	// so only partition 0 needs an election.
+	topics, err := adm.ListTopics(ctx, "t")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p0 := topics["t"].Partitions[0]
+	preferred := p0.Replicas[0]
+	if err := c.MoveTopicPartition("t", 0, p0.Replicas[1]); err != nil {
+		t.Fatal(err)
+	}
+
+	var s kadm.TopicsSet
+	s.Add("t", 0, 1, 2)
+	rs, err := adm.ElectLeaders(ctx, kadm.ElectPreferredReplica, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Partitions already led by their preferred replica are not errors.
+	if err := rs.Error(); err != nil {
+		t.Errorf("got err %v, exp none", err)
+	}
+	sorted := rs.Sorted()
+	if len(sorted) != 3 {
+		t.Fatalf("got %d results, exp 3", len(sorted))
+	}
+	for i, r := range sorted {
+		if r.Topic != "t" || r.Partition != int32(i) || r.How != kadm.ElectPreferredReplica {
+			t.Errorf("got sorted result %d %+v, exp t/%d", i, r, i)
+		}
+		if exp := i == 0; (r.Err == nil) != exp || !exp && !errors.Is(r.Err, kerr.ElectionNotNeeded) {
+			t.Errorf("t/%d: got err %v, exp ELECTION_NOT_NEEDED unless elected", i, r.Err)
+		}
+	}
+	var n int
+	rs.Each(func(kadm.ElectLeadersResult) { n++ })
+	if n != 3 {
+		t.Errorf("got %d results from Each, exp 3", n)
+	}
+	topics, err = adm.ListTopics(ctx, "t")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if leader := topics["t"].Partitions[0].Leader; leader != preferred {
+		t.Errorf("got leader %d after electing, exp the preferred %d", leader, preferred)
+	}
+
+	// Lookup and On find results, and On returns fn's error.
+	if r, ok := rs.Lookup("t", 0); !ok || r.Partition != 0 {
+		t.Errorf("got %+v, %v looking up t/0, exp it", r, ok)
+	}
+	for _, missing := range []struct {
+		t string
+		p int32
+	}{{"t", 9}, {"missing", 0}} {
+		if _, ok := rs.Lookup(missing.t, missing.p); ok {
+			t.Errorf("found %s/%d, exp it missing", missing.t, missing.p)
+		}
+		if _, err := rs.On(missing.t, missing.p, nil); !errors.Is(err, kerr.UnknownTopicOrPartition) {
+			t.Errorf("got err %v from On %s/%d, exp UNKNOWN_TOPIC_OR_PARTITION", err, missing.t, missing.p)
+		}
+	}
+	if _, ok := kadm.ElectLeadersResults(nil).Lookup("t", 0); ok {
+		t.Error("found t/0 in nil results")
+	}
+	errFn := errors.New("fn error")
+	r, err := rs.On("t", 1, func(r *kadm.ElectLeadersResult) error {
+		r.ErrMessage = "modified"
+		return errFn
+	})
+	if !errors.Is(err, errFn) || r.ErrMessage != "modified" || rs["t"][1].ErrMessage == "modified" {
+		t.Errorf("got %+v, %v from On, exp fn's error and a modified copy", r, err)
+	}
+
+	// Electing everything only returns partitions that needed an
+	// election, and partitions that do not exist are errors.
+	if err := c.MoveTopicPartition("t", 2, topics["t"].Partitions[2].Replicas[2]); err != nil {
+		t.Fatal(err)
+	}
+	rs, err = adm.ElectLeaders(ctx, kadm.ElectPreferredReplica, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r, ok := rs.Lookup("t", 2); len(rs["t"]) != 1 || !ok || r.Err != nil {
+		t.Errorf("got %v electing all, exp only t/2 elected", rs.Sorted())
+	}
+	s.Add("t", 9)
+	rs, err = adm.ElectLeaders(ctx, kadm.ElectPreferredReplica, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rs.Error(); !errors.Is(err, kerr.UnknownTopicOrPartition) {
+		t.Errorf("got err %v with an unknown partition, exp UNKNOWN_TOPIC_OR_PARTITION", err)
+	}
+
+	// A top level error fails the request.
+	c.ControlKey(int16(kmsg.ElectLeaders), func(kreq kmsg.Request) (kmsg.Response, error, bool) {
+		resp := kreq.ResponseKind().(*kmsg.ElectLeadersResponse)
+		resp.ErrorCode = kerr.InvalidRequest.Code
+		return resp, nil, true
+	})
+	if _, err := adm.ElectLeaders(ctx, kadm.ElectPreferredReplica, s); !errors.Is(err, kerr.InvalidRequest) {
+		t.Errorf("got err %v, exp INVALID_REQUEST", err)
+	}
+}
@@ -0,0 +1,157 @@
+package kadm_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kfake"
+	"github.com/twmb/franz-go/pkg/kfake/kadmtest"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func TestDescribePartitionReassignments(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// With four brokers, the topic has three replicas, leaving one broker
+	// to reassign to. Reassignments stay in progress until completed.
+	adm, c := kadmtest.New(t,
+		kadmtest.ClusterOpts(kfake.NumBrokers(4), kfake.ReassignmentDelay(-1)),
+		kadmtest.Topic("t", 2, nil),
+		kadmtest.Records(&kgo.Record{Topic: "t", Value: []byte("v")}),
+	)
+
+	var s kadm.TopicsSet
+	s.Add("t", 0, 1)
+
+	// Nothing is being reassigned to start.
+	ps, err := adm.DescribePartitionReassignments(ctx, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ps.Done() || ps.Error() != nil || len(ps.Sorted()) != 2 {
+		t.Fatalf("got %+v, exp two done partitions without errors", ps.Sorted())
+	}
+	p0 := ps["t"][0]
+	if len(p0.Replicas) != 3 || !slices.Contains(p0.Replicas, p0.Leader) || len(p0.ISR) != 3 || len(p0.AddingReplicas) != 0 || p0.ReplicaLagBytes != nil {
+		t.Fatalf("got %+v, exp three in sync replicas including the leader and nothing being added", p0)
+	}
+
+	// Replace the last replica with the unused broker.
+	var unused int32
+	for b := int32(0); b < 4; b++ {
+		if !slices.Contains(p0.Replicas, b) {
+			unused = b
+		}
+	}
+	removed := p0.Replicas[2]
+	target := []int32{p0.Replicas[0], p0.Replicas[1], unused}
+	var req kadm.AlterPartitionAssignmentsReq
+	req.Assign("t", 0, target)
+	if as, err := adm.AlterPartitionAssignments(ctx, req); err != nil || as.Error() != nil {
+		t.Fatalf("unable to reassign: %v, %v", err, as.Error())
+	}
+
+	ps, err = adm.DescribePartitionReassignments(ctx, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ps.Done() || ps.Error() != nil {
+		t.Errorf("got done %v, err %v while reassigning, exp not done and no error", ps.Done(), ps.Error())
+	}
+	p0 = ps["t"][0]
+	if p0.Done || !slices.Equal(p0.AddingReplicas, []int32{unused}) || !slices.Equal(p0.RemovingReplicas, []int32{removed}) || len(p0.Replicas) != 4 {
+		t.Errorf("got %+v, exp adding %d and removing %d with four replicas", p0, unused, removed)
+	}
+	if len(p0.ReplicaLagBytes) != 1 || p0.ReplicaLagBytes[unused] != 0 {
+		t.Errorf("got replica lag %v, exp only the adding replica, caught up", p0.ReplicaLagBytes)
+	}
+	if p1 := ps["t"][1]; !p1.Done || len(p1.AddingReplicas) != 0 {
+		t.Errorf("got %+v, exp partition 1 done", p1)
+	}
+
+	// Unknown topics and partitions are errors per partition.
+	var unknown kadm.TopicsSet
+	unknown.Add("t", 9)
+	unknown.Add("missing", 0)
+	ps, err = adm.DescribePartitionReassignments(ctx, unknown)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ps.Each(func(p kadm.PartitionReassignmentProgress) {
+		if !errors.Is(p.Err, kerr.UnknownTopicOrPartition) || p.Leader != -1 || !p.Done {
+			t.Errorf("%s/%d: got %+v, exp UNKNOWN_TOPIC_OR_PARTITION", p.Topic, p.Partition, p)
+		}
+	})
+
+	if ps, err := adm.DescribePartitionReassignments(ctx, nil); err != nil || len(ps) != 0 {
+		t.Errorf("got %v, %v describing nothing, exp nothing", ps, err)
+	}
+
+	// Watching reports progress until the reassignment completes.
+	var calls int
+	err = adm.WatchPartitionReassignments(ctx, s, 10*time.Millisecond, func(ps kadm.PartitionReassignmentsProgress) {
+		calls++
+		if calls == 1 {
+			if ps.Done() {
+				t.Error("first watch progress is done, exp in progress")
+			}
+			c.CompleteReassignments()
+		}
+	})
+	if err != nil || calls != 2 {
+		t.Fatalf("got watch err %v after %d calls, exp nil after 2", err, calls)
+	}
+	ps, err = adm.DescribePartitionReassignments(ctx, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p0 := ps["t"][0]; !p0.Done || !slices.Equal(p0.Replicas, target) || !slices.Contains(target, p0.Leader) {
+		t.Errorf("got %+v after completing, exp replicas %v", p0, target)
+	}
+}
+
+func TestWatchPartitionReassignmentsCanceled(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	adm, _ := kadmtest.New(t,
+		kadmtest.ClusterOpts(kfake.NumBrokers(2), kfake.ReassignmentDelay(-1)),
+		kadmtest.Topic("t", 1, nil),
+	)
+	var req kadm.AlterPartitionAssignmentsReq
+	req.Assign("t", 0, []int32{0})
+	if as, err := adm.AlterPartitionAssignments(ctx, req); err != nil || as.Error() != nil {
+		t.Fatalf("unable to reassign: %v, %v", err, as.Error())
+	}
+
+	// Removing a replica completes immediately, so the watch returns
+	// after one call without waiting.
+	var s kadm.TopicsSet
+	s.Add("t", 0)
+	var calls int
+	if err := adm.WatchPartitionReassignments(ctx, s, time.Hour, func(kadm.PartitionReassignmentsProgress) { calls++ }); err != nil || calls != 1 {
+		t.Fatalf("got watch err %v after %d calls, exp nil after 1", err, calls)
+	}
+
+	// A reassignment that never completes is watched until canceled.
+	req.Assign("t", 0, []int32{1})
+	if as, err := adm.AlterPartitionAssignments(ctx, req); err != nil || as.Error() != nil {
+		t.Fatalf("unable to reassign: %v, %v", err, as.Error())
+	}
+	watchCtx, watchCancel := context.WithCancel(ctx)
+	calls = 0
+	err := adm.WatchPartitionReassignments(watchCtx, s, 10*time.Millisecond, func(ps kadm.PartitionReassignmentsProgress) {
+		if calls++; calls == 3 {
+			watchCancel()
+		}
+	})
+	if !errors.Is(err, context.Canceled) || calls != 3 {
+		t.Errorf("got watch err %v after %d calls, exp context.Canceled after 3", err, calls)
+	}
+}
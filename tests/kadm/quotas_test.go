@@ -0,0 +1,111 @@
+package kadm_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kfake/kadmtest"
+)
+
+func TestClientQuotaEntity(t *testing.T) {
+	e := kadm.NewClientQuotaEntity(kadm.QuotaUser("alice"), kadm.QuotaClientID("app"))
+	if e[0].Type != kadm.QuotaEntityClientID || e[1].Type != kadm.QuotaEntityUser {
+		t.Errorf("got entity %v, exp components sorted by type", e)
+	}
+	for _, test := range []struct {
+		name  string
+		other kadm.ClientQuotaEntity
+		exp   bool
+	}{
+		{"reordered", kadm.ClientQuotaEntity{kadm.QuotaUser("alice"), kadm.QuotaClientID("app")}, true},
+		{"other name", kadm.NewClientQuotaEntity(kadm.QuotaUser("bob"), kadm.QuotaClientID("app")), false},
+		{"default", kadm.NewClientQuotaEntity(kadm.QuotaUser("alice"), kadm.QuotaDefaultClientID()), false},
+		{"fewer components", kadm.NewClientQuotaEntity(kadm.QuotaUser("alice")), false},
+	} {
+		if got := e.Equal(test.other); got != test.exp {
+			t.Errorf("%s: got equal %v, exp %v", test.name, got, test.exp)
+		}
+	}
+}
+
+func TestClientQuotas(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	adm, _ := kadmtest.New(t)
+	userApp := kadm.NewClientQuotaEntity(kadm.QuotaUser("alice"), kadm.QuotaClientID("app"))
+	user := kadm.NewClientQuotaEntity(kadm.QuotaUser("alice"))
+	defaultUser := kadm.NewClientQuotaEntity(kadm.QuotaDefaultUser())
+	ip := kadm.NewClientQuotaEntity(kadm.QuotaIP("10.0.0.1"))
+
+	expValues := func(entity kadm.ClientQuotaEntity, exp map[string]float64) {
+		t.Helper()
+		q, err := adm.DescribeClientQuotasFor(ctx, entity)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !q.Entity.Equal(entity) {
+			t.Errorf("got entity %v, exp %v", q.Entity, entity)
+		}
+		if len(q.Values) != len(exp) {
+			t.Errorf("%v: got values %v, exp %v", entity, q.Values, exp)
+		}
+		for k, v := range exp {
+			if got, ok := q.Values.Get(k); !ok || got != v {
+				t.Errorf("%v: got %s %v (exists %v), exp %v", entity, k, got, ok, v)
+			}
+		}
+	}
+
+	if err := adm.AlterClientQuota(ctx, userApp, kadm.SetProducerByteRate(1024), kadm.SetRequestPercentage(200)); err != nil {
+		t.Fatal(err)
+	}
+	if err := adm.AlterClientQuota(ctx, defaultUser, kadm.SetConsumerByteRate(2048)); err != nil {
+		t.Fatal(err)
+	}
+	if err := adm.AlterClientQuota(ctx, ip, kadm.SetConnectionCreationRate(5)); err != nil {
+		t.Fatal(err)
+	}
+
+	// Describing matches the entity exactly: the user alone and the
+	// default user are separate from the user with a client ID.
+	expValues(userApp, map[string]float64{
+		kadm.QuotaProducerByteRate:  1024,
+		kadm.QuotaRequestPercentage: 200,
+	})
+	expValues(user, nil)
+	expValues(defaultUser, map[string]float64{kadm.QuotaConsumerByteRate: 2048})
+	expValues(ip, map[string]float64{kadm.QuotaConnectionCreationRate: 5})
+
+	// Removing one key keeps the others.
+	if err := adm.AlterClientQuota(ctx, userApp, kadm.RemoveQuota(kadm.QuotaRequestPercentage)); err != nil {
+		t.Fatal(err)
+	}
+	expValues(userApp, map[string]float64{kadm.QuotaProducerByteRate: 1024})
+
+	// Invalid alterations return the entity's error with its message and
+	// change nothing.
+	for _, test := range []struct {
+		name   string
+		entity kadm.ClientQuotaEntity
+		ops    []kadm.AlterClientQuotaOp
+	}{
+		{"connection rate for a user", user, []kadm.AlterClientQuotaOp{kadm.SetConnectionCreationRate(1)}},
+		{"byte rate for an ip", ip, []kadm.AlterClientQuotaOp{kadm.SetProducerByteRate(1)}},
+		{"non-positive value", userApp, []kadm.AlterClientQuotaOp{kadm.SetProducerByteRate(0)}},
+		{"unknown key", userApp, []kadm.AlterClientQuotaOp{kadm.SetQuota("unknown", 1)}},
+		{"ip with user", kadm.NewClientQuotaEntity(kadm.QuotaIP("10.0.0.1"), kadm.QuotaUser("alice")), []kadm.AlterClientQuotaOp{kadm.SetConnectionCreationRate(1)}},
+	} {
+		err := adm.AlterClientQuota(ctx, test.entity, test.ops...)
+		var em *kadm.ErrAndMessage
+		if !errors.As(err, &em) || !errors.Is(err, kerr.InvalidRequest) || em.ErrMessage == "" {
+			t.Errorf("%s: got err %v, exp INVALID_REQUEST with a message", test.name, err)
+		}
+	}
+	expValues(userApp, map[string]float64{kadm.QuotaProducerByteRate: 1024})
+	expValues(ip, map[string]float64{kadm.QuotaConnectionCreationRate: 5})
+}
@@ -0,0 +1,113 @@
+package kadm_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kfake/kadmtest"
+)
+
+func TestApplyTopics(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	retention := "1000"
+	cleanup := "compact"
+	adm, _ := kadmtest.New(t,
+		kadmtest.Topic("keep", 3, map[string]*string{"retention.ms": &retention}),
+		kadmtest.Topic("grow", 2, nil),
+		kadmtest.Topic("shrink", 4, nil),
+	)
+
+	specs := []kadm.TopicSpec{
+		{Topic: "keep", Partitions: 0, Configs: map[string]*string{"retention.ms": &retention}},
+		{Topic: "grow", Partitions: 5, Configs: map[string]*string{"cleanup.policy": &cleanup}},
+		{Topic: "shrink", Partitions: -1},
+		{Topic: "new", Partitions: 0, ReplicationFactor: 0},
+		{Topic: "new-sized", Partitions: 2, ReplicationFactor: 1},
+	}
+
+	// A dry run plans changes without making them.
+	plans, err := adm.ApplyTopics(ctx, true, specs...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := plans.Error(); err != nil {
+		t.Fatalf("unexpected plan error: %v", err)
+	}
+	exp := map[string]struct {
+		create  bool
+		adds    bool
+		configs int
+		parts   int32
+	}{
+		"grow":      {false, true, 1, 5},
+		"keep":      {false, false, 0, -1},
+		"new":       {true, false, 0, -1},
+		"new-sized": {true, false, 0, 2},
+		"shrink":    {false, false, 0, -1},
+	}
+	for _, p := range plans {
+		e := exp[p.Topic]
+		if p.Create != e.create || p.AddsPartitions() != e.adds || len(p.Configs) != e.configs || p.Partitions != e.parts {
+			t.Errorf("%s: got create %v, adds partitions %v, %d config changes, partitions %d; exp %v, %v, %d, %d",
+				p.Topic, p.Create, p.AddsPartitions(), len(p.Configs), p.Partitions, e.create, e.adds, e.configs, e.parts)
+		}
+	}
+	if changed := plans.Changed(); len(changed) != 3 {
+		t.Errorf("got %d changed plans, exp 3 (grow, new, new-sized)", len(changed))
+	}
+	if tds, err := adm.ListTopics(ctx, "new"); err != nil || tds.Has("new") {
+		t.Fatalf("dry run created a topic: %v", err)
+	}
+
+	// Applying makes the changes, after which nothing is left to do.
+	if plans, err = adm.ApplyTopics(ctx, false, specs...); err != nil || plans.Error() != nil {
+		t.Fatalf("unable to apply: %v, %v", err, plans.Error())
+	}
+	tds, err := adm.ListTopics(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for topic, partitions := range map[string]int{"keep": 3, "grow": 5, "shrink": 4, "new": 10, "new-sized": 2} {
+		if got := len(tds[topic].Partitions); got != partitions {
+			t.Errorf("%s: got %d partitions, exp %d", topic, got, partitions)
+		}
+	}
+	if plans, err = adm.ApplyTopics(ctx, true, specs...); err != nil {
+		t.Fatal(err)
+	}
+	if changed := plans.Changed(); len(changed) != 0 {
+		t.Errorf("got changes after applying: %v", changed)
+	}
+
+	// Partitions cannot be removed, and a nil config deletes a config set
+	// on the topic.
+	plans, err = adm.ApplyTopics(ctx, false,
+		kadm.TopicSpec{Topic: "shrink", Partitions: 2},
+		kadm.TopicSpec{Topic: "keep", Configs: map[string]*string{"retention.ms": nil}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := plans.Error(); err == nil || !strings.Contains(err.Error(), "cannot decrease partitions from 4 to 2") {
+		t.Errorf("got err %v, exp one for decreasing partitions", err)
+	}
+	rcs, err := adm.DescribeTopicConfigs(ctx, "keep")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rc, _ := rcs.On("keep", nil)
+	for _, c := range rc.Configs {
+		if c.Key == "retention.ms" && c.MaybeValue() == retention {
+			t.Error("retention.ms was not deleted")
+		}
+	}
+
+	if _, err := adm.ApplyTopics(ctx, true, kadm.TopicSpec{Topic: "a"}, kadm.TopicSpec{Topic: "a"}); err == nil {
+		t.Error("expected an error for duplicate specs")
+	}
+}
@@ -0,0 +1,270 @@
+package kadm_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kfake/kadmtest"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// txnState is the producer and transaction state served by the handlers that
+// txnCluster registers, since kfake does not implement DescribeProducers,
+// ListTransactions, DescribeTransactions, or WriteTxnMarkers.
+type txnState struct {
+	mu sync.Mutex
+
+	producers map[int32][]kmsg.DescribeProducersResponseTopicPartitionActiveProducer // t's partition => producers
+	txns      []kmsg.DescribeTransactionsResponseTransactionState
+
+	listedPIDs []int64                             // producer ID filters of the last ListTransactions
+	markers    []kmsg.WriteTxnMarkersRequestMarker // every written marker
+	markerErr  int16                               // error code for every marker partition
+}
+
+func txnCluster(t *testing.T, s *txnState) *kadm.Client {
+	_, c := kadmtest.New(t, kadmtest.Topic("t", 2, nil))
+
+	c.RegisterHandler(int16(kmsg.DescribeProducers), func(kreq kmsg.Request) (kmsg.Response, error) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		req := kreq.(*kmsg.DescribeProducersRequest)
+		resp := req.ResponseKind().(*kmsg.DescribeProducersResponse)
+		for _, rt := range req.Topics {
+			st := kmsg.NewDescribeProducersResponseTopic()
+			st.Topic = rt.Topic
+			for _, p := range rt.Partitions {
+				sp := kmsg.NewDescribeProducersResponseTopicPartition()
+				sp.Partition = p
+				sp.ActiveProducers = s.producers[p]
+				st.Partitions = append(st.Partitions, sp)
+			}
+			resp.Topics = append(resp.Topics, st)
+		}
+		return resp, nil
+	})
+	c.RegisterHandler(int16(kmsg.ListTransactions), func(kreq kmsg.Request) (kmsg.Response, error) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		req := kreq.(*kmsg.ListTransactionsRequest)
+		resp := req.ResponseKind().(*kmsg.ListTransactionsResponse)
+		s.listedPIDs = append([]int64(nil), req.ProducerIDFilters...)
+		for _, txn := range s.txns {
+			if len(req.ProducerIDFilters) > 0 && !slices.Contains(req.ProducerIDFilters, txn.ProducerID) {
+				continue
+			}
+			st := kmsg.NewListTransactionsResponseTransactionState()
+			st.TransactionalID = txn.TransactionalID
+			st.ProducerID = txn.ProducerID
+			st.TransactionState = txn.State
+			resp.TransactionStates = append(resp.TransactionStates, st)
+		}
+		return resp, nil
+	})
+	c.RegisterHandler(int16(kmsg.DescribeTransactions), func(kreq kmsg.Request) (kmsg.Response, error) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		req := kreq.(*kmsg.DescribeTransactionsRequest)
+		resp := req.ResponseKind().(*kmsg.DescribeTransactionsResponse)
+		for _, id := range req.TransactionalIDs {
+			st := kmsg.NewDescribeTransactionsResponseTransactionState()
+			st.TransactionalID = id
+			st.ErrorCode = kerr.TransactionalIDNotFound.Code
+			for _, txn := range s.txns {
+				if txn.TransactionalID == id {
+					st = txn
+				}
+			}
+			resp.TransactionStates = append(resp.TransactionStates, st)
+		}
+		return resp, nil
+	})
+	c.RegisterHandler(int16(kmsg.WriteTxnMarkers), func(kreq kmsg.Request) (kmsg.Response, error) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		req := kreq.(*kmsg.WriteTxnMarkersRequest)
+		resp := req.ResponseKind().(*kmsg.WriteTxnMarkersResponse)
+		for _, m := range req.Markers {
+			s.markers = append(s.markers, m)
+			sm := kmsg.NewWriteTxnMarkersResponseMarker()
+			sm.ProducerID = m.ProducerID
+			for _, rt := range m.Topics {
+				st := kmsg.NewWriteTxnMarkersResponseMarkerTopic()
+				st.Topic = rt.Topic
+				for _, p := range rt.Partitions {
+					sp := kmsg.NewWriteTxnMarkersResponseMarkerTopicPartition()
+					sp.Partition = p
+					sp.ErrorCode = s.markerErr
+					st.Partitions = append(st.Partitions, sp)
+				}
+				sm.Topics = append(sm.Topics, st)
+			}
+			resp.Markers = append(resp.Markers, sm)
+		}
+		return resp, nil
+	})
+
+	// The handled keys are only advertised to clients that connect after
+	// registering.
+	return kadm.NewClient(c.NewTestClient(t))
+}
+
+func activeProducer(pid int64, epoch int16, lastTimestamp, txnStart int64) kmsg.DescribeProducersResponseTopicPartitionActiveProducer {
+	p := kmsg.NewDescribeProducersResponseTopicPartitionActiveProducer()
+	p.ProducerID = pid
+	p.ProducerEpoch = int32(epoch)
+	p.LastTimestamp = lastTimestamp
+	p.CoordinatorEpoch = -1
+	p.CurrentTxnStartOffset = txnStart
+	return p
+}
+
+func txnDescription(id string, pid int64, epoch int16, state string, partitions ...int32) kmsg.DescribeTransactionsResponseTransactionState {
+	st := kmsg.NewDescribeTransactionsResponseTransactionState()
+	st.TransactionalID = id
+	st.ProducerID = pid
+	st.ProducerEpoch = epoch
+	st.State = state
+	if len(partitions) > 0 {
+		rt := kmsg.NewDescribeTransactionsResponseTransactionStateTopic()
+		rt.Topic = "t"
+		rt.Partitions = partitions
+		st.Topics = append(st.Topics, rt)
+	}
+	return st
+}
+
+func TestFindHangingTransactions(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	old := time.Now().Add(-time.Hour).UnixMilli()
+	recent := time.Now().UnixMilli()
+	s := &txnState{
+		producers: map[int32][]kmsg.DescribeProducersResponseTopicPartitionActiveProducer{
+			0: {
+				activeProducer(1, 0, old, 5),    // untracked: hanging
+				activeProducer(2, 0, old, 6),    // ongoing in its transaction: not hanging
+				activeProducer(3, 3, old, 7),    // its transactional ID moved to a new epoch: hanging
+				activeProducer(4, 0, recent, 8), // not open long enough
+				activeProducer(5, 0, old, -1),   // no open transaction
+				activeProducer(7, 0, old, 9),    // its transaction no longer includes the partition: hanging
+			},
+			1: {
+				activeProducer(6, 0, old, 2), // its transaction completed: hanging
+			},
+		},
+		txns: []kmsg.DescribeTransactionsResponseTransactionState{
+			txnDescription("live", 2, 0, "Ongoing", 0),
+			txnDescription("moved", 3, 4, "Ongoing", 0),
+			txnDescription("done", 6, 0, "CompleteCommit"),
+			txnDescription("elsewhere", 7, 0, "Ongoing", 1),
+			txnDescription("unrelated", 8, 0, "Ongoing", 0),
+		},
+	}
+	adm := txnCluster(t, s)
+
+	hanging, err := adm.FindHangingTransactions(ctx, nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	type found struct {
+		partition int32
+		pid       int64
+		txnID     string
+		state     string
+	}
+	var got []found
+	for _, h := range hanging {
+		if h.Producer.Topic != "t" {
+			t.Errorf("got hanging transaction on topic %q, exp t", h.Producer.Topic)
+		}
+		got = append(got, found{h.Producer.Partition, h.Producer.ProducerID, h.TxnID, h.TxnState})
+	}
+	exp := []found{
+		{0, 1, "", ""},
+		{0, 3, "moved", "Ongoing"},
+		{0, 7, "elsewhere", "Ongoing"},
+		{1, 6, "done", "CompleteCommit"},
+	}
+	if !slices.Equal(got, exp) {
+		t.Errorf("got hanging %+v, exp %+v", got, exp)
+	}
+
+	// Only the candidates' producer IDs are listed.
+	s.mu.Lock()
+	listed := append([]int64(nil), s.listedPIDs...)
+	s.mu.Unlock()
+	slices.Sort(listed)
+	if exp := []int64{1, 2, 3, 6, 7}; !slices.Equal(listed, exp) {
+		t.Errorf("listed transactions for producer IDs %v, exp %v", listed, exp)
+	}
+
+	// Nothing is hanging if transactions can be open longer, and a
+	// narrower set only checks its partitions.
+	if hanging, err := adm.FindHangingTransactions(ctx, nil, 2*time.Hour); err != nil || len(hanging) != 0 {
+		t.Errorf("got %v, %v with a two hour timeout, exp nothing hanging", hanging, err)
+	}
+	var p1 kadm.TopicsSet
+	p1.Add("t", 1)
+	hanging, err = adm.FindHangingTransactions(ctx, p1, 0)
+	if err != nil || len(hanging) != 1 || hanging[0].Producer.ProducerID != 6 {
+		t.Errorf("got %+v, %v checking partition 1, exp only producer 6", hanging, err)
+	}
+}
+
+func TestAbortTransaction(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	s := &txnState{
+		producers: map[int32][]kmsg.DescribeProducersResponseTopicPartitionActiveProducer{
+			1: {activeProducer(1, 2, time.Now().Add(-time.Hour).UnixMilli(), 5)},
+		},
+	}
+	adm := txnCluster(t, s)
+	hanging, err := adm.FindHangingTransactions(ctx, nil, 0)
+	if err != nil || len(hanging) != 1 {
+		t.Fatalf("got %+v, %v, exp one hanging transaction", hanging, err)
+	}
+
+	// The abort marker is written for the producer's epoch to only the
+	// producer's partition, with the lowest coordinator epoch since the
+	// producer has none.
+	if err := adm.AbortTransaction(ctx, hanging[0].Producer); err != nil {
+		t.Fatal(err)
+	}
+	s.mu.Lock()
+	markers := s.markers
+	s.mu.Unlock()
+	if len(markers) != 1 {
+		t.Fatalf("got %d markers, exp 1", len(markers))
+	}
+	m := markers[0]
+	if m.ProducerID != 1 || m.ProducerEpoch != 2 || m.Committed || m.CoordinatorEpoch != 0 ||
+		len(m.Topics) != 1 || m.Topics[0].Topic != "t" || !slices.Equal(m.Topics[0].Partitions, []int32{1}) {
+		t.Errorf("got marker %+v, exp an abort for producer 1 epoch 2 on t/1 with coordinator epoch 0", m)
+	}
+
+	// A known coordinator epoch is kept, and partition errors are
+	// returned.
+	p := hanging[0].Producer
+	p.CoordinatorEpoch = 3
+	s.mu.Lock()
+	s.markerErr = kerr.InvalidProducerEpoch.Code
+	s.mu.Unlock()
+	if err := adm.AbortTransaction(ctx, p); !errors.Is(err, kerr.InvalidProducerEpoch) {
+		t.Errorf("got err %v, exp INVALID_PRODUCER_EPOCH", err)
+	}
+	s.mu.Lock()
+	if n := len(s.markers); n != 2 || s.markers[1].CoordinatorEpoch != 3 {
+		t.Errorf("got markers %+v, exp a second marker with coordinator epoch 3", s.markers)
+	}
+	s.mu.Unlock()
+}
@@ -0,0 +1,199 @@
+package kgo_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func TestBatchConsumer(t *testing.T) {
+	t.Parallel()
+
+	const n = 5
+	c := newChanCluster(t, n)
+
+	var (
+		mu       sync.Mutex
+		batches  = make(map[int32][][]int64)
+		failedAt time.Time
+		retryGap time.Duration
+	)
+	b, err := kgo.NewBatchConsumer(func(_ context.Context, p kgo.FetchTopicPartition) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if p.Partition == 1 && failedAt.IsZero() {
+			failedAt = time.Now()
+			return errors.New("fail the first batch once")
+		}
+		if p.Partition == 1 && retryGap == 0 {
+			retryGap = time.Since(failedAt)
+		}
+		var offsets []int64
+		for _, r := range p.Records {
+			offsets = append(offsets, r.Offset)
+		}
+		batches[p.Partition] = append(batches[p.Partition], offsets)
+		return nil
+	},
+		kgo.SeedBrokers(c.ListenAddrs()...),
+		kgo.ConsumerGroup("g"),
+		kgo.ConsumeTopics("t"),
+		kgo.FetchMaxPartitionBytes(1), // few record batches per partition per fetch
+		kgo.RetryBackoffFn(func(int) time.Duration { return 100 * time.Millisecond }),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runErr := make(chan error, 1)
+	go func() { runErr <- b.Run(ctx) }()
+
+	// Every successful batch is committed once it is processed.
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		committed := b.Client().CommittedOffsets()["t"]
+		if committed[0].Offset == n && committed[1].Offset == n {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for every batch to be committed, committed: %v", committed)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	if err := <-runErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("got Run err %v, exp context.Canceled", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	// Batches are limited by the client's fetch sizes: with a one byte
+	// partition limit, a fetch returns at most a couple of single record
+	// batches per partition, so every partition is processed in several
+	// small batches, each in order and continuing the previous one.
+	for p := int32(0); p < 2; p++ {
+		var next int64
+		for _, batch := range batches[p] {
+			if len(batch) == 0 || len(batch) > 2 {
+				t.Fatalf("partition %d: got batches %v, exp one or two records per batch", p, batches[p])
+			}
+			for _, o := range batch {
+				if o != next {
+					t.Fatalf("partition %d: got batches %v, exp contiguous offsets", p, batches[p])
+				}
+				next++
+			}
+		}
+		if len(batches[p]) < 2 || next != n {
+			t.Errorf("partition %d: got batches %v, exp all %d records over several batches", p, batches[p], n)
+		}
+	}
+
+	// A failed batch is retried after the client's retry backoff.
+	if retryGap < 100*time.Millisecond {
+		t.Errorf("failed batch was retried after %v, exp at least the 100ms backoff", retryGap)
+	}
+}
+
+func TestBatchConsumerRevokeLeavesFailedBatch(t *testing.T) {
+	t.Parallel()
+
+	c := newChanCluster(t, 1)
+	opts := []kgo.Opt{
+		kgo.SeedBrokers(c.ListenAddrs()...),
+		kgo.ConsumerGroup("g"),
+		kgo.ConsumeTopics("t"),
+		kgo.RetryBackoffFn(func(int) time.Duration { return 10 * time.Millisecond }),
+	}
+
+	// The first consumer always fails partition 0 and succeeds on
+	// partition 1.
+	var (
+		mu       sync.Mutex
+		attempts int
+		failing  = make(chan struct{})
+		once     sync.Once
+	)
+	b, err := kgo.NewBatchConsumer(func(_ context.Context, p kgo.FetchTopicPartition) error {
+		if p.Partition == 1 {
+			return nil
+		}
+		mu.Lock()
+		attempts++
+		mu.Unlock()
+		once.Do(func() { close(failing) })
+		return errors.New("always fail")
+	}, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	runErr := make(chan error, 1)
+	go func() { runErr <- b.Run(context.Background()) }()
+	select {
+	case <-failing:
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for processing to fail")
+	}
+
+	// Closing revokes every partition: the failing batch stops being
+	// retried and is left uncommitted.
+	b.Close()
+	if err := <-runErr; !errors.Is(err, kgo.ErrClientClosed) {
+		t.Errorf("got Run err %v, exp ErrClientClosed", err)
+	}
+	mu.Lock()
+	stopped := attempts
+	mu.Unlock()
+	time.Sleep(50 * time.Millisecond)
+	mu.Lock()
+	if attempts != stopped {
+		t.Errorf("failed batch was retried after its partition was revoked")
+	}
+	mu.Unlock()
+
+	// The next consumer in the group reprocesses partition 0 from the
+	// start, and does not see the committed partition 1.
+	got := make(chan kgo.FetchTopicPartition, 10)
+	b2, err := kgo.NewBatchConsumer(func(_ context.Context, p kgo.FetchTopicPartition) error {
+		got <- p
+		return nil
+	}, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b2.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go b2.Run(ctx)
+	select {
+	case p := <-got:
+		if p.Partition != 0 || p.Records[0].Offset != 0 {
+			t.Errorf("got %d/%d, exp the uncommitted 0/0", p.Partition, p.Records[0].Offset)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for the uncommitted batch to be reprocessed")
+	}
+	select {
+	case p := <-got:
+		t.Errorf("got unexpected batch for partition %d", p.Partition)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestBatchConsumerRequiresGroup(t *testing.T) {
+	_, err := kgo.NewBatchConsumer(func(context.Context, kgo.FetchTopicPartition) error { return nil },
+		kgo.SeedBrokers("localhost:9092"),
+		kgo.ConsumeTopics("t"),
+	)
+	if err == nil || !strings.Contains(err.Error(), "missing required group") {
+		t.Errorf("got err %v, exp missing required group", err)
+	}
+}
@@ -0,0 +1,102 @@
+package kgo_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kfake"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func brokerState(t *testing.T, cl *kgo.Client, node int32) kgo.BrokerState {
+	t.Helper()
+	for _, s := range cl.BrokerStates() {
+		if s.Meta.NodeID == node {
+			return s
+		}
+	}
+	t.Fatalf("broker %d not found in broker states", node)
+	return kgo.BrokerState{}
+}
+
+func TestBrokerStates(t *testing.T) {
+	t.Parallel()
+
+	c := kfake.NewTestCluster(t, kfake.NumBrokers(3))
+	cl := c.NewTestClient(t)
+	ctx := context.Background()
+
+	// Before any request, only seeds are known and nothing is connected.
+	states := cl.BrokerStates()
+	if len(states) != 3 {
+		t.Fatalf("got %d broker states before any request, exp the 3 seeds", len(states))
+	}
+	for _, s := range states {
+		if s.Meta.NodeID >= 0 || s.OpenConnections != 0 || !s.LastSuccess.IsZero() || s.LastError != nil || !s.Healthy() {
+			t.Errorf("got seed state %+v, exp an unconnected healthy seed", s)
+		}
+	}
+
+	// Discovering brokers adds them; requests to a broker open a
+	// connection and record the success.
+	cl.ForceMetadataRefresh()
+	waitFor(t, "brokers to be discovered", func() bool { return len(cl.BrokerStates()) == 6 })
+	before := time.Now()
+	if _, err := cl.Broker(1).Request(ctx, kmsg.NewPtrListGroupsRequest()); err != nil {
+		t.Fatal(err)
+	}
+	s := brokerState(t, cl, 1)
+	if s.OpenConnections < 1 || s.LastSuccess.Before(before) || s.InflightRequests != 0 || !s.Healthy() {
+		t.Errorf("got %+v after a request, exp a connected healthy broker with nothing in flight", s)
+	}
+
+	// Requests waiting on the broker are in flight.
+	release := make(chan struct{})
+	c.ControlKey(int16(kmsg.ListGroups), func(kmsg.Request) (kmsg.Response, error, bool) {
+		c.SleepControl(func() { <-release })
+		return nil, nil, false
+	})
+	done := make(chan error, 1)
+	go func() {
+		_, err := cl.Broker(1).Request(ctx, kmsg.NewPtrListGroupsRequest())
+		done <- err
+	}()
+	waitFor(t, "the request to be in flight", func() bool { return brokerState(t, cl, 1).InflightRequests == 1 })
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if s := brokerState(t, cl, 1); s.InflightRequests != 0 {
+		t.Errorf("got %d requests in flight after the response, exp 0", s.InflightRequests)
+	}
+
+	// A broker closing the connection is an error that makes the broker
+	// unhealthy until the next successful response.
+	c.ControlKey(int16(kmsg.ListGroups), func(kmsg.Request) (kmsg.Response, error, bool) {
+		return nil, errors.New("close the connection"), true
+	})
+	if _, err := cl.Broker(2).Request(ctx, kmsg.NewPtrListGroupsRequest()); err == nil {
+		t.Fatal("expected an error from the closed connection")
+	}
+	s = brokerState(t, cl, 2)
+	if s.LastError == nil || s.LastErrorTime.Before(before) || s.Healthy() {
+		t.Errorf("got %+v after the connection was closed, exp an unhealthy broker", s)
+	}
+	if _, err := cl.Broker(2).Request(ctx, kmsg.NewPtrListGroupsRequest()); err != nil {
+		t.Fatal(err)
+	}
+	if s = brokerState(t, cl, 2); s.LastError == nil || !s.Healthy() {
+		t.Errorf("got %+v after a successful request, exp the last error kept but the broker healthy", s)
+	}
+
+	// Canceled requests are not errors.
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	cl.Broker(0).Request(canceled, kmsg.NewPtrListGroupsRequest())
+	if s := brokerState(t, cl, 0); s.LastError != nil {
+		t.Errorf("got last error %v from a canceled request, exp none", s.LastError)
+	}
+}
@@ -0,0 +1,196 @@
+package kgo_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kfake"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// limitedProducer produces to a two partition topic "t" on a cluster that
+// can hold produce responses, so that records stay buffered.
+type limitedProducer struct {
+	c  *kfake.Cluster
+	cl *kgo.Client
+
+	mu      sync.Mutex
+	hold    bool
+	release chan struct{}
+}
+
+func newLimitedProducer(t *testing.T, opts ...kgo.Opt) *limitedProducer {
+	t.Helper()
+	c := kfake.NewTestCluster(t, kfake.NumBrokers(1), kfake.SeedTopics(2, "t"))
+	p := &limitedProducer{c: c, release: make(chan struct{})}
+	c.ControlKey(int16(kmsg.Produce), func(kmsg.Request) (kmsg.Response, error, bool) {
+		c.KeepControl()
+		p.mu.Lock()
+		hold, release := p.hold, p.release
+		p.mu.Unlock()
+		if hold {
+			c.SleepControl(func() { <-release })
+		}
+		return nil, nil, false
+	})
+	p.cl = c.NewTestClient(t, append([]kgo.Opt{
+		kgo.DefaultProduceTopic("t"),
+		kgo.RecordPartitioner(kgo.ManualPartitioner()),
+		kgo.ProducerLinger(0),
+	}, opts...)...)
+	return p
+}
+
+func (p *limitedProducer) holdProduces() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.hold = true
+}
+
+func (p *limitedProducer) releaseProduces() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.hold = false
+	close(p.release)
+	p.release = make(chan struct{})
+}
+
+// produce produces a ten byte record to partition, returning a channel that
+// receives the record's produce error.
+func (p *limitedProducer) produce(partition int32) <-chan error {
+	done := make(chan error, 1)
+	p.cl.Produce(context.Background(), &kgo.Record{Partition: partition, Value: []byte("0123456789")}, func(_ *kgo.Record, err error) {
+		done <- err
+	})
+	return done
+}
+
+func waitProduced(t *testing.T, done <-chan error) error {
+	t.Helper()
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for a produce to finish")
+		return nil
+	}
+}
+
+func TestMaxBufferedBytesPerPartitionFinished(t *testing.T) {
+	t.Parallel()
+
+	p := newLimitedProducer(t, kgo.MaxBufferedBytesPerPartition(15))
+	if err := waitProduced(t, p.produce(0)); err != nil { // load metadata
+		t.Fatal(err)
+	}
+
+	// While the first record is buffered, a second to the same partition
+	// would exceed the limit and fails immediately, but a record to a
+	// different partition is buffered.
+	p.holdProduces()
+	first := p.produce(0)
+	if err := waitProduced(t, p.produce(0)); !errors.Is(err, kgo.ErrMaxBufferedPartition) {
+		t.Fatalf("got %v, exp ErrMaxBufferedPartition", err)
+	}
+	other := p.produce(1)
+
+	// Finishing the batch frees its bytes.
+	p.releaseProduces()
+	for _, done := range []<-chan error{first, other} {
+		if err := waitProduced(t, done); err != nil {
+			t.Fatal(err)
+		}
+	}
+	p.holdProduces()
+	first = p.produce(0)
+	if err := waitProduced(t, p.produce(0)); !errors.Is(err, kgo.ErrMaxBufferedPartition) {
+		t.Fatalf("got %v, exp ErrMaxBufferedPartition", err)
+	}
+	p.releaseProduces()
+	if err := waitProduced(t, first); err != nil {
+		t.Fatal(err)
+	}
+	if err := waitProduced(t, p.produce(0)); err != nil {
+		t.Fatalf("produce after the buffer drained: %v", err)
+	}
+}
+
+func TestMaxBufferedBytesPerTopic(t *testing.T) {
+	t.Parallel()
+
+	var limited []int32
+	var mu sync.Mutex
+	p := newLimitedProducer(t,
+		kgo.MaxBufferedBytesPerTopic(15),
+		kgo.WithHooks(bufferLimitedHook(func(_ string, partition int32) {
+			mu.Lock()
+			defer mu.Unlock()
+			limited = append(limited, partition)
+		})),
+	)
+	if err := waitProduced(t, p.produce(0)); err != nil {
+		t.Fatal(err)
+	}
+
+	p.holdProduces()
+	first := p.produce(0)
+	if err := waitProduced(t, p.produce(1)); !errors.Is(err, kgo.ErrMaxBufferedPartition) {
+		t.Fatalf("got %v, exp ErrMaxBufferedPartition", err)
+	}
+	p.releaseProduces()
+	if err := waitProduced(t, first); err != nil {
+		t.Fatal(err)
+	}
+	if err := waitProduced(t, p.produce(1)); err != nil {
+		t.Fatalf("produce after the buffer drained: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(limited) != 1 || limited[0] != 1 {
+		t.Errorf("got buffer limited hook calls for partitions %v, exp [1]", limited)
+	}
+}
+
+func TestMaxBufferedBytesPerPartitionFailed(t *testing.T) {
+	t.Parallel()
+
+	p := newLimitedProducer(t, kgo.MaxBufferedBytesPerPartition(15))
+	if err := waitProduced(t, p.produce(0)); err != nil {
+		t.Fatal(err)
+	}
+
+	// A batch failed by the broker fails every record buffered for the
+	// partition, freeing their bytes.
+	p.c.RejectProducedRecords("t", 0, func(*kmsg.Record) string { return "no" })
+	if err := waitProduced(t, p.produce(0)); !errors.Is(err, kerr.InvalidRecord) {
+		t.Fatalf("got %v, exp INVALID_RECORD", err)
+	}
+	p.c.ClearProduceFaults()
+	if err := waitProduced(t, p.produce(0)); err != nil {
+		t.Fatalf("produce after a failed batch: %v", err)
+	}
+
+	// Purging fails every buffered record, freeing their bytes.
+	p.holdProduces()
+	first := p.produce(0)
+	p.cl.PurgeTopicsFromProducing("t")
+	if err := waitProduced(t, first); err == nil {
+		t.Fatal("unexpected success producing a purged record")
+	}
+	p.releaseProduces()
+	if err := waitProduced(t, p.produce(0)); err != nil {
+		t.Fatalf("produce after purging: %v", err)
+	}
+}
+
+type bufferLimitedHook func(string, int32)
+
+func (fn bufferLimitedHook) OnProduceBufferLimited(topic string, partition int32) {
+	fn(topic, partition)
+}
@@ -0,0 +1,82 @@
+package kgo_test
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/twmb/franz-go/pkg/kfake"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestProducerTopicCompression(t *testing.T) {
+	t.Parallel()
+
+	topics := []string{"default", "zstd", "none", "fallback"}
+	c := kfake.NewTestCluster(t, kfake.NumBrokers(1), kfake.SeedTopics(1, topics...))
+
+	// Record the codec of every batch produced to each topic, as written
+	// on the wire.
+	var (
+		mu     sync.Mutex
+		codecs = make(map[string][]int16)
+	)
+	c.ControlKey(int16(kmsg.Produce), func(kreq kmsg.Request) (kmsg.Response, error, bool) {
+		c.KeepControl()
+		req := kreq.(*kmsg.ProduceRequest)
+		mu.Lock()
+		defer mu.Unlock()
+		for _, rt := range req.Topics {
+			for _, rp := range rt.Partitions {
+				var b kmsg.RecordBatch
+				if err := b.ReadFrom(rp.Records); err != nil {
+					t.Errorf("unable to read produced batch: %v", err)
+					continue
+				}
+				codecs[rt.Topic] = append(codecs[rt.Topic], b.Attributes&0x07)
+			}
+		}
+		return nil, nil, false
+	})
+
+	cl := c.NewTestClient(t,
+		kgo.ProducerBatchCompression(kgo.SnappyCompression()),
+		kgo.ProducerTopicCompression("zstd", kgo.ZstdCompression()),
+		kgo.ProducerTopicCompression("none", kgo.NoCompression()),
+		kgo.ProducerTopicCompression("fallback", kgo.Lz4Compression()),
+		kgo.ProducerTopicCompression("fallback", kgo.GzipCompression()), // the last use for a topic wins
+		kgo.ConsumeTopics(topics...),
+	)
+
+	// Values must be compressible, or the client writes them uncompressed.
+	value := bytes.Repeat([]byte("compress me "), 100)
+	var rs []*kgo.Record
+	for _, topic := range topics {
+		rs = append(rs, &kgo.Record{Topic: topic, Value: value})
+	}
+	if err := cl.ProduceSync(context.Background(), rs...).FirstErr(); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	for topic, exp := range map[string]int16{
+		"default":  2, // snappy
+		"zstd":     4,
+		"none":     0,
+		"fallback": 1, // gzip
+	} {
+		if got := codecs[topic]; len(got) != 1 || got[0] != exp {
+			t.Errorf("%s: got batch codecs %v, exp [%d]", topic, got, exp)
+		}
+	}
+	mu.Unlock()
+
+	// Every topic's records are decompressed when consumed.
+	for _, r := range pollRecords(t, cl, len(topics)) {
+		if !bytes.Equal(r.Value, value) {
+			t.Errorf("%s: consumed value does not match the produced value", r.Topic)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package kgo_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kfake"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// newChanCluster returns a cluster with topic "t" having two partitions of n
+// records each, whose values are their offsets.
+func newChanCluster(t *testing.T, n int) *kfake.Cluster {
+	t.Helper()
+	c := kfake.NewTestCluster(t, kfake.NumBrokers(1), kfake.SeedTopics(2, "t"))
+	for p := int32(0); p < 2; p++ {
+		for i := 0; i < n; i++ {
+			if _, err := c.ProduceTo("t", p, &kgo.Record{Value: []byte(strconv.Itoa(i))}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	return c
+}
+
+func recvRecord(t *testing.T, ch <-chan *kgo.Record) *kgo.Record {
+	t.Helper()
+	select {
+	case r, ok := <-ch:
+		if !ok {
+			t.Fatal("channel closed early")
+		}
+		return r
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for a record")
+		return nil
+	}
+}
+
+func expectClosed[T any](t *testing.T, ch <-chan T) {
+	t.Helper()
+	timeout := time.After(10 * time.Second)
+	for {
+		select {
+		case _, ok := <-ch:
+			if !ok {
+				return
+			}
+		case <-timeout:
+			t.Fatal("timed out waiting for the channel to close")
+		}
+	}
+}
+
+func TestRecordsChan(t *testing.T) {
+	t.Parallel()
+
+	const n = 20
+	c := newChanCluster(t, n)
+	cl := c.NewTestClient(t, kgo.ConsumeTopics("t"), kgo.FetchMaxPartitionBytes(100))
+
+	// With a one record buffer, polling blocks on a full channel rather
+	// than dropping records, and every record arrives in order per
+	// partition once we read.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch := cl.RecordsChan(ctx, 1, func(fe kgo.FetchError) { t.Errorf("fetch error: %v", fe.Err) })
+	first := recvRecord(t, ch)
+	time.Sleep(100 * time.Millisecond)
+	if len(ch) != 1 {
+		t.Errorf("got %d records buffered in the channel, exp it to be full at 1", len(ch))
+	}
+
+	next := map[int32]int64{first.Partition: first.Offset + 1}
+	for i := 1; i < 2*n; i++ {
+		r := recvRecord(t, ch)
+		if r.Offset != next[r.Partition] || string(r.Value) != strconv.FormatInt(r.Offset, 10) {
+			t.Fatalf("partition %d: got offset %d (value %s), exp %d", r.Partition, r.Offset, r.Value, next[r.Partition])
+		}
+		next[r.Partition]++
+	}
+
+	// Canceling the context closes the channel.
+	cancel()
+	expectClosed(t, ch)
+}
+
+func TestRecordsChanClientClose(t *testing.T) {
+	t.Parallel()
+
+	c := newChanCluster(t, 1)
+	cl, err := kgo.NewClient(kgo.SeedBrokers(c.ListenAddrs()...), kgo.ConsumeTopics("t"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ch := cl.RecordsChan(context.Background(), 10, nil)
+	recvRecord(t, ch)
+	cl.Close()
+	expectClosed(t, ch)
+}
+
+func TestPartitionRecordsChan(t *testing.T) {
+	t.Parallel()
+
+	const n = 10
+	c := newChanCluster(t, n)
+	cl := c.NewTestClient(t, kgo.ConsumeTopics("t"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	parts := cl.PartitionRecordsChan(ctx, 1, nil)
+
+	chans := make(map[int32]<-chan *kgo.Record)
+	for len(chans) < 2 {
+		select {
+		case p := <-parts:
+			if p.Topic != "t" {
+				t.Fatalf("got unexpected topic %s", p.Topic)
+			}
+			chans[p.Partition] = p.Records
+		case <-time.After(10 * time.Second):
+			t.Fatal("timed out waiting for partition channels")
+		}
+	}
+
+	// Partition 0 is not read, which must not block partition 1.
+	for i := 0; i < n; i++ {
+		if r := recvRecord(t, chans[1]); r.Partition != 1 || r.Offset != int64(i) {
+			t.Fatalf("got %d/%d, exp 1/%d", r.Partition, r.Offset, i)
+		}
+	}
+
+	// Partition 0 filled its channel and is paused until it drains.
+	if paused := cl.PauseFetchPartitions(nil); len(paused["t"]) != 1 || paused["t"][0] != 0 {
+		t.Errorf("got paused partitions %v, exp t/0", paused)
+	}
+	for i := 0; i < n; i++ {
+		if r := recvRecord(t, chans[0]); r.Partition != 0 || r.Offset != int64(i) {
+			t.Fatalf("got %d/%d, exp 0/%d", r.Partition, r.Offset, i)
+		}
+	}
+	deadline := time.Now().Add(10 * time.Second)
+	for len(cl.PauseFetchPartitions(nil)) > 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("partition 0 was not resumed after draining")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Canceling the context closes every channel.
+	cancel()
+	expectClosed(t, parts)
+	expectClosed(t, chans[0])
+	expectClosed(t, chans[1])
+}
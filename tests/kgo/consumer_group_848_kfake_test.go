@@ -0,0 +1,435 @@
+package kgo_test
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kfake"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+	"github.com/twmb/franz-go/pkg/kversion"
+)
+
+// fake848 runs topic "t" on a kfake cluster, whose group coordinator
+// implements ConsumerGroupHeartbeat. Every heartbeat is recorded. Heartbeats
+// are answered by kfake unless a test scripts them with setNext, which tests
+// use to inject errors and behavior that kfake does not have; only
+// heartbeats that arrive while next is set are scripted.
+type fake848 struct {
+	c     *kfake.Cluster
+	topic [16]byte
+
+	mu     sync.Mutex
+	reqs   []kmsg.ConsumerGroupHeartbeatRequest
+	events []string // heartbeats and rebalance callbacks, in order
+	next   func(*kmsg.ConsumerGroupHeartbeatRequest, *kmsg.ConsumerGroupHeartbeatResponse)
+}
+
+// newFake848 returns a cluster with nine records in the three partitions of
+// topic "t". If support848 is false, the cluster does not support
+// ConsumerGroupHeartbeat, as if it were too old.
+func newFake848(t *testing.T, support848 bool, opts ...kfake.Opt) *fake848 {
+	t.Helper()
+	opts = append([]kfake.Opt{
+		kfake.NumBrokers(1),
+		kfake.SeedTopics(3, "t"),
+		kfake.GroupConsumerHeartbeatInterval(50 * time.Millisecond),
+	}, opts...)
+	if !support848 {
+		opts = append(opts, kfake.MaxKeyVersion(int16(kmsg.ConsumerGroupHeartbeat), -1))
+	}
+	c := kfake.NewTestCluster(t, opts...)
+	p := c.NewTestClient(t,
+		kgo.DefaultProduceTopic("t"),
+		kgo.RecordPartitioner(kgo.RoundRobinPartitioner()),
+	)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	for i := 0; i < 9; i++ {
+		if err := p.ProduceSync(ctx, kgo.StringRecord("v")).FirstErr(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// kfake's OffsetFetch returns nothing for groups it does not know,
+	// and scripted joins do not create the group, so we create the group
+	// by committing the start offsets.
+	commit := kmsg.NewPtrOffsetCommitRequest()
+	commit.Group = "g"
+	commit.Generation = -1
+	ct := kmsg.NewOffsetCommitRequestTopic()
+	ct.Topic = "t"
+	for p := int32(0); p < 3; p++ {
+		cp := kmsg.NewOffsetCommitRequestTopicPartition()
+		cp.Partition = p
+		ct.Partitions = append(ct.Partitions, cp)
+	}
+	commit.Topics = append(commit.Topics, ct)
+	if _, err := commit.RequestWith(ctx, p); err != nil {
+		t.Fatal(err)
+	}
+	meta, err := kmsg.NewPtrMetadataRequest().RequestWith(ctx, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &fake848{c: c, topic: meta.Topics[0].TopicID}
+	if !support848 {
+		return f
+	}
+	c.ControlKey(int16(kmsg.ConsumerGroupHeartbeat), func(kreq kmsg.Request) (kmsg.Response, error, bool) {
+		req := kreq.(*kmsg.ConsumerGroupHeartbeatRequest)
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.reqs = append(f.reqs, *req)
+		f.events = append(f.events, "heartbeat")
+		if f.next == nil {
+			return nil, nil, false // kfake handles the heartbeat
+		}
+		c.KeepControl()
+		resp := req.ResponseKind().(*kmsg.ConsumerGroupHeartbeatResponse)
+		resp.HeartbeatIntervalMillis = 50
+		resp.MemberEpoch = req.MemberEpoch
+		f.next(req, resp)
+		return resp, nil, true
+	})
+	return f
+}
+
+// assign returns an assignment of the given partitions of topic "t".
+func (f *fake848) assign(partitions ...int32) *kmsg.ConsumerGroupHeartbeatResponseAssignment {
+	a := kmsg.NewConsumerGroupHeartbeatResponseAssignment()
+	at := kmsg.NewConsumerGroupHeartbeatResponseAssignmentTopic()
+	at.TopicID = f.topic
+	at.Partitions = partitions
+	a.Topics = append(a.Topics, at)
+	return &a
+}
+
+// joiner assigns every partition to a member joining with epoch 0. Since v1,
+// the broker keeps the member ID the client generated; at v0, the broker
+// generates the ID, which is always "v0-member" here.
+func (f *fake848) joiner(epoch int32) func(*kmsg.ConsumerGroupHeartbeatRequest, *kmsg.ConsumerGroupHeartbeatResponse) {
+	return func(req *kmsg.ConsumerGroupHeartbeatRequest, resp *kmsg.ConsumerGroupHeartbeatResponse) {
+		if req.MemberEpoch == 0 {
+			resp.MemberID = kmsg.StringPtr(req.MemberID)
+			if req.Version == 0 {
+				resp.MemberID = kmsg.StringPtr("v0-member")
+			}
+			resp.MemberEpoch = epoch
+			resp.Assignment = f.assign(0, 1, 2)
+		}
+	}
+}
+
+// joinedAs returns the member ID of the first heartbeat.
+func (f *fake848) joinedAs() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.reqs[0].MemberID
+}
+
+func (f *fake848) setNext(fn func(*kmsg.ConsumerGroupHeartbeatRequest, *kmsg.ConsumerGroupHeartbeatResponse)) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.next = fn
+}
+
+func (f *fake848) event(ev string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.events = append(f.events, ev)
+}
+
+func (f *fake848) client(t *testing.T, opts ...kgo.Opt) *kgo.Client {
+	t.Helper()
+	return f.c.NewTestClient(t, append([]kgo.Opt{
+		kgo.ConsumeTopics("t"),
+		kgo.ConsumerGroup("g"),
+		kgo.ConsumerGroupProtocol("consumer"),
+		kgo.DisableAutoCommit(),
+		kgo.OnPartitionsRevoked(func(_ context.Context, _ *kgo.Client, m map[string][]int32) {
+			if len(m) > 0 {
+				f.event("revoked " + fmtTopics(m))
+			}
+		}),
+		kgo.OnPartitionsLost(func(_ context.Context, _ *kgo.Client, m map[string][]int32) {
+			f.event("lost " + fmtTopics(m))
+		}),
+	}, opts...)...)
+}
+
+// waitFor polls until cond is true, failing the test after five seconds.
+func (f *fake848) waitFor(t *testing.T, what string, cond func(reqs []kmsg.ConsumerGroupHeartbeatRequest) bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		f.mu.Lock()
+		ok := cond(f.reqs)
+		f.mu.Unlock()
+		if ok {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func consume848(t *testing.T, cl *kgo.Client, n int) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for got := 0; got < n; {
+		fs := cl.PollFetches(ctx)
+		if err := ctx.Err(); err != nil {
+			t.Fatalf("consumed %d of %d records", got, n)
+		}
+		fs.EachError(func(_ string, _ int32, err error) {
+			t.Errorf("unexpected poll error: %v", err)
+		})
+		got += fs.NumRecords()
+	}
+}
+
+func owned(req kmsg.ConsumerGroupHeartbeatRequest) []int32 {
+	var ps []int32
+	for _, t := range req.Topics {
+		ps = append(ps, t.Partitions...)
+	}
+	return ps
+}
+
+func TestGroup848JoinAndReconcile(t *testing.T) {
+	f := newFake848(t, true)
+	cl1 := f.client(t, kgo.ServerAssignor("uniform"))
+	consume848(t, cl1, 9)
+
+	m1 := f.joinedAs()
+	if m, epoch := cl1.GroupMetadata(); m != m1 || epoch != 1 {
+		t.Fatalf("got member %q epoch %d, expected %q and 1", m, epoch, m1)
+	}
+
+	// A second member joins. The first must revoke what moves to the
+	// second before the second is assigned it, and both then report
+	// owning a disjoint share of every partition at the same epoch.
+	cl2 := f.client(t)
+	f.waitFor(t, "both members owning a share of every partition", func(reqs []kmsg.ConsumerGroupHeartbeatRequest) bool {
+		m2, _ := cl2.GroupMetadata()
+		last := make(map[string]kmsg.ConsumerGroupHeartbeatRequest)
+		for _, req := range reqs {
+			last[req.MemberID] = req
+		}
+		l1, ok1 := last[m1]
+		l2, ok2 := last[m2]
+		if !ok1 || !ok2 || l1.MemberEpoch != l2.MemberEpoch || len(owned(l1)) == 0 || len(owned(l2)) == 0 {
+			return false
+		}
+		all := append(owned(l1), owned(l2)...)
+		sort.Slice(all, func(i, j int) bool { return all[i] < all[j] })
+		return reflect.DeepEqual(all, []int32{0, 1, 2})
+	})
+
+	f.mu.Lock()
+	var revoked bool
+	for _, ev := range f.events {
+		revoked = revoked || strings.HasPrefix(ev, "revoked t[")
+	}
+	f.mu.Unlock()
+	if !revoked {
+		t.Error("the first member did not revoke anything when the second joined")
+	}
+
+	cl2.Close()
+	cl1.Close()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	join := f.reqs[0]
+	if join.MemberEpoch != 0 || join.MemberID == "" || !reflect.DeepEqual(join.SubscribedTopicNames, []string{"t"}) || join.ServerAssignor == nil || *join.ServerAssignor != "uniform" {
+		t.Errorf("unexpected join heartbeat %+v", join)
+	}
+	if leave := f.reqs[len(f.reqs)-1]; leave.MemberEpoch != -1 || leave.MemberID != m1 {
+		t.Errorf("unexpected leave heartbeat %+v", leave)
+	}
+}
+
+func TestGroup848Regex(t *testing.T) {
+	for _, test := range []struct {
+		name      string
+		maxV      int16
+		expRegex  *string
+		expTopics []string
+	}{
+		{"regex", 1, kmsg.StringPtr(".*(?:^t$).*"), nil},
+		{"v0 sends matched topics", 0, nil, []string{"t"}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			f := newFake848(t, true)
+			versions := kversion.Stable()
+			versions.SetMaxKeyVersion(int16(kmsg.ConsumerGroupHeartbeat), test.maxV)
+			cl := f.client(t, kgo.ConsumeTopics("^t$"), kgo.ConsumeRegex(), kgo.MaxVersions(versions))
+			consume848(t, cl, 9)
+
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			join := f.reqs[0]
+			if join.Version != test.maxV || !reflect.DeepEqual(join.SubscribedTopicRegex, test.expRegex) || !reflect.DeepEqual(join.SubscribedTopicNames, test.expTopics) {
+				t.Errorf("got join heartbeat v%d with regex %v and topics %v, expected v%d with regex %v and topics %v",
+					join.Version, strPtr(join.SubscribedTopicRegex), join.SubscribedTopicNames,
+					test.maxV, strPtr(test.expRegex), test.expTopics)
+			}
+		})
+	}
+}
+
+// At v0, the broker may generate our member ID, which we must then use.
+// kfake keeps the ID we send, so the join is scripted.
+func TestGroup848V0MemberID(t *testing.T) {
+	f := newFake848(t, true)
+	f.setNext(f.joiner(1))
+	versions := kversion.Stable()
+	versions.SetMaxKeyVersion(int16(kmsg.ConsumerGroupHeartbeat), 0)
+	cl := f.client(t, kgo.MaxVersions(versions))
+	consume848(t, cl, 9)
+	if m, _ := cl.GroupMetadata(); m != "v0-member" {
+		t.Errorf("got member %q, expected v0-member", m)
+	}
+}
+
+func TestGroup848FencedReset(t *testing.T) {
+	// A scripted fence replies to one heartbeat with an error, and kfake
+	// then handles the rejoin. A session timeout is a real fence: kfake
+	// removes the member, and its next heartbeat fails.
+	scriptFence := func(fenceErr *kerr.Error) func(*fake848) {
+		return func(f *fake848) {
+			f.setNext(func(_ *kmsg.ConsumerGroupHeartbeatRequest, resp *kmsg.ConsumerGroupHeartbeatResponse) {
+				resp.ErrorCode = fenceErr.Code
+				f.next = nil
+			})
+		}
+	}
+	now := time.Now()
+	for _, test := range []struct {
+		name  string
+		opts  []kfake.Opt
+		fence func(*fake848)
+	}{
+		{"scripted " + kerr.FencedMemberEpoch.Message, nil, scriptFence(kerr.FencedMemberEpoch)},
+		{"scripted " + kerr.UnknownMemberID.Message, nil, scriptFence(kerr.UnknownMemberID)},
+		{"session timeout", []kfake.Opt{kfake.Clock(func() time.Time { return now })}, func(f *fake848) {
+			f.c.AdvanceTime(time.Minute)
+		}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			f := newFake848(t, true, test.opts...)
+			cl := f.client(t)
+			consume848(t, cl, 9)
+			memberID := f.joinedAs()
+
+			test.fence(f)
+			lostAt := func() int {
+				for i, ev := range f.events {
+					if ev == "lost t[0 1 2]" {
+						return i
+					}
+				}
+				return -1
+			}
+			f.waitFor(t, "a rejoin after losing everything", func(reqs []kmsg.ConsumerGroupHeartbeatRequest) bool {
+				i := lostAt()
+				m, epoch := cl.GroupMetadata()
+				return i >= 0 && i+1 < len(f.events) && m == memberID && epoch > 0 && reqs[len(reqs)-1].MemberEpoch > 0
+			})
+
+			f.mu.Lock()
+			i := lostAt()
+			events := append([]string(nil), f.events[i-1:i+2]...)
+			var rejoin kmsg.ConsumerGroupHeartbeatRequest
+			for _, req := range f.reqs {
+				if req.MemberEpoch == 0 {
+					rejoin = req // the last join
+				}
+			}
+			f.mu.Unlock()
+
+			// Everything we owned is lost after the fenced
+			// heartbeat and before we rejoin, and the rejoin keeps
+			// our member ID but reports owning nothing.
+			if events[0] != "heartbeat" || events[2] != "heartbeat" {
+				t.Errorf("expected the fenced heartbeat, then lost, then the rejoin; got %v", events)
+			}
+			if rejoin.MemberID != memberID || len(rejoin.Topics) != 0 {
+				t.Errorf("unexpected rejoin heartbeat %+v", rejoin)
+			}
+
+			// Fencing is not surfaced as an error, and we consume
+			// again from the start since we never committed.
+			consume848(t, cl, 9)
+		})
+	}
+}
+
+func TestGroup848Downgrade(t *testing.T) {
+	for _, test := range []struct {
+		name      string
+		advertise bool
+		pinned    bool
+	}{
+		{"broker too old", false, false},
+		{"unsupported version", true, false}, // scripted
+		{"max versions without heartbeats", true, true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			f := newFake848(t, test.advertise)
+			f.setNext(func(_ *kmsg.ConsumerGroupHeartbeatRequest, resp *kmsg.ConsumerGroupHeartbeatResponse) {
+				resp.ErrorCode = kerr.UnsupportedVersion.Code
+			})
+			var opts []kgo.Opt
+			if test.pinned {
+				opts = append(opts, kgo.MaxVersions(kversion.V3_5_0()))
+			}
+			cl := f.client(t, opts...)
+			consume848(t, cl, 9)
+
+			// The classic protocol has a generation, not an epoch.
+			if m, gen := cl.GroupMetadata(); m == "" || gen < 1 {
+				t.Errorf("got member %q generation %d after downgrading, expected a classic member", m, gen)
+			}
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			exp := 0
+			if test.advertise && !test.pinned {
+				exp = 1
+			}
+			if n := len(f.reqs); n != exp {
+				t.Errorf("got %d consumer group heartbeats, expected %d", n, exp)
+			}
+		})
+	}
+}
+
+// fmtTopics formats topics and their sorted partitions as "t[0 1]".
+func fmtTopics(m map[string][]int32) string {
+	topics := make([]string, 0, len(m))
+	for topic, partitions := range m {
+		partitions = append([]int32(nil), partitions...)
+		sort.Slice(partitions, func(i, j int) bool { return partitions[i] < partitions[j] })
+		topics = append(topics, fmt.Sprintf("%s%v", topic, partitions))
+	}
+	sort.Strings(topics)
+	return strings.Join(topics, ", ")
+}
+
+func strPtr(s *string) string {
+	if s == nil {
+		return "<nil>"
+	}
+	return *s
+}
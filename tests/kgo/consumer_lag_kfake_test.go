@@ -0,0 +1,105 @@
+package kgo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func TestConsumerLag(t *testing.T) {
+	t.Parallel()
+
+	const n = 10
+	c := newChanCluster(t, n)
+
+	if lag := c.NewTestClient(t, kgo.ConsumeTopics("t")).ConsumerLag(); lag != nil {
+		t.Errorf("got lag %v without ConsumerLagInterval, exp nil", lag)
+	}
+
+	cl := c.NewTestClient(t,
+		kgo.ConsumerGroup("g"),
+		kgo.ConsumeTopics("t"),
+		kgo.DisableAutoCommit(),
+		kgo.ConsumerLagInterval(50*time.Millisecond),
+	)
+	rs := pollRecords(t, cl, 2*n)
+
+	// Everything is polled and nothing is committed yet: the committed
+	// offset is where the group started consuming, as in CommittedOffsets.
+	lag := cl.ConsumerLag()
+	for p := int32(0); p < 2; p++ {
+		exp := kgo.PartitionLag{End: n, Polled: n, Committed: 0, Lag: 0, CommittedLag: n}
+		if got := lag["t"][p]; got.Updated.IsZero() || withoutUpdated(got) != exp {
+			t.Errorf("partition %d: got %+v, exp %+v", p, got, exp)
+		}
+	}
+
+	// Committing halfway through partition 0 is reflected in CommittedLag.
+	for _, r := range rs {
+		if r.Partition == 0 && r.Offset == n/2-1 {
+			if err := cl.CommitRecords(context.Background(), r); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if got := cl.ConsumerLag()["t"][0]; got.Committed != n/2 || got.CommittedLag != n/2 || got.Lag != 0 {
+		t.Errorf("got %+v, exp committed %d, committed lag %d, lag 0", got, n/2, n/2)
+	}
+
+	// While paused, nothing is fetched, but end offsets are still listed
+	// so that lag grows as records are produced.
+	cl.PauseFetchTopics("t")
+	if _, err := c.ProduceTo("t", 1, &kgo.Record{Value: []byte("a")}, &kgo.Record{Value: []byte("b")}); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "the end offset to be listed", func() bool { return cl.ConsumerLag()["t"][1].End == n+2 })
+	if got := cl.ConsumerLag()["t"][1]; got.Polled != n || got.Lag != 2 {
+		t.Errorf("got %+v, exp polled %d and lag 2", got, n)
+	}
+
+	// Resuming and polling catches up.
+	cl.ResumeFetchTopics("t")
+	pollRecords(t, cl, 2)
+	if got := cl.ConsumerLag()["t"][1]; got.End != n+2 || got.Polled != n+2 || got.Lag != 0 {
+		t.Errorf("got %+v after catching up, exp end and polled %d with lag 0", got, n+2)
+	}
+}
+
+func TestConsumerLagDropsUnconsumed(t *testing.T) {
+	t.Parallel()
+
+	c := newChanCluster(t, 1)
+	cl := c.NewTestClient(t,
+		kgo.ConsumePartitions(map[string]map[int32]kgo.Offset{"t": {
+			0: kgo.NewOffset().AtStart(),
+			1: kgo.NewOffset().AtStart(),
+		}}),
+		kgo.ConsumerLagInterval(50*time.Millisecond),
+	)
+	pollRecords(t, cl, 2)
+	lag := cl.ConsumerLag()
+	if len(lag["t"]) != 2 {
+		t.Fatalf("got lag %v, exp both partitions", lag)
+	}
+	for p, l := range lag["t"] {
+		if l.Committed != -1 || l.CommittedLag != -1 || l.Lag != 0 {
+			t.Errorf("partition %d: got %+v, exp nothing committed outside of a group and no lag", p, l)
+		}
+	}
+
+	// Partitions that are no longer consumed are dropped on the next
+	// interval.
+	cl.RemoveConsumePartitions(map[string][]int32{"t": {0}})
+	waitFor(t, "the removed partition to be dropped", func() bool {
+		lag := cl.ConsumerLag()
+		_, ok := lag["t"][0]
+		return !ok && len(lag["t"]) == 1
+	})
+}
+
+func withoutUpdated(l kgo.PartitionLag) kgo.PartitionLag {
+	l.Updated = time.Time{}
+	return l
+}
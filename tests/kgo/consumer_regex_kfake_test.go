@@ -0,0 +1,169 @@
+package kgo_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kfake"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func createTopic(t *testing.T, cl *kgo.Client, topic string) {
+	t.Helper()
+	req := kmsg.NewPtrCreateTopicsRequest()
+	rt := kmsg.NewCreateTopicsRequestTopic()
+	rt.Topic = topic
+	rt.NumPartitions = 1
+	rt.ReplicationFactor = 1
+	req.Topics = append(req.Topics, rt)
+	resp, err := req.RequestWith(context.Background(), cl)
+	if err == nil {
+		err = kerr.ErrorForCode(resp.Topics[0].ErrorCode)
+	}
+	if err != nil {
+		t.Fatalf("unable to create topic %s: %v", topic, err)
+	}
+}
+
+// pollTopics polls until a record has been polled from each of the given
+// topics, failing the test if a record is polled from any other topic.
+func pollTopics(t *testing.T, cl *kgo.Client, topics ...string) {
+	t.Helper()
+	allowed := make(map[string]bool)
+	for _, topic := range topics {
+		allowed[topic] = false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	for polled := 0; polled < len(topics); {
+		fs := cl.PollFetches(ctx)
+		if ctx.Err() != nil {
+			t.Fatalf("timed out waiting for records from %v, polled: %v", topics, allowed)
+		}
+		fs.EachRecord(func(r *kgo.Record) {
+			seen, ok := allowed[r.Topic]
+			if !ok {
+				t.Errorf("got record from unexpected topic %s", r.Topic)
+				return
+			}
+			if !seen {
+				allowed[r.Topic] = true
+				polled++
+			}
+		})
+	}
+}
+
+func TestConsumeRegexDiscovery(t *testing.T) {
+	t.Parallel()
+
+	for _, group := range []bool{false, true} {
+		group := group
+		name := "direct"
+		if group {
+			name = "group"
+		}
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			c := kfake.NewTestCluster(t, kfake.NumBrokers(1), kfake.SeedTopics(1, "a1", "b1"))
+			opts := []kgo.Opt{
+				kgo.ConsumeTopics("^a"),
+				kgo.ConsumeRegex(),
+				kgo.ConsumeResetOffset(kgo.NewOffset().AtStart()),
+				kgo.MetadataMinAge(10 * time.Millisecond),
+				kgo.ConsumeRegexDiscoveryInterval(50 * time.Millisecond),
+				kgo.FetchMaxWait(100 * time.Millisecond), // new topics are fetched once the in flight fetch returns
+			}
+			if group {
+				opts = append(opts, kgo.ConsumerGroup("g"), kgo.HeartbeatInterval(100*time.Millisecond))
+			}
+			cl := c.NewTestClient(t, opts...)
+			for _, topic := range []string{"a1", "b1"} {
+				if _, err := c.ProduceTo(topic, 0, &kgo.Record{Value: []byte(topic)}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			pollTopics(t, cl, "a1")
+
+			// A new matching topic is discovered at the discovery
+			// interval rather than at the metadata max age.
+			createTopic(t, cl, "a2")
+			if _, err := c.ProduceTo("a2", 0, &kgo.Record{Value: []byte("a2")}); err != nil {
+				t.Fatal(err)
+			}
+			pollTopics(t, cl, "a2")
+		})
+	}
+}
+
+func TestConsumeRegexAddRemove(t *testing.T) {
+	t.Parallel()
+
+	c := kfake.NewTestCluster(t, kfake.NumBrokers(1), kfake.SeedTopics(1, "a", "b"))
+	cl := c.NewTestClient(t,
+		kgo.ConsumeTopics("^a$"),
+		kgo.ConsumeRegex(),
+		kgo.ConsumeResetOffset(kgo.NewOffset().AtStart()),
+		kgo.MetadataMinAge(10*time.Millisecond),
+		kgo.FetchMaxWait(100*time.Millisecond),
+	)
+	produce := func() {
+		t.Helper()
+		for _, topic := range []string{"a", "b"} {
+			if _, err := c.ProduceTo(topic, 0, &kgo.Record{Value: []byte(topic)}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	produce()
+	pollTopics(t, cl, "a")
+
+	// Adding a regex re-evaluates topics that previously did not match.
+	if err := cl.AddConsumeRegex("^b$"); err != nil {
+		t.Fatal(err)
+	}
+	if got, exp := cl.GetConsumeRegex(), []string{"^a$", "^b$"}; !reflect.DeepEqual(got, exp) {
+		t.Errorf("got regexes %v, exp %v", got, exp)
+	}
+	pollTopics(t, cl, "b")
+
+	// Removing a regex purges topics that no longer match anything.
+	cl.RemoveConsumeRegex("^b$")
+	if got, exp := cl.GetConsumeRegex(), []string{"^a$"}; !reflect.DeepEqual(got, exp) {
+		t.Errorf("got regexes %v, exp %v", got, exp)
+	}
+	produce()
+	pollTopics(t, cl, "a")
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	cl.PollFetches(ctx).EachRecord(func(r *kgo.Record) {
+		if r.Topic == "b" {
+			t.Error("got record from b after removing its regex")
+		}
+	})
+
+	if err := cl.AddConsumeRegex("("); err == nil {
+		t.Error("expected an error adding an invalid regex")
+	}
+	if got, exp := cl.GetConsumeRegex(), []string{"^a$"}; !reflect.DeepEqual(got, exp) {
+		t.Errorf("got regexes %v after a failed add, exp %v", got, exp)
+	}
+}
+
+func TestConsumeRegexNotRegexConsuming(t *testing.T) {
+	t.Parallel()
+
+	c := kfake.NewTestCluster(t, kfake.NumBrokers(1), kfake.SeedTopics(1, "a"))
+	cl := c.NewTestClient(t, kgo.ConsumeTopics("a"))
+	if err := cl.AddConsumeRegex("^b"); err == nil {
+		t.Errorf("got err %v, exp an error when not consuming via regex", err)
+	}
+	if got := cl.GetConsumeRegex(); got != nil {
+		t.Errorf("got regexes %v, exp nil when not consuming via regex", got)
+	}
+}
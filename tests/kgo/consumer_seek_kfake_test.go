@@ -0,0 +1,121 @@
+package kgo_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kfake"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func TestSeekToTimestamp(t *testing.T) {
+	t.Parallel()
+
+	// Partition 0 has records at 1s, 2s, and 3s; partition 1 has a single
+	// record at 1s. Each record is its own batch so that timestamps
+	// resolve to individual offsets.
+	base := time.UnixMilli(1_000_000)
+	c := kfake.NewTestCluster(t, kfake.NumBrokers(1), kfake.SeedTopics(2, "t"))
+	for _, pr := range []struct {
+		p  int32
+		ts time.Duration
+	}{{0, time.Second}, {0, 2 * time.Second}, {0, 3 * time.Second}, {1, time.Second}} {
+		if _, err := c.ProduceTo("t", pr.p, &kgo.Record{Timestamp: base.Add(pr.ts)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cl := c.NewTestClient(t, kgo.ConsumePartitions(map[string]map[int32]kgo.Offset{"t": {
+		0: kgo.NewOffset().AtStart(),
+		1: kgo.NewOffset().AtStart(),
+	}}))
+	ctx := context.Background()
+
+	// Nothing has been consumed yet, so there is nothing to seek.
+	if offsets, err := cl.SeekToTimestamp(ctx, base, nil); offsets != nil || err != nil {
+		t.Errorf("got %v, %v before consuming, exp nothing", offsets, err)
+	}
+	pollRecords(t, cl, 4)
+
+	for _, test := range []struct {
+		name string
+		ts   time.Duration
+		exp  map[int32]int64
+	}{
+		{"before every record", 0, map[int32]int64{0: 0, 1: 0}},
+		{"exact timestamp", 2 * time.Second, map[int32]int64{0: 1, 1: 1}},
+		{"between timestamps", 2500 * time.Millisecond, map[int32]int64{0: 2, 1: 1}},
+		{"after every record", time.Hour, map[int32]int64{0: 3, 1: 1}},
+	} {
+		offsets, err := cl.SeekToTimestamp(ctx, base.Add(test.ts), nil)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		for p, exp := range test.exp {
+			if got := offsets["t"][p].Offset; got != exp {
+				t.Errorf("%s: partition %d: got offset %d, exp %d", test.name, p, got, exp)
+			}
+		}
+	}
+
+	// The seek is applied: consuming resumes from the resolved offsets.
+	if _, err := cl.SeekToTimestamp(ctx, base.Add(2*time.Second), map[string][]int32{"t": {0}}); err != nil {
+		t.Fatal(err)
+	}
+	rs := pollRecords(t, cl, 2)
+	if rs[0].Partition != 0 || rs[0].Offset != 1 || rs[1].Offset != 2 {
+		t.Errorf("got %d/%d, %d/%d after seeking, exp 0/1, 0/2", rs[0].Partition, rs[0].Offset, rs[1].Partition, rs[1].Offset)
+	}
+
+	// A partition that does not exist fails, but the others are still
+	// seeked.
+	offsets, err := cl.SeekToTimestamp(ctx, base, map[string][]int32{"t": {0, 5}})
+	if !errors.Is(err, kerr.UnknownTopicOrPartition) {
+		t.Errorf("got err %v, exp UNKNOWN_TOPIC_OR_PARTITION", err)
+	}
+	if len(offsets["t"]) != 1 || offsets["t"][0].Offset != 0 {
+		t.Errorf("got offsets %v, exp only t/0 at 0", offsets)
+	}
+	rs = pollRecords(t, cl, 3)
+	if rs[0].Partition != 0 || rs[0].Offset != 0 {
+		t.Errorf("got %d/%d after seeking, exp 0/0", rs[0].Partition, rs[0].Offset)
+	}
+}
+
+func TestSetOffsets(t *testing.T) {
+	t.Parallel()
+
+	c := newChanCluster(t, 5)
+	cl := c.NewTestClient(t, kgo.ConsumePartitions(map[string]map[int32]kgo.Offset{"t": {
+		0: kgo.NewOffset().AtStart(),
+	}}))
+
+	// Partitions that were never consumed are skipped.
+	cl.SetOffsets(map[string]map[int32]kgo.EpochOffset{"t": {1: {Epoch: -1, Offset: 3}}})
+	pollRecords(t, cl, 5)
+
+	// Seeking backwards and forwards both resume from the set offset.
+	for _, offset := range []int64{2, 4, 0} {
+		cl.SetOffsets(map[string]map[int32]kgo.EpochOffset{"t": {0: {Epoch: -1, Offset: offset}}})
+		rs := pollRecords(t, cl, int(5-offset))
+		if rs[0].Offset != offset {
+			t.Errorf("got offset %d after setting %d", rs[0].Offset, offset)
+		}
+		for _, r := range rs {
+			if r.Partition != 0 {
+				t.Fatalf("got record for partition %d, which was never consumed", r.Partition)
+			}
+		}
+	}
+
+	// Seeking past the end waits for new records at that offset.
+	cl.SetOffsets(map[string]map[int32]kgo.EpochOffset{"t": {0: {Epoch: -1, Offset: 5}}})
+	if _, err := c.ProduceTo("t", 0, &kgo.Record{Value: []byte("5")}); err != nil {
+		t.Fatal(err)
+	}
+	if rs := pollRecords(t, cl, 1); rs[0].Offset != 5 {
+		t.Errorf("got offset %d, exp 5", rs[0].Offset)
+	}
+}
@@ -0,0 +1,90 @@
+package kgo_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func TestValidateOffsets(t *testing.T) {
+	t.Parallel()
+
+	// Both partitions have 10 records. Partition 0 is in its first epoch;
+	// partition 1 had epoch 0 end at offset 4 and is now in epoch 1.
+	const n = 10
+	c := newChanCluster(t, n)
+	if err := c.SetEpochHistory("t", 1, map[int32]int64{0: 4}); err != nil {
+		t.Fatal(err)
+	}
+	cl := c.NewTestClient(t)
+
+	for _, test := range []struct {
+		name      string
+		topic     string
+		partition int32
+		offset    kgo.EpochOffset
+
+		expEnd      kgo.EpochOffset
+		expDataLoss int64 // ResetTo if non-zero
+		expErr      error
+	}{
+		{name: "current epoch inside log", topic: "t", partition: 0, offset: kgo.EpochOffset{Epoch: 0, Offset: 5}, expEnd: kgo.EpochOffset{Epoch: 0, Offset: n}},
+		{name: "current epoch at log end", topic: "t", partition: 0, offset: kgo.EpochOffset{Epoch: 0, Offset: n}, expEnd: kgo.EpochOffset{Epoch: 0, Offset: n}},
+		{name: "current epoch past log end", topic: "t", partition: 0, offset: kgo.EpochOffset{Epoch: 0, Offset: n + 2}, expEnd: kgo.EpochOffset{Epoch: 0, Offset: n}, expDataLoss: n},
+		{name: "prior epoch before its end", topic: "t", partition: 1, offset: kgo.EpochOffset{Epoch: 0, Offset: 3}, expEnd: kgo.EpochOffset{Epoch: 0, Offset: 4}},
+		{name: "prior epoch diverged", topic: "t", partition: 1, offset: kgo.EpochOffset{Epoch: 0, Offset: 7}, expEnd: kgo.EpochOffset{Epoch: 0, Offset: 4}, expDataLoss: 4},
+		{name: "negative epoch is not validated", topic: "t", partition: 0, offset: kgo.EpochOffset{Epoch: -1, Offset: 100}, expEnd: kgo.EpochOffset{Epoch: -1, Offset: 100}},
+		{name: "unknown partition", topic: "t", partition: 9, offset: kgo.EpochOffset{Epoch: 0, Offset: 1}, expErr: kerr.UnknownTopicOrPartition},
+		{name: "unknown topic", topic: "missing", partition: 0, offset: kgo.EpochOffset{Epoch: 0, Offset: 1}, expErr: kerr.UnknownTopicOrPartition},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			offsets := map[string]map[int32]kgo.EpochOffset{test.topic: {test.partition: test.offset}}
+			validated := cl.ValidateOffsets(context.Background(), offsets)
+			v, ok := validated[test.topic][test.partition]
+			if !ok || len(validated) != 1 || len(validated[test.topic]) != 1 {
+				t.Fatalf("got %v, exp exactly %s/%d", validated, test.topic, test.partition)
+			}
+			if v.Offset != test.offset {
+				t.Errorf("got offset %+v, exp %+v", v.Offset, test.offset)
+			}
+
+			switch {
+			case test.expErr != nil:
+				if !errors.Is(v.Err, test.expErr) {
+					t.Errorf("got err %v, exp %v", v.Err, test.expErr)
+				}
+				return
+			case test.expDataLoss != 0:
+				var dl *kgo.ErrDataLoss
+				if !errors.As(v.Err, &dl) {
+					t.Fatalf("got err %v, exp data loss", v.Err)
+				}
+				exp := kgo.ErrDataLoss{Topic: test.topic, Partition: test.partition, ConsumedTo: test.offset.Offset, ResetTo: test.expDataLoss}
+				if *dl != exp {
+					t.Errorf("got data loss %+v, exp %+v", *dl, exp)
+				}
+			case v.Err != nil:
+				t.Errorf("got unexpected err %v", v.Err)
+			}
+			if v.End != test.expEnd {
+				t.Errorf("got end %+v, exp %+v", v.End, test.expEnd)
+			}
+		})
+	}
+
+	// Many partitions are validated in one call, and nothing is returned
+	// for nothing requested.
+	validated := cl.ValidateOffsets(context.Background(), map[string]map[int32]kgo.EpochOffset{
+		"t":       {0: {Epoch: 0, Offset: 1}, 1: {Epoch: 1, Offset: 1}},
+		"missing": {0: {Epoch: 0, Offset: 1}},
+	})
+	if len(validated["t"]) != 2 || validated["t"][0].Err != nil || validated["t"][1].Err != nil || validated["missing"][0].Err == nil {
+		t.Errorf("got %+v, exp both t partitions valid and missing/0 failed", validated)
+	}
+	if validated := cl.ValidateOffsets(context.Background(), nil); len(validated) != 0 {
+		t.Errorf("got %v validating nothing, exp nothing", validated)
+	}
+}
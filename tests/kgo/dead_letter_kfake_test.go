@@ -0,0 +1,127 @@
+package kgo_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kfake"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func TestDeadLetterQueue(t *testing.T) {
+	t.Parallel()
+
+	c := kfake.NewTestCluster(t, kfake.NumBrokers(1), kfake.SeedTopics(1, "in", "dlq"))
+	if _, err := c.ProduceTo("in", 0,
+		&kgo.Record{Key: []byte("k0"), Value: []byte("ok")},
+		&kgo.Record{Key: []byte("k1"), Value: []byte("bad"), Headers: []kgo.RecordHeader{{Key: "h", Value: []byte("v")}}},
+		&kgo.Record{Key: []byte("k2"), Value: []byte("flaky")},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	cl := c.NewTestClient(t,
+		kgo.ConsumerGroup("g"),
+		kgo.ConsumeTopics("in"),
+		kgo.DisableAutoCommit(),
+	)
+	var backoffs []int
+	q := kgo.NewDeadLetterQueue(cl, "dlq",
+		kgo.DeadLetterAttempts(2),
+		kgo.DeadLetterBackoff(func(attempt int) time.Duration {
+			backoffs = append(backoffs, attempt)
+			return time.Millisecond
+		}),
+	)
+
+	attempts := make(map[string]int)
+	process := func(_ context.Context, r *kgo.Record) error {
+		attempts[string(r.Value)]++
+		switch {
+		case string(r.Value) == "bad":
+			return errors.New("cannot process")
+		case string(r.Value) == "flaky" && attempts["flaky"] == 1:
+			return errors.New("try again")
+		}
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	for done := 0; done < 3; {
+		fs := cl.PollFetches(ctx)
+		if ctx.Err() != nil {
+			t.Fatal("timed out consuming")
+		}
+		if err := q.ProcessFetches(ctx, fs, process); err != nil {
+			t.Fatal(err)
+		}
+		done += fs.NumRecords()
+	}
+	if exp := map[string]int{"ok": 1, "bad": 2, "flaky": 2}; len(attempts) != 3 || attempts["ok"] != 1 || attempts["bad"] != 2 || attempts["flaky"] != 2 {
+		t.Errorf("got attempts %v, exp %v", attempts, exp)
+	}
+	if len(backoffs) != 2 || backoffs[0] != 1 || backoffs[1] != 1 {
+		t.Errorf("got backoffs after attempts %v, exp [1 1]", backoffs)
+	}
+
+	// Only the record that failed every attempt is dead lettered, with
+	// its key, value, and headers intact plus the dead letter headers.
+	dead, err := c.ReadRecords("dlq", 0, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dead) != 1 {
+		t.Fatalf("got %d dead letter records, exp 1", len(dead))
+	}
+	r := dead[0]
+	if string(r.Key) != "k1" || string(r.Value) != "bad" {
+		t.Errorf("got dead letter record %s=%s, exp k1=bad", r.Key, r.Value)
+	}
+	if len(r.Headers) == 0 || r.Headers[0].Key != "h" || string(r.Headers[0].Value) != "v" {
+		t.Errorf("original headers were not kept first: %v", r.Headers)
+	}
+	for key, exp := range map[string]string{
+		kgo.DeadLetterHeaderTopic:     "in",
+		kgo.DeadLetterHeaderPartition: "0",
+		kgo.DeadLetterHeaderOffset:    "1",
+		kgo.DeadLetterHeaderError:     "cannot process",
+		kgo.DeadLetterHeaderAttempts:  "2",
+	} {
+		if got, _ := headerValue(r, key); got != exp {
+			t.Errorf("header %s: got %q, exp %q", key, got, exp)
+		}
+	}
+
+	// Every record, including the dead lettered one, was committed.
+	if o := cl.CommittedOffsets()["in"][0]; o.Offset != 3 {
+		t.Errorf("got committed offset %d, exp 3", o.Offset)
+	}
+}
+
+func TestDeadLetterQueueProduceFails(t *testing.T) {
+	t.Parallel()
+
+	// If the dead letter record cannot be produced, the record is not
+	// committed and the error is returned.
+	c := kfake.NewTestCluster(t, kfake.NumBrokers(1), kfake.SeedTopics(1, "in"))
+	cl := c.NewTestClient(t, kgo.ConsumerGroup("g"), kgo.ConsumeTopics("in"), kgo.DisableAutoCommit(), kgo.RecordRetries(1))
+	if _, err := c.ProduceTo("in", 0, &kgo.Record{Value: []byte("bad")}); err != nil {
+		t.Fatal(err)
+	}
+	rs := pollRecords(t, cl, 1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	q := kgo.NewDeadLetterQueue(cl, "missing", kgo.DeadLetterAttempts(0))
+	err := q.Process(ctx, rs[0], func(context.Context, *kgo.Record) error { return errors.New("no") })
+	if err == nil || !strings.Contains(err.Error(), "unable to produce dead letter record") {
+		t.Errorf("got err %v, exp a dead letter produce error", err)
+	}
+	if o, ok := cl.CommittedOffsets()["in"][0]; ok && o.Offset > 0 {
+		t.Errorf("record was committed at %d despite failing to dead letter it", o.Offset)
+	}
+}
@@ -0,0 +1,47 @@
+package kgo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kfake"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// TestDeliveryReportsEventLoop produces and drains DeliveryReports from one
+// goroutine with a buffer far smaller than the number of records: Produce
+// waits on the buffer, and the buffer must drain without the loop receiving.
+func TestDeliveryReportsEventLoop(t *testing.T) {
+	t.Parallel()
+
+	const n = 100
+	c := kfake.NewTestCluster(t, kfake.NumBrokers(1), kfake.SeedTopics(1, "t"))
+	ch := make(chan kgo.ProduceResult)
+	cl := c.NewTestClient(t,
+		kgo.DefaultProduceTopic("t"),
+		kgo.MaxBufferedRecords(2),
+		kgo.DeliveryReports(ch),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var produced, received int
+	for received < n {
+		if produced < n {
+			cl.Produce(ctx, kgo.StringRecord("v"), nil)
+			produced++
+			continue
+		}
+		select {
+		case r := <-ch:
+			if r.Err != nil {
+				t.Fatalf("record %d failed: %v", received, r.Err)
+			}
+			received++
+		case <-ctx.Done():
+			t.Fatalf("timed out with %d of %d results received", received, n)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package kgo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// pollRecords polls cl until it has at least n records, failing the test on
+// fetch errors or if the records do not arrive within 10s.
+func pollRecords(t *testing.T, cl *kgo.Client, n int) []*kgo.Record {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	var rs []*kgo.Record
+	for len(rs) < n {
+		fs := cl.PollFetches(ctx)
+		if ctx.Err() != nil {
+			t.Fatalf("timed out with %d of %d records polled", len(rs), n)
+		}
+		for _, err := range fs.Errors() {
+			t.Fatalf("fetch error on %s/%d: %v", err.Topic, err.Partition, err.Err)
+		}
+		rs = append(rs, fs.Records()...)
+	}
+	return rs
+}
+
+// headerValue returns the value of the first header with the given key.
+func headerValue(r *kgo.Record, key string) (string, bool) {
+	for _, h := range r.Headers {
+		if h.Key == key {
+			return string(h.Value), true
+		}
+	}
+	return "", false
+}
+
+// waitFor polls fn until it returns true, failing the test if it does not
+// within 10s.
+func waitFor(t *testing.T, what string, fn func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for !fn() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
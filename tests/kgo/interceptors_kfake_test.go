@@ -0,0 +1,186 @@
+package kgo_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/twmb/franz-go/pkg/kfake"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// callLog records interceptor calls across interceptors, in order.
+type callLog struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (l *callLog) add(format string, args ...any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.calls = append(l.calls, fmt.Sprintf(format, args...))
+}
+
+func (l *callLog) take() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	calls := l.calls
+	l.calls = nil
+	return calls
+}
+
+// tagInterceptor adds a header with its name to every produced record,
+// failing records whose key is its name, and drops consumed records whose
+// value is its name.
+type tagInterceptor struct {
+	name string
+	log  *callLog
+}
+
+func (i *tagInterceptor) OnSend(r *kgo.Record) error {
+	var seen []string
+	for _, h := range r.Headers {
+		seen = append(seen, h.Key)
+	}
+	i.log.add("%s send %s [%s]", i.name, r.Key, strings.Join(seen, ","))
+	if string(r.Key) == i.name {
+		return errors.New(i.name + " rejected")
+	}
+	r.Headers = append(r.Headers, kgo.RecordHeader{Key: i.name})
+	return nil
+}
+
+func (i *tagInterceptor) OnAck(r *kgo.Record, err error) {
+	i.log.add("%s ack %s %v", i.name, r.Key, err)
+}
+
+func (i *tagInterceptor) OnPoll(fs kgo.Fetches) kgo.Fetches {
+	var values []string
+	fs.EachRecord(func(r *kgo.Record) { values = append(values, string(r.Value)) })
+	i.log.add("%s poll [%s]", i.name, strings.Join(values, ","))
+	for _, f := range fs {
+		for ti := range f.Topics {
+			for pi := range f.Topics[ti].Partitions {
+				p := &f.Topics[ti].Partitions[pi]
+				keep := p.Records[:0]
+				for _, r := range p.Records {
+					if string(r.Value) != i.name {
+						keep = append(keep, r)
+					}
+				}
+				p.Records = keep
+			}
+		}
+	}
+	return fs
+}
+
+func (i *tagInterceptor) OnCommit(req *kmsg.OffsetCommitRequest, resp *kmsg.OffsetCommitResponse, err error) {
+	var offsets []string
+	for _, t := range req.Topics {
+		for _, p := range t.Partitions {
+			offsets = append(offsets, fmt.Sprintf("%s/%d@%d", t.Topic, p.Partition, p.Offset))
+		}
+	}
+	i.log.add("%s commit [%s] %v %v", i.name, strings.Join(offsets, ","), resp != nil, err)
+}
+
+func TestProducerInterceptors(t *testing.T) {
+	t.Parallel()
+
+	var log callLog
+	c := kfake.NewTestCluster(t, kfake.NumBrokers(1), kfake.SeedTopics(1, "t"))
+	cl := c.NewTestClient(t,
+		kgo.DefaultProduceTopic("t"),
+		kgo.ProducerInterceptors(&tagInterceptor{"a", &log}),
+		kgo.ProducerInterceptors(&tagInterceptor{"b", &log}),
+	)
+	ctx := context.Background()
+
+	// Interceptors run in order, each seeing the prior's mutations, and
+	// the mutations are produced.
+	if err := cl.ProduceSync(ctx, &kgo.Record{Key: []byte("k")}).FirstErr(); err != nil {
+		t.Fatal(err)
+	}
+	expectCalls(t, log.take(),
+		"a send k []",
+		"b send k [a]",
+		"a ack k <nil>",
+		"b ack k <nil>",
+	)
+	rs, err := c.ReadRecords("t", 0, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rs) != 1 || len(rs[0].Headers) != 2 || rs[0].Headers[0].Key != "a" || rs[0].Headers[1].Key != "b" {
+		t.Fatalf("got produced records %v, exp one with headers a, b", rs)
+	}
+
+	// A failing interceptor stops the chain and fails the record, but
+	// every interceptor is still notified of the failure.
+	err = cl.ProduceSync(ctx, &kgo.Record{Key: []byte("a")}).FirstErr()
+	if err == nil || err.Error() != "a rejected" {
+		t.Errorf("got err %v, exp a rejected", err)
+	}
+	expectCalls(t, log.take(),
+		"a send a []",
+		"a ack a a rejected",
+		"b ack a a rejected",
+	)
+	if rs, _ := c.ReadRecords("t", 0, 0, 0); len(rs) != 1 {
+		t.Errorf("got %d produced records, exp the rejected record to not be produced", len(rs))
+	}
+}
+
+func TestConsumerInterceptors(t *testing.T) {
+	t.Parallel()
+
+	var log callLog
+	c := kfake.NewTestCluster(t, kfake.NumBrokers(1), kfake.SeedTopics(1, "t"))
+	if _, err := c.ProduceTo("t", 0,
+		&kgo.Record{Value: []byte("x")},
+		&kgo.Record{Value: []byte("a")},
+		&kgo.Record{Value: []byte("b")},
+		&kgo.Record{Value: []byte("y")},
+	); err != nil {
+		t.Fatal(err)
+	}
+	cl := c.NewTestClient(t,
+		kgo.ConsumerGroup("g"),
+		kgo.ConsumeTopics("t"),
+		kgo.DisableAutoCommit(),
+		kgo.ConsumerInterceptors(&tagInterceptor{"a", &log}, &tagInterceptor{"b", &log}),
+	)
+
+	// Each interceptor sees the records the prior interceptor returned,
+	// and polling returns what the last interceptor returned.
+	rs := pollRecords(t, cl, 2)
+	if len(rs) != 2 || string(rs[0].Value) != "x" || string(rs[1].Value) != "y" {
+		t.Fatalf("got %d records, exp x and y", len(rs))
+	}
+	expectCalls(t, log.take(),
+		"a poll [x,a,b,y]",
+		"b poll [x,b,y]",
+	)
+
+	// Dropped records are still committed, and every interceptor sees
+	// the commit.
+	if err := cl.CommitUncommittedOffsets(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	expectCalls(t, log.take(),
+		"a commit [t/0@4] true <nil>",
+		"b commit [t/0@4] true <nil>",
+	)
+}
+
+func expectCalls(t *testing.T, got []string, exp ...string) {
+	t.Helper()
+	if strings.Join(got, "\n") != strings.Join(exp, "\n") {
+		t.Errorf("got interceptor calls:\n%s\nexp:\n%s", strings.Join(got, "\n"), strings.Join(exp, "\n"))
+	}
+}
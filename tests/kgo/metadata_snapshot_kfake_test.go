@@ -0,0 +1,100 @@
+package kgo_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kfake"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func TestMetadataSnapshot(t *testing.T) {
+	t.Parallel()
+
+	c := kfake.NewTestCluster(t,
+		kfake.NumBrokers(3),
+		kfake.SeedTopics(2, "both"),
+		kfake.SeedTopics(1, "prod"),
+		kfake.SeedTopics(3, "cons"),
+	)
+
+	// A client that has not loaded metadata has nothing to show.
+	if s := c.NewTestClient(t).MetadataSnapshot(); len(s.Brokers) != 0 || len(s.Topics) != 0 || !s.LastUpdate.IsZero() {
+		t.Errorf("got %+v before loading metadata, exp an empty snapshot", s)
+	}
+
+	start := time.Now()
+	cl := c.NewTestClient(t,
+		kgo.ConsumeTopics("both", "cons"),
+		kgo.UnknownTopicRetries(0),
+	)
+	ctx := context.Background()
+	for _, topic := range []string{"both", "prod"} {
+		if err := cl.ProduceSync(ctx, &kgo.Record{Topic: topic}).FirstErr(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := cl.ProduceSync(ctx, &kgo.Record{Topic: "missing"}).FirstErr(); !errors.Is(err, kerr.UnknownTopicOrPartition) {
+		t.Fatalf("got err %v producing to a missing topic, exp UNKNOWN_TOPIC_OR_PARTITION", err)
+	}
+	pollRecords(t, cl, 1)
+
+	s := cl.MetadataSnapshot()
+	if len(s.Brokers) != 3 {
+		t.Errorf("got %d brokers, exp 3", len(s.Brokers))
+	}
+	if s.LastUpdate.Before(start) {
+		t.Errorf("got last update %v, exp after the client started at %v", s.LastUpdate, start)
+	}
+	for topic, exp := range map[string]struct {
+		producing, consuming bool
+		partitions           int
+	}{
+		"both": {true, true, 2},
+		"prod": {true, false, 1},
+		"cons": {false, true, 3},
+	} {
+		st, ok := s.Topics[topic]
+		if !ok {
+			t.Errorf("topic %s missing from the snapshot", topic)
+			continue
+		}
+		if st.Producing != exp.producing || st.Consuming != exp.consuming || len(st.Partitions) != exp.partitions || st.LoadErr != nil {
+			t.Errorf("%s: got %+v, exp producing %v, consuming %v, %d partitions, no error", topic, st, exp.producing, exp.consuming, exp.partitions)
+		}
+		for i, p := range st.Partitions {
+			if p.Leader < 0 || p.Leader > 2 || len(p.ISR) == 0 || p.LoadErr != nil {
+				t.Errorf("%s/%d: got %+v, exp a known leader and ISR", topic, i, p)
+			}
+		}
+	}
+	if st := s.Topics["missing"]; !errors.Is(st.LoadErr, kerr.UnknownTopicOrPartition) || len(st.Partitions) != 0 {
+		t.Errorf("missing: got %+v, exp UNKNOWN_TOPIC_OR_PARTITION and no partitions", st)
+	}
+
+	// Moving a partition is visible once the client updates metadata;
+	// the snapshot itself does not update metadata.
+	prior := s.Topics["cons"].Partitions[0]
+	to := (prior.Leader + 1) % 3
+	if err := c.MoveTopicPartition("cons", 0, to); err != nil {
+		t.Fatal(err)
+	}
+	if p := cl.MetadataSnapshot().Topics["cons"].Partitions[0]; p.Leader != prior.Leader {
+		t.Errorf("got leader %d before updating metadata, exp the cached %d", p.Leader, prior.Leader)
+	}
+	cl.ForceMetadataRefresh()
+	waitFor(t, "the new leader to be loaded", func() bool {
+		p := cl.MetadataSnapshot().Topics["cons"].Partitions[0]
+		return p.Leader == to && p.LeaderEpoch > prior.LeaderEpoch
+	})
+
+	// The snapshot is a copy that callers can modify.
+	s = cl.MetadataSnapshot()
+	s.Topics["cons"].Partitions[0].ISR[0] = -100
+	if isr := cl.MetadataSnapshot().Topics["cons"].Partitions[0].ISR; isr[0] == -100 {
+		t.Error("modifying a snapshot modified the client's metadata")
+	}
+}
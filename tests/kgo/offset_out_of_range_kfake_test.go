@@ -0,0 +1,160 @@
+package kgo_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// oorRecorder records every OnOffsetOutOfRange call and returns reset.
+type oorRecorder struct {
+	mu    sync.Mutex
+	calls []kgo.OffsetOutOfRangePartition
+	reset kgo.Offset
+}
+
+func (o *oorRecorder) fn(p kgo.OffsetOutOfRangePartition) kgo.Offset {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.calls = append(o.calls, p)
+	return o.reset
+}
+
+func (o *oorRecorder) take() []kgo.OffsetOutOfRangePartition {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	calls := o.calls
+	o.calls = nil
+	return calls
+}
+
+func deleteRecordsBefore(t *testing.T, cl *kgo.Client, topic string, partition int32, offset int64) {
+	t.Helper()
+	req := kmsg.NewPtrDeleteRecordsRequest()
+	rt := kmsg.NewDeleteRecordsRequestTopic()
+	rt.Topic = topic
+	rp := kmsg.NewDeleteRecordsRequestTopicPartition()
+	rp.Partition = partition
+	rp.Offset = offset
+	rt.Partitions = append(rt.Partitions, rp)
+	req.Topics = append(req.Topics, rt)
+	resp, err := req.RequestWith(context.Background(), cl)
+	if err == nil {
+		err = kerr.ErrorForCode(resp.Topics[0].Partitions[0].ErrorCode)
+	}
+	if err != nil {
+		t.Fatalf("unable to delete records: %v", err)
+	}
+}
+
+func TestOnOffsetOutOfRange(t *testing.T) {
+	t.Parallel()
+
+	const n = 5
+	c := newChanCluster(t, n)
+	o := &oorRecorder{reset: kgo.NewOffset().AtStart()}
+	cl := c.NewTestClient(t,
+		kgo.ConsumePartitions(map[string]map[int32]kgo.Offset{"t": {0: kgo.NewOffset().At(100)}}),
+		kgo.ConsumeResetOffset(kgo.NewOffset().AtEnd()),
+		kgo.OnOffsetOutOfRange(o.fn),
+		kgo.DisableFetchSessions(), // every fetch lists the partition, so we can fail it below
+	)
+
+	// Starting past the end is out of range; the function is called with
+	// the partition's bounds and overrides ConsumeResetOffset.
+	rs := pollRecords(t, cl, n)
+	if rs[0].Offset != 0 {
+		t.Errorf("got first offset %d, exp 0 from resetting to the start", rs[0].Offset)
+	}
+	calls := o.take()
+	if len(calls) != 1 {
+		t.Fatalf("got %d calls, exp 1", len(calls))
+	}
+	if got, exp := calls[0], (kgo.OffsetOutOfRangePartition{Topic: "t", Partition: 0, Offset: 100, LogStartOffset: 0, HighWatermark: n}); got != exp {
+		t.Errorf("got %+v, exp %+v", got, exp)
+	}
+
+	// Once records are consumed, the last consumed record's timestamp is
+	// passed along.
+	c.ControlKey(int16(kmsg.Fetch), func(kreq kmsg.Request) (kmsg.Response, error, bool) {
+		req := kreq.(*kmsg.FetchRequest)
+		resp := req.ResponseKind().(*kmsg.FetchResponse)
+		for _, rt := range req.Topics {
+			st := kmsg.NewFetchResponseTopic()
+			st.Topic, st.TopicID = rt.Topic, rt.TopicID
+			for _, rp := range rt.Partitions {
+				sp := kmsg.NewFetchResponseTopicPartition()
+				sp.Partition = rp.Partition
+				sp.ErrorCode = kerr.OffsetOutOfRange.Code
+				sp.LogStartOffset = 0
+				sp.HighWatermark = n
+				st.Partitions = append(st.Partitions, sp)
+			}
+			resp.Topics = append(resp.Topics, st)
+		}
+		return resp, nil, true
+	})
+	if rs := pollRecords(t, cl, n); rs[0].Offset != 0 {
+		t.Errorf("got first offset %d, exp 0 from resetting to the start", rs[0].Offset)
+	}
+	calls = o.take()
+	if len(calls) != 1 || calls[0].Offset != n || !calls[0].LastConsumed.Equal(rs[n-1].Timestamp) {
+		t.Errorf("got calls %+v, exp one for offset %d last consumed at %v", calls, n, rs[n-1].Timestamp)
+	}
+
+	// After records are deleted, seeking back before the log start is out
+	// of range. Seeking starts over, so nothing was consumed since.
+	deleteRecordsBefore(t, cl, "t", 0, 3)
+	cl.SetOffsets(map[string]map[int32]kgo.EpochOffset{"t": {0: {Epoch: -1, Offset: 1}}})
+	if rs := pollRecords(t, cl, 2); rs[0].Offset != 3 {
+		t.Errorf("got first offset %d, exp the new log start 3", rs[0].Offset)
+	}
+	calls = o.take()
+	if len(calls) != 1 {
+		t.Fatalf("got %d calls, exp 1", len(calls))
+	}
+	if got := calls[0]; got.Offset != 1 || got.LogStartOffset != 3 || got.HighWatermark != n || !got.LastConsumed.IsZero() {
+		t.Errorf("got %+v, exp offset 1, log start 3, high watermark %d, and no last consumed time", got, n)
+	}
+}
+
+func TestOnOffsetOutOfRangeNoReset(t *testing.T) {
+	t.Parallel()
+
+	c := newChanCluster(t, 1)
+	o := &oorRecorder{reset: kgo.NoResetOffset()}
+	cl := c.NewTestClient(t,
+		kgo.ConsumePartitions(map[string]map[int32]kgo.Offset{"t": {0: kgo.NewOffset().At(100)}}),
+		kgo.OnOffsetOutOfRange(o.fn),
+	)
+
+	// Returning NoResetOffset fails the partition with the error, even
+	// though the default ConsumeResetOffset would reset.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	for {
+		fs := cl.PollFetches(ctx)
+		if ctx.Err() != nil {
+			t.Fatal("timed out waiting for OFFSET_OUT_OF_RANGE")
+		}
+		if len(fs.Records()) > 0 {
+			t.Fatal("got records despite not resetting")
+		}
+		var err error
+		fs.EachError(func(_ string, _ int32, fe error) { err = fe })
+		if errors.Is(err, kerr.OffsetOutOfRange) {
+			break
+		} else if err != nil {
+			t.Fatalf("got unexpected fetch error %v", err)
+		}
+	}
+	if calls := o.take(); len(calls) != 1 || calls[0].Offset != 100 {
+		t.Errorf("got calls %+v, exp one for offset 100", calls)
+	}
+}
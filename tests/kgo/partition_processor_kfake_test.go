@@ -0,0 +1,227 @@
+package kgo_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kfake"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// processed tracks every record processed by any partition processor in a
+// test, per partition.
+type processed struct {
+	mu      sync.Mutex
+	offsets map[int32][]int64
+}
+
+func (p *processed) add(r *kgo.Record) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.offsets == nil {
+		p.offsets = make(map[int32][]int64)
+	}
+	p.offsets[r.Partition] = append(p.offsets[r.Partition], r.Offset)
+}
+
+func (p *processed) count() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var n int
+	for _, os := range p.offsets {
+		n += len(os)
+	}
+	return n
+}
+
+func TestPartitionProcessorRebalance(t *testing.T) {
+	t.Parallel()
+
+	const n = 10
+	c := newChanCluster(t, n)
+	opts := []kgo.Opt{
+		kgo.SeedBrokers(c.ListenAddrs()...),
+		kgo.ConsumerGroup("g"),
+		kgo.ConsumeTopics("t"),
+		kgo.HeartbeatInterval(100 * time.Millisecond),
+		kgo.AutoCommitInterval(time.Hour), // only commit in revoke
+	}
+	var all processed
+
+	// The first processor owns both partitions and processes the first n
+	// records of each, and then blocks processing the next record of every
+	// partition until the gate is closed.
+	var (
+		gate    = make(chan struct{})
+		gated   = make(chan int32, 2)
+		revoked = make(chan time.Time, 1)
+	)
+	pp1, err := kgo.NewPartitionProcessor(func(ctx context.Context, r *kgo.Record) {
+		if r.Offset == n {
+			gated <- r.Partition
+			<-gate
+		}
+		all.add(r)
+	}, append(opts, kgo.OnPartitionsRevoked(func(_ context.Context, _ *kgo.Client, rs map[string][]int32) {
+		if len(rs["t"]) > 0 {
+			select {
+			case revoked <- time.Now():
+			default:
+			}
+		}
+	}))...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pp1.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go pp1.Run(ctx)
+
+	waitFor(t, "the first records to be processed", func() bool { return all.count() == 2*n })
+	for p := int32(0); p < 2; p++ {
+		for i := 0; i < n; i++ {
+			if _, err := c.ProduceTo("t", p, &kgo.Record{Value: []byte(strconv.Itoa(n + i))}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	for i := 0; i < 2; i++ {
+		select {
+		case <-gated:
+		case <-time.After(10 * time.Second):
+			t.Fatal("timed out waiting for processing to block")
+		}
+	}
+
+	// A second processor joins, which revokes one partition from the first.
+	// The revoke waits for the record being processed, drops the queued
+	// records, and commits what was processed so that the new owner
+	// resumes exactly where the old owner stopped.
+	pp2, err := kgo.NewPartitionProcessor(func(_ context.Context, r *kgo.Record) { all.add(r) }, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pp2.Close()
+	go pp2.Run(ctx)
+
+	time.Sleep(300 * time.Millisecond)
+	select {
+	case <-revoked:
+		t.Fatal("revoke completed while a revoked partition's record was being processed")
+	default:
+	}
+	released := time.Now()
+	close(gate)
+
+	select {
+	case at := <-revoked:
+		if at.Before(released) {
+			t.Error("revoke completed before the in flight record finished")
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for the revoke")
+	}
+
+	waitFor(t, "every record to be processed", func() bool { return all.count() >= 4*n })
+
+	// Every record was processed exactly once, in order per partition,
+	// across both owners.
+	all.mu.Lock()
+	for p := int32(0); p < 2; p++ {
+		os := all.offsets[p]
+		if len(os) != 2*n {
+			t.Errorf("partition %d: got %d processed records, exp %d: %v", p, len(os), 2*n, os)
+			continue
+		}
+		for i, o := range os {
+			if o != int64(i) {
+				t.Errorf("partition %d: got processed offsets %v, exp each once in order", p, os)
+				break
+			}
+		}
+	}
+	all.mu.Unlock()
+
+	// Leaving the group revokes everything and commits what was processed.
+	cancel()
+	pp1.Close()
+	pp2.Close()
+	lag, err := c.Lag("g")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for p := int32(0); p < 2; p++ {
+		if got := lag["t"][p].Committed; got != 2*n {
+			t.Errorf("partition %d: got committed offset %d, exp %d", p, got, 2*n)
+		}
+	}
+}
+
+func TestPartitionProcessorPausesSlowPartition(t *testing.T) {
+	t.Parallel()
+
+	// Enough records that a blocked worker's queue fills and its partition
+	// is paused, while the other partition keeps being processed.
+	const n = 200
+	c := kfake.NewTestCluster(t, kfake.NumBrokers(1), kfake.SeedTopics(2, "t"))
+	for p := int32(0); p < 2; p++ {
+		for i := 0; i < n; i++ {
+			if _, err := c.ProduceTo("t", p, &kgo.Record{Value: []byte(strconv.Itoa(i))}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	var all processed
+	gate := make(chan struct{})
+	pp, err := kgo.NewPartitionProcessor(func(_ context.Context, r *kgo.Record) {
+		if r.Partition == 0 {
+			<-gate
+		}
+		all.add(r)
+	},
+		kgo.SeedBrokers(c.ListenAddrs()...),
+		kgo.ConsumerGroup("g"),
+		kgo.ConsumeTopics("t"),
+		kgo.FetchMaxPartitionBytes(100),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pp.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	runErr := make(chan error, 1)
+	go func() { runErr <- pp.Run(ctx) }()
+
+	waitFor(t, "partition 1 to be processed", func() bool { return all.count() == n })
+	waitFor(t, "partition 0 to be paused", func() bool {
+		paused := pp.Client().PauseFetchPartitions(nil)
+		return len(paused["t"]) == 1 && paused["t"][0] == 0
+	})
+
+	close(gate)
+	waitFor(t, "partition 0 to be processed", func() bool { return all.count() == 2*n })
+	waitFor(t, "partition 0 to be resumed", func() bool { return len(pp.Client().PauseFetchPartitions(nil)) == 0 })
+
+	cancel()
+	if err := <-runErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("got Run err %v, exp context.Canceled", err)
+	}
+}
+
+func TestPartitionProcessorRequiresGroup(t *testing.T) {
+	_, err := kgo.NewPartitionProcessor(func(context.Context, *kgo.Record) {},
+		kgo.SeedBrokers("localhost:9092"),
+		kgo.ConsumeTopics("t"),
+	)
+	if err == nil || !strings.Contains(err.Error(), "missing required group") {
+		t.Errorf("got err %v, exp missing required group", err)
+	}
+}
@@ -0,0 +1,269 @@
+package kgo_test
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kfake"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// hedgeCluster is a three broker kfake cluster where every broker has a
+// replica of each partition of topic "t", and where fetches to the leader can
+// be held until released.
+type hedgeCluster struct {
+	c      *kfake.Cluster
+	p      *kgo.Client
+	leader int32
+
+	mu      sync.Mutex
+	hold    bool          // whether to hold the next leader fetch
+	release chan struct{} // closed to release a held fetch
+	held    int           // number of leader fetches held
+	late    int           // number of held fetches answered after release
+	leaderF []kmsg.FetchRequest
+	follwrF []kmsg.FetchRequest
+}
+
+func newHedgeCluster(t *testing.T, followers bool) *hedgeCluster {
+	t.Helper()
+	c := kfake.NewTestCluster(t, kfake.NumBrokers(3), kfake.SeedTopics(2, "t"))
+
+	h := &hedgeCluster{
+		c:       c,
+		release: make(chan struct{}),
+	}
+	h.p = c.NewTestClient(t,
+		kgo.DefaultProduceTopic("t"),
+		kgo.RecordPartitioner(kgo.RoundRobinPartitioner()),
+	)
+
+	// Both partitions are led by the same broker so that one fetch
+	// request covers them. The topic is replicated to every broker, so
+	// the other brokers are in-sync followers.
+	h.leader = 0
+	for p := int32(0); p < 2; p++ {
+		if err := c.MoveTopicPartition("t", p, h.leader); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c.ControlKey(int16(kmsg.Fetch), func(kreq kmsg.Request) (kmsg.Response, error, bool) {
+		c.KeepControl()
+		req := *kreq.(*kmsg.FetchRequest)
+		if c.CurrentNode() != h.leader {
+			h.mu.Lock()
+			h.follwrF = append(h.follwrF, req)
+			h.mu.Unlock()
+			if !followers {
+				return notFollowing(&req), nil, true
+			}
+			return nil, nil, false
+		}
+
+		h.mu.Lock()
+		h.leaderF = append(h.leaderF, req)
+		hold := h.hold && len(req.Topics) > 0
+		if hold {
+			h.hold = false
+			h.held++
+		}
+		release := h.release
+		h.mu.Unlock()
+
+		if hold {
+			c.SleepControl(func() { <-release })
+			h.mu.Lock()
+			h.late++
+			h.mu.Unlock()
+		}
+		return nil, nil, false // kfake answers the fetch, even if late
+	})
+	return h
+}
+
+// notFollowing answers a fetch to a broker that is not following any of the
+// fetched partitions.
+func notFollowing(req *kmsg.FetchRequest) *kmsg.FetchResponse {
+	resp := req.ResponseKind().(*kmsg.FetchResponse)
+	for _, rt := range req.Topics {
+		st := kmsg.NewFetchResponseTopic()
+		st.Topic = rt.Topic
+		st.TopicID = rt.TopicID
+		for _, rp := range rt.Partitions {
+			sp := kmsg.NewFetchResponseTopicPartition()
+			sp.Partition = rp.Partition
+			sp.ErrorCode = kerr.NotLeaderForPartition.Code
+			st.Partitions = append(st.Partitions, sp)
+		}
+		resp.Topics = append(resp.Topics, st)
+	}
+	return resp
+}
+
+func (h *hedgeCluster) produce(t *testing.T, n int) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	for i := 0; i < n; i++ {
+		if err := h.p.ProduceSync(ctx, kgo.StringRecord("v")).FirstErr(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// consumer returns a client consuming "t" that hedges fetches that are 50ms
+// slower than they should be.
+func (h *hedgeCluster) consumer(t *testing.T) *kgo.Client {
+	t.Helper()
+	return h.c.NewTestClient(t,
+		kgo.ConsumeTopics("t"),
+		kgo.FetchMaxWait(100*time.Millisecond),
+		kgo.HedgeFetches(50*time.Millisecond),
+	)
+}
+
+func (h *hedgeCluster) holdNext() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.hold = true
+}
+
+func (h *hedgeCluster) releaseHeld() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	close(h.release)
+	h.release = make(chan struct{})
+}
+
+func (h *hedgeCluster) counts() (held, late, follower int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.held, h.late, len(h.follwrF)
+}
+
+// consumeHedged polls cl until it has n records, failing if any offset is
+// consumed twice or out of order.
+func consumeHedged(t *testing.T, cl *kgo.Client, next map[int32]int64, n int) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	for n > 0 {
+		fs := cl.PollFetches(ctx)
+		if err := ctx.Err(); err != nil {
+			t.Fatalf("timed out with %d records left to consume", n)
+		}
+		for _, err := range fs.Errors() {
+			t.Fatalf("fetch error: %v", err)
+		}
+		fs.EachRecord(func(r *kgo.Record) {
+			if r.Offset != next[r.Partition] {
+				t.Errorf("partition %d: got offset %d, exp %d", r.Partition, r.Offset, next[r.Partition])
+			}
+			next[r.Partition] = r.Offset + 1
+			n--
+		})
+	}
+	if n < 0 {
+		t.Errorf("consumed %d more records than expected", -n)
+	}
+}
+
+func TestHedgeFetchFollowerWins(t *testing.T) {
+	t.Parallel()
+
+	h := newHedgeCluster(t, true)
+	h.produce(t, 4)
+	h.holdNext()
+
+	cl := h.consumer(t)
+
+	// The leader is holding our first fetch, so the records we receive
+	// come from the hedged fetch to a follower.
+	next := make(map[int32]int64)
+	consumeHedged(t, cl, next, 4)
+	if held, late, follower := h.counts(); held != 1 || late != 0 || follower == 0 {
+		t.Fatalf("got %d held, %d late, %d follower fetches; exp 1 held, 0 late, some follower fetches", held, late, follower)
+	}
+
+	// Releasing the held fetch answers it with the records we already
+	// consumed, which must be discarded. We then continue consuming from
+	// where the follower left us without skipping anything.
+	h.releaseHeld()
+	h.produce(t, 4)
+	consumeHedged(t, cl, next, 4)
+	if _, late, _ := h.counts(); late != 1 {
+		t.Fatalf("held fetch was answered %d times, exp 1", late)
+	}
+	if exp := map[int32]int64{0: 4, 1: 4}; !reflect.DeepEqual(next, exp) {
+		t.Fatalf("got next offsets %v, exp %v", next, exp)
+	}
+
+	// Nothing more arrives: the held response did not produce duplicates.
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	if fs := cl.PollFetches(ctx); fs.NumRecords() != 0 {
+		t.Fatalf("got %d unexpected records", fs.NumRecords())
+	}
+
+	// We abandoned the held request in our session, so the session was
+	// reset and the next fetch to the leader is a full fetch for both
+	// partitions from the offsets the follower returned.
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(h.leaderF) < 2 {
+		t.Fatalf("got %d leader fetches, exp at least 2", len(h.leaderF))
+	}
+	req := h.leaderF[1]
+	if req.SessionEpoch > 0 {
+		t.Errorf("leader fetch after the hedge used session epoch %d", req.SessionEpoch)
+	}
+	if n := len(req.Topics); n != 1 || len(req.Topics[0].Partitions) != 2 {
+		t.Errorf("leader fetch after the hedge was not a full fetch of both partitions: %v", req.Topics)
+	}
+	for _, req := range h.follwrF {
+		if req.SessionID != 0 || req.SessionEpoch != -1 {
+			t.Errorf("hedged fetch used session %d epoch %d, exp sessionless", req.SessionID, req.SessionEpoch)
+		}
+	}
+}
+
+func TestHedgeFetchUnusableFollower(t *testing.T) {
+	t.Parallel()
+
+	// The followers reject fetches as if they were not following, so
+	// the hedged fetch returns partition errors and we keep waiting for
+	// the leader.
+	h := newHedgeCluster(t, false)
+	h.produce(t, 4)
+	h.holdNext()
+
+	cl := h.consumer(t)
+
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		if _, _, follower := h.counts(); follower > 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for a hedged fetch")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	if fs := cl.PollFetches(ctx); fs.NumRecords() != 0 {
+		t.Fatalf("got %d records from an unusable hedged fetch", fs.NumRecords())
+	}
+
+	h.releaseHeld()
+	consumeHedged(t, cl, make(map[int32]int64), 4)
+	if _, late, _ := h.counts(); late != 1 {
+		t.Fatalf("held fetch was answered %d times, exp 1", late)
+	}
+}
@@ -0,0 +1,82 @@
+package kgo_test
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kfake"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+	"github.com/twmb/franz-go/pkg/kversion"
+)
+
+func newTelemetryCluster(t *testing.T) (*kfake.Cluster, *atomic.Int32) {
+	t.Helper()
+	c := kfake.NewTestCluster(t,
+		kfake.NumBrokers(1),
+		kfake.SeedTopics(1, "t"),
+		kfake.TelemetrySubscription(100*time.Millisecond, "org.apache.kafka.producer."),
+	)
+	gets := new(atomic.Int32)
+	c.ControlKey(int16(kmsg.GetTelemetrySubscriptions), func(kmsg.Request) (kmsg.Response, error, bool) {
+		c.KeepControl()
+		gets.Add(1)
+		return nil, nil, false
+	})
+	return c, gets
+}
+
+func TestTelemetryPushesWhenSubscribed(t *testing.T) {
+	t.Parallel()
+
+	c, _ := newTelemetryCluster(t)
+	cl := c.NewTestClient(t, kgo.DefaultProduceTopic("t"))
+	if err := cl.ProduceSync(context.Background(), kgo.StringRecord("v")).FirstErr(); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "a telemetry push", func() bool { return len(c.PushedTelemetry()) > 0 })
+}
+
+// telemetryLogger counts log lines about client telemetry.
+type telemetryLogger struct{ n atomic.Int32 }
+
+func (*telemetryLogger) Level() kgo.LogLevel { return kgo.LogLevelDebug }
+
+func (l *telemetryLogger) Log(_ kgo.LogLevel, msg string, _ ...any) {
+	if strings.Contains(msg, "telemetry") {
+		l.n.Add(1)
+	}
+}
+
+// TestTelemetryPinnedMaxVersions pins versions that predate client
+// telemetry: the client must not ask for a subscription at all, rather than
+// retrying a request it can never issue.
+func TestTelemetryPinnedMaxVersions(t *testing.T) {
+	t.Parallel()
+
+	c, gets := newTelemetryCluster(t)
+	var logger telemetryLogger
+	cl := c.NewTestClient(t,
+		kgo.DefaultProduceTopic("t"),
+		kgo.MaxVersions(kversion.V3_5_0()),
+		kgo.WithLogger(&logger),
+	)
+	if err := cl.ProduceSync(context.Background(), kgo.StringRecord("v")).FirstErr(); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(300 * time.Millisecond) // well past the first push interval
+	cl.Close()
+
+	if n := gets.Load(); n != 0 {
+		t.Errorf("got %d GetTelemetrySubscriptions requests, expected none", n)
+	}
+	if pushes := c.PushedTelemetry(); len(pushes) != 0 {
+		t.Errorf("got %d telemetry pushes, expected none", len(pushes))
+	}
+	if n := logger.n.Load(); n != 0 {
+		t.Errorf("got %d telemetry log lines, expected none", n)
+	}
+}
@@ -0,0 +1,227 @@
+package kgo_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kfake"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// newTxnCluster returns a cluster with topics "in" and "out" having two
+// partitions each, with n records in each "in" partition whose values are
+// their offsets.
+func newTxnCluster(t *testing.T, n int) *kfake.Cluster {
+	t.Helper()
+	c := kfake.NewTestCluster(t, kfake.NumBrokers(1), kfake.SeedTopics(2, "in", "out"))
+	for p := int32(0); p < 2; p++ {
+		for i := 0; i < n; i++ {
+			if _, err := c.ProduceTo("in", p, &kgo.Record{Value: []byte(strconv.Itoa(i))}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	return c
+}
+
+func newTxnSession(t *testing.T, c *kfake.Cluster, id string) *kgo.GroupTransactSession {
+	t.Helper()
+	s, err := kgo.NewGroupTransactSession(
+		kgo.SeedBrokers(c.ListenAddrs()...),
+		kgo.TransactionalID(id),
+		kgo.ConsumerGroup("g"),
+		kgo.ConsumeTopics("in"),
+		kgo.FetchIsolationLevel(kgo.ReadCommitted()),
+		kgo.RequireStableFetchOffsets(),
+		kgo.RecordPartitioner(kgo.ManualPartitioner()),
+		kgo.HeartbeatInterval(100*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(s.Close)
+	return s
+}
+
+// copyToOut returns every record copied to "out", to the same partition.
+func copyToOut(fs kgo.Fetches) []*kgo.Record {
+	var rs []*kgo.Record
+	fs.EachRecord(func(r *kgo.Record) {
+		rs = append(rs, &kgo.Record{Topic: "out", Partition: r.Partition, Value: r.Value})
+	})
+	return rs
+}
+
+// expectCommittedOut checks that "out" has, as read committed, exactly the n
+// records of each "in" partition, in order and once each.
+func expectCommittedOut(t *testing.T, c *kfake.Cluster, n int) {
+	t.Helper()
+	cl := c.NewTestClient(t,
+		kgo.ConsumeTopics("out"),
+		kgo.FetchIsolationLevel(kgo.ReadCommitted()),
+		kgo.FetchMaxWait(100*time.Millisecond),
+	)
+	next := make(map[int32]int)
+	for _, r := range pollRecords(t, cl, 2*n) {
+		if string(r.Value) != strconv.Itoa(next[r.Partition]) {
+			t.Fatalf("partition %d: got value %s, exp %d", r.Partition, r.Value, next[r.Partition])
+		}
+		next[r.Partition]++
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	if rs := cl.PollFetches(ctx).Records(); len(rs) > 0 {
+		t.Errorf("got %d extra committed records in out", len(rs))
+	}
+}
+
+func waitCommitted(t *testing.T, c *kfake.Cluster, n int64) {
+	t.Helper()
+	waitFor(t, "every input record to be committed", func() bool {
+		lag, err := c.Lag("g")
+		return err == nil && lag["in"][0].Committed == n && lag["in"][1].Committed == n
+	})
+}
+
+func TestGroupTransactSessionRun(t *testing.T) {
+	t.Parallel()
+
+	const n = 10
+	c := newTxnCluster(t, n)
+
+	// The first batch produced to out/0 is rejected, which aborts the
+	// transaction; the aborted input is then polled and processed again.
+	var rejected atomic.Bool
+	c.RejectProducedRecords("out", 0, func(*kmsg.Record) string {
+		if rejected.CompareAndSwap(false, true) {
+			return "rejected once"
+		}
+		return ""
+	})
+
+	s := newTxnSession(t, c, "txn")
+	var processed atomic.Int64
+	ctx, cancel := context.WithCancel(context.Background())
+	runErr := make(chan error, 1)
+	go func() {
+		runErr <- s.Run(ctx, func(_ context.Context, fs kgo.Fetches) ([]*kgo.Record, error) {
+			processed.Add(int64(fs.NumRecords()))
+			return copyToOut(fs), nil
+		})
+	}()
+
+	waitCommitted(t, c, n)
+	cancel()
+	if err := <-runErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("got Run err %v, exp context.Canceled", err)
+	}
+	if !rejected.Load() {
+		t.Fatal("no produce was rejected")
+	}
+	if got := processed.Load(); got <= 2*n {
+		t.Errorf("processed %d records, exp more than %d from replaying the aborted transaction", got, 2*n)
+	}
+	expectCommittedOut(t, c, n)
+}
+
+func TestGroupTransactSessionRunFnError(t *testing.T) {
+	t.Parallel()
+
+	const n = 5
+	c := newTxnCluster(t, n)
+	s := newTxnSession(t, c, "txn")
+
+	// An error from fn aborts the transaction and is returned.
+	errFn := errors.New("cannot process")
+	err := s.Run(context.Background(), func(_ context.Context, fs kgo.Fetches) ([]*kgo.Record, error) {
+		for _, r := range copyToOut(fs) {
+			s.Produce(context.Background(), r, nil) // produced, but aborted below
+		}
+		return nil, errFn
+	})
+	if !errors.Is(err, errFn) {
+		t.Fatalf("got Run err %v, exp %v", err, errFn)
+	}
+
+	// Running again resumes from the last committed offsets, so the
+	// aborted records are processed again and end up in out once.
+	ctx, cancel := context.WithCancel(context.Background())
+	runErr := make(chan error, 1)
+	go func() {
+		runErr <- s.Run(ctx, func(_ context.Context, fs kgo.Fetches) ([]*kgo.Record, error) {
+			return copyToOut(fs), nil
+		})
+	}()
+	waitCommitted(t, c, n)
+	cancel()
+	if err := <-runErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("got Run err %v, exp context.Canceled", err)
+	}
+	expectCommittedOut(t, c, n)
+}
+
+func TestGroupTransactSessionRunRebalance(t *testing.T) {
+	t.Parallel()
+
+	const n = 10
+	c := newTxnCluster(t, n)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The first session blocks in its first transaction until a second
+	// session joins the group, which revokes a partition from the first.
+	var (
+		gate    = make(chan struct{})
+		blocked = make(chan struct{})
+		once    sync.Once
+		run1Err = make(chan error, 1)
+		run2Err = make(chan error, 1)
+		copyFn  = func(_ context.Context, fs kgo.Fetches) ([]*kgo.Record, error) { return copyToOut(fs), nil }
+		blockFn = func(ctx context.Context, fs kgo.Fetches) ([]*kgo.Record, error) {
+			once.Do(func() {
+				close(blocked)
+				<-gate
+			})
+			return copyFn(ctx, fs)
+		}
+	)
+	s1 := newTxnSession(t, c, "txn1")
+	go func() { run1Err <- s1.Run(ctx, blockFn) }()
+	select {
+	case <-blocked:
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for the first transaction")
+	}
+	s2 := newTxnSession(t, c, "txn2")
+	go func() { run2Err <- s2.Run(ctx, copyFn) }()
+	time.Sleep(500 * time.Millisecond)
+	close(gate)
+
+	// The first transaction was produced but aborted rather than
+	// committed, and everything is processed exactly once regardless.
+	waitCommitted(t, c, n)
+	cancel()
+	for _, ch := range []chan error{run1Err, run2Err} {
+		if err := <-ch; !errors.Is(err, context.Canceled) {
+			t.Errorf("got Run err %v, exp context.Canceled", err)
+		}
+	}
+	var written int
+	for p := int32(0); p < 2; p++ {
+		rs, err := c.ReadRecords("out", p, 0, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		written += len(rs)
+	}
+	if written <= 2*n {
+		t.Errorf("got %d records written to out, exp more than %d including the aborted transaction", written, 2*n)
+	}
+	expectCommittedOut(t, c, n)
+}